@@ -19,6 +19,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"regexp"
 	"strconv"
 	"strings"
 	"syscall"
@@ -26,91 +27,161 @@ import (
 
 	// Application layer
 	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/application/cqrs"
+	"github.com/alem-hub/alem-community-hub/internal/application/eventhandler"
 	"github.com/alem-hub/alem-community-hub/internal/application/query"
 	"github.com/alem-hub/alem-community-hub/internal/application/saga"
+	"github.com/alem-hub/alem-community-hub/internal/domain/analytics"
 
 	// Domain layer
 	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
 	// "github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
 
 	// Infrastructure layer
 	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/alem"
 	"github.com/alem-hub/alem-community-hub/internal/infrastructure/messaging"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/metrics"
 	"github.com/alem-hub/alem-community-hub/internal/infrastructure/persistence/postgres"
 	"github.com/alem-hub/alem-community-hub/internal/infrastructure/persistence/redis"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/scheduler"
 	"github.com/alem-hub/alem-community-hub/internal/infrastructure/service"
 
 	// Interface layer
 	httpserver "github.com/alem-hub/alem-community-hub/internal/interface/http"
+	"github.com/alem-hub/alem-community-hub/internal/interface/http/handlers"
 	"github.com/alem-hub/alem-community-hub/internal/interface/telegram"
 
 	// Packages
+	"github.com/alem-hub/alem-community-hub/pkg/config"
+	"github.com/alem-hub/alem-community-hub/pkg/featureflag"
+	"github.com/alem-hub/alem-community-hub/pkg/httpclient"
 	"github.com/alem-hub/alem-community-hub/pkg/logger"
 )
 
+// version - версия сборки, задаётся через -ldflags "-X main.version=...".
+var version = "dev"
+
 // ══════════════════════════════════════════════════════════════════════════════
 // CONFIGURATION
 // ══════════════════════════════════════════════════════════════════════════════
 
 // Config содержит всю конфигурацию приложения.
+// Поля заполняются из переменных окружения по тегам env (см. pkg/config).
 type Config struct {
 	// App
-	AppEnv      string // development, staging, production
-	AppDebug    bool
-	AppTimezone string
+	AppEnv      string `env:"APP_ENV" default:"development"` // development, staging, production
+	AppDebug    bool   `env:"APP_DEBUG"`
+	AppTimezone string `env:"APP_TIMEZONE" default:"Asia/Almaty"`
+
+	// Уровень логов; APP_DEBUG включает debug независимо от него
+	LogLevel string `env:"LOG_LEVEL" default:"info" enum:"debug,info,warn,error" reload:"true"`
 
 	// Telegram Bot
-	TelegramToken   string
-	TelegramMode    string // polling или webhook
-	TelegramWebhook string
+	TelegramToken   string  `env:"TELEGRAM_BOT_TOKEN" required:"true" secret:"true"`
+	TelegramMode    string  `env:"TELEGRAM_MODE" default:"polling" enum:"polling,webhook"`
+	TelegramWebhook string  `env:"TELEGRAM_WEBHOOK_URL"`
+	AdminIDs        []int64 `env:"TELEGRAM_ADMIN_IDS"` // Telegram ID администраторов
+
+	// Секрет, который Telegram присылает в X-Telegram-Bot-Api-Secret-Token
+	// (обязателен в режиме webhook: 1-256 символов A-Z, a-z, 0-9, _ и -)
+	TelegramWebhookSecret string `env:"TELEGRAM_WEBHOOK_SECRET_TOKEN" secret:"true"`
+
+	// Жалобы: после скольких подтверждённых жалоб студент исключается из подбора помощников
+	HelpReportThreshold int `env:"HELP_REPORT_THRESHOLD" default:"3"`
 
-	// PostgreSQL (Supabase)
-	DatabaseURL string
+	// Сколько открытых запросов помощи может быть у студента одновременно
+	HelpMaxOpenRequests int `env:"HELP_MAX_OPEN_REQUESTS" default:"3"`
+
+	// PostgreSQL (Supabase), пароль в URL маскируется при выводе
+	DatabaseURL   string `env:"DATABASE_URL" required:"true"`
+	RunMigrations bool   `env:"RUN_MIGRATIONS" default:"true"` // false - миграции применяет другой процесс, здесь только проверка версии схемы
 
 	// Redis (опционально, для кеширования)
-	RedisURL     string
-	RedisEnabled bool
+	RedisURL     string `env:"REDIS_URL"`
+	RedisEnabled bool   `env:"REDIS_ENABLED"`
 
 	// HTTP Server
-	HTTPHost string
-	HTTPPort int
+	HTTPHost           string   `env:"HTTP_HOST" default:"0.0.0.0"`
+	HTTPPort           int      `env:"HTTP_PORT" default:"8080"`
+	HTTPAPIKeys        []string `env:"HTTP_API_KEYS" secret:"true" reload:"true"` // ключи для админских эндпоинтов
+	HTTPCursorSecret   string   `env:"HTTP_CURSOR_SECRET" secret:"true"`          // ключ подписи курсоров пагинации
+	HTTPLandingOrigins []string `env:"HTTP_LANDING_ORIGINS"`                      // origins лендинга для /stats.json
+
+	// Лимиты запросов в минуту (0 - без лимита)
+	HTTPRateLimit      int `env:"HTTP_RATE_LIMIT" default:"100" reload:"true"`      // с одного IP ко всем эндпоинтам
+	HTTPPageRateLimit  int `env:"HTTP_PAGE_RATE_LIMIT" default:"20" reload:"true"`  // с одного IP к странице лидерборда
+	HTTPTokenRateLimit int `env:"HTTP_TOKEN_RATE_LIMIT" default:"60" reload:"true"` // на личный токен к /api/v1/me
+	HTTPStatsRateLimit int `env:"HTTP_STATS_RATE_LIMIT" default:"10" reload:"true"` // с одного IP к /stats.json
+
+	// Ответ фича-флагов, пока их не удаётся загрузить: flag - по настройке
+	// каждого флага, open - все включены, closed - все выключены
+	FeatureFlagFailMode string `env:"FEATURE_FLAG_FAIL_MODE" default:"flag" enum:"flag,open,closed" reload:"true"`
 
 	// Alem Platform API
-	AlemAPIURL   string
-	AlemAPIToken string
+	AlemAPIURL   string `env:"ALEM_API_URL" default:"https://platform.alem.school"`
+	AlemAPIToken string `env:"ALEM_API_TOKEN" secret:"true"`
+
+	// Доля лимита Alem API (в процентах), зарезервированная под /sync_me
+	AlemOnDemandPercent int `env:"ALEM_ON_DEMAND_PERCENT" default:"20"`
+
+	// Таймаут одной проверки /readyz (БД, Redis, Alem API)
+	HealthCheckTimeout time.Duration `env:"HEALTH_CHECK_TIMEOUT" default:"2s"`
+
+	// Метрики Prometheus на /metrics; выключены - там краткая сводка в JSON
+	MetricsEnabled bool `env:"METRICS_ENABLED"`
 
 	// Graceful Shutdown
-	ShutdownTimeout time.Duration
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" default:"30s"`
 }
 
+// webhookSecretTokenPattern - допустимый secret_token для setWebhook.
+var webhookSecretTokenPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
 // LoadConfig загружает конфигурацию из переменных окружения.
+// Возвращает сразу все ошибки: отсутствующие и некорректные переменные.
 func LoadConfig() (*Config, error) {
-	cfg := &Config{
-		AppEnv:          getEnv("APP_ENV", "development"),
-		AppDebug:        getEnvBool("APP_DEBUG", false),
-		AppTimezone:     getEnv("APP_TIMEZONE", "Asia/Almaty"),
-		TelegramToken:   getEnv("TELEGRAM_BOT_TOKEN", ""),
-		TelegramMode:    getEnv("TELEGRAM_MODE", "polling"),
-		TelegramWebhook: getEnv("TELEGRAM_WEBHOOK_URL", ""),
-		DatabaseURL:     getEnv("DATABASE_URL", ""),
-		RedisURL:        getEnv("REDIS_URL", ""),
-		RedisEnabled:    getEnvBool("REDIS_ENABLED", false),
-		HTTPHost:        getEnv("HTTP_HOST", "0.0.0.0"),
-		HTTPPort:        getEnvInt("HTTP_PORT", 8080),
-		AlemAPIURL:      getEnv("ALEM_API_URL", "https://platform.alem.school"),
-		AlemAPIToken:    getEnv("ALEM_API_TOKEN", ""),
-		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
-	}
-
-	// Валидация обязательных полей
-	if cfg.TelegramToken == "" {
-		return nil, errors.New("TELEGRAM_BOT_TOKEN is required")
-	}
-	if cfg.DatabaseURL == "" {
-		return nil, errors.New("DATABASE_URL is required")
+	cfg := &Config{}
+
+	loader := config.New(nil)
+	if err := loader.Bind(cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.TelegramMode == "webhook" && cfg.TelegramWebhook == "" {
+		loader.Errorf("TELEGRAM_WEBHOOK_URL is required when TELEGRAM_MODE=webhook")
+	}
+	if cfg.TelegramMode == "webhook" && !webhookSecretTokenPattern.MatchString(cfg.TelegramWebhookSecret) {
+		loader.Errorf("TELEGRAM_WEBHOOK_SECRET_TOKEN is required when TELEGRAM_MODE=webhook: 1-256 characters A-Z, a-z, 0-9, _ and -")
+	}
+	if cfg.HTTPPort < 1 || cfg.HTTPPort > 65535 {
+		loader.Errorf("HTTP_PORT: %d is out of range 1-65535", cfg.HTTPPort)
+	}
+	if cfg.HealthCheckTimeout <= 0 {
+		loader.Errorf("HEALTH_CHECK_TIMEOUT: must be positive, got %s", cfg.HealthCheckTimeout)
+	}
+	if cfg.HelpReportThreshold < 1 {
+		loader.Errorf("HELP_REPORT_THRESHOLD: must be at least 1, got %d", cfg.HelpReportThreshold)
+	}
+	if cfg.HelpMaxOpenRequests < 1 {
+		loader.Errorf("HELP_MAX_OPEN_REQUESTS: must be at least 1, got %d", cfg.HelpMaxOpenRequests)
+	}
+	for key, limit := range map[string]int{
+		"HTTP_RATE_LIMIT":       cfg.HTTPRateLimit,
+		"HTTP_PAGE_RATE_LIMIT":  cfg.HTTPPageRateLimit,
+		"HTTP_TOKEN_RATE_LIMIT": cfg.HTTPTokenRateLimit,
+		"HTTP_STATS_RATE_LIMIT": cfg.HTTPStatsRateLimit,
+	} {
+		if limit < 0 {
+			loader.Errorf("%s: must not be negative, got %d", key, limit)
+		}
 	}
 
+	if err := loader.Err(); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
@@ -142,12 +213,16 @@ func run(ctx context.Context) error {
 	// ─────────────────────────────────────────────────────────────────────────
 	// 2. НАСТРОЙКА ЛОГИРОВАНИЯ
 	// ─────────────────────────────────────────────────────────────────────────
-	log := setupLogger(cfg)
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(configLogLevel(cfg))
+	log := setupLogger(cfg, logLevel)
+	student.SetRevealTelegramIDs(cfg.AppDebug) // полные Telegram ID в логах только при отладке
 	log.Info("starting Alem Community Hub Bot",
 		"env", cfg.AppEnv,
 		"debug", cfg.AppDebug,
 		"timezone", cfg.AppTimezone,
 	)
+	config.Dump(log, cfg)
 
 	// ─────────────────────────────────────────────────────────────────────────
 	// 3. ПОДКЛЮЧЕНИЕ К БАЗЕ ДАННЫХ (PostgreSQL/Supabase)
@@ -172,9 +247,16 @@ func run(ctx context.Context) error {
 	// 4. ЗАПУСК МИГРАЦИЙ
 	// ─────────────────────────────────────────────────────────────────────────
 	log.Info("running database migrations...")
-	migrator := postgres.NewMigrator(dbConn)
-	if err := migrator.Migrate(ctx); err != nil {
-		return fmt.Errorf("failed to run migrations: %w", err)
+	migrator := postgres.NewMigrator(dbConn).WithLogger(log)
+	if cfg.RunMigrations {
+		if err := migrator.Migrate(ctx); err != nil {
+			return fmt.Errorf("failed to run migrations: %w", err)
+		}
+	} else {
+		log.Info("migrations disabled (RUN_MIGRATIONS=false), checking schema version only")
+	}
+	if err := migrator.CheckSchemaVersion(ctx); err != nil {
+		return err
 	}
 
 	status, err := migrator.Status(ctx)
@@ -190,13 +272,24 @@ func run(ctx context.Context) error {
 		log.Info("migrations completed", "applied", appliedCount, "total", len(status))
 	}
 
+	// Метрики Prometheus (nil - выключены, вызовы ничего не записывают)
+	var metricsRegistry *metrics.Registry
+	if cfg.MetricsEnabled {
+		metricsRegistry = metrics.New()
+	}
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// 5. ИНИЦИАЛИЗАЦИЯ REDIS (опционально)
 	// ─────────────────────────────────────────────────────────────────────────
 	var redisCache *redis.Cache
 	var redisOnlineTracker *redis.OnlineTracker
 	var leaderboardCache leaderboard.LeaderboardCache
+	var leaderboardUpdates leaderboard.UpdateSubscriber
+	var leaderboardBackup leaderboard.CacheBackup
 	var studentCache student.StudentCache
+	var usageCounter analytics.UsageCounter
+	var displayNameCache student.DisplayNameCache
+	var leaderboardNames command.LeaderboardNames
 
 	if cfg.RedisEnabled && cfg.RedisURL != "" {
 		log.Info("connecting to Redis...")
@@ -219,8 +312,19 @@ func run(ctx context.Context) error {
 		} else {
 			defer redisCache.Close()
 			redisOnlineTracker = redis.NewOnlineTracker(redisCache)
-			leaderboardCache = redis.NewLeaderboardCache(redisCache)
+			redisLeaderboard := redis.NewLeaderboardCache(redisCache)
+			leaderboardCache = redisLeaderboard
+			leaderboardUpdates = redisLeaderboard
+			leaderboardBackup = redisLeaderboard
+			leaderboardNames = redisLeaderboard
 			studentCache = redis.NewStudentCache(redisCache)
+			usageCounter = redis.NewUsageCounter(redisCache)
+			displayNameCache = redis.NewDisplayNameCache(redisCache)
+			metricsRegistry.LookupGauge("leaderboard_cache_entries", "Students in the cached overall leaderboard.",
+				func(ctx context.Context) (float64, error) {
+					n, err := redisLeaderboard.GetCount(ctx, "")
+					return float64(n), err
+				})
 			log.Info("Redis connection established")
 		}
 	}
@@ -234,11 +338,31 @@ func run(ctx context.Context) error {
 	// 6. ИНИЦИАЛИЗАЦИЯ РЕПОЗИТОРИЕВ
 	// ─────────────────────────────────────────────────────────────────────────
 	log.Info("initializing repositories...")
-	studentRepo := postgres.NewStudentRepository(dbConn)
+	studentRepo := postgres.NewStudentRepository(dbConn).WithDisplayNameCache(displayNameCache)
 	progressRepo := postgres.NewProgressRepository(dbConn)
 	leaderboardRepo := postgres.NewLeaderboardRepository(dbConn)
 	socialRepo := postgres.NewSocialRepository(dbConn)
 	activityRepo := postgres.NewActivityRepository(dbConn)
+	auditLog := postgres.NewAuditLogRepository(dbConn)
+	notificationRepo := postgres.NewNotificationRepository(dbConn)
+	usageRepo := postgres.NewCommandUsageRepository(dbConn)
+	cohortSettings := service.NewCohortSettings(postgres.NewCohortSettingsRepository(dbConn), log)
+	webhookRepo := postgres.NewWebhookRepository(dbConn)
+	triggerRules := service.NewTriggerRules(postgres.NewTriggerRuleRepository(dbConn), log)
+	if err := triggerRules.Seed(ctx); err != nil {
+		log.Error("failed to seed trigger rules", "error", err)
+	}
+	featureFlags := featureflag.NewClient(postgres.NewFeatureFlagRepository(dbConn),
+		featureflag.WithLogger(log), featureflag.WithDefaults(settings.BuiltinFeatureFlags()...))
+	if err := featureFlags.Seed(ctx); err != nil {
+		log.Error("failed to seed feature flags", "error", err)
+	}
+	featureFlags.SetFailMode(featureflag.FailMode(cfg.FeatureFlagFailMode))
+	featureflag.SetDefault(featureFlags)
+	go featureFlags.Run(ctx)
+	// Чёрный список фраз в пользовательском тексте (правится через admin API)
+	contentBlocklist := service.NewContentBlocklist(postgres.NewContentBlocklistRepository(dbConn), log)
+	displayNames := service.NewDisplayNameResolver(displayNameCache, studentRepo, log)
 
 	// ─────────────────────────────────────────────────────────────────────────
 	// 7. ИНИЦИАЛИЗАЦИЯ EVENT BUS
@@ -252,6 +376,32 @@ func run(ctx context.Context) error {
 		log.Info("closing event bus...")
 		_ = eventBus.Close()
 	}()
+	metricsRegistry.Gauge("event_bus_queue_depth", "Event handler runs waiting for a worker or running.",
+		func() float64 { return float64(eventBus.QueueDepth()) })
+
+	// Зеркалирование запросов помощи во внешние интеграции (Discord/Slack)
+	webhookConfig := httpclient.DefaultConfig("webhooks")
+	webhookConfig.Timeout = 10 * time.Second
+	webhookConfig.Logger = log
+	webhookClient := httpclient.New(webhookConfig)
+	webhookDispatcher := service.NewWebhookDispatcher(
+		webhookRepo,
+		socialRepo.HelpRequests(),
+		studentRepo,
+		webhookClient,
+		log,
+		service.DefaultWebhookDispatcherConfig(),
+	)
+	if err := webhookDispatcher.Subscribe(eventBus); err != nil {
+		log.Error("failed to subscribe webhook dispatcher", "error", err)
+	}
+
+	// Лента сообщества (/feed): достижения, помощь, рекорды, новички
+	feedRepo := postgres.NewFeedRepository(dbConn)
+	feedProjection := eventhandler.NewFeedProjectionHandler(feedRepo, studentRepo, cohortSettings, log)
+	if err := feedProjection.Subscribe(eventBus); err != nil {
+		log.Error("failed to subscribe feed projection", "error", err)
+	}
 
 	// ─────────────────────────────────────────────────────────────────────────
 	// 8. ИНИЦИАЛИЗАЦИЯ ВНЕШНИХ КЛИЕНТОВ
@@ -262,6 +412,7 @@ func run(ctx context.Context) error {
 	alemConfig := alem.DefaultClientConfig(cfg.AlemAPIURL)
 	alemConfig.APIKey = cfg.AlemAPIToken
 	alemConfig.Logger = log
+	alemConfig.RateLimiterConfig.OnDemandShare = float64(cfg.AlemOnDemandPercent) / 100
 	alemClient := alem.NewClient(alemConfig)
 	alemAPIAdapter := service.NewAlemAPIAdapter(alemClient)
 	sagaAlemAPIAdapter := service.NewSagaAlemAPIAdapter(alemClient)
@@ -273,9 +424,23 @@ func run(ctx context.Context) error {
 
 	// Services
 	leaderboardService := service.NewLeaderboardService(leaderboardRepo, leaderboardCache)
+	var cacheCircuit func() httpserver.CacheCircuitStatus
+	if redisCache != nil {
+		// Пока Redis лежал, записи в кеш терялись: прогреваем рейтинг из Postgres
+		redisCache.OnRecover(func(ctx context.Context) {
+			log.Info("Redis is back, warming leaderboard cache")
+			if err := leaderboardService.WarmCache(ctx); err != nil {
+				log.Warn("leaderboard cache warmup failed", "error", err)
+			}
+		})
+		cacheCircuit = func() httpserver.CacheCircuitStatus {
+			return httpserver.CacheCircuitStatus{State: redisCache.CircuitState(), DroppedWrites: redisCache.DroppedWrites()}
+		}
+	}
 	helperNotifier := service.NewHelperNotifierStub()
 	matchingService := service.NewHelperMatchingServiceStub()
-	notificationService := service.NewNotificationServiceStub(log)
+	// Запланированные уведомления отправляет DeliverNotifications в worker
+	notificationService := service.NewNotificationServiceStub(log).WithTriggerRules(triggerRules).WithOutbox(notificationRepo)
 	idGenerator := service.NewIDGenerator()
 
 	// Commands (CQRS Write Side)
@@ -288,6 +453,8 @@ func run(ctx context.Context) error {
 		command.DefaultSyncStudentHandlerConfig(),
 	)
 
+	requestHelpConfig := command.DefaultRequestHelpHandlerConfig()
+	requestHelpConfig.MaxOpenRequests = cfg.HelpMaxOpenRequests
 	requestHelpCmd := command.NewRequestHelpHandler(
 		studentRepo,
 		socialRepo,
@@ -296,8 +463,16 @@ func run(ctx context.Context) error {
 		helperNotifier,
 		matchingService,
 		eventBus,
-		command.DefaultRequestHelpHandlerConfig(),
+		requestHelpConfig,
 	)
+	helpClusterCmd := command.NewHelpClusterHandler(socialRepo, studentRepo).WithEventPublisher(eventBus)
+	helpTeamCmd := command.NewHelpTeamHandler(socialRepo, studentRepo)
+	respondToHelpCmd := command.NewRespondToHelpRequestHandler(socialRepo).WithEventPublisher(eventBus)
+	snoozeHelpCmd := command.NewSnoozeHelpRequestHandler(socialRepo.HelpRequests(), socialRepo.HelpSnoozes())
+
+	// Персональные токены API (/token): выдаёт бот, проверяет HTTP сервер
+	accessTokenRepo := postgres.NewAccessTokenRepository(dbConn)
+	accessTokensCmd := command.NewAccessTokensHandler(accessTokenRepo)
 
 	connectStudentsCmd := command.NewConnectStudentsHandler(
 		studentRepo,
@@ -308,8 +483,37 @@ func run(ctx context.Context) error {
 	updatePrefsCmd := command.NewUpdatePreferencesHandler(
 		studentRepo,
 		studentCache,
+	).WithStreaks(progressRepo)
+
+	// Nudges are capped per pair and day; without Redis the cap lives
+	// until restart
+	var nudgeCooldowns notification.CooldownStore = service.NewInMemoryCooldownStore()
+	if redisCache != nil {
+		nudgeCooldowns = redis.NewNotificationCooldown(redisCache)
+	}
+	nudgeCmd := command.NewNudgeHandler(studentRepo, socialRepo.Connections(), progressRepo, nudgeCooldowns)
+
+	// /sync_me: раз в 3 минуты на студента, ждёт пакетную синхронизацию
+	// worker вместо повторной
+	var syncLock student.SyncLock
+	if redisCache != nil {
+		syncLock = redis.NewSyncLock(redisCache)
+	}
+	syncMeCmd := command.NewSyncMeHandler(
+		syncStudentCmd,
+		studentRepo,
+		leaderboardService,
+		nudgeCooldowns,
+		syncLock,
+		command.DefaultSyncMeConfig(),
 	)
 
+	// /relink: перенос профиля на новый Telegram аккаунт по email и паролю
+	relinkCmd := command.NewRelinkTelegramHandler(studentRepo, studentRepo, nudgeCooldowns, studentCache)
+	if redisCache != nil {
+		relinkCmd.WithInvalidations(messaging.NewInvalidationPublisher(redis.NewPubSubClient(redisCache), log))
+	}
+
 	// Queries (CQRS Read Side)
 	leaderboardQuery := query.NewGetLeaderboardHandler(
 		leaderboardRepo,
@@ -330,13 +534,30 @@ func run(ctx context.Context) error {
 		studentOnlineTracker,
 	)
 
+	// The bus applies logging, panic recovery and validation to every
+	// command and query registered on it
+	bus := cqrs.New(cqrs.Logging(log), cqrs.Recovery(log), cqrs.Validation())
+	cqrs.Register(bus, syncStudentCmd)
+	cqrs.Register(bus, requestHelpCmd)
+	cqrs.Register(bus, connectStudentsCmd)
+	cqrs.Register(bus, leaderboardQuery)
+	cqrs.Register(bus, studentRankQuery)
+	cqrs.Register(bus, neighborsQuery)
+
+	// Helper streaks and the month's helper ranking (computed by the worker)
+	helperStreakRepo := postgres.NewHelperStreakRepository(dbConn)
+	if redisCache != nil {
+		cqrs.Register(bus, query.NewGetMonthlyHelpersHandler(redis.NewHelperBoard(redisCache), helperStreakRepo, studentRepo))
+	}
+
+	taskDifficultyRepo := postgres.NewTaskDifficultyRepository(dbConn)
 	findHelpersQuery := query.NewFindHelpersHandler(
 		studentRepo,
 		activityRepo,
 		activityOnlineTracker,
 		taskIndex,
 		socialRepo,
-	)
+	).WithTaskDifficulty(taskDifficultyRepo).WithCohortSettings(cohortSettings).WithHelperStreaks(helperStreakRepo)
 
 	onlineNowQuery := query.NewGetOnlineNowHandler(
 		studentRepo,
@@ -349,17 +570,101 @@ func run(ctx context.Context) error {
 		studentRepo,
 		progressRepo,
 		leaderboardRepo,
+	).WithPersonalBests(postgres.NewPersonalBestRepository(dbConn))
+
+	notificationsQuery := query.NewGetNotificationsHandler(
+		studentRepo,
+		notificationRepo,
+	)
+
+	markNotifsReadCmd := command.NewMarkNotificationsReadHandler(
+		studentRepo,
+		notificationRepo,
 	)
 
+	mergeStudentsCmd := command.NewMergeStudentsHandler(
+		studentRepo,
+		postgres.NewStudentMergeUnitOfWorkFactory(dbConn),
+		studentOnlineTracker,
+		studentCache,
+		leaderboardCache,
+	)
+	if redisCache != nil {
+		mergeStudentsCmd.WithInvalidations(messaging.NewInvalidationPublisher(redis.NewPubSubClient(redisCache), log))
+	}
+
+	// Имена из профиля Telegram: строка, лидерборд и кеш имён обновляются вместе
+	renameStudentCmd := command.NewRenameStudentHandler(
+		studentRepo,
+		studentCache,
+		leaderboardNames,
+		displayNameCache,
+	)
+	if redisCache != nil {
+		renameStudentCmd.WithInvalidations(messaging.NewInvalidationPublisher(redis.NewPubSubClient(redisCache), log))
+	}
+
+	achievementsQuery := query.NewGetAchievementsHandler(
+		studentRepo,
+		progressRepo,
+		leaderboardRepo,
+	)
+
+	weeklyGoalRepo := postgres.NewWeeklyGoalRepository(dbConn)
+	setWeeklyGoalCmd := command.NewSetWeeklyGoalHandler(weeklyGoalRepo, progressRepo)
+	weeklyGoalQuery := query.NewGetWeeklyGoalHandler(weeklyGoalRepo, progressRepo)
+	forecastQuery := query.NewGetForecastHandler(studentRepo, progressRepo, leaderboardRepo)
+	popularTasksQuery := query.NewGetPopularTasksHandler(socialRepo.HelpRequests(), postgres.NewTaskCatalog(dbConn))
+
+	// Челленджи: живые лидерборды хранятся в Redis
+	eventRepo := postgres.NewEventRepository(dbConn)
+	var eventQuery *query.GetEventLeaderboardHandler
+	if redisCache != nil {
+		eventQuery = query.NewGetEventLeaderboardHandler(eventRepo, redis.NewEventScoreboard(redisCache), studentRepo)
+	}
+
+	muteNotifsCmd := command.NewMuteNotificationsHandler(studentRepo)
+	reviewXPFlagCmd := command.NewReviewXPFlagHandler(postgres.NewXPFlagRepository(dbConn), studentRepo)
+
+	reportRepo := postgres.NewReportRepository(dbConn)
+	reportStudentCmd := command.NewReportStudentHandler(reportRepo, studentRepo, socialRepo)
+	reviewReportCmd := command.NewReviewReportHandler(reportRepo, studentRepo, studentRepo, auditLog, cfg.HelpReportThreshold)
+
+	// Фокус-сессии: таймеры - одноразовые задачи своего планировщика,
+	// после перезапуска бот восстанавливает их из focus_sessions
+	focusCmd := command.NewFocusSessionHandler(postgres.NewFocusRepository(dbConn), studentRepo, socialRepo.Connections(), progressRepo)
+	focusSchedulerConfig := scheduler.DefaultSchedulerConfig()
+	focusSchedulerConfig.Logger = log
+	focusScheduler := scheduler.NewScheduler(focusSchedulerConfig)
+
+	previewQuery := query.NewPreviewNotificationHandler(
+		studentRepo,
+		progressRepo,
+		leaderboardRepo,
+	)
+
+	commandUsageQuery := query.NewGetCommandUsageHandler(usageRepo)
+	responseTimesQuery := query.NewGetResponseTimesHandler(socialRepo.HelpRequests())
+	cohortXPSeriesQuery := query.NewGetCohortXPSeriesHandler(progressRepo)
+	feedQuery := query.NewGetFeedHandler(feedRepo, studentRepo)
+	activityCalendarQuery := query.NewGetActivityCalendarHandler(studentRepo, progressRepo)
+	helpSatisfactionQuery := query.NewGetHelpSatisfactionHandler(socialRepo.HelpFeedback())
+	taskDifficultyQuery := query.NewGetTaskDifficultyHandler(taskDifficultyRepo, postgres.NewTaskCatalog(dbConn))
+	topHelpersQuery := query.NewGetTopHelpersHandler(socialRepo.Endorsements(), displayNames)
+	endorsementsQuery := query.NewListEndorsementsHandler(socialRepo.Endorsements(), displayNames).WithContentBlocklist(contentBlocklist)
+	connectionsQuery := query.NewListConnectionsHandler(socialRepo.Connections(), displayNames)
+
 	// Sagas (сложные бизнес-процессы)
 	onboardingSaga := saga.NewOnboardingSaga(
 		studentRepo,
 		progressRepo,
 		leaderboardRepo,
 		notificationService,
+		notificationRepo,
 		sagaAlemAPIAdapter,
 		eventBus,
 		idGenerator,
+		postgres.NewSagaExecutionRepository(dbConn),
 		saga.DefaultOnboardingConfig(),
 	)
 
@@ -377,22 +682,65 @@ func run(ctx context.Context) error {
 	botConfig := telegram.DefaultBotConfig(cfg.TelegramToken)
 	botConfig.Mode = cfg.TelegramMode
 	botConfig.WebhookURL = cfg.TelegramWebhook
+	if cfg.TelegramMode == "webhook" {
+		botConfig.WebhookSecretToken = cfg.TelegramWebhookSecret
+	}
 	botConfig.Debug = cfg.AppDebug
 	botConfig.Logger = log
+	botConfig.AdminIDs = cfg.AdminIDs
+	botConfig.OffsetStore = postgres.NewBotStateRepository(dbConn)
+
+	dryRunOutbox := postgres.NewDryRunOutboxRepository(dbConn)
 
 	botDeps := telegram.BotDependencies{
+		Bus:                bus,
 		StudentRepo:        studentRepo,
-		SyncStudentCmd:     syncStudentCmd,
-		RequestHelpCmd:     requestHelpCmd,
-		ConnectStudentsCmd: connectStudentsCmd,
+		ProgressRepo:       progressRepo,
+		HelpRequestRepo:    socialRepo.HelpRequests(),
+		HelpFeedback:       socialRepo.HelpFeedback(),
+		AuditLog:           auditLog,
+		IdentityConflicts:  postgres.NewIdentityConflictRepository(dbConn),
+		DryRunOutbox:       dryRunOutbox,
+		ReportRepo:         reportRepo,
+		HelperStreaks:      helperStreakRepo,
+		HelpClusterCmd:     helpClusterCmd,
 		UpdatePrefsCmd:     updatePrefsCmd,
-		LeaderboardQuery:   leaderboardQuery,
-		StudentRankQuery:   studentRankQuery,
-		NeighborsQuery:     neighborsQuery,
 		FindHelpersQuery:   findHelpersQuery,
 		OnlineNowQuery:     onlineNowQuery,
 		DailyProgressQuery: dailyProgressQuery,
-		OnboardingSaga:     onboardingSaga,
+		NotificationsQuery: notificationsQuery,
+		AchievementsQuery:  achievementsQuery,
+		MarkNotifsReadCmd:  markNotifsReadCmd,
+		MergeStudentsCmd:   mergeStudentsCmd,
+		SetWeeklyGoalCmd:   setWeeklyGoalCmd,
+		MuteNotifsCmd:      muteNotifsCmd,
+		ReviewXPFlagCmd:    reviewXPFlagCmd,
+		ReportStudentCmd:   reportStudentCmd,
+		ReviewReportCmd:    reviewReportCmd,
+		FocusCmd:           focusCmd,
+		NudgeCmd:           nudgeCmd,
+		RenameStudentCmd:   renameStudentCmd,
+		RespondToHelpCmd:   respondToHelpCmd,
+		SnoozeHelpCmd:      snoozeHelpCmd,
+		AccessTokensCmd:    accessTokensCmd,
+		SyncMeCmd:          syncMeCmd,
+		RelinkCmd:          relinkCmd,
+		HelpTeamCmd:        helpTeamCmd,
+		FocusScheduler:     focusScheduler,
+		WeeklyGoalQuery:    weeklyGoalQuery,
+		ForecastQuery:      forecastQuery,
+		EventQuery:         eventQuery,
+		PopularTasksQuery:  popularTasksQuery,
+		FeedQuery:          feedQuery,
+		CompareQuery:       query.NewCompareStudentsHandler(studentRepo, leaderboardRepo, progressRepo),
+		ConnectionMilestonesQuery: query.NewGetConnectionMilestonesHandler(
+			socialRepo.Connections(), studentRepo, progressRepo),
+		UsageCounter:      usageCounter,
+		CommandUsageQuery: commandUsageQuery,
+		OnboardingSaga:    onboardingSaga,
+	}
+	if metricsRegistry != nil {
+		botDeps.Metrics = metricsRegistry
 	}
 
 	bot, err := telegram.NewBot(botConfig, botDeps)
@@ -400,6 +748,12 @@ func run(ctx context.Context) error {
 		return fmt.Errorf("failed to create bot: %w", err)
 	}
 
+	// Подбадривания уходят через фильтр заглушек, как и уведомления worker
+	nudgeCmd.WithNotifier(service.NewChannelSender(
+		service.NewMuteFilter(bot.Client(), studentRepo, log),
+		notification.DefaultDeliveryOptions(),
+	))
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// 12. СОЗДАНИЕ HTTP SERVER
 	// ─────────────────────────────────────────────────────────────────────────
@@ -408,15 +762,90 @@ func run(ctx context.Context) error {
 	httpConfig := httpserver.DefaultConfig()
 	httpConfig.Host = cfg.HTTPHost
 	httpConfig.Port = cfg.HTTPPort
+	httpConfig.APIKeys = cfg.HTTPAPIKeys
+	httpConfig.RateLimitPerMinute = cfg.HTTPRateLimit
+	httpConfig.PageRateLimitPerMinute = cfg.HTTPPageRateLimit
+	httpConfig.TokenRateLimitPerMinute = cfg.HTTPTokenRateLimit
+	httpConfig.StatsRateLimitPerMinute = cfg.HTTPStatsRateLimit
+	httpConfig.AdminIDs = cfg.AdminIDs
+	httpConfig.CursorSecret = cfg.HTTPCursorSecret
+	httpConfig.LandingOrigins = cfg.HTTPLandingOrigins
+	httpConfig.Version = version
+	if cfg.TelegramMode == "webhook" {
+		httpConfig.TelegramSecretToken = cfg.TelegramWebhookSecret
+	}
+
+	// Проверки готовности (/readyz); /healthz их не запускает
+	healthChecker := handlers.NewCompositeHealthChecker(version)
+	healthChecker.SetTimeout(cfg.HealthCheckTimeout)
+	healthChecker.AddCheck("database", handlers.NewDatabaseCheck(dbConn))
+	if redisCache != nil {
+		healthChecker.AddCheck("redis", handlers.NewCacheCheck(redisCache))
+	}
+	healthChecker.AddCheck("alem_api", handlers.NewExternalAPICheck(alemClient))
 
 	httpDeps := httpserver.Dependencies{
-		GetLeaderboardHandler:   leaderboardQuery,
-		GetStudentRankHandler:   studentRankQuery,
-		GetOnlineNowHandler:     onlineNowQuery,
-		GetNeighborsHandler:     neighborsQuery,
-		GetDailyProgressHandler: dailyProgressQuery,
-		FindHelpersHandler:      findHelpersQuery,
-		Logger:                  logger.Default(),
+		Bus: bus,
+		Public: httpserver.PublicDependencies{
+			GetOnlineNowHandler:        onlineNowQuery,
+			GetDailyProgressHandler:    dailyProgressQuery,
+			FindHelpersHandler:         findHelpersQuery,
+			GetNotificationsHandler:    notificationsQuery,
+			GetResponseTimesHandler:    responseTimesQuery,
+			GetTaskDifficultyHandler:   taskDifficultyQuery,
+			GetTopHelpersHandler:       topHelpersQuery,
+			ListEndorsementsHandler:    endorsementsQuery,
+			ListConnectionsHandler:     connectionsQuery,
+			GetForecastHandler:         forecastQuery,
+			GetCohortXPSeriesHandler:   cohortXPSeriesQuery,
+			GetFeedHandler:             feedQuery,
+			GetHelpSatisfactionHandler: helpSatisfactionQuery,
+			LeaderboardUpdates:         leaderboardUpdates,
+		},
+		Landing: httpserver.LandingDependencies{
+			Cohorts:      leaderboardRepo,
+			HelpRequests: socialRepo.HelpRequests(),
+			Streaks:      progressRepo,
+		},
+		Self: httpserver.SelfDependencies{
+			Tokens:                     service.NewAccessTokens(accessTokenRepo, log),
+			GetActivityCalendarHandler: activityCalendarQuery,
+		},
+		Admin: httpserver.AdminDependencies{
+			PreviewNotificationHandler: previewQuery,
+			GetCommandUsageHandler:     commandUsageQuery,
+			GetHelpSatisfactionHandler: helpSatisfactionQuery,
+			CohortSettings:             cohortSettings,
+			TriggerRules:               triggerRules,
+			FeatureFlags:               featureFlags,
+			ContentBlocklist:           contentBlocklist,
+			Webhooks:                   webhookRepo,
+			Events:                     eventRepo,
+			Experiments:                postgres.NewExperimentRepository(dbConn),
+			Backfill:                   postgres.NewBackfillRepository(dbConn),
+			DryRunOutbox:               dryRunOutbox,
+			LeaderboardBackup:          leaderboardBackup,
+			PreviewSender: httpserver.PreviewSenderFunc(func(ctx context.Context, chatID int64, html string) error {
+				_, err := bot.Client().SendHTML(ctx, chatID, html)
+				return err
+			}),
+		},
+		Health: httpserver.HealthDependencies{
+			HealthChecker: healthChecker,
+			OutboundStats: func() map[string]httpclient.Stats {
+				return map[string]httpclient.Stats{
+					"alem":     alemClient.HTTPStats(),
+					"telegram": bot.Client().HTTPStats(),
+					"webhooks": webhookClient.Stats(),
+				}
+			},
+			PreferencesDecodeFailures: postgres.PreferencesDecodeFailures,
+			CacheCircuit:              cacheCircuit,
+		},
+		Logger: logger.Default(),
+	}
+	if metricsRegistry != nil {
+		httpDeps.Metrics = metricsRegistry
 	}
 
 	httpServer := httpserver.NewServer(httpConfig, httpDeps)
@@ -437,6 +866,27 @@ func run(ctx context.Context) error {
 		}
 	}()
 
+	// Инвалидации in-process кешей от worker и других инстансов бота
+	if redisCache != nil {
+		subscriberConfig := messaging.DefaultInvalidationSubscriberConfig()
+		subscriberConfig.Logger = log
+		invalidationSubscriber := messaging.NewInvalidationSubscriber(
+			redis.NewPubSubClient(redisCache),
+			subscriberConfig,
+			bot.InvalidationTarget(),
+		)
+		go func() {
+			if err := invalidationSubscriber.Run(ctx); err != nil {
+				log.Error("cache invalidation subscriber stopped", "error", err)
+			}
+		}()
+	}
+
+	// Планировщик таймеров /focus
+	if err := focusScheduler.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start focus scheduler: %w", err)
+	}
+
 	// Запускаем Telegram бота
 	go func() {
 		log.Info("starting Telegram bot", "mode", cfg.TelegramMode)
@@ -453,9 +903,13 @@ func run(ctx context.Context) error {
 		"telegram_mode", cfg.TelegramMode,
 	)
 
+	// SIGHUP перечитывает конфигурацию без перезапуска
+	reloader := newConfigReloader(cfg, LoadConfig, log, logLevel, httpServer, featureFlags)
+	reloader.Watch(ctx)
+
 	// Ожидаем сигнал завершения или ошибку
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
 	select {
 	case sig := <-sigCh:
@@ -488,9 +942,14 @@ func run(ctx context.Context) error {
 		shutdownErr = err
 	}
 
-	// 3. Event bus закроется через defer
+	// 3. Останавливаем таймеры /focus (активные сессии восстановятся при старте)
+	if err := focusScheduler.Stop(); err != nil {
+		log.Error("failed to stop focus scheduler", "error", err)
+	}
+
+	// 4. Event bus закроется через defer
 
-	// 4. База данных закроется через defer
+	// 5. База данных закроется через defer
 
 	if shutdownErr != nil {
 		log.Warn("shutdown completed with errors")
@@ -505,16 +964,13 @@ func run(ctx context.Context) error {
 // HELPERS
 // ══════════════════════════════════════════════════════════════════════════════
 
-// setupLogger настраивает структурированное логирование.
-func setupLogger(cfg *Config) *slog.Logger {
+// setupLogger настраивает структурированное логирование. Уровень берётся из
+// level, чтобы его можно было менять на лету.
+func setupLogger(cfg *Config, level *slog.LevelVar) *slog.Logger {
 	var handler slog.Handler
 
 	opts := &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}
-
-	if cfg.AppDebug {
-		opts.Level = slog.LevelDebug
+		Level: level,
 	}
 
 	if cfg.AppEnv == "production" {
@@ -531,43 +987,15 @@ func setupLogger(cfg *Config) *slog.Logger {
 	return log
 }
 
-// getEnv возвращает переменную окружения или значение по умолчанию.
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
-// getEnvBool возвращает boolean переменную окружения.
-func getEnvBool(key string, defaultValue bool) bool {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-	return value == "true" || value == "1" || value == "yes"
-}
-
-// getEnvInt возвращает int переменную окружения.
-func getEnvInt(key string, defaultValue int) int {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-	if i, err := strconv.Atoi(value); err == nil {
-		return i
+// configLogLevel возвращает уровень логов из конфигурации.
+func configLogLevel(cfg *Config) slog.Level {
+	if cfg.AppDebug {
+		return slog.LevelDebug
 	}
-	return defaultValue
-}
 
-// getEnvDuration возвращает time.Duration переменную окружения.
-func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-	if d, err := time.ParseDuration(value); err == nil {
-		return d
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		return slog.LevelInfo
 	}
-	return defaultValue
+	return level
 }
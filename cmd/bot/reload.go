@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+
+	httpserver "github.com/alem-hub/alem-community-hub/internal/interface/http"
+	"github.com/alem-hub/alem-community-hub/pkg/config"
+	"github.com/alem-hub/alem-community-hub/pkg/featureflag"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CONFIG RELOAD
+// ══════════════════════════════════════════════════════════════════════════════
+
+// httpReloadTarget - настройки HTTP сервера, которые меняются на лету.
+type httpReloadTarget interface {
+	SetAPIKeys(keys []string)
+	SetRateLimits(limits httpserver.RateLimits)
+}
+
+// flagReloadTarget - фича-флаги, у которых меняется режим отказа.
+type flagReloadTarget interface {
+	SetFailMode(mode featureflag.FailMode)
+}
+
+// configReloader перечитывает конфигурацию по SIGHUP и применяет к
+// работающим компонентам поля с тегом reload:"true". Остальные изменения
+// (DATABASE_URL, токен бота, ...) только логируются как требующие
+// перезапуска: процесс продолжает работать со старыми значениями.
+type configReloader struct {
+	load     func() (*Config, error)
+	log      *slog.Logger
+	logLevel *slog.LevelVar
+	http     httpReloadTarget
+	flags    flagReloadTarget
+
+	mu      sync.Mutex
+	current *Config
+}
+
+// newConfigReloader создаёт reloader для запущенной конфигурации cfg.
+func newConfigReloader(
+	cfg *Config,
+	load func() (*Config, error),
+	log *slog.Logger,
+	logLevel *slog.LevelVar,
+	http httpReloadTarget,
+	flags flagReloadTarget,
+) *configReloader {
+	return &configReloader{
+		load:     load,
+		log:      log,
+		logLevel: logLevel,
+		http:     http,
+		flags:    flags,
+		current:  cfg,
+	}
+}
+
+// Watch подписывается на SIGHUP и перечитывает конфигурацию на каждый
+// сигнал, пока не отменён ctx. Подписка происходит до возврата, так что
+// сигнал сразу после вызова не завершит процесс.
+func (r *configReloader) Watch(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				_ = r.Reload()
+			}
+		}
+	}()
+}
+
+// Reload перечитывает конфигурацию и применяет изменения. При ошибке
+// загрузки текущая конфигурация остаётся без изменений.
+func (r *configReloader) Reload() error {
+	next, err := r.load()
+	if err != nil {
+		r.log.Error("config reload failed, keeping current configuration", "error", err)
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changes := config.Diff(r.current, next)
+	if len(changes) == 0 {
+		r.log.Info("config reloaded, nothing changed")
+		return nil
+	}
+
+	reloadable := reloadableKeys()
+	var applied, restart []string
+	for _, c := range changes {
+		if reloadable[c.Key] {
+			applied = append(applied, c.Key)
+			r.log.Info("config changed", "key", c.Key, "old", c.Old, "new", c.New)
+		} else {
+			restart = append(restart, c.Key)
+			r.log.Warn("config change requires restart", "key", c.Key, "old", c.Old, "new", c.New)
+		}
+	}
+
+	r.current = mergeReloadable(r.current, next)
+	r.apply(r.current)
+
+	r.log.Info("config reloaded", "applied", applied, "requires_restart", restart)
+	return nil
+}
+
+// Config возвращает действующую конфигурацию.
+func (r *configReloader) Config() *Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// apply передаёт перезагружаемые настройки компонентам.
+func (r *configReloader) apply(cfg *Config) {
+	r.logLevel.Set(configLogLevel(cfg))
+	if r.http != nil {
+		r.http.SetAPIKeys(cfg.HTTPAPIKeys)
+		r.http.SetRateLimits(httpserver.RateLimits{
+			PerIP:  cfg.HTTPRateLimit,
+			Pages:  cfg.HTTPPageRateLimit,
+			Tokens: cfg.HTTPTokenRateLimit,
+			Stats:  cfg.HTTPStatsRateLimit,
+		})
+	}
+	if r.flags != nil {
+		r.flags.SetFailMode(featureflag.FailMode(cfg.FeatureFlagFailMode))
+	}
+}
+
+// reloadableKeys возвращает переменные полей Config с тегом reload:"true".
+func reloadableKeys() map[string]bool {
+	t := reflect.TypeOf(Config{})
+	keys := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Tag.Get("reload") == "true" {
+			keys[field.Tag.Get("env")] = true
+		}
+	}
+	return keys
+}
+
+// mergeReloadable возвращает running с перезагружаемыми полями из next.
+func mergeReloadable(running, next *Config) *Config {
+	merged := *running
+	mv, nv := reflect.ValueOf(&merged).Elem(), reflect.ValueOf(next).Elem()
+	for i := 0; i < mv.NumField(); i++ {
+		if mv.Type().Field(i).Tag.Get("reload") == "true" {
+			mv.Field(i).Set(nv.Field(i))
+		}
+	}
+	return &merged
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpserver "github.com/alem-hub/alem-community-hub/internal/interface/http"
+	"github.com/alem-hub/alem-community-hub/pkg/featureflag"
+)
+
+func adminStatus(server *httpserver.Server, key string) int {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/experiments", nil)
+	req.Header.Set("X-API-Key", key)
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestConfigReloader_SIGHUP(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_TOKEN", "123:abc")
+	t.Setenv("DATABASE_URL", "postgres://hub:pw@db/hub")
+	t.Setenv("LOG_LEVEL", "info")
+	t.Setenv("HTTP_API_KEYS", "old-key")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(configLogLevel(cfg))
+	httpConfig := httpserver.DefaultConfig()
+	httpConfig.APIKeys = cfg.HTTPAPIKeys
+	server := httpserver.NewServer(httpConfig, httpserver.Dependencies{})
+	flags := featureflag.NewClient(nil)
+
+	// Админские эндпоинты без зависимостей отвечают 501 после проверки ключа
+	require.Equal(t, http.StatusNotImplemented, adminStatus(server, "old-key"))
+	require.Equal(t, http.StatusUnauthorized, adminStatus(server, "new-key"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	reloader := newConfigReloader(cfg, LoadConfig, log, logLevel, server, flags)
+	reloader.Watch(ctx)
+
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("HTTP_API_KEYS", "new-key")
+	t.Setenv("DATABASE_URL", "postgres://hub:pw@other/hub")
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+
+	assert.Eventually(t, func() bool { return logLevel.Level() == slog.LevelDebug }, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return adminStatus(server, "new-key") == http.StatusNotImplemented
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, http.StatusUnauthorized, adminStatus(server, "old-key"))
+
+	// DATABASE_URL не применяется на лету: работаем со старым до перезапуска
+	assert.Equal(t, "postgres://hub:pw@db/hub", reloader.Config().DatabaseURL)
+	assert.Equal(t, []string{"new-key"}, reloader.Config().HTTPAPIKeys)
+}
+
+func TestConfigReloader_KeepsConfigOnError(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_TOKEN", "123:abc")
+	t.Setenv("DATABASE_URL", "postgres://hub:pw@db/hub")
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+
+	logLevel := new(slog.LevelVar)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	reloader := newConfigReloader(cfg, LoadConfig, log, logLevel, nil, nil)
+
+	t.Setenv("LOG_LEVEL", "verbose")
+	assert.Error(t, reloader.Reload())
+	assert.Same(t, cfg, reloader.Config())
+	assert.Equal(t, slog.LevelInfo, logLevel.Level())
+}
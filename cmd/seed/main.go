@@ -0,0 +1,117 @@
+// Package main - генератор синтетических данных для нагрузочного тестирования
+// Alem Community Hub.
+//
+// Seed создаёт N студентов в M когортах с правдоподобным (степенным)
+// распределением XP, историей XP, дневным прогрессом и сериями за последние
+// дни, а также социальный граф связей и благодарностей. Данные детерминированы
+// значением -seed, поэтому бенчмарки лидерборда и дайджестов воспроизводимы.
+//
+// Запуск в production запрещён (APP_ENV=production).
+//
+//	DATABASE_URL=postgres://... go run ./cmd/seed -students 20000 -cohorts 8 -seed 42
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/persistence/postgres"
+	"github.com/alem-hub/alem-community-hub/internal/testutil/seed"
+)
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "seed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	cfg := seed.DefaultConfig()
+
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.Int64Var(&cfg.Seed, "seed", cfg.Seed, "random seed (same seed = same data)")
+	fs.IntVar(&cfg.Students, "students", cfg.Students, "number of students")
+	fs.IntVar(&cfg.Cohorts, "cohorts", cfg.Cohorts, "number of cohorts")
+	fs.IntVar(&cfg.Days, "days", cfg.Days, "days of xp history and daily grinds")
+	fs.Float64Var(&cfg.ConnectionsPerStudent, "connections", cfg.ConnectionsPerStudent, "average connections per student")
+	fs.Float64Var(&cfg.EndorsementsPerStudent, "endorsements", cfg.EndorsementsPerStudent, "average endorsements given per student")
+	fs.IntVar(&cfg.ChunkSize, "chunk", cfg.ChunkSize, "students generated and written per chunk")
+	fs.Int64Var(&cfg.TelegramIDBase, "telegram-id-base", cfg.TelegramIDBase, "first synthetic telegram id")
+	now := fs.String("now", "", "anchor date of the history window (YYYY-MM-DD, default today)")
+	migrate := fs.Bool("migrate", true, "apply migrations before seeding")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Защита от случайного запуска против боевой базы
+	if env := os.Getenv("APP_ENV"); env == "production" {
+		return errors.New("refusing to seed with APP_ENV=production")
+	}
+
+	if *now != "" {
+		t, err := time.Parse(time.DateOnly, *now)
+		if err != nil {
+			return fmt.Errorf("invalid -now: %w", err)
+		}
+		cfg.Now = t
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		return errors.New("DATABASE_URL is required")
+	}
+
+	dbConn, err := postgres.NewConnectionFromURL(ctx, databaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer dbConn.Close()
+
+	if *migrate {
+		if err := postgres.NewMigrator(dbConn).Migrate(ctx); err != nil {
+			return fmt.Errorf("failed to run migrations: %w", err)
+		}
+	}
+
+	socialRepo := postgres.NewSocialRepository(dbConn)
+	seeder, err := seed.New(cfg, seed.Stores{
+		Students:     postgres.NewStudentRepository(dbConn),
+		Progress:     postgres.NewProgressRepository(dbConn),
+		Connections:  socialRepo.Connections(),
+		Endorsements: socialRepo.Endorsements(),
+	})
+	if err != nil {
+		return err
+	}
+	seeder.OnChunk = func(phase string, done, total int) {
+		fmt.Fprintf(os.Stderr, "\r%-8s %d/%d", phase, done, total)
+		if done == total {
+			fmt.Fprintln(os.Stderr)
+		}
+	}
+
+	fmt.Printf("seeding %d students in %d cohorts (seed=%d, days=%d)\n",
+		cfg.Students, cfg.Cohorts, cfg.Seed, cfg.Days)
+
+	result, err := seeder.Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(result)
+	return nil
+}
@@ -16,105 +16,181 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/google/uuid"
+
 	// Infrastructure layer
 	// Infrastructure layer
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/application/eventhandler"
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/application/saga"
+	"github.com/alem-hub/alem-community-hub/internal/domain/backfill"
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
 	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/alem"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
 	"github.com/alem-hub/alem-community-hub/internal/infrastructure/messaging"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/metrics"
 	"github.com/alem-hub/alem-community-hub/internal/infrastructure/persistence/postgres"
 	"github.com/alem-hub/alem-community-hub/internal/infrastructure/persistence/redis"
 	"github.com/alem-hub/alem-community-hub/internal/infrastructure/scheduler"
 	"github.com/alem-hub/alem-community-hub/internal/infrastructure/scheduler/jobs"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/service"
+
+	// Packages
+	"github.com/alem-hub/alem-community-hub/pkg/config"
+	"github.com/alem-hub/alem-community-hub/pkg/featureflag"
+	"github.com/alem-hub/alem-community-hub/pkg/sharecard"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
 // CONFIGURATION
 // ══════════════════════════════════════════════════════════════════════════════
 
-// Config содержит всю конфигурацию Worker приложения.
+// Config содержит всю конфигурацию worker.
+// Поля заполняются из переменных окружения по тегам env (см. pkg/config).
 type Config struct {
 	// App
-	AppEnv      string // development, staging, production
-	AppDebug    bool
-	AppTimezone string
+	AppEnv      string `env:"APP_ENV" default:"development"` // development, staging, production
+	AppDebug    bool   `env:"APP_DEBUG"`
+	AppTimezone string `env:"APP_TIMEZONE" default:"Asia/Almaty"`
 
-	// PostgreSQL (Supabase)
-	DatabaseURL string
+	// PostgreSQL (Supabase), пароль в URL маскируется при выводе
+	DatabaseURL   string `env:"DATABASE_URL" required:"true"`
+	RunMigrations bool   `env:"RUN_MIGRATIONS" default:"true"` // false - миграции применяет другой процесс, здесь только проверка версии схемы
 
 	// Redis (опционально, для кеширования)
-	RedisURL     string
-	RedisEnabled bool
+	RedisURL     string `env:"REDIS_URL"`
+	RedisEnabled bool   `env:"REDIS_ENABLED"`
 
 	// Alem Platform API
-	AlemAPIURL       string
-	AlemAPIToken     string
-	AlemRateLimit    int           // requests per minute
-	AlemSyncInterval time.Duration // как часто синхронизировать
+	AlemAPIURL       string        `env:"ALEM_API_URL" default:"https://platform.alem.school"`
+	AlemAPIToken     string        `env:"ALEM_API_TOKEN" secret:"true"`
+	AlemRateLimit    int           `env:"ALEM_RATE_LIMIT" default:"10"`    // requests per minute
+	AlemSyncInterval time.Duration `env:"ALEM_SYNC_INTERVAL" default:"5m"` // как часто синхронизировать
 
 	// Telegram (для отправки уведомлений)
-	TelegramToken string
+	TelegramToken string `env:"TELEGRAM_BOT_TOKEN" secret:"true"`
 
 	// Scheduler
-	SyncStudentsInterval    time.Duration
-	RebuildLeaderboardCron  string // cron expression
-	DetectInactiveInterval  time.Duration
-	DailyDigestTime         string // время в формате "HH:MM"
-	DailyDigestEnabled      bool
-	InactivityThresholdDays int
+	SyncStudentsInterval    time.Duration `env:"SYNC_STUDENTS_INTERVAL" default:"5m"`
+	RebuildLeaderboardCron  string        `env:"REBUILD_LEADERBOARD_CRON" default:"*/10 * * * *"`
+	DetectInactiveInterval  time.Duration `env:"DETECT_INACTIVE_INTERVAL" default:"1h"`
+	DailyDigestTime         string        `env:"DAILY_DIGEST_TIME" default:"21:00"` // время в формате "HH:MM"
+	DailyDigestEnabled      bool          `env:"DAILY_DIGEST_ENABLED" default:"true"`
+	DigestShards            int           `env:"DIGEST_SHARDS" default:"1"`              // на сколько шардов делить получателей дайджеста
+	DigestSpreadWindow      time.Duration `env:"DIGEST_SPREAD_WINDOW" default:"30m"`     // за какое время после начала часа запускаются все шарды
+	DigestMaxParallelShards int           `env:"DIGEST_MAX_PARALLEL_SHARDS" default:"2"` // сколько шардов рассылаются одновременно
+	InactivityThresholdDays int           `env:"INACTIVITY_THRESHOLD_DAYS" default:"3"`
+	UsageFlushCron          string        `env:"USAGE_FLUSH_CRON" default:"30 5 * * *"`     // выгрузка статистики команд за прошедшие сутки (00:30 UTC в Asia/Almaty)
+	HelpEscalationWindow    time.Duration `env:"HELP_ESCALATION_WINDOW" default:"30m"`      // сколько срочный запрос помощи ждёт ответа до эскалации менторам
+	StuckDetectionCron      string        `env:"STUCK_DETECTION_CRON" default:"0 14 * * *"` // поиск застрявших студентов (раз в день, днём)
+	TaskDifficultyCron      string        `env:"TASK_DIFFICULTY_CRON" default:"15 4 * * *"` // пересчёт сложности задач (раз в день, ночью)
+	EventAnnouncementChatID int64         `env:"EVENT_ANNOUNCEMENT_CHAT_ID"`                // чат для объявления победителей челленджей (0 - не объявлять)
+	AnnouncementQuietStart  int           `env:"ANNOUNCEMENT_QUIET_START" default:"23"`     // начало тихих часов общих чатов (час по APP_TIMEZONE), если чат не настроил иначе
+	AnnouncementQuietEnd    int           `env:"ANNOUNCEMENT_QUIET_END" default:"8"`        // конец тихих часов; объявления из очереди уходят после него
+	PersonalBestsCron       string        `env:"PERSONAL_BESTS_CRON" default:"45 5 * * *"`  // обновление рекордов дня и недели (00:45 UTC в Asia/Almaty)
+	CohortReportCron        string        `env:"COHORT_REPORT_CRON" default:"0 9 * * 1"`    // еженедельный отчёт кураторам (понедельник утром)
+	CohortReportCSV         bool          `env:"COHORT_REPORT_CSV"`                         // прикладывать CSV к отчёту, если поток не настроил иначе
+	XPReviewChatID          int64         `env:"XP_REVIEW_CHAT_ID"`                         // чат кураторов для проверки всплесков XP (0 - только помечать)
+	XPSpikeWindowDays       int           `env:"XP_SPIKE_WINDOW_DAYS" default:"14"`         // за сколько дней считается обычный темп студента
+	XPSpikeK                float64       `env:"XP_SPIKE_K" default:"3"`                    // всплеск - день выше среднего на K стандартных отклонений
+	XPSpikeMinXP            int           `env:"XP_SPIKE_MIN_XP" default:"2000"`            // и не меньше этого XP за день
+
+	// Хранение истории
+	RetentionCron              string        `env:"RETENTION_CRON" default:"30 3 * * *"`        // чистка старых строк (ночью, когда нагрузка минимальна)
+	RetentionXPHistoryMonths   int           `env:"RETENTION_XP_HISTORY_MONTHS" default:"12"`   // сырая история XP; старше - только помесячные итоги (0 - хранить всё)
+	RetentionNotificationsDays int           `env:"RETENTION_NOTIFICATIONS_DAYS" default:"90"`  // 0 - хранить всё
+	RetentionCommandUsageDays  int           `env:"RETENTION_COMMAND_USAGE_DAYS" default:"183"` // 0 - хранить всё
+	RetentionAuditLogDays      int           `env:"RETENTION_AUDIT_LOG_DAYS" default:"730"`     // 0 - хранить всё
+	RetentionBatchSize         int           `env:"RETENTION_BATCH_SIZE" default:"5000"`        // строк за один DELETE
+	RetentionBatchPause        time.Duration `env:"RETENTION_BATCH_PAUSE" default:"200ms"`      // пауза между пачками
+
+	// Одноразовые задачи
+	BackfillCohortAchievements bool `env:"BACKFILL_COHORT_ACHIEVEMENTS"`            // выдать достижения потока текущим лидерам
+	MigratePreferences         bool `env:"MIGRATE_PREFERENCES"`                     // переписать настройки уведомлений в формат v2
+	BackfillPersonalBests      bool `env:"BACKFILL_PERSONAL_BESTS"`                 // посчитать личные рекорды по истории без уведомлений
+	BackfillActivity           bool `env:"BACKFILL_ACTIVITY"`                       // загрузить полную историю выполненных задач из Alem
+	BackfillActivityDryRun     bool `env:"BACKFILL_ACTIVITY_DRY_RUN"`               // только посчитать, сколько выполнений добавится
+	BackfillActivityRatePct    int  `env:"BACKFILL_ACTIVITY_RATE_PCT" default:"25"` // доля лимита Alem для загрузки, %
 
 	// Bootcamp Config
-	BootcampID string
-	CohortID   string
+	BootcampID string `env:"ALEM_BOOTCAMP_ID" default:"7ed99bd0-87b2-4dbb-a97b-596c3f29c49b"`
+	CohortID   string `env:"ALEM_COHORT_ID" default:"005ed731-6eb5-47df-8268-7011aeb3e4bf"`
 
 	// Service Account for Alem API (for testing)
-	AlemServiceEmail    string
-	AlemServicePassword string
+	AlemServiceEmail    string `env:"ALEM_SERVICE_EMAIL"`
+	AlemServicePassword string `env:"ALEM_SERVICE_PASSWORD" secret:"true"`
+
+	// Режим симуляции: задачи работают на настоящих данных, но сообщения
+	// пишутся в dry_run_outbox вместо отправки
+	DryRun         bool     `env:"WORKER_DRY_RUN"`          // симуляция для всех задач и обработчиков событий
+	DryRunJobs     []string `env:"WORKER_DRY_RUN_JOBS"`     // симуляция только для этих задач ("daily_digest" включает шарды)
+	LiveJobs       []string `env:"WORKER_LIVE_JOBS"`        // задачи, которые отправляют по-настоящему даже при WORKER_DRY_RUN
+	DryRunSuppress []string `env:"WORKER_DRY_RUN_SUPPRESS"` // что ещё пропускать в симуляции: status_updates, cache_rebuilds, delivery_markers
+
+	// Метрики Prometheus (задачи планировщика, очередь событий, кеш лидерборда)
+	MetricsEnabled bool   `env:"METRICS_ENABLED"`
+	MetricsAddr    string `env:"WORKER_METRICS_ADDR" default:":9091"` // адрес GET /metrics у worker
 
 	// Graceful Shutdown
-	ShutdownTimeout time.Duration
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" default:"60s"`
 }
 
 // LoadConfig загружает конфигурацию из переменных окружения.
+// Возвращает сразу все ошибки: отсутствующие и некорректные переменные.
 func LoadConfig() (*Config, error) {
-	cfg := &Config{
-		AppEnv:                  getEnv("APP_ENV", "development"),
-		AppDebug:                getEnvBool("APP_DEBUG", false),
-		AppTimezone:             getEnv("APP_TIMEZONE", "Asia/Almaty"),
-		DatabaseURL:             getEnv("DATABASE_URL", ""),
-		RedisURL:                getEnv("REDIS_URL", ""),
-		RedisEnabled:            getEnvBool("REDIS_ENABLED", false),
-		AlemAPIURL:              getEnv("ALEM_API_URL", "https://platform.alem.school"),
-		AlemAPIToken:            getEnv("ALEM_API_TOKEN", ""),
-		AlemRateLimit:           getEnvInt("ALEM_RATE_LIMIT", 10),
-		AlemSyncInterval:        getEnvDuration("ALEM_SYNC_INTERVAL", 5*time.Minute),
-		TelegramToken:           getEnv("TELEGRAM_BOT_TOKEN", ""),
-		SyncStudentsInterval:    getEnvDuration("SYNC_STUDENTS_INTERVAL", 5*time.Minute),
-		RebuildLeaderboardCron:  getEnv("REBUILD_LEADERBOARD_CRON", "*/10 * * * *"),
-		DetectInactiveInterval:  getEnvDuration("DETECT_INACTIVE_INTERVAL", 1*time.Hour),
-		DailyDigestTime:         getEnv("DAILY_DIGEST_TIME", "21:00"),
-		DailyDigestEnabled:      getEnvBool("DAILY_DIGEST_ENABLED", true),
-		InactivityThresholdDays: getEnvInt("INACTIVITY_THRESHOLD_DAYS", 3),
-		BootcampID:              getEnv("ALEM_BOOTCAMP_ID", "7ed99bd0-87b2-4dbb-a97b-596c3f29c49b"),
-		CohortID:                getEnv("ALEM_COHORT_ID", "005ed731-6eb5-47df-8268-7011aeb3e4bf"),
-		AlemServiceEmail:        getEnv("ALEM_SERVICE_EMAIL", ""),
-		AlemServicePassword:     getEnv("ALEM_SERVICE_PASSWORD", ""),
-		ShutdownTimeout:         getEnvDuration("SHUTDOWN_TIMEOUT", 60*time.Second),
-	}
-
-	// Валидация обязательных полей
-	if cfg.DatabaseURL == "" {
-		return nil, errors.New("DATABASE_URL is required")
+	cfg := &Config{}
+
+	loader := config.New(nil)
+	if err := loader.Bind(cfg); err != nil {
+		return nil, err
 	}
 
+	for _, effect := range cfg.DryRunSuppress {
+		if !slices.Contains(notification.DryRunEffects(), notification.DryRunEffect(effect)) {
+			loader.Errorf("WORKER_DRY_RUN_SUPPRESS: unknown effect %q", effect)
+		}
+	}
+
+	if err := loader.Err(); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
+// DryRunPolicy собирает режим симуляции из конфигурации.
+// WORKER_LIVE_JOBS сильнее WORKER_DRY_RUN_JOBS для одного и того же имени.
+func (c *Config) DryRunPolicy() notification.DryRunPolicy {
+	policy := notification.DryRunPolicy{
+		Global: c.DryRun,
+		Jobs:   make(map[string]bool, len(c.DryRunJobs)+len(c.LiveJobs)),
+	}
+	for _, job := range c.DryRunJobs {
+		policy.Jobs[job] = true
+	}
+	for _, job := range c.LiveJobs {
+		policy.Jobs[job] = false
+	}
+	for _, effect := range c.DryRunSuppress {
+		policy.Suppress = append(policy.Suppress, notification.DryRunEffect(effect))
+	}
+	return policy
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // MAIN
 // ══════════════════════════════════════════════════════════════════════════════
@@ -144,11 +220,13 @@ func run(ctx context.Context) error {
 	// 2. НАСТРОЙКА ЛОГИРОВАНИЯ
 	// ─────────────────────────────────────────────────────────────────────────
 	log := setupLogger(cfg)
+	student.SetRevealTelegramIDs(cfg.AppDebug) // полные Telegram ID в логах только при отладке
 	log.Info("starting Alem Community Hub Worker",
 		"env", cfg.AppEnv,
 		"debug", cfg.AppDebug,
 		"timezone", cfg.AppTimezone,
 	)
+	config.Dump(log, cfg)
 
 	// ─────────────────────────────────────────────────────────────────────────
 	// 3. ПОДКЛЮЧЕНИЕ К БАЗЕ ДАННЫХ (PostgreSQL/Supabase)
@@ -173,17 +251,31 @@ func run(ctx context.Context) error {
 	// 4. ЗАПУСК МИГРАЦИЙ (Worker также должен иметь актуальную схему)
 	// ─────────────────────────────────────────────────────────────────────────
 	log.Info("checking database migrations...")
-	migrator := postgres.NewMigrator(dbConn)
-	if err := migrator.Migrate(ctx); err != nil {
-		return fmt.Errorf("failed to run migrations: %w", err)
+	migrator := postgres.NewMigrator(dbConn).WithLogger(log)
+	if cfg.RunMigrations {
+		if err := migrator.Migrate(ctx); err != nil {
+			return fmt.Errorf("failed to run migrations: %w", err)
+		}
+	} else {
+		log.Info("migrations disabled (RUN_MIGRATIONS=false), checking schema version only")
+	}
+	if err := migrator.CheckSchemaVersion(ctx); err != nil {
+		return err
 	}
 	log.Info("database schema is up to date")
 
+	// Метрики Prometheus (nil - выключены, вызовы ничего не записывают)
+	var metricsRegistry *metrics.Registry
+	if cfg.MetricsEnabled {
+		metricsRegistry = metrics.New()
+	}
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// 5. ИНИЦИАЛИЗАЦИЯ REDIS (опционально)
 	// ─────────────────────────────────────────────────────────────────────────
 	var redisCache *redis.Cache
 	var leaderboardCache *redis.LeaderboardCache
+	var displayNameCache student.DisplayNameCache
 
 	if cfg.RedisEnabled && cfg.RedisURL != "" {
 		log.Info("connecting to Redis...")
@@ -205,28 +297,40 @@ func run(ctx context.Context) error {
 		} else {
 			defer redisCache.Close()
 			leaderboardCache = redis.NewLeaderboardCache(redisCache)
+			displayNameCache = redis.NewDisplayNameCache(redisCache)
+			metricsRegistry.LookupGauge("leaderboard_cache_entries", "Students in the cached overall leaderboard.",
+				func(ctx context.Context) (float64, error) {
+					n, err := leaderboardCache.GetCount(ctx, "")
+					return float64(n), err
+				})
 			log.Info("Redis connection established")
 		}
 	}
 
-	// Suppress unused variable warnings
-	_ = leaderboardCache
-
 	// ─────────────────────────────────────────────────────────────────────────
 	// 6. ИНИЦИАЛИЗАЦИЯ РЕПОЗИТОРИЕВ
 	// ─────────────────────────────────────────────────────────────────────────
 	log.Info("initializing repositories...")
-	studentRepo := postgres.NewStudentRepository(dbConn)
+	studentRepo := postgres.NewStudentRepository(dbConn).WithDisplayNameCache(displayNameCache)
 	progressRepo := postgres.NewProgressRepository(dbConn)
 	leaderboardRepo := postgres.NewLeaderboardRepository(dbConn)
 	syncRepo := postgres.NewSyncRepository(dbConn)
 	activityRepo := postgres.NewActivityRepository(dbConn)
+	usageRepo := postgres.NewCommandUsageRepository(dbConn)
+	notificationRepo := postgres.NewNotificationRepository(dbConn)
+	sagaExecutionRepo := postgres.NewSagaExecutionRepository(dbConn)
+
+	// Флаги функций: строки таблицы создаёт бот, до первой загрузки
+	// действуют режимы отказа из settings.BuiltinFeatureFlags
+	featureFlags := featureflag.NewClient(postgres.NewFeatureFlagRepository(dbConn),
+		featureflag.WithLogger(log), featureflag.WithDefaults(settings.BuiltinFeatureFlags()...))
+	featureflag.SetDefault(featureFlags)
+	go featureFlags.Run(ctx)
 
 	// Suppress unused variable warnings
 	_ = studentRepo
 	_ = progressRepo
 	_ = leaderboardRepo
-	_ = syncRepo
 	_ = activityRepo
 
 	// ─────────────────────────────────────────────────────────────────────────
@@ -241,9 +345,26 @@ func run(ctx context.Context) error {
 		log.Info("closing event bus...")
 		_ = eventBus.Close()
 	}()
+	metricsRegistry.Gauge("event_bus_queue_depth", "Event handler runs waiting for a worker or running.",
+		func() float64 { return float64(eventBus.QueueDepth()) })
 
-	// Suppress unused variable warning
-	_ = eventBus
+	// Личные рекорды записывает задача RecordPersonalBests
+	recordPersonalBestCmd := command.NewRecordPersonalBestHandler(
+		postgres.NewPersonalBestRepository(dbConn),
+		eventBus,
+	)
+
+	// Лента сообщества: рекорды, достижения и помощник недели из задач воркера
+	feedRepo := postgres.NewFeedRepository(dbConn)
+	feedProjection := eventhandler.NewFeedProjectionHandler(
+		feedRepo,
+		studentRepo,
+		service.NewCohortSettings(postgres.NewCohortSettingsRepository(dbConn), log),
+		log,
+	)
+	if err := feedProjection.Subscribe(eventBus); err != nil {
+		log.Error("failed to subscribe feed projection", "error", err)
+	}
 
 	// ─────────────────────────────────────────────────────────────────────────
 	// 8. ИНИЦИАЛИЗАЦИЯ ВНЕШНИХ КЛИЕНТОВ
@@ -272,6 +393,116 @@ func run(ctx context.Context) error {
 		log.Warn("Alem Platform credentials not provided, bootcamp sync will be limited")
 	}
 
+	// Уведомления "напарник онлайн": синхронизация публикует переходы в онлайн
+	dryRunPolicy := cfg.DryRunPolicy()
+	dryRunOutbox := postgres.NewDryRunOutboxRepository(dbConn)
+	if dryRunPolicy.Active() {
+		log.Warn("worker dry run is active: messages are recorded to dry_run_outbox instead of being sent",
+			"global", dryRunPolicy.Global,
+			"jobs", dryRunPolicy.Jobs,
+			"suppress", dryRunPolicy.Suppress,
+		)
+	}
+
+	// A/B эксперименты с формулировками уведомлений
+	experimentRepo := postgres.NewExperimentRepository(dbConn)
+
+	// Правила триггеров читаются из таблицы (её заполняет бот при старте)
+	// через снимок, который обновляется раз в минуту
+	triggerRules := service.NewTriggerRules(postgres.NewTriggerRuleRepository(dbConn), log)
+
+	var telegramSender *service.ChannelSender
+	var reportSender jobs.ReportSender
+	if cfg.TelegramToken != "" {
+		tgConfig := telegram.DefaultClientConfig(cfg.TelegramToken)
+		tgConfig.Logger = log
+		// Все отправки worker идут через guard: в режиме симуляции
+		// сообщения попадают в outbox, а не в Telegram
+		tgClient := service.NewDryRunGuard(telegram.NewClient(tgConfig), dryRunOutbox, dryRunPolicy, log)
+		reportSender = tgClient
+
+		// Без Redis кулдауны живут до перезапуска worker
+		var cooldowns notification.CooldownStore = service.NewInMemoryCooldownStore()
+		if redisCache != nil {
+			cooldowns = redis.NewNotificationCooldown(redisCache)
+		}
+
+		// Лимиты Telegram (30 сообщений в секунду, одно в секунду на чат)
+		// общие для всех уведомлений worker. Заглушки (/mute) и настройки
+		// получателя проверяет фильтр перед лимитом: пропущенные
+		// уведомления не занимают очередь
+		telegramSender = service.NewChannelSender(
+			service.NewMuteFilter(
+				service.NewRateLimitedChannel(tgClient, service.DefaultTelegramRateLimit()),
+				studentRepo,
+				log,
+			),
+			notification.DefaultDeliveryOptions(),
+		)
+		buddyOnlineHandler := eventhandler.NewOnStudentOnlineHandler(
+			studentRepo,
+			postgres.NewSocialRepository(dbConn).Connections(),
+			cooldowns,
+			telegramSender,
+			log,
+			eventhandler.DefaultStudentOnlineConfig(),
+		)
+		if err := eventBus.Subscribe(shared.EventStudentWentOnline, buddyOnlineHandler.Handle); err != nil {
+			log.Error("failed to subscribe buddy online handler", "error", err)
+		}
+
+		// Предложение помощи застрявшим: события публикует DetectStuckStudents
+		stuckHandler := eventhandler.NewOnStudentPlateauHandler(
+			studentRepo,
+			cooldowns,
+			telegramSender,
+			log,
+			eventhandler.DefaultStudentPlateauConfig(),
+		)
+		if err := eventBus.Subscribe(shared.EventStudentStuck, stuckHandler.Handle); err != nil {
+			log.Error("failed to subscribe stuck help handler", "error", err)
+		}
+
+		// Поздравления с личными рекордами: события публикует RecordPersonalBest
+		personalBestHandler := eventhandler.NewOnPersonalBestHandler(studentRepo, telegramSender, log)
+		if err := eventBus.Subscribe(shared.EventPersonalBestSet, personalBestHandler.Handle); err != nil {
+			log.Error("failed to subscribe personal best handler", "error", err)
+		}
+
+		// Вехи серии с карточкой: события публикует SyncAllStudents
+		milestoneHandler := eventhandler.NewOnStreakMilestoneHandler(
+			studentRepo,
+			sharecard.NewRenderer(sharecard.DefaultRendererConfig()),
+			telegramSender,
+			log,
+		)
+		if err := eventBus.Subscribe(shared.EventStreakMilestone, milestoneHandler.Handle); err != nil {
+			log.Error("failed to subscribe streak milestone handler", "error", err)
+		}
+
+		// Уведомления о смене ранга (за флагом rank_change_notifications):
+		// события публикуют RebuildLeaderboard и SyncStudent. Кеш лидерборда
+		// обновляет сам RebuildLeaderboard, поэтому обработчику он не нужен.
+		// Формулировку может выбрать идущий A/B эксперимент, порог повышения
+		// задаёт правило rank_up. Отправленные уведомления попадают в
+		// /notifications.
+		rankChangedHandler := eventhandler.NewOnRankChangedHandler(
+			studentRepo,
+			telegramSender,
+			nil,
+			service.NewCohortSettings(postgres.NewCohortSettingsRepository(dbConn), log),
+			log,
+			eventhandler.DefaultRankChangedConfig(),
+		).WithExperiments(eventhandler.NewNotificationExperiments(experimentRepo, log)).
+			WithTriggerRules(service.NewNotificationServiceStub(log).WithTriggerRules(triggerRules)).
+			WithHistory(notificationRepo)
+		if err := eventBus.Subscribe(shared.EventRankChanged, rankChangedHandler.Handle); err != nil {
+			log.Error("failed to subscribe rank changed handler", "error", err)
+		}
+	} else {
+		log.Warn("TELEGRAM_BOT_TOKEN not set, buddy online and stuck help notifications disabled")
+	}
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// 9. ИНИЦИАЛИЗАЦИЯ SCHEDULER И ЗАПУСК JOBS
 	// ─────────────────────────────────────────────────────────────────────────
@@ -279,11 +510,28 @@ func run(ctx context.Context) error {
 
 	schedulerConfig := scheduler.DefaultSchedulerConfig()
 	schedulerConfig.Logger = log
-	if loc, err := time.LoadLocation(cfg.AppTimezone); err == nil {
+	schedulerConfig.EnableMetrics = cfg.MetricsEnabled
+	if loc, err := timeutil.LoadLocation(cfg.AppTimezone); err == nil {
 		schedulerConfig.Timezone = loc
 	}
 
 	sch := scheduler.NewScheduler(schedulerConfig)
+	if metricsRegistry != nil {
+		sch.OnJobStart(metricsRegistry.JobStarted)
+		sch.OnJobComplete(func(result scheduler.JobResult) {
+			metricsRegistry.JobCompleted(result.JobName, result.Duration, result.Error)
+		})
+		sch.OnJobError(metricsRegistry.JobFailed)
+	}
+	sch.OnJobRun(func(ctx context.Context, jobName string) (context.Context, func(map[string]interface{})) {
+		run := dryRunPolicy.ForJob(jobName)
+		return notification.WithDryRun(ctx, run), func(metadata map[string]interface{}) {
+			if run.Enabled {
+				metadata["dry_run"] = true
+				metadata["dry_run_sends"] = run.Recorded()
+			}
+		}
+	})
 
 	// Job: SyncAllStudents
 	syncJob := jobs.NewSyncAllStudentsJob(
@@ -297,17 +545,483 @@ func run(ctx context.Context) error {
 		jobs.SyncAllStudentsConfig{
 			BatchSize:     50,
 			RetryAttempts: 3,
-			BootcampID:    cfg.BootcampID,
-			CohortID:      cfg.CohortID,
+			// Студенты, синхронизированные позже ALEM_SYNC_INTERVAL назад,
+			// пропускаются; недошедшие в упавшем прогоне идут первыми
+			MinSyncInterval:    cfg.AlemSyncInterval,
+			SkipRecentlySynced: true,
+			BootcampID:         cfg.BootcampID,
+			CohortID:           cfg.CohortID,
+			XPSpike: student.XPSpikeCriteria{
+				WindowDays: cfg.XPSpikeWindowDays,
+				K:          cfg.XPSpikeK,
+				MinXP:      student.XP(cfg.XPSpikeMinXP),
+			},
+			XPReviewChatID: cfg.XPReviewChatID,
 		},
 	)
 
+	// Всплески XP помечаются всегда, кураторам пишем при наличии бота
+	xpFlagRepo := postgres.NewXPFlagRepository(dbConn)
+	var xpReviewer jobs.KeyboardNotificationService
+	if telegramSender != nil {
+		xpReviewer = telegramSender
+	}
+	syncJob.WithXPFlags(xpFlagRepo, xpReviewer)
+
+	// Логин Alem и email на разных студентах уходят админам в /merge
+	syncJob.WithIdentity(postgres.NewIdentityConflictRepository(dbConn), postgres.NewAuditLogRepository(dbConn))
+
+	// Community events: scores are kept in Redis sorted sets
+	var eventRepo *postgres.EventRepository
+	var eventScoreboard *redis.EventScoreboard
+	if redisCache != nil {
+		eventRepo = postgres.NewEventRepository(dbConn)
+		eventScoreboard = redis.NewEventScoreboard(redisCache)
+		syncJob.WithEvents(eventRepo, eventScoreboard)
+	}
+
+	// Инвалидации кешей бота идут через Redis Pub/Sub
+	var invalidations *messaging.InvalidationPublisher
+	if redisCache != nil {
+		invalidations = messaging.NewInvalidationPublisher(redis.NewPubSubClient(redisCache), log)
+		syncJob.WithInvalidations(invalidations)
+	}
+
+	// /sync_me в боте и пакетная синхронизация не синхронизируют студента дважды
+	if redisCache != nil {
+		syncJob.WithSyncLock(redis.NewSyncLock(redisCache))
+	}
+
 	// Register with interval from config
 	syncInterval := scheduler.NewIntervalSchedule(cfg.SyncStudentsInterval)
 	if err := sch.Register(syncJob, syncInterval); err != nil {
 		log.Error("failed to register sync job", "error", err)
 	}
 
+	// Job: RebuildLeaderboard (снапшоты рейтинга и движение позиций за день)
+	var rebuildCache leaderboard.LeaderboardCache
+	if leaderboardCache != nil {
+		rebuildCache = leaderboardCache
+	}
+	rebuildJob := jobs.NewRebuildLeaderboardJob(
+		studentRepo,
+		leaderboardRepo,
+		rebuildCache,
+		nil,
+		eventBus,
+		nil,
+		log,
+		jobs.DefaultRebuildLeaderboardConfig(),
+	).WithDailyRanks(progressRepo).WithXPReviews(xpFlagRepo)
+	if invalidations != nil {
+		rebuildJob.WithInvalidations(invalidations)
+	}
+	rebuildSchedule, err := scheduler.ParseCronExpression(cfg.RebuildLeaderboardCron)
+	if err != nil {
+		log.Error("invalid REBUILD_LEADERBOARD_CRON", "error", err)
+	} else if err := sch.Register(rebuildJob, rebuildSchedule); err != nil {
+		log.Error("failed to register leaderboard rebuild job", "error", err)
+	}
+
+	// Job: FlushCommandUsage (счётчики команд живут только в Redis)
+	if redisCache != nil {
+		flushConfig := jobs.DefaultFlushCommandUsageConfig()
+		flushConfig.Retention = 0 // старые строки удаляет prune_history
+		flushJob := jobs.NewFlushCommandUsageJob(
+			redis.NewUsageCounter(redisCache),
+			usageRepo,
+			log,
+			flushConfig,
+		)
+		flushSchedule, err := scheduler.ParseCronExpression(cfg.UsageFlushCron)
+		if err != nil {
+			log.Error("invalid USAGE_FLUSH_CRON", "error", err)
+		} else if err := sch.Register(flushJob, flushSchedule); err != nil {
+			log.Error("failed to register usage flush job", "error", err)
+		}
+	}
+
+	// Job: PruneHistory (история XP сворачивается по месяцам, остальное удаляется пачками)
+	pruneConfig := jobs.DefaultPruneHistoryConfig()
+	pruneConfig.XPHistoryMonths = cfg.RetentionXPHistoryMonths
+	pruneConfig.Notifications = days(cfg.RetentionNotificationsDays)
+	pruneConfig.CommandUsage = days(cfg.RetentionCommandUsageDays)
+	pruneConfig.AuditLog = days(cfg.RetentionAuditLogDays)
+	pruneConfig.BatchSize = cfg.RetentionBatchSize
+	pruneConfig.BatchPause = cfg.RetentionBatchPause
+	pruneJob := jobs.NewPruneHistoryJob(postgres.NewRetentionRepository(dbConn), log, pruneConfig)
+	pruneSchedule, err := scheduler.ParseCronExpression(cfg.RetentionCron)
+	if err != nil {
+		log.Error("invalid RETENTION_CRON", "error", err)
+	} else if err := sch.Register(pruneJob, pruneSchedule); err != nil {
+		log.Error("failed to register history prune job", "error", err)
+	}
+
+	// Job: PruneFeed (записи ленты старше 30 дней, каждый час)
+	if err := sch.Register(jobs.NewPruneFeedJob(feedRepo, log), scheduler.NewIntervalSchedule(time.Hour)); err != nil {
+		log.Error("failed to register feed prune job", "error", err)
+	}
+
+	// Job: ExperimentOutcomes (активность на следующий день по вариантам экспериментов, каждый час)
+	if err := sch.Register(jobs.NewExperimentOutcomesJob(experimentRepo, log), scheduler.NewIntervalSchedule(time.Hour)); err != nil {
+		log.Error("failed to register experiment outcomes job", "error", err)
+	}
+
+	// Job: BackfillActivity (продвигает идущую загрузку истории, каждую минуту)
+	backfillRepo := postgres.NewBackfillRepository(dbConn)
+	backfillActivityConfig := jobs.DefaultBackfillActivityConfig()
+	backfillActivityConfig.BudgetPerSecond = alemConfig.RateLimiterConfig.RequestsPerSecond
+	backfillActivityJob := jobs.NewBackfillActivityJob(backfillRepo, activityRepo, alemClient, log, backfillActivityConfig)
+	if err := sch.Register(backfillActivityJob, scheduler.NewIntervalSchedule(time.Minute)); err != nil {
+		log.Error("failed to register activity backfill job", "error", err)
+	}
+
+	// Job: EscalateHelpRequests (срочные запросы без ответа уходят менторам задачи)
+	if telegramSender != nil {
+		escalationConfig := jobs.DefaultEscalateHelpRequestsConfig()
+		escalationConfig.Window = cfg.HelpEscalationWindow
+		escalationJob := jobs.NewEscalateHelpRequestsJob(
+			postgres.NewSocialRepository(dbConn).HelpRequests(),
+			leaderboardRepo,
+			studentRepo,
+			telegramSender,
+			log,
+			escalationConfig,
+		)
+		if err := sch.Register(escalationJob, scheduler.NewIntervalSchedule(5*time.Minute)); err != nil {
+			log.Error("failed to register help escalation job", "error", err)
+		}
+	}
+
+	// Job: ExpireHelpRequests (запросы, которые никто не взял до срока)
+	expireJob := jobs.NewExpireHelpRequestsJob(
+		postgres.NewSocialRepository(dbConn).HelpRequests(),
+		log,
+		jobs.DefaultExpireHelpRequestsConfig(),
+	)
+	if err := sch.Register(expireJob, scheduler.NewIntervalSchedule(15*time.Minute)); err != nil {
+		log.Error("failed to register help request expiry job", "error", err)
+	}
+
+	// Job: RemindEndorsements (одно напоминание поблагодарить помощника)
+	if telegramSender != nil {
+		socialRepo := postgres.NewSocialRepository(dbConn)
+		remindJob := jobs.NewRemindEndorsementsJob(
+			socialRepo.HelpRequests(),
+			socialRepo.Endorsements(),
+			studentRepo,
+			telegramSender,
+			log,
+			jobs.DefaultRemindEndorsementsConfig(),
+		)
+		if err := sch.Register(remindJob, scheduler.NewIntervalSchedule(time.Hour)); err != nil {
+			log.Error("failed to register endorsement reminder job", "error", err)
+		}
+	}
+
+	// Job: RemindSnoozedHelp (отложенные приглашения помочь)
+	if telegramSender != nil {
+		socialRepo := postgres.NewSocialRepository(dbConn)
+		snoozeJob := jobs.NewRemindSnoozedHelpJob(
+			socialRepo.HelpSnoozes(),
+			socialRepo.HelpRequests(),
+			studentRepo,
+			telegramSender,
+			log,
+			jobs.DefaultRemindSnoozedHelpConfig(),
+		)
+		if err := sch.Register(snoozeJob, scheduler.NewIntervalSchedule(5*time.Minute)); err != nil {
+			log.Error("failed to register snoozed help reminder job", "error", err)
+		}
+	}
+
+	// Job: PollHelpFeedback (закрытый опрос о полезности помощи через сутки)
+	if telegramSender != nil {
+		socialRepo := postgres.NewSocialRepository(dbConn)
+		pollJob := jobs.NewPollHelpFeedbackJob(
+			socialRepo.HelpRequests(),
+			socialRepo.Endorsements(),
+			socialRepo.HelpFeedback(),
+			studentRepo,
+			telegramSender,
+			log,
+			jobs.DefaultPollHelpFeedbackConfig(),
+		)
+		if err := sch.Register(pollJob, scheduler.NewIntervalSchedule(time.Hour)); err != nil {
+			log.Error("failed to register help feedback poll job", "error", err)
+		}
+	}
+
+	// Job: DetectStuckStudents (заходят, но XP не растёт - предлагаем помощь)
+	if telegramSender != nil {
+		stuckJob := jobs.NewDetectStuckStudentsJob(
+			progressRepo,
+			activityRepo,
+			alemClient,
+			eventBus,
+			log,
+			jobs.DefaultDetectStuckStudentsConfig(),
+		)
+		stuckSchedule, err := scheduler.ParseCronExpression(cfg.StuckDetectionCron)
+		if err != nil {
+			log.Error("invalid STUCK_DETECTION_CRON", "error", err)
+		} else if err := sch.Register(stuckJob, stuckSchedule); err != nil {
+			log.Error("failed to register stuck detection job", "error", err)
+		}
+	}
+
+	// Job: RefreshTaskDifficulty (запросы помощи на 100 решений за 30 дней)
+	difficultyJob := jobs.NewRefreshTaskDifficultyJob(
+		postgres.NewTaskDifficultyRepository(dbConn),
+		log,
+		jobs.DefaultRefreshTaskDifficultyConfig(),
+	)
+	difficultySchedule, err := scheduler.ParseCronExpression(cfg.TaskDifficultyCron)
+	if err != nil {
+		log.Error("invalid TASK_DIFFICULTY_CRON", "error", err)
+	} else if err := sch.Register(difficultyJob, difficultySchedule); err != nil {
+		log.Error("failed to register task difficulty job", "error", err)
+	}
+
+	// Sagas: запланированные уведомления попадают в outbox (таблица
+	// notifications), их отправляет DeliverNotifications
+	achievementSaga := saga.NewAchievementFlowSaga(
+		studentRepo,
+		progressRepo,
+		leaderboardRepo,
+		service.NewNotificationServiceStub(log).WithTriggerRules(triggerRules).WithOutbox(notificationRepo),
+		notificationRepo,
+		eventBus,
+		service.NewIDGenerator(),
+		sagaExecutionRepo,
+		saga.DefaultAchievementFlowConfig(),
+	)
+	onboardingSaga := saga.NewOnboardingSaga(
+		studentRepo,
+		progressRepo,
+		leaderboardRepo,
+		service.NewNotificationServiceStub(log).WithTriggerRules(triggerRules).WithOutbox(notificationRepo),
+		notificationRepo,
+		service.NewSagaAlemAPIAdapter(alemClient),
+		eventBus,
+		service.NewIDGenerator(),
+		sagaExecutionRepo,
+		saga.DefaultOnboardingConfig(),
+	)
+
+	// Job: ResumeSagas (продолжает саги, прерванные перезапуском)
+	resumeJob := jobs.NewResumeSagasJob(
+		sagaExecutionRepo,
+		achievementSaga,
+		onboardingSaga,
+		log,
+		jobs.DefaultResumeSagasConfig(),
+	)
+	if err := sch.Register(resumeJob, scheduler.NewIntervalSchedule(5*time.Minute)); err != nil {
+		log.Error("failed to register saga recovery job", "error", err)
+	}
+
+	// Job: EvaluateWeeklyGoals (отмечает достигнутые цели недели, ставит
+	// поздравление в outbox, Goal Getter)
+	if telegramSender != nil {
+		goalsJob := jobs.NewEvaluateWeeklyGoalsJob(
+			postgres.NewWeeklyGoalRepository(dbConn),
+			progressRepo,
+			studentRepo,
+			service.NewNotificationServiceStub(log).WithOutbox(notificationRepo),
+			achievementSaga,
+			log,
+			jobs.DefaultEvaluateWeeklyGoalsConfig(),
+		)
+		if err := sch.Register(goalsJob, scheduler.NewIntervalSchedule(time.Hour)); err != nil {
+			log.Error("failed to register weekly goals job", "error", err)
+		}
+	}
+
+	// Job: TrackHelperStreaks (серии недель помощи, Consistent Helper, топ помощников месяца)
+	helperStreakRepo := postgres.NewHelperStreakRepository(dbConn)
+	var helperBoard student.HelperBoard
+	if redisCache != nil {
+		helperBoard = redis.NewHelperBoard(redisCache)
+	}
+	helperStreaksJob := jobs.NewTrackHelperStreaksJob(
+		helperStreakRepo,
+		helperStreakRepo,
+		helperBoard,
+		achievementSaga,
+		log,
+		jobs.DefaultTrackHelperStreaksConfig(),
+	).WithEventPublisher(eventBus)
+	if err := sch.Register(helperStreaksJob, scheduler.NewIntervalSchedule(time.Hour)); err != nil {
+		log.Error("failed to register helper streaks job", "error", err)
+	}
+
+	// Job: DailyDigest (по шардам, каждый час: у потоков свой час дайджеста)
+	var digestShardJobs []*jobs.DailyDigestShardJob
+	if cfg.DailyDigestEnabled && telegramSender != nil {
+		digestConfig := jobs.DefaultDailyDigestConfig()
+		digestConfig.Timezone = schedulerConfig.Timezone
+		if t, err := time.Parse("15:04", cfg.DailyDigestTime); err == nil {
+			digestConfig.SendTime = t.Hour()
+		} else {
+			log.Error("invalid DAILY_DIGEST_TIME", "error", err)
+		}
+		digestConfig.Shards = cfg.DigestShards
+		digestConfig.SpreadWindow = cfg.DigestSpreadWindow
+		digestConfig.MaxParallelShards = cfg.DigestMaxParallelShards
+
+		digestJob := jobs.NewDailyDigestJob(
+			studentRepo,
+			progressRepo,
+			leaderboardRepo,
+			nil,
+			telegramSender,
+			eventBus,
+			service.NewCohortSettings(postgres.NewCohortSettingsRepository(dbConn), log),
+			log,
+			digestConfig,
+		).WithWeeklyGoals(postgres.NewWeeklyGoalRepository(dbConn)).WithDigestGuard(studentRepo).
+			WithConnectionMilestones(query.NewGetConnectionMilestonesHandler(
+				postgres.NewSocialRepository(dbConn).Connections(), studentRepo, progressRepo)).
+			WithDigestAttempts(notificationRepo)
+		if redisCache != nil {
+			digestJob.WithShardMarkers(redis.NewDigestShardMarkers(redisCache))
+		}
+
+		for _, shardJob := range digestJob.ShardJobs() {
+			minute := int(shardJob.Offset() / time.Minute)
+			shardSchedule, err := scheduler.ParseCronExpression(fmt.Sprintf("%d * * * *", minute))
+			if err != nil {
+				log.Error("invalid digest shard schedule", "job", shardJob.Name(), "error", err)
+				continue
+			}
+			if err := sch.Register(shardJob, shardSchedule); err != nil {
+				log.Error("failed to register digest shard job", "job", shardJob.Name(), "error", err)
+				continue
+			}
+			digestShardJobs = append(digestShardJobs, shardJob)
+		}
+
+		// Повтор неудачных дайджестов через 30 минут; в 23:30 по времени
+		// студента несостоявшийся дайджест переносится в следующий
+		retryJob := digestJob.RetryJob(jobs.DefaultDigestRetryConfig())
+		if err := sch.Register(retryJob, scheduler.NewIntervalSchedule(10*time.Minute)); err != nil {
+			log.Error("failed to register digest retry job", "error", err)
+		}
+	}
+
+	// Job: DeliverNotifications (отправляет уведомления из outbox)
+	if telegramSender != nil {
+		deliverJob := jobs.NewDeliverNotificationsJob(
+			notificationRepo,
+			studentRepo,
+			telegramSender,
+			log,
+			jobs.DefaultDeliverNotificationsConfig(),
+		)
+		if err := sch.Register(deliverJob, scheduler.NewIntervalSchedule(time.Minute)); err != nil {
+			log.Error("failed to register deliver notifications job", "error", err)
+		}
+	}
+
+	// Объявления в общих чатах: в тихие часы ждут в очереди до утра
+	var announcer jobs.Announcer
+	if telegramSender != nil {
+		announcer = service.NewAnnouncer(
+			telegramSender,
+			postgres.NewAnnouncementRepository(dbConn),
+			service.NewCohortSettings(postgres.NewCohortSettingsRepository(dbConn), log),
+			notification.QuietHours{Start: cfg.AnnouncementQuietStart, End: cfg.AnnouncementQuietEnd},
+			schedulerConfig.Timezone,
+			log,
+		)
+
+		// Job: FlushAnnouncements (отправляет объявления, отложенные на тихие часы)
+		flushAnnouncementsJob := jobs.NewFlushAnnouncementsJob(announcer, log, jobs.DefaultFlushAnnouncementsConfig())
+		if err := sch.Register(flushAnnouncementsJob, scheduler.NewIntervalSchedule(5*time.Minute)); err != nil {
+			log.Error("failed to register flush announcements job", "error", err)
+		}
+	}
+
+	// Job: FinalizeEvents (замораживает результаты челленджей, объявляет победителей)
+	if eventRepo != nil {
+		finalizeConfig := jobs.DefaultFinalizeEventsConfig()
+		finalizeConfig.AnnouncementChatID = cfg.EventAnnouncementChatID
+		finalizeJob := jobs.NewFinalizeEventsJob(
+			eventRepo,
+			eventScoreboard,
+			studentRepo,
+			announcer,
+			achievementSaga,
+			log,
+			finalizeConfig,
+		)
+		if err := sch.Register(finalizeJob, scheduler.NewIntervalSchedule(time.Minute)); err != nil {
+			log.Error("failed to register finalize events job", "error", err)
+		}
+	}
+
+	// Job: RecordPersonalBests (рекорды дня, недели и самой длинной
+	// фокус-сессии по закрытым дням)
+	personalBestsJob := jobs.NewRecordPersonalBestsJob(
+		progressRepo,
+		recordPersonalBestCmd,
+		log,
+		jobs.DefaultRecordPersonalBestsConfig(),
+	).WithFocusSessions(postgres.NewFocusRepository(dbConn))
+	personalBestsSchedule, err := scheduler.ParseCronExpression(cfg.PersonalBestsCron)
+	if err != nil {
+		log.Error("invalid PERSONAL_BESTS_CRON", "error", err)
+	} else if err := sch.Register(personalBestsJob, personalBestsSchedule); err != nil {
+		log.Error("failed to register personal bests job", "error", err)
+	}
+
+	// Job: CohortHealthReport (отчёт о потоке в чат куратора)
+	if reportSender != nil {
+		reportConfig := jobs.DefaultCohortHealthReportConfig()
+		reportConfig.AttachCSV = cfg.CohortReportCSV
+		cohortReportJob := jobs.NewCohortHealthReportJob(
+			leaderboardRepo,
+			service.NewCohortSettings(postgres.NewCohortSettingsRepository(dbConn), log),
+			query.NewCohortReportBuilder(postgres.NewCohortHealthRepository(dbConn), query.DefaultCohortReportConfig()).
+				WithPopularTasks(query.NewGetPopularTasksHandler(
+					postgres.NewSocialRepository(dbConn).HelpRequests(),
+					postgres.NewTaskCatalog(dbConn),
+				)),
+			reportSender,
+			log,
+			reportConfig,
+		)
+		cohortReportSchedule, err := scheduler.ParseCronExpression(cfg.CohortReportCron)
+		if err != nil {
+			log.Error("invalid COHORT_REPORT_CRON", "error", err)
+		} else if err := sch.Register(cohortReportJob, cohortReportSchedule); err != nil {
+			log.Error("failed to register cohort report job", "error", err)
+		}
+	}
+
+	// Зависимости jobs: рейтинг строится по свежей синхронизации, дайджест —
+	// по свежему рейтингу. Снапшоты и события о смене места создаёт сам
+	// rebuild_leaderboard за один прогон, отдельного ребра для них нет.
+	if err := sch.DependsOn(rebuildJob.Name(), scheduler.Dependency{
+		Job:         syncJob.Name(),
+		MaxAge:      2 * cfg.SyncStudentsInterval,
+		Policy:      scheduler.DependencyWait,
+		WaitTimeout: cfg.SyncStudentsInterval,
+	}); err != nil {
+		log.Error("failed to add leaderboard rebuild dependency", "error", err)
+	}
+	for _, shardJob := range digestShardJobs {
+		if err := sch.DependsOn(shardJob.Name(), scheduler.Dependency{
+			Job:    rebuildJob.Name(),
+			MaxAge: time.Hour,
+			Policy: scheduler.DependencyRunStale,
+		}); err != nil {
+			log.Error("failed to add digest dependency", "job", shardJob.Name(), "error", err)
+		}
+	}
+	log.Info("job dependency graph", "graph", sch.DependencyGraph())
+
 	// Start Scheduler
 	if err := sch.Start(ctx); err != nil {
 		return fmt.Errorf("failed to start scheduler: %w", err)
@@ -317,12 +1031,85 @@ func run(ctx context.Context) error {
 		_ = sch.Stop()
 	}()
 
+	// HTTP сервер только для /metrics: у worker нет другого HTTP
+	if metricsRegistry != nil {
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", metricsRegistry.Handler())
+		metricsServer := &http.Server{Addr: cfg.MetricsAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			log.Info("starting metrics server", "address", cfg.MetricsAddr)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Error("metrics server stopped", "error", err)
+			}
+		}()
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = metricsServer.Shutdown(shutdownCtx)
+		}()
+	}
+
+	// Job: BackfillCohortAchievements (one-off, runs after the initial sync)
+	var backfillJob *jobs.BackfillCohortAchievementsJob
+	if cfg.BackfillCohortAchievements {
+		backfillJob = jobs.NewBackfillCohortAchievementsJob(
+			leaderboardRepo,
+			achievementSaga,
+			log,
+			jobs.DefaultBackfillCohortAchievementsConfig(),
+		)
+	}
+
+	// Job: MigratePreferences (one-off, independent of the sync)
+	if cfg.MigratePreferences {
+		migrateJob := jobs.NewMigratePreferencesJob(
+			studentRepo,
+			log,
+			jobs.DefaultMigratePreferencesConfig(),
+		)
+		go func() {
+			if err := migrateJob.Run(ctx); err != nil {
+				log.Error("preferences migration failed", "error", err)
+			}
+		}()
+	}
+
+	// Job: BackfillPersonalBests (one-off, records are stored silently)
+	if cfg.BackfillPersonalBests {
+		go func() {
+			if err := personalBestsJob.Backfill(ctx); err != nil {
+				log.Error("personal bests backfill failed", "error", err)
+			}
+		}()
+	}
+
+	// Job: BackfillActivity (one-off start; the run continues across restarts)
+	if cfg.BackfillActivity {
+		share := float64(cfg.BackfillActivityRatePct) / 100
+		run, err := backfill.Start(ctx, backfillRepo, uuid.NewString(), cfg.BackfillActivityDryRun, share, time.Now())
+		switch {
+		case errors.Is(err, backfill.ErrRunActive):
+			log.Info("activity backfill already in progress")
+		case err != nil:
+			log.Error("failed to start activity backfill", "error", err)
+		default:
+			log.Info("activity backfill started", "run_id", run.ID, "dry_run", run.DryRun, "students", run.StudentsTotal)
+		}
+	}
+
 	// Run sync immediately on startup
 	log.Info("triggering initial sync...")
 	go func() {
 		if _, err := sch.RunNow(ctx, syncJob.Name()); err != nil {
 			log.Error("initial sync failed", "error", err)
 		}
+
+		// Бэкфилл идёт после синхронизации, чтобы ранги в потоках были свежими
+		if backfillJob != nil {
+			if err := backfillJob.Run(ctx); err != nil {
+				log.Error("cohort achievements backfill failed", "error", err)
+			}
+		}
 	}()
 
 	// ─────────────────────────────────────────────────────────────────────────
@@ -378,43 +1165,7 @@ func setupLogger(cfg *Config) *slog.Logger {
 	return log
 }
 
-// getEnv возвращает переменную окружения или значение по умолчанию.
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
-// getEnvBool возвращает boolean переменную окружения.
-func getEnvBool(key string, defaultValue bool) bool {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-	return value == "true" || value == "1" || value == "yes"
-}
-
-// getEnvInt возвращает int переменную окружения.
-func getEnvInt(key string, defaultValue int) int {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-	if i, err := strconv.Atoi(value); err == nil {
-		return i
-	}
-	return defaultValue
-}
-
-// getEnvDuration возвращает time.Duration переменную окружения.
-func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-	if d, err := time.ParseDuration(value); err == nil {
-		return d
-	}
-	return defaultValue
+// days переводит срок хранения в днях в time.Duration (0 - хранить всё).
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
 }
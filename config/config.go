@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
 )
 
 // Environment represents the application environment.
@@ -237,7 +239,7 @@ func loadAppConfig() AppConfig {
 	env := Environment(getEnv("APP_ENV", "development"))
 	timezone := getEnv("APP_TIMEZONE", "Asia/Almaty")
 
-	loc, err := time.LoadLocation(timezone)
+	loc, err := timeutil.LoadLocation(timezone)
 	if err != nil {
 		loc = time.UTC
 	}
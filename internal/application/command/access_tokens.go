@@ -0,0 +1,81 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// ACCESS TOKEN COMMANDS
+// "/token create" issues a personal read-only API token for the student's
+// own data, "/token revoke" revokes one. The token is returned once; only
+// its hash is stored.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// CreateAccessTokenResult contains a new token.
+type CreateAccessTokenResult struct {
+	// Token is the secret to show the student. It cannot be recovered.
+	Token string
+
+	// AccessToken is the stored token (without the secret).
+	AccessToken *student.AccessToken
+}
+
+// AccessTokensHandler creates, lists and revokes personal access tokens.
+type AccessTokensHandler struct {
+	tokens student.AccessTokenRepository
+	now    func() time.Time
+}
+
+// NewAccessTokensHandler creates a new handler.
+func NewAccessTokensHandler(tokens student.AccessTokenRepository) *AccessTokensHandler {
+	return &AccessTokensHandler{
+		tokens: tokens,
+		now:    time.Now,
+	}
+}
+
+// Create issues a new token. It returns student.ErrAccessTokenLimit when
+// the student already has student.MaxActiveAccessTokens active tokens.
+func (h *AccessTokensHandler) Create(ctx context.Context, studentID string) (*CreateAccessTokenResult, error) {
+	if studentID == "" {
+		return nil, errors.New("create_access_token: student_id is required")
+	}
+
+	token, secret, err := student.NewAccessToken(studentID, h.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := h.tokens.Create(ctx, token); err != nil {
+		if errors.Is(err, student.ErrAccessTokenLimit) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to save access token: %w", err)
+	}
+
+	return &CreateAccessTokenResult{Token: secret, AccessToken: token}, nil
+}
+
+// List returns the student's active tokens, newest first.
+func (h *AccessTokensHandler) List(ctx context.Context, studentID string) ([]*student.AccessToken, error) {
+	tokens, err := h.tokens.ListActive(ctx, studentID, h.now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// Revoke revokes a token of the student. It returns
+// student.ErrAccessTokenNotFound when the student has no such active token,
+// so nobody can revoke another student's token. API servers stop accepting
+// the token once their cache entry expires.
+func (h *AccessTokensHandler) Revoke(ctx context.Context, studentID, tokenID string) error {
+	if studentID == "" || tokenID == "" {
+		return student.ErrAccessTokenNotFound
+	}
+	return h.tokens.Revoke(ctx, studentID, tokenID, h.now())
+}
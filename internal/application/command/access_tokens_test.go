@@ -0,0 +1,79 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// memoryAccessTokens keeps tokens in memory, like access_tokens.
+type memoryAccessTokens struct {
+	student.AccessTokenRepository
+	tokens []*student.AccessToken
+}
+
+func (r *memoryAccessTokens) Create(ctx context.Context, token *student.AccessToken) error {
+	active, _ := r.ListActive(ctx, token.StudentID, token.CreatedAt)
+	if len(active) >= student.MaxActiveAccessTokens {
+		return student.ErrAccessTokenLimit
+	}
+	token.ID = fmt.Sprintf("t%d", len(r.tokens)+1)
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *memoryAccessTokens) ListActive(_ context.Context, studentID string, now time.Time) ([]*student.AccessToken, error) {
+	var active []*student.AccessToken
+	for _, t := range r.tokens {
+		if t.StudentID == studentID && t.IsActive(now) {
+			active = append(active, t)
+		}
+	}
+	return active, nil
+}
+
+func (r *memoryAccessTokens) Revoke(_ context.Context, studentID, tokenID string, at time.Time) error {
+	for _, t := range r.tokens {
+		if t.ID == tokenID && t.StudentID == studentID && t.RevokedAt == nil {
+			t.RevokedAt = &at
+			return nil
+		}
+	}
+	return student.ErrAccessTokenNotFound
+}
+
+func TestAccessTokens(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	repo := &memoryAccessTokens{}
+	h := NewAccessTokensHandler(repo)
+	h.now = func() time.Time { return now }
+
+	first, err := h.Create(ctx, "dana")
+	require.NoError(t, err)
+	assert.True(t, student.LooksLikeAccessToken(first.Token))
+	assert.Equal(t, student.HashAccessToken(first.Token), first.AccessToken.Hash)
+	assert.NotContains(t, first.AccessToken.Masked(), first.Token)
+
+	_, err = h.Create(ctx, "dana")
+	require.NoError(t, err)
+	_, err = h.Create(ctx, "dana")
+	assert.ErrorIs(t, err, student.ErrAccessTokenLimit)
+
+	// Only the owner can revoke; revoking frees a slot
+	assert.ErrorIs(t, h.Revoke(ctx, "aigerim", first.AccessToken.ID), student.ErrAccessTokenNotFound)
+	require.NoError(t, h.Revoke(ctx, "dana", first.AccessToken.ID))
+	assert.ErrorIs(t, h.Revoke(ctx, "dana", first.AccessToken.ID), student.ErrAccessTokenNotFound)
+
+	tokens, err := h.List(ctx, "dana")
+	require.NoError(t, err)
+	assert.Len(t, tokens, 1)
+	_, err = h.Create(ctx, "dana")
+	assert.NoError(t, err)
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
 	"github.com/alem-hub/alem-community-hub/internal/domain/social"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
@@ -221,7 +223,7 @@ func (h *ConnectStudentsHandler) createNewConnection(
 	cmd ConnectStudentsCommand,
 	result *ConnectStudentsResult,
 ) (*ConnectStudentsResult, error) {
-	connectionID := generateConnectionID(cmd.InitiatorID, cmd.TargetID)
+	connectionID := generateConnectionID()
 
 	// Build connection context from command data
 	connContext := social.ConnectionContext{
@@ -297,8 +299,9 @@ func (h *ConnectStudentsHandler) shouldUpgrade(current, requested social.Connect
 	return upgradeOrder[requested] > upgradeOrder[current]
 }
 
-func generateConnectionID(initiatorID, targetID string) string {
-	return fmt.Sprintf("conn_%s_%s_%d", initiatorID, targetID, time.Now().UnixNano())
+// generateConnectionID returns a new connection ID (connections.id is a UUID).
+func generateConnectionID() string {
+	return uuid.New().String()
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -510,8 +513,23 @@ func (h *GiveEndorsementHandler) Handle(
 		return nil, fmt.Errorf("give_endorsement: receiver not found: %w", err)
 	}
 
+	// Endorsements for a help request are only accepted while its prompt is
+	// valid. A team request is counted for the helper once: every teammate's
+	// rating revises the request's share of the average instead of adding to it
+	rating := social.TeamHelpRating{First: true, Revised: cmd.Rating}
+	if cmd.HelpRequestID != "" {
+		req, earlier, err := h.endorsableRequest(ctx, cmd)
+		if err != nil {
+			return nil, err
+		}
+		if cmd.TaskID == "" {
+			cmd.TaskID = string(req.TaskID)
+		}
+		rating = social.NewTeamHelpRating(earlier, social.Rating(cmd.Rating))
+	}
+
 	// Create endorsement
-	endorsementID := generateEndorsementID(cmd.GiverID, cmd.ReceiverID)
+	endorsementID := generateEndorsementID()
 
 	endorsementType := cmd.Type
 	if endorsementType == "" {
@@ -541,10 +559,14 @@ func (h *GiveEndorsementHandler) Handle(
 	}
 
 	// Update receiver's rating
-	if err := receiver.AddHelpRating(cmd.Rating); err != nil {
-		// Log but don't fail
+	if rating.First {
+		err = receiver.AddHelpRating(rating.Revised)
+	} else {
+		err = receiver.ReviseHelpRating(rating.Previous, rating.Revised)
+	}
+	if err == nil {
+		_ = h.studentRepo.Update(ctx, receiver)
 	}
-	_ = h.studentRepo.Update(ctx, receiver)
 
 	result := &GiveEndorsementResult{
 		EndorsementID:             endorsementID,
@@ -559,7 +581,7 @@ func (h *GiveEndorsementHandler) Handle(
 	if cmd.TaskID != "" {
 		event.TaskID = cmd.TaskID
 	}
-	event.Comment = cmd.Comment
+	event.Comment = endorsement.Comment
 	if cmd.CorrelationID != "" {
 		event.BaseEvent = event.BaseEvent.WithCorrelationID(cmd.CorrelationID)
 	}
@@ -569,6 +591,36 @@ func (h *GiveEndorsementHandler) Handle(
 	return result, nil
 }
 
-func generateEndorsementID(giverID, receiverID string) string {
-	return fmt.Sprintf("endorsement_%s_%s_%d", giverID, receiverID, time.Now().UnixNano())
+// endorsableRequest loads the endorsed help request and checks that the giver
+// is one of its participants, the receiver its helper, and that it can still
+// be endorsed by the giver. It also returns the endorsements already given for
+// the request.
+func (h *GiveEndorsementHandler) endorsableRequest(ctx context.Context, cmd GiveEndorsementCommand) (*social.HelpRequest, []*social.Endorsement, error) {
+	req, err := h.socialRepo.HelpRequests().GetByID(ctx, cmd.HelpRequestID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("give_endorsement: failed to get help request: %w", err)
+	}
+
+	giverID := social.StudentID(cmd.GiverID)
+	if !req.IsParticipant(giverID) || req.HelperID == nil || string(*req.HelperID) != cmd.ReceiverID {
+		return nil, nil, errors.New("give_endorsement: help request does not match giver and receiver")
+	}
+	if !req.IsEndorsableAt(time.Now()) {
+		return nil, nil, fmt.Errorf("give_endorsement: %w", social.ErrEndorsementPromptExpired)
+	}
+
+	earlier, err := h.socialRepo.Endorsements().ListByHelpRequestID(ctx, req.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("give_endorsement: failed to check existing endorsement: %w", err)
+	}
+	if social.HasEndorsed(earlier, giverID) {
+		return nil, nil, fmt.Errorf("give_endorsement: %w", social.ErrEndorsementAlreadyExists)
+	}
+
+	return req, earlier, nil
+}
+
+// generateEndorsementID returns a new endorsement ID (endorsements.id is a UUID).
+func generateEndorsementID() string {
+	return uuid.New().String()
 }
@@ -0,0 +1,361 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// FOCUS SESSION COMMANDS
+// A timed co-working session (/focus), alone or with one connected buddy.
+// The handler owns the session state; timers (halfway nudge, end) are
+// scheduled through FocusTimers and call back into Halfway and End. Active
+// sessions are rescheduled by Recover on startup, so a restart loses nothing.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// maxFocusBuddies is how many connections are offered as buddies.
+const maxFocusBuddies = 5
+
+// ErrFocusNotConnected is returned when inviting a student who is not a
+// connection of the owner.
+var ErrFocusNotConnected = errors.New("focus buddy is not a connection")
+
+// ErrFocusBuddyBusy is returned when the buddy is in another session.
+var ErrFocusBuddyBusy = errors.New("focus buddy is in another session")
+
+// FocusTimers schedules the halfway nudge and the end of a session.
+type FocusTimers interface {
+	// ScheduleFocus schedules the timers of a running session. Timers that
+	// are already due fire right away.
+	ScheduleFocus(session *social.FocusSession) error
+}
+
+// FocusStartResult contains a started session.
+type FocusStartResult struct {
+	Session *social.FocusSession
+
+	// Buddies are connections the owner can invite.
+	Buddies []*student.Student
+}
+
+// FocusInviteResult contains a session after an invite or an answer.
+type FocusInviteResult struct {
+	Session *social.FocusSession
+	Owner   *student.Student
+	Buddy   *student.Student
+}
+
+// FocusEndResult contains an ended session.
+type FocusEndResult struct {
+	Session *social.FocusSession
+
+	// Participants are the students who were in the session at the end.
+	Participants []*student.Student
+}
+
+// FocusSessionHandler handles focus session commands.
+type FocusSessionHandler struct {
+	focusRepo    social.FocusRepository
+	studentRepo  student.Repository
+	connections  social.ConnectionRepository
+	progressRepo student.ProgressRepository
+	timers       FocusTimers
+	now          func() time.Time
+}
+
+// NewFocusSessionHandler creates a new FocusSessionHandler.
+func NewFocusSessionHandler(
+	focusRepo social.FocusRepository,
+	studentRepo student.Repository,
+	connections social.ConnectionRepository,
+	progressRepo student.ProgressRepository,
+) *FocusSessionHandler {
+	return &FocusSessionHandler{
+		focusRepo:    focusRepo,
+		studentRepo:  studentRepo,
+		connections:  connections,
+		progressRepo: progressRepo,
+		now:          time.Now,
+	}
+}
+
+// WithTimers sets the timer scheduler. Without it sessions never end on
+// their own.
+func (h *FocusSessionHandler) WithTimers(timers FocusTimers) *FocusSessionHandler {
+	h.timers = timers
+	return h
+}
+
+// Start starts a session for the student.
+func (h *FocusSessionHandler) Start(ctx context.Context, studentID string, halfwayNudge bool) (*FocusStartResult, error) {
+	if err := h.checkFree(ctx, studentID); err != nil {
+		return nil, fmt.Errorf("focus_start: %w", err)
+	}
+
+	session := social.NewFocusSession(social.StudentID(studentID), halfwayNudge, h.now().UTC())
+	if err := h.focusRepo.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("focus_start: %w", err)
+	}
+	if err := h.schedule(session); err != nil {
+		return nil, fmt.Errorf("focus_start: %w", err)
+	}
+
+	return &FocusStartResult{
+		Session: session,
+		Buddies: h.buddies(ctx, studentID),
+	}, nil
+}
+
+// Active returns the running session of the student or
+// social.ErrFocusNotFound.
+func (h *FocusSessionHandler) Active(ctx context.Context, studentID string) (*social.FocusSession, error) {
+	return h.focusRepo.GetActiveByStudent(ctx, social.StudentID(studentID))
+}
+
+// Buddies returns connections the student can invite.
+func (h *FocusSessionHandler) Buddies(ctx context.Context, studentID string) []*student.Student {
+	return h.buddies(ctx, studentID)
+}
+
+// Invite invites a connection of the owner into the session.
+func (h *FocusSessionHandler) Invite(ctx context.Context, sessionID int64, ownerID, buddyID string) (*FocusInviteResult, error) {
+	session, err := h.focusRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("focus_invite: %w", err)
+	}
+	if string(session.OwnerID) != ownerID {
+		return nil, fmt.Errorf("focus_invite: %w", social.ErrFocusNotParticipant)
+	}
+
+	conn, err := h.connections.GetByStudents(ctx, social.StudentID(ownerID), social.StudentID(buddyID))
+	if err != nil || conn == nil {
+		return nil, fmt.Errorf("focus_invite: %w", ErrFocusNotConnected)
+	}
+	if err := h.checkFree(ctx, buddyID); err != nil {
+		return nil, fmt.Errorf("focus_invite: %w", ErrFocusBuddyBusy)
+	}
+
+	if err := session.Invite(social.StudentID(buddyID)); err != nil {
+		return nil, fmt.Errorf("focus_invite: %w", err)
+	}
+	if err := h.focusRepo.Update(ctx, session); err != nil {
+		return nil, fmt.Errorf("focus_invite: %w", err)
+	}
+
+	return h.inviteResult(ctx, session)
+}
+
+// Respond accepts or declines an invite.
+func (h *FocusSessionHandler) Respond(ctx context.Context, sessionID int64, buddyID string, accept bool) (*FocusInviteResult, error) {
+	session, err := h.focusRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("focus_respond: %w", err)
+	}
+
+	if accept {
+		if err := h.checkFree(ctx, buddyID); err != nil {
+			return nil, fmt.Errorf("focus_respond: %w", err)
+		}
+		err = session.Accept(social.StudentID(buddyID), h.now().UTC())
+	} else {
+		err = session.Decline(social.StudentID(buddyID))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("focus_respond: %w", err)
+	}
+	if err := h.focusRepo.Update(ctx, session); err != nil {
+		return nil, fmt.Errorf("focus_respond: %w", err)
+	}
+
+	return h.inviteResult(ctx, session)
+}
+
+// Halfway marks the halfway nudge as sent. Returns nil when no nudge is due
+// (disabled, already sent or the session is over).
+func (h *FocusSessionHandler) Halfway(ctx context.Context, sessionID int64) (*FocusEndResult, error) {
+	session, err := h.focusRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("focus_halfway: %w", err)
+	}
+	if !session.HalfwayNudge || !session.IsActive() {
+		return nil, nil
+	}
+
+	marked, err := h.focusRepo.MarkHalfwaySent(ctx, sessionID)
+	if err != nil || !marked {
+		return nil, err
+	}
+	session.HalfwaySent = true
+
+	return &FocusEndResult{Session: session, Participants: h.participants(ctx, session)}, nil
+}
+
+// End ends the session by its timer. Returns nil if it was already ended.
+func (h *FocusSessionHandler) End(ctx context.Context, sessionID int64) (*FocusEndResult, error) {
+	return h.end(ctx, sessionID, "")
+}
+
+// Stop ends the session early on behalf of a participant.
+func (h *FocusSessionHandler) Stop(ctx context.Context, sessionID int64, studentID string) (*FocusEndResult, error) {
+	result, err := h.end(ctx, sessionID, studentID)
+	if err == nil && result == nil {
+		return nil, fmt.Errorf("focus_stop: %w", social.ErrFocusEnded)
+	}
+	return result, err
+}
+
+// end ends the session and counts the minutes of every participant in
+// their daily progress. by is empty for the timer.
+func (h *FocusSessionHandler) end(ctx context.Context, sessionID int64, by string) (*FocusEndResult, error) {
+	session, err := h.focusRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("focus_end: %w", err)
+	}
+	if by != "" && !session.IsParticipant(social.StudentID(by)) {
+		return nil, fmt.Errorf("focus_end: %w", social.ErrFocusNotParticipant)
+	}
+	if !session.IsActive() {
+		return nil, nil
+	}
+
+	now := h.now().UTC()
+	if by == "" {
+		// The timer ends the session at its planned end, even when it fires
+		// late after a restart
+		now = session.EndsAt
+	}
+
+	ended, err := h.focusRepo.MarkEnded(ctx, sessionID, now)
+	if err != nil {
+		return nil, fmt.Errorf("focus_end: %w", err)
+	}
+	if !ended {
+		return nil, nil
+	}
+	session.EndedAt = &now
+
+	for _, id := range session.Participants() {
+		if minutes := session.Minutes(id); minutes > 0 {
+			if err := h.progressRepo.RecordDailySessionMinutes(ctx, string(id), now, minutes); err != nil {
+				return nil, fmt.Errorf("focus_end: %w", err)
+			}
+		}
+	}
+
+	return &FocusEndResult{Session: session, Participants: h.participants(ctx, session)}, nil
+}
+
+// RecordOutcome stores what a participant got done.
+func (h *FocusSessionHandler) RecordOutcome(ctx context.Context, sessionID int64, studentID string, outcome social.FocusOutcome) (*social.FocusSession, error) {
+	session, err := h.focusRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("focus_outcome: %w", err)
+	}
+	if err := session.SetOutcome(social.StudentID(studentID), outcome); err != nil {
+		return nil, fmt.Errorf("focus_outcome: %w", err)
+	}
+	if err := h.focusRepo.Update(ctx, session); err != nil {
+		return nil, fmt.Errorf("focus_outcome: %w", err)
+	}
+	return session, nil
+}
+
+// Recover reschedules the timers of every running session. Returns the
+// number of sessions restored.
+func (h *FocusSessionHandler) Recover(ctx context.Context) (int, error) {
+	sessions, err := h.focusRepo.ListActive(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("focus_recover: %w", err)
+	}
+
+	restored := 0
+	for _, session := range sessions {
+		if err := h.schedule(session); err != nil {
+			return restored, fmt.Errorf("focus_recover: session %d: %w", session.ID, err)
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// schedule schedules the timers of the session if timers are configured.
+func (h *FocusSessionHandler) schedule(session *social.FocusSession) error {
+	if h.timers == nil {
+		return nil
+	}
+	return h.timers.ScheduleFocus(session)
+}
+
+// checkFree returns social.ErrFocusActive if the student is in a session.
+func (h *FocusSessionHandler) checkFree(ctx context.Context, studentID string) error {
+	_, err := h.focusRepo.GetActiveByStudent(ctx, social.StudentID(studentID))
+	switch {
+	case err == nil:
+		return social.ErrFocusActive
+	case errors.Is(err, social.ErrFocusNotFound):
+		return nil
+	default:
+		return err
+	}
+}
+
+// buddies returns up to maxFocusBuddies active connections of the student.
+func (h *FocusSessionHandler) buddies(ctx context.Context, studentID string) []*student.Student {
+	conns, err := h.connections.GetActiveByStudentID(ctx, social.StudentID(studentID))
+	if err != nil {
+		return nil
+	}
+
+	buddies := make([]*student.Student, 0, maxFocusBuddies)
+	seen := make(map[social.StudentID]bool, len(conns))
+	for _, conn := range conns {
+		other := conn.InitiatorID
+		if other == social.StudentID(studentID) {
+			other = conn.ReceiverID
+		}
+		if seen[other] {
+			continue
+		}
+		seen[other] = true
+
+		s, err := h.studentRepo.GetByID(ctx, string(other))
+		if err != nil {
+			continue
+		}
+		buddies = append(buddies, s)
+		if len(buddies) == maxFocusBuddies {
+			break
+		}
+	}
+	return buddies
+}
+
+// participants loads the participants of the session.
+func (h *FocusSessionHandler) participants(ctx context.Context, session *social.FocusSession) []*student.Student {
+	ids := session.Participants()
+	students := make([]*student.Student, 0, len(ids))
+	for _, id := range ids {
+		if s, err := h.studentRepo.GetByID(ctx, string(id)); err == nil {
+			students = append(students, s)
+		}
+	}
+	return students
+}
+
+// inviteResult loads the owner and the buddy of the session.
+func (h *FocusSessionHandler) inviteResult(ctx context.Context, session *social.FocusSession) (*FocusInviteResult, error) {
+	owner, err := h.studentRepo.GetByID(ctx, string(session.OwnerID))
+	if err != nil {
+		return nil, fmt.Errorf("focus: owner not found: %w", err)
+	}
+	buddy, err := h.studentRepo.GetByID(ctx, string(session.BuddyID))
+	if err != nil {
+		return nil, fmt.Errorf("focus: buddy not found: %w", err)
+	}
+	return &FocusInviteResult{Session: session, Owner: owner, Buddy: buddy}, nil
+}
@@ -0,0 +1,254 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// memoryFocusRepo keeps sessions in memory, like focus_sessions.
+type memoryFocusRepo struct {
+	sessions map[int64]*social.FocusSession
+	nextID   int64
+}
+
+func newMemoryFocusRepo() *memoryFocusRepo {
+	return &memoryFocusRepo{sessions: make(map[int64]*social.FocusSession)}
+}
+
+func (r *memoryFocusRepo) Create(_ context.Context, s *social.FocusSession) error {
+	for _, existing := range r.sessions {
+		if existing.OwnerID == s.OwnerID && existing.IsActive() {
+			return social.ErrFocusActive
+		}
+	}
+	r.nextID++
+	s.ID = r.nextID
+	cp := *s
+	r.sessions[s.ID] = &cp
+	return nil
+}
+
+func (r *memoryFocusRepo) GetByID(_ context.Context, id int64) (*social.FocusSession, error) {
+	s, ok := r.sessions[id]
+	if !ok {
+		return nil, social.ErrFocusNotFound
+	}
+	cp := *s
+	return &cp, nil
+}
+
+func (r *memoryFocusRepo) GetActiveByStudent(_ context.Context, id social.StudentID) (*social.FocusSession, error) {
+	for _, s := range r.sessions {
+		if s.IsActive() && s.IsParticipant(id) {
+			cp := *s
+			return &cp, nil
+		}
+	}
+	return nil, social.ErrFocusNotFound
+}
+
+func (r *memoryFocusRepo) ListActive(_ context.Context) ([]*social.FocusSession, error) {
+	var out []*social.FocusSession
+	for id := int64(1); id <= r.nextID; id++ {
+		if s, ok := r.sessions[id]; ok && s.IsActive() {
+			cp := *s
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryFocusRepo) Update(_ context.Context, s *social.FocusSession) error {
+	cp := *s
+	r.sessions[s.ID] = &cp
+	return nil
+}
+
+func (r *memoryFocusRepo) MarkHalfwaySent(_ context.Context, id int64) (bool, error) {
+	s := r.sessions[id]
+	if s.HalfwaySent || !s.IsActive() {
+		return false, nil
+	}
+	s.HalfwaySent = true
+	return true, nil
+}
+
+func (r *memoryFocusRepo) MarkEnded(_ context.Context, id int64, at time.Time) (bool, error) {
+	s := r.sessions[id]
+	if !s.IsActive() {
+		return false, nil
+	}
+	s.EndedAt = &at
+	return true, nil
+}
+
+// recordingTimers remembers scheduled sessions.
+type recordingTimers struct {
+	scheduled []int64
+}
+
+func (t *recordingTimers) ScheduleFocus(s *social.FocusSession) error {
+	t.scheduled = append(t.scheduled, s.ID)
+	return nil
+}
+
+type focusStudents struct {
+	student.Repository
+	byID map[string]*student.Student
+}
+
+func (r *focusStudents) GetByID(_ context.Context, id string) (*student.Student, error) {
+	if s, ok := r.byID[id]; ok {
+		return s, nil
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+// focusConnections treats every pair of students as connected.
+type focusConnections struct {
+	social.ConnectionRepository
+}
+
+func (focusConnections) GetByStudents(_ context.Context, a, b social.StudentID) (*social.Connection, error) {
+	return &social.Connection{InitiatorID: a, ReceiverID: b}, nil
+}
+
+func (focusConnections) GetActiveByStudentID(context.Context, social.StudentID) ([]*social.Connection, error) {
+	return nil, nil
+}
+
+type focusProgress struct {
+	student.ProgressRepository
+	minutes map[string]int
+}
+
+func (p *focusProgress) RecordDailySessionMinutes(_ context.Context, id string, _ time.Time, minutes int) error {
+	p.minutes[id] += minutes
+	return nil
+}
+
+func newFocusTestHandler(t *testing.T, repo *memoryFocusRepo, now *time.Time) (*FocusSessionHandler, *recordingTimers, *focusProgress) {
+	t.Helper()
+
+	students := &focusStudents{byID: map[string]*student.Student{
+		"dana":  {ID: "dana", DisplayName: "Dana"},
+		"arman": {ID: "arman", DisplayName: "Arman"},
+		"aliya": {ID: "aliya", DisplayName: "Aliya"},
+	}}
+	progress := &focusProgress{minutes: make(map[string]int)}
+	timers := &recordingTimers{}
+
+	h := NewFocusSessionHandler(repo, students, focusConnections{}, progress).WithTimers(timers)
+	h.now = func() time.Time { return *now }
+	return h, timers, progress
+}
+
+func TestFocusSession_RecoverAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.October, 14, 10, 0, 0, 0, time.UTC)
+	repo := newMemoryFocusRepo()
+
+	// Before the restart: two sessions, one of them already finished
+	before, _, _ := newFocusTestHandler(t, repo, &now)
+	running, err := before.Start(ctx, "dana", true)
+	require.NoError(t, err)
+	finished, err := before.Start(ctx, "arman", false)
+	require.NoError(t, err)
+	now = now.Add(10 * time.Minute)
+	_, err = before.Stop(ctx, finished.Session.ID, "arman")
+	require.NoError(t, err)
+
+	// The bot is down past the end of the running session
+	now = now.Add(time.Hour)
+	after, timers, progress := newFocusTestHandler(t, repo, &now)
+
+	restored, err := after.Recover(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, restored)
+	assert.Equal(t, []int64{running.Session.ID}, timers.scheduled)
+
+	// The overdue timer ends the session at its planned end, once
+	result, err := after.End(ctx, running.Session.ID)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, running.Session.EndsAt, *result.Session.EndedAt)
+	assert.Equal(t, 50, progress.minutes["dana"])
+
+	again, err := after.End(ctx, running.Session.ID)
+	require.NoError(t, err)
+	assert.Nil(t, again)
+	assert.Equal(t, 50, progress.minutes["dana"])
+
+	restored, err = after.Recover(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, restored)
+}
+
+func TestFocusSession_InviteAndDecline(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.October, 14, 10, 0, 0, 0, time.UTC)
+	repo := newMemoryFocusRepo()
+	h, _, progress := newFocusTestHandler(t, repo, &now)
+
+	started, err := h.Start(ctx, "dana", true)
+	require.NoError(t, err)
+	id := started.Session.ID
+
+	// Only the owner invites, and only one buddy at a time
+	_, err = h.Invite(ctx, id, "arman", "aliya")
+	assert.ErrorIs(t, err, social.ErrFocusNotParticipant)
+
+	invited, err := h.Invite(ctx, id, "dana", "arman")
+	require.NoError(t, err)
+	assert.Equal(t, social.FocusInvitePending, invited.Session.BuddyStatus)
+	assert.Equal(t, "Arman", invited.Buddy.DisplayName)
+
+	_, err = h.Invite(ctx, id, "dana", "aliya")
+	assert.ErrorIs(t, err, social.ErrFocusBuddyTaken)
+
+	// Someone else cannot answer for the buddy
+	_, err = h.Respond(ctx, id, "aliya", true)
+	assert.ErrorIs(t, err, social.ErrFocusNotInvited)
+
+	declined, err := h.Respond(ctx, id, "arman", false)
+	require.NoError(t, err)
+	assert.Equal(t, social.FocusInviteDeclined, declined.Session.BuddyStatus)
+	assert.Equal(t, []social.StudentID{"dana"}, declined.Session.Participants())
+
+	// A declined invite cannot be accepted later
+	_, err = h.Respond(ctx, id, "arman", true)
+	assert.ErrorIs(t, err, social.ErrFocusNotInvited)
+
+	// After a decline the owner may invite someone else, who joins midway
+	_, err = h.Invite(ctx, id, "dana", "aliya")
+	require.NoError(t, err)
+	now = now.Add(20 * time.Minute)
+	joined, err := h.Respond(ctx, id, "aliya", true)
+	require.NoError(t, err)
+	assert.True(t, joined.Session.IsParticipant("aliya"))
+
+	// Concurrent sessions are refused for both participants
+	_, err = h.Start(ctx, "aliya", true)
+	assert.ErrorIs(t, err, social.ErrFocusActive)
+	_, err = h.Start(ctx, "dana", true)
+	assert.ErrorIs(t, err, social.ErrFocusActive)
+
+	// The declined student is free to start their own session
+	_, err = h.Start(ctx, "arman", true)
+	require.NoError(t, err)
+
+	now = now.Add(30 * time.Minute)
+	ended, err := h.End(ctx, id)
+	require.NoError(t, err)
+	assert.Len(t, ended.Participants, 2)
+	assert.Equal(t, 50, progress.minutes["dana"])
+	assert.Equal(t, 30, progress.minutes["aliya"])
+	assert.Zero(t, progress.minutes["arman"])
+}
@@ -0,0 +1,205 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HELP CLUSTER COMMANDS
+// Actions on a cluster of near-identical help requests (see
+// social.HelpClusterWindow for how clusters form and the lifecycle rules):
+// requesters opt into a temporary group that shares their contacts, and a
+// helper who accepted one request takes or resolves the whole cluster.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ErrHelpRequestNotRequester is returned when a student acts on someone
+// else's request.
+var ErrHelpRequestNotRequester = errors.New("student is not the requester of the help request")
+
+// JoinHelpGroupResult contains the group after a requester opted in.
+type JoinHelpGroupResult struct {
+	// Joined is false when the requester was already in the group.
+	Joined bool
+
+	// Request is the requester's help request.
+	Request *social.HelpRequest
+
+	// Requester is the student who opted in.
+	Requester *student.Student
+
+	// Members are the other students of the group whose contacts are
+	// shared with the requester (and who get the requester's contact).
+	Members []*student.Student
+
+	// Waiting is how many other students of the cluster haven't joined.
+	Waiting int
+}
+
+// HelpClusterResult contains the requests changed by a cluster action.
+type HelpClusterResult struct {
+	// Requests are the taken or resolved requests.
+	Requests []*social.HelpRequest
+}
+
+// HelpClusterHandler handles help cluster commands.
+type HelpClusterHandler struct {
+	socialRepo     social.Repository
+	studentRepo    student.Repository
+	eventPublisher shared.EventPublisher
+	now            func() time.Time
+}
+
+// NewHelpClusterHandler creates a new HelpClusterHandler.
+func NewHelpClusterHandler(socialRepo social.Repository, studentRepo student.Repository) *HelpClusterHandler {
+	return &HelpClusterHandler{
+		socialRepo:  socialRepo,
+		studentRepo: studentRepo,
+		now:         time.Now,
+	}
+}
+
+// WithEventPublisher publishes assignment and resolution events for every
+// request of a taken or resolved cluster.
+func (h *HelpClusterHandler) WithEventPublisher(publisher shared.EventPublisher) *HelpClusterHandler {
+	h.eventPublisher = publisher
+	return h
+}
+
+// JoinGroup opts the requester into the group of their request's cluster.
+// The caller delivers the requester's contact to the returned members.
+func (h *HelpClusterHandler) JoinGroup(ctx context.Context, requestID, requesterID string) (*JoinHelpGroupResult, error) {
+	request, err := h.socialRepo.HelpRequests().GetByID(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("help_group: %w", err)
+	}
+	if string(request.RequesterID) != requesterID {
+		return nil, fmt.Errorf("help_group: %w", ErrHelpRequestNotRequester)
+	}
+	if request.ClusterID == "" {
+		return nil, fmt.Errorf("help_group: %w", social.ErrHelpRequestNotClustered)
+	}
+
+	members, err := h.socialRepo.HelpRequests().GetByCluster(ctx, request.ClusterID)
+	if err != nil {
+		return nil, fmt.Errorf("help_group: %w", err)
+	}
+	peers := social.OpenClusterPeers(request, members)
+	if len(peers) == 0 {
+		return nil, fmt.Errorf("help_group: %w", social.ErrHelpRequestNotClustered)
+	}
+
+	joined, err := request.OptInClusterGroup(h.now())
+	if err != nil {
+		return nil, fmt.Errorf("help_group: %w", err)
+	}
+	if joined {
+		if err := h.socialRepo.HelpRequests().Update(ctx, request); err != nil {
+			return nil, fmt.Errorf("help_group: failed to save: %w", err)
+		}
+	}
+
+	requester, err := h.studentRepo.GetByID(ctx, requesterID)
+	if err != nil {
+		return nil, fmt.Errorf("help_group: failed to get requester: %w", err)
+	}
+
+	result := &JoinHelpGroupResult{Joined: joined, Request: request, Requester: requester}
+	seen := map[social.StudentID]bool{request.RequesterID: true}
+	for _, peer := range peers {
+		if seen[peer.RequesterID] {
+			continue
+		}
+		seen[peer.RequesterID] = true
+
+		if !peer.InClusterGroup() {
+			result.Waiting++
+			continue
+		}
+		member, err := h.studentRepo.GetByID(ctx, string(peer.RequesterID))
+		if err != nil {
+			continue
+		}
+		result.Members = append(result.Members, member)
+	}
+	return result, nil
+}
+
+// Take assigns the helper of one request to every other open request of its
+// cluster that has no helper yet.
+func (h *HelpClusterHandler) Take(ctx context.Context, requestID, helperID string) (*HelpClusterResult, error) {
+	members, err := h.helperCluster(ctx, requestID, helperID)
+	if err != nil {
+		return nil, fmt.Errorf("help_cluster_take: %w", err)
+	}
+
+	result := &HelpClusterResult{}
+	for _, req := range social.TakeCluster(members, social.StudentID(helperID)) {
+		if err := h.socialRepo.HelpRequests().Update(ctx, req); err != nil {
+			return result, fmt.Errorf("help_cluster_take: failed to save: %w", err)
+		}
+		result.Requests = append(result.Requests, req)
+
+		if h.eventPublisher != nil {
+			_ = h.eventPublisher.Publish(shared.NewHelperAssignedEvent(
+				req.ID, string(req.RequesterID), helperID, string(req.TaskID),
+			))
+		}
+	}
+	return result, nil
+}
+
+// Resolve resolves every request of the cluster the helper is assigned to.
+// Requests with another helper or none stay open.
+func (h *HelpClusterHandler) Resolve(ctx context.Context, requestID, helperID, notes string) (*HelpClusterResult, error) {
+	members, err := h.helperCluster(ctx, requestID, helperID)
+	if err != nil {
+		return nil, fmt.Errorf("help_cluster_resolve: %w", err)
+	}
+
+	result := &HelpClusterResult{}
+	for _, req := range social.ResolveCluster(members, social.StudentID(helperID), notes) {
+		if err := h.socialRepo.HelpRequests().Update(ctx, req); err != nil {
+			return result, fmt.Errorf("help_cluster_resolve: failed to save: %w", err)
+		}
+		result.Requests = append(result.Requests, req)
+
+		if h.eventPublisher != nil {
+			_ = h.eventPublisher.Publish(shared.NewHelpRequestResolvedEvent(
+				req.ID, string(req.RequesterID), helperID, string(req.TaskID),
+			))
+			provided := shared.NewHelpProvidedEvent(helperID, string(req.RequesterID), string(req.TaskID))
+			provided.RequestID = req.ID
+			_ = h.eventPublisher.Publish(provided)
+		}
+	}
+
+	if len(result.Requests) > 0 {
+		if helper, err := h.studentRepo.GetByID(ctx, helperID); err == nil {
+			helper.HelpCount += len(result.Requests)
+			_ = h.studentRepo.Update(ctx, helper)
+		}
+	}
+	return result, nil
+}
+
+// helperCluster returns the cluster of a request the helper is assigned to.
+func (h *HelpClusterHandler) helperCluster(ctx context.Context, requestID, helperID string) ([]*social.HelpRequest, error) {
+	request, err := h.socialRepo.HelpRequests().GetByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.HelperID == nil || string(*request.HelperID) != helperID {
+		return nil, social.ErrHelpRequestNotHelper
+	}
+	if request.ClusterID == "" {
+		return nil, social.ErrHelpRequestNotClustered
+	}
+	return h.socialRepo.HelpRequests().GetByCluster(ctx, request.ClusterID)
+}
@@ -0,0 +1,339 @@
+package command
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/activity"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// memoryHelpRequests keeps help requests in memory, like help_requests and
+// help_cluster_notifications. Like idx_help_requests_open_requester_task, it
+// keeps one open request per student and task.
+type memoryHelpRequests struct {
+	social.HelpRequestRepository
+	mu       sync.Mutex
+	requests map[string]*social.HelpRequest
+	cohorts  map[social.StudentID]string
+	notified map[string]bool
+}
+
+func newMemoryHelpRequests(cohorts map[social.StudentID]string) *memoryHelpRequests {
+	return &memoryHelpRequests{
+		requests: make(map[string]*social.HelpRequest),
+		cohorts:  cohorts,
+		notified: make(map[string]bool),
+	}
+}
+
+func (r *memoryHelpRequests) Create(_ context.Context, req *social.HelpRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.requests {
+		if stored.RequesterID == req.RequesterID && stored.TaskID == req.TaskID && isOpenOrMatched(stored) {
+			return social.ErrHelpRequestDuplicate
+		}
+	}
+	r.requests[req.ID] = req.Clone()
+	return nil
+}
+
+func isOpenOrMatched(req *social.HelpRequest) bool {
+	return req.Status == social.HelpRequestStatusOpen || req.Status == social.HelpRequestStatusMatched
+}
+
+func (r *memoryHelpRequests) GetByID(_ context.Context, id string) (*social.HelpRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	req, ok := r.requests[id]
+	if !ok {
+		return nil, social.ErrHelpRequestNotFound
+	}
+	return req.Clone(), nil
+}
+
+func (r *memoryHelpRequests) Update(_ context.Context, req *social.HelpRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests[req.ID] = req.Clone()
+	return nil
+}
+
+func (r *memoryHelpRequests) GetOpenByRequesterID(_ context.Context, requesterID social.StudentID) ([]*social.HelpRequest, error) {
+	return r.sorted(func(req *social.HelpRequest) bool {
+		return req.RequesterID == requesterID && isOpenOrMatched(req)
+	}), nil
+}
+
+func (r *memoryHelpRequests) SaveMembers(_ context.Context, req *social.HelpRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.requests[req.ID].Clone()
+	stored.Members = req.Clone().Members
+	r.requests[req.ID] = stored
+	return nil
+}
+
+func (r *memoryHelpRequests) GetOpenMemberships(_ context.Context, studentID social.StudentID) ([]*social.HelpRequest, error) {
+	return r.sorted(func(req *social.HelpRequest) bool {
+		return !req.Status.IsClosed() && req.RequesterID != studentID && req.IsParticipant(studentID)
+	}), nil
+}
+
+func (r *memoryHelpRequests) sorted(keep func(*social.HelpRequest) bool) []*social.HelpRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*social.HelpRequest
+	for _, req := range r.requests {
+		if keep(req) {
+			out = append(out, req.Clone())
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+func (r *memoryHelpRequests) FindDuplicates(_ context.Context, taskID social.TaskID, cohort string, requesterID social.StudentID, since time.Time) ([]*social.HelpRequest, error) {
+	return r.sorted(func(req *social.HelpRequest) bool {
+		return req.TaskID == taskID && !req.Status.IsClosed() && !req.CreatedAt.Before(since) &&
+			req.RequesterID != requesterID && r.cohorts[req.RequesterID] == cohort
+	}), nil
+}
+
+func (r *memoryHelpRequests) SetCluster(_ context.Context, clusterID string, ids []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		r.requests[id].JoinCluster(clusterID)
+	}
+	return nil
+}
+
+func (r *memoryHelpRequests) GetByCluster(_ context.Context, clusterID string) ([]*social.HelpRequest, error) {
+	return r.sorted(func(req *social.HelpRequest) bool { return req.ClusterID == clusterID }), nil
+}
+
+func (r *memoryHelpRequests) ClaimHelperNotification(_ context.Context, clusterID string, helperID social.StudentID, _ time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := clusterID + "/" + string(helperID)
+	if r.notified[key] {
+		return false, nil
+	}
+	r.notified[key] = true
+	return true, nil
+}
+
+type memorySocial struct {
+	social.Repository
+	helpRequests *memoryHelpRequests
+}
+
+func (s *memorySocial) HelpRequests() social.HelpRequestRepository { return s.helpRequests }
+
+type clusterStudents struct {
+	student.Repository
+	byID map[string]*student.Student
+}
+
+func (r *clusterStudents) GetByID(_ context.Context, id string) (*student.Student, error) {
+	if s, ok := r.byID[id]; ok {
+		return s, nil
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+func (r *clusterStudents) Update(_ context.Context, s *student.Student) error {
+	r.byID[s.ID] = s
+	return nil
+}
+
+// solvedBy reports every task as solved by the same helper.
+type solvedBy struct {
+	activity.Repository
+	helper activity.StudentID
+}
+
+func (a solvedBy) GetStudentsWhoCompletedTask(context.Context, activity.TaskID, int) ([]activity.StudentID, error) {
+	return []activity.StudentID{a.helper}, nil
+}
+
+type alwaysOnline struct {
+	activity.OnlineTracker
+}
+
+func (alwaysOnline) IsOnline(context.Context, activity.StudentID) (bool, error) { return true, nil }
+
+type countingNotifier struct {
+	pings map[string]int
+}
+
+func (n *countingNotifier) NotifyHelpRequest(_ context.Context, helperID string, _ *social.HelpRequest) error {
+	n.pings[helperID]++
+	return nil
+}
+
+type discardEvents struct{}
+
+func (discardEvents) Publish(shared.Event) error { return nil }
+
+type clusterFixture struct {
+	requests *memoryHelpRequests
+	students *clusterStudents
+	notifier *countingNotifier
+	request  *RequestHelpHandler
+	clusters *HelpClusterHandler
+}
+
+func newClusterFixture() *clusterFixture {
+	enrolled := func(id string, tg student.TelegramID) *student.Student {
+		return &student.Student{
+			ID: id, TelegramID: tg, DisplayName: id, Cohort: "2025-spring", Status: student.StatusActive,
+			Preferences: student.NotificationPreferences{HelpRequests: true},
+		}
+	}
+	students := &clusterStudents{byID: map[string]*student.Student{
+		"dana":    enrolled("dana", 1),
+		"ali":     enrolled("ali", 2),
+		"arman":   enrolled("arman", 3),
+		"aigerim": enrolled("aigerim", 4),
+	}}
+	students.byID["other-cohort"] = enrolled("other-cohort", 5)
+	students.byID["other-cohort"].Cohort = "2024-fall"
+
+	requests := newMemoryHelpRequests(map[social.StudentID]string{
+		"dana": "2025-spring", "ali": "2025-spring", "arman": "2025-spring",
+		"aigerim": "2025-spring", "other-cohort": "2024-fall",
+	})
+	repo := &memorySocial{helpRequests: requests}
+	notifier := &countingNotifier{pings: make(map[string]int)}
+
+	return &clusterFixture{
+		requests: requests,
+		students: students,
+		notifier: notifier,
+		request: NewRequestHelpHandler(
+			students, repo, solvedBy{helper: "aigerim"}, alwaysOnline{}, notifier, nil, discardEvents{},
+			DefaultRequestHelpHandlerConfig(),
+		),
+		clusters: NewHelpClusterHandler(repo, students).WithEventPublisher(discardEvents{}),
+	}
+}
+
+func (f *clusterFixture) ask(t *testing.T, requesterID string) *RequestHelpResult {
+	t.Helper()
+	result, err := f.request.Handle(context.Background(), RequestHelpCommand{
+		RequesterID: requesterID, TaskID: "graph-01", NotifyHelpers: true,
+	})
+	require.NoError(t, err)
+	return result
+}
+
+func TestRequestHelp_ClustersDuplicatesAndNotifiesHelperOnce(t *testing.T) {
+	f := newClusterFixture()
+
+	first := f.ask(t, "dana")
+	assert.Equal(t, first.RequestID, first.ClusterID, "the first request opens its own cluster")
+	assert.Zero(t, first.ClusterPeers)
+	assert.Equal(t, 1, first.NotifiedCount)
+
+	second := f.ask(t, "ali")
+	third := f.ask(t, "arman")
+	assert.Equal(t, first.ClusterID, second.ClusterID)
+	assert.Equal(t, first.ClusterID, third.ClusterID)
+	assert.Equal(t, 1, second.ClusterPeers)
+	assert.Equal(t, 2, third.ClusterPeers)
+	assert.Zero(t, third.NotifiedCount)
+	assert.Equal(t, 1, f.notifier.pings["aigerim"], "the helper is pinged once per cluster")
+
+	// Another cohort's request is not a duplicate
+	other := f.ask(t, "other-cohort")
+	assert.NotEqual(t, first.ClusterID, other.ClusterID)
+	assert.Equal(t, 2, f.notifier.pings["aigerim"])
+}
+
+func TestRequestHelp_PullsLegacyRequestIntoCluster(t *testing.T) {
+	f := newClusterFixture()
+
+	legacy := f.ask(t, "dana")
+	f.requests.requests[legacy.RequestID].ClusterID = "" // created before clusters
+
+	joined := f.ask(t, "ali")
+	assert.Equal(t, legacy.RequestID, joined.ClusterID)
+	assert.Equal(t, 1, joined.ClusterPeers)
+	assert.Equal(t, legacy.RequestID, f.requests.requests[legacy.RequestID].ClusterID)
+}
+
+func TestHelpCluster_JoinGroupSharesContactsOfMembersWhoOptedIn(t *testing.T) {
+	ctx := context.Background()
+	f := newClusterFixture()
+	dana := f.ask(t, "dana")
+	ali := f.ask(t, "ali")
+	f.ask(t, "arman")
+
+	first, err := f.clusters.JoinGroup(ctx, dana.RequestID, "dana")
+	require.NoError(t, err)
+	assert.True(t, first.Joined)
+	assert.Empty(t, first.Members)
+	assert.Equal(t, 2, first.Waiting)
+
+	second, err := f.clusters.JoinGroup(ctx, ali.RequestID, "ali")
+	require.NoError(t, err)
+	require.Len(t, second.Members, 1)
+	assert.Equal(t, "dana", second.Members[0].ID)
+	assert.Equal(t, 1, second.Waiting)
+
+	again, err := f.clusters.JoinGroup(ctx, ali.RequestID, "ali")
+	require.NoError(t, err)
+	assert.False(t, again.Joined)
+
+	_, err = f.clusters.JoinGroup(ctx, ali.RequestID, "dana")
+	assert.ErrorIs(t, err, ErrHelpRequestNotRequester)
+
+	alone := f.ask(t, "other-cohort")
+	_, err = f.clusters.JoinGroup(ctx, alone.RequestID, "other-cohort")
+	assert.ErrorIs(t, err, social.ErrHelpRequestNotClustered)
+}
+
+func TestHelpCluster_AcceptOffersClusterThenTakeAndResolve(t *testing.T) {
+	ctx := context.Background()
+	f := newClusterFixture()
+	dana := f.ask(t, "dana")
+	ali := f.ask(t, "ali")
+	arman := f.ask(t, "arman")
+
+	respond := NewRespondToHelpRequestHandler(&memorySocial{helpRequests: f.requests})
+	accepted, err := respond.Handle(ctx, RespondToHelpRequestCommand{RequestID: dana.RequestID, HelperID: "aigerim", Kind: HelpResponseAccept})
+	require.NoError(t, err)
+	assert.Equal(t, 2, accepted.ClusterTakeable)
+
+	// Only the helper of a request can take its cluster
+	_, err = f.clusters.Take(ctx, dana.RequestID, "ali")
+	assert.ErrorIs(t, err, social.ErrHelpRequestNotHelper)
+
+	taken, err := f.clusters.Take(ctx, dana.RequestID, "aigerim")
+	require.NoError(t, err)
+	assert.Len(t, taken.Requests, 2)
+	for _, id := range []string{ali.RequestID, arman.RequestID} {
+		assert.Equal(t, social.StudentID("aigerim"), *f.requests.requests[id].HelperID)
+	}
+
+	// Arman resolves on their own: only their request closes
+	require.NoError(t, f.requests.requests[arman.RequestID].Resolve(social.HelpResolution{Method: social.HelpResolutionSelf}))
+
+	resolved, err := f.clusters.Resolve(ctx, dana.RequestID, "aigerim", "")
+	require.NoError(t, err)
+	assert.Len(t, resolved.Requests, 2)
+	assert.Equal(t, social.HelpRequestStatusResolved, f.requests.requests[dana.RequestID].Status)
+	assert.Equal(t, social.HelpRequestStatusResolved, f.requests.requests[ali.RequestID].Status)
+	assert.Equal(t, 2, f.students.byID["aigerim"].HelpCount)
+}
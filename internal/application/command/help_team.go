@@ -0,0 +1,96 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HELP TEAM COMMANDS
+// Answers to invitations into a team help request (see social.MaxHelpTeamSize
+// for the team rules). The request itself is created by RequestHelpHandler
+// with RequestHelpCommand.CoRequesterIDs.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// HelpTeamResult contains the team after an invitation was answered.
+type HelpTeamResult struct {
+	// Request is the team help request.
+	Request *social.HelpRequest
+
+	// Student is the invited student who answered.
+	Student *student.Student
+
+	// Participants are the other participants of the request, requester
+	// first; the caller tells them about the answer.
+	Participants []*student.Student
+}
+
+// HelpTeamHandler handles answers to team help request invitations.
+type HelpTeamHandler struct {
+	socialRepo  social.Repository
+	studentRepo student.Repository
+	now         func() time.Time
+}
+
+// NewHelpTeamHandler creates a new HelpTeamHandler.
+func NewHelpTeamHandler(socialRepo social.Repository, studentRepo student.Repository) *HelpTeamHandler {
+	return &HelpTeamHandler{
+		socialRepo:  socialRepo,
+		studentRepo: studentRepo,
+		now:         time.Now,
+	}
+}
+
+// Accept makes the invited student a participant of the request.
+func (h *HelpTeamHandler) Accept(ctx context.Context, requestID, studentID string) (*HelpTeamResult, error) {
+	return h.answer(ctx, requestID, studentID, func(req *social.HelpRequest, at time.Time) error {
+		return req.AddCoRequester(social.StudentID(studentID), at)
+	})
+}
+
+// Decline records that the invited student declined the invitation.
+func (h *HelpTeamHandler) Decline(ctx context.Context, requestID, studentID string) (*HelpTeamResult, error) {
+	return h.answer(ctx, requestID, studentID, func(req *social.HelpRequest, at time.Time) error {
+		return req.DeclineInvitation(social.StudentID(studentID), at)
+	})
+}
+
+// answer applies the student's answer to the request and saves its members.
+func (h *HelpTeamHandler) answer(
+	ctx context.Context,
+	requestID, studentID string,
+	apply func(*social.HelpRequest, time.Time) error,
+) (*HelpTeamResult, error) {
+	request, err := h.socialRepo.HelpRequests().GetByID(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("help_team: %w", err)
+	}
+
+	if err := apply(request, h.now()); err != nil {
+		return nil, fmt.Errorf("help_team: %w", err)
+	}
+	if err := h.socialRepo.HelpRequests().SaveMembers(ctx, request); err != nil {
+		return nil, fmt.Errorf("help_team: failed to save members: %w", err)
+	}
+
+	stud, err := h.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("help_team: failed to get student: %w", err)
+	}
+
+	result := &HelpTeamResult{Request: request, Student: stud}
+	for _, id := range request.Participants() {
+		if string(id) == studentID {
+			continue
+		}
+		// Best effort: a participant who can't be loaded just isn't told
+		if p, err := h.studentRepo.GetByID(ctx, string(id)); err == nil {
+			result.Participants = append(result.Participants, p)
+		}
+	}
+	return result, nil
+}
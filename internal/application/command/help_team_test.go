@@ -0,0 +1,114 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+type memoryEndorsements struct {
+	social.EndorsementRepository
+	byRequest map[string][]*social.Endorsement
+}
+
+func (r *memoryEndorsements) Create(_ context.Context, e *social.Endorsement) error {
+	r.byRequest[e.HelpRequestID] = append(r.byRequest[e.HelpRequestID], e)
+	return nil
+}
+
+func (r *memoryEndorsements) ListByHelpRequestID(_ context.Context, helpRequestID string) ([]*social.Endorsement, error) {
+	return r.byRequest[helpRequestID], nil
+}
+
+type teamSocial struct {
+	*memorySocial
+	endorsements *memoryEndorsements
+}
+
+func (s *teamSocial) Endorsements() social.EndorsementRepository { return s.endorsements }
+
+// askTeam creates dana's team request with ali and arman invited.
+func (f *clusterFixture) askTeam(t *testing.T) *RequestHelpResult {
+	t.Helper()
+	result, err := f.request.Handle(context.Background(), RequestHelpCommand{
+		RequesterID: "dana", TaskID: "graph-01", NotifyHelpers: true,
+		CoRequesterIDs: []string{"ali", "arman"},
+	})
+	require.NoError(t, err)
+	return result
+}
+
+func TestHelpTeam_InvitationsAndOneThreadPerTask(t *testing.T) {
+	ctx := context.Background()
+	f := newClusterFixture()
+	teams := NewHelpTeamHandler(&memorySocial{helpRequests: f.requests}, f.students)
+
+	result := f.askTeam(t)
+	assert.Equal(t, []string{"ali", "arman"}, result.Invited)
+	assert.Equal(t, 1, f.notifier.pings["aigerim"])
+
+	accepted, err := teams.Accept(ctx, result.RequestID, "ali")
+	require.NoError(t, err)
+	require.Len(t, accepted.Participants, 1)
+	assert.Equal(t, "dana", accepted.Participants[0].ID)
+
+	_, err = teams.Accept(ctx, result.RequestID, "ali")
+	assert.ErrorIs(t, err, social.ErrHelpRequestAlreadyMember)
+	_, err = teams.Decline(ctx, result.RequestID, "arman")
+	require.NoError(t, err)
+	_, err = teams.Accept(ctx, result.RequestID, "arman")
+	assert.ErrorIs(t, err, social.ErrHelpRequestNotInvited, "a declined invitation can't be accepted")
+
+	// The teammate doesn't open a second thread of helper pings for the task
+	_, err = f.request.Handle(ctx, RequestHelpCommand{RequesterID: "ali", TaskID: "graph-01"})
+	assert.ErrorIs(t, err, social.ErrHelpRequestAlreadyMember)
+	assert.Equal(t, 1, f.notifier.pings["aigerim"])
+}
+
+func TestHelpTeam_HelperIsCountedOncePerRequest(t *testing.T) {
+	ctx := context.Background()
+	f := newClusterFixture()
+	repo := &teamSocial{
+		memorySocial: &memorySocial{helpRequests: f.requests},
+		endorsements: &memoryEndorsements{byRequest: make(map[string][]*social.Endorsement)},
+	}
+	teams := NewHelpTeamHandler(repo, f.students)
+	resolve := NewResolveHelpRequestHandler(repo, f.students, discardEvents{})
+	endorse := NewGiveEndorsementHandler(f.students, repo, discardEvents{})
+
+	request := f.askTeam(t)
+	for _, id := range []string{"ali", "arman"} {
+		_, err := teams.Accept(ctx, request.RequestID, id)
+		require.NoError(t, err)
+	}
+
+	resolved, err := resolve.Handle(ctx, ResolveHelpRequestCommand{
+		RequestID: request.RequestID, RequesterID: "arman", HelperID: "aigerim",
+	})
+	require.NoError(t, err, "any participant resolves the request")
+	assert.Equal(t, []string{"dana", "ali", "arman"}, resolved.Participants)
+
+	helper := f.students.byID["aigerim"]
+	helper.HelpCount, helper.HelpRating = 2, 4.0
+
+	thank := func(giverID string, rating float64) error {
+		_, err := endorse.Handle(ctx, GiveEndorsementCommand{
+			GiverID: giverID, ReceiverID: "aigerim", HelpRequestID: request.RequestID, Rating: rating,
+		})
+		return err
+	}
+	require.NoError(t, thank("dana", 5))
+	require.NoError(t, thank("ali", 4))
+	require.NoError(t, thank("arman", 3))
+
+	assert.Equal(t, 3, helper.HelpCount, "the request counts once")
+	assert.InDelta(t, 4.0, helper.HelpRating, 1e-9, "the request adds the mean of the team's ratings")
+
+	assert.ErrorIs(t, thank("ali", 5), social.ErrEndorsementAlreadyExists)
+	assert.Error(t, thank("other-cohort", 5), "only participants endorse the helper")
+	assert.Equal(t, 3, helper.HelpCount)
+}
@@ -0,0 +1,91 @@
+// Package command contains write operations (CQRS - Commands).
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MARK NOTIFICATIONS READ COMMAND
+// Clears the unread badge of the notification center.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// MarkNotificationsReadCommand marks all of a student's notifications as read.
+type MarkNotificationsReadCommand struct {
+	// StudentID is the ID of the student.
+	StudentID string
+
+	// TelegramID identifies the student when StudentID is empty.
+	TelegramID int64
+}
+
+// Validate validates the command.
+func (c MarkNotificationsReadCommand) Validate() error {
+	if c.StudentID == "" && c.TelegramID == 0 {
+		return errors.New("either student_id or telegram_id is required")
+	}
+	if c.TelegramID != 0 && !student.TelegramID(c.TelegramID).IsValid() {
+		return student.ErrInvalidTelegramID
+	}
+	return nil
+}
+
+// MarkNotificationsReadResult contains the result of the command.
+type MarkNotificationsReadResult struct {
+	// StudentID is the resolved student ID.
+	StudentID string
+
+	// Marked is the number of notifications marked as read.
+	Marked int64
+}
+
+// MarkNotificationsReadHandler handles the MarkNotificationsRead command.
+type MarkNotificationsReadHandler struct {
+	studentRepo      student.Repository
+	notificationRepo notification.NotificationRepository
+}
+
+// NewMarkNotificationsReadHandler creates a new MarkNotificationsReadHandler.
+func NewMarkNotificationsReadHandler(
+	studentRepo student.Repository,
+	notificationRepo notification.NotificationRepository,
+) *MarkNotificationsReadHandler {
+	return &MarkNotificationsReadHandler{
+		studentRepo:      studentRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// Handle executes the command.
+func (h *MarkNotificationsReadHandler) Handle(
+	ctx context.Context,
+	cmd MarkNotificationsReadCommand,
+) (*MarkNotificationsReadResult, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	studentID := cmd.StudentID
+	if studentID == "" {
+		stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(cmd.TelegramID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get student: %w", err)
+		}
+		studentID = stud.ID
+	}
+
+	marked, err := h.notificationRepo.MarkAllRead(ctx, notification.RecipientID(studentID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+
+	return &MarkNotificationsReadResult{
+		StudentID: studentID,
+		Marked:    marked,
+	}, nil
+}
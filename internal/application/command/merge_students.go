@@ -0,0 +1,382 @@
+// Package command contains write operations (CQRS - Commands).
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MERGE STUDENTS COMMAND
+// Merges a duplicate student account into the one that is kept.
+// Duplicates happen when a cohort import and an early /start (with a typo'd
+// email) both create a row, splitting XP history and breaking rank.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ErrMergeActiveSessions is returned when both accounts are used right now
+// from different Telegram accounts, so it's unclear which one is the duplicate.
+var ErrMergeActiveSessions = errors.New("merge_students: both accounts have active sessions with different telegram ids")
+
+// MergeStudentsCommand contains the data to merge two student accounts.
+type MergeStudentsCommand struct {
+	// IntoID is the ID of the student that is kept.
+	IntoID string
+
+	// FromID is the ID of the duplicate that is merged and soft-deleted.
+	FromID string
+
+	// ActorID identifies the admin running the merge (for the audit log).
+	ActorID string
+
+	// DryRun runs the merge and rolls it back, returning what would be moved.
+	DryRun bool
+}
+
+// Validate validates the command.
+func (c MergeStudentsCommand) Validate() error {
+	if c.IntoID == "" {
+		return errors.New("merge_students: into_id is required")
+	}
+	if c.FromID == "" {
+		return errors.New("merge_students: from_id is required")
+	}
+	if c.IntoID == c.FromID {
+		return errors.New("merge_students: cannot merge a student into itself")
+	}
+	if c.ActorID == "" {
+		return errors.New("merge_students: actor_id is required")
+	}
+	return nil
+}
+
+// MergeSummary counts the rows moved by a merge.
+type MergeSummary struct {
+	XPHistory                   int64
+	DailyGrinds                 int
+	DailyGrindsSummed           int
+	StreakFromDuplicate         bool
+	Achievements                int64
+	ConnectionsRepointed        int
+	ConnectionsDroppedSelf      int
+	ConnectionsDroppedDuplicate int
+	HelpRequests                int64
+	Endorsements                int64
+	EndorsementsDropped         int64
+	TelegramIDMoved             bool
+}
+
+// AuditDetails returns the summary as audit log details.
+func (s MergeSummary) AuditDetails() map[string]interface{} {
+	return map[string]interface{}{
+		"xp_history":                    s.XPHistory,
+		"daily_grinds":                  s.DailyGrinds,
+		"daily_grinds_summed":           s.DailyGrindsSummed,
+		"streak_from_duplicate":         s.StreakFromDuplicate,
+		"achievements":                  s.Achievements,
+		"connections_repointed":         s.ConnectionsRepointed,
+		"connections_dropped_self":      s.ConnectionsDroppedSelf,
+		"connections_dropped_duplicate": s.ConnectionsDroppedDuplicate,
+		"help_requests":                 s.HelpRequests,
+		"endorsements":                  s.Endorsements,
+		"endorsements_dropped":          s.EndorsementsDropped,
+		"telegram_id_moved":             s.TelegramIDMoved,
+	}
+}
+
+// MergeStudentsResult contains the result of a merge.
+type MergeStudentsResult struct {
+	// Into is the kept student as it was before the merge.
+	Into *student.Student
+
+	// From is the duplicate as it was before the merge.
+	From *student.Student
+
+	// Summary counts the moved rows.
+	Summary MergeSummary
+
+	// DryRun indicates that nothing was committed.
+	DryRun bool
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// UNIT OF WORK
+// ══════════════════════════════════════════════════════════════════════════════
+
+// StudentMergeUnitOfWork is a single transaction spanning the student and
+// social domains, so a merge is applied fully or not at all.
+type StudentMergeUnitOfWork interface {
+	// Students returns the student merge repository bound to the transaction.
+	Students() student.MergeRepository
+
+	// Social returns the social merge repository bound to the transaction.
+	Social() social.MergeRepository
+
+	// Audit returns the audit log bound to the transaction.
+	Audit() shared.AuditLog
+
+	// Commit commits the transaction.
+	Commit(ctx context.Context) error
+
+	// Rollback rolls the transaction back. Safe to call after Commit.
+	Rollback(ctx context.Context) error
+}
+
+// StudentMergeUnitOfWorkFactory begins merge transactions.
+type StudentMergeUnitOfWorkFactory interface {
+	// Begin starts a new transaction.
+	Begin(ctx context.Context) (StudentMergeUnitOfWork, error)
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HANDLER
+// ══════════════════════════════════════════════════════════════════════════════
+
+// MergeStudentsHandler handles the MergeStudentsCommand.
+type MergeStudentsHandler struct {
+	studentRepo      student.Repository
+	uowFactory       StudentMergeUnitOfWorkFactory
+	onlineTracker    student.OnlineTracker
+	studentCache     student.StudentCache
+	leaderboardCache leaderboard.LeaderboardCache
+	invalidations    StudentInvalidations
+}
+
+// StudentInvalidations announces changed students to the in-process caches
+// of every service. Implemented by messaging.InvalidationPublisher.
+type StudentInvalidations interface {
+	StudentUpdated(ctx context.Context, studentID string, telegramID int64)
+	StudentDeleted(ctx context.Context, studentID string, telegramID int64)
+}
+
+// NewMergeStudentsHandler creates a new MergeStudentsHandler.
+// studentCache and leaderboardCache may be nil when Redis is disabled.
+func NewMergeStudentsHandler(
+	studentRepo student.Repository,
+	uowFactory StudentMergeUnitOfWorkFactory,
+	onlineTracker student.OnlineTracker,
+	studentCache student.StudentCache,
+	leaderboardCache leaderboard.LeaderboardCache,
+) *MergeStudentsHandler {
+	return &MergeStudentsHandler{
+		studentRepo:      studentRepo,
+		uowFactory:       uowFactory,
+		onlineTracker:    onlineTracker,
+		studentCache:     studentCache,
+		leaderboardCache: leaderboardCache,
+	}
+}
+
+// WithInvalidations announces merged students to other bot instances.
+func (h *MergeStudentsHandler) WithInvalidations(invalidations StudentInvalidations) *MergeStudentsHandler {
+	h.invalidations = invalidations
+	return h
+}
+
+// Handle executes the merge.
+func (h *MergeStudentsHandler) Handle(ctx context.Context, cmd MergeStudentsCommand) (*MergeStudentsResult, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, fmt.Errorf("merge_students: validation failed: %w", err)
+	}
+
+	into, err := h.studentRepo.GetByID(ctx, cmd.IntoID)
+	if err != nil {
+		return nil, fmt.Errorf("merge_students: student to keep not found: %w", err)
+	}
+	from, err := h.studentRepo.GetByID(ctx, cmd.FromID)
+	if err != nil {
+		return nil, fmt.Errorf("merge_students: duplicate not found: %w", err)
+	}
+	if from.Status == student.StatusLeft {
+		return nil, errors.New("merge_students: duplicate is already deleted")
+	}
+
+	moveTelegramID, err := h.checkSessions(ctx, into, from)
+	if err != nil {
+		return nil, err
+	}
+
+	uow, err := h.uowFactory.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("merge_students: failed to begin transaction: %w", err)
+	}
+	defer func() { _ = uow.Rollback(ctx) }()
+
+	summary, err := h.merge(ctx, uow, into, from, moveTelegramID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MergeStudentsResult{
+		Into:    into,
+		From:    from,
+		Summary: summary,
+		DryRun:  cmd.DryRun,
+	}
+	if cmd.DryRun {
+		return result, nil
+	}
+
+	details := summary.AuditDetails()
+	details["from_id"] = from.ID
+	entry := shared.NewAuditEntry(cmd.ActorID, shared.AuditActionStudentMerge, into.ID, details)
+	if err := uow.Audit().Record(ctx, entry); err != nil {
+		return nil, fmt.Errorf("merge_students: failed to write audit entry: %w", err)
+	}
+
+	if err := uow.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("merge_students: failed to commit: %w", err)
+	}
+
+	h.invalidateCaches(ctx, into, from)
+
+	return result, nil
+}
+
+// checkSessions rejects merges of two accounts that are both in use from
+// different Telegram accounts. It reports whether the kept student should
+// take over the duplicate's Telegram ID: when it has none, or when only the
+// duplicate's Telegram account is active.
+func (h *MergeStudentsHandler) checkSessions(ctx context.Context, into, from *student.Student) (bool, error) {
+	if from.TelegramID == 0 || from.TelegramID == into.TelegramID {
+		return false, nil
+	}
+	if into.TelegramID == 0 {
+		return true, nil
+	}
+
+	intoOnline, err := h.onlineTracker.IsOnline(ctx, into.ID)
+	if err != nil {
+		return false, fmt.Errorf("merge_students: failed to check session: %w", err)
+	}
+	fromOnline, err := h.onlineTracker.IsOnline(ctx, from.ID)
+	if err != nil {
+		return false, fmt.Errorf("merge_students: failed to check session: %w", err)
+	}
+
+	if intoOnline && fromOnline {
+		return false, ErrMergeActiveSessions
+	}
+	return fromOnline, nil
+}
+
+// merge moves all of the duplicate's data inside the transaction.
+func (h *MergeStudentsHandler) merge(
+	ctx context.Context,
+	uow StudentMergeUnitOfWork,
+	into, from *student.Student,
+	moveTelegramID bool,
+) (MergeSummary, error) {
+	var summary MergeSummary
+	var err error
+
+	students := uow.Students()
+	socials := uow.Social()
+
+	// Progress
+	if summary.XPHistory, err = students.MoveXPHistory(ctx, from.ID, into.ID); err != nil {
+		return summary, fmt.Errorf("merge_students: failed to move xp history: %w", err)
+	}
+
+	intoGrinds, err := students.GetAllDailyGrinds(ctx, into.ID)
+	if err != nil {
+		return summary, fmt.Errorf("merge_students: failed to load daily grinds: %w", err)
+	}
+	fromGrinds, err := students.GetAllDailyGrinds(ctx, from.ID)
+	if err != nil {
+		return summary, fmt.Errorf("merge_students: failed to load daily grinds: %w", err)
+	}
+	grinds, summed := student.MergeDailyGrinds(into.ID, intoGrinds, fromGrinds)
+	if err := students.ReplaceDailyGrinds(ctx, from.ID, grinds); err != nil {
+		return summary, fmt.Errorf("merge_students: failed to merge daily grinds: %w", err)
+	}
+	summary.DailyGrinds = len(grinds)
+	summary.DailyGrindsSummed = summed
+
+	intoStreak, err := students.GetStreak(ctx, into.ID)
+	if err != nil {
+		return summary, fmt.Errorf("merge_students: failed to load streak: %w", err)
+	}
+	fromStreak, err := students.GetStreak(ctx, from.ID)
+	if err != nil {
+		return summary, fmt.Errorf("merge_students: failed to load streak: %w", err)
+	}
+	if streak, fromWon := student.BetterStreak(into.ID, intoStreak, fromStreak); streak != nil {
+		if err := students.ReplaceStreak(ctx, from.ID, streak); err != nil {
+			return summary, fmt.Errorf("merge_students: failed to merge streak: %w", err)
+		}
+		summary.StreakFromDuplicate = fromWon
+	}
+
+	if summary.Achievements, err = students.MoveAchievements(ctx, from.ID, into.ID); err != nil {
+		return summary, fmt.Errorf("merge_students: failed to merge achievements: %w", err)
+	}
+
+	// Social graph
+	intoID, fromID := social.StudentID(into.ID), social.StudentID(from.ID)
+
+	intoConns, err := socials.GetConnections(ctx, intoID)
+	if err != nil {
+		return summary, fmt.Errorf("merge_students: failed to load connections: %w", err)
+	}
+	fromConns, err := socials.GetConnections(ctx, fromID)
+	if err != nil {
+		return summary, fmt.Errorf("merge_students: failed to load connections: %w", err)
+	}
+	plan := social.PlanConnectionMerge(intoID, fromID, intoConns, fromConns)
+	if err := socials.ApplyConnectionPlan(ctx, plan); err != nil {
+		return summary, fmt.Errorf("merge_students: failed to merge connections: %w", err)
+	}
+	summary.ConnectionsRepointed = len(plan.Repoint)
+	summary.ConnectionsDroppedSelf = len(plan.DropSelf)
+	summary.ConnectionsDroppedDuplicate = len(plan.DropDuplicate)
+
+	if summary.HelpRequests, err = socials.MoveHelpRequests(ctx, fromID, intoID); err != nil {
+		return summary, fmt.Errorf("merge_students: failed to move help requests: %w", err)
+	}
+	if summary.Endorsements, summary.EndorsementsDropped, err = socials.MoveEndorsements(ctx, fromID, intoID); err != nil {
+		return summary, fmt.Errorf("merge_students: failed to move endorsements: %w", err)
+	}
+
+	// The duplicate itself. Preferences stay as they are on the kept student.
+	if err := students.MarkMerged(ctx, from.ID); err != nil {
+		return summary, fmt.Errorf("merge_students: failed to delete duplicate: %w", err)
+	}
+	if moveTelegramID {
+		if err := students.SetTelegramID(ctx, into.ID, from.TelegramID); err != nil {
+			return summary, fmt.Errorf("merge_students: failed to move telegram id: %w", err)
+		}
+		summary.TelegramIDMoved = true
+	}
+
+	return summary, nil
+}
+
+// invalidateCaches drops both students from every cache after a merge.
+// Failures are ignored: entries expire on their own and the merge is committed.
+func (h *MergeStudentsHandler) invalidateCaches(ctx context.Context, into, from *student.Student) {
+	if h.studentCache != nil {
+		_ = h.studentCache.Invalidate(ctx, from.ID)
+		_ = h.studentCache.Invalidate(ctx, into.ID)
+	}
+
+	_ = h.onlineTracker.MarkOffline(ctx, from.ID)
+
+	if h.leaderboardCache != nil {
+		_ = h.leaderboardCache.InvalidateCache(ctx, leaderboard.CohortAll)
+		_ = h.leaderboardCache.InvalidateCache(ctx, leaderboard.Cohort(into.Cohort))
+		if from.Cohort != into.Cohort {
+			_ = h.leaderboardCache.InvalidateCache(ctx, leaderboard.Cohort(from.Cohort))
+		}
+	}
+
+	if h.invalidations != nil {
+		h.invalidations.StudentDeleted(ctx, from.ID, int64(from.TelegramID))
+		h.invalidations.StudentUpdated(ctx, into.ID, int64(into.TelegramID))
+	}
+}
@@ -0,0 +1,106 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MUTE NOTIFICATIONS COMMAND
+// Temporarily silences the bot (/mute 24h) or one notification category
+// (/mute rank). Unlike preferences, a mute ends by itself. Urgent alerts
+// are never muted.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// MuteNotificationsCommand contains the data to mute notifications.
+type MuteNotificationsCommand struct {
+	// StudentID is the ID of the student.
+	StudentID string
+
+	// Duration is how long the mute lasts.
+	Duration time.Duration
+
+	// Category limits the mute to one category
+	// (student.MuteCategoryOther mutes everything non-urgent).
+	Category student.MuteCategory
+}
+
+// Validate validates the command.
+func (c MuteNotificationsCommand) Validate() error {
+	if c.StudentID == "" {
+		return errors.New("mute_notifications: student_id is required")
+	}
+	if c.Duration < time.Minute || c.Duration > student.MaxMuteDuration {
+		return fmt.Errorf("mute_notifications: %w", student.ErrInvalidMuteDuration)
+	}
+	if c.Category != student.MuteCategoryOther {
+		if _, ok := student.ParseMuteCategory(string(c.Category)); !ok {
+			return fmt.Errorf("mute_notifications: unknown category %q", c.Category)
+		}
+	}
+	return nil
+}
+
+// MuteNotificationsResult contains the result of muting.
+type MuteNotificationsResult struct {
+	// Until is when the mute ends.
+	Until time.Time
+}
+
+// MuteNotificationsHandler handles muting and unmuting.
+type MuteNotificationsHandler struct {
+	studentRepo student.Repository
+	now         func() time.Time
+}
+
+// NewMuteNotificationsHandler creates a new MuteNotificationsHandler.
+func NewMuteNotificationsHandler(studentRepo student.Repository) *MuteNotificationsHandler {
+	return &MuteNotificationsHandler{
+		studentRepo: studentRepo,
+		now:         time.Now,
+	}
+}
+
+// Handle executes the mute command.
+func (h *MuteNotificationsHandler) Handle(ctx context.Context, cmd MuteNotificationsCommand) (*MuteNotificationsResult, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	stud, err := h.studentRepo.GetByID(ctx, cmd.StudentID)
+	if err != nil {
+		return nil, fmt.Errorf("mute_notifications: failed to get student: %w", err)
+	}
+
+	now := h.now()
+	if cmd.Category == student.MuteCategoryOther {
+		stud.Mute(cmd.Duration, now)
+	} else {
+		stud.MuteCategory(cmd.Category, cmd.Duration, now)
+	}
+
+	if err := h.studentRepo.SaveMutes(ctx, stud); err != nil {
+		return nil, fmt.Errorf("mute_notifications: failed to save mutes: %w", err)
+	}
+
+	return &MuteNotificationsResult{Until: now.Add(cmd.Duration).UTC()}, nil
+}
+
+// Unmute clears all mutes of the student.
+func (h *MuteNotificationsHandler) Unmute(ctx context.Context, studentID string) error {
+	stud, err := h.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return fmt.Errorf("mute_notifications: failed to get student: %w", err)
+	}
+
+	stud.Unmute(h.now())
+
+	if err := h.studentRepo.SaveMutes(ctx, stud); err != nil {
+		return fmt.Errorf("mute_notifications: failed to save mutes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// NUDGE COMMAND
+// Cheers on a connection who is close to a milestone: "👏 Твой напарник Dana
+// болеет за тебя: ещё чуть-чуть до 30 дней!". Offered by the "👏 Подбодрить"
+// button in the daily digest and /connections.
+//
+// A nudge is a small social gesture, so it is cheap to suppress: at most one
+// per sender and recipient per day (the recipient's day), never in quiet
+// hours or while the recipient is muted. Nudges are not audited; delivery
+// outcomes are only counted (see Metrics).
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ErrNudgeNotConnected is returned when the recipient is not an active buddy
+// or mentor connection of the sender.
+var ErrNudgeNotConnected = errors.New("nudge recipient is not a connection")
+
+// Reasons a nudge was not delivered (NudgeResult.SkipReason).
+const (
+	NudgeSkipNoMilestone = "no_milestone"
+	NudgeSkipAlreadySent = "already_sent"
+	NudgeSkipQuietHours  = "quiet_hours"
+	NudgeSkipMuted       = "muted"
+	NudgeSkipUnavailable = "unavailable"
+)
+
+// nudgeCooldownTTL outlives the recipient's day; the key itself carries
+// the date.
+const nudgeCooldownTTL = 24 * time.Hour
+
+// NudgeCommand contains the data to cheer on a connection.
+type NudgeCommand struct {
+	// SenderID is the student who taps the button.
+	SenderID string
+
+	// RecipientID is the connection being cheered on.
+	RecipientID string
+}
+
+// Validate validates the command.
+func (c NudgeCommand) Validate() error {
+	if c.SenderID == "" {
+		return errors.New("nudge: sender_id is required")
+	}
+	if c.RecipientID == "" {
+		return errors.New("nudge: recipient_id is required")
+	}
+	if c.SenderID == c.RecipientID {
+		return errors.New("nudge: cannot nudge self")
+	}
+	return nil
+}
+
+// NudgeResult contains the outcome of a nudge.
+type NudgeResult struct {
+	// Delivered is true if the recipient got the message.
+	Delivered bool
+
+	// SkipReason explains why the nudge was not sent (NudgeSkip*).
+	SkipReason string
+
+	// RecipientName is the display name of the recipient.
+	RecipientName string
+
+	// Milestone is the milestone the recipient is close to.
+	Milestone student.UpcomingMilestone
+}
+
+// NudgeNotifier delivers the nudge message.
+type NudgeNotifier interface {
+	Send(ctx context.Context, notification *notification.Notification) notification.DeliveryResult
+}
+
+// NudgeHandler handles nudges between connections.
+type NudgeHandler struct {
+	studentRepo  student.Repository
+	connections  social.ConnectionRepository
+	progressRepo student.ProgressRepository
+	cooldowns    notification.CooldownStore
+	notifier     NudgeNotifier
+	clock        shared.Clock
+
+	metrics nudgeMetrics
+}
+
+// NewNudgeHandler creates a new NudgeHandler. The notifier is bound
+// separately (WithNotifier) by the process that owns the Telegram client.
+func NewNudgeHandler(
+	studentRepo student.Repository,
+	connections social.ConnectionRepository,
+	progressRepo student.ProgressRepository,
+	cooldowns notification.CooldownStore,
+) *NudgeHandler {
+	return &NudgeHandler{
+		studentRepo:  studentRepo,
+		connections:  connections,
+		progressRepo: progressRepo,
+		cooldowns:    cooldowns,
+		clock:        shared.SystemClock{},
+	}
+}
+
+// WithNotifier sets how nudges are delivered.
+func (h *NudgeHandler) WithNotifier(notifier NudgeNotifier) *NudgeHandler {
+	h.notifier = notifier
+	return h
+}
+
+// WithClock replaces the system clock (used in tests).
+func (h *NudgeHandler) WithClock(clock shared.Clock) *NudgeHandler {
+	h.clock = shared.ClockOrSystem(clock)
+	return h
+}
+
+// Handle sends the nudge, unless it has to be suppressed. A suppressed nudge
+// is not an error: the result carries the reason.
+func (h *NudgeHandler) Handle(ctx context.Context, cmd NudgeCommand) (*NudgeResult, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+	if h.notifier == nil {
+		return nil, errors.New("nudge: no notifier configured")
+	}
+
+	sender, err := h.studentRepo.GetByID(ctx, cmd.SenderID)
+	if err != nil {
+		return nil, fmt.Errorf("nudge: failed to get sender: %w", err)
+	}
+	recipient, err := h.studentRepo.GetByID(ctx, cmd.RecipientID)
+	if err != nil {
+		return nil, fmt.Errorf("nudge: failed to get recipient: %w", err)
+	}
+
+	conn, err := h.connections.GetByStudents(ctx, social.StudentID(sender.ID), social.StudentID(recipient.ID))
+	if err != nil || conn == nil || !conn.IsActive() || !conn.Type.AllowsNudges() {
+		return nil, ErrNudgeNotConnected
+	}
+
+	now := h.clock.Now()
+	result := &NudgeResult{RecipientName: recipient.DisplayName}
+
+	milestone, ok := student.NearMilestone(recipient.CurrentXP, h.streakOf(ctx, recipient))
+	if !ok {
+		return h.skip(result, NudgeSkipNoMilestone), nil
+	}
+	result.Milestone = milestone
+
+	switch {
+	case !recipient.Status.CanReceiveNotifications():
+		return h.skip(result, NudgeSkipUnavailable), nil
+	case !recipient.AcceptsNotification(string(notification.NotificationTypeEncouragement), now):
+		return h.skip(result, NudgeSkipMuted), nil
+	case recipient.Preferences.IsQuietHour(now):
+		return h.skip(result, NudgeSkipQuietHours), nil
+	}
+
+	// The cap is taken last: suppressed nudges don't use it up
+	key := nudgeCooldownKey(sender.ID, recipient.ID, student.LocalDate(now, recipient.Preferences.Location()))
+	acquired, err := h.cooldowns.Acquire(ctx, key, nudgeCooldownTTL)
+	if err != nil {
+		return nil, fmt.Errorf("nudge: failed to check cooldown: %w", err)
+	}
+	if !acquired {
+		return h.skip(result, NudgeSkipAlreadySent), nil
+	}
+
+	notif, err := notification.NewNotification(notification.NewNotificationParams{
+		ID:             notification.NotificationID(uuid.New().String()),
+		Type:           notification.NotificationTypeEncouragement,
+		RecipientID:    notification.RecipientID(recipient.ID),
+		TelegramChatID: notification.TelegramChatID(recipient.TelegramID),
+		Message:        nudgeMessage(sender, conn, milestone),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("nudge: failed to create notification: %w", err)
+	}
+	notif.SetMetadata("nudge_from", sender.ID)
+
+	delivery := h.notifier.Send(ctx, notif)
+	if delivery.Skipped() {
+		// Muted between the check above and the send
+		return h.skip(result, NudgeSkipMuted), nil
+	}
+	if !delivery.Success {
+		h.metrics.add("failed")
+		return nil, fmt.Errorf("nudge: failed to deliver: %w", delivery.Error)
+	}
+	h.metrics.add("sent")
+
+	result.Delivered = true
+	return result, nil
+}
+
+// Metrics returns how many nudges were sent, failed or suppressed (by
+// reason) since the process started.
+func (h *NudgeHandler) Metrics() map[string]int64 {
+	return h.metrics.snapshot()
+}
+
+// skip counts a suppressed nudge and returns the result with the reason.
+func (h *NudgeHandler) skip(result *NudgeResult, reason string) *NudgeResult {
+	h.metrics.add("skipped_" + reason)
+	result.SkipReason = reason
+	return result
+}
+
+// streakOf returns the student's streak in their time zone (nil if none).
+func (h *NudgeHandler) streakOf(ctx context.Context, stud *student.Student) *student.Streak {
+	if h.progressRepo == nil {
+		return nil
+	}
+	streak, err := h.progressRepo.GetStreak(ctx, stud.ID)
+	if err != nil || streak == nil {
+		return nil
+	}
+	return streak.WithClock(h.clock).WithLocation(stud.Preferences.Location())
+}
+
+// nudgeCooldownKey is the once-per-day key of a sender and recipient.
+func nudgeCooldownKey(senderID, recipientID string, day time.Time) string {
+	return fmt.Sprintf("nudge:%s:%s:%s", senderID, recipientID, day.Format("2006-01-02"))
+}
+
+// nudgeMessage renders the encouragement attributed to the sender. Mentors
+// and mentees are named without a role: the connection doesn't say which
+// side the sender is on.
+func nudgeMessage(sender *student.Student, conn *social.Connection, milestone student.UpcomingMilestone) string {
+	who := fmt.Sprintf("<b>%s</b>", html.EscapeString(sender.DisplayName))
+	if conn.Type == social.ConnectionTypeStudyBuddy {
+		who = "Твой напарник " + who
+	}
+
+	goal := fmt.Sprintf("%d уровня", milestone.Target)
+	if milestone.Kind == student.MilestoneKindStreak {
+		goal = fmt.Sprintf("%d дней", milestone.Target)
+	}
+
+	return fmt.Sprintf("👏 %s болеет за тебя: ещё чуть-чуть до %s!", who, goal)
+}
+
+// nudgeMetrics counts nudge outcomes.
+type nudgeMetrics struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func (m *nudgeMetrics) add(outcome string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counts == nil {
+		m.counts = make(map[string]int64)
+	}
+	m.counts[outcome]++
+}
+
+func (m *nudgeMetrics) snapshot() map[string]int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make(map[string]int64, len(m.counts))
+	for k, v := range m.counts {
+		snapshot[k] = v
+	}
+	return snapshot
+}
@@ -0,0 +1,202 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
+)
+
+type nudgeStudents struct {
+	student.Repository
+	byID map[string]*student.Student
+}
+
+func (r *nudgeStudents) GetByID(_ context.Context, id string) (*student.Student, error) {
+	if s, ok := r.byID[id]; ok {
+		copied := *s
+		return &copied, nil
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+type nudgeConnections struct {
+	social.ConnectionRepository
+	conn *social.Connection
+}
+
+func (c *nudgeConnections) GetByStudents(_ context.Context, a, b social.StudentID) (*social.Connection, error) {
+	if c.conn.InvolveStudent(a) && c.conn.InvolveStudent(b) {
+		return c.conn, nil
+	}
+	return nil, social.ErrConnectionNotFound
+}
+
+type nudgeStreaks struct {
+	student.ProgressRepository
+	byID map[string]*student.Streak
+}
+
+func (p *nudgeStreaks) GetStreak(_ context.Context, id string) (*student.Streak, error) {
+	if s, ok := p.byID[id]; ok {
+		copied := *s
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+type nudgeCooldowns struct {
+	taken map[string]bool
+}
+
+func (c *nudgeCooldowns) Acquire(_ context.Context, key string, _ time.Duration) (bool, error) {
+	if c.taken[key] {
+		return false, nil
+	}
+	c.taken[key] = true
+	return true, nil
+}
+
+type nudgeNotifier struct {
+	sent []*notification.Notification
+}
+
+func (n *nudgeNotifier) Send(_ context.Context, notif *notification.Notification) notification.DeliveryResult {
+	n.sent = append(n.sent, notif)
+	return notification.DeliveryResult{Success: true}
+}
+
+type nudgeFixture struct {
+	handler  *NudgeHandler
+	students *nudgeStudents
+	notifier *nudgeNotifier
+	clock    *shared.FakeClock
+}
+
+// newNudgeFixture: dana and arman are study buddies; arman is on a 29-day
+// streak, active today.
+func newNudgeFixture(now time.Time) *nudgeFixture {
+	newStudent := func(id, name string, telegramID int64) *student.Student {
+		return &student.Student{
+			ID:          id,
+			TelegramID:  student.TelegramID(telegramID),
+			DisplayName: name,
+			Status:      student.StatusActive,
+			CurrentXP:   4500,
+			Preferences: student.DefaultNotificationPreferences(),
+		}
+	}
+	students := &nudgeStudents{byID: map[string]*student.Student{
+		"dana":  newStudent("dana", "Dana", 101),
+		"arman": newStudent("arman", "Arman", 102),
+		"bolat": newStudent("bolat", "Bolat", 103),
+	}}
+	streaks := &nudgeStreaks{byID: map[string]*student.Streak{
+		"arman": {
+			StudentID:      "arman",
+			CurrentStreak:  29,
+			BestStreak:     29,
+			LastActiveDate: student.LocalDate(now, timeutil.AlmatyTZ),
+		},
+	}}
+	conn := &social.Connection{
+		InitiatorID: "dana",
+		ReceiverID:  "arman",
+		Type:        social.ConnectionTypeStudyBuddy,
+		Status:      social.ConnectionStatusActive,
+	}
+	clock := shared.NewFakeClock(now)
+	notifier := &nudgeNotifier{}
+
+	handler := NewNudgeHandler(students, &nudgeConnections{conn: conn}, streaks, &nudgeCooldowns{taken: make(map[string]bool)}).
+		WithNotifier(notifier).
+		WithClock(clock)
+
+	return &nudgeFixture{handler: handler, students: students, notifier: notifier, clock: clock}
+}
+
+func TestNudge_OncePerPairPerDay(t *testing.T) {
+	ctx := context.Background()
+	f := newNudgeFixture(time.Date(2026, time.October, 17, 14, 0, 0, 0, timeutil.AlmatyTZ))
+	cmd := NudgeCommand{SenderID: "dana", RecipientID: "arman"}
+
+	result, err := f.handler.Handle(ctx, cmd)
+	require.NoError(t, err)
+	assert.True(t, result.Delivered)
+	assert.Equal(t, student.UpcomingMilestone{Kind: student.MilestoneKindStreak, Target: 30, Remaining: 1}, result.Milestone)
+	require.Len(t, f.notifier.sent, 1)
+	assert.Equal(t, "👏 Твой напарник <b>Dana</b> болеет за тебя: ещё чуть-чуть до 30 дней!", f.notifier.sent[0].Message)
+	assert.Equal(t, notification.TelegramChatID(102), f.notifier.sent[0].TelegramChatID)
+
+	// The second tap the same day is capped
+	result, err = f.handler.Handle(ctx, cmd)
+	require.NoError(t, err)
+	assert.False(t, result.Delivered)
+	assert.Equal(t, NudgeSkipAlreadySent, result.SkipReason)
+	assert.Len(t, f.notifier.sent, 1)
+
+	// The next day the cap is reset
+	f.clock.Advance(24 * time.Hour)
+	result, err = f.handler.Handle(ctx, cmd)
+	require.NoError(t, err)
+	assert.True(t, result.Delivered)
+	assert.Len(t, f.notifier.sent, 2)
+
+	assert.Equal(t, map[string]int64{"sent": 2, "skipped_already_sent": 1}, f.handler.Metrics())
+
+	// Only connections can nudge
+	_, err = f.handler.Handle(ctx, NudgeCommand{SenderID: "bolat", RecipientID: "arman"})
+	assert.ErrorIs(t, err, ErrNudgeNotConnected)
+}
+
+func TestNudge_SuppressedForMutedRecipient(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.October, 17, 14, 0, 0, 0, timeutil.AlmatyTZ)
+	f := newNudgeFixture(now)
+	f.students.byID["arman"].Mute(8*time.Hour, now)
+
+	result, err := f.handler.Handle(ctx, NudgeCommand{SenderID: "dana", RecipientID: "arman"})
+	require.NoError(t, err)
+	assert.False(t, result.Delivered)
+	assert.Equal(t, NudgeSkipMuted, result.SkipReason)
+	assert.Empty(t, f.notifier.sent)
+
+	// A suppressed nudge doesn't use up the day's cap
+	f.clock.Advance(9 * time.Hour) // 23:00: the mute is over, quiet hours began
+	result, err = f.handler.Handle(ctx, NudgeCommand{SenderID: "dana", RecipientID: "arman"})
+	require.NoError(t, err)
+	assert.Equal(t, NudgeSkipQuietHours, result.SkipReason)
+
+	// Back at 16:00 once arman has unmuted
+	f.clock.Set(now.Add(2 * time.Hour))
+	f.students.byID["arman"].Unmute(now)
+	result, err = f.handler.Handle(ctx, NudgeCommand{SenderID: "dana", RecipientID: "arman"})
+	require.NoError(t, err)
+	assert.True(t, result.Delivered)
+}
+
+func TestNudge_NothingToCheer(t *testing.T) {
+	f := newNudgeFixture(time.Date(2026, time.October, 17, 14, 0, 0, 0, timeutil.AlmatyTZ))
+
+	// dana has no streak and is 500 XP away from level 5
+	result, err := f.handler.Handle(context.Background(), NudgeCommand{SenderID: "arman", RecipientID: "dana"})
+	require.NoError(t, err)
+	assert.Equal(t, NudgeSkipNoMilestone, result.SkipReason)
+	assert.Empty(t, f.notifier.sent)
+
+	// 40 XP short of level 5
+	f.students.byID["dana"].CurrentXP = 4960
+	result, err = f.handler.Handle(context.Background(), NudgeCommand{SenderID: "arman", RecipientID: "dana"})
+	require.NoError(t, err)
+	assert.True(t, result.Delivered)
+	assert.Equal(t, student.UpcomingMilestone{Kind: student.MilestoneKindLevel, Target: 5, Remaining: 40}, result.Milestone)
+	assert.Contains(t, f.notifier.sent[0].Message, "до 5 уровня")
+}
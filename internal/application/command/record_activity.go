@@ -143,6 +143,9 @@ type RecordActivityHandler struct {
 	// Configuration
 	onlineTTL         time.Duration // How long to consider someone online without heartbeat
 	sessionExpiration time.Duration // When to auto-expire sessions
+
+	// clock is the source of default timestamps and of "today" for streaks.
+	clock shared.Clock
 }
 
 // RecordActivityHandlerConfig contains configuration for the handler.
@@ -180,9 +183,16 @@ func NewRecordActivityHandler(
 		eventPublisher:    eventPublisher,
 		onlineTTL:         config.OnlineTTL,
 		sessionExpiration: config.SessionExpiration,
+		clock:             shared.SystemClock{},
 	}
 }
 
+// WithClock replaces the system clock (used in tests).
+func (h *RecordActivityHandler) WithClock(clock shared.Clock) *RecordActivityHandler {
+	h.clock = shared.ClockOrSystem(clock)
+	return h
+}
+
 // Handle executes the record activity command.
 func (h *RecordActivityHandler) Handle(ctx context.Context, cmd RecordActivityCommand) (*RecordActivityResult, error) {
 	// Validate command
@@ -193,7 +203,7 @@ func (h *RecordActivityHandler) Handle(ctx context.Context, cmd RecordActivityCo
 	// Set timestamp if not provided
 	timestamp := cmd.Timestamp
 	if timestamp.IsZero() {
-		timestamp = time.Now().UTC()
+		timestamp = h.clock.Now()
 	}
 
 	// Get student
@@ -245,7 +255,7 @@ func (h *RecordActivityHandler) Handle(ctx context.Context, cmd RecordActivityCo
 	}
 
 	// Update daily progress
-	if err := h.updateDailyProgress(ctx, cmd.StudentID, cmd.Type, cmd.XPEarned, timestamp); err != nil {
+	if err := h.updateDailyProgress(ctx, stud, cmd.Type, cmd.XPEarned, timestamp); err != nil {
 		// Log but don't fail
 	}
 
@@ -295,7 +305,7 @@ func (h *RecordActivityHandler) handleGoOnline(
 		// Create new session
 		timestamp := cmd.Timestamp
 		if timestamp.IsZero() {
-			timestamp = time.Now().UTC()
+			timestamp = h.clock.Now()
 		}
 
 		newSession, err := activity.NewSession(
@@ -345,7 +355,7 @@ func (h *RecordActivityHandler) handleGoOffline(
 	if err == nil && session != nil {
 		timestamp := cmd.Timestamp
 		if timestamp.IsZero() {
-			timestamp = time.Now().UTC()
+			timestamp = h.clock.Now()
 		}
 
 		if err := session.End(timestamp); err == nil {
@@ -409,7 +419,7 @@ func (h *RecordActivityHandler) handleTaskCompleted(
 ) error {
 	timestamp := cmd.Timestamp
 	if timestamp.IsZero() {
-		timestamp = time.Now().UTC()
+		timestamp = h.clock.Now()
 	}
 
 	// Create task completion record
@@ -492,7 +502,7 @@ func (h *RecordActivityHandler) handleSessionEnd(
 		if err == nil && session != nil && string(session.ID) == cmd.SessionID {
 			timestamp := cmd.Timestamp
 			if timestamp.IsZero() {
-				timestamp = time.Now().UTC()
+				timestamp = h.clock.Now()
 			}
 			_ = session.End(timestamp)
 			_ = h.activityRepo.SaveSession(ctx, session)
@@ -518,6 +528,7 @@ func (h *RecordActivityHandler) updateStreak(
 		// Create new streak if not exists
 		streak = student.NewStreak(stud.ID)
 	}
+	streak.WithClock(h.clock).WithLocation(stud.Preferences.Location())
 
 	previousStreak := streak.CurrentStreak
 
@@ -527,6 +538,9 @@ func (h *RecordActivityHandler) updateStreak(
 	// Record activity
 	streak.RecordActivity(activityTime)
 
+	// A milestone is marked on the streak before saving, so it fires once
+	milestone, reached := streak.ReachMilestone(previousStreak)
+
 	// Save streak
 	if err := h.progressRepo.SaveStreak(ctx, streak); err != nil {
 		return fmt.Errorf("failed to save streak: %w", err)
@@ -535,6 +549,10 @@ func (h *RecordActivityHandler) updateStreak(
 	result.CurrentStreak = streak.CurrentStreak
 	result.StreakUpdated = streak.CurrentStreak != previousStreak
 
+	if reached {
+		result.Events = append(result.Events, shared.NewStreakMilestoneReachedEvent(stud.ID, milestone, streak.CurrentStreak))
+	}
+
 	// Check if streak was broken
 	if wasBroken && previousStreak > 1 {
 		result.StreakBroken = true
@@ -552,20 +570,17 @@ func (h *RecordActivityHandler) updateStreak(
 // updateDailyProgress updates the daily grind progress.
 func (h *RecordActivityHandler) updateDailyProgress(
 	ctx context.Context,
-	studentID string,
+	stud *student.Student,
 	activityType ActivityType,
 	xpEarned int,
 	timestamp time.Time,
 ) error {
-	// Get or create today's daily grind
-	grind, err := h.progressRepo.GetTodayDailyGrind(ctx, studentID)
+	// Get or create the daily grind of the student's local day
+	date := student.LocalDate(timestamp, stud.Preferences.Location())
+	grind, err := h.progressRepo.GetDailyGrind(ctx, stud.ID, date)
 	if err != nil {
-		// Get current XP and rank for new daily grind
-		stud, err := h.studentRepo.GetByID(ctx, studentID)
-		if err != nil {
-			return err
-		}
-		grind = student.NewDailyGrind(studentID, stud.CurrentXP, 0)
+		grind = student.NewDailyGrind(stud.ID, stud.CurrentXP, 0)
+		grind.Date = date
 	}
 
 	// Update based on activity type
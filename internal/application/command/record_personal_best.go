@@ -0,0 +1,95 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RECORD PERSONAL BEST COMMAND
+// Stores a candidate personal best (best day, best week, longest session)
+// and announces it when a previous record is beaten.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// RecordPersonalBestCommand contains a candidate value for a personal best.
+type RecordPersonalBestCommand struct {
+	// StudentID is the ID of the student.
+	StudentID string
+
+	// Metric is the kind of record.
+	Metric student.PersonalBestMetric
+
+	// Value is the candidate value (XP or minutes).
+	Value int
+
+	// AchievedOn is the day the value was reached (the Monday for weeks).
+	AchievedOn time.Time
+
+	// Suppress stores the record without announcing it.
+	// Set by historical backfills.
+	Suppress bool
+}
+
+// RecordPersonalBestResult contains the result of recording a personal best.
+type RecordPersonalBestResult struct {
+	// Update is the outcome of the conditional upsert.
+	Update student.PersonalBestUpdate
+
+	// Announced is true if a PersonalBestSetEvent was published.
+	Announced bool
+}
+
+// RecordPersonalBestHandler handles the RecordPersonalBestCommand.
+type RecordPersonalBestHandler struct {
+	bests          student.PersonalBestRepository
+	eventPublisher shared.EventPublisher
+}
+
+// NewRecordPersonalBestHandler creates a new RecordPersonalBestHandler.
+// A nil eventPublisher stores records without announcing them.
+func NewRecordPersonalBestHandler(
+	bests student.PersonalBestRepository,
+	eventPublisher shared.EventPublisher,
+) *RecordPersonalBestHandler {
+	return &RecordPersonalBestHandler{
+		bests:          bests,
+		eventPublisher: eventPublisher,
+	}
+}
+
+// Handle records the value. Reprocessing the same value is a no-op, and a
+// value that only ties the record is not announced.
+func (h *RecordPersonalBestHandler) Handle(ctx context.Context, cmd RecordPersonalBestCommand) (*RecordPersonalBestResult, error) {
+	best, err := student.NewPersonalBest(cmd.StudentID, cmd.Metric, cmd.Value, cmd.AchievedOn)
+	if err != nil {
+		return nil, fmt.Errorf("record_personal_best: %w", err)
+	}
+
+	update, err := h.bests.UpsertBest(ctx, best)
+	if err != nil {
+		return nil, fmt.Errorf("record_personal_best: %w", err)
+	}
+
+	result := &RecordPersonalBestResult{Update: update}
+	if cmd.Suppress || h.eventPublisher == nil || !update.ShouldNotify() {
+		return result, nil
+	}
+
+	event := shared.NewPersonalBestSetEvent(
+		best.StudentID,
+		string(best.Metric),
+		best.Value,
+		update.Previous.Value,
+		best.AchievedOn,
+	)
+	if err := h.eventPublisher.Publish(event); err != nil {
+		return result, fmt.Errorf("record_personal_best: publish event: %w", err)
+	}
+	result.Announced = true
+
+	return result, nil
+}
@@ -0,0 +1,168 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RELINK TELEGRAM COMMAND
+// Moves a student's profile to a new Telegram account after a phone number
+// change. The student proves the profile is theirs with the Alem email and
+// password they registered with in /start (stored as a bcrypt hash). The
+// swap is conditional on the old Telegram ID, so concurrent attempts cannot
+// both succeed, and a per-student cooldown rejects back-to-back relinks.
+// ══════════════════════════════════════════════════════════════════════════════
+
+var (
+	// ErrRelinkInvalidCredentials is returned when no profile matches the
+	// email and password. The two cases are not told apart.
+	ErrRelinkInvalidCredentials = errors.New("relink_telegram: invalid email or password")
+
+	// ErrRelinkAlreadyRegistered is returned when the new Telegram account
+	// already has a profile of its own (two profiles are merged by admins).
+	ErrRelinkAlreadyRegistered = errors.New("relink_telegram: telegram account already has a profile")
+
+	// ErrRelinkTooSoon is returned when the profile was relinked less than
+	// student.RelinkCooldown ago.
+	ErrRelinkTooSoon = errors.New("relink_telegram: profile was relinked recently")
+)
+
+// RelinkTelegramCommand contains the data to move a profile.
+type RelinkTelegramCommand struct {
+	// NewTelegramID is the Telegram account the profile moves to.
+	NewTelegramID int64
+
+	// Email and Password are the Alem credentials of the profile.
+	Email    string
+	Password string
+}
+
+// Validate validates the command.
+func (c RelinkTelegramCommand) Validate() error {
+	if !student.TelegramID(c.NewTelegramID).IsValid() {
+		return fmt.Errorf("relink_telegram: %w", student.ErrInvalidTelegramID)
+	}
+	if strings.TrimSpace(c.Email) == "" {
+		return errors.New("relink_telegram: email is required")
+	}
+	if c.Password == "" {
+		return errors.New("relink_telegram: password is required")
+	}
+	return nil
+}
+
+// RelinkTelegramResult contains the result of a relink.
+type RelinkTelegramResult struct {
+	// Student is the relinked student with the new Telegram ID.
+	Student *student.Student
+
+	// OldTelegramID is the released Telegram ID.
+	OldTelegramID student.TelegramID
+}
+
+// RelinkTelegramHandler handles the RelinkTelegramCommand.
+type RelinkTelegramHandler struct {
+	studentRepo   student.Repository
+	relinker      student.TelegramRelinker
+	cooldowns     notification.CooldownStore
+	studentCache  student.StudentCache
+	invalidations StudentInvalidations
+}
+
+// NewRelinkTelegramHandler creates a new RelinkTelegramHandler.
+// studentCache may be nil when Redis is disabled.
+func NewRelinkTelegramHandler(
+	studentRepo student.Repository,
+	relinker student.TelegramRelinker,
+	cooldowns notification.CooldownStore,
+	studentCache student.StudentCache,
+) *RelinkTelegramHandler {
+	return &RelinkTelegramHandler{
+		studentRepo:  studentRepo,
+		relinker:     relinker,
+		cooldowns:    cooldowns,
+		studentCache: studentCache,
+	}
+}
+
+// WithInvalidations announces relinked students to other bot instances.
+func (h *RelinkTelegramHandler) WithInvalidations(invalidations StudentInvalidations) *RelinkTelegramHandler {
+	h.invalidations = invalidations
+	return h
+}
+
+// Handle verifies the credentials and moves the profile.
+func (h *RelinkTelegramHandler) Handle(ctx context.Context, cmd RelinkTelegramCommand) (*RelinkTelegramResult, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+	newID := student.TelegramID(cmd.NewTelegramID)
+
+	exists, err := h.studentRepo.ExistsByTelegramID(ctx, newID)
+	if err != nil {
+		return nil, fmt.Errorf("relink_telegram: failed to check telegram id: %w", err)
+	}
+	if exists {
+		return nil, ErrRelinkAlreadyRegistered
+	}
+
+	stud, err := h.studentRepo.GetByEmail(ctx, strings.TrimSpace(cmd.Email))
+	if errors.Is(err, student.ErrStudentNotFound) {
+		return nil, ErrRelinkInvalidCredentials
+	}
+	if err != nil {
+		return nil, fmt.Errorf("relink_telegram: failed to load student: %w", err)
+	}
+	if stud.Status == student.StatusLeft || stud.PasswordHash == "" ||
+		bcrypt.CompareHashAndPassword([]byte(stud.PasswordHash), []byte(cmd.Password)) != nil {
+		return nil, ErrRelinkInvalidCredentials
+	}
+
+	acquired, err := h.cooldowns.Acquire(ctx, relinkCooldownKey(stud.ID), student.RelinkCooldown)
+	if err != nil {
+		return nil, fmt.Errorf("relink_telegram: failed to check cooldown: %w", err)
+	}
+	if !acquired {
+		return nil, ErrRelinkTooSoon
+	}
+
+	oldID := stud.TelegramID
+	entry := shared.NewAuditEntry(stud.ID, shared.AuditActionTelegramRelinked, stud.ID, map[string]interface{}{
+		"old_telegram_id": int64(oldID),
+		"new_telegram_id": int64(newID),
+	})
+	if err := h.relinker.RelinkTelegramID(ctx, stud.ID, oldID, newID, entry); err != nil {
+		return nil, fmt.Errorf("relink_telegram: %w", err)
+	}
+	stud.TelegramID = newID
+
+	h.invalidateCaches(ctx, stud.ID, oldID)
+
+	return &RelinkTelegramResult{Student: stud, OldTelegramID: oldID}, nil
+}
+
+// invalidateCaches drops the student from shared caches and tells every bot
+// instance to forget the old Telegram ID. Failures are ignored: entries
+// expire on their own and the relink is committed.
+func (h *RelinkTelegramHandler) invalidateCaches(ctx context.Context, studentID string, oldID student.TelegramID) {
+	if h.studentCache != nil {
+		_ = h.studentCache.Invalidate(ctx, studentID)
+	}
+	if h.invalidations != nil {
+		h.invalidations.StudentUpdated(ctx, studentID, int64(oldID))
+	}
+}
+
+// relinkCooldownKey returns the cooldown key of a student.
+func relinkCooldownKey(studentID string) string {
+	return "relink:" + studentID
+}
@@ -0,0 +1,194 @@
+package command
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// relinkStore is a student.Repository and student.TelegramRelinker over one
+// stored student.
+type relinkStore struct {
+	student.Repository
+	mu      sync.Mutex
+	stored  student.Student
+	audit   []shared.AuditEntry
+	readers *sync.WaitGroup // when set, GetByEmail waits for all readers
+}
+
+func (s *relinkStore) ExistsByTelegramID(_ context.Context, id student.TelegramID) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stored.TelegramID == id, nil
+}
+
+func (s *relinkStore) GetByEmail(_ context.Context, email string) (*student.Student, error) {
+	s.mu.Lock()
+	if email != s.stored.Email {
+		s.mu.Unlock()
+		return nil, student.ErrStudentNotFound
+	}
+	copied := s.stored
+	s.mu.Unlock()
+
+	if s.readers != nil {
+		s.readers.Done()
+		s.readers.Wait()
+	}
+	return &copied, nil
+}
+
+func (s *relinkStore) RelinkTelegramID(_ context.Context, studentID string, from, to student.TelegramID, audit shared.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stored.ID != studentID || s.stored.TelegramID != from {
+		return student.ErrRelinkConflict
+	}
+	s.stored.TelegramID = to
+	s.audit = append(s.audit, audit)
+	return nil
+}
+
+// relinkCooldowns is a concurrency-safe notification.CooldownStore; open
+// lets every attempt through.
+type relinkCooldowns struct {
+	mu    sync.Mutex
+	taken map[string]bool
+	open  bool
+}
+
+func (c *relinkCooldowns) Acquire(_ context.Context, key string, _ time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.open {
+		return true, nil
+	}
+	if c.taken[key] {
+		return false, nil
+	}
+	c.taken[key] = true
+	return true, nil
+}
+
+// relinkCache records invalidated students.
+type relinkCache struct {
+	student.StudentCache
+	invalidated []string
+}
+
+func (c *relinkCache) Invalidate(_ context.Context, studentID string) error {
+	c.invalidated = append(c.invalidated, studentID)
+	return nil
+}
+
+// relinkInvalidations records published student updates.
+type relinkInvalidations struct {
+	updated map[string]int64
+}
+
+func (i *relinkInvalidations) StudentUpdated(_ context.Context, studentID string, telegramID int64) {
+	i.updated[studentID] = telegramID
+}
+
+func (i *relinkInvalidations) StudentDeleted(context.Context, string, int64) {}
+
+func newRelinkStore(t *testing.T) *relinkStore {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+	return &relinkStore{stored: student.Student{
+		ID:           "s1",
+		TelegramID:   100,
+		Email:        "dana@alem.school",
+		PasswordHash: string(hash),
+		DisplayName:  "Dana",
+		Status:       student.StatusActive,
+	}}
+}
+
+func TestRelinkTelegram_SwapsAndInvalidates(t *testing.T) {
+	ctx := context.Background()
+	store := newRelinkStore(t)
+	cache := &relinkCache{}
+	invalidations := &relinkInvalidations{updated: map[string]int64{}}
+	h := NewRelinkTelegramHandler(store, store, &relinkCooldowns{taken: map[string]bool{}}, cache).
+		WithInvalidations(invalidations)
+
+	_, err := h.Handle(ctx, RelinkTelegramCommand{NewTelegramID: 200, Email: "dana@alem.school", Password: "wrong"})
+	assert.ErrorIs(t, err, ErrRelinkInvalidCredentials)
+	_, err = h.Handle(ctx, RelinkTelegramCommand{NewTelegramID: 200, Email: "nobody@alem.school", Password: "secret"})
+	assert.ErrorIs(t, err, ErrRelinkInvalidCredentials)
+	_, err = h.Handle(ctx, RelinkTelegramCommand{NewTelegramID: 100, Email: "dana@alem.school", Password: "secret"})
+	assert.ErrorIs(t, err, ErrRelinkAlreadyRegistered)
+	assert.Equal(t, student.TelegramID(100), store.stored.TelegramID)
+
+	result, err := h.Handle(ctx, RelinkTelegramCommand{NewTelegramID: 200, Email: " dana@alem.school ", Password: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, student.TelegramID(100), result.OldTelegramID)
+	assert.Equal(t, student.TelegramID(200), result.Student.TelegramID)
+	assert.Equal(t, student.TelegramID(200), store.stored.TelegramID)
+
+	// The old ID is released: nobody holds it any more
+	exists, err := store.ExistsByTelegramID(ctx, 100)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.Len(t, store.audit, 1)
+	assert.Equal(t, shared.AuditActionTelegramRelinked, store.audit[0].Action)
+	assert.Equal(t, "s1", store.audit[0].TargetID)
+	assert.Equal(t, int64(100), store.audit[0].Details["old_telegram_id"])
+	assert.Equal(t, int64(200), store.audit[0].Details["new_telegram_id"])
+
+	// Caches keyed by the old ID are dropped everywhere
+	assert.Equal(t, []string{"s1"}, cache.invalidated)
+	assert.Equal(t, map[string]int64{"s1": 100}, invalidations.updated)
+
+	// A second relink right away is rejected by the cooldown
+	_, err = h.Handle(ctx, RelinkTelegramCommand{NewTelegramID: 300, Email: "dana@alem.school", Password: "secret"})
+	assert.ErrorIs(t, err, ErrRelinkTooSoon)
+	assert.Equal(t, student.TelegramID(200), store.stored.TelegramID)
+}
+
+func TestRelinkTelegram_ConcurrentAttempts(t *testing.T) {
+	store := newRelinkStore(t)
+	// Both attempts load the student before either swaps, and the cooldown
+	// is not shared (e.g. two instances without Redis): the conditional
+	// swap still lets only one through.
+	store.readers = &sync.WaitGroup{}
+	store.readers.Add(2)
+	h := NewRelinkTelegramHandler(store, store, &relinkCooldowns{open: true}, nil)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, newID := range []int64{200, 300} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = h.Handle(context.Background(), RelinkTelegramCommand{
+				NewTelegramID: newID,
+				Email:         "dana@alem.school",
+				Password:      "secret",
+			})
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.ErrorIs(t, err, student.ErrRelinkConflict)
+	}
+	assert.Equal(t, 1, succeeded)
+	assert.Len(t, store.audit, 1)
+	assert.Contains(t, []student.TelegramID{200, 300}, store.stored.TelegramID)
+}
@@ -0,0 +1,198 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RENAME STUDENT COMMAND
+// Keeps DisplayName in step with the student's Telegram profile. Every
+// update carries the current first/last name and username; when they no
+// longer match the stored name, the row and every cache that shows the name
+// (leaderboard entries, display-name cache, card views) are updated here,
+// in one place, so no cache is left showing the old name.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// RenameStudentInterval is how often a student can be renamed at most.
+// Telegram names change far less often than updates arrive, and a student
+// flipping names back and forth shouldn't rewrite caches on every message.
+const RenameStudentInterval = 24 * time.Hour
+
+// RenameStudentCommand contains the Telegram profile seen in an update.
+type RenameStudentCommand struct {
+	// StudentID is the ID of the student who sent the update.
+	StudentID string
+
+	// KnownName is the display name the caller already has for the student
+	// (e.g. from the auth cache). Matching names end the command without
+	// touching the database.
+	KnownName string
+
+	// FirstName, LastName and Username come from the update's sender.
+	FirstName string
+	LastName  string
+	Username  string
+}
+
+// Validate validates the command.
+func (c RenameStudentCommand) Validate() error {
+	if c.StudentID == "" {
+		return errors.New("rename_student: student_id is required")
+	}
+	return nil
+}
+
+// RenameStudentResult contains the result of a rename.
+type RenameStudentResult struct {
+	// Renamed is false when the name is unchanged or the rename is throttled.
+	Renamed bool
+
+	// OldName is the name before the rename.
+	OldName string
+
+	// Student is the renamed student (nil when not renamed).
+	Student *student.Student
+}
+
+// LeaderboardNames rewrites the name in cached leaderboard entries.
+// Implemented by redis.LeaderboardCache.
+type LeaderboardNames interface {
+	RenameEntry(ctx context.Context, studentID, displayName string, cohort string) error
+}
+
+// DisplayNameView is an in-process read model that shows student names.
+// Implemented by projections.StudentCardView.
+type DisplayNameView interface {
+	UpdateDisplayName(studentID, displayName string)
+}
+
+// RenameStudentHandler handles the RenameStudentCommand.
+type RenameStudentHandler struct {
+	studentRepo   student.Repository
+	studentCache  student.StudentCache
+	leaderboard   LeaderboardNames
+	names         student.DisplayNameCache
+	views         []DisplayNameView
+	invalidations StudentInvalidations
+	now           func() time.Time
+
+	mu sync.Mutex
+	// attempts holds when each student was last renamed (or a rename was
+	// attempted), for RenameStudentInterval throttling.
+	attempts map[string]time.Time
+}
+
+// NewRenameStudentHandler creates a new RenameStudentHandler.
+// studentCache, leaderboard and names may be nil when Redis is disabled.
+func NewRenameStudentHandler(
+	studentRepo student.Repository,
+	studentCache student.StudentCache,
+	leaderboard LeaderboardNames,
+	names student.DisplayNameCache,
+) *RenameStudentHandler {
+	return &RenameStudentHandler{
+		studentRepo:  studentRepo,
+		studentCache: studentCache,
+		leaderboard:  leaderboard,
+		names:        names,
+		now:          time.Now,
+		attempts:     make(map[string]time.Time),
+	}
+}
+
+// WithViews updates the name in in-process read models on rename.
+func (h *RenameStudentHandler) WithViews(views ...DisplayNameView) *RenameStudentHandler {
+	h.views = append(h.views, views...)
+	return h
+}
+
+// WithInvalidations announces renamed students to other bot instances.
+func (h *RenameStudentHandler) WithInvalidations(invalidations StudentInvalidations) *RenameStudentHandler {
+	h.invalidations = invalidations
+	return h
+}
+
+// Handle renames the student when the Telegram profile name differs from
+// the known one.
+func (h *RenameStudentHandler) Handle(ctx context.Context, cmd RenameStudentCommand) (*RenameStudentResult, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	name := student.TelegramDisplayName(cmd.FirstName, cmd.LastName, cmd.Username)
+	if name == "" || name == cmd.KnownName || !h.allow(cmd.StudentID) {
+		return &RenameStudentResult{OldName: cmd.KnownName}, nil
+	}
+
+	stud, err := h.studentRepo.GetByID(ctx, cmd.StudentID)
+	if err != nil {
+		return nil, fmt.Errorf("rename_student: failed to get student: %w", err)
+	}
+
+	oldName := stud.DisplayName
+	changed, err := stud.Rename(name)
+	if err != nil {
+		return nil, fmt.Errorf("rename_student: %w", err)
+	}
+	if !changed {
+		return &RenameStudentResult{OldName: oldName}, nil
+	}
+
+	if err := h.studentRepo.Update(ctx, stud); err != nil {
+		return nil, fmt.Errorf("rename_student: failed to update student: %w", err)
+	}
+
+	h.refreshCaches(ctx, stud)
+
+	return &RenameStudentResult{Renamed: true, OldName: oldName, Student: stud}, nil
+}
+
+// allow reports whether the student may be renamed now and, if so, starts
+// a new throttling interval. Failed attempts count too, so a broken rename
+// isn't retried on every update.
+func (h *RenameStudentHandler) allow(studentID string) bool {
+	now := h.now()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if last, ok := h.attempts[studentID]; ok && now.Sub(last) < RenameStudentInterval {
+		return false
+	}
+	h.attempts[studentID] = now
+	return true
+}
+
+// refreshCaches puts the new name into every cache that shows it.
+// Cache errors are ignored: the row is already updated and the caches
+// expire on their own.
+func (h *RenameStudentHandler) refreshCaches(ctx context.Context, stud *student.Student) {
+	if h.leaderboard != nil {
+		_ = h.leaderboard.RenameEntry(ctx, stud.ID, stud.DisplayName, "")
+		if stud.Cohort != "" {
+			_ = h.leaderboard.RenameEntry(ctx, stud.ID, stud.DisplayName, string(stud.Cohort))
+		}
+	}
+
+	if h.names != nil {
+		_ = h.names.SetNames(ctx, map[string]string{stud.ID: stud.DisplayName})
+	}
+
+	if h.studentCache != nil {
+		_ = h.studentCache.Invalidate(ctx, stud.ID)
+	}
+
+	for _, view := range h.views {
+		view.UpdateDisplayName(stud.ID, stud.DisplayName)
+	}
+
+	if h.invalidations != nil {
+		h.invalidations.StudentUpdated(ctx, stud.ID, int64(stud.TelegramID))
+	}
+}
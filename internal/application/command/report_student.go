@@ -0,0 +1,328 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// REPORT STUDENT COMMAND
+// A student reports someone the bot introduced them to. Only introductions
+// the bot actually made can be reported: a connection between the two, or
+// a help request the reported student opened. The reason is optional and
+// arrives later as a follow-up message.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ReportStudentCommand contains the data of a new report.
+type ReportStudentCommand struct {
+	// ReporterID is the ID of the reporting student.
+	ReporterID string
+
+	// Context is where the introduction happened.
+	Context social.ReportContext
+
+	// ReportedID is the reported student (ReportContextConnection).
+	ReportedID string
+
+	// HelpRequestID is the help request (ReportContextHelpRequest); the
+	// reported student is its requester.
+	HelpRequestID string
+}
+
+// ReportStudentResult contains the created report.
+type ReportStudentResult struct {
+	Report   *social.Report
+	Reporter *student.Student
+	Reported *student.Student
+
+	// TaskID is the task of the introduction, if known.
+	TaskID string
+}
+
+// ReportStudentHandler handles the ReportStudentCommand.
+type ReportStudentHandler struct {
+	reports     social.ReportRepository
+	studentRepo student.Repository
+	socialRepo  social.Repository
+	now         func() time.Time
+}
+
+// NewReportStudentHandler creates a new ReportStudentHandler.
+func NewReportStudentHandler(
+	reports social.ReportRepository,
+	studentRepo student.Repository,
+	socialRepo social.Repository,
+) *ReportStudentHandler {
+	return &ReportStudentHandler{
+		reports:     reports,
+		studentRepo: studentRepo,
+		socialRepo:  socialRepo,
+		now:         time.Now,
+	}
+}
+
+// Handle creates the report.
+func (h *ReportStudentHandler) Handle(ctx context.Context, cmd ReportStudentCommand) (*ReportStudentResult, error) {
+	reporter, err := h.studentRepo.GetByID(ctx, cmd.ReporterID)
+	if err != nil {
+		return nil, fmt.Errorf("report_student: reporter not found: %w", err)
+	}
+
+	var reportedID, contextID, taskID string
+	switch cmd.Context {
+	case social.ReportContextConnection:
+		if cmd.ReportedID == cmd.ReporterID {
+			return nil, fmt.Errorf("report_student: %w", social.ErrReportSelf)
+		}
+		conn, err := h.socialRepo.Connections().GetByStudents(ctx, social.StudentID(cmd.ReporterID), social.StudentID(cmd.ReportedID))
+		if err != nil || conn == nil {
+			return nil, fmt.Errorf("report_student: no introduction between the students: %w", social.ErrReportInvalid)
+		}
+		reportedID, contextID, taskID = cmd.ReportedID, conn.ID, string(conn.Context.TaskID)
+	case social.ReportContextHelpRequest:
+		req, err := h.socialRepo.HelpRequests().GetByID(ctx, cmd.HelpRequestID)
+		if err != nil {
+			return nil, fmt.Errorf("report_student: help request not found: %w", social.ErrReportInvalid)
+		}
+		reportedID, contextID, taskID = string(req.RequesterID), req.ID, string(req.TaskID)
+	default:
+		return nil, fmt.Errorf("report_student: %w", social.ErrReportInvalid)
+	}
+
+	reported, err := h.studentRepo.GetByID(ctx, reportedID)
+	if err != nil {
+		return nil, fmt.Errorf("report_student: reported student not found: %w", err)
+	}
+
+	report, err := social.NewReport(social.StudentID(reporter.ID), social.StudentID(reported.ID), cmd.Context, contextID, h.now())
+	if err != nil {
+		return nil, fmt.Errorf("report_student: %w", err)
+	}
+	if err := h.reports.Create(ctx, report); err != nil {
+		return nil, fmt.Errorf("report_student: %w", err)
+	}
+
+	return &ReportStudentResult{
+		Report:   report,
+		Reporter: reporter,
+		Reported: reported,
+		TaskID:   taskID,
+	}, nil
+}
+
+// AttachReason stores a follow-up message as the reason of the reporter's
+// latest report. Returns social.ErrReportNotFound when no report is
+// waiting for a reason.
+func (h *ReportStudentHandler) AttachReason(ctx context.Context, reporterID, reason string) (*social.Report, error) {
+	reason = social.NormalizeReportReason(reason)
+	if reason == "" {
+		return nil, social.ErrReportNotFound
+	}
+	return h.reports.AttachReason(ctx, social.StudentID(reporterID), reason, h.now().Add(-social.ReportReasonWindow))
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// REVIEW REPORT COMMAND
+// Admin decision on a report. Once a student collects the threshold of
+// upheld reports, helper matching skips them until an admin restores it.
+// Every decision is written to the audit log.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ReportDecision is the admin's decision on a report.
+type ReportDecision string
+
+const (
+	// ReportUphold confirms the report.
+	ReportUphold ReportDecision = "uphold"
+
+	// ReportDismiss rejects the report; it is never counted.
+	ReportDismiss ReportDecision = "dismiss"
+)
+
+// ErrInvalidReportDecision is returned for an unknown decision.
+var ErrInvalidReportDecision = errors.New("invalid report decision")
+
+// ReviewReportCommand contains an admin decision.
+type ReviewReportCommand struct {
+	// ReportID is the ID of the report.
+	ReportID int64
+
+	// Decision is uphold or dismiss.
+	Decision ReportDecision
+
+	// AdminID is the Telegram ID of the admin.
+	AdminID int64
+}
+
+// ReviewReportResult contains the outcome of a review.
+type ReviewReportResult struct {
+	// Report is the report after the decision (or as it was, when it had
+	// already been resolved).
+	Report *social.Report
+
+	// Reporter and Reported are nil if they no longer exist.
+	Reporter *student.Student
+	Reported *student.Student
+
+	// UpheldCount is the number of upheld reports on the reported student.
+	UpheldCount int
+
+	// MatchingDisabled is true when this decision excluded the reported
+	// student from helper matching.
+	MatchingDisabled bool
+}
+
+// ReviewReportHandler handles the ReviewReportCommand.
+type ReviewReportHandler struct {
+	reports      social.ReportRepository
+	studentRepo  student.Repository
+	helpMatching student.HelpMatchingRepository
+	auditLog     shared.AuditLog
+	threshold    int
+	now          func() time.Time
+}
+
+// NewReviewReportHandler creates a new ReviewReportHandler. threshold is
+// the number of upheld reports that disables helper matching (<= 0 uses
+// social.DefaultReportThreshold).
+func NewReviewReportHandler(
+	reports social.ReportRepository,
+	studentRepo student.Repository,
+	helpMatching student.HelpMatchingRepository,
+	auditLog shared.AuditLog,
+	threshold int,
+) *ReviewReportHandler {
+	if threshold <= 0 {
+		threshold = social.DefaultReportThreshold
+	}
+
+	return &ReviewReportHandler{
+		reports:      reports,
+		studentRepo:  studentRepo,
+		helpMatching: helpMatching,
+		auditLog:     auditLog,
+		threshold:    threshold,
+		now:          time.Now,
+	}
+}
+
+// Threshold returns the number of upheld reports that disables matching.
+func (h *ReviewReportHandler) Threshold() int {
+	return h.threshold
+}
+
+// Handle applies the decision. A report that was already resolved returns
+// social.ErrReportResolved together with the result.
+func (h *ReviewReportHandler) Handle(ctx context.Context, cmd ReviewReportCommand) (*ReviewReportResult, error) {
+	var status social.ReportStatus
+	switch cmd.Decision {
+	case ReportUphold:
+		status = social.ReportUpheld
+	case ReportDismiss:
+		status = social.ReportDismissed
+	default:
+		return nil, fmt.Errorf("review_report: %w", ErrInvalidReportDecision)
+	}
+
+	report, err := h.reports.GetByID(ctx, cmd.ReportID)
+	if err != nil {
+		return nil, fmt.Errorf("review_report: %w", err)
+	}
+
+	result := &ReviewReportResult{Report: report}
+	h.loadStudents(ctx, result)
+
+	if !report.IsPending() {
+		return result, social.ErrReportResolved
+	}
+
+	resolved, err := h.reports.Resolve(ctx, cmd.ReportID, status, cmd.AdminID, h.now())
+	if errors.Is(err, social.ErrReportResolved) {
+		if current, getErr := h.reports.GetByID(ctx, cmd.ReportID); getErr == nil {
+			result.Report = current
+		}
+		return result, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("review_report: %w", err)
+	}
+	result.Report = resolved
+
+	all, err := h.reports.ListByReported(ctx, resolved.ReportedID)
+	if err != nil {
+		return nil, fmt.Errorf("review_report: %w", err)
+	}
+	result.UpheldCount = social.CountUpheld(all)
+
+	if status == social.ReportUpheld && social.ShouldDisableHelpMatching(all, h.threshold) &&
+		result.Reported != nil && !result.Reported.HelpMatchingDisabled {
+		if err := h.helpMatching.SetHelpMatchingDisabled(ctx, result.Reported.ID, true); err != nil {
+			return nil, fmt.Errorf("review_report: failed to disable help matching: %w", err)
+		}
+		result.Reported.HelpMatchingDisabled = true
+		result.MatchingDisabled = true
+	}
+
+	entry := shared.NewAuditEntry(strconv.FormatInt(cmd.AdminID, 10), shared.AuditActionReportReviewed, string(resolved.ReportedID), map[string]interface{}{
+		"report_id":         resolved.ID,
+		"reporter_id":       string(resolved.ReporterID),
+		"decision":          string(cmd.Decision),
+		"upheld_count":      result.UpheldCount,
+		"matching_disabled": result.MatchingDisabled,
+	})
+	if err := h.auditLog.Record(ctx, entry); err != nil {
+		return nil, fmt.Errorf("review_report: failed to write audit entry: %w", err)
+	}
+
+	return result, nil
+}
+
+// RestoreHelpMatching brings the reported student of a report back into
+// helper matching after a curator review. Upheld reports stay counted,
+// so the next upheld report disables matching again.
+func (h *ReviewReportHandler) RestoreHelpMatching(ctx context.Context, reportID, adminID int64) (*ReviewReportResult, error) {
+	report, err := h.reports.GetByID(ctx, reportID)
+	if err != nil {
+		return nil, fmt.Errorf("restore_help_matching: %w", err)
+	}
+
+	result := &ReviewReportResult{Report: report}
+	h.loadStudents(ctx, result)
+	if result.Reported == nil {
+		return nil, fmt.Errorf("restore_help_matching: %w", student.ErrStudentNotFound)
+	}
+	if !result.Reported.HelpMatchingDisabled {
+		return result, nil
+	}
+
+	if err := h.helpMatching.SetHelpMatchingDisabled(ctx, result.Reported.ID, false); err != nil {
+		return nil, fmt.Errorf("restore_help_matching: %w", err)
+	}
+	result.Reported.HelpMatchingDisabled = false
+
+	entry := shared.NewAuditEntry(strconv.FormatInt(adminID, 10), shared.AuditActionHelpMatchingRestored, result.Reported.ID, map[string]interface{}{
+		"report_id": report.ID,
+	})
+	if err := h.auditLog.Record(ctx, entry); err != nil {
+		return nil, fmt.Errorf("restore_help_matching: failed to write audit entry: %w", err)
+	}
+
+	return result, nil
+}
+
+// loadStudents fills the reporter and the reported student of the result.
+func (h *ReviewReportHandler) loadStudents(ctx context.Context, result *ReviewReportResult) {
+	if s, err := h.studentRepo.GetByID(ctx, string(result.Report.ReporterID)); err == nil {
+		result.Reporter = s
+	}
+	if s, err := h.studentRepo.GetByID(ctx, string(result.Report.ReportedID)); err == nil {
+		result.Reported = s
+	}
+}
@@ -5,9 +5,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/alem-hub/alem-community-hub/internal/domain/activity"
+	"github.com/alem-hub/alem-community-hub/internal/domain/matchscore"
 	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
 	"github.com/alem-hub/alem-community-hub/internal/domain/social"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
@@ -46,6 +51,10 @@ type RequestHelpCommand struct {
 	// NotifyHelpers controls whether to notify matched helpers.
 	NotifyHelpers bool
 
+	// CoRequesterIDs are teammates invited into the request (optional, see
+	// social.MaxHelpTeamSize). They join once they accept the invitation.
+	CoRequesterIDs []string
+
 	// CorrelationID for tracing.
 	CorrelationID string
 }
@@ -90,6 +99,22 @@ type RequestHelpResult struct {
 	// ExpiresAt is when the request will expire.
 	ExpiresAt time.Time
 
+	// ClusterID is the cluster of near-identical requests the request is in
+	// (see social.HelpClusterWindow).
+	ClusterID string
+
+	// ClusterPeers is how many other open requests from the cohort are in
+	// the cluster, i.e. how many others are stuck on the same task.
+	ClusterPeers int
+
+	// Invited is the teammates invited into the request.
+	Invited []string
+
+	// Deduplicated is set when the requester already had an open request
+	// for the task: the result describes that request and nothing new was
+	// created or sent.
+	Deduplicated bool
+
 	// Events contains domain events generated.
 	Events []shared.Event
 
@@ -126,8 +151,15 @@ type MatchedHelperInfo struct {
 	// MatchScore is the matching score (0-100).
 	MatchScore int
 
+	// MatchReasons explains the score, strongest factor first.
+	MatchReasons []string
+
 	// CompletedTaskAt is when the helper completed the task.
 	CompletedTaskAt *time.Time
+
+	// CompletedTask indicates the helper has solved the task, even when the
+	// completion time is unknown.
+	CompletedTask bool
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -163,12 +195,14 @@ type RequestHelpHandler struct {
 	// Configuration
 	requestExpiration time.Duration
 	maxOpenRequests   int
+	clusterWindow     time.Duration
 }
 
 // RequestHelpHandlerConfig contains configuration for the handler.
 type RequestHelpHandlerConfig struct {
 	RequestExpiration time.Duration // How long before a request expires
-	MaxOpenRequests   int           // Max open requests per student
+	MaxOpenRequests   int           // Max concurrent open requests per student (0 = default)
+	ClusterWindow     time.Duration // How far back duplicates are looked for (0 = no clustering)
 }
 
 // DefaultRequestHelpHandlerConfig returns default configuration.
@@ -176,6 +210,7 @@ func DefaultRequestHelpHandlerConfig() RequestHelpHandlerConfig {
 	return RequestHelpHandlerConfig{
 		RequestExpiration: 24 * time.Hour,
 		MaxOpenRequests:   3,
+		ClusterWindow:     social.HelpClusterWindow,
 	}
 }
 
@@ -193,6 +228,9 @@ func NewRequestHelpHandler(
 	if config.RequestExpiration == 0 {
 		config = DefaultRequestHelpHandlerConfig()
 	}
+	if config.MaxOpenRequests <= 0 {
+		config.MaxOpenRequests = DefaultRequestHelpHandlerConfig().MaxOpenRequests
+	}
 
 	return &RequestHelpHandler{
 		studentRepo:       studentRepo,
@@ -204,6 +242,7 @@ func NewRequestHelpHandler(
 		eventPublisher:    eventPublisher,
 		requestExpiration: config.RequestExpiration,
 		maxOpenRequests:   config.MaxOpenRequests,
+		clusterWindow:     config.ClusterWindow,
 	}
 }
 
@@ -225,13 +264,25 @@ func (h *RequestHelpHandler) Handle(ctx context.Context, cmd RequestHelpCommand)
 		return nil, errors.New("request_help: student is not enrolled")
 	}
 
-	// Check for open requests limit
+	// Asking again about the same task returns the open request, so a
+	// double tap does not ping the helpers twice
 	openRequests, err := h.socialRepo.HelpRequests().GetOpenByRequesterID(
 		ctx,
 		social.StudentID(cmd.RequesterID),
 	)
-	if err == nil && len(openRequests) >= h.maxOpenRequests {
-		return nil, fmt.Errorf("request_help: max open requests limit reached (%d)", h.maxOpenRequests)
+	if err == nil {
+		if existing := openRequestForTask(openRequests, cmd.TaskID); existing != nil {
+			return existingRequestResult(existing), nil
+		}
+		if len(openRequests) >= h.maxOpenRequests {
+			return nil, fmt.Errorf("request_help: %w", &social.HelpRequestLimitError{Limit: h.maxOpenRequests})
+		}
+	}
+
+	// A teammate of an open team request for the task already has a thread
+	// of helper pings; a second one would only spam the same helpers
+	if err := h.checkTeamMembership(ctx, cmd); err != nil {
+		return nil, fmt.Errorf("request_help: %w", err)
 	}
 
 	// Initialize result
@@ -242,14 +293,27 @@ func (h *RequestHelpHandler) Handle(ctx context.Context, cmd RequestHelpCommand)
 		Events:    make([]shared.Event, 0),
 	}
 
+	// Look for the same request from the cohort before creating this one,
+	// so the request is stored with its cluster
+	duplicates := h.findDuplicates(ctx, cmd, requester, now)
+
 	// Create help request
-	request, err := h.createHelpRequest(ctx, cmd, requester, now, result)
+	request, err := h.createHelpRequest(ctx, cmd, requester, now, result, duplicates)
+	if errors.Is(err, social.ErrHelpRequestDuplicate) {
+		// A concurrent request for the task won the race
+		return h.winningRequest(ctx, cmd)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("request_help: failed to create request: %w", err)
 	}
 
 	result.RequestID = request.ID
 	result.Status = request.Status
+	result.ClusterID = request.ClusterID
+	result.ClusterPeers = h.joinDuplicates(ctx, request, duplicates)
+	for _, m := range request.Members {
+		result.Invited = append(result.Invited, string(m.StudentID))
+	}
 
 	// Find potential helpers
 	helpers, err := h.findAndMatchHelpers(ctx, cmd, request)
@@ -262,13 +326,15 @@ func (h *RequestHelpHandler) Handle(ctx context.Context, cmd RequestHelpCommand)
 
 	// Update request with matched helpers
 	if len(helpers) > 0 {
-		request.Status = social.HelpRequestStatusMatched
 		for _, helper := range helpers {
-			request.MatchedHelpers = append(request.MatchedHelpers, social.MatchedHelper{
-				StudentID:  social.StudentID(helper.StudentID),
-				MatchScore: helper.MatchScore,
-				IsOnline:   helper.IsOnline,
-				LastSeenAt: helper.LastSeenAt,
+			_ = request.AddMatchedHelper(social.MatchedHelper{
+				StudentID:    social.StudentID(helper.StudentID),
+				DisplayName:  helper.DisplayName,
+				HelpRating:   social.Rating(helper.HelpRating),
+				MatchScore:   helper.MatchScore,
+				MatchReasons: helper.MatchReasons,
+				IsOnline:     helper.IsOnline,
+				LastSeenAt:   helper.LastSeenAt,
 			})
 		}
 		_ = h.socialRepo.HelpRequests().Update(ctx, request)
@@ -280,7 +346,8 @@ func (h *RequestHelpHandler) Handle(ctx context.Context, cmd RequestHelpCommand)
 	}
 
 	// Emit event
-	event := shared.NewHelpRequestedEvent(cmd.RequesterID, cmd.TaskID, cmd.Message)
+	event := shared.NewHelpRequestedEvent(cmd.RequesterID, cmd.TaskID, request.Description)
+	event.RequestID = request.ID
 	if cmd.CorrelationID != "" {
 		event.BaseEvent = event.BaseEvent.WithCorrelationID(cmd.CorrelationID)
 	}
@@ -294,6 +361,61 @@ func (h *RequestHelpHandler) Handle(ctx context.Context, cmd RequestHelpCommand)
 	return result, nil
 }
 
+// winningRequest returns the open request for the task stored by a
+// concurrent call.
+func (h *RequestHelpHandler) winningRequest(ctx context.Context, cmd RequestHelpCommand) (*RequestHelpResult, error) {
+	openRequests, err := h.socialRepo.HelpRequests().GetOpenByRequesterID(ctx, social.StudentID(cmd.RequesterID))
+	if err != nil {
+		return nil, fmt.Errorf("request_help: failed to load open requests: %w", err)
+	}
+	existing := openRequestForTask(openRequests, cmd.TaskID)
+	if existing == nil {
+		// Closed right after winning; asking again creates a new one
+		return nil, fmt.Errorf("request_help: %w", social.ErrHelpRequestDuplicate)
+	}
+	return existingRequestResult(existing), nil
+}
+
+// openRequestForTask returns the open or matched request for taskID.
+func openRequestForTask(requests []*social.HelpRequest, taskID string) *social.HelpRequest {
+	for _, req := range requests {
+		if req.TaskID == social.TaskID(taskID) &&
+			(req.Status == social.HelpRequestStatusOpen || req.Status == social.HelpRequestStatusMatched) {
+			return req
+		}
+	}
+	return nil
+}
+
+// existingRequestResult describes an already open request as a deduplicated result.
+func existingRequestResult(request *social.HelpRequest) *RequestHelpResult {
+	result := &RequestHelpResult{
+		RequestID:    request.ID,
+		Status:       request.Status,
+		ExpiresAt:    request.ExpiresAt,
+		ClusterID:    request.ClusterID,
+		Deduplicated: true,
+		Events:       []shared.Event{},
+		CreatedAt:    request.CreatedAt,
+	}
+	for _, helper := range request.MatchedHelpers {
+		result.MatchedHelpers = append(result.MatchedHelpers, MatchedHelperInfo{
+			StudentID:    string(helper.StudentID),
+			DisplayName:  helper.DisplayName,
+			IsOnline:     helper.IsOnline,
+			LastSeenAt:   helper.LastSeenAt,
+			HelpRating:   float64(helper.HelpRating),
+			MatchScore:   helper.MatchScore,
+			MatchReasons: helper.MatchReasons,
+		})
+	}
+	result.TotalHelpersFound = len(result.MatchedHelpers)
+	for _, m := range request.Members {
+		result.Invited = append(result.Invited, string(m.StudentID))
+	}
+	return result
+}
+
 // createHelpRequest creates a new help request entity.
 func (h *RequestHelpHandler) createHelpRequest(
 	ctx context.Context,
@@ -301,8 +423,9 @@ func (h *RequestHelpHandler) createHelpRequest(
 	requester *student.Student,
 	now time.Time,
 	result *RequestHelpResult,
+	duplicates []*social.HelpRequest,
 ) (*social.HelpRequest, error) {
-	requestID := generateHelpRequestID(cmd.RequesterID, cmd.TaskID)
+	requestID := generateHelpRequestID()
 
 	params := social.NewHelpRequestParams{
 		ID:          requestID,
@@ -322,14 +445,104 @@ func (h *RequestHelpHandler) createHelpRequest(
 	// Set expiration
 	request.ExpiresAt = result.ExpiresAt
 
+	for _, id := range cmd.CoRequesterIDs {
+		if err := request.InviteCoRequester(social.StudentID(id), now); err != nil {
+			return nil, fmt.Errorf("failed to invite %s: %w", id, err)
+		}
+	}
+
+	// Join the oldest duplicate's cluster or open a new one
+	if h.clusterWindow > 0 {
+		clusterID := social.PickCluster(duplicates)
+		if clusterID == "" {
+			clusterID = requestID
+		}
+		request.JoinCluster(clusterID)
+	}
+
 	// Save to repository
 	if err := h.socialRepo.HelpRequests().Create(ctx, request); err != nil {
 		return nil, fmt.Errorf("failed to save help request: %w", err)
 	}
+	if len(request.Members) > 0 {
+		if err := h.socialRepo.HelpRequests().SaveMembers(ctx, request); err != nil {
+			return nil, fmt.Errorf("failed to save help request members: %w", err)
+		}
+	}
 
 	return request, nil
 }
 
+// checkTeamMembership rejects a request from a student who is already a
+// teammate of an open team request for the same task. The check is best
+// effort: on errors the request goes through.
+func (h *RequestHelpHandler) checkTeamMembership(ctx context.Context, cmd RequestHelpCommand) error {
+	memberships, err := h.socialRepo.HelpRequests().GetOpenMemberships(ctx, social.StudentID(cmd.RequesterID))
+	if err != nil {
+		return nil
+	}
+	for _, req := range memberships {
+		if req.TaskID == social.TaskID(cmd.TaskID) {
+			return fmt.Errorf("already in team request %s for the task: %w", req.ID, social.ErrHelpRequestAlreadyMember)
+		}
+	}
+	return nil
+}
+
+// findDuplicates returns open requests for the same task from the
+// requester's cohort created within the cluster window. Clustering is best
+// effort: on errors the request simply opens its own cluster.
+func (h *RequestHelpHandler) findDuplicates(
+	ctx context.Context,
+	cmd RequestHelpCommand,
+	requester *student.Student,
+	now time.Time,
+) []*social.HelpRequest {
+	if h.clusterWindow <= 0 {
+		return nil
+	}
+
+	duplicates, err := h.socialRepo.HelpRequests().FindDuplicates(
+		ctx,
+		social.TaskID(cmd.TaskID),
+		string(requester.Cohort),
+		social.StudentID(cmd.RequesterID),
+		now.Add(-h.clusterWindow),
+	)
+	if err != nil {
+		return nil
+	}
+	return duplicates
+}
+
+// joinDuplicates pulls duplicates without a cluster into the request's
+// cluster and returns how many open requests share the cluster.
+func (h *RequestHelpHandler) joinDuplicates(
+	ctx context.Context,
+	request *social.HelpRequest,
+	duplicates []*social.HelpRequest,
+) int {
+	if request.ClusterID == "" {
+		return 0
+	}
+
+	var legacy []string
+	peers := 0
+	for _, d := range duplicates {
+		switch d.ClusterID {
+		case request.ClusterID:
+			peers++
+		case "":
+			legacy = append(legacy, d.ID)
+		}
+	}
+
+	if len(legacy) > 0 && h.socialRepo.HelpRequests().SetCluster(ctx, request.ClusterID, legacy) == nil {
+		peers += len(legacy)
+	}
+	return peers
+}
+
 // findAndMatchHelpers finds and ranks potential helpers.
 func (h *RequestHelpHandler) findAndMatchHelpers(
 	ctx context.Context,
@@ -341,16 +554,50 @@ func (h *RequestHelpHandler) findAndMatchHelpers(
 		maxHelpers = 5
 	}
 
-	// Use matching service if available
+	var helpers []MatchedHelperInfo
+	var err error
+
+	// Use matching service if available, fall back to manual matching
 	if h.matchingService != nil {
-		suggestions, err := h.matchingService.FindHelpers(ctx, cmd.RequesterID, cmd.TaskID, maxHelpers*2)
+		var suggestions []activity.HelperSuggestion
+		suggestions, err = h.matchingService.FindHelpers(ctx, cmd.RequesterID, cmd.TaskID, maxHelpers*2)
 		if err == nil {
-			return h.convertSuggestionsToHelpers(ctx, cmd.RequesterID, suggestions, maxHelpers)
+			helpers, err = h.convertSuggestionsToHelpers(ctx, cmd.RequesterID, suggestions, maxHelpers)
+		}
+	}
+	if helpers == nil {
+		helpers, err = h.manualHelperMatching(ctx, cmd, maxHelpers)
+	}
+
+	// Teammates can't help their own request
+	kept := helpers[:0]
+	for _, helper := range helpers {
+		if !request.IsMember(social.StudentID(helper.StudentID)) {
+			kept = append(kept, helper)
 		}
 	}
+	helpers = kept
+
+	if len(request.Members) > 0 {
+		preferTeamHelpers(helpers)
+	}
+	return helpers, err
+}
 
-	// Fallback: Manual matching
-	return h.manualHelperMatching(ctx, cmd, maxHelpers)
+// preferTeamHelpers moves strong helpers (see social.IsStrongTeamHelper) to
+// the front, keeping the match order otherwise. A stuck team needs someone
+// who can explain the whole task, not just a nearby peer.
+func preferTeamHelpers(helpers []MatchedHelperInfo) {
+	for i := range helpers {
+		h := &helpers[i]
+		if social.IsStrongTeamHelper(h.CompletedTask, h.HelpRating, h.TimesHelped) {
+			h.MatchReasons = append([]string{"strong helper for team requests"}, h.MatchReasons...)
+		}
+	}
+	sort.SliceStable(helpers, func(i, j int) bool {
+		return social.IsStrongTeamHelper(helpers[i].CompletedTask, helpers[i].HelpRating, helpers[i].TimesHelped) &&
+			!social.IsStrongTeamHelper(helpers[j].CompletedTask, helpers[j].HelpRating, helpers[j].TimesHelped)
+	})
 }
 
 // convertSuggestionsToHelpers converts activity suggestions to helper info.
@@ -379,7 +626,7 @@ func (h *RequestHelpHandler) convertSuggestionsToHelpers(
 		}
 
 		// Skip students who don't want to help
-		if !stud.CanHelp() {
+		if !stud.IsDiscoverableHelper() {
 			continue
 		}
 
@@ -392,11 +639,12 @@ func (h *RequestHelpHandler) convertSuggestionsToHelpers(
 			HelpRating:      suggestion.HelperRating,
 			TimesHelped:     suggestion.TimesHelpedOther,
 			HasHelpedBefore: suggestion.HasPriorContact,
-			MatchScore:      calculateMatchScore(suggestion),
 		}
+		helper.MatchScore, helper.MatchReasons = calculateMatchScore(suggestion)
 
 		if !suggestion.CompletedTaskAt.IsZero() {
 			helper.CompletedTaskAt = &suggestion.CompletedTaskAt
+			helper.CompletedTask = true
 		}
 
 		helpers = append(helpers, helper)
@@ -420,7 +668,7 @@ func (h *RequestHelpHandler) manualHelperMatching(
 		}
 
 		stud, err := h.studentRepo.GetByID(ctx, preferredID)
-		if err != nil || !stud.CanHelp() {
+		if err != nil || !stud.IsDiscoverableHelper() {
 			continue
 		}
 
@@ -437,14 +685,15 @@ func (h *RequestHelpHandler) manualHelperMatching(
 		isOnline, _ := h.onlineTracker.IsOnline(ctx, activity.StudentID(preferredID))
 
 		helpers = append(helpers, MatchedHelperInfo{
-			StudentID:   preferredID,
-			DisplayName: stud.DisplayName,
-			TelegramID:  int64(stud.TelegramID),
-			IsOnline:    isOnline,
-			LastSeenAt:  stud.LastSeenAt,
-			HelpRating:  stud.HelpRating,
-			TimesHelped: stud.HelpCount,
-			MatchScore:  90, // High score for preferred helpers
+			StudentID:     preferredID,
+			DisplayName:   stud.DisplayName,
+			TelegramID:    int64(stud.TelegramID),
+			IsOnline:      isOnline,
+			LastSeenAt:    stud.LastSeenAt,
+			HelpRating:    stud.HelpRating,
+			TimesHelped:   stud.HelpCount,
+			MatchScore:    90, // High score for preferred helpers
+			CompletedTask: true,
 		})
 	}
 
@@ -477,32 +726,31 @@ func (h *RequestHelpHandler) manualHelperMatching(
 				}
 
 				stud, err := h.studentRepo.GetByID(ctx, string(studentID))
-				if err != nil || !stud.CanHelp() {
+				if err != nil || !stud.IsDiscoverableHelper() {
 					continue
 				}
 
 				isOnline, _ := h.onlineTracker.IsOnline(ctx, studentID)
 
-				score := 50
-				if isOnline {
-					score += 20
-				}
-				if stud.HelpRating >= 4.0 {
-					score += 15
-				}
-				if stud.HelpCount > 5 {
-					score += 10
-				}
+				score, reasons := calculateMatchScore(activity.HelperSuggestion{
+					StudentID:        studentID,
+					IsOnline:         isOnline,
+					LastSeenAt:       stud.LastSeenAt,
+					HelperRating:     stud.HelpRating,
+					TimesHelpedOther: stud.HelpCount,
+				})
 
 				helpers = append(helpers, MatchedHelperInfo{
-					StudentID:   string(studentID),
-					DisplayName: stud.DisplayName,
-					TelegramID:  int64(stud.TelegramID),
-					IsOnline:    isOnline,
-					LastSeenAt:  stud.LastSeenAt,
-					HelpRating:  stud.HelpRating,
-					TimesHelped: stud.HelpCount,
-					MatchScore:  score,
+					StudentID:     string(studentID),
+					DisplayName:   stud.DisplayName,
+					TelegramID:    int64(stud.TelegramID),
+					IsOnline:      isOnline,
+					LastSeenAt:    stud.LastSeenAt,
+					HelpRating:    stud.HelpRating,
+					TimesHelped:   stud.HelpCount,
+					MatchScore:    score,
+					MatchReasons:  reasons,
+					CompletedTask: true,
 				})
 			}
 		}
@@ -511,7 +759,9 @@ func (h *RequestHelpHandler) manualHelperMatching(
 	return helpers, nil
 }
 
-// notifyHelpers notifies matched helpers about the request.
+// notifyHelpers notifies matched helpers about the request. A helper is
+// notified about a cluster once: helpers already pinged about an earlier
+// request of the cluster are skipped.
 func (h *RequestHelpHandler) notifyHelpers(
 	ctx context.Context,
 	helpers []MatchedHelperInfo,
@@ -525,6 +775,10 @@ func (h *RequestHelpHandler) notifyHelpers(
 			continue
 		}
 
+		if !h.claimClusterNotification(ctx, request, helper.StudentID) {
+			continue
+		}
+
 		if err := h.helperNotifier.NotifyHelpRequest(ctx, helper.StudentID, request); err != nil {
 			continue
 		}
@@ -535,52 +789,41 @@ func (h *RequestHelpHandler) notifyHelpers(
 	return notified
 }
 
-// calculateMatchScore calculates a match score for a helper.
-func calculateMatchScore(suggestion activity.HelperSuggestion) int {
-	score := 50 // Base score
-
-	// Online bonus
-	if suggestion.IsOnline {
-		score += 25
-	} else if time.Since(suggestion.LastSeenAt) < 30*time.Minute {
-		score += 15
-	} else if time.Since(suggestion.LastSeenAt) < time.Hour {
-		score += 10
+// claimClusterNotification reports whether the helper should be notified
+// about the request's cluster. The claim is taken before sending, so a failed
+// send isn't retried for the cluster; when the claim itself fails the helper
+// is notified rather than missed.
+func (h *RequestHelpHandler) claimClusterNotification(ctx context.Context, request *social.HelpRequest, helperID string) bool {
+	if request.ClusterID == "" {
+		return true
 	}
 
-	// Rating bonus
-	if suggestion.HelperRating >= 4.5 {
-		score += 15
-	} else if suggestion.HelperRating >= 4.0 {
-		score += 10
-	} else if suggestion.HelperRating >= 3.5 {
-		score += 5
-	}
-
-	// Experience bonus
-	if suggestion.TimesHelpedOther >= 20 {
-		score += 10
-	} else if suggestion.TimesHelpedOther >= 10 {
-		score += 7
-	} else if suggestion.TimesHelpedOther >= 5 {
-		score += 5
-	}
-
-	// Prior contact bonus
-	if suggestion.HasPriorContact {
-		score += 5
-	}
+	claimed, err := h.socialRepo.HelpRequests().ClaimHelperNotification(
+		ctx, request.ClusterID, social.StudentID(helperID), time.Now().UTC(),
+	)
+	return err != nil || claimed
+}
 
-	// Cap at 100
-	if score > 100 {
-		score = 100
+// calculateMatchScore scores a helper with the shared helper matching
+// weights and returns the score (0-100) with its reasons.
+func calculateMatchScore(suggestion activity.HelperSuggestion) (int, []string) {
+	in := matchscore.Input{
+		Now:          time.Now(),
+		SolvedAt:     suggestion.CompletedTaskAt,
+		IsOnline:     suggestion.IsOnline,
+		LastSeenAt:   suggestion.LastSeenAt,
+		Rating:       suggestion.HelperRating,
+		HelpCount:    suggestion.TimesHelpedOther,
+		PriorContact: suggestion.HasPriorContact,
 	}
 
-	return score
+	result := matchscore.Score(in, matchscore.DefaultWeights())
+	return min(int(math.Round(result.Score)), 100), result.Reasons()
 }
 
-func generateHelpRequestID(requesterID, taskID string) string {
-	return fmt.Sprintf("help_%s_%s_%d", requesterID, taskID, time.Now().UnixNano())
+// generateHelpRequestID returns a new help request ID (help_requests.id is a UUID).
+func generateHelpRequestID() string {
+	return uuid.New().String()
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -593,7 +836,7 @@ type ResolveHelpRequestCommand struct {
 	// RequestID is the ID of the help request.
 	RequestID string
 
-	// RequesterID is the ID of the requester (for validation).
+	// RequesterID is the ID of the requester or a teammate (for validation).
 	RequesterID string
 
 	// HelperID is the ID of the helper who resolved it.
@@ -631,6 +874,10 @@ type ResolveHelpRequestResult struct {
 	// Duration is how long the request was open.
 	Duration time.Duration
 
+	// Participants is the requester and the teammates, each of whom may
+	// endorse the helper.
+	Participants []string
+
 	// Events contains domain events generated.
 	Events []shared.Event
 }
@@ -670,8 +917,8 @@ func (h *ResolveHelpRequestHandler) Handle(
 		return nil, fmt.Errorf("resolve_help: request not found: %w", err)
 	}
 
-	// Verify requester
-	if string(request.RequesterID) != cmd.RequesterID {
+	// Verify requester: any participant of a team request may resolve it
+	if !request.IsParticipant(social.StudentID(cmd.RequesterID)) {
 		return nil, errors.New("resolve_help: requester mismatch")
 	}
 
@@ -713,12 +960,27 @@ func (h *ResolveHelpRequestHandler) Handle(
 		Duration:  time.Since(request.CreatedAt),
 		Events:    make([]shared.Event, 0),
 	}
+	for _, id := range request.Participants() {
+		result.Participants = append(result.Participants, string(id))
+	}
+
+	// Emit events
+	resolved := shared.NewHelpRequestResolvedEvent(
+		cmd.RequestID,
+		string(request.RequesterID),
+		cmd.HelperID,
+		string(request.TaskID),
+	)
+	if cmd.CorrelationID != "" {
+		resolved.BaseEvent = resolved.BaseEvent.WithCorrelationID(cmd.CorrelationID)
+	}
+	result.Events = append(result.Events, resolved)
+	_ = h.eventPublisher.Publish(resolved)
 
-	// Emit event
 	if cmd.HelperID != "" {
 		event := shared.NewHelpProvidedEvent(
 			cmd.HelperID,
-			cmd.RequesterID,
+			string(request.RequesterID),
 			string(request.TaskID),
 		)
 		event.RequestID = cmd.RequestID
@@ -731,3 +993,147 @@ func (h *ResolveHelpRequestHandler) Handle(
 
 	return result, nil
 }
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RESPOND TO HELP REQUEST COMMAND
+// Records a helper's reaction to a help request: accepting it or writing to
+// the requester. The first reaction sets the request's first response time.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// HelpResponseKind is the kind of a helper's reaction.
+type HelpResponseKind string
+
+const (
+	// HelpResponseAccept means the helper takes the request.
+	HelpResponseAccept HelpResponseKind = "accept"
+
+	// HelpResponseMessage means the helper wrote to the requester.
+	HelpResponseMessage HelpResponseKind = "message"
+)
+
+// RespondToHelpRequestCommand records a helper's reaction to a help request.
+type RespondToHelpRequestCommand struct {
+	// RequestID is the ID of the help request.
+	RequestID string
+
+	// HelperID is the ID of the responding helper.
+	HelperID string
+
+	// Kind is the kind of reaction (default: message).
+	Kind HelpResponseKind
+}
+
+// Validate validates the command.
+func (c RespondToHelpRequestCommand) Validate() error {
+	if c.RequestID == "" {
+		return errors.New("respond_help: request_id is required")
+	}
+	if c.HelperID == "" {
+		return errors.New("respond_help: helper_id is required")
+	}
+	switch c.Kind {
+	case "", HelpResponseAccept, HelpResponseMessage:
+	default:
+		return fmt.Errorf("respond_help: invalid kind: %s", c.Kind)
+	}
+	return nil
+}
+
+// RespondToHelpRequestResult contains the result of recording a reaction.
+type RespondToHelpRequestResult struct {
+	// RequestID is the ID of the help request.
+	RequestID string
+
+	// Status is the status of the request after the reaction.
+	Status social.HelpRequestStatus
+
+	// FirstResponse indicates that this was the first reaction to the request.
+	FirstResponse bool
+
+	// FirstResponseTime is the time from creation to the first reaction.
+	FirstResponseTime time.Duration
+
+	// ClusterTakeable is how many other requests of the cluster the helper
+	// can take along after accepting (see HelpClusterHandler.Take).
+	ClusterTakeable int
+}
+
+// RespondToHelpRequestHandler handles the RespondToHelpRequestCommand.
+type RespondToHelpRequestHandler struct {
+	socialRepo     social.Repository
+	eventPublisher shared.EventPublisher
+	now            func() time.Time
+}
+
+// NewRespondToHelpRequestHandler creates a new handler.
+func NewRespondToHelpRequestHandler(socialRepo social.Repository) *RespondToHelpRequestHandler {
+	return &RespondToHelpRequestHandler{
+		socialRepo: socialRepo,
+		now:        time.Now,
+	}
+}
+
+// WithEventPublisher publishes HelperAssignedEvent when a helper accepts a request.
+func (h *RespondToHelpRequestHandler) WithEventPublisher(publisher shared.EventPublisher) *RespondToHelpRequestHandler {
+	h.eventPublisher = publisher
+	return h
+}
+
+// Handle executes the respond to help request command.
+func (h *RespondToHelpRequestHandler) Handle(
+	ctx context.Context,
+	cmd RespondToHelpRequestCommand,
+) (*RespondToHelpRequestResult, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	request, err := h.socialRepo.HelpRequests().GetByID(ctx, cmd.RequestID)
+	if err != nil {
+		return nil, fmt.Errorf("respond_help: request not found: %w", err)
+	}
+
+	hadResponse := request.FirstHelperResponseAt != nil
+	helperID := social.StudentID(cmd.HelperID)
+
+	if cmd.Kind == HelpResponseAccept {
+		if err := request.AssignHelper(helperID); err != nil {
+			return nil, fmt.Errorf("respond_help: failed to accept: %w", err)
+		}
+	} else if _, err := request.RecordHelperResponse(helperID, h.now()); err != nil {
+		return nil, fmt.Errorf("respond_help: failed to record response: %w", err)
+	}
+
+	result := &RespondToHelpRequestResult{
+		RequestID:     request.ID,
+		Status:        request.Status,
+		FirstResponse: !hadResponse && request.FirstHelperResponseAt != nil,
+	}
+	result.FirstResponseTime, _ = request.FirstResponseTime()
+
+	// Nothing changed: a repeated message or a message to a closed request
+	if cmd.Kind != HelpResponseAccept && !result.FirstResponse {
+		return result, nil
+	}
+
+	if err := h.socialRepo.HelpRequests().Update(ctx, request); err != nil {
+		return nil, fmt.Errorf("respond_help: failed to save: %w", err)
+	}
+
+	if cmd.Kind == HelpResponseAccept && h.eventPublisher != nil {
+		_ = h.eventPublisher.Publish(shared.NewHelperAssignedEvent(
+			request.ID,
+			string(request.RequesterID),
+			cmd.HelperID,
+			string(request.TaskID),
+		))
+	}
+
+	if cmd.Kind == HelpResponseAccept && request.ClusterID != "" {
+		if members, err := h.socialRepo.HelpRequests().GetByCluster(ctx, request.ClusterID); err == nil {
+			result.ClusterTakeable = len(social.TakeableInCluster(members, helperID))
+		}
+	}
+
+	return result, nil
+}
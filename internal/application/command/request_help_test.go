@@ -0,0 +1,142 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestHelp_ReturnsOpenRequestForSameTask(t *testing.T) {
+	f := newClusterFixture()
+
+	first := f.ask(t, "dana")
+	again := f.ask(t, "dana")
+
+	assert.False(t, first.Deduplicated)
+	assert.True(t, again.Deduplicated)
+	assert.Equal(t, first.RequestID, again.RequestID)
+	require.Len(t, again.MatchedHelpers, 1)
+	assert.Equal(t, "aigerim", again.MatchedHelpers[0].StudentID)
+	assert.Len(t, f.requests.requests, 1)
+	assert.Equal(t, 1, f.notifier.pings["aigerim"], "a repeated ask must not ping helpers again")
+}
+
+func TestExistingRequestResult_StoredHelpers(t *testing.T) {
+	// As read back from help_requests: helpers from matched_helpers, no
+	// solved-at time
+	seen := time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)
+	request := &social.HelpRequest{
+		ID:          "req-1",
+		RequesterID: "dana",
+		TaskID:      "go-reloaded",
+		Status:      social.HelpRequestStatusOpen,
+		MatchedHelpers: []social.MatchedHelper{{
+			StudentID:    "aigerim",
+			DisplayName:  "Айгерим",
+			HelpRating:   4.5,
+			IsOnline:     true,
+			LastSeenAt:   seen,
+			MatchScore:   87,
+			MatchReasons: []string{"solved the task"},
+		}},
+	}
+
+	result := existingRequestResult(request)
+
+	assert.True(t, result.Deduplicated)
+	assert.Equal(t, 1, result.TotalHelpersFound)
+	assert.Equal(t, []MatchedHelperInfo{{
+		StudentID:    "aigerim",
+		DisplayName:  "Айгерим",
+		IsOnline:     true,
+		LastSeenAt:   seen,
+		HelpRating:   4.5,
+		MatchScore:   87,
+		MatchReasons: []string{"solved the task"},
+	}}, result.MatchedHelpers)
+}
+
+func TestRequestHelp_CapsOpenRequestsPerStudent(t *testing.T) {
+	f := newClusterFixture()
+	f.request.maxOpenRequests = 2
+
+	for _, task := range []string{"graph-01", "graph-02"} {
+		_, err := f.request.Handle(context.Background(), RequestHelpCommand{RequesterID: "dana", TaskID: task})
+		require.NoError(t, err)
+	}
+
+	_, err := f.request.Handle(context.Background(), RequestHelpCommand{RequesterID: "dana", TaskID: "graph-03"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, social.ErrHelpRequestLimit))
+
+	var limitErr *social.HelpRequestLimitError
+	require.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, 2, limitErr.Limit)
+	assert.Len(t, f.requests.requests, 2)
+}
+
+func TestRequestHelp_ConcurrentAsksCreateOneRequest(t *testing.T) {
+	f := newClusterFixture()
+	// Both asks pass the open-request check before either one is stored, so
+	// only the unique index decides which of them wins.
+	requests := &barrierHelpRequests{memoryHelpRequests: f.requests, waiting: 2, release: make(chan struct{})}
+	f.request.socialRepo = &barrierSocial{memorySocial: &memorySocial{helpRequests: f.requests}, requests: requests}
+
+	results := make([]*RequestHelpResult, 2)
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = f.request.Handle(context.Background(), RequestHelpCommand{
+				RequesterID: "dana", TaskID: "graph-01",
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	assert.Equal(t, results[0].RequestID, results[1].RequestID)
+	assert.NotEqual(t, results[0].Deduplicated, results[1].Deduplicated, "exactly one ask creates the request")
+	assert.Len(t, f.requests.requests, 1)
+}
+
+// barrierHelpRequests holds the first lookups of open requests until all of
+// them have arrived.
+type barrierHelpRequests struct {
+	*memoryHelpRequests
+	mu      sync.Mutex
+	waiting int
+	release chan struct{}
+}
+
+func (r *barrierHelpRequests) GetOpenByRequesterID(ctx context.Context, requesterID social.StudentID) ([]*social.HelpRequest, error) {
+	open, err := r.memoryHelpRequests.GetOpenByRequesterID(ctx, requesterID)
+
+	r.mu.Lock()
+	if r.waiting > 0 {
+		r.waiting--
+		if r.waiting == 0 {
+			close(r.release)
+		}
+	}
+	r.mu.Unlock()
+
+	<-r.release
+	return open, err
+}
+
+type barrierSocial struct {
+	*memorySocial
+	requests social.HelpRequestRepository
+}
+
+func (s *barrierSocial) HelpRequests() social.HelpRequestRepository { return s.requests }
@@ -0,0 +1,124 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// REVIEW XP FLAG COMMAND
+// Curator decision on a suspicious XP spike. Approving clears the flag;
+// dismissing as abuse needs a second, explicit confirmation and then
+// records a negative XP adjustment for the excess gain.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// XPFlagDecision is the curator's decision on a flagged day.
+type XPFlagDecision string
+
+const (
+	// XPFlagApprove marks the gain as legitimate.
+	XPFlagApprove XPFlagDecision = "approve"
+
+	// XPFlagDismiss marks the gain as abuse.
+	XPFlagDismiss XPFlagDecision = "dismiss"
+)
+
+// ErrInvalidXPFlagDecision is returned for an unknown decision.
+var ErrInvalidXPFlagDecision = errors.New("invalid xp flag decision")
+
+// ReviewXPFlagCommand contains a curator decision.
+type ReviewXPFlagCommand struct {
+	// FlagID is the ID of the flag.
+	FlagID int64
+
+	// Decision is approve or dismiss.
+	Decision XPFlagDecision
+
+	// CuratorID is the Telegram ID of the curator.
+	CuratorID int64
+
+	// Confirmed applies a dismissal. Without it a dismissal only returns
+	// the adjustment that would be recorded.
+	Confirmed bool
+}
+
+// ReviewXPFlagResult contains the outcome of a review.
+type ReviewXPFlagResult struct {
+	// Flag is the flag after the decision (or unchanged, when only
+	// confirmation is needed).
+	Flag *student.XPFlag
+
+	// Student is the flagged student (nil if they no longer exist).
+	Student *student.Student
+
+	// NeedsConfirmation is true for an unconfirmed dismissal.
+	NeedsConfirmation bool
+}
+
+// ReviewXPFlagHandler handles the ReviewXPFlagCommand.
+type ReviewXPFlagHandler struct {
+	flags       student.XPFlagRepository
+	studentRepo student.Repository
+	now         func() time.Time
+}
+
+// NewReviewXPFlagHandler creates a new ReviewXPFlagHandler.
+func NewReviewXPFlagHandler(flags student.XPFlagRepository, studentRepo student.Repository) *ReviewXPFlagHandler {
+	return &ReviewXPFlagHandler{
+		flags:       flags,
+		studentRepo: studentRepo,
+		now:         time.Now,
+	}
+}
+
+// Handle applies the decision. A flag that was already resolved returns
+// student.ErrXPFlagResolved together with the result, so the caller can
+// show who got there first.
+func (h *ReviewXPFlagHandler) Handle(ctx context.Context, cmd ReviewXPFlagCommand) (*ReviewXPFlagResult, error) {
+	var status student.XPFlagStatus
+	switch cmd.Decision {
+	case XPFlagApprove:
+		status = student.XPFlagApproved
+	case XPFlagDismiss:
+		status = student.XPFlagDismissed
+	default:
+		return nil, fmt.Errorf("review_xp_flag: %w", ErrInvalidXPFlagDecision)
+	}
+
+	flag, err := h.flags.GetByID(ctx, cmd.FlagID)
+	if err != nil {
+		return nil, fmt.Errorf("review_xp_flag: %w", err)
+	}
+
+	result := &ReviewXPFlagResult{Flag: flag}
+	if s, err := h.studentRepo.GetByID(ctx, flag.StudentID); err == nil {
+		result.Student = s
+	}
+
+	if !flag.IsPending() {
+		return result, student.ErrXPFlagResolved
+	}
+
+	if status == student.XPFlagDismissed && !cmd.Confirmed {
+		result.NeedsConfirmation = true
+		return result, nil
+	}
+
+	resolved, err := h.flags.Resolve(ctx, cmd.FlagID, status, cmd.CuratorID, h.now())
+	if errors.Is(err, student.ErrXPFlagResolved) {
+		if current, getErr := h.flags.GetByID(ctx, cmd.FlagID); getErr == nil {
+			result.Flag = current
+		}
+		return result, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("review_xp_flag: %w", err)
+	}
+
+	result.Flag = resolved
+	return result, nil
+}
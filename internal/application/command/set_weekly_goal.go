@@ -0,0 +1,114 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// SET WEEKLY GOAL COMMAND
+// Sets or updates the student's XP target for the current ISO week.
+// A goal that is already reached is locked until the week ends.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// SetWeeklyGoalCommand contains the data to set a weekly goal.
+type SetWeeklyGoalCommand struct {
+	// StudentID is the ID of the student.
+	StudentID string
+
+	// TargetXP is the XP the student wants to earn this week.
+	TargetXP int
+}
+
+// Validate validates the command.
+func (c SetWeeklyGoalCommand) Validate() error {
+	if c.StudentID == "" {
+		return errors.New("set_weekly_goal: student_id is required")
+	}
+	if err := student.ValidateGoalTarget(student.XP(c.TargetXP)); err != nil {
+		return fmt.Errorf("set_weekly_goal: %w", err)
+	}
+	return nil
+}
+
+// SetWeeklyGoalResult contains the result of setting a goal.
+type SetWeeklyGoalResult struct {
+	// Goal is the saved goal.
+	Goal *student.WeeklyGoal
+
+	// Progress is the goal progress right after saving.
+	Progress student.WeeklyGoalProgress
+
+	// Updated is true if an existing goal was changed.
+	Updated bool
+}
+
+// SetWeeklyGoalHandler handles the SetWeeklyGoalCommand.
+type SetWeeklyGoalHandler struct {
+	goals    student.WeeklyGoalRepository
+	progress student.ProgressRepository
+	now      func() time.Time
+}
+
+// NewSetWeeklyGoalHandler creates a new SetWeeklyGoalHandler.
+func NewSetWeeklyGoalHandler(
+	goals student.WeeklyGoalRepository,
+	progress student.ProgressRepository,
+) *SetWeeklyGoalHandler {
+	return &SetWeeklyGoalHandler{
+		goals:    goals,
+		progress: progress,
+		now:      time.Now,
+	}
+}
+
+// Handle executes the set weekly goal command.
+// Returns student.ErrGoalOutOfRange, student.ErrGoalAlreadyReached or
+// student.ErrGoalTargetAlreadyEarned (wrapped) when the goal cannot be set.
+func (h *SetWeeklyGoalHandler) Handle(ctx context.Context, cmd SetWeeklyGoalCommand) (*SetWeeklyGoalResult, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := h.now().UTC()
+	week := student.ISOWeekOf(now)
+	target := student.XP(cmd.TargetXP)
+
+	start, err := week.Start()
+	if err != nil {
+		return nil, fmt.Errorf("set_weekly_goal: %w", err)
+	}
+	weekXP, err := h.progress.GetXPGainedBetween(ctx, cmd.StudentID, start, start.AddDate(0, 0, 7))
+	if err != nil {
+		return nil, fmt.Errorf("set_weekly_goal: failed to get week xp: %w", err)
+	}
+
+	result := &SetWeeklyGoalResult{}
+	goal, err := h.goals.Get(ctx, cmd.StudentID, week)
+	switch {
+	case err == nil:
+		if err := goal.ChangeTarget(target, weekXP, now); err != nil {
+			return nil, fmt.Errorf("set_weekly_goal: %w", err)
+		}
+		result.Updated = true
+	case errors.Is(err, student.ErrGoalNotFound):
+		goal, err = student.NewWeeklyGoal(cmd.StudentID, week, target, now)
+		if err != nil {
+			return nil, fmt.Errorf("set_weekly_goal: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("set_weekly_goal: failed to get goal: %w", err)
+	}
+
+	if err := h.goals.Save(ctx, goal); err != nil {
+		return nil, fmt.Errorf("set_weekly_goal: failed to save goal: %w", err)
+	}
+
+	result.Goal = goal
+	result.Progress = goal.Progress(weekXP, now)
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type memoryGoals struct {
+	student.WeeklyGoalRepository
+	goals map[student.ISOWeek]*student.WeeklyGoal
+}
+
+func (m *memoryGoals) Get(_ context.Context, _ string, week student.ISOWeek) (*student.WeeklyGoal, error) {
+	goal, ok := m.goals[week]
+	if !ok {
+		return nil, student.ErrGoalNotFound
+	}
+	stored := *goal
+	return &stored, nil
+}
+
+func (m *memoryGoals) Save(_ context.Context, goal *student.WeeklyGoal) error {
+	if stored, ok := m.goals[goal.Week]; ok && stored.IsReached() {
+		return student.ErrGoalAlreadyReached
+	}
+	stored := *goal
+	m.goals[goal.Week] = &stored
+	return nil
+}
+
+type fixedWeekXP struct {
+	student.ProgressRepository
+	xp student.XP
+}
+
+func (f *fixedWeekXP) GetXPGainedBetween(context.Context, string, time.Time, time.Time) (student.XP, error) {
+	return f.xp, nil
+}
+
+func newSetGoalHandler(weekXP student.XP, now time.Time) (*SetWeeklyGoalHandler, *memoryGoals, *fixedWeekXP) {
+	goals := &memoryGoals{goals: make(map[student.ISOWeek]*student.WeeklyGoal)}
+	progress := &fixedWeekXP{xp: weekXP}
+	h := NewSetWeeklyGoalHandler(goals, progress)
+	h.now = func() time.Time { return now }
+	return h, goals, progress
+}
+
+func TestSetWeeklyGoal_SetAndChange(t *testing.T) {
+	wednesday := time.Date(2026, time.October, 14, 10, 0, 0, 0, time.UTC)
+	h, goals, progress := newSetGoalHandler(120, wednesday)
+	ctx := context.Background()
+
+	result, err := h.Handle(ctx, SetWeeklyGoalCommand{StudentID: "dana", TargetXP: 500})
+	require.NoError(t, err)
+	assert.False(t, result.Updated)
+	assert.Equal(t, student.XP(380), result.Progress.RemainingXP)
+
+	progress.xp = 300
+	result, err = h.Handle(ctx, SetWeeklyGoalCommand{StudentID: "dana", TargetXP: 800})
+	require.NoError(t, err)
+	assert.True(t, result.Updated)
+	assert.Equal(t, student.XP(800), goals.goals[student.ISOWeekOf(wednesday)].TargetXP)
+
+	_, err = h.Handle(ctx, SetWeeklyGoalCommand{StudentID: "dana", TargetXP: 10})
+	assert.ErrorIs(t, err, student.ErrGoalOutOfRange)
+}
+
+func TestSetWeeklyGoal_RejectsTargetAlreadyEarned(t *testing.T) {
+	wednesday := time.Date(2026, time.October, 14, 10, 0, 0, 0, time.UTC)
+	h, goals, progress := newSetGoalHandler(0, wednesday)
+	ctx := context.Background()
+
+	_, err := h.Handle(ctx, SetWeeklyGoalCommand{StudentID: "dana", TargetXP: 1000})
+	require.NoError(t, err)
+
+	// Lowering the goal to the XP earned so far would reach it at once
+	progress.xp = 400
+	for _, target := range []int{400, 300} {
+		_, err = h.Handle(ctx, SetWeeklyGoalCommand{StudentID: "dana", TargetXP: target})
+		assert.ErrorIs(t, err, student.ErrGoalTargetAlreadyEarned, "target %d", target)
+	}
+	assert.Equal(t, student.XP(1000), goals.goals[student.ISOWeekOf(wednesday)].TargetXP)
+
+	// A reached goal is locked until the week ends
+	progress.xp = 1000
+	_, err = h.Handle(ctx, SetWeeklyGoalCommand{StudentID: "dana", TargetXP: 2000})
+	assert.ErrorIs(t, err, student.ErrGoalAlreadyReached)
+}
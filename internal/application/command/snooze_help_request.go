@@ -0,0 +1,109 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// SNOOZE HELP REQUEST COMMAND
+// "⏰ Напомнить через 2 часа" on a help invitation: the worker sends the
+// invitation again after social.HelpSnoozeDelay (see RemindSnoozedHelpJob).
+// A helper can snooze a request at most social.MaxHelpSnoozes times, and
+// only while it still waits for a helper.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// SnoozeHelpRequestCommand postpones a help invitation.
+type SnoozeHelpRequestCommand struct {
+	// RequestID is the ID of the help request.
+	RequestID string
+
+	// HelperID is the ID of the invited helper.
+	HelperID string
+}
+
+// Validate validates the command.
+func (c SnoozeHelpRequestCommand) Validate() error {
+	if c.RequestID == "" {
+		return errors.New("snooze_help: request_id is required")
+	}
+	if c.HelperID == "" {
+		return errors.New("snooze_help: helper_id is required")
+	}
+	return nil
+}
+
+// SnoozeHelpRequestResult contains the result of a snooze.
+type SnoozeHelpRequestResult struct {
+	// RemindAt is when the invitation comes again.
+	RemindAt time.Time
+
+	// Remaining is how many more times the helper can snooze the request.
+	Remaining int
+}
+
+// SnoozeHelpRequestHandler handles the SnoozeHelpRequestCommand.
+type SnoozeHelpRequestHandler struct {
+	helpRequests social.HelpRequestRepository
+	snoozes      social.HelpSnoozeRepository
+	now          func() time.Time
+}
+
+// NewSnoozeHelpRequestHandler creates a new handler.
+func NewSnoozeHelpRequestHandler(
+	helpRequests social.HelpRequestRepository,
+	snoozes social.HelpSnoozeRepository,
+) *SnoozeHelpRequestHandler {
+	return &SnoozeHelpRequestHandler{
+		helpRequests: helpRequests,
+		snoozes:      snoozes,
+		now:          time.Now,
+	}
+}
+
+// Handle executes the snooze help request command. It returns
+// social.ErrHelpRequestAlreadyClosed when the request no longer waits for a
+// helper, social.ErrHelpRequestAlreadyMatched when the helper already took
+// it and social.ErrHelpSnoozeLimit when the snoozes are used up.
+func (h *SnoozeHelpRequestHandler) Handle(
+	ctx context.Context,
+	cmd SnoozeHelpRequestCommand,
+) (*SnoozeHelpRequestResult, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	request, err := h.helpRequests.GetByID(ctx, cmd.RequestID)
+	if err != nil {
+		return nil, fmt.Errorf("snooze_help: request not found: %w", err)
+	}
+
+	now := h.now()
+	helperID := social.StudentID(cmd.HelperID)
+	if helperID == request.RequesterID {
+		return nil, social.ErrHelpRequestSelfHelp
+	}
+	switch request.SnoozeAction(helperID, now) {
+	case social.HelpSnoozeNothing:
+		return nil, social.ErrHelpRequestAlreadyMatched
+	case social.HelpSnoozeAlreadyHandled:
+		return nil, social.ErrHelpRequestAlreadyClosed
+	}
+
+	snooze := social.NewHelpSnooze(request.ID, helperID, now)
+	if err := h.snoozes.Create(ctx, snooze); err != nil {
+		if errors.Is(err, social.ErrHelpSnoozeLimit) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("snooze_help: failed to save: %w", err)
+	}
+
+	return &SnoozeHelpRequestResult{
+		RemindAt:  snooze.RemindAt,
+		Remaining: social.MaxHelpSnoozes - snooze.Attempt,
+	}, nil
+}
@@ -0,0 +1,109 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+// memoryHelpSnoozes keeps snoozes in memory, like help_snoozes.
+type memoryHelpSnoozes struct {
+	social.HelpSnoozeRepository
+	snoozes []*social.HelpSnooze
+}
+
+func (r *memoryHelpSnoozes) Create(_ context.Context, snooze *social.HelpSnooze) error {
+	attempt := 1
+	for _, s := range r.snoozes {
+		if s.HelpRequestID == snooze.HelpRequestID && s.HelperID == snooze.HelperID {
+			attempt++
+		}
+	}
+	if attempt > social.MaxHelpSnoozes {
+		return social.ErrHelpSnoozeLimit
+	}
+	snooze.ID = int64(len(r.snoozes) + 1)
+	snooze.Attempt = attempt
+	r.snoozes = append(r.snoozes, snooze)
+	return nil
+}
+
+func TestSnoozeHelpRequest(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	newHandler := func(req *social.HelpRequest) (*SnoozeHelpRequestHandler, *memoryHelpSnoozes) {
+		requests := newMemoryHelpRequests(nil)
+		require.NoError(t, requests.Create(ctx, req))
+		snoozes := &memoryHelpSnoozes{}
+		h := NewSnoozeHelpRequestHandler(requests, snoozes)
+		h.now = func() time.Time { return now }
+		return h, snoozes
+	}
+	openRequest := func() *social.HelpRequest {
+		return &social.HelpRequest{
+			ID:          "req-1",
+			RequesterID: "dana",
+			TaskID:      "go-reloaded",
+			Status:      social.HelpRequestStatusOpen,
+			ExpiresAt:   now.Add(time.Hour),
+		}
+	}
+	cmd := SnoozeHelpRequestCommand{RequestID: "req-1", HelperID: "aigerim"}
+
+	t.Run("at most twice per request", func(t *testing.T) {
+		h, snoozes := newHandler(openRequest())
+
+		first, err := h.Handle(ctx, cmd)
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(social.HelpSnoozeDelay), first.RemindAt)
+		assert.Equal(t, 1, first.Remaining)
+
+		second, err := h.Handle(ctx, cmd)
+		require.NoError(t, err)
+		assert.Equal(t, 0, second.Remaining)
+
+		_, err = h.Handle(ctx, cmd)
+		assert.ErrorIs(t, err, social.ErrHelpSnoozeLimit)
+		assert.Len(t, snoozes.snoozes, 2)
+
+		// Another helper has their own snoozes
+		_, err = h.Handle(ctx, SnoozeHelpRequestCommand{RequestID: "req-1", HelperID: "arman"})
+		require.NoError(t, err)
+	})
+
+	t.Run("closed request", func(t *testing.T) {
+		req := openRequest()
+		req.Status = social.HelpRequestStatusResolved
+		h, snoozes := newHandler(req)
+
+		_, err := h.Handle(ctx, cmd)
+		assert.ErrorIs(t, err, social.ErrHelpRequestAlreadyClosed)
+		assert.Empty(t, snoozes.snoozes)
+	})
+
+	t.Run("expired request", func(t *testing.T) {
+		req := openRequest()
+		req.ExpiresAt = now.Add(-time.Minute)
+		h, _ := newHandler(req)
+
+		_, err := h.Handle(ctx, cmd)
+		assert.ErrorIs(t, err, social.ErrHelpRequestAlreadyClosed)
+	})
+
+	t.Run("taken by the helper", func(t *testing.T) {
+		req := openRequest()
+		helper := social.StudentID("aigerim")
+		req.HelperID = &helper
+		req.Status = social.HelpRequestStatusInProgress
+		h, _ := newHandler(req)
+
+		_, err := h.Handle(ctx, cmd)
+		assert.ErrorIs(t, err, social.ErrHelpRequestAlreadyMatched)
+	})
+}
@@ -0,0 +1,230 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// SYNC ME COMMAND
+// "/sync_me" syncs the student right away instead of waiting for the next
+// batch sync. It runs through SyncStudentHandler, so XP history, rank and
+// top-N events fire as usual, and reports the before/after change. At most
+// once per student.OnDemandSyncCooldown; if the batch sync is syncing the
+// student right now, the command waits for it instead of syncing twice.
+// ══════════════════════════════════════════════════════════════════════════════
+
+var (
+	// ErrSyncMeTooSoon is returned when the student synced less than
+	// student.OnDemandSyncCooldown ago.
+	ErrSyncMeTooSoon = errors.New("sync_me: synced recently")
+
+	// ErrSyncMeBusy is returned when the batch sync of the student did not
+	// finish within SyncMeConfig.BatchWait.
+	ErrSyncMeBusy = errors.New("sync_me: batch sync in progress")
+)
+
+// SyncMeCommand requests an on-demand sync.
+type SyncMeCommand struct {
+	StudentID string
+}
+
+// SyncMeResult is the change made by the sync.
+type SyncMeResult struct {
+	OldXP   int
+	NewXP   int
+	XPDelta int
+
+	// OldRank and NewRank are 0 when the student is not ranked.
+	OldRank int
+	NewRank int
+
+	// ByBatch is set when the batch sync did the work while we waited.
+	ByBatch bool
+}
+
+// RankChanged reports whether both ranks are known and differ.
+func (r *SyncMeResult) RankChanged() bool {
+	return r.OldRank > 0 && r.NewRank > 0 && r.OldRank != r.NewRank
+}
+
+// StudentSyncer syncs one student (implemented by SyncStudentHandler).
+type StudentSyncer interface {
+	Handle(ctx context.Context, cmd SyncStudentCommand) (*SyncStudentResult, error)
+}
+
+// SyncMeConfig contains configuration for the handler.
+type SyncMeConfig struct {
+	// BatchWait bounds the wait for a running batch sync of the student.
+	BatchWait time.Duration
+
+	// PollInterval is how often the batch sync lock is checked while waiting.
+	PollInterval time.Duration
+}
+
+// DefaultSyncMeConfig returns default configuration.
+func DefaultSyncMeConfig() SyncMeConfig {
+	return SyncMeConfig{
+		BatchWait:    20 * time.Second,
+		PollInterval: 500 * time.Millisecond,
+	}
+}
+
+// SyncMeHandler handles /sync_me.
+type SyncMeHandler struct {
+	syncer      StudentSyncer
+	studentRepo student.Repository
+	ranks       LeaderboardService
+	cooldowns   notification.CooldownStore
+	locks       student.SyncLock
+	config      SyncMeConfig
+}
+
+// NewSyncMeHandler creates a new SyncMeHandler. cooldowns enforces
+// student.OnDemandSyncCooldown; locks (nil = no coordination with the
+// batch sync) is shared with the worker.
+func NewSyncMeHandler(
+	syncer StudentSyncer,
+	studentRepo student.Repository,
+	ranks LeaderboardService,
+	cooldowns notification.CooldownStore,
+	locks student.SyncLock,
+	config SyncMeConfig,
+) *SyncMeHandler {
+	if config.BatchWait <= 0 || config.PollInterval <= 0 {
+		config = DefaultSyncMeConfig()
+	}
+
+	return &SyncMeHandler{
+		syncer:      syncer,
+		studentRepo: studentRepo,
+		ranks:       ranks,
+		cooldowns:   cooldowns,
+		locks:       locks,
+		config:      config,
+	}
+}
+
+// Handle syncs the student and returns the change.
+func (h *SyncMeHandler) Handle(ctx context.Context, cmd SyncMeCommand) (*SyncMeResult, error) {
+	if cmd.StudentID == "" {
+		return nil, errors.New("sync_me: student_id is required")
+	}
+
+	acquired, err := h.cooldowns.Acquire(ctx, syncMeCooldownKey(cmd.StudentID), student.OnDemandSyncCooldown)
+	if err != nil {
+		return nil, fmt.Errorf("sync_me: failed to check cooldown: %w", err)
+	}
+	if !acquired {
+		return nil, ErrSyncMeTooSoon
+	}
+
+	before, err := h.snapshot(ctx, cmd.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	byBatch, err := h.sync(ctx, cmd.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := h.snapshot(ctx, cmd.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newSyncMeResult(before, after)
+	result.ByBatch = byBatch
+	return result, nil
+}
+
+// sync runs the sync, or waits for the batch sync already running for the
+// student. It reports whether the batch sync did the work.
+func (h *SyncMeHandler) sync(ctx context.Context, studentID string) (bool, error) {
+	if h.locks != nil {
+		locked, err := h.locks.TryLock(ctx, studentID)
+		if err != nil {
+			return false, fmt.Errorf("sync_me: failed to lock student: %w", err)
+		}
+		if !locked {
+			return true, h.waitForBatch(ctx, studentID)
+		}
+		defer func() { _ = h.locks.Unlock(context.WithoutCancel(ctx), studentID) }()
+	}
+
+	_, err := h.syncer.Handle(student.WithOnDemandSync(ctx), SyncStudentCommand{
+		StudentID: studentID,
+		ForceSync: true,
+	})
+	if err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// waitForBatch waits until the batch sync releases the student, at most
+// SyncMeConfig.BatchWait.
+func (h *SyncMeHandler) waitForBatch(ctx context.Context, studentID string) error {
+	ctx, cancel := context.WithTimeout(ctx, h.config.BatchWait)
+	defer cancel()
+
+	ticker := time.NewTicker(h.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ErrSyncMeBusy
+		case <-ticker.C:
+		}
+
+		locked, err := h.locks.IsLocked(ctx, studentID)
+		if err != nil {
+			return fmt.Errorf("sync_me: failed to check lock: %w", err)
+		}
+		if !locked {
+			return nil
+		}
+	}
+}
+
+// syncSnapshot is the student's XP and rank at one moment.
+type syncSnapshot struct {
+	xp   int
+	rank int
+}
+
+// snapshot reads the student's XP and rank (0 if unknown).
+func (h *SyncMeHandler) snapshot(ctx context.Context, studentID string) (syncSnapshot, error) {
+	stud, err := h.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return syncSnapshot{}, fmt.Errorf("sync_me: failed to get student: %w", err)
+	}
+	rank, err := h.ranks.GetStudentRank(ctx, studentID)
+	if err != nil {
+		rank = 0
+	}
+	return syncSnapshot{xp: int(stud.CurrentXP), rank: rank}, nil
+}
+
+// newSyncMeResult computes the change between two snapshots.
+func newSyncMeResult(before, after syncSnapshot) *SyncMeResult {
+	return &SyncMeResult{
+		OldXP:   before.xp,
+		NewXP:   after.xp,
+		XPDelta: after.xp - before.xp,
+		OldRank: before.rank,
+		NewRank: after.rank,
+	}
+}
+
+// syncMeCooldownKey returns the cooldown key of a student.
+func syncMeCooldownKey(studentID string) string {
+	return "sync_me:" + studentID
+}
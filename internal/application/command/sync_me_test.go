@@ -0,0 +1,157 @@
+package command
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// syncMeWorld is the student's XP and rank, changed by the fake sync.
+type syncMeWorld struct {
+	student.Repository
+	mu       sync.Mutex
+	xp       int
+	rank     int
+	syncs    int
+	onDemand bool
+}
+
+func (w *syncMeWorld) GetByID(_ context.Context, id string) (*student.Student, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return &student.Student{ID: id, CurrentXP: student.XP(w.xp)}, nil
+}
+
+func (w *syncMeWorld) GetStudentRank(context.Context, string) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rank, nil
+}
+
+func (w *syncMeWorld) InvalidateCache(context.Context) error { return nil }
+
+func (w *syncMeWorld) Handle(ctx context.Context, _ SyncStudentCommand) (*SyncStudentResult, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.syncs++
+	w.onDemand = student.IsOnDemandSync(ctx)
+	w.xp += 120
+	w.rank -= 3
+	return &SyncStudentResult{}, nil
+}
+
+// syncMeCooldowns is an in-memory notification.CooldownStore.
+type syncMeCooldowns struct {
+	taken map[string]bool
+}
+
+func (c *syncMeCooldowns) Acquire(_ context.Context, key string, _ time.Duration) (bool, error) {
+	if c.taken[key] {
+		return false, nil
+	}
+	c.taken[key] = true
+	return true, nil
+}
+
+// syncMeLock is a student.SyncLock held by a batch sync until released.
+type syncMeLock struct {
+	mu     sync.Mutex
+	locked bool
+}
+
+func (l *syncMeLock) TryLock(context.Context, string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.locked {
+		return false, nil
+	}
+	l.locked = true
+	return true, nil
+}
+
+func (l *syncMeLock) Unlock(context.Context, string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.locked = false
+	return nil
+}
+
+func (l *syncMeLock) IsLocked(context.Context, string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.locked, nil
+}
+
+func newSyncMeTest(lock student.SyncLock) (*SyncMeHandler, *syncMeWorld) {
+	world := &syncMeWorld{xp: 1000, rank: 84}
+	h := NewSyncMeHandler(world, world, world, &syncMeCooldowns{taken: map[string]bool{}}, lock, SyncMeConfig{
+		BatchWait:    200 * time.Millisecond,
+		PollInterval: 5 * time.Millisecond,
+	})
+	return h, world
+}
+
+func TestSyncMe_Delta(t *testing.T) {
+	ctx := context.Background()
+	lock := &syncMeLock{}
+	h, world := newSyncMeTest(lock)
+
+	result, err := h.Handle(ctx, SyncMeCommand{StudentID: "dana"})
+	require.NoError(t, err)
+	assert.Equal(t, 1000, result.OldXP)
+	assert.Equal(t, 1120, result.NewXP)
+	assert.Equal(t, 120, result.XPDelta)
+	assert.Equal(t, 84, result.OldRank)
+	assert.Equal(t, 81, result.NewRank)
+	assert.True(t, result.RankChanged())
+	assert.False(t, result.ByBatch)
+	assert.True(t, world.onDemand)
+	assert.False(t, lock.locked)
+}
+
+func TestSyncMe_Cooldown(t *testing.T) {
+	ctx := context.Background()
+	h, world := newSyncMeTest(nil)
+
+	_, err := h.Handle(ctx, SyncMeCommand{StudentID: "dana"})
+	require.NoError(t, err)
+	_, err = h.Handle(ctx, SyncMeCommand{StudentID: "dana"})
+	assert.ErrorIs(t, err, ErrSyncMeTooSoon)
+	assert.Equal(t, 1, world.syncs)
+}
+
+func TestSyncMe_WaitsForBatch(t *testing.T) {
+	ctx := context.Background()
+	lock := &syncMeLock{locked: true}
+	h, world := newSyncMeTest(lock)
+
+	// The batch sync finishes while we wait
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		world.mu.Lock()
+		world.xp += 50
+		world.mu.Unlock()
+		_ = lock.Unlock(ctx, "dana")
+	}()
+
+	result, err := h.Handle(ctx, SyncMeCommand{StudentID: "dana"})
+	require.NoError(t, err)
+	assert.True(t, result.ByBatch)
+	assert.Equal(t, 50, result.XPDelta)
+	assert.False(t, result.RankChanged())
+	assert.Zero(t, world.syncs)
+}
+
+func TestSyncMe_BatchTimeout(t *testing.T) {
+	h, world := newSyncMeTest(&syncMeLock{locked: true})
+
+	_, err := h.Handle(context.Background(), SyncMeCommand{StudentID: "dana"})
+	assert.ErrorIs(t, err, ErrSyncMeBusy)
+	assert.Zero(t, world.syncs)
+}
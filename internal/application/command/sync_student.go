@@ -7,9 +7,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
 	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
 )
@@ -112,7 +112,8 @@ type AlemAPIClient interface {
 
 // LeaderboardService provides rank information.
 type LeaderboardService interface {
-	// GetStudentRank returns the current rank of a student.
+	// GetStudentRank returns the current global rank of a student.
+	// Returns leaderboard.ErrStudentNotRanked if the student has no rank yet.
 	GetStudentRank(ctx context.Context, studentID string) (int, error)
 
 	// InvalidateCache invalidates the leaderboard cache after updates.
@@ -196,20 +197,21 @@ func (h *SyncStudentHandler) Handle(ctx context.Context, cmd SyncStudentCommand)
 	}
 
 	// Fetch data from Alem API
-	// Derive login from email
-	parts := strings.Split(existingStudent.Email, "@")
-	if len(parts) != 2 {
+	login := existingStudent.SyncLogin()
+	if login == "" {
 		return nil, fmt.Errorf("sync_student: invalid email format: %s", existingStudent.Email)
 	}
-	login := parts[0]
 
 	alemData, err := h.alemClient.GetStudentByLogin(ctx, login)
 	if err != nil {
 		return nil, fmt.Errorf("sync_student: failed to fetch from Alem API: %w", err)
 	}
 
-	// Get current rank before sync
-	oldRank, _ := h.leaderboardService.GetStudentRank(ctx, existingStudent.ID)
+	// Get current rank before sync (0 for students not ranked yet)
+	oldRank, err := rankOrZero(h.leaderboardService.GetStudentRank(ctx, existingStudent.ID))
+	if err != nil {
+		return nil, fmt.Errorf("sync_student: failed to get rank: %w", err)
+	}
 
 	// Perform synchronization
 	result, err := h.syncStudentData(ctx, existingStudent, alemData, oldRank, cmd.CorrelationID)
@@ -318,10 +320,14 @@ func (h *SyncStudentHandler) syncStudentData(
 	}
 
 	// Sync online state
-	if alemData.IsOnline {
-		existingStudent.MarkOnline()
-	} else {
-		existingStudent.MarkOffline()
+	if existingStudent.ApplyOnlineStatus(alemData.IsOnline) {
+		// Persist the transition, otherwise the next sync would emit it again
+		hasChanges = true
+		event := shared.NewStudentWentOnlineEvent(existingStudent.ID, "")
+		if correlationID != "" {
+			event.BaseEvent = event.BaseEvent.WithCorrelationID(correlationID)
+		}
+		result.Events = append(result.Events, event)
 	}
 
 	// Update sync timestamp
@@ -335,7 +341,7 @@ func (h *SyncStudentHandler) syncStudentData(
 		result.WasUpdated = true
 
 		// Check for rank changes after update
-		newRank, err := h.leaderboardService.GetStudentRank(ctx, existingStudent.ID)
+		newRank, err := rankOrZero(h.leaderboardService.GetStudentRank(ctx, existingStudent.ID))
 		if err == nil && newRank > 0 {
 			result.NewRank = newRank
 			if oldRank > 0 && newRank != oldRank {
@@ -532,3 +538,12 @@ func (h *SyncAllStudentsHandler) Handle(ctx context.Context, cmd SyncAllStudents
 
 	return result, nil
 }
+
+// rankOrZero treats a student who is not ranked yet as rank 0 and keeps
+// other lookup errors.
+func rankOrZero(rank int, err error) (int, error) {
+	if errors.Is(err, leaderboard.ErrStudentNotRanked) {
+		return 0, nil
+	}
+	return rank, err
+}
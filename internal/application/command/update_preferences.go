@@ -29,6 +29,9 @@ type UpdatePreferencesCommand struct {
 	CorrelationID string
 }
 
+// DigestHourCohort resets PreferenceUpdates.DigestHour to the cohort's hour.
+const DigestHourCohort = -1
+
 // PreferenceUpdates contains optional preference updates.
 // nil values mean "don't change".
 type PreferenceUpdates struct {
@@ -44,12 +47,37 @@ type PreferenceUpdates struct {
 	// InactivityReminders - send reminders when inactive.
 	InactivityReminders *bool
 
+	// BuddyOnline - notify when a study buddy or mentor comes online.
+	BuddyOnline *bool
+
+	// StuckHelpOffers - offer help when progress has stalled.
+	StuckHelpOffers *bool
+
+	// HideFromLeaderboard - hide from public leaderboard listings.
+	HideFromLeaderboard *bool
+
+	// HideFromHelperSearch - don't suggest the student as a helper.
+	HideFromHelperSearch *bool
+
+	// HideOnlineStatus - don't show the student's online status to others.
+	HideOnlineStatus *bool
+
+	// QuickActions - show the quick action reply keyboard in private chat.
+	QuickActions *bool
+
 	// QuietHoursStart - start of quiet hours (0-23).
 	QuietHoursStart *int
 
 	// QuietHoursEnd - end of quiet hours (0-23).
 	QuietHoursEnd *int
 
+	// DigestHour - personal daily digest hour (0-23);
+	// DigestHourCohort goes back to the cohort's hour.
+	DigestHour *int
+
+	// Timezone - the student's IANA time zone.
+	Timezone *string
+
 	// IsOpenToHelp - whether the student is willing to help others.
 	IsOpenToHelp *bool
 
@@ -78,6 +106,18 @@ func (c UpdatePreferencesCommand) Validate() error {
 		}
 	}
 
+	if c.Preferences.DigestHour != nil {
+		if *c.Preferences.DigestHour < DigestHourCohort || *c.Preferences.DigestHour > 23 {
+			return errors.New("update_preferences: digest_hour must be 0-23 or -1")
+		}
+	}
+
+	if c.Preferences.Timezone != nil {
+		if err := student.ValidateTimezone(*c.Preferences.Timezone); err != nil {
+			return fmt.Errorf("update_preferences: %w", err)
+		}
+	}
+
 	// Validate display name if provided
 	if c.Preferences.DisplayName != nil {
 		name := *c.Preferences.DisplayName
@@ -113,8 +153,10 @@ type UpdatePreferencesResult struct {
 
 // UpdatePreferencesHandler handles the UpdatePreferencesCommand.
 type UpdatePreferencesHandler struct {
-	studentRepo student.Repository
-	cache       student.StudentCache // Optional cache for invalidation
+	studentRepo  student.Repository
+	cache        student.StudentCache       // Optional cache for invalidation
+	progressRepo student.ProgressRepository // Optional: streaks follow timezone changes
+	now          func() time.Time
 }
 
 // NewUpdatePreferencesHandler creates a new UpdatePreferencesHandler.
@@ -125,9 +167,18 @@ func NewUpdatePreferencesHandler(
 	return &UpdatePreferencesHandler{
 		studentRepo: studentRepo,
 		cache:       cache,
+		now:         time.Now,
 	}
 }
 
+// WithStreaks moves the student's daily streak to the new timezone when it
+// changes, so that the change itself neither breaks the streak nor counts
+// a day twice.
+func (h *UpdatePreferencesHandler) WithStreaks(progressRepo student.ProgressRepository) *UpdatePreferencesHandler {
+	h.progressRepo = progressRepo
+	return h
+}
+
 // Handle executes the update preferences command.
 func (h *UpdatePreferencesHandler) Handle(
 	ctx context.Context,
@@ -169,6 +220,36 @@ func (h *UpdatePreferencesHandler) Handle(
 		changedFields = append(changedFields, "inactivity_reminders")
 	}
 
+	if cmd.Preferences.BuddyOnline != nil && *cmd.Preferences.BuddyOnline != prefs.BuddyOnline {
+		prefs.BuddyOnline = *cmd.Preferences.BuddyOnline
+		changedFields = append(changedFields, "buddy_online")
+	}
+
+	if cmd.Preferences.StuckHelpOffers != nil && *cmd.Preferences.StuckHelpOffers != prefs.StuckHelpOffers {
+		prefs.StuckHelpOffers = *cmd.Preferences.StuckHelpOffers
+		changedFields = append(changedFields, "stuck_help_offers")
+	}
+
+	if cmd.Preferences.HideFromLeaderboard != nil && *cmd.Preferences.HideFromLeaderboard != prefs.HideFromLeaderboard {
+		prefs.HideFromLeaderboard = *cmd.Preferences.HideFromLeaderboard
+		changedFields = append(changedFields, "hide_from_leaderboard")
+	}
+
+	if cmd.Preferences.HideFromHelperSearch != nil && *cmd.Preferences.HideFromHelperSearch != prefs.HideFromHelperSearch {
+		prefs.HideFromHelperSearch = *cmd.Preferences.HideFromHelperSearch
+		changedFields = append(changedFields, "hide_from_helper_search")
+	}
+
+	if cmd.Preferences.HideOnlineStatus != nil && *cmd.Preferences.HideOnlineStatus != prefs.HideOnlineStatus {
+		prefs.HideOnlineStatus = *cmd.Preferences.HideOnlineStatus
+		changedFields = append(changedFields, "hide_online_status")
+	}
+
+	if cmd.Preferences.QuickActions != nil && *cmd.Preferences.QuickActions != prefs.QuickActions {
+		prefs.QuickActions = *cmd.Preferences.QuickActions
+		changedFields = append(changedFields, "quick_actions")
+	}
+
 	if cmd.Preferences.QuietHoursStart != nil && *cmd.Preferences.QuietHoursStart != prefs.QuietHoursStart {
 		prefs.QuietHoursStart = *cmd.Preferences.QuietHoursStart
 		changedFields = append(changedFields, "quiet_hours_start")
@@ -179,6 +260,25 @@ func (h *UpdatePreferencesHandler) Handle(
 		changedFields = append(changedFields, "quiet_hours_end")
 	}
 
+	if cmd.Preferences.DigestHour != nil {
+		var hour *int
+		if h := *cmd.Preferences.DigestHour; h != DigestHourCohort {
+			hour = &h
+		}
+		if !equalHour(hour, prefs.DigestHour) {
+			prefs.DigestHour = hour
+			changedFields = append(changedFields, "digest_hour")
+		}
+	}
+
+	previousLocation := prefs.Location()
+	timezoneChanged := false
+	if cmd.Preferences.Timezone != nil && *cmd.Preferences.Timezone != prefs.Timezone {
+		prefs.Timezone = *cmd.Preferences.Timezone
+		timezoneChanged = true
+		changedFields = append(changedFields, "timezone")
+	}
+
 	// Update display name if provided
 	if cmd.Preferences.DisplayName != nil && *cmd.Preferences.DisplayName != stud.DisplayName {
 		stud.DisplayName = *cmd.Preferences.DisplayName
@@ -200,6 +300,12 @@ func (h *UpdatePreferencesHandler) Handle(
 		}
 	}
 
+	if timezoneChanged {
+		if err := h.relocateStreak(ctx, stud.ID, previousLocation, prefs.Location()); err != nil {
+			return nil, fmt.Errorf("update_preferences: %w", err)
+		}
+	}
+
 	return &UpdatePreferencesResult{
 		Success:            true,
 		StudentID:          cmd.StudentID,
@@ -209,6 +315,40 @@ func (h *UpdatePreferencesHandler) Handle(
 	}, nil
 }
 
+// relocateStreak shifts the streak dates when "today" of the student changes
+// with the timezone.
+func (h *UpdatePreferencesHandler) relocateStreak(ctx context.Context, studentID string, from, to *time.Location) error {
+	if h.progressRepo == nil {
+		return nil
+	}
+
+	streak, err := h.progressRepo.GetStreak(ctx, studentID)
+	if err != nil {
+		return fmt.Errorf("failed to get streak: %w", err)
+	}
+	if streak == nil || streak.LastActiveDate.IsZero() {
+		return nil
+	}
+
+	now := h.now()
+	if student.LocalDate(now, from).Equal(student.LocalDate(now, to)) {
+		return nil
+	}
+	streak.WithLocation(from).Relocate(to, now)
+	if err := h.progressRepo.SaveStreak(ctx, streak); err != nil {
+		return fmt.Errorf("failed to save streak: %w", err)
+	}
+	return nil
+}
+
+// equalHour compares optional hours.
+func equalHour(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // PRESET PREFERENCES
 // Helper commands for common preference presets.
@@ -258,6 +398,8 @@ func (h *PresetPreferencesHandler) HandleEnableAll(
 			DailyDigest:         &t,
 			HelpRequests:        &t,
 			InactivityReminders: &t,
+			BuddyOnline:         &t,
+			StuckHelpOffers:     &t,
 		},
 	})
 }
@@ -276,6 +418,8 @@ func (h *PresetPreferencesHandler) HandleDisableAll(
 			DailyDigest:         &f,
 			HelpRequests:        &f,
 			InactivityReminders: &f,
+			BuddyOnline:         &f,
+			StuckHelpOffers:     &f,
 		},
 	})
 }
@@ -428,8 +572,14 @@ func (h *ResetPreferencesHandler) Handle(
 		return nil, fmt.Errorf("reset_preferences: student not found: %w", err)
 	}
 
-	// Reset to defaults
+	// Reset to defaults. Privacy choices, the quick action keyboard and the
+	// timezone are not notification settings and survive a reset.
 	defaultPrefs := student.DefaultNotificationPreferences()
+	defaultPrefs.HideFromLeaderboard = stud.Preferences.HideFromLeaderboard
+	defaultPrefs.HideFromHelperSearch = stud.Preferences.HideFromHelperSearch
+	defaultPrefs.HideOnlineStatus = stud.Preferences.HideOnlineStatus
+	defaultPrefs.QuickActions = stud.Preferences.QuickActions
+	defaultPrefs.Timezone = stud.Preferences.Timezone
 	stud.UpdatePreferences(defaultPrefs)
 
 	// Save changes
@@ -0,0 +1,92 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
+)
+
+type prefsStudents struct {
+	student.Repository
+	stud *student.Student
+}
+
+func (r *prefsStudents) GetByID(context.Context, string) (*student.Student, error) {
+	copied := *r.stud
+	return &copied, nil
+}
+
+func (r *prefsStudents) Update(_ context.Context, s *student.Student) error {
+	r.stud = s
+	return nil
+}
+
+type prefsStreaks struct {
+	student.ProgressRepository
+	streak *student.Streak
+	saves  int
+}
+
+func (p *prefsStreaks) GetStreak(context.Context, string) (*student.Streak, error) {
+	copied := *p.streak
+	return &copied, nil
+}
+
+func (p *prefsStreaks) SaveStreak(_ context.Context, s *student.Streak) error {
+	p.streak = s
+	p.saves++
+	return nil
+}
+
+func TestUpdatePreferences_TimezoneMovesStreak(t *testing.T) {
+	stud := &student.Student{ID: "dana", Preferences: student.DefaultNotificationPreferences()}
+	students := &prefsStudents{stud: stud}
+
+	// 01:00 in Almaty on October 18, already active today; in London it is
+	// still October 17
+	now := time.Date(2026, time.October, 18, 1, 0, 0, 0, timeutil.AlmatyTZ)
+	streak := student.NewStreak("dana").WithLocation(timeutil.AlmatyTZ)
+	streak.RecordActivity(now.AddDate(0, 0, -1))
+	streak.RecordActivity(now)
+	streaks := &prefsStreaks{streak: streak}
+
+	handler := NewUpdatePreferencesHandler(students, nil).WithStreaks(streaks)
+	handler.now = func() time.Time { return now }
+
+	london := "Europe/London"
+	result, err := handler.Handle(context.Background(), UpdatePreferencesCommand{
+		StudentID:   "dana",
+		Preferences: PreferenceUpdates{Timezone: &london},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"timezone"}, result.ChangedFields)
+	assert.Equal(t, london, students.stud.Preferences.Timezone)
+
+	// The streak dates follow "today": October 18 becomes October 17
+	require.Equal(t, 1, streaks.saves)
+	assert.Equal(t, time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC), streaks.streak.LastActiveDate)
+	assert.Equal(t, time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC), streaks.streak.StreakStartDate)
+	assert.Equal(t, 2, streaks.streak.CurrentStreak)
+
+	// Setting the same zone again changes nothing
+	result, err = handler.Handle(context.Background(), UpdatePreferencesCommand{
+		StudentID:   "dana",
+		Preferences: PreferenceUpdates{Timezone: &london},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.ChangedFields)
+	assert.Equal(t, 1, streaks.saves)
+
+	unknown := "Mars/Olympus"
+	_, err = handler.Handle(context.Background(), UpdatePreferencesCommand{
+		StudentID:   "dana",
+		Preferences: PreferenceUpdates{Timezone: &unknown},
+	})
+	assert.ErrorIs(t, err, student.ErrInvalidTimezone)
+}
@@ -0,0 +1,161 @@
+// Package cqrs provides the application-layer bus that dispatches commands
+// and queries to their handlers.
+//
+// Handlers are registered once per message type:
+//
+//	cqrs.Register(bus, leaderboardQuery)
+//
+// and executed through the bus, which runs every message through the same
+// middleware chain (logging, validation, panic recovery, transactions):
+//
+//	result, err := cqrs.Execute[*query.GetLeaderboardResult](ctx, bus, q)
+//
+// Interface code that wants a typed dependency takes a Handler and gets it
+// from the bus with Bind, so the concrete handler still fits in tests.
+package cqrs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// BUS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Errors returned by the bus.
+var (
+	// ErrNoHandler is returned when no handler is registered for a message.
+	ErrNoHandler = errors.New("cqrs: no handler registered")
+
+	// ErrResultType is returned when a handler's result is not of the
+	// requested type.
+	ErrResultType = errors.New("cqrs: unexpected result type")
+)
+
+// Handler handles messages of type C and returns results of type R.
+// Existing command and query handlers implement it as is.
+type Handler[C, R any] interface {
+	Handle(ctx context.Context, msg C) (R, error)
+}
+
+// HandlerFunc adapts a function to Handler.
+type HandlerFunc[C, R any] func(ctx context.Context, msg C) (R, error)
+
+// Handle calls f(ctx, msg).
+func (f HandlerFunc[C, R]) Handle(ctx context.Context, msg C) (R, error) {
+	return f(ctx, msg)
+}
+
+// Dispatch runs a message and returns its result.
+type Dispatch func(ctx context.Context, msg any) (any, error)
+
+// Middleware wraps the dispatch of every message. Middleware added first
+// runs outermost.
+type Middleware func(next Dispatch) Dispatch
+
+// Bus dispatches commands and queries to their registered handlers.
+type Bus struct {
+	mu         sync.RWMutex
+	handlers   map[reflect.Type]Dispatch
+	middleware []Middleware
+}
+
+// New creates a bus with the given middleware.
+func New(middleware ...Middleware) *Bus {
+	return &Bus{
+		handlers:   make(map[reflect.Type]Dispatch),
+		middleware: middleware,
+	}
+}
+
+// Use appends middleware to the chain.
+func (b *Bus) Use(middleware ...Middleware) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.middleware = append(b.middleware, middleware...)
+}
+
+// Register registers the handler of messages of type C. Registering a type
+// twice or a nil handler is a programming error and panics.
+func Register[C, R any](b *Bus, h Handler[C, R]) {
+	if v := reflect.ValueOf(h); h == nil || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		panic(fmt.Sprintf("cqrs: nil handler for %s", typeName(reflect.TypeFor[C]())))
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := reflect.TypeFor[C]()
+	if _, exists := b.handlers[key]; exists {
+		panic(fmt.Sprintf("cqrs: handler for %s registered twice", typeName(key)))
+	}
+	b.handlers[key] = func(ctx context.Context, msg any) (any, error) {
+		return h.Handle(ctx, msg.(C))
+	}
+}
+
+// Registered reports whether a handler is registered for messages of type C.
+func Registered[C any](b *Bus) bool {
+	if b == nil {
+		return false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	_, ok := b.handlers[reflect.TypeFor[C]()]
+	return ok
+}
+
+// Execute dispatches msg through the middleware to its handler and returns
+// the result as R.
+func Execute[R, C any](ctx context.Context, b *Bus, msg C) (R, error) {
+	var zero R
+	if b == nil {
+		return zero, fmt.Errorf("%w for %s", ErrNoHandler, Name(msg))
+	}
+
+	b.mu.RLock()
+	handler, ok := b.handlers[reflect.TypeFor[C]()]
+	chain := Dispatch(handler)
+	for i := len(b.middleware) - 1; i >= 0 && ok; i-- {
+		chain = b.middleware[i](chain)
+	}
+	b.mu.RUnlock()
+
+	if !ok {
+		return zero, fmt.Errorf("%w for %s", ErrNoHandler, Name(msg))
+	}
+
+	out, err := chain(ctx, msg)
+	if out == nil {
+		return zero, err
+	}
+	result, isR := out.(R)
+	if !isR {
+		return zero, fmt.Errorf("%w: %s returned %T", ErrResultType, Name(msg), out)
+	}
+	return result, err
+}
+
+// Bind returns a Handler that executes messages of type C through the bus.
+func Bind[C, R any](b *Bus) Handler[C, R] {
+	return HandlerFunc[C, R](func(ctx context.Context, msg C) (R, error) {
+		return Execute[R](ctx, b, msg)
+	})
+}
+
+// Name returns the name of a message type used in logs,
+// e.g. "query.GetLeaderboardQuery".
+func Name(msg any) string {
+	return typeName(reflect.TypeOf(msg))
+}
+
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return "<nil>"
+	}
+	return t.String()
+}
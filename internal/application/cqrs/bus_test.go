@@ -0,0 +1,166 @@
+package cqrs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+type greetQuery struct {
+	Name  string
+	Limit int
+}
+
+// Validate has a pointer receiver like most query Validate methods.
+func (q *greetQuery) Validate() error {
+	if q.Name == "" {
+		return errors.New("name is required")
+	}
+	if q.Limit == 0 {
+		q.Limit = 10
+	}
+	return nil
+}
+
+type greetResult struct {
+	Text string
+}
+
+type renameCommand struct {
+	To string
+}
+
+func (renameCommand) InTransaction() bool { return true }
+
+type recordingUnitOfWork struct {
+	events *[]string
+}
+
+func (u recordingUnitOfWork) Commit(context.Context) error {
+	*u.events = append(*u.events, "commit")
+	return nil
+}
+
+func (u recordingUnitOfWork) Rollback(context.Context) error {
+	*u.events = append(*u.events, "rollback")
+	return nil
+}
+
+func tracing(name string, events *[]string) Middleware {
+	return func(next Dispatch) Dispatch {
+		return func(ctx context.Context, msg any) (any, error) {
+			*events = append(*events, name+" before")
+			out, err := next(ctx, msg)
+			*events = append(*events, name+" after")
+			return out, err
+		}
+	}
+}
+
+func greeter(calls *int) Handler[greetQuery, *greetResult] {
+	return HandlerFunc[greetQuery, *greetResult](func(_ context.Context, q greetQuery) (*greetResult, error) {
+		*calls++
+		return &greetResult{Text: "hello, " + q.Name}, nil
+	})
+}
+
+func TestBus_ExecuteRunsMiddlewareInOrder(t *testing.T) {
+	var events []string
+	bus := New(tracing("outer", &events))
+	bus.Use(tracing("inner", &events))
+
+	calls := 0
+	Register(bus, greeter(&calls))
+
+	result, err := Execute[*greetResult](context.Background(), bus, greetQuery{Name: "dana"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello, dana", result.Text)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, []string{"outer before", "inner before", "inner after", "outer after"}, events)
+}
+
+func TestBus_ValidationRejectsBeforeTheHandler(t *testing.T) {
+	bus := New(Validation())
+	calls := 0
+	Register(bus, greeter(&calls))
+
+	_, err := Execute[*greetResult](context.Background(), bus, greetQuery{})
+	require.Error(t, err)
+	assert.True(t, shared.IsValidation(err))
+	assert.Zero(t, calls, "an invalid query never reaches the handler")
+
+	_, err = Execute[*greetResult](context.Background(), bus, greetQuery{Name: "dana"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestBus_UnregisteredAndMistypedMessages(t *testing.T) {
+	bus := New()
+	calls := 0
+	Register(bus, greeter(&calls))
+
+	_, err := Execute[*greetResult](context.Background(), bus, renameCommand{})
+	assert.ErrorIs(t, err, ErrNoHandler)
+	assert.False(t, Registered[renameCommand](bus))
+	assert.True(t, Registered[greetQuery](bus))
+
+	_, err = Execute[string](context.Background(), bus, greetQuery{Name: "dana"})
+	assert.ErrorIs(t, err, ErrResultType)
+
+	assert.Panics(t, func() { Register(bus, greeter(&calls)) }, "a type is registered once")
+}
+
+func TestBus_RecoveryAndLogging(t *testing.T) {
+	var logs bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	bus := New(Logging(log), Recovery(log))
+	Register(bus, HandlerFunc[greetQuery, *greetResult](func(context.Context, greetQuery) (*greetResult, error) {
+		panic("boom")
+	}))
+
+	_, err := Execute[*greetResult](context.Background(), bus, greetQuery{Name: "dana"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	assert.Contains(t, logs.String(), "cqrs handler panicked")
+	assert.Contains(t, logs.String(), "message=cqrs.greetQuery")
+	assert.Contains(t, logs.String(), "duration=")
+}
+
+func TestBus_TransactionCommitsOrRollsBack(t *testing.T) {
+	var events []string
+	bus := New(Transaction(func(context.Context) (UnitOfWork, error) {
+		events = append(events, "begin")
+		return recordingUnitOfWork{events: &events}, nil
+	}))
+
+	failing := errors.New("rename failed")
+	Register(bus, HandlerFunc[renameCommand, string](func(ctx context.Context, cmd renameCommand) (string, error) {
+		_, ok := UnitOfWorkFrom(ctx)
+		require.True(t, ok)
+		if cmd.To == "" {
+			return "", failing
+		}
+		return cmd.To, nil
+	}))
+	calls := 0
+	Register(bus, greeter(&calls))
+
+	_, err := Execute[string](context.Background(), bus, renameCommand{To: "dana"})
+	require.NoError(t, err)
+	_, err = Execute[string](context.Background(), bus, renameCommand{})
+	assert.ErrorIs(t, err, failing)
+	assert.Equal(t, []string{"begin", "commit", "begin", "rollback"}, events)
+
+	// Queries don't open transactions
+	_, err = Execute[*greetResult](context.Background(), bus, greetQuery{Name: "dana"})
+	require.NoError(t, err)
+	assert.Len(t, events, 4)
+}
@@ -0,0 +1,167 @@
+package cqrs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"runtime/debug"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MIDDLEWARE
+// Cross-cutting concerns applied uniformly to every command and query.
+// The usual order is Logging, Recovery, Validation, Transaction: the log line
+// covers recovered panics and rejected messages, and no transaction is begun
+// for a message that fails validation.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Logging logs every message with its name and duration: failures at warn
+// level, successes at debug level.
+func Logging(log *slog.Logger) Middleware {
+	if log == nil {
+		log = slog.Default()
+	}
+	return func(next Dispatch) Dispatch {
+		return func(ctx context.Context, msg any) (any, error) {
+			start := time.Now()
+			out, err := next(ctx, msg)
+
+			attrs := []any{
+				slog.String("message", Name(msg)),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if err != nil {
+				log.WarnContext(ctx, "cqrs message failed", append(attrs, slog.String("error", err.Error()))...)
+			} else {
+				log.DebugContext(ctx, "cqrs message handled", attrs...)
+			}
+			return out, err
+		}
+	}
+}
+
+// Validator is implemented by messages that validate their input.
+type Validator interface {
+	Validate() error
+}
+
+// Validation rejects messages whose Validate method fails before they reach
+// the handler. The error wraps shared.ErrValidation. Validate methods with a
+// pointer receiver run on a copy, so defaults they fill in don't leak back;
+// the handler still applies them itself.
+func Validation() Middleware {
+	return func(next Dispatch) Dispatch {
+		return func(ctx context.Context, msg any) (any, error) {
+			if v := validatorOf(msg); v != nil {
+				if err := v.Validate(); err != nil {
+					return nil, shared.WrapError("cqrs", Name(msg), shared.ErrValidation, err.Error(), err)
+				}
+			}
+			return next(ctx, msg)
+		}
+	}
+}
+
+func validatorOf(msg any) Validator {
+	if v, ok := msg.(Validator); ok {
+		return v
+	}
+	rv := reflect.ValueOf(msg)
+	if !rv.IsValid() || rv.Kind() == reflect.Ptr {
+		return nil
+	}
+	ptr := reflect.New(rv.Type())
+	ptr.Elem().Set(rv)
+	v, _ := ptr.Interface().(Validator)
+	return v
+}
+
+// Recovery turns a panicking handler into an error so that one broken
+// handler doesn't take the bot or the HTTP server down.
+func Recovery(log *slog.Logger) Middleware {
+	if log == nil {
+		log = slog.Default()
+	}
+	return func(next Dispatch) Dispatch {
+		return func(ctx context.Context, msg any) (out any, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.ErrorContext(ctx, "cqrs handler panicked",
+						slog.String("message", Name(msg)),
+						slog.Any("panic", r),
+						slog.String("stack", string(debug.Stack())),
+					)
+					out, err = nil, fmt.Errorf("cqrs: %s panicked: %v", Name(msg), r)
+				}
+			}()
+			return next(ctx, msg)
+		}
+	}
+}
+
+// UnitOfWork is a transaction begun for one command.
+type UnitOfWork interface {
+	// Commit commits the transaction.
+	Commit(ctx context.Context) error
+
+	// Rollback rolls the transaction back.
+	Rollback(ctx context.Context) error
+}
+
+// UnitOfWorkFactory begins units of work.
+type UnitOfWorkFactory func(ctx context.Context) (UnitOfWork, error)
+
+// Transactional is implemented by commands that run in a transaction.
+type Transactional interface {
+	InTransaction() bool
+}
+
+type unitOfWorkKey struct{}
+
+// UnitOfWorkFrom returns the unit of work of the command being handled.
+// Handlers type-assert it to the domain unit of work they need.
+func UnitOfWorkFrom(ctx context.Context) (UnitOfWork, bool) {
+	uow, ok := ctx.Value(unitOfWorkKey{}).(UnitOfWork)
+	return uow, ok
+}
+
+// Transaction runs every Transactional command in its own unit of work,
+// available to the handler through UnitOfWorkFrom. The unit of work commits
+// when the handler succeeds and rolls back when it fails. Other messages
+// run as is.
+func Transaction(begin UnitOfWorkFactory) Middleware {
+	return func(next Dispatch) Dispatch {
+		return func(ctx context.Context, msg any) (out any, err error) {
+			tx, ok := msg.(Transactional)
+			if !ok || !tx.InTransaction() {
+				return next(ctx, msg)
+			}
+
+			uow, err := begin(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("cqrs: %s: begin transaction: %w", Name(msg), err)
+			}
+
+			committed := false
+			defer func() {
+				if !committed {
+					_ = uow.Rollback(ctx)
+				}
+			}()
+
+			out, err = next(context.WithValue(ctx, unitOfWorkKey{}, uow), msg)
+			if err != nil {
+				return out, err
+			}
+			if err := uow.Commit(ctx); err != nil {
+				return nil, fmt.Errorf("cqrs: %s: commit: %w", Name(msg), err)
+			}
+			committed = true
+			return out, nil
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package eventhandler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/experiment"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// NOTIFICATION EXPERIMENTS
+// Выбор формулировки уведомления по идущему A/B эксперименту.
+//
+// Вариант студента детерминирован (experiment.Experiment.Assign), шаблон
+// варианта берётся из notification.VariantTemplate. Показ записывается
+// только после успешной отправки - иначе неотправленные уведомления
+// занижали бы долю активности варианта.
+// ═══════════════════════════════════════════════════════════════════════════
+
+// experimentsCacheTTL - как долго держим список идущих экспериментов,
+// чтобы не ходить в базу на каждое событие.
+const experimentsCacheTTL = time.Minute
+
+// ExperimentMessage - сообщение в варианте эксперимента.
+type ExperimentMessage struct {
+	// Experiment - эксперимент.
+	Experiment *experiment.Experiment
+
+	// Variant - вариант студента.
+	Variant string
+
+	// Text - отрендеренное сообщение.
+	Text string
+}
+
+// NotificationExperiments выбирает формулировки уведомлений по экспериментам.
+type NotificationExperiments struct {
+	repo     experiment.Repository
+	renderer *notification.Renderer
+	logger   *slog.Logger
+	now      func() time.Time
+
+	mu       sync.Mutex
+	running  []*experiment.Experiment
+	loadedAt time.Time
+}
+
+// NewNotificationExperiments создаёт выбор формулировок по экспериментам.
+func NewNotificationExperiments(repo experiment.Repository, logger *slog.Logger) *NotificationExperiments {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &NotificationExperiments{
+		repo:     repo,
+		renderer: notification.NewRenderer(),
+		logger:   logger.With("component", "notification_experiments"),
+		now:      time.Now,
+	}
+}
+
+// Render возвращает сообщение в варианте студента, если для типа уведомления
+// идёт эксперимент. При нескольких экспериментах на тип действует начатый
+// раньше. false - оставить обычную формулировку.
+func (e *NotificationExperiments) Render(
+	ctx context.Context,
+	notifType notification.NotificationType,
+	studentID string,
+	data notification.NotificationData,
+) (*ExperimentMessage, bool) {
+	now := e.now()
+	for _, exp := range e.runningAt(ctx, now) {
+		if exp.NotificationType != string(notifType) || !exp.IsRunning(now) {
+			continue
+		}
+
+		variant := exp.Assign(studentID)
+		tmpl, ok := notification.VariantTemplate(notifType, variant)
+		if !ok {
+			e.logger.Warn("experiment variant has no template",
+				"experiment", exp.Name,
+				"variant", variant,
+			)
+			return nil, false
+		}
+
+		text, err := e.renderer.Format(tmpl, data)
+		if err != nil {
+			e.logger.Warn("failed to render experiment variant",
+				"experiment", exp.Name,
+				"variant", variant,
+				"error", err,
+			)
+			return nil, false
+		}
+		return &ExperimentMessage{Experiment: exp, Variant: variant, Text: text}, true
+	}
+	return nil, false
+}
+
+// RecordExposure записывает показ варианта после успешной отправки.
+// Повторные показы тому же студенту не записываются.
+func (e *NotificationExperiments) RecordExposure(ctx context.Context, msg *ExperimentMessage, studentID, notificationID string) {
+	_, err := e.repo.RecordExposure(ctx, experiment.Assignment{
+		ExperimentID:   msg.Experiment.ID,
+		StudentID:      studentID,
+		Variant:        msg.Variant,
+		NotificationID: notificationID,
+		ExposedAt:      e.now().UTC(),
+	})
+	if err != nil {
+		e.logger.Warn("failed to record experiment exposure",
+			"experiment", msg.Experiment.Name,
+			"student_id", studentID,
+			"error", err,
+		)
+	}
+}
+
+// runningAt возвращает идущие эксперименты из кеша. Если база недоступна,
+// остаётся прежний список: эксперимент не должен мигать.
+func (e *NotificationExperiments) runningAt(ctx context.Context, now time.Time) []*experiment.Experiment {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.loadedAt.IsZero() && now.Sub(e.loadedAt) < experimentsCacheTTL {
+		return e.running
+	}
+
+	running, err := e.repo.ListRunning(ctx, now)
+	if err != nil {
+		e.logger.Warn("failed to load running experiments", "error", err)
+		return e.running
+	}
+	e.running, e.loadedAt = running, now
+	return running
+}
@@ -0,0 +1,86 @@
+package eventhandler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/experiment"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type fakeExperimentRepo struct {
+	experiment.Repository
+	running   []*experiment.Experiment
+	exposures []experiment.Assignment
+}
+
+func (f *fakeExperimentRepo) ListRunning(context.Context, time.Time) ([]*experiment.Experiment, error) {
+	return f.running, nil
+}
+
+func (f *fakeExperimentRepo) RecordExposure(_ context.Context, a experiment.Assignment) (bool, error) {
+	f.exposures = append(f.exposures, a)
+	return true, nil
+}
+
+type flakyNotifier struct {
+	fakeNotifier
+	fail bool
+}
+
+func (f *flakyNotifier) Send(ctx context.Context, n *notification.Notification) notification.DeliveryResult {
+	if f.fail {
+		return notification.NewFailureResult(notification.ChannelTypeTelegram, errors.New("telegram is down"), true)
+	}
+	return f.fakeNotifier.Send(ctx, n)
+}
+
+func TestOnRankChangedHandler_RecordsExposureOnlyWhenSent(t *testing.T) {
+	now := time.Date(2026, time.May, 4, 12, 0, 0, 0, time.UTC)
+	exp, err := experiment.NewExperiment("exp-1", "rank_up_wording", string(notification.NotificationTypeRankUp), []experiment.Variant{
+		{Name: notification.VariantEncouraging, Weight: 1},
+		{Name: notification.VariantCompetitive, Weight: 1},
+	}, now.Add(-time.Hour), nil, now)
+	require.NoError(t, err)
+
+	repo := &fakeExperimentRepo{running: []*experiment.Experiment{exp}}
+	experiments := NewNotificationExperiments(repo, nil)
+	experiments.now = func() time.Time { return now }
+
+	students := &fakeStudentRepo{students: map[string]*student.Student{
+		"ali": newBuddyStudent("ali", 1, student.OnlineStateOnline),
+	}}
+	notifier := &flakyNotifier{fail: true}
+	config := DefaultRankChangedConfig()
+	config.QuietHoursEnabled = false
+	h := NewOnRankChangedHandler(students, notifier, nil, nil, nil, config).WithExperiments(experiments)
+
+	event := shared.NewRankChangedEvent("ali", 30, 20, "2024")
+
+	// Неотправленное уведомление - не показ
+	require.NoError(t, h.Handle(event))
+	assert.Empty(t, repo.exposures)
+
+	notifier.fail = false
+	require.NoError(t, h.Handle(event))
+	require.Len(t, notifier.sent, 1)
+	require.Len(t, repo.exposures, 1)
+
+	variant := exp.Assign("ali")
+	assert.Equal(t, experiment.Assignment{
+		ExperimentID:   "exp-1",
+		StudentID:      "ali",
+		Variant:        variant,
+		NotificationID: string(notifier.sent[0].ID),
+		ExposedAt:      now,
+	}, repo.exposures[0])
+	assert.Equal(t, variant, notifier.sent[0].Metadata["variant"])
+	assert.Contains(t, notifier.sent[0].Message, "#20")
+}
@@ -0,0 +1,119 @@
+package eventhandler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/featureflag"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// PERSONAL BEST HANDLER
+// Личные рекорды: поздравляем с побитым рекордом.
+// ═══════════════════════════════════════════════════════════════════════════
+
+// OnPersonalBestHandler поздравляет студента с новым личным рекордом.
+type OnPersonalBestHandler struct {
+	// Dependencies
+	studentRepo student.Repository
+	notifier    Notifier
+
+	// Logger
+	logger *slog.Logger
+
+	// now - источник времени (подменяется в тестах).
+	now func() time.Time
+}
+
+// NewOnPersonalBestHandler создаёт новый обработчик личных рекордов.
+func NewOnPersonalBestHandler(
+	studentRepo student.Repository,
+	notifier Notifier,
+	logger *slog.Logger,
+) *OnPersonalBestHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &OnPersonalBestHandler{
+		studentRepo: studentRepo,
+		notifier:    notifier,
+		logger:      logger.With("handler", "on_personal_best"),
+		now:         time.Now,
+	}
+}
+
+// Handle обрабатывает событие нового рекорда.
+// Реализует интерфейс shared.EventHandler.
+func (h *OnPersonalBestHandler) Handle(event shared.Event) error {
+	ctx := context.Background()
+
+	bestEvent, ok := event.(shared.PersonalBestSetEvent)
+	if !ok {
+		h.logger.Warn("received non-PersonalBestSetEvent",
+			"event_type", event.EventType(),
+		)
+		return nil
+	}
+
+	stud, err := h.studentRepo.GetByID(ctx, bestEvent.StudentID)
+	if err != nil {
+		return fmt.Errorf("get student: %w", err)
+	}
+
+	if !featureflag.Enabled(ctx, settings.FeaturePersonalBests, stud.ID, string(stud.Cohort)) {
+		h.logger.Debug("personal best notifications are off for student", "student_id", stud.ID)
+		return nil
+	}
+
+	if !stud.CanReceiveNotification(string(notification.NotificationTypePersonalBest), h.now()) {
+		h.logger.Debug("skipping personal best notification", "student_id", stud.ID)
+		return nil
+	}
+
+	best := student.PersonalBest{
+		StudentID:  bestEvent.StudentID,
+		Metric:     student.PersonalBestMetric(bestEvent.Metric),
+		Value:      bestEvent.Value,
+		AchievedOn: bestEvent.AchievedOn,
+	}
+	message := fmt.Sprintf("%s\n\nПрошлый рекорд: %s.",
+		best.Message(), best.Metric.FormatValue(bestEvent.PreviousValue))
+
+	notif, err := notification.NewNotification(notification.NewNotificationParams{
+		ID:             notification.NotificationID(generateID()),
+		Type:           notification.NotificationTypePersonalBest,
+		RecipientID:    notification.RecipientID(stud.ID),
+		TelegramChatID: notification.TelegramChatID(stud.TelegramID),
+		Message:        message,
+	})
+	if err != nil {
+		return fmt.Errorf("create personal best notification: %w", err)
+	}
+	notif.SetMetadata("metric", bestEvent.Metric)
+	notif.SetMetadata("value", strconv.Itoa(bestEvent.Value))
+
+	result := h.notifier.Send(ctx, notif)
+	if result.Skipped() {
+		h.logger.Debug("personal best notification skipped", "student_id", stud.ID, "reason", result.Error)
+		return nil
+	}
+	if !result.Success {
+		return fmt.Errorf("send personal best notification: %w", result.Error)
+	}
+
+	h.logger.Info("personal best notification sent",
+		"student_id", stud.ID,
+		"metric", bestEvent.Metric,
+		"value", bestEvent.Value,
+	)
+
+	return nil
+}
@@ -15,8 +15,10 @@ import (
 
 	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
 	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
 	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/featureflag"
 )
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -34,8 +36,12 @@ import (
 type OnRankChangedHandler struct {
 	// Dependencies (интерфейсы из domain layer)
 	studentRepo        student.Repository
-	notificationSender notification.NotificationSender
+	notificationSender Notifier
 	leaderboardCache   leaderboard.LeaderboardCache
+	cohortSettings     settings.Reader
+	experiments        *NotificationExperiments
+	triggers           TriggerEvaluator
+	history            NotificationHistory
 
 	// Logger для структурированного логирования
 	logger *slog.Logger
@@ -55,6 +61,8 @@ type RankChangedConfig struct {
 	TopNMilestones []int
 
 	// NotifyOnOvertake — уведомлять ли, когда кто-то обогнал студента.
+	// Глобальное значение; поток может переопределить его настройкой
+	// competitor_close_enabled.
 	NotifyOnOvertake bool
 
 	// CooldownPeriod — минимальный интервал между уведомлениями одному студенту.
@@ -75,27 +83,67 @@ func DefaultRankChangedConfig() RankChangedConfig {
 	}
 }
 
+// TriggerEvaluator вычисляет правила триггеров из таблицы trigger_rules.
+// Реализуется service.NotificationServiceStub.
+type TriggerEvaluator interface {
+	EvaluateTriggers(ctx context.Context, triggerCtx *notification.TriggerContext) ([]*notification.TriggerRule, error)
+}
+
+// NotificationHistory хранит отправленные уведомления для центра
+// уведомлений (/notifications). Реализуется postgres.NotificationRepository.
+type NotificationHistory interface {
+	Save(ctx context.Context, n *notification.Notification) error
+}
+
 // NewOnRankChangedHandler создаёт новый обработчик события изменения ранга.
 func NewOnRankChangedHandler(
 	studentRepo student.Repository,
-	notificationSender notification.NotificationSender,
+	notificationSender Notifier,
 	leaderboardCache leaderboard.LeaderboardCache,
+	cohortSettings settings.Reader,
 	logger *slog.Logger,
 	config RankChangedConfig,
 ) *OnRankChangedHandler {
 	if logger == nil {
 		logger = slog.Default()
 	}
+	if cohortSettings == nil {
+		cohortSettings = settings.Defaults{}
+	}
 
 	return &OnRankChangedHandler{
 		studentRepo:        studentRepo,
 		notificationSender: notificationSender,
 		leaderboardCache:   leaderboardCache,
+		cohortSettings:     cohortSettings,
 		logger:             logger.With("handler", "on_rank_changed"),
 		config:             config,
 	}
 }
 
+// WithExperiments включает формулировки из A/B экспериментов.
+func (h *OnRankChangedHandler) WithExperiments(experiments *NotificationExperiments) *OnRankChangedHandler {
+	h.experiments = experiments
+	return h
+}
+
+// WithTriggerRules включает правила триггеров: о повышении уведомляем,
+// только если срабатывает включённое правило rank_up, поэтому порог
+// настраивается в админке без деплоя. Без правил действует
+// MinRankChangeForNotification.
+func (h *OnRankChangedHandler) WithTriggerRules(triggers TriggerEvaluator) *OnRankChangedHandler {
+	h.triggers = triggers
+	return h
+}
+
+// WithHistory включает запись уведомлений о ранге в центр уведомлений:
+// доставленные и заглушенные (/mute) уведомления сохраняются, чтобы
+// студент мог просмотреть их в /notifications.
+func (h *OnRankChangedHandler) WithHistory(history NotificationHistory) *OnRankChangedHandler {
+	h.history = history
+	return h
+}
+
 // Handle обрабатывает событие изменения ранга.
 // Реализует интерфейс shared.EventHandler.
 func (h *OnRankChangedHandler) Handle(event shared.Event) error {
@@ -128,8 +176,13 @@ func (h *OnRankChangedHandler) Handle(event shared.Event) error {
 		return fmt.Errorf("get student: %w", err)
 	}
 
+	// Уведомления о смене ранга выкатываются флагом
+	if !featureflag.Enabled(ctx, settings.FeatureRankChangeNotifications, studentEntity.ID, string(studentEntity.Cohort)) {
+		return nil
+	}
+
 	// 2. Проверяем, можно ли отправить уведомление
-	if !h.shouldNotify(studentEntity, rankEvent) {
+	if !h.shouldNotify(ctx, studentEntity, rankEvent) {
 		h.logger.Debug("skipping notification",
 			"reason", "notification conditions not met",
 			"student_id", rankEvent.StudentID,
@@ -174,6 +227,7 @@ func (h *OnRankChangedHandler) Handle(event shared.Event) error {
 
 // shouldNotify определяет, нужно ли отправлять уведомление.
 func (h *OnRankChangedHandler) shouldNotify(
+	ctx context.Context,
 	studentEntity *student.Student,
 	event shared.RankChangedEvent,
 ) bool {
@@ -199,6 +253,9 @@ func (h *OnRankChangedHandler) shouldNotify(
 	if absChange < 0 {
 		absChange = -absChange
 	}
+	if event.MovedUp() && h.triggers != nil {
+		return h.rankUpRuleFires(ctx, studentEntity, absChange)
+	}
 	if absChange < h.config.MinRankChangeForNotification {
 		return false
 	}
@@ -206,6 +263,34 @@ func (h *OnRankChangedHandler) shouldNotify(
 	return true
 }
 
+// rankUpRuleFires проверяет, срабатывает ли правило о повышении в рейтинге.
+// Если правила не загрузились, действует порог из конфигурации.
+func (h *OnRankChangedHandler) rankUpRuleFires(
+	ctx context.Context,
+	studentEntity *student.Student,
+	change int,
+) bool {
+	triggerCtx := notification.NewTriggerContext(studentEntity.ID, notification.TelegramChatID(studentEntity.TelegramID))
+	triggerCtx.SetValue(notification.ConditionTypeRankChange, change)
+	triggerCtx.SetUserPreference("rank_changes", studentEntity.Preferences.RankChanges)
+
+	rules, err := h.triggers.EvaluateTriggers(ctx, triggerCtx)
+	if err != nil {
+		h.logger.Warn("failed to evaluate trigger rules, using configured threshold",
+			"student_id", studentEntity.ID,
+			"error", err,
+		)
+		return change >= h.config.MinRankChangeForNotification
+	}
+
+	for _, rule := range rules {
+		if rule.NotificationType == notification.NotificationTypeRankUp {
+			return true
+		}
+	}
+	return false
+}
+
 // sendNotification формирует и отправляет уведомление о изменении ранга.
 func (h *OnRankChangedHandler) sendNotification(
 	ctx context.Context,
@@ -215,6 +300,7 @@ func (h *OnRankChangedHandler) sendNotification(
 	var notificationType notification.NotificationType
 	var message string
 	var priority notification.Priority
+	experimentAllowed := true
 
 	if event.MovedUp() {
 		// Студент поднялся в рейтинге 🚀
@@ -227,12 +313,30 @@ func (h *OnRankChangedHandler) sendNotification(
 		notificationType = notification.NotificationTypeRankDown
 		priority = notification.PriorityLow // Понижаем приоритет, чтобы не расстраивать
 
-		message = h.formatRankDownMessage(event)
+		notifyOnOvertake := h.cohortSettings.GetBool(ctx, string(studentEntity.Cohort),
+			settings.KeyCompetitorCloseEnabled, h.config.NotifyOnOvertake) &&
+			featureflag.Enabled(ctx, settings.FeatureCompetitorClose, studentEntity.ID, string(studentEntity.Cohort))
+		message = h.formatRankDownMessage(event, notifyOnOvertake)
+		// Соревновательная формулировка - то же "соперник рядом": без него
+		// эксперимент не проводим
+		experimentAllowed = notifyOnOvertake
 	} else {
 		// Ранг не изменился — не отправляем
 		return nil
 	}
 
+	var variant *ExperimentMessage
+	if h.experiments != nil && experimentAllowed {
+		rankChange := event.RankChange
+		if rankChange < 0 {
+			rankChange = -rankChange
+		}
+		data := notification.NotificationData{OldRank: event.OldRank, NewRank: event.NewRank, RankChange: rankChange}
+		if msg, ok := h.experiments.Render(ctx, notificationType, studentEntity.ID, data); ok {
+			message, variant = msg.Text, msg
+		}
+	}
+
 	// Создаём уведомление
 	notif, err := notification.NewNotification(notification.NewNotificationParams{
 		ID:             notification.NotificationID(generateID()),
@@ -251,13 +355,25 @@ func (h *OnRankChangedHandler) sendNotification(
 	notif.SetMetadata("new_rank", fmt.Sprintf("%d", event.NewRank))
 	notif.SetMetadata("rank_change", fmt.Sprintf("%d", event.RankChange))
 	notif.SetMetadata("cohort", event.Cohort)
+	if variant != nil {
+		notif.SetMetadata("experiment", variant.Experiment.Name)
+		notif.SetMetadata("variant", variant.Variant)
+	}
 
-	// Отправляем уведомление
-	result := h.notificationSender.Send(ctx, notif)
+	// Отправляем уведомление (заглушку /mute rank проверяет отправитель)
+	result := h.deliver(ctx, notif)
+	if result.Skipped() {
+		return nil
+	}
 	if !result.Success {
 		return fmt.Errorf("send notification: %w", result.Error)
 	}
 
+	// Показ засчитывается только отправленному уведомлению
+	if variant != nil {
+		h.experiments.RecordExposure(ctx, variant, studentEntity.ID, string(notif.ID))
+	}
+
 	h.logger.Debug("notification sent",
 		"notification_id", notif.ID,
 		"type", notificationType,
@@ -267,6 +383,41 @@ func (h *OnRankChangedHandler) sendNotification(
 	return nil
 }
 
+// deliver отправляет уведомление и записывает его в центр уведомлений.
+// Неудачная отправка не записывается; ошибка записи только логируется.
+func (h *OnRankChangedHandler) deliver(ctx context.Context, notif *notification.Notification) notification.DeliveryResult {
+	result := h.notificationSender.Send(ctx, notif)
+	if h.history == nil {
+		return result
+	}
+
+	var err error
+	switch {
+	case result.Skipped():
+		reason := "skipped"
+		if result.Error != nil {
+			reason = result.Error.Error()
+		}
+		err = notif.MarkSkipped(reason)
+	case result.Success:
+		if err = notif.MarkSending(); err == nil {
+			err = notif.MarkDelivered()
+		}
+	default:
+		return result
+	}
+	if err == nil {
+		err = h.history.Save(ctx, notif)
+	}
+	if err != nil {
+		h.logger.Warn("failed to record notification",
+			"notification_id", notif.ID,
+			"error", err,
+		)
+	}
+	return result
+}
+
 // formatRankUpMessage формирует сообщение о повышении в рейтинге.
 // Философия: мотивировать, признавать усилия, но не хвастаться.
 func (h *OnRankChangedHandler) formatRankUpMessage(event shared.RankChangedEvent) string {
@@ -294,13 +445,14 @@ func (h *OnRankChangedHandler) formatRankUpMessage(event shared.RankChangedEvent
 
 // formatRankDownMessage формирует сообщение о понижении в рейтинге.
 // Философия: мягко подать, предложить поддержку, не демотивировать.
-func (h *OnRankChangedHandler) formatRankDownMessage(event shared.RankChangedEvent) string {
+// notifyOnOvertake — включены ли для потока сообщения "соперник рядом".
+func (h *OnRankChangedHandler) formatRankDownMessage(event shared.RankChangedEvent, notifyOnOvertake bool) string {
 	emoji := notification.NotificationTypeRankDown.Emoji()
 	change := -event.RankChange // RankChange отрицательный при понижении
 
 	// Мягкие формулировки, фокус на возможности улучшить
 	switch {
-	case event.Overtook != "" && h.config.NotifyOnOvertake:
+	case event.Overtook != "" && notifyOnOvertake:
 		return fmt.Sprintf("%s Кто-то наступает на пятки! Позиция #%d. Время поднажать?",
 			emoji, event.NewRank)
 
@@ -372,8 +524,8 @@ func (h *OnRankChangedHandler) sendTopEnteredNotification(
 	notif.SetMetadata("top_n", fmt.Sprintf("%d", topN))
 	notif.SetMetadata("new_rank", fmt.Sprintf("%d", newRank))
 
-	result := h.notificationSender.Send(ctx, notif)
-	if !result.Success {
+	result := h.deliver(ctx, notif)
+	if !result.Success && !result.Skipped() {
 		return fmt.Errorf("send notification: %w", result.Error)
 	}
 
@@ -408,8 +560,8 @@ func (h *OnRankChangedHandler) sendTopLeftNotification(
 	notif.SetMetadata("top_n", fmt.Sprintf("%d", topN))
 	notif.SetMetadata("new_rank", fmt.Sprintf("%d", newRank))
 
-	result := h.notificationSender.Send(ctx, notif)
-	if !result.Success {
+	result := h.deliver(ctx, notif)
+	if !result.Success && !result.Skipped() {
 		return fmt.Errorf("send notification: %w", result.Error)
 	}
 
@@ -0,0 +1,122 @@
+package eventhandler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ruleEvaluator вычисляет заданные правила, как NotificationServiceStub.
+type ruleEvaluator struct {
+	rules []*notification.TriggerRule
+	err   error
+	calls int
+}
+
+func (e *ruleEvaluator) EvaluateTriggers(_ context.Context, triggerCtx *notification.TriggerContext) ([]*notification.TriggerRule, error) {
+	e.calls++
+	if e.err != nil {
+		return nil, e.err
+	}
+	var matched []*notification.TriggerRule
+	for _, rule := range e.rules {
+		if rule.Evaluate(triggerCtx).ShouldTrigger {
+			matched = append(matched, rule)
+		}
+	}
+	return matched, nil
+}
+
+func TestOnRankChangedHandler_RankUpRuleSetsThreshold(t *testing.T) {
+	rule, err := notification.NewRankUpRule(notification.BuiltinRankUpRuleID, 10)
+	require.NoError(t, err)
+	evaluator := &ruleEvaluator{rules: []*notification.TriggerRule{rule}}
+
+	students := &fakeStudentRepo{students: map[string]*student.Student{
+		"ali": newBuddyStudent("ali", 1, student.OnlineStateOnline),
+	}}
+	notifier := &fakeNotifier{}
+	config := DefaultRankChangedConfig()
+	config.QuietHoursEnabled = false
+	h := NewOnRankChangedHandler(students, notifier, nil, nil, nil, config).WithTriggerRules(evaluator)
+
+	// Порог правила (10), а не конфигурации (3)
+	require.NoError(t, h.Handle(shared.NewRankChangedEvent("ali", 30, 25, "2024")))
+	assert.Empty(t, notifier.sent)
+
+	require.NoError(t, h.Handle(shared.NewRankChangedEvent("ali", 30, 18, "2024")))
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, notification.NotificationTypeRankUp, notifier.sent[0].Type)
+
+	// Выключенное правило отключает уведомления о повышении
+	rule.Disable()
+	require.NoError(t, h.Handle(shared.NewRankChangedEvent("ali", 30, 18, "2024")))
+	assert.Len(t, notifier.sent, 1)
+
+	// Понижение правилами не проверяется
+	calls := evaluator.calls
+	require.NoError(t, h.Handle(shared.NewRankChangedEvent("ali", 18, 25, "2024")))
+	assert.Len(t, notifier.sent, 2)
+	assert.Equal(t, calls, evaluator.calls)
+
+	// Правила не загрузились - действует порог конфигурации
+	evaluator.err = errors.New("connection refused")
+	require.NoError(t, h.Handle(shared.NewRankChangedEvent("ali", 30, 25, "2024")))
+	assert.Len(t, notifier.sent, 3)
+}
+
+// memoryHistory хранит уведомления, как таблица notifications.
+type memoryHistory struct {
+	saved []*notification.Notification
+}
+
+func (m *memoryHistory) Save(_ context.Context, n *notification.Notification) error {
+	m.saved = append(m.saved, n)
+	return nil
+}
+
+// mutingNotifier пропускает уведомления, как MuteFilter при /mute rank.
+type mutingNotifier struct{}
+
+func (mutingNotifier) Send(context.Context, *notification.Notification) notification.DeliveryResult {
+	return notification.NewSkippedResult(notification.ChannelTypeTelegram, notification.ErrRecipientMuted)
+}
+
+func TestOnRankChangedHandler_RecordsNotificationHistory(t *testing.T) {
+	students := &fakeStudentRepo{students: map[string]*student.Student{
+		"ali": newBuddyStudent("ali", 1, student.OnlineStateOnline),
+	}}
+	history := &memoryHistory{}
+	notifier := &flakyNotifier{}
+	config := DefaultRankChangedConfig()
+	config.QuietHoursEnabled = false
+	h := NewOnRankChangedHandler(students, notifier, nil, nil, nil, config).WithHistory(history)
+
+	// Повышение и вход в топ-10 - два доставленных уведомления
+	require.NoError(t, h.Handle(shared.NewRankChangedEvent("ali", 14, 9, "2024")))
+	require.Len(t, history.saved, 2)
+	assert.Equal(t, notification.NotificationTypeRankUp, history.saved[0].Type)
+	assert.Equal(t, notification.NotificationTypeEnteredTop, history.saved[1].Type)
+	for _, n := range history.saved {
+		assert.Equal(t, notification.StatusDelivered, n.Status)
+		assert.NotNil(t, n.DeliveredAt)
+	}
+
+	// Неудачная отправка в центр уведомлений не попадает
+	notifier.fail = true
+	require.NoError(t, h.Handle(shared.NewRankChangedEvent("ali", 30, 20, "2024")))
+	assert.Len(t, history.saved, 2)
+
+	// Заглушённое уведомление сохраняется, чтобы его можно было прочитать
+	h = NewOnRankChangedHandler(students, mutingNotifier{}, nil, nil, nil, config).WithHistory(history)
+	require.NoError(t, h.Handle(shared.NewRankChangedEvent("ali", 30, 20, "2024")))
+	require.Len(t, history.saved, 3)
+	assert.Equal(t, notification.StatusSkipped, history.saved[2].Status)
+}
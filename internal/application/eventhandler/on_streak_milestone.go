@@ -0,0 +1,198 @@
+package eventhandler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/sharecard"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// STREAK MILESTONE HANDLER
+// Поздравляет с вехой серии (7, 14, 30, 60, 100 дней) и прикладывает
+// карточку, которой можно поделиться. Если карточку не удалось
+// нарисовать или отправить, поздравление уходит обычным сообщением.
+// ═══════════════════════════════════════════════════════════════════════════
+
+// PhotoNotifier отправляет уведомления, в том числе с картинкой: текст
+// уведомления становится подписью к ней.
+type PhotoNotifier interface {
+	Notifier
+	SendWithPhoto(ctx context.Context, notification *notification.Notification, filename string, photo []byte) notification.DeliveryResult
+}
+
+// CardRenderer рисует карточку вехи в PNG.
+type CardRenderer interface {
+	Render(ctx context.Context, card sharecard.Card) ([]byte, error)
+}
+
+// OnStreakMilestoneHandler поздравляет студента с вехой серии.
+type OnStreakMilestoneHandler struct {
+	// Dependencies
+	studentRepo student.Repository
+	cards       CardRenderer
+	notifier    PhotoNotifier
+
+	// Logger
+	logger *slog.Logger
+
+	// now - источник времени (подменяется в тестах).
+	now func() time.Time
+}
+
+// NewOnStreakMilestoneHandler создаёт обработчик вех серии.
+// Без cards (nil) поздравления отправляются без карточки.
+func NewOnStreakMilestoneHandler(
+	studentRepo student.Repository,
+	cards CardRenderer,
+	notifier PhotoNotifier,
+	logger *slog.Logger,
+) *OnStreakMilestoneHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &OnStreakMilestoneHandler{
+		studentRepo: studentRepo,
+		cards:       cards,
+		notifier:    notifier,
+		logger:      logger.With("handler", "on_streak_milestone"),
+		now:         time.Now,
+	}
+}
+
+// Handle обрабатывает событие вехи серии.
+// Реализует интерфейс shared.EventHandler.
+func (h *OnStreakMilestoneHandler) Handle(event shared.Event) error {
+	ctx := context.Background()
+
+	milestoneEvent, ok := event.(shared.StreakMilestoneReachedEvent)
+	if !ok {
+		h.logger.Warn("received non-StreakMilestoneReachedEvent",
+			"event_type", event.EventType(),
+		)
+		return nil
+	}
+
+	stud, err := h.studentRepo.GetByID(ctx, milestoneEvent.StudentID)
+	if err != nil {
+		return fmt.Errorf("get student: %w", err)
+	}
+
+	if !stud.CanReceiveNotification(string(notification.NotificationTypeStreakMilestone), h.now()) {
+		h.logger.Debug("skipping streak milestone notification", "student_id", stud.ID)
+		return nil
+	}
+
+	message := streakMilestoneMessage(milestoneEvent.Milestone)
+	result, err := h.sendCard(ctx, stud, milestoneEvent.Milestone, message+"\n\n"+streakCardHint)
+	if err != nil {
+		h.logger.Warn("failed to send streak milestone card, sending text",
+			"student_id", stud.ID,
+			"milestone", milestoneEvent.Milestone,
+			"error", err,
+		)
+		if result, err = h.sendText(ctx, stud, milestoneEvent.Milestone, message); err != nil {
+			return err
+		}
+	}
+	if result.Skipped() {
+		h.logger.Debug("streak milestone notification skipped", "student_id", stud.ID, "reason", result.Error)
+		return nil
+	}
+
+	h.logger.Info("streak milestone notification sent",
+		"student_id", stud.ID,
+		"milestone", milestoneEvent.Milestone,
+	)
+
+	return nil
+}
+
+// sendCard рисует карточку и отправляет её с поздравлением в подписи.
+func (h *OnStreakMilestoneHandler) sendCard(ctx context.Context, stud *student.Student, milestone int, caption string) (notification.DeliveryResult, error) {
+	if h.cards == nil {
+		return notification.DeliveryResult{}, fmt.Errorf("share cards are not configured")
+	}
+
+	png, err := h.cards.Render(ctx, sharecard.Card{
+		Name:   stud.DisplayName,
+		Streak: milestone,
+		Cohort: string(stud.Cohort),
+	})
+	if err != nil {
+		return notification.DeliveryResult{}, fmt.Errorf("render card: %w", err)
+	}
+
+	notif, err := newStreakMilestoneNotification(stud, milestone, caption)
+	if err != nil {
+		return notification.DeliveryResult{}, err
+	}
+
+	filename := fmt.Sprintf("streak-%d.png", milestone)
+	result := h.notifier.SendWithPhoto(ctx, notif, filename, png)
+	if !result.Success && !result.Skipped() {
+		return result, fmt.Errorf("send streak milestone card: %w", result.Error)
+	}
+	return result, nil
+}
+
+// sendText отправляет поздравление обычным уведомлением.
+func (h *OnStreakMilestoneHandler) sendText(ctx context.Context, stud *student.Student, milestone int, message string) (notification.DeliveryResult, error) {
+	notif, err := newStreakMilestoneNotification(stud, milestone, message)
+	if err != nil {
+		return notification.DeliveryResult{}, err
+	}
+
+	result := h.notifier.Send(ctx, notif)
+	if !result.Success && !result.Skipped() {
+		return result, fmt.Errorf("send streak milestone notification: %w", result.Error)
+	}
+	return result, nil
+}
+
+// newStreakMilestoneNotification создаёт уведомление о вехе серии.
+func newStreakMilestoneNotification(stud *student.Student, milestone int, message string) (*notification.Notification, error) {
+	notif, err := notification.NewNotification(notification.NewNotificationParams{
+		ID:             notification.NotificationID(generateID()),
+		Type:           notification.NotificationTypeStreakMilestone,
+		RecipientID:    notification.RecipientID(stud.ID),
+		TelegramChatID: notification.TelegramChatID(stud.TelegramID),
+		Message:        message,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create streak milestone notification: %w", err)
+	}
+	notif.SetMetadata("milestone", strconv.Itoa(milestone))
+	return notif, nil
+}
+
+// streakMilestoneMessage возвращает поздравление с вехой.
+func streakMilestoneMessage(milestone int) string {
+	var title string
+	switch milestone {
+	case 7:
+		title = "🔥 Неделя без пропусков!"
+	case 14:
+		title = "🔥 Две недели подряд!"
+	case 30:
+		title = "💪 Целый месяц подряд!"
+	case 60:
+		title = "⚡ Два месяца подряд!"
+	case 100:
+		title = "🏆 Сто дней подряд!"
+	default:
+		title = "🎯 Новая веха серии!"
+	}
+
+	return fmt.Sprintf("%s\n\nТвоя серия - %d дней активности подряд.", title, milestone)
+}
+
+// streakCardHint дописывается к подписи карточки.
+const streakCardHint = "Сохрани карточку и поделись с друзьями!"
@@ -0,0 +1,240 @@
+package eventhandler
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// ON STUDENT ONLINE HANDLER
+// Сообщает напарникам и менторам, что студент появился онлайн.
+//
+// Философия "От конкуренции к сотрудничеству":
+// - Учиться вместе проще, когда знаешь, что напарник рядом
+// - Уведомление редкое: не чаще раза в несколько часов на получателя
+// - Не будим ночью и не пишем тем, кто сам сейчас не за компьютером
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Notifier доставляет одно уведомление.
+// Подмножество notification.NotificationSender.
+type Notifier interface {
+	Send(ctx context.Context, notification *notification.Notification) notification.DeliveryResult
+}
+
+// OnStudentOnlineHandler обрабатывает переход студента оффлайн → онлайн.
+type OnStudentOnlineHandler struct {
+	// Dependencies
+	studentRepo    student.Repository
+	connectionRepo social.ConnectionRepository
+	cooldowns      notification.CooldownStore
+	notifier       Notifier
+
+	// Logger
+	logger *slog.Logger
+
+	// Configuration
+	config StudentOnlineConfig
+
+	// now - источник времени (подменяется в тестах).
+	now func() time.Time
+}
+
+// StudentOnlineConfig содержит конфигурацию обработчика.
+type StudentOnlineConfig struct {
+	// Cooldown — минимальный интервал между уведомлениями одному получателю.
+	Cooldown time.Duration
+
+	// ConnectionTypes — типы связей, участникам которых отправляется уведомление.
+	ConnectionTypes []social.ConnectionType
+}
+
+// DefaultStudentOnlineConfig возвращает конфигурацию по умолчанию.
+func DefaultStudentOnlineConfig() StudentOnlineConfig {
+	return StudentOnlineConfig{
+		Cooldown: 4 * time.Hour,
+		ConnectionTypes: []social.ConnectionType{
+			social.ConnectionTypeStudyBuddy,
+			social.ConnectionTypeMentor,
+		},
+	}
+}
+
+// NewOnStudentOnlineHandler создаёт новый обработчик события "студент онлайн".
+func NewOnStudentOnlineHandler(
+	studentRepo student.Repository,
+	connectionRepo social.ConnectionRepository,
+	cooldowns notification.CooldownStore,
+	notifier Notifier,
+	logger *slog.Logger,
+	config StudentOnlineConfig,
+) *OnStudentOnlineHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &OnStudentOnlineHandler{
+		studentRepo:    studentRepo,
+		connectionRepo: connectionRepo,
+		cooldowns:      cooldowns,
+		notifier:       notifier,
+		logger:         logger.With("handler", "on_student_online"),
+		config:         config,
+		now:            time.Now,
+	}
+}
+
+// Handle обрабатывает событие перехода в онлайн.
+// Реализует интерфейс shared.EventHandler.
+func (h *OnStudentOnlineHandler) Handle(event shared.Event) error {
+	ctx := context.Background()
+
+	onlineEvent, ok := event.(shared.StudentWentOnlineEvent)
+	if !ok {
+		h.logger.Warn("received non-StudentWentOnlineEvent",
+			"event_type", event.EventType(),
+		)
+		return nil
+	}
+
+	// 1. Студент, который появился онлайн
+	buddy, err := h.studentRepo.GetByID(ctx, onlineEvent.StudentID)
+	if err != nil {
+		return fmt.Errorf("get student: %w", err)
+	}
+
+	// 2. Его напарники и менторы
+	conns, err := h.connectionRepo.GetActiveByStudentID(ctx, social.StudentID(buddy.ID))
+	if err != nil {
+		return fmt.Errorf("get connections: %w", err)
+	}
+
+	notified := make(map[social.StudentID]bool)
+	for _, conn := range conns {
+		if !conn.IsActive() || !h.isNotifiedType(conn.Type) {
+			continue
+		}
+
+		counterpartyID := conn.GetOtherStudent(social.StudentID(buddy.ID))
+		if counterpartyID == social.StudentID(buddy.ID) || notified[counterpartyID] {
+			continue
+		}
+		notified[counterpartyID] = true
+
+		h.notifyCounterparty(ctx, buddy, string(counterpartyID))
+	}
+
+	return nil
+}
+
+// notifyCounterparty отправляет уведомление одному получателю, если это уместно.
+func (h *OnStudentOnlineHandler) notifyCounterparty(ctx context.Context, buddy *student.Student, counterpartyID string) {
+	counterparty, err := h.studentRepo.GetByID(ctx, counterpartyID)
+	if err != nil {
+		h.logger.Warn("failed to get counterparty",
+			"counterparty_id", counterpartyID,
+			"error", err,
+		)
+		return
+	}
+
+	if reason := h.skipReason(counterparty); reason != "" {
+		h.logger.Debug("skipping buddy online notification",
+			"reason", reason,
+			"counterparty_id", counterpartyID,
+		)
+		return
+	}
+
+	// Кулдаун берём последним: пропущенные уведомления его не расходуют.
+	// При ошибке хранилища не отправляем - лишнее сообщение хуже пропущенного.
+	acquired, err := h.cooldowns.Acquire(ctx, "buddy_online:"+counterpartyID, h.config.Cooldown)
+	if err != nil {
+		h.logger.Warn("failed to check buddy online cooldown",
+			"counterparty_id", counterpartyID,
+			"error", err,
+		)
+		return
+	}
+	if !acquired {
+		h.logger.Debug("skipping buddy online notification",
+			"reason", "cooldown",
+			"counterparty_id", counterpartyID,
+		)
+		return
+	}
+
+	message := fmt.Sprintf("🟢 Твой напарник <b>%s</b> сейчас онлайн", html.EscapeString(buddy.DisplayName))
+	notif, err := notification.NewNotification(notification.NewNotificationParams{
+		ID:             notification.NotificationID(generateID()),
+		Type:           notification.NotificationTypeBuddyOnline,
+		RecipientID:    notification.RecipientID(counterparty.ID),
+		TelegramChatID: notification.TelegramChatID(counterparty.TelegramID),
+		Message:        message,
+	})
+	if err != nil {
+		h.logger.Warn("failed to create buddy online notification",
+			"counterparty_id", counterpartyID,
+			"error", err,
+		)
+		return
+	}
+	notif.SetMetadata("buddy_id", buddy.ID)
+
+	result := h.notifier.Send(ctx, notif)
+	if result.Skipped() {
+		return
+	}
+	if !result.Success {
+		h.logger.Warn("failed to send buddy online notification",
+			"counterparty_id", counterpartyID,
+			"error", result.Error,
+		)
+		return
+	}
+
+	h.logger.Debug("buddy online notification sent",
+		"buddy_id", buddy.ID,
+		"counterparty_id", counterpartyID,
+	)
+}
+
+// skipReason возвращает причину не уведомлять получателя (пустая - уведомлять).
+func (h *OnStudentOnlineHandler) skipReason(counterparty *student.Student) string {
+	switch {
+	case !counterparty.Status.CanReceiveNotifications():
+		return "status"
+	case !counterparty.Preferences.BuddyOnline:
+		return "preference"
+	case counterparty.Preferences.IsQuietHour(h.now()):
+		return "quiet_hours"
+	case !counterparty.AcceptsNotification(string(notification.NotificationTypeBuddyOnline), h.now()):
+		return "muted"
+	case !counterparty.OnlineState.IsAvailable():
+		return "counterparty_offline"
+	default:
+		return ""
+	}
+}
+
+// isNotifiedType проверяет, уведомляем ли участников связи этого типа.
+func (h *OnStudentOnlineHandler) isNotifiedType(t social.ConnectionType) bool {
+	for _, ct := range h.config.ConnectionTypes {
+		if ct == t {
+			return true
+		}
+	}
+	return false
+}
+
+// EventType возвращает тип события, который обрабатывает этот handler.
+func (h *OnStudentOnlineHandler) EventType() shared.EventType {
+	return shared.EventStudentWentOnline
+}
@@ -0,0 +1,155 @@
+package eventhandler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type fakeStudentRepo struct {
+	student.Repository
+	students map[string]*student.Student
+}
+
+func (f *fakeStudentRepo) GetByID(_ context.Context, id string) (*student.Student, error) {
+	s, ok := f.students[id]
+	if !ok {
+		return nil, student.ErrStudentNotFound
+	}
+	return s, nil
+}
+
+type fakeConnectionRepo struct {
+	social.ConnectionRepository
+	conns []*social.Connection
+}
+
+func (f *fakeConnectionRepo) GetActiveByStudentID(_ context.Context, id social.StudentID) ([]*social.Connection, error) {
+	var result []*social.Connection
+	for _, c := range f.conns {
+		if c.InvolveStudent(id) {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+type fakeCooldowns struct {
+	now     func() time.Time
+	expires map[string]time.Time
+}
+
+func (f *fakeCooldowns) Acquire(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	if until, ok := f.expires[key]; ok && f.now().Before(until) {
+		return false, nil
+	}
+	f.expires[key] = f.now().Add(ttl)
+	return true, nil
+}
+
+type fakeNotifier struct {
+	sent []*notification.Notification
+}
+
+func (f *fakeNotifier) Send(_ context.Context, n *notification.Notification) notification.DeliveryResult {
+	f.sent = append(f.sent, n)
+	return notification.NewSuccessResult(notification.ChannelTypeTelegram, "1")
+}
+
+func newBuddyStudent(id string, telegramID int64, state student.OnlineState) *student.Student {
+	return &student.Student{
+		ID:          id,
+		TelegramID:  student.TelegramID(telegramID),
+		DisplayName: id,
+		Status:      student.StatusActive,
+		OnlineState: state,
+		Preferences: student.DefaultNotificationPreferences(),
+	}
+}
+
+func TestOnStudentOnlineHandler_RateLimitsPerCounterparty(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, time.Local)
+	clock := func() time.Time { return now }
+
+	students := &fakeStudentRepo{students: map[string]*student.Student{
+		"ali":    newBuddyStudent("ali", 1, student.OnlineStateOnline),
+		"dana":   newBuddyStudent("dana", 2, student.OnlineStateOnline),
+		"mentor": newBuddyStudent("mentor", 3, student.OnlineStateAway),
+		"sleepy": newBuddyStudent("sleepy", 4, student.OnlineStateOffline),
+		"helped": newBuddyStudent("helped", 5, student.OnlineStateOnline),
+	}}
+	conns := &fakeConnectionRepo{conns: []*social.Connection{
+		{ID: "c1", InitiatorID: "ali", ReceiverID: "dana", Type: social.ConnectionTypeStudyBuddy, Status: social.ConnectionStatusActive},
+		{ID: "c2", InitiatorID: "mentor", ReceiverID: "ali", Type: social.ConnectionTypeMentor, Status: social.ConnectionStatusActive},
+		{ID: "c3", InitiatorID: "ali", ReceiverID: "sleepy", Type: social.ConnectionTypeStudyBuddy, Status: social.ConnectionStatusActive},
+		{ID: "c4", InitiatorID: "ali", ReceiverID: "helped", Type: social.ConnectionTypeHelper, Status: social.ConnectionStatusActive},
+	}}
+	notifier := &fakeNotifier{}
+
+	h := NewOnStudentOnlineHandler(students, conns, &fakeCooldowns{now: clock, expires: map[string]time.Time{}},
+		notifier, nil, DefaultStudentOnlineConfig())
+	h.now = clock
+
+	event := shared.NewStudentWentOnlineEvent("ali", "")
+
+	// Напарник и ментор онлайн; оффлайн-напарник и разовая помощь - нет
+	require.NoError(t, h.Handle(event))
+	require.Len(t, notifier.sent, 2)
+	assert.ElementsMatch(t,
+		[]notification.RecipientID{"dana", "mentor"},
+		[]notification.RecipientID{notifier.sent[0].RecipientID, notifier.sent[1].RecipientID},
+	)
+	assert.Contains(t, notifier.sent[0].Message, "<b>ali</b> сейчас онлайн")
+
+	// Повторный вход в течение 4 часов не уведомляет снова
+	now = now.Add(3 * time.Hour) // 17:00
+	require.NoError(t, h.Handle(event))
+	assert.Len(t, notifier.sent, 2)
+
+	// Тихие часы подавляют уведомление
+	now = now.Add(6 * time.Hour) // 23:00
+	require.NoError(t, h.Handle(event))
+	assert.Len(t, notifier.sent, 2)
+
+	// После кулдауна - снова, но только тем, у кого включена настройка
+	now = now.Add(11 * time.Hour) // 10:00
+	students.students["dana"].Preferences.BuddyOnline = false
+	require.NoError(t, h.Handle(event))
+	require.Len(t, notifier.sent, 3)
+	assert.Equal(t, notification.RecipientID("mentor"), notifier.sent[2].RecipientID)
+}
+
+func TestOnStudentOnlineHandler_SkippedNotificationKeepsCooldown(t *testing.T) {
+	now := time.Date(2025, 3, 10, 23, 30, 0, 0, time.Local)
+	clock := func() time.Time { return now }
+
+	students := &fakeStudentRepo{students: map[string]*student.Student{
+		"ali":  newBuddyStudent("ali", 1, student.OnlineStateOnline),
+		"dana": newBuddyStudent("dana", 2, student.OnlineStateOnline),
+	}}
+	conns := &fakeConnectionRepo{conns: []*social.Connection{
+		{ID: "c1", InitiatorID: "ali", ReceiverID: "dana", Type: social.ConnectionTypeStudyBuddy, Status: social.ConnectionStatusActive},
+	}}
+	notifier := &fakeNotifier{}
+
+	h := NewOnStudentOnlineHandler(students, conns, &fakeCooldowns{now: clock, expires: map[string]time.Time{}},
+		notifier, nil, DefaultStudentOnlineConfig())
+	h.now = clock
+
+	// Ночью уведомление подавлено и кулдаун не расходуется
+	require.NoError(t, h.Handle(shared.NewStudentWentOnlineEvent("ali", "")))
+	assert.Empty(t, notifier.sent)
+
+	// Утром уведомление уходит сразу, не дожидаясь 4 часов
+	now = now.Add(9 * time.Hour) // 08:30
+	require.NoError(t, h.Handle(shared.NewStudentWentOnlineEvent("ali", "")))
+	assert.Len(t, notifier.sent, 1)
+}
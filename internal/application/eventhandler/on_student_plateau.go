@@ -0,0 +1,216 @@
+package eventhandler
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/featureflag"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// ON STUDENT PLATEAU HANDLER
+// Предлагает помощь студенту, который несколько дней заходит на платформу,
+// но не получает XP.
+//
+// Философия "От конкуренции к сотрудничеству":
+// - Застрять - нормально, стыдно не спросить
+// - Одно нажатие: попросить помощь или посмотреть, кто решил задачу
+// - Не навязываемся: не чаще раза в 10 дней и можно отключить в настройках
+// ═══════════════════════════════════════════════════════════════════════════
+
+// KeyboardNotifier доставляет уведомление с inline-клавиатурой.
+type KeyboardNotifier interface {
+	SendWithKeyboard(ctx context.Context, notification *notification.Notification, keyboard [][]notification.InlineButton) notification.DeliveryResult
+}
+
+// OnStudentPlateauHandler обрабатывает событие застревания студента.
+type OnStudentPlateauHandler struct {
+	// Dependencies
+	studentRepo student.Repository
+	cooldowns   notification.CooldownStore
+	notifier    KeyboardNotifier
+
+	// Logger
+	logger *slog.Logger
+
+	// Configuration
+	config StudentPlateauConfig
+
+	// now - источник времени (подменяется в тестах).
+	now func() time.Time
+}
+
+// StudentPlateauConfig содержит конфигурацию обработчика.
+type StudentPlateauConfig struct {
+	// Cooldown — минимальный интервал между предложениями одному студенту.
+	Cooldown time.Duration
+}
+
+// DefaultStudentPlateauConfig возвращает конфигурацию по умолчанию.
+func DefaultStudentPlateauConfig() StudentPlateauConfig {
+	return StudentPlateauConfig{
+		Cooldown: 10 * 24 * time.Hour,
+	}
+}
+
+// NewOnStudentPlateauHandler создаёт новый обработчик события застревания.
+func NewOnStudentPlateauHandler(
+	studentRepo student.Repository,
+	cooldowns notification.CooldownStore,
+	notifier KeyboardNotifier,
+	logger *slog.Logger,
+	config StudentPlateauConfig,
+) *OnStudentPlateauHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &OnStudentPlateauHandler{
+		studentRepo: studentRepo,
+		cooldowns:   cooldowns,
+		notifier:    notifier,
+		logger:      logger.With("handler", "on_student_plateau"),
+		config:      config,
+		now:         time.Now,
+	}
+}
+
+// Handle обрабатывает событие застревания.
+// Реализует интерфейс shared.EventHandler.
+func (h *OnStudentPlateauHandler) Handle(event shared.Event) error {
+	ctx := context.Background()
+
+	stuckEvent, ok := event.(shared.StudentStuckEvent)
+	if !ok {
+		h.logger.Warn("received non-StudentStuckEvent",
+			"event_type", event.EventType(),
+		)
+		return nil
+	}
+
+	stud, err := h.studentRepo.GetByID(ctx, stuckEvent.StudentID)
+	if err != nil {
+		return fmt.Errorf("get student: %w", err)
+	}
+
+	if !featureflag.Enabled(ctx, settings.FeatureStuckDetection, stud.ID, string(stud.Cohort)) {
+		h.logger.Debug("skipping stuck help offer",
+			"reason", "feature_flag",
+			"student_id", stud.ID,
+		)
+		return nil
+	}
+
+	if reason := h.skipReason(stud); reason != "" {
+		h.logger.Debug("skipping stuck help offer",
+			"reason", reason,
+			"student_id", stud.ID,
+		)
+		return nil
+	}
+
+	// Кулдаун берём последним: пропущенные предложения его не расходуют.
+	acquired, err := h.cooldowns.Acquire(ctx, "stuck_help:"+stud.ID, h.config.Cooldown)
+	if err != nil {
+		return fmt.Errorf("check stuck help cooldown: %w", err)
+	}
+	if !acquired {
+		h.logger.Debug("skipping stuck help offer",
+			"reason", "cooldown",
+			"student_id", stud.ID,
+		)
+		return nil
+	}
+
+	notif, err := notification.NewNotification(notification.NewNotificationParams{
+		ID:             notification.NotificationID(generateID()),
+		Type:           notification.NotificationTypeHelpOffer,
+		RecipientID:    notification.RecipientID(stud.ID),
+		TelegramChatID: notification.TelegramChatID(stud.TelegramID),
+		Message:        formatStuckHelpMessage(stud, stuckEvent),
+	})
+	if err != nil {
+		return fmt.Errorf("create stuck help notification: %w", err)
+	}
+	notif.SetMetadata("plateau_days", strconv.Itoa(stuckEvent.PlateauDays))
+	if stuckEvent.TaskID != "" {
+		notif.SetMetadata("task_id", stuckEvent.TaskID)
+	}
+
+	result := h.notifier.SendWithKeyboard(ctx, notif, stuckHelpKeyboard(stuckEvent.TaskID))
+	if result.Skipped() {
+		return nil
+	}
+	if !result.Success {
+		return fmt.Errorf("send stuck help offer: %w", result.Error)
+	}
+
+	h.logger.Info("stuck help offer sent",
+		"student_id", stud.ID,
+		"task_id", stuckEvent.TaskID,
+		"plateau_days", stuckEvent.PlateauDays,
+	)
+
+	return nil
+}
+
+// skipReason возвращает причину не писать студенту (пустая - писать).
+func (h *OnStudentPlateauHandler) skipReason(stud *student.Student) string {
+	switch {
+	case !stud.Status.CanReceiveNotifications():
+		return "status"
+	case !stud.Preferences.StuckHelpOffers:
+		return "preference"
+	case stud.Preferences.IsQuietHour(h.now()):
+		return "quiet_hours"
+	case !stud.AcceptsNotification(string(notification.NotificationTypeHelpOffer), h.now()):
+		return "muted"
+	default:
+		return ""
+	}
+}
+
+// formatStuckHelpMessage формирует текст предложения помощи.
+func formatStuckHelpMessage(stud *student.Student, event shared.StudentStuckEvent) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("🧗 %s, вижу, ты уже %d дн. заходишь и упорно работаешь, а XP пока не растёт.\n\n",
+		html.EscapeString(stud.DisplayName), event.PlateauDays))
+
+	if event.TaskID != "" {
+		sb.WriteString(fmt.Sprintf("Похоже, задача <b>%s</b> не поддаётся. ", html.EscapeString(event.TaskID)))
+		sb.WriteString("Это бывает со всеми — её уже решили другие, и они могут подсказать.")
+	} else {
+		sb.WriteString("Это бывает со всеми. Напиши /help, и мы найдём тех, кто уже прошёл через это.")
+	}
+
+	return sb.String()
+}
+
+// stuckHelpKeyboard возвращает кнопки для задачи; без задачи кнопок нет.
+func stuckHelpKeyboard(taskID string) [][]notification.InlineButton {
+	if taskID == "" {
+		return nil
+	}
+
+	return [][]notification.InlineButton{
+		{
+			notification.NewCallbackButton("🆘 Попросить помощь", "help:request:"+taskID),
+			notification.NewCallbackButton("👀 Кто решил", "help:refresh:"+taskID),
+		},
+	}
+}
+
+// EventType возвращает тип события, который обрабатывает этот handler.
+func (h *OnStudentPlateauHandler) EventType() shared.EventType {
+	return shared.EventStudentStuck
+}
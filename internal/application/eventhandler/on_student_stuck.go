@@ -42,6 +42,9 @@ type OnStudentStuckHandler struct {
 	// Notification sender
 	notificationSender notification.NotificationSender
 
+	// Чёрный список фраз для описания запроса (nil - без маскировки)
+	blocklist social.ContentBlocklist
+
 	// Logger
 	logger *slog.Logger
 
@@ -116,6 +119,13 @@ func NewOnStudentStuckHandler(
 	}
 }
 
+// WithContentBlocklist включает маскировку фраз из чёрного списка в
+// описании запроса, которое видят помощники.
+func (h *OnStudentStuckHandler) WithContentBlocklist(blocklist social.ContentBlocklist) *OnStudentStuckHandler {
+	h.blocklist = blocklist
+	return h
+}
+
 // Handle обрабатывает событие запроса помощи.
 // Реализует интерфейс shared.EventHandler.
 func (h *OnStudentStuckHandler) Handle(event shared.Event) error {
@@ -290,7 +300,7 @@ func (h *OnStudentStuckHandler) rankHelpers(
 		}
 
 		// Проверяем, может ли студент помогать
-		if !studentEntity.CanHelp() {
+		if !studentEntity.IsDiscoverableHelper() {
 			continue
 		}
 
@@ -449,7 +459,7 @@ func (h *OnStudentStuckHandler) notifyStudentWithHelpers(
 	notif.SetMetadata("helpers_count", fmt.Sprintf("%d", len(helpers)))
 
 	result := h.notificationSender.Send(ctx, notif)
-	if !result.Success {
+	if !result.Success && !result.Skipped() {
 		return fmt.Errorf("send notification: %w", result.Error)
 	}
 
@@ -484,17 +494,8 @@ func (h *OnStudentStuckHandler) notifyPotentialHelpers(
 	emoji := notification.NotificationTypeHelpRequest.Emoji()
 
 	for _, helper := range toNotify {
-		// Проверяем, что помощник может получать уведомления
-		if !helper.Student.Status.CanReceiveNotifications() {
-			continue
-		}
-
-		if !helper.Student.Preferences.HelpRequests {
-			continue
-		}
-
-		// Проверяем тихие часы
-		if helper.Student.Preferences.IsQuietHour(time.Now()) {
+		// Статус, настройки, тихие часы и режим тишины (/mute help)
+		if !helper.Student.CanReceiveNotification(string(notification.NotificationTypeHelpRequest), time.Now()) {
 			continue
 		}
 
@@ -511,11 +512,7 @@ func (h *OnStudentStuckHandler) notifyPotentialHelpers(
 
 		// Добавляем сообщение от студента, если есть
 		if event.Message != "" {
-			// Ограничиваем длину сообщения
-			msg := event.Message
-			if len(msg) > 100 {
-				msg = msg[:97] + "..."
-			}
+			msg := social.RenderUserHTML(event.Message, h.renderOptions(ctx, requestingStudent.ID, helper.StudentID))
 			message += fmt.Sprintf("\n\n💬 \"%s\"", msg)
 		}
 
@@ -542,7 +539,24 @@ func (h *OnStudentStuckHandler) notifyPotentialHelpers(
 			notif.SetMetadata("help_request_id", helpRequest.ID)
 		}
 
-		result := h.notificationSender.Send(ctx, notif)
+		var result notification.DeliveryResult
+		if kn, ok := h.notificationSender.(KeyboardNotifier); ok && helpRequest != nil {
+			// Ответ на приглашение и кнопка жалобы на запросившего
+			// (знакомство через бота)
+			result = kn.SendWithKeyboard(ctx, notif, [][]notification.InlineButton{
+				{
+					notification.NewCallbackButton("✅ Помогу", "helpreq:accept:"+helpRequest.ID),
+					notification.NewCallbackButton("🙅 Не сейчас", "helpreq:decline:"+helpRequest.ID),
+				},
+				{notification.NewCallbackButton("⏰ Напомнить через 2 часа", "helpreq:snooze:"+helpRequest.ID)},
+				{notification.NewCallbackButton("⚠️ Пожаловаться", "report:h:"+helpRequest.ID)},
+			})
+		} else {
+			result = h.notificationSender.Send(ctx, notif)
+		}
+		if result.Skipped() {
+			continue
+		}
 		if !result.Success {
 			h.logger.Warn("failed to send helper notification",
 				"helper_id", helper.StudentID,
@@ -559,6 +573,23 @@ func (h *OnStudentStuckHandler) notifyPotentialHelpers(
 	return nil
 }
 
+// helperMessageRunes - длина описания запроса в уведомлении помощнику.
+const helperMessageRunes = 100
+
+// renderOptions возвращает параметры показа описания запроса помощнику:
+// ссылки-приглашения видны, только если у них активная связь.
+func (h *OnStudentStuckHandler) renderOptions(ctx context.Context, requesterID, helperID string) social.RenderOptions {
+	opts := social.RenderOptions{MaxRunes: helperMessageRunes}
+	if h.blocklist != nil {
+		opts.Blocklist = h.blocklist.Phrases(ctx)
+	}
+	if h.socialRepo != nil {
+		conn, err := h.socialRepo.Connections().GetByStudents(ctx, social.StudentID(requesterID), social.StudentID(helperID))
+		opts.Trusted = err == nil && conn != nil && conn.IsActive()
+	}
+	return opts
+}
+
 // EventType возвращает тип события, который обрабатывает этот handler.
 func (h *OnStudentStuckHandler) EventType() shared.EventType {
 	return shared.EventHelpRequested
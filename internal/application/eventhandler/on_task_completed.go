@@ -386,7 +386,7 @@ func (h *OnTaskCompletedHandler) sendMilestoneNotification(
 	notif.SetMetadata("milestone_value", fmt.Sprintf("%d", milestone))
 
 	result := h.notificationSender.Send(ctx, notif)
-	if !result.Success {
+	if !result.Success && !result.Skipped() {
 		return fmt.Errorf("send notification: %w", result.Error)
 	}
 
@@ -584,7 +584,7 @@ func (h *OnTaskCompletedHandler) notifyHelperOnSuccess(
 	notif.SetMetadata("task_id", event.TaskID)
 
 	result := h.notificationSender.Send(ctx, notif)
-	if !result.Success {
+	if !result.Success && !result.Skipped() {
 		return fmt.Errorf("send notification: %w", result.Error)
 	}
 
@@ -628,7 +628,7 @@ func (h *OnTaskCompletedHandler) sendCompletionConfirmation(
 	notif.SetMetadata("xp_earned", fmt.Sprintf("%d", event.XPEarned))
 
 	result := h.notificationSender.Send(ctx, notif)
-	if !result.Success {
+	if !result.Success && !result.Skipped() {
 		return fmt.Errorf("send notification: %w", result.Error)
 	}
 
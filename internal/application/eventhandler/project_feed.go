@@ -0,0 +1,165 @@
+package eventhandler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// FEED PROJECTION
+// Проецирует избранные доменные события в ленту сообщества (/feed).
+// Каждый вид записи включается отдельно в настройках потока.
+// ═══════════════════════════════════════════════════════════════════════════
+
+// feedEvents - события, попадающие в ленту, и ключ настройки их вида.
+var feedEvents = map[shared.EventType]settings.Key{
+	shared.EventStudentRegistered:   settings.KeyFeedStudentJoined,
+	shared.EventHelpRequestResolved: settings.KeyFeedHelpResolved,
+	shared.EventPersonalBestSet:     settings.KeyFeedPersonalBests,
+	shared.EventAchievementUnlocked: settings.KeyFeedAchievements,
+	shared.EventHelperOfTheWeek:     settings.KeyFeedHelperOfTheWeek,
+}
+
+// FeedProjectionHandler записывает события в ленту сообщества.
+type FeedProjectionHandler struct {
+	// Dependencies
+	feed        social.FeedRepository
+	studentRepo student.Repository
+	settings    settings.Reader
+
+	// Logger
+	logger *slog.Logger
+
+	// now - источник времени (подменяется в тестах).
+	now func() time.Time
+}
+
+// NewFeedProjectionHandler создаёт проекцию ленты. Без settings все виды
+// записей включены.
+func NewFeedProjectionHandler(
+	feed social.FeedRepository,
+	studentRepo student.Repository,
+	cohortSettings settings.Reader,
+	logger *slog.Logger,
+) *FeedProjectionHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if cohortSettings == nil {
+		cohortSettings = settings.Defaults{}
+	}
+
+	return &FeedProjectionHandler{
+		feed:        feed,
+		studentRepo: studentRepo,
+		settings:    cohortSettings,
+		logger:      logger.With("handler", "project_feed"),
+		now:         time.Now,
+	}
+}
+
+// Subscribe подписывает проекцию на все события ленты.
+func (h *FeedProjectionHandler) Subscribe(bus shared.EventSubscriber) error {
+	for eventType := range feedEvents {
+		if err := bus.Subscribe(eventType, h.Handle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handle записывает событие в ленту.
+// Реализует интерфейс shared.EventHandler.
+func (h *FeedProjectionHandler) Handle(event shared.Event) error {
+	ctx := context.Background()
+
+	itemType, actorID, payload, ok := feedEntry(event)
+	if !ok {
+		return nil
+	}
+
+	stud, err := h.studentRepo.GetByID(ctx, actorID)
+	if err != nil {
+		return fmt.Errorf("get student: %w", err)
+	}
+
+	cohort := string(stud.Cohort)
+	if !h.settings.GetBool(ctx, cohort, feedEvents[event.EventType()], true) {
+		h.logger.Debug("feed item type is off for cohort", "type", itemType, "cohort", cohort)
+		return nil
+	}
+
+	createdAt := event.OccurredAt()
+	if createdAt.IsZero() {
+		createdAt = h.now()
+	}
+
+	// Скрытый из рейтинга студент не попадает в ленту: запись остаётся
+	// скрытой, даже если он потом откроется
+	item, err := social.NewFeedItem(itemType, social.StudentID(stud.ID), stud.DisplayName, cohort,
+		payload, !stud.IsOnPublicLeaderboard(), createdAt)
+	if err != nil {
+		return fmt.Errorf("create feed item: %w", err)
+	}
+	if err := h.feed.Create(ctx, item); err != nil {
+		return fmt.Errorf("save feed item: %w", err)
+	}
+
+	h.logger.Debug("feed item projected",
+		"type", itemType,
+		"student_id", stud.ID,
+		"visibility", item.Visibility,
+	)
+	return nil
+}
+
+// feedEntry извлекает из события вид записи, студента и подробности.
+// ok = false - событие не попадает в ленту.
+func feedEntry(event shared.Event) (itemType social.FeedItemType, actorID string, payload map[string]string, ok bool) {
+	switch e := event.(type) {
+	case shared.StudentRegisteredEvent:
+		return social.FeedItemStudentJoined, e.AggregateID(), nil, true
+
+	case shared.HelpRequestResolvedEvent:
+		// Запрос, закрытый без помощника, нечем отметить
+		if e.HelperID == "" {
+			return "", "", nil, false
+		}
+		return social.FeedItemHelpResolved, e.HelperID, map[string]string{"task_id": e.TaskID}, true
+
+	case shared.PersonalBestSetEvent:
+		metric := student.PersonalBestMetric(e.Metric)
+		return social.FeedItemPersonalBest, e.StudentID, map[string]string{
+			"metric": e.Metric,
+			"label":  metric.Label(),
+			"value":  metric.FormatValue(e.Value),
+		}, true
+
+	case shared.AchievementUnlockedEvent:
+		achievement := student.AchievementType(e.AchievementType)
+		def, known := student.GetAchievementDefinition(achievement)
+		if !known || !achievement.IsPublic() {
+			return "", "", nil, false
+		}
+		return social.FeedItemAchievement, e.StudentID, map[string]string{
+			"achievement": e.AchievementType,
+			"name":        def.Name,
+			"emoji":       def.Emoji,
+		}, true
+
+	case shared.HelperOfTheWeekEvent:
+		return social.FeedItemHelperOfTheWeek, e.StudentID, map[string]string{
+			"week":         e.Week,
+			"endorsements": strconv.Itoa(e.Endorsements),
+		}, true
+	}
+	return "", "", nil, false
+}
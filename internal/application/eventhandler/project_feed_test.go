@@ -0,0 +1,91 @@
+package eventhandler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type fakeFeedRepo struct {
+	social.FeedRepository
+	items []*social.FeedItem
+}
+
+func (f *fakeFeedRepo) Create(_ context.Context, item *social.FeedItem) error {
+	item.ID = int64(len(f.items) + 1)
+	f.items = append(f.items, item)
+	return nil
+}
+
+// fakeFeedSettings - настройки потоков с переопределениями.
+type fakeFeedSettings map[string]settings.Values
+
+func (f fakeFeedSettings) GetBool(_ context.Context, cohort string, key settings.Key, def bool) bool {
+	return f[cohort].Bool(key, def)
+}
+
+func (f fakeFeedSettings) GetInt(_ context.Context, cohort string, key settings.Key, def int) int {
+	return f[cohort].Int(key, def)
+}
+
+func (f fakeFeedSettings) GetString(_ context.Context, cohort string, key settings.Key, def string) string {
+	return f[cohort].String(key, def)
+}
+
+func TestFeedProjectionHandler(t *testing.T) {
+	newStudent := func(id, cohort string, hidden bool) *student.Student {
+		s := newBuddyStudent(id, 1, student.OnlineStateOnline)
+		s.Cohort = student.Cohort(cohort)
+		s.Preferences.HideFromLeaderboard = hidden
+		return s
+	}
+	students := &fakeStudentRepo{students: map[string]*student.Student{
+		"dana":   newStudent("dana", "2025", false),
+		"shadow": newStudent("shadow", "2025", true),
+		"arman":  newStudent("arman", "2024", false),
+	}}
+	feed := &fakeFeedRepo{}
+	cohorts := fakeFeedSettings{"2024": settings.Values{
+		settings.KeyFeedAchievements: json.RawMessage("false"),
+	}}
+	h := NewFeedProjectionHandler(feed, students, cohorts, nil)
+
+	require.NoError(t, h.Handle(shared.NewAchievementUnlockedEvent("dana", string(student.AchievementStreak7), 100)))
+	require.Len(t, feed.items, 1)
+	assert.Equal(t, social.FeedItemAchievement, feed.items[0].Type)
+	assert.True(t, feed.items[0].IsPublic())
+	assert.Equal(t, "🔥 dana получает достижение «Неделя огня»", feed.items[0].Summary())
+
+	t.Run("hidden student is stored hidden", func(t *testing.T) {
+		require.NoError(t, h.Handle(shared.NewHelpRequestResolvedEvent("req-1", "dana", "shadow", "go-reloaded")))
+		last := feed.items[len(feed.items)-1]
+		assert.Equal(t, social.StudentID("shadow"), last.ActorID)
+		assert.Equal(t, social.FeedVisibilityHidden, last.Visibility)
+	})
+
+	t.Run("type switched off for the cohort", func(t *testing.T) {
+		before := len(feed.items)
+		require.NoError(t, h.Handle(shared.NewAchievementUnlockedEvent("arman", string(student.AchievementStreak7), 100)))
+		assert.Len(t, feed.items, before)
+
+		// Другие виды записей потока остаются
+		require.NoError(t, h.Handle(shared.NewPersonalBestSetEvent("arman", string(student.PersonalBestDayXP), 500, 300, time.Now())))
+		assert.Len(t, feed.items, before+1)
+	})
+
+	t.Run("private achievements and helpless resolutions are skipped", func(t *testing.T) {
+		before := len(feed.items)
+		require.NoError(t, h.Handle(shared.NewAchievementUnlockedEvent("dana", string(student.AchievementNightOwl), 50)))
+		require.NoError(t, h.Handle(shared.NewHelpRequestResolvedEvent("req-2", "dana", "", "go-reloaded")))
+		assert.Len(t, feed.items, before)
+	})
+}
@@ -0,0 +1,297 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/analytics"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// COHORT REPORT
+// Еженедельный отчёт о "здоровье" потока для кураторов: сколько студентов
+// активны, кто пропал, сколько запросов помощи висят без ответа и кто
+// быстрее всех поднялся. Все цифры считаются агрегатными запросами.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// CohortReportConfig содержит пороги отчёта.
+type CohortReportConfig struct {
+	// InactiveAfter - сколько без активности считается "пропал".
+	InactiveAfter time.Duration
+
+	// StaleHelpAfter - сколько запрос помощи может ждать до попадания в отчёт.
+	StaleHelpAfter time.Duration
+
+	// MaxInactiveNames - сколько имён неактивных студентов перечислить.
+	MaxInactiveNames int
+
+	// TopClimbers - сколько лидеров подъёма показать.
+	TopClimbers int
+
+	// TopPopularTasks - сколько горячих задач недели показать.
+	TopPopularTasks int
+}
+
+// DefaultCohortReportConfig возвращает конфигурацию по умолчанию.
+func DefaultCohortReportConfig() CohortReportConfig {
+	return CohortReportConfig{
+		InactiveAfter:    7 * 24 * time.Hour,
+		StaleHelpAfter:   48 * time.Hour,
+		MaxInactiveNames: 15,
+		TopClimbers:      3,
+		TopPopularTasks:  3,
+	}
+}
+
+// InactiveStudentDTO - студент без активности.
+type InactiveStudentDTO struct {
+	DisplayName  string `json:"display_name"`
+	InactiveDays int    `json:"inactive_days"`
+}
+
+// RankClimberDTO - студент, поднявшийся в рейтинге.
+type RankClimberDTO struct {
+	DisplayName string `json:"display_name"`
+	OldRank     int    `json:"old_rank"`
+	NewRank     int    `json:"new_rank"`
+}
+
+// CohortReport - отчёт о потоке за неделю [WeekStart, WeekEnd).
+type CohortReport struct {
+	Cohort      string    `json:"cohort"`
+	WeekStart   time.Time `json:"week_start"`
+	WeekEnd     time.Time `json:"week_end"`
+	GeneratedAt time.Time `json:"generated_at"`
+
+	TotalStudents    int     `json:"total_students"`
+	ActiveStudents   int     `json:"active_students"`
+	NewStudents      int     `json:"new_students"`
+	XPGained         int     `json:"xp_gained"`
+	PreviousXPGained int     `json:"previous_xp_gained"`
+	MedianStreak     float64 `json:"median_streak"`
+
+	// InactiveCount - всего неактивных; InactiveStudents - первые из них.
+	InactiveCount    int                  `json:"inactive_count"`
+	InactiveStudents []InactiveStudentDTO `json:"inactive_students"`
+	InactiveDays     int                  `json:"inactive_days"`
+
+	StaleHelpRequests int `json:"stale_help_requests"`
+	StaleHelpHours    int `json:"stale_help_hours"`
+
+	TopClimbers []RankClimberDTO `json:"top_climbers"`
+
+	// PopularTasks - горячие задачи за последние 7 дней (по всем потокам).
+	PopularTasks []PopularTaskDTO `json:"popular_tasks,omitempty"`
+}
+
+// XPChangePercent возвращает изменение XP к прошлой неделе в процентах;
+// false, если на прошлой неделе XP не было.
+func (r *CohortReport) XPChangePercent() (int, bool) {
+	if r.PreviousXPGained <= 0 {
+		return 0, false
+	}
+	change := float64(r.XPGained-r.PreviousXPGained) / float64(r.PreviousXPGained) * 100
+	return int(math.Round(change)), true
+}
+
+// CohortReportBuilder собирает отчёт о потоке.
+type CohortReportBuilder struct {
+	repo    analytics.CohortHealthRepository
+	popular *GetPopularTasksHandler
+	config  CohortReportConfig
+	now     func() time.Time
+}
+
+// NewCohortReportBuilder создаёт новый сборщик отчётов.
+func NewCohortReportBuilder(repo analytics.CohortHealthRepository, config CohortReportConfig) *CohortReportBuilder {
+	return &CohortReportBuilder{repo: repo, config: config, now: time.Now}
+}
+
+// WithPopularTasks добавляет в отчёт горячие задачи недели.
+func (b *CohortReportBuilder) WithPopularTasks(popular *GetPopularTasksHandler) *CohortReportBuilder {
+	b.popular = popular
+	return b
+}
+
+// Build собирает отчёт о потоке за прошлую полную неделю (пн-вс, UTC).
+// Неактивные студенты и зависшие запросы считаются на момент сборки.
+func (b *CohortReportBuilder) Build(ctx context.Context, cohort string) (*CohortReport, error) {
+	now := b.now().UTC()
+	weekEnd := startOfWeek(now)
+	weekStart := weekEnd.AddDate(0, 0, -7)
+
+	activity, err := b.repo.GetActivity(ctx, cohort, weekStart.AddDate(0, 0, -7), weekStart, weekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cohort activity: %w", err)
+	}
+
+	inactive, inactiveCount, err := b.repo.ListInactive(ctx, cohort, now.Add(-b.config.InactiveAfter), b.config.MaxInactiveNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inactive students: %w", err)
+	}
+
+	staleHelp, err := b.repo.CountStaleHelpRequests(ctx, cohort, now.Add(-b.config.StaleHelpAfter))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count stale help requests: %w", err)
+	}
+
+	climbers, err := b.repo.TopClimbers(ctx, cohort, weekStart, weekEnd, b.config.TopClimbers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top climbers: %w", err)
+	}
+
+	report := &CohortReport{
+		Cohort:            cohort,
+		WeekStart:         weekStart,
+		WeekEnd:           weekEnd,
+		GeneratedAt:       now,
+		TotalStudents:     activity.TotalStudents,
+		ActiveStudents:    activity.ActiveStudents,
+		NewStudents:       activity.NewStudents,
+		XPGained:          activity.XPGained,
+		PreviousXPGained:  activity.PreviousXPGained,
+		MedianStreak:      activity.MedianStreak,
+		InactiveCount:     inactiveCount,
+		InactiveStudents:  make([]InactiveStudentDTO, 0, len(inactive)),
+		InactiveDays:      int(b.config.InactiveAfter.Hours() / 24),
+		StaleHelpRequests: staleHelp,
+		StaleHelpHours:    int(b.config.StaleHelpAfter.Hours()),
+		TopClimbers:       make([]RankClimberDTO, 0, len(climbers)),
+	}
+	for _, s := range inactive {
+		report.InactiveStudents = append(report.InactiveStudents, InactiveStudentDTO{
+			DisplayName:  s.DisplayName,
+			InactiveDays: int(now.Sub(s.LastSeenAt).Hours() / 24),
+		})
+	}
+	for _, c := range climbers {
+		report.TopClimbers = append(report.TopClimbers, RankClimberDTO(c))
+	}
+
+	if b.popular != nil && b.config.TopPopularTasks > 0 {
+		popular, err := b.popular.Handle(ctx, GetPopularTasksQuery{
+			Window: PopularTasksWeek,
+			Limit:  b.config.TopPopularTasks,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get popular tasks: %w", err)
+		}
+		report.PopularTasks = popular.Tasks
+	}
+
+	return report, nil
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RENDERING
+// ══════════════════════════════════════════════════════════════════════════════
+
+// RenderCohortReport форматирует отчёт как Telegram-сообщение (HTML).
+func RenderCohortReport(r *CohortReport) string {
+	var b strings.Builder
+
+	lastDay := r.WeekEnd.AddDate(0, 0, -1)
+	fmt.Fprintf(&b, "📊 <b>Поток %s: неделя %s–%s</b>\n\n",
+		html.EscapeString(r.Cohort), r.WeekStart.Format("02.01"), lastDay.Format("02.01.2006"))
+
+	fmt.Fprintf(&b, "👥 Активны за неделю: <b>%d</b> из %d\n", r.ActiveStudents, r.TotalStudents)
+	fmt.Fprintf(&b, "🆕 Новых регистраций: <b>%d</b>\n", r.NewStudents)
+	fmt.Fprintf(&b, "⚡ XP за неделю: <b>%d</b> %s\n", r.XPGained, formatXPChange(r))
+	fmt.Fprintf(&b, "🔥 Медиана серии: <b>%s</b> дн.\n", strconv.FormatFloat(r.MedianStreak, 'f', -1, 64))
+	fmt.Fprintf(&b, "🆘 Запросы помощи без ответа дольше %d ч: <b>%d</b>\n", r.StaleHelpHours, r.StaleHelpRequests)
+
+	fmt.Fprintf(&b, "\n😴 <b>Не заходили %d+ дней: %d</b>\n", r.InactiveDays, r.InactiveCount)
+	for _, s := range r.InactiveStudents {
+		fmt.Fprintf(&b, "• %s — %d дн.\n", html.EscapeString(s.DisplayName), s.InactiveDays)
+	}
+	if hidden := r.InactiveCount - len(r.InactiveStudents); hidden > 0 {
+		fmt.Fprintf(&b, "…и ещё %d\n", hidden)
+	}
+
+	b.WriteString("\n🚀 <b>Лучший подъём в рейтинге</b>\n")
+	if len(r.TopClimbers) == 0 {
+		b.WriteString("На этой неделе без перемен.\n")
+	}
+	for i, c := range r.TopClimbers {
+		fmt.Fprintf(&b, "%d. %s — #%d → #%d (+%d)\n",
+			i+1, html.EscapeString(c.DisplayName), c.OldRank, c.NewRank, c.OldRank-c.NewRank)
+	}
+
+	if len(r.PopularTasks) > 0 {
+		b.WriteString("\n🧩 <b>Горячие задачи недели</b>\n")
+		for i, t := range r.PopularTasks {
+			fmt.Fprintf(&b, "%d. %s — запросов помощи: %d, решено: %d\n",
+				i+1, html.EscapeString(t.TaskName), t.Requests, t.Resolved)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formatXPChange описывает изменение XP к прошлой неделе.
+func formatXPChange(r *CohortReport) string {
+	percent, ok := r.XPChangePercent()
+	if !ok {
+		return fmt.Sprintf("(на прошлой неделе: %d)", r.PreviousXPGained)
+	}
+
+	arrow := "▲"
+	if percent < 0 {
+		arrow, percent = "▼", -percent
+	} else if percent == 0 {
+		arrow = "="
+	}
+	return fmt.Sprintf("(%s %d%% к прошлой: %d)", arrow, percent, r.PreviousXPGained)
+}
+
+// CohortReportCSV выгружает отчёт в CSV: показатели, затем неактивные
+// студенты, лидеры подъёма и горячие задачи (колонки section, name, value, detail).
+func CohortReportCSV(r *CohortReport) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{
+		{"section", "name", "value", "detail"},
+		{"summary", "week_start", r.WeekStart.Format("2006-01-02"), ""},
+		{"summary", "week_end", r.WeekEnd.AddDate(0, 0, -1).Format("2006-01-02"), ""},
+		{"summary", "total_students", strconv.Itoa(r.TotalStudents), ""},
+		{"summary", "active_students", strconv.Itoa(r.ActiveStudents), ""},
+		{"summary", "new_students", strconv.Itoa(r.NewStudents), ""},
+		{"summary", "xp_gained", strconv.Itoa(r.XPGained), ""},
+		{"summary", "previous_xp_gained", strconv.Itoa(r.PreviousXPGained), ""},
+		{"summary", "median_streak", strconv.FormatFloat(r.MedianStreak, 'f', -1, 64), ""},
+		{"summary", "inactive_students", strconv.Itoa(r.InactiveCount), ""},
+		{"summary", "stale_help_requests", strconv.Itoa(r.StaleHelpRequests), ""},
+	}
+	for _, s := range r.InactiveStudents {
+		rows = append(rows, []string{"inactive", csvText(s.DisplayName), strconv.Itoa(s.InactiveDays), "days"})
+	}
+	for _, c := range r.TopClimbers {
+		rows = append(rows, []string{"climber", csvText(c.DisplayName), strconv.Itoa(c.OldRank - c.NewRank),
+			fmt.Sprintf("%d->%d", c.OldRank, c.NewRank)})
+	}
+	for _, t := range r.PopularTasks {
+		rows = append(rows, []string{"popular_task", csvText(t.TaskName), strconv.Itoa(t.Requests),
+			fmt.Sprintf("%d resolved", t.Resolved)})
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to write report csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// csvText защищает от формул: имя "=HYPERLINK(...)" таблица иначе выполнит.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,97 @@
+package query
+
+import (
+	"context"
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/analytics"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+// fakeCohortHealth returns fixed aggregates and records the requested windows.
+type fakeCohortHealth struct {
+	prevFrom, from, to time.Time
+	inactiveSince      time.Time
+	staleBefore        time.Time
+}
+
+func (f *fakeCohortHealth) GetActivity(ctx context.Context, cohort string, prevFrom, from, to time.Time) (analytics.CohortActivity, error) {
+	f.prevFrom, f.from, f.to = prevFrom, from, to
+	return analytics.CohortActivity{
+		TotalStudents:    48,
+		ActiveStudents:   31,
+		NewStudents:      3,
+		XPGained:         18400,
+		PreviousXPGained: 16000,
+		MedianStreak:     4.5,
+	}, nil
+}
+
+func (f *fakeCohortHealth) ListInactive(ctx context.Context, cohort string, since time.Time, limit int) ([]analytics.InactiveStudent, int, error) {
+	f.inactiveSince = since
+	now := time.Date(2026, 3, 9, 4, 0, 0, 0, time.UTC)
+	return []analytics.InactiveStudent{
+		{DisplayName: "arman", LastSeenAt: now.AddDate(0, 0, -12)},
+		{DisplayName: "=HYPERLINK(\"x\")", LastSeenAt: now.AddDate(0, 0, -9)},
+		{DisplayName: "<b>dana</b>", LastSeenAt: now.AddDate(0, 0, -8)},
+	}, 5, nil
+}
+
+func (f *fakeCohortHealth) CountStaleHelpRequests(ctx context.Context, cohort string, olderThan time.Time) (int, error) {
+	f.staleBefore = olderThan
+	return 2, nil
+}
+
+func (f *fakeCohortHealth) TopClimbers(ctx context.Context, cohort string, from, to time.Time, limit int) ([]analytics.RankClimber, error) {
+	return []analytics.RankClimber{
+		{DisplayName: "aigerim", OldRank: 14, NewRank: 6},
+		{DisplayName: "daniyar", OldRank: 9, NewRank: 5},
+	}, nil
+}
+
+func TestCohortReport_Golden(t *testing.T) {
+	repo := &fakeCohortHealth{}
+	now := time.Date(2026, 3, 9, 4, 0, 0, 0, time.UTC)
+	resolvedAt := now.Add(-time.Hour)
+	popular := NewGetPopularTasksHandler(&fixtureHelpRequests{requests: []*social.HelpRequest{
+		{TaskID: "go-reloaded", TaskName: "Go Reloaded", CreatedAt: now.Add(-2 * time.Hour), Status: social.HelpRequestStatusResolved, ResolvedAt: &resolvedAt},
+		{TaskID: "go-reloaded", TaskName: "Go Reloaded", CreatedAt: now.Add(-48 * time.Hour)},
+		{TaskID: "ascii-art", TaskName: "ascii-art", CreatedAt: now.Add(-24 * time.Hour)},
+	}}, nil)
+	popular.now = func() time.Time { return now }
+
+	builder := NewCohortReportBuilder(repo, DefaultCohortReportConfig()).WithPopularTasks(popular)
+	builder.now = func() time.Time { return now }
+
+	report, err := builder.Build(context.Background(), "2025-spring")
+	require.NoError(t, err)
+
+	// Monday run covers the previous Mon-Sun week
+	assert.Equal(t, time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC), repo.prevFrom)
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), repo.from)
+	assert.Equal(t, time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), repo.to)
+	assert.Equal(t, time.Date(2026, 3, 2, 4, 0, 0, 0, time.UTC), repo.inactiveSince)
+	assert.Equal(t, time.Date(2026, 3, 7, 4, 0, 0, 0, time.UTC), repo.staleBefore)
+
+	content, err := CohortReportCSV(report)
+	require.NoError(t, err)
+	got := RenderCohortReport(report) + "\n\n--- csv ---\n" + string(content)
+
+	golden := "testdata/cohort_report.golden"
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll("testdata", 0o755))
+		require.NoError(t, os.WriteFile(golden, []byte(got), 0o644))
+	}
+
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), got)
+}
@@ -0,0 +1,194 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// COMPARE STUDENTS QUERY
+// Сравнение "один на один" для /compare: XP, уровень, места в общем рейтинге
+// и в потоке, серия, задачи за сегодня и рейтинг помощника, плюс разница.
+// Места студента, скрывшегося из рейтинга, другим не показываются.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ErrCompareWithSelf - студент пытается сравнить себя с собой.
+var ErrCompareWithSelf = shared.NewDomainError("query", "CompareStudents", shared.ErrInvalidInput,
+	"can't compare a student with themselves")
+
+// CompareStudentsQuery содержит параметры сравнения.
+type CompareStudentsQuery struct {
+	// ViewerID - кто сравнивает.
+	ViewerID string
+
+	// OtherLogin - логин Alem второго студента (можно с @).
+	OtherLogin string
+}
+
+// Validate проверяет корректность параметров.
+func (q *CompareStudentsQuery) Validate() error {
+	q.OtherLogin = strings.TrimPrefix(strings.TrimSpace(q.OtherLogin), "@")
+	if q.ViewerID == "" {
+		return errors.New("viewer_id is required")
+	}
+	if q.OtherLogin == "" {
+		return errors.New("other_login is required")
+	}
+	return nil
+}
+
+// ComparedStudentDTO - показатели одного из сравниваемых студентов.
+type ComparedStudentDTO struct {
+	StudentID   string `json:"student_id"`
+	Login       string `json:"login"`
+	DisplayName string `json:"display_name"`
+	Cohort      string `json:"cohort"`
+
+	XP    int `json:"xp"`
+	Level int `json:"level"`
+
+	// GlobalRank, CohortRank - места в общем рейтинге и в потоке
+	// (0 - студента ещё нет в рейтинге или места скрыты).
+	GlobalRank int `json:"global_rank"`
+	CohortRank int `json:"cohort_rank"`
+
+	// RankHidden - студент скрылся из рейтинга, места не показываются.
+	RankHidden bool `json:"rank_hidden"`
+
+	// Streak - текущая серия активных дней.
+	Streak int `json:"streak"`
+
+	// TasksToday - задач решено сегодня.
+	TasksToday int `json:"tasks_today"`
+
+	// HelpRating - средняя оценка как помощника (0.0 - 5.0).
+	HelpRating float64 `json:"help_rating"`
+}
+
+// CompareLeader - кто впереди.
+type CompareLeader string
+
+const (
+	CompareLeaderViewer CompareLeader = "viewer"
+	CompareLeaderOther  CompareLeader = "other"
+	CompareLeaderTie    CompareLeader = "tie"
+)
+
+// CompareStudentsResult содержит результат сравнения.
+type CompareStudentsResult struct {
+	Viewer ComparedStudentDTO `json:"viewer"`
+	Other  ComparedStudentDTO `json:"other"`
+
+	// XPGap - насколько у смотрящего больше XP (отрицательное - меньше).
+	XPGap int `json:"xp_gap"`
+
+	// RankGap - на сколько мест смотрящий выше в общем рейтинге
+	// (отрицательное - ниже). 0, если место неизвестно хотя бы у одного.
+	RankGap int `json:"rank_gap"`
+
+	// Leader - кто впереди по XP.
+	Leader CompareLeader `json:"leader"`
+}
+
+// CompareStudentsHandler обрабатывает запросы сравнения.
+type CompareStudentsHandler struct {
+	studentRepo     student.Repository
+	leaderboardRepo leaderboard.LeaderboardRepository
+	progressRepo    student.ProgressRepository
+}
+
+// NewCompareStudentsHandler создаёт новый обработчик.
+// Без progressRepo серия и задачи за сегодня не заполняются.
+func NewCompareStudentsHandler(
+	studentRepo student.Repository,
+	leaderboardRepo leaderboard.LeaderboardRepository,
+	progressRepo student.ProgressRepository,
+) *CompareStudentsHandler {
+	return &CompareStudentsHandler{
+		studentRepo:     studentRepo,
+		leaderboardRepo: leaderboardRepo,
+		progressRepo:    progressRepo,
+	}
+}
+
+// Handle выполняет сравнение.
+func (h *CompareStudentsHandler) Handle(ctx context.Context, query CompareStudentsQuery) (*CompareStudentsResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, shared.WrapError("query", "CompareStudents", shared.ErrValidation, err.Error(), err)
+	}
+
+	viewer, err := h.studentRepo.GetByID(ctx, query.ViewerID)
+	if err != nil {
+		return nil, shared.WrapError("query", "CompareStudents", shared.ErrNotFound, "viewer not found", err)
+	}
+	other, err := h.studentRepo.GetByAlemLogin(ctx, query.OtherLogin)
+	if err != nil {
+		return nil, shared.WrapError("query", "CompareStudents", shared.ErrNotFound, "student not found", err)
+	}
+	if other.ID == viewer.ID {
+		return nil, ErrCompareWithSelf
+	}
+
+	result := &CompareStudentsResult{
+		Viewer: h.describe(ctx, viewer, true),
+		Other:  h.describe(ctx, other, other.IsOnPublicLeaderboard()),
+	}
+
+	result.XPGap = result.Viewer.XP - result.Other.XP
+	switch {
+	case result.XPGap > 0:
+		result.Leader = CompareLeaderViewer
+	case result.XPGap < 0:
+		result.Leader = CompareLeaderOther
+	default:
+		result.Leader = CompareLeaderTie
+	}
+	if result.Viewer.GlobalRank > 0 && result.Other.GlobalRank > 0 {
+		result.RankGap = result.Other.GlobalRank - result.Viewer.GlobalRank
+	}
+
+	return result, nil
+}
+
+// describe собирает показатели студента. Ошибки необязательных источников
+// (рейтинг, прогресс) не ломают сравнение: показатель остаётся пустым.
+func (h *CompareStudentsHandler) describe(ctx context.Context, stud *student.Student, showRanks bool) ComparedStudentDTO {
+	dto := ComparedStudentDTO{
+		StudentID:   stud.ID,
+		Login:       stud.AlemLogin,
+		DisplayName: stud.DisplayName,
+		Cohort:      string(stud.Cohort),
+		XP:          int(stud.CurrentXP),
+		Level:       int(stud.Level()),
+		HelpRating:  stud.HelpRating,
+		RankHidden:  !showRanks,
+	}
+
+	if showRanks {
+		if entry, err := h.leaderboardRepo.GetStudentRank(ctx, stud.ID, leaderboard.CohortAll); err == nil && entry != nil {
+			dto.GlobalRank = int(entry.Rank)
+		}
+		if stud.Cohort != "" {
+			cohort := leaderboard.Cohort(stud.Cohort)
+			if entry, err := h.leaderboardRepo.GetStudentRank(ctx, stud.ID, cohort); err == nil && entry != nil {
+				dto.CohortRank = int(entry.Rank)
+			}
+		}
+	}
+
+	if h.progressRepo != nil {
+		if streak, err := h.progressRepo.GetStreak(ctx, stud.ID); err == nil && streak != nil && !streak.IsBroken() {
+			dto.Streak = streak.CurrentStreak
+		}
+		if grind, err := h.progressRepo.GetTodayDailyGrind(ctx, stud.ID); err == nil && grind != nil {
+			dto.TasksToday = grind.TasksCompleted
+		}
+	}
+
+	return dto
+}
@@ -0,0 +1,107 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type compareStudents struct {
+	student.Repository
+	byID map[string]*student.Student
+}
+
+func (s *compareStudents) GetByID(_ context.Context, id string) (*student.Student, error) {
+	if stud, ok := s.byID[id]; ok {
+		return stud, nil
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+func (s *compareStudents) GetByAlemLogin(_ context.Context, login string) (*student.Student, error) {
+	for _, stud := range s.byID {
+		if stud.AlemLogin == login {
+			return stud, nil
+		}
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+type compareRanks struct {
+	leaderboard.LeaderboardRepository
+	ranks map[leaderboard.Cohort]map[string]leaderboard.Rank
+}
+
+func (r *compareRanks) GetStudentRank(_ context.Context, id string, cohort leaderboard.Cohort) (*leaderboard.LeaderboardEntry, error) {
+	rank, ok := r.ranks[cohort][id]
+	if !ok {
+		return nil, nil
+	}
+	return &leaderboard.LeaderboardEntry{StudentID: id, Rank: rank}, nil
+}
+
+func newCompareHandler() *CompareStudentsHandler {
+	newStudent := func(id string, xp student.XP, hidden bool) *student.Student {
+		s := &student.Student{ID: id, AlemLogin: id, DisplayName: id, Cohort: "2025-a", CurrentXP: xp, HelpRating: 4.5}
+		s.Preferences = student.DefaultNotificationPreferences()
+		s.Preferences.HideFromLeaderboard = hidden
+		return s
+	}
+
+	return NewCompareStudentsHandler(
+		&compareStudents{byID: map[string]*student.Student{
+			"dana":   newStudent("dana", 1500, false),
+			"arman":  newStudent("arman", 1200, false),
+			"hidden": newStudent("hidden", 900, true),
+			"newbie": newStudent("newbie", 0, false),
+		}},
+		&compareRanks{ranks: map[leaderboard.Cohort]map[string]leaderboard.Rank{
+			leaderboard.CohortAll: {"dana": 4, "arman": 10, "hidden": 20},
+			"2025-a":              {"dana": 1, "arman": 3, "hidden": 5},
+		}},
+		nil,
+	)
+}
+
+func TestCompareStudents_Deltas(t *testing.T) {
+	result, err := newCompareHandler().Handle(context.Background(), CompareStudentsQuery{ViewerID: "arman", OtherLogin: "@dana"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, result.Viewer.GlobalRank)
+	assert.Equal(t, 3, result.Viewer.CohortRank)
+	assert.Equal(t, 1, result.Other.CohortRank)
+	assert.Equal(t, -300, result.XPGap)
+	assert.Equal(t, -6, result.RankGap)
+	assert.Equal(t, CompareLeaderOther, result.Leader)
+}
+
+func TestCompareStudents_EdgeCases(t *testing.T) {
+	ctx := context.Background()
+	handler := newCompareHandler()
+
+	_, err := handler.Handle(ctx, CompareStudentsQuery{ViewerID: "dana", OtherLogin: "dana"})
+	assert.ErrorIs(t, err, ErrCompareWithSelf)
+
+	_, err = handler.Handle(ctx, CompareStudentsQuery{ViewerID: "dana", OtherLogin: "nobody"})
+	assert.True(t, shared.IsNotFound(err))
+
+	// Второго студента ещё нет в рейтинге: места пустые, разница мест не считается
+	result, err := handler.Handle(ctx, CompareStudentsQuery{ViewerID: "dana", OtherLogin: "newbie"})
+	require.NoError(t, err)
+	assert.Zero(t, result.Other.GlobalRank)
+	assert.Zero(t, result.RankGap)
+	assert.Equal(t, CompareLeaderViewer, result.Leader)
+
+	// Скрывшийся из рейтинга: места не раскрываются
+	result, err = handler.Handle(ctx, CompareStudentsQuery{ViewerID: "dana", OtherLogin: "hidden"})
+	require.NoError(t, err)
+	assert.True(t, result.Other.RankHidden)
+	assert.Zero(t, result.Other.GlobalRank)
+	assert.Zero(t, result.Other.CohortRank)
+}
@@ -4,13 +4,19 @@ package query
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/alem-hub/alem-community-hub/internal/domain/activity"
+	"github.com/alem-hub/alem-community-hub/internal/domain/matchscore"
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
 	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
 	"github.com/alem-hub/alem-community-hub/internal/domain/social"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/pagination"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -60,20 +66,30 @@ type FindHelpersQuery struct {
 	Cohort string
 }
 
+// FindHelpersBounds - ограничения числа возвращаемых помощников.
+var FindHelpersBounds = pagination.Bounds{DefaultLimit: 5, MaxLimit: 20}
+
+const (
+	// FindHelpersXPLead - на сколько XP помощник должен опережать
+	// запросившего, когда задачу ещё никто не решил (один уровень).
+	FindHelpersXPLead = 1000
+
+	// findHelpersCandidatePool - сколько кандидатов оценивается за запрос.
+	findHelpersCandidatePool = 100
+)
+
 // Validate проверяет корректность параметров.
 func (q *FindHelpersQuery) Validate() error {
 	if q.RequesterID == "" && q.RequesterTelegramID == 0 {
 		return errors.New("requester identification is required")
 	}
+	if q.RequesterTelegramID != 0 && !student.TelegramID(q.RequesterTelegramID).IsValid() {
+		return student.ErrInvalidTelegramID
+	}
 	if q.TaskID == "" {
 		return errors.New("task_id is required")
 	}
-	if q.Limit <= 0 {
-		q.Limit = 5
-	}
-	if q.Limit > 20 {
-		q.Limit = 20
-	}
+	q.Limit = FindHelpersBounds.ClampLimit(q.Limit)
 	if q.MinHelpRating < 0 || q.MinHelpRating > 5 {
 		return errors.New("min_help_rating must be between 0 and 5")
 	}
@@ -137,6 +153,9 @@ type HelperDTO struct {
 	// TotalHelpCount - сколько раз помогал другим.
 	TotalHelpCount int `json:"total_help_count"`
 
+	// HelperStreakWeeks - сколько недель подряд помогает (0 - серии нет).
+	HelperStreakWeeks int `json:"helper_streak_weeks,omitempty"`
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// История взаимодействий
 	// ─────────────────────────────────────────────────────────────────────────
@@ -170,6 +189,10 @@ type HelperDTO struct {
 	// ScoreBreakdown - разбивка скора по факторам.
 	ScoreBreakdown map[string]float64 `json:"score_breakdown,omitempty"`
 
+	// MatchReasons - причины по факторам скора, самые весомые первыми
+	// ("решил 2 недели назад", "сейчас онлайн").
+	MatchReasons []string `json:"match_reasons,omitempty"`
+
 	// RecommendationReason - почему рекомендуем этого помощника.
 	RecommendationReason string `json:"recommendation_reason,omitempty"`
 }
@@ -199,6 +222,19 @@ type FindHelpersResult struct {
 	// OnlineSolvers - сколько из них сейчас онлайн.
 	OnlineSolvers int `json:"online_solvers"`
 
+	// Difficulty - запросов помощи на 100 решений за 30 дней;
+	// nil, если сложность ещё не оценена.
+	Difficulty *float64 `json:"difficulty,omitempty"`
+
+	// FrequentlyAsked - по задаче часто просят помощь
+	// (social.HighDifficultyScore). Подбор тогда сильнее учитывает рейтинг.
+	FrequentlyAsked bool `json:"frequently_asked"`
+
+	// MatchedByXP - задачу ещё никто не решил, и помощники подобраны
+	// по опыту: студенты того же потока минимум на FindHelpersXPLead XP
+	// впереди запросившего.
+	MatchedByXP bool `json:"matched_by_xp,omitempty"`
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// Метаданные
 	// ─────────────────────────────────────────────────────────────────────────
@@ -228,6 +264,11 @@ type FindHelpersHandler struct {
 	onlineTracker activity.OnlineTracker
 	taskIndex     activity.TaskIndex
 	socialRepo    social.Repository
+	difficulty    social.TaskDifficultyRepository
+	helperStreaks student.HelperStreakRepository
+
+	// cohortSettings - веса подбора по потокам.
+	cohortSettings settings.Reader
 }
 
 // NewFindHelpersHandler создаёт новый обработчик.
@@ -239,12 +280,35 @@ func NewFindHelpersHandler(
 	socialRepo social.Repository,
 ) *FindHelpersHandler {
 	return &FindHelpersHandler{
-		studentRepo:   studentRepo,
-		activityRepo:  activityRepo,
-		onlineTracker: onlineTracker,
-		taskIndex:     taskIndex,
-		socialRepo:    socialRepo,
+		studentRepo:    studentRepo,
+		activityRepo:   activityRepo,
+		onlineTracker:  onlineTracker,
+		taskIndex:      taskIndex,
+		socialRepo:     socialRepo,
+		cohortSettings: settings.Defaults{},
+	}
+}
+
+// WithCohortSettings включает переопределение весов подбора потоком
+// запросившего студента (settings.KeyMatchWeight*).
+func (h *FindHelpersHandler) WithCohortSettings(reader settings.Reader) *FindHelpersHandler {
+	if reader != nil {
+		h.cohortSettings = reader
 	}
+	return h
+}
+
+// WithTaskDifficulty включает учёт сложности задачи: предупреждение
+// в результате и больший вес рейтинга помощника для сложных задач.
+func (h *FindHelpersHandler) WithTaskDifficulty(repo social.TaskDifficultyRepository) *FindHelpersHandler {
+	h.difficulty = repo
+	return h
+}
+
+// WithHelperStreaks показывает у помощников серию недель помощи.
+func (h *FindHelpersHandler) WithHelperStreaks(repo student.HelperStreakRepository) *FindHelpersHandler {
+	h.helperStreaks = repo
+	return h
 }
 
 // Handle выполняет поиск помощников.
@@ -254,21 +318,34 @@ func (h *FindHelpersHandler) Handle(ctx context.Context, query FindHelpersQuery)
 		return nil, shared.WrapError("query", "FindHelpers", shared.ErrValidation, err.Error(), err)
 	}
 
-	// Получаем ID запрашивающего студента
-	requesterID, err := h.getRequesterID(ctx, query)
+	// Получаем запрашивающего студента (поток и тихие часы нужны для подбора)
+	requesterID, requester, err := h.getRequester(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
+	// Сложность задачи (нет оценки - обычный подбор)
+	difficulty := h.getDifficulty(ctx, query.TaskID)
+	highDifficulty := difficulty != nil && difficulty.IsHigh()
+
 	// Получаем студентов, решивших задачу
 	taskID := activity.TaskID(query.TaskID)
-	solverIDs, err := h.taskIndex.GetSolvers(ctx, taskID, 100) // Берём больше, потом фильтруем
+	solverIDs, err := h.taskIndex.GetSolvers(ctx, taskID, findHelpersCandidatePool) // Берём больше, потом фильтруем
 	if err != nil {
 		return nil, shared.WrapError("query", "FindHelpers", shared.ErrNotFound, "failed to get solvers", err)
 	}
 
+	// Решивших нет - подбираем по опыту в потоке
+	candidateIDs := solverIDs
+	var seniors map[string]*student.Student
+	byXP := false
 	if len(solverIDs) == 0 {
-		return &FindHelpersResult{
+		candidateIDs, seniors = h.findSeniorStudents(ctx, requester)
+		byXP = true
+	}
+
+	if len(candidateIDs) == 0 {
+		result := &FindHelpersResult{
 			Helpers:       []HelperDTO{},
 			TotalFound:    0,
 			TaskID:        query.TaskID,
@@ -276,18 +353,28 @@ func (h *FindHelpersHandler) Handle(ctx context.Context, query FindHelpersQuery)
 			OnlineSolvers: 0,
 			GeneratedAt:   time.Now().UTC(),
 			Message:       "Пока никто не решил эту задачу. Ты можешь стать первым! 💪",
-		}, nil
+		}
+		result.setDifficulty(difficulty)
+		return result, nil
 	}
 
 	// Фильтруем и обогащаем данные
-	helpers, err := h.buildHelpersList(ctx, solverIDs, requesterID, query)
+	helpers, err := h.buildHelpersList(ctx, candidateIDs, seniors, requesterID, requester, query, highDifficulty, byXP)
 	if err != nil {
 		return nil, err
 	}
 
-	// Сортируем по скору
+	// Сортируем по скору; при равенстве - по рейтингу и ID, чтобы порядок
+	// не зависел от порядка кандидатов
 	sort.Slice(helpers, func(i, j int) bool {
-		return helpers[i].Score > helpers[j].Score
+		a, b := helpers[i], helpers[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.HelpRating != b.HelpRating {
+			return a.HelpRating > b.HelpRating
+		}
+		return a.StudentID < b.StudentID
 	})
 
 	// Подсчёт статистики до обрезки
@@ -306,8 +393,11 @@ func (h *FindHelpersHandler) Handle(ctx context.Context, query FindHelpersQuery)
 
 	// Генерируем сообщение
 	message := h.generateMessage(helpers, onlineSolvers, totalFound)
+	if byXP && len(helpers) > 0 {
+		message = "🧭 Эту задачу пока никто не решил — вот кто опытнее тебя в потоке."
+	}
 
-	return &FindHelpersResult{
+	result := &FindHelpersResult{
 		Helpers:       helpers,
 		TotalFound:    totalFound,
 		TaskID:        query.TaskID,
@@ -320,31 +410,111 @@ func (h *FindHelpersHandler) Handle(ctx context.Context, query FindHelpersQuery)
 			MinHelpRating:   query.MinHelpRating,
 			MaxResponseTime: query.MaxResponseTime,
 		},
-		Message: message,
-	}, nil
+		Message:     message,
+		MatchedByXP: byXP,
+	}
+	result.setDifficulty(difficulty)
+	return result, nil
 }
 
-// getRequesterID получает ID запрашивающего студента.
-func (h *FindHelpersHandler) getRequesterID(ctx context.Context, query FindHelpersQuery) (string, error) {
+// findSeniorStudents возвращает студентов потока запросившего, которые
+// опережают его минимум на FindHelpersXPLead XP, вместе с загруженными
+// данными, чтобы не запрашивать их повторно. Без данных о запросившем
+// или при ошибке хранилища - пусто: подбор по опыту необязателен.
+func (h *FindHelpersHandler) findSeniorStudents(ctx context.Context, requester *student.Student) ([]activity.StudentID, map[string]*student.Student) {
+	if requester == nil {
+		return nil, nil
+	}
+
+	students, err := h.studentRepo.FindHelpersInCohort(ctx, requester.Cohort, requester.CurrentXP+FindHelpersXPLead, findHelpersCandidatePool)
+	if err != nil {
+		return nil, nil
+	}
+
+	ids := make([]activity.StudentID, 0, len(students))
+	loaded := make(map[string]*student.Student, len(students))
+	for _, s := range students {
+		if s.ID == requester.ID {
+			continue
+		}
+		ids = append(ids, activity.StudentID(s.ID))
+		loaded[s.ID] = s
+	}
+	return ids, loaded
+}
+
+// getDifficulty возвращает оценённую сложность задачи или nil.
+// Ошибки хранилища не мешают поиску помощников.
+func (h *FindHelpersHandler) getDifficulty(ctx context.Context, taskID string) *social.TaskDifficulty {
+	if h.difficulty == nil {
+		return nil
+	}
+	d, err := h.difficulty.GetByTaskID(ctx, social.TaskID(taskID))
+	if err != nil || !d.Scored {
+		return nil
+	}
+	return d
+}
+
+// setDifficulty заполняет поля сложности задачи.
+func (r *FindHelpersResult) setDifficulty(d *social.TaskDifficulty) {
+	if d == nil {
+		return
+	}
+	score := d.Score
+	r.Difficulty = &score
+	r.FrequentlyAsked = d.IsHigh()
+}
+
+// getRequester получает ID запрашивающего студента и, если удалось,
+// самого студента (nil - подбор без учёта потока и часов активности).
+func (h *FindHelpersHandler) getRequester(ctx context.Context, query FindHelpersQuery) (string, *student.Student, error) {
 	if query.RequesterID != "" {
-		return query.RequesterID, nil
+		stud, err := h.studentRepo.GetByID(ctx, query.RequesterID)
+		if err != nil {
+			return query.RequesterID, nil, nil
+		}
+		return query.RequesterID, stud, nil
 	}
 
 	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(query.RequesterTelegramID))
 	if err != nil {
-		return "", shared.WrapError("query", "FindHelpers", shared.ErrNotFound, "requester not found", err)
+		return "", nil, shared.WrapError("query", "FindHelpers", shared.ErrNotFound, "requester not found", err)
 	}
-	return stud.ID, nil
+	return stud.ID, stud, nil
+}
+
+// helperCandidate - собранные факты о помощнике до скоринга.
+type helperCandidate struct {
+	stud            *student.Student
+	completion      *activity.TaskCompletion
+	solveDuration   time.Duration
+	isOnline        bool
+	onlineStatus    string
+	hasPriorContact bool
+	priorHelpCount  int
+	helperWeeks     int
+
+	// xpLead - на сколько XP опережает запросившего (только при подборе
+	// по опыту, иначе 0).
+	xpLead int
 }
 
 // buildHelpersList строит список помощников с полной информацией.
+// Сначала собираются факты обо всех кандидатах, потом они оцениваются:
+// типичное время решения задачи и загрузка считаются по всему списку.
+// Студенты из loaded повторно не загружаются.
 func (h *FindHelpersHandler) buildHelpersList(
 	ctx context.Context,
 	solverIDs []activity.StudentID,
+	loaded map[string]*student.Student,
 	requesterID string,
+	requester *student.Student,
 	query FindHelpersQuery,
+	highDifficulty bool,
+	byXP bool,
 ) ([]HelperDTO, error) {
-	helpers := make([]HelperDTO, 0, len(solverIDs))
+	candidates := make([]*helperCandidate, 0, len(solverIDs))
 
 	for _, solverID := range solverIDs {
 		// Пропускаем самого себя
@@ -352,112 +522,222 @@ func (h *FindHelpersHandler) buildHelpersList(
 			continue
 		}
 
-		helper, err := h.buildHelperDTO(ctx, string(solverID), requesterID, query)
+		stud, ok := loaded[string(solverID)]
+		if !ok {
+			var err error
+			if stud, err = h.studentRepo.GetByID(ctx, string(solverID)); err != nil {
+				continue // Пропускаем при ошибке
+			}
+		}
+
+		candidate, err := h.buildCandidate(ctx, stud, requesterID, query)
 		if err != nil {
-			continue // Пропускаем при ошибке
+			continue // Скрылся из поиска помощников
+		}
+		if byXP && requester != nil {
+			candidate.xpLead = int(candidate.stud.CurrentXP - requester.CurrentXP)
 		}
 
 		// Фильтруем по минимальному рейтингу
-		if query.MinHelpRating > 0 && helper.HelpRating < query.MinHelpRating {
+		if query.MinHelpRating > 0 && candidate.stud.HelpRating < query.MinHelpRating {
 			continue
 		}
 
 		// Фильтруем по времени последней активности
-		if helper.LastSeenAt != nil && query.MaxResponseTime > 0 {
-			if time.Since(*helper.LastSeenAt) > query.MaxResponseTime {
+		if candidate.lastSeenAt() != nil && query.MaxResponseTime > 0 {
+			if time.Since(*candidate.lastSeenAt()) > query.MaxResponseTime {
 				continue
 			}
 		}
 
-		helpers = append(helpers, *helper)
+		candidates = append(candidates, candidate)
+	}
+
+	durations := make([]time.Duration, 0, len(candidates))
+	helperIDs := make([]social.StudentID, 0, len(candidates))
+	for _, c := range candidates {
+		durations = append(durations, c.solveDuration)
+		helperIDs = append(helperIDs, social.StudentID(c.stud.ID))
+	}
+	typicalDuration := matchscore.MedianDuration(durations)
+	openAssignments := h.countOpenAssignments(ctx, helperIDs)
+
+	now := time.Now()
+	cohort := ""
+	var requesterQuiet *matchscore.QuietHours
+	if requester != nil {
+		cohort = string(requester.Cohort)
+		requesterQuiet = quietHoursOf(requester, now)
+	}
+	weights := h.matchWeights(ctx, cohort, highDifficulty)
+
+	helpers := make([]HelperDTO, 0, len(candidates))
+	for _, c := range candidates {
+		in := matchscore.Input{
+			Now:                  now,
+			SolveDuration:        c.solveDuration,
+			TypicalSolveDuration: typicalDuration,
+			OpenAssignments:      openAssignments[social.StudentID(c.stud.ID)],
+			IsOnline:             c.isOnline,
+			HelperQuietHours:     quietHoursOf(c.stud, now),
+			RequesterQuietHours:  requesterQuiet,
+			Rating:               c.stud.HelpRating,
+			HelpCount:            c.stud.HelpCount,
+			PriorContact:         c.hasPriorContact,
+		}
+		if c.completion != nil {
+			in.SolvedAt = c.completion.CompletedAt
+		}
+		if lastSeen := c.lastSeenAt(); lastSeen != nil {
+			in.LastSeenAt = *lastSeen
+		}
+
+		helpers = append(helpers, h.buildHelperDTO(c, matchscore.Score(in, weights)))
 	}
 
 	return helpers, nil
 }
 
-// buildHelperDTO строит DTO для одного помощника.
-func (h *FindHelpersHandler) buildHelperDTO(
+// errHelperHidden - студент скрылся из поиска помощников.
+var errHelperHidden = errors.New("helper is hidden from helper search")
+
+// buildCandidate собирает факты об одном помощнике.
+func (h *FindHelpersHandler) buildCandidate(
 	ctx context.Context,
-	helperID string,
+	stud *student.Student,
 	requesterID string,
 	query FindHelpersQuery,
-) (*HelperDTO, error) {
-	// Получаем данные студента
-	stud, err := h.studentRepo.GetByID(ctx, helperID)
-	if err != nil {
-		return nil, err
+) (*helperCandidate, error) {
+	helperID := stud.ID
+	if !stud.IsDiscoverableHelper() {
+		return nil, errHelperHidden
 	}
 
+	candidate := &helperCandidate{stud: stud, onlineStatus: "offline"}
+
 	// Получаем информацию о решении задачи
-	taskID := activity.TaskID(query.TaskID)
-	completion, err := h.getTaskCompletion(ctx, helperID, taskID)
+	candidate.completion, candidate.solveDuration = h.getTaskCompletion(ctx, helperID, activity.TaskID(query.TaskID))
 
 	// Проверяем онлайн-статус
-	isOnline := false
-	onlineStatus := "offline"
-	if h.onlineTracker != nil {
-		isOnline, _ = h.onlineTracker.IsOnline(ctx, activity.StudentID(helperID))
-		if isOnline {
-			onlineStatus = "online"
+	if h.onlineTracker != nil && stud.ShowsOnlineStatus() {
+		candidate.isOnline, _ = h.onlineTracker.IsOnline(ctx, activity.StudentID(helperID))
+		if candidate.isOnline {
+			candidate.onlineStatus = "online"
 		} else if stud.LastSeenAt.After(time.Now().Add(-30 * time.Minute)) {
-			onlineStatus = "away"
+			candidate.onlineStatus = "away"
 		}
 	}
 
 	// Проверяем прошлые взаимодействия
-	hasPriorContact, priorHelpCount := h.checkPriorContact(ctx, helperID, requesterID)
+	candidate.hasPriorContact, candidate.priorHelpCount = h.checkPriorContact(ctx, helperID, requesterID)
+
+	// Серия недель помощи
+	if h.helperStreaks != nil {
+		if streak, err := h.helperStreaks.Get(ctx, helperID); err == nil {
+			candidate.helperWeeks = streak.ActiveWeeks(time.Now())
+		}
+	}
+
+	return candidate, nil
+}
 
-	// Вычисляем скор
-	score, breakdown := h.calculateScore(stud, isOnline, hasPriorContact, completion)
+// lastSeenAt возвращает время последней активности, если студент его не скрыл.
+func (c *helperCandidate) lastSeenAt() *time.Time {
+	if c.stud.LastSeenAt.IsZero() || !c.stud.ShowsOnlineStatus() {
+		return nil
+	}
+	return &c.stud.LastSeenAt
+}
 
-	dto := &HelperDTO{
+// buildHelperDTO строит DTO для одного помощника.
+func (h *FindHelpersHandler) buildHelperDTO(c *helperCandidate, score matchscore.Result) HelperDTO {
+	stud := c.stud
+	dto := HelperDTO{
 		StudentID:           stud.ID,
 		DisplayName:         stud.DisplayName,
-		IsOnline:            isOnline,
-		OnlineStatus:        onlineStatus,
+		IsOnline:            c.isOnline,
+		OnlineStatus:        c.onlineStatus,
 		HelpRating:          stud.HelpRating,
 		HelpRatingFormatted: formatHelpRating(stud.HelpRating),
 		TotalHelpCount:      stud.HelpCount,
-		HasPriorContact:     hasPriorContact,
-		PriorHelpCount:      priorHelpCount,
+		HelperStreakWeeks:   c.helperWeeks,
+		HasPriorContact:     c.hasPriorContact,
+		PriorHelpCount:      c.priorHelpCount,
 		Level:               int(stud.Level()),
 		XP:                  int(stud.CurrentXP),
-		Score:               score,
-		ScoreBreakdown:      breakdown,
+		Score:               score.Score,
+		ScoreBreakdown:      score.Breakdown(),
+		MatchReasons:        score.Reasons(),
+	}
+
+	// Задачу не решал: первой причиной идёт опыт
+	if c.xpLead > 0 {
+		dto.MatchReasons = append([]string{fmt.Sprintf("опытнее на %d XP", c.xpLead)}, dto.MatchReasons...)
 	}
 
-	// Last seen
-	if !stud.LastSeenAt.IsZero() {
-		dto.LastSeenAt = &stud.LastSeenAt
-		dto.LastSeenFormatted = formatLastSeen(stud.LastSeenAt)
+	// Last seen (тоже онлайн-статус, если студент его скрыл)
+	if lastSeen := c.lastSeenAt(); lastSeen != nil {
+		dto.LastSeenAt = lastSeen
+		dto.LastSeenFormatted = formatLastSeen(*lastSeen)
 	}
 
 	// Completion time
-	if completion != nil {
-		dto.CompletedTaskAt = completion.CompletedAt
-		dto.TimeSinceCompletion = formatTimeSince(completion.CompletedAt)
+	if c.completion != nil {
+		dto.CompletedTaskAt = c.completion.CompletedAt
+		dto.TimeSinceCompletion = formatTimeSince(c.completion.CompletedAt)
 	}
 
-	// Reason
-	dto.RecommendationReason = h.generateRecommendationReason(dto)
+	// Reason: две самые весомые причины, например "решил 2 недели назад, сейчас онлайн"
+	if len(dto.MatchReasons) > 0 {
+		dto.RecommendationReason = "💡 " + strings.Join(dto.MatchReasons[:min(2, len(dto.MatchReasons))], ", ")
+	} else {
+		dto.RecommendationReason = h.generateRecommendationReason(&dto)
+	}
 
-	return dto, nil
+	return dto
 }
 
-// getTaskCompletion получает информацию о завершении задачи.
-func (h *FindHelpersHandler) getTaskCompletion(ctx context.Context, studentID string, taskID activity.TaskID) (*activity.TaskCompletion, error) {
+// getTaskCompletion получает информацию о завершении задачи и время,
+// которое студент на неё потратил (0 - неизвестно). Если время не
+// отслеживалось, оно выводится из промежутка с предыдущего решения.
+func (h *FindHelpersHandler) getTaskCompletion(ctx context.Context, studentID string, taskID activity.TaskID) (*activity.TaskCompletion, time.Duration) {
 	completions, err := h.activityRepo.GetTaskCompletionsByStudent(ctx, activity.StudentID(studentID), 100)
 	if err != nil {
-		return nil, err
+		return nil, 0
 	}
 
+	var completion *activity.TaskCompletion
 	for _, c := range completions {
 		if c.TaskID == taskID {
-			return c, nil
+			completion = c
+			break
+		}
+	}
+	if completion == nil {
+		return nil, 0
+	}
+
+	var previous time.Time
+	for _, c := range completions {
+		if c.CompletedAt.Before(completion.CompletedAt) && c.CompletedAt.After(previous) {
+			previous = c.CompletedAt
 		}
 	}
 
-	return nil, nil
+	return completion, matchscore.SolveDuration(completion.CompletedAt, completion.TimeSpent, previous)
+}
+
+// countOpenAssignments возвращает загрузку помощников. Ошибка не мешает
+// подбору: загрузка тогда просто не учитывается.
+func (h *FindHelpersHandler) countOpenAssignments(ctx context.Context, helperIDs []social.StudentID) map[social.StudentID]int {
+	if h.socialRepo == nil {
+		return nil
+	}
+	counts, err := h.socialRepo.HelpRequests().CountOpenByHelpers(ctx, helperIDs)
+	if err != nil {
+		return nil
+	}
+	return counts
 }
 
 // checkPriorContact проверяет, есть ли история взаимодействий.
@@ -475,68 +755,44 @@ func (h *FindHelpersHandler) checkPriorContact(ctx context.Context, helperID, re
 	return true, conn.Stats.InteractionCount
 }
 
-// calculateScore вычисляет скор помощника для ранжирования.
-func (h *FindHelpersHandler) calculateScore(
-	stud *student.Student,
-	isOnline bool,
-	hasPriorContact bool,
-	completion *activity.TaskCompletion,
-) (float64, map[string]float64) {
-	breakdown := make(map[string]float64)
-	score := 0.0
-
-	// Онлайн-статус (максимум 30 баллов)
-	if isOnline {
-		breakdown["online"] = 30.0
-		score += 30.0
-	} else if !stud.LastSeenAt.IsZero() {
-		elapsed := time.Since(stud.LastSeenAt)
-		if elapsed < 5*time.Minute {
-			breakdown["online"] = 25.0
-			score += 25.0
-		} else if elapsed < 30*time.Minute {
-			breakdown["online"] = 15.0
-			score += 15.0
-		} else if elapsed < time.Hour {
-			breakdown["online"] = 5.0
-			score += 5.0
-		}
+// matchWeights возвращает веса подбора с переопределениями потока.
+// Для сложной задачи рейтинг весит больше: там важнее тот, кто умеет
+// объяснять, чем тот, кто просто онлайн.
+func (h *FindHelpersHandler) matchWeights(ctx context.Context, cohort string, highDifficulty bool) matchscore.Weights {
+	w := matchscore.DefaultWeights()
+	weight := func(key settings.Key, def float64) float64 {
+		return float64(h.cohortSettings.GetInt(ctx, cohort, key, int(def)))
 	}
 
-	// Рейтинг помощника (максимум 25 баллов)
-	ratingScore := stud.HelpRating * 5.0 // 0-25
-	breakdown["rating"] = ratingScore
-	score += ratingScore
+	w.Recency = weight(settings.KeyMatchWeightRecency, w.Recency)
+	w.Struggle = weight(settings.KeyMatchWeightStruggle, w.Struggle)
+	w.Load = weight(settings.KeyMatchWeightLoad, w.Load)
+	w.Online = weight(settings.KeyMatchWeightOnline, w.Online)
+	w.HoursOverlap = weight(settings.KeyMatchWeightHoursOverlap, w.HoursOverlap)
+	w.Rating = weight(settings.KeyMatchWeightRating, w.Rating)
+	w.Experience = weight(settings.KeyMatchWeightExperience, w.Experience)
+	w.PriorContact = weight(settings.KeyMatchWeightPriorContact, w.PriorContact)
 
-	// История помощи (максимум 20 баллов)
-	if stud.HelpCount > 0 {
-		helpScore := float64(stud.HelpCount)
-		if helpScore > 20 {
-			helpScore = 20
-		}
-		breakdown["help_history"] = helpScore
-		score += helpScore
+	if highDifficulty {
+		w.Rating *= HighDifficultyRatingBoost
 	}
+	return w
+}
 
-	// Предыдущий контакт (бонус 15 баллов)
-	if hasPriorContact {
-		breakdown["prior_contact"] = 15.0
-		score += 15.0
-	}
+// HighDifficultyRatingBoost - во сколько раз растёт вес рейтинга для
+// задачи, по которой часто просят помощь.
+const HighDifficultyRatingBoost = 1.6
 
-	// Свежесть решения задачи (максимум 10 баллов)
-	if completion != nil {
-		elapsed := time.Since(completion.CompletedAt)
-		if elapsed < 24*time.Hour {
-			breakdown["recency"] = 10.0
-			score += 10.0
-		} else if elapsed < 7*24*time.Hour {
-			breakdown["recency"] = 5.0
-			score += 5.0
-		}
+// quietHoursOf возвращает тихие часы студента, переведённые в часы
+// Алматы: так сравниваются тихие часы студентов из разных поясов.
+func quietHoursOf(s *student.Student, now time.Time) *matchscore.QuietHours {
+	_, offset := now.In(s.Preferences.Location()).Zone()
+	_, almatyOffset := now.In(timeutil.AlmatyTZ).Zone()
+	shift := (almatyOffset - offset) / 3600
+	return &matchscore.QuietHours{
+		Start: (s.Preferences.QuietHoursStart + shift + 24) % 24,
+		End:   (s.Preferences.QuietHoursEnd + shift + 24) % 24,
 	}
-
-	return score, breakdown
 }
 
 // generateRecommendationReason генерирует причину рекомендации.
@@ -573,7 +829,7 @@ func (h *FindHelpersHandler) generateMessage(helpers []HelperDTO, onlineSolvers,
 	}
 
 	if totalFound > len(helpers) {
-		return "📋 Показаны лучшие кандидаты. Всего найдено: " + string(rune(totalFound))
+		return fmt.Sprintf("📋 Показаны лучшие кандидаты. Всего найдено: %d", totalFound)
 	}
 
 	return "👋 Напиши любому из них — комьюнити всегда поможет!"
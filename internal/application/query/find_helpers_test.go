@@ -0,0 +1,194 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/activity"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type helperStudents struct {
+	student.Repository
+	byID    map[string]*student.Student
+	lookups int
+}
+
+func (s *helperStudents) GetByID(_ context.Context, id string) (*student.Student, error) {
+	s.lookups++
+	if stud, ok := s.byID[id]; ok {
+		return stud, nil
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+func (s *helperStudents) FindHelpersInCohort(_ context.Context, cohort student.Cohort, minXP student.XP, limit int) ([]*student.Student, error) {
+	var found []*student.Student
+	for _, stud := range s.byID {
+		if stud.Cohort == cohort && stud.CurrentXP >= minXP && stud.IsDiscoverableHelper() {
+			found = append(found, stud)
+		}
+	}
+	// Как в postgres: по убыванию XP, затем по ID
+	slices.SortFunc(found, func(a, b *student.Student) int {
+		if a.CurrentXP != b.CurrentXP {
+			return int(b.CurrentXP - a.CurrentXP)
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return found[:min(limit, len(found))], nil
+}
+
+type helperSolvers struct {
+	activity.TaskIndex
+	solvers []activity.StudentID
+}
+
+func (i *helperSolvers) GetSolvers(_ context.Context, _ activity.TaskID, limit int) ([]activity.StudentID, error) {
+	return i.solvers[:min(limit, len(i.solvers))], nil
+}
+
+type helperActivity struct {
+	activity.Repository
+}
+
+func (helperActivity) GetTaskCompletionsByStudent(context.Context, activity.StudentID, int) ([]*activity.TaskCompletion, error) {
+	return nil, nil
+}
+
+type helperOnline struct {
+	activity.OnlineTracker
+	online map[activity.StudentID]bool
+}
+
+func (t *helperOnline) IsOnline(_ context.Context, id activity.StudentID) (bool, error) {
+	return t.online[id], nil
+}
+
+func helperStudent(id string, cohort student.Cohort, xp student.XP, rating float64) *student.Student {
+	return &student.Student{
+		ID:          id,
+		DisplayName: id,
+		Cohort:      cohort,
+		CurrentXP:   xp,
+		Status:      student.StatusActive,
+		HelpRating:  rating,
+		Preferences: student.DefaultNotificationPreferences(),
+	}
+}
+
+func newFindHelpers(students map[string]*student.Student, solvers []activity.StudentID, online ...activity.StudentID) *FindHelpersHandler {
+	tracker := &helperOnline{online: map[activity.StudentID]bool{}}
+	for _, id := range online {
+		tracker.online[id] = true
+	}
+	return NewFindHelpersHandler(
+		&helperStudents{byID: students},
+		helperActivity{},
+		tracker,
+		&helperSolvers{solvers: solvers},
+		nil,
+	)
+}
+
+func helperIDs(result *FindHelpersResult) []string {
+	ids := make([]string, 0, len(result.Helpers))
+	for _, h := range result.Helpers {
+		ids = append(ids, h.StudentID)
+	}
+	return ids
+}
+
+func TestFindHelpers_MatchesByXPWhenNobodySolvedTheTask(t *testing.T) {
+	students := map[string]*student.Student{
+		"dana":  helperStudent("dana", "2024-spring", 1500, 0),
+		"arman": helperStudent("arman", "2024-spring", 3000, 4.8),
+		"aida":  helperStudent("aida", "2024-spring", 2600, 4.0),
+		"bolat": helperStudent("bolat", "2025-winter", 9000, 5.0), // другой поток
+		"erlan": helperStudent("erlan", "2024-spring", 2000, 5.0), // опережает меньше чем на уровень
+		"nurs":  helperStudent("nurs", "2024-spring", 5000, 5.0),
+	}
+	students["nurs"].Preferences.HelpRequests = false
+	handler := newFindHelpers(students, nil, "aida")
+
+	result, err := handler.Handle(context.Background(), FindHelpersQuery{RequesterID: "dana", TaskID: "go-reloaded"})
+	require.NoError(t, err)
+
+	assert.True(t, result.MatchedByXP)
+	assert.Zero(t, result.TotalSolvers)
+	assert.ElementsMatch(t, []string{"arman", "aida"}, helperIDs(result))
+	for _, h := range result.Helpers {
+		require.NotEmpty(t, h.MatchReasons)
+		switch h.StudentID {
+		case "arman":
+			assert.Equal(t, "опытнее на 1500 XP", h.MatchReasons[0])
+			assert.Contains(t, h.MatchReasons, "отличный рейтинг")
+		case "aida":
+			assert.Equal(t, "опытнее на 1100 XP", h.MatchReasons[0])
+			assert.True(t, h.IsOnline)
+		}
+	}
+}
+
+func TestFindHelpers_SeniorPoolIsLimitedAndNotReloaded(t *testing.T) {
+	students := map[string]*student.Student{
+		"dana": helperStudent("dana", "2024-spring", 1500, 0),
+	}
+	for i := 0; i < findHelpersCandidatePool+20; i++ {
+		id := fmt.Sprintf("senior-%03d", i)
+		students[id] = helperStudent(id, "2024-spring", student.XP(3000+i), 4.0)
+	}
+	handler := newFindHelpers(students, nil)
+	repo := handler.studentRepo.(*helperStudents)
+
+	result, err := handler.Handle(context.Background(), FindHelpersQuery{RequesterID: "dana", TaskID: "go-reloaded"})
+	require.NoError(t, err)
+
+	assert.True(t, result.MatchedByXP)
+	assert.Equal(t, findHelpersCandidatePool, result.TotalFound)
+	// Загружается только запросивший: кандидаты уже пришли из выборки по потоку
+	assert.Equal(t, 1, repo.lookups)
+}
+
+func TestFindHelpers_OrderIsDeterministic(t *testing.T) {
+	students := map[string]*student.Student{
+		"dana":  helperStudent("dana", "2024-spring", 1500, 0),
+		"c":     helperStudent("c", "2024-spring", 3000, 4.0),
+		"a":     helperStudent("a", "2024-spring", 3000, 4.0),
+		"b":     helperStudent("b", "2024-spring", 3000, 4.0),
+		"rated": helperStudent("rated", "2024-spring", 3000, 4.9),
+	}
+	solvers := []activity.StudentID{"c", "dana", "a", "rated", "b"}
+
+	var orders [][]string
+	for _, order := range [][]activity.StudentID{solvers, {"b", "rated", "a", "dana", "c"}} {
+		result, err := newFindHelpers(students, order).Handle(context.Background(), FindHelpersQuery{
+			RequesterID: "dana", TaskID: "go-reloaded", Limit: 10,
+		})
+		require.NoError(t, err)
+		assert.False(t, result.MatchedByXP)
+		orders = append(orders, helperIDs(result))
+	}
+
+	// Сам запросивший не попадает в список; равные кандидаты идут по ID
+	assert.Equal(t, []string{"rated", "a", "b", "c"}, orders[0])
+	assert.Equal(t, orders[0], orders[1])
+}
+
+func TestFindHelpers_NoSeniorStudentsKeepsFirstSolverMessage(t *testing.T) {
+	students := map[string]*student.Student{
+		"dana": helperStudent("dana", "2024-spring", 1500, 0),
+	}
+
+	result, err := newFindHelpers(students, nil).Handle(context.Background(), FindHelpersQuery{RequesterID: "dana", TaskID: "go-reloaded"})
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Helpers)
+	assert.Contains(t, result.Message, "Ты можешь стать первым")
+}
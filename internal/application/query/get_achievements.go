@@ -0,0 +1,203 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GET ACHIEVEMENTS QUERY
+// Достижения студента и прогресс по двум рейтинговым лестницам: общей
+// и лестнице своего потока. Для новых потоков общий топ-10 почти недостижим,
+// поэтому рядом показываем цель, до которой реально дотянуться.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// GetAchievementsQuery содержит параметры запроса достижений.
+type GetAchievementsQuery struct {
+	// StudentID - внутренний ID студента.
+	StudentID string
+
+	// TelegramID - альтернативная идентификация.
+	TelegramID int64
+}
+
+// Validate проверяет корректность параметров.
+func (q GetAchievementsQuery) Validate() error {
+	if q.StudentID == "" && q.TelegramID == 0 {
+		return errors.New("either student_id or telegram_id must be provided")
+	}
+	if q.TelegramID != 0 && !student.TelegramID(q.TelegramID).IsValid() {
+		return student.ErrInvalidTelegramID
+	}
+	return nil
+}
+
+// AchievementDTO - DTO полученного или доступного достижения.
+type AchievementDTO struct {
+	Type        string     `json:"type"`
+	Emoji       string     `json:"emoji"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	XPBonus     int        `json:"xp_bonus"`
+	UnlockedAt  *time.Time `json:"unlocked_at,omitempty"`
+}
+
+// LadderStepDTO - ступень рейтинговой лестницы.
+type LadderStepDTO struct {
+	AchievementDTO
+	MaxRank int `json:"max_rank"`
+
+	// PositionsToGo - сколько мест осталось подняться (0 - уже достигнуто или ранг неизвестен).
+	PositionsToGo int `json:"positions_to_go"`
+}
+
+// RankLadderDTO - рейтинговая лестница с текущим местом студента.
+type RankLadderDTO struct {
+	Cohort string          `json:"cohort"`
+	Rank   int             `json:"rank"`
+	Total  int             `json:"total"`
+	Steps  []LadderStepDTO `json:"steps"`
+}
+
+// GetAchievementsResult содержит результат запроса.
+type GetAchievementsResult struct {
+	StudentID    string           `json:"student_id"`
+	Unlocked     []AchievementDTO `json:"unlocked"`
+	TotalCount   int              `json:"total_count"`
+	GlobalLadder RankLadderDTO    `json:"global_ladder"`
+	CohortLadder RankLadderDTO    `json:"cohort_ladder"`
+}
+
+// GetAchievementsHandler обрабатывает запросы достижений.
+type GetAchievementsHandler struct {
+	studentRepo     student.Repository
+	progressRepo    student.ProgressRepository
+	leaderboardRepo leaderboard.LeaderboardRepository
+}
+
+// NewGetAchievementsHandler создаёт новый обработчик.
+func NewGetAchievementsHandler(
+	studentRepo student.Repository,
+	progressRepo student.ProgressRepository,
+	leaderboardRepo leaderboard.LeaderboardRepository,
+) *GetAchievementsHandler {
+	return &GetAchievementsHandler{
+		studentRepo:     studentRepo,
+		progressRepo:    progressRepo,
+		leaderboardRepo: leaderboardRepo,
+	}
+}
+
+// Handle выполняет запрос.
+func (h *GetAchievementsHandler) Handle(ctx context.Context, query GetAchievementsQuery) (*GetAchievementsResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, shared.WrapError("query", "GetAchievements", shared.ErrValidation, err.Error(), err)
+	}
+
+	stud, err := h.resolveStudent(ctx, query)
+	if err != nil {
+		return nil, shared.WrapError("query", "GetAchievements", shared.ErrNotFound, "student not found", err)
+	}
+
+	achievements, err := h.progressRepo.GetAchievements(ctx, stud.ID)
+	if err != nil {
+		return nil, fmt.Errorf("load achievements: %w", err)
+	}
+
+	unlockedAt := make(map[student.AchievementType]time.Time, len(achievements))
+	for _, a := range achievements {
+		unlockedAt[a.Type] = a.UnlockedAt
+	}
+
+	globalRank, globalTotal := h.loadRank(ctx, stud.ID, leaderboard.CohortAll)
+
+	// Без потока (CohortAll == "") рейтинг потока совпал бы с общим
+	var cohortRank, cohortTotal int
+	if stud.Cohort != "" {
+		cohortRank, cohortTotal = h.loadRank(ctx, stud.ID, leaderboard.Cohort(stud.Cohort))
+	}
+
+	result := &GetAchievementsResult{
+		StudentID:    stud.ID,
+		Unlocked:     make([]AchievementDTO, 0, len(achievements)),
+		TotalCount:   len(student.GetAchievementDefinitions()),
+		GlobalLadder: buildLadder(leaderboard.CohortAll, globalRank, globalTotal, student.GlobalRankLadder, unlockedAt),
+		CohortLadder: buildLadder(leaderboard.Cohort(stud.Cohort), cohortRank, cohortTotal, student.CohortRankLadder, unlockedAt),
+	}
+	for _, a := range achievements {
+		result.Unlocked = append(result.Unlocked, toAchievementDTO(a.Type, unlockedAt))
+	}
+
+	return result, nil
+}
+
+// loadRank возвращает место студента и размер лидерборда (0, если неизвестно).
+func (h *GetAchievementsHandler) loadRank(ctx context.Context, studentID string, cohort leaderboard.Cohort) (rank, total int) {
+	if h.leaderboardRepo == nil {
+		return 0, 0
+	}
+	if entry, err := h.leaderboardRepo.GetStudentRank(ctx, studentID, cohort); err == nil && entry != nil {
+		rank = int(entry.Rank)
+	}
+	if n, err := h.leaderboardRepo.GetTotalCount(ctx, cohort); err == nil {
+		total = n
+	}
+	return rank, total
+}
+
+// buildLadder строит рейтинговую лестницу с текущим местом студента.
+func buildLadder(
+	cohort leaderboard.Cohort,
+	rank, total int,
+	steps []student.RankAchievementStep,
+	unlockedAt map[student.AchievementType]time.Time,
+) RankLadderDTO {
+	ladder := RankLadderDTO{
+		Cohort: string(cohort),
+		Rank:   rank,
+		Total:  total,
+		Steps:  make([]LadderStepDTO, 0, len(steps)),
+	}
+
+	for _, step := range steps {
+		dto := LadderStepDTO{
+			AchievementDTO: toAchievementDTO(step.Type, unlockedAt),
+			MaxRank:        step.MaxRank,
+		}
+		if dto.UnlockedAt == nil && ladder.Rank > step.MaxRank {
+			dto.PositionsToGo = ladder.Rank - step.MaxRank
+		}
+		ladder.Steps = append(ladder.Steps, dto)
+	}
+
+	return ladder
+}
+
+// resolveStudent находит студента по запросу.
+func (h *GetAchievementsHandler) resolveStudent(ctx context.Context, query GetAchievementsQuery) (*student.Student, error) {
+	if query.StudentID != "" {
+		return h.studentRepo.GetByID(ctx, query.StudentID)
+	}
+	return h.studentRepo.GetByTelegramID(ctx, student.TelegramID(query.TelegramID))
+}
+
+// toAchievementDTO преобразует тип достижения в DTO.
+func toAchievementDTO(t student.AchievementType, unlockedAt map[student.AchievementType]time.Time) AchievementDTO {
+	dto := AchievementDTO{Type: string(t), Emoji: "🏅", Name: string(t)}
+	if def, ok := student.GetAchievementDefinition(t); ok {
+		dto.Emoji = def.Emoji
+		dto.Name = def.Name
+		dto.Description = def.Description
+		dto.XPBonus = int(def.XPBonus)
+	}
+	if at, ok := unlockedAt[t]; ok {
+		dto.UnlockedAt = &at
+	}
+	return dto
+}
@@ -0,0 +1,131 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GET ACTIVITY CALENDAR QUERY
+// Календарь активности студента по дням (как "контрибуции" на GitHub) для
+// GET /api/v1/me/calendar: XP и задачи за каждый день последних недель,
+// включая дни без активности. Дни считаются в часовом поясе студента.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// DefaultActivityCalendarWeeks - период календаря по умолчанию.
+	DefaultActivityCalendarWeeks = 12
+
+	// MaxActivityCalendarWeeks - максимальный период календаря.
+	MaxActivityCalendarWeeks = 52
+)
+
+// GetActivityCalendarQuery содержит параметры запроса.
+type GetActivityCalendarQuery struct {
+	// StudentID - внутренний ID студента.
+	StudentID string
+
+	// Weeks - за сколько недель (0 = DefaultActivityCalendarWeeks).
+	Weeks int
+}
+
+// Validate проверяет корректность параметров.
+func (q *GetActivityCalendarQuery) Validate() error {
+	if q.StudentID == "" {
+		return fmt.Errorf("student_id is required")
+	}
+	if q.Weeks < 0 || q.Weeks > MaxActivityCalendarWeeks {
+		return fmt.Errorf("weeks must be between 1 and %d", MaxActivityCalendarWeeks)
+	}
+	if q.Weeks == 0 {
+		q.Weeks = DefaultActivityCalendarWeeks
+	}
+	return nil
+}
+
+// ActivityDayDTO - один день календаря.
+type ActivityDayDTO struct {
+	Date           string `json:"date"`
+	XPGained       int    `json:"xp_gained"`
+	TasksCompleted int    `json:"tasks_completed"`
+	Active         bool   `json:"active"`
+}
+
+// GetActivityCalendarResult содержит результат запроса.
+type GetActivityCalendarResult struct {
+	StudentID  string           `json:"student_id"`
+	From       string           `json:"from"`
+	To         string           `json:"to"`
+	ActiveDays int              `json:"active_days"`
+	TotalXP    int              `json:"total_xp"`
+	Days       []ActivityDayDTO `json:"days"`
+}
+
+// GetActivityCalendarHandler обрабатывает запросы календаря.
+type GetActivityCalendarHandler struct {
+	studentRepo  student.Repository
+	progressRepo student.ProgressRepository
+	now          func() time.Time
+}
+
+// NewGetActivityCalendarHandler создаёт новый обработчик.
+func NewGetActivityCalendarHandler(studentRepo student.Repository, progressRepo student.ProgressRepository) *GetActivityCalendarHandler {
+	return &GetActivityCalendarHandler{
+		studentRepo:  studentRepo,
+		progressRepo: progressRepo,
+		now:          time.Now,
+	}
+}
+
+// Handle выполняет запрос.
+func (h *GetActivityCalendarHandler) Handle(ctx context.Context, query GetActivityCalendarQuery) (*GetActivityCalendarResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, shared.WrapError("query", "GetActivityCalendar", shared.ErrValidation, err.Error(), err)
+	}
+
+	stud, err := h.studentRepo.GetByID(ctx, query.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	days := query.Weeks * 7
+	grinds, err := h.progressRepo.GetDailyGrindHistory(ctx, stud.ID, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily progress: %w", err)
+	}
+	byDate := make(map[string]*student.DailyGrind, len(grinds))
+	for _, g := range grinds {
+		byDate[g.Date.Format(time.DateOnly)] = g
+	}
+
+	// "Сегодня" - по часам студента; даты в daily_grinds - полночь UTC
+	local := h.now().In(stud.Preferences.Location())
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	from := today.AddDate(0, 0, -(days - 1))
+
+	result := &GetActivityCalendarResult{
+		StudentID: stud.ID,
+		From:      from.Format(time.DateOnly),
+		To:        today.Format(time.DateOnly),
+		Days:      make([]ActivityDayDTO, 0, days),
+	}
+	for d := from; !d.After(today); d = d.AddDate(0, 0, 1) {
+		day := ActivityDayDTO{Date: d.Format(time.DateOnly)}
+		if g, ok := byDate[day.Date]; ok {
+			day.XPGained = int(g.XPGained)
+			day.TasksCompleted = g.TasksCompleted
+			day.Active = g.IsActive()
+		}
+		if day.Active {
+			result.ActiveDays++
+		}
+		result.TotalXP += day.XPGained
+		result.Days = append(result.Days, day)
+	}
+
+	return result, nil
+}
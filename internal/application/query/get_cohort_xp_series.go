@@ -0,0 +1,235 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GET COHORT XP SERIES QUERY
+// Временной ряд прогресса потоков для графиков дашборда: XP и активные
+// студенты по дням или ISO-неделям (время Алматы). Можно сравнить до трёх
+// потоков. Ряды кэшируются на CohortXPSeriesTTL по параметрам.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// CohortXPSeriesTTL - время жизни кэша ряда одного потока.
+	CohortXPSeriesTTL = time.Hour
+
+	// MaxXPSeriesCohorts - сколько потоков можно сравнить в одном запросе.
+	MaxXPSeriesCohorts = 3
+
+	// MaxXPSeriesDays - максимальная длина периода.
+	MaxXPSeriesDays = 366
+
+	// DefaultXPSeriesWeeks - период по умолчанию.
+	DefaultXPSeriesWeeks = 12
+)
+
+// CohortXPSeriesRepository - агрегация дневного прогресса по потоку.
+// Возвращает только шаги с данными.
+type CohortXPSeriesRepository interface {
+	GetCohortXPSeries(ctx context.Context, cohort student.Cohort, from, to time.Time, bucket student.XPSeriesBucket) ([]student.XPSeriesPoint, error)
+}
+
+// GetCohortXPSeriesQuery содержит параметры запроса.
+type GetCohortXPSeriesQuery struct {
+	// Cohorts - потоки (от 1 до MaxXPSeriesCohorts), у каждого свой ряд.
+	Cohorts []string
+
+	// From, To - первый и последний день (включительно, даты по Алматы).
+	// По умолчанию - последние DefaultXPSeriesWeeks недель по сегодня.
+	From time.Time
+	To   time.Time
+
+	// Bucket - шаг ряда; по умолчанию неделя.
+	Bucket student.XPSeriesBucket
+}
+
+// Validate проверяет корректность параметров.
+func (q *GetCohortXPSeriesQuery) Validate() error {
+	if len(q.Cohorts) == 0 {
+		return fmt.Errorf("at least one cohort is required")
+	}
+	if len(q.Cohorts) > MaxXPSeriesCohorts {
+		return fmt.Errorf("at most %d cohorts can be compared", MaxXPSeriesCohorts)
+	}
+	for _, c := range q.Cohorts {
+		if !student.Cohort(c).IsValid() {
+			return fmt.Errorf("invalid cohort %q", c)
+		}
+	}
+
+	if q.Bucket == "" {
+		q.Bucket = student.XPSeriesWeek
+	}
+	if !q.Bucket.IsValid() {
+		return fmt.Errorf("bucket must be day or week")
+	}
+	return nil
+}
+
+// validatePeriod проверяет период после подстановки значений по умолчанию.
+func validatePeriod(from, to time.Time) error {
+	if to.Before(from) {
+		return fmt.Errorf("to must not be before from")
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > MaxXPSeriesDays {
+		return fmt.Errorf("range must not exceed %d days", MaxXPSeriesDays)
+	}
+	return nil
+}
+
+// XPSeriesBucketDTO - одна точка ряда.
+type XPSeriesBucketDTO struct {
+	// Date - начало шага: день или понедельник недели (YYYY-MM-DD).
+	Date           string `json:"date"`
+	TotalXP        int    `json:"total_xp"`
+	ActiveStudents int    `json:"active_students"`
+}
+
+// CohortXPSeriesDTO - ряд одного потока.
+type CohortXPSeriesDTO struct {
+	Cohort  string              `json:"cohort"`
+	Buckets []XPSeriesBucketDTO `json:"buckets"`
+}
+
+// GetCohortXPSeriesResult содержит результат запроса.
+type GetCohortXPSeriesResult struct {
+	Bucket student.XPSeriesBucket `json:"bucket"`
+	From   string                 `json:"from"`
+	To     string                 `json:"to"`
+
+	// Series - по ряду на поток в порядке запроса. Шаги без данных
+	// присутствуют с нулями.
+	Series []CohortXPSeriesDTO `json:"series"`
+}
+
+// cohortXPSeriesKey - параметры, по которым кэшируется ряд.
+type cohortXPSeriesKey struct {
+	cohort   string
+	bucket   student.XPSeriesBucket
+	from, to string
+}
+
+// cohortXPSeriesEntry - закэшированный ряд.
+type cohortXPSeriesEntry struct {
+	buckets   []XPSeriesBucketDTO
+	expiresAt time.Time
+}
+
+// GetCohortXPSeriesHandler обрабатывает запросы рядов XP потоков.
+type GetCohortXPSeriesHandler struct {
+	repo CohortXPSeriesRepository
+	now  func() time.Time
+
+	mu    sync.Mutex
+	cache map[cohortXPSeriesKey]cohortXPSeriesEntry
+}
+
+// NewGetCohortXPSeriesHandler создаёт новый обработчик.
+func NewGetCohortXPSeriesHandler(repo CohortXPSeriesRepository) *GetCohortXPSeriesHandler {
+	return &GetCohortXPSeriesHandler{
+		repo:  repo,
+		now:   time.Now,
+		cache: make(map[cohortXPSeriesKey]cohortXPSeriesEntry),
+	}
+}
+
+// Handle выполняет запрос.
+func (h *GetCohortXPSeriesHandler) Handle(ctx context.Context, query GetCohortXPSeriesQuery) (*GetCohortXPSeriesResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, shared.WrapError("query", "GetCohortXPSeries", shared.ErrValidation, err.Error(), err)
+	}
+
+	from, to := h.period(query)
+	if err := validatePeriod(from, to); err != nil {
+		return nil, shared.WrapError("query", "GetCohortXPSeries", shared.ErrValidation, err.Error(), err)
+	}
+
+	result := &GetCohortXPSeriesResult{
+		Bucket: query.Bucket,
+		From:   from.Format("2006-01-02"),
+		To:     to.Format("2006-01-02"),
+		Series: make([]CohortXPSeriesDTO, 0, len(query.Cohorts)),
+	}
+	for _, cohort := range query.Cohorts {
+		buckets, err := h.load(ctx, cohort, from, to, query.Bucket)
+		if err != nil {
+			return nil, err
+		}
+		result.Series = append(result.Series, CohortXPSeriesDTO{Cohort: cohort, Buckets: buckets})
+	}
+
+	return result, nil
+}
+
+// period возвращает границы периода как календарные даты по Алматы.
+// Для недель from сдвигается на понедельник, чтобы первая неделя была целой.
+func (h *GetCohortXPSeriesHandler) period(query GetCohortXPSeriesQuery) (from, to time.Time) {
+	to = student.SeriesDate(timeutil.ToAlmaty(h.now()))
+	if !query.To.IsZero() {
+		to = student.SeriesDate(query.To)
+	}
+
+	from = to.AddDate(0, 0, -7*DefaultXPSeriesWeeks+1)
+	if !query.From.IsZero() {
+		from = student.SeriesDate(query.From)
+	}
+	return query.Bucket.Start(from), to
+}
+
+// load возвращает заполненный ряд потока из кэша или из БД.
+func (h *GetCohortXPSeriesHandler) load(ctx context.Context, cohort string, from, to time.Time, bucket student.XPSeriesBucket) ([]XPSeriesBucketDTO, error) {
+	key := cohortXPSeriesKey{
+		cohort: cohort,
+		bucket: bucket,
+		from:   from.Format("2006-01-02"),
+		to:     to.Format("2006-01-02"),
+	}
+	now := h.now()
+
+	h.mu.Lock()
+	entry, ok := h.cache[key]
+	h.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.buckets, nil
+	}
+
+	points, err := h.repo.GetCohortXPSeries(ctx, student.Cohort(cohort), from, to, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get xp series of cohort %s: %w", cohort, err)
+	}
+	filled, err := student.FillXPSeries(points, from, to, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]XPSeriesBucketDTO, len(filled))
+	for i, p := range filled {
+		buckets[i] = XPSeriesBucketDTO{
+			Date:           p.Date.Format("2006-01-02"),
+			TotalXP:        int(p.TotalXP),
+			ActiveStudents: p.ActiveStudents,
+		}
+	}
+
+	h.mu.Lock()
+	// Ключи зависят от произвольных дат, поэтому протухшие записи
+	// вычищаются, чтобы кэш не рос без конца.
+	for k, e := range h.cache {
+		if !now.Before(e.expiresAt) {
+			delete(h.cache, k)
+		}
+	}
+	h.cache[key] = cohortXPSeriesEntry{buckets: buckets, expiresAt: now.Add(CohortXPSeriesTTL)}
+	h.mu.Unlock()
+
+	return buckets, nil
+}
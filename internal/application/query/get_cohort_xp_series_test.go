@@ -0,0 +1,137 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// fixtureXPSeries returns stored buckets per cohort, like the SQL aggregate:
+// only buckets with data, already truncated to the bucket start.
+type fixtureXPSeries struct {
+	points map[student.Cohort][]student.XPSeriesPoint
+	calls  int
+}
+
+func (f *fixtureXPSeries) GetCohortXPSeries(_ context.Context, cohort student.Cohort, from, to time.Time, _ student.XPSeriesBucket) ([]student.XPSeriesPoint, error) {
+	f.calls++
+	var points []student.XPSeriesPoint
+	for _, p := range f.points[cohort] {
+		if !p.Date.Before(from) && !p.Date.After(to) {
+			points = append(points, p)
+		}
+	}
+	return points, nil
+}
+
+func xpSeriesDay(day int) time.Time {
+	return time.Date(2025, time.March, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestGetCohortXPSeries_MultiCohortShapeWithGaps(t *testing.T) {
+	repo := &fixtureXPSeries{points: map[student.Cohort][]student.XPSeriesPoint{
+		"2024-fall":   {{Date: xpSeriesDay(3), TotalXP: 1200, ActiveStudents: 10}, {Date: xpSeriesDay(17), TotalXP: 800, ActiveStudents: 7}},
+		"2025-spring": {{Date: xpSeriesDay(10), TotalXP: 300, ActiveStudents: 3}},
+	}}
+	h := NewGetCohortXPSeriesHandler(repo)
+
+	result, err := h.Handle(context.Background(), GetCohortXPSeriesQuery{
+		Cohorts: []string{"2024-fall", "2025-spring"},
+		From:    xpSeriesDay(5), // среда: ряд начинается с понедельника
+		To:      xpSeriesDay(20),
+	})
+	require.NoError(t, err)
+
+	body, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"bucket": "week",
+		"from": "2025-03-03",
+		"to": "2025-03-20",
+		"series": [
+			{"cohort": "2024-fall", "buckets": [
+				{"date": "2025-03-03", "total_xp": 1200, "active_students": 10},
+				{"date": "2025-03-10", "total_xp": 0, "active_students": 0},
+				{"date": "2025-03-17", "total_xp": 800, "active_students": 7}
+			]},
+			{"cohort": "2025-spring", "buckets": [
+				{"date": "2025-03-03", "total_xp": 0, "active_students": 0},
+				{"date": "2025-03-10", "total_xp": 300, "active_students": 3},
+				{"date": "2025-03-17", "total_xp": 0, "active_students": 0}
+			]}
+		]
+	}`, string(body))
+}
+
+func TestGetCohortXPSeries_DayBucketsDefaultToAlmatyToday(t *testing.T) {
+	repo := &fixtureXPSeries{points: map[student.Cohort][]student.XPSeriesPoint{
+		"2024-fall": {{Date: xpSeriesDay(20), TotalXP: 50, ActiveStudents: 1}},
+	}}
+	h := NewGetCohortXPSeriesHandler(repo)
+	// 20 марта 21:00 UTC - в Алматы уже 21 марта.
+	h.now = func() time.Time { return time.Date(2025, time.March, 20, 21, 0, 0, 0, time.UTC) }
+
+	result, err := h.Handle(context.Background(), GetCohortXPSeriesQuery{
+		Cohorts: []string{"2024-fall"},
+		From:    xpSeriesDay(19),
+		Bucket:  student.XPSeriesDay,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "2025-03-21", result.To)
+	assert.Equal(t, []XPSeriesBucketDTO{
+		{Date: "2025-03-19"},
+		{Date: "2025-03-20", TotalXP: 50, ActiveStudents: 1},
+		{Date: "2025-03-21"},
+	}, result.Series[0].Buckets)
+}
+
+func TestGetCohortXPSeries_CachesPerParams(t *testing.T) {
+	repo := &fixtureXPSeries{}
+	h := NewGetCohortXPSeriesHandler(repo)
+	now := time.Date(2025, time.March, 20, 12, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+
+	q := GetCohortXPSeriesQuery{Cohorts: []string{"2024-fall"}, From: xpSeriesDay(3), To: xpSeriesDay(20)}
+	for i := 0; i < 2; i++ {
+		_, err := h.Handle(context.Background(), q)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, repo.calls)
+
+	// Другие параметры - другой ключ; поток из кэша не запрашивается снова.
+	q.Cohorts = []string{"2024-fall", "2025-spring"}
+	_, err := h.Handle(context.Background(), q)
+	require.NoError(t, err)
+	assert.Equal(t, 2, repo.calls)
+
+	now = now.Add(CohortXPSeriesTTL)
+	_, err = h.Handle(context.Background(), q)
+	require.NoError(t, err)
+	assert.Equal(t, 4, repo.calls)
+}
+
+func TestGetCohortXPSeries_Validation(t *testing.T) {
+	h := NewGetCohortXPSeriesHandler(&fixtureXPSeries{})
+
+	tests := map[string]GetCohortXPSeriesQuery{
+		"no cohort":      {},
+		"four cohorts":   {Cohorts: []string{"2024-a", "2024-b", "2024-c", "2024-d"}},
+		"unknown bucket": {Cohorts: []string{"2024-fall"}, Bucket: "month"},
+		"reversed range": {Cohorts: []string{"2024-fall"}, From: xpSeriesDay(20), To: xpSeriesDay(3)},
+		"too long":       {Cohorts: []string{"2024-fall"}, From: xpSeriesDay(1).AddDate(-2, 0, 0), To: xpSeriesDay(1)},
+	}
+	for name, q := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := h.Handle(context.Background(), q)
+			assert.True(t, shared.IsValidation(err), "got %v", err)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/analytics"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GET COMMAND USAGE QUERY
+// Обезличенная статистика команд бота по дням: какие команды и кнопки
+// используют, а какие можно убрать. Данные попадают в PostgreSQL раз в сутки,
+// поэтому текущий день в статистике не виден.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// MaxCommandUsageRangeDays - максимальная длина запрашиваемого периода.
+// Соответствует сроку хранения статистики (~6 месяцев).
+const MaxCommandUsageRangeDays = 186
+
+// GetCommandUsageQuery содержит параметры запроса статистики.
+type GetCommandUsageQuery struct {
+	// From - первый день периода (включительно).
+	From time.Time
+
+	// To - последний день периода (включительно).
+	To time.Time
+
+	// Limit - максимальное число команд (0 - все).
+	Limit int
+}
+
+// Validate проверяет корректность параметров.
+func (q GetCommandUsageQuery) Validate() error {
+	if q.From.IsZero() || q.To.IsZero() {
+		return errors.New("from and to must be provided")
+	}
+	if q.To.Before(q.From) {
+		return errors.New("to must not be before from")
+	}
+	days := int(analytics.DayOf(q.To).Sub(analytics.DayOf(q.From)).Hours()/24) + 1
+	if days > MaxCommandUsageRangeDays {
+		return fmt.Errorf("range must not exceed %d days", MaxCommandUsageRangeDays)
+	}
+	if q.Limit < 0 {
+		return errors.New("limit must not be negative")
+	}
+	return nil
+}
+
+// UsagePointDTO - число вызовов за день.
+type UsagePointDTO struct {
+	Day   string `json:"day"`
+	Count int64  `json:"count"`
+}
+
+// CommandSeriesDTO - временной ряд одной команды.
+type CommandSeriesDTO struct {
+	Command string          `json:"command"`
+	Total   int64           `json:"total"`
+	Points  []UsagePointDTO `json:"points"`
+}
+
+// GetCommandUsageResult содержит результат запроса.
+type GetCommandUsageResult struct {
+	From     string             `json:"from"`
+	To       string             `json:"to"`
+	Total    int64              `json:"total"`
+	Commands []CommandSeriesDTO `json:"commands"`
+}
+
+// GetCommandUsageHandler обрабатывает запросы статистики команд.
+type GetCommandUsageHandler struct {
+	usageRepo analytics.UsageRepository
+}
+
+// NewGetCommandUsageHandler создаёт новый обработчик.
+func NewGetCommandUsageHandler(usageRepo analytics.UsageRepository) *GetCommandUsageHandler {
+	return &GetCommandUsageHandler{usageRepo: usageRepo}
+}
+
+// Handle выполняет запрос.
+func (h *GetCommandUsageHandler) Handle(ctx context.Context, query GetCommandUsageQuery) (*GetCommandUsageResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, shared.WrapError("query", "GetCommandUsage", shared.ErrValidation, err.Error(), err)
+	}
+
+	from, to := analytics.DayOf(query.From), analytics.DayOf(query.To)
+	usage, err := h.usageRepo.GetRange(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get command usage: %w", err)
+	}
+
+	series := analytics.BuildSeries(usage)
+	if query.Limit > 0 && len(series) > query.Limit {
+		series = series[:query.Limit]
+	}
+
+	result := &GetCommandUsageResult{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Commands: make([]CommandSeriesDTO, 0, len(series)),
+	}
+	for _, s := range series {
+		dto := CommandSeriesDTO{
+			Command: s.Command,
+			Total:   s.Total,
+			Points:  make([]UsagePointDTO, 0, len(s.Points)),
+		}
+		for _, p := range s.Points {
+			dto.Points = append(dto.Points, UsagePointDTO{Day: p.Day.Format("2006-01-02"), Count: p.Count})
+		}
+		result.Total += s.Total
+		result.Commands = append(result.Commands, dto)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GET CONNECTION MILESTONES QUERY
+// Напарники и подопечные студента и то, насколько каждый близок к вехе:
+// дню серии из StreakMilestones или следующему уровню. Тех, кто почти у
+// цели, можно подбодрить (команда Nudge) из сводки и /connections.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// GetConnectionMilestonesQuery содержит параметры запроса.
+type GetConnectionMilestonesQuery struct {
+	StudentID string
+}
+
+// ConnectionMilestoneDTO - связь студента с вехой собеседника.
+type ConnectionMilestoneDTO struct {
+	StudentID   string                `json:"student_id"`
+	DisplayName string                `json:"display_name"`
+	Type        social.ConnectionType `json:"type"`
+
+	// CurrentStreak - текущая серия собеседника.
+	CurrentStreak int `json:"current_streak"`
+
+	// Milestone - веха, до которой осталось чуть-чуть (nil - далеко).
+	Milestone *student.UpcomingMilestone `json:"milestone,omitempty"`
+}
+
+// GetConnectionMilestonesResult содержит связи в порядке репозитория.
+type GetConnectionMilestonesResult struct {
+	Connections []ConnectionMilestoneDTO `json:"connections"`
+}
+
+// NearMilestone возвращает связи, собеседник которых почти у цели.
+func (r *GetConnectionMilestonesResult) NearMilestone() []ConnectionMilestoneDTO {
+	var near []ConnectionMilestoneDTO
+	for _, c := range r.Connections {
+		if c.Milestone != nil {
+			near = append(near, c)
+		}
+	}
+	return near
+}
+
+// GetConnectionMilestonesHandler обрабатывает запрос.
+type GetConnectionMilestonesHandler struct {
+	connections  social.ConnectionRepository
+	studentRepo  student.Repository
+	progressRepo student.ProgressRepository
+	clock        shared.Clock
+}
+
+// NewGetConnectionMilestonesHandler создаёт новый обработчик.
+func NewGetConnectionMilestonesHandler(
+	connections social.ConnectionRepository,
+	studentRepo student.Repository,
+	progressRepo student.ProgressRepository,
+) *GetConnectionMilestonesHandler {
+	return &GetConnectionMilestonesHandler{
+		connections:  connections,
+		studentRepo:  studentRepo,
+		progressRepo: progressRepo,
+		clock:        shared.SystemClock{},
+	}
+}
+
+// WithClock заменяет системные часы (для тестов).
+func (h *GetConnectionMilestonesHandler) WithClock(clock shared.Clock) *GetConnectionMilestonesHandler {
+	h.clock = shared.ClockOrSystem(clock)
+	return h
+}
+
+// Handle выполняет запрос.
+func (h *GetConnectionMilestonesHandler) Handle(ctx context.Context, q GetConnectionMilestonesQuery) (*GetConnectionMilestonesResult, error) {
+	if q.StudentID == "" {
+		return nil, shared.WrapError("query", "GetConnectionMilestones", shared.ErrValidation, "student_id is required", nil)
+	}
+
+	self := social.StudentID(q.StudentID)
+	conns, err := h.connections.GetActiveByStudentID(ctx, self)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connections: %w", err)
+	}
+
+	result := &GetConnectionMilestonesResult{Connections: []ConnectionMilestoneDTO{}}
+	seen := make(map[social.StudentID]bool)
+	for _, conn := range conns {
+		other := conn.GetOtherStudent(self)
+		if !conn.IsActive() || !conn.Type.AllowsNudges() || other == self || seen[other] {
+			continue
+		}
+		seen[other] = true
+
+		stud, err := h.studentRepo.GetByID(ctx, string(other))
+		if err != nil {
+			// Удалённый или слитый студент - просто не показываем
+			continue
+		}
+
+		dto := ConnectionMilestoneDTO{
+			StudentID:   stud.ID,
+			DisplayName: stud.DisplayName,
+			Type:        conn.Type,
+		}
+		streak := h.streakOf(ctx, stud)
+		if streak != nil && !streak.IsBroken() {
+			dto.CurrentStreak = streak.CurrentStreak
+		}
+		if milestone, ok := student.NearMilestone(stud.CurrentXP, streak); ok {
+			dto.Milestone = &milestone
+		}
+		result.Connections = append(result.Connections, dto)
+	}
+
+	return result, nil
+}
+
+// streakOf возвращает серию студента в его часовом поясе (nil - серии нет).
+func (h *GetConnectionMilestonesHandler) streakOf(ctx context.Context, stud *student.Student) *student.Streak {
+	if h.progressRepo == nil {
+		return nil
+	}
+	streak, err := h.progressRepo.GetStreak(ctx, stud.ID)
+	if err != nil || streak == nil {
+		return nil
+	}
+	return streak.WithClock(h.clock).WithLocation(stud.Preferences.Location())
+}
@@ -33,6 +33,10 @@ type GetDailyProgressQuery struct {
 	// TelegramID - альтернативная идентификация.
 	TelegramID int64
 
+	// Student - уже загруженный студент (опционально): тогда он не читается
+	// из репозитория повторно, StudentID и TelegramID не нужны.
+	Student *student.Student
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// Период
 	// ─────────────────────────────────────────────────────────────────────────
@@ -65,9 +69,12 @@ type GetDailyProgressQuery struct {
 
 // Validate проверяет корректность параметров.
 func (q *GetDailyProgressQuery) Validate() error {
-	if q.StudentID == "" && q.TelegramID == 0 {
+	if q.StudentID == "" && q.TelegramID == 0 && q.Student == nil {
 		return errors.New("either student_id or telegram_id must be provided")
 	}
+	if q.TelegramID != 0 && !student.TelegramID(q.TelegramID).IsValid() {
+		return student.ErrInvalidTelegramID
+	}
 	if q.Date.IsZero() {
 		q.Date = time.Now().UTC()
 	}
@@ -224,6 +231,27 @@ type ComparisonDTO struct {
 	Message string `json:"message"`
 }
 
+// PersonalBestDTO - личный рекорд.
+type PersonalBestDTO struct {
+	// Metric - вид рекорда: "best_day_xp", "best_week_xp", "longest_session_minutes".
+	Metric string `json:"metric"`
+
+	// Label - название рекорда.
+	Label string `json:"label"`
+
+	// Value - значение (XP или минуты).
+	Value int `json:"value"`
+
+	// ValueFormatted - форматированное значение.
+	ValueFormatted string `json:"value_formatted"`
+
+	// AchievedOn - когда установлен.
+	AchievedOn time.Time `json:"achieved_on"`
+
+	// AchievedOnFormatted - форматированная дата.
+	AchievedOnFormatted string `json:"achieved_on_formatted"`
+}
+
 // GetDailyProgressResult содержит результат запроса.
 type GetDailyProgressResult struct {
 	// ─────────────────────────────────────────────────────────────────────────
@@ -276,6 +304,9 @@ type GetDailyProgressResult struct {
 	// WeeklyTasks - задач за неделю.
 	WeeklyTasks int `json:"weekly_tasks,omitempty"`
 
+	// PersonalBests - личные рекорды (только установленные).
+	PersonalBests []PersonalBestDTO `json:"personal_bests,omitempty"`
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// Метаданные
 	// ─────────────────────────────────────────────────────────────────────────
@@ -292,6 +323,7 @@ type GetDailyProgressHandler struct {
 	studentRepo     student.Repository
 	progressRepo    student.ProgressRepository
 	leaderboardRepo leaderboard.LeaderboardRepository
+	bestsRepo       student.PersonalBestRepository
 }
 
 // NewGetDailyProgressHandler создаёт новый обработчик.
@@ -307,6 +339,12 @@ func NewGetDailyProgressHandler(
 	}
 }
 
+// WithPersonalBests включает личные рекорды в результат.
+func (h *GetDailyProgressHandler) WithPersonalBests(repo student.PersonalBestRepository) *GetDailyProgressHandler {
+	h.bestsRepo = repo
+	return h
+}
+
 // Handle выполняет запрос.
 func (h *GetDailyProgressHandler) Handle(ctx context.Context, query GetDailyProgressQuery) (*GetDailyProgressResult, error) {
 	// Валидация
@@ -318,9 +356,12 @@ func (h *GetDailyProgressHandler) Handle(ctx context.Context, query GetDailyProg
 	var stud *student.Student
 	var err error
 
-	if query.StudentID != "" {
+	switch {
+	case query.Student != nil:
+		stud = query.Student
+	case query.StudentID != "":
 		stud, err = h.studentRepo.GetByID(ctx, query.StudentID)
-	} else {
+	default:
 		stud, err = h.studentRepo.GetByTelegramID(ctx, student.TelegramID(query.TelegramID))
 	}
 
@@ -328,11 +369,12 @@ func (h *GetDailyProgressHandler) Handle(ctx context.Context, query GetDailyProg
 		return nil, shared.WrapError("query", "GetDailyProgress", shared.ErrNotFound, "student not found", err)
 	}
 
-	// Получаем дневной прогресс
+	// Получаем дневной прогресс ("сегодня" - в часовом поясе студента)
 	todayGrind, err := h.progressRepo.GetTodayDailyGrind(ctx, stud.ID)
 	if err != nil {
 		// Создаём пустой, если не найден
 		todayGrind = student.NewDailyGrind(stud.ID, stud.CurrentXP, 0)
+		todayGrind.Date = student.LocalDate(time.Now(), stud.Preferences.Location())
 	}
 
 	// Получаем позицию в рейтинге
@@ -365,7 +407,12 @@ func (h *GetDailyProgressHandler) Handle(ctx context.Context, query GetDailyProg
 
 	// Получаем серию
 	if query.IncludeStreak {
-		result.Streak = h.getStreakInfo(ctx, stud.ID, todayDTO.IsActive)
+		result.Streak = h.getStreakInfo(ctx, stud, todayDTO.IsActive)
+	}
+
+	// Личные рекорды
+	if h.bestsRepo != nil {
+		result.PersonalBests = h.getPersonalBests(ctx, stud.ID)
 	}
 
 	// Сравнение со вчера
@@ -379,6 +426,37 @@ func (h *GetDailyProgressHandler) Handle(ctx context.Context, query GetDailyProg
 	return result, nil
 }
 
+// getPersonalBests возвращает рекорды в порядке student.PersonalBestMetrics.
+// Ошибка не ломает запрос: рекорды просто не показываются.
+func (h *GetDailyProgressHandler) getPersonalBests(ctx context.Context, studentID string) []PersonalBestDTO {
+	bests, err := h.bestsRepo.GetBests(ctx, studentID)
+	if err != nil {
+		return nil
+	}
+
+	byMetric := make(map[student.PersonalBestMetric]student.PersonalBest, len(bests))
+	for _, b := range bests {
+		byMetric[b.Metric] = b
+	}
+
+	var dtos []PersonalBestDTO
+	for _, metric := range student.PersonalBestMetrics {
+		b, ok := byMetric[metric]
+		if !ok {
+			continue
+		}
+		dtos = append(dtos, PersonalBestDTO{
+			Metric:              string(metric),
+			Label:               metric.Label(),
+			Value:               b.Value,
+			ValueFormatted:      metric.FormatValue(b.Value),
+			AchievedOn:          b.AchievedOn,
+			AchievedOnFormatted: formatDateRu(b.AchievedOn),
+		})
+	}
+	return dtos
+}
+
 // buildDailyProgressDTO строит DTO дневного прогресса.
 func (h *GetDailyProgressHandler) buildDailyProgressDTO(grind *student.DailyGrind, isToday bool) DailyProgressDTO {
 	dto := DailyProgressDTO{
@@ -450,8 +528,8 @@ func (h *GetDailyProgressHandler) calculateWeeklyStats(history []DailyProgressDT
 }
 
 // getStreakInfo получает информацию о серии.
-func (h *GetDailyProgressHandler) getStreakInfo(ctx context.Context, studentID string, isActiveToday bool) *StreakInfoDTO {
-	streak, err := h.progressRepo.GetStreak(ctx, studentID)
+func (h *GetDailyProgressHandler) getStreakInfo(ctx context.Context, stud *student.Student, isActiveToday bool) *StreakInfoDTO {
+	streak, err := h.progressRepo.GetStreak(ctx, stud.ID)
 	if err != nil || streak == nil {
 		return &StreakInfoDTO{
 			CurrentStreak: 0,
@@ -470,8 +548,10 @@ func (h *GetDailyProgressHandler) getStreakInfo(ctx context.Context, studentID s
 	// Проверяем риск потери серии
 	if !isActiveToday && streak.CurrentStreak > 0 {
 		info.IsAtRisk = true
-		endOfDay := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 23, 59, 59, 0, time.UTC)
-		info.HoursToSaveStreak = int(time.Until(endOfDay).Hours())
+		// День заканчивается в полночь по часовому поясу студента
+		now := time.Now().In(stud.Preferences.Location())
+		endOfDay := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
+		info.HoursToSaveStreak = int(endOfDay.Sub(now).Hours())
 	}
 
 	// Генерируем сообщение
@@ -0,0 +1,160 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/event"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GET EVENT LEADERBOARD QUERY
+// Лидерборды идущих челленджей: топ участников и место запросившего.
+// События могут пересекаться, поэтому возвращаются все идущие.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// DefaultEventTopLimit - сколько участников показывать в топе события.
+const DefaultEventTopLimit = 20
+
+// GetEventLeaderboardQuery содержит параметры запроса.
+type GetEventLeaderboardQuery struct {
+	// StudentID - внутренний ID запросившего (для его места), может быть пустым.
+	StudentID string
+
+	// Limit - размер топа (по умолчанию DefaultEventTopLimit).
+	Limit int
+}
+
+// EventLeaderboardEntry - строка лидерборда события.
+type EventLeaderboardEntry struct {
+	Rank        int
+	StudentID   string
+	DisplayName string
+	Score       int
+	IsMe        bool
+}
+
+// EventLeaderboard - лидерборд одного события.
+type EventLeaderboard struct {
+	// Event - событие.
+	Event *event.Event
+
+	// Top - первые участники.
+	Top []EventLeaderboardEntry
+
+	// Me - место запросившего; nil, если он ещё не набрал очков.
+	Me *event.Standing
+
+	// Participants - количество участников с очками.
+	Participants int
+}
+
+// GetEventLeaderboardResult содержит лидерборды идущих событий.
+type GetEventLeaderboardResult struct {
+	// Events - идущие события по началу окна; пусто, если челленджей нет.
+	Events []EventLeaderboard
+}
+
+// GetEventLeaderboardHandler обрабатывает запросы лидербордов событий.
+type GetEventLeaderboardHandler struct {
+	events      event.Repository
+	scoreboard  event.Scoreboard
+	studentRepo student.Repository
+	now         func() time.Time
+}
+
+// NewGetEventLeaderboardHandler создаёт новый обработчик.
+func NewGetEventLeaderboardHandler(
+	events event.Repository,
+	scoreboard event.Scoreboard,
+	studentRepo student.Repository,
+) *GetEventLeaderboardHandler {
+	return &GetEventLeaderboardHandler{
+		events:      events,
+		scoreboard:  scoreboard,
+		studentRepo: studentRepo,
+		now:         time.Now,
+	}
+}
+
+// Handle выполняет запрос.
+func (h *GetEventLeaderboardHandler) Handle(ctx context.Context, query GetEventLeaderboardQuery) (*GetEventLeaderboardResult, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultEventTopLimit
+	}
+
+	open, err := h.events.ListOpen(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	now := h.now()
+	result := &GetEventLeaderboardResult{}
+	for _, e := range open {
+		if !e.IsLive(now) {
+			continue
+		}
+		board, err := h.leaderboard(ctx, e, query.StudentID, limit)
+		if err != nil {
+			return nil, err
+		}
+		result.Events = append(result.Events, *board)
+	}
+
+	return result, nil
+}
+
+// leaderboard собирает лидерборд одного события.
+func (h *GetEventLeaderboardHandler) leaderboard(ctx context.Context, e *event.Event, studentID string, limit int) (*EventLeaderboard, error) {
+	top, err := h.scoreboard.Top(ctx, e.ID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event top: %w", err)
+	}
+
+	board := &EventLeaderboard{Event: e}
+	board.Participants, err = h.scoreboard.Count(ctx, e.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count event participants: %w", err)
+	}
+	if studentID != "" {
+		board.Me, err = h.scoreboard.Position(ctx, e.ID, studentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get event position: %w", err)
+		}
+	}
+
+	ids := make([]string, len(top))
+	for i, s := range top {
+		ids[i] = s.StudentID
+	}
+	names := make(map[string]string, len(top))
+	if len(ids) > 0 {
+		students, err := h.studentRepo.GetByIDs(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get students: %w", err)
+		}
+		for _, s := range students {
+			names[s.ID] = s.DisplayName
+		}
+	}
+
+	board.Top = make([]EventLeaderboardEntry, len(top))
+	for i, s := range top {
+		name := names[s.StudentID]
+		if name == "" {
+			name = "Студент"
+		}
+		board.Top[i] = EventLeaderboardEntry{
+			Rank:        s.Rank,
+			StudentID:   s.StudentID,
+			DisplayName: name,
+			Score:       s.Score,
+			IsMe:        s.StudentID == studentID,
+		}
+	}
+
+	return board, nil
+}
@@ -0,0 +1,177 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GET FEED QUERY
+// Лента сообщества для /feed и GET /api/v1/feed: последние события потока,
+// новые первыми. Листается по ID записи, поэтому новые записи не сдвигают
+// уже открытые страницы. Записи студентов, скрывшихся из рейтинга после
+// события, отбрасываются при чтении.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// feedMaxScans - сколько раз добирать страницу, если записи отброшены.
+const feedMaxScans = 3
+
+// GetFeedQuery содержит параметры запроса.
+type GetFeedQuery struct {
+	// Cohort - поток; пусто - все потоки.
+	Cohort string
+
+	// BeforeID - записи старше этой (курсор); 0 - с самых новых.
+	BeforeID int64
+
+	// Limit - размер страницы.
+	Limit int
+}
+
+// Validate проверяет корректность параметров.
+func (q *GetFeedQuery) Validate() error {
+	if q.Limit < 0 {
+		return fmt.Errorf("limit must not be negative")
+	}
+	if q.BeforeID < 0 {
+		return fmt.Errorf("before_id must not be negative")
+	}
+	q.Limit = social.FeedBounds.ClampLimit(q.Limit)
+	return nil
+}
+
+// FeedItemDTO - запись ленты.
+type FeedItemDTO struct {
+	ID        int64             `json:"id"`
+	Type      string            `json:"type"`
+	ActorID   string            `json:"actor_id"`
+	ActorName string            `json:"actor_name"`
+	Cohort    string            `json:"cohort"`
+	Text      string            `json:"text"`
+	Payload   map[string]string `json:"payload"`
+	CreatedAt time.Time         `json:"created_at"`
+
+	// Ago - когда, относительно момента запроса ("2 ч назад").
+	Ago string `json:"ago"`
+}
+
+// GetFeedResult содержит результат запроса.
+type GetFeedResult struct {
+	Items []FeedItemDTO `json:"items"`
+
+	// NextBeforeID - курсор следующей страницы; 0 - страниц больше нет.
+	NextBeforeID int64 `json:"-"`
+}
+
+// GetFeedHandler обрабатывает запросы ленты.
+type GetFeedHandler struct {
+	feed        social.FeedRepository
+	studentRepo student.Repository
+	now         func() time.Time
+}
+
+// NewGetFeedHandler создаёт новый обработчик.
+func NewGetFeedHandler(feed social.FeedRepository, studentRepo student.Repository) *GetFeedHandler {
+	return &GetFeedHandler{
+		feed:        feed,
+		studentRepo: studentRepo,
+		now:         time.Now,
+	}
+}
+
+// Handle выполняет запрос.
+func (h *GetFeedHandler) Handle(ctx context.Context, query GetFeedQuery) (*GetFeedResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, shared.WrapError("query", "GetFeed", shared.ErrValidation, err.Error(), err)
+	}
+
+	var (
+		visible   []*social.FeedItem
+		before    = query.BeforeID
+		exhausted bool
+	)
+
+	// Берём на одну запись больше, чтобы знать, есть ли следующая страница;
+	// если часть записей отброшена, добираем
+	for scan := 0; scan < feedMaxScans && len(visible) <= query.Limit; scan++ {
+		batch, err := h.feed.ListPublic(ctx, query.Cohort, before, query.Limit+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list feed: %w", err)
+		}
+
+		public, err := h.stillPublic(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		visible = append(visible, public...)
+
+		if len(batch) <= query.Limit {
+			exhausted = true
+			break
+		}
+		before = batch[len(batch)-1].ID
+	}
+
+	result := &GetFeedResult{Items: make([]FeedItemDTO, 0, min(len(visible), query.Limit))}
+	switch {
+	case len(visible) > query.Limit:
+		visible = visible[:query.Limit]
+		result.NextBeforeID = visible[len(visible)-1].ID
+	case !exhausted:
+		// Все просмотренные записи отброшены: продолжаем с последней
+		result.NextBeforeID = before
+	}
+
+	now := h.now()
+	for _, item := range visible {
+		result.Items = append(result.Items, FeedItemDTO{
+			ID:        item.ID,
+			Type:      string(item.Type),
+			ActorID:   string(item.ActorID),
+			ActorName: item.ActorName,
+			Cohort:    item.Cohort,
+			Text:      item.Summary(),
+			Payload:   item.Payload,
+			CreatedAt: item.CreatedAt,
+			Ago:       timeutil.FormatRelativeAt(item.CreatedAt, now),
+		})
+	}
+
+	return result, nil
+}
+
+// stillPublic отбрасывает записи студентов, которые с тех пор скрылись из
+// рейтинга или удалены.
+func (h *GetFeedHandler) stillPublic(ctx context.Context, items []*social.FeedItem) ([]*social.FeedItem, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, string(item.ActorID))
+	}
+	students, err := h.studentRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed actors: %w", err)
+	}
+
+	public := make(map[string]bool, len(students))
+	for _, s := range students {
+		public[s.ID] = s.IsOnPublicLeaderboard()
+	}
+
+	kept := make([]*social.FeedItem, 0, len(items))
+	for _, item := range items {
+		if public[string(item.ActorID)] {
+			kept = append(kept, item)
+		}
+	}
+	return kept, nil
+}
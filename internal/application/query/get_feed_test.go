@@ -0,0 +1,127 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// memoryFeed keeps feed items in memory, like feed_items.
+type memoryFeed struct {
+	social.FeedRepository
+	items []*social.FeedItem
+}
+
+func (f *memoryFeed) add(actor, cohort string, hidden bool, at time.Time) {
+	item, _ := social.NewFeedItem(social.FeedItemStudentJoined, social.StudentID(actor), actor, cohort, nil, hidden, at)
+	item.ID = int64(len(f.items) + 1)
+	f.items = append(f.items, item)
+}
+
+func (f *memoryFeed) ListPublic(_ context.Context, cohort string, beforeID int64, limit int) ([]*social.FeedItem, error) {
+	var result []*social.FeedItem
+	for _, item := range f.items {
+		if item.IsPublic() && (cohort == "" || item.Cohort == cohort) && (beforeID == 0 || item.ID < beforeID) {
+			result = append(result, item)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID > result[j].ID })
+	return result[:min(limit, len(result))], nil
+}
+
+type feedStudents struct {
+	student.Repository
+	byID map[string]*student.Student
+}
+
+func (s *feedStudents) GetByIDs(_ context.Context, ids []string) ([]*student.Student, error) {
+	var result []*student.Student
+	for _, id := range ids {
+		if stud, ok := s.byID[id]; ok {
+			result = append(result, stud)
+		}
+	}
+	return result, nil
+}
+
+func newFeedStudent(id string, hidden bool) *student.Student {
+	s := &student.Student{ID: id, DisplayName: id}
+	s.Preferences = student.DefaultNotificationPreferences()
+	s.Preferences.HideFromLeaderboard = hidden
+	return s
+}
+
+func TestGetFeed_PrivacyFiltering(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	feed := &memoryFeed{}
+	feed.add("dana", "2025", false, now.Add(-2*time.Hour))
+	feed.add("shadow", "2025", true, now.Add(-time.Hour)) // скрыт с самого начала
+	feed.add("late", "2025", false, now.Add(-30*time.Minute))
+	feed.add("arman", "2024", false, now.Add(-10*time.Minute))
+
+	students := &feedStudents{byID: map[string]*student.Student{
+		"dana":   newFeedStudent("dana", false),
+		"shadow": newFeedStudent("shadow", true),
+		"late":   newFeedStudent("late", true), // скрылся после события
+		"arman":  newFeedStudent("arman", false),
+	}}
+	h := NewGetFeedHandler(feed, students)
+	h.now = func() time.Time { return now }
+
+	result, err := h.Handle(context.Background(), GetFeedQuery{Cohort: "2025"})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, "dana", result.Items[0].ActorID)
+	assert.Equal(t, "2 ч назад", result.Items[0].Ago)
+	assert.Zero(t, result.NextBeforeID)
+
+	all, err := h.Handle(context.Background(), GetFeedQuery{})
+	require.NoError(t, err)
+	require.Len(t, all.Items, 2)
+	assert.Equal(t, "arman", all.Items[0].ActorID)
+}
+
+func TestGetFeed_CursorStableWhileNewItemsArrive(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	feed := &memoryFeed{}
+	students := &feedStudents{byID: map[string]*student.Student{}}
+	for i := 1; i <= 7; i++ {
+		id := fmt.Sprintf("s%d", i)
+		students.byID[id] = newFeedStudent(id, i == 4)
+		feed.add(id, "2025", false, now.Add(time.Duration(i)*time.Minute))
+	}
+	h := NewGetFeedHandler(feed, students)
+
+	first, err := h.Handle(context.Background(), GetFeedQuery{Cohort: "2025", Limit: 3})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s7", "s6", "s5"}, feedActors(first))
+	require.NotZero(t, first.NextBeforeID)
+
+	// Новые записи появились между страницами
+	for i := 8; i <= 9; i++ {
+		id := fmt.Sprintf("s%d", i)
+		students.byID[id] = newFeedStudent(id, false)
+		feed.add(id, "2025", false, now.Add(time.Duration(i)*time.Minute))
+	}
+
+	second, err := h.Handle(context.Background(), GetFeedQuery{Cohort: "2025", Limit: 3, BeforeID: first.NextBeforeID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s3", "s2", "s1"}, feedActors(second), "s4 is hidden, nothing repeats or is skipped")
+	assert.Zero(t, second.NextBeforeID)
+}
+
+func feedActors(result *GetFeedResult) []string {
+	actors := make([]string, 0, len(result.Items))
+	for _, item := range result.Items {
+		actors = append(actors, item.ActorID)
+	}
+	return actors
+}
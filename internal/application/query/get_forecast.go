@@ -0,0 +1,194 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/forecast"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GET FORECAST QUERY
+// "В таком темпе ты дойдёшь до цели к <дате>". Темп - средний XP за последние
+// активные дни (daily_grinds), расчёт - в pkg/forecast. Целью может быть XP,
+// уровень или позиция в общем рейтинге.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ForecastTarget - вид цели прогноза.
+type ForecastTarget string
+
+const (
+	// ForecastTargetXP - достичь заданного XP.
+	ForecastTargetXP ForecastTarget = "xp"
+
+	// ForecastTargetLevel - достичь уровня.
+	ForecastTargetLevel ForecastTarget = "level"
+
+	// ForecastTargetRank - догнать XP того, кто сейчас занимает позицию.
+	ForecastTargetRank ForecastTarget = "rank"
+)
+
+// forecastHistoryDays - сколько последних дней прогресса загружается.
+const forecastHistoryDays = int(forecast.Lookback / (24 * time.Hour))
+
+// GetForecastQuery содержит параметры запроса прогноза.
+type GetForecastQuery struct {
+	// StudentID - внутренний ID студента.
+	StudentID string
+
+	// Target - вид цели (пустой = следующий уровень).
+	Target ForecastTarget
+
+	// Value - XP, уровень или позиция (для пустого Target не нужен).
+	Value int
+}
+
+// Validate проверяет корректность параметров запроса.
+func (q GetForecastQuery) Validate() error {
+	if q.StudentID == "" {
+		return shared.WrapError("query", "GetForecast", shared.ErrValidation, "student_id is required", nil)
+	}
+	switch q.Target {
+	case "":
+		return nil
+	case ForecastTargetXP, ForecastTargetLevel, ForecastTargetRank:
+		if q.Value <= 0 {
+			return shared.WrapError("query", "GetForecast", shared.ErrValidation, "target value must be positive", nil)
+		}
+		return nil
+	default:
+		return shared.WrapError("query", "GetForecast", shared.ErrValidation,
+			fmt.Sprintf("unknown forecast target %q", q.Target), nil)
+	}
+}
+
+// GetForecastResult содержит прогноз.
+type GetForecastResult struct {
+	// Target и Value - цель прогноза (Value заполнен и для следующего уровня).
+	Target ForecastTarget `json:"target"`
+	Value  int            `json:"value"`
+
+	// CurrentXP - XP студента сейчас.
+	CurrentXP student.XP `json:"current_xp"`
+
+	// TargetXP - XP, который нужно набрать.
+	TargetXP student.XP `json:"target_xp"`
+
+	// RankHolder - кто сейчас на целевой позиции (для ForecastTargetRank).
+	// Его XP - статичный ориентир: он тоже растёт, прогноз этого не учитывает.
+	RankHolder *ForecastRankHolder `json:"rank_holder,omitempty"`
+
+	// Pace - темп за последние активные дни.
+	Pace forecast.Pace `json:"pace"`
+
+	// Forecast - прогноз даты.
+	Forecast forecast.Forecast `json:"forecast"`
+}
+
+// ForecastRankHolder - студент на целевой позиции в момент прогноза.
+type ForecastRankHolder struct {
+	StudentID   string     `json:"student_id"`
+	DisplayName string     `json:"display_name"`
+	XP          student.XP `json:"xp"`
+}
+
+// GetForecastHandler обрабатывает запросы прогноза.
+type GetForecastHandler struct {
+	studentRepo     student.Repository
+	progressRepo    student.ProgressRepository
+	leaderboardRepo leaderboard.LeaderboardRepository
+	now             func() time.Time
+}
+
+// NewGetForecastHandler создаёт новый обработчик.
+func NewGetForecastHandler(
+	studentRepo student.Repository,
+	progressRepo student.ProgressRepository,
+	leaderboardRepo leaderboard.LeaderboardRepository,
+) *GetForecastHandler {
+	return &GetForecastHandler{
+		studentRepo:     studentRepo,
+		progressRepo:    progressRepo,
+		leaderboardRepo: leaderboardRepo,
+		now:             time.Now,
+	}
+}
+
+// Handle выполняет запрос.
+func (h *GetForecastHandler) Handle(ctx context.Context, query GetForecastQuery) (*GetForecastResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	stud, err := h.studentRepo.GetByID(ctx, query.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetForecastResult{
+		Target:    query.Target,
+		Value:     query.Value,
+		CurrentXP: stud.CurrentXP,
+	}
+
+	switch query.Target {
+	case "":
+		result.Target = ForecastTargetLevel
+		next := student.NextLevel(stud.CurrentXP)
+		result.Value = int(next)
+		result.TargetXP = next.MinXP()
+	case ForecastTargetXP:
+		result.TargetXP = student.XP(query.Value)
+	case ForecastTargetLevel:
+		result.TargetXP = student.Level(query.Value).MinXP()
+	case ForecastTargetRank:
+		holder, err := h.rankHolder(ctx, query.Value)
+		if err != nil {
+			return nil, err
+		}
+		if holder.StudentID == stud.ID {
+			// Сам студент на этой позиции - догонять некого
+			holder.XP = stud.CurrentXP
+		}
+		result.RankHolder = holder
+		result.TargetXP = holder.XP
+	}
+
+	now := h.now().UTC()
+	grinds, err := h.progressRepo.GetDailyGrindHistory(ctx, stud.ID, forecastHistoryDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily progress: %w", err)
+	}
+	days := make([]forecast.Day, 0, len(grinds))
+	for _, g := range grinds {
+		days = append(days, forecast.Day{Date: g.Date, XP: int64(g.XPGained)})
+	}
+
+	result.Pace = forecast.PaceOf(days, now)
+	result.Forecast = forecast.Estimate(int64(result.CurrentXP), int64(result.TargetXP), result.Pace, now)
+	return result, nil
+}
+
+// rankHolder возвращает студента на позиции общего рейтинга.
+func (h *GetForecastHandler) rankHolder(ctx context.Context, rank int) (*ForecastRankHolder, error) {
+	entries, err := h.leaderboardRepo.GetAfterRank(ctx, leaderboard.CohortAll, rank-1, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rank %d: %w", rank, err)
+	}
+	if len(entries) == 0 {
+		return nil, shared.WrapError("query", "GetForecast", shared.ErrNotFound,
+			fmt.Sprintf("rank %d is not on the leaderboard", rank), errors.New("rank out of range"))
+	}
+
+	entry := entries[0]
+	return &ForecastRankHolder{
+		StudentID:   entry.StudentID,
+		DisplayName: entry.DisplayName,
+		XP:          student.XP(entry.XP),
+	}, nil
+}
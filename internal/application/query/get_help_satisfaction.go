@@ -0,0 +1,121 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GET HELP SATISFACTION QUERY
+// Удовлетворённость помощью по закрытым опросам: средняя оценка и индекс
+// в духе NPS, общие и по потокам за каждую неделю. Отдельные оценки
+// никогда не отдаются, недели с малым числом ответов скрываются.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// DefaultHelpSatisfactionWeeks - период статистики по умолчанию.
+	DefaultHelpSatisfactionWeeks = 8
+
+	// MaxHelpSatisfactionWeeks - максимальный период статистики.
+	MaxHelpSatisfactionWeeks = 52
+)
+
+// GetHelpSatisfactionQuery содержит параметры запроса.
+type GetHelpSatisfactionQuery struct {
+	// Weeks - за сколько последних недель считать (1..MaxHelpSatisfactionWeeks,
+	// обычно DefaultHelpSatisfactionWeeks).
+	Weeks int
+}
+
+// Validate проверяет корректность параметров.
+func (q GetHelpSatisfactionQuery) Validate() error {
+	if q.Weeks < 1 || q.Weeks > MaxHelpSatisfactionWeeks {
+		return fmt.Errorf("weeks must be between 1 and %d", MaxHelpSatisfactionWeeks)
+	}
+	return nil
+}
+
+// WeeklySatisfactionDTO - удовлетворённость в одном потоке за неделю.
+type WeeklySatisfactionDTO struct {
+	Cohort       string  `json:"cohort"`
+	WeekStart    string  `json:"week_start"`
+	AverageScore float64 `json:"average_score"`
+	NetScore     int     `json:"net_score"`
+	Responses    int     `json:"responses"`
+}
+
+// GetHelpSatisfactionResult содержит результат запроса.
+type GetHelpSatisfactionResult struct {
+	Since string `json:"since"`
+
+	// AverageScore - средняя оценка за период; nil, если ответов мало.
+	AverageScore *float64 `json:"average_score"`
+
+	// NetScore - доля пятёрок минус доля оценок 1-3; nil, если ответов мало.
+	NetScore *int `json:"net_score"`
+
+	Responses int                     `json:"responses"`
+	Weekly    []WeeklySatisfactionDTO `json:"weekly"`
+}
+
+// GetHelpSatisfactionHandler обрабатывает запросы статистики удовлетворённости.
+type GetHelpSatisfactionHandler struct {
+	feedback social.HelpFeedbackRepository
+	now      func() time.Time
+}
+
+// NewGetHelpSatisfactionHandler создаёт новый обработчик.
+func NewGetHelpSatisfactionHandler(feedback social.HelpFeedbackRepository) *GetHelpSatisfactionHandler {
+	return &GetHelpSatisfactionHandler{feedback: feedback, now: time.Now}
+}
+
+// Handle выполняет запрос.
+func (h *GetHelpSatisfactionHandler) Handle(ctx context.Context, query GetHelpSatisfactionQuery) (*GetHelpSatisfactionResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, shared.WrapError("query", "GetHelpSatisfaction", shared.ErrValidation, err.Error(), err)
+	}
+
+	since := startOfWeek(h.now().UTC()).AddDate(0, 0, -7*(query.Weeks-1))
+
+	stats, err := h.feedback.GetSatisfactionStats(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get satisfaction stats: %w", err)
+	}
+
+	result := &GetHelpSatisfactionResult{
+		Since:     since.Format("2006-01-02"),
+		Responses: stats.Responses,
+		Weekly:    make([]WeeklySatisfactionDTO, 0, len(stats.Weekly)),
+	}
+	if stats.Responses >= social.MinSatisfactionResponses {
+		average := roundScore(stats.AverageScore())
+		net := stats.NetScore()
+		result.AverageScore = &average
+		result.NetScore = &net
+	}
+	for _, w := range stats.Weekly {
+		// По паре ответов легко угадать, чья это оценка
+		if w.Responses < social.MinSatisfactionResponses {
+			continue
+		}
+		result.Weekly = append(result.Weekly, WeeklySatisfactionDTO{
+			Cohort:       w.Cohort,
+			WeekStart:    w.WeekStart.Format("2006-01-02"),
+			AverageScore: roundScore(w.AverageScore()),
+			NetScore:     w.NetScore(),
+			Responses:    w.Responses,
+		})
+	}
+
+	return result, nil
+}
+
+// roundScore округляет среднюю оценку до двух знаков.
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
@@ -25,12 +25,20 @@ type GetLeaderboardQuery struct {
 	// Cohort - фильтр по когорте (пустая строка = все когорты).
 	Cohort string
 
+	// Period - период рейтинга: "all" (по умолчанию) или "week" - по XP,
+	// набранному с понедельника по времени Алматы.
+	Period string
+
 	// Limit - количество записей (по умолчанию 20, максимум 100).
 	Limit int
 
 	// Offset - смещение для пагинации.
 	Offset int
 
+	// AfterRank - keyset-пагинация: записи с рангом больше указанного.
+	// Если задан, Offset игнорируется.
+	AfterRank int
+
 	// OnlyOnline - показывать только онлайн студентов.
 	OnlyOnline bool
 
@@ -39,6 +47,13 @@ type GetLeaderboardQuery struct {
 
 	// IncludeRankChange - включать информацию об изменении позиции.
 	IncludeRankChange bool
+
+	// ViewerID - ID студента, который смотрит рейтинг. Скрывшийся из
+	// рейтинга студент видит в нём себя, остальные - нет.
+	ViewerID string
+
+	// IncludeHidden - показывать всех, включая скрывшихся (для админки).
+	IncludeHidden bool
 }
 
 // Validate проверяет корректность параметров запроса.
@@ -46,15 +61,18 @@ func (q *GetLeaderboardQuery) Validate() error {
 	if q.Limit < 0 {
 		return errors.New("limit cannot be negative")
 	}
-	if q.Limit > 100 {
-		q.Limit = 100
-	}
-	if q.Limit == 0 {
-		q.Limit = 20
-	}
+	q.Limit = leaderboard.PageBounds.ClampLimit(q.Limit)
 	if q.Offset < 0 {
 		return errors.New("offset cannot be negative")
 	}
+	if q.AfterRank < 0 {
+		return errors.New("after_rank cannot be negative")
+	}
+	period, err := leaderboard.ParsePeriod(q.Period)
+	if err != nil {
+		return err
+	}
+	q.Period = string(period)
 	return nil
 }
 
@@ -95,6 +113,9 @@ type LeaderboardEntryDTO struct {
 
 	// LastSeenAt - время последней активности.
 	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+
+	// XPReviewPending - всплеск XP ждёт проверки куратора.
+	XPReviewPending bool `json:"xp_review_pending,omitempty"`
 }
 
 // GetLeaderboardResult содержит результат запроса лидерборда.
@@ -108,6 +129,12 @@ type GetLeaderboardResult struct {
 	// Cohort - когорта, по которой фильтровали (пустая = все).
 	Cohort string `json:"cohort"`
 
+	// Period - период рейтинга ("all" или "week").
+	Period string `json:"period"`
+
+	// Week - ISO-неделя недельного рейтинга ("2024-W07").
+	Week string `json:"week,omitempty"`
+
 	// OnlineCount - количество онлайн студентов.
 	OnlineCount int `json:"online_count"`
 
@@ -128,6 +155,10 @@ type GetLeaderboardResult struct {
 
 	// PageSize - размер страницы.
 	PageSize int `json:"page_size"`
+
+	// NextAfterRank - ранг последней записи страницы для запроса следующей
+	// (0, если записей больше нет).
+	NextAfterRank int `json:"-"`
 }
 
 // GetLeaderboardHandler обрабатывает запросы на получение лидерборда.
@@ -159,14 +190,23 @@ func (h *GetLeaderboardHandler) Handle(ctx context.Context, query GetLeaderboard
 
 	cohort := leaderboard.Cohort(query.Cohort)
 
-	// Попытка получить из кеша
-	cachedEntries, err := h.tryGetFromCache(ctx, cohort, query.Limit+query.Offset)
-	if err == nil && len(cachedEntries) > 0 {
-		return h.buildResult(ctx, cachedEntries, query, cohort)
+	if leaderboard.Period(query.Period) == leaderboard.PeriodWeek {
+		return h.handleWeekly(ctx, query, cohort)
+	}
+
+	// Глубокие страницы читаем по рангу, без OFFSET
+	if query.AfterRank > 0 {
+		return h.handleAfterRank(ctx, query, cohort)
 	}
 
-	// Получаем из репозитория
-	entries, err := h.leaderboardRepo.GetTop(ctx, cohort, query.Limit+query.Offset)
+	// Читаем из кеша, при промахе - из репозитория. Скрывшиеся студенты
+	// убираются до пагинации, поэтому страница не укорачивается
+	entries, err := h.fetchVisible(query, query.Limit+query.Offset, func(n int) ([]*leaderboard.LeaderboardEntry, error) {
+		if cached, err := h.tryGetFromCache(ctx, cohort, n); err == nil && len(cached) > 0 {
+			return cached, nil
+		}
+		return h.leaderboardRepo.GetTop(ctx, cohort, n)
+	})
 	if err != nil {
 		return nil, shared.WrapError("query", "GetLeaderboard", shared.ErrNotFound, "failed to get leaderboard", err)
 	}
@@ -178,7 +218,8 @@ func (h *GetLeaderboardHandler) Handle(ctx context.Context, query GetLeaderboard
 		// В production здесь был бы логгер
 	}
 
-	// Применяем фильтры
+	// Применяем настройки приватности и фильтры
+	entries = h.applyPrivacy(entries, query)
 	entries = h.applyFilters(entries, query)
 
 	// Применяем пагинацию
@@ -187,6 +228,133 @@ func (h *GetLeaderboardHandler) Handle(ctx context.Context, query GetLeaderboard
 	return h.buildResult(ctx, paginatedEntries, query, cohort)
 }
 
+// handleAfterRank возвращает страницу, следующую за рангом query.AfterRank.
+func (h *GetLeaderboardHandler) handleAfterRank(
+	ctx context.Context,
+	query GetLeaderboardQuery,
+	cohort leaderboard.Cohort,
+) (*GetLeaderboardResult, error) {
+	// Дочитываем вместо скрывшихся студентов, пока страница не заполнится
+	// или рейтинг не кончится. Следующая страница продолжается после
+	// последнего прочитанного ранга, даже если фильтры отбросили часть записей
+	entries := make([]*leaderboard.LeaderboardEntry, 0, query.Limit)
+	afterRank, lastRank := query.AfterRank, 0
+	for len(entries) < query.Limit {
+		want := query.Limit - len(entries)
+		batch, err := h.leaderboardRepo.GetAfterRank(ctx, cohort, afterRank, want)
+		if err != nil {
+			return nil, shared.WrapError("query", "GetLeaderboard", shared.ErrNotFound, "failed to get leaderboard", err)
+		}
+		entries = append(entries, h.applyPrivacy(batch, query)...)
+		if len(batch) < want {
+			lastRank = 0
+			break
+		}
+		afterRank = int(batch[len(batch)-1].Rank)
+		lastRank = afterRank
+	}
+
+	// Онлайн-статус не критичен
+	entries, _ = h.enrichWithOnlineStatus(ctx, entries)
+
+	entries = h.applyPrivacy(entries, query)
+	entries = h.applyFilters(entries, query)
+
+	query.Offset = query.AfterRank
+	result, err := h.buildResult(ctx, entries, query, cohort)
+	if err != nil {
+		return nil, err
+	}
+	result.HasMore = lastRank > 0 && lastRank < result.TotalCount
+	result.NextAfterRank = 0
+	if result.HasMore {
+		result.NextAfterRank = lastRank
+	}
+
+	return result, nil
+}
+
+// weeklyCacheTTL - время жизни недельного рейтинга в кеше.
+const weeklyCacheTTL = 5 * time.Minute
+
+// handleWeekly возвращает рейтинг по XP, набранному за текущую неделю.
+// Ранги недельного рейтинга идут подряд, поэтому AfterRank совпадает
+// со смещением.
+func (h *GetLeaderboardHandler) handleWeekly(
+	ctx context.Context,
+	query GetLeaderboardQuery,
+	cohort leaderboard.Cohort,
+) (*GetLeaderboardResult, error) {
+	if query.AfterRank > 0 {
+		query.Offset = query.AfterRank
+	}
+	week := leaderboard.WeekOf(time.Now())
+	limit := query.Offset + query.Limit
+
+	var total int
+	entries, err := h.fetchVisible(query, limit, func(n int) (entries []*leaderboard.LeaderboardEntry, err error) {
+		entries, total, err = h.weeklyTop(ctx, week, cohort, n)
+		return entries, err
+	})
+	if err != nil {
+		return nil, shared.WrapError("query", "GetLeaderboard", shared.ErrNotFound, "failed to get weekly leaderboard", err)
+	}
+
+	// Онлайн-статус не критичен
+	entries, _ = h.enrichWithOnlineStatus(ctx, entries)
+
+	entries = h.applyPrivacy(entries, query)
+	entries = h.applyFilters(entries, query)
+	entries = h.paginate(entries, query.Offset, query.Limit)
+
+	result, err := h.buildResult(ctx, entries, query, cohort)
+	if err != nil {
+		return nil, err
+	}
+	result.TotalCount = total
+	result.HasMore = query.Offset+query.Limit < total
+	result.NextAfterRank = 0
+	if result.HasMore {
+		result.NextAfterRank = query.Offset + query.Limit
+	}
+	result.Week = week
+
+	return result, nil
+}
+
+// weeklyTop читает недельный топ из кеша, а при промахе - из репозитория,
+// и кладёт его в кеш.
+func (h *GetLeaderboardHandler) weeklyTop(
+	ctx context.Context,
+	week string,
+	cohort leaderboard.Cohort,
+	limit int,
+) ([]*leaderboard.LeaderboardEntry, int, error) {
+	if h.leaderboardCache != nil {
+		if entries, total, err := h.leaderboardCache.GetCachedWeeklyTop(ctx, week, cohort, limit); err == nil {
+			return entries, total, nil
+		}
+	}
+
+	entries, err := h.leaderboardRepo.GetWeeklyTop(ctx, cohort, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := len(entries)
+	if total == limit {
+		if total, err = h.leaderboardRepo.GetWeeklyCount(ctx, cohort); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if h.leaderboardCache != nil {
+		// Кеш не критичен: следующий запрос просто сходит в базу
+		_ = h.leaderboardCache.SetCachedWeeklyTop(ctx, week, cohort, entries, total, weeklyCacheTTL)
+	}
+
+	return entries, total, nil
+}
+
 // tryGetFromCache пытается получить данные из кеша.
 func (h *GetLeaderboardHandler) tryGetFromCache(
 	ctx context.Context,
@@ -231,6 +399,56 @@ func (h *GetLeaderboardHandler) enrichWithOnlineStatus(
 	return entries, nil
 }
 
+// applyPrivacy убирает из выдачи скрывшихся студентов и прячет онлайн-статус
+// тех, кто его скрыл. Ранги не пересчитываются: скрытый студент занимает
+// своё место, просто его не видно.
+func (h *GetLeaderboardHandler) applyPrivacy(
+	entries []*leaderboard.LeaderboardEntry,
+	query GetLeaderboardQuery,
+) []*leaderboard.LeaderboardEntry {
+	if query.IncludeHidden {
+		return entries
+	}
+	return publicEntries(entries, query.ViewerID)
+}
+
+// fetchVisible читает записи через fetch, пока после скрытия приватных
+// записей не наберётся want видимых или рейтинг не кончится: каждый
+// следующий запрос длиннее на число скрытых.
+func (h *GetLeaderboardHandler) fetchVisible(
+	query GetLeaderboardQuery,
+	want int,
+	fetch func(n int) ([]*leaderboard.LeaderboardEntry, error),
+) ([]*leaderboard.LeaderboardEntry, error) {
+	n := want
+	for {
+		entries, err := fetch(n)
+		if err != nil {
+			return nil, err
+		}
+		visible := h.applyPrivacy(entries, query)
+		if len(visible) >= want || len(entries) < n {
+			return visible, nil
+		}
+		n += len(entries) - len(visible)
+	}
+}
+
+// publicEntries возвращает записи, которые можно показать студенту viewerID.
+func publicEntries(entries []*leaderboard.LeaderboardEntry, viewerID string) []*leaderboard.LeaderboardEntry {
+	visible := make([]*leaderboard.LeaderboardEntry, 0, len(entries))
+	for _, e := range entries {
+		if !e.VisibleTo(viewerID) {
+			continue
+		}
+		if !e.ShowsOnlineTo(viewerID) {
+			e.IsOnline = false
+		}
+		visible = append(visible, e)
+	}
+	return visible
+}
+
 // applyFilters применяет фильтры к записям.
 func (h *GetLeaderboardHandler) applyFilters(
 	entries []*leaderboard.LeaderboardEntry,
@@ -323,10 +541,16 @@ func (h *GetLeaderboardHandler) buildResult(
 
 	hasMore := query.Offset+len(entries) < totalCount
 
+	nextAfterRank := 0
+	if hasMore && len(entries) > 0 {
+		nextAfterRank = int(entries[len(entries)-1].Rank)
+	}
+
 	return &GetLeaderboardResult{
 		Entries:     dtos,
 		TotalCount:  totalCount,
 		Cohort:      string(cohort),
+		Period:      query.Period,
 		OnlineCount: onlineCount,
 		AverageXP:   avgXP,
 		MedianXP:    medianXP,
@@ -334,6 +558,8 @@ func (h *GetLeaderboardHandler) buildResult(
 		HasMore:     hasMore,
 		Page:        page,
 		PageSize:    query.Limit,
+
+		NextAfterRank: nextAfterRank,
 	}, nil
 }
 
@@ -351,6 +577,7 @@ func (h *GetLeaderboardHandler) toDTO(e *leaderboard.LeaderboardEntry) Leaderboa
 		IsOnline:           e.IsOnline,
 		IsAvailableForHelp: e.IsAvailableForHelp,
 		HelpRating:         e.HelpRating,
+		XPReviewPending:    e.XPReviewPending,
 	}
 
 	if !e.UpdatedAt.IsZero() {
@@ -0,0 +1,185 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+)
+
+type weeklyBoard struct {
+	leaderboard.LeaderboardRepository
+	weekly      []*leaderboard.LeaderboardEntry
+	weeklyCalls int
+}
+
+func (b *weeklyBoard) GetWeeklyTop(_ context.Context, _ leaderboard.Cohort, limit int) ([]*leaderboard.LeaderboardEntry, error) {
+	b.weeklyCalls++
+	entries := make([]*leaderboard.LeaderboardEntry, 0, limit)
+	for _, e := range b.weekly[:min(limit, len(b.weekly))] {
+		entries = append(entries, e.Clone())
+	}
+	return entries, nil
+}
+
+func (b *weeklyBoard) GetWeeklyCount(context.Context, leaderboard.Cohort) (int, error) {
+	return len(b.weekly), nil
+}
+
+func (b *weeklyBoard) GetTotalCount(context.Context, leaderboard.Cohort) (int, error) {
+	return 1000, nil // весь рейтинг, а не недельный
+}
+
+type weeklyCache struct {
+	leaderboard.LeaderboardCache
+	entries map[string][]*leaderboard.LeaderboardEntry
+	totals  map[string]int
+}
+
+func (c *weeklyCache) GetCachedWeeklyTop(_ context.Context, week string, cohort leaderboard.Cohort, limit int) ([]*leaderboard.LeaderboardEntry, int, error) {
+	key := week + ":" + cohort.String()
+	entries, ok := c.entries[key]
+	if !ok || (len(entries) < limit && len(entries) < c.totals[key]) {
+		return nil, 0, fmt.Errorf("cache miss")
+	}
+	return entries[:min(limit, len(entries))], c.totals[key], nil
+}
+
+func (c *weeklyCache) SetCachedWeeklyTop(_ context.Context, week string, cohort leaderboard.Cohort, entries []*leaderboard.LeaderboardEntry, total int, _ time.Duration) error {
+	key := week + ":" + cohort.String()
+	c.entries[key] = entries
+	c.totals[key] = total
+	return nil
+}
+
+func TestGetLeaderboard_WeeklyPeriod(t *testing.T) {
+	board := &weeklyBoard{}
+	for i := 1; i <= 25; i++ {
+		board.weekly = append(board.weekly, &leaderboard.LeaderboardEntry{
+			Rank: leaderboard.Rank(i), StudentID: fmt.Sprintf("s%d", i), XP: leaderboard.XP(500 - i*10),
+		})
+	}
+	cache := &weeklyCache{entries: map[string][]*leaderboard.LeaderboardEntry{}, totals: map[string]int{}}
+	h := NewGetLeaderboardHandler(board, cache, nil)
+	ctx := context.Background()
+
+	result, err := h.Handle(ctx, GetLeaderboardQuery{Period: "week", Limit: 10, Offset: 10})
+	require.NoError(t, err)
+	assert.Equal(t, "week", result.Period)
+	assert.Equal(t, leaderboard.WeekOf(time.Now()), result.Week)
+	assert.Equal(t, 25, result.TotalCount)
+	require.Len(t, result.Entries, 10)
+	assert.Equal(t, 11, result.Entries[0].Rank)
+	assert.Equal(t, 390, result.Entries[0].XP)
+	assert.True(t, result.HasMore)
+	assert.Equal(t, 20, result.NextAfterRank)
+	assert.Equal(t, 1, board.weeklyCalls)
+
+	// Первая страница уже в кеше; курсор продолжает с 21-го места
+	_, err = h.Handle(ctx, GetLeaderboardQuery{Period: "week", Limit: 10})
+	require.NoError(t, err)
+	result, err = h.Handle(ctx, GetLeaderboardQuery{Period: "week", Limit: 10, AfterRank: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 2, board.weeklyCalls)
+	require.Len(t, result.Entries, 5)
+	assert.Equal(t, 21, result.Entries[0].Rank)
+	assert.False(t, result.HasMore)
+	assert.Zero(t, result.NextAfterRank)
+
+	_, err = h.Handle(ctx, GetLeaderboardQuery{Period: "month"})
+	assert.Error(t, err)
+}
+
+// rankedBoard - общий рейтинг из последнего снапшота.
+type rankedBoard struct {
+	leaderboard.LeaderboardRepository
+	entries []*leaderboard.LeaderboardEntry
+}
+
+func (b *rankedBoard) GetTop(_ context.Context, _ leaderboard.Cohort, limit int) ([]*leaderboard.LeaderboardEntry, error) {
+	return b.GetAfterRank(context.Background(), "", 0, limit)
+}
+
+func (b *rankedBoard) GetAfterRank(_ context.Context, _ leaderboard.Cohort, afterRank, limit int) ([]*leaderboard.LeaderboardEntry, error) {
+	var entries []*leaderboard.LeaderboardEntry
+	for _, e := range b.entries {
+		if int(e.Rank) > afterRank && len(entries) < limit {
+			entries = append(entries, e.Clone())
+		}
+	}
+	return entries, nil
+}
+
+func (b *rankedBoard) GetTotalCount(context.Context, leaderboard.Cohort) (int, error) {
+	return len(b.entries), nil
+}
+
+func (b *rankedBoard) GetStudentRank(_ context.Context, studentID string, _ leaderboard.Cohort) (*leaderboard.LeaderboardEntry, error) {
+	for _, e := range b.entries {
+		if e.StudentID == studentID {
+			return e.Clone(), nil
+		}
+	}
+	return nil, nil
+}
+
+// newRankedBoard строит рейтинг из size мест, где скрыты места hidden.
+func newRankedBoard(size int, hidden ...int) *rankedBoard {
+	board := &rankedBoard{}
+	for i := 1; i <= size; i++ {
+		board.entries = append(board.entries, &leaderboard.LeaderboardEntry{
+			Rank: leaderboard.Rank(i), StudentID: fmt.Sprintf("s%d", i), XP: leaderboard.XP(1000 - i*10),
+		})
+	}
+	for _, rank := range hidden {
+		board.entries[rank-1].HiddenFromLeaderboard = true
+	}
+	return board
+}
+
+func ranksOf(entries []LeaderboardEntryDTO) []int {
+	ranks := make([]int, len(entries))
+	for i, e := range entries {
+		ranks[i] = e.Rank
+	}
+	return ranks
+}
+
+func TestGetLeaderboard_HiddenStudentsDoNotShortenPages(t *testing.T) {
+	board := newRankedBoard(12, 2, 3, 7)
+	h := NewGetLeaderboardHandler(board, nil, nil)
+	ctx := context.Background()
+
+	// Скрытые убираются до пагинации: страница полная, ранги не пересчитаны
+	result, err := h.Handle(ctx, GetLeaderboardQuery{Limit: 4})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 4, 5, 6}, ranksOf(result.Entries))
+
+	result, err = h.Handle(ctx, GetLeaderboardQuery{Limit: 4, Offset: 4})
+	require.NoError(t, err)
+	assert.Equal(t, []int{8, 9, 10, 11}, ranksOf(result.Entries))
+
+	// Курсор дочитывает вместо скрытых и продолжает после последнего ранга
+	result, err = h.Handle(ctx, GetLeaderboardQuery{Limit: 4, AfterRank: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []int{4, 5, 6, 8}, ranksOf(result.Entries))
+	assert.Equal(t, 8, result.NextAfterRank)
+
+	result, err = h.Handle(ctx, GetLeaderboardQuery{Limit: 4, AfterRank: 8})
+	require.NoError(t, err)
+	assert.Equal(t, []int{9, 10, 11, 12}, ranksOf(result.Entries))
+
+	// Скрывшийся студент видит себя, админка - всех
+	result, err = h.Handle(ctx, GetLeaderboardQuery{Limit: 4, ViewerID: "s2"})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 4, 5}, ranksOf(result.Entries))
+
+	result, err = h.Handle(ctx, GetLeaderboardQuery{Limit: 4, IncludeHidden: true})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4}, ranksOf(result.Entries))
+}
@@ -0,0 +1,142 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/pagination"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GET MONTHLY HELPERS QUERY
+// Топ помощников месяца для /top: кого чаще всех благодарили с начала
+// календарного месяца (по времени Алматы). В отличие от GetTopHelpers
+// (скользящее окно в днях, прямо из БД) рейтинг берётся из sorted set,
+// который обновляет задача track_helper_streaks, а рядом показывается
+// серия недель помощи.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// MonthlyHelpersBounds - ограничения числа помощников в ответе.
+var MonthlyHelpersBounds = pagination.Bounds{DefaultLimit: 10, MaxLimit: 50}
+
+// GetMonthlyHelpersQuery содержит параметры запроса.
+type GetMonthlyHelpersQuery struct {
+	// Limit - максимальное количество помощников.
+	Limit int
+
+	// ViewerID - кто смотрит: скрытый из рейтинга студент видит себя.
+	ViewerID string
+}
+
+// Validate проверяет корректность параметров.
+func (q *GetMonthlyHelpersQuery) Validate() error {
+	if q.Limit < 0 {
+		return fmt.Errorf("limit must not be negative")
+	}
+	q.Limit = MonthlyHelpersBounds.ClampLimit(q.Limit)
+	return nil
+}
+
+// MonthlyHelperDTO - строка топа помощников.
+type MonthlyHelperDTO struct {
+	Rank        int    `json:"rank"`
+	StudentID   string `json:"student_id"`
+	DisplayName string `json:"display_name"`
+
+	// Endorsements - благодарностей за месяц.
+	Endorsements int `json:"endorsements"`
+
+	// StreakWeeks - текущая серия недель помощи (0 - нет).
+	StreakWeeks int `json:"streak_weeks"`
+}
+
+// GetMonthlyHelpersResult содержит результат запроса.
+type GetMonthlyHelpersResult struct {
+	// Month - месяц рейтинга, например "Октябрь 2026".
+	Month string `json:"month"`
+
+	Helpers []MonthlyHelperDTO `json:"helpers"`
+}
+
+// GetMonthlyHelpersHandler обрабатывает запросы топа помощников месяца.
+type GetMonthlyHelpersHandler struct {
+	board       student.HelperBoard
+	streaks     student.HelperStreakRepository
+	studentRepo student.Repository
+	now         func() time.Time
+}
+
+// NewGetMonthlyHelpersHandler создаёт новый обработчик.
+// Без репозитория серий серия не показывается.
+func NewGetMonthlyHelpersHandler(
+	board student.HelperBoard,
+	streaks student.HelperStreakRepository,
+	studentRepo student.Repository,
+) *GetMonthlyHelpersHandler {
+	return &GetMonthlyHelpersHandler{
+		board:       board,
+		streaks:     streaks,
+		studentRepo: studentRepo,
+		now:         time.Now,
+	}
+}
+
+// Handle выполняет запрос.
+func (h *GetMonthlyHelpersHandler) Handle(ctx context.Context, query GetMonthlyHelpersQuery) (*GetMonthlyHelpersResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, shared.WrapError("query", "GetMonthlyHelpers", shared.ErrValidation, err.Error(), err)
+	}
+
+	now := h.now()
+	month := timeutil.ToAlmaty(now)
+
+	// Берём с запасом: скрытые из рейтинга студенты пропускаются
+	entries, err := h.board.TopOfMonth(ctx, now, MonthlyHelpersBounds.MaxLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monthly helpers: %w", err)
+	}
+
+	result := &GetMonthlyHelpersResult{
+		Month:   fmt.Sprintf("%s %d", timeutil.MonthNameRu(month.Month()), month.Year()),
+		Helpers: make([]MonthlyHelperDTO, 0, min(len(entries), query.Limit)),
+	}
+
+	rank, prev := 0, -1
+	for i, e := range entries {
+		if len(result.Helpers) == query.Limit {
+			break
+		}
+
+		// Одинаковое число благодарностей - одно место
+		if e.Endorsements != prev {
+			rank, prev = i+1, e.Endorsements
+		}
+
+		stud, err := h.studentRepo.GetByID(ctx, e.StudentID)
+		if err != nil {
+			continue
+		}
+		if !stud.IsOnPublicLeaderboard() && stud.ID != query.ViewerID {
+			continue
+		}
+
+		dto := MonthlyHelperDTO{
+			Rank:         rank,
+			StudentID:    stud.ID,
+			DisplayName:  stud.DisplayName,
+			Endorsements: e.Endorsements,
+		}
+		if h.streaks != nil {
+			if streak, err := h.streaks.Get(ctx, stud.ID); err == nil {
+				dto.StreakWeeks = streak.ActiveWeeks(now)
+			}
+		}
+		result.Helpers = append(result.Helpers, dto)
+	}
+
+	return result, nil
+}
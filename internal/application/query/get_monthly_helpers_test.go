@@ -0,0 +1,91 @@
+package query
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type monthlyBoard struct {
+	entries []student.HelperBoardEntry
+}
+
+func (b *monthlyBoard) ReplaceMonth(context.Context, time.Time, map[string]int) error { return nil }
+
+func (b *monthlyBoard) TopOfMonth(_ context.Context, _ time.Time, limit int) ([]student.HelperBoardEntry, error) {
+	return b.entries[:min(limit, len(b.entries))], nil
+}
+
+type monthlyStudents struct {
+	student.Repository
+	byID map[string]*student.Student
+}
+
+func (s *monthlyStudents) GetByID(_ context.Context, id string) (*student.Student, error) {
+	if stud, ok := s.byID[id]; ok {
+		return stud, nil
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+type monthlyStreaks struct {
+	student.HelperStreakRepository
+	byID map[string]*student.HelperStreak
+}
+
+func (s *monthlyStreaks) Get(_ context.Context, id string) (*student.HelperStreak, error) {
+	return s.byID[id], nil
+}
+
+func TestGetMonthlyHelpers_RanksTiesAndHidesPrivateStudents(t *testing.T) {
+	newStudent := func(id string, hidden bool) *student.Student {
+		s := &student.Student{ID: id, DisplayName: id}
+		s.Preferences = student.DefaultNotificationPreferences()
+		s.Preferences.HideFromLeaderboard = hidden
+		return s
+	}
+
+	handler := NewGetMonthlyHelpersHandler(
+		&monthlyBoard{entries: []student.HelperBoardEntry{
+			{StudentID: "dana", Endorsements: 9},
+			{StudentID: "hidden", Endorsements: 7},
+			{StudentID: "arman", Endorsements: 7},
+			{StudentID: "aigerim", Endorsements: 3},
+		}},
+		&monthlyStreaks{byID: map[string]*student.HelperStreak{
+			"dana":  {StudentID: "dana", CurrentWeeks: 6, LastHelpedWeek: "2026-W41"},
+			"arman": {StudentID: "arman", CurrentWeeks: 3, LastHelpedWeek: "2026-W38"},
+		}},
+		&monthlyStudents{byID: map[string]*student.Student{
+			"dana":    newStudent("dana", false),
+			"hidden":  newStudent("hidden", true),
+			"arman":   newStudent("arman", false),
+			"aigerim": newStudent("aigerim", false),
+		}},
+	)
+	handler.now = func() time.Time { return time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC) }
+
+	result, err := handler.Handle(context.Background(), GetMonthlyHelpersQuery{Limit: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "Октябрь 2026", result.Month)
+
+	require.Len(t, result.Helpers, 3)
+	assert.Equal(t, "dana", result.Helpers[0].StudentID)
+	assert.Equal(t, 6, result.Helpers[0].StreakWeeks)
+
+	// The hidden student keeps their place: arman shares rank 2 with them
+	assert.Equal(t, "arman", result.Helpers[1].StudentID)
+	assert.Equal(t, 2, result.Helpers[1].Rank)
+	assert.Zero(t, result.Helpers[1].StreakWeeks, "a streak that missed a week is over")
+	assert.Equal(t, 4, result.Helpers[2].Rank)
+
+	// A hidden student still sees themselves
+	result, err = handler.Handle(context.Background(), GetMonthlyHelpersQuery{ViewerID: "hidden"})
+	require.NoError(t, err)
+	assert.Len(t, result.Helpers, 4)
+}
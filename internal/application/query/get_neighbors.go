@@ -45,6 +45,9 @@ func (q *GetNeighborsQuery) Validate() error {
 	if q.StudentID == "" && q.TelegramID == 0 {
 		return errors.New("either student_id or telegram_id must be provided")
 	}
+	if q.TelegramID != 0 && !student.TelegramID(q.TelegramID).IsValid() {
+		return student.ErrInvalidTelegramID
+	}
 	if q.RangeSize < 0 {
 		return errors.New("range_size cannot be negative")
 	}
@@ -228,6 +231,9 @@ func (h *GetNeighborsHandler) Handle(ctx context.Context, query GetNeighborsQuer
 		neighbors = h.enrichWithOnlineStatus(ctx, neighbors)
 	}
 
+	// Скрывшихся соседей не показываем, сам студент себя видит
+	neighbors = publicEntries(neighbors, stud.ID)
+
 	// Получаем общее количество
 	totalCount, err := h.leaderboardRepo.GetTotalCount(ctx, cohort)
 	if err != nil {
@@ -0,0 +1,175 @@
+// Package query contains read operations (CQRS - Queries).
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GET NOTIFICATIONS QUERY
+// Центр уведомлений: последние уведомления студента с состоянием прочтения.
+// Нужен тем, кто выключил звук в Telegram и пропустил изменения позиции
+// или достижения.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// DefaultNotificationsPageSize - размер страницы центра уведомлений.
+const DefaultNotificationsPageSize = 15
+
+// GetNotificationsQuery содержит параметры запроса уведомлений.
+type GetNotificationsQuery struct {
+	// StudentID - внутренний ID студента.
+	StudentID string
+
+	// TelegramID - альтернативная идентификация.
+	TelegramID int64
+
+	// Page - номер страницы (с 1).
+	Page int
+
+	// PageSize - размер страницы (по умолчанию 15).
+	PageSize int
+}
+
+// Validate проверяет корректность параметров.
+func (q *GetNotificationsQuery) Validate() error {
+	if q.StudentID == "" && q.TelegramID == 0 {
+		return errors.New("either student_id or telegram_id must be provided")
+	}
+	if q.TelegramID != 0 && !student.TelegramID(q.TelegramID).IsValid() {
+		return student.ErrInvalidTelegramID
+	}
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize <= 0 {
+		q.PageSize = DefaultNotificationsPageSize
+	}
+	if q.PageSize > 50 {
+		q.PageSize = 50
+	}
+	return nil
+}
+
+// NotificationDTO - DTO уведомления для центра уведомлений.
+type NotificationDTO struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	Emoji      string     `json:"emoji"`
+	Title      string     `json:"title,omitempty"`
+	Message    string     `json:"message"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReadAt     *time.Time `json:"read_at,omitempty"`
+	Unread     bool       `json:"unread"`
+	Superseded bool       `json:"superseded"`
+}
+
+// GetNotificationsResult содержит результат запроса.
+type GetNotificationsResult struct {
+	StudentID   string            `json:"student_id"`
+	Items       []NotificationDTO `json:"items"`
+	UnreadCount int               `json:"unread_count"`
+	Page        int               `json:"page"`
+	PageSize    int               `json:"page_size"`
+	HasMore     bool              `json:"has_more"`
+}
+
+// GetNotificationsHandler обрабатывает запросы центра уведомлений.
+type GetNotificationsHandler struct {
+	studentRepo      student.Repository
+	notificationRepo notification.NotificationRepository
+}
+
+// NewGetNotificationsHandler создаёт новый обработчик.
+func NewGetNotificationsHandler(
+	studentRepo student.Repository,
+	notificationRepo notification.NotificationRepository,
+) *GetNotificationsHandler {
+	return &GetNotificationsHandler{
+		studentRepo:      studentRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// Handle выполняет запрос.
+func (h *GetNotificationsHandler) Handle(ctx context.Context, query GetNotificationsQuery) (*GetNotificationsResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, shared.WrapError("query", "GetNotifications", shared.ErrValidation, err.Error(), err)
+	}
+
+	studentID, err := h.resolveStudentID(ctx, query)
+	if err != nil {
+		return nil, shared.WrapError("query", "GetNotifications", shared.ErrNotFound, "student not found", err)
+	}
+
+	recipient := notification.RecipientID(studentID)
+
+	// Берём на одну запись больше, чтобы узнать, есть ли следующая страница
+	offset := (query.Page - 1) * query.PageSize
+	items, err := h.notificationRepo.GetInbox(ctx, recipient, offset, query.PageSize+1)
+	if err != nil {
+		return nil, fmt.Errorf("load notifications: %w", err)
+	}
+
+	unread, err := h.notificationRepo.CountUnread(ctx, recipient)
+	if err != nil {
+		return nil, fmt.Errorf("count unread notifications: %w", err)
+	}
+
+	hasMore := len(items) > query.PageSize
+	if hasMore {
+		items = items[:query.PageSize]
+	}
+
+	result := &GetNotificationsResult{
+		StudentID:   studentID,
+		Items:       make([]NotificationDTO, 0, len(items)),
+		UnreadCount: unread,
+		Page:        query.Page,
+		PageSize:    query.PageSize,
+		HasMore:     hasMore,
+	}
+	for _, n := range items {
+		result.Items = append(result.Items, toNotificationDTO(n))
+	}
+
+	return result, nil
+}
+
+// CountUnread возвращает количество непрочитанных уведомлений студента.
+func (h *GetNotificationsHandler) CountUnread(ctx context.Context, studentID string) (int, error) {
+	return h.notificationRepo.CountUnread(ctx, notification.RecipientID(studentID))
+}
+
+// resolveStudentID находит ID студента по запросу.
+func (h *GetNotificationsHandler) resolveStudentID(ctx context.Context, query GetNotificationsQuery) (string, error) {
+	if query.StudentID != "" {
+		return query.StudentID, nil
+	}
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(query.TelegramID))
+	if err != nil {
+		return "", err
+	}
+	return stud.ID, nil
+}
+
+// toNotificationDTO преобразует уведомление в DTO.
+func toNotificationDTO(n *notification.Notification) NotificationDTO {
+	return NotificationDTO{
+		ID:         string(n.ID),
+		Type:       string(n.Type),
+		Emoji:      n.Type.Emoji(),
+		Title:      n.Title,
+		Message:    n.Message,
+		CreatedAt:  n.CreatedAt,
+		ReadAt:     n.ReadAt,
+		Unread:     n.IsUnread(),
+		Superseded: n.SupersededAt != nil,
+	}
+}
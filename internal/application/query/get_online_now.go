@@ -11,6 +11,7 @@ import (
 	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
 	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/pagination"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -83,14 +84,12 @@ type GetOnlineNowQuery struct {
 	IncludeRank bool
 }
 
+// OnlineNowBounds - ограничения размера страницы онлайн-студентов.
+var OnlineNowBounds = pagination.Bounds{DefaultLimit: 50, MaxLimit: 100}
+
 // Validate проверяет корректность параметров.
 func (q *GetOnlineNowQuery) Validate() error {
-	if q.Limit <= 0 {
-		q.Limit = 50
-	}
-	if q.Limit > 100 {
-		q.Limit = 100
-	}
+	q.Limit = OnlineNowBounds.ClampLimit(q.Limit)
 	if q.Offset < 0 {
 		return errors.New("offset cannot be negative")
 	}
@@ -288,6 +287,11 @@ func (h *GetOnlineNowHandler) Handle(ctx context.Context, query GetOnlineNowQuer
 			continue
 		}
 
+		// Скрывших онлайн-статус в списке нет
+		if !stud.ShowsOnlineStatus() {
+			continue
+		}
+
 		// Определяем статус
 		status := h.determineStatus(stud.LastSeenAt)
 
@@ -300,7 +304,7 @@ func (h *GetOnlineNowHandler) Handle(ctx context.Context, query GetOnlineNowQuer
 		}
 
 		// Фильтр по готовности помогать
-		if query.OnlyAvailableForHelp && !stud.CanHelp() {
+		if query.OnlyAvailableForHelp && !stud.IsDiscoverableHelper() {
 			continue
 		}
 
@@ -326,12 +330,8 @@ func (h *GetOnlineNowHandler) Handle(ctx context.Context, query GetOnlineNowQuer
 	hasMore := false
 
 	if query.Offset < len(dtos) {
-		end := query.Offset + query.Limit
-		if end > len(dtos) {
-			end = len(dtos)
-		} else {
-			hasMore = true
-		}
+		end := min(query.Offset+query.Limit, len(dtos))
+		hasMore = end < len(dtos)
 		dtos = dtos[query.Offset:end]
 	} else {
 		dtos = []OnlineStudentDTO{}
@@ -387,7 +387,7 @@ func (h *GetOnlineNowHandler) buildDTO(
 		LastSeenFormatted:  formatOnlineTime(stud.LastSeenAt),
 		XP:                 int(stud.CurrentXP),
 		Level:              int(stud.Level()),
-		IsAvailableForHelp: stud.CanHelp(),
+		IsAvailableForHelp: stud.IsDiscoverableHelper(),
 		HelpRating:         stud.HelpRating,
 		HelpCount:          stud.HelpCount,
 		Cohort:             string(stud.Cohort),
@@ -0,0 +1,204 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/activity"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/pkg/pagination"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GET POPULAR TASKS QUERY
+// Горячие задачи: по каким задачам чаще всего просили помощи за период.
+// Результат кэшируется на PopularTasksTTL - агрегат по всем запросам.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// PopularTasksTTL - время жизни кэша популярных задач.
+const PopularTasksTTL = 30 * time.Minute
+
+// PopularTasksBounds - ограничения числа задач в ответе.
+var PopularTasksBounds = pagination.Bounds{DefaultLimit: 10, MaxLimit: 50}
+
+// PopularTasksWindow - период, за который считаются запросы.
+type PopularTasksWindow string
+
+const (
+	PopularTasksDay   PopularTasksWindow = "day"
+	PopularTasksWeek  PopularTasksWindow = "week"
+	PopularTasksMonth PopularTasksWindow = "month"
+)
+
+// Duration возвращает длину периода.
+func (w PopularTasksWindow) Duration() time.Duration {
+	switch w {
+	case PopularTasksDay:
+		return 24 * time.Hour
+	case PopularTasksMonth:
+		return 30 * 24 * time.Hour
+	default:
+		return 7 * 24 * time.Hour
+	}
+}
+
+// GetPopularTasksQuery содержит параметры запроса.
+type GetPopularTasksQuery struct {
+	// Window - период; по умолчанию неделя.
+	Window PopularTasksWindow
+
+	// Limit - максимальное количество задач.
+	Limit int
+}
+
+// Validate проверяет корректность параметров.
+func (q *GetPopularTasksQuery) Validate() error {
+	switch q.Window {
+	case "":
+		q.Window = PopularTasksWeek
+	case PopularTasksDay, PopularTasksWeek, PopularTasksMonth:
+	default:
+		return fmt.Errorf("unknown window %q", q.Window)
+	}
+	if q.Limit < 0 {
+		return fmt.Errorf("limit must not be negative")
+	}
+	q.Limit = PopularTasksBounds.ClampLimit(q.Limit)
+	return nil
+}
+
+// PopularTaskDTO - одна горячая задача.
+type PopularTaskDTO struct {
+	TaskID   string `json:"task_id"`
+	TaskName string `json:"task_name"`
+
+	// Requests - запросов помощи за период.
+	Requests int `json:"requests"`
+
+	// Resolved - из них решённых.
+	Resolved int `json:"resolved"`
+
+	// AvgResolutionMinutes - среднее время до решения (0 - решённых нет).
+	AvgResolutionMinutes int `json:"avg_resolution_minutes"`
+}
+
+// GetPopularTasksResult содержит результат запроса.
+type GetPopularTasksResult struct {
+	Window PopularTasksWindow `json:"window"`
+	Since  time.Time          `json:"since"`
+	Tasks  []PopularTaskDTO   `json:"tasks"`
+}
+
+// popularTasksEntry - закэшированный результат для одного периода.
+type popularTasksEntry struct {
+	result    *GetPopularTasksResult
+	expiresAt time.Time
+}
+
+// GetPopularTasksHandler обрабатывает запросы популярных задач.
+type GetPopularTasksHandler struct {
+	repo    social.HelpRequestRepository
+	catalog activity.TaskCatalog
+	now     func() time.Time
+
+	mu    sync.Mutex
+	cache map[PopularTasksWindow]popularTasksEntry
+}
+
+// NewGetPopularTasksHandler создаёт новый обработчик.
+// Без каталога используется название из запроса помощи, а если его нет - ID.
+func NewGetPopularTasksHandler(repo social.HelpRequestRepository, catalog activity.TaskCatalog) *GetPopularTasksHandler {
+	return &GetPopularTasksHandler{
+		repo:    repo,
+		catalog: catalog,
+		now:     time.Now,
+		cache:   make(map[PopularTasksWindow]popularTasksEntry),
+	}
+}
+
+// Handle выполняет запрос.
+func (h *GetPopularTasksHandler) Handle(ctx context.Context, query GetPopularTasksQuery) (*GetPopularTasksResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, shared.WrapError("query", "GetPopularTasks", shared.ErrValidation, err.Error(), err)
+	}
+
+	full, err := h.load(ctx, query.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	result := *full
+	if len(result.Tasks) > query.Limit {
+		result.Tasks = result.Tasks[:query.Limit]
+	}
+	return &result, nil
+}
+
+// load возвращает полный список периода (до MaxLimit) из кэша или из БД.
+func (h *GetPopularTasksHandler) load(ctx context.Context, window PopularTasksWindow) (*GetPopularTasksResult, error) {
+	now := h.now()
+
+	h.mu.Lock()
+	entry, ok := h.cache[window]
+	h.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.result, nil
+	}
+
+	since := now.Add(-window.Duration())
+	stats, err := h.repo.GetPopularTasks(ctx, PopularTasksBounds.MaxLimit, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get popular tasks: %w", err)
+	}
+
+	names := h.taskNames(ctx, stats)
+
+	result := &GetPopularTasksResult{
+		Window: window,
+		Since:  since,
+		Tasks:  make([]PopularTaskDTO, 0, len(stats)),
+	}
+	for _, s := range stats {
+		dto := PopularTaskDTO{
+			TaskID:               string(s.TaskID),
+			TaskName:             s.TaskName,
+			Requests:             s.RequestCount,
+			Resolved:             s.ResolvedCount,
+			AvgResolutionMinutes: s.AverageResolutionMinutes,
+		}
+		if name, ok := names[activity.TaskID(s.TaskID)]; ok {
+			dto.TaskName = name
+		}
+		if dto.TaskName == "" {
+			dto.TaskName = dto.TaskID
+		}
+		result.Tasks = append(result.Tasks, dto)
+	}
+
+	h.mu.Lock()
+	h.cache[window] = popularTasksEntry{result: result, expiresAt: now.Add(PopularTasksTTL)}
+	h.mu.Unlock()
+
+	return result, nil
+}
+
+// taskNames резолвит названия через каталог; ошибка каталога не ломает ответ.
+func (h *GetPopularTasksHandler) taskNames(ctx context.Context, stats []social.TaskHelpStats) map[activity.TaskID]string {
+	if h.catalog == nil || len(stats) == 0 {
+		return nil
+	}
+
+	ids := make([]activity.TaskID, len(stats))
+	for i, s := range stats {
+		ids[i] = activity.TaskID(s.TaskID)
+	}
+
+	names, err := h.catalog.TaskNames(ctx, ids)
+	if err != nil {
+		return nil
+	}
+	return names
+}
@@ -0,0 +1,104 @@
+package query
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/activity"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+// fixtureHelpRequests aggregates popular tasks over in-memory requests.
+type fixtureHelpRequests struct {
+	social.HelpRequestRepository
+	requests []*social.HelpRequest
+	calls    int
+}
+
+func (f *fixtureHelpRequests) GetPopularTasks(_ context.Context, limit int, since time.Time) ([]social.TaskHelpStats, error) {
+	f.calls++
+	return social.AggregatePopularTasks(f.requests, since, limit), nil
+}
+
+// fixedCatalog knows the names of some tasks.
+type fixedCatalog map[activity.TaskID]string
+
+func (c fixedCatalog) TaskNames(_ context.Context, ids []activity.TaskID) (map[activity.TaskID]string, error) {
+	names := make(map[activity.TaskID]string)
+	for _, id := range ids {
+		if name, ok := c[id]; ok {
+			names[id] = name
+		}
+	}
+	return names, nil
+}
+
+func TestGetPopularTasks_CountsRequestsInsideWindow(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	request := func(task string, age time.Duration, resolveAfter time.Duration) *social.HelpRequest {
+		r := &social.HelpRequest{
+			TaskID:    social.TaskID(task),
+			TaskName:  task + " (from request)",
+			Status:    social.HelpRequestStatusOpen,
+			CreatedAt: now.Add(-age),
+		}
+		if resolveAfter > 0 {
+			resolvedAt := r.CreatedAt.Add(resolveAfter)
+			r.Status = social.HelpRequestStatusResolved
+			r.ResolvedAt = &resolvedAt
+		}
+		return r
+	}
+
+	repo := &fixtureHelpRequests{requests: []*social.HelpRequest{
+		// go-reloaded: three this week, two of them resolved
+		request("go-reloaded", time.Hour, 20*time.Minute),
+		request("go-reloaded", 2*24*time.Hour, 40*time.Minute),
+		request("go-reloaded", 6*24*time.Hour, 0),
+		// ascii-art: two this week, plus older ones that don't count
+		request("ascii-art", 3*time.Hour, 0),
+		request("ascii-art", 5*24*time.Hour, 0),
+		request("ascii-art", 8*24*time.Hour, 0),
+		request("ascii-art", 20*24*time.Hour, 0),
+		// lem-in: busy last month, a single request this week
+		request("lem-in", 24*time.Hour, 0),
+		request("lem-in", 10*24*time.Hour, 0),
+		request("lem-in", 11*24*time.Hour, 0),
+		request("lem-in", 12*24*time.Hour, 0),
+	}}
+	h := NewGetPopularTasksHandler(repo, fixedCatalog{"go-reloaded": "Go Reloaded"})
+	h.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	week, err := h.Handle(ctx, GetPopularTasksQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, PopularTasksWeek, week.Window)
+	assert.True(t, week.Since.Equal(now.Add(-7*24*time.Hour)))
+	assert.Equal(t, []PopularTaskDTO{
+		{TaskID: "go-reloaded", TaskName: "Go Reloaded", Requests: 3, Resolved: 2, AvgResolutionMinutes: 30},
+		{TaskID: "ascii-art", TaskName: "ascii-art (from request)", Requests: 2},
+	}, week.Tasks)
+
+	month, err := h.Handle(ctx, GetPopularTasksQuery{Window: PopularTasksMonth, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, month.Tasks, 1)
+	assert.Equal(t, "ascii-art", month.Tasks[0].TaskID)
+	assert.Equal(t, 4, month.Tasks[0].Requests)
+
+	// Served from the cache until the TTL runs out
+	_, err = h.Handle(ctx, GetPopularTasksQuery{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, repo.calls)
+
+	now = now.Add(PopularTasksTTL)
+	_, err = h.Handle(ctx, GetPopularTasksQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, repo.calls)
+
+	_, err = h.Handle(ctx, GetPopularTasksQuery{Window: "year"})
+	assert.Error(t, err)
+}
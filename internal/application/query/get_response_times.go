@@ -0,0 +1,119 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GET RESPONSE TIMES QUERY
+// Время первого ответа на запросы помощи: сколько студент ждёт, пока
+// помощник примет запрос или напишет ему. Медиана общая и по потокам
+// за каждую неделю.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// DefaultResponseTimeWeeks - период статистики по умолчанию.
+	DefaultResponseTimeWeeks = 8
+
+	// MaxResponseTimeWeeks - максимальный период статистики.
+	MaxResponseTimeWeeks = 52
+)
+
+// GetResponseTimesQuery содержит параметры запроса.
+type GetResponseTimesQuery struct {
+	// Weeks - за сколько последних недель считать (1..MaxResponseTimeWeeks,
+	// обычно DefaultResponseTimeWeeks).
+	Weeks int
+}
+
+// Validate проверяет корректность параметров.
+func (q GetResponseTimesQuery) Validate() error {
+	if q.Weeks < 1 || q.Weeks > MaxResponseTimeWeeks {
+		return fmt.Errorf("weeks must be between 1 and %d", MaxResponseTimeWeeks)
+	}
+	return nil
+}
+
+// WeeklyResponseTimeDTO - медиана за неделю в одном потоке.
+type WeeklyResponseTimeDTO struct {
+	Cohort        string  `json:"cohort"`
+	WeekStart     string  `json:"week_start"`
+	MedianMinutes float64 `json:"median_minutes"`
+	Answered      int     `json:"answered"`
+}
+
+// GetResponseTimesResult содержит результат запроса.
+type GetResponseTimesResult struct {
+	Since string `json:"since"`
+
+	// MedianMinutes - медиана за весь период; nil, если ответов не было.
+	MedianMinutes *float64 `json:"median_minutes"`
+
+	Answered   int                     `json:"answered"`
+	Unanswered int                     `json:"unanswered"`
+	Weekly     []WeeklyResponseTimeDTO `json:"weekly"`
+}
+
+// GetResponseTimesHandler обрабатывает запросы статистики времени ответа.
+type GetResponseTimesHandler struct {
+	helpRequests social.HelpRequestRepository
+	now          func() time.Time
+}
+
+// NewGetResponseTimesHandler создаёт новый обработчик.
+func NewGetResponseTimesHandler(helpRequests social.HelpRequestRepository) *GetResponseTimesHandler {
+	return &GetResponseTimesHandler{helpRequests: helpRequests, now: time.Now}
+}
+
+// Handle выполняет запрос.
+func (h *GetResponseTimesHandler) Handle(ctx context.Context, query GetResponseTimesQuery) (*GetResponseTimesResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, shared.WrapError("query", "GetResponseTimes", shared.ErrValidation, err.Error(), err)
+	}
+
+	since := startOfWeek(h.now().UTC()).AddDate(0, 0, -7*(query.Weeks-1))
+
+	stats, err := h.helpRequests.GetResponseTimeStats(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response time stats: %w", err)
+	}
+
+	result := &GetResponseTimesResult{
+		Since:      since.Format("2006-01-02"),
+		Answered:   stats.Answered,
+		Unanswered: stats.Unanswered,
+		Weekly:     make([]WeeklyResponseTimeDTO, 0, len(stats.Weekly)),
+	}
+	if stats.Answered > 0 {
+		median := durationMinutes(stats.MedianFirstResponse)
+		result.MedianMinutes = &median
+	}
+	for _, w := range stats.Weekly {
+		result.Weekly = append(result.Weekly, WeeklyResponseTimeDTO{
+			Cohort:        w.Cohort,
+			WeekStart:     w.WeekStart.Format("2006-01-02"),
+			MedianMinutes: durationMinutes(w.MedianFirstResponse),
+			Answered:      w.Answered,
+		})
+	}
+
+	return result, nil
+}
+
+// startOfWeek возвращает понедельник 00:00 недели t.
+func startOfWeek(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// durationMinutes переводит длительность в минуты с одним знаком после запятой.
+func durationMinutes(d time.Duration) float64 {
+	return math.Round(d.Minutes()*10) / 10
+}
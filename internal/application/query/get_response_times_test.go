@@ -0,0 +1,23 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeeksValidation_MatchesMessage(t *testing.T) {
+	// Сообщение об ошибке называет ровно тот диапазон, который принимается
+	for _, weeks := range []int{-1, 0, MaxResponseTimeWeeks + 1} {
+		err := GetResponseTimesQuery{Weeks: weeks}.Validate()
+		assert.EqualError(t, err, "weeks must be between 1 and 52", "weeks %d", weeks)
+
+		err = GetHelpSatisfactionQuery{Weeks: weeks}.Validate()
+		assert.EqualError(t, err, "weeks must be between 1 and 52", "weeks %d", weeks)
+	}
+
+	for _, weeks := range []int{1, DefaultResponseTimeWeeks, MaxResponseTimeWeeks} {
+		assert.NoError(t, GetResponseTimesQuery{Weeks: weeks}.Validate())
+		assert.NoError(t, GetHelpSatisfactionQuery{Weeks: weeks}.Validate())
+	}
+}
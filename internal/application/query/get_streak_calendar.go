@@ -0,0 +1,155 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GET STREAK CALENDAR QUERY
+// История серии для /streak: активные дни за последние 30 дней вместе с
+// текущей и лучшей серией и временем, оставшимся до конца дня. Дни меняются
+// в полночь по часовому поясу студента (по умолчанию Алматы), поэтому
+// активность в 23:50 по местному времени засчитывается этому же дню.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// StreakCalendarDays - сколько дней показывает календарь серии.
+const StreakCalendarDays = 30
+
+// GetStreakCalendarQuery содержит параметры запроса.
+type GetStreakCalendarQuery struct {
+	// StudentID - внутренний ID студента.
+	StudentID string
+}
+
+// StreakCalendarDay - один день календаря.
+type StreakCalendarDay struct {
+	// Date - дата по часовому поясу студента (полночь в UTC, как в daily_grinds).
+	Date time.Time
+
+	// Active - в этот день была активность.
+	Active bool
+
+	// Today - это сегодняшний день студента.
+	Today bool
+}
+
+// GetStreakCalendarResult содержит результат запроса.
+type GetStreakCalendarResult struct {
+	// Days - дни от самого раннего к сегодняшнему, всегда StreakCalendarDays.
+	Days []StreakCalendarDay
+
+	// ActiveDays - сколько дней из Days активны.
+	ActiveDays int
+
+	// CurrentStreak - текущая серия (0, если прервана).
+	CurrentStreak int
+
+	// BestStreak - лучшая серия, не меньше текущей.
+	BestStreak int
+
+	// ActiveToday - сегодня серия уже продлена.
+	ActiveToday bool
+
+	// AtRisk - серия идёт, но сегодня ещё не продлена: она прервётся
+	// через TimeLeftToday.
+	AtRisk bool
+
+	// TimeLeftToday - сколько осталось до полуночи по поясу студента.
+	TimeLeftToday time.Duration
+}
+
+// GetStreakCalendarHandler обрабатывает запросы календаря серии.
+type GetStreakCalendarHandler struct {
+	studentRepo  student.Repository
+	progressRepo student.ProgressRepository
+	clock        shared.Clock
+}
+
+// NewGetStreakCalendarHandler создаёт новый обработчик.
+func NewGetStreakCalendarHandler(studentRepo student.Repository, progressRepo student.ProgressRepository) *GetStreakCalendarHandler {
+	return &GetStreakCalendarHandler{
+		studentRepo:  studentRepo,
+		progressRepo: progressRepo,
+		clock:        shared.SystemClock{},
+	}
+}
+
+// WithClock заменяет системные часы (для тестов).
+func (h *GetStreakCalendarHandler) WithClock(clock shared.Clock) *GetStreakCalendarHandler {
+	h.clock = shared.ClockOrSystem(clock)
+	return h
+}
+
+// Handle выполняет запрос.
+func (h *GetStreakCalendarHandler) Handle(ctx context.Context, query GetStreakCalendarQuery) (*GetStreakCalendarResult, error) {
+	if query.StudentID == "" {
+		return nil, shared.WrapError("query", "GetStreakCalendar", shared.ErrValidation, "student_id is required", nil)
+	}
+
+	stud, err := h.studentRepo.GetByID(ctx, query.StudentID)
+	if err != nil {
+		return nil, err
+	}
+	loc := stud.Preferences.Location()
+
+	streak, err := h.progressRepo.GetStreak(ctx, stud.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get streak: %w", err)
+	}
+	grinds, err := h.progressRepo.GetDailyGrindHistory(ctx, stud.ID, StreakCalendarDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily progress: %w", err)
+	}
+
+	// Активные дни: по дневному прогрессу и по датам текущей серии, чтобы
+	// календарь не расходился с её длиной, даже если строки дня ещё нет
+	active := make(map[time.Time]bool, len(grinds))
+	for _, g := range grinds {
+		if g.IsActive() {
+			active[student.LocalDate(g.Date, nil)] = true
+		}
+	}
+
+	now := h.clock.Now()
+	today := student.LocalDate(now, loc)
+	from := today.AddDate(0, 0, -(StreakCalendarDays - 1))
+	result := &GetStreakCalendarResult{
+		Days:          make([]StreakCalendarDay, 0, StreakCalendarDays),
+		TimeLeftToday: shared.StartOfDay(now, loc).AddDate(0, 0, 1).Sub(now),
+	}
+
+	if streak != nil {
+		streak.WithClock(h.clock).WithLocation(loc)
+		if !streak.IsBroken() {
+			result.CurrentStreak = streak.CurrentStreak
+		}
+		result.BestStreak = max(streak.BestStreak, result.CurrentStreak)
+		if result.CurrentStreak > 0 && !streak.StreakStartDate.IsZero() {
+			start := student.LocalDate(streak.StreakStartDate, nil)
+			if start.Before(from) {
+				start = from
+			}
+			for d := start; !d.After(student.LocalDate(streak.LastActiveDate, nil)); d = d.AddDate(0, 0, 1) {
+				active[d] = true
+			}
+		}
+	}
+
+	for d := from; !d.After(today); d = d.AddDate(0, 0, 1) {
+		day := StreakCalendarDay{Date: d, Active: active[d], Today: d.Equal(today)}
+		if day.Active {
+			result.ActiveDays++
+		}
+		result.Days = append(result.Days, day)
+	}
+
+	result.ActiveToday = active[today]
+	result.AtRisk = result.CurrentStreak > 0 && !result.ActiveToday
+
+	return result, nil
+}
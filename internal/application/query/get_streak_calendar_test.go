@@ -0,0 +1,150 @@
+package query
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type streakProgress struct {
+	student.ProgressRepository
+	streak *student.Streak
+	grinds []*student.DailyGrind
+}
+
+func (p *streakProgress) GetStreak(context.Context, string) (*student.Streak, error) {
+	return p.streak, nil
+}
+
+func (p *streakProgress) GetDailyGrindHistory(_ context.Context, _ string, days int) ([]*student.DailyGrind, error) {
+	return p.grinds[:min(days, len(p.grinds))], nil
+}
+
+// grindDate - дата daily_grinds: полночь в UTC.
+func grindDate(s string) time.Time {
+	d, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func activeOn(dates ...string) []*student.DailyGrind {
+	grinds := make([]*student.DailyGrind, 0, len(dates))
+	for _, d := range dates {
+		grinds = append(grinds, &student.DailyGrind{Date: grindDate(d), XPGained: 100, TasksCompleted: 1})
+	}
+	return grinds
+}
+
+func newStreakCalendar(progress *streakProgress, now time.Time) *GetStreakCalendarHandler {
+	stud := &student.Student{ID: "dana", Preferences: student.DefaultNotificationPreferences()}
+	students := &monthlyStudents{byID: map[string]*student.Student{"dana": stud}}
+	return NewGetStreakCalendarHandler(students, progress).WithClock(shared.NewFakeClock(now))
+}
+
+func activeDates(result *GetStreakCalendarResult) []string {
+	var dates []string
+	for _, d := range result.Days {
+		if d.Active {
+			dates = append(dates, d.Date.Format(time.DateOnly))
+		}
+	}
+	return dates
+}
+
+func TestGetStreakCalendar_LateLocalActivityCountsForThatDay(t *testing.T) {
+	// 23:50 17 октября в Алматы - ещё 18:50 UTC того же дня
+	now := time.Date(2026, 10, 17, 18, 50, 0, 0, time.UTC)
+	streak := &student.Streak{
+		StudentID: "dana", CurrentStreak: 3, BestStreak: 8,
+		StreakStartDate: grindDate("2026-10-15"), LastActiveDate: grindDate("2026-10-17"),
+	}
+	handler := newStreakCalendar(&streakProgress{
+		streak: streak,
+		grinds: activeOn("2026-10-17", "2026-10-16", "2026-10-15", "2026-10-10"),
+	}, now)
+
+	result, err := handler.Handle(context.Background(), GetStreakCalendarQuery{StudentID: "dana"})
+	require.NoError(t, err)
+
+	require.Len(t, result.Days, StreakCalendarDays)
+	assert.Equal(t, "2026-09-18", result.Days[0].Date.Format(time.DateOnly))
+	last := result.Days[len(result.Days)-1]
+	assert.Equal(t, "2026-10-17", last.Date.Format(time.DateOnly))
+	assert.True(t, last.Today)
+	assert.True(t, last.Active)
+
+	assert.Equal(t, []string{"2026-10-10", "2026-10-15", "2026-10-16", "2026-10-17"}, activeDates(result))
+	assert.Equal(t, 4, result.ActiveDays)
+	assert.Equal(t, 3, result.CurrentStreak)
+	assert.Equal(t, 8, result.BestStreak)
+	assert.True(t, result.ActiveToday)
+	assert.False(t, result.AtRisk)
+	assert.Equal(t, 10*time.Minute, result.TimeLeftToday)
+}
+
+func TestGetStreakCalendar_AfterLocalMidnightStreakIsAtRisk(t *testing.T) {
+	// 01:30 18 октября в Алматы, по UTC всё ещё 17-е
+	now := time.Date(2026, 10, 17, 20, 30, 0, 0, time.UTC)
+	streak := &student.Streak{
+		StudentID: "dana", CurrentStreak: 3, BestStreak: 3,
+		StreakStartDate: grindDate("2026-10-15"), LastActiveDate: grindDate("2026-10-17"),
+	}
+	handler := newStreakCalendar(&streakProgress{
+		streak: streak,
+		grinds: activeOn("2026-10-17", "2026-10-16", "2026-10-15"),
+	}, now)
+
+	result, err := handler.Handle(context.Background(), GetStreakCalendarQuery{StudentID: "dana"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "2026-10-18", result.Days[len(result.Days)-1].Date.Format(time.DateOnly))
+	assert.Equal(t, 3, result.CurrentStreak)
+	assert.False(t, result.ActiveToday)
+	assert.True(t, result.AtRisk)
+	assert.Equal(t, 22*time.Hour+30*time.Minute, result.TimeLeftToday)
+}
+
+func TestGetStreakCalendar_NewStudentWithStreakStartedToday(t *testing.T) {
+	now := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	streak := &student.Streak{
+		StudentID: "dana", CurrentStreak: 1, BestStreak: 1,
+		StreakStartDate: grindDate("2026-10-17"), LastActiveDate: grindDate("2026-10-17"),
+	}
+	// Строки дня ещё нет: активность видна только по серии
+	handler := newStreakCalendar(&streakProgress{streak: streak}, now)
+
+	result, err := handler.Handle(context.Background(), GetStreakCalendarQuery{StudentID: "dana"})
+	require.NoError(t, err)
+
+	require.Len(t, result.Days, StreakCalendarDays)
+	assert.Equal(t, []string{"2026-10-17"}, activeDates(result))
+	assert.Equal(t, 1, result.CurrentStreak)
+	assert.Equal(t, 1, result.BestStreak)
+	assert.True(t, result.ActiveToday)
+	assert.False(t, result.AtRisk)
+}
+
+func TestGetStreakCalendar_BrokenStreakShowsOnlyBest(t *testing.T) {
+	now := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	streak := &student.Streak{
+		StudentID: "dana", CurrentStreak: 5, BestStreak: 5,
+		StreakStartDate: grindDate("2026-10-08"), LastActiveDate: grindDate("2026-10-12"),
+	}
+	handler := newStreakCalendar(&streakProgress{streak: streak, grinds: activeOn("2026-10-12", "2026-10-11")}, now)
+
+	result, err := handler.Handle(context.Background(), GetStreakCalendarQuery{StudentID: "dana"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, result.CurrentStreak)
+	assert.Equal(t, 5, result.BestStreak)
+	assert.False(t, result.AtRisk)
+	assert.Equal(t, []string{"2026-10-11", "2026-10-12"}, activeDates(result))
+}
@@ -25,6 +25,10 @@ type GetStudentRankQuery struct {
 	// TelegramID - альтернативный способ идентификации (опционально).
 	TelegramID int64
 
+	// Student - уже загруженный студент (опционально): тогда он не читается
+	// из репозитория повторно, StudentID и TelegramID не нужны.
+	Student *student.Student
+
 	// Cohort - когорта для расчёта позиции (пустая = общий рейтинг).
 	Cohort string
 
@@ -33,13 +37,20 @@ type GetStudentRankQuery struct {
 
 	// HistoryDays - за сколько дней показывать историю (по умолчанию 7).
 	HistoryDays int
+
+	// Public - запрос из публичного API: позиция скрывшегося из рейтинга
+	// студента не раскрывается, как и в самом лидерборде.
+	Public bool
 }
 
 // Validate проверяет корректность параметров запроса.
 func (q *GetStudentRankQuery) Validate() error {
-	if q.StudentID == "" && q.TelegramID == 0 {
+	if q.StudentID == "" && q.TelegramID == 0 && q.Student == nil {
 		return errors.New("either student_id or telegram_id must be provided")
 	}
+	if q.TelegramID != 0 && !student.TelegramID(q.TelegramID).IsValid() {
+		return student.ErrInvalidTelegramID
+	}
 	if q.HistoryDays < 0 {
 		return errors.New("history_days cannot be negative")
 	}
@@ -220,9 +231,12 @@ func (h *GetStudentRankHandler) Handle(ctx context.Context, query GetStudentRank
 	var stud *student.Student
 	var err error
 
-	if query.StudentID != "" {
+	switch {
+	case query.Student != nil:
+		stud = query.Student
+	case query.StudentID != "":
 		stud, err = h.studentRepo.GetByID(ctx, query.StudentID)
-	} else {
+	default:
 		stud, err = h.studentRepo.GetByTelegramID(ctx, student.TelegramID(query.TelegramID))
 	}
 
@@ -238,6 +252,11 @@ func (h *GetStudentRankHandler) Handle(ctx context.Context, query GetStudentRank
 		return nil, shared.WrapError("query", "GetStudentRank", shared.ErrNotFound, "rank not found", err)
 	}
 
+	// Скрывшийся студент для публичного API не отличается от ненайденного
+	if query.Public && entry != nil && !entry.VisibleTo("") {
+		return nil, shared.WrapError("query", "GetStudentRank", shared.ErrNotFound, "rank not found", nil)
+	}
+
 	// Если студент еще не попал в снапшот лидерборда
 	if entry == nil {
 		// Возвращаем пустой результат (студент без ранга)
@@ -357,15 +376,20 @@ func (h *GetStudentRankHandler) buildDTO(
 			if n.StudentID == stud.ID {
 				continue
 			}
+			// Имя скрывшегося соседа не показываем, разрыв в XP - да
+			name := ""
+			if n.VisibleTo(stud.ID) {
+				name = n.DisplayName
+			}
 			// Сосед выше (ранг меньше = выше)
 			if n.Rank < entry.Rank {
 				dto.XPToNextRank = int(n.XP) - int(entry.XP) + 1
-				dto.NextRankStudent = n.DisplayName
+				dto.NextRankStudent = name
 			}
 			// Сосед ниже
 			if n.Rank > entry.Rank {
 				dto.XPAheadOfPrevious = int(entry.XP) - int(n.XP)
-				dto.PreviousRankStudent = n.DisplayName
+				dto.PreviousRankStudent = name
 			}
 		}
 	}
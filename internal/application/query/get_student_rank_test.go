@@ -0,0 +1,48 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// rankBoard дополняет рейтинг соседями и лучшим рангом.
+type rankBoard struct {
+	*rankedBoard
+}
+
+func (rankBoard) GetNeighbors(context.Context, string, leaderboard.Cohort, int) ([]*leaderboard.LeaderboardEntry, error) {
+	return nil, nil
+}
+
+func (rankBoard) GetBestRank(context.Context, string) (*leaderboard.RankHistoryEntry, error) {
+	return nil, nil
+}
+
+func TestGetStudentRank_PublicHidesHiddenStudent(t *testing.T) {
+	students := &compareStudents{byID: map[string]*student.Student{
+		"s1": {ID: "s1", DisplayName: "Дана"},
+		"s2": {ID: "s2", DisplayName: "Арман"},
+	}}
+	h := NewGetStudentRankHandler(students, rankBoard{newRankedBoard(3, 2)}, nil, nil)
+	ctx := context.Background()
+
+	result, err := h.Handle(ctx, GetStudentRankQuery{StudentID: "s1", Public: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Student.Rank)
+
+	// Публичный API не раскрывает позицию скрывшегося студента
+	_, err = h.Handle(ctx, GetStudentRankQuery{StudentID: "s2", Public: true})
+	assert.True(t, shared.IsNotFound(err))
+
+	// Сам студент (/me) свою позицию видит
+	result, err = h.Handle(ctx, GetStudentRankQuery{StudentID: "s2"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Student.Rank)
+}
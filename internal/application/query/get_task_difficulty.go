@@ -0,0 +1,133 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/activity"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/pkg/pagination"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GET TASK DIFFICULTY QUERY
+// Сложность задач для дашборда: запросы помощи на 100 решений за 30 дней,
+// от самых сложных. Рассчитывается раз в день воркером.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// TaskDifficultyBounds - ограничения числа задач в ответе.
+var TaskDifficultyBounds = pagination.Bounds{DefaultLimit: 50, MaxLimit: 200}
+
+// GetTaskDifficultyQuery содержит параметры запроса.
+type GetTaskDifficultyQuery struct {
+	// Limit - максимальное количество задач.
+	Limit int
+}
+
+// Validate проверяет корректность параметров.
+func (q *GetTaskDifficultyQuery) Validate() error {
+	if q.Limit < 0 {
+		return fmt.Errorf("limit must not be negative")
+	}
+	q.Limit = TaskDifficultyBounds.ClampLimit(q.Limit)
+	return nil
+}
+
+// TaskDifficultyDTO - сложность одной задачи.
+type TaskDifficultyDTO struct {
+	TaskID   string `json:"task_id"`
+	TaskName string `json:"task_name"`
+
+	// Score - запросов помощи на 100 решений; nil, если решений меньше
+	// минимальной выборки.
+	Score *float64 `json:"score"`
+
+	HelpRequests int  `json:"help_requests"`
+	Completions  int  `json:"completions"`
+	High         bool `json:"high"`
+}
+
+// GetTaskDifficultyResult содержит результат запроса.
+type GetTaskDifficultyResult struct {
+	Tasks []TaskDifficultyDTO `json:"tasks"`
+
+	// MinSample - минимум решений для оценки.
+	MinSample int `json:"min_sample"`
+
+	// ComputedAt - когда рассчитан снимок; nil, если ещё не рассчитывался.
+	ComputedAt *time.Time `json:"computed_at"`
+}
+
+// GetTaskDifficultyHandler обрабатывает запросы сложности задач.
+type GetTaskDifficultyHandler struct {
+	repo    social.TaskDifficultyRepository
+	catalog activity.TaskCatalog
+}
+
+// NewGetTaskDifficultyHandler создаёт новый обработчик.
+// Без каталога вместо названия задачи отдаётся её ID.
+func NewGetTaskDifficultyHandler(repo social.TaskDifficultyRepository, catalog activity.TaskCatalog) *GetTaskDifficultyHandler {
+	return &GetTaskDifficultyHandler{repo: repo, catalog: catalog}
+}
+
+// Handle выполняет запрос.
+func (h *GetTaskDifficultyHandler) Handle(ctx context.Context, query GetTaskDifficultyQuery) (*GetTaskDifficultyResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, shared.WrapError("query", "GetTaskDifficulty", shared.ErrValidation, err.Error(), err)
+	}
+
+	difficulties, err := h.repo.List(ctx, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task difficulty: %w", err)
+	}
+
+	names := h.taskNames(ctx, difficulties)
+
+	result := &GetTaskDifficultyResult{
+		Tasks:     make([]TaskDifficultyDTO, 0, len(difficulties)),
+		MinSample: social.MinDifficultySample,
+	}
+	for _, d := range difficulties {
+		dto := TaskDifficultyDTO{
+			TaskID:       string(d.TaskID),
+			TaskName:     string(d.TaskID),
+			HelpRequests: d.HelpRequests,
+			Completions:  d.Completions,
+			High:         d.IsHigh(),
+		}
+		if name, ok := names[activity.TaskID(d.TaskID)]; ok {
+			dto.TaskName = name
+		}
+		if d.Scored {
+			score := d.Score
+			dto.Score = &score
+		}
+		if result.ComputedAt == nil || d.ComputedAt.After(*result.ComputedAt) {
+			computedAt := d.ComputedAt
+			result.ComputedAt = &computedAt
+		}
+		result.Tasks = append(result.Tasks, dto)
+	}
+
+	return result, nil
+}
+
+// taskNames резолвит названия через каталог; ошибка каталога не ломает ответ.
+func (h *GetTaskDifficultyHandler) taskNames(ctx context.Context, difficulties []social.TaskDifficulty) map[activity.TaskID]string {
+	if h.catalog == nil || len(difficulties) == 0 {
+		return nil
+	}
+
+	ids := make([]activity.TaskID, len(difficulties))
+	for i, d := range difficulties {
+		ids[i] = activity.TaskID(d.TaskID)
+	}
+
+	names, err := h.catalog.TaskNames(ctx, ids)
+	if err != nil {
+		return nil
+	}
+	return names
+}
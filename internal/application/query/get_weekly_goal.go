@@ -0,0 +1,81 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GET WEEKLY GOAL QUERY
+// Цель студента на текущую неделю и прогресс по ней. XP недели считается
+// по дневному прогрессу (daily_grinds) с понедельника по UTC.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// GetWeeklyGoalQuery содержит параметры запроса недельной цели.
+type GetWeeklyGoalQuery struct {
+	// StudentID - внутренний ID студента.
+	StudentID string
+}
+
+// GetWeeklyGoalResult содержит цель и прогресс по ней.
+type GetWeeklyGoalResult struct {
+	// Week - текущая неделя.
+	Week student.ISOWeek
+
+	// HasGoal - цель на неделю поставлена.
+	HasGoal bool
+
+	// Progress - прогресс цели (при HasGoal).
+	Progress student.WeeklyGoalProgress
+
+	// WeekXP - XP, набранный за неделю (есть и без цели).
+	WeekXP student.XP
+}
+
+// GetWeeklyGoalHandler обрабатывает запросы недельной цели.
+type GetWeeklyGoalHandler struct {
+	goals    student.WeeklyGoalRepository
+	progress student.ProgressRepository
+	now      func() time.Time
+}
+
+// NewGetWeeklyGoalHandler создаёт новый обработчик.
+func NewGetWeeklyGoalHandler(goals student.WeeklyGoalRepository, progress student.ProgressRepository) *GetWeeklyGoalHandler {
+	return &GetWeeklyGoalHandler{goals: goals, progress: progress, now: time.Now}
+}
+
+// Handle выполняет запрос.
+func (h *GetWeeklyGoalHandler) Handle(ctx context.Context, query GetWeeklyGoalQuery) (*GetWeeklyGoalResult, error) {
+	if query.StudentID == "" {
+		return nil, shared.WrapError("query", "GetWeeklyGoal", shared.ErrValidation, "student_id is required", nil)
+	}
+
+	now := h.now().UTC()
+	result := &GetWeeklyGoalResult{Week: student.ISOWeekOf(now)}
+
+	start, err := result.Week.Start()
+	if err != nil {
+		return nil, err
+	}
+	result.WeekXP, err = h.progress.GetXPGainedBetween(ctx, query.StudentID, start, start.AddDate(0, 0, 7))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get week xp: %w", err)
+	}
+
+	goal, err := h.goals.Get(ctx, query.StudentID, result.Week)
+	if err != nil {
+		if errors.Is(err, student.ErrGoalNotFound) {
+			return result, nil
+		}
+		return nil, fmt.Errorf("failed to get weekly goal: %w", err)
+	}
+
+	result.HasGoal = true
+	result.Progress = goal.Progress(result.WeekXP, now)
+	return result, nil
+}
@@ -0,0 +1,277 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PREVIEW NOTIFICATION QUERY
+// Предпросмотр уведомлений и дайджеста для администраторов: тот же рендерер,
+// что и при отправке, но без доставки студенту. Позволяет проверить новый
+// шаблон на реальных или синтетических данных до того, как его увидят все.
+//
+// Сообщения бота пока только на русском, поэтому язык не выбирается.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Источники данных для предпросмотра.
+const (
+	PreviewSourceStudent   = "student"
+	PreviewSourceSynthetic = "synthetic"
+)
+
+// PreviewNotificationQuery содержит параметры предпросмотра.
+type PreviewNotificationQuery struct {
+	// Type - тип уведомления.
+	Type string
+
+	// Template - шаблон вместо шаблона по умолчанию (необязательно).
+	Template string
+
+	// StudentID - реальный студент, чьи данные подставляются в шаблон.
+	StudentID string
+
+	// Data - синтетические данные вместо реального студента.
+	Data *notification.NotificationData
+
+	// Digest - синтетическое содержимое ежедневной сводки.
+	Digest *notification.DigestContent
+}
+
+// Validate проверяет корректность параметров.
+func (q *PreviewNotificationQuery) Validate() error {
+	if !notification.NotificationType(q.Type).IsValid() {
+		return fmt.Errorf("unknown notification type %q", q.Type)
+	}
+	synthetic := q.Data != nil || q.Digest != nil
+	if q.StudentID == "" && !synthetic {
+		return errors.New("either student_id or synthetic data must be provided")
+	}
+	if q.StudentID != "" && synthetic {
+		return errors.New("student_id and synthetic data are mutually exclusive")
+	}
+	if q.rendersDigest() && q.StudentID == "" && q.Digest == nil {
+		return errors.New("daily_digest preview without a template needs digest data")
+	}
+	if !q.rendersDigest() && q.Template == "" {
+		if _, ok := notification.DefaultTemplate(notification.NotificationType(q.Type)); !ok {
+			return fmt.Errorf("notification type %q has no default template, pass one", q.Type)
+		}
+	}
+	return nil
+}
+
+// rendersDigest возвращает true, если рендерится полная ежедневная сводка.
+func (q *PreviewNotificationQuery) rendersDigest() bool {
+	return notification.NotificationType(q.Type) == notification.NotificationTypeDailyDigest && q.Template == ""
+}
+
+// PreviewNotificationResult содержит отрендеренное сообщение.
+type PreviewNotificationResult struct {
+	Type      string                       `json:"type"`
+	Source    string                       `json:"source"`
+	StudentID string                       `json:"student_id,omitempty"`
+	Template  string                       `json:"template,omitempty"`
+	Text      string                       `json:"text"`
+	ParseMode string                       `json:"parse_mode"`
+	Length    int                          `json:"length"`
+	Warnings  []notification.RenderWarning `json:"warnings"`
+}
+
+// PreviewNotificationHandler обрабатывает запросы предпросмотра.
+type PreviewNotificationHandler struct {
+	studentRepo     student.Repository
+	progressRepo    student.ProgressRepository
+	leaderboardRepo leaderboard.LeaderboardRepository
+	renderer        *notification.Renderer
+}
+
+// NewPreviewNotificationHandler создаёт новый обработчик.
+func NewPreviewNotificationHandler(
+	studentRepo student.Repository,
+	progressRepo student.ProgressRepository,
+	leaderboardRepo leaderboard.LeaderboardRepository,
+) *PreviewNotificationHandler {
+	return &PreviewNotificationHandler{
+		studentRepo:     studentRepo,
+		progressRepo:    progressRepo,
+		leaderboardRepo: leaderboardRepo,
+		renderer:        notification.NewRenderer(),
+	}
+}
+
+// Handle выполняет запрос.
+func (h *PreviewNotificationHandler) Handle(ctx context.Context, query PreviewNotificationQuery) (*PreviewNotificationResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, shared.WrapError("query", "PreviewNotification", shared.ErrValidation, err.Error(), err)
+	}
+
+	result := &PreviewNotificationResult{
+		Type:      query.Type,
+		Source:    PreviewSourceSynthetic,
+		ParseMode: h.renderer.SupportedFormat(),
+		Warnings:  []notification.RenderWarning{},
+	}
+
+	var snapshot *studentSnapshot
+	if query.StudentID != "" {
+		s, err := h.studentRepo.GetByID(ctx, query.StudentID)
+		if err != nil {
+			return nil, shared.WrapError("query", "PreviewNotification", shared.ErrNotFound, "student not found", err)
+		}
+		snapshot = h.loadSnapshot(ctx, s)
+		result.Source = PreviewSourceStudent
+		result.StudentID = s.ID
+	}
+
+	if query.rendersDigest() {
+		content := query.Digest
+		if snapshot != nil {
+			content = snapshot.digest()
+		}
+		result.Text = notification.RenderDigest(content)
+	} else {
+		tmpl := query.Template
+		if tmpl == "" {
+			tmpl, _ = notification.DefaultTemplate(notification.NotificationType(query.Type))
+		}
+		result.Template = tmpl
+
+		unknown, err := notification.UnknownTemplateFields(tmpl)
+		if err != nil {
+			return nil, shared.WrapError("query", "PreviewNotification", shared.ErrValidation, err.Error(), err)
+		}
+		for _, field := range unknown {
+			result.Warnings = append(result.Warnings, notification.RenderWarning{
+				Code:    notification.WarningUnknownField,
+				Message: fmt.Sprintf("template references unknown field %q", field),
+			})
+		}
+		// Шаблон с неизвестными полями упадёт при отправке — показываем только предупреждения
+		if len(unknown) > 0 {
+			return result, nil
+		}
+
+		data := query.Data
+		if snapshot != nil {
+			data = snapshot.data()
+		}
+		if data == nil {
+			data = &notification.NotificationData{}
+		}
+
+		text, err := h.renderer.Format(tmpl, *data)
+		if err != nil {
+			return nil, shared.WrapError("query", "PreviewNotification", shared.ErrValidation, err.Error(), err)
+		}
+		result.Text = text
+	}
+
+	result.Length = notification.MessageLength(result.Text)
+	result.Warnings = append(result.Warnings, notification.CheckMessage(result.Text)...)
+
+	return result, nil
+}
+
+// studentSnapshot - данные реального студента для подстановки в шаблоны.
+type studentSnapshot struct {
+	student *student.Student
+	grind   *student.DailyGrind
+	streak  *student.Streak
+	rank    *leaderboard.LeaderboardEntry
+	now     time.Time
+}
+
+// loadSnapshot собирает данные студента. Недоступные части просто пропускаются:
+// предпросмотр должен работать и для новичка без истории.
+func (h *PreviewNotificationHandler) loadSnapshot(ctx context.Context, s *student.Student) *studentSnapshot {
+	snap := &studentSnapshot{student: s, now: time.Now()}
+
+	if h.progressRepo != nil {
+		if grind, err := h.progressRepo.GetDailyGrind(ctx, s.ID, snap.now.Truncate(24*time.Hour)); err == nil {
+			snap.grind = grind
+		}
+		if streak, err := h.progressRepo.GetStreak(ctx, s.ID); err == nil {
+			snap.streak = streak
+		}
+	}
+	if h.leaderboardRepo != nil {
+		if entry, err := h.leaderboardRepo.GetStudentRank(ctx, s.ID, leaderboard.CohortAll); err == nil {
+			snap.rank = entry
+		}
+	}
+
+	return snap
+}
+
+// data строит NotificationData из снимка.
+func (s *studentSnapshot) data() *notification.NotificationData {
+	data := &notification.NotificationData{
+		TotalXP:      int(s.student.CurrentXP),
+		NewLevel:     int(s.student.Level()),
+		DaysInactive: s.student.DaysSinceLastSeen(),
+	}
+	if s.grind != nil {
+		data.XPGained = int(s.grind.XPGained)
+		data.TasksCompleted = s.grind.TasksCompleted
+	}
+	if s.streak != nil {
+		data.StreakDays = s.streak.CurrentStreak
+		data.BestStreak = s.streak.BestStreak
+	}
+	if s.rank != nil {
+		data.NewRank = int(s.rank.Rank)
+		data.RankChange = int(s.rank.RankChange)
+		data.OldRank = data.NewRank + data.RankChange
+	}
+	return data
+}
+
+// digest строит содержимое сводки из снимка. Социальные показатели
+// и статистика сообщества собираются только джобой рассылки.
+func (s *studentSnapshot) digest() *notification.DigestContent {
+	content := &notification.DigestContent{
+		StudentName: s.student.DisplayName,
+		Date:        s.now.Format("02.01.2006"),
+		TotalXP:     int(s.student.CurrentXP),
+		Level:       int(s.student.Level()),
+	}
+	if s.grind != nil {
+		content.TodayXP = int(s.grind.XPGained)
+		content.TasksCompleted = s.grind.TasksCompleted
+	}
+	if s.streak != nil {
+		content.CurrentStreak = s.streak.CurrentStreak
+		content.BestStreak = s.streak.BestStreak
+		switch {
+		case s.streak.CurrentStreak > s.streak.BestStreak:
+			content.StreakStatus = notification.DigestStreakNewRecord
+		case s.streak.CurrentStreak > 0:
+			content.StreakStatus = notification.DigestStreakMaintained
+		default:
+			content.StreakStatus = notification.DigestStreakBroken
+		}
+	}
+	if s.rank != nil {
+		content.CurrentRank = int(s.rank.Rank)
+		content.RankChange = int(s.rank.RankChange)
+		switch {
+		case s.rank.RankChange > 0:
+			content.RankDirection = "up"
+		case s.rank.RankChange < 0:
+			content.RankDirection = "down"
+		default:
+			content.RankDirection = "same"
+		}
+	}
+	content.MotivationalQuote = notification.DigestQuote(s.now)
+	content.PersonalizedTip = notification.DigestTip(content, s.now)
+	return content
+}
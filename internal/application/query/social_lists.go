@@ -0,0 +1,300 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// SOCIAL LISTS QUERIES
+// Рейтинг помощников, благодарности и связи студента. Репозиторий social
+// возвращает только ID студентов, имена подставляются одной пачкой через
+// DisplayNameResolver, без JOIN с таблицей students.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// DefaultTopHelpersDays - период рейтинга помощников по умолчанию.
+	DefaultTopHelpersDays = 30
+
+	// MaxTopHelpersDays - максимальный период рейтинга помощников.
+	MaxTopHelpersDays = 365
+)
+
+// TopHelpersBounds - лимиты размера рейтинга помощников.
+var TopHelpersBounds = social.ListBounds
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Top Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// GetTopHelpersQuery содержит параметры рейтинга помощников.
+type GetTopHelpersQuery struct {
+	// Days - за сколько последних дней учитывать благодарности (по умолчанию 30).
+	Days int
+
+	// Limit - размер рейтинга.
+	Limit int
+}
+
+// Validate проверяет корректность параметров.
+func (q GetTopHelpersQuery) Validate() error {
+	if q.Days < 0 || q.Days > MaxTopHelpersDays {
+		return fmt.Errorf("days must be between 1 and %d", MaxTopHelpersDays)
+	}
+	return nil
+}
+
+// TopHelperDTO - запись рейтинга помощников.
+type TopHelperDTO struct {
+	Rank             int     `json:"rank"`
+	StudentID        string  `json:"student_id"`
+	DisplayName      string  `json:"display_name"`
+	AverageRating    float64 `json:"average_rating"`
+	EndorsementCount int     `json:"endorsement_count"`
+	HelpCount        int     `json:"help_count"`
+}
+
+// GetTopHelpersResult содержит рейтинг помощников.
+type GetTopHelpersResult struct {
+	Since   string         `json:"since"`
+	Helpers []TopHelperDTO `json:"helpers"`
+}
+
+// GetTopHelpersHandler обрабатывает запросы рейтинга помощников.
+type GetTopHelpersHandler struct {
+	endorsements social.EndorsementRepository
+	names        student.DisplayNameResolver
+	now          func() time.Time
+}
+
+// NewGetTopHelpersHandler создаёт новый обработчик.
+func NewGetTopHelpersHandler(endorsements social.EndorsementRepository, names student.DisplayNameResolver) *GetTopHelpersHandler {
+	return &GetTopHelpersHandler{endorsements: endorsements, names: names, now: time.Now}
+}
+
+// Handle выполняет запрос.
+func (h *GetTopHelpersHandler) Handle(ctx context.Context, query GetTopHelpersQuery) (*GetTopHelpersResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, shared.WrapError("query", "GetTopHelpers", shared.ErrValidation, err.Error(), err)
+	}
+
+	days := query.Days
+	if days == 0 {
+		days = DefaultTopHelpersDays
+	}
+	since := h.now().UTC().AddDate(0, 0, -days)
+
+	entries, err := h.endorsements.GetTopHelpers(ctx, TopHelpersBounds.ClampLimit(query.Limit), since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top helpers: %w", err)
+	}
+
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = string(e.StudentID)
+	}
+	names := h.names.Resolve(ctx, ids)
+
+	result := &GetTopHelpersResult{
+		Since:   since.Format("2006-01-02"),
+		Helpers: make([]TopHelperDTO, 0, len(entries)),
+	}
+	for _, e := range entries {
+		result.Helpers = append(result.Helpers, TopHelperDTO{
+			Rank:             e.Rank,
+			StudentID:        string(e.StudentID),
+			DisplayName:      names[string(e.StudentID)],
+			AverageRating:    float64(e.AverageRating),
+			EndorsementCount: e.EndorsementCount,
+			HelpCount:        e.HelpCount,
+		})
+	}
+
+	return result, nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Endorsements
+// ─────────────────────────────────────────────────────────────────────────────
+
+// ListEndorsementsQuery содержит параметры списка полученных благодарностей.
+type ListEndorsementsQuery struct {
+	StudentID string
+	Limit     int
+	Offset    int
+}
+
+// EndorsementDTO - благодарность в списке.
+type EndorsementDTO struct {
+	ID        string    `json:"id"`
+	GiverID   string    `json:"giver_id"`
+	GiverName string    `json:"giver_name"`
+	Rating    float64   `json:"rating"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListEndorsementsResult содержит страницу благодарностей.
+type ListEndorsementsResult struct {
+	Endorsements []EndorsementDTO `json:"endorsements"`
+	HasMore      bool             `json:"has_more"`
+}
+
+// ListEndorsementsHandler обрабатывает запросы списка благодарностей.
+type ListEndorsementsHandler struct {
+	endorsements social.EndorsementRepository
+	names        student.DisplayNameResolver
+	blocklist    social.ContentBlocklist
+}
+
+// NewListEndorsementsHandler создаёт новый обработчик.
+func NewListEndorsementsHandler(endorsements social.EndorsementRepository, names student.DisplayNameResolver) *ListEndorsementsHandler {
+	return &ListEndorsementsHandler{endorsements: endorsements, names: names}
+}
+
+// WithContentBlocklist включает маскировку фраз из чёрного списка в
+// комментариях.
+func (h *ListEndorsementsHandler) WithContentBlocklist(blocklist social.ContentBlocklist) *ListEndorsementsHandler {
+	h.blocklist = blocklist
+	return h
+}
+
+// Handle выполняет запрос.
+func (h *ListEndorsementsHandler) Handle(ctx context.Context, query ListEndorsementsQuery) (*ListEndorsementsResult, error) {
+	if query.StudentID == "" {
+		return nil, shared.WrapError("query", "ListEndorsements", shared.ErrValidation, "student_id is required", nil)
+	}
+
+	opts := social.DefaultEndorsementListOptions()
+	opts.Limit = query.Limit
+	opts.Offset = query.Offset
+	page := opts.Normalized()
+	// Лишняя запись показывает, есть ли следующая страница
+	opts.Limit, opts.Offset = page.Limit+1, page.Offset
+	opts.Bounds.MaxLimit = page.Limit + 1
+
+	endorsements, err := h.endorsements.GetByReceiverID(ctx, social.StudentID(query.StudentID), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endorsements: %w", err)
+	}
+
+	result := &ListEndorsementsResult{Endorsements: []EndorsementDTO{}}
+	if len(endorsements) > page.Limit {
+		endorsements = endorsements[:page.Limit]
+		result.HasMore = true
+	}
+
+	ids := make([]string, len(endorsements))
+	for i, e := range endorsements {
+		ids[i] = string(e.GiverID)
+	}
+	names := h.names.Resolve(ctx, ids)
+
+	// Список публичный: зритель не связан с автором комментария
+	render := social.RenderOptions{MaxRunes: social.MaxEndorsementCommentLength}
+	if h.blocklist != nil {
+		render.Blocklist = h.blocklist.Phrases(ctx)
+	}
+
+	for _, e := range endorsements {
+		result.Endorsements = append(result.Endorsements, EndorsementDTO{
+			ID:        e.ID,
+			GiverID:   string(e.GiverID),
+			GiverName: names[string(e.GiverID)],
+			Rating:    float64(e.Rating),
+			Comment:   social.RenderUserText(e.Comment, render),
+			CreatedAt: e.CreatedAt,
+		})
+	}
+
+	return result, nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Connections
+// ─────────────────────────────────────────────────────────────────────────────
+
+// ListConnectionsQuery содержит параметры списка связей студента.
+type ListConnectionsQuery struct {
+	StudentID string
+	Limit     int
+	Offset    int
+}
+
+// ConnectionDTO - связь в списке, с точки зрения студента из запроса.
+type ConnectionDTO struct {
+	ID          string    `json:"id"`
+	StudentID   string    `json:"student_id"`
+	DisplayName string    `json:"display_name"`
+	Type        string    `json:"type"`
+	Initiated   bool      `json:"initiated"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ListConnectionsResult содержит страницу связей.
+type ListConnectionsResult struct {
+	Connections []ConnectionDTO `json:"connections"`
+	HasMore     bool            `json:"has_more"`
+}
+
+// ListConnectionsHandler обрабатывает запросы списка связей.
+type ListConnectionsHandler struct {
+	connections social.ConnectionRepository
+	names       student.DisplayNameResolver
+}
+
+// NewListConnectionsHandler создаёт новый обработчик.
+func NewListConnectionsHandler(connections social.ConnectionRepository, names student.DisplayNameResolver) *ListConnectionsHandler {
+	return &ListConnectionsHandler{connections: connections, names: names}
+}
+
+// Handle выполняет запрос.
+func (h *ListConnectionsHandler) Handle(ctx context.Context, query ListConnectionsQuery) (*ListConnectionsResult, error) {
+	if query.StudentID == "" {
+		return nil, shared.WrapError("query", "ListConnections", shared.ErrValidation, "student_id is required", nil)
+	}
+
+	opts := social.DefaultConnectionListOptions()
+	opts.Limit = query.Limit
+	opts.Offset = query.Offset
+	page := opts.Normalized()
+	// Лишняя запись показывает, есть ли следующая страница
+	opts.Limit, opts.Offset = page.Limit+1, page.Offset
+	opts.Bounds.MaxLimit = page.Limit + 1
+
+	self := social.StudentID(query.StudentID)
+	connections, err := h.connections.GetByStudentID(ctx, self, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list connections: %w", err)
+	}
+
+	result := &ListConnectionsResult{Connections: []ConnectionDTO{}}
+	if len(connections) > page.Limit {
+		connections = connections[:page.Limit]
+		result.HasMore = true
+	}
+
+	ids := make([]string, len(connections))
+	for i, c := range connections {
+		ids[i] = string(c.GetOtherStudent(self))
+	}
+	names := h.names.Resolve(ctx, ids)
+
+	for i, c := range connections {
+		result.Connections = append(result.Connections, ConnectionDTO{
+			ID:          c.ID,
+			StudentID:   ids[i],
+			DisplayName: names[ids[i]],
+			Type:        string(c.Type),
+			Initiated:   c.InitiatorID == self,
+			CreatedAt:   c.CreatedAt,
+		})
+	}
+
+	return result, nil
+}
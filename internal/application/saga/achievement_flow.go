@@ -4,8 +4,10 @@ package saga
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
@@ -17,9 +19,12 @@ import (
 // ══════════════════════════════════════════════════════════════════════════════
 // ACHIEVEMENT FLOW SAGA
 // Complex business process: Achievement unlocking and notification
-// Flow: Check Conditions → Validate Not Already Unlocked → Grant Achievement →
+// Flow: Check Conditions → Validate Not Already Unlocked →
 //
-//	Award XP Bonus → Send Notification → Update Statistics → Publish Event
+//	Grant Achievement with XP Bonus → Send Notification → Update Statistics → Publish Event
+//
+// Runs that reach Grant Achievements are recorded as saga executions and can be
+// resumed from the step after the last completed one (see Resume).
 //
 // Philosophy: Achievements celebrate progress and encourage collaboration.
 // They are designed to motivate students and recognize both individual
@@ -36,6 +41,16 @@ type AchievementCheckInput struct {
 
 	// Context - additional context for achievement checking.
 	Context AchievementContext
+
+	// OnlyTypes - if set, only these achievement types are granted.
+	// Backfills use it so that time-of-day achievements are not handed out
+	// to everyone just because the backfill ran at night.
+	OnlyTypes []student.AchievementType
+
+	// Silent - grant achievements without notifying the student or
+	// publishing events (the activity feed would announce old news).
+	// Used by backfills so long-time leaders aren't notified out of the blue.
+	Silent bool
 }
 
 // AchievementContext contains contextual data for achievement evaluation.
@@ -46,9 +61,12 @@ type AchievementContext struct {
 	// NewLevel - current level after the triggering event.
 	NewLevel int
 
-	// CurrentRank - current position in leaderboard.
+	// CurrentRank - current position in the global leaderboard.
 	CurrentRank int
 
+	// CohortRank - current position in the student's cohort leaderboard.
+	CohortRank int
+
 	// CurrentStreak - current daily streak.
 	CurrentStreak int
 
@@ -61,6 +79,17 @@ type AchievementContext struct {
 	// DaysInactive - days since last activity (for comeback achievement).
 	DaysInactive int
 
+	// GoalWeeksInARow - consecutive weeks the weekly XP goal was reached.
+	GoalWeeksInARow int
+
+	// HelperWeeksInARow - consecutive weeks the student helped others.
+	HelperWeeksInARow int
+
+	// EventID and EventScore - the finished community event and the
+	// student's score in it.
+	EventID    string
+	EventScore int
+
 	// TaskID - ID of the completed task (if applicable).
 	TaskID string
 
@@ -107,6 +136,8 @@ const (
 	StepLoadExistingAchievs AchievementFlowStep = "load_existing_achievements"
 	StepCheckAchievements   AchievementFlowStep = "check_achievements"
 	StepGrantAchievements   AchievementFlowStep = "grant_achievements"
+	// StepAwardXPBonus is no longer run: bonuses are awarded together with the
+	// grant. Executions recorded with it resume from the grant.
 	StepAwardXPBonus        AchievementFlowStep = "award_xp_bonus"
 	StepSendNotifications   AchievementFlowStep = "send_notifications"
 	StepUpdateStats         AchievementFlowStep = "update_statistics"
@@ -114,6 +145,15 @@ const (
 	StepAchievementComplete AchievementFlowStep = "complete"
 )
 
+// achievementFlowRecordedSteps are the steps that change state. They run
+// after the execution is recorded and are each safe to repeat on recovery.
+var achievementFlowRecordedSteps = []AchievementFlowStep{
+	StepGrantAchievements,
+	StepSendNotifications,
+	StepUpdateStats,
+	StepPublishAchievEvents,
+}
+
 // AchievementFlowState tracks the current state of the achievement flow saga.
 type AchievementFlowState struct {
 	CurrentStep          AchievementFlowStep
@@ -130,6 +170,23 @@ type AchievementFlowState struct {
 	FailedStep           AchievementFlowStep
 }
 
+// achievementFlowProgress is the part of the state stored with the execution.
+// Everything else is reloaded when the flow is resumed.
+type achievementFlowProgress struct {
+	NewAchievements   []student.Achievement `json:"new_achievements"`
+	TotalXPBonus      int                   `json:"total_xp_bonus"`
+	NotificationsSent int                   `json:"notifications_sent"`
+}
+
+// progress returns the state to persist after a step.
+func (s *AchievementFlowState) progress() achievementFlowProgress {
+	return achievementFlowProgress{
+		NewAchievements:   s.NewAchievements,
+		TotalXPBonus:      s.TotalXPBonus,
+		NotificationsSent: s.NotificationsSent,
+	}
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // ACHIEVEMENT FLOW SAGA IMPLEMENTATION
 // ══════════════════════════════════════════════════════════════════════════════
@@ -142,9 +199,12 @@ type AchievementFlowSaga struct {
 	progressRepo       student.ProgressRepository
 	leaderboardRepo    leaderboard.LeaderboardRepository
 	notificationSvc    notification.NotificationService
+	notificationRepo   notification.NotificationRepository
 	eventBus           shared.EventPublisher
 	achievementChecker *student.AchievementChecker
 	idGenerator        IDGenerator
+	executions         ExecutionRepository
+	now                func() time.Time
 
 	// Configuration
 	enableXPBonuses       bool
@@ -169,13 +229,17 @@ func DefaultAchievementFlowConfig() AchievementFlowConfig {
 }
 
 // NewAchievementFlowSaga creates a new achievement flow saga with all dependencies.
+// notificationRepo and executions are optional: without them notifications are
+// not checked for duplicates and runs are not recorded.
 func NewAchievementFlowSaga(
 	studentRepo student.Repository,
 	progressRepo student.ProgressRepository,
 	leaderboardRepo leaderboard.LeaderboardRepository,
 	notificationSvc notification.NotificationService,
+	notificationRepo notification.NotificationRepository,
 	eventBus shared.EventPublisher,
 	idGenerator IDGenerator,
+	executions ExecutionRepository,
 	config AchievementFlowConfig,
 ) *AchievementFlowSaga {
 	return &AchievementFlowSaga{
@@ -183,9 +247,12 @@ func NewAchievementFlowSaga(
 		progressRepo:          progressRepo,
 		leaderboardRepo:       leaderboardRepo,
 		notificationSvc:       notificationSvc,
+		notificationRepo:      notificationRepo,
 		eventBus:              eventBus,
 		achievementChecker:    student.NewAchievementChecker(),
 		idGenerator:           idGenerator,
+		executions:            executions,
+		now:                   time.Now,
 		enableXPBonuses:       config.EnableXPBonuses,
 		enableNotifications:   config.EnableNotifications,
 		maxAchievementsPerRun: config.MaxAchievementsPerRun,
@@ -237,41 +304,93 @@ func (s *AchievementFlowSaga) Execute(ctx context.Context, input AchievementChec
 		}, nil
 	}
 
-	// Step 4: Grant achievements (persist to DB)
-	state.CurrentStep = StepGrantAchievements
-	if err := s.stepGrantAchievements(ctx, state); err != nil {
-		return nil, s.wrapError(state, err)
+	// From here on the flow changes state, so the run is recorded
+	rec := &executionRecorder{repo: s.executions, now: s.now}
+	rec.start(ctx, s.idGenerator.GenerateID(), SagaTypeAchievementFlow, input, string(StepCheckAchievements), state.progress())
+
+	// Steps 4-7: Grant with XP Bonus → Notifications → Statistics → Events
+	return s.runRecordedSteps(ctx, state, rec, StepGrantAchievements)
+}
+
+// Resume continues an interrupted execution from the step after the last
+// completed one. The student is reloaded; the achievements found by the
+// original run are taken from the execution state.
+func (s *AchievementFlowSaga) Resume(ctx context.Context, exec *Execution) (*AchievementFlowResult, error) {
+	if exec.SagaType != SagaTypeAchievementFlow || exec.IsFinished() {
+		return nil, ErrExecutionNotResumable
 	}
 
-	// Step 5: Award XP bonuses
-	state.CurrentStep = StepAwardXPBonus
-	if err := s.stepAwardXPBonus(ctx, state); err != nil {
-		// Non-critical - log but continue
-		// XP can be awarded manually later
+	rec := &executionRecorder{repo: s.executions, now: s.now}
+	rec.resume(exec)
+
+	var input AchievementCheckInput
+	if err := json.Unmarshal(exec.Input, &input); err != nil {
+		err = fmt.Errorf("%w: decode input: %v", ErrExecutionNotResumable, err)
+		rec.fail(ctx, err)
+		return nil, err
+	}
+	var progress achievementFlowProgress
+	if err := json.Unmarshal(exec.State, &progress); err != nil {
+		err = fmt.Errorf("%w: decode state: %v", ErrExecutionNotResumable, err)
+		rec.fail(ctx, err)
+		return nil, err
 	}
 
-	// Step 6: Send notifications
-	state.CurrentStep = StepSendNotifications
-	if err := s.stepSendNotifications(ctx, state); err != nil {
-		// Non-critical - log but continue
+	state := &AchievementFlowState{
+		CurrentStep:       AchievementFlowStep(exec.CurrentStep),
+		Input:             input,
+		NewAchievements:   progress.NewAchievements,
+		TotalXPBonus:      progress.TotalXPBonus,
+		NotificationsSent: progress.NotificationsSent,
+		StartedAt:         exec.StartedAt,
 	}
 
-	// Step 7: Update statistics
-	state.CurrentStep = StepUpdateStats
-	if err := s.stepUpdateStatistics(ctx, state); err != nil {
-		// Non-critical - log but continue
+	if err := s.stepLoadStudent(ctx, state); err != nil {
+		rec.fail(ctx, err)
+		return nil, s.wrapError(state, err)
 	}
 
-	// Step 8: Publish domain events
-	state.CurrentStep = StepPublishAchievEvents
-	if err := s.stepPublishEvents(ctx, state); err != nil {
-		// Non-critical - events can be replayed
+	return s.runRecordedSteps(ctx, state, rec, nextAchievementFlowStep(state.CurrentStep))
+}
+
+// runRecordedSteps runs the state-changing steps starting at from,
+// recording each completed step.
+func (s *AchievementFlowSaga) runRecordedSteps(
+	ctx context.Context,
+	state *AchievementFlowState,
+	rec *executionRecorder,
+	from AchievementFlowStep,
+) (*AchievementFlowResult, error) {
+	start := slices.Index(achievementFlowRecordedSteps, from)
+	if start < 0 {
+		start = len(achievementFlowRecordedSteps)
+	}
+
+	for _, step := range achievementFlowRecordedSteps[start:] {
+		// A cancelled run stays "running" and is picked up by recovery
+		if err := ctx.Err(); err != nil {
+			state.FailedStep = step
+			return nil, s.wrapError(state, err)
+		}
+
+		state.CurrentStep = step
+		if err := s.runStep(ctx, state, step); err != nil {
+			if step == StepGrantAchievements {
+				rec.fail(ctx, err)
+				return nil, s.wrapError(state, err)
+			}
+			// Other steps are non-critical - continue
+			// (notifications are checked for duplicates, events can be replayed)
+		}
+
+		rec.stepDone(ctx, string(step), state.progress())
 	}
 
 	// Complete
 	state.CurrentStep = StepAchievementComplete
-	now := time.Now().UTC()
+	now := s.now().UTC()
 	state.CompletedAt = &now
+	rec.complete(ctx, string(StepAchievementComplete))
 
 	return &AchievementFlowResult{
 		StudentID:         state.Input.StudentID,
@@ -282,6 +401,36 @@ func (s *AchievementFlowSaga) Execute(ctx context.Context, input AchievementChec
 	}, nil
 }
 
+// runStep dispatches a state-changing step.
+func (s *AchievementFlowSaga) runStep(ctx context.Context, state *AchievementFlowState, step AchievementFlowStep) error {
+	switch step {
+	case StepGrantAchievements:
+		return s.stepGrantAchievements(ctx, state)
+	case StepSendNotifications:
+		return s.stepSendNotifications(ctx, state)
+	case StepUpdateStats:
+		return s.stepUpdateStatistics(ctx, state)
+	case StepPublishAchievEvents:
+		return s.stepPublishEvents(ctx, state)
+	default:
+		return fmt.Errorf("unknown step %q", step)
+	}
+}
+
+// nextAchievementFlowStep returns the step to resume after the given
+// completed step. Executions are recorded after the check, so any earlier
+// step resumes from the grant.
+func nextAchievementFlowStep(completed AchievementFlowStep) AchievementFlowStep {
+	i := slices.Index(achievementFlowRecordedSteps, completed)
+	if i < 0 {
+		return StepGrantAchievements
+	}
+	if i+1 < len(achievementFlowRecordedSteps) {
+		return achievementFlowRecordedSteps[i+1]
+	}
+	return StepAchievementComplete
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // SAGA STEPS
 // ══════════════════════════════════════════════════════════════════════════════
@@ -325,17 +474,14 @@ func (s *AchievementFlowSaga) stepLoadExistingAchievements(ctx context.Context,
 func (s *AchievementFlowSaga) stepCheckAchievements(ctx context.Context, state *AchievementFlowState) error {
 	var newAchievements []student.Achievement
 
-	// Get current rank from context or fetch it
+	// Get global and cohort ranks from context or fetch them
 	currentRank := state.Input.Context.CurrentRank
-	if currentRank == 0 && s.leaderboardRepo != nil {
-		entry, err := s.leaderboardRepo.GetStudentRank(
-			ctx,
-			state.Student.ID,
-			leaderboard.Cohort(state.Student.Cohort),
-		)
-		if err == nil && entry != nil {
-			currentRank = int(entry.Rank)
-		}
+	if currentRank == 0 {
+		currentRank = s.fetchRank(ctx, state.Student.ID, leaderboard.CohortAll)
+	}
+	cohortRank := state.Input.Context.CohortRank
+	if cohortRank == 0 && state.Student.Cohort != "" {
+		cohortRank = s.fetchRank(ctx, state.Student.ID, leaderboard.Cohort(state.Student.Cohort))
 	}
 
 	// Use the domain achievement checker for standard achievements
@@ -343,6 +489,7 @@ func (s *AchievementFlowSaga) stepCheckAchievements(ctx context.Context, state *
 		state.Student,
 		state.Streak,
 		currentRank,
+		cohortRank,
 		state.ExistingAchievements,
 	)
 	newAchievements = append(newAchievements, standardAchievements...)
@@ -369,6 +516,48 @@ func (s *AchievementFlowSaga) stepCheckAchievements(ctx context.Context, state *
 		}
 	}
 
+	// Check for "Goal Getter" achievement
+	if state.Input.Context.GoalWeeksInARow > 0 {
+		goalGetter := s.achievementChecker.CheckGoalGetter(
+			state.Input.Context.GoalWeeksInARow,
+			state.ExistingAchievements,
+		)
+		if goalGetter != nil {
+			newAchievements = append(newAchievements, *goalGetter)
+		}
+	}
+
+	// Check for "Consistent Helper" achievements
+	if state.Input.Context.HelperWeeksInARow > 0 {
+		newAchievements = append(newAchievements, s.achievementChecker.CheckConsistentHelper(
+			state.Input.Context.HelperWeeksInARow,
+			state.ExistingAchievements,
+		)...)
+	}
+
+	// Check for "Event Participant" achievement
+	if state.Input.Context.EventScore > 0 {
+		participant := s.achievementChecker.CheckEventParticipant(
+			state.Input.Context.EventID,
+			state.Input.Context.EventScore,
+			state.ExistingAchievements,
+		)
+		if participant != nil {
+			newAchievements = append(newAchievements, *participant)
+		}
+	}
+
+	// Restrict to the requested types (backfills)
+	if len(state.Input.OnlyTypes) > 0 {
+		filtered := newAchievements[:0]
+		for _, a := range newAchievements {
+			if slices.Contains(state.Input.OnlyTypes, a.Type) {
+				filtered = append(filtered, a)
+			}
+		}
+		newAchievements = filtered
+	}
+
 	// Limit achievements per run to prevent spam
 	if len(newAchievements) > s.maxAchievementsPerRun {
 		newAchievements = newAchievements[:s.maxAchievementsPerRun]
@@ -378,61 +567,31 @@ func (s *AchievementFlowSaga) stepCheckAchievements(ctx context.Context, state *
 	return nil
 }
 
-// stepGrantAchievements persists the new achievements to the database.
+// stepGrantAchievements persists the new achievements together with their
+// XP bonuses. Each achievement is granted in its own transaction, so a failure
+// leaves the earlier ones complete and the rest untouched; a retry grants the
+// rest without awarding any bonus twice.
 func (s *AchievementFlowSaga) stepGrantAchievements(ctx context.Context, state *AchievementFlowState) error {
-	for _, achievement := range state.NewAchievements {
-		if err := s.progressRepo.SaveAchievement(ctx, state.Input.StudentID, achievement); err != nil {
-			state.FailedStep = StepGrantAchievements
-			state.Error = fmt.Errorf("failed to save achievement %s: %w", achievement.Type, err)
-			return state.Error
-		}
-	}
-
-	return nil
-}
-
-// stepAwardXPBonus awards XP bonuses for each achievement.
-func (s *AchievementFlowSaga) stepAwardXPBonus(ctx context.Context, state *AchievementFlowState) error {
-	if !s.enableXPBonuses {
-		return nil
-	}
-
 	totalBonus := 0
 
 	for _, achievement := range state.NewAchievements {
-		def, found := student.GetAchievementDefinition(achievement.Type)
-		if !found {
-			continue
-		}
-
-		if def.XPBonus > 0 {
-			totalBonus += int(def.XPBonus)
-
-			// Record XP change in history
-			entry := student.XPHistoryEntry{
-				Timestamp: time.Now().UTC(),
-				OldXP:     state.Student.CurrentXP,
-				NewXP:     state.Student.CurrentXP + student.XP(def.XPBonus),
-				Delta:     def.XPBonus,
-				Reason:    fmt.Sprintf("achievement_%s", achievement.Type),
-			}
-
-			if err := s.progressRepo.SaveXPChange(ctx, entry); err != nil {
-				// Log but continue - XP history is not critical
-				continue
-			}
+		var bonus student.XP
+		if def, found := student.GetAchievementDefinition(achievement.Type); found && s.enableXPBonuses {
+			bonus = def.XPBonus
 		}
-	}
 
-	// Update student's XP
-	if totalBonus > 0 {
-		newXP := state.Student.CurrentXP + student.XP(totalBonus)
-		if _, err := state.Student.UpdateXP(newXP); err != nil {
-			return fmt.Errorf("failed to update student XP: %w", err)
+		_, currentXP, err := s.progressRepo.GrantAchievementWithBonus(
+			ctx, state.Input.StudentID, achievement, bonus, achievementXPReason(achievement.Type),
+		)
+		if err != nil {
+			state.FailedStep = StepGrantAchievements
+			state.Error = fmt.Errorf("failed to grant achievement %s: %w", achievement.Type, err)
+			return state.Error
 		}
 
-		if err := s.studentRepo.Update(ctx, state.Student); err != nil {
-			return fmt.Errorf("failed to persist student XP update: %w", err)
+		state.Student.CurrentXP = currentXP
+		if bonus > 0 {
+			totalBonus += int(bonus)
 		}
 	}
 
@@ -441,20 +600,28 @@ func (s *AchievementFlowSaga) stepAwardXPBonus(ctx context.Context, state *Achie
 }
 
 // stepSendNotifications sends achievement notifications to the student.
+// Notifications have a stable ID per achievement, so one that is already
+// in the notifications table is not sent again.
 func (s *AchievementFlowSaga) stepSendNotifications(ctx context.Context, state *AchievementFlowState) error {
-	if !s.enableNotifications || s.notificationSvc == nil {
+	if !s.enableNotifications || s.notificationSvc == nil || state.Input.Silent {
 		return nil
 	}
 
 	notificationsSent := 0
 
 	for _, achievement := range state.NewAchievements {
+		notificationID := achievementNotificationID(state.Student.ID, achievement.Type)
+		if s.notificationExists(ctx, notificationID) {
+			notificationsSent++
+			continue
+		}
+
 		// Build notification message
 		message := s.buildAchievementMessage(achievement)
 
 		achievPriority := notification.PriorityHigh
 		achievNotification, err := notification.NewNotification(notification.NewNotificationParams{
-			ID:             notification.NotificationID(s.idGenerator.GenerateID()),
+			ID:             notificationID,
 			Type:           notification.NotificationTypeAchievement,
 			RecipientID:    notification.RecipientID(state.Student.ID),
 			TelegramChatID: notification.TelegramChatID(state.Student.TelegramID),
@@ -500,6 +667,7 @@ func (s *AchievementFlowSaga) stepUpdateStatistics(ctx context.Context, state *A
 			}
 		}
 		dailyGrind = student.NewDailyGrind(state.Input.StudentID, state.Student.CurrentXP, rank)
+		dailyGrind.Date = student.LocalDate(time.Now(), state.Student.Preferences.Location())
 	}
 
 	// Record XP gained from achievements
@@ -517,7 +685,7 @@ func (s *AchievementFlowSaga) stepUpdateStatistics(ctx context.Context, state *A
 
 // stepPublishEvents publishes domain events for each achievement.
 func (s *AchievementFlowSaga) stepPublishEvents(ctx context.Context, state *AchievementFlowState) error {
-	if s.eventBus == nil {
+	if s.eventBus == nil || state.Input.Silent {
 		return nil
 	}
 
@@ -538,6 +706,39 @@ func (s *AchievementFlowSaga) stepPublishEvents(ctx context.Context, state *Achi
 // HELPER METHODS
 // ══════════════════════════════════════════════════════════════════════════════
 
+// fetchRank returns the student's rank in the given leaderboard, or 0 if unknown.
+func (s *AchievementFlowSaga) fetchRank(ctx context.Context, studentID string, cohort leaderboard.Cohort) int {
+	if s.leaderboardRepo == nil {
+		return 0
+	}
+	entry, err := s.leaderboardRepo.GetStudentRank(ctx, studentID, cohort)
+	if err != nil || entry == nil {
+		return 0
+	}
+	return int(entry.Rank)
+}
+
+// notificationExists reports whether a notification is already stored.
+// Without a repository nothing is known to exist.
+func (s *AchievementFlowSaga) notificationExists(ctx context.Context, id notification.NotificationID) bool {
+	if s.notificationRepo == nil {
+		return false
+	}
+	_, err := s.notificationRepo.GetByID(ctx, id)
+	return err == nil
+}
+
+// achievementXPReason returns the XP history reason of an achievement bonus.
+func achievementXPReason(achievementType student.AchievementType) string {
+	return fmt.Sprintf("achievement_%s", achievementType)
+}
+
+// achievementNotificationID returns the notification ID of an achievement.
+// Each achievement is granted once, so the ID is stable across retries.
+func achievementNotificationID(studentID string, achievementType student.AchievementType) notification.NotificationID {
+	return notification.NotificationID(fmt.Sprintf("achievement:%s:%s", studentID, achievementType))
+}
+
 // buildAchievementMessage creates a formatted message for an achievement notification.
 func (s *AchievementFlowSaga) buildAchievementMessage(achievement student.Achievement) string {
 	def, found := student.GetAchievementDefinition(achievement.Type)
@@ -579,6 +780,10 @@ func (s *AchievementFlowSaga) getAchievementSuffix(achievementType student.Achie
 		return "👑 Ты в элите! Помоги тем, кто ещё в пути."
 	case student.AchievementTop50:
 		return "⭐ Звезда восходит! До топ-10 рукой подать."
+	case student.AchievementCohortTop10:
+		return "🎖 Ты среди лучших своего потока! Поделись опытом с однокурсниками."
+	case student.AchievementCohortTop3:
+		return "🥇 Пьедестал потока! Тебя видят и на тебя равняются."
 	case student.AchievementHelper5:
 		return "🤝 Спасибо за помощь сообществу!"
 	case student.AchievementHelper20:
@@ -589,6 +794,12 @@ func (s *AchievementFlowSaga) getAchievementSuffix(achievementType student.Achie
 		return "🦉 Полуночное кодинг-сессия? Уважаем!"
 	case student.AchievementEarlyBird:
 		return "🐦 Ранняя пташка! Кто рано встаёт, тому XP даёт."
+	case student.AchievementConsistentHelper:
+		return "🫶 Месяц помощи без перерыва! Сообщество держится на таких, как ты."
+	case student.AchievementConsistentHelperLong:
+		return "💞 Три месяца помощи подряд! Спасибо, что ты рядом."
+	case student.AchievementEventParticipant:
+		return "🏁 Спасибо за участие в челлендже! До встречи в следующем."
 	default:
 		return ""
 	}
@@ -747,13 +958,15 @@ var (
 
 // AchievementFlowSagaBuilder provides a fluent API for building AchievementFlowSaga.
 type AchievementFlowSagaBuilder struct {
-	studentRepo     student.Repository
-	progressRepo    student.ProgressRepository
-	leaderboardRepo leaderboard.LeaderboardRepository
-	notificationSvc notification.NotificationService
-	eventBus        shared.EventPublisher
-	idGenerator     IDGenerator
-	config          AchievementFlowConfig
+	studentRepo      student.Repository
+	progressRepo     student.ProgressRepository
+	leaderboardRepo  leaderboard.LeaderboardRepository
+	notificationSvc  notification.NotificationService
+	notificationRepo notification.NotificationRepository
+	eventBus         shared.EventPublisher
+	idGenerator      IDGenerator
+	executions       ExecutionRepository
+	config           AchievementFlowConfig
 }
 
 // NewAchievementFlowSagaBuilder creates a new builder.
@@ -787,6 +1000,12 @@ func (b *AchievementFlowSagaBuilder) WithNotificationService(svc notification.No
 	return b
 }
 
+// WithNotificationRepo sets the notification repository used to skip duplicates.
+func (b *AchievementFlowSagaBuilder) WithNotificationRepo(repo notification.NotificationRepository) *AchievementFlowSagaBuilder {
+	b.notificationRepo = repo
+	return b
+}
+
 // WithEventBus sets the event bus.
 func (b *AchievementFlowSagaBuilder) WithEventBus(bus shared.EventPublisher) *AchievementFlowSagaBuilder {
 	b.eventBus = bus
@@ -799,6 +1018,12 @@ func (b *AchievementFlowSagaBuilder) WithIDGenerator(gen IDGenerator) *Achieveme
 	return b
 }
 
+// WithExecutionRepo sets the repository that records saga executions.
+func (b *AchievementFlowSagaBuilder) WithExecutionRepo(repo ExecutionRepository) *AchievementFlowSagaBuilder {
+	b.executions = repo
+	return b
+}
+
 // WithConfig sets the configuration.
 func (b *AchievementFlowSagaBuilder) WithConfig(config AchievementFlowConfig) *AchievementFlowSagaBuilder {
 	b.config = config
@@ -822,8 +1047,10 @@ func (b *AchievementFlowSagaBuilder) Build() (*AchievementFlowSaga, error) {
 		b.progressRepo,
 		b.leaderboardRepo,
 		b.notificationSvc,
+		b.notificationRepo,
 		b.eventBus,
 		b.idGenerator,
+		b.executions,
 		b.config,
 	), nil
 }
@@ -833,36 +1060,7 @@ func (b *AchievementFlowSagaBuilder) Build() (*AchievementFlowSaga, error) {
 // Adapts student domain events to shared.Event interface for the event bus.
 // ══════════════════════════════════════════════════════════════════════════════
 
-// achievementEventAdapter adapts a student.AchievementUnlockedEvent to shared.Event.
-type achievementEventAdapter struct {
-	event     student.AchievementUnlockedEvent
-	studentID string
-}
-
-// EventType implements shared.Event interface.
-func (a *achievementEventAdapter) EventType() shared.EventType {
-	return shared.EventLevelUp // Use level up event type as closest match for achievements
-}
-
-// OccurredAt implements shared.Event interface.
-func (a *achievementEventAdapter) OccurredAt() time.Time {
-	return a.event.OccurredAt()
-}
-
-// AggregateID implements shared.Event interface.
-func (a *achievementEventAdapter) AggregateID() string {
-	return a.studentID
-}
-
-// Payload implements shared.Event interface.
-func (a *achievementEventAdapter) Payload() map[string]interface{} {
-	return map[string]interface{}{
-		"achievement_type": string(a.event.Achievement.Type),
-		"xp_bonus":         int(a.event.XPBonus),
-	}
-}
-
-// wrapAchievementEvent wraps a student event to satisfy shared.Event interface.
+// wrapAchievementEvent converts a student event to the shared event published on the bus.
 func wrapAchievementEvent(studentID string, event student.AchievementUnlockedEvent) shared.Event {
-	return &achievementEventAdapter{event: event, studentID: studentID}
+	return shared.NewAchievementUnlockedEvent(studentID, string(event.Achievement.Type), int(event.XPBonus))
 }
@@ -0,0 +1,186 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// SAGA EXECUTIONS
+// Persistent record of a saga run, so that a run interrupted by a crash or a
+// restart can be found and resumed from the step after the last completed one.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// SagaType identifies the saga an execution belongs to.
+type SagaType string
+
+const (
+	SagaTypeAchievementFlow SagaType = "achievement_flow"
+	SagaTypeOnboarding      SagaType = "onboarding"
+)
+
+// ExecutionStatus is the lifecycle status of a saga execution.
+type ExecutionStatus string
+
+const (
+	// ExecutionStatusRunning - the saga has not finished yet (or was interrupted).
+	ExecutionStatusRunning ExecutionStatus = "running"
+
+	// ExecutionStatusCompleted - all steps are done.
+	ExecutionStatusCompleted ExecutionStatus = "completed"
+
+	// ExecutionStatusFailed - a critical step failed; the execution is not resumed.
+	ExecutionStatusFailed ExecutionStatus = "failed"
+)
+
+// Execution is the persisted state of one saga run.
+type Execution struct {
+	ID       string
+	SagaType SagaType
+
+	// Input - the saga input, serialized as JSON.
+	Input json.RawMessage
+
+	// State - step outputs needed to continue the run, serialized as JSON.
+	State json.RawMessage
+
+	// CurrentStep - the last completed step.
+	CurrentStep string
+
+	Status      ExecutionStatus
+	Error       string
+	StartedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// IsFinished returns true if the execution will not be resumed.
+func (e *Execution) IsFinished() bool {
+	return e.Status == ExecutionStatusCompleted || e.Status == ExecutionStatusFailed
+}
+
+// ExecutionRepository stores saga executions.
+type ExecutionRepository interface {
+	// Create stores a new execution.
+	Create(ctx context.Context, exec *Execution) error
+
+	// Update stores the current step, state, status and error of an execution.
+	Update(ctx context.Context, exec *Execution) error
+
+	// GetStalled returns running executions that have not progressed since
+	// updatedBefore, oldest first.
+	GetStalled(ctx context.Context, updatedBefore time.Time, limit int) ([]*Execution, error)
+
+	// ClaimStalled marks a stalled execution as picked up at the given time.
+	// It returns false if the execution progressed or was claimed by someone
+	// else after updatedBefore.
+	ClaimStalled(ctx context.Context, id string, updatedBefore, at time.Time) (bool, error)
+}
+
+// Execution errors.
+var (
+	// ErrExecutionNotFound - the execution does not exist.
+	ErrExecutionNotFound = errors.New("saga: execution not found")
+
+	// ErrExecutionNotResumable - the execution cannot be continued.
+	ErrExecutionNotResumable = errors.New("saga: execution cannot be resumed")
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// EXECUTION RECORDER
+// ══════════════════════════════════════════════════════════════════════════════
+
+// executionRecorder persists the progress of one saga run.
+// A recorder without a repository does nothing, so sagas work without persistence.
+//
+// Recording failures never fail the saga: a lost update only makes recovery
+// repeat steps, and every step after the first write is idempotent.
+type executionRecorder struct {
+	repo ExecutionRepository
+	exec *Execution
+	now  func() time.Time
+}
+
+// start creates the execution record.
+func (r *executionRecorder) start(ctx context.Context, id string, sagaType SagaType, input any, step string, state any) {
+	if r.repo == nil {
+		return
+	}
+
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return
+	}
+	stateJSON, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+
+	now := r.now().UTC()
+	exec := &Execution{
+		ID:          id,
+		SagaType:    sagaType,
+		Input:       inputJSON,
+		State:       stateJSON,
+		CurrentStep: step,
+		Status:      ExecutionStatusRunning,
+		StartedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := r.repo.Create(ctx, exec); err != nil {
+		return
+	}
+	r.exec = exec
+}
+
+// resume continues recording an existing execution.
+func (r *executionRecorder) resume(exec *Execution) {
+	r.exec = exec
+}
+
+// stepDone records a completed step together with the state after it.
+func (r *executionRecorder) stepDone(ctx context.Context, step string, state any) {
+	if r.repo == nil || r.exec == nil {
+		return
+	}
+
+	stateJSON, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+
+	r.exec.CurrentStep = step
+	r.exec.State = stateJSON
+	r.exec.UpdatedAt = r.now().UTC()
+	_ = r.repo.Update(ctx, r.exec)
+}
+
+// complete marks the execution as completed.
+func (r *executionRecorder) complete(ctx context.Context, step string) {
+	if r.repo == nil || r.exec == nil {
+		return
+	}
+
+	now := r.now().UTC()
+	r.exec.CurrentStep = step
+	r.exec.Status = ExecutionStatusCompleted
+	r.exec.UpdatedAt = now
+	r.exec.CompletedAt = &now
+	_ = r.repo.Update(ctx, r.exec)
+}
+
+// fail marks the execution as failed.
+func (r *executionRecorder) fail(ctx context.Context, cause error) {
+	if r.repo == nil || r.exec == nil {
+		return
+	}
+
+	now := r.now().UTC()
+	r.exec.Status = ExecutionStatusFailed
+	r.exec.Error = cause.Error()
+	r.exec.UpdatedAt = now
+	r.exec.CompletedAt = &now
+	_ = r.repo.Update(ctx, r.exec)
+}
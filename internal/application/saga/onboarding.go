@@ -5,8 +5,10 @@ package saga
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
@@ -24,6 +26,8 @@ import (
 //
 //	Initialize Progress → Send Welcome → Publish Event
 //
+// Runs are recorded as saga executions once the student is created, and can
+// be resumed from the step after the last completed one (see Resume).
 // ══════════════════════════════════════════════════════════════════════════════
 
 // OnboardingInput contains all data required to onboard a new student.
@@ -38,7 +42,8 @@ type OnboardingInput struct {
 	Email string
 
 	// Password - password for authentication (required).
-	Password string
+	// Never stored with the saga execution.
+	Password string `json:"-"`
 
 	// Cohort - student's cohort/batch (optional, can be auto-detected).
 	Cohort string
@@ -46,8 +51,8 @@ type OnboardingInput struct {
 
 // Validate checks if the input is valid for onboarding.
 func (i OnboardingInput) Validate() error {
-	if i.TelegramID <= 0 {
-		return errors.New("onboarding: telegram ID must be positive")
+	if !student.TelegramID(i.TelegramID).IsValid() {
+		return fmt.Errorf("onboarding: %w", student.ErrInvalidTelegramID)
 	}
 	if i.Email == "" {
 		return errors.New("onboarding: email is required")
@@ -87,6 +92,14 @@ const (
 	StepComplete           OnboardingStep = "complete"
 )
 
+// onboardingRecordedSteps are the steps after the student is created.
+// They run after the execution is recorded and are each safe to repeat.
+var onboardingRecordedSteps = []OnboardingStep{
+	StepInitializeProgress,
+	StepSendWelcome,
+	StepPublishEvent,
+}
+
 // OnboardingState tracks the current state of the onboarding saga.
 type OnboardingState struct {
 	CurrentStep OnboardingStep
@@ -97,6 +110,25 @@ type OnboardingState struct {
 	CompletedAt *time.Time
 	Error       error
 	FailedStep  OnboardingStep
+
+	// WelcomeNotificationID - ID of the scheduled welcome notification.
+	WelcomeNotificationID string
+}
+
+// onboardingProgress is the part of the state stored with the execution.
+type onboardingProgress struct {
+	StudentID             string `json:"student_id"`
+	WelcomeNotificationID string `json:"welcome_notification_id,omitempty"`
+}
+
+// progress returns the state to persist after a step.
+func (s *OnboardingState) progress() onboardingProgress {
+	var p onboardingProgress
+	if s.Student != nil {
+		p.StudentID = s.Student.ID
+	}
+	p.WelcomeNotificationID = s.WelcomeNotificationID
+	return p
 }
 
 // AlemStudentData represents data fetched from Alem API.
@@ -143,13 +175,16 @@ type IDGenerator interface {
 // It should be welcoming, informative, and set the tone for collaboration.
 type OnboardingSaga struct {
 	// Dependencies (injected via constructor)
-	studentRepo     student.Repository
-	progressRepo    student.ProgressRepository
-	leaderboardRepo leaderboard.LeaderboardRepository
-	notificationSvc notification.NotificationService
-	alemClient      AlemAPIClient
-	eventBus        shared.EventPublisher
-	idGenerator     IDGenerator
+	studentRepo      student.Repository
+	progressRepo     student.ProgressRepository
+	leaderboardRepo  leaderboard.LeaderboardRepository
+	notificationSvc  notification.NotificationService
+	notificationRepo notification.NotificationRepository
+	alemClient       AlemAPIClient
+	eventBus         shared.EventPublisher
+	idGenerator      IDGenerator
+	executions       ExecutionRepository
+	now              func() time.Time
 
 	// Configuration
 	defaultCohort  string
@@ -174,27 +209,34 @@ func DefaultOnboardingConfig() OnboardingSagaConfig {
 }
 
 // NewOnboardingSaga creates a new onboarding saga with all dependencies.
+// notificationRepo and executions are optional: without them the welcome
+// notification is not checked for duplicates and runs are not recorded.
 func NewOnboardingSaga(
 	studentRepo student.Repository,
 	progressRepo student.ProgressRepository,
 	leaderboardRepo leaderboard.LeaderboardRepository,
 	notificationSvc notification.NotificationService,
+	notificationRepo notification.NotificationRepository,
 	alemClient AlemAPIClient,
 	eventBus shared.EventPublisher,
 	idGenerator IDGenerator,
+	executions ExecutionRepository,
 	config OnboardingSagaConfig,
 ) *OnboardingSaga {
 	return &OnboardingSaga{
-		studentRepo:     studentRepo,
-		progressRepo:    progressRepo,
-		leaderboardRepo: leaderboardRepo,
-		notificationSvc: notificationSvc,
-		alemClient:      alemClient,
-		eventBus:        eventBus,
-		idGenerator:     idGenerator,
-		defaultCohort:   config.DefaultCohort,
-		welcomeTimeout:  config.WelcomeTimeout,
-		maxRetries:      config.MaxRetries,
+		studentRepo:      studentRepo,
+		progressRepo:     progressRepo,
+		leaderboardRepo:  leaderboardRepo,
+		notificationSvc:  notificationSvc,
+		notificationRepo: notificationRepo,
+		alemClient:       alemClient,
+		eventBus:         eventBus,
+		idGenerator:      idGenerator,
+		executions:       executions,
+		now:              time.Now,
+		defaultCohort:    config.DefaultCohort,
+		welcomeTimeout:   config.WelcomeTimeout,
+		maxRetries:       config.MaxRetries,
 	}
 }
 
@@ -230,46 +272,141 @@ func (s *OnboardingSaga) Execute(ctx context.Context, input OnboardingInput) (*O
 		return nil, s.wrapError(state, err)
 	}
 
-	// Step 5: Initialize progress tracking
-	state.CurrentStep = StepInitializeProgress
-	if err := s.stepInitializeProgress(ctx, state); err != nil {
-		// Try to rollback student creation
-		s.rollbackStudentCreation(ctx, state)
-		return nil, s.wrapError(state, err)
+	// From here on the student exists, so the run is recorded
+	rec := &executionRecorder{repo: s.executions, now: s.now}
+	rec.start(ctx, s.idGenerator.GenerateID(), SagaTypeOnboarding, input, string(StepCreateStudent), state.progress())
+
+	// Steps 5-7: Initialize Progress → Send Welcome → Publish Event
+	return s.runRecordedSteps(ctx, state, rec, StepInitializeProgress)
+}
+
+// Resume continues an interrupted execution from the step after the last
+// completed one. The student is reloaded by the ID stored with the execution.
+func (s *OnboardingSaga) Resume(ctx context.Context, exec *Execution) (*OnboardingResult, error) {
+	if exec.SagaType != SagaTypeOnboarding || exec.IsFinished() {
+		return nil, ErrExecutionNotResumable
 	}
 
-	// Step 6: Send welcome notification
-	state.CurrentStep = StepSendWelcome
-	welcomeNotificationID, err := s.stepSendWelcome(ctx, state)
-	if err != nil {
-		// Non-critical - log but continue
-		// We don't rollback for notification failures
-		welcomeNotificationID = ""
+	rec := &executionRecorder{repo: s.executions, now: s.now}
+	rec.resume(exec)
+
+	var input OnboardingInput
+	if err := json.Unmarshal(exec.Input, &input); err != nil {
+		err = fmt.Errorf("%w: decode input: %v", ErrExecutionNotResumable, err)
+		rec.fail(ctx, err)
+		return nil, err
+	}
+	var progress onboardingProgress
+	if err := json.Unmarshal(exec.State, &progress); err != nil {
+		err = fmt.Errorf("%w: decode state: %v", ErrExecutionNotResumable, err)
+		rec.fail(ctx, err)
+		return nil, err
+	}
+
+	state := &OnboardingState{
+		CurrentStep:           OnboardingStep(exec.CurrentStep),
+		Input:                 input,
+		StartedAt:             exec.StartedAt,
+		WelcomeNotificationID: progress.WelcomeNotificationID,
+	}
+
+	if progress.StudentID == "" {
+		err := fmt.Errorf("%w: no student was created", ErrExecutionNotResumable)
+		rec.fail(ctx, err)
+		return nil, err
 	}
+	st, err := s.studentRepo.GetByID(ctx, progress.StudentID)
+	if err != nil {
+		// The student was rolled back or deleted since
+		state.FailedStep = state.CurrentStep
+		rec.fail(ctx, err)
+		return nil, s.wrapError(state, fmt.Errorf("failed to load student: %w", err))
+	}
+	state.Student = st
+
+	return s.runRecordedSteps(ctx, state, rec, nextOnboardingStep(state.CurrentStep))
+}
+
+// runRecordedSteps runs the steps after student creation starting at from,
+// recording each completed step.
+func (s *OnboardingSaga) runRecordedSteps(
+	ctx context.Context,
+	state *OnboardingState,
+	rec *executionRecorder,
+	from OnboardingStep,
+) (*OnboardingResult, error) {
+	start := slices.Index(onboardingRecordedSteps, from)
+	if start < 0 {
+		start = len(onboardingRecordedSteps)
+	}
+
+	for _, step := range onboardingRecordedSteps[start:] {
+		// A cancelled run stays "running" and is picked up by recovery
+		if err := ctx.Err(); err != nil {
+			state.FailedStep = step
+			return nil, s.wrapError(state, err)
+		}
+
+		state.CurrentStep = step
+		switch step {
+		case StepInitializeProgress:
+			if err := s.stepInitializeProgress(ctx, state); err != nil {
+				// Try to rollback student creation
+				s.rollbackStudentCreation(ctx, state)
+				rec.fail(ctx, err)
+				return nil, s.wrapError(state, err)
+			}
+
+		case StepSendWelcome:
+			welcomeNotificationID, err := s.stepSendWelcome(ctx, state)
+			if err != nil {
+				// Non-critical - log but continue
+				// We don't rollback for notification failures
+				welcomeNotificationID = ""
+			}
+			state.WelcomeNotificationID = welcomeNotificationID
+
+		case StepPublishEvent:
+			if err := s.stepPublishEvent(ctx, state); err != nil {
+				// Non-critical - log but continue
+				// Events can be replayed later
+			}
+		}
 
-	// Step 7: Publish domain event
-	state.CurrentStep = StepPublishEvent
-	if err := s.stepPublishEvent(ctx, state); err != nil {
-		// Non-critical - log but continue
-		// Events can be replayed later
+		rec.stepDone(ctx, string(step), state.progress())
 	}
 
 	// Complete
 	state.CurrentStep = StepComplete
-	now := time.Now().UTC()
+	now := s.now().UTC()
 	state.CompletedAt = &now
+	rec.complete(ctx, string(StepComplete))
 
 	// Get initial rank
 	initialRank := s.getInitialRank(ctx, state.Student)
 
 	return &OnboardingResult{
 		Student:               state.Student,
-		WelcomeNotificationID: welcomeNotificationID,
+		WelcomeNotificationID: state.WelcomeNotificationID,
 		InitialRank:           initialRank,
 		OnboardedAt:           now,
 	}, nil
 }
 
+// nextOnboardingStep returns the step to resume after the given completed
+// step. Executions are recorded after student creation, so any earlier step
+// resumes from progress initialization.
+func nextOnboardingStep(completed OnboardingStep) OnboardingStep {
+	i := slices.Index(onboardingRecordedSteps, completed)
+	if i < 0 {
+		return StepInitializeProgress
+	}
+	if i+1 < len(onboardingRecordedSteps) {
+		return onboardingRecordedSteps[i+1]
+	}
+	return StepComplete
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // SAGA STEPS
 // ══════════════════════════════════════════════════════════════════════════════
@@ -449,7 +586,12 @@ func (s *OnboardingSaga) stepCreateStudent(ctx context.Context, state *Onboardin
 }
 
 // stepInitializeProgress sets up initial progress tracking for the student.
+// A streak that already has progress is kept, so the step is safe to repeat.
 func (s *OnboardingSaga) stepInitializeProgress(ctx context.Context, state *OnboardingState) error {
+	if existing, err := s.progressRepo.GetStreak(ctx, state.Student.ID); err == nil && existing != nil && existing.BestStreak > 0 {
+		return nil
+	}
+
 	// Create initial streak
 	streak := student.NewStreak(state.Student.ID)
 
@@ -469,6 +611,7 @@ func (s *OnboardingSaga) stepInitializeProgress(ctx context.Context, state *Onbo
 			state.Student.CurrentXP,
 			rank,
 		)
+		dailyGrind.Date = student.LocalDate(time.Now(), state.Student.Preferences.Location())
 
 		if err := s.progressRepo.SaveDailyGrind(ctx, dailyGrind); err != nil {
 			// Non-critical, log but continue
@@ -480,10 +623,19 @@ func (s *OnboardingSaga) stepInitializeProgress(ctx context.Context, state *Onbo
 }
 
 // stepSendWelcome sends a welcome notification to the new student.
+// The notification has a stable ID per student, so one that is already in
+// the notifications table is not sent again.
 func (s *OnboardingSaga) stepSendWelcome(ctx context.Context, state *OnboardingState) (string, error) {
+	notificationID := notification.NotificationID("welcome:" + state.Student.ID)
+	if s.notificationRepo != nil {
+		if _, err := s.notificationRepo.GetByID(ctx, notificationID); err == nil {
+			return notificationID.String(), nil
+		}
+	}
+
 	welcomePriority := notification.PriorityHigh
 	welcomeNotification, err := notification.NewNotification(notification.NewNotificationParams{
-		ID:             notification.NotificationID(s.idGenerator.GenerateID()),
+		ID:             notificationID,
 		Type:           notification.NotificationTypeWelcome,
 		RecipientID:    notification.RecipientID(state.Student.ID),
 		TelegramChatID: notification.TelegramChatID(state.Input.TelegramID),
@@ -654,14 +806,16 @@ var (
 
 // OnboardingSagaBuilder provides a fluent API for building OnboardingSaga.
 type OnboardingSagaBuilder struct {
-	studentRepo     student.Repository
-	progressRepo    student.ProgressRepository
-	leaderboardRepo leaderboard.LeaderboardRepository
-	notificationSvc notification.NotificationService
-	alemClient      AlemAPIClient
-	eventBus        shared.EventPublisher
-	idGenerator     IDGenerator
-	config          OnboardingSagaConfig
+	studentRepo      student.Repository
+	progressRepo     student.ProgressRepository
+	leaderboardRepo  leaderboard.LeaderboardRepository
+	notificationSvc  notification.NotificationService
+	notificationRepo notification.NotificationRepository
+	alemClient       AlemAPIClient
+	eventBus         shared.EventPublisher
+	idGenerator      IDGenerator
+	executions       ExecutionRepository
+	config           OnboardingSagaConfig
 }
 
 // NewOnboardingSagaBuilder creates a new builder.
@@ -695,6 +849,12 @@ func (b *OnboardingSagaBuilder) WithNotificationService(svc notification.Notific
 	return b
 }
 
+// WithNotificationRepo sets the notification repository used to skip duplicates.
+func (b *OnboardingSagaBuilder) WithNotificationRepo(repo notification.NotificationRepository) *OnboardingSagaBuilder {
+	b.notificationRepo = repo
+	return b
+}
+
 // WithAlemClient sets the Alem API client.
 func (b *OnboardingSagaBuilder) WithAlemClient(client AlemAPIClient) *OnboardingSagaBuilder {
 	b.alemClient = client
@@ -713,6 +873,12 @@ func (b *OnboardingSagaBuilder) WithIDGenerator(gen IDGenerator) *OnboardingSaga
 	return b
 }
 
+// WithExecutionRepo sets the repository that records saga executions.
+func (b *OnboardingSagaBuilder) WithExecutionRepo(repo ExecutionRepository) *OnboardingSagaBuilder {
+	b.executions = repo
+	return b
+}
+
 // WithConfig sets the configuration.
 func (b *OnboardingSagaBuilder) WithConfig(config OnboardingSagaConfig) *OnboardingSagaBuilder {
 	b.config = config
@@ -742,9 +908,11 @@ func (b *OnboardingSagaBuilder) Build() (*OnboardingSaga, error) {
 		b.progressRepo,
 		b.leaderboardRepo,
 		b.notificationSvc,
+		b.notificationRepo,
 		b.alemClient,
 		b.eventBus,
 		b.idGenerator,
+		b.executions,
 		b.config,
 	), nil
 }
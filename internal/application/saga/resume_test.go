@@ -0,0 +1,371 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// fakeExecutions хранит исполнения в памяти и "убивает" процесс,
+// отменяя контекст, когда записан шаг killAfter.
+type fakeExecutions struct {
+	executions map[string]Execution
+	killAfter  string
+	kill       context.CancelFunc
+}
+
+func newFakeExecutions() *fakeExecutions {
+	return &fakeExecutions{executions: map[string]Execution{}}
+}
+
+func (f *fakeExecutions) Create(ctx context.Context, exec *Execution) error {
+	return f.Update(ctx, exec)
+}
+
+func (f *fakeExecutions) Update(_ context.Context, exec *Execution) error {
+	f.executions[exec.ID] = *exec
+	if f.kill != nil && exec.CurrentStep == f.killAfter {
+		f.kill()
+	}
+	return nil
+}
+
+func (f *fakeExecutions) GetStalled(context.Context, time.Time, int) ([]*Execution, error) {
+	var result []*Execution
+	for _, e := range f.executions {
+		if e.Status == ExecutionStatusRunning {
+			e := e
+			result = append(result, &e)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeExecutions) ClaimStalled(context.Context, string, time.Time, time.Time) (bool, error) {
+	return true, nil
+}
+
+// only возвращает единственное исполнение.
+func (f *fakeExecutions) only(t *testing.T) *Execution {
+	t.Helper()
+	require.Len(t, f.executions, 1)
+	for _, e := range f.executions {
+		return &e
+	}
+	return nil
+}
+
+type fakeSagaStudents struct {
+	student.Repository
+	students map[string]*student.Student
+	updates  int
+}
+
+func (f *fakeSagaStudents) GetByID(_ context.Context, id string) (*student.Student, error) {
+	if s, ok := f.students[id]; ok {
+		return s, nil
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+func (f *fakeSagaStudents) Update(_ context.Context, s *student.Student) error {
+	f.students[s.ID] = s
+	f.updates++
+	return nil
+}
+
+func (f *fakeSagaStudents) Create(_ context.Context, s *student.Student) error {
+	f.students[s.ID] = s
+	return nil
+}
+
+func (f *fakeSagaStudents) ExistsByTelegramID(context.Context, student.TelegramID) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeSagaStudents) ExistsByEmail(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+// fakeSagaProgress хранит достижения и историю XP в памяти. Выдача
+// достижения из grantErrors "откатывается" целиком, как транзакция.
+type fakeSagaProgress struct {
+	student.ProgressRepository
+	achievements map[student.AchievementType]int
+	xpReasons    map[string]int
+	xp           map[string]student.XP
+	grantErrors  map[student.AchievementType]error
+	streakSaves  int
+}
+
+func newFakeSagaProgress() *fakeSagaProgress {
+	return &fakeSagaProgress{
+		achievements: map[student.AchievementType]int{},
+		xpReasons:    map[string]int{},
+		xp:           map[string]student.XP{},
+		grantErrors:  map[student.AchievementType]error{},
+	}
+}
+
+func (f *fakeSagaProgress) GetStreak(_ context.Context, studentID string) (*student.Streak, error) {
+	return student.NewStreak(studentID), nil
+}
+
+func (f *fakeSagaProgress) SaveStreak(context.Context, *student.Streak) error {
+	f.streakSaves++
+	return nil
+}
+
+func (f *fakeSagaProgress) GetAchievements(context.Context, string) ([]student.Achievement, error) {
+	return nil, nil
+}
+
+func (f *fakeSagaProgress) GrantAchievementWithBonus(
+	_ context.Context,
+	studentID string,
+	a student.Achievement,
+	bonus student.XP,
+	reason string,
+) (bool, student.XP, error) {
+	if err := f.grantErrors[a.Type]; err != nil {
+		return false, 0, err
+	}
+
+	f.achievements[a.Type] = 1
+	if bonus <= 0 || f.xpReasons[reason] > 0 {
+		return false, f.xp[studentID], nil
+	}
+	f.xpReasons[reason]++
+	f.xp[studentID] += bonus
+	return true, f.xp[studentID], nil
+}
+
+func (f *fakeSagaProgress) GetTodayDailyGrind(context.Context, string) (*student.DailyGrind, error) {
+	return nil, errors.New("not found")
+}
+
+func (f *fakeSagaProgress) SaveDailyGrind(context.Context, *student.DailyGrind) error {
+	return nil
+}
+
+// fakeNotifications одновременно сервис (планирует) и репозиторий (хранит).
+type fakeNotifications struct {
+	notification.NotificationService
+	notification.NotificationRepository
+	stored    map[notification.NotificationID]*notification.Notification
+	scheduled int
+}
+
+func newFakeNotifications() *fakeNotifications {
+	return &fakeNotifications{stored: map[notification.NotificationID]*notification.Notification{}}
+}
+
+func (f *fakeNotifications) ScheduleNotification(_ context.Context, n *notification.Notification) error {
+	f.stored[n.ID] = n
+	f.scheduled++
+	return nil
+}
+
+func (f *fakeNotifications) GetByID(_ context.Context, id notification.NotificationID) (*notification.Notification, error) {
+	if n, ok := f.stored[id]; ok {
+		return n, nil
+	}
+	return nil, notification.ErrNotificationNotFound
+}
+
+type fakeSagaEvents struct {
+	published []shared.Event
+}
+
+func (f *fakeSagaEvents) Publish(event shared.Event) error {
+	f.published = append(f.published, event)
+	return nil
+}
+
+type sequentialIDs struct{ n int }
+
+func (g *sequentialIDs) GenerateID() string {
+	g.n++
+	return fmt.Sprintf("id-%d", g.n)
+}
+
+type fakeAlem struct{ AlemAPIClient }
+
+func (fakeAlem) Authenticate(context.Context, string, string) (*AlemStudentData, error) {
+	return &AlemStudentData{Login: "dana", DisplayName: "Dana", XP: 1200}, nil
+}
+
+func TestAchievementFlowSaga_ResumesAfterCrashAfterGrant(t *testing.T) {
+	dana, err := student.NewStudent(student.NewStudentParams{
+		ID: "dana", TelegramID: 42, Email: "dana@alem.school", PasswordHash: "hash",
+		DisplayName: "Dana", Cohort: "2024-09", InitialXP: 1000,
+	})
+	require.NoError(t, err)
+
+	students := &fakeSagaStudents{students: map[string]*student.Student{"dana": dana}}
+	progress := newFakeSagaProgress()
+	progress.xp["dana"] = dana.CurrentXP
+	notifications := newFakeNotifications()
+	events := &fakeSagaEvents{}
+	executions := newFakeExecutions()
+
+	s := NewAchievementFlowSaga(students, progress, nil, notifications, notifications, events,
+		&sequentialIDs{}, executions, DefaultAchievementFlowConfig())
+
+	// Процесс "умирает" сразу после шага 4 (выдача достижений)
+	ctx, kill := context.WithCancel(context.Background())
+	executions.killAfter, executions.kill = string(StepGrantAchievements), kill
+
+	_, err = s.Execute(ctx, AchievementCheckInput{
+		StudentID:    "dana",
+		TriggerEvent: "task_completed",
+		Context:      AchievementContext{TasksCompleted: 1},
+		OnlyTypes:    []student.AchievementType{student.AchievementFirstTask},
+	})
+	require.ErrorIs(t, err, context.Canceled)
+
+	exec := executions.only(t)
+	assert.Equal(t, ExecutionStatusRunning, exec.Status)
+	assert.Equal(t, string(StepGrantAchievements), exec.CurrentStep)
+	assert.Equal(t, 1, progress.achievements[student.AchievementFirstTask])
+	assert.Equal(t, 1, progress.xpReasons["achievement_first_task"], "bonus is granted with the achievement")
+	assert.Equal(t, student.XP(1050), progress.xp["dana"])
+	assert.Zero(t, notifications.scheduled)
+
+	// Восстановление продолжает со следующего шага
+	stalled, err := executions.GetStalled(context.Background(), time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, stalled, 1)
+
+	result, err := s.Resume(context.Background(), stalled[0])
+	require.NoError(t, err)
+	assert.Equal(t, 50, result.TotalXPBonus)
+	assert.Equal(t, 1, result.NotificationsSent)
+
+	exec = executions.only(t)
+	assert.Equal(t, ExecutionStatusCompleted, exec.Status)
+	assert.Equal(t, 1, progress.achievements[student.AchievementFirstTask])
+	assert.Equal(t, 1, progress.xpReasons["achievement_first_task"])
+	assert.Zero(t, students.updates, "XP is incremented by the repository")
+	assert.Equal(t, student.XP(1050), dana.CurrentXP)
+	assert.Equal(t, 1, notifications.scheduled)
+	assert.Len(t, events.published, 1)
+
+	// Завершённое исполнение повторно не продолжается
+	_, err = s.Resume(context.Background(), exec)
+	assert.ErrorIs(t, err, ErrExecutionNotResumable)
+
+	// Даже повтор уже выполненных шагов ничего не дублирует
+	exec.Status = ExecutionStatusRunning
+	exec.CurrentStep = string(StepGrantAchievements)
+	_, err = s.Resume(context.Background(), exec)
+	require.NoError(t, err)
+	assert.Equal(t, 1, progress.xpReasons["achievement_first_task"])
+	assert.Equal(t, student.XP(1050), progress.xp["dana"])
+	assert.Equal(t, 1, notifications.scheduled)
+}
+
+func TestAchievementFlowSaga_FailedGrantIsRetryable(t *testing.T) {
+	dana, err := student.NewStudent(student.NewStudentParams{
+		ID: "dana", TelegramID: 42, Email: "dana@alem.school", PasswordHash: "hash",
+		DisplayName: "Dana", Cohort: "2024-09", InitialXP: 1000,
+	})
+	require.NoError(t, err)
+
+	students := &fakeSagaStudents{students: map[string]*student.Student{"dana": dana}}
+	progress := newFakeSagaProgress()
+	progress.xp["dana"] = dana.CurrentXP
+	notifications := newFakeNotifications()
+	events := &fakeSagaEvents{}
+	executions := newFakeExecutions()
+
+	s := NewAchievementFlowSaga(students, progress, nil, notifications, notifications, events,
+		&sequentialIDs{}, executions, DefaultAchievementFlowConfig())
+
+	input := AchievementCheckInput{
+		StudentID:    "dana",
+		TriggerEvent: "task_completed",
+		Context:      AchievementContext{TasksCompleted: 1, GoalWeeksInARow: 4},
+		OnlyTypes:    []student.AchievementType{student.AchievementFirstTask, student.AchievementGoalGetter},
+	}
+	firstBonus, _ := student.GetAchievementDefinition(student.AchievementFirstTask)
+	secondBonus, _ := student.GetAchievementDefinition(student.AchievementGoalGetter)
+
+	// Первое достижение выдаётся, на втором база недоступна
+	progress.grantErrors[student.AchievementGoalGetter] = errors.New("connection reset")
+
+	_, err = s.Execute(context.Background(), input)
+	require.Error(t, err)
+
+	assert.Equal(t, ExecutionStatusFailed, executions.only(t).Status)
+	assert.Equal(t, 1, progress.achievements[student.AchievementFirstTask])
+	assert.Zero(t, progress.achievements[student.AchievementGoalGetter])
+	assert.Zero(t, progress.xpReasons["achievement_goal_getter"])
+	assert.Equal(t, 1000+firstBonus.XPBonus, progress.xp["dana"])
+	assert.Zero(t, notifications.scheduled)
+
+	// Повтор выдаёт второе достижение, не начисляя первый бонус дважды
+	delete(progress.grantErrors, student.AchievementGoalGetter)
+
+	result, err := s.Execute(context.Background(), input)
+	require.NoError(t, err)
+	assert.Equal(t, int(firstBonus.XPBonus+secondBonus.XPBonus), result.TotalXPBonus)
+	assert.Equal(t, 1, progress.achievements[student.AchievementGoalGetter])
+	assert.Equal(t, 1, progress.xpReasons["achievement_first_task"])
+	assert.Equal(t, 1, progress.xpReasons["achievement_goal_getter"])
+	assert.Equal(t, 1000+firstBonus.XPBonus+secondBonus.XPBonus, progress.xp["dana"])
+	assert.Equal(t, progress.xp["dana"], dana.CurrentXP)
+}
+
+func TestOnboardingSaga_ResumesAfterCrashAfterCreate(t *testing.T) {
+	students := &fakeSagaStudents{students: map[string]*student.Student{}}
+	progress := newFakeSagaProgress()
+	notifications := newFakeNotifications()
+	events := &fakeSagaEvents{}
+	executions := newFakeExecutions()
+
+	s := NewOnboardingSaga(students, progress, nil, notifications, notifications, fakeAlem{}, events,
+		&sequentialIDs{}, executions, DefaultOnboardingConfig())
+
+	// Процесс "умирает" сразу после шага 4 (создание студента)
+	ctx, kill := context.WithCancel(context.Background())
+	executions.killAfter, executions.kill = string(StepCreateStudent), kill
+
+	_, err := s.Execute(ctx, OnboardingInput{
+		TelegramID: 42,
+		Email:      "dana@alem.school",
+		Password:   "secret",
+		Cohort:     "2024-09",
+	})
+	require.ErrorIs(t, err, context.Canceled)
+
+	exec := executions.only(t)
+	assert.Equal(t, ExecutionStatusRunning, exec.Status)
+	assert.NotContains(t, string(exec.Input), "secret")
+	require.Len(t, students.students, 1)
+	assert.Zero(t, progress.streakSaves)
+	assert.Zero(t, notifications.scheduled)
+
+	result, err := s.Resume(context.Background(), exec)
+	require.NoError(t, err)
+	assert.Equal(t, "Dana", result.Student.DisplayName)
+
+	assert.Equal(t, ExecutionStatusCompleted, executions.only(t).Status)
+	assert.Equal(t, 1, progress.streakSaves)
+	assert.Equal(t, 1, notifications.scheduled)
+	assert.Len(t, events.published, 1)
+	assert.Equal(t, "welcome:"+result.Student.ID, result.WelcomeNotificationID)
+
+	_, err = s.Resume(context.Background(), executions.only(t))
+	assert.ErrorIs(t, err, ErrExecutionNotResumable)
+	assert.Equal(t, 1, notifications.scheduled)
+}
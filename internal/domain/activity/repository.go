@@ -31,6 +31,14 @@ type Repository interface {
 	// SaveTaskCompletion persists a task completion record.
 	SaveTaskCompletion(ctx context.Context, completion *TaskCompletion) error
 
+	// ImportTaskCompletions bulk-inserts historical completions, skipping the
+	// ones already stored. Returns the number of inserted records.
+	ImportTaskCompletions(ctx context.Context, completions []*TaskCompletion) (int, error)
+
+	// CountNewTaskCompletions returns how many of the completions
+	// ImportTaskCompletions would insert.
+	CountNewTaskCompletions(ctx context.Context, completions []*TaskCompletion) (int, error)
+
 	// GetTaskCompletion returns a specific task completion by ID.
 	GetTaskCompletion(ctx context.Context, id string) (*TaskCompletion, error)
 
@@ -138,6 +146,13 @@ type TaskIndex interface {
 	GetRecentSolvers(ctx context.Context, within time.Duration, limit int) ([]StudentID, error)
 }
 
+// TaskCatalog resolves task IDs into human-readable task names.
+type TaskCatalog interface {
+	// TaskNames returns the known names of the given tasks.
+	// Tasks without a known name are missing from the map.
+	TaskNames(ctx context.Context, taskIDs []TaskID) (map[TaskID]string, error)
+}
+
 // HelperFinder combines multiple sources to find the best helpers for a task.
 // This encapsulates the core "find help" business logic.
 type HelperFinder interface {
@@ -0,0 +1,64 @@
+package analytics
+
+import (
+	"context"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// COHORT HEALTH
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Агрегаты "здоровья" потока для еженедельного отчёта кураторам.
+// В отличие от статистики команд, здесь есть имена: отчёт видят только
+// кураторы потока, и им нужно знать, кому написать.
+
+// CohortActivity - сводные показатели потока за неделю.
+type CohortActivity struct {
+	// TotalStudents - активные (по статусу) студенты потока.
+	TotalStudents int
+
+	// ActiveStudents - студенты, у которых за неделю была активность.
+	ActiveStudents int
+
+	// NewStudents - зарегистрировались за неделю.
+	NewStudents int
+
+	// XPGained, PreviousXPGained - XP потока за неделю и за предыдущую.
+	XPGained         int
+	PreviousXPGained int
+
+	// MedianStreak - медиана текущих серий активных студентов (дней).
+	MedianStreak float64
+}
+
+// InactiveStudent - студент без активности дольше порога.
+type InactiveStudent struct {
+	DisplayName string
+	LastSeenAt  time.Time
+}
+
+// RankClimber - студент, поднявшийся в рейтинге потока за неделю.
+type RankClimber struct {
+	DisplayName string
+	OldRank     int
+	NewRank     int
+}
+
+// CohortHealthRepository - агрегатные запросы для отчёта о потоке.
+// Все запросы считаются в базе: студенты потока в память не загружаются.
+type CohortHealthRepository interface {
+	// GetActivity возвращает показатели за [from, to) и XP за [prevFrom, from).
+	GetActivity(ctx context.Context, cohort string, prevFrom, from, to time.Time) (CohortActivity, error)
+
+	// ListInactive возвращает до limit студентов, не заходивших с since
+	// (давние первыми), и их общее число.
+	ListInactive(ctx context.Context, cohort string, since time.Time, limit int) ([]InactiveStudent, int, error)
+
+	// CountStaleHelpRequests считает незакрытые запросы помощи, созданные до olderThan.
+	CountStaleHelpRequests(ctx context.Context, cohort string, olderThan time.Time) (int, error)
+
+	// TopClimbers возвращает до limit студентов с наибольшим подъёмом
+	// в рейтинге между последними снимками до from и до to.
+	TopClimbers(ctx context.Context, cohort string, from, to time.Time, limit int) ([]RankClimber, error)
+}
@@ -0,0 +1,124 @@
+// Package analytics содержит агрегированную статистику использования бота.
+//
+// Статистика намеренно обезличена: счётчики ведутся только в разрезе
+// (день, команда, поток), без Telegram ID и ID студентов.
+package analytics
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// COMMAND USAGE
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// CohortUnknown - поток неизвестен: пользователь не зарегистрирован
+	// или команда публичная и не требует загрузки студента.
+	CohortUnknown = "none"
+
+	// CommandUnknown - незарегистрированная команда (произвольный текст после /).
+	CommandUnknown = "unknown"
+
+	// CallbackPrefix отличает нажатия кнопок от команд ("cb:refresh").
+	CallbackPrefix = "cb:"
+)
+
+// CommandUsage - число вызовов команды студентами одного потока за день.
+type CommandUsage struct {
+	// Day - день (начало дня в UTC).
+	Day time.Time
+
+	// Command - имя команды без "/" или "cb:<префикс>" для кнопок.
+	Command string
+
+	// Cohort - поток или CohortUnknown.
+	Cohort string
+
+	// Count - число вызовов.
+	Count int64
+}
+
+// DayOf возвращает начало дня в UTC.
+func DayOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// UsagePoint - значение временного ряда за один день.
+type UsagePoint struct {
+	Day   time.Time
+	Count int64
+}
+
+// CommandSeries - временной ряд вызовов одной команды по всем потокам.
+type CommandSeries struct {
+	Command string
+	Total   int64
+	Points  []UsagePoint
+}
+
+// BuildSeries сворачивает счётчики по потокам в ряды по командам.
+// Ряды отсортированы по убыванию общего числа вызовов, точки - по дням.
+func BuildSeries(usage []CommandUsage) []CommandSeries {
+	byCommand := make(map[string]map[time.Time]int64)
+	for _, u := range usage {
+		days, ok := byCommand[u.Command]
+		if !ok {
+			days = make(map[time.Time]int64)
+			byCommand[u.Command] = days
+		}
+		days[DayOf(u.Day)] += u.Count
+	}
+
+	series := make([]CommandSeries, 0, len(byCommand))
+	for command, days := range byCommand {
+		s := CommandSeries{Command: command, Points: make([]UsagePoint, 0, len(days))}
+		for day, count := range days {
+			s.Points = append(s.Points, UsagePoint{Day: day, Count: count})
+			s.Total += count
+		}
+		sort.Slice(s.Points, func(i, j int) bool {
+			return s.Points[i].Day.Before(s.Points[j].Day)
+		})
+		series = append(series, s)
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		if series[i].Total != series[j].Total {
+			return series[i].Total > series[j].Total
+		}
+		return series[i].Command < series[j].Command
+	})
+
+	return series
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// REPOSITORY INTERFACES
+// ══════════════════════════════════════════════════════════════════════════════
+
+// UsageCounter - быстрые дневные счётчики (Redis).
+type UsageCounter interface {
+	// Increment увеличивает счётчик команды за день на 1.
+	Increment(ctx context.Context, day time.Time, command, cohort string) error
+
+	// GetDay возвращает все счётчики за день.
+	GetDay(ctx context.Context, day time.Time) ([]CommandUsage, error)
+}
+
+// UsageRepository - долговременное хранилище дневной статистики (PostgreSQL).
+type UsageRepository interface {
+	// SaveDay записывает счётчики за день, перезаписывая уже сохранённые.
+	// Повторная запись тех же данных ничего не меняет.
+	SaveDay(ctx context.Context, day time.Time, usage []CommandUsage) error
+
+	// GetRange возвращает счётчики за дни from..to включительно.
+	GetRange(ctx context.Context, from, to time.Time) ([]CommandUsage, error)
+
+	// DeleteBefore удаляет статистику старше указанного дня.
+	// Возвращает число удалённых строк.
+	DeleteBefore(ctx context.Context, day time.Time) (int64, error)
+}
@@ -0,0 +1,274 @@
+// Package backfill содержит загрузку полной истории выполненных задач из
+// Alem в activity.Repository.
+//
+// Загрузка идёт запусками (Run): воркер обходит студентов по ID, забирает
+// историю постранично и после каждой страницы сохраняет курсор студента
+// (Cursor). Прерванный запуск продолжается с первой несохранённой страницы,
+// уже загруженные страницы повторно не запрашиваются. Запуск можно
+// приостановить, продолжить или отменить; одновременно идёт не больше
+// одного запуска.
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CONSTANTS & ERRORS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// DefaultRateShare - доля лимита запросов к Alem, которую забирает загрузка,
+// чтобы синхронизация и /sync_me не простаивали.
+const DefaultRateShare = 0.25
+
+// Ошибки загрузки.
+var (
+	// ErrRunNotFound - запуска нет.
+	ErrRunNotFound = shared.NewDomainError("backfill", "Find", shared.ErrNotFound, "backfill run not found")
+
+	// ErrRunActive - другой запуск ещё идёт или приостановлен.
+	ErrRunActive = shared.NewDomainError("backfill", "Start", shared.ErrAlreadyExists, "another backfill run is in progress")
+
+	// ErrInvalidTransition - запуск не может перейти в это состояние.
+	ErrInvalidTransition = shared.NewDomainError("backfill", "Transition", shared.ErrInvalidState, "backfill run can't change to this status")
+)
+
+// Status - состояние запуска.
+type Status string
+
+const (
+	// StatusRunning - воркер загружает историю.
+	StatusRunning Status = "running"
+
+	// StatusPaused - загрузка приостановлена, курсоры сохранены.
+	StatusPaused Status = "paused"
+
+	// StatusCancelled - загрузка отменена; следующий запуск начнёт заново.
+	StatusCancelled Status = "cancelled"
+
+	// StatusCompleted - история загружена для всех студентов.
+	StatusCompleted Status = "completed"
+)
+
+// IsFinal возвращает true для отменённых и завершённых запусков.
+func (s Status) IsFinal() bool {
+	return s == StatusCancelled || s == StatusCompleted
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RUN
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Run - запуск загрузки истории.
+type Run struct {
+	// ID - идентификатор запуска (UUID).
+	ID string `json:"id"`
+
+	// Status - состояние запуска.
+	Status Status `json:"status"`
+
+	// DryRun - только посчитать, сколько выполнений было бы добавлено.
+	DryRun bool `json:"dry_run"`
+
+	// RateShare - доля лимита запросов к Alem (0, 1].
+	RateShare float64 `json:"rate_share"`
+
+	// StudentsTotal - сколько студентов было на старте.
+	StudentsTotal int `json:"students_total"`
+
+	// StudentsDone - сколько студентов загружено полностью.
+	StudentsDone int `json:"students_done"`
+
+	// LastStudentID - последний полностью загруженный студент; обход
+	// продолжается со следующего по ID.
+	LastStudentID string `json:"last_student_id,omitempty"`
+
+	// Pages - сколько страниц истории получено.
+	Pages int `json:"pages"`
+
+	// Inserted - сколько выполнений добавлено (в DryRun - было бы добавлено).
+	Inserted int `json:"inserted"`
+
+	// ActiveTime - сколько запуск работал, без пауз; по нему считается ETA.
+	ActiveTime time.Duration `json:"-"`
+
+	// CreatedAt - когда запуск создан.
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt - когда запуск последний раз продвинулся или сменил состояние.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// FinishedAt - когда запуск отменён или завершён.
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// NewRun создаёт идущий запуск.
+func NewRun(id string, dryRun bool, rateShare float64, studentsTotal int, now time.Time) (*Run, error) {
+	if rateShare <= 0 || rateShare > 1 {
+		return nil, shared.NewDomainError("backfill", "Start", shared.ErrInvalidInput,
+			fmt.Sprintf("rate share must be in (0, 1], got %g", rateShare))
+	}
+
+	now = now.UTC()
+	return &Run{
+		ID:            id,
+		Status:        StatusRunning,
+		DryRun:        dryRun,
+		RateShare:     rateShare,
+		StudentsTotal: studentsTotal,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// Pause приостанавливает идущий запуск.
+func (r *Run) Pause(now time.Time) error {
+	return r.transition(StatusPaused, now, StatusRunning)
+}
+
+// Resume продолжает приостановленный запуск.
+func (r *Run) Resume(now time.Time) error {
+	return r.transition(StatusRunning, now, StatusPaused)
+}
+
+// Cancel отменяет идущий или приостановленный запуск.
+func (r *Run) Cancel(now time.Time) error {
+	return r.transition(StatusCancelled, now, StatusRunning, StatusPaused)
+}
+
+// Complete завершает идущий запуск.
+func (r *Run) Complete(now time.Time) error {
+	return r.transition(StatusCompleted, now, StatusRunning)
+}
+
+func (r *Run) transition(to Status, now time.Time, from ...Status) error {
+	allowed := false
+	for _, s := range from {
+		allowed = allowed || r.Status == s
+	}
+	if !allowed {
+		return ErrInvalidTransition
+	}
+
+	now = now.UTC()
+	r.Status = to
+	r.UpdatedAt = now
+	if to.IsFinal() {
+		r.FinishedAt = &now
+	}
+	return nil
+}
+
+// Progress - отчёт о ходе запуска.
+type Progress struct {
+	// StudentsDone, StudentsTotal - загружено студентов из общего числа.
+	StudentsDone  int `json:"students_done"`
+	StudentsTotal int `json:"students_total"`
+
+	// Percent - доля загруженных студентов, 0..100.
+	Percent float64 `json:"percent"`
+
+	// ETASeconds - оценка оставшегося времени работы (0 - неизвестно
+	// или запуск закончен). Паузы в оценку не входят.
+	ETASeconds int64 `json:"eta_seconds,omitempty"`
+}
+
+// Progress возвращает отчёт о ходе запуска. ETA считается по среднему
+// времени на студента за время работы запуска.
+func (r *Run) Progress() Progress {
+	p := Progress{StudentsDone: r.StudentsDone, StudentsTotal: r.StudentsTotal}
+	if r.StudentsTotal > 0 {
+		p.Percent = min(100, float64(r.StudentsDone)*100/float64(r.StudentsTotal))
+	}
+	if r.Status.IsFinal() || r.StudentsDone == 0 || r.StudentsDone >= r.StudentsTotal {
+		return p
+	}
+
+	perStudent := r.ActiveTime / time.Duration(r.StudentsDone)
+	p.ETASeconds = int64((perStudent * time.Duration(r.StudentsTotal-r.StudentsDone)).Seconds())
+	return p
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CURSOR
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Cursor - докуда загружена история студента в запуске.
+type Cursor struct {
+	RunID     string
+	StudentID string
+
+	// Page - последняя сохранённая страница (0 - ещё ни одной).
+	Page int
+
+	// LastCompletedAt - самое позднее выполнение на сохранённых страницах.
+	LastCompletedAt *time.Time
+
+	// Done - история студента загружена полностью.
+	Done bool
+
+	// Inserted - сколько выполнений студента добавлено.
+	Inserted int
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// REPOSITORY
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Repository хранит запуски, курсоры студентов и порядок обхода.
+type Repository interface {
+	// CreateRun сохраняет новый запуск или возвращает ErrRunActive.
+	CreateRun(ctx context.Context, run *Run) error
+
+	// GetRun возвращает запуск или ErrRunNotFound.
+	GetRun(ctx context.Context, id string) (*Run, error)
+
+	// GetActiveRun возвращает идущий или приостановленный запуск
+	// или ErrRunNotFound.
+	GetActiveRun(ctx context.Context) (*Run, error)
+
+	// ListRuns возвращает последние запуски, новые первыми.
+	ListRuns(ctx context.Context, limit int) ([]*Run, error)
+
+	// UpdateStatus сохраняет состояние запуска, если в базе оно всё ещё
+	// from; иначе возвращает ErrInvalidTransition.
+	UpdateStatus(ctx context.Context, run *Run, from Status) error
+
+	// SaveProgress сохраняет счётчики запуска, не трогая состояние.
+	SaveProgress(ctx context.Context, run *Run) error
+
+	// GetCursor возвращает курсор студента (пустой, если загрузка
+	// студента не начиналась).
+	GetCursor(ctx context.Context, runID, studentID string) (*Cursor, error)
+
+	// SaveCursor сохраняет курсор студента.
+	SaveCursor(ctx context.Context, cursor *Cursor) error
+
+	// CountStudents возвращает число студентов для загрузки.
+	CountStudents(ctx context.Context) (int, error)
+
+	// NextStudents возвращает ID студентов после afterID по возрастанию.
+	NextStudents(ctx context.Context, afterID string, limit int) ([]string, error)
+}
+
+// Start создаёт запуск по всем студентам. Возвращает ErrRunActive, если
+// предыдущий запуск не отменён и не завершён.
+func Start(ctx context.Context, repo Repository, id string, dryRun bool, rateShare float64, now time.Time) (*Run, error) {
+	total, err := repo.CountStudents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count students: %w", err)
+	}
+
+	run, err := NewRun(id, dryRun, rateShare, total, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.CreateRun(ctx, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
@@ -0,0 +1,48 @@
+package backfill
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_Transitions(t *testing.T) {
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	run, err := NewRun("run-1", false, DefaultRateShare, 10, now)
+	require.NoError(t, err)
+
+	require.NoError(t, run.Pause(now))
+	assert.ErrorIs(t, run.Pause(now), ErrInvalidTransition)
+	assert.ErrorIs(t, run.Complete(now), ErrInvalidTransition)
+
+	require.NoError(t, run.Resume(now))
+	require.NoError(t, run.Cancel(now.Add(time.Minute)))
+	assert.Equal(t, StatusCancelled, run.Status)
+	require.NotNil(t, run.FinishedAt)
+
+	// Отменённый запуск не продолжается
+	assert.ErrorIs(t, run.Resume(now), ErrInvalidTransition)
+
+	_, err = NewRun("run-2", false, 1.5, 10, now)
+	assert.Error(t, err)
+}
+
+func TestRun_ProgressETA(t *testing.T) {
+	run, err := NewRun("run-1", true, DefaultRateShare, 10, time.Now())
+	require.NoError(t, err)
+
+	// Пока ни один студент не загружен, ETA неизвестна
+	assert.Zero(t, run.Progress().ETASeconds)
+
+	run.StudentsDone = 4
+	run.ActiveTime = 8 * time.Minute
+	p := run.Progress()
+	assert.Equal(t, 40.0, p.Percent)
+	assert.Equal(t, int64((12 * time.Minute).Seconds()), p.ETASeconds)
+
+	// Паузы не сдвигают оценку: считается только время работы
+	require.NoError(t, run.Pause(time.Now().Add(time.Hour)))
+	assert.Equal(t, p.ETASeconds, run.Progress().ETASeconds)
+}
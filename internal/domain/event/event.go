@@ -0,0 +1,320 @@
+// Package event содержит челленджи сообщества - ограниченные по времени
+// соревнования ("кто решит больше чекпоинтов за неделю") со своим
+// лидербордом, не зависящим от потоков.
+//
+// Очки начисляются синхронизацией только за прирост, попавший в окно
+// [StartsAt, EndsAt). События могут пересекаться: один и тот же прирост
+// засчитывается во все идущие события. После EndsAt результаты замораживаются
+// воркером, победители объявляются, участники получают достижение.
+package event
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CONSTANTS & ERRORS
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// MaxNameLength - максимальная длина названия события.
+	MaxNameLength = 100
+
+	// MaxDuration - максимальная длительность события.
+	MaxDuration = 31 * 24 * time.Hour
+
+	// WinnersCount - сколько победителей объявляется.
+	WinnersCount = 3
+)
+
+// Ошибки событий.
+var (
+	// ErrEventNotFound - события нет.
+	ErrEventNotFound = shared.NewDomainError("event", "Find", shared.ErrNotFound, "event not found")
+
+	// ErrEventFinalized - результаты события уже заморожены.
+	ErrEventFinalized = shared.NewDomainError("event", "Close", shared.ErrInvalidState, "event is already finalized")
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// METRIC & STATUS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Metric - за что начисляются очки.
+type Metric string
+
+const (
+	// MetricXPGained - прирост XP.
+	MetricXPGained Metric = "xp_gained"
+
+	// MetricTasksCompleted - количество выполненных задач.
+	MetricTasksCompleted Metric = "tasks_completed"
+)
+
+// Metrics возвращает все метрики.
+func Metrics() []Metric {
+	return []Metric{MetricXPGained, MetricTasksCompleted}
+}
+
+// Unit возвращает единицу очков для вывода.
+func (m Metric) Unit() string {
+	if m == MetricTasksCompleted {
+		return "задач"
+	}
+	return "XP"
+}
+
+// Status - состояние события.
+type Status string
+
+const (
+	// StatusOpen - событие запланировано или идёт; очки начисляются в окне.
+	StatusOpen Status = "open"
+
+	// StatusFinalized - результаты заморожены в event_results.
+	StatusFinalized Status = "finalized"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// EVENT
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Event - челлендж сообщества.
+type Event struct {
+	// ID - идентификатор события (UUID).
+	ID string `json:"id"`
+
+	// Name - название, которое видят студенты.
+	Name string `json:"name"`
+
+	// StartsAt - начало окна (включительно).
+	StartsAt time.Time `json:"starts_at"`
+
+	// EndsAt - конец окна (не включительно).
+	EndsAt time.Time `json:"ends_at"`
+
+	// Metric - за что начисляются очки.
+	Metric Metric `json:"metric"`
+
+	// Status - состояние события.
+	Status Status `json:"status"`
+
+	// CreatedAt - когда событие создано.
+	CreatedAt time.Time `json:"created_at"`
+
+	// FinalizedAt - когда результаты заморожены.
+	FinalizedAt *time.Time `json:"finalized_at,omitempty"`
+}
+
+// NewEvent создаёт событие. Окно должно заканчиваться в будущем и быть
+// не длиннее MaxDuration.
+func NewEvent(id, name string, startsAt, endsAt time.Time, metric Metric, now time.Time) (*Event, error) {
+	e := &Event{
+		ID:        id,
+		Name:      strings.TrimSpace(name),
+		StartsAt:  startsAt.UTC(),
+		EndsAt:    endsAt.UTC(),
+		Metric:    metric,
+		Status:    StatusOpen,
+		CreatedAt: now.UTC(),
+	}
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	if !e.EndsAt.After(now) {
+		return nil, invalid("ends_at must be in the future")
+	}
+	return e, nil
+}
+
+// Validate проверяет название, окно и метрику.
+func (e *Event) Validate() error {
+	if e.Name == "" {
+		return invalid("name is required")
+	}
+	if utf8.RuneCountInString(e.Name) > MaxNameLength {
+		return invalid(fmt.Sprintf("name must be at most %d characters", MaxNameLength))
+	}
+	if e.StartsAt.IsZero() || e.EndsAt.IsZero() {
+		return invalid("starts_at and ends_at are required")
+	}
+	if e.EndsAt.Before(e.StartsAt) {
+		return invalid("ends_at must not be before starts_at")
+	}
+	if e.EndsAt.Sub(e.StartsAt) > MaxDuration {
+		return invalid(fmt.Sprintf("event must not be longer than %d days", int(MaxDuration.Hours()/24)))
+	}
+	if !slices.Contains(Metrics(), e.Metric) {
+		return invalid(fmt.Sprintf("unknown metric %q", e.Metric))
+	}
+	return nil
+}
+
+// IsLive возвращает true, если в момент t начисляются очки.
+func (e *Event) IsLive(t time.Time) bool {
+	return e.Status == StatusOpen && !t.Before(e.StartsAt) && t.Before(e.EndsAt)
+}
+
+// HasEnded возвращает true, если окно закрыто и результаты можно замораживать.
+func (e *Event) HasEnded(t time.Time) bool {
+	return !t.Before(e.EndsAt)
+}
+
+// Close досрочно завершает событие: окно обрезается до now. Событие, которое
+// ещё не началось, закрывается с пустым окном.
+func (e *Event) Close(now time.Time) error {
+	if e.Status == StatusFinalized {
+		return ErrEventFinalized
+	}
+	now = now.UTC()
+	if now.Before(e.StartsAt) {
+		now = e.StartsAt
+	}
+	if now.Before(e.EndsAt) {
+		e.EndsAt = now
+	}
+	return nil
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// SCORING
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Gain - прирост студента с моментом, к которому он отнесён.
+type Gain struct {
+	// At - когда получен прирост (время выполнения задачи или синхронизации).
+	At time.Time
+
+	// XP - прирост XP.
+	XP int
+
+	// Tasks - выполненные задачи.
+	Tasks int
+}
+
+// Score возвращает очки за приросты, попавшие в окно события.
+// Отрицательные корректировки XP очки не уменьшают.
+func (e *Event) Score(gains []Gain) int {
+	score := 0
+	for _, g := range gains {
+		if !e.IsLive(g.At) {
+			continue
+		}
+		switch e.Metric {
+		case MetricXPGained:
+			score += max(g.XP, 0)
+		case MetricTasksCompleted:
+			score += max(g.Tasks, 0)
+		}
+	}
+	return score
+}
+
+// Standing - место студента в лидерборде события.
+type Standing struct {
+	// StudentID - ID студента.
+	StudentID string `json:"student_id"`
+
+	// Score - набранные очки.
+	Score int `json:"score"`
+
+	// Rank - место (1 - первое). Одинаковые очки делят место.
+	Rank int `json:"rank"`
+}
+
+// RankStandings сортирует участников по очкам и проставляет места.
+// Участники без очков отбрасываются: они не участвовали.
+func RankStandings(standings []Standing) []Standing {
+	ranked := make([]Standing, 0, len(standings))
+	for _, s := range standings {
+		if s.Score > 0 {
+			ranked = append(ranked, s)
+		}
+	}
+
+	slices.SortStableFunc(ranked, func(a, b Standing) int {
+		if a.Score != b.Score {
+			return b.Score - a.Score
+		}
+		return strings.Compare(a.StudentID, b.StudentID)
+	})
+
+	for i := range ranked {
+		if i > 0 && ranked[i].Score == ranked[i-1].Score {
+			ranked[i].Rank = ranked[i-1].Rank
+		} else {
+			ranked[i].Rank = i + 1
+		}
+	}
+	return ranked
+}
+
+// Winners возвращает участников, занявших первые WinnersCount мест.
+func Winners(ranked []Standing) []Standing {
+	var winners []Standing
+	for _, s := range ranked {
+		if s.Rank > WinnersCount {
+			break
+		}
+		winners = append(winners, s)
+	}
+	return winners
+}
+
+func invalid(msg string) error {
+	return shared.NewDomainError("event", "Validate", shared.ErrInvalidInput, msg)
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// REPOSITORIES
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Repository хранит события и их замороженные результаты.
+type Repository interface {
+	// Create сохраняет новое событие.
+	Create(ctx context.Context, e *Event) error
+
+	// GetByID возвращает событие или ErrEventNotFound.
+	GetByID(ctx context.Context, id string) (*Event, error)
+
+	// Update сохраняет окно и состояние события.
+	Update(ctx context.Context, e *Event) error
+
+	// List возвращает последние события, новые первыми.
+	List(ctx context.Context, limit int) ([]*Event, error)
+
+	// ListOpen возвращает все незамороженные события по началу окна.
+	ListOpen(ctx context.Context) ([]*Event, error)
+
+	// Finalize в одной транзакции сохраняет результаты и помечает событие
+	// замороженным. Возвращает false, если событие уже было заморожено.
+	Finalize(ctx context.Context, eventID string, results []Standing, finalizedAt time.Time) (bool, error)
+
+	// GetResults возвращает замороженные результаты по местам.
+	GetResults(ctx context.Context, eventID string, limit int) ([]Standing, error)
+}
+
+// Scoreboard - живой лидерборд события (Redis sorted set).
+type Scoreboard interface {
+	// AddScore прибавляет очки студенту.
+	AddScore(ctx context.Context, eventID, studentID string, score int) error
+
+	// Top возвращает первых limit участников по очкам.
+	Top(ctx context.Context, eventID string, limit int) ([]Standing, error)
+
+	// Position возвращает место студента; nil, если у него нет очков.
+	Position(ctx context.Context, eventID, studentID string) (*Standing, error)
+
+	// All возвращает всех участников события.
+	All(ctx context.Context, eventID string) ([]Standing, error)
+
+	// Count возвращает количество участников.
+	Count(ctx context.Context, eventID string) (int, error)
+}
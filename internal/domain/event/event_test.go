@@ -0,0 +1,68 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvent_Score(t *testing.T) {
+	now := time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2026, time.May, 4, 4, 0, 0, 0, time.UTC) // 09:00 по Алматы
+	end := start.Add(7 * 24 * time.Hour)
+
+	e, err := NewEvent("evt-1", "Неделя графов", start, end, MetricTasksCompleted, now)
+	require.NoError(t, err)
+
+	gains := []Gain{
+		{At: start.Add(-time.Second), XP: 100, Tasks: 1}, // до старта - не считается
+		{At: start, XP: 200, Tasks: 2},                   // ровно на старте - считается
+		{At: start.Add(time.Hour), XP: -50, Tasks: 0},    // корректировка не отнимает очки
+		{At: end.Add(-time.Second), XP: 300, Tasks: 3},
+		{At: end, XP: 400, Tasks: 4}, // конец окна не включительно
+	}
+	assert.Equal(t, 5, e.Score(gains))
+
+	e.Metric = MetricXPGained
+	assert.Equal(t, 500, e.Score(gains))
+}
+
+func TestRankStandings(t *testing.T) {
+	ranked := RankStandings([]Standing{
+		{StudentID: "c", Score: 10},
+		{StudentID: "a", Score: 30},
+		{StudentID: "zero", Score: 0},
+		{StudentID: "b", Score: 10},
+		{StudentID: "d", Score: 5},
+	})
+
+	// Участник без очков отброшен, одинаковые очки делят место
+	assert.Equal(t, []Standing{
+		{StudentID: "a", Score: 30, Rank: 1},
+		{StudentID: "b", Score: 10, Rank: 2},
+		{StudentID: "c", Score: 10, Rank: 2},
+		{StudentID: "d", Score: 5, Rank: 4},
+	}, ranked)
+	assert.Len(t, Winners(ranked), 3)
+}
+
+func TestEvent_Close(t *testing.T) {
+	now := time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC)
+	start := now.Add(24 * time.Hour)
+
+	// Ещё не начавшееся событие закрывается с пустым окном
+	e, err := NewEvent("evt-1", "Спринт", start, start.Add(48*time.Hour), MetricXPGained, now)
+	require.NoError(t, err)
+	require.NoError(t, e.Close(now))
+	assert.Equal(t, start, e.EndsAt)
+	assert.True(t, e.HasEnded(now.Add(24*time.Hour)))
+	assert.Zero(t, e.Score([]Gain{{At: start, XP: 100}}))
+
+	e.Status = StatusFinalized
+	assert.ErrorIs(t, e.Close(now), ErrEventFinalized)
+
+	_, err = NewEvent("evt-2", "Прошлое", now.Add(-48*time.Hour), now.Add(-time.Hour), MetricXPGained, now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,323 @@
+// Package experiment содержит A/B эксперименты с формулировками уведомлений.
+//
+// Студент попадает в вариант детерминированно (featureflag.Pick по имени
+// эксперимента и ID студента), поэтому вариант не меняется между
+// перезапусками. Показ записывается в experiment_assignments только когда
+// уведомление действительно отправлено, и только первый. Исход показа -
+// любой прирост XP в течение OutcomeWindow после него; воркер сводит исходы
+// по вариантам в experiment_results.
+package experiment
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/pkg/featureflag"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CONSTANTS & ERRORS
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// OutcomeWindow - сколько после показа ждём прироста XP.
+	OutcomeWindow = 24 * time.Hour
+
+	// MinVariants - сколько вариантов нужно для сравнения.
+	MinVariants = 2
+
+	// confidenceZ - z-значение для 95% доверительного интервала.
+	confidenceZ = 1.96
+)
+
+// namePattern - имя эксперимента входит в хеш распределения, поэтому оно
+// неизменно и ограничено тем, что безопасно в URL и логах.
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_.]{0,99}$`)
+
+// Ошибки экспериментов.
+var (
+	// ErrExperimentNotFound - эксперимента нет.
+	ErrExperimentNotFound = shared.NewDomainError("experiment", "Find", shared.ErrNotFound, "experiment not found")
+
+	// ErrExperimentExists - эксперимент с таким именем уже есть.
+	ErrExperimentExists = shared.NewDomainError("experiment", "Create", shared.ErrAlreadyExists, "experiment with this name already exists")
+
+	// ErrExperimentStopped - эксперимент уже остановлен.
+	ErrExperimentStopped = shared.NewDomainError("experiment", "Stop", shared.ErrInvalidState, "experiment is already stopped")
+)
+
+// Status - состояние эксперимента.
+type Status string
+
+const (
+	// StatusRunning - эксперимент идёт в окне [StartsAt, EndsAt).
+	StatusRunning Status = "running"
+
+	// StatusStopped - эксперимент остановлен, варианты больше не выдаются.
+	StatusStopped Status = "stopped"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// EXPERIMENT
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Variant - вариант эксперимента.
+type Variant struct {
+	// Name - имя варианта; по нему выбирается шаблон уведомления.
+	Name string `json:"name"`
+
+	// Weight - относительная доля студентов в варианте.
+	Weight int `json:"weight"`
+}
+
+// Experiment - эксперимент с формулировкой одного типа уведомлений.
+type Experiment struct {
+	// ID - идентификатор эксперимента (UUID).
+	ID string `json:"id"`
+
+	// Name - неизменное имя, например "rank_up_wording".
+	Name string `json:"name"`
+
+	// NotificationType - тип уведомления, формулировку которого сравниваем.
+	NotificationType string `json:"notification_type"`
+
+	// Variants - варианты с весами.
+	Variants []Variant `json:"variants"`
+
+	// StartsAt - начало эксперимента.
+	StartsAt time.Time `json:"starts_at"`
+
+	// EndsAt - конец эксперимента (nil - до остановки).
+	EndsAt *time.Time `json:"ends_at,omitempty"`
+
+	// Status - состояние эксперимента.
+	Status Status `json:"status"`
+
+	// CreatedAt - когда эксперимент создан.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewExperiment создаёт идущий эксперимент.
+func NewExperiment(id, name, notificationType string, variants []Variant, startsAt time.Time, endsAt *time.Time, now time.Time) (*Experiment, error) {
+	e := &Experiment{
+		ID:               id,
+		Name:             name,
+		NotificationType: notificationType,
+		Variants:         variants,
+		StartsAt:         startsAt.UTC(),
+		Status:           StatusRunning,
+		CreatedAt:        now.UTC(),
+	}
+	if endsAt != nil {
+		end := endsAt.UTC()
+		e.EndsAt = &end
+	}
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Validate проверяет имя, варианты и окно.
+func (e *Experiment) Validate() error {
+	if !namePattern.MatchString(e.Name) {
+		return invalid(fmt.Sprintf("name %q must match %s", e.Name, namePattern))
+	}
+	if e.NotificationType == "" {
+		return invalid("notification_type is required")
+	}
+	if len(e.Variants) < MinVariants {
+		return invalid(fmt.Sprintf("at least %d variants are required", MinVariants))
+	}
+	seen := make(map[string]bool, len(e.Variants))
+	for _, v := range e.Variants {
+		if v.Name == "" {
+			return invalid("variant name is required")
+		}
+		if seen[v.Name] {
+			return invalid(fmt.Sprintf("duplicate variant %q", v.Name))
+		}
+		seen[v.Name] = true
+		if v.Weight <= 0 {
+			return invalid(fmt.Sprintf("variant %q must have a positive weight", v.Name))
+		}
+	}
+	if e.StartsAt.IsZero() {
+		return invalid("starts_at is required")
+	}
+	if e.EndsAt != nil && !e.EndsAt.After(e.StartsAt) {
+		return invalid("ends_at must be after starts_at")
+	}
+	return nil
+}
+
+// IsRunning возвращает true, если в момент t эксперимент выдаёт варианты.
+func (e *Experiment) IsRunning(t time.Time) bool {
+	return e.Status == StatusRunning && !t.Before(e.StartsAt) && (e.EndsAt == nil || t.Before(*e.EndsAt))
+}
+
+// Stop останавливает эксперимент: окно обрезается до now.
+func (e *Experiment) Stop(now time.Time) error {
+	if e.Status == StatusStopped {
+		return ErrExperimentStopped
+	}
+	now = now.UTC()
+	e.Status = StatusStopped
+	if e.EndsAt == nil || now.Before(*e.EndsAt) {
+		e.EndsAt = &now
+	}
+	return nil
+}
+
+// Assign возвращает вариант студента. Вариант зависит только от имени
+// эксперимента и ID студента.
+func (e *Experiment) Assign(studentID string) string {
+	weights := make([]int, len(e.Variants))
+	for i, v := range e.Variants {
+		weights[i] = v.Weight
+	}
+	if i := featureflag.Pick(e.Name, studentID, weights); i >= 0 {
+		return e.Variants[i].Name
+	}
+	return ""
+}
+
+// Assignment - первый показ варианта студенту.
+type Assignment struct {
+	ExperimentID   string    `json:"experiment_id"`
+	StudentID      string    `json:"student_id"`
+	Variant        string    `json:"variant"`
+	NotificationID string    `json:"notification_id,omitempty"`
+	ExposedAt      time.Time `json:"exposed_at"`
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// OUTCOMES
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Exposure - показ с первым приростом XP после него.
+type Exposure struct {
+	// Variant - показанный вариант.
+	Variant string
+
+	// ExposedAt - когда уведомление отправлено.
+	ExposedAt time.Time
+
+	// NextGainAt - первый прирост XP после показа (nil - не было).
+	NextGainAt *time.Time
+}
+
+// Converted проверяет, что прирост XP попал в окно исхода.
+func (x Exposure) Converted() bool {
+	return x.NextGainAt != nil &&
+		x.NextGainAt.After(x.ExposedAt) &&
+		!x.NextGainAt.After(x.ExposedAt.Add(OutcomeWindow))
+}
+
+// Result - исход варианта.
+type Result struct {
+	// Variant - вариант.
+	Variant string `json:"variant"`
+
+	// Exposed - показы с закрытым окном исхода.
+	Exposed int `json:"exposed"`
+
+	// Converted - показы с приростом XP в окне.
+	Converted int `json:"converted"`
+
+	// Rate - доля показов с приростом.
+	Rate float64 `json:"rate"`
+
+	// CILow, CIHigh - 95% доверительный интервал доли.
+	CILow  float64 `json:"ci_low"`
+	CIHigh float64 `json:"ci_high"`
+}
+
+// Summarize сводит показы по вариантам эксперимента. Показы, окно исхода
+// которых на момент now ещё не закрылось, не учитываются: иначе свежие
+// показы занижали бы долю.
+func (e *Experiment) Summarize(exposures []Exposure, now time.Time) []Result {
+	results := make([]Result, len(e.Variants))
+	index := make(map[string]int, len(e.Variants))
+	for i, v := range e.Variants {
+		results[i].Variant = v.Name
+		index[v.Name] = i
+	}
+
+	for _, x := range exposures {
+		i, ok := index[x.Variant]
+		if !ok || now.Before(x.ExposedAt.Add(OutcomeWindow)) {
+			continue
+		}
+		results[i].Exposed++
+		if x.Converted() {
+			results[i].Converted++
+		}
+	}
+
+	for i := range results {
+		r := &results[i]
+		r.Rate, r.CILow, r.CIHigh = proportion(r.Converted, r.Exposed)
+	}
+	return results
+}
+
+// proportion возвращает долю успехов и её 95% интервал в нормальном
+// приближении, обрезанный до [0, 1].
+func proportion(successes, n int) (rate, low, high float64) {
+	if n == 0 {
+		return 0, 0, 0
+	}
+	rate = float64(successes) / float64(n)
+	margin := confidenceZ * math.Sqrt(rate*(1-rate)/float64(n))
+	return rate, math.Max(0, rate-margin), math.Min(1, rate+margin)
+}
+
+func invalid(msg string) error {
+	return shared.NewDomainError("experiment", "Validate", shared.ErrInvalidInput, msg)
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// REPOSITORY
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Repository хранит эксперименты, показы и их исходы.
+type Repository interface {
+	// Create сохраняет новый эксперимент.
+	Create(ctx context.Context, e *Experiment) error
+
+	// GetByID возвращает эксперимент или ErrExperimentNotFound.
+	GetByID(ctx context.Context, id string) (*Experiment, error)
+
+	// Update сохраняет окно и состояние эксперимента.
+	Update(ctx context.Context, e *Experiment) error
+
+	// List возвращает последние эксперименты, новые первыми.
+	List(ctx context.Context, limit int) ([]*Experiment, error)
+
+	// ListRunning возвращает идущие в момент now эксперименты по началу.
+	ListRunning(ctx context.Context, now time.Time) ([]*Experiment, error)
+
+	// ListEndedSince возвращает эксперименты, идущие сейчас или
+	// закончившиеся после since.
+	ListEndedSince(ctx context.Context, since time.Time) ([]*Experiment, error)
+
+	// RecordExposure сохраняет первый показ студенту. Возвращает false,
+	// если показ уже был.
+	RecordExposure(ctx context.Context, a Assignment) (bool, error)
+
+	// ListExposures возвращает показы эксперимента с первым приростом XP
+	// после каждого.
+	ListExposures(ctx context.Context, experimentID string) ([]Exposure, error)
+
+	// SaveResults заменяет исходы эксперимента.
+	SaveResults(ctx context.Context, experimentID string, results []Result, computedAt time.Time) error
+
+	// GetResults возвращает исходы эксперимента и время расчёта
+	// (нулевое, если исходы ещё не считались).
+	GetResults(ctx context.Context, experimentID string) ([]Result, time.Time, error)
+}
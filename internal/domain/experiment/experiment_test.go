@@ -0,0 +1,114 @@
+package experiment
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRankExperiment(t *testing.T, weights ...int) *Experiment {
+	t.Helper()
+	now := time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC)
+	e, err := NewExperiment("exp-1", "rank_up_wording", "rank_up", []Variant{
+		{Name: "encouraging", Weight: weights[0]},
+		{Name: "competitive", Weight: weights[1]},
+	}, now, nil, now)
+	require.NoError(t, err)
+	return e
+}
+
+func TestExperiment_AssignIsStable(t *testing.T) {
+	e := newRankExperiment(t, 1, 1)
+	first := e.Assign("student-42")
+
+	// Тот же эксперимент, загруженный заново (как после перезапуска)
+	reloaded := *e
+	reloaded.Variants = append([]Variant(nil), e.Variants...)
+	assert.Equal(t, first, reloaded.Assign("student-42"))
+
+	// Другой эксперимент распределяет студентов независимо
+	other := newRankExperiment(t, 1, 1)
+	other.Name = "rank_up_wording_v2"
+	differ := 0
+	for i := range 1000 {
+		id := fmt.Sprintf("student-%04d", i)
+		if e.Assign(id) != other.Assign(id) {
+			differ++
+		}
+	}
+	assert.InDelta(t, 500, differ, 100)
+}
+
+func TestExperiment_AssignFollowsWeights(t *testing.T) {
+	e := newRankExperiment(t, 3, 1)
+
+	counts := map[string]int{}
+	for i := range 20000 {
+		counts[e.Assign(fmt.Sprintf("student-%05d", i))]++
+	}
+	assert.InDelta(t, 15000, counts["encouraging"], 400)
+	assert.InDelta(t, 5000, counts["competitive"], 400)
+}
+
+func TestExperiment_Validate(t *testing.T) {
+	now := time.Now()
+	_, err := NewExperiment("exp-1", "Rank Wording", "rank_up", []Variant{{Name: "a", Weight: 1}, {Name: "b", Weight: 1}}, now, nil, now)
+	assert.Error(t, err, "name must be stable and URL-safe")
+
+	_, err = NewExperiment("exp-1", "rank_wording", "rank_up", []Variant{{Name: "a", Weight: 1}}, now, nil, now)
+	assert.Error(t, err, "one variant is not an experiment")
+
+	_, err = NewExperiment("exp-1", "rank_wording", "rank_up", []Variant{{Name: "a", Weight: 1}, {Name: "a", Weight: 1}}, now, nil, now)
+	assert.Error(t, err)
+
+	_, err = NewExperiment("exp-1", "rank_wording", "rank_up", []Variant{{Name: "a", Weight: 1}, {Name: "b", Weight: 0}}, now, nil, now)
+	assert.Error(t, err)
+}
+
+func TestExperiment_IsRunningAndStop(t *testing.T) {
+	e := newRankExperiment(t, 1, 1)
+	at := e.StartsAt.Add(time.Hour)
+
+	assert.False(t, e.IsRunning(e.StartsAt.Add(-time.Second)))
+	assert.True(t, e.IsRunning(at))
+
+	require.NoError(t, e.Stop(at))
+	assert.False(t, e.IsRunning(at))
+	assert.Equal(t, at, *e.EndsAt)
+	assert.ErrorIs(t, e.Stop(at), ErrExperimentStopped)
+}
+
+func TestExperiment_SummarizeAttributionWindow(t *testing.T) {
+	e := newRankExperiment(t, 1, 1)
+	exposed := e.StartsAt.Add(time.Hour)
+	at := func(d time.Duration) *time.Time {
+		t := exposed.Add(d)
+		return &t
+	}
+	now := exposed.Add(OutcomeWindow)
+
+	exposures := []Exposure{
+		{Variant: "encouraging", ExposedAt: exposed, NextGainAt: at(time.Hour)},                 // прирост в окне
+		{Variant: "encouraging", ExposedAt: exposed, NextGainAt: at(OutcomeWindow)},             // ровно на границе - в окне
+		{Variant: "encouraging", ExposedAt: exposed, NextGainAt: at(OutcomeWindow + 1)},         // позже окна
+		{Variant: "encouraging", ExposedAt: exposed, NextGainAt: nil},                           // без прироста
+		{Variant: "competitive", ExposedAt: exposed, NextGainAt: at(0)},                         // прирост до отправки не считается
+		{Variant: "competitive", ExposedAt: now.Add(-time.Hour), NextGainAt: at(OutcomeWindow)}, // окно не закрылось
+		{Variant: "retired", ExposedAt: exposed, NextGainAt: at(time.Hour)},                     // неизвестный вариант
+	}
+
+	results := e.Summarize(exposures, now)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "encouraging", results[0].Variant)
+	assert.Equal(t, 4, results[0].Exposed)
+	assert.Equal(t, 2, results[0].Converted)
+	assert.InDelta(t, 0.5, results[0].Rate, 1e-9)
+	assert.InDelta(t, 0.5-1.96*0.25, results[0].CILow, 1e-9)
+	assert.InDelta(t, 0.5+1.96*0.25, results[0].CIHigh, 1e-9)
+
+	assert.Equal(t, Result{Variant: "competitive", Exposed: 1}, results[1])
+}
@@ -0,0 +1,40 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CACHE BACKUP
+// Резервная копия кэша лидерборда для переноса между инстансами Redis
+// без полного пересчёта рейтинга.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ErrInvalidBackup - резервную копию нельзя восстановить: неизвестная версия,
+// обрезанный файл или не сходятся счётчики записей.
+var ErrInvalidBackup = errors.New("invalid leaderboard backup")
+
+// BackupCohort - что выгружено или восстановлено для одной когорты.
+type BackupCohort struct {
+	Cohort    string `json:"cohort"`
+	Entries   int    `json:"entries"`
+	Snapshots int    `json:"snapshots"`
+}
+
+// BackupSummary - итог выгрузки или восстановления.
+type BackupSummary struct {
+	Version int            `json:"version"`
+	Cohorts []BackupCohort `json:"cohorts"`
+}
+
+// CacheBackup определяет контракт резервного копирования кэша лидерборда.
+type CacheBackup interface {
+	// ExportBackup пишет состояние когорт в w (все когорты, если список пуст).
+	ExportBackup(ctx context.Context, w io.Writer, cohorts []string) (*BackupSummary, error)
+
+	// ImportBackup восстанавливает копию; каждая когорта заменяется атомарно.
+	// Ошибки формата оборачивают ErrInvalidBackup.
+	ImportBackup(ctx context.Context, r io.Reader) (*BackupSummary, error)
+}
@@ -189,6 +189,17 @@ type LeaderboardEntry struct {
 
 	// UpdatedAt - время последнего обновления XP.
 	UpdatedAt time.Time
+
+	// HiddenFromLeaderboard - студент скрыл себя из публичного рейтинга:
+	// ранг считается как обычно, но другим запись не показывается.
+	HiddenFromLeaderboard bool
+
+	// HidesOnlineStatus - студент не показывает другим онлайн-статус.
+	HidesOnlineStatus bool
+
+	// XPReviewPending - прирост XP студента ждёт проверки куратора.
+	// XP не снимается, в рейтинге рядом с именем показывается пометка.
+	XPReviewPending bool
 }
 
 // NewLeaderboardEntry создаёт новую запись лидерборда с валидацией.
@@ -266,6 +277,17 @@ func (e *LeaderboardEntry) XPGap(other *LeaderboardEntry) XP {
 	return XP(diff)
 }
 
+// VisibleTo проверяет, показывать ли запись студенту viewerID в публичном
+// рейтинге. Скрывшийся студент всегда видит себя.
+func (e *LeaderboardEntry) VisibleTo(viewerID string) bool {
+	return !e.HiddenFromLeaderboard || (viewerID != "" && e.StudentID == viewerID)
+}
+
+// ShowsOnlineTo проверяет, показывать ли студенту viewerID онлайн-статус записи.
+func (e *LeaderboardEntry) ShowsOnlineTo(viewerID string) bool {
+	return !e.HidesOnlineStatus || (viewerID != "" && e.StudentID == viewerID)
+}
+
 // Clone создаёт копию записи.
 func (e *LeaderboardEntry) Clone() *LeaderboardEntry {
 	if e == nil {
@@ -527,4 +549,8 @@ var (
 
 	// ErrEmptyLeaderboard - лидерборд пуст.
 	ErrEmptyLeaderboard = errors.New("leaderboard is empty")
+
+	// ErrStudentNotRanked - студента ещё нет в рейтинге (например, новый
+	// студент до первой пересборки лидерборда).
+	ErrStudentNotRanked = errors.New("student is not ranked yet")
 )
@@ -0,0 +1,50 @@
+package leaderboard
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PERIOD
+// Недельный рейтинг считается по XP, набранному с понедельника 00:00 по
+// времени Алматы: неделя сменяется в полночь по местному времени, а не по UTC.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Period - период, за который строится рейтинг.
+type Period string
+
+const (
+	// PeriodAll - рейтинг по всему XP.
+	PeriodAll Period = "all"
+
+	// PeriodWeek - рейтинг по XP, набранному за текущую неделю.
+	PeriodWeek Period = "week"
+)
+
+// ParsePeriod разбирает период; пустая строка означает PeriodAll.
+func ParsePeriod(s string) (Period, error) {
+	switch Period(s) {
+	case "", PeriodAll:
+		return PeriodAll, nil
+	case PeriodWeek:
+		return PeriodWeek, nil
+	default:
+		return "", fmt.Errorf("unknown period %q (want %q or %q)", s, PeriodAll, PeriodWeek)
+	}
+}
+
+// WeekOf возвращает ISO-неделю момента t по времени Алматы ("2024-W07").
+func WeekOf(t time.Time) string {
+	year, week := timeutil.ToAlmaty(t).ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", year, week)
+}
+
+// WeekBounds возвращает границы недели момента t по времени Алматы:
+// понедельник 00:00 и следующий понедельник 00:00.
+func WeekBounds(t time.Time) (time.Time, time.Time) {
+	start := timeutil.StartOfWeek(t)
+	return start, start.AddDate(0, 0, 7)
+}
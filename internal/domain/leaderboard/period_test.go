@@ -0,0 +1,37 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeekBounds_RollOverAtAlmatyMidnight(t *testing.T) {
+	// Понедельник 00:00 по Алматы - это воскресенье 19:00 UTC
+	monday := time.Date(2026, time.October, 11, 19, 0, 0, 0, time.UTC)
+
+	before := monday.Add(-time.Second)
+	assert.Equal(t, "2026-W41", WeekOf(before))
+	assert.Equal(t, "2026-W42", WeekOf(monday))
+
+	start, end := WeekBounds(monday.Add(3 * 24 * time.Hour))
+	assert.True(t, start.Equal(monday), start)
+	assert.True(t, end.Equal(monday.AddDate(0, 0, 7)), end)
+
+	// Последняя секунда прошлой недели ещё относится к ней
+	_, prevEnd := WeekBounds(before)
+	assert.True(t, prevEnd.Equal(monday), prevEnd)
+}
+
+func TestParsePeriod(t *testing.T) {
+	for in, want := range map[string]Period{"": PeriodAll, "all": PeriodAll, "week": PeriodWeek} {
+		got, err := ParsePeriod(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	_, err := ParsePeriod("month")
+	assert.Error(t, err)
+}
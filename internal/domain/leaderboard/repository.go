@@ -4,8 +4,13 @@ package leaderboard
 import (
 	"context"
 	"time"
+
+	"github.com/alem-hub/alem-community-hub/pkg/pagination"
 )
 
+// PageBounds - ограничения размера страницы лидерборда.
+var PageBounds = pagination.Bounds{DefaultLimit: 20, MaxLimit: 100}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // LEADERBOARD REPOSITORY INTERFACE
 // ══════════════════════════════════════════════════════════════════════════════
@@ -54,6 +59,14 @@ type LeaderboardRepository interface {
 	// Возвращает nil, если студент не найден.
 	GetStudentRank(ctx context.Context, studentID string, cohort Cohort) (*LeaderboardEntry, error)
 
+	// GetGlobalRank возвращает позицию студента в общем лидерборде
+	// (по всем когортам). Возвращает nil, если студента ещё нет в рейтинге.
+	GetGlobalRank(ctx context.Context, studentID string) (*LeaderboardEntry, error)
+
+	// GetStudentRanks возвращает текущие позиции нескольких студентов одним запросом.
+	// Студентов, которых нет в лидерборде, в результате нет.
+	GetStudentRanks(ctx context.Context, studentIDs []string, cohort Cohort) (map[string]*LeaderboardEntry, error)
+
 	// GetTop возвращает топ-N студентов из кеша или последнего снапшота.
 	GetTop(ctx context.Context, cohort Cohort, limit int) ([]*LeaderboardEntry, error)
 
@@ -61,12 +74,25 @@ type LeaderboardRepository interface {
 	// page начинается с 1, pageSize — количество записей на странице.
 	GetPage(ctx context.Context, cohort Cohort, page, pageSize int) ([]*LeaderboardEntry, error)
 
+	// GetAfterRank возвращает до limit записей с рангом больше afterRank
+	// (keyset-пагинация: стоимость не растёт с глубиной страницы).
+	GetAfterRank(ctx context.Context, cohort Cohort, afterRank, limit int) ([]*LeaderboardEntry, error)
+
 	// GetNeighbors возвращает соседей студента по рангу (±rangeSize).
 	GetNeighbors(ctx context.Context, studentID string, cohort Cohort, rangeSize int) ([]*LeaderboardEntry, error)
 
 	// GetTotalCount возвращает общее количество студентов в лидерборде.
 	GetTotalCount(ctx context.Context, cohort Cohort) (int, error)
 
+	// GetWeeklyTop возвращает топ-N студентов по XP, набранному за текущую
+	// неделю (см. WeekBounds). XP записей - прирост за неделю, ранги идут
+	// подряд с 1. Студентов без прироста в результате нет.
+	GetWeeklyTop(ctx context.Context, cohort Cohort, limit int) ([]*LeaderboardEntry, error)
+
+	// GetWeeklyCount возвращает количество студентов с приростом XP
+	// за текущую неделю.
+	GetWeeklyCount(ctx context.Context, cohort Cohort) (int, error)
+
 	// ──────────────────────────────────────────────────────────────────────────
 	// RANK HISTORY
 	// ──────────────────────────────────────────────────────────────────────────
@@ -154,9 +180,22 @@ type LeaderboardCache interface {
 	// SetCachedTop сохраняет топ-N в кеш с TTL.
 	SetCachedTop(ctx context.Context, cohort Cohort, entries []*LeaderboardEntry, ttl time.Duration) error
 
+	// GetCachedWeeklyTop возвращает закешированный недельный топ-N недели week
+	// (см. WeekOf) и общее число студентов в недельном рейтинге.
+	// Возвращает ошибку, если в кеше нет недели или в нём меньше limit записей
+	// при большем total.
+	GetCachedWeeklyTop(ctx context.Context, week string, cohort Cohort, limit int) ([]*LeaderboardEntry, int, error)
+
+	// SetCachedWeeklyTop сохраняет недельный топ и total в кеш с TTL.
+	SetCachedWeeklyTop(ctx context.Context, week string, cohort Cohort, entries []*LeaderboardEntry, total int, ttl time.Duration) error
+
 	// GetCachedRank возвращает закешированный ранг студента.
 	GetCachedRank(ctx context.Context, studentID string, cohort Cohort) (*LeaderboardEntry, error)
 
+	// GetCachedRanks возвращает закешированные ранги нескольких студентов за один запрос.
+	// Студентов, которых нет в кеше, в результате нет.
+	GetCachedRanks(ctx context.Context, studentIDs []string, cohort Cohort) (map[string]Rank, error)
+
 	// SetCachedRank сохраняет ранг студента в кеш.
 	SetCachedRank(ctx context.Context, entry *LeaderboardEntry, ttl time.Duration) error
 
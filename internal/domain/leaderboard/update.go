@@ -0,0 +1,82 @@
+package leaderboard
+
+import (
+	"context"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// LIVE UPDATES
+// Изменения лидерборда в реальном времени для дашборда без опроса.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// UpdateKind - тип изменения лидерборда.
+type UpdateKind string
+
+const (
+	// UpdateEntry - у студента изменились XP или полоса ранга.
+	UpdateEntry UpdateKind = "entry"
+
+	// UpdateRebuilt - когорта перестроена целиком; таблицу нужно перечитать.
+	UpdateRebuilt UpdateKind = "rebuilt"
+
+	// UpdateResync - подписка восстановлена после обрыва; изменения за время
+	// обрыва потеряны, таблицу нужно перечитать.
+	UpdateResync UpdateKind = "resync"
+)
+
+// Update - компактное сообщение об изменении лидерборда.
+type Update struct {
+	// Kind - тип изменения.
+	Kind UpdateKind `json:"type"`
+
+	// Cohort - когорта.
+	Cohort string `json:"cohort,omitempty"`
+
+	// StudentID, XP, Rank, OldRank заполнены для UpdateEntry.
+	// Rank и OldRank равны 0, если студента не было в рейтинге.
+	StudentID string `json:"student_id,omitempty"`
+	XP        int64  `json:"xp,omitempty"`
+	Rank      int64  `json:"rank,omitempty"`
+	OldRank   int64  `json:"old_rank,omitempty"`
+
+	// Count - количество записей для UpdateRebuilt.
+	Count int `json:"count,omitempty"`
+
+	// At - время изменения.
+	At time.Time `json:"at"`
+}
+
+// UpdateSubscriber определяет контракт подписки на изменения лидерборда.
+type UpdateSubscriber interface {
+	// SubscribeUpdates возвращает канал изменений когорты ("" - всех когорт).
+	// Канал закрывается, когда ctx завершён.
+	SubscribeUpdates(ctx context.Context, cohort string) <-chan Update
+}
+
+// RankBand - полоса рейтинга (топ-10, топ-50, топ-100, остальные).
+type RankBand string
+
+const (
+	RankBandNone   RankBand = ""
+	RankBandTop10  RankBand = "top10"
+	RankBandTop50  RankBand = "top50"
+	RankBandTop100 RankBand = "top100"
+	RankBandRest   RankBand = "rest"
+)
+
+// Band возвращает полосу рейтинга; RankBandNone для студента вне рейтинга.
+func (r Rank) Band() RankBand {
+	switch {
+	case !r.IsValid():
+		return RankBandNone
+	case r.IsTop10():
+		return RankBandTop10
+	case r.IsTop50():
+		return RankBandTop50
+	case r.IsTop100():
+		return RankBandTop100
+	default:
+		return RankBandRest
+	}
+}
@@ -0,0 +1,473 @@
+// Package matchscore оценивает, насколько помощник подходит студенту,
+// который застрял на задаче.
+//
+// Пакет чистый: на вход - факты о помощнике и запросе, на выход - скор
+// с разбивкой по факторам и причинами для интерфейса ("решил 2 недели
+// назад, сейчас онлайн"). Данные собирает вызывающий код.
+//
+// Философия: лучший помощник - не самый сильный, а тот, кто недавно сам
+// прошёл через эту задачу, свободен и на связи в те же часы.
+package matchscore
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// FACTORS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Factor - имя фактора в разбивке скора.
+type Factor string
+
+const (
+	// FactorRecency - как давно помощник решил задачу.
+	FactorRecency Factor = "recency"
+
+	// FactorStruggle - помощник сам долго решал задачу.
+	FactorStruggle Factor = "struggle"
+
+	// FactorLoad - сколько запросов помощник уже ведёт (чем меньше, тем лучше).
+	FactorLoad Factor = "load"
+
+	// FactorOnline - онлайн сейчас или был недавно.
+	FactorOnline Factor = "online"
+
+	// FactorHoursOverlap - совпадение часов активности с запросившим.
+	FactorHoursOverlap Factor = "hours_overlap"
+
+	// FactorRating - рейтинг помощника.
+	FactorRating Factor = "rating"
+
+	// FactorExperience - сколько раз помогал другим.
+	FactorExperience Factor = "help_history"
+
+	// FactorPriorContact - уже помогал этому студенту.
+	FactorPriorContact Factor = "prior_contact"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// WEIGHTS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Weights - максимум баллов за каждый фактор. Фактор со значением 1.0
+// приносит полный вес, 0.0 - ничего. Ноль выключает фактор.
+type Weights struct {
+	Recency      float64
+	Struggle     float64
+	Load         float64
+	Online       float64
+	HoursOverlap float64
+	Rating       float64
+	Experience   float64
+	PriorContact float64
+}
+
+// DefaultWeights возвращает веса по умолчанию (в сумме 100).
+func DefaultWeights() Weights {
+	return Weights{
+		Recency:      15,
+		Struggle:     10,
+		Load:         10,
+		Online:       25,
+		HoursOverlap: 5,
+		Rating:       20,
+		Experience:   5,
+		PriorContact: 10,
+	}
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// INPUT
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Пороги свежести решения.
+const (
+	// FreshSolveAge - решил недавно и помнит задачу: полный балл.
+	FreshSolveAge = 30 * 24 * time.Hour
+
+	// StaleSolveAge - решил давно: минимальный балл.
+	StaleSolveAge = 365 * 24 * time.Hour
+
+	// staleRecency - доля балла за давнее решение.
+	staleRecency = 0.1
+
+	// MaxDerivedSolveGap - максимальный промежуток между соседними
+	// решениями, который ещё считается временем работы над задачей.
+	MaxDerivedSolveGap = 14 * 24 * time.Hour
+
+	// experienceCap - после скольких помощей опыт даёт полный балл.
+	experienceCap = 20
+)
+
+// QuietHours - тихие часы студента [Start, End) в часах 0-23.
+// Start == End - тихих часов нет.
+type QuietHours struct {
+	Start int
+	End   int
+}
+
+// Input - факты о помощнике относительно конкретного запроса.
+type Input struct {
+	// Now - момент оценки.
+	Now time.Time
+
+	// SolvedAt - когда помощник решил задачу (нулевое - неизвестно).
+	SolvedAt time.Time
+
+	// SolveDuration - сколько помощник решал задачу (0 - неизвестно).
+	SolveDuration time.Duration
+
+	// TypicalSolveDuration - типичное время решения задачи (0 - неизвестно).
+	TypicalSolveDuration time.Duration
+
+	// OpenAssignments - запросы, которые помощник уже ведёт.
+	OpenAssignments int
+
+	// IsOnline - онлайн сейчас.
+	IsOnline bool
+
+	// LastSeenAt - последняя активность (нулевое - неизвестно или скрыто).
+	LastSeenAt time.Time
+
+	// HelperQuietHours, RequesterQuietHours - тихие часы (nil - неизвестно).
+	HelperQuietHours    *QuietHours
+	RequesterQuietHours *QuietHours
+
+	// Rating - рейтинг помощника (0-5).
+	Rating float64
+
+	// HelpCount - сколько раз помогал другим.
+	HelpCount int
+
+	// PriorContact - помогал этому студенту раньше.
+	PriorContact bool
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RESULT
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Component - вклад одного фактора.
+type Component struct {
+	Factor Factor
+
+	// Value - значение фактора 0.0-1.0.
+	Value float64
+
+	// Points - Value * вес фактора.
+	Points float64
+
+	// Reason - объяснение для студента (пустое - нечего сказать).
+	Reason string
+}
+
+// Result - итоговый скор с разбивкой.
+type Result struct {
+	Score      float64
+	Components []Component
+}
+
+// Breakdown возвращает баллы по факторам (только ненулевые).
+func (r Result) Breakdown() map[string]float64 {
+	breakdown := make(map[string]float64, len(r.Components))
+	for _, c := range r.Components {
+		if c.Points != 0 {
+			breakdown[string(c.Factor)] = c.Points
+		}
+	}
+	return breakdown
+}
+
+// Reasons возвращает причины, самые весомые первыми. Предупреждение
+// о загрузке помощника идёт последним.
+func (r Result) Reasons() []string {
+	components := make([]Component, 0, len(r.Components))
+	var loadReason string
+	for _, c := range r.Components {
+		if c.Reason == "" {
+			continue
+		}
+		if c.Factor == FactorLoad {
+			loadReason = c.Reason
+			continue
+		}
+		components = append(components, c)
+	}
+	sort.SliceStable(components, func(i, j int) bool {
+		return components[i].Points > components[j].Points
+	})
+
+	reasons := make([]string, 0, len(components)+1)
+	for _, c := range components {
+		reasons = append(reasons, c.Reason)
+	}
+	if loadReason != "" {
+		reasons = append(reasons, loadReason)
+	}
+	return reasons
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// SCORING
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Score оценивает помощника.
+func Score(in Input, w Weights) Result {
+	components := []Component{
+		component(FactorRecency, w.Recency)(recency(in.Now, in.SolvedAt)),
+		component(FactorStruggle, w.Struggle)(struggle(in.SolveDuration, in.TypicalSolveDuration)),
+		component(FactorLoad, w.Load)(load(in.OpenAssignments)),
+		component(FactorOnline, w.Online)(online(in.Now, in.IsOnline, in.LastSeenAt)),
+		component(FactorHoursOverlap, w.HoursOverlap)(hoursOverlap(in.RequesterQuietHours, in.HelperQuietHours)),
+		component(FactorRating, w.Rating)(rating(in.Rating)),
+		component(FactorExperience, w.Experience)(experience(in.HelpCount)),
+		component(FactorPriorContact, w.PriorContact)(priorContact(in.PriorContact)),
+	}
+
+	result := Result{Components: components}
+	for _, c := range components {
+		result.Score += c.Points
+	}
+	return result
+}
+
+// component собирает вклад фактора из значения и причины.
+func component(factor Factor, weight float64) func(value float64, reason string) Component {
+	return func(value float64, reason string) Component {
+		if weight == 0 {
+			reason = ""
+		}
+		return Component{Factor: factor, Value: value, Points: value * weight, Reason: reason}
+	}
+}
+
+// recency: решил за последние 30 дней - 1.0, дальше линейно до 0.1 к году.
+func recency(now, solvedAt time.Time) (float64, string) {
+	if solvedAt.IsZero() {
+		return 0, ""
+	}
+
+	age := now.Sub(solvedAt)
+	if age < 0 {
+		age = 0
+	}
+
+	value := staleRecency
+	switch {
+	case age <= FreshSolveAge:
+		value = 1
+	case age < StaleSolveAge:
+		progress := float64(age-FreshSolveAge) / float64(StaleSolveAge-FreshSolveAge)
+		value = 1 - progress*(1-staleRecency)
+	}
+	return value, "решил " + solvedAgo(age)
+}
+
+// struggle: решал дольше типичного - лучше понимает, где застревают.
+// Вдвое быстрее типичного - 0.0, как все - 0.5, в полтора раза дольше - 1.0.
+func struggle(duration, typical time.Duration) (float64, string) {
+	if duration <= 0 || typical <= 0 {
+		return 0, ""
+	}
+
+	ratio := float64(duration) / float64(typical)
+	value := clamp(ratio - 0.5)
+	if ratio >= 1.5 {
+		return value, "сам долго решал эту задачу"
+	}
+	return value, ""
+}
+
+// load: свободный помощник - 1.0, с каждым открытым запросом меньше.
+func load(open int) (float64, string) {
+	if open <= 0 {
+		return 1, ""
+	}
+	return 1 / float64(1+open), fmt.Sprintf("уже помогает: %d", open)
+}
+
+// online: онлайн - 1.0, недавно был - меньше.
+func online(now time.Time, isOnline bool, lastSeenAt time.Time) (float64, string) {
+	if isOnline {
+		return 1, "сейчас онлайн"
+	}
+	if lastSeenAt.IsZero() {
+		return 0, ""
+	}
+
+	switch elapsed := now.Sub(lastSeenAt); {
+	case elapsed < 5*time.Minute:
+		return 0.8, "был онлайн только что"
+	case elapsed < 30*time.Minute:
+		return 0.5, "был онлайн недавно"
+	case elapsed < time.Hour:
+		return 0.2, ""
+	default:
+		return 0, ""
+	}
+}
+
+// hoursOverlap: доля часов, когда запросивший не спит, а помощник тоже.
+func hoursOverlap(requester, helper *QuietHours) (float64, string) {
+	if requester == nil || helper == nil {
+		return 0, ""
+	}
+
+	awake, both := 0, 0
+	for hour := 0; hour < 24; hour++ {
+		if requester.contains(hour) {
+			continue
+		}
+		awake++
+		if !helper.contains(hour) {
+			both++
+		}
+	}
+	if awake == 0 {
+		return 0, ""
+	}
+
+	value := float64(both) / float64(awake)
+	if value >= 0.8 {
+		return value, "активен в те же часы"
+	}
+	return value, ""
+}
+
+// contains проверяет, что час попадает в тихие часы (с переходом через полночь).
+func (q QuietHours) contains(hour int) bool {
+	if q.Start == q.End {
+		return false
+	}
+	if q.Start < q.End {
+		return hour >= q.Start && hour < q.End
+	}
+	return hour >= q.Start || hour < q.End
+}
+
+// rating: рейтинг 0-5 линейно.
+func rating(r float64) (float64, string) {
+	value := clamp(r / 5)
+	if r >= 4.5 {
+		return value, "отличный рейтинг"
+	}
+	return value, ""
+}
+
+// experience: до experienceCap помощей линейно.
+func experience(count int) (float64, string) {
+	if count <= 0 {
+		return 0, ""
+	}
+	value := clamp(float64(count) / experienceCap)
+	if count >= 10 {
+		return value, fmt.Sprintf("помог %d %s", count, plural(count, "раз", "раза", "раз"))
+	}
+	return value, ""
+}
+
+// priorContact: уже помогал - полный балл.
+func priorContact(prior bool) (float64, string) {
+	if prior {
+		return 1, "помогал тебе раньше"
+	}
+	return 0, ""
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// SOLVE DURATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// SolveDuration возвращает, сколько студент решал задачу. Если время
+// отслеживалось (timeSpent), берётся оно; иначе - промежуток с предыдущего
+// решения, если он не длиннее MaxDerivedSolveGap (дольше - это перерыв,
+// а не работа). 0 - неизвестно.
+func SolveDuration(completedAt time.Time, timeSpent time.Duration, previousCompletedAt time.Time) time.Duration {
+	if timeSpent > 0 {
+		return timeSpent
+	}
+	if previousCompletedAt.IsZero() || !previousCompletedAt.Before(completedAt) {
+		return 0
+	}
+
+	gap := completedAt.Sub(previousCompletedAt)
+	if gap > MaxDerivedSolveGap {
+		return 0
+	}
+	return gap
+}
+
+// MedianDuration возвращает медиану известных длительностей (0 - нет данных).
+func MedianDuration(durations []time.Duration) time.Duration {
+	known := make([]time.Duration, 0, len(durations))
+	for _, d := range durations {
+		if d > 0 {
+			known = append(known, d)
+		}
+	}
+	if len(known) == 0 {
+		return 0
+	}
+
+	sort.Slice(known, func(i, j int) bool { return known[i] < known[j] })
+	mid := len(known) / 2
+	if len(known)%2 == 0 {
+		return (known[mid-1] + known[mid]) / 2
+	}
+	return known[mid]
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HELPERS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// solvedAgo форматирует давность решения: "сегодня", "2 недели назад".
+func solvedAgo(age time.Duration) string {
+	days := int(age.Hours() / 24)
+	switch {
+	case days < 1:
+		return "сегодня"
+	case days < 2:
+		return "вчера"
+	case days < 14:
+		return fmt.Sprintf("%d %s назад", days, plural(days, "день", "дня", "дней"))
+	case days < 60:
+		weeks := days / 7
+		return fmt.Sprintf("%d %s назад", weeks, plural(weeks, "неделю", "недели", "недель"))
+	case days < 365:
+		months := days / 30
+		return fmt.Sprintf("%d %s назад", months, plural(months, "месяц", "месяца", "месяцев"))
+	default:
+		return "больше года назад"
+	}
+}
+
+// plural выбирает форму слова для числа: 1 день, 2 дня, 5 дней.
+func plural(n int, one, few, many string) string {
+	n %= 100
+	if n >= 11 && n <= 14 {
+		return many
+	}
+	switch n % 10 {
+	case 1:
+		return one
+	case 2, 3, 4:
+		return few
+	default:
+		return many
+	}
+}
+
+// clamp ограничивает значение диапазоном 0.0-1.0.
+func clamp(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
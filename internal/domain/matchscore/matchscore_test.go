@@ -0,0 +1,222 @@
+package matchscore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 5, 10, 18, 0, 0, 0, time.UTC)
+
+const day = 24 * time.Hour
+
+// only оставляет один фактор с весом 100, чтобы проверять его отдельно.
+func only(factor Factor) Weights {
+	var w Weights
+	switch factor {
+	case FactorRecency:
+		w.Recency = 100
+	case FactorStruggle:
+		w.Struggle = 100
+	case FactorLoad:
+		w.Load = 100
+	case FactorOnline:
+		w.Online = 100
+	case FactorHoursOverlap:
+		w.HoursOverlap = 100
+	case FactorRating:
+		w.Rating = 100
+	case FactorExperience:
+		w.Experience = 100
+	case FactorPriorContact:
+		w.PriorContact = 100
+	}
+	return w
+}
+
+func TestScore_Factors(t *testing.T) {
+	tests := []struct {
+		name   string
+		factor Factor
+		in     Input
+		score  float64
+		reason string
+	}{
+		{"recency unknown", FactorRecency, Input{}, 0, ""},
+		{"recency today", FactorRecency, Input{SolvedAt: now.Add(-3 * time.Hour)}, 100, "решил сегодня"},
+		{"recency yesterday", FactorRecency, Input{SolvedAt: now.Add(-30 * time.Hour)}, 100, "решил вчера"},
+		{"recency days", FactorRecency, Input{SolvedAt: now.Add(-5 * day)}, 100, "решил 5 дней назад"},
+		{"recency weeks", FactorRecency, Input{SolvedAt: now.Add(-15 * day)}, 100, "решил 2 недели назад"},
+		{"recency fresh edge", FactorRecency, Input{SolvedAt: now.Add(-FreshSolveAge)}, 100, "решил 4 недели назад"},
+		{"recency half year", FactorRecency, Input{SolvedAt: now.Add(-197*day - 12*time.Hour)}, 55, "решил 6 месяцев назад"},
+		{"recency stale", FactorRecency, Input{SolvedAt: now.Add(-400 * day)}, 10, "решил больше года назад"},
+		{"recency future", FactorRecency, Input{SolvedAt: now.Add(time.Hour)}, 100, "решил сегодня"},
+
+		{"struggle unknown", FactorStruggle, Input{SolveDuration: time.Hour}, 0, ""},
+		{"struggle fast", FactorStruggle, Input{SolveDuration: time.Hour, TypicalSolveDuration: 4 * time.Hour}, 0, ""},
+		{"struggle typical", FactorStruggle, Input{SolveDuration: 2 * time.Hour, TypicalSolveDuration: 2 * time.Hour}, 50, ""},
+		{"struggle long", FactorStruggle, Input{SolveDuration: 3 * time.Hour, TypicalSolveDuration: 2 * time.Hour}, 100, "сам долго решал эту задачу"},
+		{"struggle very long", FactorStruggle, Input{SolveDuration: 9 * time.Hour, TypicalSolveDuration: 2 * time.Hour}, 100, "сам долго решал эту задачу"},
+
+		{"load free", FactorLoad, Input{}, 100, ""},
+		{"load one", FactorLoad, Input{OpenAssignments: 1}, 50, "уже помогает: 1"},
+		{"load three", FactorLoad, Input{OpenAssignments: 3}, 25, "уже помогает: 3"},
+
+		{"online now", FactorOnline, Input{IsOnline: true}, 100, "сейчас онлайн"},
+		{"online just now", FactorOnline, Input{LastSeenAt: now.Add(-2 * time.Minute)}, 80, "был онлайн только что"},
+		{"online recently", FactorOnline, Input{LastSeenAt: now.Add(-20 * time.Minute)}, 50, "был онлайн недавно"},
+		{"online within hour", FactorOnline, Input{LastSeenAt: now.Add(-45 * time.Minute)}, 20, ""},
+		{"online long ago", FactorOnline, Input{LastSeenAt: now.Add(-3 * time.Hour)}, 0, ""},
+		{"online hidden", FactorOnline, Input{}, 0, ""},
+
+		{"hours unknown", FactorHoursOverlap, Input{RequesterQuietHours: &QuietHours{23, 7}}, 0, ""},
+		{"hours same", FactorHoursOverlap, Input{
+			RequesterQuietHours: &QuietHours{23, 7}, HelperQuietHours: &QuietHours{23, 7},
+		}, 100, "активен в те же часы"},
+		{"hours none", FactorHoursOverlap, Input{
+			RequesterQuietHours: &QuietHours{0, 0}, HelperQuietHours: &QuietHours{0, 0},
+		}, 100, "активен в те же часы"},
+		{"hours shifted", FactorHoursOverlap, Input{
+			// Запросивший не спит 7-23 (16 ч), помощник спит 2-10: общие 10-23 (13 ч)
+			RequesterQuietHours: &QuietHours{23, 7}, HelperQuietHours: &QuietHours{2, 10},
+		}, 81.25, "активен в те же часы"},
+		{"hours night owl", FactorHoursOverlap, Input{
+			// Помощник спит 8-20: общие часы с запросившим 7 и 20-23 (4 из 16)
+			RequesterQuietHours: &QuietHours{23, 7}, HelperQuietHours: &QuietHours{8, 20},
+		}, 25, ""},
+
+		{"rating none", FactorRating, Input{}, 0, ""},
+		{"rating good", FactorRating, Input{Rating: 4}, 80, ""},
+		{"rating excellent", FactorRating, Input{Rating: 4.5}, 90, "отличный рейтинг"},
+		{"rating above max", FactorRating, Input{Rating: 7}, 100, "отличный рейтинг"},
+
+		{"experience none", FactorExperience, Input{}, 0, ""},
+		{"experience some", FactorExperience, Input{HelpCount: 5}, 25, ""},
+		{"experience many", FactorExperience, Input{HelpCount: 12}, 60, "помог 12 раз"},
+		{"experience plural", FactorExperience, Input{HelpCount: 22}, 100, "помог 22 раза"},
+
+		{"prior contact", FactorPriorContact, Input{PriorContact: true}, 100, "помогал тебе раньше"},
+		{"no prior contact", FactorPriorContact, Input{}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.in.Now = now
+			result := Score(tt.in, only(tt.factor))
+
+			assert.InDelta(t, tt.score, result.Score, 0.01)
+			if tt.reason == "" {
+				assert.Empty(t, result.Reasons())
+			} else {
+				assert.Equal(t, []string{tt.reason}, result.Reasons())
+			}
+		})
+	}
+}
+
+func TestScore_DefaultWeightsSumTo100(t *testing.T) {
+	best := Input{
+		Now:                  now,
+		SolvedAt:             now.Add(-day),
+		SolveDuration:        3 * time.Hour,
+		TypicalSolveDuration: 2 * time.Hour,
+		IsOnline:             true,
+		HelperQuietHours:     &QuietHours{23, 7},
+		RequesterQuietHours:  &QuietHours{23, 7},
+		Rating:               5,
+		HelpCount:            experienceCap,
+		PriorContact:         true,
+	}
+
+	assert.InDelta(t, 100, Score(best, DefaultWeights()).Score, 0.001)
+}
+
+func TestScore_RecentStrugglerBeatsStrongVeteran(t *testing.T) {
+	w := DefaultWeights()
+
+	veteran := Score(Input{
+		Now: now, SolvedAt: now.Add(-300 * day), SolveDuration: time.Hour, TypicalSolveDuration: 4 * time.Hour,
+		OpenAssignments: 2, IsOnline: true, Rating: 5, HelpCount: 40,
+	}, w)
+	struggler := Score(Input{
+		Now: now, SolvedAt: now.Add(-10 * day), SolveDuration: 7 * time.Hour, TypicalSolveDuration: 4 * time.Hour,
+		IsOnline: true, Rating: 4, HelpCount: 3,
+	}, w)
+
+	assert.Greater(t, struggler.Score, veteran.Score)
+}
+
+func TestResult_ReasonsAndBreakdown(t *testing.T) {
+	result := Score(Input{
+		Now:             now,
+		SolvedAt:        now.Add(-14 * day),
+		OpenAssignments: 2,
+		IsOnline:        true,
+		Rating:          4.8,
+	}, DefaultWeights())
+
+	// Сначала самые весомые, загрузка - в конце
+	assert.Equal(t, []string{"сейчас онлайн", "отличный рейтинг", "решил 2 недели назад", "уже помогает: 2"}, result.Reasons())
+
+	breakdown := result.Breakdown()
+	assert.InDelta(t, 25, breakdown["online"], 0.001)
+	assert.InDelta(t, 19.2, breakdown["rating"], 0.001)
+	assert.InDelta(t, 15, breakdown["recency"], 0.001)
+	assert.InDelta(t, 10.0/3, breakdown["load"], 0.001)
+	assert.NotContains(t, breakdown, "prior_contact")
+}
+
+func TestScore_ZeroWeightSilencesReason(t *testing.T) {
+	w := DefaultWeights()
+	w.Online = 0
+
+	result := Score(Input{Now: now, IsOnline: true, PriorContact: true}, w)
+
+	assert.Equal(t, []string{"помогал тебе раньше"}, result.Reasons())
+	require.Len(t, result.Components, 8)
+	for _, c := range result.Components {
+		if c.Factor == FactorOnline {
+			assert.Equal(t, 1.0, c.Value)
+			assert.Zero(t, c.Points)
+		}
+	}
+}
+
+func TestSolveDuration(t *testing.T) {
+	completed := now
+
+	tests := []struct {
+		name      string
+		timeSpent time.Duration
+		previous  time.Time
+		want      time.Duration
+	}{
+		{"tracked time wins", 3 * time.Hour, completed.Add(-day), 3 * time.Hour},
+		{"gap since previous", 0, completed.Add(-2 * day), 2 * day},
+		{"gap too long", 0, completed.Add(-20 * day), 0},
+		{"no previous", 0, time.Time{}, 0},
+		{"previous after completion", 0, completed.Add(time.Hour), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SolveDuration(completed, tt.timeSpent, tt.previous))
+		})
+	}
+}
+
+func TestMedianDuration(t *testing.T) {
+	assert.Zero(t, MedianDuration(nil))
+	assert.Zero(t, MedianDuration([]time.Duration{0, 0}))
+	assert.Equal(t, 2*time.Hour, MedianDuration([]time.Duration{5 * time.Hour, time.Hour, 0, 2 * time.Hour}))
+	assert.Equal(t, 90*time.Minute, MedianDuration([]time.Duration{2 * time.Hour, time.Hour}))
+}
+
+func TestPlural(t *testing.T) {
+	forms := map[int]string{1: "день", 2: "дня", 4: "дня", 5: "дней", 11: "дней", 14: "дней", 21: "день", 22: "дня", 111: "дней"}
+	for n, want := range forms {
+		assert.Equal(t, want, plural(n, "день", "дня", "дней"), n)
+	}
+}
@@ -0,0 +1,118 @@
+package notification
+
+import (
+	"context"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// ANNOUNCEMENTS
+// Объявления в общих чатах (итоги челленджей и т.п.). Ночью они не
+// отправляются, а ждут в очереди до конца тихих часов чата.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Announcement - сообщение для общего чата.
+type Announcement struct {
+	// ChatID - чат, в который отправляется объявление.
+	ChatID int64
+
+	// DedupKey - ключ повторов: из накопившихся в очереди объявлений с
+	// одинаковым ключом отправляется одно (последнее). Пустой - без склейки.
+	DedupKey string
+
+	// Message - текст объявления (HTML).
+	Message string
+
+	// Force - отправить сразу, даже в тихие часы (ручная отправка админом).
+	Force bool
+}
+
+// QuietHours - тихие часы чата: с Start:00 до End:00 по местному времени.
+// Окно может переходить через полночь (23-8). Start == End - тихих часов нет.
+type QuietHours struct {
+	Start int
+	End   int
+}
+
+// Enabled возвращает true, если тихие часы заданы.
+func (q QuietHours) Enabled() bool {
+	return q.Start != q.End
+}
+
+// Contains проверяет, попадает ли t в тихие часы (в часовом поясе t).
+func (q QuietHours) Contains(t time.Time) bool {
+	if !q.Enabled() {
+		return false
+	}
+	hour := t.Hour()
+	if q.Start < q.End {
+		return hour >= q.Start && hour < q.End
+	}
+	return hour >= q.Start || hour < q.End
+}
+
+// EndAfter возвращает ближайший после t конец тихих часов.
+func (q QuietHours) EndAfter(t time.Time) time.Time {
+	end := time.Date(t.Year(), t.Month(), t.Day(), q.End, 0, 0, 0, t.Location())
+	if !end.After(t) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+// PendingAnnouncement - объявление, ожидающее конца тихих часов.
+type PendingAnnouncement struct {
+	ID           string
+	ChatID       int64
+	DedupKey     string
+	Message      string
+	CreatedAt    time.Time
+	DeliverAfter time.Time
+}
+
+// CollapseAnnouncements склеивает повторы в очереди (в порядке постановки).
+// Из объявлений одного чата с одинаковым DedupKey остаётся одно - на месте
+// первого, но с текстом последнего: повторный запуск задачи знает больше.
+// Возвращает объявления к отправке и поглощённые повторы.
+func CollapseAnnouncements(pending []*PendingAnnouncement) (send, duplicates []*PendingAnnouncement) {
+	type dedupKey struct {
+		chatID int64
+		key    string
+	}
+	first := make(map[dedupKey]int)
+
+	for _, a := range pending {
+		if a.DedupKey == "" {
+			send = append(send, a)
+			continue
+		}
+
+		k := dedupKey{chatID: a.ChatID, key: a.DedupKey}
+		i, seen := first[k]
+		if !seen {
+			first[k] = len(send)
+			send = append(send, a)
+			continue
+		}
+
+		// Новый текст уходит на месте первого
+		merged := *send[i]
+		merged.Message = a.Message
+		duplicates = append(duplicates, a)
+		send[i] = &merged
+	}
+
+	return send, duplicates
+}
+
+// AnnouncementQueue - очередь отложенных объявлений (PostgreSQL).
+type AnnouncementQueue interface {
+	// Enqueue ставит объявление в очередь.
+	Enqueue(ctx context.Context, a *PendingAnnouncement) error
+
+	// ListDue возвращает объявления с DeliverAfter <= now в порядке постановки.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*PendingAnnouncement, error)
+
+	// Delete удаляет отправленные (или поглощённые) объявления.
+	Delete(ctx context.Context, ids []string) error
+}
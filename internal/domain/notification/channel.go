@@ -126,13 +126,33 @@ func NewRateLimitedResult(channel ChannelType, retryAfter time.Duration) Deliver
 		Channel:     channel,
 		DeliveredAt: time.Now().UTC(),
 		Error:       ErrRateLimited,
-		ErrorCode:   "RATE_LIMITED",
+		ErrorCode:   ErrorCodeRateLimited,
 		Retryable:   true,
 		RetryAfter:  retryAfter,
 		Metadata:    make(map[string]string),
 	}
 }
 
+// NewSkippedResult создаёт результат намеренно не отправленного уведомления:
+// получатель заглушил его или отключил в настройках. Это не ошибка
+// доставки, повторять отправку не нужно.
+func NewSkippedResult(channel ChannelType, reason error) DeliveryResult {
+	return DeliveryResult{
+		Success:     false,
+		Channel:     channel,
+		DeliveredAt: time.Now().UTC(),
+		Error:       reason,
+		ErrorCode:   ErrorCodeSkipped,
+		Metadata:    make(map[string]string),
+	}
+}
+
+// Skipped возвращает true, если уведомление не отправлялось по воле
+// получателя (см. NewSkippedResult).
+func (dr DeliveryResult) Skipped() bool {
+	return !dr.Success && dr.ErrorCode == ErrorCodeSkipped
+}
+
 // SetMetadata устанавливает метаданные результата.
 func (dr *DeliveryResult) SetMetadata(key, value string) {
 	if dr.Metadata == nil {
@@ -164,6 +184,18 @@ type DeliveryOptions struct {
 
 	// Timeout - таймаут отправки.
 	Timeout time.Duration
+
+	// Photo - картинка; текст уведомления становится подписью к ней.
+	Photo *Photo
+}
+
+// Photo - картинка, отправляемая вместе с уведомлением.
+type Photo struct {
+	// Filename - имя файла (по расширению канал узнаёт формат).
+	Filename string
+
+	// Data - содержимое файла (JPEG или PNG).
+	Data []byte
 }
 
 // DefaultDeliveryOptions возвращает опции по умолчанию.
@@ -194,6 +226,12 @@ func (opts DeliveryOptions) WithInlineKeyboard(keyboard [][]InlineButton) Delive
 	return opts
 }
 
+// WithPhoto создаёт копию опций с картинкой.
+func (opts DeliveryOptions) WithPhoto(filename string, data []byte) DeliveryOptions {
+	opts.Photo = &Photo{Filename: filename, Data: data}
+	return opts
+}
+
 // WithTimeout создаёт копию опций с указанным таймаутом.
 func (opts DeliveryOptions) WithTimeout(timeout time.Duration) DeliveryOptions {
 	opts.Timeout = timeout
@@ -336,6 +374,21 @@ type NotificationRepository interface {
 
 	// CountByType возвращает количество уведомлений определённого типа за период.
 	CountByType(ctx context.Context, notificationType NotificationType, since time.Time) (int, error)
+
+	// ─────────────────────────────────────────────────────────────────────────
+	// Центр уведомлений
+	// Доставка уведомления вытесняет предыдущие непрочитанные того же типа,
+	// чтобы счётчик не рос от повторяющихся изменений позиции.
+	// ─────────────────────────────────────────────────────────────────────────
+
+	// GetInbox возвращает уведомления центра уведомлений, новые первыми.
+	GetInbox(ctx context.Context, recipientID RecipientID, offset, limit int) ([]*Notification, error)
+
+	// CountUnread возвращает количество непрочитанных уведомлений.
+	CountUnread(ctx context.Context, recipientID RecipientID) (int, error)
+
+	// MarkAllRead помечает все уведомления получателя прочитанными.
+	MarkAllRead(ctx context.Context, recipientID RecipientID) (int64, error)
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -421,6 +474,17 @@ type TriggerHistoryRepository interface {
 	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
 }
 
+// ══════════════════════════════════════════════════════════════════════════════
+// NOTIFICATION COOLDOWN
+// ══════════════════════════════════════════════════════════════════════════════
+
+// CooldownStore ограничивает частоту однотипных уведомлений одному получателю.
+type CooldownStore interface {
+	// Acquire занимает ключ на ttl. Возвращает false, если ключ уже занят,
+	// то есть уведомление по этому ключу отправлялось меньше ttl назад.
+	Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // MESSAGE FORMATTER
 // ══════════════════════════════════════════════════════════════════════════════
@@ -488,6 +552,12 @@ var (
 	// ErrInvalidMessage - невалидное сообщение.
 	ErrInvalidMessage = errors.New("invalid notification message")
 
+	// ErrRecipientMuted - получатель заглушил уведомления (/mute).
+	ErrRecipientMuted = errors.New("recipient has muted notifications")
+
+	// ErrRecipientOptedOut - получатель отключил этот тип уведомлений.
+	ErrRecipientOptedOut = errors.New("recipient has turned this notification type off")
+
 	// ErrRecipientBlocked - получатель заблокировал бота.
 	ErrRecipientBlocked = errors.New("recipient has blocked the bot")
 
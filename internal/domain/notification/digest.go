@@ -0,0 +1,287 @@
+package notification
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// DAILY DIGEST
+// Содержимое и рендеринг ежедневной сводки. Сбор данных остаётся в джобе,
+// а форматирование здесь, чтобы его можно было вызвать для предпросмотра.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Статусы серии в сводке.
+const (
+	DigestStreakMaintained = "maintained"
+	DigestStreakBroken     = "broken"
+	DigestStreakNewRecord  = "new_record"
+)
+
+// DigestContent содержит персональное содержимое ежедневной сводки.
+type DigestContent struct {
+	// Базовая информация
+	StudentName string `json:"student_name"`
+	Date        string `json:"date"`
+
+	// Главное за день, чья сводка не дошла (nil - переносить нечего)
+	CarryOver *DigestCarryOver `json:"carry_over,omitempty"`
+
+	// Прогресс
+	TodayXP        int `json:"today_xp"`
+	TotalXP        int `json:"total_xp"`
+	TasksCompleted int `json:"tasks_completed"`
+	Level          int `json:"level"`
+
+	// Цель на неделю (GoalTargetXP = 0 - цели нет)
+	GoalTargetXP int  `json:"goal_target_xp,omitempty"`
+	GoalWeekXP   int  `json:"goal_week_xp,omitempty"`
+	GoalDaysLeft int  `json:"goal_days_left,omitempty"`
+	GoalReached  bool `json:"goal_reached,omitempty"`
+
+	// Прогноз следующего уровня в текущем темпе (только при цели на неделю;
+	// ForecastLevel = 0 - прогноза нет)
+	ForecastLevel int    `json:"forecast_level,omitempty"`
+	ForecastDate  string `json:"forecast_date,omitempty"`
+
+	// Серия
+	CurrentStreak int    `json:"current_streak"`
+	BestStreak    int    `json:"best_streak"`
+	StreakStatus  string `json:"streak_status"` // "maintained", "broken", "new_record"
+
+	// Лидерборд
+	CurrentRank   int    `json:"current_rank"`
+	RankChange    int    `json:"rank_change"`
+	RankDirection string `json:"rank_direction"` // "up", "down", "same"
+	NeighborAbove string `json:"neighbor_above"` // Имя студента выше
+	NeighborBelow string `json:"neighbor_below"` // Имя студента ниже
+	XPToNextRank  int    `json:"xp_to_next_rank"`
+
+	// Социальное
+	HelpProvided         int `json:"help_provided"`
+	EndorsementsReceived int `json:"endorsements_received"`
+	NewConnections       int `json:"new_connections"`
+
+	// Напарники и подопечные, которые почти у вехи (кнопка "Подбодрить")
+	CheerConnections []DigestCheer `json:"cheer_connections,omitempty"`
+
+	// Сообщество
+	StudentsOnlineNow int    `json:"students_online_now"`
+	CommunityXPToday  int    `json:"community_xp_today"`
+	TopMover          string `json:"top_mover"` // Кто набрал больше всех XP сегодня
+
+	// Мотивация
+	MotivationalQuote string `json:"motivational_quote"`
+	PersonalizedTip   string `json:"personalized_tip"`
+}
+
+// DigestCheer - связь студента, которая почти у вехи.
+type DigestCheer struct {
+	StudentID string `json:"student_id"`
+	Name      string `json:"name"`
+
+	// StreakDays - веха серии в днях; 0 - веха уровня.
+	StreakDays int `json:"streak_days,omitempty"`
+
+	// Level и XPLeft - следующий уровень и сколько XP до него.
+	Level  int `json:"level,omitempty"`
+	XPLeft int `json:"xp_left,omitempty"`
+}
+
+// RenderDigest форматирует сводку в HTML-сообщение для Telegram.
+func RenderDigest(content *DigestContent) string {
+	var sb strings.Builder
+	esc := html.EscapeString
+
+	// Заголовок
+	sb.WriteString(fmt.Sprintf("📊 <b>Твой день, %s</b>\n", esc(content.StudentName)))
+	sb.WriteString(fmt.Sprintf("<i>%s</i>\n\n", esc(content.Date)))
+	sb.WriteString(renderCarryOver(content.CarryOver))
+
+	// Прогресс
+	sb.WriteString("<b>📈 Прогресс</b>\n")
+	if content.TodayXP > 0 {
+		sb.WriteString(fmt.Sprintf("• Сегодня: +%d XP\n", content.TodayXP))
+	} else {
+		sb.WriteString("• Сегодня: пока без XP\n")
+	}
+	sb.WriteString(fmt.Sprintf("• Всего: %d XP (уровень %d)\n", content.TotalXP, content.Level))
+
+	if content.TasksCompleted > 0 {
+		sb.WriteString(fmt.Sprintf("• Задач решено: %d\n", content.TasksCompleted))
+	}
+	sb.WriteString("\n")
+
+	// Цель на неделю
+	if content.GoalTargetXP > 0 {
+		sb.WriteString("<b>🎯 Цель недели</b>\n")
+		sb.WriteString(fmt.Sprintf("• %d / %d XP\n", content.GoalWeekXP, content.GoalTargetXP))
+		if content.GoalReached {
+			sb.WriteString("• Достигнута! ✅\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("• Осталось %d XP, дней: %d\n", content.GoalTargetXP-content.GoalWeekXP, content.GoalDaysLeft))
+		}
+		if content.ForecastLevel > 0 {
+			sb.WriteString(fmt.Sprintf("• В таком темпе уровень %d — к %s\n", content.ForecastLevel, esc(content.ForecastDate)))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Серия
+	if content.CurrentStreak > 0 || content.BestStreak > 0 {
+		sb.WriteString("<b>🔥 Серия</b>\n")
+		switch content.StreakStatus {
+		case DigestStreakNewRecord:
+			sb.WriteString(fmt.Sprintf("• Новый рекорд! %d дней подряд! 🎉\n", content.CurrentStreak))
+		case DigestStreakMaintained:
+			sb.WriteString(fmt.Sprintf("• %d дней подряд (рекорд: %d)\n", content.CurrentStreak, content.BestStreak))
+		case DigestStreakBroken:
+			sb.WriteString("• Серия прервалась :(\n")
+			sb.WriteString(fmt.Sprintf("• Твой рекорд: %d дней — побьём его?\n", content.BestStreak))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Рейтинг
+	if content.CurrentRank > 0 {
+		sb.WriteString("<b>🏆 Рейтинг</b>\n")
+
+		rankEmoji := "➖"
+		if content.RankDirection == "up" {
+			rankEmoji = fmt.Sprintf("🔼+%d", content.RankChange)
+		} else if content.RankDirection == "down" {
+			rankEmoji = fmt.Sprintf("🔽%d", content.RankChange)
+		}
+
+		sb.WriteString(fmt.Sprintf("• Место: #%d %s\n", content.CurrentRank, rankEmoji))
+
+		if content.NeighborAbove != "" && content.XPToNextRank > 0 {
+			sb.WriteString(fmt.Sprintf("• До %s: %d XP\n", esc(content.NeighborAbove), content.XPToNextRank))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Социальное
+	if content.HelpProvided > 0 || content.EndorsementsReceived > 0 {
+		sb.WriteString("<b>🤝 Сообщество</b>\n")
+		if content.HelpProvided > 0 {
+			sb.WriteString(fmt.Sprintf("• Помог %d студентам 👏\n", content.HelpProvided))
+		}
+		if content.EndorsementsReceived > 0 {
+			sb.WriteString(fmt.Sprintf("• Получено благодарностей: %d ⭐\n", content.EndorsementsReceived))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Напарники у цели
+	if len(content.CheerConnections) > 0 {
+		sb.WriteString("<b>👏 Почти у цели</b>\n")
+		for _, c := range content.CheerConnections {
+			if c.StreakDays > 0 {
+				sb.WriteString(fmt.Sprintf("• %s — день до серии в %d дней\n", esc(c.Name), c.StreakDays))
+			} else {
+				sb.WriteString(fmt.Sprintf("• %s — %d XP до %d уровня\n", esc(c.Name), c.XPLeft, c.Level))
+			}
+		}
+		sb.WriteString("<i>Подбодри — это займёт секунду.</i>\n\n")
+	}
+
+	// Сообщество прямо сейчас
+	if content.StudentsOnlineNow > 0 {
+		sb.WriteString("<b>👥 Прямо сейчас</b>\n")
+		sb.WriteString(fmt.Sprintf("• Студентов онлайн: %d\n", content.StudentsOnlineNow))
+		if content.TopMover != "" {
+			sb.WriteString(fmt.Sprintf("• Топ-мувер дня: %s 🚀\n", esc(content.TopMover)))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Мотивация
+	if content.MotivationalQuote != "" {
+		sb.WriteString("─────────────────\n")
+		sb.WriteString(fmt.Sprintf("<i>\"%s\"</i>\n", esc(content.MotivationalQuote)))
+		sb.WriteString("\n")
+	}
+
+	// Персональный совет
+	if content.PersonalizedTip != "" {
+		sb.WriteString(fmt.Sprintf("💡 %s\n", esc(content.PersonalizedTip)))
+	}
+
+	// Подвал
+	sb.WriteString("\n<i>Удачного дня! 🍀</i>")
+
+	return sb.String()
+}
+
+// DigestQuote возвращает мотивационную цитату дня.
+// Цитата зависит только от дня года, поэтому одинакова у всех в этот день.
+func DigestQuote(day time.Time) string {
+	quotes := []string{
+		"Код — это поэзия, которую понимают машины",
+		"Каждый эксперт когда-то был новичком",
+		"Лучший код — это тот, который не нужно писать",
+		"Ошибки — это ступени к мастерству",
+		"Вместе мы можем больше, чем поодиночке",
+		"Сегодняшний баг — завтрашний опыт",
+		"Маленькие шаги приводят к большим результатам",
+		"Помогая другим, мы растём сами",
+		"Учиться никогда не поздно",
+		"Успех — это сумма маленьких усилий",
+	}
+
+	return quotes[day.YearDay()%len(quotes)]
+}
+
+// DigestTip возвращает персональный совет на основе содержимого сводки.
+func DigestTip(content *DigestContent, day time.Time) string {
+	// Сначала советы, зависящие от ситуации студента
+	if content.StreakStatus == DigestStreakBroken {
+		return "Самое время начать новую серию! Даже одна задача сегодня — уже победа."
+	}
+
+	if content.TodayXP == 0 {
+		return "Ещё не поздно добавить XP сегодня. Начни с чего-то простого!"
+	}
+
+	if content.CurrentStreak >= 7 {
+		return "Целая неделя подряд! Ты на правильном пути. Не останавливайся!"
+	}
+
+	if content.RankDirection == "down" && content.RankChange < -5 {
+		return "Не расстраивайся из-за рейтинга — завтра новый день для рывка!"
+	}
+
+	if content.HelpProvided > 0 {
+		return "Спасибо, что помогаешь другим. Это делает сообщество сильнее!"
+	}
+
+	// Общие советы
+	tips := []string{
+		"Попробуй помочь кому-нибудь сегодня — это лучший способ закрепить знания.",
+		"Не забывай делать перерывы. Отдохнувший мозг работает лучше!",
+		"Застрял? Загляни в /help — кто-то точно может подсказать.",
+	}
+
+	return tips[day.YearDay()%len(tips)]
+}
+
+// renderCarryOver форматирует строку о дне, чья сводка не дошла.
+// Пустая строка, если за тот день нечего показать.
+func renderCarryOver(c *DigestCarryOver) string {
+	if c == nil || (c.XP == 0 && c.Tasks == 0) {
+		return ""
+	}
+
+	day := "Вчера"
+	if !c.Yesterday {
+		day = "За " + html.EscapeString(c.Date)
+	}
+	line := fmt.Sprintf("📬 <i>%s: +%d XP", day, c.XP)
+	if c.Tasks > 0 {
+		line += fmt.Sprintf(", задач: %d", c.Tasks)
+	}
+	return line + "</i>\n\n"
+}
@@ -0,0 +1,213 @@
+package notification
+
+import (
+	"errors"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// DIGEST DELIVERY
+// Учёт попыток доставки ежедневной сводки: категория ошибки решает, стоит ли
+// повторять отправку, а не доставленная к вечеру сводка переносит главное
+// за день в заголовок следующей.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Коды ошибок DeliveryResult.ErrorCode, которые выставляют каналы.
+const (
+	ErrorCodeRateLimited  = "RATE_LIMITED"
+	ErrorCodeNetwork      = "NETWORK"
+	ErrorCodeServer       = "SERVER_ERROR"
+	ErrorCodeRejected     = "REJECTED"
+	ErrorCodeBlocked      = "BLOCKED"
+	ErrorCodeChatNotFound = "CHAT_NOT_FOUND"
+
+	// ErrorCodeSkipped - уведомление не отправлялось (NewSkippedResult).
+	ErrorCodeSkipped = "SKIPPED"
+)
+
+// DeliveryErrorCategory - категория неудачной доставки.
+type DeliveryErrorCategory string
+
+const (
+	// DeliveryErrorNone - доставлено.
+	DeliveryErrorNone DeliveryErrorCategory = ""
+
+	// DeliveryErrorNetwork - сеть или таймаут.
+	DeliveryErrorNetwork DeliveryErrorCategory = "network"
+
+	// DeliveryErrorRateLimited - канал ограничил частоту (429).
+	DeliveryErrorRateLimited DeliveryErrorCategory = "rate_limited"
+
+	// DeliveryErrorServer - ошибка на стороне канала (5xx).
+	DeliveryErrorServer DeliveryErrorCategory = "server_error"
+
+	// DeliveryErrorBlocked - получатель заблокировал бота.
+	DeliveryErrorBlocked DeliveryErrorCategory = "blocked"
+
+	// DeliveryErrorChatNotFound - чата больше нет.
+	DeliveryErrorChatNotFound DeliveryErrorCategory = "chat_not_found"
+
+	// DeliveryErrorRejected - канал отклонил сообщение (прочие 4xx).
+	DeliveryErrorRejected DeliveryErrorCategory = "rejected"
+
+	// DeliveryErrorOther - причина неизвестна.
+	DeliveryErrorOther DeliveryErrorCategory = "other"
+)
+
+// RetryableDeliveryErrors - категории, после которых отправку повторяют.
+var RetryableDeliveryErrors = []DeliveryErrorCategory{
+	DeliveryErrorNetwork,
+	DeliveryErrorRateLimited,
+	DeliveryErrorServer,
+}
+
+// Retryable возвращает true, если повторная отправка может пройти.
+func (c DeliveryErrorCategory) Retryable() bool {
+	for _, r := range RetryableDeliveryErrors {
+		if c == r {
+			return true
+		}
+	}
+	return false
+}
+
+// CategorizeDelivery определяет категорию результата доставки. Сначала
+// смотрим на код ошибки канала, затем на известные ошибки домена.
+func CategorizeDelivery(result DeliveryResult) DeliveryErrorCategory {
+	if result.Success {
+		return DeliveryErrorNone
+	}
+
+	switch {
+	case errors.Is(result.Error, ErrRecipientBlocked) || result.ErrorCode == ErrorCodeBlocked:
+		return DeliveryErrorBlocked
+	case errors.Is(result.Error, ErrChatNotFound) || result.ErrorCode == ErrorCodeChatNotFound:
+		return DeliveryErrorChatNotFound
+	case result.ErrorCode == ErrorCodeRateLimited || errors.Is(result.Error, ErrRateLimited):
+		return DeliveryErrorRateLimited
+	case result.ErrorCode == ErrorCodeServer:
+		return DeliveryErrorServer
+	case result.ErrorCode == ErrorCodeNetwork || errors.Is(result.Error, ErrTimeout):
+		return DeliveryErrorNetwork
+	case result.ErrorCode == ErrorCodeRejected:
+		return DeliveryErrorRejected
+	case result.Retryable:
+		// Канал не назвал причину, но считает её временной
+		return DeliveryErrorNetwork
+	default:
+		return DeliveryErrorOther
+	}
+}
+
+// Параметры повторной отправки сводки.
+const (
+	// DigestRetryDelay - пауза перед повторной отправкой.
+	DigestRetryDelay = 30 * time.Minute
+
+	// DigestMaxAttempts - всего попыток на сводку, включая первую.
+	DigestMaxAttempts = 2
+
+	// DigestReconcileHour и DigestReconcileMinute - местное время, после
+	// которого не доставленная сводка переносится в следующую.
+	DigestReconcileHour   = 23
+	DigestReconcileMinute = 30
+)
+
+// DigestHighlights - главное за день из сводки.
+type DigestHighlights struct {
+	XP    int `json:"xp"`
+	Tasks int `json:"tasks"`
+}
+
+// DigestAttempt - доставка сводки одному студенту за один местный день.
+type DigestAttempt struct {
+	StudentID string
+	ChatID    TelegramChatID
+
+	// Date - местная дата студента (полночь в UTC).
+	Date time.Time
+
+	Delivered     bool
+	Attempts      int
+	ErrorCategory DeliveryErrorCategory
+	LastError     string
+	LastAttemptAt time.Time
+
+	// Highlights - главное за день на момент последней попытки.
+	Highlights DigestHighlights
+
+	// CarryOver - сводка не доставлена, её главное ждёт следующей сводки.
+	CarryOver bool
+}
+
+// RetryDue возвращает true, если неудачную сводку пора отправить ещё раз.
+func (a DigestAttempt) RetryDue(now time.Time, delay time.Duration, maxAttempts int) bool {
+	return !a.Delivered &&
+		!a.CarryOver &&
+		a.ErrorCategory.Retryable() &&
+		a.Attempts < maxAttempts &&
+		!now.Before(a.LastAttemptAt.Add(delay))
+}
+
+// ReconcileDue возвращает true, если в часовом поясе loc наступило время
+// сверки дня сводки: после него сводка уже не отправляется.
+func (a DigestAttempt) ReconcileDue(now time.Time, loc *time.Location) bool {
+	if loc == nil {
+		loc = time.UTC
+	}
+	y, m, d := a.Date.Date()
+	deadline := time.Date(y, m, d, DigestReconcileHour, DigestReconcileMinute, 0, 0, loc)
+	return !now.Before(deadline)
+}
+
+// DigestCarryOver - главное за день, когда сводка не дошла; показывается
+// в заголовке следующей сводки.
+type DigestCarryOver struct {
+	// Date - дата не доставленной сводки ("02.01").
+	Date string `json:"date"`
+
+	// Yesterday - сводка была вчерашней.
+	Yesterday bool `json:"yesterday"`
+
+	XP    int `json:"xp"`
+	Tasks int `json:"tasks"`
+}
+
+// NewDigestCarryOver переносит главное из не доставленной сводки в сводку
+// за день today (обе даты - местные, полночь в UTC).
+func NewDigestCarryOver(missed DigestAttempt, today time.Time) *DigestCarryOver {
+	return &DigestCarryOver{
+		Date:      missed.Date.Format("02.01"),
+		Yesterday: missed.Date.Equal(today.AddDate(0, 0, -1)),
+		XP:        missed.Highlights.XP,
+		Tasks:     missed.Highlights.Tasks,
+	}
+}
+
+// DigestDeliverySummary - итоги доставки сводок за период.
+type DigestDeliverySummary struct {
+	// Total - студентов, которым отправляли сводку.
+	Total int
+
+	// FirstPassDelivered - доставлено с первой попытки.
+	FirstPassDelivered int
+
+	// Delivered - доставлено в итоге, с повторами.
+	Delivered int
+}
+
+// FirstPassRate - доля сводок, доставленных с первой попытки.
+func (s DigestDeliverySummary) FirstPassRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.FirstPassDelivered) / float64(s.Total)
+}
+
+// AfterRetryRate - доля сводок, доставленных с учётом повторов.
+func (s DigestDeliverySummary) AfterRetryRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Delivered) / float64(s.Total)
+}
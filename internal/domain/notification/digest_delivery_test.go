@@ -0,0 +1,89 @@
+package notification
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategorizeDelivery(t *testing.T) {
+	tests := []struct {
+		name   string
+		result DeliveryResult
+		want   DeliveryErrorCategory
+	}{
+		{"delivered", NewSuccessResult(ChannelTypeTelegram, "1"), DeliveryErrorNone},
+		{"rate limited", NewRateLimitedResult(ChannelTypeTelegram, time.Second), DeliveryErrorRateLimited},
+		{"server error", DeliveryResult{Error: errors.New("502"), ErrorCode: ErrorCodeServer, Retryable: true}, DeliveryErrorServer},
+		{"network", DeliveryResult{Error: errors.New("connection reset"), ErrorCode: ErrorCodeNetwork, Retryable: true}, DeliveryErrorNetwork},
+		{"timeout", DeliveryResult{Error: ErrTimeout}, DeliveryErrorNetwork},
+		{"blocked", DeliveryResult{Error: ErrRecipientBlocked, ErrorCode: ErrorCodeBlocked}, DeliveryErrorBlocked},
+		{"chat not found", DeliveryResult{Error: ErrChatNotFound}, DeliveryErrorChatNotFound},
+		{"rejected", DeliveryResult{Error: errors.New("400"), ErrorCode: ErrorCodeRejected}, DeliveryErrorRejected},
+		{"unknown", DeliveryResult{Error: errors.New("boom")}, DeliveryErrorOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CategorizeDelivery(tt.result))
+		})
+	}
+
+	assert.True(t, DeliveryErrorRateLimited.Retryable())
+	assert.True(t, DeliveryErrorServer.Retryable())
+	assert.True(t, DeliveryErrorNetwork.Retryable())
+	assert.False(t, DeliveryErrorBlocked.Retryable())
+	assert.False(t, DeliveryErrorChatNotFound.Retryable())
+	assert.False(t, DeliveryErrorRejected.Retryable())
+}
+
+func TestDigestAttempt_RetryDueAndReconcile(t *testing.T) {
+	almaty := time.FixedZone("Asia/Almaty", 5*3600)
+	failedAt := time.Date(2025, 6, 16, 21, 0, 0, 0, almaty)
+	attempt := DigestAttempt{
+		Date:          time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC),
+		Attempts:      1,
+		ErrorCategory: DeliveryErrorServer,
+		LastAttemptAt: failedAt,
+	}
+
+	assert.False(t, attempt.RetryDue(failedAt.Add(29*time.Minute), DigestRetryDelay, DigestMaxAttempts))
+	assert.True(t, attempt.RetryDue(failedAt.Add(30*time.Minute), DigestRetryDelay, DigestMaxAttempts))
+
+	blocked := attempt
+	blocked.ErrorCategory = DeliveryErrorBlocked
+	assert.False(t, blocked.RetryDue(failedAt.Add(time.Hour), DigestRetryDelay, DigestMaxAttempts))
+
+	exhausted := attempt
+	exhausted.Attempts = DigestMaxAttempts
+	assert.False(t, exhausted.RetryDue(failedAt.Add(time.Hour), DigestRetryDelay, DigestMaxAttempts))
+
+	// Сверка - в 23:30 по времени студента
+	assert.False(t, attempt.ReconcileDue(time.Date(2025, 6, 16, 23, 29, 0, 0, almaty), almaty))
+	assert.True(t, attempt.ReconcileDue(time.Date(2025, 6, 16, 23, 30, 0, 0, almaty), almaty))
+}
+
+func TestRenderDigest_CarryOver(t *testing.T) {
+	today := time.Date(2025, 6, 17, 0, 0, 0, 0, time.UTC)
+	missed := DigestAttempt{
+		Date:       time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC),
+		Highlights: DigestHighlights{XP: 300, Tasks: 2},
+	}
+
+	content := &DigestContent{StudentName: "Dana", Date: "17.06.2025", CarryOver: NewDigestCarryOver(missed, today)}
+	text := RenderDigest(content)
+	assert.Contains(t, text, "📬 <i>Вчера: +300 XP, задач: 2</i>")
+	// Строка о вчерашнем дне - сразу под заголовком
+	assert.Less(t, strings.Index(text, "Вчера"), strings.Index(text, "Прогресс"))
+	assert.Empty(t, CheckMessage(text))
+
+	older := NewDigestCarryOver(missed, today.AddDate(0, 0, 2))
+	assert.Contains(t, RenderDigest(&DigestContent{CarryOver: older}), "За 16.06: +300 XP")
+
+	// Пустой день не переносится
+	empty := NewDigestCarryOver(DigestAttempt{Date: missed.Date}, today)
+	assert.NotContains(t, RenderDigest(&DigestContent{CarryOver: empty}), "📬")
+}
@@ -0,0 +1,191 @@
+package notification
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// DRY RUN
+// Режим симуляции worker: задачи работают на настоящих данных, но вместо
+// отправки каждое сообщение записывается в dry_run_outbox. Режим задачи
+// передаётся через context; отправку перехватывает обёртка над клиентом
+// Telegram, поэтому задачам ничего о нём знать не нужно.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// DryRunEffect - побочный эффект, кроме отправки, который можно отключить
+// в режиме симуляции. По умолчанию все эффекты выполняются как обычно.
+type DryRunEffect string
+
+const (
+	// DryRunEffectStatusUpdates - смена статуса студентов (например, inactive).
+	DryRunEffectStatusUpdates DryRunEffect = "status_updates"
+
+	// DryRunEffectCacheRebuilds - перестройка кешей лидерборда.
+	DryRunEffectCacheRebuilds DryRunEffect = "cache_rebuilds"
+
+	// DryRunEffectDeliveryMarkers - отметки "уже отправлено" (дайджест за
+	// день, готовые шарды). Без них настоящий запуск в тот же день
+	// не посчитает сообщения отправленными.
+	DryRunEffectDeliveryMarkers DryRunEffect = "delivery_markers"
+)
+
+// DryRunEffects возвращает все известные эффекты.
+func DryRunEffects() []DryRunEffect {
+	return []DryRunEffect{DryRunEffectStatusUpdates, DryRunEffectCacheRebuilds, DryRunEffectDeliveryMarkers}
+}
+
+// DryRun - режим одного запуска задачи.
+type DryRun struct {
+	// Job - имя задачи.
+	Job string
+
+	// Enabled - вместо отправки сообщения пишутся в outbox.
+	Enabled bool
+
+	// Suppress - эффекты, которые пропускаются при Enabled.
+	Suppress map[DryRunEffect]bool
+
+	recorded atomic.Int64
+}
+
+// Suppresses проверяет, пропускается ли эффект в этом запуске.
+func (d *DryRun) Suppresses(effect DryRunEffect) bool {
+	return d != nil && d.Enabled && d.Suppress[effect]
+}
+
+// CountRecorded учитывает сообщение, записанное вместо отправки.
+func (d *DryRun) CountRecorded() {
+	if d != nil {
+		d.recorded.Add(1)
+	}
+}
+
+// Recorded возвращает число сообщений, записанных вместо отправки.
+func (d *DryRun) Recorded() int64 {
+	if d == nil {
+		return 0
+	}
+	return d.recorded.Load()
+}
+
+// dryRunKey - ключ режима запуска в context.
+type dryRunKey struct{}
+
+// WithDryRun возвращает context запуска задачи с режимом run.
+func WithDryRun(ctx context.Context, run *DryRun) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, run)
+}
+
+// DryRunFrom возвращает режим запуска из context (nil - не запуск задачи).
+func DryRunFrom(ctx context.Context) *DryRun {
+	run, _ := ctx.Value(dryRunKey{}).(*DryRun)
+	return run
+}
+
+// SkipEffect проверяет, нужно ли задаче пропустить эффект в этом запуске.
+func SkipEffect(ctx context.Context, effect DryRunEffect) bool {
+	return DryRunFrom(ctx).Suppresses(effect)
+}
+
+// DryRunPolicy - настройки режима симуляции worker.
+type DryRunPolicy struct {
+	// Global - режим по умолчанию для всех задач и для отправок вне задач
+	// (обработчики событий).
+	Global bool
+
+	// Jobs - переопределения по имени задачи. Ключ подходит и к задачам
+	// с суффиксом: "daily_digest" относится к "daily_digest_shard_03".
+	Jobs map[string]bool
+
+	// Suppress - эффекты, отключаемые в задачах в режиме симуляции.
+	Suppress []DryRunEffect
+}
+
+// Active возвращает true, если хоть одна задача работает в режиме симуляции.
+func (p DryRunPolicy) Active() bool {
+	if p.Global {
+		return true
+	}
+	for _, dry := range p.Jobs {
+		if dry {
+			return true
+		}
+	}
+	return false
+}
+
+// ForJob возвращает режим нового запуска задачи. Из переопределений
+// берётся самое длинное подходящее имя.
+func (p DryRunPolicy) ForJob(job string) *DryRun {
+	enabled := p.Global
+	matched := -1
+	for name, dry := range p.Jobs {
+		if (job == name || strings.HasPrefix(job, name+"_")) && len(name) > matched {
+			enabled, matched = dry, len(name)
+		}
+	}
+
+	run := &DryRun{Job: job, Enabled: enabled, Suppress: make(map[DryRunEffect]bool, len(p.Suppress))}
+	for _, effect := range p.Suppress {
+		run.Suppress[effect] = true
+	}
+	return run
+}
+
+// Enabled проверяет, нужно ли вместо отправки записать сообщение.
+// Вне запуска задачи действует Global.
+func (p DryRunPolicy) Enabled(ctx context.Context) bool {
+	if run := DryRunFrom(ctx); run != nil {
+		return run.Enabled
+	}
+	return p.Global
+}
+
+// DryRunEntry - сообщение, записанное вместо отправки.
+type DryRunEntry struct {
+	ID int64 `json:"id"`
+
+	// RecipientID - студент-получатель (пусто для чатов и отчётов).
+	RecipientID string `json:"recipient_id,omitempty"`
+
+	// ChatID - чат Telegram, куда ушло бы сообщение.
+	ChatID int64 `json:"chat_id"`
+
+	// Type - тип уведомления или вид отправки (document, photo).
+	Type string `json:"type"`
+
+	// Message - готовый текст сообщения (или подпись к файлу).
+	Message string `json:"message"`
+
+	// Job - задача, которая отправляла сообщение (пусто вне задач).
+	Job string `json:"job,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DryRunFilter - отбор записей outbox. Пустые поля не ограничивают.
+type DryRunFilter struct {
+	Job    string
+	Type   string
+	ChatID int64
+	Since  time.Time
+	Until  time.Time
+
+	// Limit - не больше записей (только для List).
+	Limit int
+}
+
+// DryRunOutbox хранит сообщения, записанные вместо отправки.
+type DryRunOutbox interface {
+	// Record сохраняет сообщение.
+	Record(ctx context.Context, entry DryRunEntry) error
+
+	// List возвращает записи по фильтру, новые первыми.
+	List(ctx context.Context, filter DryRunFilter) ([]DryRunEntry, error)
+
+	// Purge удаляет записи по фильтру и возвращает их число.
+	Purge(ctx context.Context, filter DryRunFilter) (int64, error)
+}
@@ -0,0 +1,33 @@
+package notification
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRunPolicy_ForJob(t *testing.T) {
+	policy := DryRunPolicy{
+		Global:   true,
+		Jobs:     map[string]bool{"daily_digest": false, "daily_digest_shard_01": true},
+		Suppress: []DryRunEffect{DryRunEffectCacheRebuilds},
+	}
+
+	assert.True(t, policy.ForJob("rebuild_leaderboard").Enabled, "global applies without an override")
+	assert.False(t, policy.ForJob("daily_digest").Enabled)
+	assert.False(t, policy.ForJob("daily_digest_shard_00").Enabled, "prefix override covers shards")
+	assert.True(t, policy.ForJob("daily_digest_shard_01").Enabled, "the longest override wins")
+	assert.True(t, policy.ForJob("daily_digestive").Enabled, "a prefix must end at an underscore")
+
+	run := policy.ForJob("rebuild_leaderboard")
+	ctx := WithDryRun(context.Background(), run)
+	assert.True(t, SkipEffect(ctx, DryRunEffectCacheRebuilds))
+	assert.False(t, SkipEffect(ctx, DryRunEffectStatusUpdates))
+	assert.False(t, SkipEffect(context.Background(), DryRunEffectCacheRebuilds), "no run - nothing skipped")
+
+	live := WithDryRun(context.Background(), policy.ForJob("daily_digest"))
+	assert.False(t, SkipEffect(live, DryRunEffectCacheRebuilds), "live runs keep every effect")
+	assert.False(t, policy.Enabled(live))
+	assert.True(t, policy.Enabled(context.Background()), "sends outside jobs follow the global flag")
+}
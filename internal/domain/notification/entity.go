@@ -138,6 +138,22 @@ const (
 	// NotificationTypeEndorsementReceived - получена благодарность за помощь.
 	// "⭐ @dana поблагодарил тебя за помощь!"
 	NotificationTypeEndorsementReceived NotificationType = "endorsement_received"
+
+	// NotificationTypeEndorsementReminder - напоминание поблагодарить помощника.
+	// "🙏 @arman помог тебе с graph-01. Поблагодаришь?"
+	NotificationTypeEndorsementReminder NotificationType = "endorsement_reminder"
+
+	// NotificationTypeHelpFeedbackPoll - закрытый опрос о полезности помощи.
+	// "💬 Насколько полезной была помощь с graph-01?"
+	NotificationTypeHelpFeedbackPoll NotificationType = "help_feedback_poll"
+
+	// NotificationTypeGoalReached - достигнута недельная цель.
+	// "🎯 Цель недели выполнена: 520/500 XP!"
+	NotificationTypeGoalReached NotificationType = "goal_reached"
+
+	// NotificationTypePersonalBest - побит личный рекорд.
+	// "🏆 Новый личный рекорд: 820 XP за день!"
+	NotificationTypePersonalBest NotificationType = "personal_best"
 )
 
 // IsValid проверяет, что тип уведомления корректен.
@@ -163,7 +179,11 @@ func (t NotificationType) IsValid() bool {
 		NotificationTypeWelcome,
 		NotificationTypeSystemAlert,
 		NotificationTypeTaskCompleted,
-		NotificationTypeEndorsementReceived:
+		NotificationTypeEndorsementReceived,
+		NotificationTypeEndorsementReminder,
+		NotificationTypeHelpFeedbackPoll,
+		NotificationTypeGoalReached,
+		NotificationTypePersonalBest:
 		return true
 	default:
 		return false
@@ -178,7 +198,8 @@ func (t NotificationType) Category() NotificationCategory {
 		return CategoryRanking
 
 	case NotificationTypeHelpRequest, NotificationTypeHelpOffer,
-		NotificationTypeEndorsementReceived:
+		NotificationTypeEndorsementReceived, NotificationTypeEndorsementReminder,
+		NotificationTypeHelpFeedbackPoll:
 		return CategorySocial
 
 	case NotificationTypeDailyDigest, NotificationTypeWeeklyDigest:
@@ -189,7 +210,8 @@ func (t NotificationType) Category() NotificationCategory {
 		return CategoryMotivation
 
 	case NotificationTypeAchievement, NotificationTypeLevelUp,
-		NotificationTypeTaskCompleted:
+		NotificationTypeTaskCompleted, NotificationTypeGoalReached,
+		NotificationTypePersonalBest:
 		return CategoryProgress
 
 	case NotificationTypeNewNeighbor, NotificationTypeBuddyOnline:
@@ -210,17 +232,19 @@ func (t NotificationType) Category() NotificationCategory {
 func (t NotificationType) DefaultPriority() Priority {
 	switch t {
 	case NotificationTypeWelcome, NotificationTypeAchievement,
-		NotificationTypeEnteredTop, NotificationTypeLevelUp:
+		NotificationTypeEnteredTop, NotificationTypeLevelUp,
+		NotificationTypeGoalReached:
 		return PriorityHigh
 
 	case NotificationTypeRankUp, NotificationTypeRankDown,
 		NotificationTypeHelpRequest, NotificationTypeTaskCompleted,
-		NotificationTypeEndorsementReceived:
+		NotificationTypeEndorsementReceived, NotificationTypePersonalBest:
 		return PriorityNormal
 
 	case NotificationTypeDailyDigest, NotificationTypeWeeklyDigest,
 		NotificationTypeStreakReminder, NotificationTypeEncouragement,
-		NotificationTypeBuddyOnline, NotificationTypeNewNeighbor:
+		NotificationTypeBuddyOnline, NotificationTypeNewNeighbor,
+		NotificationTypeEndorsementReminder, NotificationTypeHelpFeedbackPoll:
 		return PriorityLow
 
 	case NotificationTypeInactivityReminder, NotificationTypeStreakBroken,
@@ -280,6 +304,14 @@ func (t NotificationType) Emoji() string {
 		return "✅"
 	case NotificationTypeEndorsementReceived:
 		return "⭐"
+	case NotificationTypeEndorsementReminder:
+		return "🙏"
+	case NotificationTypeHelpFeedbackPoll:
+		return "💬"
+	case NotificationTypeGoalReached:
+		return "🎯"
+	case NotificationTypePersonalBest:
+		return "🏆"
 	default:
 		return "📬"
 	}
@@ -505,6 +537,13 @@ type Notification struct {
 	// Metadata - произвольные метаданные.
 	Metadata map[string]string
 
+	// ReadAt - время прочтения в центре уведомлений (nil = не прочитано).
+	ReadAt *time.Time
+
+	// SupersededAt - время, когда уведомление было вытеснено более новым
+	// того же типа. Вытесненные не считаются непрочитанными.
+	SupersededAt *time.Time
+
 	// CreatedAt - время создания.
 	CreatedAt time.Time
 
@@ -772,6 +811,27 @@ func (n *Notification) IsReadyToSend() bool {
 	return true
 }
 
+// IsUnread возвращает true, если уведомление не прочитано и не вытеснено.
+func (n *Notification) IsUnread() bool {
+	return n.ReadAt == nil && n.SupersededAt == nil
+}
+
+// IsInInbox возвращает true, если уведомление показывается в центре уведомлений.
+// Туда попадают доставленные и пропущенные (например, в тихие часы) уведомления.
+func (n *Notification) IsInInbox() bool {
+	return n.Status == StatusDelivered || n.Status == StatusSkipped
+}
+
+// MarkRead помечает уведомление как прочитанное.
+func (n *Notification) MarkRead() {
+	if n.ReadAt != nil {
+		return
+	}
+	now := time.Now().UTC()
+	n.ReadAt = &now
+	n.UpdatedAt = now
+}
+
 // SetMetadata устанавливает значение метаданных.
 func (n *Notification) SetMetadata(key, value string) {
 	if n.Metadata == nil {
@@ -815,6 +875,14 @@ func (n *Notification) Clone() *Notification {
 		t := *n.ExpiresAt
 		clone.ExpiresAt = &t
 	}
+	if n.ReadAt != nil {
+		t := *n.ReadAt
+		clone.ReadAt = &t
+	}
+	if n.SupersededAt != nil {
+		t := *n.SupersededAt
+		clone.SupersededAt = &t
+	}
 
 	// Копируем map
 	if n.Metadata != nil {
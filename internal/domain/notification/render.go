@@ -0,0 +1,280 @@
+package notification
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"unicode/utf16"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MESSAGE RENDERER
+// Рендеринг текста уведомлений вынесен из путей отправки, чтобы одним и тем же
+// кодом пользовались и доставка, и предпросмотр для администраторов.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// TelegramMessageLimit - максимальная длина текста сообщения в Telegram
+// (в UTF-16 символах после разбора разметки).
+const TelegramMessageLimit = 4096
+
+// Шаблоны сообщений предопределённых правил.
+const (
+	RankUpTemplate         = "🚀 Ты поднялся на {{.RankChange}} мест! Теперь ты #{{.NewRank}}"
+	InactivityTemplate     = "👋 Давно тебя не видели! Уже {{.DaysInactive}} дней без задач. Возвращайся!"
+	StreakReminderTemplate = "🔥 Не потеряй свою серию в {{.StreakDays}} дней! Осталось {{.HoursRemaining}} часов"
+	DailyDigestTemplate    = "📊 Твой день: +{{.XPGained}} XP, {{.TasksCompleted}} задач, #{{.NewRank}} в рейтинге"
+)
+
+// defaultTemplates - шаблоны по умолчанию для типов уведомлений.
+var defaultTemplates = map[NotificationType]string{
+	NotificationTypeRankUp:             RankUpTemplate,
+	NotificationTypeInactivityReminder: InactivityTemplate,
+	NotificationTypeStreakReminder:     StreakReminderTemplate,
+	NotificationTypeDailyDigest:        DailyDigestTemplate,
+}
+
+// DefaultTemplate возвращает шаблон по умолчанию для типа уведомления.
+func DefaultTemplate(t NotificationType) (string, bool) {
+	tmpl, ok := defaultTemplates[t]
+	return tmpl, ok
+}
+
+// Варианты формулировок для экспериментов (см. пакет experiment).
+const (
+	// VariantEncouraging - поддерживающая формулировка: акцент на усилиях.
+	VariantEncouraging = "encouraging"
+
+	// VariantCompetitive - соревновательная формулировка: акцент на соперниках.
+	VariantCompetitive = "competitive"
+)
+
+// variantTemplates - шаблоны вариантов по типу уведомления и имени варианта.
+// RankChange в шаблонах - модуль изменения.
+var variantTemplates = map[NotificationType]map[string]string{
+	NotificationTypeRankUp: {
+		VariantEncouraging: "🚀 Твоя работа видна: +{{.RankChange}} мест, теперь ты #{{.NewRank}}. Так держать!",
+		VariantCompetitive: "🚀 Ты обошёл {{.RankChange}} соперников и теперь #{{.NewRank}}. Кто следующий?",
+	},
+	NotificationTypeRankDown: {
+		VariantEncouraging: "⚡ Позиция изменилась: теперь #{{.NewRank}}. Каждая задача — шаг вперёд, нужна помощь — /help",
+		VariantCompetitive: "⚡ Тебя обошли на {{.RankChange}} мест — теперь ты #{{.NewRank}}. Время отыграться!",
+	},
+}
+
+// VariantTemplate возвращает шаблон варианта эксперимента для типа уведомления.
+func VariantTemplate(t NotificationType, variant string) (string, bool) {
+	tmpl, ok := variantTemplates[t][variant]
+	return tmpl, ok
+}
+
+// Renderer рендерит шаблоны уведомлений в HTML для Telegram.
+// Строковые значения из NotificationData экранируются, поэтому имя студента
+// с "<" или "&" не ломает разметку сообщения.
+// Реализует MessageFormatter.
+type Renderer struct{}
+
+// NewRenderer создаёт новый рендерер.
+func NewRenderer() *Renderer {
+	return &Renderer{}
+}
+
+// Format форматирует сообщение из шаблона и данных.
+func (r *Renderer) Format(tmpl string, data NotificationData) (string, error) {
+	t, err := parseTemplate(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err := t.Execute(&sb, escapeData(data)); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrTemplateError, err)
+	}
+	return sb.String(), nil
+}
+
+// FormatTitle форматирует заголовок.
+func (r *Renderer) FormatTitle(tmpl string, data NotificationData) (string, error) {
+	return r.Format(tmpl, data)
+}
+
+// SupportedFormat возвращает поддерживаемый формат.
+func (r *Renderer) SupportedFormat() string {
+	return "HTML"
+}
+
+// parseTemplate разбирает шаблон сообщения.
+func parseTemplate(tmpl string) (*template.Template, error) {
+	t, err := template.New("notification").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTemplateError, err)
+	}
+	return t, nil
+}
+
+// escapeData возвращает копию данных с HTML-экранированными строками.
+func escapeData(data NotificationData) NotificationData {
+	v := reflect.ValueOf(&data).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); f.Kind() == reflect.String {
+			f.SetString(html.EscapeString(f.String()))
+		}
+	}
+	return data
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// TEMPLATE INSPECTION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// TemplateFields возвращает имена полей NotificationData, доступных в шаблонах.
+func TemplateFields() []string {
+	t := reflect.TypeOf(NotificationData{})
+	fields := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		fields = append(fields, t.Field(i).Name)
+	}
+	return fields
+}
+
+// UnknownTemplateFields возвращает поля, на которые ссылается шаблон,
+// но которых нет в NotificationData. Такие шаблоны падают при отправке.
+func UnknownTemplateFields(tmpl string) ([]string, error) {
+	t, err := parseTemplate(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool)
+	for _, f := range TemplateFields() {
+		known[f] = true
+	}
+
+	unknown := make(map[string]bool)
+	var walk func(node parse.Node, nested bool)
+	walk = func(node parse.Node, nested bool) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child, nested)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe, nested)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				for _, arg := range cmd.Args {
+					walk(arg, nested)
+				}
+			}
+		case *parse.IfNode:
+			walk(n.Pipe, nested)
+			walk(n.List, nested)
+			walk(n.ElseList, nested)
+		case *parse.WithNode:
+			// Внутри with/range точка указывает на другое значение
+			walk(n.Pipe, nested)
+			walk(n.List, true)
+			walk(n.ElseList, nested)
+		case *parse.RangeNode:
+			walk(n.Pipe, nested)
+			walk(n.List, true)
+			walk(n.ElseList, nested)
+		case *parse.FieldNode:
+			if !nested && !known[n.Ident[0]] {
+				unknown[n.Ident[0]] = true
+			}
+		case *parse.VariableNode:
+			if len(n.Ident) > 1 && n.Ident[0] == "$" && !known[n.Ident[1]] {
+				unknown[n.Ident[1]] = true
+			}
+		}
+	}
+	walk(t.Tree.Root, false)
+
+	result := make([]string, 0, len(unknown))
+	for f := range unknown {
+		result = append(result, f)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MESSAGE VALIDATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Коды предупреждений валидации сообщения.
+const (
+	WarningUnknownField   = "unknown_field"
+	WarningTooLong        = "too_long"
+	WarningUnbalancedHTML = "unbalanced_html"
+)
+
+// RenderWarning - предупреждение о проблеме в отрендеренном сообщении.
+type RenderWarning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// htmlTagPattern находит открывающие и закрывающие HTML-теги.
+var htmlTagPattern = regexp.MustCompile(`<(/?)([a-zA-Z][a-zA-Z0-9-]*)[^<>]*>`)
+
+// MessageLength возвращает длину сообщения так, как её считает Telegram:
+// в UTF-16 символах без HTML-тегов и с раскрытыми сущностями.
+func MessageLength(text string) int {
+	plain := html.UnescapeString(htmlTagPattern.ReplaceAllString(text, ""))
+	return len(utf16.Encode([]rune(plain)))
+}
+
+// CheckMessage проверяет отрендеренное HTML-сообщение на ошибки,
+// из-за которых Telegram отклонит его или покажет неправильно.
+func CheckMessage(text string) []RenderWarning {
+	var warnings []RenderWarning
+
+	if n := MessageLength(text); n > TelegramMessageLimit {
+		warnings = append(warnings, RenderWarning{
+			Code:    WarningTooLong,
+			Message: fmt.Sprintf("message is %d characters, Telegram limit is %d", n, TelegramMessageLimit),
+		})
+	}
+
+	for _, problem := range unbalancedTags(text) {
+		warnings = append(warnings, RenderWarning{Code: WarningUnbalancedHTML, Message: problem})
+	}
+
+	return warnings
+}
+
+// unbalancedTags возвращает описания незакрытых и лишних тегов.
+func unbalancedTags(text string) []string {
+	var problems []string
+	var stack []string
+
+	for _, m := range htmlTagPattern.FindAllStringSubmatch(text, -1) {
+		closing, tag := m[1] == "/", strings.ToLower(m[2])
+		if !closing {
+			stack = append(stack, tag)
+			continue
+		}
+		if len(stack) == 0 || stack[len(stack)-1] != tag {
+			problems = append(problems, fmt.Sprintf("unexpected closing tag </%s>", tag))
+			continue
+		}
+		stack = stack[:len(stack)-1]
+	}
+
+	for _, tag := range stack {
+		problems = append(problems, fmt.Sprintf("tag <%s> is not closed", tag))
+	}
+	return problems
+}
@@ -0,0 +1,89 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderer_FormatEscapesData(t *testing.T) {
+	r := NewRenderer()
+
+	text, err := r.Format("<b>{{.HelperName}}</b> помог тебе с {{.TaskName}}", NotificationData{
+		HelperName: "Tom & <Jerry>",
+		TaskName:   "go-reloaded",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "<b>Tom &amp; &lt;Jerry&gt;</b> помог тебе с go-reloaded", text)
+	assert.Empty(t, CheckMessage(text))
+}
+
+func TestUnknownTemplateFields(t *testing.T) {
+	unknown, err := UnknownTemplateFields(`{{.NewRank}} {{.Rnak}} {{if .Streak}}{{.StreakDays}}{{end}} {{$.Nope}}`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Nope", "Rnak", "Streak"}, unknown)
+
+	for _, tmpl := range []string{RankUpTemplate, InactivityTemplate, StreakReminderTemplate, DailyDigestTemplate} {
+		unknown, err := UnknownTemplateFields(tmpl)
+		require.NoError(t, err)
+		assert.Empty(t, unknown, tmpl)
+	}
+
+	for _, variants := range variantTemplates {
+		for _, tmpl := range variants {
+			unknown, err := UnknownTemplateFields(tmpl)
+			require.NoError(t, err)
+			assert.Empty(t, unknown, tmpl)
+		}
+	}
+
+	_, err = UnknownTemplateFields("{{.NewRank")
+	assert.ErrorIs(t, err, ErrTemplateError)
+}
+
+func TestCheckMessage(t *testing.T) {
+	warnings := CheckMessage("<b>жирный <i>курсив</b></i> <code>x")
+	codes := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		codes = append(codes, w.Code)
+	}
+	assert.Equal(t, []string{WarningUnbalancedHTML, WarningUnbalancedHTML, WarningUnbalancedHTML}, codes)
+
+	// Теги и сущности не считаются в длину, эмодзи вне BMP занимают два символа
+	assert.Equal(t, 3, MessageLength("<b>&amp;</b>🚀"))
+
+	long := "<b>" + strings.Repeat("я", TelegramMessageLimit) + "</b>"
+	assert.Empty(t, CheckMessage(long))
+	warnings = CheckMessage(long + "!")
+	require.Len(t, warnings, 1)
+	assert.Equal(t, WarningTooLong, warnings[0].Code)
+}
+
+func TestRenderDigest_IsBalancedHTML(t *testing.T) {
+	content := &DigestContent{
+		StudentName:       "<script>",
+		Date:              "16.06.2025",
+		TodayXP:           120,
+		TotalXP:           4200,
+		Level:             7,
+		CurrentStreak:     8,
+		BestStreak:        8,
+		StreakStatus:      DigestStreakMaintained,
+		CurrentRank:       12,
+		RankChange:        3,
+		RankDirection:     "up",
+		NeighborAbove:     "Айдана",
+		XPToNextRank:      40,
+		StudentsOnlineNow: 31,
+		MotivationalQuote: "Каждый эксперт когда-то был новичком",
+	}
+
+	text := RenderDigest(content)
+
+	assert.Contains(t, text, "📊 <b>Твой день, &lt;script&gt;</b>")
+	assert.Contains(t, text, "• Место: #12 🔼+3")
+	assert.Empty(t, CheckMessage(text))
+}
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -407,7 +410,7 @@ func NewTimeConstraint(hoursStart, hoursEnd int, timezone string) (*TimeConstrai
 func (tc *TimeConstraint) IsAllowed(t time.Time) bool {
 	// Конвертируем в нужный часовой пояс
 	if tc.Timezone != "" {
-		loc, err := time.LoadLocation(tc.Timezone)
+		loc, err := timeutil.LoadLocation(tc.Timezone)
 		if err == nil {
 			t = t.In(loc)
 		}
@@ -564,6 +567,9 @@ type TriggerRule struct {
 
 	// UpdatedAt - время обновления.
 	UpdatedAt time.Time
+
+	// clock - источник текущего времени; nil - системные часы.
+	clock shared.Clock
 }
 
 // NewTriggerRuleParams содержит параметры для создания правила.
@@ -573,6 +579,9 @@ type NewTriggerRuleParams struct {
 	NotificationType NotificationType
 	MessageTemplate  string
 	Priority         *Priority
+
+	// Clock - источник текущего времени (nil - системные часы).
+	Clock shared.Clock
 }
 
 // NewTriggerRule создаёт новое правило с валидацией.
@@ -595,7 +604,7 @@ func NewTriggerRule(params NewTriggerRuleParams) (*TriggerRule, error) {
 		priority = *params.Priority
 	}
 
-	now := time.Now().UTC()
+	now := shared.ClockOrSystem(params.Clock).Now()
 
 	return &TriggerRule{
 		ID:                  params.ID,
@@ -612,9 +621,21 @@ func NewTriggerRule(params NewTriggerRuleParams) (*TriggerRule, error) {
 		Metadata:            make(map[string]string),
 		CreatedAt:           now,
 		UpdatedAt:           now,
+		clock:               params.Clock,
 	}, nil
 }
 
+// WithClock задаёт часы для проверки cooldown и временных ограничений.
+func (tr *TriggerRule) WithClock(clock shared.Clock) *TriggerRule {
+	tr.clock = clock
+	return tr
+}
+
+// now возвращает текущее время по часам правила.
+func (tr *TriggerRule) now() time.Time {
+	return shared.ClockOrSystem(tr.clock).Now()
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // DOMAIN METHODS
 // ══════════════════════════════════════════════════════════════════════════════
@@ -625,7 +646,7 @@ func (tr *TriggerRule) AddCondition(condition *Condition) error {
 		return ErrNilCondition
 	}
 	tr.Conditions = append(tr.Conditions, condition)
-	tr.UpdatedAt = time.Now().UTC()
+	tr.UpdatedAt = tr.now()
 	return nil
 }
 
@@ -635,51 +656,51 @@ func (tr *TriggerRule) RemoveCondition(index int) error {
 		return ErrConditionIndexOutOfRange
 	}
 	tr.Conditions = append(tr.Conditions[:index], tr.Conditions[index+1:]...)
-	tr.UpdatedAt = time.Now().UTC()
+	tr.UpdatedAt = tr.now()
 	return nil
 }
 
 // SetTimeConstraint устанавливает временные ограничения.
 func (tr *TriggerRule) SetTimeConstraint(tc *TimeConstraint) {
 	tr.TimeConstraint = tc
-	tr.UpdatedAt = time.Now().UTC()
+	tr.UpdatedAt = tr.now()
 }
 
 // SetRateLimit устанавливает ограничение частоты.
 func (tr *TriggerRule) SetRateLimit(rl *RateLimit) {
 	tr.RateLimit = rl
-	tr.UpdatedAt = time.Now().UTC()
+	tr.UpdatedAt = tr.now()
 }
 
 // Enable активирует правило.
 func (tr *TriggerRule) Enable() {
 	tr.IsEnabled = true
-	tr.UpdatedAt = time.Now().UTC()
+	tr.UpdatedAt = tr.now()
 }
 
 // Disable деактивирует правило.
 func (tr *TriggerRule) Disable() {
 	tr.IsEnabled = false
-	tr.UpdatedAt = time.Now().UTC()
+	tr.UpdatedAt = tr.now()
 }
 
 // SetCooldown устанавливает период охлаждения.
 func (tr *TriggerRule) SetCooldown(duration time.Duration) {
 	tr.CooldownPeriod = duration
-	tr.UpdatedAt = time.Now().UTC()
+	tr.UpdatedAt = tr.now()
 }
 
 // SetExpiration устанавливает время устаревания уведомлений.
 func (tr *TriggerRule) SetExpiration(duration time.Duration) {
 	tr.ExpiresAfter = duration
-	tr.UpdatedAt = time.Now().UTC()
+	tr.UpdatedAt = tr.now()
 }
 
 // RequireConsent устанавливает требование согласия пользователя.
 func (tr *TriggerRule) RequireConsent(settingKey string) {
 	tr.RequiresUserConsent = true
 	tr.ConsentSettingKey = settingKey
-	tr.UpdatedAt = time.Now().UTC()
+	tr.UpdatedAt = tr.now()
 }
 
 // AddTag добавляет тег.
@@ -690,7 +711,7 @@ func (tr *TriggerRule) AddTag(tag string) {
 		}
 	}
 	tr.Tags = append(tr.Tags, tag)
-	tr.UpdatedAt = time.Now().UTC()
+	tr.UpdatedAt = tr.now()
 }
 
 // HasTag проверяет наличие тега.
@@ -709,7 +730,7 @@ func (tr *TriggerRule) SetMetadata(key, value string) {
 		tr.Metadata = make(map[string]string)
 	}
 	tr.Metadata[key] = value
-	tr.UpdatedAt = time.Now().UTC()
+	tr.UpdatedAt = tr.now()
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -724,7 +745,8 @@ type TriggerContext struct {
 	// TelegramChatID - ID чата Telegram.
 	TelegramChatID TelegramChatID
 
-	// Timestamp - время события.
+	// Timestamp - время события. Окно отправки и cooldown проверяются
+	// по часам правила, а не по этому полю.
 	Timestamp time.Time
 
 	// Values - значения для проверки условий (ключ = тип условия).
@@ -805,8 +827,10 @@ func (tr *TriggerRule) Evaluate(ctx *TriggerContext) EvaluationResult {
 		}
 	}
 
+	now := tr.now()
+
 	// Проверяем временные ограничения
-	if tr.TimeConstraint != nil && !tr.TimeConstraint.IsAllowed(ctx.Timestamp) {
+	if tr.TimeConstraint != nil && !tr.TimeConstraint.IsAllowed(now) {
 		return EvaluationResult{
 			ShouldTrigger: false,
 			Reason:        "outside allowed time window",
@@ -815,7 +839,7 @@ func (tr *TriggerRule) Evaluate(ctx *TriggerContext) EvaluationResult {
 
 	// Проверяем cooldown
 	if tr.CooldownPeriod > 0 && ctx.LastTriggeredAt != nil {
-		if time.Since(*ctx.LastTriggeredAt) < tr.CooldownPeriod {
+		if now.Sub(*ctx.LastTriggeredAt) < tr.CooldownPeriod {
 			return EvaluationResult{
 				ShouldTrigger: false,
 				Reason:        "cooldown period not elapsed",
@@ -912,7 +936,7 @@ func NewRankUpRule(id TriggerRuleID, minPositions int) (*TriggerRule, error) {
 		ID:               id,
 		Name:             "Rank Up Notification",
 		NotificationType: NotificationTypeRankUp,
-		MessageTemplate:  "🚀 Ты поднялся на {{.RankChange}} мест! Теперь ты #{{.NewRank}}",
+		MessageTemplate:  RankUpTemplate,
 	})
 	if err != nil {
 		return nil, err
@@ -931,7 +955,7 @@ func NewInactivityRule(id TriggerRuleID, days int) (*TriggerRule, error) {
 		ID:               id,
 		Name:             "Inactivity Reminder",
 		NotificationType: NotificationTypeInactivityReminder,
-		MessageTemplate:  "👋 Давно тебя не видели! Уже {{.DaysInactive}} дней без задач. Возвращайся!",
+		MessageTemplate:  InactivityTemplate,
 	})
 	if err != nil {
 		return nil, err
@@ -952,7 +976,7 @@ func NewStreakReminderRule(id TriggerRuleID, hoursRemaining int) (*TriggerRule,
 		ID:               id,
 		Name:             "Streak At Risk Reminder",
 		NotificationType: NotificationTypeStreakReminder,
-		MessageTemplate:  "🔥 Не потеряй свою серию в {{.StreakDays}} дней! Осталось {{.HoursRemaining}} часов",
+		MessageTemplate:  StreakReminderTemplate,
 	})
 	if err != nil {
 		return nil, err
@@ -972,7 +996,7 @@ func NewDailyDigestRule(id TriggerRuleID, hour int, timezone string) (*TriggerRu
 		ID:               id,
 		Name:             "Daily Digest",
 		NotificationType: NotificationTypeDailyDigest,
-		MessageTemplate:  "📊 Твой день: +{{.XPGained}} XP, {{.TasksCompleted}} задач, #{{.NewRank}} в рейтинге",
+		MessageTemplate:  DailyDigestTemplate,
 	})
 	if err != nil {
 		return nil, err
@@ -1029,4 +1053,13 @@ var (
 
 	// ErrTriggerRuleNotFound - правило не найдено.
 	ErrTriggerRuleNotFound = errors.New("trigger rule not found")
+
+	// ErrInvalidDayOfWeek - день недели вне 0-6.
+	ErrInvalidDayOfWeek = errors.New("invalid day of week: must be 0-6")
+
+	// ErrEmptyConsentKey - правило требует согласия, но ключ настройки не задан.
+	ErrEmptyConsentKey = errors.New("consent setting key cannot be empty")
+
+	// ErrNegativeDuration - отрицательный cooldown или срок жизни.
+	ErrNegativeDuration = errors.New("duration cannot be negative")
 )
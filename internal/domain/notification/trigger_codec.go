@@ -0,0 +1,419 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// TRIGGER RULE DOCUMENT
+// Хранимый формат TriggerRule (JSONB trigger_rules.rule) и тело запросов
+// админского API. Правило хранится целиком: условия, окно отправки,
+// rate limit, cooldown, шаблоны и теги. Длительности - строки Go
+// ("24h0m0s"), приоритет - имя ("normal").
+// ══════════════════════════════════════════════════════════════════════════════
+
+// TriggerRuleSchemaVersion - текущая версия формата правила.
+const TriggerRuleSchemaVersion = 1
+
+// TriggerRuleDocument - правило в хранимом формате.
+type TriggerRuleDocument struct {
+	Version             int                     `json:"v"`
+	ID                  string                  `json:"id"`
+	Name                string                  `json:"name"`
+	Description         string                  `json:"description,omitempty"`
+	NotificationType    string                  `json:"notification_type"`
+	Conditions          []ConditionDocument     `json:"conditions"`
+	TimeConstraint      *TimeConstraintDocument `json:"time_constraint,omitempty"`
+	RateLimit           *RateLimitDocument      `json:"rate_limit,omitempty"`
+	Priority            string                  `json:"priority"`
+	MessageTemplate     string                  `json:"message_template"`
+	TitleTemplate       string                  `json:"title_template,omitempty"`
+	Enabled             bool                    `json:"enabled"`
+	RequiresUserConsent bool                    `json:"requires_user_consent,omitempty"`
+	ConsentSettingKey   string                  `json:"consent_setting_key,omitempty"`
+	Cooldown            string                  `json:"cooldown,omitempty"`
+	ExpiresAfter        string                  `json:"expires_after,omitempty"`
+	Tags                []string                `json:"tags,omitempty"`
+	Metadata            map[string]string       `json:"metadata,omitempty"`
+	CreatedAt           time.Time               `json:"created_at"`
+	UpdatedAt           time.Time               `json:"updated_at"`
+}
+
+// ConditionDocument - условие в хранимом формате.
+type ConditionDocument struct {
+	Type        string `json:"type"`
+	Operator    string `json:"operator"`
+	Value       int    `json:"value,omitempty"`
+	MinValue    int    `json:"min,omitempty"`
+	MaxValue    int    `json:"max,omitempty"`
+	ListValues  []int  `json:"values,omitempty"`
+	StringValue string `json:"string_value,omitempty"`
+	Negate      bool   `json:"negate,omitempty"`
+}
+
+// TimeConstraintDocument - окно отправки в хранимом формате.
+type TimeConstraintDocument struct {
+	DaysOfWeek        []int  `json:"days_of_week,omitempty"`
+	HoursStart        int    `json:"hours_start"`
+	HoursEnd          int    `json:"hours_end"`
+	Timezone          string `json:"timezone,omitempty"`
+	ExcludeQuietHours bool   `json:"exclude_quiet_hours,omitempty"`
+}
+
+// RateLimitDocument - ограничение частоты в хранимом формате.
+type RateLimitDocument struct {
+	MaxCount     int    `json:"max_count"`
+	Period       string `json:"period"`
+	PerRecipient bool   `json:"per_recipient,omitempty"`
+	BurstAllowed bool   `json:"burst_allowed,omitempty"`
+}
+
+// Document возвращает правило в хранимом формате.
+func (tr *TriggerRule) Document() TriggerRuleDocument {
+	doc := TriggerRuleDocument{
+		Version:             TriggerRuleSchemaVersion,
+		ID:                  string(tr.ID),
+		Name:                tr.Name,
+		Description:         tr.Description,
+		NotificationType:    string(tr.NotificationType),
+		Conditions:          make([]ConditionDocument, 0, len(tr.Conditions)),
+		Priority:            tr.Priority.String(),
+		MessageTemplate:     tr.MessageTemplate,
+		TitleTemplate:       tr.TitleTemplate,
+		Enabled:             tr.IsEnabled,
+		RequiresUserConsent: tr.RequiresUserConsent,
+		ConsentSettingKey:   tr.ConsentSettingKey,
+		Cooldown:            formatRuleDuration(tr.CooldownPeriod),
+		ExpiresAfter:        formatRuleDuration(tr.ExpiresAfter),
+		Tags:                slices.Clone(tr.Tags),
+		CreatedAt:           tr.CreatedAt,
+		UpdatedAt:           tr.UpdatedAt,
+	}
+
+	for _, c := range tr.Conditions {
+		if c == nil {
+			continue
+		}
+		doc.Conditions = append(doc.Conditions, ConditionDocument{
+			Type:        string(c.Type),
+			Operator:    string(c.Operator),
+			Value:       c.Value,
+			MinValue:    c.MinValue,
+			MaxValue:    c.MaxValue,
+			ListValues:  slices.Clone(c.ListValues),
+			StringValue: c.StringValue,
+			Negate:      c.Negate,
+		})
+	}
+
+	if tc := tr.TimeConstraint; tc != nil {
+		doc.TimeConstraint = &TimeConstraintDocument{
+			DaysOfWeek:        slices.Clone(tc.DaysOfWeek),
+			HoursStart:        tc.HoursStart,
+			HoursEnd:          tc.HoursEnd,
+			Timezone:          tc.Timezone,
+			ExcludeQuietHours: tc.ExcludeQuietHours,
+		}
+	}
+
+	if rl := tr.RateLimit; rl != nil {
+		doc.RateLimit = &RateLimitDocument{
+			MaxCount:     rl.MaxCount,
+			Period:       formatRuleDuration(rl.Period),
+			PerRecipient: rl.PerRecipient,
+			BurstAllowed: rl.BurstAllowed,
+		}
+	}
+
+	if len(tr.Metadata) > 0 {
+		doc.Metadata = make(map[string]string, len(tr.Metadata))
+		for k, v := range tr.Metadata {
+			doc.Metadata[k] = v
+		}
+	}
+
+	return doc
+}
+
+// Rule собирает правило из документа и проверяет его (см. TriggerRule.Validate).
+func (d TriggerRuleDocument) Rule() (*TriggerRule, error) {
+	invalid := func(format string, args ...any) error {
+		return shared.NewDomainError("notification", "DecodeTriggerRule", shared.ErrInvalidInput,
+			fmt.Sprintf(format, args...))
+	}
+
+	if d.Version != TriggerRuleSchemaVersion {
+		return nil, invalid("unsupported rule version %d", d.Version)
+	}
+
+	priority, ok := parsePriority(d.Priority)
+	if !ok {
+		return nil, invalid("unknown priority %q", d.Priority)
+	}
+	cooldown, err := parseRuleDuration(d.Cooldown)
+	if err != nil {
+		return nil, invalid("cooldown: %v", err)
+	}
+	expiresAfter, err := parseRuleDuration(d.ExpiresAfter)
+	if err != nil {
+		return nil, invalid("expires_after: %v", err)
+	}
+
+	rule := &TriggerRule{
+		ID:                  TriggerRuleID(d.ID),
+		Name:                d.Name,
+		Description:         d.Description,
+		NotificationType:    NotificationType(d.NotificationType),
+		Conditions:          make([]*Condition, 0, len(d.Conditions)),
+		Priority:            priority,
+		MessageTemplate:     d.MessageTemplate,
+		TitleTemplate:       d.TitleTemplate,
+		IsEnabled:           d.Enabled,
+		RequiresUserConsent: d.RequiresUserConsent,
+		ConsentSettingKey:   d.ConsentSettingKey,
+		CooldownPeriod:      cooldown,
+		ExpiresAfter:        expiresAfter,
+		Tags:                make([]string, 0, len(d.Tags)),
+		Metadata:            make(map[string]string, len(d.Metadata)),
+		CreatedAt:           d.CreatedAt,
+		UpdatedAt:           d.UpdatedAt,
+	}
+	rule.Tags = append(rule.Tags, d.Tags...)
+	for k, v := range d.Metadata {
+		rule.Metadata[k] = v
+	}
+
+	for _, c := range d.Conditions {
+		rule.Conditions = append(rule.Conditions, &Condition{
+			Type:        ConditionType(c.Type),
+			Operator:    ComparisonOperator(c.Operator),
+			Value:       c.Value,
+			MinValue:    c.MinValue,
+			MaxValue:    c.MaxValue,
+			ListValues:  slices.Clone(c.ListValues),
+			StringValue: c.StringValue,
+			Negate:      c.Negate,
+		})
+	}
+
+	if tc := d.TimeConstraint; tc != nil {
+		rule.TimeConstraint = &TimeConstraint{
+			DaysOfWeek:        slices.Clone(tc.DaysOfWeek),
+			HoursStart:        tc.HoursStart,
+			HoursEnd:          tc.HoursEnd,
+			Timezone:          tc.Timezone,
+			ExcludeQuietHours: tc.ExcludeQuietHours,
+		}
+	}
+
+	if rl := d.RateLimit; rl != nil {
+		period, err := parseRuleDuration(rl.Period)
+		if err != nil {
+			return nil, invalid("rate_limit.period: %v", err)
+		}
+		rule.RateLimit = &RateLimit{
+			MaxCount:     rl.MaxCount,
+			Period:       period,
+			PerRecipient: rl.PerRecipient,
+			BurstAllowed: rl.BurstAllowed,
+		}
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// EncodeTriggerRule сериализует правило в хранимый формат.
+func EncodeTriggerRule(rule *TriggerRule) ([]byte, error) {
+	return json.Marshal(rule.Document())
+}
+
+// DecodeTriggerRule разбирает и проверяет хранимое правило.
+// Неизвестные поля - ошибка: правило не должно молча терять настройки.
+func DecodeTriggerRule(data []byte) (*TriggerRule, error) {
+	var doc TriggerRuleDocument
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, shared.WrapError("notification", "DecodeTriggerRule", shared.ErrInvalidInput,
+			"malformed rule", err)
+	}
+	return doc.Rule()
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// VALIDATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Validate проверяет правило целиком: типы условий и операторы, диапазоны,
+// окно отправки, rate limit и поля шаблонов. Правило, прошедшее проверку,
+// можно сохранить и вычислять без сюрпризов при отправке.
+func (tr *TriggerRule) Validate() error {
+	invalid := func(err error, format string, args ...any) error {
+		return shared.WrapError("notification", "ValidateTriggerRule", shared.ErrInvalidInput,
+			fmt.Sprintf(format, args...), err)
+	}
+
+	if !tr.ID.IsValid() {
+		return invalid(ErrInvalidTriggerRuleID, "id is required")
+	}
+	if tr.Name == "" {
+		return invalid(ErrEmptyRuleName, "name is required")
+	}
+	if !tr.NotificationType.IsValid() {
+		return invalid(ErrInvalidNotificationType, "unknown notification type %q", tr.NotificationType)
+	}
+	if !tr.Priority.IsValid() {
+		return invalid(ErrInvalidPriority, "invalid priority %d", tr.Priority)
+	}
+	if tr.MessageTemplate == "" {
+		return invalid(ErrEmptyMessageTemplate, "message_template is required")
+	}
+	if err := validateRuleTemplate(tr.MessageTemplate); err != nil {
+		return invalid(err, "message_template")
+	}
+	if tr.TitleTemplate != "" {
+		if err := validateRuleTemplate(tr.TitleTemplate); err != nil {
+			return invalid(err, "title_template")
+		}
+	}
+
+	for i, c := range tr.Conditions {
+		if err := c.validate(); err != nil {
+			return invalid(err, "condition %d", i)
+		}
+	}
+
+	if tc := tr.TimeConstraint; tc != nil {
+		if tc.HoursStart < 0 || tc.HoursStart > 23 || tc.HoursEnd < 0 || tc.HoursEnd > 23 {
+			return invalid(ErrInvalidHours, "time_constraint hours")
+		}
+		for _, d := range tc.DaysOfWeek {
+			if d < 0 || d > 6 {
+				return invalid(ErrInvalidDayOfWeek, "time_constraint day %d", d)
+			}
+		}
+		if _, err := timeutil.LoadLocation(tc.Timezone); err != nil {
+			return invalid(err, "time_constraint timezone %q", tc.Timezone)
+		}
+	}
+
+	if rl := tr.RateLimit; rl != nil {
+		if rl.MaxCount <= 0 {
+			return invalid(ErrInvalidRateLimit, "rate_limit")
+		}
+		if rl.Period <= 0 {
+			return invalid(ErrInvalidRateLimitPeriod, "rate_limit")
+		}
+	}
+
+	if tr.RequiresUserConsent && tr.ConsentSettingKey == "" {
+		return invalid(ErrEmptyConsentKey, "consent_setting_key is required")
+	}
+	if tr.CooldownPeriod < 0 || tr.ExpiresAfter < 0 {
+		return invalid(ErrNegativeDuration, "cooldown and expires_after")
+	}
+
+	return nil
+}
+
+// validate проверяет одно условие.
+func (c *Condition) validate() error {
+	if c == nil {
+		return ErrNilCondition
+	}
+	if !c.Type.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidConditionType, c.Type)
+	}
+	if !c.Operator.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidOperator, c.Operator)
+	}
+
+	switch c.Operator {
+	case OpBetween:
+		if c.MinValue > c.MaxValue {
+			return ErrInvalidRange
+		}
+	case OpIn, OpNotIn:
+		if len(c.ListValues) == 0 {
+			return ErrEmptyValueList
+		}
+	}
+	return nil
+}
+
+// validateRuleTemplate проверяет, что шаблон разбирается и ссылается
+// только на поля NotificationData.
+func validateRuleTemplate(tmpl string) error {
+	unknown, err := UnknownTemplateFields(tmpl)
+	if err != nil {
+		return err
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w: unknown fields %v", ErrTemplateError, unknown)
+	}
+	return nil
+}
+
+// parsePriority разбирает имя приоритета.
+func parsePriority(s string) (Priority, bool) {
+	for p := PriorityLow; p <= PriorityUrgent; p++ {
+		if p.String() == s {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// formatRuleDuration форматирует длительность; ноль - пустая строка.
+func formatRuleDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// parseRuleDuration разбирает длительность; пустая строка - ноль.
+func parseRuleDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// BUILT-IN RULES
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Идентификаторы встроенных правил.
+const (
+	BuiltinRankUpRuleID         TriggerRuleID = "builtin.rank_up"
+	BuiltinInactivityRuleID     TriggerRuleID = "builtin.inactivity"
+	BuiltinStreakReminderRuleID TriggerRuleID = "builtin.streak_reminder"
+	BuiltinDailyDigestRuleID    TriggerRuleID = "builtin.daily_digest"
+)
+
+// BuiltinTriggerRules возвращает встроенные правила. Ими заполняется
+// пустая таблица trigger_rules при старте; дальше правила настраиваются
+// через админку без деплоя.
+func BuiltinTriggerRules() []*TriggerRule {
+	rankUp, _ := NewRankUpRule(BuiltinRankUpRuleID, 3)
+	inactivity, _ := NewInactivityRule(BuiltinInactivityRuleID, 3)
+	streak, _ := NewStreakReminderRule(BuiltinStreakReminderRuleID, 4)
+	digest, _ := NewDailyDigestRule(BuiltinDailyDigestRuleID, 20, "Asia/Almaty")
+
+	rules := []*TriggerRule{rankUp, inactivity, streak, digest}
+	for _, rule := range rules {
+		rule.AddTag("builtin")
+	}
+	return rules
+}
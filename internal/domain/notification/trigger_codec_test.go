@@ -0,0 +1,148 @@
+package notification
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+var allConditionTypes = []ConditionType{
+	ConditionTypeRankChange, ConditionTypeXPGained, ConditionTypeXPThreshold, ConditionTypeLevelUp,
+	ConditionTypeTopEntered, ConditionTypeTopLeft, ConditionTypeTaskCompleted, ConditionTypeStreakDays,
+	ConditionTypeStreakBroken, ConditionTypeStreakAtRisk, ConditionTypeInactiveDays, ConditionTypeStudentOnline,
+	ConditionTypeStudentOffline, ConditionTypeHelpRequested, ConditionTypeEndorsementReceived,
+	ConditionTypeAchievementUnlocked, ConditionTypeScheduled, ConditionTypeCompetitorClose,
+	ConditionTypeNewRegistration,
+}
+
+var allOperators = []ComparisonOperator{
+	OpEqual, OpNotEqual, OpGreaterThan, OpGreaterOrEqual, OpLessThan, OpLessOrEqual, OpBetween, OpIn, OpNotIn,
+}
+
+// roundTrip сериализует правило и разбирает его обратно.
+func roundTrip(t *testing.T, rule *TriggerRule) *TriggerRule {
+	t.Helper()
+	data, err := EncodeTriggerRule(rule)
+	require.NoError(t, err)
+	decoded, err := DecodeTriggerRule(data)
+	require.NoError(t, err, string(data))
+	return decoded
+}
+
+func TestTriggerRuleCodec_RoundTripsEveryConditionAndOperator(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	for _, condType := range allConditionTypes {
+		require.True(t, condType.IsValid(), condType)
+
+		for _, op := range allOperators {
+			for _, negate := range []bool{false, true} {
+				rule, err := NewTriggerRule(NewTriggerRuleParams{
+					ID:               "rule-" + TriggerRuleID(condType) + "-" + TriggerRuleID(op),
+					Name:             "Round trip",
+					NotificationType: NotificationTypeRankUp,
+					MessageTemplate:  RankUpTemplate,
+				})
+				require.NoError(t, err)
+
+				cond := &Condition{Type: condType, Operator: op, StringValue: "go-reloaded", Negate: negate}
+				switch op {
+				case OpBetween:
+					cond.MinValue, cond.MaxValue = -2, 7
+				case OpIn, OpNotIn:
+					cond.ListValues = []int{0, 3, -1}
+				default:
+					cond.Value = -5
+				}
+				require.NoError(t, rule.AddCondition(cond))
+				rule.CreatedAt, rule.UpdatedAt = at, at.Add(time.Hour)
+
+				assert.Equal(t, rule, roundTrip(t, rule), "%s %s negate=%v", condType, op, negate)
+			}
+		}
+	}
+}
+
+func TestTriggerRuleCodec_RoundTripsAllRuleFields(t *testing.T) {
+	high := PriorityHigh
+	rule, err := NewTriggerRule(NewTriggerRuleParams{
+		ID:               "full",
+		Name:             "Full rule",
+		NotificationType: NotificationTypeStreakReminder,
+		MessageTemplate:  StreakReminderTemplate,
+		Priority:         &high,
+	})
+	require.NoError(t, err)
+
+	rule.Description = "все поля"
+	rule.TitleTemplate = "🔥 {{.StreakDays}}"
+	rule.SetTimeConstraint(&TimeConstraint{
+		DaysOfWeek: []int{1, 3, 5}, HoursStart: 21, HoursEnd: 9, Timezone: "Asia/Almaty", ExcludeQuietHours: true,
+	})
+	rule.SetRateLimit(&RateLimit{MaxCount: 2, Period: 90 * time.Minute, PerRecipient: true, BurstAllowed: true})
+	rule.RequireConsent("streak_reminders")
+	rule.SetCooldown(6 * time.Hour)
+	rule.SetExpiration(0)
+	rule.AddTag("streak")
+	rule.AddTag("evening")
+	rule.SetMetadata("owner", "curators")
+	rule.Disable()
+	at := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	rule.CreatedAt, rule.UpdatedAt = at, at
+
+	assert.Equal(t, rule, roundTrip(t, rule))
+
+	for _, builtin := range BuiltinTriggerRules() {
+		require.NoError(t, builtin.Validate(), builtin.ID)
+		builtin.CreatedAt, builtin.UpdatedAt = at, at
+		assert.Equal(t, builtin, roundTrip(t, builtin), builtin.ID)
+	}
+}
+
+func TestTriggerRuleCodec_RejectsInvalidRules(t *testing.T) {
+	valid := func() TriggerRuleDocument {
+		rankUp, err := NewRankUpRule("rank", 3)
+		require.NoError(t, err)
+		return rankUp.Document()
+	}
+
+	tests := map[string]func(d *TriggerRuleDocument){
+		"unknown condition type": func(d *TriggerRuleDocument) { d.Conditions[0].Type = "rank_chnage" },
+		"unknown operator":       func(d *TriggerRuleDocument) { d.Conditions[0].Operator = "~=" },
+		"inverted range": func(d *TriggerRuleDocument) {
+			d.Conditions[0] = ConditionDocument{Type: "rank_change", Operator: "between", MinValue: 5, MaxValue: 1}
+		},
+		"empty list":             func(d *TriggerRuleDocument) { d.Conditions[0].Operator = "in" },
+		"unknown template field": func(d *TriggerRuleDocument) { d.MessageTemplate = "#{{.NewRnak}}" },
+		"broken template":        func(d *TriggerRuleDocument) { d.MessageTemplate = "{{.NewRank" },
+		"unknown title field":    func(d *TriggerRuleDocument) { d.TitleTemplate = "{{.Oops}}" },
+		"bad hours": func(d *TriggerRuleDocument) {
+			d.TimeConstraint = &TimeConstraintDocument{HoursStart: 9, HoursEnd: 24}
+		},
+		"bad rate limit":   func(d *TriggerRuleDocument) { d.RateLimit = &RateLimitDocument{MaxCount: 1, Period: "soon"} },
+		"unknown priority": func(d *TriggerRuleDocument) { d.Priority = "asap" },
+		"missing consent":  func(d *TriggerRuleDocument) { d.ConsentSettingKey = "" },
+	}
+
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			doc := valid()
+			mutate(&doc)
+			data, err := json.Marshal(doc)
+			require.NoError(t, err)
+
+			_, err = DecodeTriggerRule(data)
+			require.Error(t, err)
+			assert.True(t, shared.IsValidation(err), err)
+		})
+	}
+
+	// Неизвестное поле - ошибка, а не молча потерянная настройка
+	_, err := DecodeTriggerRule([]byte(`{"v":1,"id":"rank","cooldwn":"1h"}`))
+	assert.True(t, shared.IsValidation(err), err)
+}
@@ -0,0 +1,65 @@
+package notification
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // Asia/Almaty без системной базы часовых поясов
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
+)
+
+func newTestTriggerRule(t *testing.T, clock shared.Clock) *TriggerRule {
+	t.Helper()
+	rule, err := NewTriggerRule(NewTriggerRuleParams{
+		ID:               "rule-1",
+		Name:             "Напоминание",
+		NotificationType: NotificationTypeInactivityReminder,
+		MessageTemplate:  "Пора вернуться",
+		Clock:            clock,
+	})
+	require.NoError(t, err)
+	return rule
+}
+
+func TestTriggerRule_CooldownBoundary(t *testing.T) {
+	clock := shared.NewFakeClock(time.Date(2026, time.May, 11, 12, 0, 0, 0, time.UTC))
+	rule := newTestTriggerRule(t, clock)
+	rule.SetCooldown(time.Hour)
+
+	last := clock.Now()
+	ctx := NewTriggerContext("dana", 1)
+	ctx.LastTriggeredAt = &last
+
+	clock.Advance(time.Hour - time.Nanosecond)
+	assert.Equal(t, "cooldown period not elapsed", rule.Evaluate(ctx).Reason)
+
+	// Ровно через cooldown правило снова может сработать
+	clock.Advance(time.Nanosecond)
+	assert.True(t, rule.Evaluate(ctx).ShouldTrigger)
+}
+
+func TestTriggerRule_OvernightWindow(t *testing.T) {
+	clock := shared.NewFakeClock(timeutil.DateTime(2026, 5, 11, 20, 59, 59))
+	rule := newTestTriggerRule(t, clock)
+
+	// Окно через полночь: 21:00 - 9:00 по Алматы
+	tc, err := NewTimeConstraint(21, 9, "Asia/Almaty")
+	require.NoError(t, err)
+	rule.SetTimeConstraint(tc)
+	ctx := NewTriggerContext("dana", 1)
+
+	assert.Equal(t, "outside allowed time window", rule.Evaluate(ctx).Reason)
+
+	clock.Advance(time.Second) // 21:00
+	assert.True(t, rule.Evaluate(ctx).ShouldTrigger)
+
+	clock.Set(timeutil.DateTime(2026, 5, 12, 8, 59, 59))
+	assert.True(t, rule.Evaluate(ctx).ShouldTrigger)
+
+	clock.Advance(time.Second) // 9:00
+	assert.False(t, rule.Evaluate(ctx).ShouldTrigger)
+}
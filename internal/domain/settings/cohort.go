@@ -0,0 +1,251 @@
+// Package settings содержит настройки геймификации на уровне потока (cohort).
+//
+// Кураторы разных потоков хотят разного: кому-то не нужны уведомления
+// "тебя догоняют", кому-то удобнее дайджест в 20:00. Настройка потока
+// переопределяет глобальное значение (из env), а личная настройка студента,
+// если она есть для этой функции, важнее настройки потока.
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// KEYS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Key - имя настройки потока.
+type Key string
+
+const (
+	// KeyDigestHour - час отправки ежедневного дайджеста (0-23).
+	KeyDigestHour Key = "digest_hour"
+
+	// KeyCompetitorCloseEnabled - уведомлять, что соперник догоняет/обогнал.
+	KeyCompetitorCloseEnabled Key = "competitor_close_enabled"
+
+	// KeyStreakFreezeEnabled - разрешена ли заморозка серии.
+	KeyStreakFreezeEnabled Key = "streak_freeze_enabled"
+
+	// KeyWeeklyRecapEnabled - отправлять ли еженедельный итог.
+	KeyWeeklyRecapEnabled Key = "weekly_recap_enabled"
+
+	// Ступени лестницы неактивности: через сколько дней без активности
+	// выполняется соответствующее действие.
+	KeyInactivityGentleReminderDays Key = "inactivity_days.gentle_reminder"
+	KeyInactivityEncouragementDays  Key = "inactivity_days.encouragement"
+	KeyInactivityStudyBuddyDays     Key = "inactivity_days.notify_study_buddy"
+	KeyInactivityUrgentOutreachDays Key = "inactivity_days.urgent_outreach"
+	KeyInactivityMarkInactiveDays   Key = "inactivity_days.mark_inactive"
+
+	// Веса подбора помощников (matchscore.Weights): максимум баллов за фактор.
+	KeyMatchWeightRecency      Key = "match_weight.recency"
+	KeyMatchWeightStruggle     Key = "match_weight.struggle"
+	KeyMatchWeightLoad         Key = "match_weight.load"
+	KeyMatchWeightOnline       Key = "match_weight.online"
+	KeyMatchWeightHoursOverlap Key = "match_weight.hours_overlap"
+	KeyMatchWeightRating       Key = "match_weight.rating"
+	KeyMatchWeightExperience   Key = "match_weight.help_history"
+	KeyMatchWeightPriorContact Key = "match_weight.prior_contact"
+
+	// KeyCuratorChatID - чат кураторов потока для еженедельного отчёта (0 - не отправлять).
+	KeyCuratorChatID Key = "curator.chat_id"
+
+	// KeyCuratorReportCSV - прикладывать ли к отчёту CSV-файл.
+	KeyCuratorReportCSV Key = "curator.report_csv"
+
+	// Тихие часы чата для объявлений (час начала и конца, 0-23; равные -
+	// без тихих часов). Задаются для "потока" ChatScope(chatID).
+	KeyQuietHoursStart Key = "quiet_hours.start"
+	KeyQuietHoursEnd   Key = "quiet_hours.end"
+
+	// Какие события попадают в ленту потока (/feed).
+	KeyFeedAchievements    Key = "feed.achievement_unlocked"
+	KeyFeedHelpResolved    Key = "feed.help_resolved"
+	KeyFeedPersonalBests   Key = "feed.personal_best"
+	KeyFeedStudentJoined   Key = "feed.student_joined"
+	KeyFeedHelperOfTheWeek Key = "feed.helper_of_the_week"
+)
+
+// ChatScope возвращает имя, под которым хранятся настройки чата.
+func ChatScope(chatID int64) string {
+	return fmt.Sprintf("chat:%d", chatID)
+}
+
+// Kind - тип значения настройки.
+type Kind string
+
+const (
+	KindBool   Kind = "bool"
+	KindInt    Kind = "int"
+	KindString Kind = "string"
+)
+
+// Definition описывает допустимые значения настройки.
+type Definition struct {
+	Key         Key    `json:"key"`
+	Kind        Kind   `json:"kind"`
+	Min         int    `json:"min,omitempty"`
+	Max         int    `json:"max,omitempty"`
+	Description string `json:"description"`
+}
+
+// maxChatID - граница Telegram chat ID (целое, точно представимое в JSON).
+const maxChatID = 1<<53 - 1
+
+// definitions - все известные настройки потока.
+var definitions = map[Key]Definition{
+	KeyDigestHour:                   {Key: KeyDigestHour, Kind: KindInt, Min: 0, Max: 23, Description: "Час отправки ежедневного дайджеста"},
+	KeyCompetitorCloseEnabled:       {Key: KeyCompetitorCloseEnabled, Kind: KindBool, Description: "Уведомления о том, что соперник рядом"},
+	KeyStreakFreezeEnabled:          {Key: KeyStreakFreezeEnabled, Kind: KindBool, Description: "Заморозка серии"},
+	KeyWeeklyRecapEnabled:           {Key: KeyWeeklyRecapEnabled, Kind: KindBool, Description: "Еженедельный итог"},
+	KeyInactivityGentleReminderDays: {Key: KeyInactivityGentleReminderDays, Kind: KindInt, Min: 1, Max: 365, Description: "Дней до мягкого напоминания"},
+	KeyInactivityEncouragementDays:  {Key: KeyInactivityEncouragementDays, Kind: KindInt, Min: 1, Max: 365, Description: "Дней до поддерживающего сообщения"},
+	KeyInactivityStudyBuddyDays:     {Key: KeyInactivityStudyBuddyDays, Kind: KindInt, Min: 1, Max: 365, Description: "Дней до уведомления напарников"},
+	KeyInactivityUrgentOutreachDays: {Key: KeyInactivityUrgentOutreachDays, Kind: KindInt, Min: 1, Max: 365, Description: "Дней до срочного обращения"},
+	KeyInactivityMarkInactiveDays:   {Key: KeyInactivityMarkInactiveDays, Kind: KindInt, Min: 1, Max: 365, Description: "Дней до пометки неактивным"},
+	KeyMatchWeightRecency:           {Key: KeyMatchWeightRecency, Kind: KindInt, Min: 0, Max: 100, Description: "Подбор помощников: вес свежести решения задачи"},
+	KeyMatchWeightStruggle:          {Key: KeyMatchWeightStruggle, Kind: KindInt, Min: 0, Max: 100, Description: "Подбор помощников: вес того, что помощник сам долго решал задачу"},
+	KeyMatchWeightLoad:              {Key: KeyMatchWeightLoad, Kind: KindInt, Min: 0, Max: 100, Description: "Подбор помощников: вес свободы от других запросов"},
+	KeyMatchWeightOnline:            {Key: KeyMatchWeightOnline, Kind: KindInt, Min: 0, Max: 100, Description: "Подбор помощников: вес онлайн-статуса"},
+	KeyMatchWeightHoursOverlap:      {Key: KeyMatchWeightHoursOverlap, Kind: KindInt, Min: 0, Max: 100, Description: "Подбор помощников: вес совпадения часов активности"},
+	KeyMatchWeightRating:            {Key: KeyMatchWeightRating, Kind: KindInt, Min: 0, Max: 100, Description: "Подбор помощников: вес рейтинга"},
+	KeyMatchWeightExperience:        {Key: KeyMatchWeightExperience, Kind: KindInt, Min: 0, Max: 100, Description: "Подбор помощников: вес опыта помощи"},
+	KeyMatchWeightPriorContact:      {Key: KeyMatchWeightPriorContact, Kind: KindInt, Min: 0, Max: 100, Description: "Подбор помощников: вес прошлого контакта"},
+	KeyCuratorChatID:                {Key: KeyCuratorChatID, Kind: KindInt, Min: -maxChatID, Max: maxChatID, Description: "Telegram-чат кураторов для еженедельного отчёта о потоке"},
+	KeyCuratorReportCSV:             {Key: KeyCuratorReportCSV, Kind: KindBool, Description: "CSV-файл к еженедельному отчёту кураторам"},
+	KeyQuietHoursStart:              {Key: KeyQuietHoursStart, Kind: KindInt, Min: 0, Max: 23, Description: "Начало тихих часов чата для объявлений"},
+	KeyQuietHoursEnd:                {Key: KeyQuietHoursEnd, Kind: KindInt, Min: 0, Max: 23, Description: "Конец тихих часов чата для объявлений"},
+	KeyFeedAchievements:             {Key: KeyFeedAchievements, Kind: KindBool, Description: "Лента: полученные достижения"},
+	KeyFeedHelpResolved:             {Key: KeyFeedHelpResolved, Kind: KindBool, Description: "Лента: решённые запросы помощи"},
+	KeyFeedPersonalBests:            {Key: KeyFeedPersonalBests, Kind: KindBool, Description: "Лента: личные рекорды"},
+	KeyFeedStudentJoined:            {Key: KeyFeedStudentJoined, Kind: KindBool, Description: "Лента: новые студенты"},
+	KeyFeedHelperOfTheWeek:          {Key: KeyFeedHelperOfTheWeek, Kind: KindBool, Description: "Лента: помощник недели"},
+}
+
+// Lookup возвращает описание настройки.
+func Lookup(key Key) (Definition, bool) {
+	def, ok := definitions[key]
+	return def, ok
+}
+
+// Definitions возвращает описания всех настроек, отсортированные по ключу.
+func Definitions() []Definition {
+	result := make([]Definition, 0, len(definitions))
+	for _, def := range definitions {
+		result = append(result, def)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// Validate проверяет, что ключ известен, а значение подходит по типу и диапазону.
+func Validate(key Key, value json.RawMessage) error {
+	def, ok := definitions[key]
+	if !ok {
+		return shared.NewDomainError("settings", "Validate", shared.ErrInvalidInput,
+			fmt.Sprintf("unknown setting %q", key))
+	}
+
+	invalid := func(msg string) error {
+		return shared.NewDomainError("settings", "Validate", shared.ErrInvalidInput,
+			fmt.Sprintf("setting %q: %s", key, msg))
+	}
+
+	if len(value) == 0 || string(value) == "null" {
+		return invalid("value is required")
+	}
+
+	switch def.Kind {
+	case KindBool:
+		var v bool
+		if err := json.Unmarshal(value, &v); err != nil {
+			return invalid("expected boolean")
+		}
+	case KindInt:
+		var v int
+		if err := json.Unmarshal(value, &v); err != nil {
+			return invalid("expected integer")
+		}
+		if v < def.Min || v > def.Max {
+			return invalid(fmt.Sprintf("must be between %d and %d", def.Min, def.Max))
+		}
+	case KindString:
+		var v string
+		if err := json.Unmarshal(value, &v); err != nil {
+			return invalid("expected string")
+		}
+	}
+
+	return nil
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// VALUES
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Values - переопределённые настройки одного потока (JSON-значения).
+type Values map[Key]json.RawMessage
+
+// Bool возвращает значение булевой настройки или def, если её нет.
+func (v Values) Bool(key Key, def bool) bool {
+	var result bool
+	if raw, ok := v[key]; ok && json.Unmarshal(raw, &result) == nil {
+		return result
+	}
+	return def
+}
+
+// Int возвращает значение целочисленной настройки или def, если её нет.
+func (v Values) Int(key Key, def int) int {
+	var result int
+	if raw, ok := v[key]; ok && json.Unmarshal(raw, &result) == nil {
+		return result
+	}
+	return def
+}
+
+// String возвращает значение строковой настройки или def, если её нет.
+func (v Values) String(key Key, def string) string {
+	var result string
+	if raw, ok := v[key]; ok && json.Unmarshal(raw, &result) == nil {
+		return result
+	}
+	return def
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// INTERFACES
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Repository - хранилище настроек потоков (PostgreSQL).
+type Repository interface {
+	// GetByCohort возвращает все переопределённые настройки потока.
+	GetByCohort(ctx context.Context, cohort string) (Values, error)
+
+	// Set сохраняет значение настройки потока.
+	Set(ctx context.Context, cohort string, key Key, value json.RawMessage) error
+
+	// Delete удаляет переопределение, возвращая поток к глобальному значению.
+	Delete(ctx context.Context, cohort string, key Key) error
+}
+
+// Reader - типизированный доступ к настройкам потока для кода функций.
+// def - глобальное значение, используемое, если поток его не переопределил.
+type Reader interface {
+	GetBool(ctx context.Context, cohort string, key Key, def bool) bool
+	GetInt(ctx context.Context, cohort string, key Key, def int) int
+	GetString(ctx context.Context, cohort string, key Key, def string) string
+}
+
+// Defaults - Reader без переопределений: всегда возвращает глобальные значения.
+type Defaults struct{}
+
+func (Defaults) GetBool(_ context.Context, _ string, _ Key, def bool) bool       { return def }
+func (Defaults) GetInt(_ context.Context, _ string, _ Key, def int) int          { return def }
+func (Defaults) GetString(_ context.Context, _ string, _ Key, def string) string { return def }
@@ -0,0 +1,62 @@
+package settings
+
+import "github.com/alem-hub/alem-community-hub/pkg/featureflag"
+
+// ══════════════════════════════════════════════════════════════════════════════
+// FEATURE FLAGS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Флаги функций, которые выкатываются постепенно (см. pkg/featureflag).
+// В отличие от настроек потока, флаг может включить функцию части
+// студентов внутри потока.
+const (
+	// FeatureStuckDetection - предложение помощи студентам на плато.
+	FeatureStuckDetection = "stuck_detection"
+
+	// FeatureCompetitorClose - уведомление "тебя обогнали, соперник рядом".
+	FeatureCompetitorClose = "competitor_close"
+
+	// FeaturePersonalBests - поздравления с личными рекордами.
+	FeaturePersonalBests = "personal_best_notifications"
+
+	// FeatureRankChangeNotifications - уведомления о смене места в рейтинге
+	// (в том числе варианты экспериментов с их формулировкой).
+	FeatureRankChangeNotifications = "rank_change_notifications"
+)
+
+// BuiltinFeatureFlags возвращает флаги по умолчанию. Существовавшие до
+// флагов функции включены для всех; новые уведомления выкатываются
+// вручную. Рискованные функции при недоступной базе выключаются,
+// безобидные - остаются.
+func BuiltinFeatureFlags() []featureflag.Flag {
+	return []featureflag.Flag{
+		{
+			Name:           FeatureStuckDetection,
+			Description:    "Предложение помощи студентам, застрявшим на плато",
+			Enabled:        true,
+			RolloutPercent: 100,
+			FailOpen:       false,
+		},
+		{
+			Name:           FeatureCompetitorClose,
+			Description:    "Уведомление о том, что соперник рядом",
+			Enabled:        true,
+			RolloutPercent: 100,
+			FailOpen:       false,
+		},
+		{
+			Name:           FeaturePersonalBests,
+			Description:    "Поздравления с личными рекордами",
+			Enabled:        true,
+			RolloutPercent: 100,
+			FailOpen:       true,
+		},
+		{
+			Name:           FeatureRankChangeNotifications,
+			Description:    "Уведомления о смене места в рейтинге",
+			Enabled:        false,
+			RolloutPercent: 0,
+			FailOpen:       false,
+		},
+	}
+}
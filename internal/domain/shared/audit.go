@@ -0,0 +1,74 @@
+package shared
+
+import (
+	"context"
+	"time"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Audit Log
+// ═══════════════════════════════════════════════════════════════════════════
+
+// AuditAction identifies a privileged action recorded in the audit log.
+type AuditAction string
+
+const (
+	// AuditActionImpersonateView is recorded when an admin views the bot as a student.
+	AuditActionImpersonateView AuditAction = "impersonate_view"
+
+	// AuditActionStudentMerge is recorded when an admin merges duplicate student accounts.
+	AuditActionStudentMerge AuditAction = "student_merge"
+
+	// AuditActionReportReviewed is recorded when an admin upholds or dismisses a report.
+	AuditActionReportReviewed AuditAction = "report_reviewed"
+
+	// AuditActionHelpMatchingRestored is recorded when an admin brings a
+	// reported student back into helper matching.
+	AuditActionHelpMatchingRestored AuditAction = "help_matching_restored"
+
+	// AuditActionIdentityChanged is recorded when sync finds a student by
+	// Alem login under a new email and updates it.
+	AuditActionIdentityChanged AuditAction = "identity_changed"
+
+	// AuditActionTelegramRelinked is recorded when a student moves their
+	// profile to a new Telegram account with /relink.
+	AuditActionTelegramRelinked AuditAction = "telegram_relinked"
+)
+
+// AuditEntry is a single record of a privileged action.
+type AuditEntry struct {
+	// ActorID identifies who performed the action (e.g. admin Telegram ID).
+	ActorID string
+
+	// Action is what was done.
+	Action AuditAction
+
+	// TargetID identifies the subject of the action (e.g. student ID).
+	TargetID string
+
+	// Details holds action-specific data.
+	Details map[string]interface{}
+
+	// OccurredAt is when the action happened.
+	OccurredAt time.Time
+}
+
+// NewAuditEntry creates an audit entry stamped with the current time.
+func NewAuditEntry(actorID string, action AuditAction, targetID string, details map[string]interface{}) AuditEntry {
+	if details == nil {
+		details = make(map[string]interface{})
+	}
+	return AuditEntry{
+		ActorID:    actorID,
+		Action:     action,
+		TargetID:   targetID,
+		Details:    details,
+		OccurredAt: time.Now().UTC(),
+	}
+}
+
+// AuditLog persists audit entries.
+type AuditLog interface {
+	// Record stores an audit entry.
+	Record(ctx context.Context, entry AuditEntry) error
+}
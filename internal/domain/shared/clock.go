@@ -0,0 +1,88 @@
+package shared
+
+import (
+	"sync"
+	"time"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Clock
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Clock is the source of the current time for time-dependent domain logic
+// (streaks, expiry, cooldowns). Entities take it via WithClock and fall back
+// to the system clock, so production code does not need to pass one.
+type Clock interface {
+	// Now returns the current time in UTC.
+	Now() time.Time
+
+	// Today returns the start of the current day in the given location.
+	Today(loc *time.Location) time.Time
+}
+
+// SystemClock is the real clock.
+type SystemClock struct{}
+
+// Now returns the current system time in UTC.
+func (SystemClock) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// Today returns the start of the current day in loc.
+func (c SystemClock) Today(loc *time.Location) time.Time {
+	return StartOfDay(c.Now(), loc)
+}
+
+// ClockOrSystem returns c, or the system clock if c is nil.
+func ClockOrSystem(c Clock) Clock {
+	if c == nil {
+		return SystemClock{}
+	}
+	return c
+}
+
+// StartOfDay returns midnight of t's day in loc (UTC if loc is nil).
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// FakeClock is a manually controlled clock for tests.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock creates a clock stopped at now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now.UTC()}
+}
+
+// Now returns the current fake time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Today returns the start of the current fake day in loc.
+func (c *FakeClock) Today(loc *time.Location) time.Time {
+	return StartOfDay(c.Now(), loc)
+}
+
+// Set moves the clock to t.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t.UTC()
+}
+
+// Advance moves the clock forward by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
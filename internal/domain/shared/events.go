@@ -20,11 +20,15 @@ const (
 	EventStudentReactivated EventType = "student.reactivated"
 
 	// Progress events
-	EventXPGained           EventType = "progress.xp_gained"
-	EventLevelUp            EventType = "progress.level_up"
-	EventTaskCompleted      EventType = "progress.task_completed"
-	EventDailyStreakUpdated EventType = "progress.streak_updated"
-	EventDailyStreakBroken  EventType = "progress.streak_broken"
+	EventXPGained            EventType = "progress.xp_gained"
+	EventLevelUp             EventType = "progress.level_up"
+	EventTaskCompleted       EventType = "progress.task_completed"
+	EventDailyStreakUpdated  EventType = "progress.streak_updated"
+	EventDailyStreakBroken   EventType = "progress.streak_broken"
+	EventStreakMilestone     EventType = "progress.streak_milestone"
+	EventStudentStuck        EventType = "progress.student_stuck"
+	EventPersonalBestSet     EventType = "progress.personal_best_set"
+	EventAchievementUnlocked EventType = "progress.achievement_unlocked"
 
 	// Leaderboard events
 	EventRankChanged        EventType = "leaderboard.rank_changed"
@@ -39,11 +43,14 @@ const (
 	EventSessionEnded       EventType = "activity.session_ended"
 
 	// Social events
-	EventHelpRequested    EventType = "social.help_requested"
-	EventHelpProvided     EventType = "social.help_provided"
-	EventConnectionMade   EventType = "social.connection_made"
-	EventEndorsementGiven EventType = "social.endorsement_given"
-	EventMentorMatched    EventType = "social.mentor_matched"
+	EventHelpRequested       EventType = "social.help_requested"
+	EventHelperAssigned      EventType = "social.helper_assigned"
+	EventHelpRequestResolved EventType = "social.help_request_resolved"
+	EventHelpProvided        EventType = "social.help_provided"
+	EventConnectionMade      EventType = "social.connection_made"
+	EventEndorsementGiven    EventType = "social.endorsement_given"
+	EventMentorMatched       EventType = "social.mentor_matched"
+	EventHelperOfTheWeek     EventType = "social.helper_of_the_week"
 
 	// Notification events
 	EventNotificationSent   EventType = "notification.sent"
@@ -245,6 +252,127 @@ func NewDailyStreakBrokenEvent(studentID string, previousStreak, daysMissed int)
 	}
 }
 
+// StreakMilestoneReachedEvent is emitted once per streak run when the
+// streak reaches one of the celebrated lengths.
+type StreakMilestoneReachedEvent struct {
+	BaseEvent
+	StudentID     string `json:"student_id"`
+	Milestone     int    `json:"milestone"`
+	CurrentStreak int    `json:"current_streak"`
+}
+
+// Payload implements Event interface.
+func (e StreakMilestoneReachedEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{
+		"student_id":     e.StudentID,
+		"milestone":      e.Milestone,
+		"current_streak": e.CurrentStreak,
+	}
+}
+
+// NewStreakMilestoneReachedEvent creates a new StreakMilestoneReachedEvent.
+func NewStreakMilestoneReachedEvent(studentID string, milestone, currentStreak int) StreakMilestoneReachedEvent {
+	return StreakMilestoneReachedEvent{
+		BaseEvent:     NewBaseEvent(EventStreakMilestone, studentID),
+		StudentID:     studentID,
+		Milestone:     milestone,
+		CurrentStreak: currentStreak,
+	}
+}
+
+// StudentStuckEvent is emitted when a student keeps logging in but gains no XP
+// after a period of steady progress.
+type StudentStuckEvent struct {
+	BaseEvent
+	StudentID   string `json:"student_id"`
+	TaskID      string `json:"task_id,omitempty"`
+	PlateauDays int    `json:"plateau_days"`
+	OnlineDays  int    `json:"online_days"`
+}
+
+// Payload implements Event interface.
+func (e StudentStuckEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{
+		"student_id":   e.StudentID,
+		"task_id":      e.TaskID,
+		"plateau_days": e.PlateauDays,
+		"online_days":  e.OnlineDays,
+	}
+}
+
+// NewStudentStuckEvent creates a new StudentStuckEvent.
+// taskID may be empty when the task the student is stuck on is unknown.
+func NewStudentStuckEvent(studentID, taskID string, plateauDays, onlineDays int) StudentStuckEvent {
+	return StudentStuckEvent{
+		BaseEvent:   NewBaseEvent(EventStudentStuck, studentID),
+		StudentID:   studentID,
+		TaskID:      taskID,
+		PlateauDays: plateauDays,
+		OnlineDays:  onlineDays,
+	}
+}
+
+// PersonalBestSetEvent is emitted when a student beats one of their personal
+// records. It is not emitted for a first record or a historical backfill.
+type PersonalBestSetEvent struct {
+	BaseEvent
+	StudentID     string    `json:"student_id"`
+	Metric        string    `json:"metric"`
+	Value         int       `json:"value"`
+	PreviousValue int       `json:"previous_value"`
+	AchievedOn    time.Time `json:"achieved_on"`
+}
+
+// Payload implements Event interface.
+func (e PersonalBestSetEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{
+		"student_id":     e.StudentID,
+		"metric":         e.Metric,
+		"value":          e.Value,
+		"previous_value": e.PreviousValue,
+		"achieved_on":    e.AchievedOn.Format("2006-01-02"),
+	}
+}
+
+// NewPersonalBestSetEvent creates a new PersonalBestSetEvent.
+func NewPersonalBestSetEvent(studentID, metric string, value, previousValue int, achievedOn time.Time) PersonalBestSetEvent {
+	return PersonalBestSetEvent{
+		BaseEvent:     NewBaseEvent(EventPersonalBestSet, studentID),
+		StudentID:     studentID,
+		Metric:        metric,
+		Value:         value,
+		PreviousValue: previousValue,
+		AchievedOn:    achievedOn,
+	}
+}
+
+// AchievementUnlockedEvent is emitted when a student unlocks an achievement.
+type AchievementUnlockedEvent struct {
+	BaseEvent
+	StudentID       string `json:"student_id"`
+	AchievementType string `json:"achievement_type"`
+	XPBonus         int    `json:"xp_bonus"`
+}
+
+// Payload implements Event interface.
+func (e AchievementUnlockedEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{
+		"student_id":       e.StudentID,
+		"achievement_type": e.AchievementType,
+		"xp_bonus":         e.XPBonus,
+	}
+}
+
+// NewAchievementUnlockedEvent creates a new AchievementUnlockedEvent.
+func NewAchievementUnlockedEvent(studentID, achievementType string, xpBonus int) AchievementUnlockedEvent {
+	return AchievementUnlockedEvent{
+		BaseEvent:       NewBaseEvent(EventAchievementUnlocked, studentID),
+		StudentID:       studentID,
+		AchievementType: achievementType,
+		XPBonus:         xpBonus,
+	}
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Leaderboard Events
 // ═══════════════════════════════════════════════════════════════════════════
@@ -397,6 +525,7 @@ type HelpRequestedEvent struct {
 	StudentID string `json:"student_id"`
 	TaskID    string `json:"task_id"`
 	Message   string `json:"message,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // Payload implements Event interface.
@@ -405,6 +534,7 @@ func (e HelpRequestedEvent) Payload() map[string]interface{} {
 		"student_id": e.StudentID,
 		"task_id":    e.TaskID,
 		"message":    e.Message,
+		"request_id": e.RequestID,
 	}
 }
 
@@ -418,6 +548,67 @@ func NewHelpRequestedEvent(studentID, taskID, message string) HelpRequestedEvent
 	}
 }
 
+// HelperAssignedEvent is emitted when a helper takes a help request.
+type HelperAssignedEvent struct {
+	BaseEvent
+	RequestID   string `json:"request_id"`
+	RequesterID string `json:"requester_id"`
+	HelperID    string `json:"helper_id"`
+	TaskID      string `json:"task_id"`
+}
+
+// Payload implements Event interface.
+func (e HelperAssignedEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{
+		"request_id":   e.RequestID,
+		"requester_id": e.RequesterID,
+		"helper_id":    e.HelperID,
+		"task_id":      e.TaskID,
+	}
+}
+
+// NewHelperAssignedEvent creates a new HelperAssignedEvent.
+func NewHelperAssignedEvent(requestID, requesterID, helperID, taskID string) HelperAssignedEvent {
+	return HelperAssignedEvent{
+		BaseEvent:   NewBaseEvent(EventHelperAssigned, requestID),
+		RequestID:   requestID,
+		RequesterID: requesterID,
+		HelperID:    helperID,
+		TaskID:      taskID,
+	}
+}
+
+// HelpRequestResolvedEvent is emitted when a help request is resolved,
+// with or without a helper.
+type HelpRequestResolvedEvent struct {
+	BaseEvent
+	RequestID   string `json:"request_id"`
+	RequesterID string `json:"requester_id"`
+	HelperID    string `json:"helper_id,omitempty"`
+	TaskID      string `json:"task_id"`
+}
+
+// Payload implements Event interface.
+func (e HelpRequestResolvedEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{
+		"request_id":   e.RequestID,
+		"requester_id": e.RequesterID,
+		"helper_id":    e.HelperID,
+		"task_id":      e.TaskID,
+	}
+}
+
+// NewHelpRequestResolvedEvent creates a new HelpRequestResolvedEvent.
+func NewHelpRequestResolvedEvent(requestID, requesterID, helperID, taskID string) HelpRequestResolvedEvent {
+	return HelpRequestResolvedEvent{
+		BaseEvent:   NewBaseEvent(EventHelpRequestResolved, requestID),
+		RequestID:   requestID,
+		RequesterID: requesterID,
+		HelperID:    helperID,
+		TaskID:      taskID,
+	}
+}
+
 // HelpProvidedEvent is emitted when a student provides help to another.
 type HelpProvidedEvent struct {
 	BaseEvent
@@ -507,6 +698,34 @@ func NewEndorsementGivenEvent(fromID, toID string, rating int) EndorsementGivenE
 	}
 }
 
+// HelperOfTheWeekEvent is emitted once a week for the student who received
+// the most endorsements in the finished week.
+type HelperOfTheWeekEvent struct {
+	BaseEvent
+	StudentID    string `json:"student_id"`
+	Week         string `json:"week"` // ISO week, e.g. "2026-W41"
+	Endorsements int    `json:"endorsements"`
+}
+
+// Payload implements Event interface.
+func (e HelperOfTheWeekEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{
+		"student_id":   e.StudentID,
+		"week":         e.Week,
+		"endorsements": e.Endorsements,
+	}
+}
+
+// NewHelperOfTheWeekEvent creates a new HelperOfTheWeekEvent.
+func NewHelperOfTheWeekEvent(studentID, week string, endorsements int) HelperOfTheWeekEvent {
+	return HelperOfTheWeekEvent{
+		BaseEvent:    NewBaseEvent(EventHelperOfTheWeek, studentID),
+		StudentID:    studentID,
+		Week:         week,
+		Endorsements: endorsements,
+	}
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // System Events
 // ═══════════════════════════════════════════════════════════════════════════
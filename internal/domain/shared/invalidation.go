@@ -0,0 +1,77 @@
+package shared
+
+import "time"
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Cache Invalidations
+// The worker tells bot instances which in-process caches went stale. The
+// schema is versioned: fields may be added within a version, anything that
+// changes the meaning of a message bumps CacheInvalidationVersion, and
+// consumers drop messages of versions they do not know.
+// ═══════════════════════════════════════════════════════════════════════════
+
+// CacheInvalidationVersion is the current schema version of CacheInvalidation.
+const CacheInvalidationVersion = 1
+
+// InvalidationKind is what changed.
+type InvalidationKind string
+
+const (
+	// InvalidationStudentUpdated - a student's data changed.
+	InvalidationStudentUpdated InvalidationKind = "student_updated"
+
+	// InvalidationStudentDeleted - a student left or was merged away.
+	InvalidationStudentDeleted InvalidationKind = "student_deleted"
+
+	// InvalidationLeaderboardRebuilt - a cohort leaderboard was rebuilt.
+	InvalidationLeaderboardRebuilt InvalidationKind = "leaderboard_rebuilt"
+
+	// InvalidationCohortArchived - a cohort was archived.
+	InvalidationCohortArchived InvalidationKind = "cohort_archived"
+)
+
+// CacheInvalidation is a message about stale cached data.
+type CacheInvalidation struct {
+	Version    int              `json:"v"`
+	Kind       InvalidationKind `json:"kind"`
+	StudentID  string           `json:"student_id,omitempty"`
+	TelegramID int64            `json:"telegram_id,omitempty"`
+	Cohort     string           `json:"cohort,omitempty"`
+	At         time.Time        `json:"at"`
+}
+
+// Supported reports whether the message has a schema version this build
+// understands.
+func (m CacheInvalidation) Supported() bool {
+	return m.Version >= 1 && m.Version <= CacheInvalidationVersion
+}
+
+// NewStudentInvalidation creates a student_updated or student_deleted message.
+func NewStudentInvalidation(kind InvalidationKind, studentID string, telegramID int64) CacheInvalidation {
+	return CacheInvalidation{
+		Version:    CacheInvalidationVersion,
+		Kind:       kind,
+		StudentID:  studentID,
+		TelegramID: telegramID,
+		At:         time.Now().UTC(),
+	}
+}
+
+// NewCohortInvalidation creates a leaderboard_rebuilt or cohort_archived message.
+func NewCohortInvalidation(kind InvalidationKind, cohort string) CacheInvalidation {
+	return CacheInvalidation{
+		Version: CacheInvalidationVersion,
+		Kind:    kind,
+		Cohort:  cohort,
+		At:      time.Now().UTC(),
+	}
+}
+
+// InvalidationTarget is an in-process cache that applies invalidations.
+type InvalidationTarget interface {
+	// Invalidate drops the entries the message makes stale.
+	Invalidate(msg CacheInvalidation)
+
+	// InvalidateAll drops every entry; used when messages may have been lost.
+	InvalidateAll()
+}
@@ -0,0 +1,346 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// USER CONTENT (пользовательский текст)
+// Описание запроса помощи и комментарий благодарности - свободный текст,
+// который бот пересылает другим студентам. При записи текст очищается
+// (CleanUserText), при показе - обезвреживается (RenderUserText): ссылки-
+// приглашения и упоминания скрываются, если у отправителя и получателя нет
+// активной связи, ссылок не больше MaxLinksPerMessage, фразы из чёрного
+// списка маскируются.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// MaxHelpDescriptionLength - максимальная длина описания запроса помощи (в символах).
+	MaxHelpDescriptionLength = 500
+
+	// MaxEndorsementCommentLength - максимальная длина комментария благодарности.
+	MaxEndorsementCommentLength = 300
+
+	// MaxLinksPerMessage - сколько ссылок показывается в одном тексте.
+	MaxLinksPerMessage = 2
+
+	// MaxBlockedPhrases - максимальный размер чёрного списка.
+	MaxBlockedPhrases = 500
+
+	// MaxBlockedPhraseLength - максимальная длина фразы чёрного списка.
+	MaxBlockedPhraseLength = 100
+
+	// HiddenLinkPlaceholder заменяет скрытые ссылки и упоминания.
+	HiddenLinkPlaceholder = "[ссылка скрыта]"
+
+	// BlockedPhraseMask заменяет фразы из чёрного списка.
+	BlockedPhraseMask = "***"
+
+	// maxCombiningMarks - сколько диакритик подряд остаётся у символа
+	// (защита от "zalgo"-текста).
+	maxCombiningMarks = 2
+)
+
+// ErrInvalidBlocklist - чёрный список не прошёл проверку.
+var ErrInvalidBlocklist = errors.New("invalid content blocklist")
+
+// BlockedPhraseRepository хранит чёрный список фраз.
+type BlockedPhraseRepository interface {
+	// List возвращает все фразы.
+	List(ctx context.Context) ([]string, error)
+
+	// Replace заменяет весь список.
+	Replace(ctx context.Context, phrases []string) error
+}
+
+// ContentBlocklist отдаёт актуальный чёрный список для показа текста.
+type ContentBlocklist interface {
+	// Phrases возвращает нормализованные фразы (nil - список недоступен).
+	Phrases(ctx context.Context) []string
+}
+
+// CleanUserText очищает текст при записи: убирает управляющие и невидимые
+// символы, схлопывает повторяющиеся пробелы и пустые строки и обрезает
+// текст до maxRunes символов.
+func CleanUserText(text string, maxRunes int) string {
+	if !utf8.ValidString(text) {
+		text = strings.ToValidUTF8(text, "")
+	}
+
+	var b strings.Builder
+	b.Grow(min(len(text), maxRunes*utf8.UTFMax))
+
+	pendingSpace := false
+	newlines := 0
+	marks := 0
+	written := 0
+
+	for _, r := range text {
+		switch {
+		case r == '\n':
+			if written > 0 {
+				newlines++
+			}
+			pendingSpace = false
+			continue
+		case unicode.IsSpace(r):
+			if written > 0 && newlines == 0 {
+				pendingSpace = true
+			}
+			continue
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r), unicode.Is(unicode.Co, r):
+			// Невидимые символы (zero-width, bidi) и private use
+			continue
+		case unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r):
+			marks++
+			if marks > maxCombiningMarks {
+				continue
+			}
+		default:
+			marks = 0
+		}
+
+		switch {
+		case newlines > 0:
+			// Не больше одной пустой строки подряд
+			b.WriteString(strings.Repeat("\n", min(newlines, 2)))
+			written += min(newlines, 2)
+			newlines = 0
+		case pendingSpace:
+			b.WriteByte(' ')
+			written++
+		}
+		pendingSpace = false
+
+		b.WriteRune(r)
+		written++
+		if written > maxRunes {
+			break
+		}
+	}
+
+	return truncateRunes(b.String(), maxRunes)
+}
+
+// truncateRunes обрезает текст до maxRunes символов с многоточием.
+func truncateRunes(text string, maxRunes int) string {
+	if maxRunes <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(text) <= maxRunes {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimRightFunc(string(runes[:maxRunes-1]), unicode.IsSpace) + "…"
+}
+
+// RenderOptions - параметры показа пользовательского текста.
+type RenderOptions struct {
+	// Trusted - у отправителя и получателя есть активная связь:
+	// ссылки-приглашения и упоминания показываются.
+	Trusted bool
+
+	// Blocklist - нормализованные фразы для маскировки (NormalizeBlocklist).
+	Blocklist []string
+
+	// MaxRunes - максимальная длина текста (0 - MaxHelpDescriptionLength).
+	MaxRunes int
+}
+
+// RenderUserText обезвреживает текст для показа другому студенту.
+// Результат - простой текст; для HTML см. RenderUserHTML.
+func RenderUserText(text string, opts RenderOptions) string {
+	maxRunes := opts.MaxRunes
+	if maxRunes <= 0 {
+		maxRunes = MaxHelpDescriptionLength
+	}
+
+	text = CleanUserText(text, maxRunes)
+	text = maskBlockedPhrases(text, opts.Blocklist)
+	text = neutralizeLinks(text, opts.Trusted)
+	return text
+}
+
+// RenderUserHTML - RenderUserText для сообщений с разметкой HTML.
+func RenderUserHTML(text string, opts RenderOptions) string {
+	return html.EscapeString(RenderUserText(text, opts))
+}
+
+// linkStartPattern находит начало ссылки, в том числе без схемы и
+// приклеенной к слову (wordt.me/...).
+var linkStartPattern = regexp.MustCompile(`(?i)(?:https?://|tg://|www\.|(?:t|telegram)\.(?:me|dog)/|discord\.(?:gg|com/invite)/|chat\.whatsapp\.com/)`)
+
+// schemePattern - начало ссылки, которое всегда открывает новую ссылку,
+// даже внутри предыдущей (https://a.b/https://c.d/ - две ссылки).
+var schemePattern = regexp.MustCompile(`(?i)^(?:https?|tg)://`)
+
+// invitePattern - ссылки-приглашения в чаты и каналы.
+var invitePattern = regexp.MustCompile(`(?i)(?:(?:t|telegram)\.(?:me|dog)/|tg://(?:join|resolve)|discord\.(?:gg|com/invite)/|chat\.whatsapp\.com/)`)
+
+// mentionPattern - упоминания Telegram (@username).
+var mentionPattern = regexp.MustCompile(`@[A-Za-z][A-Za-z0-9_]{4,31}`)
+
+// findLinks возвращает границы ссылок в тексте.
+func findLinks(text string) [][2]int {
+	var links [][2]int
+	end := -1
+	for _, m := range linkStartPattern.FindAllStringIndex(text, -1) {
+		if m[0] < end {
+			if !schemePattern.MatchString(text[m[0]:m[1]]) {
+				continue
+			}
+			links[len(links)-1][1] = m[0]
+		}
+
+		end = len(text)
+		if i := strings.IndexFunc(text[m[1]:], isLinkTerminator); i >= 0 {
+			end = m[1] + i
+		}
+		links = append(links, [2]int{m[0], end})
+	}
+	return links
+}
+
+// isLinkTerminator сообщает, заканчивает ли символ ссылку.
+func isLinkTerminator(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(`<>"'`, r)
+}
+
+// neutralizeLinks скрывает приглашения и упоминания от незнакомых
+// отправителей и ссылки сверх MaxLinksPerMessage.
+func neutralizeLinks(text string, trusted bool) string {
+	var b strings.Builder
+	last, shown := 0, 0
+	for _, link := range findLinks(text) {
+		b.WriteString(text[last:link[0]])
+		last = link[1]
+
+		url := text[link[0]:link[1]]
+		if (!trusted && invitePattern.MatchString(url)) || shown >= MaxLinksPerMessage {
+			b.WriteString(HiddenLinkPlaceholder)
+			continue
+		}
+		shown++
+		b.WriteString(url)
+	}
+	b.WriteString(text[last:])
+	text = b.String()
+
+	if trusted {
+		return text
+	}
+
+	// Упоминание - это @ в начале слова (не email)
+	matches := mentionPattern.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+	b.Reset()
+	last = 0
+	for _, m := range matches {
+		if m[0] > 0 {
+			prev, _ := utf8.DecodeLastRuneInString(text[:m[0]])
+			if unicode.IsLetter(prev) || unicode.IsDigit(prev) || prev == '_' || prev == '.' {
+				continue
+			}
+		}
+		b.WriteString(text[last:m[0]])
+		b.WriteString(HiddenLinkPlaceholder)
+		last = m[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// maskBlockedPhrases маскирует фразы из чёрного списка без учёта регистра.
+func maskBlockedPhrases(text string, blocklist []string) string {
+	if len(blocklist) == 0 || text == "" {
+		return text
+	}
+
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	masked := make([]bool, len(runes))
+	found := false
+	for _, phrase := range blocklist {
+		p := []rune(phrase)
+		if len(p) == 0 {
+			continue
+		}
+		for i := 0; i+len(p) <= len(lower); i++ {
+			if equalRunes(lower[i:i+len(p)], p) {
+				for j := i; j < i+len(p); j++ {
+					masked[j] = true
+				}
+				found = true
+			}
+		}
+	}
+	if !found {
+		return text
+	}
+
+	var b strings.Builder
+	for i, r := range runes {
+		if !masked[i] {
+			b.WriteRune(r)
+			continue
+		}
+		if i == 0 || !masked[i-1] {
+			b.WriteString(BlockedPhraseMask)
+		}
+	}
+	return b.String()
+}
+
+// equalRunes сравнивает два среза символов.
+func equalRunes(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeBlocklist проверяет и нормализует чёрный список: нижний регистр,
+// без повторов и пустых строк, по алфавиту.
+func NormalizeBlocklist(phrases []string) ([]string, error) {
+	if len(phrases) > MaxBlockedPhrases {
+		return nil, fmt.Errorf("%w: at most %d phrases", ErrInvalidBlocklist, MaxBlockedPhrases)
+	}
+
+	seen := make(map[string]bool, len(phrases))
+	normalized := make([]string, 0, len(phrases))
+	for _, phrase := range phrases {
+		if utf8.RuneCountInString(phrase) > MaxBlockedPhraseLength {
+			return nil, fmt.Errorf("%w: phrase longer than %d characters", ErrInvalidBlocklist, MaxBlockedPhraseLength)
+		}
+		p := strings.ToLower(CleanUserText(strings.ReplaceAll(phrase, "\n", " "), MaxBlockedPhraseLength))
+		if utf8.RuneCountInString(p) < 2 {
+			if p != "" {
+				return nil, fmt.Errorf("%w: phrase %q is too short", ErrInvalidBlocklist, p)
+			}
+			continue
+		}
+		if !seen[p] {
+			seen[p] = true
+			normalized = append(normalized, p)
+		}
+	}
+
+	sort.Strings(normalized)
+	return normalized, nil
+}
@@ -0,0 +1,147 @@
+package social
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hostileInputs are texts a spammer or a broken client could send.
+func hostileInputs() []string {
+	inputs := []string{
+		"<b><i><a href=\"https://evil.example\">click</a></i></b>",
+		"<<script>alert(1)</script>>",
+		"</code><pre><a href='tg://join?invite=abc'>x</a></pre>",
+		"&lt;b&gt; &amp;amp; &#60;",
+		"he\u200bllo\u200d w\u2060orld\ufeff \u202eevil\u202c",
+		"line\r\n\r\n\r\n\r\n\r\nline\t\t\t   end\x00\x07\x1b[31m",
+		"t.me/joinchat/AAAA t.me/+BBBB https://t.me/spam telegram.me/joinchat/x discord.gg/abc chat.whatsapp.com/zzz",
+		"@spammer1 @spammer2 @spammer3 write me",
+		"Z\u0334\u0321\u0322\u031b\u031b\u0316\u0317\u0318\u0319\u031c\u031d\u031e\u031f\u0320\u0347\u0348\u0349\u034d\u034e\u0308\u0301\u0313\u0314\u033d\u033e\u033f\u0300\u0301\u0342\u0313\u0308\u0301\u0346\u034a\u034b\u034c\u0345algo",
+		strings.Repeat("a", 10_000),
+		strings.Repeat("я ", 10_000),
+		strings.Repeat("\u200b", 10_000),
+		strings.Repeat("https://spam.example/ ", 500),
+		strings.Repeat("<b>", 3_000) + strings.Repeat("</b>", 3_000),
+		"\xff\xfe invalid utf8 \xc3\x28",
+	}
+
+	// Random mixes of the pieces above
+	rng := rand.New(rand.NewSource(1))
+	pieces := []string{"<", ">", "&", "\"", "'", "\u200b", "\u202e", "\n", " ", "\t", "t.me/+x", "https://a.b/", "@someone", "é", "\u0301", "\x00", "word"}
+	for i := 0; i < 200; i++ {
+		var b strings.Builder
+		for j := rng.Intn(400); j > 0; j-- {
+			b.WriteString(pieces[rng.Intn(len(pieces))])
+		}
+		inputs = append(inputs, b.String())
+	}
+	return inputs
+}
+
+func TestCleanUserText_Hostile(t *testing.T) {
+	for _, input := range hostileInputs() {
+		out := CleanUserText(input, MaxHelpDescriptionLength)
+
+		require.True(t, utf8.ValidString(out))
+		assert.LessOrEqual(t, utf8.RuneCountInString(out), MaxHelpDescriptionLength)
+		assert.Equal(t, strings.TrimSpace(out), out)
+		assert.NotContains(t, out, "  ")
+		assert.NotContains(t, out, "\n\n\n")
+		for _, r := range out {
+			assert.False(t, r != '\n' && unicode.IsControl(r), "control character %U", r)
+			assert.False(t, unicode.Is(unicode.Cf, r), "format character %U", r)
+		}
+
+		// Cleaning is idempotent
+		assert.Equal(t, out, CleanUserText(out, MaxHelpDescriptionLength))
+	}
+}
+
+func TestCleanUserText(t *testing.T) {
+	assert.Equal(t, "hello world", CleanUserText("  hel\u200blo \t\t world \u202e ", 100))
+	assert.Equal(t, "a\n\nb", CleanUserText("a  \n\n\n\n  b", 100))
+	assert.Equal(t, "abcd…", CleanUserText("abcdefgh", 5))
+	assert.Equal(t, "", CleanUserText("\u200b \n\t", 100))
+}
+
+func TestRenderUserHTML_Hostile(t *testing.T) {
+	blocklist, err := NormalizeBlocklist([]string{"Buy Followers", "казино"})
+	require.NoError(t, err)
+
+	for _, trusted := range []bool{false, true} {
+		opts := RenderOptions{Trusted: trusted, Blocklist: blocklist}
+		for _, input := range hostileInputs() {
+			out := RenderUserHTML(input, opts)
+
+			// No markup survives escaping
+			assert.NotContains(t, out, "<")
+			assert.NotContains(t, out, ">")
+			assert.NotContains(t, out, "\"")
+
+			// Bounded: entities and placeholders at most quintuple the cleaned text
+			assert.LessOrEqual(t, utf8.RuneCountInString(out), MaxHelpDescriptionLength*len("&#34;"))
+
+			plain := RenderUserText(input, opts)
+			assert.LessOrEqual(t, len(findLinks(plain)), MaxLinksPerMessage)
+			if !trusted {
+				assert.NotRegexp(t, `(?i)(t\.me|telegram\.me|discord\.gg|whatsapp\.com)/`, plain)
+				assert.NotRegexp(t, `(^|\s)@[A-Za-z][A-Za-z0-9_]{4}`, plain)
+			}
+		}
+	}
+}
+
+func TestRenderUserText_Links(t *testing.T) {
+	text := "join t.me/joinchat/abc or https://t.me/+xyz, docs https://go.dev and https://pkg.go.dev and https://third.example"
+
+	untrusted := RenderUserText(text, RenderOptions{})
+	assert.NotContains(t, untrusted, "t.me")
+	assert.Contains(t, untrusted, "https://go.dev")
+	assert.Contains(t, untrusted, "https://pkg.go.dev")
+	assert.NotContains(t, untrusted, "third.example")
+	assert.Equal(t, 3, strings.Count(untrusted, HiddenLinkPlaceholder))
+
+	// Connected students see the invite links, still at most two links
+	trusted := RenderUserText(text, RenderOptions{Trusted: true})
+	assert.Contains(t, trusted, "t.me/joinchat/abc")
+	assert.Contains(t, trusted, "https://t.me/+xyz")
+	assert.NotContains(t, trusted, "go.dev")
+}
+
+func TestRenderUserText_Mentions(t *testing.T) {
+	out := RenderUserText("ping @spammer_bot, mail me at dana@example.com", RenderOptions{})
+	assert.Equal(t, "ping "+HiddenLinkPlaceholder+", mail me at dana@example.com", out)
+
+	out = RenderUserText("ping @friend_01", RenderOptions{Trusted: true})
+	assert.Equal(t, "ping @friend_01", out)
+}
+
+func TestRenderUserText_Blocklist(t *testing.T) {
+	blocklist, err := NormalizeBlocklist([]string{"  Buy FOLLOWERS ", "казино", "казино"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"buy followers", "казино"}, blocklist)
+
+	out := RenderUserText("Лучшее КАЗИНО! buy\u200b followers now", RenderOptions{Blocklist: blocklist})
+	assert.Equal(t, "Лучшее ***! *** now", out)
+}
+
+func TestNormalizeBlocklist_Invalid(t *testing.T) {
+	_, err := NormalizeBlocklist([]string{strings.Repeat("a", MaxBlockedPhraseLength+1)})
+	assert.ErrorIs(t, err, ErrInvalidBlocklist)
+
+	_, err = NormalizeBlocklist([]string{"x"})
+	assert.ErrorIs(t, err, ErrInvalidBlocklist)
+
+	_, err = NormalizeBlocklist(make([]string, MaxBlockedPhrases+1))
+	assert.ErrorIs(t, err, ErrInvalidBlocklist)
+
+	phrases, err := NormalizeBlocklist([]string{"", " \u200b "})
+	require.NoError(t, err)
+	assert.Empty(t, phrases)
+}
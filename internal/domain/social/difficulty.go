@@ -0,0 +1,112 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// TASK DIFFICULTY
+// Сложность задачи по плотности запросов помощи: сколько запросов
+// приходится на 100 решений за последние 30 дней. Считается раз в день
+// воркером и подсказывает студенту, что с задачей застревают многие,
+// а подбору помощников - что здесь важнее опытный помощник.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// DifficultyWindow - за какой период считаются запросы и решения.
+	DifficultyWindow = 30 * 24 * time.Hour
+
+	// MinDifficultySample - минимум решений за период, с которого задаче
+	// присваивается оценка. На меньшей выборке один запрос даёт шум.
+	MinDifficultySample = 10
+
+	// HighDifficultyScore - оценка, начиная с которой задача считается
+	// сложной (каждый четвёртый решивший просил помощь).
+	HighDifficultyScore = 25.0
+)
+
+// ErrTaskDifficultyNotFound - для задачи ещё нет рассчитанной сложности.
+var ErrTaskDifficultyNotFound = errors.New("task difficulty not found")
+
+// TaskHelpDensity - исходные счётчики по задаче за период.
+type TaskHelpDensity struct {
+	// TaskID - ID задачи.
+	TaskID TaskID
+
+	// HelpRequests - запросы помощи по задаче.
+	HelpRequests int
+
+	// Completions - решения задачи.
+	Completions int
+}
+
+// TaskDifficulty - рассчитанная сложность задачи.
+type TaskDifficulty struct {
+	// TaskID - ID задачи.
+	TaskID TaskID
+
+	// HelpRequests - запросы помощи за период.
+	HelpRequests int
+
+	// Completions - решения за период.
+	Completions int
+
+	// Score - запросов на 100 решений; имеет смысл только при Scored.
+	Score float64
+
+	// Scored - решений достаточно для оценки (MinDifficultySample).
+	Scored bool
+
+	// ComputedAt - когда рассчитано.
+	ComputedAt time.Time
+}
+
+// ComputeDifficultyScore возвращает число запросов помощи на 100 решений,
+// округлённое до десятых. ok = false, если решений меньше
+// MinDifficultySample: оценка на такой выборке не присваивается.
+func ComputeDifficultyScore(helpRequests, completions int) (score float64, ok bool) {
+	if completions < MinDifficultySample || helpRequests < 0 {
+		return 0, false
+	}
+	raw := float64(helpRequests) * 100 / float64(completions)
+	return math.Round(raw*10) / 10, true
+}
+
+// NewTaskDifficulty рассчитывает сложность задачи по счётчикам.
+func NewTaskDifficulty(density TaskHelpDensity, computedAt time.Time) TaskDifficulty {
+	score, ok := ComputeDifficultyScore(density.HelpRequests, density.Completions)
+	return TaskDifficulty{
+		TaskID:       density.TaskID,
+		HelpRequests: density.HelpRequests,
+		Completions:  density.Completions,
+		Score:        score,
+		Scored:       ok,
+		ComputedAt:   computedAt.UTC(),
+	}
+}
+
+// IsHigh проверяет, что задача оценена и считается сложной.
+func (d TaskDifficulty) IsHigh() bool {
+	return d.Scored && d.Score >= HighDifficultyScore
+}
+
+// TaskDifficultyRepository хранит рассчитанную сложность задач.
+type TaskDifficultyRepository interface {
+	// GetHelpDensity возвращает число запросов помощи и решений по каждой
+	// задаче, у которой с since было хотя бы одно из них.
+	GetHelpDensity(ctx context.Context, since time.Time) ([]TaskHelpDensity, error)
+
+	// ReplaceAll заменяет все рассчитанные значения одним снимком.
+	ReplaceAll(ctx context.Context, difficulties []TaskDifficulty) error
+
+	// GetByTaskID возвращает сложность задачи.
+	// Возвращает ErrTaskDifficultyNotFound, если её ещё не считали.
+	GetByTaskID(ctx context.Context, taskID TaskID) (*TaskDifficulty, error)
+
+	// List возвращает до limit задач: оценённые по убыванию оценки,
+	// затем неоценённые по числу запросов.
+	List(ctx context.Context, limit int) ([]TaskDifficulty, error)
+}
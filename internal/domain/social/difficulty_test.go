@@ -0,0 +1,54 @@
+package social
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeDifficultyScore(t *testing.T) {
+	tests := []struct {
+		name        string
+		requests    int
+		completions int
+		want        float64
+		wantOK      bool
+	}{
+		{"no activity", 0, 0, 0, false},
+		{"requests without completions", 7, 0, 0, false},
+		{"one below min sample", 5, MinDifficultySample - 1, 0, false},
+		{"exactly min sample", 5, MinDifficultySample, 50, true},
+		{"no requests", 0, 40, 0, true},
+		{"more requests than completions", 30, 10, 300, true},
+		{"rounded to tenths", 1, 30, 3.3, true},
+		{"negative requests", -1, 20, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ComputeDifficultyScore(tt.requests, tt.completions)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewTaskDifficulty_IsHigh(t *testing.T) {
+	now := time.Date(2026, time.May, 10, 4, 15, 0, 0, time.UTC)
+
+	// Ровно на пороге - уже сложная
+	d := NewTaskDifficulty(TaskHelpDensity{TaskID: "graph-01", HelpRequests: 5, Completions: 20}, now)
+	assert.True(t, d.Scored)
+	assert.Equal(t, HighDifficultyScore, d.Score)
+	assert.True(t, d.IsHigh())
+
+	d = NewTaskDifficulty(TaskHelpDensity{TaskID: "graph-01", HelpRequests: 4, Completions: 20}, now)
+	assert.False(t, d.IsHigh())
+
+	// Много запросов на маленькой выборке - оценки нет
+	d = NewTaskDifficulty(TaskHelpDensity{TaskID: "graph-02", HelpRequests: 8, Completions: 2}, now)
+	assert.False(t, d.Scored)
+	assert.False(t, d.IsHigh())
+	assert.Equal(t, 8, d.HelpRequests)
+}
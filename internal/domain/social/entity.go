@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -89,6 +91,12 @@ func (c ConnectionType) IsBidirectional() bool {
 	return c == ConnectionTypeStudyBuddy || c == ConnectionTypeCoworker
 }
 
+// AllowsNudges возвращает true, если участники связи могут подбадривать
+// друг друга перед вехой: напарники и ментор с подопечным.
+func (c ConnectionType) AllowsNudges() bool {
+	return c == ConnectionTypeStudyBuddy || c == ConnectionTypeMentor
+}
+
 // ConnectionStatus определяет статус связи.
 type ConnectionStatus string
 
@@ -211,6 +219,16 @@ func (p HelpRequestPriority) Weight() int {
 // EndorsementType определяет тип благодарности.
 type EndorsementType string
 
+const (
+	// EndorsementReminderDelay - через сколько после решения запроса без
+	// благодарности отправляется напоминание.
+	EndorsementReminderDelay = 20 * time.Hour
+
+	// EndorsementPromptTTL - сколько после решения запроса можно
+	// поблагодарить помощника кнопками.
+	EndorsementPromptTTL = 7 * 24 * time.Hour
+)
+
 const (
 	// EndorsementTypeClear - понятно объяснил.
 	EndorsementTypeClear EndorsementType = "clear"
@@ -323,6 +341,13 @@ var (
 	// ErrHelpRequestAlreadyMatched - помощник уже назначен.
 	ErrHelpRequestAlreadyMatched = errors.New("help request already has a helper")
 
+	// ErrHelpRequestDuplicate - у студента уже есть открытый запрос по задаче.
+	ErrHelpRequestDuplicate = errors.New("open help request for the task already exists")
+
+	// ErrHelpRequestLimit - у студента слишком много открытых запросов
+	// (см. HelpRequestLimitError).
+	ErrHelpRequestLimit = errors.New("too many open help requests")
+
 	// ErrEndorsementNotFound - благодарность не найдена.
 	ErrEndorsementNotFound = errors.New("endorsement not found")
 
@@ -335,6 +360,9 @@ var (
 	// ErrEndorsementSelfEndorse - нельзя благодарить самого себя.
 	ErrEndorsementSelfEndorse = errors.New("cannot endorse yourself")
 
+	// ErrEndorsementPromptExpired - запрос больше нельзя отметить благодарностью.
+	ErrEndorsementPromptExpired = errors.New("help request is no longer endorsable")
+
 	// ErrInvalidStudentID - невалидный ID студента.
 	ErrInvalidStudentID = errors.New("invalid student id")
 
@@ -342,6 +370,23 @@ var (
 	ErrInvalidTaskID = errors.New("invalid task id")
 )
 
+// HelpRequestLimitError - у студента уже Limit открытых запросов помощи.
+// errors.Is(err, ErrHelpRequestLimit) для неё истинно.
+type HelpRequestLimitError struct {
+	// Limit - сколько открытых запросов может быть одновременно.
+	Limit int
+}
+
+// Error реализует error.
+func (e *HelpRequestLimitError) Error() string {
+	return fmt.Sprintf("%s (limit %d)", ErrHelpRequestLimit, e.Limit)
+}
+
+// Unwrap возвращает ErrHelpRequestLimit.
+func (e *HelpRequestLimitError) Unwrap() error {
+	return ErrHelpRequestLimit
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // ENTITY: CONNECTION
 // Представляет связь между двумя студентами.
@@ -622,7 +667,8 @@ type HelpRequest struct {
 	// TaskName - название задачи (для отображения).
 	TaskName string
 
-	// Description - описание проблемы (опционально).
+	// Description - описание проблемы (опционально). Очищено при создании
+	// (CleanUserText); показывать другим через RenderUserText.
 	Description string
 
 	// Priority - приоритет запроса.
@@ -654,6 +700,39 @@ type HelpRequest struct {
 
 	// Resolution - как была решена проблема.
 	Resolution *HelpResolution
+
+	// FirstMatchedAt - когда впервые нашлись потенциальные помощники.
+	// Устанавливается один раз и больше не меняется.
+	FirstMatchedAt *time.Time
+
+	// FirstHelperResponseAt - когда помощник впервые отреагировал
+	// (принял запрос или написал). Устанавливается один раз.
+	FirstHelperResponseAt *time.Time
+
+	// EscalatedAt - когда срочный запрос без ответа передан менторам.
+	EscalatedAt *time.Time
+
+	// EndorsementReminderSentAt - когда студенту напомнили поблагодарить
+	// помощника. Напоминание отправляется не больше одного раза.
+	EndorsementReminderSentAt *time.Time
+
+	// FeedbackPollSentAt - когда студенту отправили закрытый опрос о
+	// полезности помощи. Опрос отправляется не больше одного раза.
+	FeedbackPollSentAt *time.Time
+
+	// ClusterID - кластер одинаковых запросов ("" - запрос создан до
+	// появления кластеров). См. help_cluster.go.
+	ClusterID string
+
+	// GroupOptInAt - когда студент согласился делиться контактом с группой
+	// кластера (nil - не соглашался).
+	GroupOptInAt *time.Time
+
+	// Members - приглашённые однокомандники (см. help_team.go).
+	Members []HelpRequestMember
+
+	// clock - источник текущего времени; nil - системные часы.
+	clock shared.Clock
 }
 
 // MatchedHelper представляет потенциального помощника.
@@ -724,6 +803,9 @@ type NewHelpRequestParams struct {
 	Description string
 	Priority    HelpRequestPriority
 	DeadlineAt  *time.Time
+
+	// Clock - источник текущего времени (nil - системные часы).
+	Clock shared.Clock
 }
 
 // NewHelpRequest создаёт новый запрос помощи.
@@ -752,7 +834,7 @@ func NewHelpRequest(params NewHelpRequestParams) (*HelpRequest, error) {
 		return nil, ErrHelpRequestInvalidPriority
 	}
 
-	now := time.Now().UTC()
+	now := shared.ClockOrSystem(params.Clock).Now()
 
 	// Запрос истекает через 24 часа по умолчанию
 	expiresAt := now.Add(24 * time.Hour)
@@ -762,7 +844,7 @@ func NewHelpRequest(params NewHelpRequestParams) (*HelpRequest, error) {
 		RequesterID:    params.RequesterID,
 		TaskID:         params.TaskID,
 		TaskName:       params.TaskName,
-		Description:    params.Description,
+		Description:    CleanUserText(params.Description, MaxHelpDescriptionLength),
 		Priority:       priority,
 		Status:         HelpRequestStatusOpen,
 		MatchedHelpers: make([]MatchedHelper, 0),
@@ -770,12 +852,25 @@ func NewHelpRequest(params NewHelpRequestParams) (*HelpRequest, error) {
 		ExpiresAt:      expiresAt,
 		CreatedAt:      now,
 		UpdatedAt:      now,
+		clock:          params.Clock,
 	}, nil
 }
 
+// WithClock задаёт часы для проверок истечения и отметок времени
+// (для запросов, загруженных из хранилища).
+func (h *HelpRequest) WithClock(clock shared.Clock) *HelpRequest {
+	h.clock = clock
+	return h
+}
+
+// now возвращает текущее время по часам запроса.
+func (h *HelpRequest) now() time.Time {
+	return shared.ClockOrSystem(h.clock).Now()
+}
+
 // AddMatchedHelper добавляет потенциального помощника.
 func (h *HelpRequest) AddMatchedHelper(helper MatchedHelper) error {
-	if helper.StudentID == h.RequesterID {
+	if h.IsMember(helper.StudentID) {
 		return ErrHelpRequestSelfHelp
 	}
 
@@ -786,9 +881,13 @@ func (h *HelpRequest) AddMatchedHelper(helper MatchedHelper) error {
 		}
 	}
 
+	now := h.now()
 	h.MatchedHelpers = append(h.MatchedHelpers, helper)
 	h.Status = HelpRequestStatusMatched
-	h.UpdatedAt = time.Now().UTC()
+	h.UpdatedAt = now
+	if h.FirstMatchedAt == nil {
+		h.FirstMatchedAt = &now
+	}
 	return nil
 }
 
@@ -798,7 +897,7 @@ func (h *HelpRequest) AssignHelper(helperID StudentID) error {
 		return ErrHelpRequestAlreadyClosed
 	}
 
-	if helperID == h.RequesterID {
+	if h.IsMember(helperID) {
 		return ErrHelpRequestSelfHelp
 	}
 
@@ -806,23 +905,119 @@ func (h *HelpRequest) AssignHelper(helperID StudentID) error {
 		return ErrHelpRequestAlreadyMatched
 	}
 
+	now := h.now()
 	h.HelperID = &helperID
 	h.Status = HelpRequestStatusInProgress
-	h.UpdatedAt = time.Now().UTC()
+	h.UpdatedAt = now
+	h.markFirstResponse(now)
 	return nil
 }
 
+// RecordHelperResponse фиксирует реакцию помощника (например, сообщение
+// студенту). Время первой реакции запоминается один раз; возвращает true,
+// если эта реакция первая.
+func (h *HelpRequest) RecordHelperResponse(helperID StudentID, at time.Time) (bool, error) {
+	if h.IsMember(helperID) {
+		return false, ErrHelpRequestSelfHelp
+	}
+	if h.Status.IsClosed() {
+		return false, nil
+	}
+
+	first := h.markFirstResponse(at.UTC())
+	if first {
+		h.UpdatedAt = at.UTC()
+	}
+	return first, nil
+}
+
+// markFirstResponse запоминает время первой реакции, если его ещё нет.
+func (h *HelpRequest) markFirstResponse(at time.Time) bool {
+	if h.FirstHelperResponseAt != nil {
+		return false
+	}
+	h.FirstHelperResponseAt = &at
+	return true
+}
+
+// FirstResponseTime возвращает время от создания запроса до первой реакции
+// помощника; false, если реакции ещё не было.
+func (h *HelpRequest) FirstResponseTime() (time.Duration, bool) {
+	if h.FirstHelperResponseAt == nil {
+		return 0, false
+	}
+	return h.FirstHelperResponseAt.Sub(h.CreatedAt), true
+}
+
+// NeedsEscalation проверяет, что срочный запрос остался без ответа дольше
+// window и ещё не передавался менторам.
+func (h *HelpRequest) NeedsEscalation(now time.Time, window time.Duration) bool {
+	return h.Priority == HelpRequestPriorityUrgent &&
+		h.IsOpen() &&
+		h.FirstHelperResponseAt == nil &&
+		h.EscalatedAt == nil &&
+		now.Sub(h.CreatedAt) >= window
+}
+
+// MarkEscalated помечает запрос как переданный менторам.
+func (h *HelpRequest) MarkEscalated(at time.Time) {
+	if h.EscalatedAt != nil {
+		return
+	}
+	at = at.UTC()
+	h.EscalatedAt = &at
+	h.UpdatedAt = at
+}
+
+// IsEndorsableAt проверяет, что за запрос ещё можно поблагодарить кнопками:
+// он решён с помощником и с решения прошло меньше EndorsementPromptTTL.
+// Наличие благодарности проверяется отдельно, через репозиторий.
+func (h *HelpRequest) IsEndorsableAt(now time.Time) bool {
+	return h.Status == HelpRequestStatusResolved &&
+		h.HelperID != nil &&
+		h.ResolvedAt != nil &&
+		now.Before(h.ResolvedAt.Add(EndorsementPromptTTL))
+}
+
+// NeedsEndorsementReminder проверяет, что пора напомнить о благодарности:
+// запрос решён не меньше EndorsementReminderDelay назад, его ещё можно
+// отметить и напоминание не отправлялось.
+func (h *HelpRequest) NeedsEndorsementReminder(now time.Time) bool {
+	return h.IsEndorsableAt(now) &&
+		h.EndorsementReminderSentAt == nil &&
+		now.Sub(*h.ResolvedAt) >= EndorsementReminderDelay
+}
+
+// MarkEndorsementReminderSent помечает, что напоминание отправлено.
+func (h *HelpRequest) MarkEndorsementReminderSent(at time.Time) {
+	if h.EndorsementReminderSentAt != nil {
+		return
+	}
+	at = at.UTC()
+	h.EndorsementReminderSentAt = &at
+	h.UpdatedAt = at
+}
+
 // Resolve помечает запрос как решённый.
 func (h *HelpRequest) Resolve(resolution HelpResolution) error {
 	if h.Status.IsClosed() {
 		return ErrHelpRequestAlreadyClosed
 	}
 
-	now := time.Now().UTC()
+	now := h.now()
 	h.Status = HelpRequestStatusResolved
 	h.ResolvedAt = &now
 	h.Resolution = &resolution
 	h.UpdatedAt = now
+	if h.HelperID == nil && resolution.HelperID != nil {
+		helperID := *resolution.HelperID
+		h.HelperID = &helperID
+	}
+
+	// Помощь без отдельного ответа - тоже реакция помощника
+	if resolution.HelperID != nil {
+		h.markFirstResponse(now)
+	}
 	return nil
 }
 
@@ -833,7 +1028,7 @@ func (h *HelpRequest) Cancel() error {
 	}
 
 	h.Status = HelpRequestStatusCancelled
-	h.UpdatedAt = time.Now().UTC()
+	h.UpdatedAt = h.now()
 	return nil
 }
 
@@ -844,13 +1039,13 @@ func (h *HelpRequest) MarkExpired() error {
 	}
 
 	h.Status = HelpRequestStatusExpired
-	h.UpdatedAt = time.Now().UTC()
+	h.UpdatedAt = h.now()
 	return nil
 }
 
 // IsExpired проверяет, истёк ли запрос.
 func (h *HelpRequest) IsExpired() bool {
-	return time.Now().After(h.ExpiresAt)
+	return h.now().After(h.ExpiresAt)
 }
 
 // IsOpen проверяет, открыт ли запрос.
@@ -864,7 +1059,7 @@ func (h *HelpRequest) HoursUntilDeadline() int {
 		return -1 // Нет дедлайна
 	}
 
-	hours := int(time.Until(*h.DeadlineAt).Hours())
+	hours := int(h.DeadlineAt.Sub(h.now()).Hours())
 	if hours < 0 {
 		return 0
 	}
@@ -920,9 +1115,50 @@ func (h *HelpRequest) Clone() *HelpRequest {
 		clone.Resolution = &resolution
 	}
 
+	if h.FirstMatchedAt != nil {
+		firstMatchedAt := *h.FirstMatchedAt
+		clone.FirstMatchedAt = &firstMatchedAt
+	}
+
+	if h.FirstHelperResponseAt != nil {
+		firstResponseAt := *h.FirstHelperResponseAt
+		clone.FirstHelperResponseAt = &firstResponseAt
+	}
+
+	if h.EscalatedAt != nil {
+		escalatedAt := *h.EscalatedAt
+		clone.EscalatedAt = &escalatedAt
+	}
+
+	if h.EndorsementReminderSentAt != nil {
+		reminderSentAt := *h.EndorsementReminderSentAt
+		clone.EndorsementReminderSentAt = &reminderSentAt
+	}
+
+	if h.FeedbackPollSentAt != nil {
+		pollSentAt := *h.FeedbackPollSentAt
+		clone.FeedbackPollSentAt = &pollSentAt
+	}
+
+	if h.GroupOptInAt != nil {
+		optInAt := *h.GroupOptInAt
+		clone.GroupOptInAt = &optInAt
+	}
+
 	clone.MatchedHelpers = make([]MatchedHelper, len(h.MatchedHelpers))
 	copy(clone.MatchedHelpers, h.MatchedHelpers)
 
+	if h.Members != nil {
+		clone.Members = make([]HelpRequestMember, len(h.Members))
+		for i, m := range h.Members {
+			if m.RespondedAt != nil {
+				respondedAt := *m.RespondedAt
+				m.RespondedAt = &respondedAt
+			}
+			clone.Members[i] = m
+		}
+	}
+
 	return &clone
 }
 
@@ -955,7 +1191,8 @@ type Endorsement struct {
 	// Rating - числовая оценка (1-5).
 	Rating Rating
 
-	// Comment - текстовый комментарий (опционально).
+	// Comment - текстовый комментарий (опционально). Очищен при создании
+	// (CleanUserText); показывать другим через RenderUserText.
 	Comment string
 
 	// IsPublic - показывать ли публично.
@@ -1020,7 +1257,7 @@ func NewEndorsement(params NewEndorsementParams) (*Endorsement, error) {
 		TaskID:        params.TaskID,
 		Type:          endorsementType,
 		Rating:        params.Rating,
-		Comment:       params.Comment,
+		Comment:       CleanUserText(params.Comment, MaxEndorsementCommentLength),
 		IsPublic:      params.IsPublic,
 		CreatedAt:     time.Now().UTC(),
 	}, nil
@@ -0,0 +1,100 @@
+package social
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+func newTestHelpRequest(t *testing.T, priority HelpRequestPriority) *HelpRequest {
+	t.Helper()
+	req, err := NewHelpRequest(NewHelpRequestParams{
+		ID:          "req-1",
+		RequesterID: "dana",
+		TaskID:      "graph-01",
+		TaskName:    "graph-01",
+		Priority:    priority,
+	})
+	require.NoError(t, err)
+	return req
+}
+
+func TestHelpRequest_FirstTimestampsAreSetOnce(t *testing.T) {
+	req := newTestHelpRequest(t, HelpRequestPriorityNormal)
+
+	_, answered := req.FirstResponseTime()
+	assert.False(t, answered)
+
+	require.NoError(t, req.AddMatchedHelper(MatchedHelper{StudentID: "ali"}))
+	require.NotNil(t, req.FirstMatchedAt)
+	firstMatched := *req.FirstMatchedAt
+
+	require.NoError(t, req.AddMatchedHelper(MatchedHelper{StudentID: "arman"}))
+	assert.Equal(t, firstMatched, *req.FirstMatchedAt)
+
+	// Первое сообщение помощника фиксирует время ответа
+	firstAt := req.CreatedAt.Add(12 * time.Minute)
+	first, err := req.RecordHelperResponse("ali", firstAt)
+	require.NoError(t, err)
+	assert.True(t, first)
+
+	// Последующие реакции его не меняют
+	first, err = req.RecordHelperResponse("arman", firstAt.Add(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, first)
+	require.NoError(t, req.AssignHelper("arman"))
+
+	responseTime, answered := req.FirstResponseTime()
+	assert.True(t, answered)
+	assert.Equal(t, 12*time.Minute, responseTime)
+
+	// Автор запроса не может "ответить" сам себе
+	_, err = req.RecordHelperResponse("dana", firstAt)
+	assert.ErrorIs(t, err, ErrHelpRequestSelfHelp)
+}
+
+func TestHelpRequest_NeedsEscalation(t *testing.T) {
+	window := 30 * time.Minute
+
+	urgent := newTestHelpRequest(t, HelpRequestPriorityUrgent)
+	assert.False(t, urgent.NeedsEscalation(urgent.CreatedAt.Add(29*time.Minute), window))
+	assert.True(t, urgent.NeedsEscalation(urgent.CreatedAt.Add(30*time.Minute), window))
+
+	urgent.MarkEscalated(urgent.CreatedAt.Add(30 * time.Minute))
+	assert.False(t, urgent.NeedsEscalation(urgent.CreatedAt.Add(time.Hour), window), "escalated once")
+
+	normal := newTestHelpRequest(t, HelpRequestPriorityNormal)
+	assert.False(t, normal.NeedsEscalation(normal.CreatedAt.Add(time.Hour), window))
+
+	answered := newTestHelpRequest(t, HelpRequestPriorityUrgent)
+	_, err := answered.RecordHelperResponse("ali", answered.CreatedAt.Add(5*time.Minute))
+	require.NoError(t, err)
+	assert.False(t, answered.NeedsEscalation(answered.CreatedAt.Add(time.Hour), window))
+}
+
+func TestHelpRequest_IsExpiredAtBoundary(t *testing.T) {
+	clock := shared.NewFakeClock(time.Date(2026, time.May, 11, 9, 0, 0, 0, time.UTC))
+	req, err := NewHelpRequest(NewHelpRequestParams{
+		ID:          "req-1",
+		RequesterID: "dana",
+		TaskID:      "graph-01",
+		TaskName:    "graph-01",
+		Clock:       clock,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, clock.Now().Add(24*time.Hour), req.ExpiresAt)
+
+	// Ровно в момент истечения запрос ещё действует
+	clock.Set(req.ExpiresAt)
+	assert.False(t, req.IsExpired())
+
+	clock.Advance(time.Nanosecond)
+	assert.True(t, req.IsExpired())
+
+	require.NoError(t, req.MarkExpired())
+	assert.Equal(t, clock.Now(), req.UpdatedAt)
+}
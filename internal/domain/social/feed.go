@@ -0,0 +1,172 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/pkg/pagination"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// ACTIVITY FEED (лента сообщества)
+// Сообщество кажется живым, когда видно, что в нём происходит. Избранные
+// доменные события (достижение, решённый запрос помощи, личный рекорд,
+// новый студент, помощник недели) проецируются в ленту потока. Имя
+// студента сохраняется снимком на момент события.
+//
+// Лента уважает приватность: событие студента, скрытого из рейтинга,
+// записывается со скрытой видимостью, а при чтении отбрасываются и записи
+// тех, кто скрылся позже. Записи хранятся FeedRetention.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// FeedRetention - сколько хранится запись ленты.
+	FeedRetention = 30 * 24 * time.Hour
+)
+
+// FeedBounds - лимиты страницы ленты.
+var FeedBounds = pagination.Bounds{DefaultLimit: 15, MaxLimit: 50}
+
+// FeedItemType - вид записи ленты.
+type FeedItemType string
+
+const (
+	// FeedItemAchievement - студент получил публичное достижение.
+	FeedItemAchievement FeedItemType = "achievement_unlocked"
+
+	// FeedItemHelpResolved - студент помог решить запрос помощи.
+	FeedItemHelpResolved FeedItemType = "help_resolved"
+
+	// FeedItemPersonalBest - студент побил личный рекорд.
+	FeedItemPersonalBest FeedItemType = "personal_best"
+
+	// FeedItemStudentJoined - новый студент в сообществе.
+	FeedItemStudentJoined FeedItemType = "student_joined"
+
+	// FeedItemHelperOfTheWeek - больше всех благодарностей за неделю.
+	FeedItemHelperOfTheWeek FeedItemType = "helper_of_the_week"
+)
+
+// FeedItemTypes - все виды записей ленты.
+func FeedItemTypes() []FeedItemType {
+	return []FeedItemType{
+		FeedItemAchievement,
+		FeedItemHelpResolved,
+		FeedItemPersonalBest,
+		FeedItemStudentJoined,
+		FeedItemHelperOfTheWeek,
+	}
+}
+
+// IsValid проверяет, что вид записи известен.
+func (t FeedItemType) IsValid() bool {
+	for _, known := range FeedItemTypes() {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// FeedVisibility - кому видна запись ленты.
+type FeedVisibility string
+
+const (
+	// FeedVisibilityPublic - запись видна потоку.
+	FeedVisibilityPublic FeedVisibility = "public"
+
+	// FeedVisibilityHidden - студент скрыт из рейтинга: запись не показывается.
+	FeedVisibilityHidden FeedVisibility = "hidden"
+)
+
+// FeedItem - запись ленты сообщества.
+type FeedItem struct {
+	// ID растёт с каждой записью: по нему листается лента.
+	ID int64
+
+	Type FeedItemType
+
+	// ActorID - студент, о котором запись.
+	ActorID StudentID
+
+	// ActorName - имя студента на момент события.
+	ActorName string
+
+	// Payload - подробности события (название достижения, задача, ...).
+	Payload map[string]string
+
+	// Cohort - поток студента.
+	Cohort string
+
+	Visibility FeedVisibility
+
+	CreatedAt time.Time
+}
+
+// NewFeedItem создаёт запись ленты.
+func NewFeedItem(itemType FeedItemType, actorID StudentID, actorName, cohort string, payload map[string]string, hidden bool, now time.Time) (*FeedItem, error) {
+	if !itemType.IsValid() {
+		return nil, fmt.Errorf("unknown feed item type %q", itemType)
+	}
+	if !actorID.IsValid() {
+		return nil, ErrInvalidStudentID
+	}
+	if payload == nil {
+		payload = map[string]string{}
+	}
+
+	visibility := FeedVisibilityPublic
+	if hidden {
+		visibility = FeedVisibilityHidden
+	}
+
+	return &FeedItem{
+		Type:       itemType,
+		ActorID:    actorID,
+		ActorName:  actorName,
+		Payload:    payload,
+		Cohort:     cohort,
+		Visibility: visibility,
+		CreatedAt:  now.UTC(),
+	}, nil
+}
+
+// IsPublic проверяет, видна ли запись потоку.
+func (i *FeedItem) IsPublic() bool {
+	return i.Visibility == FeedVisibilityPublic
+}
+
+// Summary возвращает текст записи без разметки, например
+// "🤝 Dana помогает с задачей go-reloaded". Глаголы в настоящем времени:
+// пол студента неизвестен.
+func (i *FeedItem) Summary() string {
+	p := i.Payload
+	switch i.Type {
+	case FeedItemAchievement:
+		return fmt.Sprintf("%s %s получает достижение «%s»", p["emoji"], i.ActorName, p["name"])
+	case FeedItemHelpResolved:
+		return fmt.Sprintf("🤝 %s помогает с задачей %s", i.ActorName, p["task_id"])
+	case FeedItemPersonalBest:
+		return fmt.Sprintf("📈 %s ставит личный рекорд: %s — %s", i.ActorName, p["label"], p["value"])
+	case FeedItemStudentJoined:
+		return fmt.Sprintf("👋 %s присоединяется к сообществу", i.ActorName)
+	case FeedItemHelperOfTheWeek:
+		return fmt.Sprintf("🌟 %s — помощник недели: %s благодарностей", i.ActorName, p["endorsements"])
+	default:
+		return i.ActorName
+	}
+}
+
+// FeedRepository хранит ленту сообщества.
+type FeedRepository interface {
+	// Create сохраняет запись и заполняет ID.
+	Create(ctx context.Context, item *FeedItem) error
+
+	// ListPublic возвращает публичные записи потока (все потоки, если cohort
+	// пуст) с ID меньше beforeID (без ограничения, если 0), новые первыми.
+	ListPublic(ctx context.Context, cohort string, beforeID int64, limit int) ([]*FeedItem, error)
+
+	// DeleteOlderThan удаляет записи, созданные до cutoff, и возвращает их число.
+	DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
+}
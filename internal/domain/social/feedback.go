@@ -0,0 +1,204 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HELP FEEDBACK (закрытая оценка помощи)
+// Через сутки после решения запроса студент получает личный опрос
+// «Насколько полезной была помощь?». В отличие от благодарностей оценка
+// не публичная: помощник её не видит, наружу выходят только средние
+// по потокам за неделю.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// HelpFeedbackPollDelay - через сколько после решения запроса
+	// отправляется опрос.
+	HelpFeedbackPollDelay = 24 * time.Hour
+
+	// HelpFeedbackAnswerTTL - сколько после отправки опроса принимается ответ.
+	HelpFeedbackAnswerTTL = 7 * 24 * time.Hour
+
+	// MinSatisfactionResponses - минимум ответов в потоке за неделю, чтобы
+	// показать среднее: по одному-двум ответам легко угадать, кто кому
+	// какую оценку поставил.
+	MinSatisfactionResponses = 3
+)
+
+var (
+	// ErrHelpFeedbackExists - на опрос по этому запросу уже ответили.
+	ErrHelpFeedbackExists = errors.New("help feedback already recorded")
+
+	// ErrHelpFeedbackExpired - опрос больше не принимает ответы.
+	ErrHelpFeedbackExpired = errors.New("help feedback poll expired")
+
+	// ErrHelpFeedbackInvalidScore - оценка вне диапазона 1-5.
+	ErrHelpFeedbackInvalidScore = errors.New("invalid help feedback score: must be between 1 and 5")
+)
+
+// HelpFeedbackSource - откуда взялась оценка.
+type HelpFeedbackSource string
+
+const (
+	// HelpFeedbackSourcePoll - ответ на опрос.
+	HelpFeedbackSourcePoll HelpFeedbackSource = "poll"
+
+	// HelpFeedbackSourceEndorsement - оценка из благодарности: если
+	// студент уже поставил звёзды, опрос не отправляется.
+	HelpFeedbackSourceEndorsement HelpFeedbackSource = "endorsement"
+)
+
+// HelpFeedback - закрытая оценка полезности помощи по запросу.
+type HelpFeedback struct {
+	// HelpRequestID - запрос помощи; одна оценка на запрос.
+	HelpRequestID string
+
+	// Score - оценка от 1 до 5.
+	Score int
+
+	// Source - ответ на опрос или оценка из благодарности.
+	Source HelpFeedbackSource
+
+	// CreatedAt - время ответа.
+	CreatedAt time.Time
+}
+
+// NewHelpFeedback создаёт оценку помощи.
+func NewHelpFeedback(helpRequestID string, score int, source HelpFeedbackSource, at time.Time) (*HelpFeedback, error) {
+	if helpRequestID == "" {
+		return nil, ErrHelpRequestNotFound
+	}
+	if score < 1 || score > 5 {
+		return nil, ErrHelpFeedbackInvalidScore
+	}
+
+	return &HelpFeedback{
+		HelpRequestID: helpRequestID,
+		Score:         score,
+		Source:        source,
+		CreatedAt:     at.UTC(),
+	}, nil
+}
+
+// HelpFeedbackFromEndorsement переносит звёзды благодарности в оценку.
+// Возвращает false, если благодарность без оценки.
+func HelpFeedbackFromEndorsement(e *Endorsement, at time.Time) (*HelpFeedback, bool) {
+	if e == nil || e.HelpRequestID == "" || e.Rating < 1 {
+		return nil, false
+	}
+
+	score := int(math.Round(float64(e.Rating)))
+	feedback, err := NewHelpFeedback(e.HelpRequestID, score, HelpFeedbackSourceEndorsement, at)
+	if err != nil {
+		return nil, false
+	}
+	return feedback, true
+}
+
+// NeedsFeedbackPoll проверяет, что пора отправить опрос: запрос решён
+// с помощником не меньше HelpFeedbackPollDelay назад и опрос не отправлялся.
+// Запросы, решённые раньше, чем успел бы истечь опрос, пропускаются.
+func (h *HelpRequest) NeedsFeedbackPoll(now time.Time) bool {
+	if h.Status != HelpRequestStatusResolved || h.HelperID == nil || h.ResolvedAt == nil {
+		return false
+	}
+	if h.FeedbackPollSentAt != nil {
+		return false
+	}
+	age := now.Sub(*h.ResolvedAt)
+	return age >= HelpFeedbackPollDelay && age < HelpFeedbackPollDelay+HelpFeedbackAnswerTTL
+}
+
+// MarkFeedbackPollSent помечает, что опрос отправлен.
+func (h *HelpRequest) MarkFeedbackPollSent(at time.Time) {
+	if h.FeedbackPollSentAt != nil {
+		return
+	}
+	at = at.UTC()
+	h.FeedbackPollSentAt = &at
+	h.UpdatedAt = at
+}
+
+// AcceptsFeedbackAt проверяет, что опрос отправлен и ответ ещё принимается.
+func (h *HelpRequest) AcceptsFeedbackAt(now time.Time) bool {
+	return h.FeedbackPollSentAt != nil &&
+		now.Before(h.FeedbackPollSentAt.Add(HelpFeedbackAnswerTTL))
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HELP FEEDBACK REPOSITORY
+// ══════════════════════════════════════════════════════════════════════════════
+
+// HelpFeedbackRepository хранит закрытые оценки помощи. Отдельных оценок
+// наружу не отдаёт - только агрегаты.
+type HelpFeedbackRepository interface {
+	// Create сохраняет оценку. Возвращает ErrHelpFeedbackExists, если
+	// по запросу оценка уже есть.
+	Create(ctx context.Context, feedback *HelpFeedback) error
+
+	// ExistsForHelpRequest проверяет, есть ли оценка по запросу.
+	ExistsForHelpRequest(ctx context.Context, helpRequestID string) (bool, error)
+
+	// GetSatisfactionStats возвращает оценки, полученные начиная с since:
+	// общие и по потокам просивших помощь по неделям.
+	GetSatisfactionStats(ctx context.Context, since time.Time) (*SatisfactionStats, error)
+}
+
+// SatisfactionStats - удовлетворённость помощью за период.
+type SatisfactionStats struct {
+	// Since - начало периода.
+	Since time.Time
+
+	// SatisfactionCounts - ответы за весь период.
+	SatisfactionCounts
+
+	// Weekly - по потокам и неделям, новые недели первыми.
+	Weekly []WeeklySatisfaction
+}
+
+// WeeklySatisfaction - удовлетворённость помощью в потоке за неделю.
+type WeeklySatisfaction struct {
+	// Cohort - поток студента, просившего помощь.
+	Cohort string
+
+	// WeekStart - понедельник недели (UTC).
+	WeekStart time.Time
+
+	SatisfactionCounts
+}
+
+// SatisfactionCounts - счётчики ответов.
+type SatisfactionCounts struct {
+	// Responses - количество оценок.
+	Responses int
+
+	// ScoreSum - сумма оценок.
+	ScoreSum int
+
+	// Promoters - оценки 5.
+	Promoters int
+
+	// Detractors - оценки 1-3.
+	Detractors int
+}
+
+// AverageScore возвращает среднюю оценку (0, если ответов нет).
+func (c SatisfactionCounts) AverageScore() float64 {
+	if c.Responses == 0 {
+		return 0
+	}
+	return float64(c.ScoreSum) / float64(c.Responses)
+}
+
+// NetScore возвращает индекс в духе NPS: доля пятёрок минус доля оценок
+// 1-3, от -100 до 100.
+func (c SatisfactionCounts) NetScore() int {
+	if c.Responses == 0 {
+		return 0
+	}
+	return int(math.Round(float64(c.Promoters-c.Detractors) * 100 / float64(c.Responses)))
+}
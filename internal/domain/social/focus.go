@@ -0,0 +1,254 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// FOCUS SESSIONS (совместная работа по таймеру)
+// /focus запускает 50 минут сосредоточенной работы. Можно позвать одного
+// напарника из своих контактов: он присоединяется, когда примет приглашение,
+// и сессия заканчивается у обоих одновременно. В конце каждый отмечает,
+// чем закончилась сессия. Студент может быть только в одной активной сессии.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// FocusDuration - длительность сессии.
+const FocusDuration = 50 * time.Minute
+
+// FocusInvite - статус приглашения напарника.
+type FocusInvite string
+
+const (
+	// FocusInviteNone - напарника не звали (или не позовут).
+	FocusInviteNone FocusInvite = ""
+
+	// FocusInvitePending - приглашение отправлено, ответа нет.
+	FocusInvitePending FocusInvite = "invited"
+
+	// FocusInviteAccepted - напарник присоединился.
+	FocusInviteAccepted FocusInvite = "accepted"
+
+	// FocusInviteDeclined - напарник отказался. Можно позвать другого.
+	FocusInviteDeclined FocusInvite = "declined"
+)
+
+// FocusOutcome - чем закончилась сессия для участника.
+type FocusOutcome string
+
+const (
+	// FocusOutcomeSolved - решил задачу.
+	FocusOutcomeSolved FocusOutcome = "solved"
+
+	// FocusOutcomeProgress - продвинулся.
+	FocusOutcomeProgress FocusOutcome = "progress"
+
+	// FocusOutcomeStuck - застрял (предлагаем попросить помощь).
+	FocusOutcomeStuck FocusOutcome = "stuck"
+)
+
+// IsValid проверяет корректность исхода.
+func (o FocusOutcome) IsValid() bool {
+	return o == FocusOutcomeSolved || o == FocusOutcomeProgress || o == FocusOutcomeStuck
+}
+
+// Ошибки фокус-сессий.
+var (
+	// ErrFocusNotFound - сессия не найдена.
+	ErrFocusNotFound = errors.New("focus session not found")
+
+	// ErrFocusActive - у студента уже есть активная сессия.
+	ErrFocusActive = errors.New("student already has an active focus session")
+
+	// ErrFocusEnded - сессия уже закончилась.
+	ErrFocusEnded = errors.New("focus session has ended")
+
+	// ErrFocusNotParticipant - студент не участвует в сессии.
+	ErrFocusNotParticipant = errors.New("student is not a participant of the focus session")
+
+	// ErrFocusBuddyTaken - напарник уже позван или присоединился.
+	ErrFocusBuddyTaken = errors.New("focus session already has a buddy")
+
+	// ErrFocusNotInvited - студента не приглашали в сессию.
+	ErrFocusNotInvited = errors.New("student is not invited to the focus session")
+
+	// ErrFocusSelfInvite - нельзя позвать самого себя.
+	ErrFocusSelfInvite = errors.New("cannot invite yourself")
+)
+
+// FocusSession - фокус-сессия одного студента или пары.
+type FocusSession struct {
+	ID int64
+
+	// OwnerID - кто начал сессию.
+	OwnerID StudentID
+
+	// BuddyID и BuddyStatus - приглашённый напарник (пусто, если не звали).
+	BuddyID     StudentID
+	BuddyStatus FocusInvite
+
+	// BuddyJoinedAt - когда напарник присоединился.
+	BuddyJoinedAt *time.Time
+
+	// HalfwayNudge - напомнить на середине; HalfwaySent - уже напомнили.
+	HalfwayNudge bool
+	HalfwaySent  bool
+
+	StartedAt time.Time
+	EndsAt    time.Time
+
+	// EndedAt - когда сессия закончилась (по таймеру или досрочно).
+	EndedAt *time.Time
+
+	// OwnerOutcome и BuddyOutcome - отметки участников в конце.
+	OwnerOutcome FocusOutcome
+	BuddyOutcome FocusOutcome
+}
+
+// NewFocusSession создаёт сессию на FocusDuration.
+func NewFocusSession(ownerID StudentID, halfwayNudge bool, now time.Time) *FocusSession {
+	return &FocusSession{
+		OwnerID:      ownerID,
+		HalfwayNudge: halfwayNudge,
+		StartedAt:    now,
+		EndsAt:       now.Add(FocusDuration),
+	}
+}
+
+// IsActive возвращает true, пока сессия не закончилась.
+func (s *FocusSession) IsActive() bool {
+	return s.EndedAt == nil
+}
+
+// HalfwayAt возвращает середину сессии.
+func (s *FocusSession) HalfwayAt() time.Time {
+	return s.StartedAt.Add(s.EndsAt.Sub(s.StartedAt) / 2)
+}
+
+// Participants возвращает участников: владельца и присоединившегося напарника.
+func (s *FocusSession) Participants() []StudentID {
+	if s.BuddyStatus == FocusInviteAccepted {
+		return []StudentID{s.OwnerID, s.BuddyID}
+	}
+	return []StudentID{s.OwnerID}
+}
+
+// IsParticipant проверяет, участвует ли студент в сессии.
+func (s *FocusSession) IsParticipant(id StudentID) bool {
+	return id == s.OwnerID || (id == s.BuddyID && s.BuddyStatus == FocusInviteAccepted)
+}
+
+// Invite приглашает напарника. Позвать другого можно только после отказа.
+func (s *FocusSession) Invite(buddyID StudentID) error {
+	if !s.IsActive() {
+		return ErrFocusEnded
+	}
+	if buddyID == s.OwnerID {
+		return ErrFocusSelfInvite
+	}
+	if s.BuddyStatus == FocusInvitePending || s.BuddyStatus == FocusInviteAccepted {
+		return ErrFocusBuddyTaken
+	}
+
+	s.BuddyID = buddyID
+	s.BuddyStatus = FocusInvitePending
+	s.BuddyJoinedAt = nil
+	return nil
+}
+
+// Accept принимает приглашение.
+func (s *FocusSession) Accept(buddyID StudentID, now time.Time) error {
+	if err := s.checkInvited(buddyID); err != nil {
+		return err
+	}
+	s.BuddyStatus = FocusInviteAccepted
+	s.BuddyJoinedAt = &now
+	return nil
+}
+
+// Decline отклоняет приглашение.
+func (s *FocusSession) Decline(buddyID StudentID) error {
+	if err := s.checkInvited(buddyID); err != nil {
+		return err
+	}
+	s.BuddyStatus = FocusInviteDeclined
+	return nil
+}
+
+// checkInvited проверяет, что студент приглашён и ещё не ответил.
+func (s *FocusSession) checkInvited(buddyID StudentID) error {
+	if !s.IsActive() {
+		return ErrFocusEnded
+	}
+	if buddyID != s.BuddyID || s.BuddyStatus != FocusInvitePending {
+		return ErrFocusNotInvited
+	}
+	return nil
+}
+
+// Minutes возвращает, сколько минут студент провёл в сессии. Для
+// незакончившейся сессии считается до конца по таймеру.
+func (s *FocusSession) Minutes(id StudentID) int {
+	if !s.IsParticipant(id) {
+		return 0
+	}
+
+	from := s.StartedAt
+	if id != s.OwnerID && s.BuddyJoinedAt != nil {
+		from = *s.BuddyJoinedAt
+	}
+	to := s.EndsAt
+	if s.EndedAt != nil && s.EndedAt.Before(to) {
+		to = *s.EndedAt
+	}
+	if !to.After(from) {
+		return 0
+	}
+	return int(to.Sub(from) / time.Minute)
+}
+
+// SetOutcome записывает отметку участника. Отметку можно поменять.
+func (s *FocusSession) SetOutcome(id StudentID, outcome FocusOutcome) error {
+	if !outcome.IsValid() {
+		return errors.New("invalid focus outcome")
+	}
+	switch {
+	case id == s.OwnerID:
+		s.OwnerOutcome = outcome
+	case s.IsParticipant(id):
+		s.BuddyOutcome = outcome
+	default:
+		return ErrFocusNotParticipant
+	}
+	return nil
+}
+
+// FocusRepository хранит фокус-сессии.
+type FocusRepository interface {
+	// Create сохраняет новую сессию и заполняет её ID. Если у владельца уже
+	// есть активная сессия, возвращает ErrFocusActive.
+	Create(ctx context.Context, session *FocusSession) error
+
+	// GetByID возвращает сессию или ErrFocusNotFound.
+	GetByID(ctx context.Context, id int64) (*FocusSession, error)
+
+	// GetActiveByStudent возвращает активную сессию, где студент владелец
+	// или присоединившийся напарник, или ErrFocusNotFound.
+	GetActiveByStudent(ctx context.Context, studentID StudentID) (*FocusSession, error)
+
+	// ListActive возвращает все незакончившиеся сессии (восстановление
+	// таймеров после перезапуска).
+	ListActive(ctx context.Context) ([]*FocusSession, error)
+
+	// Update сохраняет напарника и отметки участников.
+	Update(ctx context.Context, session *FocusSession) error
+
+	// MarkHalfwaySent отмечает напоминание на середине. Возвращает false,
+	// если оно уже было отправлено или сессия закончилась.
+	MarkHalfwaySent(ctx context.Context, id int64) (bool, error)
+
+	// MarkEnded завершает сессию. Возвращает false, если она уже была
+	// завершена (таймер и досрочная остановка не завершают её дважды).
+	MarkEnded(ctx context.Context, id int64, at time.Time) (bool, error)
+}
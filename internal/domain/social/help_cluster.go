@@ -0,0 +1,167 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HELP REQUEST CLUSTERS (одинаковые запросы помощи)
+// Перед дедлайном несколько студентов одной когорты просят помощь по одной
+// и той же задаче. Такие запросы объединяются в кластер, чтобы помощники
+// получали одно уведомление, а не пять, и могли взять всех сразу.
+//
+// Правила:
+//   - Новый запрос ищет открытые запросы по той же задаче от студентов своей
+//     когорты, созданные за последние HelpClusterWindow. Если такие есть, он
+//     вступает в самый старый кластер среди них; иначе открывает свой кластер
+//     (ClusterID = ID запроса). Кластеры не сливаются.
+//   - Помощник получает уведомление о кластере не больше одного раза.
+//   - Решение или отмена одного запроса закрывает только его. Остальные
+//     запросы кластера остаются открытыми.
+//   - Помощник, взявший один запрос кластера, может взять весь кластер:
+//     назначается на все открытые запросы без помощника (TakeCluster).
+//   - Решение кластера закрывает только запросы, которые ведёт этот помощник
+//     (ResolveCluster); запросы с другим помощником или без него не трогаются.
+//   - Студент по желанию вступает в группу кластера: бот показывает ему
+//     контакты остальных вступивших и сообщает им о новом участнике.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// HelpClusterWindow - за какое время ищутся одинаковые запросы.
+const HelpClusterWindow = 3 * time.Hour
+
+// Ошибки кластеров.
+var (
+	// ErrHelpRequestNotClustered - у запроса нет других запросов в кластере.
+	ErrHelpRequestNotClustered = errors.New("help request is not clustered")
+
+	// ErrHelpRequestNotHelper - студент не ведёт этот запрос.
+	ErrHelpRequestNotHelper = errors.New("student is not the helper of the help request")
+)
+
+// JoinCluster записывает запрос в кластер. Кластер запроса не меняется,
+// если он уже задан.
+func (h *HelpRequest) JoinCluster(clusterID string) {
+	if h.ClusterID != "" || clusterID == "" {
+		return
+	}
+	h.ClusterID = clusterID
+}
+
+// OptInClusterGroup отмечает согласие студента делиться контактом с группой
+// кластера. Возвращает false, если согласие уже было или запрос закрыт.
+func (h *HelpRequest) OptInClusterGroup(at time.Time) (bool, error) {
+	if h.ClusterID == "" {
+		return false, ErrHelpRequestNotClustered
+	}
+	if h.Status.IsClosed() {
+		return false, ErrHelpRequestAlreadyClosed
+	}
+	if h.GroupOptInAt != nil {
+		return false, nil
+	}
+	at = at.UTC()
+	h.GroupOptInAt = &at
+	h.UpdatedAt = at
+	return true, nil
+}
+
+// InClusterGroup проверяет, что студент открытого запроса в группе кластера.
+func (h *HelpRequest) InClusterGroup() bool {
+	return h.GroupOptInAt != nil && !h.Status.IsClosed()
+}
+
+// PickCluster выбирает кластер для нового запроса среди одинаковых:
+// кластер самого старого запроса. Запросы без кластера (созданные до
+// появления кластеров) дают кластер по своему ID. "" - одинаковых нет.
+func PickCluster(duplicates []*HelpRequest) string {
+	var oldest *HelpRequest
+	for _, d := range duplicates {
+		if d.Status.IsClosed() {
+			continue
+		}
+		if oldest == nil || d.CreatedAt.Before(oldest.CreatedAt) {
+			oldest = d
+		}
+	}
+	if oldest == nil {
+		return ""
+	}
+	if oldest.ClusterID != "" {
+		return oldest.ClusterID
+	}
+	return oldest.ID
+}
+
+// OpenClusterPeers возвращает открытые запросы кластера кроме самого req.
+func OpenClusterPeers(req *HelpRequest, members []*HelpRequest) []*HelpRequest {
+	var peers []*HelpRequest
+	for _, m := range members {
+		if m.ID == req.ID || m.Status.IsClosed() {
+			continue
+		}
+		peers = append(peers, m)
+	}
+	return peers
+}
+
+// TakeableInCluster возвращает запросы кластера, которые помощник может
+// взять вместе с уже взятым: открытые, без помощника и не его собственные.
+func TakeableInCluster(members []*HelpRequest, helperID StudentID) []*HelpRequest {
+	var takeable []*HelpRequest
+	for _, m := range members {
+		if m.Status.IsClosed() || m.HelperID != nil || m.RequesterID == helperID {
+			continue
+		}
+		takeable = append(takeable, m)
+	}
+	return takeable
+}
+
+// TakeCluster назначает помощника на все запросы кластера, которые он может
+// взять (TakeableInCluster), и возвращает их.
+func TakeCluster(members []*HelpRequest, helperID StudentID) []*HelpRequest {
+	var taken []*HelpRequest
+	for _, m := range TakeableInCluster(members, helperID) {
+		if err := m.AssignHelper(helperID); err != nil {
+			continue
+		}
+		taken = append(taken, m)
+	}
+	return taken
+}
+
+// ResolveCluster закрывает запросы кластера, которые ведёт помощник,
+// и возвращает их. Остальные запросы кластера остаются открытыми.
+func ResolveCluster(members []*HelpRequest, helperID StudentID, notes string) []*HelpRequest {
+	var resolved []*HelpRequest
+	for _, m := range members {
+		if m.Status.IsClosed() || m.HelperID == nil || *m.HelperID != helperID {
+			continue
+		}
+		id := helperID
+		if err := m.Resolve(HelpResolution{Method: HelpResolutionWithHelper, HelperID: &id, Notes: notes}); err != nil {
+			continue
+		}
+		resolved = append(resolved, m)
+	}
+	return resolved
+}
+
+// HelpClusterRepository хранит кластеры запросов помощи.
+type HelpClusterRepository interface {
+	// FindDuplicates возвращает незакрытые запросы по задаче от студентов
+	// когорты, созданные не раньше since, кроме запросов самого студента.
+	FindDuplicates(ctx context.Context, taskID TaskID, cohort string, requesterID StudentID, since time.Time) ([]*HelpRequest, error)
+
+	// SetCluster записывает кластер запросам, у которых его ещё нет.
+	SetCluster(ctx context.Context, clusterID string, requestIDs []string) error
+
+	// GetByCluster возвращает все запросы кластера, старые первыми.
+	GetByCluster(ctx context.Context, clusterID string) ([]*HelpRequest, error)
+
+	// ClaimHelperNotification отмечает, что помощник получил уведомление о
+	// кластере. Возвращает false, если уведомление уже было.
+	ClaimHelperNotification(ctx context.Context, clusterID string, helperID StudentID, at time.Time) (bool, error)
+}
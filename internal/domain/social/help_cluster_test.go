@@ -0,0 +1,100 @@
+package social
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clusteredRequest(t *testing.T, id string, requester StudentID, createdAt time.Time) *HelpRequest {
+	t.Helper()
+	req, err := NewHelpRequest(NewHelpRequestParams{
+		ID:          id,
+		RequesterID: requester,
+		TaskID:      "graph-01",
+		TaskName:    "graph-01",
+	})
+	require.NoError(t, err)
+	req.CreatedAt = createdAt
+	req.ClusterID = "cluster-1"
+	return req
+}
+
+func TestPickCluster(t *testing.T) {
+	now := time.Now()
+
+	assert.Empty(t, PickCluster(nil))
+
+	legacy := clusteredRequest(t, "legacy", "ali", now.Add(-2*time.Hour))
+	legacy.ClusterID = ""
+	newer := clusteredRequest(t, "newer", "arman", now.Add(-time.Hour))
+	assert.Equal(t, "legacy", PickCluster([]*HelpRequest{newer, legacy}), "the oldest request's ID for a request without a cluster")
+
+	older := clusteredRequest(t, "older", "dana", now.Add(-3*time.Hour))
+	older.ClusterID = "cluster-0"
+	assert.Equal(t, "cluster-0", PickCluster([]*HelpRequest{newer, legacy, older}))
+
+	require.NoError(t, older.Cancel())
+	assert.Equal(t, "legacy", PickCluster([]*HelpRequest{newer, legacy, older}), "closed requests are skipped")
+}
+
+func TestHelpRequest_JoinClusterKeepsTheFirstCluster(t *testing.T) {
+	req := clusteredRequest(t, "req-1", "dana", time.Now())
+	req.JoinCluster("cluster-2")
+	assert.Equal(t, "cluster-1", req.ClusterID)
+}
+
+func TestHelpRequest_OptInClusterGroup(t *testing.T) {
+	req := clusteredRequest(t, "req-1", "dana", time.Now())
+
+	joined, err := req.OptInClusterGroup(time.Now())
+	require.NoError(t, err)
+	assert.True(t, joined)
+	assert.True(t, req.InClusterGroup())
+
+	joined, err = req.OptInClusterGroup(time.Now())
+	require.NoError(t, err)
+	assert.False(t, joined, "opting in twice is a no-op")
+
+	require.NoError(t, req.Resolve(HelpResolution{Method: HelpResolutionSelf}))
+	assert.False(t, req.InClusterGroup(), "a closed request leaves the group")
+
+	unclustered := newTestHelpRequest(t, HelpRequestPriorityNormal)
+	_, err = unclustered.OptInClusterGroup(time.Now())
+	assert.ErrorIs(t, err, ErrHelpRequestNotClustered)
+}
+
+func TestClusterLifecycle_TakeAndResolve(t *testing.T) {
+	now := time.Now()
+	dana := clusteredRequest(t, "dana-req", "dana", now.Add(-time.Hour))
+	ali := clusteredRequest(t, "ali-req", "ali", now.Add(-30*time.Minute))
+	arman := clusteredRequest(t, "arman-req", "arman", now.Add(-20*time.Minute))
+	helperOwn := clusteredRequest(t, "aigerim-req", "aigerim", now.Add(-10*time.Minute))
+	members := []*HelpRequest{dana, ali, arman, helperOwn}
+
+	// Арман уже получил помощь от другого помощника
+	require.NoError(t, arman.AssignHelper("timur"))
+
+	// Айгерим взяла запрос Даны и берёт весь кластер
+	require.NoError(t, dana.AssignHelper("aigerim"))
+	assert.Equal(t, []*HelpRequest{ali}, TakeableInCluster(members, "aigerim"))
+	taken := TakeCluster(members, "aigerim")
+	require.Len(t, taken, 1)
+	assert.Equal(t, StudentID("aigerim"), *ali.HelperID)
+	assert.Equal(t, StudentID("timur"), *arman.HelperID, "a request with another helper is not taken")
+	assert.Nil(t, helperOwn.HelperID, "the helper's own request is not taken")
+
+	// Решение одного запроса закрывает только его
+	require.NoError(t, dana.Resolve(HelpResolution{Method: HelpResolutionWithHelper}))
+	assert.Len(t, OpenClusterPeers(dana, members), 3)
+	assert.Equal(t, HelpRequestStatusInProgress, ali.Status)
+
+	// Решение кластера закрывает только запросы этого помощника
+	resolved := ResolveCluster(members, "aigerim", "")
+	assert.Equal(t, []*HelpRequest{ali}, resolved)
+	assert.Equal(t, HelpRequestStatusResolved, ali.Status)
+	assert.Equal(t, HelpRequestStatusInProgress, arman.Status)
+	assert.Equal(t, HelpRequestStatusOpen, helperOwn.Status)
+}
@@ -0,0 +1,110 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HELP SNOOZE (напомнить помощнику позже)
+// Приглашение помочь приходит не вовремя - помощник может отложить его
+// кнопкой «⏰ Напомнить через 2 часа». В назначенное время приглашение
+// приходит снова, если запрос всё ещё ждёт помощника. Если запрос за это
+// время закрыли или его взял другой помощник, вместо приглашения приходит
+// короткое «уже решили, спасибо!». Отложить один запрос можно не больше
+// MaxHelpSnoozes раз.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// HelpSnoozeDelay - через сколько приходит отложенное приглашение.
+	HelpSnoozeDelay = 2 * time.Hour
+
+	// MaxHelpSnoozes - сколько раз помощник может отложить один запрос.
+	MaxHelpSnoozes = 2
+)
+
+var (
+	// ErrHelpSnoozeLimit - помощник уже откладывал запрос MaxHelpSnoozes раз.
+	ErrHelpSnoozeLimit = errors.New("help request snoozed too many times")
+
+	// ErrHelpSnoozeNotFound - откладывание не найдено.
+	ErrHelpSnoozeNotFound = errors.New("help snooze not found")
+)
+
+// HelpSnooze - отложенное приглашение помочь.
+type HelpSnooze struct {
+	ID int64
+
+	// HelpRequestID - запрос помощи.
+	HelpRequestID string
+
+	// HelperID - помощник, отложивший приглашение.
+	HelperID StudentID
+
+	// Attempt - номер откладывания этого запроса этим помощником (1..MaxHelpSnoozes).
+	Attempt int
+
+	// RemindAt - когда прислать приглашение снова.
+	RemindAt time.Time
+
+	// CreatedAt - когда помощник нажал кнопку.
+	CreatedAt time.Time
+
+	// SentAt - когда отправлено напоминание или «уже решили» (nil - ещё нет).
+	SentAt *time.Time
+}
+
+// NewHelpSnooze откладывает приглашение на HelpSnoozeDelay.
+func NewHelpSnooze(helpRequestID string, helperID StudentID, now time.Time) *HelpSnooze {
+	now = now.UTC()
+	return &HelpSnooze{
+		HelpRequestID: helpRequestID,
+		HelperID:      helperID,
+		RemindAt:      now.Add(HelpSnoozeDelay),
+		CreatedAt:     now,
+	}
+}
+
+// HelpSnoozeAction - что сделать, когда подошло время напоминания.
+type HelpSnoozeAction string
+
+const (
+	// HelpSnoozeRemind - запрос ждёт помощника: прислать приглашение снова.
+	HelpSnoozeRemind HelpSnoozeAction = "remind"
+
+	// HelpSnoozeAlreadyHandled - запрос решён, отменён, истёк или его взял
+	// другой помощник: поблагодарить и ничего не предлагать.
+	HelpSnoozeAlreadyHandled HelpSnoozeAction = "already_handled"
+
+	// HelpSnoozeNothing - помощник сам уже взялся за запрос.
+	HelpSnoozeNothing HelpSnoozeAction = "nothing"
+)
+
+// SnoozeAction решает, что сделать с отложенным приглашением помощника в
+// момент now.
+func (h *HelpRequest) SnoozeAction(helperID StudentID, now time.Time) HelpSnoozeAction {
+	if h.HelperID != nil && *h.HelperID == helperID {
+		return HelpSnoozeNothing
+	}
+	if !h.IsOpen() || now.After(h.ExpiresAt) || h.HelperID != nil {
+		return HelpSnoozeAlreadyHandled
+	}
+	return HelpSnoozeRemind
+}
+
+// HelpSnoozeRepository хранит отложенные приглашения.
+type HelpSnoozeRepository interface {
+	// Create сохраняет откладывание, заполняет ID и Attempt. Возвращает
+	// ErrHelpSnoozeLimit, если помощник уже откладывал запрос
+	// MaxHelpSnoozes раз.
+	Create(ctx context.Context, snooze *HelpSnooze) error
+
+	// GetDue возвращает неотправленные откладывания с RemindAt <= now,
+	// старые первыми.
+	GetDue(ctx context.Context, now time.Time, limit int) ([]*HelpSnooze, error)
+
+	// MarkSent отмечает, что напоминание отправлено. Возвращает false, если
+	// его уже отметил другой запуск.
+	MarkSent(ctx context.Context, id int64, at time.Time) (bool, error)
+}
@@ -0,0 +1,277 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// TEAM HELP REQUESTS (командные запросы помощи)
+// Проекты на Alem делают командой, и застревает команда тоже вместе. Такой
+// запрос один на всю команду: автор приглашает однокомандников, каждый
+// подтверждает участие кнопкой в приглашении.
+//
+// Правила:
+//   - Пригласить можно не больше MaxHelpTeamSize студентов; себя и уже
+//     приглашённых - нельзя. В участники попадают только принявшие
+//     приглашение (AddCoRequester).
+//   - Участник не может быть помощником своего запроса и, пока запрос
+//     открыт, не открывает второй запрос по той же задаче: у команды одна
+//     ветка приглашений помощникам, а не по ветке на каждого.
+//   - Запрос истекает, решается и отменяется целиком; неотвеченные
+//     приглашения истекают вместе с ним.
+//   - Решить запрос может любой участник. Поблагодарить помощника может
+//     каждый участник отдельно, но помощнику запрос засчитывается один раз:
+//     HelpCount растёт на первой благодарности, а в рейтинг запрос входит
+//     средним оценок всех участников (TeamHelpRating).
+//   - В подборе помощников для командного запроса выше те, кто сам решил
+//     задачу и кого стабильно хорошо благодарят (IsStrongTeamHelper).
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// MaxHelpTeamSize - сколько однокомандников можно пригласить в запрос.
+	MaxHelpTeamSize = 4
+
+	// StrongHelperRating - рейтинг «сильного» помощника для командных запросов.
+	StrongHelperRating = 4.5
+
+	// StrongHelperMinHelps - сколько благодарностей нужно, чтобы рейтинг
+	// считался историей, а не случайностью.
+	StrongHelperMinHelps = 3
+)
+
+// Ошибки командных запросов.
+var (
+	// ErrHelpRequestSelfMember - автор не может пригласить сам себя.
+	ErrHelpRequestSelfMember = errors.New("requester cannot be a co-requester")
+
+	// ErrHelpRequestAlreadyMember - студент уже приглашён или участвует.
+	ErrHelpRequestAlreadyMember = errors.New("student is already a member of the help request")
+
+	// ErrHelpRequestNotInvited - студента не приглашали в запрос.
+	ErrHelpRequestNotInvited = errors.New("student is not invited to the help request")
+
+	// ErrHelpTeamFull - в запрос приглашено MaxHelpTeamSize студентов.
+	ErrHelpTeamFull = errors.New("help request team is full")
+
+	// ErrHelpRequestNotParticipant - студент не участник запроса.
+	ErrHelpRequestNotParticipant = errors.New("student is not a participant of the help request")
+)
+
+// HelpMemberStatus - статус однокомандника в запросе.
+type HelpMemberStatus string
+
+const (
+	// HelpMemberInvited - приглашён, ещё не ответил.
+	HelpMemberInvited HelpMemberStatus = "invited"
+
+	// HelpMemberAccepted - принял приглашение, участник запроса.
+	HelpMemberAccepted HelpMemberStatus = "accepted"
+
+	// HelpMemberDeclined - отказался.
+	HelpMemberDeclined HelpMemberStatus = "declined"
+)
+
+// HelpRequestMember - однокомандник в запросе помощи.
+type HelpRequestMember struct {
+	// StudentID - приглашённый студент.
+	StudentID StudentID
+
+	// Status - ответ на приглашение.
+	Status HelpMemberStatus
+
+	// InvitedAt - когда пригласили.
+	InvitedAt time.Time
+
+	// RespondedAt - когда ответил (nil - ещё не ответил).
+	RespondedAt *time.Time
+}
+
+// InviteCoRequester приглашает однокомандника в запрос. Отказавшегося
+// можно пригласить снова.
+func (h *HelpRequest) InviteCoRequester(studentID StudentID, at time.Time) error {
+	if !studentID.IsValid() {
+		return ErrInvalidStudentID
+	}
+	if h.Status.IsClosed() {
+		return ErrHelpRequestAlreadyClosed
+	}
+	if studentID == h.RequesterID {
+		return ErrHelpRequestSelfMember
+	}
+	if h.HelperID != nil && *h.HelperID == studentID {
+		return ErrHelpRequestSelfHelp
+	}
+
+	at = at.UTC()
+	active := 0
+	for i, m := range h.Members {
+		if m.StudentID == studentID {
+			if m.Status != HelpMemberDeclined {
+				return ErrHelpRequestAlreadyMember
+			}
+			h.Members[i] = HelpRequestMember{StudentID: studentID, Status: HelpMemberInvited, InvitedAt: at}
+			h.UpdatedAt = at
+			return nil
+		}
+		if m.Status != HelpMemberDeclined {
+			active++
+		}
+	}
+	if active >= MaxHelpTeamSize {
+		return ErrHelpTeamFull
+	}
+
+	h.Members = append(h.Members, HelpRequestMember{StudentID: studentID, Status: HelpMemberInvited, InvitedAt: at})
+	h.UpdatedAt = at
+	return nil
+}
+
+// AddCoRequester делает приглашённого студента участником запроса.
+func (h *HelpRequest) AddCoRequester(studentID StudentID, at time.Time) error {
+	if studentID == h.RequesterID {
+		return ErrHelpRequestSelfMember
+	}
+	if h.Status.IsClosed() {
+		return ErrHelpRequestAlreadyClosed
+	}
+
+	member := h.member(studentID)
+	switch {
+	case member == nil || member.Status == HelpMemberDeclined:
+		return ErrHelpRequestNotInvited
+	case member.Status == HelpMemberAccepted:
+		return ErrHelpRequestAlreadyMember
+	}
+
+	at = at.UTC()
+	member.Status = HelpMemberAccepted
+	member.RespondedAt = &at
+	h.UpdatedAt = at
+	return nil
+}
+
+// DeclineInvitation отмечает отказ приглашённого студента.
+func (h *HelpRequest) DeclineInvitation(studentID StudentID, at time.Time) error {
+	member := h.member(studentID)
+	if member == nil || member.Status != HelpMemberInvited {
+		return ErrHelpRequestNotInvited
+	}
+
+	at = at.UTC()
+	member.Status = HelpMemberDeclined
+	member.RespondedAt = &at
+	h.UpdatedAt = at
+	return nil
+}
+
+// member возвращает запись однокомандника или nil.
+func (h *HelpRequest) member(studentID StudentID) *HelpRequestMember {
+	for i := range h.Members {
+		if h.Members[i].StudentID == studentID {
+			return &h.Members[i]
+		}
+	}
+	return nil
+}
+
+// CoRequesters возвращает однокомандников, принявших приглашение.
+func (h *HelpRequest) CoRequesters() []StudentID {
+	var ids []StudentID
+	for _, m := range h.Members {
+		if m.Status == HelpMemberAccepted {
+			ids = append(ids, m.StudentID)
+		}
+	}
+	return ids
+}
+
+// IsTeam проверяет, что у запроса есть однокомандники.
+func (h *HelpRequest) IsTeam() bool {
+	return len(h.CoRequesters()) > 0
+}
+
+// Participants возвращает автора и однокомандников, автора первым.
+func (h *HelpRequest) Participants() []StudentID {
+	return append([]StudentID{h.RequesterID}, h.CoRequesters()...)
+}
+
+// IsParticipant проверяет, что студент - автор или однокомандник.
+func (h *HelpRequest) IsParticipant(studentID StudentID) bool {
+	if studentID == h.RequesterID {
+		return true
+	}
+	m := h.member(studentID)
+	return m != nil && m.Status == HelpMemberAccepted
+}
+
+// IsMember проверяет, что студент - автор, участник или ждёт ответа на
+// приглашение. Такой студент не может быть помощником запроса.
+func (h *HelpRequest) IsMember(studentID StudentID) bool {
+	if studentID == h.RequesterID {
+		return true
+	}
+	m := h.member(studentID)
+	return m != nil && m.Status != HelpMemberDeclined
+}
+
+// IsStrongTeamHelper проверяет, что помощник подходит командному запросу
+// лучше остальных: сам решил задачу и его стабильно хорошо благодарят.
+func IsStrongTeamHelper(completedTask bool, rating float64, helpCount int) bool {
+	return completedTask && helpCount >= StrongHelperMinHelps && rating >= StrongHelperRating
+}
+
+// TeamHelpRating - как очередная благодарность за запрос меняет рейтинг
+// помощника. Запрос засчитывается помощнику один раз: первая благодарность
+// добавляет оценку, следующие от однокомандников заменяют вклад запроса
+// средним оценок всех поблагодаривших.
+type TeamHelpRating struct {
+	// First - первая благодарность за запрос: HelpCount растёт.
+	First bool
+
+	// Previous - вклад запроса в рейтинг до этой благодарности.
+	Previous float64
+
+	// Revised - вклад запроса с этой благодарностью.
+	Revised float64
+}
+
+// NewTeamHelpRating считает изменение рейтинга по уже данным за запрос
+// благодарностям earlier и новой оценке rating.
+func NewTeamHelpRating(earlier []*Endorsement, rating Rating) TeamHelpRating {
+	if len(earlier) == 0 {
+		return TeamHelpRating{First: true, Revised: float64(rating)}
+	}
+
+	var sum float64
+	for _, e := range earlier {
+		sum += float64(e.Rating)
+	}
+	n := float64(len(earlier))
+	return TeamHelpRating{
+		Previous: sum / n,
+		Revised:  (sum + float64(rating)) / (n + 1),
+	}
+}
+
+// HasEndorsed проверяет, благодарил ли студент за запрос.
+func HasEndorsed(endorsements []*Endorsement, giverID StudentID) bool {
+	for _, e := range endorsements {
+		if e.GiverID == giverID {
+			return true
+		}
+	}
+	return false
+}
+
+// HelpRequestMemberRepository хранит однокомандников запросов помощи.
+// GetByID возвращает запрос вместе с Members.
+type HelpRequestMemberRepository interface {
+	// SaveMembers сохраняет приглашения и ответы однокомандников запроса.
+	SaveMembers(ctx context.Context, req *HelpRequest) error
+
+	// GetOpenMemberships возвращает незакрытые запросы, в которых студент
+	// участвует как однокомандник.
+	GetOpenMemberships(ctx context.Context, studentID StudentID) ([]*HelpRequest, error)
+}
@@ -0,0 +1,47 @@
+package social
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelpRequest_TeamInvitations(t *testing.T) {
+	now := time.Now()
+	req := clusteredRequest(t, "req-1", "dana", now)
+
+	assert.ErrorIs(t, req.InviteCoRequester("dana", now), ErrHelpRequestSelfMember)
+	assert.ErrorIs(t, req.AddCoRequester("ali", now), ErrHelpRequestNotInvited)
+
+	require.NoError(t, req.InviteCoRequester("ali", now))
+	assert.ErrorIs(t, req.InviteCoRequester("ali", now), ErrHelpRequestAlreadyMember)
+	assert.False(t, req.IsParticipant("ali"), "an invitation alone doesn't make a participant")
+	assert.ErrorIs(t, req.AddMatchedHelper(MatchedHelper{StudentID: "ali"}), ErrHelpRequestSelfHelp)
+
+	require.NoError(t, req.AddCoRequester("ali", now))
+	assert.ErrorIs(t, req.AddCoRequester("ali", now), ErrHelpRequestAlreadyMember)
+	assert.True(t, req.IsTeam())
+	assert.Equal(t, []StudentID{"dana", "ali"}, req.Participants())
+
+	require.NoError(t, req.InviteCoRequester("arman", now))
+	require.NoError(t, req.DeclineInvitation("arman", now))
+	assert.ErrorIs(t, req.AddCoRequester("arman", now), ErrHelpRequestNotInvited)
+	require.NoError(t, req.InviteCoRequester("arman", now), "a declined student can be invited again")
+
+	for _, id := range []StudentID{"aigerim", "aibek"} {
+		require.NoError(t, req.InviteCoRequester(id, now))
+	}
+	assert.ErrorIs(t, req.InviteCoRequester("madina", now), ErrHelpTeamFull)
+}
+
+func TestNewTeamHelpRating(t *testing.T) {
+	first := NewTeamHelpRating(nil, 5)
+	assert.Equal(t, TeamHelpRating{First: true, Revised: 5}, first)
+
+	next := NewTeamHelpRating([]*Endorsement{{Rating: 5}, {Rating: 4}}, 3)
+	assert.False(t, next.First)
+	assert.InDelta(t, 4.5, next.Previous, 1e-9)
+	assert.InDelta(t, 4.0, next.Revised, 1e-9)
+}
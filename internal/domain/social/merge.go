@@ -0,0 +1,61 @@
+package social
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MERGE (слияние дубликатов)
+// Перенос социальных связей, когда два аккаунта одного студента
+// сливаются в один.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ConnectionMergePlan описывает, что происходит со связями поглощаемого аккаунта.
+type ConnectionMergePlan struct {
+	// Repoint - связи from, в которых from уже заменён на into.
+	// ID сохраняются, поэтому достаточно обновить участников.
+	Repoint []*Connection
+
+	// DropSelf - связи между from и into: после слияния они стали бы
+	// связью студента с самим собой.
+	DropSelf []*Connection
+
+	// DropDuplicate - связи с теми, с кем into уже связан.
+	DropDuplicate []*Connection
+}
+
+// Dropped возвращает все связи, которые нужно удалить.
+func (p ConnectionMergePlan) Dropped() []*Connection {
+	dropped := make([]*Connection, 0, len(p.DropSelf)+len(p.DropDuplicate))
+	dropped = append(dropped, p.DropSelf...)
+	return append(dropped, p.DropDuplicate...)
+}
+
+// PlanConnectionMerge строит план переноса связей from на into.
+// Связь считается дублем независимо от направления: если into уже связан
+// с X, связь from с X удаляется, а не переносится.
+func PlanConnectionMerge(intoID, fromID StudentID, intoConns, fromConns []*Connection) ConnectionMergePlan {
+	peers := make(map[StudentID]bool, len(intoConns))
+	for _, c := range intoConns {
+		peers[c.GetOtherStudent(intoID)] = true
+	}
+
+	var plan ConnectionMergePlan
+	for _, c := range fromConns {
+		other := c.GetOtherStudent(fromID)
+
+		switch {
+		case other == intoID:
+			plan.DropSelf = append(plan.DropSelf, c)
+		case peers[other]:
+			plan.DropDuplicate = append(plan.DropDuplicate, c)
+		default:
+			moved := c.Clone()
+			if moved.InitiatorID == fromID {
+				moved.InitiatorID = intoID
+			} else {
+				moved.ReceiverID = intoID
+			}
+			plan.Repoint = append(plan.Repoint, moved)
+			peers[other] = true
+		}
+	}
+
+	return plan
+}
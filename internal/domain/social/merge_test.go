@@ -0,0 +1,46 @@
+package social
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanConnectionMerge_AvoidsSelfConnections(t *testing.T) {
+	into := []*Connection{
+		{ID: "a-x", InitiatorID: "a", ReceiverID: "x"},
+	}
+	from := []*Connection{
+		{ID: "b-a", InitiatorID: "b", ReceiverID: "a"},
+		{ID: "x-b", InitiatorID: "x", ReceiverID: "b"},
+		{ID: "y-b", InitiatorID: "y", ReceiverID: "b"},
+		{ID: "b-y", InitiatorID: "b", ReceiverID: "y"},
+		{ID: "b-z", InitiatorID: "b", ReceiverID: "z"},
+	}
+
+	plan := PlanConnectionMerge("a", "b", into, from)
+
+	require.Len(t, plan.DropSelf, 1)
+	assert.Equal(t, "b-a", plan.DropSelf[0].ID)
+
+	dupIDs := make([]string, 0, len(plan.DropDuplicate))
+	for _, c := range plan.DropDuplicate {
+		dupIDs = append(dupIDs, c.ID)
+	}
+	assert.ElementsMatch(t, []string{"x-b", "b-y"}, dupIDs)
+
+	require.Len(t, plan.Repoint, 2)
+	assert.Equal(t, "y-b", plan.Repoint[0].ID)
+	assert.Equal(t, StudentID("y"), plan.Repoint[0].InitiatorID)
+	assert.Equal(t, StudentID("a"), plan.Repoint[0].ReceiverID)
+	assert.Equal(t, "b-z", plan.Repoint[1].ID)
+	assert.Equal(t, StudentID("a"), plan.Repoint[1].InitiatorID)
+	assert.Equal(t, StudentID("z"), plan.Repoint[1].ReceiverID)
+
+	for _, c := range plan.Repoint {
+		assert.NotEqual(t, c.InitiatorID, c.ReceiverID)
+	}
+	assert.Equal(t, StudentID("b"), from[2].ReceiverID, "input is not modified")
+	assert.Len(t, plan.Dropped(), 3)
+}
@@ -0,0 +1,73 @@
+package social
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// POPULAR TASKS
+// Задачи, по которым чаще всего просят помощи за период.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// MinPopularTaskRequests - минимум запросов, чтобы задача попала в популярные:
+// один запрос - это ещё не тренд.
+const MinPopularTaskRequests = 2
+
+// AggregatePopularTasks считает популярные задачи по запросам помощи,
+// созданным не раньше since: от самых запрашиваемых к менее, не больше limit.
+// Задачи с числом запросов меньше MinPopularTaskRequests не попадают.
+// Совпадает с агрегатом HelpRequestRepository.GetPopularTasks в PostgreSQL.
+func AggregatePopularTasks(requests []*HelpRequest, since time.Time, limit int) []TaskHelpStats {
+	type totals struct {
+		stats           TaskHelpStats
+		resolvedMinutes float64
+		timed           int
+	}
+	byTask := make(map[TaskID]*totals)
+
+	for _, r := range requests {
+		if r.CreatedAt.Before(since) {
+			continue
+		}
+		t, ok := byTask[r.TaskID]
+		if !ok {
+			t = &totals{stats: TaskHelpStats{TaskID: r.TaskID}}
+			byTask[r.TaskID] = t
+		}
+		if r.TaskName > t.stats.TaskName {
+			t.stats.TaskName = r.TaskName
+		}
+		t.stats.RequestCount++
+		if r.Status == HelpRequestStatusResolved {
+			t.stats.ResolvedCount++
+			if r.ResolvedAt != nil {
+				t.resolvedMinutes += r.ResolvedAt.Sub(r.CreatedAt).Minutes()
+				t.timed++
+			}
+		}
+	}
+
+	result := make([]TaskHelpStats, 0, len(byTask))
+	for _, t := range byTask {
+		if t.stats.RequestCount < MinPopularTaskRequests {
+			continue
+		}
+		if t.timed > 0 {
+			t.stats.AverageResolutionMinutes = int(math.Round(t.resolvedMinutes / float64(t.timed)))
+		}
+		result = append(result, t.stats)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].RequestCount != result[j].RequestCount {
+			return result[i].RequestCount > result[j].RequestCount
+		}
+		return result[i].TaskID < result[j].TaskID
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
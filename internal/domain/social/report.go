@@ -0,0 +1,174 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// REPORTS (жалобы на знакомства через бота)
+// Бот знакомит незнакомых студентов: помощника с тем, кто просит помощь.
+// Под каждым таким сообщением есть кнопка «Пожаловаться». Жалоба уходит
+// администраторам, а студент, на которого набралось ReportThreshold
+// подтверждённых жалоб, больше не предлагается в помощники, пока куратор
+// не вернёт его в подбор. Отклонённые жалобы не считаются.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// DefaultReportThreshold - после скольких подтверждённых жалоб студент
+	// исключается из подбора помощников.
+	DefaultReportThreshold = 3
+
+	// ReportReasonWindow - сколько после жалобы ждём текст причины
+	// следующим сообщением.
+	ReportReasonWindow = 15 * time.Minute
+
+	// MaxReportReasonLength - максимальная длина причины (в символах).
+	MaxReportReasonLength = 1000
+)
+
+// ReportContext - где произошло знакомство, на которое жалуются.
+type ReportContext string
+
+const (
+	// ReportContextConnection - контакт из кнопки «Написать».
+	ReportContextConnection ReportContext = "connection"
+
+	// ReportContextHelpRequest - уведомление помощнику о запросе помощи.
+	ReportContextHelpRequest ReportContext = "help_request"
+)
+
+// IsValid проверяет корректность контекста.
+func (c ReportContext) IsValid() bool {
+	return c == ReportContextConnection || c == ReportContextHelpRequest
+}
+
+// ReportStatus - статус жалобы.
+type ReportStatus string
+
+const (
+	// ReportPending - ждёт решения администратора.
+	ReportPending ReportStatus = "pending"
+
+	// ReportUpheld - жалоба подтверждена.
+	ReportUpheld ReportStatus = "upheld"
+
+	// ReportDismissed - жалоба отклонена и не учитывается.
+	ReportDismissed ReportStatus = "dismissed"
+)
+
+// Ошибки жалоб.
+var (
+	// ErrReportNotFound - жалоба не найдена.
+	ErrReportNotFound = errors.New("report not found")
+
+	// ErrReportResolved - по жалобе уже принято решение.
+	ErrReportResolved = errors.New("report already resolved")
+
+	// ErrReportSelf - нельзя пожаловаться на самого себя.
+	ErrReportSelf = errors.New("cannot report yourself")
+
+	// ErrReportInvalid - не хватает данных для жалобы.
+	ErrReportInvalid = errors.New("invalid report")
+)
+
+// Report - жалоба одного студента на другого.
+type Report struct {
+	ID int64
+
+	// ReporterID - кто пожаловался, ReportedID - на кого.
+	ReporterID StudentID
+	ReportedID StudentID
+
+	// Context и ContextID - знакомство, после которого пришла жалоба
+	// (ID связи или запроса помощи).
+	Context   ReportContext
+	ContextID string
+
+	// Reason - причина своими словами (необязательна).
+	Reason string
+
+	Status    ReportStatus
+	CreatedAt time.Time
+
+	// ResolvedAt и ResolvedBy (Telegram ID администратора) заполняются решением.
+	ResolvedAt *time.Time
+	ResolvedBy int64
+}
+
+// NewReport создаёт жалобу, ждущую решения.
+func NewReport(reporterID, reportedID StudentID, reportContext ReportContext, contextID string, now time.Time) (*Report, error) {
+	if reporterID == "" || reportedID == "" || !reportContext.IsValid() {
+		return nil, ErrReportInvalid
+	}
+	if reporterID == reportedID {
+		return nil, ErrReportSelf
+	}
+
+	return &Report{
+		ReporterID: reporterID,
+		ReportedID: reportedID,
+		Context:    reportContext,
+		ContextID:  contextID,
+		Status:     ReportPending,
+		CreatedAt:  now,
+	}, nil
+}
+
+// IsPending возвращает true, если решение ещё не принято.
+func (r *Report) IsPending() bool {
+	return r.Status == ReportPending
+}
+
+// NormalizeReportReason обрезает пробелы и слишком длинный текст причины.
+func NormalizeReportReason(reason string) string {
+	reason = strings.TrimSpace(reason)
+	if runes := []rune(reason); len(runes) > MaxReportReasonLength {
+		reason = string(runes[:MaxReportReasonLength])
+	}
+	return reason
+}
+
+// CountUpheld возвращает число подтверждённых жалоб. Ждущие решения и
+// отклонённые не считаются.
+func CountUpheld(reports []*Report) int {
+	count := 0
+	for _, r := range reports {
+		if r != nil && r.Status == ReportUpheld {
+			count++
+		}
+	}
+	return count
+}
+
+// ShouldDisableHelpMatching возвращает true, если подтверждённых жалоб
+// набралось threshold или больше. threshold <= 0 выключает правило.
+func ShouldDisableHelpMatching(reports []*Report, threshold int) bool {
+	return threshold > 0 && CountUpheld(reports) >= threshold
+}
+
+// ReportRepository хранит жалобы.
+type ReportRepository interface {
+	// Create сохраняет жалобу и заполняет её ID.
+	Create(ctx context.Context, report *Report) error
+
+	// GetByID возвращает жалобу или ErrReportNotFound.
+	GetByID(ctx context.Context, id int64) (*Report, error)
+
+	// AttachReason записывает причину в последнюю жалобу студента без
+	// причины, поданную не раньше since и ещё ждущую решения. Если такой
+	// нет, возвращает ErrReportNotFound.
+	AttachReason(ctx context.Context, reporterID StudentID, reason string, since time.Time) (*Report, error)
+
+	// Resolve записывает решение, если жалоба ещё ждёт его.
+	// Иначе возвращает ErrReportResolved.
+	Resolve(ctx context.Context, id int64, status ReportStatus, adminID int64, at time.Time) (*Report, error)
+
+	// ListByReported возвращает все жалобы на студента, новые первыми.
+	ListByReported(ctx context.Context, reportedID StudentID) ([]*Report, error)
+
+	// ListPending возвращает до limit жалоб, ждущих решения, старые первыми.
+	ListPending(ctx context.Context, limit int) ([]*Report, error)
+}
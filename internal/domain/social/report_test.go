@@ -0,0 +1,89 @@
+package social
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReport(t *testing.T) {
+	now := time.Date(2026, time.October, 12, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		reporter StudentID
+		reported StudentID
+		context  ReportContext
+		wantErr  error
+	}{
+		{"connection", "dana", "arman", ReportContextConnection, nil},
+		{"help request", "dana", "arman", ReportContextHelpRequest, nil},
+		{"self", "dana", "dana", ReportContextConnection, ErrReportSelf},
+		{"no reporter", "", "arman", ReportContextConnection, ErrReportInvalid},
+		{"no reported", "dana", "", ReportContextConnection, ErrReportInvalid},
+		{"unknown context", "dana", "arman", ReportContext("chat"), ErrReportInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := NewReport(tt.reporter, tt.reported, tt.context, "ctx-1", now)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, report)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.reporter, report.ReporterID)
+			assert.Equal(t, tt.reported, report.ReportedID)
+			assert.Equal(t, "ctx-1", report.ContextID)
+			assert.Equal(t, now, report.CreatedAt)
+			assert.True(t, report.IsPending())
+			assert.Empty(t, report.Reason)
+		})
+	}
+}
+
+func TestShouldDisableHelpMatching(t *testing.T) {
+	reports := func(statuses ...ReportStatus) []*Report {
+		out := make([]*Report, 0, len(statuses))
+		for _, s := range statuses {
+			out = append(out, &Report{ReporterID: "dana", ReportedID: "arman", Status: s})
+		}
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		reports   []*Report
+		threshold int
+		want      bool
+	}{
+		{"no reports", nil, 3, false},
+		{"below threshold", reports(ReportUpheld, ReportUpheld), 3, false},
+		{"at threshold", reports(ReportUpheld, ReportUpheld, ReportUpheld), 3, true},
+		{"above threshold", reports(ReportUpheld, ReportUpheld, ReportUpheld, ReportUpheld), 3, true},
+		// Отклонённые и ждущие решения не считаются
+		{"dismissed do not count", reports(ReportUpheld, ReportUpheld, ReportDismissed, ReportDismissed), 3, false},
+		{"pending do not count", reports(ReportUpheld, ReportPending, ReportPending), 2, false},
+		{"threshold one", reports(ReportUpheld), 1, true},
+		{"rule disabled", reports(ReportUpheld, ReportUpheld, ReportUpheld), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ShouldDisableHelpMatching(tt.reports, tt.threshold))
+		})
+	}
+}
+
+func TestNormalizeReportReason(t *testing.T) {
+	assert.Equal(t, "грубил в личке", NormalizeReportReason("  грубил в личке \n"))
+	assert.Empty(t, NormalizeReportReason("   "))
+
+	long := NormalizeReportReason(strings.Repeat("я", MaxReportReasonLength+10))
+	assert.Equal(t, MaxReportReasonLength, len([]rune(long)))
+}
@@ -3,6 +3,8 @@ package social
 import (
 	"context"
 	"time"
+
+	"github.com/alem-hub/alem-community-hub/pkg/pagination"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -16,6 +18,10 @@ import (
 // - CQRS-ready: методы разделены на команды (изменение) и запросы (чтение)
 // ══════════════════════════════════════════════════════════════════════════════
 
+// ListBounds - лимиты страницы социальных списков по умолчанию.
+// Общие для связей, запросов помощи, благодарностей и профилей.
+var ListBounds = pagination.Bounds{DefaultLimit: 50, MaxLimit: 200}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // CONNECTION REPOSITORY
 // Работа со связями между студентами.
@@ -111,15 +117,18 @@ type ConnectionRepository interface {
 
 	// FindStale находит связи, неактивные более указанного времени.
 	FindStale(ctx context.Context, threshold time.Duration) ([]*Connection, error)
+
+	// CreateBatch создаёт несколько связей одной транзакцией.
+	CreateBatch(ctx context.Context, conns []*Connection) error
 }
 
 // ConnectionListOptions параметры для списка связей.
 type ConnectionListOptions struct {
-	// Offset - смещение (для пагинации).
-	Offset int
+	// Page - смещение и размер страницы.
+	pagination.Page
 
-	// Limit - максимальное количество записей.
-	Limit int
+	// Bounds - лимиты страницы для конкретного вызова (нулевые = ListBounds).
+	Bounds pagination.Bounds
 
 	// IncludeEnded - включать завершённые связи.
 	IncludeEnded bool
@@ -137,8 +146,8 @@ type ConnectionListOptions struct {
 // DefaultConnectionListOptions возвращает параметры по умолчанию.
 func DefaultConnectionListOptions() ConnectionListOptions {
 	return ConnectionListOptions{
-		Offset:       0,
-		Limit:        50,
+		Page:         pagination.Page{Limit: ListBounds.DefaultLimit},
+		Bounds:       ListBounds,
 		IncludeEnded: false,
 		Types:        nil,
 		SortBy:       "created_at",
@@ -146,6 +155,14 @@ func DefaultConnectionListOptions() ConnectionListOptions {
 	}
 }
 
+// Normalized возвращает страницу, ограниченную лимитами вызова.
+func (o ConnectionListOptions) Normalized() pagination.Page {
+	if o.Bounds == (pagination.Bounds{}) {
+		return o.Page.Normalize(ListBounds)
+	}
+	return o.Page.Normalize(o.Bounds)
+}
+
 // ConnectionStatsAggregate агрегированная статистика связей.
 type ConnectionStatsAggregate struct {
 	// TotalConnections - общее количество связей.
@@ -182,6 +199,8 @@ type HelpRequestRepository interface {
 	// ─────────────────────────────────────────────────────────────────────────
 
 	// Create создаёт новый запрос помощи.
+	// Возвращает ErrHelpRequestDuplicate, если у студента уже есть открытый
+	// запрос по задаче: из одновременных запросов сохраняется только один.
 	Create(ctx context.Context, req *HelpRequest) error
 
 	// GetByID возвращает запрос по ID.
@@ -230,6 +249,25 @@ type HelpRequestRepository interface {
 	// GetUrgent возвращает срочные запросы (дедлайн скоро).
 	GetUrgent(ctx context.Context, withinHours int) ([]*HelpRequest, error)
 
+	// GetUnansweredUrgent возвращает открытые срочные запросы, созданные
+	// не позже createdBefore, на которые ещё никто не ответил и которые
+	// ещё не передавались менторам.
+	GetUnansweredUrgent(ctx context.Context, createdBefore time.Time) ([]*HelpRequest, error)
+
+	// GetAwaitingEndorsement возвращает запросы, решённые с помощником
+	// в интервале [resolvedAfter, resolvedBefore], за которые ещё нет
+	// благодарности и о которых ещё не напоминали.
+	GetAwaitingEndorsement(ctx context.Context, resolvedAfter, resolvedBefore time.Time) ([]*HelpRequest, error)
+
+	// GetAwaitingFeedbackPoll возвращает запросы, решённые с помощником
+	// в интервале [resolvedAfter, resolvedBefore], по которым ещё нет
+	// оценки и не отправлялся опрос.
+	GetAwaitingFeedbackPoll(ctx context.Context, resolvedAfter, resolvedBefore time.Time) ([]*HelpRequest, error)
+
+	// CountOpenByHelpers возвращает, сколько незакрытых запросов ведёт
+	// каждый из помощников. Помощников без запросов в результате нет.
+	CountOpenByHelpers(ctx context.Context, helperIDs []StudentID) (map[StudentID]int, error)
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// Search
 	// ─────────────────────────────────────────────────────────────────────────
@@ -260,12 +298,20 @@ type HelpRequestRepository interface {
 	// CountByTaskID возвращает количество запросов по задаче.
 	CountByTaskID(ctx context.Context, taskID TaskID) (int, error)
 
+	// CountResolved возвращает количество решённых запросов за всё время.
+	CountResolved(ctx context.Context) (int, error)
+
 	// GetHelpRequestStats возвращает статистику запросов.
 	GetHelpRequestStats(ctx context.Context, studentID StudentID) (*HelpRequestStatsAggregate, error)
 
 	// GetPopularTasks возвращает задачи с наибольшим количеством запросов.
 	GetPopularTasks(ctx context.Context, limit int, since time.Time) ([]TaskHelpStats, error)
 
+	// GetResponseTimeStats возвращает медианное время первой реакции
+	// помощника на запросы, созданные начиная с since: общее и по потокам
+	// по неделям.
+	GetResponseTimeStats(ctx context.Context, since time.Time) (*ResponseTimeStats, error)
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// Bulk Operations
 	// ─────────────────────────────────────────────────────────────────────────
@@ -273,17 +319,33 @@ type HelpRequestRepository interface {
 	// GetByIDs возвращает запросы по списку ID.
 	GetByIDs(ctx context.Context, ids []string) ([]*HelpRequest, error)
 
-	// MarkExpiredRequests помечает истёкшие запросы.
-	MarkExpiredRequests(ctx context.Context) (int, error)
+	// MarkExpiredRequests помечает истёкшими незакрытые запросы, у которых
+	// ExpiresAt раньше now. Возвращает количество помеченных запросов.
+	MarkExpiredRequests(ctx context.Context, now time.Time) (int, error)
+
+	// ClaimEndorsementReminder атомарно отмечает отправку напоминания о
+	// благодарности. Возвращает false, если напоминание уже было отмечено.
+	ClaimEndorsementReminder(ctx context.Context, id string, at time.Time) (bool, error)
+
+	// ClaimFeedbackPoll атомарно отмечает отправку опроса о полезности
+	// помощи. Возвращает false, если опрос уже был отмечен.
+	ClaimFeedbackPoll(ctx context.Context, id string, at time.Time) (bool, error)
+
+	// ─────────────────────────────────────────────────────────────────────────
+	// Clusters and teams
+	// ─────────────────────────────────────────────────────────────────────────
+
+	HelpClusterRepository
+	HelpRequestMemberRepository
 }
 
 // HelpRequestListOptions параметры для списка запросов помощи.
 type HelpRequestListOptions struct {
-	// Offset - смещение (для пагинации).
-	Offset int
+	// Page - смещение и размер страницы.
+	pagination.Page
 
-	// Limit - максимальное количество записей.
-	Limit int
+	// Bounds - лимиты страницы для конкретного вызова (нулевые = ListBounds).
+	Bounds pagination.Bounds
 
 	// IncludeClosed - включать закрытые запросы.
 	IncludeClosed bool
@@ -304,8 +366,8 @@ type HelpRequestListOptions struct {
 // DefaultHelpRequestListOptions возвращает параметры по умолчанию.
 func DefaultHelpRequestListOptions() HelpRequestListOptions {
 	return HelpRequestListOptions{
-		Offset:        0,
-		Limit:         50,
+		Page:          pagination.Page{Limit: ListBounds.DefaultLimit},
+		Bounds:        ListBounds,
 		IncludeClosed: false,
 		Statuses:      nil,
 		Priorities:    nil,
@@ -314,6 +376,14 @@ func DefaultHelpRequestListOptions() HelpRequestListOptions {
 	}
 }
 
+// Normalized возвращает страницу, ограниченную лимитами вызова.
+func (o HelpRequestListOptions) Normalized() pagination.Page {
+	if o.Bounds == (pagination.Bounds{}) {
+		return o.Page.Normalize(ListBounds)
+	}
+	return o.Page.Normalize(o.Bounds)
+}
+
 // HelpRequestSearchCriteria критерии поиска запросов.
 type HelpRequestSearchCriteria struct {
 	// TaskIDs - фильтр по задачам.
@@ -374,6 +444,39 @@ type HelpRequestStatsAggregate struct {
 	TopTasks []TaskID
 }
 
+// ResponseTimeStats - время первой реакции на запросы помощи.
+type ResponseTimeStats struct {
+	// Since - начало периода.
+	Since time.Time
+
+	// MedianFirstResponse - медиана времени до первой реакции (0, если ответов не было).
+	MedianFirstResponse time.Duration
+
+	// Answered - запросы, на которые кто-то отреагировал.
+	Answered int
+
+	// Unanswered - запросы без реакции.
+	Unanswered int
+
+	// Weekly - медианы по потокам и неделям, новые недели первыми.
+	Weekly []WeeklyResponseTime
+}
+
+// WeeklyResponseTime - медиана времени первой реакции в потоке за неделю.
+type WeeklyResponseTime struct {
+	// Cohort - поток студента, просившего помощь.
+	Cohort string
+
+	// WeekStart - понедельник недели (UTC).
+	WeekStart time.Time
+
+	// MedianFirstResponse - медиана времени до первой реакции.
+	MedianFirstResponse time.Duration
+
+	// Answered - запросы с реакцией за неделю.
+	Answered int
+}
+
 // TaskHelpStats статистика помощи по задаче.
 type TaskHelpStats struct {
 	// TaskID - ID задачи.
@@ -428,6 +531,10 @@ type EndorsementRepository interface {
 	// GetByHelpRequestID возвращает благодарность за запрос помощи.
 	GetByHelpRequestID(ctx context.Context, helpRequestID string) (*Endorsement, error)
 
+	// ListByHelpRequestID возвращает все благодарности за запрос помощи
+	// (у командного запроса их может быть несколько), старые первыми.
+	ListByHelpRequestID(ctx context.Context, helpRequestID string) ([]*Endorsement, error)
+
 	// GetByTaskID возвращает благодарности по задаче.
 	GetByTaskID(ctx context.Context, taskID TaskID, opts EndorsementListOptions) ([]*Endorsement, error)
 
@@ -450,6 +557,10 @@ type EndorsementRepository interface {
 	// ExistsForHelpRequest проверяет, есть ли благодарность за запрос.
 	ExistsForHelpRequest(ctx context.Context, helpRequestID string) (bool, error)
 
+	// ExistsFromGiverSince проверяет, благодарил ли giverID студента
+	// receiverID начиная с since.
+	ExistsFromGiverSince(ctx context.Context, giverID, receiverID StudentID, since time.Time) (bool, error)
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// Aggregations
 	// ─────────────────────────────────────────────────────────────────────────
@@ -475,15 +586,18 @@ type EndorsementRepository interface {
 
 	// GetByIDs возвращает благодарности по списку ID.
 	GetByIDs(ctx context.Context, ids []string) ([]*Endorsement, error)
+
+	// CreateBatch создаёт несколько благодарностей одной транзакцией.
+	CreateBatch(ctx context.Context, endorsements []*Endorsement) error
 }
 
 // EndorsementListOptions параметры для списка благодарностей.
 type EndorsementListOptions struct {
-	// Offset - смещение (для пагинации).
-	Offset int
+	// Page - смещение и размер страницы.
+	pagination.Page
 
-	// Limit - максимальное количество записей.
-	Limit int
+	// Bounds - лимиты страницы для конкретного вызова (нулевые = ListBounds).
+	Bounds pagination.Bounds
 
 	// Types - фильтр по типам (пустой = все типы).
 	Types []EndorsementType
@@ -504,8 +618,8 @@ type EndorsementListOptions struct {
 // DefaultEndorsementListOptions возвращает параметры по умолчанию.
 func DefaultEndorsementListOptions() EndorsementListOptions {
 	return EndorsementListOptions{
-		Offset:     0,
-		Limit:      50,
+		Page:       pagination.Page{Limit: ListBounds.DefaultLimit},
+		Bounds:     ListBounds,
 		Types:      nil,
 		MinRating:  0,
 		PublicOnly: false,
@@ -514,6 +628,14 @@ func DefaultEndorsementListOptions() EndorsementListOptions {
 	}
 }
 
+// Normalized возвращает страницу, ограниченную лимитами вызова.
+func (o EndorsementListOptions) Normalized() pagination.Page {
+	if o.Bounds == (pagination.Bounds{}) {
+		return o.Page.Normalize(ListBounds)
+	}
+	return o.Page.Normalize(o.Bounds)
+}
+
 // EndorsementStatsAggregate агрегированная статистика благодарностей.
 type EndorsementStatsAggregate struct {
 	// TotalReceived - всего получено.
@@ -674,11 +796,11 @@ type SocialProfileRepository interface {
 
 // SocialProfileListOptions параметры для списка профилей.
 type SocialProfileListOptions struct {
-	// Offset - смещение.
-	Offset int
+	// Page - смещение и размер страницы.
+	pagination.Page
 
-	// Limit - максимальное количество.
-	Limit int
+	// Bounds - лимиты страницы для конкретного вызова (нулевые = ListBounds).
+	Bounds pagination.Bounds
 
 	// MinRating - минимальный рейтинг.
 	MinRating Rating
@@ -696,8 +818,8 @@ type SocialProfileListOptions struct {
 // DefaultSocialProfileListOptions возвращает параметры по умолчанию.
 func DefaultSocialProfileListOptions() SocialProfileListOptions {
 	return SocialProfileListOptions{
-		Offset:       0,
-		Limit:        50,
+		Page:         pagination.Page{Limit: ListBounds.DefaultLimit},
+		Bounds:       ListBounds,
 		MinRating:    0,
 		MinHelpCount: 0,
 		SortBy:       "average_rating",
@@ -705,6 +827,14 @@ func DefaultSocialProfileListOptions() SocialProfileListOptions {
 	}
 }
 
+// Normalized возвращает страницу, ограниченную лимитами вызова.
+func (o SocialProfileListOptions) Normalized() pagination.Page {
+	if o.Bounds == (pagination.Bounds{}) {
+		return o.Page.Normalize(ListBounds)
+	}
+	return o.Page.Normalize(o.Bounds)
+}
+
 // CommunityStats глобальная статистика сообщества.
 type CommunityStats struct {
 	// TotalConnections - всего связей.
@@ -764,6 +894,30 @@ type Repository interface {
 	SocialProfiles() SocialProfileRepository
 }
 
+// ══════════════════════════════════════════════════════════════════════════════
+// MERGE REPOSITORY
+// Операции слияния дубликатов. Используется только внутри транзакции.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// MergeRepository переносит социальные данные одного аккаунта на другой.
+type MergeRepository interface {
+	// GetConnections возвращает все связи студента в любом направлении.
+	GetConnections(ctx context.Context, studentID StudentID) ([]*Connection, error)
+
+	// ApplyConnectionPlan применяет план переноса связей.
+	ApplyConnectionPlan(ctx context.Context, plan ConnectionMergePlan) error
+
+	// MoveHelpRequests переносит запросы помощи (как автора и как помощника).
+	// Запросы, где into помогал сам себе, остаются без помощника.
+	// Возвращает число перенесённых запросов.
+	MoveHelpRequests(ctx context.Context, fromID, intoID StudentID) (int64, error)
+
+	// MoveEndorsements переносит благодарности и пересчитывает рейтинг into.
+	// Благодарности между from и into удаляются.
+	// Возвращает число перенесённых и удалённых.
+	MoveEndorsements(ctx context.Context, fromID, intoID StudentID) (moved, dropped int64, err error)
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // UNIT OF WORK
 // Для транзакционных операций.
@@ -0,0 +1,135 @@
+package student
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// ACCESS TOKEN (персональный токен API)
+// Студент может выгрузить свою статистику в личные дашборды (Notion,
+// Obsidian): /token create выдаёт токен только для чтения своих данных
+// (GET /api/v1/me/...). Токен показывается один раз, хранится только его
+// хеш. Активных токенов не больше MaxActiveAccessTokens, живут они
+// AccessTokenTTL.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// AccessTokenTTL - срок жизни токена.
+	AccessTokenTTL = 180 * 24 * time.Hour
+
+	// MaxActiveAccessTokens - сколько активных токенов может быть у студента.
+	MaxActiveAccessTokens = 2
+
+	// AccessTokenPrefix - начало каждого токена: по нему токен отличается
+	// от API-ключей и легко находится сканерами секретов.
+	AccessTokenPrefix = "ach_"
+
+	// accessTokenBytes - случайная часть токена.
+	accessTokenBytes = 32
+)
+
+// AccessTokenScope - что разрешено токену.
+type AccessTokenScope string
+
+// AccessTokenScopeSelfRead - чтение своих данных.
+const AccessTokenScopeSelfRead AccessTokenScope = "self:read"
+
+var (
+	// ErrAccessTokenLimit - у студента уже MaxActiveAccessTokens токенов.
+	ErrAccessTokenLimit = errors.New("access token limit reached")
+
+	// ErrAccessTokenNotFound - токен не найден, отозван или истёк.
+	ErrAccessTokenNotFound = errors.New("access token not found")
+)
+
+// AccessToken - персональный токен API студента.
+type AccessToken struct {
+	ID        string
+	StudentID string
+
+	// Hash - SHA-256 токена; сам токен не хранится.
+	Hash string
+
+	// Hint - последние символы токена, чтобы студент узнал его в списке.
+	Hint string
+
+	Scope      AccessTokenScope
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// NewAccessToken создаёт токен и возвращает его вместе с секретом, который
+// нужно показать студенту: больше его узнать нельзя. ID заполняет репозиторий.
+func NewAccessToken(studentID string, now time.Time) (*AccessToken, string, error) {
+	if studentID == "" {
+		return nil, "", ErrStudentNotFound
+	}
+
+	raw := make([]byte, accessTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", fmt.Errorf("generate access token: %w", err)
+	}
+	secret := AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	now = now.UTC()
+	return &AccessToken{
+		StudentID: studentID,
+		Hash:      HashAccessToken(secret),
+		Hint:      secret[len(secret)-4:],
+		Scope:     AccessTokenScopeSelfRead,
+		CreatedAt: now,
+		ExpiresAt: now.Add(AccessTokenTTL),
+	}, secret, nil
+}
+
+// HashAccessToken возвращает хеш токена, по которому он ищется.
+func HashAccessToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// LooksLikeAccessToken проверяет, похожа ли строка на токен студента.
+func LooksLikeAccessToken(s string) bool {
+	return strings.HasPrefix(s, AccessTokenPrefix)
+}
+
+// IsActive проверяет, что токен не отозван и не истёк.
+func (t *AccessToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// Masked возвращает токен для списка, например "ach_…x7Qa".
+func (t *AccessToken) Masked() string {
+	return AccessTokenPrefix + "…" + t.Hint
+}
+
+// AccessTokenRepository хранит токены.
+type AccessTokenRepository interface {
+	// Create сохраняет токен и заполняет ID. ErrAccessTokenLimit - у студента уже
+	// MaxActiveAccessTokens активных токенов.
+	Create(ctx context.Context, token *AccessToken) error
+
+	// ListActive возвращает активные токены студента, новые первыми.
+	ListActive(ctx context.Context, studentID string, now time.Time) ([]*AccessToken, error)
+
+	// GetByHash возвращает токен по хешу, в том числе отозванный или
+	// истёкший. ErrAccessTokenNotFound - такого нет.
+	GetByHash(ctx context.Context, hash string) (*AccessToken, error)
+
+	// Revoke отзывает активный токен студента.
+	// ErrAccessTokenNotFound - у студента нет такого активного токена.
+	Revoke(ctx context.Context, studentID, tokenID string, at time.Time) error
+
+	// TouchLastUsed отмечает использование токена.
+	TouchLastUsed(ctx context.Context, tokenID string, at time.Time) error
+}
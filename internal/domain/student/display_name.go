@@ -0,0 +1,28 @@
+package student
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxDisplayNameLength - максимальная длина отображаемого имени в байтах.
+const MaxDisplayNameLength = 100
+
+// TelegramDisplayName строит отображаемое имя из профиля Telegram: имя и
+// фамилия, а без них - username. Пустая строка, если в профиле ничего нет.
+// Слишком длинные имена обрезаются по границе символа до MaxDisplayNameLength.
+func TelegramDisplayName(firstName, lastName, username string) string {
+	name := strings.TrimSpace(strings.TrimSpace(firstName) + " " + strings.TrimSpace(lastName))
+	if strings.TrimSpace(firstName) == "" {
+		name = strings.TrimSpace(username)
+	}
+
+	if len(name) <= MaxDisplayNameLength {
+		return name
+	}
+	cut := MaxDisplayNameLength
+	for cut > 0 && !utf8.RuneStart(name[cut]) {
+		cut--
+	}
+	return strings.TrimSpace(name[:cut])
+}
@@ -0,0 +1,41 @@
+package student
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelegramDisplayName(t *testing.T) {
+	assert.Equal(t, "Дана Сейт", TelegramDisplayName("Дана", "Сейт", "dana"))
+	assert.Equal(t, "Дана", TelegramDisplayName(" Дана ", "", "dana"))
+	assert.Equal(t, "dana", TelegramDisplayName("", "Сейт", "dana"))
+	assert.Empty(t, TelegramDisplayName("", "", ""))
+
+	// 64 кириллических символа имени и фамилии - больше 100 байт.
+	long := TelegramDisplayName(strings.Repeat("Д", 64), strings.Repeat("С", 64), "")
+	assert.LessOrEqual(t, len(long), MaxDisplayNameLength)
+	assert.True(t, utf8.ValidString(long))
+}
+
+func TestStudent_Rename(t *testing.T) {
+	s := &Student{DisplayName: "Dana"}
+
+	changed, err := s.Rename("Dana")
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.True(t, s.UpdatedAt.IsZero())
+
+	changed, err = s.Rename(" Дана Сейт ")
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "Дана Сейт", s.DisplayName)
+	assert.False(t, s.UpdatedAt.IsZero())
+
+	_, err = s.Rename("  ")
+	assert.ErrorIs(t, err, ErrInvalidDisplayName)
+	assert.Equal(t, "Дана Сейт", s.DisplayName)
+}
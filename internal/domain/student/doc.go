@@ -86,7 +86,7 @@
 //
 //	// Проверка достижений
 //	checker := NewAchievementChecker()
-//	newAchievements := checker.CheckNewAchievements(student, streak, rank, existing)
+//	newAchievements := checker.CheckNewAchievements(student, streak, rank, cohortRank, existing)
 //	for _, achievement := range newAchievements {
 //	    event := NewAchievementUnlockedEvent(student, achievement)
 //	    eventBus.Publish(event)
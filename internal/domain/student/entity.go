@@ -7,20 +7,14 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
 // VALUE OBJECTS
 // ══════════════════════════════════════════════════════════════════════════════
 
-// TelegramID представляет уникальный идентификатор пользователя Telegram.
-type TelegramID int64
-
-// IsValid проверяет, что TelegramID положительный.
-func (t TelegramID) IsValid() bool {
-	return t > 0
-}
-
 // XP представляет очки опыта студента.
 type XP int
 
@@ -51,6 +45,14 @@ func CalculateLevel(xp XP) Level {
 	return Level(xp / 1000)
 }
 
+// MinXP возвращает XP, с которого начинается уровень.
+func (l Level) MinXP() XP {
+	if l <= 0 {
+		return 0
+	}
+	return XP(l) * 1000
+}
+
 // Cohort представляет поток студентов (например, "2024-spring").
 type Cohort string
 
@@ -137,6 +139,10 @@ type Student struct {
 	// Email - email address of the student.
 	Email string
 
+	// AlemLogin - логин на платформе Alem, главный ключ при синхронизации.
+	// Пустой у старых записей, пока синхронизация его не заполнит.
+	AlemLogin string
+
 	// PasswordHash - hashed password.
 	PasswordHash string
 
@@ -173,6 +179,15 @@ type Student struct {
 	// HelpCount - количество оказанных помощей.
 	HelpCount int
 
+	// MutedUntil - до какого момента заглушены все несрочные уведомления
+	// (nil - не заглушены). Истёкшая заглушка хранится, пока о её окончании
+	// не сообщат (см. WithMuteEndedNotice).
+	MutedUntil *time.Time
+
+	// HelpMatchingDisabled - студент исключён из подбора помощников после
+	// подтверждённых жалоб, пока куратор не вернёт его (social.Report).
+	HelpMatchingDisabled bool
+
 	// CreatedAt - время создания записи.
 	CreatedAt time.Time
 
@@ -194,13 +209,46 @@ type NotificationPreferences struct {
 	// InactivityReminders - напоминать о неактивности.
 	InactivityReminders bool
 
+	// BuddyOnline - сообщать, когда напарник или ментор появился онлайн.
+	BuddyOnline bool
+
+	// StuckHelpOffers - предлагать помощь, если прогресс застопорился.
+	StuckHelpOffers bool
+
 	// QuietHoursStart - начало тихого времени (часы, 0-23).
 	QuietHoursStart int
 
 	// QuietHoursEnd - конец тихого времени (часы, 0-23).
 	QuietHoursEnd int
+
+	// DigestHour - личный час ежедневной сводки (0-23); nil - час потока.
+	DigestHour *int
+
+	// CategoryMutes - до какого момента заглушены отдельные категории
+	// уведомлений (/mute rank).
+	CategoryMutes map[MuteCategory]time.Time
+
+	// HideFromLeaderboard - не показывать в публичном рейтинге (/privacy).
+	HideFromLeaderboard bool
+
+	// HideFromHelperSearch - не предлагать другим как помощника.
+	HideFromHelperSearch bool
+
+	// HideOnlineStatus - не показывать другим, что студент онлайн.
+	HideOnlineStatus bool
+
+	// QuickActions - показывать в личном чате клавиатуру быстрых действий.
+	QuickActions bool
+
+	// Timezone - часовой пояс студента (имя IANA, /timezone). По нему
+	// считаются тихие часы, час сводки, границы дней серии и дневного
+	// прогресса; общие для рейтинга границы остаются по Алматы.
+	Timezone string
 }
 
+// DefaultTimezone - часовой пояс по умолчанию.
+const DefaultTimezone = "Asia/Almaty"
+
 // DefaultNotificationPreferences возвращает настройки по умолчанию.
 func DefaultNotificationPreferences() NotificationPreferences {
 	return NotificationPreferences{
@@ -208,14 +256,41 @@ func DefaultNotificationPreferences() NotificationPreferences {
 		DailyDigest:         true,
 		HelpRequests:        true,
 		InactivityReminders: true,
+		BuddyOnline:         true,
+		StuckHelpOffers:     true,
+		QuickActions:        true,
 		QuietHoursStart:     23, // 23:00 - 08:00 тихие часы
 		QuietHoursEnd:       8,
+		Timezone:            DefaultTimezone,
+	}
+}
+
+// Location возвращает часовой пояс студента; пустой или неизвестный -
+// Алматы.
+func (p NotificationPreferences) Location() *time.Location {
+	if p.Timezone != "" {
+		if loc, err := timeutil.LoadLocation(p.Timezone); err == nil {
+			return loc
+		}
+	}
+	return timeutil.AlmatyTZ
+}
+
+// ValidateTimezone проверяет, что name - известная зона IANA.
+func ValidateTimezone(name string) error {
+	if name == "" || name == "Local" {
+		return ErrInvalidTimezone
 	}
+	if _, err := timeutil.LoadLocation(name); err != nil {
+		return ErrInvalidTimezone
+	}
+	return nil
 }
 
-// IsQuietHour проверяет, попадает ли указанное время в тихие часы.
+// IsQuietHour проверяет, попадает ли указанное время в тихие часы
+// часового пояса студента.
 func (p NotificationPreferences) IsQuietHour(t time.Time) bool {
-	hour := t.Hour()
+	hour := t.In(p.Location()).Hour()
 	if p.QuietHoursStart < p.QuietHoursEnd {
 		// Простой случай: например, 1:00 - 6:00
 		return hour >= p.QuietHoursStart && hour < p.QuietHoursEnd
@@ -230,7 +305,7 @@ func (p NotificationPreferences) IsQuietHour(t time.Time) bool {
 
 var (
 	// ErrInvalidTelegramID - невалидный Telegram ID.
-	ErrInvalidTelegramID = errors.New("invalid telegram id: must be positive")
+	ErrInvalidTelegramID = errors.New("invalid telegram id")
 
 	// ErrInvalidEmail - invalid email.
 	ErrInvalidEmail = errors.New("invalid email")
@@ -253,6 +328,9 @@ var (
 	// ErrInvalidHelpRating - невалидный рейтинг помощника.
 	ErrInvalidHelpRating = errors.New("invalid help rating: must be between 0.0 and 5.0")
 
+	// ErrInvalidTimezone - неизвестный часовой пояс.
+	ErrInvalidTimezone = errors.New("invalid timezone: must be an IANA time zone name")
+
 	// ErrStudentNotFound - студент не найден.
 	ErrStudentNotFound = errors.New("student not found")
 
@@ -297,7 +375,7 @@ func NewStudent(params NewStudentParams) (*Student, error) {
 	}
 
 	displayName := strings.TrimSpace(params.DisplayName)
-	if len(displayName) == 0 || len(displayName) > 100 {
+	if len(displayName) == 0 || len(displayName) > MaxDisplayNameLength {
 		return nil, ErrInvalidDisplayName
 	}
 
@@ -379,6 +457,20 @@ func (s *Student) MarkOffline() {
 	s.UpdatedAt = time.Now().UTC()
 }
 
+// ApplyOnlineStatus применяет онлайн-статус из внешнего источника (синхронизация).
+// Возвращает true только при переходе оффлайн → онлайн: повторное
+// подтверждение онлайна (heartbeat) переходом не считается.
+func (s *Student) ApplyOnlineStatus(isOnline bool) (cameOnline bool) {
+	wasAvailable := s.OnlineState.IsAvailable()
+	if !isOnline {
+		s.MarkOffline()
+		return false
+	}
+
+	s.MarkOnline()
+	return !wasAvailable
+}
+
 // UpdateOnlineState обновляет состояние на основе времени последней активности.
 func (s *Student) UpdateOnlineState() {
 	elapsed := time.Since(s.LastSeenAt)
@@ -455,6 +547,20 @@ func (s *Student) UpdatePreferences(prefs NotificationPreferences) {
 	s.UpdatedAt = time.Now().UTC()
 }
 
+// Rename меняет отображаемое имя. Возвращает false, если имя не изменилось.
+func (s *Student) Rename(displayName string) (bool, error) {
+	displayName = strings.TrimSpace(displayName)
+	if len(displayName) == 0 || len(displayName) > MaxDisplayNameLength {
+		return false, ErrInvalidDisplayName
+	}
+	if displayName == s.DisplayName {
+		return false, nil
+	}
+	s.DisplayName = displayName
+	s.UpdatedAt = time.Now().UTC()
+	return true, nil
+}
+
 // AddHelpRating добавляет оценку за помощь и пересчитывает средний рейтинг.
 func (s *Student) AddHelpRating(rating float64) error {
 	if rating < 0.0 || rating > 5.0 {
@@ -470,6 +576,24 @@ func (s *Student) AddHelpRating(rating float64) error {
 	return nil
 }
 
+// ReviseHelpRating заменяет одну из учтённых оценок previous на revised, не
+// меняя HelpCount. Так командный запрос засчитывается помощнику один раз,
+// а в рейтинг входит средней оценкой всех участников.
+func (s *Student) ReviseHelpRating(previous, revised float64) error {
+	if previous < 0.0 || previous > 5.0 || revised < 0.0 || revised > 5.0 {
+		return ErrInvalidHelpRating
+	}
+	if s.HelpCount == 0 {
+		return s.AddHelpRating(revised)
+	}
+
+	totalRating := s.HelpRating*float64(s.HelpCount) - previous + revised
+	s.HelpRating = min(max(totalRating/float64(s.HelpCount), 0), 5)
+	s.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
 // CanHelp проверяет, может ли студент помогать другим.
 func (s *Student) CanHelp() bool {
 	return s.Status.IsEnrolled() && s.Preferences.HelpRequests
@@ -486,9 +610,21 @@ func (s *Student) CanReceiveNotification(notificationType string, at time.Time)
 		return false
 	}
 
+	return s.AcceptsNotification(notificationType, at)
+}
+
+// AcceptsNotification проверяет заглушки (/mute) и настройки студента для
+// типа уведомления. Статус и тихие часы не учитываются: их проверяет
+// CanReceiveNotification, а отложенная доставка переносит отправку на конец
+// тихих часов.
+func (s *Student) AcceptsNotification(notificationType string, at time.Time) bool {
+	if s.IsMuted(MuteCategoryFor(notificationType), at) {
+		return false
+	}
+
 	// Проверяем настройки для конкретного типа уведомлений
 	switch notificationType {
-	case "rank_change":
+	case "rank_up", "rank_down", "entered_top", "left_top":
 		return s.Preferences.RankChanges
 	case "daily_digest":
 		return s.Preferences.DailyDigest
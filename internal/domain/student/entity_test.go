@@ -0,0 +1,51 @@
+package student
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStudent_ApplyOnlineStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		before         OnlineState
+		isOnline       bool
+		want           OnlineState
+		wantCameOnline bool
+	}{
+		{"offline to online", OnlineStateOffline, true, OnlineStateOnline, true},
+		{"never seen to online", "", true, OnlineStateOnline, true},
+		{"online heartbeat", OnlineStateOnline, true, OnlineStateOnline, false},
+		{"away back to online", OnlineStateAway, true, OnlineStateOnline, false},
+		{"online to offline", OnlineStateOnline, false, OnlineStateOffline, false},
+		{"offline stays offline", OnlineStateOffline, false, OnlineStateOffline, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Student{OnlineState: tt.before, Status: StatusActive}
+
+			assert.Equal(t, tt.wantCameOnline, s.ApplyOnlineStatus(tt.isOnline))
+			assert.Equal(t, tt.want, s.OnlineState)
+		})
+	}
+}
+
+func TestNotificationPreferences_QuietHoursInStudentTimezone(t *testing.T) {
+	prefs := DefaultNotificationPreferences() // 23:00 - 08:00
+
+	// 19:30 UTC: в Алматы 00:30 - тихо, в Берлине 21:30 - нет
+	at := time.Date(2026, time.October, 17, 19, 30, 0, 0, time.UTC)
+	assert.True(t, prefs.IsQuietHour(at))
+
+	prefs.Timezone = "Europe/Berlin"
+	assert.False(t, prefs.IsQuietHour(at))
+	assert.True(t, prefs.IsQuietHour(at.Add(2*time.Hour)))
+
+	// Пустой пояс - Алматы
+	assert.Equal(t, "Asia/Almaty", NotificationPreferences{}.Location().String())
+	assert.ErrorIs(t, ValidateTimezone("Local"), ErrInvalidTimezone)
+	assert.NoError(t, ValidateTimezone("America/New_York"))
+}
@@ -0,0 +1,242 @@
+package student
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// WEEKLY GOALS (Личные цели на неделю)
+// Помимо общего рейтинга студент может поставить себе цель по XP на текущую
+// ISO-неделю ("500 XP за неделю"). Недели считаются по UTC, как и дневной
+// прогресс, из которого берётся XP недели.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// MinWeeklyGoalXP - минимальная цель на неделю.
+	MinWeeklyGoalXP XP = 50
+
+	// MaxWeeklyGoalXP - максимальная цель на неделю.
+	MaxWeeklyGoalXP XP = 5000
+
+	// GoalGetterWeeks - сколько недель подряд нужно достигать цели
+	// для достижения "Goal Getter".
+	GoalGetterWeeks = 4
+)
+
+// Ошибки недельных целей.
+var (
+	// ErrGoalNotFound - цели на неделю нет.
+	ErrGoalNotFound = errors.New("weekly goal not found")
+
+	// ErrGoalStudentRequired - цель без студента.
+	ErrGoalStudentRequired = errors.New("weekly goal requires student id")
+
+	// ErrGoalOutOfRange - цель вне допустимых границ.
+	ErrGoalOutOfRange = fmt.Errorf("weekly goal must be between %d and %d XP", MinWeeklyGoalXP, MaxWeeklyGoalXP)
+
+	// ErrGoalAlreadyReached - цель этой недели уже достигнута, менять её нельзя.
+	ErrGoalAlreadyReached = errors.New("weekly goal already reached")
+
+	// ErrGoalTargetAlreadyEarned - новая цель не выше XP, уже набранного
+	// за неделю: такая цель засчиталась бы сразу.
+	ErrGoalTargetAlreadyEarned = errors.New("weekly goal must be above the xp already earned this week")
+)
+
+// ISOWeek - ISO-неделя в формате "2006-W01".
+type ISOWeek string
+
+// ISOWeekOf возвращает ISO-неделю момента t (по UTC).
+func ISOWeekOf(t time.Time) ISOWeek {
+	year, week := t.UTC().ISOWeek()
+	return ISOWeek(fmt.Sprintf("%04d-W%02d", year, week))
+}
+
+// Start возвращает понедельник 00:00 UTC недели.
+func (w ISOWeek) Start() (time.Time, error) {
+	var year, week int
+	if _, err := fmt.Sscanf(string(w), "%04d-W%02d", &year, &week); err != nil || week < 1 || week > 53 {
+		return time.Time{}, fmt.Errorf("invalid ISO week %q", w)
+	}
+
+	// 4 января всегда попадает в первую ISO-неделю года
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	return jan4.AddDate(0, 0, -offset+7*(week-1)), nil
+}
+
+// Previous возвращает предыдущую неделю.
+func (w ISOWeek) Previous() ISOWeek {
+	start, err := w.Start()
+	if err != nil {
+		return ""
+	}
+	return ISOWeekOf(start.AddDate(0, 0, -7))
+}
+
+// WeeklyGoal - цель студента по XP на одну неделю.
+type WeeklyGoal struct {
+	// StudentID - ID студента.
+	StudentID string
+
+	// Week - неделя цели.
+	Week ISOWeek
+
+	// TargetXP - сколько XP студент хочет набрать за неделю.
+	TargetXP XP
+
+	// ReachedAt - когда цель достигнута (nil - ещё нет).
+	// Выставляется один раз, вместе с поздравлением.
+	ReachedAt *time.Time
+
+	// CreatedAt - когда цель поставлена.
+	CreatedAt time.Time
+
+	// UpdatedAt - когда цель последний раз менялась.
+	UpdatedAt time.Time
+}
+
+// ValidateGoalTarget проверяет, что цель в допустимых границах.
+func ValidateGoalTarget(target XP) error {
+	if target < MinWeeklyGoalXP || target > MaxWeeklyGoalXP {
+		return ErrGoalOutOfRange
+	}
+	return nil
+}
+
+// NewWeeklyGoal создаёт цель на неделю.
+func NewWeeklyGoal(studentID string, week ISOWeek, target XP, now time.Time) (*WeeklyGoal, error) {
+	if studentID == "" {
+		return nil, ErrGoalStudentRequired
+	}
+	if err := ValidateGoalTarget(target); err != nil {
+		return nil, err
+	}
+
+	now = now.UTC()
+	return &WeeklyGoal{
+		StudentID: studentID,
+		Week:      week,
+		TargetXP:  target,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// IsReached возвращает true, если цель отмечена достигнутой.
+func (g *WeeklyGoal) IsReached() bool {
+	return g.ReachedAt != nil
+}
+
+// ChangeTarget меняет цель. weekXP - XP, уже набранный за неделю:
+// достигнутую цель, даже ещё не отмеченную, менять нельзя, а новая цель
+// должна быть выше weekXP - иначе её можно было бы снизить до набранного
+// и сразу засчитать.
+func (g *WeeklyGoal) ChangeTarget(target, weekXP XP, now time.Time) error {
+	if err := ValidateGoalTarget(target); err != nil {
+		return err
+	}
+	if g.IsReached() || weekXP >= g.TargetXP {
+		return ErrGoalAlreadyReached
+	}
+	if target <= weekXP {
+		return ErrGoalTargetAlreadyEarned
+	}
+
+	g.TargetXP = target
+	g.UpdatedAt = now.UTC()
+	return nil
+}
+
+// MarkReached отмечает цель достигнутой.
+func (g *WeeklyGoal) MarkReached(at time.Time) {
+	if g.ReachedAt != nil {
+		return
+	}
+	at = at.UTC()
+	g.ReachedAt = &at
+	g.UpdatedAt = at
+}
+
+// WeeklyGoalProgress - прогресс цели в моменте.
+type WeeklyGoalProgress struct {
+	// WeekXP - XP, набранный за неделю.
+	WeekXP XP
+
+	// TargetXP - цель.
+	TargetXP XP
+
+	// RemainingXP - сколько осталось (0, если цель достигнута).
+	RemainingXP XP
+
+	// Percent - процент выполнения (0-100).
+	Percent int
+
+	// DaysRemaining - сколько дней недели осталось, включая сегодняшний.
+	DaysRemaining int
+
+	// Reached - цель достигнута.
+	Reached bool
+}
+
+// Progress вычисляет прогресс цели на момент now.
+func (g *WeeklyGoal) Progress(weekXP XP, now time.Time) WeeklyGoalProgress {
+	p := WeeklyGoalProgress{
+		WeekXP:        weekXP,
+		TargetXP:      g.TargetXP,
+		Reached:       g.IsReached() || weekXP >= g.TargetXP,
+		DaysRemaining: 7 - (int(now.UTC().Weekday())+6)%7,
+	}
+
+	if p.Reached {
+		p.Percent = 100
+		return p
+	}
+
+	p.RemainingXP = g.TargetXP - max(weekXP, 0)
+	if g.TargetXP > 0 {
+		p.Percent = min(int(max(weekXP, 0)*100/g.TargetXP), 99)
+	}
+	return p
+}
+
+// ConsecutiveReachedWeeks считает, сколько недель подряд, заканчивая
+// неделей through, цель была достигнута. Неделя без цели прерывает серию.
+func ConsecutiveReachedWeeks(goals []*WeeklyGoal, through ISOWeek) int {
+	reached := make(map[ISOWeek]bool, len(goals))
+	for _, g := range goals {
+		if g.IsReached() {
+			reached[g.Week] = true
+		}
+	}
+
+	count := 0
+	for week := through; week != "" && reached[week]; week = week.Previous() {
+		count++
+	}
+	return count
+}
+
+// CheckGoalGetter проверяет достижение "Goal Getter": цель достигнута
+// GoalGetterWeeks недель подряд.
+func (ac *AchievementChecker) CheckGoalGetter(
+	weeksInARow int,
+	existingAchievements []Achievement,
+) *Achievement {
+	for _, a := range existingAchievements {
+		if a.Type == AchievementGoalGetter {
+			return nil // Уже есть
+		}
+	}
+
+	if weeksInARow >= GoalGetterWeeks {
+		return &Achievement{
+			Type:       AchievementGoalGetter,
+			UnlockedAt: time.Now().UTC(),
+			Metadata:   map[string]interface{}{"weeks": weeksInARow},
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package student
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestISOWeek_YearBoundary(t *testing.T) {
+	// 1 января 2027 - пятница, неделя 2026-W53
+	week := ISOWeekOf(time.Date(2027, time.January, 1, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, ISOWeek("2026-W53"), week)
+
+	start, err := week.Start()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, time.December, 28, 0, 0, 0, 0, time.UTC), start)
+
+	assert.Equal(t, ISOWeek("2026-W53"), ISOWeek("2027-W01").Previous())
+}
+
+func TestWeeklyGoal_ChangeTargetLockedOnceReached(t *testing.T) {
+	wednesday := time.Date(2026, time.October, 14, 10, 0, 0, 0, time.UTC)
+	goal, err := NewWeeklyGoal("dana", ISOWeekOf(wednesday), 500, wednesday)
+	require.NoError(t, err)
+
+	p := goal.Progress(200, wednesday)
+	assert.Equal(t, XP(300), p.RemainingXP)
+	assert.Equal(t, 40, p.Percent)
+	assert.Equal(t, 5, p.DaysRemaining)
+
+	assert.ErrorIs(t, goal.ChangeTarget(10, 200, wednesday), ErrGoalOutOfRange)
+	require.NoError(t, goal.ChangeTarget(800, 200, wednesday))
+
+	// Снизить цель до уже набранного XP нельзя: она засчиталась бы сразу
+	assert.ErrorIs(t, goal.ChangeTarget(200, 200, wednesday), ErrGoalTargetAlreadyEarned)
+	assert.ErrorIs(t, goal.ChangeTarget(150, 200, wednesday), ErrGoalTargetAlreadyEarned)
+	assert.Equal(t, XP(800), goal.TargetXP)
+
+	// Набранную цель поднять нельзя, даже до отметки джобой
+	assert.ErrorIs(t, goal.ChangeTarget(1000, 800, wednesday), ErrGoalAlreadyReached)
+}
+
+func TestConsecutiveReachedWeeks(t *testing.T) {
+	at := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	reached := func(week ISOWeek) *WeeklyGoal {
+		return &WeeklyGoal{StudentID: "dana", Week: week, TargetXP: 100, ReachedAt: &at}
+	}
+
+	goals := []*WeeklyGoal{
+		reached("2026-W02"), reached("2026-W01"), reached("2025-W52"),
+		{StudentID: "dana", Week: "2025-W51", TargetXP: 100},
+		reached("2025-W50"),
+	}
+
+	assert.Equal(t, 3, ConsecutiveReachedWeeks(goals, "2026-W02"))
+	assert.Equal(t, 0, ConsecutiveReachedWeeks(goals, "2026-W03"))
+}
@@ -0,0 +1,162 @@
+package student
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HELPER STREAK (Серия недель помощи)
+// Кроме серии активных дней считается серия недель, в которые студент
+// помогал другим: закрыл хотя бы один запрос помощи как помощник или
+// получил хотя бы одну благодарность. Недели - ISO-недели по времени Алматы
+// (недельные цели, в отличие от них, считаются по UTC). Пропущенная неделя
+// сбрасывает серию.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// ConsistentHelperWeeks - сколько недель подряд нужно помогать
+	// для достижения "Consistent Helper".
+	ConsistentHelperWeeks = 4
+
+	// ConsistentHelperLongWeeks - вторая ступень "Consistent Helper".
+	ConsistentHelperLongWeeks = 12
+)
+
+// HelperWeekOf возвращает ISO-неделю момента t по времени Алматы.
+func HelperWeekOf(t time.Time) ISOWeek {
+	year, week := t.In(timeutil.AlmatyTZ).ISOWeek()
+	return ISOWeek(fmt.Sprintf("%04d-W%02d", year, week))
+}
+
+// HelperWeekBounds возвращает границы недели по времени Алматы:
+// понедельник 00:00 и следующий понедельник 00:00.
+func HelperWeekBounds(week ISOWeek) (time.Time, time.Time, error) {
+	monday, err := week.Start()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	start := time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, timeutil.AlmatyTZ)
+	return start, start.AddDate(0, 0, 7), nil
+}
+
+// HelperStreak - серия недель помощи студента.
+type HelperStreak struct {
+	// StudentID - ID студента.
+	StudentID string
+
+	// CurrentWeeks - текущая серия недель.
+	CurrentWeeks int
+
+	// BestWeeks - лучшая серия недель.
+	BestWeeks int
+
+	// LastHelpedWeek - последняя неделя, в которую студент помогал
+	// ("" - ещё ни одной).
+	LastHelpedWeek ISOWeek
+
+	// EvaluatedWeek - последняя учтённая неделя. Недели учитываются
+	// по порядку, повторный подсчёт той же недели ничего не меняет.
+	EvaluatedWeek ISOWeek
+
+	// UpdatedAt - когда серия последний раз менялась.
+	UpdatedAt time.Time
+}
+
+// NewHelperStreak создаёт пустую серию.
+func NewHelperStreak(studentID string) *HelperStreak {
+	return &HelperStreak{StudentID: studentID}
+}
+
+// RecordWeek учитывает итог недели: helped - студент помогал в эту неделю.
+// Неделя, помощь в которой идёт сразу за LastHelpedWeek, продлевает серию,
+// иначе серия начинается заново; неделя без помощи сбрасывает её в 0.
+// Возвращает false, если неделя уже учтена.
+func (s *HelperStreak) RecordWeek(week ISOWeek, helped bool, now time.Time) bool {
+	if week == "" || (s.EvaluatedWeek != "" && week <= s.EvaluatedWeek) {
+		return false
+	}
+	s.EvaluatedWeek = week
+	s.UpdatedAt = now.UTC()
+
+	if !helped {
+		s.CurrentWeeks = 0
+		return true
+	}
+
+	if s.LastHelpedWeek != "" && s.LastHelpedWeek == week.Previous() {
+		s.CurrentWeeks++
+	} else {
+		s.CurrentWeeks = 1
+	}
+	s.LastHelpedWeek = week
+	if s.CurrentWeeks > s.BestWeeks {
+		s.BestWeeks = s.CurrentWeeks
+	}
+	return true
+}
+
+// ActiveWeeks возвращает текущую серию на момент now: серия, последняя
+// неделя которой раньше прошлой недели, уже сброшена, даже если задача
+// подсчёта ещё не учла пропуск.
+func (s *HelperStreak) ActiveWeeks(now time.Time) int {
+	if s == nil || s.CurrentWeeks == 0 {
+		return 0
+	}
+	current := HelperWeekOf(now)
+	if s.LastHelpedWeek != current && s.LastHelpedWeek != current.Previous() {
+		return 0
+	}
+	return s.CurrentWeeks
+}
+
+// FormatHelperStreak форматирует серию для карточек: "помогает 6 недель подряд".
+func FormatHelperStreak(weeks int) string {
+	return fmt.Sprintf("помогает %d %s подряд", weeks, pluralWeeks(weeks))
+}
+
+func pluralWeeks(n int) string {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return "неделю"
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 10 || n%100 >= 20):
+		return "недели"
+	default:
+		return "недель"
+	}
+}
+
+// CheckConsistentHelper проверяет достижения "Consistent Helper": помогал
+// ConsistentHelperWeeks и ConsistentHelperLongWeeks недель подряд.
+func (ac *AchievementChecker) CheckConsistentHelper(
+	weeksInARow int,
+	existingAchievements []Achievement,
+) []Achievement {
+	existing := make(map[AchievementType]bool, len(existingAchievements))
+	for _, a := range existingAchievements {
+		existing[a.Type] = true
+	}
+
+	steps := []struct {
+		achievementType AchievementType
+		weeks           int
+	}{
+		{AchievementConsistentHelper, ConsistentHelperWeeks},
+		{AchievementConsistentHelperLong, ConsistentHelperLongWeeks},
+	}
+
+	var result []Achievement
+	for _, step := range steps {
+		if existing[step.achievementType] || weeksInARow < step.weeks {
+			continue
+		}
+		result = append(result, Achievement{
+			Type:       step.achievementType,
+			UnlockedAt: time.Now().UTC(),
+			Metadata:   map[string]interface{}{"weeks": weeksInARow},
+		})
+	}
+	return result
+}
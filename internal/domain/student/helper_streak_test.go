@@ -0,0 +1,75 @@
+package student
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelperWeekOf_UsesAlmatyTime(t *testing.T) {
+	// Воскресенье 20:00 UTC - уже понедельник 01:00 в Алматы
+	sunday := time.Date(2026, time.October, 18, 20, 0, 0, 0, time.UTC)
+	assert.Equal(t, ISOWeek("2026-W42"), ISOWeekOf(sunday))
+	assert.Equal(t, ISOWeek("2026-W43"), HelperWeekOf(sunday))
+
+	start, end, err := HelperWeekBounds("2026-W43")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, time.October, 18, 19, 0, 0, 0, time.UTC), start.UTC())
+	assert.Equal(t, 7*24*time.Hour, end.Sub(start))
+}
+
+func TestHelperStreak_RecordWeek(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	s := NewHelperStreak("dana")
+
+	assert.True(t, s.RecordWeek("2026-W38", true, now))
+	assert.True(t, s.RecordWeek("2026-W39", true, now))
+	assert.Equal(t, 2, s.CurrentWeeks)
+
+	// Повторный подсчёт недели ничего не меняет
+	assert.False(t, s.RecordWeek("2026-W39", true, now))
+	assert.Equal(t, 2, s.CurrentWeeks)
+
+	// Неделя без помощи сбрасывает серию, лучшая остаётся
+	assert.True(t, s.RecordWeek("2026-W40", false, now))
+	assert.Equal(t, 0, s.CurrentWeeks)
+	assert.True(t, s.RecordWeek("2026-W41", true, now))
+	assert.Equal(t, 1, s.CurrentWeeks)
+	assert.Equal(t, 2, s.BestWeeks)
+
+	// Пропуск, который задача не учла, тоже начинает серию заново
+	assert.True(t, s.RecordWeek("2026-W43", true, now))
+	assert.Equal(t, 1, s.CurrentWeeks)
+}
+
+func TestHelperStreak_ActiveWeeks(t *testing.T) {
+	s := &HelperStreak{CurrentWeeks: 6, LastHelpedWeek: "2026-W41"}
+
+	// Неделя W42 ещё идёт: серия держится
+	assert.Equal(t, 6, s.ActiveWeeks(time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)))
+	// W42 закончилась без помощи
+	assert.Equal(t, 0, s.ActiveWeeks(time.Date(2026, time.October, 27, 12, 0, 0, 0, time.UTC)))
+
+	var missing *HelperStreak
+	assert.Equal(t, 0, missing.ActiveWeeks(time.Now()))
+
+	assert.Equal(t, "помогает 6 недель подряд", FormatHelperStreak(6))
+	assert.Equal(t, "помогает 22 недели подряд", FormatHelperStreak(22))
+	assert.Equal(t, "помогает 21 неделю подряд", FormatHelperStreak(21))
+}
+
+func TestCheckConsistentHelper(t *testing.T) {
+	ac := NewAchievementChecker()
+
+	assert.Empty(t, ac.CheckConsistentHelper(3, nil))
+
+	got := ac.CheckConsistentHelper(4, nil)
+	require.Len(t, got, 1)
+	assert.Equal(t, AchievementConsistentHelper, got[0].Type)
+
+	got = ac.CheckConsistentHelper(12, []Achievement{{Type: AchievementConsistentHelper}})
+	require.Len(t, got, 1)
+	assert.Equal(t, AchievementConsistentHelperLong, got[0].Type)
+}
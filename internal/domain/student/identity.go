@@ -0,0 +1,124 @@
+package student
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// IDENTITY (логин Alem и email)
+// Синхронизация ищет студента сначала по логину Alem, потом по email.
+// Email на платформе может смениться, логин - нет. Если логин и email
+// указывают на разные записи, это дубликат: пара помечается для /merge.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ErrIdentityConflict - логин и email принадлежат разным студентам.
+var ErrIdentityConflict = errors.New("alem login and email belong to different students")
+
+// NormalizeAlemLogin приводит логин Alem к виду, в котором он хранится.
+func NormalizeAlemLogin(login string) string {
+	return strings.ToLower(strings.TrimSpace(login))
+}
+
+// SyncLogin возвращает логин, по которому студент запрашивается в Alem.
+// У старых записей без логина берётся часть email до "@".
+func (s *Student) SyncLogin() string {
+	if s.AlemLogin != "" {
+		return s.AlemLogin
+	}
+	if idx := strings.Index(s.Email, "@"); idx > 0 {
+		return s.Email[:idx]
+	}
+	return ""
+}
+
+// IdentityMatchKind - как найден студент.
+type IdentityMatchKind int
+
+const (
+	// IdentityNotFound - ни логин, ни email не найдены.
+	IdentityNotFound IdentityMatchKind = iota
+	// IdentityByLogin - найден по логину Alem.
+	IdentityByLogin
+	// IdentityByEmail - по логину не найден, найден по email.
+	IdentityByEmail
+	// IdentityConflictFound - логин и email у разных студентов.
+	IdentityConflictFound
+)
+
+// IdentityMatch - результат сопоставления студента по логину и email.
+type IdentityMatch struct {
+	Kind IdentityMatchKind
+
+	// Student - найденный студент (nil при IdentityNotFound и конфликте).
+	Student *Student
+
+	// ByLogin и ByEmail - записи, найденные по каждому ключу.
+	ByLogin *Student
+	ByEmail *Student
+}
+
+// MatchIdentity сопоставляет записи, найденные по логину и по email.
+// Логин важнее: если он найден, email лишь проверяется на конфликт.
+// Покинувшие программу (в том числе слитые дубликаты) не учитываются.
+func MatchIdentity(byLogin, byEmail *Student) IdentityMatch {
+	if byLogin != nil && byLogin.Status == StatusLeft {
+		byLogin = nil
+	}
+	if byEmail != nil && byEmail.Status == StatusLeft {
+		byEmail = nil
+	}
+
+	match := IdentityMatch{ByLogin: byLogin, ByEmail: byEmail}
+	switch {
+	case byLogin != nil && byEmail != nil && byLogin.ID != byEmail.ID:
+		match.Kind = IdentityConflictFound
+	case byLogin != nil:
+		match.Kind = IdentityByLogin
+		match.Student = byLogin
+	case byEmail != nil:
+		match.Kind = IdentityByEmail
+		match.Student = byEmail
+	default:
+		match.Kind = IdentityNotFound
+	}
+	return match
+}
+
+// IdentityConflict - пара записей, которые, судя по логину и email, являются
+// одним студентом. Её разбирает администратор через /merge.
+type IdentityConflict struct {
+	// LoginStudentID - запись с этим логином Alem.
+	LoginStudentID string
+
+	// EmailStudentID - запись с этим email.
+	EmailStudentID string
+
+	AlemLogin string
+	Email     string
+
+	// DetectedAt - когда конфликт обнаружен впервые.
+	DetectedAt time.Time
+}
+
+// NewIdentityConflict создаёт конфликт из результата сопоставления.
+func NewIdentityConflict(match IdentityMatch, login, email string) IdentityConflict {
+	return IdentityConflict{
+		LoginStudentID: match.ByLogin.ID,
+		EmailStudentID: match.ByEmail.ID,
+		AlemLogin:      NormalizeAlemLogin(login),
+		Email:          email,
+		DetectedAt:     time.Now().UTC(),
+	}
+}
+
+// IdentityConflictRepository хранит пары, ожидающие слияния.
+type IdentityConflictRepository interface {
+	// Flag помечает пару. Повторная пометка той же пары ничего не меняет.
+	Flag(ctx context.Context, conflict IdentityConflict) error
+
+	// ListOpen возвращает пары, где обе записи ещё не слиты, старые первыми.
+	ListOpen(ctx context.Context, limit int) ([]IdentityConflict, error)
+}
@@ -0,0 +1,42 @@
+package student
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchIdentity(t *testing.T) {
+	a := &Student{ID: "a", Status: StatusActive}
+	b := &Student{ID: "b", Status: StatusActive}
+	merged := &Student{ID: "m", Status: StatusLeft}
+
+	m := MatchIdentity(a, a)
+	assert.Equal(t, IdentityByLogin, m.Kind)
+	assert.Same(t, a, m.Student)
+
+	m = MatchIdentity(a, nil)
+	assert.Equal(t, IdentityByLogin, m.Kind, "login wins even when the email is new")
+	assert.Same(t, a, m.Student)
+
+	m = MatchIdentity(nil, b)
+	assert.Equal(t, IdentityByEmail, m.Kind)
+	assert.Same(t, b, m.Student)
+
+	m = MatchIdentity(a, b)
+	assert.Equal(t, IdentityConflictFound, m.Kind)
+	assert.Nil(t, m.Student)
+
+	m = MatchIdentity(merged, b)
+	assert.Equal(t, IdentityByEmail, m.Kind, "merged duplicates are ignored")
+	assert.Same(t, b, m.Student)
+
+	assert.Equal(t, IdentityNotFound, MatchIdentity(nil, nil).Kind)
+}
+
+func TestStudent_SyncLogin(t *testing.T) {
+	assert.Equal(t, "akim", (&Student{AlemLogin: "akim", Email: "old@alem.school"}).SyncLogin())
+	assert.Equal(t, "old", (&Student{Email: "old@alem.school"}).SyncLogin())
+	assert.Equal(t, "", (&Student{Email: "broken"}).SyncLogin())
+	assert.Equal(t, "akim", NormalizeAlemLogin("  Akim "))
+}
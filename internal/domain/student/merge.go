@@ -0,0 +1,96 @@
+package student
+
+import (
+	"sort"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MERGE (слияние дубликатов)
+// Правила переноса прогресса, когда у одного студента оказалось два аккаунта
+// (например, один из импорта потока, другой из /start с опечаткой в email).
+// ══════════════════════════════════════════════════════════════════════════════
+
+// MergeDailyGrinds переносит ежедневный прогресс from на аккаунт intoID.
+// Дни, которые есть только у from, переносятся как есть. Если день есть
+// у обоих аккаунтов, счётчики складываются, а позиции в рейтинге берутся
+// из into. Возвращает строки, которые нужно записать для intoID, и число
+// сложенных дней.
+func MergeDailyGrinds(intoID string, into, from []*DailyGrind) ([]*DailyGrind, int) {
+	byDate := make(map[int64]*DailyGrind, len(into))
+	for _, g := range into {
+		byDate[g.Date.Unix()] = g
+	}
+
+	merged := make([]*DailyGrind, 0, len(from))
+	conflicts := 0
+
+	for _, g := range from {
+		existing, ok := byDate[g.Date.Unix()]
+		if !ok {
+			moved := *g
+			moved.StudentID = intoID
+			merged = append(merged, &moved)
+			continue
+		}
+
+		conflicts++
+		merged = append(merged, sumDailyGrinds(intoID, existing, g))
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Date.Before(merged[j].Date)
+	})
+
+	return merged, conflicts
+}
+
+// sumDailyGrinds складывает прогресс двух аккаунтов за один день.
+func sumDailyGrinds(intoID string, into, from *DailyGrind) *DailyGrind {
+	sum := *into
+	sum.StudentID = intoID
+	sum.XPGained = into.XPGained + from.XPGained
+	sum.XPCurrent = into.XPStart + sum.XPGained
+	sum.TasksCompleted = into.TasksCompleted + from.TasksCompleted
+	sum.SessionsCount = into.SessionsCount + from.SessionsCount
+	sum.TotalSessionMinutes = into.TotalSessionMinutes + from.TotalSessionMinutes
+
+	if sum.FirstActivityAt.IsZero() || (!from.FirstActivityAt.IsZero() && from.FirstActivityAt.Before(sum.FirstActivityAt)) {
+		sum.FirstActivityAt = from.FirstActivityAt
+	}
+	if from.LastActivityAt.After(sum.LastActivityAt) {
+		sum.LastActivityAt = from.LastActivityAt
+	}
+	if from.StreakDay > sum.StreakDay {
+		sum.StreakDay = from.StreakDay
+	}
+
+	return &sum
+}
+
+// BetterStreak выбирает серию, которая остаётся после слияния.
+// Побеждает более длинная текущая серия, при равенстве - более свежая.
+// Лучшая серия берётся максимальной из двух. Любой из аргументов может быть nil.
+// Второе значение - true, если текущая серия взята из from.
+func BetterStreak(intoID string, into, from *Streak) (*Streak, bool) {
+	if into == nil && from == nil {
+		return nil, false
+	}
+	if into == nil {
+		into = NewStreak(intoID)
+	}
+	if from == nil {
+		from = NewStreak(intoID)
+	}
+
+	winner := *into
+	fromWon := from.CurrentStreak > into.CurrentStreak ||
+		(from.CurrentStreak == into.CurrentStreak && from.LastActiveDate.After(into.LastActiveDate))
+	if fromWon {
+		winner = *from
+	}
+
+	winner.StudentID = intoID
+	winner.BestStreak = max(into.BestStreak, from.BestStreak, winner.CurrentStreak)
+
+	return &winner, fromWon
+}
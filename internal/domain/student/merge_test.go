@@ -0,0 +1,68 @@
+package student
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeDailyGrinds_SumsSameDay(t *testing.T) {
+	day1 := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	into := []*DailyGrind{{
+		StudentID:       "a",
+		Date:            day1,
+		XPStart:         1000,
+		XPCurrent:       1100,
+		XPGained:        100,
+		TasksCompleted:  1,
+		SessionsCount:   1,
+		FirstActivityAt: day1.Add(10 * time.Hour),
+		LastActivityAt:  day1.Add(11 * time.Hour),
+		RankCurrent:     42,
+		StreakDay:       3,
+	}}
+	from := []*DailyGrind{
+		{
+			StudentID:       "b",
+			Date:            day1,
+			XPStart:         0,
+			XPCurrent:       250,
+			XPGained:        250,
+			TasksCompleted:  2,
+			SessionsCount:   2,
+			FirstActivityAt: day1.Add(8 * time.Hour),
+			LastActivityAt:  day1.Add(20 * time.Hour),
+			RankCurrent:     300,
+			StreakDay:       1,
+		},
+		{StudentID: "b", Date: day2, XPGained: 50, TasksCompleted: 1},
+	}
+
+	merged, conflicts := MergeDailyGrinds("a", into, from)
+
+	require.Len(t, merged, 2)
+	assert.Equal(t, 1, conflicts)
+
+	sum := merged[0]
+	assert.Equal(t, "a", sum.StudentID)
+	assert.Equal(t, day1, sum.Date)
+	assert.Equal(t, XP(1000), sum.XPStart)
+	assert.Equal(t, XP(350), sum.XPGained)
+	assert.Equal(t, XP(1350), sum.XPCurrent)
+	assert.Equal(t, 3, sum.TasksCompleted)
+	assert.Equal(t, 3, sum.SessionsCount)
+	assert.Equal(t, day1.Add(8*time.Hour), sum.FirstActivityAt)
+	assert.Equal(t, day1.Add(20*time.Hour), sum.LastActivityAt)
+	assert.Equal(t, 42, sum.RankCurrent, "rank stays with the kept account")
+	assert.Equal(t, 3, sum.StreakDay)
+
+	assert.Equal(t, "a", merged[1].StudentID)
+	assert.Equal(t, day2, merged[1].Date)
+	assert.Equal(t, XP(50), merged[1].XPGained)
+
+	assert.Equal(t, "b", from[0].StudentID, "input is not modified")
+}
@@ -0,0 +1,68 @@
+package student
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MILESTONE PROXIMITY (Близость к вехе)
+// Студент "почти у цели": до вехи серии остался день или до следующего
+// уровня - меньше NearLevelXP. Этим пользуются прогноз (ближайший уровень)
+// и кнопка "Подбодрить" у напарников.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// NearLevelXP - за сколько XP до следующего уровня студент считается почти
+// у цели.
+const NearLevelXP XP = 100
+
+// MilestoneKind - вид вехи.
+type MilestoneKind string
+
+const (
+	// MilestoneKindStreak - веха серии (StreakMilestones).
+	MilestoneKindStreak MilestoneKind = "streak"
+
+	// MilestoneKindLevel - следующий уровень.
+	MilestoneKindLevel MilestoneKind = "level"
+)
+
+// UpcomingMilestone - веха, до которой студенту осталось чуть-чуть.
+type UpcomingMilestone struct {
+	// Kind - вид вехи.
+	Kind MilestoneKind
+
+	// Target - длина серии в днях или номер уровня.
+	Target int
+
+	// Remaining - сколько осталось: дней серии или XP.
+	Remaining int
+}
+
+// NextStreakMilestone возвращает ближайшую веху серии больше current.
+// false - все вехи уже пройдены.
+func NextStreakMilestone(current int) (int, bool) {
+	for _, milestone := range StreakMilestones {
+		if milestone > current {
+			return milestone, true
+		}
+	}
+	return 0, false
+}
+
+// NextLevel возвращает уровень, следующий за уровнем xp.
+func NextLevel(xp XP) Level {
+	return CalculateLevel(xp) + 1
+}
+
+// NearMilestone проверяет, близок ли студент к вехе. Веха серии важнее
+// уровня: её можно потерять, пропустив день. streak может быть nil.
+func NearMilestone(xp XP, streak *Streak) (UpcomingMilestone, bool) {
+	if streak != nil && streak.CurrentStreak > 0 && !streak.IsBroken() {
+		if milestone, ok := NextStreakMilestone(streak.CurrentStreak); ok && milestone-streak.CurrentStreak == 1 {
+			return UpcomingMilestone{Kind: MilestoneKindStreak, Target: milestone, Remaining: 1}, true
+		}
+	}
+
+	next := NextLevel(xp)
+	if remaining := next.MinXP() - xp; remaining <= NearLevelXP {
+		return UpcomingMilestone{Kind: MilestoneKindLevel, Target: int(next), Remaining: int(remaining)}, true
+	}
+
+	return UpcomingMilestone{}, false
+}
@@ -0,0 +1,194 @@
+package student
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MUTE
+// Временная тишина: студент глушит бота целиком (/mute 24h) или одну
+// категорию уведомлений (/mute rank). В отличие от настроек, заглушка
+// заканчивается сама, а первое уведомление после неё сообщает об этом.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// MuteCategory - категория уведомлений, которую можно заглушить отдельно.
+type MuteCategory string
+
+const (
+	// MuteCategoryRank - изменения позиции в рейтинге.
+	MuteCategoryRank MuteCategory = "rank"
+
+	// MuteCategoryDigest - ежедневная сводка.
+	MuteCategoryDigest MuteCategory = "digest"
+
+	// MuteCategoryHelp - запросы и предложения помощи.
+	MuteCategoryHelp MuteCategory = "help"
+
+	// MuteCategoryOther - уведомления без своей категории: их глушит
+	// только общая заглушка.
+	MuteCategoryOther MuteCategory = ""
+)
+
+// MuteCategories возвращает категории, которые можно заглушить отдельно.
+func MuteCategories() []MuteCategory {
+	return []MuteCategory{MuteCategoryRank, MuteCategoryDigest, MuteCategoryHelp}
+}
+
+// MuteCategoryFor возвращает категорию заглушки для типа уведомления
+// (notification.NotificationType).
+func MuteCategoryFor(notificationType string) MuteCategory {
+	switch notificationType {
+	case "rank_up", "rank_down", "entered_top", "left_top":
+		return MuteCategoryRank
+	case "daily_digest", "weekly_digest":
+		return MuteCategoryDigest
+	case "help_request", "help_offer", "endorsement_received":
+		return MuteCategoryHelp
+	default:
+		return MuteCategoryOther
+	}
+}
+
+// ParseMuteCategory разбирает категорию из аргумента команды.
+func ParseMuteCategory(s string) (MuteCategory, bool) {
+	c := MuteCategory(strings.ToLower(strings.TrimSpace(s)))
+	for _, known := range MuteCategories() {
+		if c == known {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// MutePresets - длительности, предлагаемые кнопками /mute.
+var MutePresets = []time.Duration{time.Hour, 24 * time.Hour, 7 * 24 * time.Hour}
+
+// DefaultCategoryMute - на сколько глушится категория без указания срока.
+const DefaultCategoryMute = 24 * time.Hour
+
+// MaxMuteDuration - самая долгая заглушка. Дольше - это уже настройки.
+const MaxMuteDuration = 30 * 24 * time.Hour
+
+// MuteEndedNotice добавляется к первому уведомлению после заглушки.
+const MuteEndedNotice = "🔕 режим тишины закончился"
+
+// ErrInvalidMuteDuration - длительность не разобрана или вне допустимого.
+var ErrInvalidMuteDuration = errors.New("invalid mute duration")
+
+// ParseMuteDuration разбирает длительность вида "30m", "1h", "24h", "7d".
+func ParseMuteDuration(s string) (time.Duration, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return 0, ErrInvalidMuteDuration
+	}
+
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, ErrInvalidMuteDuration
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, ErrInvalidMuteDuration
+		}
+	}
+
+	if d < time.Minute || d > MaxMuteDuration {
+		return 0, ErrInvalidMuteDuration
+	}
+	return d, nil
+}
+
+// FormatMuteDuration форматирует длительность для кнопок и ответов: "1h", "7d".
+func FormatMuteDuration(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour && d%(24*time.Hour) == 0:
+		return strconv.Itoa(int(d/(24*time.Hour))) + "d"
+	case d >= time.Hour && d%time.Hour == 0:
+		return strconv.Itoa(int(d/time.Hour)) + "h"
+	default:
+		return strconv.Itoa(int(d/time.Minute)) + "m"
+	}
+}
+
+// Mute глушит все несрочные уведомления до now+d.
+func (s *Student) Mute(d time.Duration, now time.Time) {
+	until := now.Add(d).UTC()
+	s.MutedUntil = &until
+	s.UpdatedAt = now.UTC()
+}
+
+// MuteCategory глушит одну категорию уведомлений до now+d.
+func (s *Student) MuteCategory(category MuteCategory, d time.Duration, now time.Time) {
+	if s.Preferences.CategoryMutes == nil {
+		s.Preferences.CategoryMutes = make(map[MuteCategory]time.Time)
+	}
+	s.Preferences.CategoryMutes[category] = now.Add(d).UTC()
+	s.UpdatedAt = now.UTC()
+}
+
+// Unmute снимает все заглушки, в том числе истёкшие.
+func (s *Student) Unmute(now time.Time) {
+	s.MutedUntil = nil
+	s.Preferences.CategoryMutes = nil
+	s.UpdatedAt = now.UTC()
+}
+
+// IsMuted проверяет, заглушены ли сейчас уведомления категории category.
+// Общая заглушка глушит любую категорию. Срок не включительный: в момент
+// окончания уведомления уже приходят.
+func (s *Student) IsMuted(category MuteCategory, now time.Time) bool {
+	if s.MutedUntil != nil && now.Before(*s.MutedUntil) {
+		return true
+	}
+	if category == MuteCategoryOther {
+		return false
+	}
+	until, ok := s.Preferences.CategoryMutes[category]
+	return ok && now.Before(until)
+}
+
+// HasMutes возвращает true, если есть хоть одна заглушка (в том числе истёкшая).
+func (s *Student) HasMutes() bool {
+	return s.MutedUntil != nil || len(s.Preferences.CategoryMutes) > 0
+}
+
+// TakeMuteEndedNotice убирает истёкшие заглушки и возвращает true, если
+// такие были: тогда уведомление нужно дополнить MuteEndedNotice, а
+// заглушки сохранить. Действующие заглушки не трогаются.
+func (s *Student) TakeMuteEndedNotice(now time.Time) bool {
+	ended := false
+
+	if s.MutedUntil != nil && !now.Before(*s.MutedUntil) {
+		s.MutedUntil = nil
+		ended = true
+	}
+	for category, until := range s.Preferences.CategoryMutes {
+		if !now.Before(until) {
+			delete(s.Preferences.CategoryMutes, category)
+			ended = true
+		}
+	}
+	if len(s.Preferences.CategoryMutes) == 0 {
+		s.Preferences.CategoryMutes = nil
+	}
+
+	return ended
+}
+
+// WithMuteEndedNotice дополняет текст уведомления отметкой об окончании
+// заглушки, если она закончилась и об этом ещё не сообщали. Возвращает
+// новый текст и true, если заглушки изменились и их нужно сохранить
+// (StudentRepository.SaveMutes) после успешной отправки.
+func (s *Student) WithMuteEndedNotice(message string, now time.Time) (string, bool) {
+	if !s.HasMutes() || !s.TakeMuteEndedNotice(now) {
+		return message, false
+	}
+	return MuteEndedNotice + "\n\n" + message, true
+}
@@ -0,0 +1,140 @@
+package student
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStudent_IsMuted_OverlappingMutes(t *testing.T) {
+	now := time.Date(2026, time.May, 10, 12, 0, 0, 0, time.UTC)
+
+	s := &Student{}
+	s.Mute(time.Hour, now)
+	s.MuteCategory(MuteCategoryRank, 24*time.Hour, now)
+
+	// Пока действует общая заглушка, молчит всё
+	at := now.Add(30 * time.Minute)
+	assert.True(t, s.IsMuted(MuteCategoryOther, at))
+	assert.True(t, s.IsMuted(MuteCategoryRank, at))
+	assert.True(t, s.IsMuted(MuteCategoryDigest, at))
+
+	// Общая закончилась - молчит только рейтинг
+	at = now.Add(2 * time.Hour)
+	assert.False(t, s.IsMuted(MuteCategoryOther, at))
+	assert.True(t, s.IsMuted(MuteCategoryRank, at))
+	assert.False(t, s.IsMuted(MuteCategoryDigest, at))
+
+	// Длинная общая поверх короткой категорийной глушит категорию дольше
+	s.Unmute(now)
+	s.MuteCategory(MuteCategoryHelp, time.Hour, now)
+	s.Mute(7*24*time.Hour, now)
+	assert.True(t, s.IsMuted(MuteCategoryHelp, now.Add(48*time.Hour)))
+}
+
+func TestStudent_IsMuted_ExpiryBoundary(t *testing.T) {
+	now := time.Date(2026, time.May, 10, 12, 0, 0, 0, time.UTC)
+	until := now.Add(24 * time.Hour)
+
+	s := &Student{}
+	s.Mute(24*time.Hour, now)
+	s.MuteCategory(MuteCategoryDigest, 24*time.Hour, now)
+
+	assert.True(t, s.IsMuted(MuteCategoryOther, until.Add(-time.Nanosecond)))
+	assert.True(t, s.IsMuted(MuteCategoryDigest, until.Add(-time.Nanosecond)))
+	assert.False(t, s.IsMuted(MuteCategoryOther, until))
+	assert.False(t, s.IsMuted(MuteCategoryDigest, until))
+}
+
+func TestStudent_AcceptsNotification(t *testing.T) {
+	now := time.Date(2026, time.May, 10, 12, 0, 0, 0, time.UTC)
+
+	s := &Student{Preferences: DefaultNotificationPreferences()}
+	s.MuteCategory(MuteCategoryRank, time.Hour, now)
+
+	// Категорийная заглушка глушит все типы своей категории
+	assert.False(t, s.AcceptsNotification("rank_down", now))
+	assert.False(t, s.AcceptsNotification("entered_top", now))
+	assert.True(t, s.AcceptsNotification("daily_digest", now))
+	assert.True(t, s.AcceptsNotification("streak_milestone", now))
+
+	// Отключённая настройка действует для всех типов рейтинга
+	s.Unmute(now)
+	s.Preferences.RankChanges = false
+	assert.False(t, s.AcceptsNotification("rank_up", now))
+	assert.False(t, s.AcceptsNotification("left_top", now))
+
+	// Общая заглушка глушит и типы без категории
+	s.Mute(time.Hour, now)
+	assert.False(t, s.AcceptsNotification("goal_reached", now))
+	assert.True(t, s.AcceptsNotification("goal_reached", now.Add(time.Hour)))
+}
+
+func TestStudent_WithMuteEndedNotice(t *testing.T) {
+	now := time.Date(2026, time.May, 10, 12, 0, 0, 0, time.UTC)
+
+	s := &Student{}
+	msg, changed := s.WithMuteEndedNotice("привет", now)
+	assert.False(t, changed, "без заглушек отметки нет")
+	assert.Equal(t, "привет", msg)
+
+	s.Mute(time.Hour, now)
+	s.MuteCategory(MuteCategoryRank, 24*time.Hour, now)
+
+	// Общая истекла, категорийная ещё действует: снимаем только общую
+	msg, changed = s.WithMuteEndedNotice("привет", now.Add(time.Hour))
+	assert.True(t, changed)
+	assert.Equal(t, MuteEndedNotice+"\n\nпривет", msg)
+	assert.Nil(t, s.MutedUntil)
+	assert.Contains(t, s.Preferences.CategoryMutes, MuteCategoryRank)
+
+	// Отметка приходит один раз
+	msg, changed = s.WithMuteEndedNotice("привет", now.Add(2*time.Hour))
+	assert.False(t, changed)
+	assert.Equal(t, "привет", msg)
+
+	_, changed = s.WithMuteEndedNotice("привет", now.Add(24*time.Hour))
+	assert.True(t, changed)
+	assert.False(t, s.HasMutes())
+}
+
+func TestParseMuteDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"1h", time.Hour, true},
+		{"24h", 24 * time.Hour, true},
+		{"7d", 7 * 24 * time.Hour, true},
+		{" 30M ", 30 * time.Minute, true},
+		{"30d", MaxMuteDuration, true},
+		{"31d", 0, false},
+		{"30s", 0, false},
+		{"-1h", 0, false},
+		{"week", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseMuteDuration(tt.in)
+			if !tt.ok {
+				assert.ErrorIs(t, err, ErrInvalidMuteDuration)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, got, mustParse(t, FormatMuteDuration(got)))
+		})
+	}
+}
+
+func mustParse(t *testing.T, s string) time.Duration {
+	t.Helper()
+	d, err := ParseMuteDuration(s)
+	require.NoError(t, err)
+	return d
+}
@@ -0,0 +1,45 @@
+package student
+
+import (
+	"context"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// ON-DEMAND SYNC (синхронизация по запросу)
+// /sync_me синхронизирует одного студента сразу, не дожидаясь очередного
+// прохода worker. Такие запросы помечаются в context: клиент Alem берёт
+// для них токены из отдельной доли бюджета, чтобы вечерний наплыв не
+// съедал лимит пакетной синхронизации. Пакетная синхронизация и /sync_me
+// не синхронизируют одного студента одновременно (SyncLock).
+// ══════════════════════════════════════════════════════════════════════════════
+
+// OnDemandSyncCooldown - как часто студент может запускать /sync_me.
+const OnDemandSyncCooldown = 3 * time.Minute
+
+// onDemandSyncKey - ключ пометки запроса в context.
+type onDemandSyncKey struct{}
+
+// WithOnDemandSync помечает запросы синхронизации как запрошенные студентом.
+func WithOnDemandSync(ctx context.Context) context.Context {
+	return context.WithValue(ctx, onDemandSyncKey{}, true)
+}
+
+// IsOnDemandSync проверяет, запрошена ли синхронизация студентом.
+func IsOnDemandSync(ctx context.Context) bool {
+	v, _ := ctx.Value(onDemandSyncKey{}).(bool)
+	return v
+}
+
+// SyncLock - блокировка синхронизации одного студента, общая для бота и
+// worker. Блокировка истекает сама, если держатель упал.
+type SyncLock interface {
+	// TryLock берёт блокировку. false - студента уже синхронизируют.
+	TryLock(ctx context.Context, studentID string) (bool, error)
+
+	// Unlock снимает блокировку.
+	Unlock(ctx context.Context, studentID string) error
+
+	// IsLocked проверяет, синхронизируют ли студента сейчас.
+	IsLocked(ctx context.Context, studentID string) (bool, error)
+}
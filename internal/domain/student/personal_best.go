@@ -0,0 +1,153 @@
+package student
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PERSONAL BESTS (Личные рекорды)
+// Студентов мотивируют их собственные рекорды, а не только рейтинг:
+// лучший день, лучшая неделя и самая длинная сессия.
+// Рекорд обновляется только строго большим значением, поэтому повторная
+// обработка того же дня ничего не меняет и не шлёт уведомлений.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// PersonalBestMetric - вид личного рекорда.
+type PersonalBestMetric string
+
+const (
+	// PersonalBestDayXP - больше всего XP за день (UTC).
+	PersonalBestDayXP PersonalBestMetric = "best_day_xp"
+
+	// PersonalBestWeekXP - больше всего XP за ISO-неделю.
+	// AchievedOn - понедельник недели.
+	PersonalBestWeekXP PersonalBestMetric = "best_week_xp"
+
+	// PersonalBestSessionMinutes - самая длинная сессия в минутах.
+	PersonalBestSessionMinutes PersonalBestMetric = "longest_session_minutes"
+)
+
+// PersonalBestMetrics - все виды рекордов в порядке отображения.
+var PersonalBestMetrics = []PersonalBestMetric{
+	PersonalBestDayXP,
+	PersonalBestWeekXP,
+	PersonalBestSessionMinutes,
+}
+
+// ErrInvalidPersonalBest - рекорд с неизвестной метрикой или без студента.
+var ErrInvalidPersonalBest = errors.New("invalid personal best")
+
+// IsValid проверяет, что метрика известна.
+func (m PersonalBestMetric) IsValid() bool {
+	switch m {
+	case PersonalBestDayXP, PersonalBestWeekXP, PersonalBestSessionMinutes:
+		return true
+	default:
+		return false
+	}
+}
+
+// Label возвращает название рекорда для отображения.
+func (m PersonalBestMetric) Label() string {
+	switch m {
+	case PersonalBestDayXP:
+		return "Лучший день"
+	case PersonalBestWeekXP:
+		return "Лучшая неделя"
+	case PersonalBestSessionMinutes:
+		return "Самая длинная сессия"
+	default:
+		return string(m)
+	}
+}
+
+// FormatValue форматирует значение рекорда ("820 XP", "2 ч 15 мин").
+func (m PersonalBestMetric) FormatValue(value int) string {
+	if m != PersonalBestSessionMinutes {
+		return fmt.Sprintf("%d XP", value)
+	}
+	if value < 60 {
+		return fmt.Sprintf("%d мин", value)
+	}
+	if value%60 == 0 {
+		return fmt.Sprintf("%d ч", value/60)
+	}
+	return fmt.Sprintf("%d ч %d мин", value/60, value%60)
+}
+
+// PersonalBest - личный рекорд студента по одной метрике.
+type PersonalBest struct {
+	StudentID string
+	Metric    PersonalBestMetric
+	Value     int
+
+	// AchievedOn - дата (UTC, 00:00), когда рекорд установлен.
+	AchievedOn time.Time
+}
+
+// NewPersonalBest создаёт рекорд; дата обрезается до дня по UTC.
+func NewPersonalBest(studentID string, metric PersonalBestMetric, value int, achievedOn time.Time) (PersonalBest, error) {
+	if studentID == "" || !metric.IsValid() || value < 0 {
+		return PersonalBest{}, ErrInvalidPersonalBest
+	}
+	y, m, d := achievedOn.UTC().Date()
+	return PersonalBest{
+		StudentID:  studentID,
+		Metric:     metric,
+		Value:      value,
+		AchievedOn: time.Date(y, m, d, 0, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+// Beats возвращает true, если рекорд строго лучше other.
+// Равное значение рекордом не считается.
+func (b PersonalBest) Beats(other *PersonalBest) bool {
+	return other == nil || b.Value > other.Value
+}
+
+// Message возвращает текст уведомления о новом рекорде.
+func (b PersonalBest) Message() string {
+	switch b.Metric {
+	case PersonalBestDayXP:
+		return fmt.Sprintf("🏆 Новый личный рекорд: %d XP за день!", b.Value)
+	case PersonalBestWeekXP:
+		return fmt.Sprintf("🏆 Новый личный рекорд: %d XP за неделю!", b.Value)
+	default:
+		return fmt.Sprintf("🏆 Новый личный рекорд: сессия %s!", b.Metric.FormatValue(b.Value))
+	}
+}
+
+// PersonalBestUpdate - результат попытки обновить рекорд.
+type PersonalBestUpdate struct {
+	// Best - рекорд после обновления.
+	Best PersonalBest
+
+	// Previous - рекорд до обновления (nil, если его не было).
+	Previous *PersonalBest
+
+	// Improved - рекорд был обновлён.
+	Improved bool
+}
+
+// ShouldNotify возвращает true, если о рекорде стоит сообщить студенту:
+// рекорд побит, он был и раньше (первое значение - не достижение),
+// и прежний рекорд установлен в другой день. Последнее защищает от
+// повторных уведомлений, когда тот же день пересчитывается с большим XP.
+func (u PersonalBestUpdate) ShouldNotify() bool {
+	return u.Improved &&
+		u.Previous != nil &&
+		!u.Previous.AchievedOn.Equal(u.Best.AchievedOn)
+}
+
+// PersonalBestRepository хранит личные рекорды студентов.
+type PersonalBestRepository interface {
+	// GetBests возвращает рекорды студента (только установленные).
+	GetBests(ctx context.Context, studentID string) ([]PersonalBest, error)
+
+	// UpsertBest сохраняет рекорд, только если он строго больше текущего.
+	// Сравнение и запись выполняются атомарно.
+	UpsertBest(ctx context.Context, best PersonalBest) (PersonalBestUpdate, error)
+}
@@ -0,0 +1,19 @@
+package student
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersonalBestUpdate_ShouldNotify(t *testing.T) {
+	prev := &PersonalBest{Value: 820, AchievedOn: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)}
+	best := PersonalBest{Value: 820, AchievedOn: time.Date(2025, time.March, 9, 0, 0, 0, 0, time.UTC)}
+
+	assert.False(t, PersonalBestUpdate{Best: *prev, Previous: prev}.ShouldNotify(), "ничья")
+	assert.False(t, PersonalBestUpdate{Best: best, Improved: true}.ShouldNotify(), "первый рекорд")
+
+	best.Value = 821
+	assert.True(t, PersonalBestUpdate{Best: best, Previous: prev, Improved: true}.ShouldNotify())
+}
@@ -0,0 +1,112 @@
+package student
+
+import "time"
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PLATEAU
+// Студент "застрял": раньше стабильно набирал XP, а теперь несколько дней
+// подряд заходит на платформу, но XP не растёт. Обычно это значит, что он
+// бьётся над одной задачей и ему пора предложить помощь.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// PlateauCriteria описывает, что считать застреванием.
+type PlateauCriteria struct {
+	// PlateauDays - сколько последних полных дней подряд без XP.
+	PlateauDays int
+
+	// MinOnlineDays - в скольких из этих дней студент был онлайн.
+	MinOnlineDays int
+
+	// LookbackDays - длина периода перед плато, в котором оценивается
+	// прежняя активность.
+	LookbackDays int
+
+	// MinActiveDays - сколько дней с XP должно быть в этом периоде.
+	MinActiveDays int
+}
+
+// DefaultPlateauCriteria возвращает критерии по умолчанию: не меньше 3 дней
+// с XP за две недели, затем 4 дня без XP с заходами хотя бы в 3 из них.
+func DefaultPlateauCriteria() PlateauCriteria {
+	return PlateauCriteria{
+		PlateauDays:   4,
+		MinOnlineDays: 3,
+		LookbackDays:  14,
+		MinActiveDays: 3,
+	}
+}
+
+// HistoryDays возвращает, за сколько дней нужна история для проверки.
+func (c PlateauCriteria) HistoryDays() int {
+	return c.PlateauDays + c.LookbackDays
+}
+
+// Plateau - найденное застревание.
+type Plateau struct {
+	// Since - первый день без XP.
+	Since time.Time
+
+	// Days - длина плато в днях.
+	Days int
+
+	// OnlineDays - дней плато, в которые студент заходил.
+	OnlineDays int
+
+	// ActiveDaysBefore - дней с XP в периоде перед плато.
+	ActiveDaysBefore int
+}
+
+// DetectPlateau проверяет дневную историю студента на застревание.
+// Плато - последние PlateauDays полных дней перед today (сам today ещё
+// не закончился и не учитывается). Дни без записи считаются днями без XP
+// и без заходов. Порядок history не важен.
+func DetectPlateau(history []*DailyGrind, today time.Time, c PlateauCriteria) (*Plateau, bool) {
+	if c.PlateauDays <= 0 {
+		return nil, false
+	}
+
+	byDay := make(map[time.Time]*DailyGrind, len(history))
+	for _, g := range history {
+		if g != nil {
+			byDay[dayOf(g.Date)] = g
+		}
+	}
+
+	plateauStart := dayOf(today).AddDate(0, 0, -c.PlateauDays)
+	lookbackStart := plateauStart.AddDate(0, 0, -c.LookbackDays)
+
+	p := &Plateau{Since: plateauStart, Days: c.PlateauDays}
+
+	for day := plateauStart; day.Before(dayOf(today)); day = day.AddDate(0, 0, 1) {
+		g, ok := byDay[day]
+		if !ok {
+			continue
+		}
+		if g.XPGained > 0 {
+			return nil, false
+		}
+		if g.SessionsCount > 0 {
+			p.OnlineDays++
+		}
+	}
+	if p.OnlineDays < c.MinOnlineDays {
+		return nil, false
+	}
+
+	for day := lookbackStart; day.Before(plateauStart); day = day.AddDate(0, 0, 1) {
+		if g, ok := byDay[day]; ok && g.XPGained > 0 {
+			p.ActiveDaysBefore++
+		}
+	}
+	if p.ActiveDaysBefore < c.MinActiveDays {
+		return nil, false
+	}
+
+	return p, true
+}
+
+// dayOf возвращает начало дня t в UTC (как хранится DailyGrind.Date).
+func dayOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,67 @@
+package student
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// grindPattern builds a daily history ending yesterday, one character per day:
+// 'x' - XP gained, 'o' - online without XP, '.' - no row.
+func grindPattern(pattern string, today time.Time) []*DailyGrind {
+	var history []*DailyGrind
+	for i, c := range pattern {
+		date := today.AddDate(0, 0, i-len(pattern))
+		switch c {
+		case 'x':
+			history = append(history, &DailyGrind{Date: date, XPGained: 50, SessionsCount: 1})
+		case 'o':
+			history = append(history, &DailyGrind{Date: date, SessionsCount: 2})
+		}
+	}
+	return history
+}
+
+func TestDetectPlateau(t *testing.T) {
+	today := time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC)
+	criteria := DefaultPlateauCriteria()
+
+	tests := []struct {
+		name    string
+		pattern string // 14 lookback days, then 4 plateau days
+		want    bool
+		online  int
+	}{
+		{"grinding without progress", "..x...x....x..oooo", true, 4},
+		{"one quiet plateau day", "xxxxxxxxxxxxxxo.oo", true, 3},
+		{"too few online days", "..x...x....x..o..o", false, 0},
+		{"xp inside plateau", "..x...x....x..ooxo", false, 0},
+		{"not active before", "......x....x..oooo", false, 0},
+		{"active only long ago", "xxxx..............oooo", false, 0},
+		{"no history", "", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := DetectPlateau(grindPattern(tt.pattern, today), today.Add(15*time.Hour), criteria)
+
+			assert.Equal(t, tt.want, ok)
+			if tt.want {
+				assert.Equal(t, today.AddDate(0, 0, -4), p.Since)
+				assert.Equal(t, tt.online, p.OnlineDays)
+			}
+		})
+	}
+}
+
+func TestDetectPlateau_IgnoresToday(t *testing.T) {
+	today := time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC)
+	history := grindPattern("..x...x....x..oooo", today)
+
+	// XP сегодня не отменяет плато: день ещё не закончился
+	history = append(history, &DailyGrind{Date: today, XPGained: 10, SessionsCount: 1})
+
+	_, ok := DetectPlateau(history, today.Add(10*time.Hour), DefaultPlateauCriteria())
+	assert.True(t, ok)
+}
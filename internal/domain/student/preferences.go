@@ -0,0 +1,408 @@
+package student
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PREFERENCES SCHEMA
+// Хранимый формат NotificationPreferences (JSONB students.preferences).
+// Версия 2 - плоский объект с полем "v". Декодер строго проверяет типы и
+// диапазоны и детерминированно чинит то, что можно починить; всё, что
+// починить нельзя, заменяется значением по умолчанию и попадает в отчёт.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// PreferencesSchemaVersion - текущая версия формата настроек.
+const PreferencesSchemaVersion = 2
+
+// PreferencesDecodeStatus - итог разбора хранимых настроек.
+type PreferencesDecodeStatus string
+
+const (
+	// PreferencesCurrent - формат v2 без ошибок.
+	PreferencesCurrent PreferencesDecodeStatus = "current"
+
+	// PreferencesLegacy - формат без версии, но все значения корректны.
+	PreferencesLegacy PreferencesDecodeStatus = "legacy"
+
+	// PreferencesRepaired - часть полей исправлена или сброшена к значениям
+	// по умолчанию, остальные сохранены.
+	PreferencesRepaired PreferencesDecodeStatus = "repaired"
+
+	// PreferencesDefaulted - блоб не читается, все настройки по умолчанию.
+	PreferencesDefaulted PreferencesDecodeStatus = "defaulted"
+)
+
+// PreferencesDecodeReport описывает, что декодер сделал с блобом.
+type PreferencesDecodeReport struct {
+	// Status - итог разбора.
+	Status PreferencesDecodeStatus
+
+	// Issues - найденные проблемы в порядке полей схемы.
+	Issues []string
+}
+
+// Failed возвращает true, если данные были повреждены (что-то исправлено
+// или сброшено).
+func (r PreferencesDecodeReport) Failed() bool {
+	return r.Status == PreferencesRepaired || r.Status == PreferencesDefaulted
+}
+
+// NeedsRewrite возвращает true, если блоб нужно перезаписать в формате v2.
+func (r PreferencesDecodeReport) NeedsRewrite() bool {
+	return r.Status != PreferencesCurrent
+}
+
+// StoredPreferences - хранимый блоб настроек студента (для миграции формата).
+type StoredPreferences struct {
+	// StudentID - ID студента.
+	StudentID string
+
+	// Data - JSON как он лежит в базе.
+	Data []byte
+}
+
+// storedPreferences - формат v2.
+type storedPreferences struct {
+	V                    int               `json:"v"`
+	RankChanges          bool              `json:"rank_changes"`
+	DailyDigest          bool              `json:"daily_digest"`
+	HelpRequests         bool              `json:"help_requests"`
+	InactivityReminders  bool              `json:"inactivity_reminders"`
+	BuddyOnline          bool              `json:"buddy_online"`
+	StuckHelpOffers      bool              `json:"stuck_help_offers"`
+	QuietHoursStart      int               `json:"quiet_hours_start"`
+	QuietHoursEnd        int               `json:"quiet_hours_end"`
+	DigestHour           *int              `json:"digest_hour,omitempty"`
+	Mutes                map[string]string `json:"mutes,omitempty"`
+	HideFromLeaderboard  bool              `json:"hide_from_leaderboard,omitempty"`
+	HideFromHelperSearch bool              `json:"hide_from_helper_search,omitempty"`
+	HideOnlineStatus     bool              `json:"hide_online_status,omitempty"`
+	QuickActions         bool              `json:"quick_actions"`
+	Timezone             string            `json:"timezone,omitempty"`
+}
+
+// EncodePreferences сериализует настройки в формат v2.
+func EncodePreferences(p NotificationPreferences) ([]byte, error) {
+	stored := storedPreferences{
+		V:                    PreferencesSchemaVersion,
+		RankChanges:          p.RankChanges,
+		DailyDigest:          p.DailyDigest,
+		HelpRequests:         p.HelpRequests,
+		InactivityReminders:  p.InactivityReminders,
+		BuddyOnline:          p.BuddyOnline,
+		StuckHelpOffers:      p.StuckHelpOffers,
+		QuietHoursStart:      p.QuietHoursStart,
+		QuietHoursEnd:        p.QuietHoursEnd,
+		DigestHour:           p.DigestHour,
+		HideFromLeaderboard:  p.HideFromLeaderboard,
+		HideFromHelperSearch: p.HideFromHelperSearch,
+		HideOnlineStatus:     p.HideOnlineStatus,
+		QuickActions:         p.QuickActions,
+		Timezone:             p.Timezone,
+	}
+	if len(p.CategoryMutes) > 0 {
+		stored.Mutes = make(map[string]string, len(p.CategoryMutes))
+		for category, until := range p.CategoryMutes {
+			stored.Mutes[string(category)] = until.UTC().Format(time.RFC3339)
+		}
+	}
+	return json.Marshal(stored)
+}
+
+// DecodePreferences разбирает хранимые настройки любой версии.
+//
+// Правила починки:
+//   - bool из строки ("true", "0") или числа 0/1 - преобразуется;
+//   - час из строки с целым числом - преобразуется, вне 0-23 или дробный - сбрасывается;
+//   - неизвестный часовой пояс - сбрасывается к Алматы;
+//   - null и значения других типов - сбрасываются к умолчанию;
+//   - неизвестные ключи и некорректные заглушки категорий - отбрасываются;
+//   - объект, сериализованный в строку или вложенный в единственный ключ, - разворачивается;
+//   - не объект или битый JSON - все настройки по умолчанию.
+func DecodePreferences(data []byte) (NotificationPreferences, PreferencesDecodeReport) {
+	prefs := DefaultNotificationPreferences()
+	d := &preferencesDecoder{}
+
+	m, ok := d.object(data)
+	if !ok {
+		return prefs, PreferencesDecodeReport{Status: PreferencesDefaulted, Issues: d.issues}
+	}
+
+	versioned := d.version(m)
+
+	d.bool(m, "rank_changes", &prefs.RankChanges)
+	d.bool(m, "daily_digest", &prefs.DailyDigest)
+	d.bool(m, "help_requests", &prefs.HelpRequests)
+	d.bool(m, "inactivity_reminders", &prefs.InactivityReminders)
+	d.bool(m, "buddy_online", &prefs.BuddyOnline)
+	d.bool(m, "stuck_help_offers", &prefs.StuckHelpOffers)
+	d.hour(m, "quiet_hours_start", &prefs.QuietHoursStart)
+	d.hour(m, "quiet_hours_end", &prefs.QuietHoursEnd)
+	prefs.DigestHour = d.optionalHour(m, "digest_hour")
+	prefs.CategoryMutes = d.mutes(m)
+	d.bool(m, "hide_from_leaderboard", &prefs.HideFromLeaderboard)
+	d.bool(m, "hide_from_helper_search", &prefs.HideFromHelperSearch)
+	d.bool(m, "hide_online_status", &prefs.HideOnlineStatus)
+	d.bool(m, "quick_actions", &prefs.QuickActions)
+	d.timezone(m, "timezone", &prefs.Timezone)
+
+	unknown := make([]string, 0, len(m))
+	for key := range m {
+		if !slices.Contains(preferenceKeys, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	slices.Sort(unknown)
+	for _, key := range unknown {
+		d.issue("%s: unknown key dropped", key)
+	}
+
+	report := PreferencesDecodeReport{Issues: d.issues}
+	switch {
+	case len(d.issues) > 0:
+		report.Status = PreferencesRepaired
+	case !versioned:
+		report.Status = PreferencesLegacy
+	default:
+		report.Status = PreferencesCurrent
+	}
+	return prefs, report
+}
+
+// preferenceKeys - ключи формата v2.
+var preferenceKeys = []string{
+	"v", "rank_changes", "daily_digest", "help_requests", "inactivity_reminders",
+	"buddy_online", "stuck_help_offers", "quiet_hours_start", "quiet_hours_end", "digest_hour", "mutes",
+	"hide_from_leaderboard", "hide_from_helper_search", "hide_online_status", "quick_actions",
+	"timezone",
+}
+
+// preferencesDecoder собирает проблемы, найденные при разборе.
+type preferencesDecoder struct {
+	issues []string
+}
+
+func (d *preferencesDecoder) issue(format string, args ...any) {
+	d.issues = append(d.issues, fmt.Sprintf(format, args...))
+}
+
+// object разбирает блоб в объект, разворачивая лишнюю вложенность.
+func (d *preferencesDecoder) object(data []byte) (map[string]any, bool) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		d.issue("empty blob")
+		return nil, false
+	}
+
+	v, err := decodeJSON(data)
+	if err != nil {
+		d.issue("invalid JSON: %v", err)
+		return nil, false
+	}
+
+	// Объект, сохранённый как JSON-строка
+	if s, ok := v.(string); ok {
+		if inner, err := decodeJSON([]byte(s)); err == nil {
+			if _, isObject := inner.(map[string]any); isObject {
+				d.issue("object encoded as a string")
+				v = inner
+			}
+		}
+	}
+
+	m, ok := v.(map[string]any)
+	if !ok {
+		d.issue("not an object: %s", jsonKind(v))
+		return nil, false
+	}
+
+	// Объект, вложенный в единственный ключ ({"preferences": {...}})
+	if len(m) == 1 {
+		for key, inner := range m {
+			if nested, ok := inner.(map[string]any); ok && !slices.Contains(preferenceKeys, key) {
+				d.issue("%s: nested object unwrapped", key)
+				m = nested
+			}
+		}
+	}
+
+	return m, true
+}
+
+// version проверяет поле "v"; возвращает true для текущей версии.
+func (d *preferencesDecoder) version(m map[string]any) bool {
+	raw, ok := m["v"]
+	if !ok {
+		return false
+	}
+	n, isNumber := raw.(json.Number)
+	if !isNumber || n.String() != strconv.Itoa(PreferencesSchemaVersion) {
+		d.issue("v: unsupported version %v (%s)", raw, jsonKind(raw))
+		return false
+	}
+	return true
+}
+
+func (d *preferencesDecoder) bool(m map[string]any, key string, dst *bool) {
+	raw, ok := m[key]
+	if !ok {
+		return
+	}
+
+	switch v := raw.(type) {
+	case bool:
+		*dst = v
+		return
+	case string:
+		if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+			*dst = b
+			d.issue("%s: string %q converted to bool", key, v)
+			return
+		}
+	case json.Number:
+		if s := v.String(); s == "0" || s == "1" {
+			*dst = s == "1"
+			d.issue("%s: number %s converted to bool", key, s)
+			return
+		}
+	}
+	d.issue("%s: invalid %s, default used", key, jsonKind(raw))
+}
+
+func (d *preferencesDecoder) hour(m map[string]any, key string, dst *int) {
+	raw, ok := m[key]
+	if !ok {
+		return
+	}
+
+	var text string
+	switch v := raw.(type) {
+	case json.Number:
+		text = v.String()
+	case string:
+		text = strings.TrimSpace(v)
+	default:
+		d.issue("%s: invalid %s, default used", key, jsonKind(raw))
+		return
+	}
+
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil || f != math.Trunc(f) || f < 0 || f > 23 {
+		d.issue("%s: %q is not an hour 0-23, default used", key, text)
+		return
+	}
+	*dst = int(f)
+	if _, isString := raw.(string); isString {
+		d.issue("%s: string %q converted to hour", key, text)
+	}
+}
+
+func (d *preferencesDecoder) timezone(m map[string]any, key string, dst *string) {
+	raw, ok := m[key]
+	if !ok {
+		return
+	}
+	name, isString := raw.(string)
+	if !isString {
+		d.issue("%s: invalid %s, default used", key, jsonKind(raw))
+		return
+	}
+	if ValidateTimezone(name) != nil {
+		d.issue("%s: unknown time zone %q, default used", key, name)
+		return
+	}
+	*dst = name
+}
+
+// optionalHour разбирает необязательный час: отсутствие и null - nil,
+// некорректное значение - nil с записью в отчёт.
+func (d *preferencesDecoder) optionalHour(m map[string]any, key string) *int {
+	if raw, ok := m[key]; !ok || raw == nil {
+		return nil
+	}
+	hour := -1
+	d.hour(m, key, &hour)
+	if hour < 0 {
+		return nil
+	}
+	return &hour
+}
+
+func (d *preferencesDecoder) mutes(m map[string]any) map[MuteCategory]time.Time {
+	raw, ok := m["mutes"]
+	if !ok {
+		return nil
+	}
+	obj, ok := raw.(map[string]any)
+	if !ok {
+		d.issue("mutes: invalid %s, dropped", jsonKind(raw))
+		return nil
+	}
+
+	categories := make([]string, 0, len(obj))
+	for category := range obj {
+		categories = append(categories, category)
+	}
+	slices.Sort(categories)
+
+	var mutes map[MuteCategory]time.Time
+	for _, category := range categories {
+		if !slices.Contains(MuteCategories(), MuteCategory(category)) {
+			d.issue("mutes.%s: unknown category dropped", category)
+			continue
+		}
+		s, _ := obj[category].(string)
+		until, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			d.issue("mutes.%s: invalid time, dropped", category)
+			continue
+		}
+		if mutes == nil {
+			mutes = make(map[MuteCategory]time.Time)
+		}
+		mutes[MuteCategory(category)] = until
+	}
+	return mutes
+}
+
+// decodeJSON разбирает одно JSON-значение, сохраняя числа как json.Number.
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("trailing data after JSON value")
+	}
+	return v, nil
+}
+
+// jsonKind возвращает название JSON-типа значения для отчёта.
+func jsonKind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
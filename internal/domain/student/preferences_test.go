@@ -0,0 +1,182 @@
+package student
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodePreferences_Fixtures(t *testing.T) {
+	defaults := DefaultNotificationPreferences()
+
+	// with возвращает настройки по умолчанию с изменениями
+	with := func(change func(p *NotificationPreferences)) NotificationPreferences {
+		p := DefaultNotificationPreferences()
+		change(&p)
+		return p
+	}
+
+	tests := []struct {
+		name       string
+		blob       string
+		want       NotificationPreferences
+		wantStatus PreferencesDecodeStatus
+		wantIssues []string
+	}{
+		{
+			name:       "current v2",
+			blob:       `{"v":2,"rank_changes":false,"quiet_hours_start":22,"quiet_hours_end":7}`,
+			want:       with(func(p *NotificationPreferences) { p.RankChanges = false; p.QuietHoursStart = 22; p.QuietHoursEnd = 7 }),
+			wantStatus: PreferencesCurrent,
+		},
+		{
+			name:       "personal digest hour",
+			blob:       `{"v":2,"digest_hour":20}`,
+			want:       with(func(p *NotificationPreferences) { h := 20; p.DigestHour = &h }),
+			wantStatus: PreferencesCurrent,
+		},
+		{
+			name:       "digest hour out of range",
+			blob:       `{"v":2,"digest_hour":25}`,
+			want:       defaults,
+			wantStatus: PreferencesRepaired,
+			wantIssues: []string{`digest_hour: "25" is not an hour 0-23, default used`},
+		},
+		{
+			name:       "student timezone",
+			blob:       `{"v":2,"timezone":"Europe/Berlin"}`,
+			want:       with(func(p *NotificationPreferences) { p.Timezone = "Europe/Berlin" }),
+			wantStatus: PreferencesCurrent,
+		},
+		{
+			name:       "unknown timezone",
+			blob:       `{"v":2,"timezone":"Mars/Olympus"}`,
+			want:       defaults,
+			wantStatus: PreferencesRepaired,
+			wantIssues: []string{`timezone: unknown time zone "Mars/Olympus", default used`},
+		},
+		{
+			name:       "legacy without version",
+			blob:       `{"daily_digest":false,"quiet_hours_start":0}`,
+			want:       with(func(p *NotificationPreferences) { p.DailyDigest = false; p.QuietHoursStart = 0 }),
+			wantStatus: PreferencesLegacy,
+		},
+		{
+			name:       "hour stored as string",
+			blob:       `{"quiet_hours_start":"21","quiet_hours_end":"6"}`,
+			want:       with(func(p *NotificationPreferences) { p.QuietHoursStart = 21; p.QuietHoursEnd = 6 }),
+			wantStatus: PreferencesRepaired,
+			wantIssues: []string{
+				`quiet_hours_start: string "21" converted to hour`,
+				`quiet_hours_end: string "6" converted to hour`,
+			},
+		},
+		{
+			name:       "hours out of range or fractional",
+			blob:       `{"v":2,"quiet_hours_start":24,"quiet_hours_end":7.5}`,
+			want:       defaults,
+			wantStatus: PreferencesRepaired,
+			wantIssues: []string{
+				`quiet_hours_start: "24" is not an hour 0-23, default used`,
+				`quiet_hours_end: "7.5" is not an hour 0-23, default used`,
+			},
+		},
+		{
+			name:       "bools of wrong types",
+			blob:       `{"rank_changes":"false","daily_digest":0,"help_requests":null,"buddy_online":{"enabled":false}}`,
+			want:       with(func(p *NotificationPreferences) { p.RankChanges = false; p.DailyDigest = false }),
+			wantStatus: PreferencesRepaired,
+			wantIssues: []string{
+				`rank_changes: string "false" converted to bool`,
+				`daily_digest: number 0 converted to bool`,
+				`help_requests: invalid null, default used`,
+				`buddy_online: invalid object, default used`,
+			},
+		},
+		{
+			name:       "extra nesting",
+			blob:       `{"preferences":{"v":2,"stuck_help_offers":false}}`,
+			want:       with(func(p *NotificationPreferences) { p.StuckHelpOffers = false }),
+			wantStatus: PreferencesRepaired,
+			wantIssues: []string{`preferences: nested object unwrapped`},
+		},
+		{
+			name:       "object encoded as string",
+			blob:       `"{\"inactivity_reminders\":false}"`,
+			want:       with(func(p *NotificationPreferences) { p.InactivityReminders = false }),
+			wantStatus: PreferencesRepaired,
+			wantIssues: []string{`object encoded as a string`},
+		},
+		{
+			name: "bad mutes and unknown keys",
+			blob: `{"v":2,"mutes":{"rank":"2026-05-11T12:00:00Z","digest":"tomorrow","memes":"2026-05-11T12:00:00Z"},"theme":"dark","extra":1}`,
+			want: with(func(p *NotificationPreferences) {
+				p.CategoryMutes = map[MuteCategory]time.Time{MuteCategoryRank: time.Date(2026, time.May, 11, 12, 0, 0, 0, time.UTC)}
+			}),
+			wantStatus: PreferencesRepaired,
+			wantIssues: []string{
+				`mutes.digest: invalid time, dropped`,
+				`mutes.memes: unknown category dropped`,
+				`extra: unknown key dropped`,
+				`theme: unknown key dropped`,
+			},
+		},
+		{
+			name:       "unsupported version",
+			blob:       `{"v":"2"}`,
+			want:       defaults,
+			wantStatus: PreferencesRepaired,
+			wantIssues: []string{`v: unsupported version 2 (string)`},
+		},
+		{
+			name:       "null",
+			blob:       `null`,
+			want:       defaults,
+			wantStatus: PreferencesDefaulted,
+			wantIssues: []string{`not an object: null`},
+		},
+		{
+			name:       "array",
+			blob:       `[true,false]`,
+			want:       defaults,
+			wantStatus: PreferencesDefaulted,
+			wantIssues: []string{`not an object: array`},
+		},
+		{
+			name:       "empty",
+			blob:       ``,
+			want:       defaults,
+			wantStatus: PreferencesDefaulted,
+			wantIssues: []string{`empty blob`},
+		},
+		{
+			name:       "truncated JSON",
+			blob:       `{"rank_changes":tr`,
+			want:       defaults,
+			wantStatus: PreferencesDefaulted,
+			wantIssues: []string{`invalid JSON: unexpected EOF`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, report := DecodePreferences([]byte(tt.blob))
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantStatus, report.Status)
+			assert.Equal(t, tt.wantIssues, report.Issues)
+
+			// Починка детерминирована: повторный разбор даёт тот же отчёт
+			_, again := DecodePreferences([]byte(tt.blob))
+			assert.Equal(t, report, again)
+
+			// Перезаписанный блоб читается как текущий без потерь
+			encoded, err := EncodePreferences(got)
+			require.NoError(t, err)
+			roundTrip, report := DecodePreferences(encoded)
+			assert.Equal(t, PreferencesCurrent, report.Status)
+			assert.Equal(t, got, roundTrip)
+		})
+	}
+}
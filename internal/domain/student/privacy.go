@@ -0,0 +1,68 @@
+package student
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PRIVACY
+// Настройки видимости (/privacy): студент может не показываться в публичном
+// рейтинге, в поиске помощников и не светить онлайн-статус. Сам студент
+// всё про себя видит, кураторы и админка - тоже.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// PrivacySetting - переключатель видимости.
+type PrivacySetting string
+
+const (
+	// PrivacyHideFromLeaderboard - не показывать в публичном рейтинге.
+	PrivacyHideFromLeaderboard PrivacySetting = "hide_from_leaderboard"
+
+	// PrivacyHideFromHelperSearch - не предлагать другим как помощника.
+	PrivacyHideFromHelperSearch PrivacySetting = "hide_from_helper_search"
+
+	// PrivacyHideOnlineStatus - не показывать онлайн-статус.
+	PrivacyHideOnlineStatus PrivacySetting = "hide_online_status"
+)
+
+// PrivacySettings возвращает переключатели в порядке показа.
+func PrivacySettings() []PrivacySetting {
+	return []PrivacySetting{PrivacyHideFromLeaderboard, PrivacyHideFromHelperSearch, PrivacyHideOnlineStatus}
+}
+
+// ParsePrivacySetting разбирает переключатель из данных кнопки.
+func ParsePrivacySetting(s string) (PrivacySetting, bool) {
+	for _, known := range PrivacySettings() {
+		if PrivacySetting(s) == known {
+			return known, true
+		}
+	}
+	return "", false
+}
+
+// Hidden возвращает, включено ли скрытие в настройках.
+func (p NotificationPreferences) Hidden(setting PrivacySetting) bool {
+	switch setting {
+	case PrivacyHideFromLeaderboard:
+		return p.HideFromLeaderboard
+	case PrivacyHideFromHelperSearch:
+		return p.HideFromHelperSearch
+	case PrivacyHideOnlineStatus:
+		return p.HideOnlineStatus
+	default:
+		return false
+	}
+}
+
+// IsOnPublicLeaderboard проверяет, показывать ли студента в публичном рейтинге.
+func (s *Student) IsOnPublicLeaderboard() bool {
+	return !s.Preferences.HideFromLeaderboard
+}
+
+// IsDiscoverableHelper проверяет, можно ли предлагать студента другим как
+// помощника: он готов помогать, не скрылся из поиска и не исключён из
+// подбора после жалоб.
+func (s *Student) IsDiscoverableHelper() bool {
+	return s.CanHelp() && !s.Preferences.HideFromHelperSearch && !s.HelpMatchingDisabled
+}
+
+// ShowsOnlineStatus проверяет, можно ли показывать другим онлайн-статус.
+func (s *Student) ShowsOnlineStatus() bool {
+	return !s.Preferences.HideOnlineStatus
+}
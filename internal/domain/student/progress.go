@@ -4,6 +4,8 @@ import (
 	"errors"
 	"sort"
 	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -192,6 +194,11 @@ func (dg *DailyGrind) RecordSession(minutes int) {
 
 // UpdateRank обновляет текущий ранг и вычисляет изменение.
 func (dg *DailyGrind) UpdateRank(newRank int) {
+	if dg.RankAtStart == 0 {
+		// Позиция на начало дня не зафиксирована (студент появился в
+		// рейтинге в середине дня) - считаем от первой известной
+		dg.RankAtStart = newRank
+	}
 	dg.RankCurrent = newRank
 	dg.RankChange = dg.RankAtStart - newRank // Положительное = поднялся
 }
@@ -223,6 +230,132 @@ func (dg *DailyGrind) Summary() string {
 		string(rune(dg.TasksCompleted)) + " задач" + rankChangeStr
 }
 
+// ─────────────────────────────────────────────────────────────────────────────
+// XP Attribution
+// ─────────────────────────────────────────────────────────────────────────────
+
+// CompletionStamp - факт выполнения задачи с временем из Alem.
+// Используется для распределения прироста XP по дням.
+type CompletionStamp struct {
+	// CompletedAt - время выполнения задачи (нулевое = неизвестно).
+	CompletedAt time.Time
+
+	// XP - XP за задачу.
+	XP XP
+}
+
+// DailyXPDelta - доля прироста XP, отнесённая к конкретному дню.
+type DailyXPDelta struct {
+	// Date - дата (начало дня в UTC).
+	Date time.Time
+
+	// XPDelta - прирост XP за этот день.
+	XPDelta XP
+
+	// TasksDelta - количество новых задач за этот день.
+	TasksDelta int
+}
+
+// XPGain - доля прироста XP, отнесённая к конкретному моменту.
+type XPGain struct {
+	// At - время выполнения задачи или синхронизации.
+	At time.Time
+
+	// XP - прирост XP.
+	XP XP
+
+	// Tasks - количество новых задач (0 или 1).
+	Tasks int
+}
+
+// AttributeXPGains распределяет прирост XP по моментам выполнения задач.
+//
+// Прирост раскладывается по задачам (completions должны содержать только
+// впервые увиденные выполнения). Начиная с самых свежих, каждой задаче
+// отдаётся её XP, но не больше оставшегося прироста: если сумма XP задач
+// превышает delta, более старые задачи уже были учтены прошлой
+// синхронизацией. Остаток (а также отрицательные корректировки и задачи без
+// времени) относится к моменту синхронизации fallback.
+func AttributeXPGains(delta XP, completions []CompletionStamp, fallback time.Time) []XPGain {
+	sorted := make([]CompletionStamp, len(completions))
+	copy(sorted, completions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CompletedAt.After(sorted[j].CompletedAt)
+	})
+
+	gains := make([]XPGain, 0, len(sorted)+1)
+	remaining := delta
+	for _, c := range sorted {
+		at := c.CompletedAt
+		if at.IsZero() {
+			at = fallback
+		}
+
+		share := XP(0)
+		if remaining > 0 && c.XP > 0 {
+			share = c.XP
+			if share > remaining {
+				share = remaining
+			}
+			remaining -= share
+		}
+		gains = append(gains, XPGain{At: at, XP: share, Tasks: 1})
+	}
+
+	if remaining != 0 {
+		gains = append(gains, XPGain{At: fallback, XP: remaining})
+	}
+
+	return gains
+}
+
+// AttributeXPDelta распределяет прирост XP по дням DailyGrind
+// (см. AttributeXPGains). Дни считаются в часовом поясе студента loc.
+//
+// Результат отсортирован по дате и не содержит пустых дней.
+func AttributeXPDelta(delta XP, completions []CompletionStamp, fallback time.Time, loc *time.Location) []DailyXPDelta {
+	byDate := make(map[time.Time]*DailyXPDelta)
+	for _, g := range AttributeXPGains(delta, completions, fallback) {
+		date := LocalDate(g.At, loc)
+		d, ok := byDate[date]
+		if !ok {
+			d = &DailyXPDelta{Date: date}
+			byDate[date] = d
+		}
+		d.XPDelta += g.XP
+		d.TasksDelta += g.Tasks
+	}
+
+	result := make([]DailyXPDelta, 0, len(byDate))
+	for _, d := range byDate {
+		if d.XPDelta == 0 && d.TasksDelta == 0 {
+			continue
+		}
+		result = append(result, *d)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date.Before(result[j].Date)
+	})
+
+	return result
+}
+
+// dateOnlyUTC возвращает начало дня в UTC.
+func dateOnlyUTC(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// LocalDate возвращает календарную дату t в поясе loc (nil - UTC) как
+// полночь UTC: в таком виде хранятся дни серии и дневного прогресса.
+func LocalDate(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		return dateOnlyUTC(t)
+	}
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // STREAK (Серия активных дней)
 // ══════════════════════════════════════════════════════════════════════════════
@@ -243,6 +376,16 @@ type Streak struct {
 
 	// StreakStartDate - дата начала текущей серии.
 	StreakStartDate time.Time
+
+	// MilestoneReached - старшая веха текущей серии, с которой уже
+	// поздравили (0 - ещё ни одной). Сбрасывается вместе с серией.
+	MilestoneReached int
+
+	// clock - источник "сегодня" для IsBroken; nil - системные часы.
+	clock shared.Clock
+
+	// loc - часовой пояс студента, по которому меняются дни; nil - UTC.
+	loc *time.Location
 }
 
 // NewStreak создаёт новый трекер серии.
@@ -258,7 +401,7 @@ func NewStreak(studentID string) *Streak {
 
 // RecordActivity записывает активность и обновляет серию.
 func (s *Streak) RecordActivity(date time.Time) {
-	dateOnly := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	dateOnly := LocalDate(date, s.loc)
 
 	// Если это первая активность
 	if s.LastActiveDate.IsZero() {
@@ -266,23 +409,18 @@ func (s *Streak) RecordActivity(date time.Time) {
 		s.BestStreak = 1
 		s.LastActiveDate = dateOnly
 		s.StreakStartDate = dateOnly
+		s.MilestoneReached = 0
 		return
 	}
 
-	lastDateOnly := time.Date(
-		s.LastActiveDate.Year(),
-		s.LastActiveDate.Month(),
-		s.LastActiveDate.Day(),
-		0, 0, 0, 0, time.UTC,
-	)
-
-	daysDiff := int(dateOnly.Sub(lastDateOnly).Hours() / 24)
+	daysDiff := int(dateOnly.Sub(dateOnlyUTC(s.LastActiveDate)).Hours() / 24)
 
-	switch daysDiff {
-	case 0:
-		// Тот же день - ничего не меняем
+	switch {
+	case daysDiff <= 0:
+		// Тот же день - ничего не меняем. Вчерашний день бывает после
+		// переезда на запад: он уже засчитан
 		return
-	case 1:
+	case daysDiff == 1:
 		// Следующий день - продолжаем серию
 		s.CurrentStreak++
 		if s.CurrentStreak > s.BestStreak {
@@ -292,28 +430,53 @@ func (s *Streak) RecordActivity(date time.Time) {
 		// Пропущены дни - сбрасываем серию
 		s.CurrentStreak = 1
 		s.StreakStartDate = dateOnly
+		s.MilestoneReached = 0
 	}
 
 	s.LastActiveDate = dateOnly
 }
 
+// WithClock задаёт часы, по которым определяется "сегодня".
+func (s *Streak) WithClock(clock shared.Clock) *Streak {
+	s.clock = clock
+	return s
+}
+
+// WithLocation задаёт часовой пояс студента, в полночь которого
+// начинается новый день серии.
+func (s *Streak) WithLocation(loc *time.Location) *Streak {
+	s.loc = loc
+	return s
+}
+
+// Relocate переводит серию в новый часовой пояс, сохраняя число дней с
+// последней активности. Если при смене пояса "сегодня" студента сдвигается,
+// даты серии сдвигаются вместе с ним: переезд на восток не рвёт серию, а на
+// запад не даёт засчитать один день дважды.
+func (s *Streak) Relocate(to *time.Location, now time.Time) {
+	if !s.LastActiveDate.IsZero() {
+		shift := LocalDate(now, to).Sub(LocalDate(now, s.loc))
+		s.LastActiveDate = dateOnlyUTC(s.LastActiveDate).Add(shift)
+		if !s.StreakStartDate.IsZero() {
+			s.StreakStartDate = dateOnlyUTC(s.StreakStartDate).Add(shift)
+		}
+	}
+	s.loc = to
+}
+
+// daysSinceLastActive возвращает, сколько дней (в поясе студента) прошло с
+// последней активности.
+func (s *Streak) daysSinceLastActive() int {
+	today := LocalDate(shared.ClockOrSystem(s.clock).Now(), s.loc)
+	return max(0, int(today.Sub(dateOnlyUTC(s.LastActiveDate)).Hours()/24))
+}
+
 // IsBroken проверяет, сломана ли серия (пропущен вчерашний день).
 func (s *Streak) IsBroken() bool {
 	if s.LastActiveDate.IsZero() {
 		return false
 	}
-
-	today := time.Now().UTC()
-	todayOnly := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
-	lastOnly := time.Date(
-		s.LastActiveDate.Year(),
-		s.LastActiveDate.Month(),
-		s.LastActiveDate.Day(),
-		0, 0, 0, 0, time.UTC,
-	)
-
-	daysDiff := int(todayOnly.Sub(lastOnly).Hours() / 24)
-	return daysDiff > 1
+	return s.daysSinceLastActive() > 1
 }
 
 // DaysUntilStreakBreaks возвращает количество дней до сброса серии.
@@ -323,18 +486,7 @@ func (s *Streak) DaysUntilStreakBreaks() int {
 		return 0
 	}
 
-	today := time.Now().UTC()
-	todayOnly := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
-	lastOnly := time.Date(
-		s.LastActiveDate.Year(),
-		s.LastActiveDate.Month(),
-		s.LastActiveDate.Day(),
-		0, 0, 0, 0, time.UTC,
-	)
-
-	daysDiff := int(todayOnly.Sub(lastOnly).Hours() / 24)
-
-	switch daysDiff {
+	switch s.daysSinceLastActive() {
 	case 0:
 		return 2 // Был активен сегодня, есть завтра целый день
 	case 1:
@@ -362,6 +514,10 @@ const (
 	AchievementTop10 AchievementType = "top_10"
 	// AchievementTop50 - вошёл в топ-50.
 	AchievementTop50 AchievementType = "top_50"
+	// AchievementCohortTop10 - вошёл в топ-10 своего потока.
+	AchievementCohortTop10 AchievementType = "cohort_top_10"
+	// AchievementCohortTop3 - вошёл в топ-3 своего потока.
+	AchievementCohortTop3 AchievementType = "cohort_top_3"
 	// AchievementHelper5 - помог 5 студентам.
 	AchievementHelper5 AchievementType = "helper_5"
 	// AchievementHelper20 - помог 20 студентам.
@@ -376,6 +532,14 @@ const (
 	AchievementEarlyBird AchievementType = "early_bird"
 	// AchievementComebackKid - вернулся после 7 дней неактивности.
 	AchievementComebackKid AchievementType = "comeback_kid"
+	// AchievementGoalGetter - достигал недельной цели 4 недели подряд.
+	AchievementGoalGetter AchievementType = "goal_getter"
+	// AchievementConsistentHelper - помогал 4 недели подряд.
+	AchievementConsistentHelper AchievementType = "consistent_helper_4"
+	// AchievementConsistentHelperLong - помогал 12 недель подряд.
+	AchievementConsistentHelperLong AchievementType = "consistent_helper_12"
+	// AchievementEventParticipant - набрал очки в челлендже сообщества.
+	AchievementEventParticipant AchievementType = "event_participant"
 )
 
 // Achievement представляет полученное достижение.
@@ -407,6 +571,8 @@ func GetAchievementDefinitions() []AchievementDefinition {
 		{AchievementStreak30, "Железная воля", "30 дней подряд", "💪", 500},
 		{AchievementTop10, "Элита", "Вошёл в топ-10", "🏆", 200},
 		{AchievementTop50, "Восходящая звезда", "Вошёл в топ-50", "⭐", 100},
+		{AchievementCohortTop10, "Гордость потока", "Вошёл в топ-10 своего потока", "🎖", 75},
+		{AchievementCohortTop3, "Лидер потока", "Вошёл в топ-3 своего потока", "🥇", 150},
 		{AchievementHelper5, "Добрый самаритянин", "Помог 5 студентам", "🤝", 150},
 		{AchievementHelper20, "Наставник", "Помог 20 студентам", "🎓", 400},
 		{AchievementLevel5, "Подмастерье", "Достиг 5 уровня", "📚", 100},
@@ -414,9 +580,33 @@ func GetAchievementDefinitions() []AchievementDefinition {
 		{AchievementNightOwl, "Ночная сова", "Активность после полуночи", "🦉", 25},
 		{AchievementEarlyBird, "Ранняя пташка", "Активность до 7 утра", "🐦", 25},
 		{AchievementComebackKid, "Вернулся!", "Вернулся после недели", "🔄", 75},
+		{AchievementGoalGetter, "Goal Getter", "Достигал недельной цели 4 недели подряд", "🎯", 200},
+		{AchievementConsistentHelper, "Consistent Helper", "Помогал другим 4 недели подряд", "🫶", 150},
+		{AchievementConsistentHelperLong, "Consistent Helper: сезон", "Помогал другим 12 недель подряд", "💞", 400},
+		{AchievementEventParticipant, "Участник челленджа", "Набрал очки в челлендже сообщества", "🏁", 50},
 	}
 }
 
+// RankAchievementStep - ступень рейтинговой лестницы достижений.
+type RankAchievementStep struct {
+	Type    AchievementType
+	MaxRank int
+}
+
+// GlobalRankLadder - достижения за место в общем рейтинге (от простого к сложному).
+var GlobalRankLadder = []RankAchievementStep{
+	{AchievementTop50, 50},
+	{AchievementTop10, 10},
+}
+
+// CohortRankLadder - достижения за место в рейтинге своего потока.
+// Новые потоки соревнуются с ветеранами в общем рейтинге почти без шансов,
+// а в своём потоке топ достижим для каждого.
+var CohortRankLadder = []RankAchievementStep{
+	{AchievementCohortTop10, 10},
+	{AchievementCohortTop3, 3},
+}
+
 // GetAchievementDefinition возвращает определение достижения по типу.
 func GetAchievementDefinition(t AchievementType) (AchievementDefinition, bool) {
 	for _, def := range GetAchievementDefinitions() {
@@ -427,6 +617,18 @@ func GetAchievementDefinition(t AchievementType) (AchievementDefinition, bool) {
 	return AchievementDefinition{}, false
 }
 
+// IsPublic возвращает, можно ли объявлять о достижении в ленте сообщества.
+// Достижения о режиме дня и о возвращении после перерыва - личное дело
+// студента.
+func (t AchievementType) IsPublic() bool {
+	switch t {
+	case AchievementNightOwl, AchievementEarlyBird, AchievementComebackKid:
+		return false
+	default:
+		return true
+	}
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // PROGRESS CALCULATIONS
 // ══════════════════════════════════════════════════════════════════════════════
@@ -552,10 +754,13 @@ func NewAchievementChecker() *AchievementChecker {
 var ErrAchievementAlreadyUnlocked = errors.New("achievement already unlocked")
 
 // CheckNewAchievements проверяет и возвращает список новых достижений.
+// rank - место в общем рейтинге, cohortRank - место в рейтинге потока
+// (0 - неизвестно).
 func (ac *AchievementChecker) CheckNewAchievements(
 	student *Student,
 	streak *Streak,
 	rank int,
+	cohortRank int,
 	existingAchievements []Achievement,
 ) []Achievement {
 	existing := make(map[AchievementType]bool)
@@ -598,21 +803,9 @@ func (ac *AchievementChecker) CheckNewAchievements(
 		}
 	}
 
-	// Проверка ранга
-	if rank > 0 && rank <= 10 && !existing[AchievementTop10] {
-		newAchievements = append(newAchievements, Achievement{
-			Type:       AchievementTop10,
-			UnlockedAt: now,
-			Metadata:   map[string]interface{}{"rank": rank},
-		})
-	}
-	if rank > 0 && rank <= 50 && !existing[AchievementTop50] {
-		newAchievements = append(newAchievements, Achievement{
-			Type:       AchievementTop50,
-			UnlockedAt: now,
-			Metadata:   map[string]interface{}{"rank": rank},
-		})
-	}
+	// Проверка ранга: общий рейтинг и рейтинг потока
+	newAchievements = append(newAchievements, ac.checkRankLadder(GlobalRankLadder, rank, existing, now)...)
+	newAchievements = append(newAchievements, ac.checkRankLadder(CohortRankLadder, cohortRank, existing, now)...)
 
 	// Проверка помощи
 	if student.HelpCount >= 5 && !existing[AchievementHelper5] {
@@ -648,6 +841,30 @@ func (ac *AchievementChecker) CheckNewAchievements(
 	return newAchievements
 }
 
+// checkRankLadder возвращает ещё не полученные ступени лестницы для ранга.
+func (ac *AchievementChecker) checkRankLadder(
+	ladder []RankAchievementStep,
+	rank int,
+	existing map[AchievementType]bool,
+	now time.Time,
+) []Achievement {
+	if rank <= 0 {
+		return nil
+	}
+
+	var unlocked []Achievement
+	for _, step := range ladder {
+		if rank <= step.MaxRank && !existing[step.Type] {
+			unlocked = append(unlocked, Achievement{
+				Type:       step.Type,
+				UnlockedAt: now,
+				Metadata:   map[string]interface{}{"rank": rank},
+			})
+		}
+	}
+	return unlocked
+}
+
 // CheckComebackKid проверяет достижение "Вернулся после недели".
 func (ac *AchievementChecker) CheckComebackKid(
 	lastSeen time.Time,
@@ -691,3 +908,27 @@ func (ac *AchievementChecker) CheckFirstTask(
 
 	return nil
 }
+
+// CheckEventParticipant проверяет достижение "Участник челленджа":
+// студент набрал очки в завершившемся событии.
+func (ac *AchievementChecker) CheckEventParticipant(
+	eventID string,
+	eventScore int,
+	existingAchievements []Achievement,
+) *Achievement {
+	for _, a := range existingAchievements {
+		if a.Type == AchievementEventParticipant {
+			return nil // Уже есть
+		}
+	}
+
+	if eventScore > 0 {
+		return &Achievement{
+			Type:       AchievementEventParticipant,
+			UnlockedAt: time.Now().UTC(),
+			Metadata:   map[string]interface{}{"event_id": eventID, "score": eventScore},
+		}
+	}
+
+	return nil
+}
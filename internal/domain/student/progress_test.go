@@ -0,0 +1,140 @@
+package student
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+func TestDailyGrind_UpdateRank(t *testing.T) {
+	grind := &DailyGrind{RankAtStart: 10}
+	grind.UpdateRank(7)
+	assert.Equal(t, 7, grind.RankCurrent)
+	assert.Equal(t, 3, grind.RankChange)
+
+	// Студент появился в рейтинге в середине дня: движение считается от первой позиции
+	midDay := &DailyGrind{}
+	midDay.UpdateRank(42)
+	assert.Equal(t, 42, midDay.RankAtStart)
+	assert.Zero(t, midDay.RankChange)
+
+	midDay.UpdateRank(45)
+	assert.Equal(t, -3, midDay.RankChange)
+}
+
+func TestStreak_DayBoundaries(t *testing.T) {
+	clock := shared.NewFakeClock(time.Date(2026, time.May, 11, 23, 59, 59, 0, time.UTC))
+	streak := NewStreak("dana").WithClock(clock)
+
+	streak.RecordActivity(clock.Now())
+	assert.Equal(t, 2, streak.DaysUntilStreakBreaks())
+
+	// Полночь - уже следующий день: серия ещё жива, но нужна активность сегодня
+	clock.Advance(time.Second)
+	assert.False(t, streak.IsBroken())
+	assert.Equal(t, 1, streak.DaysUntilStreakBreaks())
+
+	streak.RecordActivity(clock.Now())
+	assert.Equal(t, 2, streak.CurrentStreak)
+
+	// Последняя секунда следующего дня - ещё не сломана
+	clock.Set(time.Date(2026, time.May, 13, 23, 59, 59, 0, time.UTC))
+	assert.False(t, streak.IsBroken())
+
+	// Пропущен целый день
+	clock.Advance(time.Second)
+	assert.True(t, streak.IsBroken())
+	assert.Zero(t, streak.DaysUntilStreakBreaks())
+
+	streak.RecordActivity(clock.Now())
+	assert.Equal(t, 1, streak.CurrentStreak)
+	assert.Equal(t, 2, streak.BestStreak)
+}
+
+func TestStreak_LocalMidnightUTCPlusOne(t *testing.T) {
+	// Лагос - UTC+1 без перехода на летнее время: полночь там - 23:00 UTC
+	lagos, err := time.LoadLocation("Africa/Lagos")
+	assert.NoError(t, err)
+
+	clock := shared.NewFakeClock(time.Date(2026, time.May, 11, 22, 59, 59, 0, time.UTC))
+	streak := NewStreak("dana").WithClock(clock).WithLocation(lagos)
+
+	streak.RecordActivity(clock.Now())
+	assert.Equal(t, time.Date(2026, time.May, 11, 0, 0, 0, 0, time.UTC), streak.LastActiveDate)
+
+	// 23:00 UTC - в UTC ещё 11 мая, у студента уже 12-е
+	clock.Advance(time.Second)
+	assert.Equal(t, 1, streak.DaysUntilStreakBreaks())
+
+	streak.RecordActivity(clock.Now())
+	assert.Equal(t, 2, streak.CurrentStreak)
+	assert.Equal(t, time.Date(2026, time.May, 12, 0, 0, 0, 0, time.UTC), streak.LastActiveDate)
+
+	// Полночь UTC 13 мая - у студента ещё 12-е: тот же день
+	clock.Set(time.Date(2026, time.May, 12, 22, 59, 59, 0, time.UTC))
+	streak.RecordActivity(clock.Now())
+	assert.Equal(t, 2, streak.CurrentStreak)
+
+	// Пропущено 13 мая по времени студента: в 23:00 UTC 13-го серия сломана,
+	// хотя в UTC 13-е ещё не кончилось
+	clock.Set(time.Date(2026, time.May, 13, 22, 59, 59, 0, time.UTC))
+	assert.False(t, streak.IsBroken())
+	clock.Advance(time.Second)
+	assert.True(t, streak.IsBroken())
+}
+
+func TestStreak_Relocate(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(t, err)
+	london, err := time.LoadLocation("Europe/London")
+	assert.NoError(t, err)
+	almaty := time.FixedZone("Asia/Almaty", 5*60*60)
+
+	t.Run("east: today moves forward, the streak survives", func(t *testing.T) {
+		// 23:00 в Алматы 17 октября, активность была 16-го: нужна сегодня.
+		// В Токио уже 18-е - без переноса пропущенным оказалось бы 17-е
+		now := time.Date(2026, time.October, 17, 23, 0, 0, 0, almaty)
+		clock := shared.NewFakeClock(now)
+		streak := NewStreak("dana").WithClock(clock).WithLocation(almaty)
+		streak.RecordActivity(now.AddDate(0, 0, -2))
+		streak.RecordActivity(now.AddDate(0, 0, -1))
+		assert.Equal(t, 1, streak.DaysUntilStreakBreaks())
+
+		streak.Relocate(tokyo, now)
+		assert.False(t, streak.IsBroken())
+		assert.Equal(t, 1, streak.DaysUntilStreakBreaks())
+
+		streak.RecordActivity(now)
+		assert.Equal(t, 3, streak.CurrentStreak)
+	})
+
+	t.Run("west: today moves back, the day counts once", func(t *testing.T) {
+		// 01:00 в Алматы 18 октября, активность уже была. В Лондоне ещё 17-е
+		now := time.Date(2026, time.October, 18, 1, 0, 0, 0, almaty)
+		clock := shared.NewFakeClock(now)
+		streak := NewStreak("dana").WithClock(clock).WithLocation(almaty)
+		streak.RecordActivity(now.AddDate(0, 0, -1))
+		streak.RecordActivity(now)
+		assert.Equal(t, 2, streak.CurrentStreak)
+
+		streak.Relocate(london, now)
+		assert.Equal(t, 2, streak.DaysUntilStreakBreaks(), "today is already counted")
+
+		clock.Advance(2 * time.Hour)
+		streak.RecordActivity(clock.Now())
+		assert.Equal(t, 2, streak.CurrentStreak)
+	})
+
+	t.Run("no relocation: yesterday after moving west is not a reset", func(t *testing.T) {
+		now := time.Date(2026, time.October, 18, 1, 0, 0, 0, almaty)
+		streak := NewStreak("dana").WithLocation(almaty)
+		streak.RecordActivity(now.AddDate(0, 0, -1))
+		streak.RecordActivity(now)
+
+		streak.WithLocation(london).RecordActivity(now.Add(time.Hour))
+		assert.Equal(t, 2, streak.CurrentStreak)
+	})
+}
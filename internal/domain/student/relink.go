@@ -0,0 +1,38 @@
+package student
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RELINK (перенос профиля на новый Telegram аккаунт)
+// Студент сменил номер и вошёл в Telegram с нового аккаунта: профиль
+// переезжает на новый telegram_id, старый освобождается.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// RelinkCooldown - как часто можно переносить профиль одного студента.
+// Защищает от перебрасывания профиля между аккаунтами и от параллельных
+// попыток, которые пришли не одновременно, а друг за другом.
+const RelinkCooldown = 10 * time.Minute
+
+var (
+	// ErrRelinkConflict - Telegram ID студента изменился, пока шёл перенос
+	// (параллельная попытка успела раньше).
+	ErrRelinkConflict = errors.New("student: telegram account was relinked concurrently")
+
+	// ErrTelegramIDInUse - новый Telegram ID уже привязан к другому студенту.
+	ErrTelegramIDInUse = errors.New("student: telegram id is already linked to another student")
+)
+
+// TelegramRelinker переносит профиль студента на другой Telegram аккаунт.
+type TelegramRelinker interface {
+	// RelinkTelegramID меняет Telegram ID студента с from на to и пишет
+	// запись аудита в той же транзакции. Замена условная: если у студента
+	// уже не from, возвращается ErrRelinkConflict, поэтому из двух
+	// одновременных попыток проходит только одна.
+	RelinkTelegramID(ctx context.Context, studentID string, from, to TelegramID, audit shared.AuditEntry) error
+}
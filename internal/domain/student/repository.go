@@ -3,6 +3,8 @@ package student
 import (
 	"context"
 	"time"
+
+	"github.com/alem-hub/alem-community-hub/pkg/pagination"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -33,10 +35,19 @@ type Repository interface {
 	// Returns ErrStudentNotFound, if student not found.
 	GetByEmail(ctx context.Context, email string) (*Student, error)
 
+	// GetByAlemLogin возвращает студента по логину Alem.
+	// Возвращает ErrStudentNotFound, если студент не найден.
+	GetByAlemLogin(ctx context.Context, login string) (*Student, error)
+
 	// Update обновляет данные студента.
 	// Возвращает ErrStudentNotFound, если студент не найден.
 	Update(ctx context.Context, student *Student) error
 
+	// SaveMutes сохраняет заглушки уведомлений студента: MutedUntil и
+	// Preferences.CategoryMutes. Остальные поля не меняются.
+	// Возвращает ErrStudentNotFound, если студент не найден.
+	SaveMutes(ctx context.Context, student *Student) error
+
 	// Delete удаляет студента (soft delete).
 	// Возвращает ErrStudentNotFound, если студент не найден.
 	Delete(ctx context.Context, id string) error
@@ -63,6 +74,10 @@ type Repository interface {
 	// CountByCohort возвращает количество студентов в когорте.
 	CountByCohort(ctx context.Context, cohort Cohort) (int, error)
 
+	// CreateBatch создаёт несколько студентов одной транзакцией.
+	// Используется для массовой загрузки (сид-данные, импорт).
+	CreateBatch(ctx context.Context, students []*Student) error
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// Search & Filter
 	// ─────────────────────────────────────────────────────────────────────────
@@ -79,6 +94,10 @@ type Repository interface {
 	// FindByXPRange находит студентов в указанном диапазоне XP.
 	FindByXPRange(ctx context.Context, minXP, maxXP XP) ([]*Student, error)
 
+	// FindHelpersInCohort находит до limit студентов когорты с XP не ниже
+	// minXP, доступных в поиске помощников, по убыванию XP.
+	FindHelpersInCohort(ctx context.Context, cohort Cohort, minXP XP, limit int) ([]*Student, error)
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// Existence Checks
 	// ─────────────────────────────────────────────────────────────────────────
@@ -93,13 +112,16 @@ type Repository interface {
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 }
 
+// ListBounds - лимиты страницы списка студентов по умолчанию.
+var ListBounds = pagination.Bounds{DefaultLimit: 50, MaxLimit: 500}
+
 // ListOptions содержит параметры для пагинации и сортировки.
 type ListOptions struct {
-	// Offset - смещение (для пагинации).
-	Offset int
+	// Page - смещение и размер страницы.
+	pagination.Page
 
-	// Limit - максимальное количество записей.
-	Limit int
+	// Bounds - лимиты страницы для конкретного вызова (нулевые = ListBounds).
+	Bounds pagination.Bounds
 
 	// SortBy - поле для сортировки.
 	SortBy string
@@ -114,8 +136,8 @@ type ListOptions struct {
 // DefaultListOptions возвращает параметры по умолчанию.
 func DefaultListOptions() ListOptions {
 	return ListOptions{
-		Offset:          0,
-		Limit:           50,
+		Page:            pagination.Page{Limit: ListBounds.DefaultLimit},
+		Bounds:          ListBounds,
 		SortBy:          "current_xp",
 		SortDesc:        true,
 		IncludeInactive: false,
@@ -141,6 +163,21 @@ func (o ListOptions) WithSort(field string, desc bool) ListOptions {
 	return o
 }
 
+// WithBounds задаёт лимиты страницы для вызова (например, для фоновых
+// задач, которым нужен весь список).
+func (o ListOptions) WithBounds(bounds pagination.Bounds) ListOptions {
+	o.Bounds = bounds
+	return o
+}
+
+// Normalized возвращает страницу, ограниченную лимитами вызова.
+func (o ListOptions) Normalized() pagination.Page {
+	if o.Bounds == (pagination.Bounds{}) {
+		return o.Page.Normalize(ListBounds)
+	}
+	return o.Page.Normalize(o.Bounds)
+}
+
 // WithInactive включает неактивных студентов.
 func (o ListOptions) WithInactive() ListOptions {
 	o.IncludeInactive = true
@@ -160,12 +197,26 @@ type ProgressRepository interface {
 	// SaveXPChange сохраняет изменение XP.
 	SaveXPChange(ctx context.Context, entry XPHistoryEntry) error
 
+	// SaveXPChangeForStudent сохраняет изменение XP указанного студента.
+	SaveXPChangeForStudent(ctx context.Context, studentID string, entry XPHistoryEntry) error
+
+	// HasXPChangeReason проверяет, есть ли в истории XP студента запись
+	// с указанной причиной (например, бонус за достижение).
+	HasXPChangeReason(ctx context.Context, studentID string, reason string) (bool, error)
+
 	// GetXPHistory возвращает историю XP студента.
 	GetXPHistory(ctx context.Context, studentID string, from, to time.Time) ([]XPHistoryEntry, error)
 
 	// GetRecentXPChanges возвращает последние N изменений XP.
 	GetRecentXPChanges(ctx context.Context, studentID string, limit int) ([]XPHistoryEntry, error)
 
+	// GetXPChangeTotal возвращает изменение XP за период [from, to) с учётом
+	// помесячных итогов свёрнутой истории (см. SumXPChanges).
+	GetXPChangeTotal(ctx context.Context, studentID string, from, to time.Time) (XP, error)
+
+	// SaveXPChangesBatch сохраняет пачку изменений XP разных студентов.
+	SaveXPChangesBatch(ctx context.Context, changes []StudentXPChange) error
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// Daily Grind
 	// ─────────────────────────────────────────────────────────────────────────
@@ -182,6 +233,40 @@ type ProgressRepository interface {
 	// GetTodayDailyGrind возвращает прогресс за сегодня.
 	GetTodayDailyGrind(ctx context.Context, studentID string) (*DailyGrind, error)
 
+	// UpsertDailyGrindDelta атомарно прибавляет XP и задачи к дневному прогрессу
+	// за указанную дату, создавая запись при необходимости.
+	UpsertDailyGrindDelta(ctx context.Context, studentID string, date time.Time, xpDelta XP, tasksDelta int) error
+
+	// RecordDailySession увеличивает счётчик заходов в дневном прогрессе
+	// за дату at, создавая запись при необходимости.
+	RecordDailySession(ctx context.Context, studentID string, at time.Time) error
+
+	// RecordDailySessionMinutes засчитывает сессию длительностью minutes
+	// (например, фокус-сессию) в дневной прогресс за дату at.
+	RecordDailySessionMinutes(ctx context.Context, studentID string, at time.Time, minutes int) error
+
+	// GetPlateauCandidates возвращает студентов, которые заходили в период
+	// [from, to), но не получили в нём XP. Это лишь грубый отбор:
+	// окончательно застревание определяет DetectPlateau.
+	GetPlateauCandidates(ctx context.Context, from, to time.Time) ([]string, error)
+
+	// SaveDailyGrindsBatch сохраняет или обновляет пачку дневных прогрессов.
+	SaveDailyGrindsBatch(ctx context.Context, grinds []*DailyGrind) error
+
+	// GetXPGainedBetween возвращает сумму XP из дневного прогресса студента
+	// за даты [from, to).
+	GetXPGainedBetween(ctx context.Context, studentID string, from, to time.Time) (XP, error)
+
+	// StartDailyRanks фиксирует позицию студентов на начало дня date,
+	// создавая дневной прогресс при необходимости. Уже зафиксированная
+	// позиция на начало дня не перезаписывается.
+	StartDailyRanks(ctx context.Context, date time.Time, entries []StudentRank) error
+
+	// BulkUpdateTodayRanks обновляет текущую позицию и изменение за день
+	// одним запросом для студентов, у которых есть дневной прогресс за date.
+	// Появившимся в середине дня позиция на начало дня ставится равной текущей.
+	BulkUpdateTodayRanks(ctx context.Context, date time.Time, entries []StudentRank) error
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// Streaks
 	// ─────────────────────────────────────────────────────────────────────────
@@ -195,6 +280,9 @@ type ProgressRepository interface {
 	// GetTopStreaks возвращает топ студентов по текущей серии.
 	GetTopStreaks(ctx context.Context, limit int) ([]*Streak, error)
 
+	// SaveStreaksBatch сохраняет или обновляет пачку серий.
+	SaveStreaksBatch(ctx context.Context, streaks []*Streak) error
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// Achievements
 	// ─────────────────────────────────────────────────────────────────────────
@@ -202,6 +290,13 @@ type ProgressRepository interface {
 	// SaveAchievement сохраняет разблокированное достижение.
 	SaveAchievement(ctx context.Context, studentID string, achievement Achievement) error
 
+	// GrantAchievementWithBonus в одной транзакции сохраняет достижение,
+	// записывает бонус в историю XP с причиной reason и атомарно прибавляет
+	// его к XP студента. Бонус, уже записанный с этой причиной, повторно не
+	// начисляется, поэтому вызов можно повторять. Возвращает, был ли начислен
+	// бонус, и XP студента после операции.
+	GrantAchievementWithBonus(ctx context.Context, studentID string, achievement Achievement, bonus XP, reason string) (awarded bool, currentXP XP, err error)
+
 	// GetAchievements возвращает все достижения студента.
 	GetAchievements(ctx context.Context, studentID string) ([]Achievement, error)
 
@@ -218,6 +313,18 @@ type StudentAchievement struct {
 	Achievement Achievement
 }
 
+// StudentXPChange связывает студента с изменением XP (для пакетной записи).
+type StudentXPChange struct {
+	StudentID string
+	Entry     XPHistoryEntry
+}
+
+// StudentRank - позиция студента в общем рейтинге (для пакетной записи).
+type StudentRank struct {
+	StudentID string
+	Rank      int
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // ONLINE TRACKER
 // Отслеживает онлайн-статус студентов (обычно реализуется через Redis).
@@ -274,6 +381,43 @@ type SyncRepository interface {
 
 	// SaveSyncError сохраняет ошибку синхронизации.
 	SaveSyncError(ctx context.Context, err SyncError) error
+
+	// StartSyncRun сохраняет начало прогона синхронизации и возвращает его ID.
+	StartSyncRun(ctx context.Context, startedAt time.Time) (int64, error)
+
+	// FinishSyncRun сохраняет итог прогона: статус, счётчики и ошибку.
+	FinishSyncRun(ctx context.Context, run SyncRun) error
+}
+
+// SyncRunStatus - состояние прогона синхронизации.
+type SyncRunStatus string
+
+const (
+	// SyncRunRunning - прогон идёт (или процесс упал, не завершив его).
+	SyncRunRunning SyncRunStatus = "running"
+	// SyncRunCompleted - прогон завершён.
+	SyncRunCompleted SyncRunStatus = "completed"
+	// SyncRunFailed - прогон прерван или упал больше половины студентов.
+	SyncRunFailed SyncRunStatus = "failed"
+)
+
+// SyncRun - один прогон пакетной синхронизации. Прерванный прогон не
+// требует восстановления: студенты, до которых он не дошёл, остаются с
+// устаревшим last_synced_at и попадают в следующий.
+type SyncRun struct {
+	ID         int64
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Status     SyncRunStatus
+
+	// Total, Synced, Failed - сколько студентов было к синхронизации,
+	// сколько синхронизировано и сколько с ошибкой.
+	Total  int
+	Synced int
+	Failed int
+
+	// Error - причина неудачи прогона.
+	Error string
 }
 
 // SyncError представляет ошибку синхронизации.
@@ -323,6 +467,157 @@ type StudentCache interface {
 	InvalidateAll(ctx context.Context) error
 }
 
+// ══════════════════════════════════════════════════════════════════════════════
+// DISPLAY NAMES
+// Спискам других доменов (благодарности, связи, рейтинг помощников) нужны
+// только имена студентов. Вместо JOIN со students они получают имена пачкой
+// через DisplayNameResolver.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// DisplayNameCache хранит отображаемые имена студентов.
+type DisplayNameCache interface {
+	// GetNames возвращает имена найденных в кеше студентов.
+	// Отсутствующие ID в результат не попадают.
+	GetNames(ctx context.Context, ids []string) (map[string]string, error)
+
+	// SetNames сохраняет имена (ID -> имя).
+	SetNames(ctx context.Context, names map[string]string) error
+
+	// DeleteNames удаляет имена из кеша.
+	DeleteNames(ctx context.Context, ids ...string) error
+}
+
+// DisplayNameResolver возвращает отображаемые имена студентов по ID.
+type DisplayNameResolver interface {
+	// Resolve возвращает имена для ids (ID -> имя).
+	// Неизвестные студенты в результат не попадают.
+	Resolve(ctx context.Context, ids []string) map[string]string
+
+	// Invalidate удаляет имена из кеша, например после удаления студента.
+	Invalidate(ctx context.Context, ids ...string) error
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// WEEKLY GOAL REPOSITORY
+// ══════════════════════════════════════════════════════════════════════════════
+
+// WeeklyGoalRepository хранит недельные цели студентов.
+type WeeklyGoalRepository interface {
+	// Get возвращает цель студента на неделю.
+	// Возвращает ErrGoalNotFound, если цели нет.
+	Get(ctx context.Context, studentID string, week ISOWeek) (*WeeklyGoal, error)
+
+	// Save создаёт или обновляет цель. Достигнутую цель не перезаписывает
+	// и возвращает ErrGoalAlreadyReached.
+	Save(ctx context.Context, goal *WeeklyGoal) error
+
+	// GetPending возвращает недостигнутые цели недели.
+	GetPending(ctx context.Context, week ISOWeek) ([]*WeeklyGoal, error)
+
+	// MarkReached отмечает цель достигнутой. Возвращает true только
+	// для вызова, который отметил её первым.
+	MarkReached(ctx context.Context, studentID string, week ISOWeek, at time.Time) (bool, error)
+
+	// GetRecent возвращает цели студента за последние weeks недель,
+	// от новых к старым.
+	GetRecent(ctx context.Context, studentID string, weeks int) ([]*WeeklyGoal, error)
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HELPER STREAK REPOSITORY
+// ══════════════════════════════════════════════════════════════════════════════
+
+// HelperStreakRepository хранит серии недель помощи.
+type HelperStreakRepository interface {
+	// Get возвращает серию студента или nil, если её нет.
+	Get(ctx context.Context, studentID string) (*HelperStreak, error)
+
+	// GetActive возвращает незакрытые серии (CurrentWeeks > 0).
+	GetActive(ctx context.Context) ([]*HelperStreak, error)
+
+	// Save создаёт или обновляет серию.
+	Save(ctx context.Context, streak *HelperStreak) error
+
+	// LastEvaluatedWeek возвращает последнюю учтённую неделю
+	// ("" - подсчёт ещё не запускался).
+	LastEvaluatedWeek(ctx context.Context) (ISOWeek, error)
+}
+
+// HelperActivityRepository читает помощь студентов из запросов помощи
+// и благодарностей.
+type HelperActivityRepository interface {
+	// GetHelpingStudents возвращает студентов, которые в [from, to) закрыли
+	// запрос помощи как помощник или получили благодарность.
+	GetHelpingStudents(ctx context.Context, from, to time.Time) ([]string, error)
+
+	// CountEndorsementsBetween возвращает число благодарностей,
+	// полученных в [from, to), по студентам.
+	CountEndorsementsBetween(ctx context.Context, from, to time.Time) (map[string]int, error)
+}
+
+// HelperBoardEntry - строка рейтинга помощников месяца.
+type HelperBoardEntry struct {
+	StudentID    string
+	Endorsements int
+}
+
+// HelperBoard - рейтинг помощников месяца по числу благодарностей.
+// Месяц - календарный месяц по времени Алматы.
+type HelperBoard interface {
+	// ReplaceMonth заменяет рейтинг месяца, в который попадает month.
+	ReplaceMonth(ctx context.Context, month time.Time, counts map[string]int) error
+
+	// TopOfMonth возвращает первых limit помощников месяца.
+	TopOfMonth(ctx context.Context, month time.Time, limit int) ([]HelperBoardEntry, error)
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HELP MATCHING REPOSITORY
+// ══════════════════════════════════════════════════════════════════════════════
+
+// HelpMatchingRepository исключает студента из подбора помощников и
+// возвращает обратно (см. Student.HelpMatchingDisabled).
+type HelpMatchingRepository interface {
+	// SetHelpMatchingDisabled меняет флаг. Возвращает ErrStudentNotFound,
+	// если студента нет.
+	SetHelpMatchingDisabled(ctx context.Context, studentID string, disabled bool) error
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MERGE REPOSITORY
+// Операции слияния дубликатов. Используется только внутри транзакции.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// MergeRepository переносит данные одного аккаунта студента на другой.
+type MergeRepository interface {
+	// GetAllDailyGrinds возвращает весь ежедневный прогресс студента.
+	GetAllDailyGrinds(ctx context.Context, studentID string) ([]*DailyGrind, error)
+
+	// ReplaceDailyGrinds удаляет прогресс fromID и записывает grinds,
+	// перезаписывая совпадающие дни.
+	ReplaceDailyGrinds(ctx context.Context, fromID string, grinds []*DailyGrind) error
+
+	// GetStreak возвращает серию студента или nil, если её нет.
+	GetStreak(ctx context.Context, studentID string) (*Streak, error)
+
+	// ReplaceStreak удаляет серию fromID и записывает streak как есть.
+	ReplaceStreak(ctx context.Context, fromID string, streak *Streak) error
+
+	// MoveXPHistory переносит историю XP. Возвращает число строк.
+	MoveXPHistory(ctx context.Context, fromID, intoID string) (int64, error)
+
+	// MoveAchievements объединяет достижения: переносит те, которых нет у intoID,
+	// остальные удаляет. Возвращает число перенесённых.
+	MoveAchievements(ctx context.Context, fromID, intoID string) (int64, error)
+
+	// SetTelegramID меняет Telegram ID студента.
+	SetTelegramID(ctx context.Context, studentID string, telegramID TelegramID) error
+
+	// MarkMerged помечает дубликат покинувшим программу (soft delete).
+	// Его Telegram ID освобождается, чтобы его мог занять оставшийся аккаунт.
+	MarkMerged(ctx context.Context, studentID string) error
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // UNIT OF WORK (для транзакций)
 // ══════════════════════════════════════════════════════════════════════════════
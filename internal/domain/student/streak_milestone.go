@@ -0,0 +1,32 @@
+package student
+
+// ══════════════════════════════════════════════════════════════════════════════
+// STREAK MILESTONES (Вехи серии)
+// Круглые даты серии активных дней отмечаются отдельным поздравлением
+// с карточкой, которой можно поделиться. Каждая веха празднуется один раз
+// за серию: после сброса серии вехи снова становятся доступны.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// StreakMilestones - длины серии (в днях), которые празднуются.
+var StreakMilestones = []int{7, 14, 30, 60, 100}
+
+// ReachMilestone отмечает веху, которую серия пересекла после записи
+// активности: previous - длина серии до RecordActivity. Если за один шаг
+// пересечено несколько вех, возвращается старшая. Веха, с которой уже
+// поздравили в этой серии, повторно не возвращается.
+func (s *Streak) ReachMilestone(previous int) (int, bool) {
+	for i := len(StreakMilestones) - 1; i >= 0; i-- {
+		milestone := StreakMilestones[i]
+		if previous >= milestone || s.CurrentStreak < milestone {
+			continue
+		}
+		if milestone <= s.MilestoneReached {
+			return 0, false
+		}
+
+		s.MilestoneReached = milestone
+		return milestone, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,61 @@
+package student
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordDay записывает активность и возвращает веху, если она достигнута.
+func recordDay(s *Streak, day time.Time) (int, bool) {
+	previous := s.CurrentStreak
+	s.RecordActivity(day)
+	return s.ReachMilestone(previous)
+}
+
+func TestStreak_ReachMilestone_OncePerThreshold(t *testing.T) {
+	start := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+	s := NewStreak("s1")
+
+	var reached []int
+	for i := 0; i < 14; i++ {
+		day := start.AddDate(0, 0, i)
+		if m, ok := recordDay(s, day); ok {
+			reached = append(reached, m)
+		}
+		// Повторная активность в тот же день не празднуется снова
+		_, ok := recordDay(s, day.Add(time.Hour))
+		assert.False(t, ok, "day %d repeat", i+1)
+	}
+	assert.Equal(t, []int{7, 14}, reached)
+	assert.Equal(t, 14, s.MilestoneReached)
+
+	// После пропуска серия начинается заново, и веха 7 снова доступна
+	restart := start.AddDate(0, 0, 20)
+	for i := 0; i < 6; i++ {
+		_, ok := recordDay(s, restart.AddDate(0, 0, i))
+		assert.False(t, ok)
+	}
+	assert.Equal(t, 0, s.MilestoneReached)
+
+	m, ok := recordDay(s, restart.AddDate(0, 0, 6))
+	assert.True(t, ok)
+	assert.Equal(t, 7, m)
+}
+
+func TestStreak_ReachMilestone_JumpAndMigratedStreak(t *testing.T) {
+	// За один шаг пересечено несколько вех - празднуется старшая
+	s := &Streak{CurrentStreak: 31}
+	m, ok := s.ReachMilestone(5)
+	assert.True(t, ok)
+	assert.Equal(t, 30, m)
+
+	_, ok = s.ReachMilestone(5)
+	assert.False(t, ok, "уже отмечена")
+
+	// Длинная серия без отметок не получает запоздалое поздравление
+	s = &Streak{CurrentStreak: 46}
+	_, ok = s.ReachMilestone(45)
+	assert.False(t, ok)
+}
@@ -0,0 +1,80 @@
+package student
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// TelegramID представляет уникальный идентификатор пользователя Telegram.
+//
+// В логах ID маскируется (LogValue): обычные логи не должны содержать
+// полные идентификаторы пользователей. Полный ID виден только при
+// включённом SetRevealTelegramIDs (APP_DEBUG).
+type TelegramID int64
+
+// MaxTelegramID - наибольший ID пользователя: по документации Bot API в нём
+// не больше 52 значащих бит.
+const MaxTelegramID TelegramID = 1<<52 - 1
+
+// IsValid проверяет, что TelegramID положительный и не больше MaxTelegramID.
+func (t TelegramID) IsValid() bool {
+	return t > 0 && t <= MaxTelegramID
+}
+
+// NewTelegramID создаёт TelegramID с проверкой.
+func NewTelegramID(id int64) (TelegramID, error) {
+	t := TelegramID(id)
+	if !t.IsValid() {
+		return 0, fmt.Errorf("%w: %d", ErrInvalidTelegramID, id)
+	}
+	return t, nil
+}
+
+// ParseTelegramID разбирает TelegramID из строки (аргументы команд, входящие
+// запросы), чтобы мусор отсекался на границе, а не в репозитории.
+func ParseTelegramID(s string) (TelegramID, error) {
+	id, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidTelegramID, s)
+	}
+	return NewTelegramID(id)
+}
+
+// revealTelegramIDs - показывать ли в логах полные ID.
+var revealTelegramIDs atomic.Bool
+
+// SetRevealTelegramIDs включает полные ID в логах (только для отладки).
+func SetRevealTelegramIDs(reveal bool) {
+	revealTelegramIDs.Store(reveal)
+}
+
+// String возвращает полный ID.
+func (t TelegramID) String() string {
+	return strconv.FormatInt(int64(t), 10)
+}
+
+// Masked возвращает ID со скрытыми средними цифрами: 123456789 -> "12*****89".
+// У коротких ID скрываются все цифры, кроме последней.
+func (t TelegramID) Masked() string {
+	digits := strconv.FormatInt(int64(t), 10)
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+
+	if len(digits) <= 4 {
+		return sign + strings.Repeat("*", len(digits)-1) + digits[len(digits)-1:]
+	}
+	return sign + digits[:2] + strings.Repeat("*", len(digits)-4) + digits[len(digits)-2:]
+}
+
+// LogValue реализует slog.LogValuer: в логах ID маскируется.
+func (t TelegramID) LogValue() slog.Value {
+	if revealTelegramIDs.Load() {
+		return slog.Int64Value(int64(t))
+	}
+	return slog.StringValue(t.Masked())
+}
@@ -0,0 +1,58 @@
+package student
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTelegramID(t *testing.T) {
+	id, err := ParseTelegramID(" 123456789 ")
+	require.NoError(t, err)
+	assert.Equal(t, TelegramID(123456789), id)
+
+	id, err = ParseTelegramID("4503599627370495") // 2^52 - 1
+	require.NoError(t, err)
+	assert.Equal(t, MaxTelegramID, id)
+
+	for _, bad := range []string{"", "0", "-42", "abc", "12.5", "4503599627370496", "99999999999999999999"} {
+		_, err := ParseTelegramID(bad)
+		assert.ErrorIs(t, err, ErrInvalidTelegramID, bad)
+	}
+}
+
+func TestTelegramID_MaskedInLogs(t *testing.T) {
+	assert.Equal(t, "12*****89", TelegramID(123456789).Masked())
+	assert.Equal(t, "10******01", TelegramID(1000000001).Masked())
+	assert.Equal(t, "***7", TelegramID(1237).Masked())
+	assert.Equal(t, "-10*********90", TelegramID(-1001234567890).Masked())
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf, nil))
+	log.Info("update", "telegram_id", TelegramID(123456789))
+	assert.Contains(t, buf.String(), "telegram_id=12*****89")
+	assert.NotContains(t, buf.String(), "123456789")
+
+	// Debug builds show the full ID
+	SetRevealTelegramIDs(true)
+	defer SetRevealTelegramIDs(false)
+	buf.Reset()
+	log.Info("update", "telegram_id", TelegramID(123456789))
+	assert.Contains(t, buf.String(), "telegram_id=123456789")
+}
+
+func TestNewStudent_RejectsInvalidTelegramID(t *testing.T) {
+	for _, id := range []TelegramID{0, -5, MaxTelegramID + 1} {
+		_, err := NewStudent(NewStudentParams{
+			ID:          "student-1",
+			TelegramID:  id,
+			Email:       "a@alem.school",
+			DisplayName: "Айдана",
+			Cohort:      "2025-01",
+		})
+		assert.ErrorIs(t, err, ErrInvalidTelegramID)
+	}
+}
@@ -0,0 +1,230 @@
+package student
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// XP FLAGS (Проверка подозрительного XP)
+// Иногда студенты находят способ накрутить XP, и это демотивирует остальных.
+// День, в который прирост резко выбивается из обычного темпа студента,
+// отправляется кураторам на проверку. XP при этом не снимается: до решения
+// куратора студент остаётся в рейтинге с пометкой.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// XPSpikeCriteria описывает, какой прирост за день считать всплеском.
+type XPSpikeCriteria struct {
+	// WindowDays - за сколько предыдущих дней считается обычный темп.
+	WindowDays int
+
+	// K - на сколько стандартных отклонений день должен превышать среднее.
+	K float64
+
+	// MinXP - абсолютный порог: меньший прирост не проверяется,
+	// каким бы необычным он ни был.
+	MinXP XP
+}
+
+// DefaultXPSpikeCriteria возвращает критерии по умолчанию: окно 14 дней,
+// среднее + 3σ и не меньше 2000 XP за день.
+func DefaultXPSpikeCriteria() XPSpikeCriteria {
+	return XPSpikeCriteria{
+		WindowDays: 14,
+		K:          3,
+		MinXP:      2000,
+	}
+}
+
+// HistoryDays возвращает, за сколько дней нужна история для проверки дня
+// (окно плюс сам день).
+func (c XPSpikeCriteria) HistoryDays() int {
+	return c.WindowDays + 1
+}
+
+// XPSpike - найденный всплеск.
+type XPSpike struct {
+	// Date - день всплеска (начало дня в UTC).
+	Date time.Time
+
+	// Amount - XP за этот день.
+	Amount XP
+
+	// Mean и StdDev - среднее и стандартное отклонение XP за день в окне.
+	Mean   float64
+	StdDev float64
+
+	// ZScore - на сколько σ день выше среднего.
+	ZScore float64
+}
+
+// Excess возвращает XP сверх обычного дня (Amount - Mean).
+func (s XPSpike) Excess() XP {
+	excess := XP(math.Round(float64(s.Amount) - s.Mean))
+	if excess < 0 {
+		return 0
+	}
+	return excess
+}
+
+// DetectXPSpike проверяет, является ли день day всплеском по сравнению с
+// WindowDays предыдущими днями. Дни окна без записи считаются днями без XP.
+// Всплеск - XP за день больше mean + K·σ и не меньше MinXP. При σ = 0
+// (например, студент ничего не набирал) достаточно абсолютного порога;
+// z-оценка тогда считается с σ = 1, чтобы оставаться конечной.
+// Порядок history не важен.
+func DetectXPSpike(history []*DailyGrind, day time.Time, c XPSpikeCriteria) (*XPSpike, bool) {
+	if c.WindowDays <= 0 {
+		return nil, false
+	}
+
+	byDay := make(map[time.Time]XP, len(history))
+	for _, g := range history {
+		if g != nil {
+			byDay[dayOf(g.Date)] += g.XPGained
+		}
+	}
+
+	day = dayOf(day)
+	amount := byDay[day]
+	if amount <= 0 || amount < c.MinXP {
+		return nil, false
+	}
+
+	var sum float64
+	values := make([]float64, 0, c.WindowDays)
+	for d := day.AddDate(0, 0, -c.WindowDays); d.Before(day); d = d.AddDate(0, 0, 1) {
+		v := float64(byDay[d])
+		values = append(values, v)
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(values)))
+
+	if float64(amount) <= mean+c.K*stddev {
+		return nil, false
+	}
+
+	return &XPSpike{
+		Date:   day,
+		Amount: amount,
+		Mean:   mean,
+		StdDev: stddev,
+		ZScore: (float64(amount) - mean) / math.Max(stddev, 1),
+	}, true
+}
+
+// IsXPImport возвращает true, если прирост - первичная загрузка истории,
+// а не заработанный XP: до синхронизации у студента не было XP или
+// в истории нет ни одного дня раньше day. При первой синхронизации
+// выполненные ранее задачи раскладываются по своим датам разом, и такие
+// дни выглядели бы всплесками.
+func IsXPImport(oldXP XP, history []*DailyGrind, day time.Time) bool {
+	if oldXP <= 0 {
+		return true
+	}
+
+	day = dayOf(day)
+	for _, g := range history {
+		if g != nil && dayOf(g.Date).Before(day) {
+			return false
+		}
+	}
+	return true
+}
+
+// XPFlagStatus - статус проверки всплеска.
+type XPFlagStatus string
+
+const (
+	// XPFlagPending - ждёт решения куратора.
+	XPFlagPending XPFlagStatus = "pending"
+
+	// XPFlagApproved - куратор подтвердил, что XP заработан честно.
+	XPFlagApproved XPFlagStatus = "approved"
+
+	// XPFlagDismissed - куратор признал накрутку, XP скорректирован.
+	XPFlagDismissed XPFlagStatus = "dismissed"
+)
+
+// XPAdjustmentReason - причина корректировки в истории XP после накрутки.
+const XPAdjustmentReason = "xp_flag_adjustment"
+
+// Ошибки проверки всплесков.
+var (
+	// ErrXPFlagNotFound - флаг не найден.
+	ErrXPFlagNotFound = errors.New("xp flag not found")
+
+	// ErrXPFlagResolved - по флагу уже принято решение.
+	ErrXPFlagResolved = errors.New("xp flag already resolved")
+)
+
+// XPFlag - запись на проверку подозрительного дня.
+type XPFlag struct {
+	ID        int64
+	StudentID string
+
+	// Date - день всплеска (UTC, 00:00).
+	Date time.Time
+
+	// Amount - XP за день на момент последней проверки.
+	Amount XP
+
+	// ZScore - на сколько σ день выше обычного.
+	ZScore float64
+
+	// Excess - XP сверх обычного дня; столько снимается при накрутке.
+	Excess XP
+
+	Status    XPFlagStatus
+	CreatedAt time.Time
+
+	// ResolvedAt и ResolvedBy (Telegram ID куратора) заполняются решением.
+	ResolvedAt *time.Time
+	ResolvedBy int64
+}
+
+// NewXPFlag создаёт флаг на проверку для найденного всплеска.
+func NewXPFlag(studentID string, spike XPSpike, now time.Time) *XPFlag {
+	return &XPFlag{
+		StudentID: studentID,
+		Date:      spike.Date,
+		Amount:    spike.Amount,
+		ZScore:    spike.ZScore,
+		Excess:    spike.Excess(),
+		Status:    XPFlagPending,
+		CreatedAt: now,
+	}
+}
+
+// IsPending возвращает true, если решение ещё не принято.
+func (f *XPFlag) IsPending() bool {
+	return f.Status == XPFlagPending
+}
+
+// XPFlagRepository хранит флаги подозрительного XP.
+type XPFlagRepository interface {
+	// Flag сохраняет флаг. На один день студента - один флаг: если он ещё
+	// ждёт решения, обновляются сумма и оценка, решённый не меняется.
+	// created = true, если флаг появился впервые (только тогда кураторы
+	// получают уведомление).
+	Flag(ctx context.Context, flag *XPFlag) (created bool, err error)
+
+	// GetByID возвращает флаг или ErrXPFlagNotFound.
+	GetByID(ctx context.Context, id int64) (*XPFlag, error)
+
+	// Resolve записывает решение по флагу, если он ещё ждёт решения.
+	// Иначе возвращает ErrXPFlagResolved. Для XPFlagDismissed в той же
+	// транзакции в историю XP пишется корректировка на -Excess.
+	Resolve(ctx context.Context, id int64, status XPFlagStatus, curatorID int64, at time.Time) (*XPFlag, error)
+
+	// PendingStudentIDs возвращает студентов с флагами, ждущими решения.
+	PendingStudentIDs(ctx context.Context) (map[string]bool, error)
+}
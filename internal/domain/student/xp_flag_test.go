@@ -0,0 +1,101 @@
+package student
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// xpDays builds a daily history ending on day (inclusive), one value per day.
+func xpDays(day time.Time, xp ...XP) []*DailyGrind {
+	history := make([]*DailyGrind, len(xp))
+	for i, v := range xp {
+		history[i] = &DailyGrind{Date: day.AddDate(0, 0, i-len(xp)+1), XPGained: v}
+	}
+	return history
+}
+
+func TestDetectXPSpike(t *testing.T) {
+	day := time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)
+	criteria := XPSpikeCriteria{WindowDays: 14, K: 3, MinXP: 1000}
+
+	// 14 days alternating 200/400: mean 300, σ 100, threshold 600
+	steady := []XP{200, 400, 200, 400, 200, 400, 200, 400, 200, 400, 200, 400, 200, 400}
+
+	t.Run("spike above threshold and floor", func(t *testing.T) {
+		spike, ok := DetectXPSpike(xpDays(day, append(steady, 1500)...), day.Add(18*time.Hour), criteria)
+		require.True(t, ok)
+		assert.Equal(t, day, spike.Date)
+		assert.Equal(t, XP(1500), spike.Amount)
+		assert.InDelta(t, 300, spike.Mean, 1e-9)
+		assert.InDelta(t, 100, spike.StdDev, 1e-9)
+		assert.InDelta(t, 12, spike.ZScore, 1e-9)
+		assert.Equal(t, XP(1200), spike.Excess())
+	})
+
+	t.Run("above threshold but under floor", func(t *testing.T) {
+		_, ok := DetectXPSpike(xpDays(day, append(steady, 900)...), day, criteria)
+		assert.False(t, ok)
+	})
+
+	t.Run("above floor but within k sigma", func(t *testing.T) {
+		busy := make([]XP, 14)
+		for i := range busy {
+			busy[i] = 1000 + XP(i%2)*1000 // mean 1500, σ 500, threshold 3000
+		}
+		_, ok := DetectXPSpike(xpDays(day, append(busy, 3000)...), day, criteria)
+		assert.False(t, ok, "exactly mean + kσ is not a spike")
+
+		_, ok = DetectXPSpike(xpDays(day, append(busy, 3001)...), day, criteria)
+		assert.True(t, ok)
+	})
+
+	t.Run("missing days count as zero", func(t *testing.T) {
+		// One 700 XP day in the window: mean 50, σ ≈ 180.3, threshold ≈ 591
+		history := []*DailyGrind{
+			{Date: day.AddDate(0, 0, -3), XPGained: 700},
+			{Date: day, XPGained: 1000},
+		}
+		spike, ok := DetectXPSpike(history, day, criteria)
+		require.True(t, ok)
+		assert.InDelta(t, 50, spike.Mean, 1e-9)
+		assert.InDelta(t, 5.27, spike.ZScore, 0.01)
+	})
+
+	t.Run("flat history uses unit sigma", func(t *testing.T) {
+		spike, ok := DetectXPSpike(xpDays(day, 1200), day, criteria)
+		require.True(t, ok)
+		assert.Zero(t, spike.StdDev)
+		assert.InDelta(t, 1200, spike.ZScore, 1e-9)
+	})
+
+	t.Run("days outside the window are ignored", func(t *testing.T) {
+		history := xpDays(day, append(steady, 1500)...)
+		history = append(history, &DailyGrind{Date: day.AddDate(0, 0, -20), XPGained: 50000})
+		_, ok := DetectXPSpike(history, day, criteria)
+		assert.True(t, ok)
+	})
+
+	t.Run("no xp that day", func(t *testing.T) {
+		_, ok := DetectXPSpike(xpDays(day, steady...), day, criteria)
+		assert.False(t, ok)
+	})
+}
+
+func TestIsXPImport(t *testing.T) {
+	day := time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)
+
+	// First sync: nothing synced before, so backfilled days are exempt
+	assert.True(t, IsXPImport(0, xpDays(day, 5000, 0, 8000), day))
+	assert.True(t, IsXPImport(1200, xpDays(day, 8000), day))
+	assert.True(t, IsXPImport(1200, nil, day))
+
+	// A student with earlier synced days is not exempt
+	assert.False(t, IsXPImport(1200, xpDays(day, 300, 8000), day))
+
+	// Rows after the checked day do not count as earlier history
+	history := []*DailyGrind{{Date: day, XPGained: 8000}, {Date: day.AddDate(0, 0, 1), XPGained: 100}}
+	assert.True(t, IsXPImport(1200, history, day))
+}
@@ -0,0 +1,122 @@
+package student
+
+import (
+	"sort"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// XP HISTORY ROLLUP
+// Старые записи xp_history сворачиваются в помесячные итоги по причинам,
+// после чего сырые строки удаляются. Месяц студента считается свёрнутым,
+// как только для него есть хотя бы один итог: дальше источник правды -
+// итоги, а оставшиеся сырые строки этого месяца не учитываются. Поэтому
+// суммы за период одинаковы до удаления сырых строк и после него.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// XPMonthlyTotal - итог изменений XP студента за месяц по одной причине.
+type XPMonthlyTotal struct {
+	// Month - первое число месяца (UTC).
+	Month time.Time
+
+	// Reason - причина изменения, как в XPHistoryEntry.
+	Reason string
+
+	// Delta - суммарное изменение XP.
+	Delta XP
+
+	// Entries - сколько сырых записей свёрнуто.
+	Entries int
+}
+
+// XPMonth возвращает начало месяца (UTC), к которому относится момент t.
+func XPMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// rolledMonths возвращает множество уже свёрнутых месяцев.
+func rolledMonths(rolled []XPMonthlyTotal) map[time.Time]bool {
+	months := make(map[time.Time]bool, len(rolled))
+	for _, t := range rolled {
+		months[XPMonth(t.Month)] = true
+	}
+	return months
+}
+
+// RollupXPHistory сворачивает записи полных месяцев до before, для которых
+// ещё нет итогов. Возвращает только новые итоги, по месяцам и причинам.
+func RollupXPHistory(entries []XPHistoryEntry, rolled []XPMonthlyTotal, before time.Time) []XPMonthlyTotal {
+	done := rolledMonths(rolled)
+	cutoff := XPMonth(before)
+
+	type key struct {
+		month  time.Time
+		reason string
+	}
+	totals := make(map[key]*XPMonthlyTotal)
+	for _, e := range entries {
+		month := XPMonth(e.Timestamp)
+		if !month.Before(cutoff) || done[month] {
+			continue
+		}
+		k := key{month, e.Reason}
+		t, ok := totals[k]
+		if !ok {
+			t = &XPMonthlyTotal{Month: month, Reason: e.Reason}
+			totals[k] = t
+		}
+		t.Delta += e.Delta
+		t.Entries++
+	}
+
+	result := make([]XPMonthlyTotal, 0, len(totals))
+	for _, t := range totals {
+		result = append(result, *t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Month.Equal(result[j].Month) {
+			return result[i].Month.Before(result[j].Month)
+		}
+		return result[i].Reason < result[j].Reason
+	})
+	return result
+}
+
+// PruneXPHistory возвращает записи, которые остаются после удаления:
+// удаляются только записи до before, чей месяц уже свёрнут.
+func PruneXPHistory(entries []XPHistoryEntry, rolled []XPMonthlyTotal, before time.Time) []XPHistoryEntry {
+	done := rolledMonths(rolled)
+
+	kept := make([]XPHistoryEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.Timestamp.Before(before) && done[XPMonth(e.Timestamp)] {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	return kept
+}
+
+// SumXPChanges возвращает изменение XP за период [from, to).
+// Свёрнутые месяцы учитываются целиком, если их начало попадает в период,
+// поэтому по старой истории точность - месяц; сырые записи свёрнутых
+// месяцев пропускаются.
+func SumXPChanges(entries []XPHistoryEntry, rolled []XPMonthlyTotal, from, to time.Time) XP {
+	done := rolledMonths(rolled)
+
+	var sum XP
+	for _, e := range entries {
+		if e.Timestamp.Before(from) || !e.Timestamp.Before(to) || done[XPMonth(e.Timestamp)] {
+			continue
+		}
+		sum += e.Delta
+	}
+	for _, t := range rolled {
+		if t.Month.Before(from) || !t.Month.Before(to) {
+			continue
+		}
+		sum += t.Delta
+	}
+	return sum
+}
@@ -0,0 +1,104 @@
+package student
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// xpTrail builds a history with a few changes every day from start to end.
+func xpTrail(start, end time.Time) []XPHistoryEntry {
+	var entries []XPHistoryEntry
+	reasons := []string{"task_completed", "bonus", "correction"}
+	for day, i := start, 0; day.Before(end); day, i = day.AddDate(0, 0, 1), i+1 {
+		entries = append(entries, XPHistoryEntry{
+			Timestamp: day.Add(time.Duration(i%20) * time.Hour),
+			Delta:     XP(10 + i%7*15),
+			Reason:    reasons[i%len(reasons)],
+		})
+		if i%5 == 0 {
+			entries = append(entries, XPHistoryEntry{
+				Timestamp: day.Add(23*time.Hour + 59*time.Minute),
+				Delta:     -5,
+				Reason:    "correction",
+			})
+		}
+	}
+	return entries
+}
+
+// xpPeriods sums every month and every week between from and to.
+func xpPeriods(entries []XPHistoryEntry, rolled []XPMonthlyTotal, from, to, weeksFrom time.Time) (months, weeks []XP) {
+	for m := XPMonth(from); m.Before(to); m = m.AddDate(0, 1, 0) {
+		months = append(months, SumXPChanges(entries, rolled, m, m.AddDate(0, 1, 0)))
+	}
+	for w := weeksFrom; w.Before(to); w = w.AddDate(0, 0, 7) {
+		weeks = append(weeks, SumXPChanges(entries, rolled, w, w.AddDate(0, 0, 7)))
+	}
+	return months, weeks
+}
+
+func TestXPRollup_PruningKeepsTotals(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	start := now.AddDate(0, -15, 0)
+	history := xpTrail(start, now)
+
+	// Raw history is kept for 12 months; weeks are asked for that window only
+	cutoff := XPMonth(now).AddDate(0, -12, 0)
+	weeksFrom := cutoff.AddDate(0, 0, 3)
+
+	wantMonths, wantWeeks := xpPeriods(history, nil, start, now, weeksFrom)
+	wantTotal := SumXPChanges(history, nil, XPMonth(start), now)
+
+	// The rollup alone changes nothing
+	rolled := RollupXPHistory(history, nil, cutoff)
+	require.NotEmpty(t, rolled)
+	for _, r := range rolled {
+		assert.True(t, r.Month.Before(cutoff))
+	}
+	months, weeks := xpPeriods(history, rolled, start, now, weeksFrom)
+	assert.Equal(t, wantMonths, months)
+	assert.Equal(t, wantWeeks, weeks)
+
+	// Neither does deleting the rolled-up rows
+	pruned := PruneXPHistory(history, rolled, cutoff)
+	assert.Less(t, len(pruned), len(history))
+	for _, e := range pruned {
+		assert.False(t, e.Timestamp.Before(cutoff))
+	}
+	months, weeks = xpPeriods(pruned, rolled, start, now, weeksFrom)
+	assert.Equal(t, wantMonths, months)
+	assert.Equal(t, wantWeeks, weeks)
+	assert.Equal(t, wantTotal, SumXPChanges(pruned, rolled, XPMonth(start), now))
+
+	// A second run finds nothing new to roll up or delete
+	assert.Empty(t, RollupXPHistory(pruned, rolled, cutoff))
+	assert.Equal(t, pruned, PruneXPHistory(pruned, rolled, cutoff))
+}
+
+func TestXPRollup_KeepsRowsUntilRolledUp(t *testing.T) {
+	cutoff := time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC)
+	history := []XPHistoryEntry{
+		{Timestamp: cutoff.AddDate(0, -2, 3), Delta: 100, Reason: "task_completed"},
+		{Timestamp: cutoff.AddDate(0, -1, 4), Delta: 40, Reason: "task_completed"},
+		{Timestamp: cutoff.AddDate(0, -1, 5), Delta: 60, Reason: "task_completed"},
+		{Timestamp: cutoff.AddDate(0, -1, 5), Delta: 25, Reason: "bonus"},
+	}
+
+	// Only September is rolled up so far: October rows stay
+	rolled := RollupXPHistory(history[:1], nil, cutoff)
+	assert.Len(t, PruneXPHistory(history, rolled, cutoff), 3)
+
+	// The next run rolls up October per reason and skips September
+	next := RollupXPHistory(history, rolled, cutoff)
+	assert.Equal(t, []XPMonthlyTotal{
+		{Month: cutoff.AddDate(0, -1, 0), Reason: "bonus", Delta: 25, Entries: 1},
+		{Month: cutoff.AddDate(0, -1, 0), Reason: "task_completed", Delta: 100, Entries: 2},
+	}, next)
+
+	rolled = append(rolled, next...)
+	assert.Empty(t, PruneXPHistory(history, rolled, cutoff))
+	assert.Equal(t, XP(225), SumXPChanges(nil, rolled, cutoff.AddDate(-1, 0, 0), cutoff))
+}
@@ -0,0 +1,91 @@
+package student
+
+import (
+	"fmt"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// COHORT XP SERIES
+// Прогресс потока во времени для графиков: сумма XP и число активных
+// студентов по дням или ISO-неделям. Дни - календарные даты дневного
+// прогресса (время Алматы), поэтому точки хранятся как полночь UTC этой даты.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// XPSeriesBucket - шаг временного ряда.
+type XPSeriesBucket string
+
+const (
+	XPSeriesDay  XPSeriesBucket = "day"
+	XPSeriesWeek XPSeriesBucket = "week"
+)
+
+// IsValid проверяет, что шаг известен.
+func (b XPSeriesBucket) IsValid() bool {
+	return b == XPSeriesDay || b == XPSeriesWeek
+}
+
+// Start возвращает начало шага, в который попадает дата: сам день или
+// понедельник его ISO-недели.
+func (b XPSeriesBucket) Start(date time.Time) time.Time {
+	day := SeriesDate(date)
+	if b != XPSeriesWeek {
+		return day
+	}
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// Next возвращает начало следующего шага.
+func (b XPSeriesBucket) Next(start time.Time) time.Time {
+	if b == XPSeriesWeek {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// XPSeriesPoint - значение ряда за один шаг.
+type XPSeriesPoint struct {
+	// Date - начало шага (полночь UTC календарной даты).
+	Date time.Time
+
+	// TotalXP - сумма XP, полученного студентами потока за шаг.
+	TotalXP XP
+
+	// ActiveStudents - сколько разных студентов потока были активны за шаг.
+	ActiveStudents int
+}
+
+// SeriesDate отбрасывает время и зону: остаётся календарная дата t в её
+// собственной зоне как полночь UTC.
+func SeriesDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// FillXPSeries возвращает по точке на каждый шаг от from до to включительно:
+// шаги без данных заполняются нулями, чтобы графики не соединяли линией
+// соседние точки через пропуск. points уже сгруппированы по шагу (так их
+// отдаёт хранилище); точки вне диапазона отбрасываются.
+func FillXPSeries(points []XPSeriesPoint, from, to time.Time, bucket XPSeriesBucket) ([]XPSeriesPoint, error) {
+	if !bucket.IsValid() {
+		return nil, fmt.Errorf("unknown series bucket %q", bucket)
+	}
+
+	first, last := bucket.Start(from), bucket.Start(to)
+	if last.Before(first) {
+		return nil, fmt.Errorf("series range ends before it starts")
+	}
+
+	byStart := make(map[time.Time]XPSeriesPoint, len(points))
+	for _, p := range points {
+		byStart[bucket.Start(p.Date)] = p
+	}
+
+	var filled []XPSeriesPoint
+	for start := first; !start.After(last); start = bucket.Next(start) {
+		p := byStart[start]
+		p.Date = start
+		filled = append(filled, p)
+	}
+	return filled, nil
+}
@@ -0,0 +1,73 @@
+package student
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seriesDay(month time.Month, day int) time.Time {
+	return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestFillXPSeries_DaysFillsGapsWithZeros(t *testing.T) {
+	points := []XPSeriesPoint{
+		{Date: seriesDay(time.March, 3), TotalXP: 500, ActiveStudents: 4},
+		{Date: seriesDay(time.March, 6), TotalXP: 120, ActiveStudents: 1},
+		{Date: seriesDay(time.March, 9), TotalXP: 999, ActiveStudents: 9}, // вне диапазона
+	}
+
+	got, err := FillXPSeries(points, seriesDay(time.March, 2), seriesDay(time.March, 7), XPSeriesDay)
+	require.NoError(t, err)
+
+	assert.Equal(t, []XPSeriesPoint{
+		{Date: seriesDay(time.March, 2)},
+		{Date: seriesDay(time.March, 3), TotalXP: 500, ActiveStudents: 4},
+		{Date: seriesDay(time.March, 4)},
+		{Date: seriesDay(time.March, 5)},
+		{Date: seriesDay(time.March, 6), TotalXP: 120, ActiveStudents: 1},
+		{Date: seriesDay(time.March, 7)},
+	}, got)
+}
+
+func TestFillXPSeries_WeeksAreISOWeeks(t *testing.T) {
+	// 2025-03-05 - среда, 2025-03-23 - воскресенье.
+	points := []XPSeriesPoint{
+		{Date: seriesDay(time.March, 3), TotalXP: 700, ActiveStudents: 5},
+		{Date: seriesDay(time.March, 17), TotalXP: 300, ActiveStudents: 2},
+	}
+
+	got, err := FillXPSeries(points, seriesDay(time.March, 5), seriesDay(time.March, 23), XPSeriesWeek)
+	require.NoError(t, err)
+
+	assert.Equal(t, []XPSeriesPoint{
+		{Date: seriesDay(time.March, 3), TotalXP: 700, ActiveStudents: 5},
+		{Date: seriesDay(time.March, 10)},
+		{Date: seriesDay(time.March, 17), TotalXP: 300, ActiveStudents: 2},
+	}, got)
+	for _, p := range got {
+		assert.Equal(t, time.Monday, p.Date.Weekday())
+	}
+}
+
+func TestXPSeriesBucket_StartUsesCalendarDateOfZone(t *testing.T) {
+	almaty := time.FixedZone("Asia/Almaty", 5*60*60)
+
+	// Неделя считается по дате в зоне самого времени: понедельник 01:00 по
+	// Алматы (в UTC ещё воскресенье) - уже новая неделя.
+	sunday := time.Date(2025, time.March, 9, 23, 30, 0, 0, almaty)
+	monday := time.Date(2025, time.March, 10, 1, 0, 0, 0, almaty)
+
+	assert.Equal(t, seriesDay(time.March, 3), XPSeriesWeek.Start(sunday))
+	assert.Equal(t, seriesDay(time.March, 10), XPSeriesWeek.Start(monday))
+}
+
+func TestFillXPSeries_RejectsBadInput(t *testing.T) {
+	_, err := FillXPSeries(nil, seriesDay(time.March, 2), seriesDay(time.March, 1), XPSeriesDay)
+	assert.Error(t, err)
+
+	_, err = FillXPSeries(nil, seriesDay(time.March, 1), seriesDay(time.March, 2), "month")
+	assert.Error(t, err)
+}
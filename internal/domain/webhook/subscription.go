@@ -0,0 +1,206 @@
+// Package webhook содержит подписки внешних интеграций на события хаба.
+//
+// Кураторы зеркалируют запросы помощи в Discord/Slack: внешний сервис
+// регистрирует URL и секрет, а хаб отправляет на него подписанный JSON при
+// создании запроса, назначении помощника и закрытии запроса. Подписка может
+// быть ограничена одним потоком (cohort).
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// EVENT TYPES
+// ══════════════════════════════════════════════════════════════════════════════
+
+// EventType - событие, на которое можно подписаться. Имена публичные и не
+// зависят от внутренних имён доменных событий.
+type EventType string
+
+const (
+	// EventHelpRequestCreated - студент попросил помощь.
+	EventHelpRequestCreated EventType = "help_request.created"
+
+	// EventHelperAssigned - помощник взялся за запрос.
+	EventHelperAssigned EventType = "help_request.helper_assigned"
+
+	// EventHelpRequestResolved - запрос закрыт.
+	EventHelpRequestResolved EventType = "help_request.resolved"
+)
+
+// EventTypes возвращает все события, на которые можно подписаться.
+func EventTypes() []EventType {
+	return []EventType{EventHelpRequestCreated, EventHelperAssigned, EventHelpRequestResolved}
+}
+
+// MaxConsecutiveFailures - после стольких неудачных доставок подряд
+// подписка отключается, чтобы не стучаться в мёртвый URL.
+const MaxConsecutiveFailures = 20
+
+// ErrSubscriptionNotFound - подписки нет.
+var ErrSubscriptionNotFound = shared.NewDomainError("webhook", "Find", shared.ErrNotFound, "webhook subscription not found")
+
+// ══════════════════════════════════════════════════════════════════════════════
+// SUBSCRIPTION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Subscription - подписка внешнего сервиса на события.
+type Subscription struct {
+	// ID - идентификатор подписки (UUID).
+	ID string `json:"id"`
+
+	// URL - куда отправлять события (http/https).
+	URL string `json:"url"`
+
+	// Secret - ключ HMAC-подписи тела запроса. Наружу не отдаётся.
+	Secret string `json:"-"`
+
+	// EventTypes - на какие события подписка.
+	EventTypes []EventType `json:"event_types"`
+
+	// Cohort - только события этого потока (пусто - все потоки).
+	Cohort string `json:"cohort,omitempty"`
+
+	// Active - отправляются ли события.
+	Active bool `json:"active"`
+
+	// ConsecutiveFailures - неудачных доставок подряд.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// LastStatus - HTTP-статус последней доставки (0 - ответа не было).
+	LastStatus int `json:"last_status,omitempty"`
+
+	// LastError - ошибка последней доставки (пусто, если успешна).
+	LastError string `json:"last_error,omitempty"`
+
+	// LastDeliveryAt - когда была последняя попытка доставки.
+	LastDeliveryAt *time.Time `json:"last_delivery_at,omitempty"`
+
+	// CreatedAt - когда подписка создана.
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt - когда подписка последний раз менялась.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewSubscription создаёт активную подписку.
+func NewSubscription(id, rawURL, secret string, eventTypes []EventType, cohort string, now time.Time) (*Subscription, error) {
+	s := &Subscription{
+		ID:         id,
+		URL:        rawURL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		Cohort:     cohort,
+		Active:     true,
+		CreatedAt:  now.UTC(),
+		UpdatedAt:  now.UTC(),
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate проверяет URL, секрет и список событий.
+func (s *Subscription) Validate() error {
+	invalid := func(msg string) error {
+		return shared.NewDomainError("webhook", "Validate", shared.ErrInvalidInput, msg)
+	}
+
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return invalid("url must be an absolute http(s) URL")
+	}
+	if len(s.Secret) < 16 {
+		return invalid("secret must be at least 16 characters")
+	}
+	if len(s.EventTypes) == 0 {
+		return invalid("at least one event type is required")
+	}
+	for _, t := range s.EventTypes {
+		if !slices.Contains(EventTypes(), t) {
+			return invalid(fmt.Sprintf("unknown event type %q", t))
+		}
+	}
+	return nil
+}
+
+// Matches возвращает true, если событие потока cohort нужно отправить
+// по этой подписке.
+func (s *Subscription) Matches(eventType EventType, cohort string) bool {
+	if !s.Active || !slices.Contains(s.EventTypes, eventType) {
+		return false
+	}
+	return s.Cohort == "" || s.Cohort == cohort
+}
+
+// Activate включает подписку и сбрасывает счётчик неудач.
+func (s *Subscription) Activate(now time.Time) {
+	s.Active = true
+	s.ConsecutiveFailures = 0
+	s.UpdatedAt = now.UTC()
+}
+
+// Deactivate выключает подписку.
+func (s *Subscription) Deactivate(now time.Time) {
+	s.Active = false
+	s.UpdatedAt = now.UTC()
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// DELIVERY
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Delivery - итог доставки одного события (после всех повторов).
+type Delivery struct {
+	// Status - HTTP-статус последней попытки (0 - ответа не было).
+	Status int
+
+	// Error - ошибка (пусто - доставлено).
+	Error string
+
+	// At - когда завершилась доставка.
+	At time.Time
+}
+
+// Succeeded возвращает true, если событие доставлено.
+func (d Delivery) Succeeded() bool {
+	return d.Error == ""
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// INTERFACES
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Repository - хранилище подписок (PostgreSQL).
+type Repository interface {
+	// Create сохраняет новую подписку.
+	Create(ctx context.Context, s *Subscription) error
+
+	// GetByID возвращает подписку или ErrSubscriptionNotFound.
+	GetByID(ctx context.Context, id string) (*Subscription, error)
+
+	// List возвращает все подписки, новые первыми.
+	List(ctx context.Context) ([]*Subscription, error)
+
+	// ListActive возвращает активные подписки.
+	ListActive(ctx context.Context) ([]*Subscription, error)
+
+	// Update сохраняет URL, секрет, события, поток и активность подписки.
+	Update(ctx context.Context, s *Subscription) error
+
+	// Delete удаляет подписку.
+	Delete(ctx context.Context, id string) error
+
+	// RecordDelivery записывает итог доставки и атомарно обновляет счётчик
+	// неудач. Возвращает true, если подписка только что отключена из-за
+	// MaxConsecutiveFailures неудач подряд.
+	RecordDelivery(ctx context.Context, id string, d Delivery) (deactivated bool, err error)
+}
@@ -0,0 +1,367 @@
+package alem
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RESPONSE CACHE - Conditional requests and short-lived body cache
+// ══════════════════════════════════════════════════════════════════════════════
+
+// EndpointCategory groups Alem API endpoints that share a cache TTL.
+type EndpointCategory string
+
+const (
+	CategoryStudents    EndpointCategory = "students"
+	CategoryOnline      EndpointCategory = "online"
+	CategoryLeaderboard EndpointCategory = "leaderboard"
+	CategoryTasks       EndpointCategory = "tasks"
+	CategoryBootcamp    EndpointCategory = "bootcamp"
+	CategorySync        EndpointCategory = "sync"
+	CategoryOther       EndpointCategory = "other"
+)
+
+// categorize maps a request path to its endpoint category.
+func categorize(path string) EndpointCategory {
+	switch {
+	case strings.HasPrefix(path, "/api/v1/bootcamp"):
+		return CategoryBootcamp
+	case strings.HasPrefix(path, "/students/online"):
+		return CategoryOnline
+	case strings.HasPrefix(path, "/task-completions"),
+		strings.HasPrefix(path, "/students/") && strings.Contains(path, "/activities"):
+		return CategoryTasks
+	case strings.HasPrefix(path, "/students"):
+		return CategoryStudents
+	case strings.HasPrefix(path, "/leaderboard"):
+		return CategoryLeaderboard
+	case strings.HasPrefix(path, "/sync"):
+		return CategorySync
+	default:
+		return CategoryOther
+	}
+}
+
+// CacheConfig contains configuration for the response cache.
+type CacheConfig struct {
+	// Enabled turns response caching and conditional requests on
+	Enabled bool
+
+	// TTL is how long a response without ETag/Last-Modified is served
+	// without contacting the API, per endpoint category. Zero disables it.
+	TTL map[EndpointCategory]time.Duration
+
+	// ValidatorTTL is how long a response with ETag/Last-Modified is kept
+	// for revalidation. Such responses are always revalidated, never served blind.
+	ValidatorTTL time.Duration
+
+	// MaxEntries bounds the in-memory store
+	MaxEntries int
+
+	// NotModifiedCost is the rate limiter cost of a 304 response,
+	// as a fraction of a full request (0 = free, 1 = full cost)
+	NotModifiedCost float64
+}
+
+// DefaultCacheConfig returns sensible defaults for the Alem API.
+func DefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		Enabled: true,
+		TTL: map[EndpointCategory]time.Duration{
+			CategoryStudents:    2 * time.Minute,
+			CategoryOnline:      15 * time.Second,
+			CategoryLeaderboard: time.Minute,
+			CategoryTasks:       2 * time.Minute,
+			CategoryBootcamp:    time.Minute,
+		},
+		ValidatorTTL:    24 * time.Hour,
+		MaxEntries:      5000,
+		NotModifiedCost: 0.25,
+	}
+}
+
+// ttlFor returns the body cache TTL for a category.
+func (c CacheConfig) ttlFor(category EndpointCategory) time.Duration {
+	return c.TTL[category]
+}
+
+// CachedResponse is a stored API response with its validators.
+type CachedResponse struct {
+	Body         []byte
+	ETag         string
+	LastModified string
+	StoredAt     time.Time
+	ExpiresAt    time.Time
+
+	// decoded holds the parsed body so 304s and TTL hits skip json.Unmarshal.
+	// Only populated by in-process stores.
+	decoded interface{}
+}
+
+// hasValidators reports whether the response can be revalidated.
+func (r *CachedResponse) hasValidators() bool {
+	return r.ETag != "" || r.LastModified != ""
+}
+
+// ResponseStore persists cached responses. The in-memory store is used by
+// default; a shared store (e.g. Redis) can be plugged in via ClientConfig.
+type ResponseStore interface {
+	Get(ctx context.Context, key string) (*CachedResponse, bool)
+	Set(ctx context.Context, key string, resp *CachedResponse)
+}
+
+// CacheStats contains response cache counters.
+type CacheStats struct {
+	// Hits are responses served from the body cache without a request
+	Hits int64
+	// NotModified are 304 responses served from the stored body
+	NotModified int64
+	// Misses are requests that returned a full body
+	Misses int64
+	// Bypassed are requests made with ForceFresh
+	Bypassed int64
+	// Decodes counts JSON body parses
+	Decodes int64
+}
+
+// HitRatio returns the share of lookups served from the cache.
+func (s CacheStats) HitRatio() float64 {
+	total := s.Hits + s.NotModified + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits+s.NotModified) / float64(total)
+}
+
+// Sub returns the counters accumulated since prev.
+func (s CacheStats) Sub(prev CacheStats) CacheStats {
+	return CacheStats{
+		Hits:        s.Hits - prev.Hits,
+		NotModified: s.NotModified - prev.NotModified,
+		Misses:      s.Misses - prev.Misses,
+		Bypassed:    s.Bypassed - prev.Bypassed,
+		Decodes:     s.Decodes - prev.Decodes,
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Force fresh
+// ─────────────────────────────────────────────────────────────────────────────
+
+type forceFreshKey struct{}
+
+// WithForceFresh returns a context whose requests bypass the response cache.
+// Fresh responses are still stored for later requests.
+func WithForceFresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceFreshKey{}, true)
+}
+
+// isForceFresh reports whether the context bypasses the cache.
+func isForceFresh(ctx context.Context) bool {
+	v, _ := ctx.Value(forceFreshKey{}).(bool)
+	return v
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Response cache
+// ─────────────────────────────────────────────────────────────────────────────
+
+// responseCache wraps a store with config and counters.
+type responseCache struct {
+	config CacheConfig
+	store  ResponseStore
+
+	hits        atomic.Int64
+	notModified atomic.Int64
+	misses      atomic.Int64
+	bypassed    atomic.Int64
+	decodes     atomic.Int64
+}
+
+// newResponseCache creates a response cache. A nil store uses memory.
+func newResponseCache(config CacheConfig, store ResponseStore) *responseCache {
+	if store == nil {
+		store = NewMemoryResponseStore(config.MaxEntries)
+	}
+	return &responseCache{config: config, store: store}
+}
+
+// key builds the cache key from the URL and the credentials it was fetched
+// with, since some endpoints return per-user data.
+func (c *responseCache) key(fullURL, authorization string) string {
+	sum := sha256.Sum256([]byte(authorization))
+	return hex.EncodeToString(sum[:8]) + ":" + fullURL
+}
+
+// lookup returns the stored entry and whether it can be served without a request.
+func (c *responseCache) lookup(ctx context.Context, key string) (*CachedResponse, bool) {
+	entry, ok := c.store.Get(ctx, key)
+	if !ok {
+		return nil, false
+	}
+	fresh := !entry.hasValidators() && time.Now().Before(entry.ExpiresAt)
+	return entry, fresh
+}
+
+// save stores a full response, if it is worth keeping.
+func (c *responseCache) save(ctx context.Context, key, path string, header http.Header, body []byte, decoded interface{}) {
+	entry := &CachedResponse{
+		Body:         body,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		StoredAt:     time.Now(),
+		decoded:      decoded,
+	}
+
+	if entry.hasValidators() {
+		entry.ExpiresAt = entry.StoredAt.Add(c.config.ValidatorTTL)
+	} else {
+		ttl := c.config.ttlFor(categorize(path))
+		if ttl <= 0 {
+			return
+		}
+		entry.ExpiresAt = entry.StoredAt.Add(ttl)
+	}
+
+	c.store.Set(ctx, key, entry)
+}
+
+// touch extends a revalidated entry.
+func (c *responseCache) touch(ctx context.Context, key string, entry *CachedResponse) {
+	refreshed := *entry
+	refreshed.StoredAt = time.Now()
+	refreshed.ExpiresAt = refreshed.StoredAt.Add(c.config.ValidatorTTL)
+	c.store.Set(ctx, key, &refreshed)
+}
+
+// decode parses body into result and returns a snapshot for the cache.
+func (c *responseCache) decode(body []byte, result interface{}) (interface{}, error) {
+	if result == nil || len(body) == 0 {
+		return nil, nil
+	}
+	c.decodes.Add(1)
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, err
+	}
+	return snapshot(result), nil
+}
+
+// load fills result from a cached entry, reusing the parsed value when possible.
+func (c *responseCache) load(entry *CachedResponse, result interface{}) error {
+	if result == nil {
+		return nil
+	}
+	if entry.decoded != nil {
+		dst := reflect.ValueOf(result)
+		src := reflect.ValueOf(entry.decoded)
+		if dst.Kind() == reflect.Ptr && src.Type() == dst.Type() {
+			dst.Elem().Set(src.Elem())
+			return nil
+		}
+	}
+	_, err := c.decode(entry.Body, result)
+	return err
+}
+
+// stats returns a snapshot of the counters.
+func (c *responseCache) stats() CacheStats {
+	return CacheStats{
+		Hits:        c.hits.Load(),
+		NotModified: c.notModified.Load(),
+		Misses:      c.misses.Load(),
+		Bypassed:    c.bypassed.Load(),
+		Decodes:     c.decodes.Load(),
+	}
+}
+
+// snapshot copies the value behind a pointer so later writes by the caller
+// don't leak into the cache. The copy is shallow: cached results are read-only.
+func snapshot(result interface{}) interface{} {
+	v := reflect.ValueOf(result)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+	cp := reflect.New(v.Elem().Type())
+	cp.Elem().Set(v.Elem())
+	return cp.Interface()
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Memory store
+// ─────────────────────────────────────────────────────────────────────────────
+
+// MemoryResponseStore is an in-process ResponseStore.
+type MemoryResponseStore struct {
+	mu         sync.Mutex
+	entries    map[string]*CachedResponse
+	maxEntries int
+}
+
+// NewMemoryResponseStore creates a memory store bounded to maxEntries.
+func NewMemoryResponseStore(maxEntries int) *MemoryResponseStore {
+	if maxEntries <= 0 {
+		maxEntries = 1000
+	}
+	return &MemoryResponseStore{
+		entries:    make(map[string]*CachedResponse),
+		maxEntries: maxEntries,
+	}
+}
+
+// Get returns an unexpired entry.
+func (s *MemoryResponseStore) Get(_ context.Context, key string) (*CachedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.ExpiresAt) {
+		delete(s.entries, key)
+		return nil, false
+	}
+	return entry, true
+}
+
+// Set stores an entry, evicting expired and then oldest entries when full.
+func (s *MemoryResponseStore) Set(_ context.Context, key string, resp *CachedResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.entries[key]; !exists && len(s.entries) >= s.maxEntries {
+		s.evict()
+	}
+	s.entries[key] = resp
+}
+
+// evict drops expired entries, or the oldest one if none expired.
+// Must be called with lock held.
+func (s *MemoryResponseStore) evict() {
+	now := time.Now()
+	var oldestKey string
+	var oldest time.Time
+
+	for k, e := range s.entries {
+		if now.After(e.ExpiresAt) {
+			delete(s.entries, k)
+			continue
+		}
+		if oldestKey == "" || e.StoredAt.Before(oldest) {
+			oldestKey, oldest = k, e.StoredAt
+		}
+	}
+
+	if len(s.entries) >= s.maxEntries && oldestKey != "" {
+		delete(s.entries, oldestKey)
+	}
+}
@@ -0,0 +1,145 @@
+package alem
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bootcampBody = `{"id":"bc-1","title":"bootcamp-go","total_xp":39900,"user_xp":19800}`
+
+// newCachingTestClient returns a client against srv with a rate limiter that
+// doesn't refill during the test, so token accounting is exact.
+func newCachingTestClient(srv *httptest.Server, notModifiedCost float64) *Client {
+	cfg := DefaultClientConfig(srv.URL)
+	cfg.RetryConfig.MaxRetries = 0
+	cfg.RateLimiterConfig = RateLimiterConfig{
+		RequestsPerSecond: 0.0001,
+		BurstSize:         10,
+		WaitTimeout:       time.Second,
+	}
+	cfg.Cache.NotModifiedCost = notModifiedCost
+	return NewClient(cfg)
+}
+
+func TestClient_ServesNotModifiedFromCache(t *testing.T) {
+	var requests, conditional atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(bootcampBody))
+	}))
+	defer srv.Close()
+
+	client := newCachingTestClient(srv, 0.25)
+	ctx := context.Background()
+
+	first, err := client.GetBootcamp(ctx, "bc-1", "c-1")
+	require.NoError(t, err)
+	afterFull := client.rateLimiter.Status().AvailableTokens
+
+	second, err := client.GetBootcamp(ctx, "bc-1", "c-1")
+	require.NoError(t, err)
+	afterNotModified := client.rateLimiter.Status().AvailableTokens
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, int32(2), requests.Load(), "validated responses are always revalidated")
+	assert.Equal(t, int32(1), conditional.Load())
+
+	stats := client.CacheStats()
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.Equal(t, int64(1), stats.NotModified)
+	assert.Equal(t, int64(1), stats.Decodes, "304 must not re-parse the body")
+	assert.InDelta(t, 0.5, stats.HitRatio(), 0.001)
+
+	assert.InDelta(t, 9, afterFull, 0.01)
+	assert.InDelta(t, 8.75, afterNotModified, 0.01, "304 is charged NotModifiedCost")
+}
+
+func TestClient_NotModifiedCostIsConfigurable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") != "" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", "Mon, 16 Jun 2025 04:00:00 GMT")
+		_, _ = w.Write([]byte(bootcampBody))
+	}))
+	defer srv.Close()
+
+	client := newCachingTestClient(srv, 1)
+	ctx := context.Background()
+
+	_, err := client.GetBootcamp(ctx, "bc-1", "c-1")
+	require.NoError(t, err)
+	_, err = client.GetBootcamp(ctx, "bc-1", "c-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1), client.CacheStats().NotModified)
+	assert.InDelta(t, 8, client.rateLimiter.Status().AvailableTokens, 0.01)
+}
+
+func TestClient_TTLCacheWithoutValidators(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(bootcampBody))
+	}))
+	defer srv.Close()
+
+	client := newCachingTestClient(srv, 0.25)
+	ctx := context.Background()
+
+	_, err := client.GetBootcamp(ctx, "bc-1", "c-1")
+	require.NoError(t, err)
+	cached, err := client.GetBootcamp(ctx, "bc-1", "c-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "bootcamp-go", cached.Title)
+	assert.Equal(t, int32(1), requests.Load())
+	assert.Equal(t, int64(1), client.CacheStats().Hits)
+
+	// ForceFresh bypasses the TTL cache
+	fresh, err := client.GetBootcamp(WithForceFresh(ctx), "bc-1", "c-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "bootcamp-go", fresh.Title)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, int64(1), client.CacheStats().Bypassed)
+}
+
+func TestClient_ForceFreshSkipsConditionalHeaders(t *testing.T) {
+	var conditional atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			conditional.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(bootcampBody))
+	}))
+	defer srv.Close()
+
+	client := newCachingTestClient(srv, 0.25)
+	ctx := WithForceFresh(context.Background())
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetBootcamp(ctx, "bc-1", "c-1")
+		require.NoError(t, err)
+	}
+
+	assert.Zero(t, conditional.Load())
+	assert.Equal(t, int64(2), client.CacheStats().Decodes)
+}
@@ -17,6 +17,8 @@ import (
 	"strconv"
 	"sync"
 	"time"
+
+	"github.com/alem-hub/alem-community-hub/pkg/httpclient"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -43,6 +45,12 @@ type ClientConfig struct {
 	// RetryConfig for retry behavior
 	RetryConfig RetryConfig
 
+	// Cache configures conditional requests and the response body cache
+	Cache CacheConfig
+
+	// CacheStore overrides the in-memory response store (e.g. Redis)
+	CacheStore ResponseStore
+
 	// Logger for structured logging
 	Logger *slog.Logger
 
@@ -58,6 +66,7 @@ func DefaultClientConfig(baseURL string) ClientConfig {
 		RateLimiterConfig:    DefaultRateLimiterConfig(),
 		CircuitBreakerConfig: DefaultCircuitBreakerConfig(),
 		RetryConfig:          DefaultRetryConfig(),
+		Cache:                DefaultCacheConfig(),
 	}
 }
 
@@ -68,11 +77,12 @@ func DefaultClientConfig(baseURL string) ClientConfig {
 // Client is the Alem Platform API client.
 type Client struct {
 	config         ClientConfig
-	httpClient     *http.Client
+	httpClient     *httpclient.Client
 	logger         *slog.Logger
 	rateLimiter    *RateLimiter
 	circuitBreaker *CircuitBreaker
 	mapper         *Mapper
+	cache          *responseCache
 
 	// Token management
 	token   *TokenDTO
@@ -85,15 +95,23 @@ func NewClient(config ClientConfig) *Client {
 		config.Logger = slog.Default()
 	}
 
+	var cache *responseCache
+	if config.Cache.Enabled {
+		cache = newResponseCache(config.Cache, config.CacheStore)
+	}
+
 	return &Client{
 		config: config,
-		httpClient: &http.Client{
+		httpClient: httpclient.New(httpclient.Config{
+			Name:    "alem",
 			Timeout: config.Timeout,
-		},
+			Logger:  config.Logger,
+		}),
 		logger:         config.Logger,
 		rateLimiter:    NewRateLimiter(config.RateLimiterConfig),
 		circuitBreaker: NewCircuitBreaker(config.CircuitBreakerConfig),
 		mapper:         NewMapper(),
+		cache:          cache,
 	}
 }
 
@@ -454,7 +472,24 @@ func (c *Client) GetStudentActivities(ctx context.Context, studentID string, sin
 // ══════════════════════════════════════════════════════════════════════════════
 
 // doRequest performs an HTTP request with rate limiting, circuit breaking, and retries.
+// GET requests go through the response cache unless the context forces a fresh fetch.
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	var (
+		cacheKey string
+		cached   *CachedResponse
+	)
+	if c.cache != nil && method == http.MethodGet {
+		cacheKey = c.cache.key(c.config.BaseURL+path, c.authorizationHeader())
+		if isForceFresh(ctx) {
+			c.cache.bypassed.Add(1)
+		} else if entry, fresh := c.cache.lookup(ctx, cacheKey); fresh {
+			c.cache.hits.Add(1)
+			return c.cache.load(entry, result)
+		} else {
+			cached = entry
+		}
+	}
+
 	// Check circuit breaker
 	if err := c.circuitBreaker.Allow(); err != nil {
 		return fmt.Errorf("circuit breaker: %w", err)
@@ -476,10 +511,10 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 			return fmt.Errorf("rate limiter: %w", err)
 		}
 
-		err := c.doSingleRequest(ctx, method, path, body, result)
+		resp, err := c.execute(ctx, method, path, body, cached)
 		if err == nil {
 			c.circuitBreaker.RecordSuccess()
-			return nil
+			return c.handleResponse(ctx, cacheKey, path, cached, resp, result)
 		}
 
 		lastErr = err
@@ -501,37 +536,84 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 	return fmt.Errorf("request failed after %d retries: %w", c.config.RetryConfig.MaxRetries, lastErr)
 }
 
+// handleResponse decodes a successful response, serving 304s from the cache.
+func (c *Client) handleResponse(
+	ctx context.Context,
+	cacheKey, path string,
+	cached *CachedResponse,
+	resp *rawResponse,
+	result interface{},
+) error {
+	if c.cache == nil || cacheKey == "" {
+		return decodeBody(resp.Body, result)
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		c.cache.notModified.Add(1)
+		c.rateLimiter.Refund(1 - c.config.Cache.NotModifiedCost)
+		c.cache.touch(ctx, cacheKey, cached)
+		return c.cache.load(cached, result)
+	}
+
+	c.cache.misses.Add(1)
+	decoded, err := c.cache.decode(resp.Body, result)
+	if err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+	c.cache.save(ctx, cacheKey, path, resp.Header, resp.Body, decoded)
+	return nil
+}
+
+// rawResponse is a successful (2xx or 304) response before decoding.
+type rawResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
 // doSingleRequest performs a single HTTP request.
 func (c *Client) doSingleRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	resp, err := c.execute(ctx, method, path, body, nil)
+	if err != nil {
+		return err
+	}
+	return decodeBody(resp.Body, result)
+}
+
+// execute sends a single HTTP request. When cached is set, the request is made
+// conditional on its validators and a 304 is returned without error.
+func (c *Client) execute(ctx context.Context, method, path string, body interface{}, cached *CachedResponse) (*rawResponse, error) {
 	fullURL := c.config.BaseURL + path
 
 	var bodyReader io.Reader
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
 		if err != nil {
-			return fmt.Errorf("marshal body: %w", err)
+			return nil, fmt.Errorf("marshal body: %w", err)
 		}
 		bodyReader = bytes.NewReader(jsonBody)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, fullURL, bodyReader)
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return nil, fmt.Errorf("create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
-	if c.config.APIKey != "" {
-		req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+	if auth := c.authorizationHeader(); auth != "" {
+		req.Header.Set("Authorization", auth)
 	}
 
-	// Add token if available
-	c.tokenMu.RLock()
-	if c.token != nil && !c.token.IsExpired() {
-		req.Header.Set("Authorization", c.token.TokenType+" "+c.token.AccessToken)
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
 	}
-	c.tokenMu.RUnlock()
 
 	if c.config.Debug {
 		c.logger.Debug("alem api request", "method", method, "path", path)
@@ -539,13 +621,13 @@ func (c *Client) doSingleRequest(ctx context.Context, method, path string, body
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("http request: %w", err)
+		return nil, fmt.Errorf("http request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("read response: %w", err)
+		return nil, fmt.Errorf("read response: %w", err)
 	}
 
 	// Handle rate limiting
@@ -556,7 +638,7 @@ func (c *Client) doSingleRequest(ctx context.Context, method, path string, body
 				retryAfter = time.Duration(seconds) * time.Second
 			}
 		}
-		return &RateLimitError{
+		return nil, &RateLimitError{
 			RetryAfter: retryAfter,
 			Message:    "rate limit exceeded",
 		}
@@ -566,18 +648,45 @@ func (c *Client) doSingleRequest(ctx context.Context, method, path string, body
 	if resp.StatusCode >= 400 {
 		var apiErr APIErrorDTO
 		if err := json.Unmarshal(respBody, &apiErr); err == nil && apiErr.Message != "" {
-			return &apiErr
+			return nil, &apiErr
 		}
-		return fmt.Errorf("api error: status %d", resp.StatusCode)
+		return nil, fmt.Errorf("api error: status %d", resp.StatusCode)
+	}
+
+	// A 304 is only meaningful for a conditional request
+	if resp.StatusCode == http.StatusNotModified && cached == nil {
+		return nil, fmt.Errorf("api error: unexpected status %d", resp.StatusCode)
 	}
 
-	// Unmarshal response
-	if result != nil && len(respBody) > 0 {
-		if err := json.Unmarshal(respBody, result); err != nil {
+	return &rawResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       respBody,
+	}, nil
+}
+
+// authorizationHeader returns the Authorization header value for requests.
+// A valid session token takes precedence over the API key.
+func (c *Client) authorizationHeader() string {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+
+	if c.token != nil && !c.token.IsExpired() {
+		return c.token.TokenType + " " + c.token.AccessToken
+	}
+	if c.config.APIKey != "" {
+		return "Bearer " + c.config.APIKey
+	}
+	return ""
+}
+
+// decodeBody unmarshals a response body into result.
+func decodeBody(body []byte, result interface{}) error {
+	if result != nil && len(body) > 0 {
+		if err := json.Unmarshal(body, result); err != nil {
 			return fmt.Errorf("unmarshal response: %w", err)
 		}
 	}
-
 	return nil
 }
 
@@ -631,15 +740,27 @@ func findStr(s, substr string) int {
 
 // IsHealthy checks if the Alem API is reachable.
 func (c *Client) IsHealthy(ctx context.Context) bool {
+	return c.HealthCheck(ctx) == nil
+}
+
+// HealthCheck calls the Alem API health endpoint once, without retries.
+func (c *Client) HealthCheck(ctx context.Context) error {
 	var response APIResponse[map[string]interface{}]
-	err := c.doSingleRequest(ctx, http.MethodGet, "/health", nil, &response)
-	return err == nil && response.Success
+	if err := c.doSingleRequest(ctx, http.MethodGet, "/health", nil, &response); err != nil {
+		return err
+	}
+	if !response.Success {
+		return errors.New("alem api reports unhealthy")
+	}
+	return nil
 }
 
 // Status returns the current status of the client.
 type ClientStatus struct {
 	RateLimiter    RateLimiterStatus
 	CircuitBreaker CircuitBreakerStatus
+	Cache          CacheStats
+	HTTP           httpclient.Stats
 	IsHealthy      bool
 }
 
@@ -648,10 +769,25 @@ func (c *Client) Status(ctx context.Context) ClientStatus {
 	return ClientStatus{
 		RateLimiter:    c.rateLimiter.Status(),
 		CircuitBreaker: c.circuitBreaker.Status(),
+		Cache:          c.CacheStats(),
+		HTTP:           c.HTTPStats(),
 		IsHealthy:      c.IsHealthy(ctx),
 	}
 }
 
+// CacheStats returns the response cache counters since the client was created.
+func (c *Client) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	return c.cache.stats()
+}
+
+// HTTPStats returns transport-level counters (in-flight requests, connection reuse).
+func (c *Client) HTTPStats() httpclient.Stats {
+	return c.httpClient.Stats()
+}
+
 // Reset resets the rate limiter and circuit breaker.
 func (c *Client) Reset() {
 	c.rateLimiter.Reset()
@@ -72,8 +72,8 @@ func (m *Mapper) StudentFromDTO(dto *StudentDTO) (*student.Student, error) {
 	s := &student.Student{
 		ID:         dto.ID,
 		TelegramID: 0, // Will be set when linking accounts
-		// AlemLogin removed - not storing it anymore
-		Email: dto.Login + "@alem.school", // Derive email if missing in DTO? Or leave empty?
+		AlemLogin:  student.NormalizeAlemLogin(dto.Login),
+		Email:      dto.Login + "@alem.school", // Derive email if missing in DTO? Or leave empty?
 		// Ideally we should have Email in DTO but DTO struct review might reveal it.
 		// If I leave Email empty, it fails validation. I need to assume email can be constructed or just placeholder?
 		// Since this mapper handles 'StudentFromDTO', which seems to be used for syncing FROM Alem,
@@ -4,8 +4,11 @@ package alem
 import (
 	"context"
 	"errors"
+	"math"
 	"sync"
 	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -27,6 +30,9 @@ type RateLimiter struct {
 	waitTimeout      time.Duration // Maximum time to wait for a token
 	retryAfter       time.Duration // How long to wait after rate limit hit
 	consecutiveWaits int           // Track consecutive waits for adaptive backoff
+
+	// onDemand is the bucket reserved for on-demand syncs (nil = no reservation)
+	onDemand *RateLimiter
 }
 
 // RateLimiterConfig contains configuration for the rate limiter.
@@ -45,6 +51,12 @@ type RateLimiterConfig struct {
 
 	// RetryAfter is the default retry time when rate limited
 	RetryAfter time.Duration
+
+	// OnDemandShare is the share (0..1) of the rate and burst reserved for
+	// syncs requested by students (student.WithOnDemandSync). They only use
+	// this share, and other requests never use it, so neither starves the
+	// other. 0 disables the reservation.
+	OnDemandShare float64
 }
 
 // DefaultRateLimiterConfig returns conservative defaults for Alem API.
@@ -85,6 +97,25 @@ func ConservativeRateLimiterConfig() RateLimiterConfig {
 
 // NewRateLimiter creates a new RateLimiter with the given configuration.
 func NewRateLimiter(config RateLimiterConfig) *RateLimiter {
+	if config.OnDemandShare <= 0 || config.OnDemandShare >= 1 {
+		return newTokenBucket(config)
+	}
+
+	onDemand := config
+	onDemand.RequestsPerSecond = config.RequestsPerSecond * config.OnDemandShare
+	onDemand.BurstSize = max(1, int(math.Round(float64(config.BurstSize)*config.OnDemandShare)))
+
+	shared := config
+	shared.RequestsPerSecond = config.RequestsPerSecond - onDemand.RequestsPerSecond
+	shared.BurstSize = max(1, config.BurstSize-onDemand.BurstSize)
+
+	rl := newTokenBucket(shared)
+	rl.onDemand = newTokenBucket(onDemand)
+	return rl
+}
+
+// newTokenBucket creates a single token bucket.
+func newTokenBucket(config RateLimiterConfig) *RateLimiter {
 	now := time.Now()
 	return &RateLimiter{
 		maxTokens:   float64(config.BurstSize),
@@ -128,7 +159,12 @@ var (
 
 // Allow checks if a request is allowed and blocks until it is or timeout.
 // Returns nil if the request can proceed, or an error if rate limited.
+// On-demand syncs take tokens from the reserved share only.
 func (rl *RateLimiter) Allow(ctx context.Context) error {
+	if rl.onDemand != nil && student.IsOnDemandSync(ctx) {
+		return rl.onDemand.Allow(ctx)
+	}
+
 	deadline := time.Now().Add(rl.waitTimeout)
 
 	for {
@@ -255,6 +291,10 @@ func (rl *RateLimiter) refillTokens() {
 // RecordRateLimitHit records that the API returned a rate limit response.
 // This adjusts internal state to be more conservative.
 func (rl *RateLimiter) RecordRateLimitHit(retryAfter time.Duration) {
+	if rl.onDemand != nil {
+		rl.onDemand.RecordRateLimitHit(retryAfter)
+	}
+
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
@@ -276,9 +316,30 @@ func (rl *RateLimiter) RecordRateLimitHit(retryAfter time.Duration) {
 	rl.consecutiveWaits++
 }
 
+// Refund returns part of a consumed token, e.g. for a cheap 304 response.
+// On-demand syncs bypass the response cache, so refunds go to the shared bucket.
+func (rl *RateLimiter) Refund(tokens float64) {
+	if tokens <= 0 {
+		return
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.refillTokens()
+	rl.tokens += tokens
+	if rl.tokens > rl.maxTokens {
+		rl.tokens = rl.maxTokens
+	}
+}
+
 // Reset resets the rate limiter to initial state.
 // Useful after a period of inactivity or configuration change.
 func (rl *RateLimiter) Reset() {
+	if rl.onDemand != nil {
+		rl.onDemand.Reset()
+	}
+
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
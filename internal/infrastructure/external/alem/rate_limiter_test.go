@@ -0,0 +1,53 @@
+package alem
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+func TestRateLimiter_OnDemandShare(t *testing.T) {
+	rl := NewRateLimiter(RateLimiterConfig{
+		RequestsPerSecond: 4,
+		BurstSize:         4,
+		WaitTimeout:       time.Millisecond,
+		RetryAfter:        time.Minute,
+		OnDemandShare:     0.25,
+	})
+	ctx := context.Background()
+	onDemand := student.WithOnDemandSync(ctx)
+
+	require.NotNil(t, rl.onDemand)
+	assert.InDelta(t, 3, rl.Status().RefillRate, 0.001)
+	assert.InDelta(t, 1, rl.onDemand.Status().RefillRate, 0.001)
+
+	// Batch requests use up their share...
+	for i := 0; i < 3; i++ {
+		require.NoError(t, rl.Allow(ctx))
+	}
+	assert.ErrorIs(t, rl.Allow(ctx), ErrRateLimitExceeded)
+
+	// ...without touching the reserved one, and on-demand syncs are capped
+	// at theirs
+	require.NoError(t, rl.Allow(onDemand))
+	assert.ErrorIs(t, rl.Allow(onDemand), ErrRateLimitExceeded)
+}
+
+func TestRateLimiter_NoOnDemandShare(t *testing.T) {
+	rl := NewRateLimiter(RateLimiterConfig{
+		RequestsPerSecond: 1,
+		BurstSize:         2,
+		WaitTimeout:       time.Millisecond,
+	})
+
+	assert.Nil(t, rl.onDemand)
+	onDemand := student.WithOnDemandSync(context.Background())
+	require.NoError(t, rl.Allow(onDemand))
+	require.NoError(t, rl.Allow(context.Background()))
+	assert.Error(t, rl.Allow(onDemand))
+}
@@ -11,12 +11,14 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"mime/multipart"
 	"net/http"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/pkg/httpclient"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -52,7 +54,7 @@ func DefaultClientConfig(token string) ClientConfig {
 	return ClientConfig{
 		Token:         token,
 		BaseURL:       "https://api.telegram.org",
-		Timeout:       60 * time.Second, // Must be > polling timeout (30s) + network latency
+		Timeout:       15 * time.Second, // Long polling extends it per call, see GetUpdates
 		RetryAttempts: 3,
 		RetryDelay:    1 * time.Second,
 	}
@@ -67,9 +69,18 @@ type Update struct {
 	UpdateID      int64          `json:"update_id"`
 	Message       *Message       `json:"message,omitempty"`
 	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
+	InlineQuery   *InlineQuery   `json:"inline_query,omitempty"`
 	EditedMessage *Message       `json:"edited_message,omitempty"`
 }
 
+// InlineQuery represents an incoming inline query.
+type InlineQuery struct {
+	ID     string `json:"id"`
+	From   *User  `json:"from"`
+	Query  string `json:"query"`
+	Offset string `json:"offset"`
+}
+
 // Message represents a Telegram message.
 type Message struct {
 	MessageID int64           `json:"message_id"`
@@ -140,6 +151,24 @@ type InlineKeyboardButton struct {
 	URL          string `json:"url,omitempty"`
 }
 
+// ReplyKeyboardMarkup represents a custom keyboard shown instead of the
+// system keyboard. Pressing a button sends its text as a message.
+type ReplyKeyboardMarkup struct {
+	Keyboard       [][]KeyboardButton `json:"keyboard"`
+	IsPersistent   bool               `json:"is_persistent,omitempty"`
+	ResizeKeyboard bool               `json:"resize_keyboard,omitempty"`
+}
+
+// KeyboardButton represents a button in a reply keyboard.
+type KeyboardButton struct {
+	Text string `json:"text"`
+}
+
+// ReplyKeyboardRemove hides a previously sent reply keyboard.
+type ReplyKeyboardRemove struct {
+	RemoveKeyboard bool `json:"remove_keyboard"`
+}
+
 // APIResponse represents a Telegram API response.
 type APIResponse struct {
 	OK          bool                `json:"ok"`
@@ -162,12 +191,8 @@ type ResponseParameters struct {
 // Client is the Telegram Bot API client.
 type Client struct {
 	config     ClientConfig
-	httpClient *http.Client
+	httpClient *httpclient.Client
 	logger     *slog.Logger
-
-	// Update handling
-	updateOffset int64
-	updateMu     sync.Mutex
 }
 
 // NewClient creates a new Telegram client.
@@ -181,13 +206,20 @@ func NewClient(config ClientConfig) *Client {
 
 	return &Client{
 		config: config,
-		httpClient: &http.Client{
+		httpClient: httpclient.New(httpclient.Config{
+			Name:    "telegram",
 			Timeout: config.Timeout,
-		},
+			Logger:  config.Logger,
+		}),
 		logger: config.Logger,
 	}
 }
 
+// HTTPStats returns transport-level counters (in-flight requests, connection reuse).
+func (c *Client) HTTPStats() httpclient.Stats {
+	return c.httpClient.Stats()
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // SENDING MESSAGES
 // ══════════════════════════════════════════════════════════════════════════════
@@ -201,6 +233,11 @@ type SendMessageParams struct {
 	DisableWebPreview   bool
 	ReplyToMessageID    int64
 	ReplyMarkup         *InlineKeyboardMarkup
+
+	// ReplyKeyboard and RemoveKeyboard are used only without ReplyMarkup:
+	// a message carries at most one markup.
+	ReplyKeyboard  *ReplyKeyboardMarkup
+	RemoveKeyboard bool
 }
 
 // SendMessage sends a text message.
@@ -222,8 +259,13 @@ func (c *Client) SendMessage(ctx context.Context, params SendMessageParams) (*Me
 	if params.ReplyToMessageID > 0 {
 		body["reply_to_message_id"] = params.ReplyToMessageID
 	}
-	if params.ReplyMarkup != nil {
+	switch {
+	case params.ReplyMarkup != nil:
 		body["reply_markup"] = params.ReplyMarkup
+	case params.ReplyKeyboard != nil:
+		body["reply_markup"] = params.ReplyKeyboard
+	case params.RemoveKeyboard:
+		body["reply_markup"] = ReplyKeyboardRemove{RemoveKeyboard: true}
 	}
 
 	var message Message
@@ -234,17 +276,69 @@ func (c *Client) SendMessage(ctx context.Context, params SendMessageParams) (*Me
 	return &message, nil
 }
 
+// SendLong sends a message of any length. Text over MessageLimit is split
+// by SplitMessage (SplitPlainText without a parse mode) and the chunks are
+// sent in order: the reply goes with the first chunk, the keyboard with the
+// last. On failure the chunks sent so far are returned with the error.
+func (c *Client) SendLong(ctx context.Context, params SendMessageParams) ([]*Message, error) {
+	var chunks []string
+	switch params.ParseMode {
+	case "HTML":
+		chunks = SplitMessage(params.Text, MessageLimit)
+	default:
+		chunks = SplitPlainText(params.Text, MessageLimit)
+	}
+	if len(chunks) <= 1 {
+		msg, err := c.SendMessage(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return []*Message{msg}, nil
+	}
+
+	sent := make([]*Message, 0, len(chunks))
+	for i, chunk := range chunks {
+		part := params
+		part.Text = chunk
+		if i > 0 {
+			part.ReplyToMessageID = 0
+		}
+		if i < len(chunks)-1 {
+			part.ReplyMarkup = nil
+			part.ReplyKeyboard = nil
+			part.RemoveKeyboard = false
+		}
+
+		msg, err := c.SendMessage(ctx, part)
+		if err != nil {
+			return sent, fmt.Errorf("send chunk %d of %d: %w", i+1, len(chunks), err)
+		}
+		sent = append(sent, msg)
+	}
+	return sent, nil
+}
+
+// sendLongLast sends a message with SendLong and returns its last chunk.
+func (c *Client) sendLongLast(ctx context.Context, params SendMessageParams) (*Message, error) {
+	sent, err := c.SendLong(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return sent[len(sent)-1], nil
+}
+
 // SendText is a convenience method for sending plain text.
 func (c *Client) SendText(ctx context.Context, chatID int64, text string) (*Message, error) {
-	return c.SendMessage(ctx, SendMessageParams{
+	return c.sendLongLast(ctx, SendMessageParams{
 		ChatID: chatID,
 		Text:   text,
 	})
 }
 
-// SendHTML sends an HTML-formatted message.
+// SendHTML sends an HTML-formatted message, split into several messages
+// when it is too long. The last message is returned.
 func (c *Client) SendHTML(ctx context.Context, chatID int64, html string) (*Message, error) {
-	return c.SendMessage(ctx, SendMessageParams{
+	return c.sendLongLast(ctx, SendMessageParams{
 		ChatID:    chatID,
 		Text:      html,
 		ParseMode: "HTML",
@@ -281,6 +375,62 @@ func (c *Client) SendSilent(ctx context.Context, chatID int64, text string) (*Me
 	})
 }
 
+// SendDocument uploads content as a file with an optional HTML caption.
+func (c *Client) SendDocument(ctx context.Context, chatID int64, filename string, content []byte, caption string) (*Message, error) {
+	message, err := c.sendFile(ctx, "sendDocument", "document", chatID, filename, content, caption)
+	if err != nil {
+		return nil, fmt.Errorf("send document: %w", err)
+	}
+	return message, nil
+}
+
+// SendPhoto uploads an image (JPEG or PNG) as a photo with an optional HTML caption.
+func (c *Client) SendPhoto(ctx context.Context, chatID int64, filename string, photo []byte, caption string) (*Message, error) {
+	message, err := c.sendFile(ctx, "sendPhoto", "photo", chatID, filename, photo, caption)
+	if err != nil {
+		return nil, fmt.Errorf("send photo: %w", err)
+	}
+	return message, nil
+}
+
+// sendFile uploads content as the given multipart field of an API method.
+func (c *Client) sendFile(ctx context.Context, method, field string, chatID int64, filename string, content []byte, caption string) (*Message, error) {
+	fields := map[string]string{"chat_id": strconv.FormatInt(chatID, 10)}
+	if caption != "" {
+		fields["caption"] = caption
+		fields["parse_mode"] = "HTML"
+	}
+
+	var message Message
+	err := c.withRetries(ctx, func() error {
+		// The body is rebuilt per attempt: a reader can only be sent once
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		for name, value := range fields {
+			if err := w.WriteField(name, value); err != nil {
+				return fmt.Errorf("write field %s: %w", name, err)
+			}
+		}
+		part, err := w.CreateFormFile(field, filename)
+		if err != nil {
+			return fmt.Errorf("create %s part: %w", field, err)
+		}
+		if _, err := part.Write(content); err != nil {
+			return fmt.Errorf("write %s: %w", field, err)
+		}
+		if err := w.Close(); err != nil {
+			return fmt.Errorf("close multipart body: %w", err)
+		}
+
+		return c.post(ctx, method, &body, w.FormDataContentType(), &message)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // EDITING MESSAGES
 // ══════════════════════════════════════════════════════════════════════════════
@@ -367,7 +517,8 @@ func (c *Client) AnswerCallbackQuery(ctx context.Context, callbackQueryID string
 // ══════════════════════════════════════════════════════════════════════════════
 
 // GetUpdates fetches updates using long polling.
-func (c *Client) GetUpdates(ctx context.Context, offset int64, limit int, timeout int) ([]Update, error) {
+// Passing offset confirms all updates before it to Telegram.
+func (c *Client) GetUpdates(ctx context.Context, offset int64, limit int, timeout int, allowedUpdates []string) ([]Update, error) {
 	body := map[string]interface{}{
 		"timeout": timeout,
 	}
@@ -378,21 +529,31 @@ func (c *Client) GetUpdates(ctx context.Context, offset int64, limit int, timeou
 	if limit > 0 {
 		body["limit"] = limit
 	}
+	if len(allowedUpdates) > 0 {
+		body["allowed_updates"] = allowedUpdates
+	}
 
+	// The server holds the request for up to timeout seconds on purpose
 	var updates []Update
-	if err := c.callAPI(ctx, "getUpdates", body, &updates); err != nil {
+	pollTimeout := httpclient.WithTimeout(time.Duration(timeout)*time.Second + c.config.Timeout)
+	if err := c.callAPI(ctx, "getUpdates", body, &updates, pollTimeout); err != nil {
 		return nil, fmt.Errorf("get updates: %w", err)
 	}
 
 	return updates, nil
 }
 
-// SetWebhook sets a webhook for receiving updates.
-func (c *Client) SetWebhook(ctx context.Context, url string, maxConnections int, allowedUpdates []string) error {
+// SetWebhook sets a webhook for receiving updates. A non-empty secretToken
+// is sent back by Telegram in the X-Telegram-Bot-Api-Secret-Token header
+// of every update.
+func (c *Client) SetWebhook(ctx context.Context, url string, maxConnections int, allowedUpdates []string, secretToken string) error {
 	body := map[string]interface{}{
 		"url": url,
 	}
 
+	if secretToken != "" {
+		body["secret_token"] = secretToken
+	}
 	if maxConnections > 0 {
 		body["max_connections"] = maxConnections
 	}
@@ -465,14 +626,26 @@ func (c *Client) Send(ctx context.Context, notif *notification.Notification, opt
 		)
 	}
 
+	// A photo goes out alone, the message becomes its caption
+	if opts.Photo != nil {
+		msg, err := c.SendPhoto(ctx, int64(notif.TelegramChatID), opts.Photo.Filename, opts.Photo.Data, notif.Message)
+		if err != nil {
+			return c.failureResult(err)
+		}
+		return notification.NewSuccessResult(
+			notification.ChannelTypeTelegram,
+			strconv.FormatInt(msg.MessageID, 10),
+		)
+	}
+
 	// Build keyboard if provided
 	var keyboard *InlineKeyboardMarkup
 	if len(opts.InlineKeyboard) > 0 {
 		keyboard = c.buildKeyboard(opts.InlineKeyboard)
 	}
 
-	// Send message
-	msg, err := c.SendMessage(ctx, SendMessageParams{
+	// Send message (long digests go out in several messages)
+	msg, err := c.sendLongLast(ctx, SendMessageParams{
 		ChatID:              int64(notif.TelegramChatID),
 		Text:                notif.Message,
 		ParseMode:           opts.ParseMode,
@@ -481,20 +654,7 @@ func (c *Client) Send(ctx context.Context, notif *notification.Notification, opt
 		ReplyMarkup:         keyboard,
 	})
 	if err != nil {
-		// Check for specific errors
-		retryable := c.isRetryableError(err)
-		result := notification.NewFailureResult(notification.ChannelTypeTelegram, err, retryable)
-
-		// Check for blocked/not found
-		if c.isChatNotFound(err) {
-			result.Error = notification.ErrChatNotFound
-			result.Retryable = false
-		} else if c.isUserBlocked(err) {
-			result.Error = notification.ErrRecipientBlocked
-			result.Retryable = false
-		}
-
-		return result
+		return c.failureResult(err)
 	}
 
 	return notification.NewSuccessResult(
@@ -503,6 +663,31 @@ func (c *Client) Send(ctx context.Context, notif *notification.Notification, opt
 	)
 }
 
+// failureResult converts a send error into a delivery result.
+func (c *Client) failureResult(err error) notification.DeliveryResult {
+	retryable := c.isRetryableError(err)
+	result := notification.NewFailureResult(notification.ChannelTypeTelegram, err, retryable)
+	result.ErrorCode = c.errorCode(err)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		result.RetryAfter = time.Duration(apiErr.RetryAfter) * time.Second
+	}
+
+	// Check for blocked/not found
+	if c.isChatNotFound(err) {
+		result.Error = notification.ErrChatNotFound
+		result.ErrorCode = notification.ErrorCodeChatNotFound
+		result.Retryable = false
+	} else if c.isUserBlocked(err) {
+		result.Error = notification.ErrRecipientBlocked
+		result.ErrorCode = notification.ErrorCodeBlocked
+		result.Retryable = false
+	}
+
+	return result
+}
+
 // Type returns the channel type.
 func (c *Client) Type() notification.ChannelType {
 	return notification.ChannelTypeTelegram
@@ -583,7 +768,14 @@ func (kb *KeyboardBuilder) Build() *InlineKeyboardMarkup {
 // ══════════════════════════════════════════════════════════════════════════════
 
 // callAPI makes a call to the Telegram Bot API with retries.
-func (c *Client) callAPI(ctx context.Context, method string, body map[string]interface{}, result interface{}) error {
+func (c *Client) callAPI(ctx context.Context, method string, body map[string]interface{}, result interface{}, opts ...httpclient.RequestOption) error {
+	return c.withRetries(ctx, func() error {
+		return c.doAPICall(ctx, method, body, result, opts...)
+	})
+}
+
+// withRetries runs an API call, retrying retryable errors with backoff.
+func (c *Client) withRetries(ctx context.Context, call func() error) error {
 	var lastErr error
 
 	for attempt := 0; attempt <= c.config.RetryAttempts; attempt++ {
@@ -596,7 +788,7 @@ func (c *Client) callAPI(ctx context.Context, method string, body map[string]int
 			}
 		}
 
-		err := c.doAPICall(ctx, method, body, result)
+		err := call()
 		if err == nil {
 			return nil
 		}
@@ -622,10 +814,8 @@ func (c *Client) callAPI(ctx context.Context, method string, body map[string]int
 	return fmt.Errorf("api call failed after %d retries: %w", c.config.RetryAttempts, lastErr)
 }
 
-// doAPICall performs a single API call.
-func (c *Client) doAPICall(ctx context.Context, method string, body map[string]interface{}, result interface{}) error {
-	url := fmt.Sprintf("%s/bot%s/%s", c.config.BaseURL, c.config.Token, method)
-
+// doAPICall performs a single API call with a JSON body.
+func (c *Client) doAPICall(ctx context.Context, method string, body map[string]interface{}, result interface{}, opts ...httpclient.RequestOption) error {
 	var bodyReader io.Reader
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
@@ -635,18 +825,25 @@ func (c *Client) doAPICall(ctx context.Context, method string, body map[string]i
 		bodyReader = bytes.NewReader(jsonBody)
 	}
 
+	return c.post(ctx, method, bodyReader, "application/json", result, opts...)
+}
+
+// post sends a request body to an API method and decodes the result.
+func (c *Client) post(ctx context.Context, method string, bodyReader io.Reader, contentType string, result interface{}, opts ...httpclient.RequestOption) error {
+	url := fmt.Sprintf("%s/bot%s/%s", c.config.BaseURL, c.config.Token, method)
+
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bodyReader)
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
 
 	if c.config.Debug {
 		c.logger.Debug("telegram api call", "method", method)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.httpClient.Do(req, opts...)
 	if err != nil {
 		return fmt.Errorf("http request: %w", err)
 	}
@@ -725,6 +922,25 @@ func (c *Client) isRetryableError(err error) bool {
 	return containsAny(errStr, []string{"timeout", "connection refused", "temporary", "reset"})
 }
 
+// errorCode classifies a send error into a notification.ErrorCode* value.
+func (c *Client) errorCode(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.Code == 429:
+			return notification.ErrorCodeRateLimited
+		case apiErr.Code >= 500:
+			return notification.ErrorCodeServer
+		case apiErr.Code >= 400:
+			return notification.ErrorCodeRejected
+		}
+	}
+	if c.isRetryableError(err) {
+		return notification.ErrorCodeNetwork
+	}
+	return ""
+}
+
 // isChatNotFound checks if the error indicates chat not found.
 func (c *Client) isChatNotFound(err error) bool {
 	var apiErr *APIError
@@ -782,50 +998,154 @@ func findStr(s, substr string) int {
 // UpdateHandler is a function that handles a Telegram update.
 type UpdateHandler func(ctx context.Context, update *Update) error
 
-// StartPolling starts long polling for updates.
-func (c *Client) StartPolling(ctx context.Context, handler UpdateHandler) error {
-	c.logger.Info("starting telegram long polling")
+// DefaultAllowedUpdates are the update types the bot handles. Telegram
+// does not send the rest, which keeps getUpdates payloads small.
+var DefaultAllowedUpdates = []string{"message", "callback_query", "inline_query"}
 
-	for {
-		select {
-		case <-ctx.Done():
-			c.logger.Info("stopping telegram long polling")
-			return ctx.Err()
-		default:
+// OffsetStore persists the offset of the next update to fetch, so a
+// restarted poller resumes where the previous one stopped.
+type OffsetStore interface {
+	// LoadOffset returns the stored offset, or 0 if none was saved yet.
+	LoadOffset(ctx context.Context) (int64, error)
+
+	// SaveOffset stores the offset of the next update to fetch.
+	SaveOffset(ctx context.Context, offset int64) error
+}
+
+// PollingConfig contains configuration for the long polling runner.
+type PollingConfig struct {
+	// Timeout is the long poll timeout in seconds.
+	Timeout int
+
+	// Limit is the maximum number of updates fetched per request.
+	Limit int
+
+	// AllowedUpdates restricts the update types Telegram sends.
+	AllowedUpdates []string
+
+	// Workers is the number of updates handled concurrently. The next
+	// batch is not fetched until the current one is handled.
+	Workers int
+
+	// OffsetStore persists the offset between restarts (optional).
+	OffsetStore OffsetStore
+}
+
+// DefaultPollingConfig returns sensible defaults.
+func DefaultPollingConfig() PollingConfig {
+	return PollingConfig{
+		Timeout:        30,
+		Limit:          100,
+		AllowedUpdates: DefaultAllowedUpdates,
+		Workers:        10,
+	}
+}
+
+// StartPolling starts long polling for updates and blocks until ctx is cancelled.
+//
+// Every update of a fetched batch is dispatched in order to a bounded pool of
+// workers. The offset is saved after the dispatched updates are handled, so
+// on restart an update is neither processed twice nor skipped. Cancelling ctx
+// aborts the pending long poll immediately, stops dispatching and lets the
+// in-flight updates finish.
+func (c *Client) StartPolling(ctx context.Context, config PollingConfig, handler UpdateHandler) error {
+	if config.Workers <= 0 {
+		config.Workers = 1
+	}
+
+	var offset int64
+	if config.OffsetStore != nil {
+		stored, err := config.OffsetStore.LoadOffset(ctx)
+		if err != nil {
+			return fmt.Errorf("load update offset: %w", err)
 		}
+		offset = stored
+	}
 
-		c.updateMu.Lock()
-		offset := c.updateOffset
-		c.updateMu.Unlock()
+	c.logger.Info("starting telegram long polling",
+		"offset", offset,
+		"workers", config.Workers,
+	)
+
+	for {
+		if ctx.Err() != nil {
+			c.logger.Info("stopping telegram long polling", "offset", offset)
+			return nil
+		}
 
-		updates, err := c.GetUpdates(ctx, offset, 100, 30)
+		updates, err := c.GetUpdates(ctx, offset, config.Limit, config.Timeout, config.AllowedUpdates)
 		if err != nil {
-			// Don't log context cancellation
-			if errors.Is(err, context.Canceled) {
-				return nil
+			// Shutdown aborts the pending long poll
+			if ctx.Err() != nil {
+				continue
 			}
 			c.logger.Error("failed to get updates", "error", err)
-			time.Sleep(5 * time.Second)
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
+			}
 			continue
 		}
 
-		for _, update := range updates {
-			// Update offset
-			c.updateMu.Lock()
-			if update.UpdateID >= c.updateOffset {
-				c.updateOffset = update.UpdateID + 1
+		next := c.dispatchUpdates(ctx, updates, config.Workers, handler)
+		if next <= offset {
+			continue
+		}
+		offset = next
+
+		if config.OffsetStore != nil {
+			// Saved even during shutdown: the dispatched updates are handled
+			if err := config.OffsetStore.SaveOffset(context.WithoutCancel(ctx), offset); err != nil {
+				c.logger.Error("failed to save update offset", "offset", offset, "error", err)
+			}
+		}
+	}
+}
+
+// dispatchUpdates hands updates to a pool of workers in order until ctx is
+// cancelled and waits for the dispatched ones. Returns the offset following
+// the last dispatched update, or 0 if none was dispatched.
+func (c *Client) dispatchUpdates(ctx context.Context, updates []Update, workers int, handler UpdateHandler) int64 {
+	// In-flight updates are finished even if polling is stopped
+	handlerCtx := context.WithoutCancel(ctx)
+
+	queue := make(chan *Update)
+	var wg sync.WaitGroup
+	for i := 0; i < workers && i < len(updates); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for update := range queue {
+				if err := handler(handlerCtx, update); err != nil {
+					c.logger.Error("failed to handle update",
+						"update_id", update.UpdateID,
+						"error", err,
+					)
+				}
 			}
-			c.updateMu.Unlock()
-
-			// Handle update
-			if err := handler(ctx, &update); err != nil {
-				c.logger.Error("failed to handle update",
-					"update_id", update.UpdateID,
-					"error", err,
-				)
+		}()
+	}
+
+	var next int64
+dispatch:
+	for i := range updates {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case queue <- &updates[i]:
+			if updates[i].UpdateID >= next {
+				next = updates[i].UpdateID + 1
 			}
+		case <-ctx.Done():
+			break dispatch
 		}
 	}
+
+	close(queue)
+	wg.Wait()
+
+	return next
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
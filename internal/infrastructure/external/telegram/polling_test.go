@@ -0,0 +1,141 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBotAPI отдаёт updates с ID 1..total, начиная с запрошенного offset.
+// Если новых нет - держит long poll до отмены запроса.
+type fakeBotAPI struct {
+	total int64
+
+	mu             sync.Mutex
+	allowedUpdates []string
+}
+
+func (f *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Offset         int64    `json:"offset"`
+		Limit          int      `json:"limit"`
+		AllowedUpdates []string `json:"allowed_updates"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	f.mu.Lock()
+	f.allowedUpdates = body.AllowedUpdates
+	f.mu.Unlock()
+
+	var updates []Update
+	for id := max(body.Offset, 1); id <= f.total && len(updates) < body.Limit; id++ {
+		updates = append(updates, Update{UpdateID: id})
+	}
+	if len(updates) == 0 {
+		<-r.Context().Done()
+		return
+	}
+
+	result, _ := json.Marshal(updates)
+	_ = json.NewEncoder(w).Encode(APIResponse{OK: true, Result: result})
+}
+
+type memoryOffsetStore struct {
+	mu     sync.Mutex
+	offset int64
+}
+
+func (s *memoryOffsetStore) LoadOffset(context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offset, nil
+}
+
+func (s *memoryOffsetStore) SaveOffset(_ context.Context, offset int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset = offset
+	return nil
+}
+
+func newPollingTestClient(t *testing.T, api http.Handler) *Client {
+	t.Helper()
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	config := DefaultClientConfig("test-token")
+	config.BaseURL = server.URL
+	config.RetryAttempts = 0
+	return NewClient(config)
+}
+
+func TestStartPolling_RestartMidBatch(t *testing.T) {
+	api := &fakeBotAPI{total: 20}
+	client := newPollingTestClient(t, api)
+	store := &memoryOffsetStore{}
+
+	config := DefaultPollingConfig()
+	config.Limit = 8
+	config.Workers = 3
+	config.OffsetStore = store
+
+	var mu sync.Mutex
+	seen := make(map[int64]int)
+
+	// Первый запуск останавливается посреди второго батча
+	ctx, cancel := context.WithCancel(context.Background())
+	err := client.StartPolling(ctx, config, func(_ context.Context, u *Update) error {
+		mu.Lock()
+		seen[u.UpdateID]++
+		mu.Unlock()
+		if u.UpdateID == 11 {
+			cancel()
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Less(t, store.offset, api.total+1, "shutdown must stop dispatching")
+
+	// Перезапуск продолжает с сохранённого offset
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	err = client.StartPolling(ctx, config, func(_ context.Context, u *Update) error {
+		mu.Lock()
+		seen[u.UpdateID]++
+		done := len(seen) == int(api.total)
+		mu.Unlock()
+		if done {
+			cancel()
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	for id := int64(1); id <= api.total; id++ {
+		assert.Equal(t, 1, seen[id], "update %d", id)
+	}
+	assert.Equal(t, api.total+1, store.offset)
+	assert.Equal(t, DefaultAllowedUpdates, api.allowedUpdates)
+}
+
+func TestStartPolling_StopAbortsLongPoll(t *testing.T) {
+	client := newPollingTestClient(t, &fakeBotAPI{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	started := time.Now()
+	err := client.StartPolling(ctx, DefaultPollingConfig(), func(context.Context, *Update) error {
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Less(t, time.Since(started), 5*time.Second)
+}
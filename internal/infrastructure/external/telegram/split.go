@@ -0,0 +1,315 @@
+package telegram
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// LONG MESSAGES
+// Telegram rejects messages longer than 4096 characters (UTF-16 code units
+// of the text after entity parsing). SplitMessage cuts long HTML into chunks
+// that each fit the limit and are valid HTML on their own: tags open at the
+// cut are closed at the end of the chunk and reopened at the start of the
+// next one. Cuts prefer paragraph breaks, then line breaks, then spaces, and
+// never fall inside a tag, an entity or a character.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// MessageLimit is the maximum length of a Telegram message.
+const MessageLimit = notification.TelegramMessageLimit
+
+// Break kinds, best last.
+const (
+	breakNone = iota
+	breakWord
+	breakLine
+	breakParagraph
+)
+
+// SplitMessage splits HTML text into chunks of at most limit characters
+// (MessageLimit when limit <= 0). Text that fits is returned as is.
+func SplitMessage(text string, limit int) []string {
+	return splitMessage(text, limit, true)
+}
+
+// SplitPlainText splits text without markup the same way as SplitMessage.
+func SplitPlainText(text string, limit int) []string {
+	return splitMessage(text, limit, false)
+}
+
+// htmlToken is a tag, an entity or a single character of the message.
+type htmlToken struct {
+	text  string
+	units int // visible length in UTF-16 code units
+
+	open  *openTag // set for opening tags
+	close string   // tag name for closing tags
+}
+
+// openTag is a tag open at some point of the message.
+type openTag struct {
+	name string
+	raw  string
+}
+
+// cut is a position where a chunk can end.
+type cut struct {
+	end   int // index of the first token of the next chunk
+	stack []openTag
+	kind  int
+}
+
+func splitMessage(text string, limit int, markup bool) []string {
+	if limit <= 0 {
+		limit = MessageLimit
+	}
+
+	tokens := tokenizeMessage(text, markup)
+	if visibleUnits(tokens) <= limit {
+		return []string{text}
+	}
+
+	var chunks []string
+	var stack []openTag
+	for i := 0; i < len(tokens); {
+		// A continuation doesn't start with the whitespace it was cut at
+		if len(chunks) > 0 {
+			for i < len(tokens) && isBlankToken(tokens[i]) {
+				i++
+			}
+			if i == len(tokens) {
+				break
+			}
+		}
+
+		end := nextCut(tokens, i, stack, limit)
+
+		var sb strings.Builder
+		for _, t := range stack {
+			sb.WriteString(t.raw)
+		}
+		for _, t := range tokens[i:end.end] {
+			sb.WriteString(t.text)
+		}
+		for k := len(end.stack) - 1; k >= 0; k-- {
+			sb.WriteString("</" + end.stack[k].name + ">")
+		}
+		if hasContent(tokens[i:end.end]) {
+			chunks = append(chunks, sb.String())
+		}
+
+		stack = end.stack
+		i = end.end
+	}
+	return chunks
+}
+
+// nextCut finds where the chunk starting at token i ends. The chunk ends at
+// the best break in its second half, otherwise at the last break, otherwise
+// at the last token that fits.
+func nextCut(tokens []htmlToken, i int, stack []openTag, limit int) cut {
+	cur := stack
+	units := 0
+	var fit, last, best cut
+
+	for j := i; j < len(tokens); j++ {
+		next := applyToken(cur, tokens[j])
+		if units+tokens[j].units > limit {
+			break
+		}
+		units += tokens[j].units
+		cur = next
+
+		c := cut{end: j + 1, stack: cur, kind: breakKind(tokens, j+1)}
+		if !splitsCluster(tokens, j+1) {
+			fit = c
+		}
+		if c.kind != breakNone {
+			last = c
+			if units >= limit/2 && c.kind >= best.kind {
+				best = c
+			}
+		}
+	}
+
+	switch {
+	case fit.end == len(tokens):
+		return fit
+	case best.kind != breakNone:
+		return best
+	case last.kind != breakNone:
+		return last
+	case fit.end > i:
+		return fit
+	default:
+		// A single token longer than the limit still makes progress
+		return cut{end: i + 1, stack: applyToken(stack, tokens[i])}
+	}
+}
+
+// breakKind returns the kind of break right before token j.
+func breakKind(tokens []htmlToken, j int) int {
+	if j <= 0 || j >= len(tokens) {
+		return breakNone
+	}
+	switch tokens[j-1].text {
+	case "\n":
+		if j >= 2 && tokens[j-2].text == "\n" {
+			return breakParagraph
+		}
+		return breakLine
+	case " ":
+		return breakWord
+	}
+	return breakNone
+}
+
+// splitsCluster reports whether a cut before token j separates characters
+// displayed as one: an emoji sequence joined by ZWJ, a variation selector,
+// a skin tone modifier or a combining mark.
+func splitsCluster(tokens []htmlToken, j int) bool {
+	if j <= 0 || j >= len(tokens) {
+		return false
+	}
+	prev, _ := utf8.DecodeLastRuneInString(tokens[j-1].text)
+	next, _ := utf8.DecodeRuneInString(tokens[j].text)
+	return prev == '\u200d' || next == '\u200d' ||
+		next >= '\ufe00' && next <= '\ufe0f' ||
+		next >= 0x1f3fb && next <= 0x1f3ff ||
+		unicode.In(next, unicode.Mn, unicode.Me)
+}
+
+// applyToken returns the tags open after the token. The stack is copied on
+// change so that cuts keep their own snapshot.
+func applyToken(stack []openTag, t htmlToken) []openTag {
+	switch {
+	case t.open != nil:
+		next := make([]openTag, len(stack), len(stack)+1)
+		copy(next, stack)
+		return append(next, *t.open)
+	case t.close != "":
+		for k := len(stack) - 1; k >= 0; k-- {
+			if stack[k].name == t.close {
+				next := make([]openTag, k)
+				copy(next, stack[:k])
+				return next
+			}
+		}
+	}
+	return stack
+}
+
+// tokenizeMessage splits text into tags, entities and characters. Without
+// markup everything is a character.
+func tokenizeMessage(text string, markup bool) []htmlToken {
+	tokens := make([]htmlToken, 0, len(text))
+	for i := 0; i < len(text); {
+		if markup {
+			if t, n, ok := readTag(text[i:]); ok {
+				tokens = append(tokens, t)
+				i += n
+				continue
+			}
+			if n := entityLen(text[i:]); n > 0 {
+				entity := text[i : i+n]
+				tokens = append(tokens, htmlToken{text: entity, units: len(utf16.Encode([]rune(html.UnescapeString(entity))))})
+				i += n
+				continue
+			}
+		}
+
+		r, n := utf8.DecodeRuneInString(text[i:])
+		tokens = append(tokens, htmlToken{text: text[i : i+n], units: utf16.RuneLen(r)})
+		i += n
+	}
+	return tokens
+}
+
+// readTag reads a tag at the start of s.
+func readTag(s string) (htmlToken, int, bool) {
+	if len(s) < 3 || s[0] != '<' {
+		return htmlToken{}, 0, false
+	}
+	end := strings.IndexByte(s, '>')
+	if end < 0 {
+		return htmlToken{}, 0, false
+	}
+	raw := s[:end+1]
+
+	closing := raw[1] == '/'
+	body := raw[1:end]
+	if closing {
+		body = body[1:]
+	}
+	name := body
+	if k := strings.IndexAny(body, " \t\n/"); k >= 0 {
+		name = body[:k]
+	}
+	if name == "" || !isTagName(name) {
+		return htmlToken{}, 0, false
+	}
+	name = strings.ToLower(name)
+
+	if closing {
+		return htmlToken{text: raw, close: name}, len(raw), true
+	}
+	return htmlToken{text: raw, open: &openTag{name: name, raw: raw}}, len(raw), true
+}
+
+func isTagName(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// entityLen returns the length of an entity such as "&amp;" or "&#128512;"
+// at the start of s, or 0.
+func entityLen(s string) int {
+	if len(s) < 3 || s[0] != '&' {
+		return 0
+	}
+	for i := 1; i < len(s) && i <= 10; i++ {
+		c := s[i]
+		switch {
+		case c == ';':
+			if i == 1 {
+				return 0
+			}
+			return i + 1
+		case c == '#' && i == 1, c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		default:
+			return 0
+		}
+	}
+	return 0
+}
+
+func visibleUnits(tokens []htmlToken) int {
+	n := 0
+	for _, t := range tokens {
+		n += t.units
+	}
+	return n
+}
+
+func isBlankToken(t htmlToken) bool {
+	return t.text == "\n" || t.text == " "
+}
+
+func hasContent(tokens []htmlToken) bool {
+	for _, t := range tokens {
+		if t.units > 0 && !isBlankToken(t) {
+			return true
+		}
+	}
+	return false
+}
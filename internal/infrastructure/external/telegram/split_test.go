@@ -0,0 +1,162 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tagPattern = regexp.MustCompile(`</?([a-zA-Z][a-zA-Z0-9-]*)[^>]*>`)
+
+// assertValidChunk checks that a chunk is balanced Telegram HTML of at most
+// limit visible characters.
+func assertValidChunk(t *testing.T, chunk string, limit int) {
+	t.Helper()
+	require.True(t, utf8.ValidString(chunk), "chunk is valid UTF-8")
+
+	var stack []string
+	for _, m := range tagPattern.FindAllStringSubmatch(chunk, -1) {
+		name := strings.ToLower(m[1])
+		if strings.HasPrefix(m[0], "</") {
+			require.NotEmpty(t, stack, "closing </%s> without an opening tag in %q", name, chunk)
+			require.Equal(t, stack[len(stack)-1], name, "tags are closed in order in %q", chunk)
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		stack = append(stack, name)
+	}
+	assert.Empty(t, stack, "every tag is closed in %q", chunk)
+
+	text := tagPattern.ReplaceAllString(chunk, "")
+	assert.NotContains(t, text, "<", "no tag is cut in half")
+	visible := html.UnescapeString(text)
+	assert.LessOrEqual(t, len(utf16.Encode([]rune(visible))), limit)
+}
+
+func TestSplitMessage_ShortTextIsUntouched(t *testing.T) {
+	text := "<b>Привет</b> &amp; добро пожаловать"
+	assert.Equal(t, []string{text}, SplitMessage(text, 0))
+}
+
+func TestSplitMessage_NestedTagsStraddlingTheCut(t *testing.T) {
+	const limit = 60
+	text := "<b>Итоги недели</b>\n\n" +
+		"<blockquote><b>Топ помощников: <i>Айгерим, Арман, <a href=\"https://t.me/x\">Дана</a> и ещё много-много людей</i></b> — спасибо!</blockquote>\n" +
+		"<pre><code class=\"language-go\">fmt.Println(\"x &lt; y\")\nfmt.Println(\"🚀🚀🚀🚀🚀🚀🚀🚀🚀🚀\")</code></pre>"
+
+	chunks := SplitMessage(text, limit)
+	require.Greater(t, len(chunks), 2)
+	for _, chunk := range chunks {
+		assertValidChunk(t, chunk, limit)
+	}
+
+	// The chunk cut inside <blockquote><b><i> reopens the same tags
+	var reopened bool
+	for _, chunk := range chunks[1:] {
+		if strings.HasPrefix(chunk, "<blockquote><b><i>") {
+			reopened = true
+		}
+	}
+	assert.True(t, reopened, "open tags are reopened in the next chunk: %q", chunks)
+
+	// Nothing is lost apart from whitespace at the cuts
+	var joined strings.Builder
+	for _, chunk := range chunks {
+		joined.WriteString(tagPattern.ReplaceAllString(chunk, ""))
+	}
+	squash := func(s string) string { return strings.Join(strings.Fields(s), "") }
+	assert.Equal(t, squash(tagPattern.ReplaceAllString(text, "")), squash(joined.String()))
+}
+
+func TestSplitMessage_PrefersParagraphs(t *testing.T) {
+	paragraph := strings.Repeat("слово ", 30)
+	text := paragraph + "\n\n" + paragraph + "\n\n" + paragraph
+
+	chunks := SplitMessage(text, 400)
+	require.Len(t, chunks, 2)
+	assert.True(t, strings.HasSuffix(chunks[0], "\n\n"), "the cut is at a paragraph break")
+	for _, chunk := range chunks {
+		assertValidChunk(t, chunk, 400)
+	}
+}
+
+func TestSplitMessage_NeverSplitsEntitiesOrSurrogatePairs(t *testing.T) {
+	// No spaces or line breaks: the text is cut between characters
+	text := strings.Repeat("a&amp;😀👩‍💻", 50)
+
+	chunks := SplitMessage(text, 25)
+	require.Greater(t, len(chunks), 1)
+	for _, chunk := range chunks {
+		assertValidChunk(t, chunk, 25)
+		assert.False(t, strings.HasSuffix(chunk, "&"), "entity is kept whole")
+		assert.False(t, strings.HasSuffix(chunk, "‍"), "ZWJ sequence is kept whole")
+		assert.False(t, strings.HasPrefix(chunk, "💻"), "ZWJ sequence is kept whole")
+	}
+	assert.Equal(t, text, strings.Join(chunks, ""))
+}
+
+func TestSplitMessage_FullSizeMessage(t *testing.T) {
+	var sb strings.Builder
+	for sb.Len() < 30000 {
+		sb.WriteString("<b>Достижение</b> <i>«Помощник недели» &amp; ещё одно</i>\n")
+	}
+
+	chunks := SplitMessage(sb.String(), 0)
+	require.Greater(t, len(chunks), 1)
+	for _, chunk := range chunks {
+		assertValidChunk(t, chunk, MessageLimit)
+	}
+}
+
+func TestSplitPlainText_IgnoresMarkup(t *testing.T) {
+	text := strings.Repeat("<b> ", 40)
+	chunks := SplitPlainText(text, 50)
+	require.Greater(t, len(chunks), 1)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, utf8.RuneCountInString(chunk), 50)
+	}
+}
+
+func TestSendLong_KeyboardOnlyOnTheLastChunk(t *testing.T) {
+	type sent struct {
+		Text        string          `json:"text"`
+		ReplyTo     int64           `json:"reply_to_message_id"`
+		ReplyMarkup json.RawMessage `json:"reply_markup"`
+	}
+	var messages []sent
+	client := newPollingTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body sent
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		messages = append(messages, body)
+		result, _ := json.Marshal(Message{MessageID: int64(len(messages))})
+		_ = json.NewEncoder(w).Encode(APIResponse{OK: true, Result: result})
+	}))
+
+	text := strings.Repeat("<b>Связь</b> с однокурсником\n", 400)
+	out, err := client.SendLong(context.Background(), SendMessageParams{
+		ChatID:           1,
+		Text:             text,
+		ParseMode:        "HTML",
+		ReplyToMessageID: 7,
+		ReplyMarkup:      NewKeyboard().Row(Button("Ещё", "more")).Build(),
+	})
+	require.NoError(t, err)
+	require.Greater(t, len(messages), 1)
+	require.Len(t, out, len(messages))
+
+	for i, m := range messages {
+		assertValidChunk(t, m.Text, MessageLimit)
+		last := i == len(messages)-1
+		assert.Equal(t, last, len(m.ReplyMarkup) > 0, "keyboard on chunk %d", i)
+		assert.Equal(t, i == 0, m.ReplyTo == 7, "reply on chunk %d", i)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
@@ -31,6 +32,9 @@ type InMemoryEventBus struct {
 	closed      bool
 	closeCh     chan struct{}
 	wg          sync.WaitGroup
+
+	// pending counts async handler runs that are queued or running
+	pending atomic.Int64
 }
 
 // InMemoryEventBusConfig contains configuration for InMemoryEventBus.
@@ -167,9 +171,11 @@ func (b *InMemoryEventBus) Publish(event shared.Event) error {
 // executeAsync executes a handler asynchronously using the worker pool.
 func (b *InMemoryEventBus) executeAsync(event shared.Event, handler shared.EventHandler) {
 	b.wg.Add(1)
+	b.pending.Add(1)
 
 	go func() {
 		defer b.wg.Done()
+		defer b.pending.Add(-1)
 
 		// Acquire worker slot
 		select {
@@ -233,6 +239,12 @@ func (b *InMemoryEventBus) Metrics() *EventBusMetrics {
 	return b.metrics
 }
 
+// QueueDepth returns how many async handler runs are waiting for a worker
+// or running. It is always 0 in sync mode.
+func (b *InMemoryEventBus) QueueDepth() int64 {
+	return b.pending.Load()
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // REDIS EVENT BUS
 // ══════════════════════════════════════════════════════════════════════════════
@@ -0,0 +1,38 @@
+package messaging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+type pingEvent struct {
+	shared.BaseEvent
+}
+
+func (pingEvent) Payload() map[string]interface{} { return nil }
+
+func TestInMemoryEventBus_QueueDepth(t *testing.T) {
+	bus := NewInMemoryEventBus(InMemoryEventBusConfig{AsyncMode: true, WorkerPoolSize: 1})
+	defer bus.Close()
+
+	release := make(chan struct{})
+	require.NoError(t, bus.Subscribe(shared.EventSyncCompleted, func(shared.Event) error {
+		<-release
+		return nil
+	}))
+
+	event := pingEvent{shared.NewBaseEvent(shared.EventSyncCompleted, "sync")}
+	require.NoError(t, bus.Publish(event))
+	require.NoError(t, bus.Publish(event))
+
+	// One handler runs, the other waits for the only worker.
+	assert.Equal(t, int64(2), bus.QueueDepth())
+
+	close(release)
+	assert.Eventually(t, func() bool { return bus.QueueDepth() == 0 }, time.Second, time.Millisecond)
+}
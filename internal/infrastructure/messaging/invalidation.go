@@ -0,0 +1,228 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CACHE INVALIDATIONS
+// The worker publishes shared.CacheInvalidation messages; every bot instance
+// subscribes and drops the stale entries of its in-process caches. Pub/Sub
+// delivery is at most once, so the subscriber also refreshes everything
+// periodically and whenever the transport reports lost messages.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// InvalidationChannel is the Pub/Sub channel of cache invalidations.
+const InvalidationChannel = "alem-hub:invalidations"
+
+// InvalidationPublisher publishes cache invalidations. Publishing is best
+// effort: caches still expire on their own, so failures are only logged.
+type InvalidationPublisher struct {
+	client  RedisClient
+	channel string
+	logger  *slog.Logger
+
+	published atomic.Int64
+	failed    atomic.Int64
+}
+
+// NewInvalidationPublisher creates a new InvalidationPublisher.
+func NewInvalidationPublisher(client RedisClient, logger *slog.Logger) *InvalidationPublisher {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &InvalidationPublisher{
+		client:  client,
+		channel: InvalidationChannel,
+		logger:  logger.With("component", "invalidation_publisher"),
+	}
+}
+
+// Publish publishes a message.
+func (p *InvalidationPublisher) Publish(ctx context.Context, msg shared.CacheInvalidation) {
+	if err := p.client.Publish(ctx, p.channel, msg); err != nil {
+		p.failed.Add(1)
+		p.logger.Warn("failed to publish cache invalidation",
+			"kind", msg.Kind,
+			"error", err,
+		)
+		return
+	}
+	p.published.Add(1)
+}
+
+// StudentUpdated publishes that a student's data changed.
+func (p *InvalidationPublisher) StudentUpdated(ctx context.Context, studentID string, telegramID int64) {
+	p.Publish(ctx, shared.NewStudentInvalidation(shared.InvalidationStudentUpdated, studentID, telegramID))
+}
+
+// StudentDeleted publishes that a student left or was merged away.
+func (p *InvalidationPublisher) StudentDeleted(ctx context.Context, studentID string, telegramID int64) {
+	p.Publish(ctx, shared.NewStudentInvalidation(shared.InvalidationStudentDeleted, studentID, telegramID))
+}
+
+// LeaderboardRebuilt publishes that a cohort leaderboard was rebuilt.
+func (p *InvalidationPublisher) LeaderboardRebuilt(ctx context.Context, cohort string) {
+	p.Publish(ctx, shared.NewCohortInvalidation(shared.InvalidationLeaderboardRebuilt, cohort))
+}
+
+// CohortArchived publishes that a cohort was archived.
+func (p *InvalidationPublisher) CohortArchived(ctx context.Context, cohort string) {
+	p.Publish(ctx, shared.NewCohortInvalidation(shared.InvalidationCohortArchived, cohort))
+}
+
+// Metrics returns the number of published and failed messages.
+func (p *InvalidationPublisher) Metrics() (published, failed int64) {
+	return p.published.Load(), p.failed.Load()
+}
+
+// InvalidationSubscriberConfig configures an InvalidationSubscriber.
+type InvalidationSubscriberConfig struct {
+	// RefreshInterval - how often every target is fully refreshed, as a
+	// fallback for lost messages.
+	RefreshInterval time.Duration
+
+	// Logger for structured logging
+	Logger *slog.Logger
+}
+
+// DefaultInvalidationSubscriberConfig returns sensible defaults.
+func DefaultInvalidationSubscriberConfig() InvalidationSubscriberConfig {
+	return InvalidationSubscriberConfig{
+		RefreshInterval: 15 * time.Minute,
+	}
+}
+
+// InvalidationMetrics is a snapshot of subscriber counters.
+type InvalidationMetrics struct {
+	// Received - messages read from the transport.
+	Received int64
+
+	// Applied - messages applied to the targets.
+	Applied int64
+
+	// Dropped - malformed messages and unknown schema versions.
+	Dropped int64
+
+	// FullRefreshes - periodic and loss-triggered full refreshes.
+	FullRefreshes int64
+}
+
+// InvalidationSubscriber applies cache invalidations to in-process caches.
+type InvalidationSubscriber struct {
+	client  RedisClient
+	channel string
+	targets []shared.InvalidationTarget
+	config  InvalidationSubscriberConfig
+	logger  *slog.Logger
+
+	received      atomic.Int64
+	applied       atomic.Int64
+	dropped       atomic.Int64
+	fullRefreshes atomic.Int64
+}
+
+// NewInvalidationSubscriber creates a subscriber that applies invalidations
+// to the given targets.
+func NewInvalidationSubscriber(client RedisClient, config InvalidationSubscriberConfig, targets ...shared.InvalidationTarget) *InvalidationSubscriber {
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = DefaultInvalidationSubscriberConfig().RefreshInterval
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+
+	return &InvalidationSubscriber{
+		client:  client,
+		channel: InvalidationChannel,
+		targets: targets,
+		config:  config,
+		logger:  config.Logger.With("component", "invalidation_subscriber"),
+	}
+}
+
+// Run applies invalidations until ctx is done.
+func (s *InvalidationSubscriber) Run(ctx context.Context) error {
+	messages, err := s.client.Subscribe(ctx, s.channel)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(s.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.refreshAll("periodic")
+		case msg, ok := <-messages:
+			if !ok {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return errors.New("invalidation subscription closed")
+			}
+			s.handle(msg)
+		}
+	}
+}
+
+// handle applies one message from the transport.
+func (s *InvalidationSubscriber) handle(msg RedisMessage) {
+	if msg.Err != nil {
+		if errors.Is(msg.Err, ErrMessagesLost) {
+			s.refreshAll("messages lost")
+			return
+		}
+		s.logger.Warn("invalidation subscription error", "error", msg.Err)
+		return
+	}
+
+	s.received.Add(1)
+
+	var inv shared.CacheInvalidation
+	if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+		s.dropped.Add(1)
+		s.logger.Warn("malformed cache invalidation", "error", err)
+		return
+	}
+	if !inv.Supported() {
+		s.dropped.Add(1)
+		s.logger.Warn("unsupported cache invalidation version", "version", inv.Version)
+		return
+	}
+
+	for _, target := range s.targets {
+		target.Invalidate(inv)
+	}
+	s.applied.Add(1)
+}
+
+// refreshAll drops every entry of every target.
+func (s *InvalidationSubscriber) refreshAll(reason string) {
+	for _, target := range s.targets {
+		target.InvalidateAll()
+	}
+	s.fullRefreshes.Add(1)
+	s.logger.Debug("in-process caches refreshed", "reason", reason)
+}
+
+// Metrics returns a snapshot of the subscriber counters.
+func (s *InvalidationSubscriber) Metrics() InvalidationMetrics {
+	return InvalidationMetrics{
+		Received:      s.received.Load(),
+		Applied:       s.applied.Load(),
+		Dropped:       s.dropped.Load(),
+		FullRefreshes: s.fullRefreshes.Load(),
+	}
+}
@@ -0,0 +1,110 @@
+package messaging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/persistence/projections"
+)
+
+func newTestCardView(t *testing.T) *projections.StudentCardView {
+	t.Helper()
+	view := projections.NewStudentCardView()
+	require.NoError(t, view.UpsertCard(&projections.StudentCard{StudentID: "s1", TelegramID: 101, Cohort: "2025-spring"}))
+	require.NoError(t, view.UpsertCard(&projections.StudentCard{StudentID: "s2", TelegramID: 102, Cohort: "2025-spring"}))
+	require.NoError(t, view.UpsertCard(&projections.StudentCard{StudentID: "s3", TelegramID: 103, Cohort: "2024-fall"}))
+	return view
+}
+
+// startSubscriber runs a subscriber and waits until it listens.
+func startSubscriber(t *testing.T, pubsub *MemoryPubSub, targets ...shared.InvalidationTarget) *InvalidationSubscriber {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	sub := NewInvalidationSubscriber(pubsub, DefaultInvalidationSubscriberConfig(), targets...)
+	go func() { _ = sub.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		pubsub.mu.Lock()
+		defer pubsub.mu.Unlock()
+		return len(pubsub.subs[InvalidationChannel]) > 0
+	}, time.Second, time.Millisecond)
+	return sub
+}
+
+func TestInvalidation_CardViewDropsDeletedStudent(t *testing.T) {
+	pubsub := NewMemoryPubSub(0)
+	view := newTestCardView(t)
+	sub := startSubscriber(t, pubsub, view)
+
+	publisher := NewInvalidationPublisher(pubsub, nil)
+	publisher.StudentDeleted(context.Background(), "s1", 101)
+
+	require.Eventually(t, func() bool { return !view.Exists("s1") }, time.Second, time.Millisecond)
+	_, err := view.GetByTelegramID(context.Background(), 101)
+	assert.Error(t, err)
+	assert.True(t, view.Exists("s2"))
+
+	publisher.CohortArchived(context.Background(), "2025-spring")
+	require.Eventually(t, func() bool { return !view.Exists("s2") }, time.Second, time.Millisecond)
+	assert.True(t, view.Exists("s3"))
+
+	published, failed := publisher.Metrics()
+	assert.Equal(t, int64(2), published)
+	assert.Zero(t, failed)
+	assert.Equal(t, InvalidationMetrics{Received: 2, Applied: 2}, sub.Metrics())
+}
+
+func TestInvalidation_DropsUnknownVersions(t *testing.T) {
+	pubsub := NewMemoryPubSub(0)
+	view := newTestCardView(t)
+	sub := startSubscriber(t, pubsub, view)
+
+	ctx := context.Background()
+	future := shared.NewStudentInvalidation(shared.InvalidationStudentDeleted, "s1", 101)
+	future.Version = shared.CacheInvalidationVersion + 1
+	require.NoError(t, pubsub.Publish(ctx, InvalidationChannel, future))
+	require.NoError(t, pubsub.Publish(ctx, InvalidationChannel, "not json"))
+
+	require.Eventually(t, func() bool { return sub.Metrics().Dropped == 2 }, time.Second, time.Millisecond)
+	assert.Zero(t, sub.Metrics().Applied)
+	assert.True(t, view.Exists("s1"))
+}
+
+func TestInvalidation_LostMessagesRefreshEverything(t *testing.T) {
+	pubsub := NewMemoryPubSub(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	messages, err := pubsub.Subscribe(ctx, InvalidationChannel)
+	require.NoError(t, err)
+
+	// The third message overflows the buffer and is lost
+	publisher := NewInvalidationPublisher(pubsub, nil)
+	publisher.StudentUpdated(ctx, "s1", 101)
+	publisher.StudentUpdated(ctx, "s2", 102)
+	publisher.StudentUpdated(ctx, "s3", 103)
+	assert.NoError(t, (<-messages).Err)
+	assert.NoError(t, (<-messages).Err)
+
+	// The loss is reported ahead of the next message
+	publisher.StudentUpdated(ctx, "s1", 101)
+
+	lost := <-messages
+	assert.ErrorIs(t, lost.Err, ErrMessagesLost)
+
+	view := newTestCardView(t)
+	sub := NewInvalidationSubscriber(pubsub, DefaultInvalidationSubscriberConfig(), view)
+	sub.handle(lost)
+	assert.Zero(t, view.Count())
+	assert.Equal(t, int64(1), sub.Metrics().FullRefreshes)
+
+	sub.handle(<-messages)
+	assert.Equal(t, int64(1), sub.Metrics().Applied)
+}
@@ -0,0 +1,161 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// IN-MEMORY PUB/SUB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ErrMessagesLost is delivered as RedisMessage.Err when messages of a
+// subscription may have been lost (a reconnect or a full buffer), so the
+// subscriber can fall back to a full refresh.
+var ErrMessagesLost = errors.New("pubsub: messages may have been lost")
+
+// MemoryPubSub implements RedisClient in process memory. It connects
+// publishers and subscribers of one process (single-instance deployments
+// and tests) with the same semantics as Redis: no persistence, and a slow
+// subscriber loses messages instead of blocking publishers.
+type MemoryPubSub struct {
+	mu         sync.Mutex
+	subs       map[string][]*memorySubscription
+	bufferSize int
+	closed     bool
+}
+
+type memorySubscription struct {
+	ch     chan RedisMessage
+	lost   bool
+	closed bool
+}
+
+// NewMemoryPubSub creates a new in-memory Pub/Sub. bufferSize is the
+// number of messages a subscriber may lag behind (64 if not positive).
+func NewMemoryPubSub(bufferSize int) *MemoryPubSub {
+	if bufferSize <= 0 {
+		bufferSize = 64
+	}
+	return &MemoryPubSub{
+		subs:       make(map[string][]*memorySubscription),
+		bufferSize: bufferSize,
+	}
+}
+
+// Publish delivers a message to the current subscribers of a channel.
+// Strings and byte slices are sent as is, other values as JSON.
+func (p *MemoryPubSub) Publish(ctx context.Context, channel string, message interface{}) error {
+	var payload string
+	switch m := message.(type) {
+	case string:
+		payload = m
+	case []byte:
+		payload = string(m)
+	default:
+		data, err := json.Marshal(message)
+		if err != nil {
+			return fmt.Errorf("marshal message: %w", err)
+		}
+		payload = string(data)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrEventBusClosed
+	}
+	for _, sub := range p.subs[channel] {
+		sub.deliver(RedisMessage{Channel: channel, Payload: payload})
+	}
+	return nil
+}
+
+// deliver sends a message without blocking. A dropped message is reported
+// with ErrMessagesLost once the subscriber has room again.
+func (s *memorySubscription) deliver(msg RedisMessage) {
+	if s.lost {
+		select {
+		case s.ch <- RedisMessage{Channel: msg.Channel, Err: ErrMessagesLost}:
+			s.lost = false
+		default:
+			return
+		}
+	}
+
+	select {
+	case s.ch <- msg:
+	default:
+		s.lost = true
+	}
+}
+
+// Subscribe subscribes to channels until ctx is done; the returned channel
+// is closed then.
+func (p *MemoryPubSub) Subscribe(ctx context.Context, channels ...string) (<-chan RedisMessage, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrEventBusClosed
+	}
+
+	sub := &memorySubscription{ch: make(chan RedisMessage, p.bufferSize)}
+	for _, channel := range channels {
+		p.subs[channel] = append(p.subs[channel], sub)
+	}
+
+	go func() {
+		<-ctx.Done()
+		p.unsubscribe(sub, channels)
+	}()
+
+	return sub.ch, nil
+}
+
+// unsubscribe removes a subscription and closes its channel.
+func (p *MemoryPubSub) unsubscribe(sub *memorySubscription, channels []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, channel := range channels {
+		subs := p.subs[channel]
+		for i, s := range subs {
+			if s == sub {
+				p.subs[channel] = append(subs[:i:i], subs[i+1:]...)
+				break
+			}
+		}
+	}
+	if !sub.closed {
+		sub.closed = true
+		close(sub.ch)
+	}
+}
+
+// Close closes all subscriptions.
+func (p *MemoryPubSub) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	for _, subs := range p.subs {
+		for _, sub := range subs {
+			if !sub.closed {
+				sub.closed = true
+				close(sub.ch)
+			}
+		}
+	}
+	p.subs = make(map[string][]*memorySubscription)
+	return nil
+}
+
+var _ RedisClient = (*MemoryPubSub)(nil)
@@ -0,0 +1,220 @@
+// Package metrics exposes Prometheus metrics of the bot and the worker.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// REGISTRY
+// Metrics are optional: a nil *Registry accepts every call and records
+// nothing, so components and tests can run without one.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Namespace prefixes every metric name.
+const Namespace = "alem_hub"
+
+// lookupTimeout bounds a LookupGauge read during a scrape.
+const lookupTimeout = 2 * time.Second
+
+// Result label values.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Registry holds the collectors of one process.
+type Registry struct {
+	registry *prometheus.Registry
+
+	httpRequests    *prometheus.HistogramVec
+	telegramUpdates *prometheus.CounterVec
+	jobDuration     *prometheus.HistogramVec
+	jobRuns         *prometheus.CounterVec
+	jobsRunning     *prometheus.GaugeVec
+	jobLastFailure  *prometheus.GaugeVec
+}
+
+// New creates a registry with the Go runtime and process collectors.
+func New() *Registry {
+	r := &Registry{
+		registry: prometheus.NewRegistry(),
+
+		httpRequests: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of HTTP requests by method, route pattern and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+
+		telegramUpdates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "telegram",
+			Name:      "updates_total",
+			Help:      "Processed Telegram updates by command and result.",
+		}, []string{"command", "result"}),
+
+		jobDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "scheduler",
+			Name:      "job_duration_seconds",
+			Help:      "Duration of scheduled job runs by job and result.",
+			Buckets:   []float64{0.1, 0.5, 1, 5, 15, 30, 60, 120, 300, 600, 1800},
+		}, []string{"job", "result"}),
+
+		jobRuns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "scheduler",
+			Name:      "job_runs_total",
+			Help:      "Completed scheduled job runs by job and result.",
+		}, []string{"job", "result"}),
+
+		jobsRunning: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: "scheduler",
+			Name:      "jobs_running",
+			Help:      "Scheduled job runs in progress.",
+		}, []string{"job"}),
+
+		jobLastFailure: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: "scheduler",
+			Name:      "job_last_failure_timestamp_seconds",
+			Help:      "Unix time of the last failed run of a scheduled job.",
+		}, []string{"job"}),
+	}
+
+	r.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		r.httpRequests,
+		r.telegramUpdates,
+		r.jobDuration,
+		r.jobRuns,
+		r.jobsRunning,
+		r.jobLastFailure,
+	)
+
+	return r
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (r *Registry) Handler() http.Handler {
+	if r == nil {
+		return http.NotFoundHandler()
+	}
+	// A failed LookupGauge must not fail the whole scrape.
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
+}
+
+// Gauge registers a gauge whose value is read on every scrape, such as a
+// queue depth. name is prefixed with Namespace.
+func (r *Registry) Gauge(name, help string, value func() float64) {
+	if r == nil {
+		return
+	}
+	r.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      name,
+		Help:      help,
+	}, value))
+}
+
+// LookupGauge registers a gauge read from an external store on every scrape,
+// such as the size of a Redis cache. A failed read leaves the gauge out of
+// that scrape.
+func (r *Registry) LookupGauge(name, help string, lookup func(ctx context.Context) (float64, error)) {
+	if r == nil {
+		return
+	}
+	r.registry.MustRegister(&lookupGauge{
+		desc:   prometheus.NewDesc(prometheus.BuildFQName(Namespace, "", name), help, nil, nil),
+		lookup: lookup,
+	})
+}
+
+// lookupGauge is a gauge whose read can fail.
+type lookupGauge struct {
+	desc   *prometheus.Desc
+	lookup func(ctx context.Context) (float64, error)
+}
+
+func (g *lookupGauge) Describe(ch chan<- *prometheus.Desc) {
+	ch <- g.desc
+}
+
+func (g *lookupGauge) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+
+	value, err := g.lookup(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(g.desc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(g.desc, prometheus.GaugeValue, value)
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RECORDERS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ObserveHTTPRequest records one HTTP request. route is the registered
+// pattern ("/api/v1/students/{id}"), not the raw path, to keep the number
+// of series bounded.
+func (r *Registry) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	if r == nil {
+		return
+	}
+	r.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// RecordTelegramUpdate counts one processed Telegram update. command must
+// come from a bounded set (known commands plus a few fixed labels).
+func (r *Registry) RecordTelegramUpdate(command string, err error) {
+	if r == nil {
+		return
+	}
+	r.telegramUpdates.WithLabelValues(command, result(err)).Inc()
+}
+
+// JobStarted marks a scheduled job run as in progress.
+func (r *Registry) JobStarted(job string) {
+	if r == nil {
+		return
+	}
+	r.jobsRunning.WithLabelValues(job).Inc()
+}
+
+// JobCompleted records a finished scheduled job run started with JobStarted.
+func (r *Registry) JobCompleted(job string, duration time.Duration, err error) {
+	if r == nil {
+		return
+	}
+	r.jobsRunning.WithLabelValues(job).Dec()
+	r.jobRuns.WithLabelValues(job, result(err)).Inc()
+	r.jobDuration.WithLabelValues(job, result(err)).Observe(duration.Seconds())
+}
+
+// JobFailed records the time of a failed scheduled job run.
+func (r *Registry) JobFailed(job string, _ error) {
+	if r == nil {
+		return
+	}
+	r.jobLastFailure.WithLabelValues(job).SetToCurrentTime()
+}
+
+func result(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
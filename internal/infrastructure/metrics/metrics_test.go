@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestRegistry_ExposesRecordedMetrics(t *testing.T) {
+	r := New()
+	r.ObserveHTTPRequest("GET", "/api/v1/students/{id}", 404, 30*time.Millisecond)
+	r.RecordTelegramUpdate("top", nil)
+	r.RecordTelegramUpdate("top", errors.New("boom"))
+	r.JobStarted("sync_all_students")
+	r.JobCompleted("sync_all_students", 2*time.Second, errors.New("alem down"))
+	r.JobFailed("sync_all_students", errors.New("alem down"))
+	r.Gauge("event_bus_queue_depth", "Queued handlers.", func() float64 { return 7 })
+	r.LookupGauge("leaderboard_cache_entries", "Cached entries.", func(context.Context) (float64, error) { return 120, nil })
+
+	body := scrape(t, r)
+	assert.Contains(t, body, `alem_hub_http_request_duration_seconds_count{method="GET",route="/api/v1/students/{id}",status="404"} 1`)
+	assert.Contains(t, body, `alem_hub_telegram_updates_total{command="top",result="success"} 1`)
+	assert.Contains(t, body, `alem_hub_telegram_updates_total{command="top",result="failure"} 1`)
+	assert.Contains(t, body, `alem_hub_scheduler_job_runs_total{job="sync_all_students",result="failure"} 1`)
+	assert.Contains(t, body, `alem_hub_scheduler_job_duration_seconds_sum{job="sync_all_students",result="failure"} 2`)
+	assert.Contains(t, body, `alem_hub_scheduler_jobs_running{job="sync_all_students"} 0`)
+	assert.Contains(t, body, `alem_hub_scheduler_job_last_failure_timestamp_seconds{job="sync_all_students"}`)
+	assert.Contains(t, body, `alem_hub_event_bus_queue_depth 7`)
+	assert.Contains(t, body, `alem_hub_leaderboard_cache_entries 120`)
+	assert.Contains(t, body, `go_goroutines`)
+}
+
+func TestRegistry_FailedLookupSkipsOnlyThatGauge(t *testing.T) {
+	r := New()
+	r.LookupGauge("leaderboard_cache_entries", "Cached entries.", func(context.Context) (float64, error) {
+		return 0, errors.New("redis: connection refused")
+	})
+	r.Gauge("event_bus_queue_depth", "Queued handlers.", func() float64 { return 3 })
+
+	body := scrape(t, r)
+	assert.NotContains(t, body, "alem_hub_leaderboard_cache_entries ")
+	assert.Contains(t, body, "alem_hub_event_bus_queue_depth 3")
+}
+
+func TestRegistry_NilIsDisabled(t *testing.T) {
+	var r *Registry
+
+	assert.NotPanics(t, func() {
+		r.ObserveHTTPRequest("GET", "/", 200, time.Millisecond)
+		r.RecordTelegramUpdate("start", nil)
+		r.JobStarted("job")
+		r.JobCompleted("job", time.Second, nil)
+		r.JobFailed("job", errors.New("boom"))
+		r.Gauge("size", "Size.", func() float64 { return 1 })
+		r.LookupGauge("entries", "Entries.", func(context.Context) (float64, error) { return 1, nil })
+	})
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// AccessTokenRepository implements student.AccessTokenRepository for PostgreSQL.
+type AccessTokenRepository struct {
+	conn *Connection
+}
+
+// NewAccessTokenRepository creates a new AccessTokenRepository.
+func NewAccessTokenRepository(conn *Connection) *AccessTokenRepository {
+	return &AccessTokenRepository{conn: conn}
+}
+
+// accessTokenColumns are the columns scanned by scanAccessToken.
+const accessTokenColumns = `id, student_id, token_hash, hint, scope, created_at, expires_at, last_used_at, revoked_at`
+
+// Create stores a token unless the student already has
+// student.MaxActiveAccessTokens active ones. The student row is locked, so
+// two concurrent /token create cannot both pass the count.
+func (r *AccessTokenRepository) Create(ctx context.Context, token *student.AccessToken) error {
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT 1 FROM students WHERE id = $1 FOR UPDATE`, token.StudentID); err != nil {
+			return fmt.Errorf("failed to lock student: %w", err)
+		}
+
+		var active int
+		err := tx.QueryRow(ctx, `
+			SELECT COUNT(*) FROM access_tokens
+			WHERE student_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		`, token.StudentID, token.CreatedAt.UTC()).Scan(&active)
+		if err != nil {
+			return fmt.Errorf("failed to count access tokens: %w", err)
+		}
+		if active >= student.MaxActiveAccessTokens {
+			return student.ErrAccessTokenLimit
+		}
+
+		err = tx.QueryRow(ctx, `
+			INSERT INTO access_tokens (student_id, token_hash, hint, scope, created_at, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`,
+			token.StudentID, token.Hash, token.Hint, string(token.Scope),
+			token.CreatedAt.UTC(), token.ExpiresAt.UTC(),
+		).Scan(&token.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create access token: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListActive returns the student's active tokens, newest first.
+func (r *AccessTokenRepository) ListActive(ctx context.Context, studentID string, now time.Time) ([]*student.AccessToken, error) {
+	rows, err := r.conn.Query(ctx, `
+		SELECT `+accessTokenColumns+`
+		FROM access_tokens
+		WHERE student_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY created_at DESC
+	`, studentID, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := make([]*student.AccessToken, 0)
+	for rows.Next() {
+		token, err := scanAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate access tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// GetByHash returns a token by its hash, active or not.
+func (r *AccessTokenRepository) GetByHash(ctx context.Context, hash string) (*student.AccessToken, error) {
+	row := r.conn.QueryRow(ctx, `SELECT `+accessTokenColumns+` FROM access_tokens WHERE token_hash = $1`, hash)
+	token, err := scanAccessToken(row)
+	if IsNoRows(err) {
+		return nil, student.ErrAccessTokenNotFound
+	}
+	return token, err
+}
+
+// Revoke revokes an active token of the student.
+func (r *AccessTokenRepository) Revoke(ctx context.Context, studentID, tokenID string, at time.Time) error {
+	tag, err := r.conn.Exec(ctx, `
+		UPDATE access_tokens SET revoked_at = $3
+		WHERE id = $1 AND student_id = $2 AND revoked_at IS NULL
+	`, tokenID, studentID, at.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return student.ErrAccessTokenNotFound
+	}
+	return nil
+}
+
+// TouchLastUsed records when the token was last used.
+func (r *AccessTokenRepository) TouchLastUsed(ctx context.Context, tokenID string, at time.Time) error {
+	if _, err := r.conn.Exec(ctx, `UPDATE access_tokens SET last_used_at = $2 WHERE id = $1`, tokenID, at.UTC()); err != nil {
+		return fmt.Errorf("failed to touch access token: %w", err)
+	}
+	return nil
+}
+
+// scanAccessToken scans a row of accessTokenColumns.
+func scanAccessToken(row pgx.Row) (*student.AccessToken, error) {
+	var (
+		token student.AccessToken
+		scope string
+	)
+	if err := row.Scan(&token.ID, &token.StudentID, &token.Hash, &token.Hint, &scope,
+		&token.CreatedAt, &token.ExpiresAt, &token.LastUsedAt, &token.RevokedAt); err != nil {
+		if IsNoRows(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan access token: %w", err)
+	}
+	token.Scope = student.AccessTokenScope(scope)
+	return &token, nil
+}
@@ -70,6 +70,60 @@ func (r *ActivityRepository) SaveTaskCompletion(ctx context.Context, completion
 	return nil
 }
 
+// ImportTaskCompletions inserts the completions in one statement; records
+// already stored (and duplicates within the batch) are skipped.
+func (r *ActivityRepository) ImportTaskCompletions(ctx context.Context, completions []*activity.TaskCompletion) (int, error) {
+	if len(completions) == 0 {
+		return 0, nil
+	}
+	studentIDs, taskIDs, xp, completedAt := completionColumns(completions)
+
+	tag, err := r.conn.Exec(ctx, `
+		INSERT INTO task_completions (student_id, task_id, xp_earned, completed_at)
+		SELECT * FROM unnest($1::uuid[], $2::varchar[], $3::int[], $4::timestamptz[])
+		ON CONFLICT (student_id, task_id) DO NOTHING
+	`, studentIDs, taskIDs, xp, completedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to import task completions: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// CountNewTaskCompletions counts the distinct completions that are not stored yet.
+func (r *ActivityRepository) CountNewTaskCompletions(ctx context.Context, completions []*activity.TaskCompletion) (int, error) {
+	if len(completions) == 0 {
+		return 0, nil
+	}
+	studentIDs, taskIDs, _, _ := completionColumns(completions)
+
+	var count int
+	err := r.conn.QueryRow(ctx, `
+		SELECT COUNT(*) FROM (
+			SELECT DISTINCT c.student_id, c.task_id
+			FROM unnest($1::uuid[], $2::varchar[]) AS c(student_id, task_id)
+			WHERE NOT EXISTS (
+				SELECT 1 FROM task_completions tc
+				WHERE tc.student_id = c.student_id AND tc.task_id = c.task_id
+			)
+		) missing
+	`, studentIDs, taskIDs).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count new task completions: %w", err)
+	}
+	return count, nil
+}
+
+// completionColumns splits the completions into column arrays for unnest.
+func completionColumns(completions []*activity.TaskCompletion) (studentIDs, taskIDs []string, xp []int, completedAt []time.Time) {
+	for _, c := range completions {
+		studentIDs = append(studentIDs, string(c.StudentID))
+		taskIDs = append(taskIDs, string(c.TaskID))
+		xp = append(xp, c.XPEarned)
+		completedAt = append(completedAt, c.CompletedAt.UTC())
+	}
+	return studentIDs, taskIDs, xp, completedAt
+}
+
 func (r *ActivityRepository) GetTaskCompletion(ctx context.Context, id string) (*activity.TaskCompletion, error) {
 	return nil, errors.New("not implemented")
 }
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+)
+
+// AnnouncementRepository implements notification.AnnouncementQueue for PostgreSQL.
+type AnnouncementRepository struct {
+	conn *Connection
+}
+
+// NewAnnouncementRepository creates a new AnnouncementRepository.
+func NewAnnouncementRepository(conn *Connection) *AnnouncementRepository {
+	return &AnnouncementRepository{conn: conn}
+}
+
+// Enqueue stores an announcement until it is due.
+func (r *AnnouncementRepository) Enqueue(ctx context.Context, a *notification.PendingAnnouncement) error {
+	_, err := r.conn.Exec(ctx, `
+		INSERT INTO pending_announcements (id, chat_id, dedup_key, message, created_at, deliver_after)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, a.ID, a.ChatID, a.DedupKey, a.Message, a.CreatedAt.UTC(), a.DeliverAfter.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to enqueue announcement: %w", err)
+	}
+	return nil
+}
+
+// ListDue returns due announcements, oldest first.
+func (r *AnnouncementRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*notification.PendingAnnouncement, error) {
+	rows, err := r.conn.Query(ctx, `
+		SELECT id, chat_id, dedup_key, message, created_at, deliver_after
+		FROM pending_announcements
+		WHERE deliver_after <= $1
+		ORDER BY created_at, id
+		LIMIT $2
+	`, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due announcements: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*notification.PendingAnnouncement
+	for rows.Next() {
+		a := &notification.PendingAnnouncement{}
+		if err := rows.Scan(&a.ID, &a.ChatID, &a.DedupKey, &a.Message, &a.CreatedAt, &a.DeliverAfter); err != nil {
+			return nil, fmt.Errorf("failed to scan announcement: %w", err)
+		}
+		result = append(result, a)
+	}
+
+	return result, rows.Err()
+}
+
+// Delete removes delivered announcements.
+func (r *AnnouncementRepository) Delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := r.conn.Exec(ctx, `DELETE FROM pending_announcements WHERE id = ANY($1::uuid[])`, ids); err != nil {
+		return fmt.Errorf("failed to delete announcements: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+// AuditLogRepository implements shared.AuditLog for PostgreSQL.
+type AuditLogRepository struct {
+	conn *Connection
+}
+
+// NewAuditLogRepository creates a new AuditLogRepository.
+func NewAuditLogRepository(conn *Connection) *AuditLogRepository {
+	return &AuditLogRepository{conn: conn}
+}
+
+// insertAuditEntryQuery inserts an audit_log row.
+const insertAuditEntryQuery = `
+	INSERT INTO audit_log (actor_id, action, target_id, details, occurred_at)
+	VALUES ($1, $2, $3, $4, $5)
+`
+
+// Record stores an audit entry.
+func (r *AuditLogRepository) Record(ctx context.Context, entry shared.AuditEntry) error {
+	args, err := auditEntryArgs(entry)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.conn.Exec(ctx, insertAuditEntryQuery, args...); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// auditEntryArgs returns the arguments for insertAuditEntryQuery.
+func auditEntryArgs(entry shared.AuditEntry) ([]interface{}, error) {
+	details, err := json.Marshal(entry.Details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit details: %w", err)
+	}
+
+	var targetID *string
+	if entry.TargetID != "" {
+		targetID = &entry.TargetID
+	}
+
+	return []interface{}{
+		entry.ActorID,
+		string(entry.Action),
+		targetID,
+		details,
+		entry.OccurredAt,
+	}, nil
+}
+
+// GetRecent returns the most recent audit entries, newest first.
+func (r *AuditLogRepository) GetRecent(ctx context.Context, limit int) ([]shared.AuditEntry, error) {
+	query := `
+		SELECT actor_id, action, COALESCE(target_id, ''), details, occurred_at
+		FROM audit_log
+		ORDER BY occurred_at DESC
+		LIMIT $1
+	`
+
+	rows, err := r.conn.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []shared.AuditEntry
+	for rows.Next() {
+		var entry shared.AuditEntry
+		var action string
+		var details []byte
+
+		if err := rows.Scan(&entry.ActorID, &action, &entry.TargetID, &details, &entry.OccurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+
+		entry.Action = shared.AuditAction(action)
+		entry.Details = make(map[string]interface{})
+		_ = json.Unmarshal(details, &entry.Details)
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
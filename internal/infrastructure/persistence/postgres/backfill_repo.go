@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/backfill"
+)
+
+// BackfillRepository implements backfill.Repository for PostgreSQL.
+type BackfillRepository struct {
+	conn *Connection
+}
+
+// NewBackfillRepository creates a new BackfillRepository.
+func NewBackfillRepository(conn *Connection) *BackfillRepository {
+	return &BackfillRepository{conn: conn}
+}
+
+const backfillRunColumns = `id, status, dry_run, rate_share, students_total, students_done,
+	COALESCE(last_student_id::text, ''), pages, inserted, active_ms, created_at, updated_at, finished_at`
+
+// CreateRun stores a new run. The partial unique index allows one run in
+// progress at a time.
+func (r *BackfillRepository) CreateRun(ctx context.Context, run *backfill.Run) error {
+	_, err := r.conn.Exec(ctx, `
+		INSERT INTO backfill_runs (id, status, dry_run, rate_share, students_total, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, run.ID, string(run.Status), run.DryRun, run.RateShare, run.StudentsTotal, run.CreatedAt, run.UpdatedAt)
+	if err != nil {
+		if IsUniqueViolation(err) {
+			return backfill.ErrRunActive
+		}
+		return fmt.Errorf("failed to create backfill run: %w", err)
+	}
+	return nil
+}
+
+// GetRun returns a run.
+func (r *BackfillRepository) GetRun(ctx context.Context, id string) (*backfill.Run, error) {
+	run, err := scanBackfillRun(r.conn.QueryRow(ctx, `SELECT `+backfillRunColumns+` FROM backfill_runs WHERE id = $1`, id))
+	if err != nil {
+		if IsNoRows(err) {
+			return nil, backfill.ErrRunNotFound
+		}
+		return nil, fmt.Errorf("failed to get backfill run: %w", err)
+	}
+	return run, nil
+}
+
+// GetActiveRun returns the running or paused run.
+func (r *BackfillRepository) GetActiveRun(ctx context.Context) (*backfill.Run, error) {
+	run, err := scanBackfillRun(r.conn.QueryRow(ctx,
+		`SELECT `+backfillRunColumns+` FROM backfill_runs WHERE status IN ('running', 'paused')`))
+	if err != nil {
+		if IsNoRows(err) {
+			return nil, backfill.ErrRunNotFound
+		}
+		return nil, fmt.Errorf("failed to get active backfill run: %w", err)
+	}
+	return run, nil
+}
+
+// ListRuns returns the latest runs, newest first.
+func (r *BackfillRepository) ListRuns(ctx context.Context, limit int) ([]*backfill.Run, error) {
+	rows, err := r.conn.Query(ctx,
+		`SELECT `+backfillRunColumns+` FROM backfill_runs ORDER BY created_at DESC LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backfill runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*backfill.Run
+	for rows.Next() {
+		run, err := scanBackfillRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan backfill run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// UpdateStatus saves the status if nobody changed it since it was read.
+func (r *BackfillRepository) UpdateStatus(ctx context.Context, run *backfill.Run, from backfill.Status) error {
+	tag, err := r.conn.Exec(ctx, `
+		UPDATE backfill_runs SET status = $2, updated_at = $3, finished_at = $4
+		WHERE id = $1 AND status = $5
+	`, run.ID, string(run.Status), run.UpdatedAt, run.FinishedAt, string(from))
+	if err != nil {
+		return fmt.Errorf("failed to update backfill run status: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := r.GetRun(ctx, run.ID); err != nil {
+			return err
+		}
+		return backfill.ErrInvalidTransition
+	}
+	return nil
+}
+
+// SaveProgress saves the counters of a run without touching its status.
+func (r *BackfillRepository) SaveProgress(ctx context.Context, run *backfill.Run) error {
+	_, err := r.conn.Exec(ctx, `
+		UPDATE backfill_runs SET
+			students_done = $2, last_student_id = NULLIF($3, '')::uuid, pages = $4,
+			inserted = $5, active_ms = $6, updated_at = $7
+		WHERE id = $1
+	`, run.ID, run.StudentsDone, run.LastStudentID, run.Pages, run.Inserted,
+		run.ActiveTime.Milliseconds(), run.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save backfill progress: %w", err)
+	}
+	return nil
+}
+
+// GetCursor returns the cursor of a student, empty if none is stored.
+func (r *BackfillRepository) GetCursor(ctx context.Context, runID, studentID string) (*backfill.Cursor, error) {
+	cursor := &backfill.Cursor{RunID: runID, StudentID: studentID}
+	err := r.conn.QueryRow(ctx, `
+		SELECT page, last_completed_at, done, inserted
+		FROM backfill_progress
+		WHERE run_id = $1 AND student_id = $2
+	`, runID, studentID).Scan(&cursor.Page, &cursor.LastCompletedAt, &cursor.Done, &cursor.Inserted)
+	if err != nil && !IsNoRows(err) {
+		return nil, fmt.Errorf("failed to get backfill cursor: %w", err)
+	}
+	return cursor, nil
+}
+
+// SaveCursor upserts the cursor of a student.
+func (r *BackfillRepository) SaveCursor(ctx context.Context, cursor *backfill.Cursor) error {
+	_, err := r.conn.Exec(ctx, `
+		INSERT INTO backfill_progress (run_id, student_id, page, last_completed_at, done, inserted, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (run_id, student_id) DO UPDATE SET
+			page = EXCLUDED.page,
+			last_completed_at = EXCLUDED.last_completed_at,
+			done = EXCLUDED.done,
+			inserted = EXCLUDED.inserted,
+			updated_at = NOW()
+	`, cursor.RunID, cursor.StudentID, cursor.Page, cursor.LastCompletedAt, cursor.Done, cursor.Inserted)
+	if err != nil {
+		return fmt.Errorf("failed to save backfill cursor: %w", err)
+	}
+	return nil
+}
+
+// CountStudents returns the number of students.
+func (r *BackfillRepository) CountStudents(ctx context.Context) (int, error) {
+	var count int
+	if err := r.conn.QueryRow(ctx, `SELECT COUNT(*) FROM students`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count students: %w", err)
+	}
+	return count, nil
+}
+
+// NextStudents returns student IDs after afterID in ascending order.
+func (r *BackfillRepository) NextStudents(ctx context.Context, afterID string, limit int) ([]string, error) {
+	rows, err := r.conn.Query(ctx, `
+		SELECT id::text FROM students
+		WHERE $1 = '' OR id > NULLIF($1, '')::uuid
+		ORDER BY id
+		LIMIT $2
+	`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list students for backfill: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan student id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func scanBackfillRun(row pgx.Row) (*backfill.Run, error) {
+	var run backfill.Run
+	var status string
+	var activeMS int64
+	err := row.Scan(&run.ID, &status, &run.DryRun, &run.RateShare, &run.StudentsTotal, &run.StudentsDone,
+		&run.LastStudentID, &run.Pages, &run.Inserted, &activeMS, &run.CreatedAt, &run.UpdatedAt, &run.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	run.Status = backfill.Status(status)
+	run.ActiveTime = time.Duration(activeMS) * time.Millisecond
+	return &run, nil
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// botStateUpdateOffset is the key of the Telegram polling offset.
+const botStateUpdateOffset = "telegram_update_offset"
+
+// BotStateRepository stores bot runtime state that must survive restarts.
+// It implements telegram.OffsetStore.
+type BotStateRepository struct {
+	conn *Connection
+}
+
+// NewBotStateRepository creates a new BotStateRepository.
+func NewBotStateRepository(conn *Connection) *BotStateRepository {
+	return &BotStateRepository{conn: conn}
+}
+
+// LoadOffset returns the saved polling offset, or 0 if none was saved yet.
+func (r *BotStateRepository) LoadOffset(ctx context.Context) (int64, error) {
+	var value string
+	err := r.conn.QueryRow(ctx, `SELECT value FROM bot_state WHERE key = $1`, botStateUpdateOffset).Scan(&value)
+	if err != nil {
+		if IsNoRows(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to load update offset: %w", err)
+	}
+
+	offset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse update offset %q: %w", value, err)
+	}
+	return offset, nil
+}
+
+// SaveOffset stores the polling offset.
+func (r *BotStateRepository) SaveOffset(ctx context.Context, offset int64) error {
+	query := `
+		INSERT INTO bot_state (key, value, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()
+	`
+
+	if _, err := r.conn.Exec(ctx, query, botStateUpdateOffset, strconv.FormatInt(offset, 10)); err != nil {
+		return fmt.Errorf("failed to save update offset: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/analytics"
+)
+
+// CohortHealthRepository implements analytics.CohortHealthRepository for PostgreSQL.
+type CohortHealthRepository struct {
+	conn *Connection
+}
+
+// NewCohortHealthRepository creates a new CohortHealthRepository.
+func NewCohortHealthRepository(conn *Connection) *CohortHealthRepository {
+	return &CohortHealthRepository{conn: conn}
+}
+
+// GetActivity returns the cohort's weekly activity figures. Days are
+// daily_grinds dates, so from, to and prevFrom are truncated to UTC days.
+func (r *CohortHealthRepository) GetActivity(ctx context.Context, cohort string, prevFrom, from, to time.Time) (analytics.CohortActivity, error) {
+	query := `
+		WITH cohort_grinds AS (
+			SELECT g.student_id, g.date, g.xp_gained, g.sessions_count, g.tasks_completed
+			FROM daily_grinds g
+			JOIN students s ON s.id = g.student_id
+			WHERE s.cohort = $1 AND g.date >= $2::date AND g.date < $4::date
+		)
+		SELECT
+			(SELECT COUNT(*) FROM students WHERE cohort = $1 AND status = 'active'),
+			(SELECT COUNT(DISTINCT student_id) FROM cohort_grinds
+				WHERE date >= $3::date AND (xp_gained > 0 OR sessions_count > 0 OR tasks_completed > 0)),
+			(SELECT COUNT(*) FROM students WHERE cohort = $1 AND joined_at >= $5 AND joined_at < $6),
+			(SELECT COALESCE(SUM(xp_gained), 0) FROM cohort_grinds WHERE date >= $3::date),
+			(SELECT COALESCE(SUM(xp_gained), 0) FROM cohort_grinds WHERE date < $3::date),
+			(SELECT COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY st.current_streak), 0)
+				FROM streaks st
+				JOIN students s ON s.id = st.student_id
+				WHERE s.cohort = $1 AND s.status = 'active')
+	`
+
+	var activity analytics.CohortActivity
+	err := r.conn.QueryRow(ctx, query,
+		cohort, prevFrom.UTC().Format("2006-01-02"), from.UTC().Format("2006-01-02"), to.UTC().Format("2006-01-02"),
+		from, to,
+	).Scan(
+		&activity.TotalStudents,
+		&activity.ActiveStudents,
+		&activity.NewStudents,
+		&activity.XPGained,
+		&activity.PreviousXPGained,
+		&activity.MedianStreak,
+	)
+	if err != nil {
+		return analytics.CohortActivity{}, fmt.Errorf("failed to get cohort activity: %w", err)
+	}
+	return activity, nil
+}
+
+// ListInactive returns active-status students not seen since the given time.
+func (r *CohortHealthRepository) ListInactive(ctx context.Context, cohort string, since time.Time, limit int) ([]analytics.InactiveStudent, int, error) {
+	query := `
+		SELECT display_name, last_seen_at, COUNT(*) OVER ()
+		FROM students
+		WHERE cohort = $1 AND status = 'active' AND last_seen_at < $2
+		ORDER BY last_seen_at, display_name
+		LIMIT $3
+	`
+
+	rows, err := r.conn.Query(ctx, query, cohort, since, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list inactive students: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		students []analytics.InactiveStudent
+		total    int
+	)
+	for rows.Next() {
+		var s analytics.InactiveStudent
+		if err := rows.Scan(&s.DisplayName, &s.LastSeenAt, &total); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan inactive student: %w", err)
+		}
+		students = append(students, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// LIMIT 0 returns no rows and therefore no total
+	if limit <= 0 {
+		if err := r.conn.QueryRow(ctx,
+			`SELECT COUNT(*) FROM students WHERE cohort = $1 AND status = 'active' AND last_seen_at < $2`,
+			cohort, since,
+		).Scan(&total); err != nil {
+			return nil, 0, fmt.Errorf("failed to count inactive students: %w", err)
+		}
+	}
+
+	return students, total, nil
+}
+
+// CountStaleHelpRequests counts unresolved help requests from the cohort
+// created before the given time.
+func (r *CohortHealthRepository) CountStaleHelpRequests(ctx context.Context, cohort string, olderThan time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM help_requests h
+		JOIN students s ON s.id = h.requester_id
+		WHERE s.cohort = $1
+		  AND h.status IN ('open', 'matched', 'in_progress')
+		  AND h.created_at < $2
+	`
+
+	var count int
+	if err := r.conn.QueryRow(ctx, query, cohort, olderThan).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count stale help requests: %w", err)
+	}
+	return count, nil
+}
+
+// TopClimbers compares each student's rank in the last snapshot before from
+// with the last snapshot before to. Students without a snapshot before from
+// (new this week) are not climbers.
+func (r *CohortHealthRepository) TopClimbers(ctx context.Context, cohort string, from, to time.Time, limit int) ([]analytics.RankClimber, error) {
+	query := `
+		WITH cohort_ranks AS (
+			SELECT rh.student_id, rh.rank, rh.snapshot_at
+			FROM rank_history rh
+			JOIN students s ON s.id = rh.student_id
+			WHERE s.cohort = $1 AND s.status = 'active' AND rh.snapshot_at < $3
+		), week_start AS (
+			SELECT DISTINCT ON (student_id) student_id, rank
+			FROM cohort_ranks
+			WHERE snapshot_at < $2
+			ORDER BY student_id, snapshot_at DESC
+		), week_end AS (
+			SELECT DISTINCT ON (student_id) student_id, rank
+			FROM cohort_ranks
+			ORDER BY student_id, snapshot_at DESC
+		)
+		SELECT s.display_name, ws.rank, we.rank
+		FROM week_start ws
+		JOIN week_end we ON we.student_id = ws.student_id
+		JOIN students s ON s.id = ws.student_id
+		WHERE we.rank < ws.rank
+		ORDER BY ws.rank - we.rank DESC, we.rank
+		LIMIT $4
+	`
+
+	rows, err := r.conn.Query(ctx, query, cohort, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top climbers: %w", err)
+	}
+	defer rows.Close()
+
+	var climbers []analytics.RankClimber
+	for rows.Next() {
+		var c analytics.RankClimber
+		if err := rows.Scan(&c.DisplayName, &c.OldRank, &c.NewRank); err != nil {
+			return nil, fmt.Errorf("failed to scan climber: %w", err)
+		}
+		climbers = append(climbers, c)
+	}
+
+	return climbers, rows.Err()
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
+)
+
+// CohortSettingsRepository implements settings.Repository for PostgreSQL.
+type CohortSettingsRepository struct {
+	conn *Connection
+}
+
+// NewCohortSettingsRepository creates a new CohortSettingsRepository.
+func NewCohortSettingsRepository(conn *Connection) *CohortSettingsRepository {
+	return &CohortSettingsRepository{conn: conn}
+}
+
+// GetByCohort returns all overridden settings of a cohort.
+func (r *CohortSettingsRepository) GetByCohort(ctx context.Context, cohort string) (settings.Values, error) {
+	rows, err := r.conn.Query(ctx, `SELECT key, value FROM cohort_settings WHERE cohort = $1`, cohort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cohort settings: %w", err)
+	}
+	defer rows.Close()
+
+	values := make(settings.Values)
+	for rows.Next() {
+		var key string
+		var value []byte
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan cohort setting: %w", err)
+		}
+		values[settings.Key(key)] = json.RawMessage(value)
+	}
+
+	return values, rows.Err()
+}
+
+// Set stores a cohort setting, replacing the previous value.
+func (r *CohortSettingsRepository) Set(ctx context.Context, cohort string, key settings.Key, value json.RawMessage) error {
+	query := `
+		INSERT INTO cohort_settings (cohort, key, value, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (cohort, key) DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()
+	`
+
+	if _, err := r.conn.Exec(ctx, query, cohort, string(key), []byte(value)); err != nil {
+		return fmt.Errorf("failed to set cohort setting: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a cohort override.
+func (r *CohortSettingsRepository) Delete(ctx context.Context, cohort string, key settings.Key) error {
+	if _, err := r.conn.Exec(ctx, `DELETE FROM cohort_settings WHERE cohort = $1 AND key = $2`, cohort, string(key)); err != nil {
+		return fmt.Errorf("failed to delete cohort setting: %w", err)
+	}
+	return nil
+}
@@ -8,7 +8,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -27,6 +32,9 @@ var (
 	// ErrMigrationFailed indicates a migration failure.
 	ErrMigrationFailed = errors.New("postgres: migration failed")
 
+	// ErrSchemaOutdated indicates the database schema is older than the build requires.
+	ErrSchemaOutdated = errors.New("postgres: schema is outdated")
+
 	// ErrTransactionFailed indicates a transaction failure.
 	ErrTransactionFailed = errors.New("postgres: transaction failed")
 
@@ -428,6 +436,18 @@ func (c *Connection) QueryRow(ctx context.Context, sql string, args ...interface
 	return c.pool.QueryRow(ctx, sql, args...)
 }
 
+// execBatch sends a batch of statements within tx and checks every result.
+func execBatch(ctx context.Context, tx pgx.Tx, batch *pgx.Batch) error {
+	br := tx.SendBatch(ctx, batch)
+	for i := 0; i < batch.Len(); i++ {
+		if _, err := br.Exec(); err != nil {
+			_ = br.Close()
+			return err
+		}
+	}
+	return br.Close()
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // MIGRATION SUPPORT
 // ══════════════════════════════════════════════════════════════════════════════
@@ -445,39 +465,192 @@ type Migration struct {
 // Migrator handles database migrations.
 type Migrator struct {
 	conn       *Connection
+	db         migrationDB
 	migrations []Migration
 	tableName  string
+	logger     *slog.Logger
+
+	// maxAttempts and retryBackoff bound retries of transient failures.
+	maxAttempts  int
+	retryBackoff time.Duration
+}
+
+// migrationDB is the storage the Migrator applies migrations to.
+// Implemented by connMigrationDB; tests substitute a fake.
+type migrationDB interface {
+	ensureTable(ctx context.Context) error
+	applied(ctx context.Context) (map[int]time.Time, error)
+	apply(ctx context.Context, mig Migration) error
 }
 
 // NewMigrator creates a new migrator with embedded migrations.
 func NewMigrator(conn *Connection) *Migrator {
-	return &Migrator{
-		conn:       conn,
-		migrations: GetMigrations(),
-		tableName:  "schema_migrations",
-	}
+	return NewMigratorWithMigrations(conn, GetMigrations())
 }
 
 // NewMigratorWithMigrations creates a migrator with custom migrations.
 func NewMigratorWithMigrations(conn *Connection, migrations []Migration) *Migrator {
+	const tableName = "schema_migrations"
 	return &Migrator{
-		conn:       conn,
-		migrations: migrations,
-		tableName:  "schema_migrations",
+		conn:         conn,
+		db:           &connMigrationDB{conn: conn, tableName: tableName},
+		migrations:   migrations,
+		tableName:    tableName,
+		logger:       slog.Default(),
+		maxAttempts:  3,
+		retryBackoff: 2 * time.Second,
+	}
+}
+
+// WithLogger sets the logger used for per-migration timing and retries.
+func (m *Migrator) WithLogger(logger *slog.Logger) *Migrator {
+	if logger != nil {
+		m.logger = logger
 	}
+	return m
 }
 
 // EnsureMigrationTable creates the migration tracking table if it doesn't exist.
 func (m *Migrator) EnsureMigrationTable(ctx context.Context) error {
+	return m.db.ensureTable(ctx)
+}
+
+// GetAppliedMigrations returns all applied migrations.
+func (m *Migrator) GetAppliedMigrations(ctx context.Context) (map[int]time.Time, error) {
+	return m.db.applied(ctx)
+}
+
+// Migrate applies all pending migrations. Transient failures (dropped
+// connections, serialization failures, lock timeouts) are retried up to
+// maxAttempts times per migration with exponential backoff; anything else
+// fails immediately.
+func (m *Migrator) Migrate(ctx context.Context) error {
+	if err := m.retry(ctx, "ensure migrations table", func() error {
+		return m.EnsureMigrationTable(ctx)
+	}); err != nil {
+		return err
+	}
+
+	var applied map[int]time.Time
+	if err := m.retry(ctx, "load applied migrations", func() error {
+		var err error
+		applied, err = m.GetAppliedMigrations(ctx)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	for _, mig := range m.migrations {
+		if _, isApplied := applied[mig.Version]; isApplied {
+			continue
+		}
+
+		if mig.UpSQL == "" {
+			return fmt.Errorf("%w: missing up SQL for migration %d", ErrMigrationFailed, mig.Version)
+		}
+
+		start := time.Now()
+		err := m.retry(ctx, fmt.Sprintf("migration %d", mig.Version), func() error {
+			err := m.db.apply(ctx, mig)
+			if err == nil || !IsTransient(err) {
+				return err
+			}
+			// A connection dropped during COMMIT may still have applied
+			// the migration; check before running it again.
+			if now, checkErr := m.GetAppliedMigrations(ctx); checkErr == nil {
+				if _, ok := now[mig.Version]; ok {
+					return nil
+				}
+			}
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("%w: version %d: %v", ErrMigrationFailed, mig.Version, err)
+		}
+
+		m.logger.Info("migration applied",
+			"version", mig.Version,
+			"name", mig.Name,
+			"duration", time.Since(start).String(),
+		)
+	}
+
+	return nil
+}
+
+// retry runs fn until it succeeds, fails with a non-transient error or
+// runs out of attempts.
+func (m *Migrator) retry(ctx context.Context, op string, fn func() error) error {
+	backoff := m.retryBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !IsTransient(err) || attempt >= m.maxAttempts {
+			return err
+		}
+
+		m.logger.Warn("transient migration error, retrying",
+			"op", op,
+			"attempt", attempt,
+			"backoff", backoff.String(),
+			"error", err,
+		)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// CheckSchemaVersion refuses to start against a schema older than
+// MinSchemaVersion, e.g. when migrations are disabled and the process that
+// owns them has not deployed yet.
+func (m *Migrator) CheckSchemaVersion(ctx context.Context) error {
+	if err := m.EnsureMigrationTable(ctx); err != nil {
+		return err
+	}
+
+	applied, err := m.GetAppliedMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := 0
+	for version := range applied {
+		if version > current {
+			current = version
+		}
+	}
+
+	if current < MinSchemaVersion {
+		return fmt.Errorf(
+			"%w: database schema is at version %d, this build requires at least %d; "+
+				"run migrations (RUN_MIGRATIONS=true on this process or deploy the process that owns them) before starting",
+			ErrSchemaOutdated, current, MinSchemaVersion,
+		)
+	}
+
+	return nil
+}
+
+// connMigrationDB stores migrations in a PostgreSQL table.
+type connMigrationDB struct {
+	conn      *Connection
+	tableName string
+}
+
+func (d *connMigrationDB) ensureTable(ctx context.Context) error {
 	query := fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS %s (
 			version INTEGER PRIMARY KEY,
 			name TEXT NOT NULL,
 			applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
 		)
-	`, m.tableName)
+	`, d.tableName)
 
-	_, err := m.conn.Exec(ctx, query)
+	_, err := d.conn.Exec(ctx, query)
 	if err != nil {
 		return fmt.Errorf("failed to create migrations table: %w", err)
 	}
@@ -485,11 +658,10 @@ func (m *Migrator) EnsureMigrationTable(ctx context.Context) error {
 	return nil
 }
 
-// GetAppliedMigrations returns all applied migrations.
-func (m *Migrator) GetAppliedMigrations(ctx context.Context) (map[int]time.Time, error) {
-	query := fmt.Sprintf("SELECT version, applied_at FROM %s ORDER BY version", m.tableName)
+func (d *connMigrationDB) applied(ctx context.Context) (map[int]time.Time, error) {
+	query := fmt.Sprintf("SELECT version, applied_at FROM %s ORDER BY version", d.tableName)
 
-	rows, err := m.conn.Query(ctx, query)
+	rows, err := d.conn.Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
 	}
@@ -510,47 +682,22 @@ func (m *Migrator) GetAppliedMigrations(ctx context.Context) (map[int]time.Time,
 	return applied, rows.Err()
 }
 
-// Migrate applies all pending migrations.
-func (m *Migrator) Migrate(ctx context.Context) error {
-	if err := m.EnsureMigrationTable(ctx); err != nil {
-		return err
-	}
-
-	applied, err := m.GetAppliedMigrations(ctx)
-	if err != nil {
-		return err
-	}
-
-	for _, mig := range m.migrations {
-		if _, isApplied := applied[mig.Version]; isApplied {
-			continue
+// apply runs the migration and records it in one transaction.
+func (d *connMigrationDB) apply(ctx context.Context, mig Migration) error {
+	return d.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		// Execute migration SQL
+		if _, err := tx.Exec(ctx, mig.UpSQL); err != nil {
+			return fmt.Errorf("failed to execute migration %d: %w", mig.Version, err)
 		}
 
-		if mig.UpSQL == "" {
-			return fmt.Errorf("%w: missing up SQL for migration %d", ErrMigrationFailed, mig.Version)
-		}
-
-		// Apply migration in transaction
-		err := m.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
-			// Execute migration SQL
-			if _, err := tx.Exec(ctx, mig.UpSQL); err != nil {
-				return fmt.Errorf("failed to execute migration %d: %w", mig.Version, err)
-			}
-
-			// Record migration
-			insertQuery := fmt.Sprintf(
-				"INSERT INTO %s (version, name) VALUES ($1, $2)",
-				m.tableName,
-			)
-			_, err := tx.Exec(ctx, insertQuery, mig.Version, mig.Name)
-			return err
-		})
-		if err != nil {
-			return fmt.Errorf("%w: version %d: %v", ErrMigrationFailed, mig.Version, err)
-		}
-	}
-
-	return nil
+		// Record migration
+		insertQuery := fmt.Sprintf(
+			"INSERT INTO %s (version, name) VALUES ($1, $2)",
+			d.tableName,
+		)
+		_, err := tx.Exec(ctx, insertQuery, mig.Version, mig.Name)
+		return err
+	})
 }
 
 // Rollback rolls back the last applied migration.
@@ -658,6 +805,38 @@ func IsNotNullViolation(err error) bool {
 	return false
 }
 
+// IsTransient reports whether err is worth retrying: a dropped or refused
+// connection, a serialization failure or deadlock, a lock timeout, or the
+// server shutting down. Context cancellation is never transient.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case strings.HasPrefix(pgErr.Code, "08"): // connection_exception
+			return true
+		case pgErr.Code == "40001", // serialization_failure
+			pgErr.Code == "40P01", // deadlock_detected
+			pgErr.Code == "55P03", // lock_not_available (lock_timeout)
+			pgErr.Code == "57P01", // admin_shutdown
+			pgErr.Code == "57P03": // cannot_connect_now
+			return true
+		}
+		return false
+	}
+
+	if pgconn.SafeToRetry(err) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
 // IsNoRows checks if the error is a "no rows" error.
 func IsNoRows(err error) bool {
 	return errors.Is(err, pgx.ErrNoRows)
@@ -667,6 +846,12 @@ func IsNoRows(err error) bool {
 // EMBEDDED MIGRATIONS
 // ══════════════════════════════════════════════════════════════════════════════
 
+// MinSchemaVersion is the oldest schema version this build can run against.
+// Every migration is read or written by the code it ships with, so it is
+// the latest one; TestMinSchemaVersionCoversMigrations fails until it is
+// bumped together with a new migration.
+const MinSchemaVersion = 45
+
 // GetMigrations returns all embedded migrations.
 func GetMigrations() []Migration {
 	return []Migration{
@@ -688,5 +873,257 @@ func GetMigrations() []Migration {
 			UpSQL:   migration003Up,
 			DownSQL: migration003Down,
 		},
+		{
+			Version: 4,
+			Name:    "create_audit_log",
+			UpSQL:   migration004Up,
+			DownSQL: migration004Down,
+		},
+		{
+			Version: 5,
+			Name:    "create_notifications",
+			UpSQL:   migration005Up,
+			DownSQL: migration005Down,
+		},
+		{
+			Version: 6,
+			Name:    "create_command_usage",
+			UpSQL:   migration006Up,
+			DownSQL: migration006Down,
+		},
+		{
+			Version: 7,
+			Name:    "create_cohort_settings",
+			UpSQL:   migration007Up,
+			DownSQL: migration007Down,
+		},
+		{
+			Version: 8,
+			Name:    "help_request_response_times",
+			UpSQL:   migration008Up,
+			DownSQL: migration008Down,
+		},
+		{
+			Version: 9,
+			Name:    "endorsement_reminders",
+			UpSQL:   migration009Up,
+			DownSQL: migration009Down,
+		},
+		{
+			Version: 10,
+			Name:    "create_saga_executions",
+			UpSQL:   migration010Up,
+			DownSQL: migration010Down,
+		},
+		{
+			Version: 11,
+			Name:    "create_weekly_goals",
+			UpSQL:   migration011Up,
+			DownSQL: migration011Down,
+		},
+		{
+			Version: 12,
+			Name:    "create_webhook_subscriptions",
+			UpSQL:   migration012Up,
+			DownSQL: migration012Down,
+		},
+		{
+			Version: 13,
+			Name:    "add_student_mute",
+			UpSQL:   migration013Up,
+			DownSQL: migration013Down,
+		},
+		{
+			Version: 14,
+			Name:    "create_task_difficulty",
+			UpSQL:   migration014Up,
+			DownSQL: migration014Down,
+		},
+		{
+			Version: 15,
+			Name:    "create_bot_state",
+			UpSQL:   migration015Up,
+			DownSQL: migration015Down,
+		},
+		{
+			Version: 16,
+			Name:    "create_events",
+			UpSQL:   migration016Up,
+			DownSQL: migration016Down,
+		},
+		{
+			Version: 17,
+			Name:    "create_personal_bests",
+			UpSQL:   migration017Up,
+			DownSQL: migration017Down,
+		},
+		{
+			Version: 18,
+			Name:    "add_last_digest_date",
+			UpSQL:   migration018Up,
+			DownSQL: migration018Down,
+		},
+		{
+			Version: 19,
+			Name:    "create_help_feedback",
+			UpSQL:   migration019Up,
+			DownSQL: migration019Down,
+		},
+		{
+			Version: 20,
+			Name:    "create_trigger_rules",
+			UpSQL:   migration020Up,
+			DownSQL: migration020Down,
+		},
+		{
+			Version: 21,
+			Name:    "create_feature_flags",
+			UpSQL:   migration021Up,
+			DownSQL: migration021Down,
+		},
+		{
+			Version: 22,
+			Name:    "create_xp_flags",
+			UpSQL:   migration022Up,
+			DownSQL: migration022Down,
+		},
+		{
+			Version: 23,
+			Name:    "add_streak_milestone_reached",
+			UpSQL:   migration023Up,
+			DownSQL: migration023Down,
+		},
+		{
+			Version: 24,
+			Name:    "create_student_reports",
+			UpSQL:   migration024Up,
+			DownSQL: migration024Down,
+		},
+		{
+			Version: 25,
+			Name:    "create_focus_sessions",
+			UpSQL:   migration025Up,
+			DownSQL: migration025Down,
+		},
+		{
+			Version: 26,
+			Name:    "create_xp_history_monthly",
+			UpSQL:   migration026Up,
+			DownSQL: migration026Down,
+		},
+		{
+			Version: 27,
+			Name:    "create_pending_announcements",
+			UpSQL:   migration027Up,
+			DownSQL: migration027Down,
+		},
+		{
+			Version: 28,
+			Name:    "index_help_requests_created_task",
+			UpSQL:   migration028Up,
+			DownSQL: migration028Down,
+		},
+		{
+			Version: 29,
+			Name:    "add_student_alem_login",
+			UpSQL:   migration029Up,
+			DownSQL: migration029Down,
+		},
+		{
+			Version: 30,
+			Name:    "create_dry_run_outbox",
+			UpSQL:   migration030Up,
+			DownSQL: migration030Down,
+		},
+		{
+			Version: 31,
+			Name:    "cluster_help_requests",
+			UpSQL:   migration031Up,
+			DownSQL: migration031Down,
+		},
+		{
+			Version: 32,
+			Name:    "create_helper_streaks",
+			UpSQL:   migration032Up,
+			DownSQL: migration032Down,
+		},
+		{
+			Version: 33,
+			Name:    "add_student_timezone",
+			UpSQL:   migration033Up,
+			DownSQL: migration033Down,
+		},
+		{
+			Version: 34,
+			Name:    "create_help_snoozes",
+			UpSQL:   migration034Up,
+			DownSQL: migration034Down,
+		},
+		{
+			Version: 35,
+			Name:    "create_feed_items",
+			UpSQL:   migration035Up,
+			DownSQL: migration035Down,
+		},
+		{
+			Version: 36,
+			Name:    "create_access_tokens",
+			UpSQL:   migration036Up,
+			DownSQL: migration036Down,
+		},
+		{
+			Version: 37,
+			Name:    "create_content_blocklist",
+			UpSQL:   migration037Up,
+			DownSQL: migration037Down,
+		},
+		{
+			Version: 38,
+			Name:    "track_digest_deliveries",
+			UpSQL:   migration038Up,
+			DownSQL: migration038Down,
+		},
+		{
+			Version: 39,
+			Name:    "create_help_request_members",
+			UpSQL:   migration039Up,
+			DownSQL: migration039Down,
+		},
+		{
+			Version: 40,
+			Name:    "create_experiments",
+			UpSQL:   migration040Up,
+			DownSQL: migration040Down,
+		},
+		{
+			Version: 41,
+			Name:    "create_backfill_progress",
+			UpSQL:   migration041Up,
+			DownSQL: migration041Down,
+		},
+		{
+			Version: 42,
+			Name:    "create_sync_runs",
+			UpSQL:   migration042Up,
+			DownSQL: migration042Down,
+		},
+		{
+			Version: 43,
+			Name:    "extend_social_tables",
+			UpSQL:   migration043Up,
+			DownSQL: migration043Down,
+		},
+		{
+			Version: 44,
+			Name:    "unique_open_help_request_per_task",
+			UpSQL:   migration044Up,
+			DownSQL: migration044Down,
+		},
+		{
+			Version: 45,
+			Name:    "add_help_request_matched_helpers",
+			UpSQL:   migration045Up,
+			DownSQL: migration045Down,
+		},
 	}
 }
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ContentBlocklistRepository implements social.BlockedPhraseRepository for PostgreSQL.
+type ContentBlocklistRepository struct {
+	conn *Connection
+}
+
+// NewContentBlocklistRepository creates a new ContentBlocklistRepository.
+func NewContentBlocklistRepository(conn *Connection) *ContentBlocklistRepository {
+	return &ContentBlocklistRepository{conn: conn}
+}
+
+// List returns all phrases ordered alphabetically.
+func (r *ContentBlocklistRepository) List(ctx context.Context) ([]string, error) {
+	rows, err := r.conn.Query(ctx, `SELECT phrase FROM content_blocklist ORDER BY phrase`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query content blocklist: %w", err)
+	}
+	defer rows.Close()
+
+	phrases := make([]string, 0)
+	for rows.Next() {
+		var phrase string
+		if err := rows.Scan(&phrase); err != nil {
+			return nil, fmt.Errorf("failed to scan blocked phrase: %w", err)
+		}
+		phrases = append(phrases, phrase)
+	}
+
+	return phrases, rows.Err()
+}
+
+// Replace replaces the whole blocklist in one transaction.
+func (r *ContentBlocklistRepository) Replace(ctx context.Context, phrases []string) error {
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM content_blocklist`); err != nil {
+			return fmt.Errorf("failed to clear content blocklist: %w", err)
+		}
+		if len(phrases) == 0 {
+			return nil
+		}
+
+		_, err := tx.Exec(ctx, `
+			INSERT INTO content_blocklist (phrase)
+			SELECT UNNEST($1::text[])
+			ON CONFLICT (phrase) DO NOTHING
+		`, phrases)
+		if err != nil {
+			return fmt.Errorf("failed to save content blocklist: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+)
+
+// DryRunOutboxRepository implements notification.DryRunOutbox for PostgreSQL.
+type DryRunOutboxRepository struct {
+	conn *Connection
+}
+
+// NewDryRunOutboxRepository creates a new DryRunOutboxRepository.
+func NewDryRunOutboxRepository(conn *Connection) *DryRunOutboxRepository {
+	return &DryRunOutboxRepository{conn: conn}
+}
+
+// Record stores a message that was captured instead of being sent.
+func (r *DryRunOutboxRepository) Record(ctx context.Context, entry notification.DryRunEntry) error {
+	query := `
+		INSERT INTO dry_run_outbox (recipient_id, chat_id, type, message, job_name, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.conn.Exec(ctx, query,
+		entry.RecipientID,
+		entry.ChatID,
+		entry.Type,
+		entry.Message,
+		entry.Job,
+		entry.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record dry run message: %w", err)
+	}
+
+	return nil
+}
+
+// List returns captured messages matching the filter, newest first.
+func (r *DryRunOutboxRepository) List(ctx context.Context, filter notification.DryRunFilter) ([]notification.DryRunEntry, error) {
+	where, args := dryRunWhere(filter)
+	query := `
+		SELECT id, recipient_id, chat_id, type, message, job_name, created_at
+		FROM dry_run_outbox` + where + `
+		ORDER BY created_at DESC, id DESC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dry run outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []notification.DryRunEntry
+	for rows.Next() {
+		var e notification.DryRunEntry
+		if err := rows.Scan(&e.ID, &e.RecipientID, &e.ChatID, &e.Type, &e.Message, &e.Job, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dry run message: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate dry run outbox: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Purge deletes captured messages matching the filter.
+func (r *DryRunOutboxRepository) Purge(ctx context.Context, filter notification.DryRunFilter) (int64, error) {
+	where, args := dryRunWhere(filter)
+
+	tag, err := r.conn.Exec(ctx, `DELETE FROM dry_run_outbox`+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge dry run outbox: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// dryRunWhere builds the WHERE clause shared by List and Purge.
+func dryRunWhere(filter notification.DryRunFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.Job != "" {
+		add("job_name = $%d", filter.Job)
+	}
+	if filter.Type != "" {
+		add("type = $%d", filter.Type)
+	}
+	if filter.ChatID != 0 {
+		add("chat_id = $%d", filter.ChatID)
+	}
+	if !filter.Since.IsZero() {
+		add("created_at >= $%d", filter.Since.UTC())
+	}
+	if !filter.Until.IsZero() {
+		add("created_at < $%d", filter.Until.UTC())
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
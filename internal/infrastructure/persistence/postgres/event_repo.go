@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/event"
+)
+
+// EventRepository implements event.Repository for PostgreSQL.
+type EventRepository struct {
+	conn *Connection
+}
+
+// NewEventRepository creates a new EventRepository.
+func NewEventRepository(conn *Connection) *EventRepository {
+	return &EventRepository{conn: conn}
+}
+
+const eventColumns = `id, name, starts_at, ends_at, metric, status, created_at, finalized_at`
+
+// Create stores a new event.
+func (r *EventRepository) Create(ctx context.Context, e *event.Event) error {
+	query := `
+		INSERT INTO events (` + eventColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.conn.Exec(ctx, query,
+		e.ID, e.Name, e.StartsAt, e.EndsAt, string(e.Metric), string(e.Status), e.CreatedAt, e.FinalizedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns an event.
+func (r *EventRepository) GetByID(ctx context.Context, id string) (*event.Event, error) {
+	query := `SELECT ` + eventColumns + ` FROM events WHERE id = $1`
+
+	e, err := scanEvent(r.conn.QueryRow(ctx, query, id))
+	if err != nil {
+		if IsNoRows(err) {
+			return nil, event.ErrEventNotFound
+		}
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	return e, nil
+}
+
+// Update saves the window of an open event.
+func (r *EventRepository) Update(ctx context.Context, e *event.Event) error {
+	query := `
+		UPDATE events SET name = $2, starts_at = $3, ends_at = $4
+		WHERE id = $1 AND status = 'open'
+	`
+
+	tag, err := r.conn.Exec(ctx, query, e.ID, e.Name, e.StartsAt, e.EndsAt)
+	if err != nil {
+		return fmt.Errorf("failed to update event: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := r.GetByID(ctx, e.ID); err != nil {
+			return err
+		}
+		return event.ErrEventFinalized
+	}
+	return nil
+}
+
+// List returns the latest events, newest first.
+func (r *EventRepository) List(ctx context.Context, limit int) ([]*event.Event, error) {
+	query := `SELECT ` + eventColumns + ` FROM events ORDER BY starts_at DESC, created_at DESC LIMIT $1`
+	return r.query(ctx, query, limit)
+}
+
+// ListOpen returns events that are not finalized yet.
+func (r *EventRepository) ListOpen(ctx context.Context) ([]*event.Event, error) {
+	query := `SELECT ` + eventColumns + ` FROM events WHERE status = 'open' ORDER BY starts_at, created_at`
+	return r.query(ctx, query)
+}
+
+// Finalize stores the results and marks the event finalized in one
+// transaction. The event row is locked first, so concurrent workers
+// finalize it once.
+func (r *EventRepository) Finalize(ctx context.Context, eventID string, results []event.Standing, finalizedAt time.Time) (bool, error) {
+	var finalized bool
+	err := r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		var status string
+		err := tx.QueryRow(ctx, `SELECT status FROM events WHERE id = $1 FOR UPDATE`, eventID).Scan(&status)
+		if err != nil {
+			if IsNoRows(err) {
+				return event.ErrEventNotFound
+			}
+			return fmt.Errorf("failed to lock event: %w", err)
+		}
+		if event.Status(status) == event.StatusFinalized {
+			return nil
+		}
+
+		batch := &pgx.Batch{}
+		for _, res := range results {
+			batch.Queue(`
+				INSERT INTO event_results (event_id, student_id, rank, score)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (event_id, student_id) DO UPDATE SET rank = EXCLUDED.rank, score = EXCLUDED.score
+			`, eventID, res.StudentID, res.Rank, res.Score)
+		}
+		batch.Queue(`UPDATE events SET status = 'finalized', finalized_at = $2 WHERE id = $1`, eventID, finalizedAt.UTC())
+
+		if err := execBatch(ctx, tx, batch); err != nil {
+			return fmt.Errorf("failed to save event results: %w", err)
+		}
+
+		finalized = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return finalized, nil
+}
+
+// GetResults returns the frozen results by rank.
+func (r *EventRepository) GetResults(ctx context.Context, eventID string, limit int) ([]event.Standing, error) {
+	query := `
+		SELECT student_id, score, rank
+		FROM event_results
+		WHERE event_id = $1
+		ORDER BY rank, student_id
+		LIMIT $2
+	`
+
+	rows, err := r.conn.Query(ctx, query, eventID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []event.Standing
+	for rows.Next() {
+		var s event.Standing
+		if err := rows.Scan(&s.StudentID, &s.Score, &s.Rank); err != nil {
+			return nil, fmt.Errorf("failed to scan event result: %w", err)
+		}
+		results = append(results, s)
+	}
+
+	return results, rows.Err()
+}
+
+func (r *EventRepository) query(ctx context.Context, query string, args ...interface{}) ([]*event.Event, error) {
+	rows, err := r.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*event.Event
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+func scanEvent(row pgx.Row) (*event.Event, error) {
+	var e event.Event
+	var metric, status string
+	if err := row.Scan(&e.ID, &e.Name, &e.StartsAt, &e.EndsAt, &metric, &status, &e.CreatedAt, &e.FinalizedAt); err != nil {
+		return nil, err
+	}
+	e.Metric = event.Metric(metric)
+	e.Status = event.Status(status)
+	return &e, nil
+}
@@ -0,0 +1,228 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/experiment"
+)
+
+// ExperimentRepository implements experiment.Repository for PostgreSQL.
+type ExperimentRepository struct {
+	conn *Connection
+}
+
+// NewExperimentRepository creates a new ExperimentRepository.
+func NewExperimentRepository(conn *Connection) *ExperimentRepository {
+	return &ExperimentRepository{conn: conn}
+}
+
+const experimentColumns = `id, name, notification_type, variants, starts_at, ends_at, status, created_at`
+
+// Create stores a new experiment.
+func (r *ExperimentRepository) Create(ctx context.Context, e *experiment.Experiment) error {
+	variants, err := json.Marshal(e.Variants)
+	if err != nil {
+		return fmt.Errorf("failed to marshal experiment variants: %w", err)
+	}
+
+	query := `
+		INSERT INTO experiments (` + experimentColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err = r.conn.Exec(ctx, query,
+		e.ID, e.Name, e.NotificationType, variants, e.StartsAt, e.EndsAt, string(e.Status), e.CreatedAt,
+	)
+	if err != nil {
+		if IsUniqueViolation(err) {
+			return experiment.ErrExperimentExists
+		}
+		return fmt.Errorf("failed to create experiment: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns an experiment.
+func (r *ExperimentRepository) GetByID(ctx context.Context, id string) (*experiment.Experiment, error) {
+	query := `SELECT ` + experimentColumns + ` FROM experiments WHERE id = $1`
+
+	e, err := scanExperiment(r.conn.QueryRow(ctx, query, id))
+	if err != nil {
+		if IsNoRows(err) {
+			return nil, experiment.ErrExperimentNotFound
+		}
+		return nil, fmt.Errorf("failed to get experiment: %w", err)
+	}
+	return e, nil
+}
+
+// Update saves the window and status of an experiment.
+func (r *ExperimentRepository) Update(ctx context.Context, e *experiment.Experiment) error {
+	query := `UPDATE experiments SET ends_at = $2, status = $3 WHERE id = $1`
+
+	tag, err := r.conn.Exec(ctx, query, e.ID, e.EndsAt, string(e.Status))
+	if err != nil {
+		return fmt.Errorf("failed to update experiment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return experiment.ErrExperimentNotFound
+	}
+	return nil
+}
+
+// List returns the latest experiments, newest first.
+func (r *ExperimentRepository) List(ctx context.Context, limit int) ([]*experiment.Experiment, error) {
+	query := `SELECT ` + experimentColumns + ` FROM experiments ORDER BY created_at DESC LIMIT $1`
+	return r.query(ctx, query, limit)
+}
+
+// ListRunning returns the experiments running at now, oldest first.
+func (r *ExperimentRepository) ListRunning(ctx context.Context, now time.Time) ([]*experiment.Experiment, error) {
+	query := `
+		SELECT ` + experimentColumns + ` FROM experiments
+		WHERE status = 'running' AND starts_at <= $1 AND (ends_at IS NULL OR ends_at > $1)
+		ORDER BY starts_at, created_at
+	`
+	return r.query(ctx, query, now.UTC())
+}
+
+// ListEndedSince returns the experiments that are still open or ended after
+// since.
+func (r *ExperimentRepository) ListEndedSince(ctx context.Context, since time.Time) ([]*experiment.Experiment, error) {
+	query := `
+		SELECT ` + experimentColumns + ` FROM experiments
+		WHERE ends_at IS NULL OR ends_at > $1
+		ORDER BY starts_at, created_at
+	`
+	return r.query(ctx, query, since.UTC())
+}
+
+// RecordExposure stores the first exposure of a student and reports whether
+// it was the first.
+func (r *ExperimentRepository) RecordExposure(ctx context.Context, a experiment.Assignment) (bool, error) {
+	tag, err := r.conn.Exec(ctx, `
+		INSERT INTO experiment_assignments (experiment_id, student_id, variant, notification_id, exposed_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		ON CONFLICT (experiment_id, student_id) DO NOTHING
+	`, a.ExperimentID, a.StudentID, a.Variant, a.NotificationID, a.ExposedAt.UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to record experiment exposure: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListExposures returns the exposures of an experiment with the first XP
+// gain after each of them.
+func (r *ExperimentRepository) ListExposures(ctx context.Context, experimentID string) ([]experiment.Exposure, error) {
+	rows, err := r.conn.Query(ctx, `
+		SELECT a.variant, a.exposed_at, (
+			SELECT MIN(h.created_at)
+			FROM xp_history h
+			WHERE h.student_id = a.student_id AND h.delta > 0 AND h.created_at > a.exposed_at
+		)
+		FROM experiment_assignments a
+		WHERE a.experiment_id = $1
+	`, experimentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list experiment exposures: %w", err)
+	}
+	defer rows.Close()
+
+	var exposures []experiment.Exposure
+	for rows.Next() {
+		var x experiment.Exposure
+		if err := rows.Scan(&x.Variant, &x.ExposedAt, &x.NextGainAt); err != nil {
+			return nil, fmt.Errorf("failed to scan experiment exposure: %w", err)
+		}
+		exposures = append(exposures, x)
+	}
+
+	return exposures, rows.Err()
+}
+
+// SaveResults replaces the results of an experiment in one transaction.
+func (r *ExperimentRepository) SaveResults(ctx context.Context, experimentID string, results []experiment.Result, computedAt time.Time) error {
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		batch.Queue(`DELETE FROM experiment_results WHERE experiment_id = $1`, experimentID)
+		for _, res := range results {
+			batch.Queue(`
+				INSERT INTO experiment_results
+					(experiment_id, variant, exposed, converted, rate, ci_low, ci_high, computed_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			`, experimentID, res.Variant, res.Exposed, res.Converted, res.Rate, res.CILow, res.CIHigh, computedAt.UTC())
+		}
+
+		if err := execBatch(ctx, tx, batch); err != nil {
+			return fmt.Errorf("failed to save experiment results: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetResults returns the stored results and when they were computed.
+func (r *ExperimentRepository) GetResults(ctx context.Context, experimentID string) ([]experiment.Result, time.Time, error) {
+	rows, err := r.conn.Query(ctx, `
+		SELECT variant, exposed, converted, rate, ci_low, ci_high, computed_at
+		FROM experiment_results
+		WHERE experiment_id = $1
+		ORDER BY variant
+	`, experimentID)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to get experiment results: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		results    []experiment.Result
+		computedAt time.Time
+	)
+	for rows.Next() {
+		var res experiment.Result
+		if err := rows.Scan(&res.Variant, &res.Exposed, &res.Converted, &res.Rate,
+			&res.CILow, &res.CIHigh, &computedAt); err != nil {
+			return nil, time.Time{}, fmt.Errorf("failed to scan experiment result: %w", err)
+		}
+		results = append(results, res)
+	}
+
+	return results, computedAt, rows.Err()
+}
+
+func (r *ExperimentRepository) query(ctx context.Context, query string, args ...interface{}) ([]*experiment.Experiment, error) {
+	rows, err := r.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list experiments: %w", err)
+	}
+	defer rows.Close()
+
+	var experiments []*experiment.Experiment
+	for rows.Next() {
+		e, err := scanExperiment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan experiment: %w", err)
+		}
+		experiments = append(experiments, e)
+	}
+
+	return experiments, rows.Err()
+}
+
+func scanExperiment(row pgx.Row) (*experiment.Experiment, error) {
+	var e experiment.Experiment
+	var variants []byte
+	var status string
+	if err := row.Scan(&e.ID, &e.Name, &e.NotificationType, &variants, &e.StartsAt, &e.EndsAt, &status, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(variants, &e.Variants); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal experiment variants: %w", err)
+	}
+	e.Status = experiment.Status(status)
+	return &e, nil
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alem-hub/alem-community-hub/pkg/featureflag"
+)
+
+// FeatureFlagRepository implements featureflag.Store for PostgreSQL.
+type FeatureFlagRepository struct {
+	conn *Connection
+}
+
+// NewFeatureFlagRepository creates a new FeatureFlagRepository.
+func NewFeatureFlagRepository(conn *Connection) *FeatureFlagRepository {
+	return &FeatureFlagRepository{conn: conn}
+}
+
+// List returns all flags ordered by name.
+func (r *FeatureFlagRepository) List(ctx context.Context) ([]featureflag.Flag, error) {
+	query := `
+		SELECT name, description, enabled, rollout_percent, cohorts, fail_open, updated_at
+		FROM feature_flags
+		ORDER BY name
+	`
+
+	rows, err := r.conn.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query feature flags: %w", err)
+	}
+	defer rows.Close()
+
+	flags := make([]featureflag.Flag, 0)
+	for rows.Next() {
+		var flag featureflag.Flag
+		if err := rows.Scan(
+			&flag.Name, &flag.Description, &flag.Enabled, &flag.RolloutPercent,
+			&flag.Cohorts, &flag.FailOpen, &flag.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan feature flag: %w", err)
+		}
+		flags = append(flags, flag)
+	}
+
+	return flags, rows.Err()
+}
+
+// Get returns a flag.
+func (r *FeatureFlagRepository) Get(ctx context.Context, name string) (featureflag.Flag, error) {
+	query := `
+		SELECT name, description, enabled, rollout_percent, cohorts, fail_open, updated_at
+		FROM feature_flags
+		WHERE name = $1
+	`
+
+	var flag featureflag.Flag
+	err := r.conn.QueryRow(ctx, query, name).Scan(
+		&flag.Name, &flag.Description, &flag.Enabled, &flag.RolloutPercent,
+		&flag.Cohorts, &flag.FailOpen, &flag.UpdatedAt,
+	)
+	if err != nil {
+		if IsNoRows(err) {
+			return featureflag.Flag{}, featureflag.ErrNotFound
+		}
+		return featureflag.Flag{}, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+	return flag, nil
+}
+
+// Save creates or replaces a flag.
+func (r *FeatureFlagRepository) Save(ctx context.Context, flag featureflag.Flag) error {
+	query := `
+		INSERT INTO feature_flags (name, description, enabled, rollout_percent, cohorts, fail_open, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (name) DO UPDATE SET
+			description = EXCLUDED.description,
+			enabled = EXCLUDED.enabled,
+			rollout_percent = EXCLUDED.rollout_percent,
+			cohorts = EXCLUDED.cohorts,
+			fail_open = EXCLUDED.fail_open,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	cohorts := flag.Cohorts
+	if cohorts == nil {
+		cohorts = []string{}
+	}
+
+	_, err := r.conn.Exec(ctx, query,
+		flag.Name, flag.Description, flag.Enabled, flag.RolloutPercent, cohorts, flag.FailOpen, flag.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save feature flag: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+// FeedRepository implements social.FeedRepository for PostgreSQL.
+type FeedRepository struct {
+	conn *Connection
+}
+
+// NewFeedRepository creates a new FeedRepository.
+func NewFeedRepository(conn *Connection) *FeedRepository {
+	return &FeedRepository{conn: conn}
+}
+
+// Create stores a feed item and fills in its ID.
+func (r *FeedRepository) Create(ctx context.Context, item *social.FeedItem) error {
+	payload, err := json.Marshal(item.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal feed payload: %w", err)
+	}
+
+	err = r.conn.QueryRow(ctx, `
+		INSERT INTO feed_items (type, actor_id, actor_name, payload, cohort, visibility, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`,
+		string(item.Type), string(item.ActorID), item.ActorName, payload,
+		item.Cohort, string(item.Visibility), item.CreatedAt.UTC(),
+	).Scan(&item.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create feed item: %w", err)
+	}
+	return nil
+}
+
+// ListPublic returns public items, newest first. Paging by id keeps a page
+// stable while new items are added on top.
+func (r *FeedRepository) ListPublic(ctx context.Context, cohort string, beforeID int64, limit int) ([]*social.FeedItem, error) {
+	rows, err := r.conn.Query(ctx, `
+		SELECT id, type, actor_id, actor_name, payload, cohort, visibility, created_at
+		FROM feed_items
+		WHERE visibility = 'public'
+		  AND ($1 = '' OR cohort = $1)
+		  AND ($2 = 0 OR id < $2)
+		ORDER BY id DESC
+		LIMIT $3
+	`, cohort, beforeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feed items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]*social.FeedItem, 0)
+	for rows.Next() {
+		var (
+			item                   social.FeedItem
+			itemType, actorID, vis string
+			payload                []byte
+		)
+		if err := rows.Scan(&item.ID, &itemType, &actorID, &item.ActorName, &payload,
+			&item.Cohort, &vis, &item.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan feed item: %w", err)
+		}
+		item.Type = social.FeedItemType(itemType)
+		item.ActorID = social.StudentID(actorID)
+		item.Visibility = social.FeedVisibility(vis)
+		item.Payload = make(map[string]string)
+		_ = json.Unmarshal(payload, &item.Payload)
+		items = append(items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate feed items: %w", err)
+	}
+	return items, nil
+}
+
+// DeleteOlderThan removes items created before cutoff.
+func (r *FeedRepository) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := r.conn.Exec(ctx, `DELETE FROM feed_items WHERE created_at < $1`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune feed items: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,208 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// FocusRepository implements social.FocusRepository for PostgreSQL.
+type FocusRepository struct {
+	conn *Connection
+}
+
+// NewFocusRepository creates a new FocusRepository.
+func NewFocusRepository(conn *Connection) *FocusRepository {
+	return &FocusRepository{conn: conn}
+}
+
+const focusColumns = `id, owner_id, COALESCE(buddy_id::text, ''), buddy_status, buddy_joined_at,
+	halfway_nudge, halfway_sent, started_at, ends_at, ended_at, owner_outcome, buddy_outcome`
+
+// Create inserts the session and fills its ID.
+func (r *FocusRepository) Create(ctx context.Context, session *social.FocusSession) error {
+	query := `
+		INSERT INTO focus_sessions (owner_id, halfway_nudge, started_at, ends_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	err := r.conn.QueryRow(ctx, query,
+		string(session.OwnerID),
+		session.HalfwayNudge,
+		session.StartedAt.UTC(),
+		session.EndsAt.UTC(),
+	).Scan(&session.ID)
+	if IsUniqueViolation(err) {
+		return social.ErrFocusActive
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save focus session: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID returns the session with the given ID.
+func (r *FocusRepository) GetByID(ctx context.Context, id int64) (*social.FocusSession, error) {
+	session, err := scanFocusSession(r.conn.QueryRow(ctx, `SELECT `+focusColumns+` FROM focus_sessions WHERE id = $1`, id))
+	if IsNoRows(err) {
+		return nil, social.ErrFocusNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get focus session: %w", err)
+	}
+	return session, nil
+}
+
+// GetActiveByStudent returns the running session the student owns or joined.
+func (r *FocusRepository) GetActiveByStudent(ctx context.Context, studentID social.StudentID) (*social.FocusSession, error) {
+	session, err := scanFocusSession(r.conn.QueryRow(ctx, `
+		SELECT `+focusColumns+` FROM focus_sessions
+		WHERE ended_at IS NULL
+		  AND (owner_id = $1 OR (buddy_id = $1 AND buddy_status = 'accepted'))
+		ORDER BY started_at DESC
+		LIMIT 1
+	`, string(studentID)))
+	if IsNoRows(err) {
+		return nil, social.ErrFocusNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active focus session: %w", err)
+	}
+	return session, nil
+}
+
+// ListActive returns every running session.
+func (r *FocusRepository) ListActive(ctx context.Context) ([]*social.FocusSession, error) {
+	rows, err := r.conn.Query(ctx, `
+		SELECT `+focusColumns+` FROM focus_sessions
+		WHERE ended_at IS NULL
+		ORDER BY ends_at
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active focus sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*social.FocusSession
+	for rows.Next() {
+		session, err := scanFocusSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan focus session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// ListEndedBetween returns the sessions that ended in [from, to).
+func (r *FocusRepository) ListEndedBetween(ctx context.Context, from, to time.Time) ([]*social.FocusSession, error) {
+	rows, err := r.conn.Query(ctx, `
+		SELECT `+focusColumns+` FROM focus_sessions
+		WHERE ended_at >= $1 AND ended_at < $2
+		ORDER BY ended_at
+	`, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ended focus sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*social.FocusSession
+	for rows.Next() {
+		session, err := scanFocusSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan focus session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// Update stores the buddy and the outcomes.
+func (r *FocusRepository) Update(ctx context.Context, session *social.FocusSession) error {
+	var buddyID *string
+	if session.BuddyID != "" {
+		id := string(session.BuddyID)
+		buddyID = &id
+	}
+
+	_, err := r.conn.Exec(ctx, `
+		UPDATE focus_sessions SET
+			buddy_id = $2, buddy_status = $3, buddy_joined_at = $4,
+			owner_outcome = $5, buddy_outcome = $6
+		WHERE id = $1
+	`,
+		session.ID,
+		buddyID,
+		string(session.BuddyStatus),
+		session.BuddyJoinedAt,
+		string(session.OwnerOutcome),
+		string(session.BuddyOutcome),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update focus session: %w", err)
+	}
+	return nil
+}
+
+// MarkHalfwaySent flags the halfway nudge of a running session.
+func (r *FocusRepository) MarkHalfwaySent(ctx context.Context, id int64) (bool, error) {
+	tag, err := r.conn.Exec(ctx, `
+		UPDATE focus_sessions SET halfway_sent = TRUE
+		WHERE id = $1 AND NOT halfway_sent AND ended_at IS NULL
+	`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark focus halfway: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// MarkEnded ends a running session.
+func (r *FocusRepository) MarkEnded(ctx context.Context, id int64, at time.Time) (bool, error) {
+	tag, err := r.conn.Exec(ctx, `
+		UPDATE focus_sessions SET ended_at = $2
+		WHERE id = $1 AND ended_at IS NULL
+	`, id, at.UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to end focus session: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// scanFocusSession scans a row selected with focusColumns.
+func scanFocusSession(row pgx.Row) (*social.FocusSession, error) {
+	var (
+		session                    social.FocusSession
+		ownerID, buddyID, status   string
+		ownerOutcome, buddyOutcome string
+	)
+	err := row.Scan(
+		&session.ID,
+		&ownerID,
+		&buddyID,
+		&status,
+		&session.BuddyJoinedAt,
+		&session.HalfwayNudge,
+		&session.HalfwaySent,
+		&session.StartedAt,
+		&session.EndsAt,
+		&session.EndedAt,
+		&ownerOutcome,
+		&buddyOutcome,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	session.OwnerID = social.StudentID(ownerID)
+	session.BuddyID = social.StudentID(buddyID)
+	session.BuddyStatus = social.FocusInvite(status)
+	session.OwnerOutcome = social.FocusOutcome(ownerOutcome)
+	session.BuddyOutcome = social.FocusOutcome(buddyOutcome)
+	return &session, nil
+}
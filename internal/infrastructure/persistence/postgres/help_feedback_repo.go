@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+// HelpFeedbackRepository implements social.HelpFeedbackRepository for PostgreSQL.
+type HelpFeedbackRepository struct {
+	conn *Connection
+}
+
+// NewHelpFeedbackRepository creates a new HelpFeedbackRepository.
+func NewHelpFeedbackRepository(conn *Connection) *HelpFeedbackRepository {
+	return &HelpFeedbackRepository{conn: conn}
+}
+
+// Create stores a feedback score. The primary key on help_request_id makes a
+// second answer a no-op, reported as social.ErrHelpFeedbackExists.
+func (r *HelpFeedbackRepository) Create(ctx context.Context, feedback *social.HelpFeedback) error {
+	query := `
+		INSERT INTO help_feedback (help_request_id, score, source, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (help_request_id) DO NOTHING
+	`
+
+	tag, err := r.conn.Exec(ctx, query, feedback.HelpRequestID, feedback.Score, string(feedback.Source), feedback.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create help feedback: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return social.ErrHelpFeedbackExists
+	}
+	return nil
+}
+
+// ExistsForHelpRequest reports whether the request already has a score.
+func (r *HelpFeedbackRepository) ExistsForHelpRequest(ctx context.Context, helpRequestID string) (bool, error) {
+	var exists bool
+	err := r.conn.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM help_feedback WHERE help_request_id = $1)`, helpRequestID,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check help feedback: %w", err)
+	}
+	return exists, nil
+}
+
+// GetSatisfactionStats returns score counts of feedback given since the given
+// time, overall and per requester cohort per week.
+func (r *HelpFeedbackRepository) GetSatisfactionStats(ctx context.Context, since time.Time) (*social.SatisfactionStats, error) {
+	stats := &social.SatisfactionStats{Since: since}
+
+	overallQuery := `
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(score), 0),
+			COUNT(*) FILTER (WHERE score = 5),
+			COUNT(*) FILTER (WHERE score <= 3)
+		FROM help_feedback
+		WHERE created_at >= $1
+	`
+
+	total := &stats.SatisfactionCounts
+	if err := r.conn.QueryRow(ctx, overallQuery, since).Scan(
+		&total.Responses, &total.ScoreSum, &total.Promoters, &total.Detractors,
+	); err != nil {
+		return nil, fmt.Errorf("failed to get satisfaction stats: %w", err)
+	}
+
+	weeklyQuery := `
+		SELECT
+			s.cohort,
+			date_trunc('week', f.created_at AT TIME ZONE 'UTC') AS week,
+			COUNT(*),
+			SUM(f.score),
+			COUNT(*) FILTER (WHERE f.score = 5),
+			COUNT(*) FILTER (WHERE f.score <= 3)
+		FROM help_feedback f
+		JOIN help_requests hr ON hr.id = f.help_request_id
+		JOIN students s ON s.id = hr.requester_id
+		WHERE f.created_at >= $1
+		GROUP BY s.cohort, week
+		ORDER BY week DESC, s.cohort
+	`
+
+	rows, err := r.conn.Query(ctx, weeklyQuery, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weekly satisfaction: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var w social.WeeklySatisfaction
+		if err := rows.Scan(&w.Cohort, &w.WeekStart, &w.Responses, &w.ScoreSum, &w.Promoters, &w.Detractors); err != nil {
+			return nil, fmt.Errorf("failed to scan weekly satisfaction: %w", err)
+		}
+		w.WeekStart = time.Date(w.WeekStart.Year(), w.WeekStart.Month(), w.WeekStart.Day(), 0, 0, 0, 0, time.UTC)
+		stats.Weekly = append(stats.Weekly, w)
+	}
+
+	return stats, rows.Err()
+}
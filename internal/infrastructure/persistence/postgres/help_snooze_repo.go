@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+// HelpSnoozeRepository implements social.HelpSnoozeRepository for PostgreSQL.
+type HelpSnoozeRepository struct {
+	conn *Connection
+}
+
+// NewHelpSnoozeRepository creates a new HelpSnoozeRepository.
+func NewHelpSnoozeRepository(conn *Connection) *HelpSnoozeRepository {
+	return &HelpSnoozeRepository{conn: conn}
+}
+
+// Create stores a snooze as the helper's next attempt on the request. No
+// row comes back once the helper has used up social.MaxHelpSnoozes; two
+// concurrent taps collide on the unique attempt and the loser gets the same
+// error.
+func (r *HelpSnoozeRepository) Create(ctx context.Context, snooze *social.HelpSnooze) error {
+	query := `
+		INSERT INTO help_snoozes (help_request_id, helper_id, attempt, remind_at, created_at)
+		SELECT $1, $2, COUNT(*) + 1, $3, $4
+		FROM help_snoozes
+		WHERE help_request_id = $1 AND helper_id = $2
+		HAVING COUNT(*) < $5
+		RETURNING id, attempt
+	`
+
+	err := r.conn.QueryRow(ctx, query,
+		snooze.HelpRequestID, string(snooze.HelperID), snooze.RemindAt.UTC(), snooze.CreatedAt.UTC(), social.MaxHelpSnoozes,
+	).Scan(&snooze.ID, &snooze.Attempt)
+	if IsNoRows(err) || IsUniqueViolation(err) {
+		return social.ErrHelpSnoozeLimit
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create help snooze: %w", err)
+	}
+	return nil
+}
+
+// GetDue returns unsent snoozes whose reminder time has come, oldest first.
+func (r *HelpSnoozeRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]*social.HelpSnooze, error) {
+	rows, err := r.conn.Query(ctx, `
+		SELECT id, help_request_id, helper_id, attempt, remind_at, created_at, sent_at
+		FROM help_snoozes
+		WHERE sent_at IS NULL AND remind_at <= $1
+		ORDER BY remind_at
+		LIMIT $2
+	`, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due help snoozes: %w", err)
+	}
+	defer rows.Close()
+
+	snoozes := make([]*social.HelpSnooze, 0)
+	for rows.Next() {
+		var (
+			snooze   social.HelpSnooze
+			helperID string
+		)
+		if err := rows.Scan(&snooze.ID, &snooze.HelpRequestID, &helperID, &snooze.Attempt,
+			&snooze.RemindAt, &snooze.CreatedAt, &snooze.SentAt); err != nil {
+			return nil, fmt.Errorf("failed to scan help snooze: %w", err)
+		}
+		snooze.HelperID = social.StudentID(helperID)
+		snoozes = append(snoozes, &snooze)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate help snoozes: %w", err)
+	}
+	return snoozes, nil
+}
+
+// MarkSent claims a snooze for sending.
+func (r *HelpSnoozeRepository) MarkSent(ctx context.Context, id int64, at time.Time) (bool, error) {
+	tag, err := r.conn.Exec(ctx, `
+		UPDATE help_snoozes SET sent_at = $2
+		WHERE id = $1 AND sent_at IS NULL
+	`, id, at.UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to mark help snooze sent: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Team help requests
+// ─────────────────────────────────────────────────────────────────────────────
+
+// SaveMembers upserts the request's invitations and their answers.
+func (r *HelpRequestRepository) SaveMembers(ctx context.Context, req *social.HelpRequest) error {
+	if len(req.Members) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO help_request_members (help_request_id, student_id, status, invited_at, responded_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (help_request_id, student_id) DO UPDATE SET
+			status = EXCLUDED.status,
+			invited_at = EXCLUDED.invited_at,
+			responded_at = EXCLUDED.responded_at
+	`
+
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		for _, m := range req.Members {
+			if _, err := tx.Exec(ctx, query, req.ID, string(m.StudentID), string(m.Status), m.InvitedAt, m.RespondedAt); err != nil {
+				return fmt.Errorf("failed to save help request member: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetOpenMemberships returns unclosed requests the student takes part in as
+// an accepted teammate, oldest first.
+func (r *HelpRequestRepository) GetOpenMemberships(ctx context.Context, studentID social.StudentID) ([]*social.HelpRequest, error) {
+	query := `SELECT ` + helpRequestColumns + `
+		FROM help_requests
+		WHERE status IN ('open', 'matched', 'in_progress')
+		  AND id IN (
+			SELECT help_request_id FROM help_request_members
+			WHERE student_id = $1 AND status = 'accepted'
+		  )
+		ORDER BY created_at
+	`
+
+	rows, err := r.conn.Query(ctx, query, string(studentID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get help request memberships: %w", err)
+	}
+	defer rows.Close()
+
+	requests, err := scanHelpRequests(rows)
+	if err != nil {
+		return nil, err
+	}
+	return requests, r.attachMembers(ctx, requests...)
+}
+
+// attachMembers loads the members of the requests.
+func (r *HelpRequestRepository) attachMembers(ctx context.Context, requests ...*social.HelpRequest) error {
+	if len(requests) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*social.HelpRequest, len(requests))
+	ids := make([]string, len(requests))
+	for i, req := range requests {
+		byID[req.ID] = req
+		ids[i] = req.ID
+	}
+
+	rows, err := r.conn.Query(ctx, `
+		SELECT help_request_id, student_id, status, invited_at, responded_at
+		FROM help_request_members
+		WHERE help_request_id = ANY($1::uuid[])
+		ORDER BY invited_at
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get help request members: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var requestID, studentID, status string
+		var m social.HelpRequestMember
+		var respondedAt *time.Time
+		if err := rows.Scan(&requestID, &studentID, &status, &m.InvitedAt, &respondedAt); err != nil {
+			return fmt.Errorf("failed to scan help request member: %w", err)
+		}
+		m.StudentID = social.StudentID(studentID)
+		m.Status = social.HelpMemberStatus(status)
+		m.RespondedAt = respondedAt
+		if req, ok := byID[requestID]; ok {
+			req.Members = append(req.Members, m)
+		}
+	}
+	return rows.Err()
+}
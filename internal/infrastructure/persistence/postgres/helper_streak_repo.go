@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// HelperStreakRepository implements student.HelperStreakRepository and
+// student.HelperActivityRepository for PostgreSQL.
+type HelperStreakRepository struct {
+	conn *Connection
+}
+
+// NewHelperStreakRepository creates a new HelperStreakRepository.
+func NewHelperStreakRepository(conn *Connection) *HelperStreakRepository {
+	return &HelperStreakRepository{conn: conn}
+}
+
+const helperStreakColumns = `student_id, current_weeks, best_weeks, last_helped_week, evaluated_week, updated_at`
+
+// Get returns the helper streak of a student, or nil if there is none.
+func (r *HelperStreakRepository) Get(ctx context.Context, studentID string) (*student.HelperStreak, error) {
+	query := `SELECT ` + helperStreakColumns + ` FROM helper_streaks WHERE student_id = $1`
+
+	streak, err := scanHelperStreak(r.conn.QueryRow(ctx, query, studentID))
+	if err != nil {
+		if IsNoRows(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get helper streak: %w", err)
+	}
+
+	return streak, nil
+}
+
+// GetActive returns the streaks that are not reset.
+func (r *HelperStreakRepository) GetActive(ctx context.Context) ([]*student.HelperStreak, error) {
+	query := `SELECT ` + helperStreakColumns + ` FROM helper_streaks WHERE current_weeks > 0`
+
+	rows, err := r.conn.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active helper streaks: %w", err)
+	}
+	defer rows.Close()
+
+	var streaks []*student.HelperStreak
+	for rows.Next() {
+		streak, err := scanHelperStreak(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan helper streak: %w", err)
+		}
+		streaks = append(streaks, streak)
+	}
+
+	return streaks, rows.Err()
+}
+
+// Save inserts or updates a helper streak.
+func (r *HelperStreakRepository) Save(ctx context.Context, streak *student.HelperStreak) error {
+	query := `
+		INSERT INTO helper_streaks (` + helperStreakColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (student_id) DO UPDATE SET
+			current_weeks = EXCLUDED.current_weeks,
+			best_weeks = EXCLUDED.best_weeks,
+			last_helped_week = EXCLUDED.last_helped_week,
+			evaluated_week = EXCLUDED.evaluated_week,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.conn.Exec(ctx, query,
+		streak.StudentID,
+		streak.CurrentWeeks,
+		streak.BestWeeks,
+		string(streak.LastHelpedWeek),
+		string(streak.EvaluatedWeek),
+		streak.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save helper streak: %w", err)
+	}
+
+	return nil
+}
+
+// LastEvaluatedWeek returns the latest evaluated week, or "" if none.
+func (r *HelperStreakRepository) LastEvaluatedWeek(ctx context.Context) (student.ISOWeek, error) {
+	var week string
+	err := r.conn.QueryRow(ctx, `SELECT COALESCE(MAX(evaluated_week), '') FROM helper_streaks`).Scan(&week)
+	if err != nil {
+		return "", fmt.Errorf("failed to get last evaluated helper week: %w", err)
+	}
+	return student.ISOWeek(week), nil
+}
+
+// GetHelpingStudents returns students who resolved a help request as the
+// helper or received an endorsement within [from, to).
+func (r *HelperStreakRepository) GetHelpingStudents(ctx context.Context, from, to time.Time) ([]string, error) {
+	query := `
+		SELECT helper_id::text FROM help_requests
+		WHERE status = 'resolved' AND helper_id IS NOT NULL
+		  AND resolved_at >= $1 AND resolved_at < $2
+		UNION
+		SELECT to_student_id::text FROM endorsements
+		WHERE created_at >= $1 AND created_at < $2
+	`
+
+	rows, err := r.conn.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get helping students: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan helping student: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// CountEndorsementsBetween returns how many endorsements each student
+// received within [from, to).
+func (r *HelperStreakRepository) CountEndorsementsBetween(ctx context.Context, from, to time.Time) (map[string]int, error) {
+	query := `
+		SELECT to_student_id::text, COUNT(*)
+		FROM endorsements
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY to_student_id
+	`
+
+	rows, err := r.conn.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count endorsements: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			id    string
+			count int
+		)
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan endorsement count: %w", err)
+		}
+		counts[id] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func scanHelperStreak(row pgx.Row) (*student.HelperStreak, error) {
+	var (
+		streak    student.HelperStreak
+		lastWeek  string
+		evaluated string
+	)
+	if err := row.Scan(&streak.StudentID, &streak.CurrentWeeks, &streak.BestWeeks, &lastWeek, &evaluated, &streak.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	streak.LastHelpedWeek = student.ISOWeek(lastWeek)
+	streak.EvaluatedWeek = student.ISOWeek(evaluated)
+	return &streak, nil
+}
+
+// Ensure interfaces are implemented
+var (
+	_ student.HelperStreakRepository   = (*HelperStreakRepository)(nil)
+	_ student.HelperActivityRepository = (*HelperStreakRepository)(nil)
+)
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// IdentityConflictRepository implements student.IdentityConflictRepository for PostgreSQL.
+type IdentityConflictRepository struct {
+	conn *Connection
+}
+
+// NewIdentityConflictRepository creates a new IdentityConflictRepository.
+func NewIdentityConflictRepository(conn *Connection) *IdentityConflictRepository {
+	return &IdentityConflictRepository{conn: conn}
+}
+
+// Flag stores the pair. A pair that is already flagged keeps its first
+// detection time.
+func (r *IdentityConflictRepository) Flag(ctx context.Context, conflict student.IdentityConflict) error {
+	query := `
+		INSERT INTO identity_conflicts (login_student_id, email_student_id, alem_login, email, detected_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (login_student_id, email_student_id) DO NOTHING
+	`
+
+	_, err := r.conn.Exec(ctx, query,
+		conflict.LoginStudentID,
+		conflict.EmailStudentID,
+		conflict.AlemLogin,
+		conflict.Email,
+		conflict.DetectedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to flag identity conflict: %w", err)
+	}
+
+	return nil
+}
+
+// ListOpen returns flagged pairs where neither student has been merged away.
+func (r *IdentityConflictRepository) ListOpen(ctx context.Context, limit int) ([]student.IdentityConflict, error) {
+	query := `
+		SELECT c.login_student_id, c.email_student_id, c.alem_login, c.email, c.detected_at
+		FROM identity_conflicts c
+		JOIN students l ON l.id = c.login_student_id
+		JOIN students e ON e.id = c.email_student_id
+		WHERE l.status != $1 AND e.status != $1
+		ORDER BY c.detected_at ASC
+		LIMIT $2
+	`
+
+	rows, err := r.conn.Query(ctx, query, string(student.StatusLeft), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identity conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	var conflicts []student.IdentityConflict
+	for rows.Next() {
+		var c student.IdentityConflict
+		if err := rows.Scan(&c.LoginStudentID, &c.EmailStudentID, &c.AlemLogin, &c.Email, &c.DetectedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan identity conflict: %w", err)
+		}
+		conflicts = append(conflicts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate identity conflicts: %w", err)
+	}
+
+	return conflicts, nil
+}
@@ -313,10 +313,38 @@ func (r *LeaderboardRepository) DeleteOldSnapshots(ctx context.Context, olderTha
 
 // GetStudentRank returns the current rank for a student.
 func (r *LeaderboardRepository) GetStudentRank(ctx context.Context, studentID string, cohort leaderboard.Cohort) (*leaderboard.LeaderboardEntry, error) {
+	return studentRank(ctx, r.conn, studentID, cohort)
+}
+
+// GetGlobalRank returns the current rank of a student in the general
+// leaderboard, across all cohorts. The rebuild stores the general
+// leaderboard as snapshots with the empty cohort (leaderboard.CohortAll)
+// alongside the per-cohort ones, so only those are read here: a rank from
+// the student's own cohort snapshot is a different number.
+func (r *LeaderboardRepository) GetGlobalRank(ctx context.Context, studentID string) (*leaderboard.LeaderboardEntry, error) {
+	return globalRank(ctx, r.conn, studentID)
+}
+
+// rowQuerier is the part of Connection a single-row lookup needs.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// globalRank reads the student's entry from the latest general snapshot.
+func globalRank(ctx context.Context, q rowQuerier, studentID string) (*leaderboard.LeaderboardEntry, error) {
+	return studentRank(ctx, q, studentID, leaderboard.CohortAll)
+}
+
+// studentRank reads the student's entry from the latest snapshot of cohort.
+// It returns nil if the student is not in it.
+func studentRank(ctx context.Context, q rowQuerier, studentID string, cohort leaderboard.Cohort) (*leaderboard.LeaderboardEntry, error) {
 	// Get from latest snapshot
 	query := `
 		SELECT le.rank, le.xp, le.level, le.rank_change, le.is_online, le.is_available_for_help,
-			   s.id, s.display_name, s.cohort, s.help_rating
+			   s.id, s.display_name, s.cohort, s.help_rating,
+			   COALESCE(s.preferences->>'hide_from_leaderboard' = 'true', false),
+			   COALESCE(s.preferences->>'hide_online_status' = 'true', false),
+			   EXISTS (SELECT 1 FROM xp_flags f WHERE f.student_id = s.id AND f.status = 'pending')
 		FROM leaderboard_entries le
 		JOIN leaderboard_snapshots ls ON le.snapshot_id = ls.id
 		JOIN students s ON le.student_id = s.id
@@ -329,7 +357,7 @@ func (r *LeaderboardRepository) GetStudentRank(ctx context.Context, studentID st
 	var rank, xp, level, rankChange int
 	var cohortStr string
 
-	err := r.conn.QueryRow(ctx, query, studentID, string(cohort)).Scan(
+	err := q.QueryRow(ctx, query, studentID, string(cohort)).Scan(
 		&rank,
 		&xp,
 		&level,
@@ -340,6 +368,9 @@ func (r *LeaderboardRepository) GetStudentRank(ctx context.Context, studentID st
 		&entry.DisplayName,
 		&cohortStr,
 		&entry.HelpRating,
+		&entry.HiddenFromLeaderboard,
+		&entry.HidesOnlineStatus,
+		&entry.XPReviewPending,
 	)
 
 	if IsNoRows(err) {
@@ -358,12 +389,60 @@ func (r *LeaderboardRepository) GetStudentRank(ctx context.Context, studentID st
 	return &entry, nil
 }
 
+// GetStudentRanks returns the current rank of several students in a single query.
+// Like GetStudentRank, each student's entry comes from the latest snapshot that
+// contains them. Students missing from the leaderboard are absent from the map.
+func (r *LeaderboardRepository) GetStudentRanks(ctx context.Context, studentIDs []string, cohort leaderboard.Cohort) (map[string]*leaderboard.LeaderboardEntry, error) {
+	result := make(map[string]*leaderboard.LeaderboardEntry, len(studentIDs))
+	if len(studentIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT rank, xp, level, rank_change, is_online, is_available_for_help,
+			   id, display_name, cohort, help_rating, hidden_from_leaderboard, hides_online_status, xp_review_pending
+		FROM (
+			SELECT le.rank, le.xp, le.level, le.rank_change, le.is_online, le.is_available_for_help,
+				   s.id, s.display_name, s.cohort, s.help_rating,
+				   COALESCE(s.preferences->>'hide_from_leaderboard' = 'true', false) AS hidden_from_leaderboard,
+				   COALESCE(s.preferences->>'hide_online_status' = 'true', false) AS hides_online_status,
+				   EXISTS (SELECT 1 FROM xp_flags f WHERE f.student_id = s.id AND f.status = 'pending') AS xp_review_pending,
+				   ROW_NUMBER() OVER (PARTITION BY le.student_id ORDER BY ls.snapshot_at DESC) AS rn
+			FROM leaderboard_entries le
+			JOIN leaderboard_snapshots ls ON le.snapshot_id = ls.id
+			JOIN students s ON le.student_id = s.id
+			WHERE le.student_id = ANY($1) AND ls.cohort = $2
+		) latest
+		WHERE rn = 1
+	`
+
+	rows, err := r.conn.Query(ctx, query, studentIDs, string(cohort))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get student ranks: %w", err)
+	}
+	defer rows.Close()
+
+	entries, err := r.scanLeaderboardEntries(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		result[entry.StudentID] = entry
+	}
+
+	return result, nil
+}
+
 // GetTop returns the top N students.
 func (r *LeaderboardRepository) GetTop(ctx context.Context, cohort leaderboard.Cohort, limit int) ([]*leaderboard.LeaderboardEntry, error) {
 	// Get from latest snapshot
 	query := `
 		SELECT le.rank, le.xp, le.level, le.rank_change, le.is_online, le.is_available_for_help,
-			   s.id, s.display_name, s.cohort, s.help_rating
+			   s.id, s.display_name, s.cohort, s.help_rating,
+			   COALESCE(s.preferences->>'hide_from_leaderboard' = 'true', false),
+			   COALESCE(s.preferences->>'hide_online_status' = 'true', false),
+			   EXISTS (SELECT 1 FROM xp_flags f WHERE f.student_id = s.id AND f.status = 'pending')
 		FROM leaderboard_entries le
 		JOIN leaderboard_snapshots ls ON le.snapshot_id = ls.id
 		JOIN students s ON le.student_id = s.id
@@ -385,11 +464,15 @@ func (r *LeaderboardRepository) GetTop(ctx context.Context, cohort leaderboard.C
 
 // GetPage returns a page of the leaderboard.
 func (r *LeaderboardRepository) GetPage(ctx context.Context, cohort leaderboard.Cohort, page, pageSize int) ([]*leaderboard.LeaderboardEntry, error) {
-	offset := (page - 1) * pageSize
+	pageSize = leaderboard.PageBounds.ClampLimit(pageSize)
+	offset := max(page-1, 0) * pageSize
 
 	query := `
 		SELECT le.rank, le.xp, le.level, le.rank_change, le.is_online, le.is_available_for_help,
-			   s.id, s.display_name, s.cohort, s.help_rating
+			   s.id, s.display_name, s.cohort, s.help_rating,
+			   COALESCE(s.preferences->>'hide_from_leaderboard' = 'true', false),
+			   COALESCE(s.preferences->>'hide_online_status' = 'true', false),
+			   EXISTS (SELECT 1 FROM xp_flags f WHERE f.student_id = s.id AND f.status = 'pending')
 		FROM leaderboard_entries le
 		JOIN leaderboard_snapshots ls ON le.snapshot_id = ls.id
 		JOIN students s ON le.student_id = s.id
@@ -409,6 +492,37 @@ func (r *LeaderboardRepository) GetPage(ctx context.Context, cohort leaderboard.
 	return r.scanLeaderboardEntries(rows)
 }
 
+// GetAfterRank returns up to limit entries ranked below afterRank.
+// Seeks by rank instead of OFFSET, so deep pages cost the same as the first.
+func (r *LeaderboardRepository) GetAfterRank(ctx context.Context, cohort leaderboard.Cohort, afterRank, limit int) ([]*leaderboard.LeaderboardEntry, error) {
+	limit = leaderboard.PageBounds.ClampLimit(limit)
+
+	query := `
+		SELECT le.rank, le.xp, le.level, le.rank_change, le.is_online, le.is_available_for_help,
+			   s.id, s.display_name, s.cohort, s.help_rating,
+			   COALESCE(s.preferences->>'hide_from_leaderboard' = 'true', false),
+			   COALESCE(s.preferences->>'hide_online_status' = 'true', false),
+			   EXISTS (SELECT 1 FROM xp_flags f WHERE f.student_id = s.id AND f.status = 'pending')
+		FROM leaderboard_entries le
+		JOIN leaderboard_snapshots ls ON le.snapshot_id = ls.id
+		JOIN students s ON le.student_id = s.id
+		WHERE ls.id = (
+			SELECT id FROM leaderboard_snapshots WHERE cohort = $1 ORDER BY snapshot_at DESC LIMIT 1
+		)
+		AND le.rank > $2
+		ORDER BY le.rank ASC
+		LIMIT $3
+	`
+
+	rows, err := r.conn.Query(ctx, query, string(cohort), afterRank, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get page after rank: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanLeaderboardEntries(rows)
+}
+
 // GetNeighbors returns neighbors around a student (±rangeSize).
 func (r *LeaderboardRepository) GetNeighbors(ctx context.Context, studentID string, cohort leaderboard.Cohort, rangeSize int) ([]*leaderboard.LeaderboardEntry, error) {
 	// First get the student's rank
@@ -429,7 +543,10 @@ func (r *LeaderboardRepository) GetNeighbors(ctx context.Context, studentID stri
 
 	query := `
 		SELECT le.rank, le.xp, le.level, le.rank_change, le.is_online, le.is_available_for_help,
-			   s.id, s.display_name, s.cohort, s.help_rating
+			   s.id, s.display_name, s.cohort, s.help_rating,
+			   COALESCE(s.preferences->>'hide_from_leaderboard' = 'true', false),
+			   COALESCE(s.preferences->>'hide_online_status' = 'true', false),
+			   EXISTS (SELECT 1 FROM xp_flags f WHERE f.student_id = s.id AND f.status = 'pending')
 		FROM leaderboard_entries le
 		JOIN leaderboard_snapshots ls ON le.snapshot_id = ls.id
 		JOIN students s ON le.student_id = s.id
@@ -470,6 +587,67 @@ func (r *LeaderboardRepository) GetTotalCount(ctx context.Context, cohort leader
 	return count, nil
 }
 
+// weeklyGainsCTE sums each active student's xp_history deltas in
+// [$1, $2), keeping students of cohort $3 (all cohorts if empty)
+// who gained XP.
+const weeklyGainsCTE = `
+	WITH weekly AS (
+		SELECT h.student_id, SUM(h.delta) AS gained
+		FROM xp_history h
+		JOIN students s ON s.id = h.student_id
+		WHERE h.created_at >= $1 AND h.created_at < $2
+		  AND s.status = 'active'
+		  AND ($3 = '' OR s.cohort = $3)
+		GROUP BY h.student_id
+		HAVING SUM(h.delta) > 0
+	)
+`
+
+// GetWeeklyTop returns the top N students by XP gained this week, with
+// weeks starting on Monday 00:00 Almaty time.
+func (r *LeaderboardRepository) GetWeeklyTop(ctx context.Context, cohort leaderboard.Cohort, limit int) ([]*leaderboard.LeaderboardEntry, error) {
+	start, end := leaderboard.WeekBounds(time.Now())
+
+	query := weeklyGainsCTE + `
+		SELECT ROW_NUMBER() OVER (ORDER BY w.gained DESC, s.current_xp DESC, s.id) AS rank,
+			   w.gained, s.current_xp / 1000, 0,
+			   s.online_state = 'online',
+			   (s.online_state = 'online' OR s.online_state = 'away') AND
+			   COALESCE((s.preferences->>'help_requests')::boolean, false) AND
+			   COALESCE(s.preferences->>'hide_from_helper_search' = 'true', false) = false,
+			   s.id, s.display_name, s.cohort, s.help_rating,
+			   COALESCE(s.preferences->>'hide_from_leaderboard' = 'true', false),
+			   COALESCE(s.preferences->>'hide_online_status' = 'true', false),
+			   EXISTS (SELECT 1 FROM xp_flags f WHERE f.student_id = s.id AND f.status = 'pending')
+		FROM weekly w
+		JOIN students s ON s.id = w.student_id
+		ORDER BY rank
+		LIMIT $4
+	`
+
+	rows, err := r.conn.Query(ctx, query, start, end, string(cohort), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weekly top: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanLeaderboardEntries(rows)
+}
+
+// GetWeeklyCount returns the number of students who gained XP this week.
+func (r *LeaderboardRepository) GetWeeklyCount(ctx context.Context, cohort leaderboard.Cohort) (int, error) {
+	start, end := leaderboard.WeekBounds(time.Now())
+
+	var count int
+	err := r.conn.QueryRow(ctx, weeklyGainsCTE+`SELECT COUNT(*) FROM weekly`,
+		start, end, string(cohort)).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get weekly count: %w", err)
+	}
+
+	return count, nil
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // RANK HISTORY
 // ─────────────────────────────────────────────────────────────────────────────
@@ -646,7 +824,11 @@ func (r *LeaderboardRepository) BuildLiveRanking(ctx context.Context, cohort lea
 		SELECT s.id, s.display_name, s.current_xp, s.cohort,
 			   s.online_state, s.help_rating,
 			   (s.online_state = 'online' OR s.online_state = 'away') AND 
-			   (s.preferences->>'help_requests')::boolean AS available_for_help
+			   (s.preferences->>'help_requests')::boolean AND
+			   COALESCE(s.preferences->>'hide_from_helper_search' = 'true', false) = false AS available_for_help,
+			   COALESCE(s.preferences->>'hide_from_leaderboard' = 'true', false),
+			   COALESCE(s.preferences->>'hide_online_status' = 'true', false),
+			   EXISTS (SELECT 1 FROM xp_flags f WHERE f.student_id = s.id AND f.status = 'pending')
 		FROM students s
 		WHERE s.status = 'active'
 	`
@@ -681,6 +863,9 @@ func (r *LeaderboardRepository) BuildLiveRanking(ctx context.Context, cohort lea
 			&onlineState,
 			&entry.HelpRating,
 			&availableForHelp,
+			&entry.HiddenFromLeaderboard,
+			&entry.HidesOnlineStatus,
+			&entry.XPReviewPending,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan student for ranking: %w", err)
@@ -725,7 +910,10 @@ func (r *LeaderboardRepository) CreateSnapshotFromLive(ctx context.Context, coho
 func (r *LeaderboardRepository) getSnapshotEntries(ctx context.Context, snapshotID string) ([]*leaderboard.LeaderboardEntry, error) {
 	query := `
 		SELECT le.rank, le.xp, le.level, le.rank_change, le.is_online, le.is_available_for_help,
-			   s.id, s.display_name, s.cohort, s.help_rating
+			   s.id, s.display_name, s.cohort, s.help_rating,
+			   COALESCE(s.preferences->>'hide_from_leaderboard' = 'true', false),
+			   COALESCE(s.preferences->>'hide_online_status' = 'true', false),
+			   EXISTS (SELECT 1 FROM xp_flags f WHERE f.student_id = s.id AND f.status = 'pending')
 		FROM leaderboard_entries le
 		JOIN students s ON le.student_id = s.id
 		WHERE le.snapshot_id = $1
@@ -761,6 +949,9 @@ func (r *LeaderboardRepository) scanLeaderboardEntries(rows pgx.Rows) ([]*leader
 			&entry.DisplayName,
 			&cohortStr,
 			&entry.HelpRating,
+			&entry.HiddenFromLeaderboard,
+			&entry.HidesOnlineStatus,
+			&entry.XPReviewPending,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan leaderboard entry: %w", err)
@@ -798,12 +989,16 @@ func (r *LeaderboardRepository) FindHelpersForTask(ctx context.Context, taskID s
 			s.online_state = 'online' as is_online,
 			(s.online_state IN ('online', 'away')) AND 
 			(s.preferences->>'help_requests')::boolean as is_available_for_help,
-			s.id, s.alem_login, s.display_name, s.cohort, s.help_rating
+			s.id, s.display_name, s.cohort, s.help_rating,
+			false as hidden_from_leaderboard,
+			COALESCE(s.preferences->>'hide_online_status' = 'true', false) as hides_online_status,
+			false as xp_review_pending
 		FROM students s
 		JOIN task_completions tc ON s.id = tc.student_id
 		WHERE tc.task_id = $1 
 			AND s.status = 'active'
 			AND (s.preferences->>'help_requests')::boolean = true
+			AND COALESCE(s.preferences->>'hide_from_helper_search' = 'true', false) = false
 		ORDER BY s.id, 
 			CASE WHEN s.online_state = 'online' THEN 0 ELSE 1 END,
 			s.help_rating DESC,
@@ -830,11 +1025,15 @@ func (r *LeaderboardRepository) FindOnlineHelpers(ctx context.Context, cohort le
 			0 as rank_change,
 			true as is_online,
 			true as is_available_for_help,
-			s.id, s.alem_login, s.display_name, s.cohort, s.help_rating
+			s.id, s.display_name, s.cohort, s.help_rating,
+			false as hidden_from_leaderboard,
+			COALESCE(s.preferences->>'hide_online_status' = 'true', false) as hides_online_status,
+			false as xp_review_pending
 		FROM students s
 		WHERE s.status = 'active'
 			AND s.online_state IN ('online', 'away')
 			AND (s.preferences->>'help_requests')::boolean = true
+			AND COALESCE(s.preferences->>'hide_from_helper_search' = 'true', false) = false
 	`
 
 	args := []interface{}{}
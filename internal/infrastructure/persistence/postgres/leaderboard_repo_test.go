@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+)
+
+// snapshotRows answers the rank lookup from the latest snapshot of each
+// cohort, keyed by the cohort argument. It records the SQL and arguments.
+type snapshotRows struct {
+	latest map[string]map[string]int
+	sql    string
+	args   []interface{}
+}
+
+func (q *snapshotRows) QueryRow(_ context.Context, sql string, args ...interface{}) pgx.Row {
+	q.sql, q.args = sql, args
+	studentID, cohort := args[0].(string), args[1].(string)
+	rank, ok := q.latest[cohort][studentID]
+	if !ok {
+		return errRow{pgx.ErrNoRows}
+	}
+	return fakeRow{rank, 1200, 5, 0, false, true, studentID, studentID, "2024-spring", 4.5, false, false, false}
+}
+
+// errRow is a pgx.Row that fails to scan.
+type errRow struct{ err error }
+
+func (r errRow) Scan(...interface{}) error { return r.err }
+
+func TestStudentRank_CohortAndGlobal(t *testing.T) {
+	// The same students ranked within their cohort and in the general
+	// leaderboard, stored under the empty cohort
+	q := &snapshotRows{latest: map[string]map[string]int{
+		"2024-spring": {"dana": 1, "arman": 2},
+		"":            {"aida": 1, "dana": 2, "arman": 3},
+	}}
+	ctx := context.Background()
+
+	entry, err := globalRank(ctx, q, "arman")
+	require.NoError(t, err)
+	assert.Equal(t, leaderboard.Rank(3), entry.Rank)
+	assert.Equal(t, []interface{}{"arman", ""}, q.args, "the general snapshot is the empty cohort")
+	assert.Contains(t, q.sql, "ls.cohort = $2")
+	assert.Contains(t, q.sql, "ORDER BY ls.snapshot_at DESC")
+
+	entry, err = studentRank(ctx, q, "arman", "2024-spring")
+	require.NoError(t, err)
+	assert.Equal(t, leaderboard.Rank(2), entry.Rank)
+	assert.Equal(t, []interface{}{"arman", "2024-spring"}, q.args)
+
+	// Not ranked yet
+	entry, err = globalRank(ctx, q, "newcomer")
+	require.NoError(t, err)
+	assert.Nil(t, entry)
+}
@@ -340,3 +340,1307 @@ DROP TABLE IF EXISTS endorsements;
 DROP TABLE IF EXISTS help_requests;
 DROP TABLE IF EXISTS connections;
 `
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MIGRATION 004: CREATE AUDIT LOG
+// ══════════════════════════════════════════════════════════════════════════════
+
+const migration004Up = `
+-- Migration: Create audit log
+-- Version: 004
+
+-- Audit log for privileged (admin) actions
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id VARCHAR(100) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    target_id VARCHAR(100),
+    details JSONB NOT NULL DEFAULT '{}'::jsonb,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor_id, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_action ON audit_log(action, occurred_at DESC);
+`
+
+const migration004Down = `
+DROP TABLE IF EXISTS audit_log;
+`
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MIGRATION 005: CREATE NOTIFICATIONS
+// ══════════════════════════════════════════════════════════════════════════════
+
+const migration005Up = `
+-- Migration: Create notifications
+-- Version: 005
+
+-- Notifications with delivery and read state (notification center)
+CREATE TABLE IF NOT EXISTS notifications (
+    id VARCHAR(100) PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    recipient_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    telegram_chat_id BIGINT NOT NULL,
+    priority SMALLINT NOT NULL DEFAULT 2,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    message TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}'::jsonb,
+    metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+    scheduled_at TIMESTAMP WITH TIME ZONE,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    retry_count INTEGER NOT NULL DEFAULT 0,
+    max_retries INTEGER NOT NULL DEFAULT 3,
+    last_error TEXT NOT NULL DEFAULT '',
+    read_at TIMESTAMP WITH TIME ZONE,
+    superseded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_recipient ON notifications(recipient_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_status ON notifications(status, scheduled_at);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(recipient_id, type)
+    WHERE read_at IS NULL AND superseded_at IS NULL;
+`
+
+const migration005Down = `
+DROP TABLE IF EXISTS notifications;
+`
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MIGRATION 006: CREATE COMMAND USAGE
+// ══════════════════════════════════════════════════════════════════════════════
+
+const migration006Up = `
+-- Migration: Create command usage
+-- Version: 006
+
+-- Anonymous daily command counters flushed from Redis (no per-user data)
+CREATE TABLE IF NOT EXISTS command_usage (
+    day DATE NOT NULL,
+    command VARCHAR(64) NOT NULL,
+    cohort VARCHAR(30) NOT NULL,
+    count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, command, cohort)
+);
+
+CREATE INDEX IF NOT EXISTS idx_command_usage_command ON command_usage(command, day);
+`
+
+const migration006Down = `
+DROP TABLE IF EXISTS command_usage;
+`
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MIGRATION 007: CREATE COHORT SETTINGS
+// ══════════════════════════════════════════════════════════════════════════════
+
+const migration007Up = `
+-- Migration: Create cohort settings
+-- Version: 007
+
+-- Per-cohort overrides of gamification settings (global defaults come from env)
+CREATE TABLE IF NOT EXISTS cohort_settings (
+    cohort VARCHAR(30) NOT NULL,
+    key VARCHAR(64) NOT NULL,
+    value JSONB NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (cohort, key)
+);
+`
+
+const migration007Down = `
+DROP TABLE IF EXISTS cohort_settings;
+`
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MIGRATION 008: HELP REQUEST RESPONSE TIMES
+// ══════════════════════════════════════════════════════════════════════════════
+
+const migration008Up = `
+-- Migration: Help request response times
+-- Version: 008
+
+-- Lifecycle columns used by the help request repository
+ALTER TABLE help_requests
+    ADD COLUMN IF NOT EXISTS priority VARCHAR(20) NOT NULL DEFAULT 'normal',
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS first_matched_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS first_helper_response_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE help_requests DROP CONSTRAINT IF EXISTS valid_help_status;
+ALTER TABLE help_requests ADD CONSTRAINT valid_help_status
+    CHECK (status IN ('open', 'matched', 'in_progress', 'resolved', 'cancelled', 'expired'));
+
+-- Response time aggregations and the urgent escalation scan
+CREATE INDEX IF NOT EXISTS idx_help_requests_created ON help_requests(created_at);
+CREATE INDEX IF NOT EXISTS idx_help_requests_unanswered_urgent ON help_requests(created_at)
+    WHERE priority = 'urgent' AND first_helper_response_at IS NULL AND escalated_at IS NULL;
+`
+
+const migration008Down = `
+DROP INDEX IF EXISTS idx_help_requests_unanswered_urgent;
+DROP INDEX IF EXISTS idx_help_requests_created;
+
+ALTER TABLE help_requests DROP CONSTRAINT IF EXISTS valid_help_status;
+ALTER TABLE help_requests ADD CONSTRAINT valid_help_status
+    CHECK (status IN ('open', 'in_progress', 'resolved', 'cancelled'));
+
+ALTER TABLE help_requests
+    DROP COLUMN IF EXISTS escalated_at,
+    DROP COLUMN IF EXISTS first_helper_response_at,
+    DROP COLUMN IF EXISTS first_matched_at,
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS priority;
+`
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MIGRATION 009: ENDORSEMENT REMINDERS
+// ══════════════════════════════════════════════════════════════════════════════
+
+const migration009Up = `
+-- Migration: Endorsement reminders
+-- Version: 009
+
+-- Set once when the requester is reminded to thank the helper
+ALTER TABLE help_requests
+    ADD COLUMN IF NOT EXISTS endorsement_reminder_sent_at TIMESTAMP WITH TIME ZONE;
+
+-- Reminder scan over recently resolved requests
+CREATE INDEX IF NOT EXISTS idx_help_requests_awaiting_endorsement ON help_requests(resolved_at)
+    WHERE status = 'resolved' AND helper_id IS NOT NULL AND endorsement_reminder_sent_at IS NULL;
+
+-- Endorsement lookups by request
+CREATE INDEX IF NOT EXISTS idx_endorsements_help_request ON endorsements(help_request_id)
+    WHERE help_request_id IS NOT NULL;
+`
+
+const migration009Down = `
+DROP INDEX IF EXISTS idx_endorsements_help_request;
+DROP INDEX IF EXISTS idx_help_requests_awaiting_endorsement;
+
+ALTER TABLE help_requests DROP COLUMN IF EXISTS endorsement_reminder_sent_at;
+`
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MIGRATION 010: CREATE SAGA EXECUTIONS
+// ══════════════════════════════════════════════════════════════════════════════
+
+const migration010Up = `
+-- Migration: Create saga executions
+-- Version: 010
+
+-- Progress of saga runs, used to resume runs interrupted by a restart
+CREATE TABLE IF NOT EXISTS saga_executions (
+    id VARCHAR(100) PRIMARY KEY,
+    saga_type VARCHAR(50) NOT NULL,
+    input JSONB NOT NULL DEFAULT '{}'::jsonb,
+    state JSONB NOT NULL DEFAULT '{}'::jsonb,
+    current_step VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT valid_saga_status CHECK (status IN ('running', 'completed', 'failed'))
+);
+
+-- Recovery scan over stalled runs
+CREATE INDEX IF NOT EXISTS idx_saga_executions_running ON saga_executions(updated_at)
+    WHERE status = 'running';
+CREATE INDEX IF NOT EXISTS idx_saga_executions_type ON saga_executions(saga_type, started_at DESC);
+`
+
+const migration010Down = `
+DROP TABLE IF EXISTS saga_executions;
+`
+
+const migration011Up = `
+-- Migration: Create weekly goals
+-- Version: 011
+
+-- Personal XP targets per ISO week ("2026-W42")
+CREATE TABLE IF NOT EXISTS weekly_goals (
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    iso_week VARCHAR(8) NOT NULL,
+    target_xp INTEGER NOT NULL,
+    reached_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (student_id, iso_week),
+    CONSTRAINT valid_weekly_goal_target CHECK (target_xp BETWEEN 50 AND 5000)
+);
+
+-- Evaluation scan over goals not reached yet
+CREATE INDEX IF NOT EXISTS idx_weekly_goals_pending ON weekly_goals(iso_week)
+    WHERE reached_at IS NULL;
+`
+
+const migration011Down = `
+DROP TABLE IF EXISTS weekly_goals;
+`
+
+const migration012Up = `
+-- Migration: Create webhook subscriptions
+-- Version: 012
+
+-- Outbound webhooks of external integrations (Discord/Slack mirrors)
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id UUID PRIMARY KEY,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    event_types TEXT[] NOT NULL,
+    cohort VARCHAR(50) NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    -- Delivery log: outcome of the last delivery
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    last_status INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    last_delivery_at TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_active ON webhook_subscriptions(active)
+    WHERE active;
+`
+
+const migration012Down = `
+DROP TABLE IF EXISTS webhook_subscriptions;
+`
+
+const migration013Up = `
+-- Migration: Add notification mute
+-- Version: 013
+
+-- Global mute (/mute 24h). Per-category mutes live in preferences->'mutes'.
+ALTER TABLE students ADD COLUMN IF NOT EXISTS muted_until TIMESTAMP WITH TIME ZONE;
+`
+
+const migration013Down = `
+ALTER TABLE students DROP COLUMN IF EXISTS muted_until;
+`
+
+const migration014Up = `
+-- Migration: Create task difficulty
+-- Version: 014
+
+-- Help requests per 100 completions over the last 30 days, refreshed daily.
+-- score is NULL while the task has too few completions to be rated.
+CREATE TABLE IF NOT EXISTS task_difficulty (
+    task_id VARCHAR(100) PRIMARY KEY,
+    help_requests INTEGER NOT NULL DEFAULT 0,
+    completions INTEGER NOT NULL DEFAULT 0,
+    score DOUBLE PRECISION,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_difficulty_score ON task_difficulty(score DESC NULLS LAST);
+`
+
+const migration014Down = `
+DROP TABLE IF EXISTS task_difficulty;
+`
+
+const migration015Up = `
+-- Migration: Create bot state
+-- Version: 015
+
+-- Small pieces of bot runtime state that must survive restarts,
+-- e.g. the offset of the next Telegram update to poll.
+CREATE TABLE IF NOT EXISTS bot_state (
+    key VARCHAR(100) PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+`
+
+const migration015Down = `
+DROP TABLE IF EXISTS bot_state;
+`
+
+const migration016Up = `
+-- Migration: Create events
+-- Version: 016
+
+-- Time-boxed community challenges with their own leaderboard.
+-- Live scores are kept in Redis; results are frozen here after ends_at.
+CREATE TABLE IF NOT EXISTS events (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    metric VARCHAR(30) NOT NULL CHECK (metric IN ('xp_gained', 'tasks_completed')),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'finalized')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finalized_at TIMESTAMP WITH TIME ZONE,
+
+    CHECK (ends_at >= starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_open ON events(starts_at) WHERE status = 'open';
+
+CREATE TABLE IF NOT EXISTS event_results (
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    rank INTEGER NOT NULL,
+    score INTEGER NOT NULL,
+
+    PRIMARY KEY (event_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_results_rank ON event_results(event_id, rank);
+`
+
+const migration016Down = `
+DROP TABLE IF EXISTS event_results;
+DROP TABLE IF EXISTS events;
+`
+
+const migration017Up = `
+-- Migration: Create personal bests
+-- Version: 017
+
+-- One row per student and metric; a row is only updated with a strictly
+-- greater value, so reprocessing the same day is a no-op.
+CREATE TABLE IF NOT EXISTS personal_bests (
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    metric VARCHAR(30) NOT NULL CHECK (metric IN ('best_day_xp', 'best_week_xp', 'longest_session_minutes')),
+    value INTEGER NOT NULL CHECK (value >= 0),
+    achieved_on DATE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (student_id, metric)
+);
+`
+
+const migration017Down = `
+DROP TABLE IF EXISTS personal_bests;
+`
+
+const migration018Up = `
+-- Migration: Add last digest date
+-- Version: 018
+
+-- Local date of the last daily digest sent to the student. Claimed before
+-- sending, so a retried digest shard never sends a second digest that day.
+ALTER TABLE students ADD COLUMN IF NOT EXISTS last_digest_date DATE;
+`
+
+const migration018Down = `
+ALTER TABLE students DROP COLUMN IF EXISTS last_digest_date;
+`
+
+const migration019Up = `
+-- Migration: Create help feedback
+-- Version: 019
+
+-- Set once when the requester gets the private "how useful was the help" poll
+ALTER TABLE help_requests
+    ADD COLUMN IF NOT EXISTS feedback_poll_sent_at TIMESTAMP WITH TIME ZONE;
+
+-- Private satisfaction score per help request, separate from endorsements.
+-- Only aggregates are ever read; the primary key rejects a second answer.
+CREATE TABLE IF NOT EXISTS help_feedback (
+    help_request_id UUID PRIMARY KEY REFERENCES help_requests(id) ON DELETE CASCADE,
+    score SMALLINT NOT NULL CHECK (score BETWEEN 1 AND 5),
+    source VARCHAR(20) NOT NULL CHECK (source IN ('poll', 'endorsement')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_help_feedback_created_at ON help_feedback(created_at);
+`
+
+const migration019Down = `
+DROP TABLE IF EXISTS help_feedback;
+
+ALTER TABLE help_requests DROP COLUMN IF EXISTS feedback_poll_sent_at;
+`
+
+const migration020Up = `
+-- Migration: Create trigger rules
+-- Version: 020
+
+-- Notification trigger rules, editable from the admin API. The full rule
+-- (conditions, time window, rate limit, cooldown, templates, tags) is kept
+-- as a versioned JSON document; enabled and notification_type are copied
+-- out for filtering and must match the document.
+CREATE TABLE IF NOT EXISTS trigger_rules (
+    id VARCHAR(100) PRIMARY KEY,
+    notification_type VARCHAR(50) NOT NULL,
+    is_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    rule JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_trigger_rules_enabled ON trigger_rules(is_enabled);
+`
+
+const migration020Down = `
+DROP TABLE IF EXISTS trigger_rules;
+`
+
+const migration021Up = `
+-- Migration: Create feature flags
+-- Version: 021
+
+-- Feature flags for gradual rollout (see pkg/featureflag). A disabled flag
+-- is off for everyone; allowlisted cohorts always get the feature; other
+-- students are bucketed by a hash of their ID into rollout_percent.
+-- fail_open decides the answer while processes cannot read this table.
+CREATE TABLE IF NOT EXISTS feature_flags (
+    name VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    rollout_percent SMALLINT NOT NULL DEFAULT 0 CHECK (rollout_percent BETWEEN 0 AND 100),
+    cohorts TEXT[] NOT NULL DEFAULT '{}',
+    fail_open BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+`
+
+const migration021Down = `
+DROP TABLE IF EXISTS feature_flags;
+`
+
+const migration022Up = `
+-- Migration: Create XP flags
+-- Version: 022
+
+-- Days whose XP gain is far above the student's usual pace, queued for
+-- curator review. One row per student and day; amount and z_score are
+-- refreshed while the flag is pending. excess is the XP above the usual
+-- day and is written to xp_history as a negative adjustment when a
+-- curator dismisses the day as abuse.
+CREATE TABLE IF NOT EXISTS xp_flags (
+    id BIGSERIAL PRIMARY KEY,
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    amount INTEGER NOT NULL,
+    z_score DOUBLE PRECISION NOT NULL,
+    excess INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'dismissed')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolved_by BIGINT,
+
+    UNIQUE (student_id, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_xp_flags_pending ON xp_flags(student_id) WHERE status = 'pending';
+`
+
+const migration022Down = `
+DROP TABLE IF EXISTS xp_flags;
+`
+
+const migration023Up = `
+-- Migration: Track celebrated streak milestones
+-- Version: 023
+
+-- Highest milestone (7, 14, 30, 60, 100 days) already celebrated in the
+-- current streak run. Reset to 0 together with the streak, so every
+-- milestone is celebrated once per run.
+ALTER TABLE streaks ADD COLUMN IF NOT EXISTS milestone_reached INTEGER NOT NULL DEFAULT 0;
+`
+
+const migration023Down = `
+ALTER TABLE streaks DROP COLUMN IF EXISTS milestone_reached;
+`
+
+const migration024Up = `
+-- Migration: Create student reports
+-- Version: 024
+
+-- Reports on introductions made by the bot (a helper and a requester who
+-- did not know each other). context_id is the connection or help request
+-- the report came from. reason is an optional follow-up message.
+-- Dismissed reports are kept but never counted.
+CREATE TABLE IF NOT EXISTS student_reports (
+    id BIGSERIAL PRIMARY KEY,
+    reporter_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    reported_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    context VARCHAR(20) NOT NULL CHECK (context IN ('connection', 'help_request')),
+    context_id VARCHAR(255) NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'upheld', 'dismissed')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolved_by BIGINT,
+
+    CHECK (reporter_id <> reported_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_student_reports_reported ON student_reports(reported_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_student_reports_pending ON student_reports(created_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_student_reports_reporter ON student_reports(reporter_id, created_at DESC);
+
+-- Set once a student collects enough upheld reports; helper matching
+-- skips them until a curator clears the flag.
+ALTER TABLE students ADD COLUMN IF NOT EXISTS help_matching_disabled BOOLEAN NOT NULL DEFAULT FALSE;
+`
+
+const migration024Down = `
+ALTER TABLE students DROP COLUMN IF EXISTS help_matching_disabled;
+DROP TABLE IF EXISTS student_reports;
+`
+
+const migration025Up = `
+-- Migration: Create focus sessions
+-- Version: 025
+
+-- Timed /focus sessions, alone or with one invited buddy. ended_at is NULL
+-- while the session runs: the bot restores its timers from these rows on
+-- startup. A student owns at most one running session.
+CREATE TABLE IF NOT EXISTS focus_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    owner_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    buddy_id UUID REFERENCES students(id) ON DELETE SET NULL,
+    buddy_status VARCHAR(20) NOT NULL DEFAULT '' CHECK (buddy_status IN ('', 'invited', 'accepted', 'declined')),
+    buddy_joined_at TIMESTAMP WITH TIME ZONE,
+    halfway_nudge BOOLEAN NOT NULL DEFAULT TRUE,
+    halfway_sent BOOLEAN NOT NULL DEFAULT FALSE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    owner_outcome VARCHAR(20) NOT NULL DEFAULT '',
+    buddy_outcome VARCHAR(20) NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_focus_sessions_active_owner ON focus_sessions(owner_id) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_focus_sessions_active_buddy ON focus_sessions(buddy_id) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_focus_sessions_owner ON focus_sessions(owner_id, started_at DESC);
+`
+
+const migration025Down = `
+DROP TABLE IF EXISTS focus_sessions;
+`
+
+const migration026Up = `
+-- Migration: Create xp history monthly rollup
+-- Version: 026
+
+-- Monthly per-reason XP totals of pruned xp_history months. A raw row is
+-- deleted only after its (student, month) has a rollup; once it does, the
+-- rollup is the source of truth for that month and raw rows are ignored.
+CREATE TABLE IF NOT EXISTS xp_history_monthly (
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    month DATE NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    delta BIGINT NOT NULL,
+    entries INTEGER NOT NULL,
+    PRIMARY KEY (student_id, month, reason)
+);
+
+CREATE INDEX IF NOT EXISTS idx_xp_history_monthly_reason ON xp_history_monthly(student_id, reason);
+
+-- Retention deletes by age alone
+CREATE INDEX IF NOT EXISTS idx_notifications_created_at ON notifications(created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_occurred_at ON audit_log(occurred_at);
+`
+
+const migration026Down = `
+DROP INDEX IF EXISTS idx_audit_log_occurred_at;
+DROP INDEX IF EXISTS idx_notifications_created_at;
+DROP TABLE IF EXISTS xp_history_monthly;
+`
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MIGRATION 027: CREATE PENDING ANNOUNCEMENTS
+// ══════════════════════════════════════════════════════════════════════════════
+
+const migration027Up = `
+-- Migration: Create pending announcements
+-- Version: 027
+
+-- Group chat announcements held back during the chat's quiet hours
+CREATE TABLE IF NOT EXISTS pending_announcements (
+    id UUID PRIMARY KEY,
+    chat_id BIGINT NOT NULL,
+    dedup_key VARCHAR(100) NOT NULL DEFAULT '',
+    message TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deliver_after TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_announcements_due ON pending_announcements(deliver_after, created_at);
+`
+
+const migration027Down = `
+DROP TABLE IF EXISTS pending_announcements;
+`
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MIGRATION 028: INDEX HELP REQUESTS FOR POPULAR TASKS
+// ══════════════════════════════════════════════════════════════════════════════
+
+const migration028Up = `
+-- Migration: Index help requests for popular tasks
+-- Version: 028
+
+-- Popular tasks aggregate recent requests per task without touching the heap
+CREATE INDEX IF NOT EXISTS idx_help_requests_created_task
+    ON help_requests(created_at, task_id) INCLUDE (task_name, status, resolved_at);
+`
+
+const migration028Down = `
+DROP INDEX IF EXISTS idx_help_requests_created_task;
+`
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MIGRATION 029: ADD STUDENT ALEM LOGIN
+// ══════════════════════════════════════════════════════════════════════════════
+
+const migration029Up = `
+-- Migration: Add student alem login
+-- Version: 029
+
+-- The Alem login never changes while the email on the platform can.
+-- Legacy rows keep NULL until sync backfills the login from the API.
+-- Databases that still have the original NOT NULL column get it relaxed.
+ALTER TABLE students ADD COLUMN IF NOT EXISTS alem_login VARCHAR(50);
+ALTER TABLE students ALTER COLUMN alem_login DROP NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_students_alem_login_unique ON students(alem_login) WHERE alem_login IS NOT NULL;
+
+-- Pairs where the login and the email point at different students.
+-- Sync does not guess which one is right: an admin resolves them via /merge.
+CREATE TABLE IF NOT EXISTS identity_conflicts (
+    login_student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    email_student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    alem_login VARCHAR(50) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (login_student_id, email_student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_identity_conflicts_detected_at ON identity_conflicts(detected_at);
+`
+
+const migration029Down = `
+DROP TABLE IF EXISTS identity_conflicts;
+DROP INDEX IF EXISTS idx_students_alem_login_unique;
+ALTER TABLE students DROP COLUMN IF EXISTS alem_login;
+`
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MIGRATION 030: CREATE DRY RUN OUTBOX
+// ══════════════════════════════════════════════════════════════════════════════
+
+const migration030Up = `
+-- Migration: Create dry run outbox
+-- Version: 030
+
+-- Messages the worker would have sent while running in dry-run mode.
+-- Nothing here is ever delivered; admins inspect and purge it via the API.
+CREATE TABLE IF NOT EXISTS dry_run_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    recipient_id VARCHAR(64) NOT NULL DEFAULT '',
+    chat_id BIGINT NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    message TEXT NOT NULL,
+    job_name VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dry_run_outbox_job_created ON dry_run_outbox(job_name, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_dry_run_outbox_created ON dry_run_outbox(created_at DESC);
+`
+
+const migration030Down = `
+DROP TABLE IF EXISTS dry_run_outbox;
+`
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MIGRATION 031: CLUSTER HELP REQUESTS
+// ══════════════════════════════════════════════════════════════════════════════
+
+const migration031Up = `
+-- Migration: Cluster help requests
+-- Version: 031
+
+-- Near-identical requests for the same task from one cohort share a cluster.
+-- A new request opens its own cluster (cluster_id = id) unless it joins an
+-- older one; legacy rows keep NULL.
+ALTER TABLE help_requests
+    ADD COLUMN IF NOT EXISTS cluster_id UUID,
+    ADD COLUMN IF NOT EXISTS group_opt_in_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_help_requests_cluster ON help_requests(cluster_id)
+    WHERE cluster_id IS NOT NULL;
+
+-- Duplicate lookup: open requests on a task created within the window
+CREATE INDEX IF NOT EXISTS idx_help_requests_open_task_created ON help_requests(task_id, created_at)
+    WHERE status IN ('open', 'matched', 'in_progress');
+
+-- Helpers notified about a cluster; the primary key rejects a second ping.
+CREATE TABLE IF NOT EXISTS help_cluster_notifications (
+    cluster_id UUID NOT NULL,
+    helper_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    notified_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (cluster_id, helper_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_help_cluster_notifications_notified_at ON help_cluster_notifications(notified_at);
+`
+
+const migration031Down = `
+DROP TABLE IF EXISTS help_cluster_notifications;
+DROP INDEX IF EXISTS idx_help_requests_open_task_created;
+DROP INDEX IF EXISTS idx_help_requests_cluster;
+
+ALTER TABLE help_requests
+    DROP COLUMN IF EXISTS group_opt_in_at,
+    DROP COLUMN IF EXISTS cluster_id;
+`
+
+const migration032Up = `
+-- Migration: Helper streaks
+-- Version: 032
+
+-- Consecutive ISO weeks (Almaty time) in which a student resolved a help
+-- request as the helper or received an endorsement. Kept next to the daily
+-- streaks table and recomputed weekly from help_requests and endorsements.
+CREATE TABLE IF NOT EXISTS helper_streaks (
+    student_id UUID PRIMARY KEY REFERENCES students(id) ON DELETE CASCADE,
+    current_weeks INTEGER NOT NULL DEFAULT 0,
+    best_weeks INTEGER NOT NULL DEFAULT 0,
+    last_helped_week VARCHAR(8) NOT NULL DEFAULT '',
+    evaluated_week VARCHAR(8) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_helper_streaks_active ON helper_streaks(current_weeks)
+    WHERE current_weeks > 0;
+
+-- Weekly activity lookup; endorsements are already indexed by created_at
+CREATE INDEX IF NOT EXISTS idx_help_requests_resolved_helper ON help_requests(resolved_at, helper_id)
+    WHERE status = 'resolved' AND helper_id IS NOT NULL;
+`
+
+const migration032Down = `
+DROP INDEX IF EXISTS idx_help_requests_resolved_helper;
+DROP TABLE IF EXISTS helper_streaks;
+`
+
+const migration033Up = `
+-- Migration: Student timezone
+-- Version: 033
+
+-- Quiet hours, the digest hour and the day boundaries of streaks and daily
+-- progress follow the student's own time zone (preferences->>'timezone',
+-- an IANA name). Everyone starts in Almaty; corrupted blobs are left to the
+-- preferences decoder.
+UPDATE students
+SET preferences = preferences || '{"timezone": "Asia/Almaty"}'::jsonb
+WHERE jsonb_typeof(preferences) = 'object' AND NOT preferences ? 'timezone';
+
+ALTER TABLE students ALTER COLUMN preferences SET DEFAULT '{
+    "rank_changes": true,
+    "daily_digest": true,
+    "help_requests": true,
+    "inactivity_reminders": true,
+    "quiet_hours_start": 23,
+    "quiet_hours_end": 8,
+    "timezone": "Asia/Almaty"
+}'::jsonb;
+`
+
+const migration033Down = `
+ALTER TABLE students ALTER COLUMN preferences SET DEFAULT '{
+    "rank_changes": true,
+    "daily_digest": true,
+    "help_requests": true,
+    "inactivity_reminders": true,
+    "quiet_hours_start": 23,
+    "quiet_hours_end": 8
+}'::jsonb;
+
+UPDATE students
+SET preferences = preferences - 'timezone'
+WHERE jsonb_typeof(preferences) = 'object' AND preferences ? 'timezone';
+`
+
+const migration034Up = `
+-- Migration: Create help snoozes
+-- Version: 034
+
+-- "⏰ Напомнить через 2 часа" on a help invitation. The worker re-sends the
+-- invitation at remind_at and sets sent_at. A helper snoozes a request at
+-- most twice: attempt is 1 or 2 and unique per (request, helper).
+CREATE TABLE IF NOT EXISTS help_snoozes (
+    id BIGSERIAL PRIMARY KEY,
+    help_request_id UUID NOT NULL REFERENCES help_requests(id) ON DELETE CASCADE,
+    helper_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    attempt SMALLINT NOT NULL CHECK (attempt BETWEEN 1 AND 2),
+    remind_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT help_snoozes_attempt_unique UNIQUE (help_request_id, helper_id, attempt)
+);
+
+CREATE INDEX IF NOT EXISTS idx_help_snoozes_due ON help_snoozes(remind_at) WHERE sent_at IS NULL;
+`
+
+const migration034Down = `
+DROP TABLE IF EXISTS help_snoozes;
+`
+
+const migration035Up = `
+-- Migration: Create feed items
+-- Version: 035
+
+-- The community activity feed (/feed, GET /api/v1/feed), projected from
+-- domain events. actor_name is a snapshot taken when the event happened.
+-- Items of students hidden from the leaderboard are stored as 'hidden'.
+-- The feed pages by id (keyset), so new items never shift older pages;
+-- items are pruned after 30 days.
+CREATE TABLE IF NOT EXISTS feed_items (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(30) NOT NULL CHECK (type IN ('achievement_unlocked', 'help_resolved', 'personal_best', 'student_joined', 'helper_of_the_week')),
+    actor_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    actor_name VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+    cohort VARCHAR(50) NOT NULL DEFAULT '',
+    visibility VARCHAR(10) NOT NULL DEFAULT 'public' CHECK (visibility IN ('public', 'hidden')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_feed_items_cohort ON feed_items(cohort, id DESC) WHERE visibility = 'public';
+CREATE INDEX IF NOT EXISTS idx_feed_items_created_at ON feed_items(created_at);
+`
+
+const migration035Down = `
+DROP TABLE IF EXISTS feed_items;
+`
+
+const migration036Up = `
+-- Migration: Create access tokens
+-- Version: 036
+
+-- Personal read-only API tokens of students (/token). Only the SHA-256 of
+-- a token is stored; hint is its last characters for the /token list.
+-- At most 2 active tokens per student, each valid for 180 days.
+CREATE TABLE IF NOT EXISTS access_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    hint VARCHAR(8) NOT NULL,
+    scope VARCHAR(30) NOT NULL DEFAULT 'self:read',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_tokens_student ON access_tokens(student_id) WHERE revoked_at IS NULL;
+`
+
+const migration036Down = `
+DROP TABLE IF EXISTS access_tokens;
+`
+
+const migration037Up = `
+-- Migration: Create content blocklist
+-- Version: 037
+
+-- Phrases masked in help request descriptions and endorsement comments
+-- when they are shown to other students. Managed through the admin API;
+-- phrases are stored lowercased.
+CREATE TABLE IF NOT EXISTS content_blocklist (
+    phrase VARCHAR(100) PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+`
+
+const migration037Down = `
+DROP TABLE IF EXISTS content_blocklist;
+`
+
+const migration038Up = `
+-- Migration: Track daily digest deliveries
+-- Version: 038
+
+-- Daily digests are recorded as notifications, one row per student and
+-- local date (digest_date). error_category is the category of the last
+-- failed attempt; carry_over marks a digest that was never delivered and
+-- whose highlights go into the student's next digest.
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS digest_date DATE;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS error_category VARCHAR(30) NOT NULL DEFAULT '';
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS carry_over BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_digest_failed ON notifications(digest_date)
+    WHERE digest_date IS NOT NULL AND status = 'failed' AND NOT carry_over;
+CREATE INDEX IF NOT EXISTS idx_notifications_digest_carry_over ON notifications(recipient_id, digest_date)
+    WHERE carry_over;
+`
+
+const migration038Down = `
+DROP INDEX IF EXISTS idx_notifications_digest_carry_over;
+DROP INDEX IF EXISTS idx_notifications_digest_failed;
+ALTER TABLE notifications DROP COLUMN IF EXISTS carry_over;
+ALTER TABLE notifications DROP COLUMN IF EXISTS error_category;
+ALTER TABLE notifications DROP COLUMN IF EXISTS digest_date;
+`
+
+const migration039Up = `
+-- Migration: Team help requests
+-- Version: 039
+
+-- Teammates invited into a help request. Only accepted members take part in
+-- the request; invitations lapse with the request itself.
+CREATE TABLE IF NOT EXISTS help_request_members (
+    help_request_id UUID NOT NULL REFERENCES help_requests(id) ON DELETE CASCADE,
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'invited',
+    invited_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    responded_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (help_request_id, student_id),
+    CONSTRAINT valid_help_member_status CHECK (status IN ('invited', 'accepted', 'declined'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_help_request_members_student ON help_request_members(student_id)
+    WHERE status = 'accepted';
+
+-- Every member of a team request may endorse the helper, but the request
+-- counts once: help_count counts requests, and a request enters help_rating
+-- as the average of its endorsements.
+CREATE OR REPLACE FUNCTION update_help_rating()
+RETURNS TRIGGER AS $$
+BEGIN
+    WITH per_request AS (
+        SELECT AVG(rating) AS rating
+        FROM endorsements
+        WHERE to_student_id = NEW.to_student_id
+        GROUP BY COALESCE(help_request_id, id)
+    )
+    UPDATE students
+    SET
+        help_rating = (SELECT COALESCE(AVG(rating)::DECIMAL(3,2), 0) FROM per_request),
+        help_count = (SELECT COUNT(*) FROM per_request)
+    WHERE id = NEW.to_student_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+`
+
+const migration039Down = `
+CREATE OR REPLACE FUNCTION update_help_rating()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE students
+    SET 
+        help_rating = (
+            SELECT COALESCE(AVG(rating)::DECIMAL(3,2), 0)
+            FROM endorsements
+            WHERE to_student_id = NEW.to_student_id
+        ),
+        help_count = (
+            SELECT COUNT(*)
+            FROM endorsements
+            WHERE to_student_id = NEW.to_student_id
+        )
+    WHERE id = NEW.to_student_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS idx_help_request_members_student;
+DROP TABLE IF EXISTS help_request_members;
+`
+
+const migration040Up = `
+-- A/B experiments with the wording of one notification type. The name is
+-- part of the assignment hash, so it never changes.
+CREATE TABLE IF NOT EXISTS experiments (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    notification_type VARCHAR(50) NOT NULL,
+    variants JSONB NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'stopped')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_experiments_running ON experiments(starts_at) WHERE status = 'running';
+
+-- The first exposure of a student; written only after the notification
+-- was sent.
+CREATE TABLE IF NOT EXISTS experiment_assignments (
+    experiment_id UUID NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    variant VARCHAR(50) NOT NULL,
+    notification_id VARCHAR(100),
+    exposed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (experiment_id, student_id)
+);
+
+-- Per-variant next-day activity, recomputed by the worker.
+CREATE TABLE IF NOT EXISTS experiment_results (
+    experiment_id UUID NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+    variant VARCHAR(50) NOT NULL,
+    exposed INTEGER NOT NULL DEFAULT 0,
+    converted INTEGER NOT NULL DEFAULT 0,
+    rate DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ci_low DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ci_high DOUBLE PRECISION NOT NULL DEFAULT 0,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (experiment_id, variant)
+);
+`
+
+const migration040Down = `
+DROP TABLE IF EXISTS experiment_results;
+DROP TABLE IF EXISTS experiment_assignments;
+DROP INDEX IF EXISTS idx_experiments_running;
+DROP TABLE IF EXISTS experiments;
+`
+
+const migration041Up = `
+-- Full-history backfill of task completions from Alem. At most one run is
+-- in progress; a new one starts once it is cancelled or completed.
+CREATE TABLE IF NOT EXISTS backfill_runs (
+    id UUID PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'running'
+        CHECK (status IN ('running', 'paused', 'cancelled', 'completed')),
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    rate_share DOUBLE PRECISION NOT NULL CHECK (rate_share > 0 AND rate_share <= 1),
+    students_total INTEGER NOT NULL DEFAULT 0,
+    students_done INTEGER NOT NULL DEFAULT 0,
+    last_student_id UUID,
+    pages INTEGER NOT NULL DEFAULT 0,
+    inserted INTEGER NOT NULL DEFAULT 0,
+    active_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_backfill_runs_active ON backfill_runs ((TRUE))
+    WHERE status IN ('running', 'paused');
+
+-- Per-student cursor: the last page stored, so an interrupted run resumes
+-- without fetching it again.
+CREATE TABLE IF NOT EXISTS backfill_progress (
+    run_id UUID NOT NULL REFERENCES backfill_runs(id) ON DELETE CASCADE,
+    student_id UUID NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    page INTEGER NOT NULL DEFAULT 0,
+    last_completed_at TIMESTAMP WITH TIME ZONE,
+    done BOOLEAN NOT NULL DEFAULT FALSE,
+    inserted INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (run_id, student_id)
+);
+`
+
+const migration041Down = `
+DROP TABLE IF EXISTS backfill_progress;
+DROP INDEX IF EXISTS idx_backfill_runs_active;
+DROP TABLE IF EXISTS backfill_runs;
+`
+
+const migration042Up = `
+-- Batch sync runs of SyncAllStudentsJob. The last completed run is the
+-- "last sync time"; a run left in 'running' means the worker died mid-run.
+CREATE TABLE IF NOT EXISTS sync_runs (
+    id BIGSERIAL PRIMARY KEY,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(20) NOT NULL DEFAULT 'running'
+        CHECK (status IN ('running', 'completed', 'failed')),
+    students_total INTEGER NOT NULL DEFAULT 0,
+    students_synced INTEGER NOT NULL DEFAULT 0,
+    students_failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_runs_completed ON sync_runs (finished_at DESC)
+    WHERE status = 'completed';
+
+-- Per-student sync status: the outcome of the last attempt and the number
+-- of consecutive failures. last_synced_at only moves on success.
+ALTER TABLE students ADD COLUMN IF NOT EXISTS last_sync_status VARCHAR(20)
+    CHECK (last_sync_status IN ('ok', 'failed'));
+ALTER TABLE students ADD COLUMN IF NOT EXISTS last_sync_error_type VARCHAR(50);
+ALTER TABLE students ADD COLUMN IF NOT EXISTS last_sync_error TEXT;
+ALTER TABLE students ADD COLUMN IF NOT EXISTS last_sync_failed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE students ADD COLUMN IF NOT EXISTS sync_failures INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_students_sync_due ON students (last_synced_at)
+    WHERE status != 'left';
+`
+
+const migration042Down = `
+DROP INDEX IF EXISTS idx_students_sync_due;
+ALTER TABLE students DROP COLUMN IF EXISTS sync_failures;
+ALTER TABLE students DROP COLUMN IF EXISTS last_sync_failed_at;
+ALTER TABLE students DROP COLUMN IF EXISTS last_sync_error;
+ALTER TABLE students DROP COLUMN IF EXISTS last_sync_error_type;
+ALTER TABLE students DROP COLUMN IF EXISTS last_sync_status;
+DROP INDEX IF EXISTS idx_sync_runs_completed;
+DROP TABLE IF EXISTS sync_runs;
+`
+
+const migration043Up = `
+-- Connections keep their whole lifecycle: pending requests, accepted and
+-- ended connections. Rows stored so far are established connections.
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active';
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS task_id VARCHAR(100);
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS help_request_id UUID REFERENCES help_requests(id) ON DELETE SET NULL;
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS note TEXT;
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS interaction_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS total_help_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS tasks_solved_together INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS last_interaction_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS accepted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS ended_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS end_reason TEXT;
+
+UPDATE connections SET accepted_at = created_at, updated_at = created_at WHERE accepted_at IS NULL;
+
+ALTER TABLE connections DROP CONSTRAINT IF EXISTS valid_connection_status;
+ALTER TABLE connections ADD CONSTRAINT valid_connection_status
+    CHECK (status IN ('pending', 'active', 'declined', 'ended'));
+
+-- 'peer' stays for rows written before helper and coworker were stored as is
+ALTER TABLE connections DROP CONSTRAINT IF EXISTS valid_connection_type;
+ALTER TABLE connections ADD CONSTRAINT valid_connection_type
+    CHECK (connection_type IN ('peer', 'helper', 'coworker', 'mentor', 'study_buddy'));
+
+CREATE INDEX IF NOT EXISTS idx_connections_pending ON connections(to_student_id)
+    WHERE status = 'pending';
+
+-- Endorsements remember their kind, task and visibility
+ALTER TABLE endorsements ADD COLUMN IF NOT EXISTS endorsement_type VARCHAR(20);
+ALTER TABLE endorsements ADD COLUMN IF NOT EXISTS task_id VARCHAR(100);
+ALTER TABLE endorsements ADD COLUMN IF NOT EXISTS is_public BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE INDEX IF NOT EXISTS idx_endorsements_task ON endorsements(task_id)
+    WHERE task_id IS NOT NULL;
+
+-- Deleting an endorsement recomputes the receiver's rating as well
+CREATE OR REPLACE FUNCTION update_help_rating()
+RETURNS TRIGGER AS $$
+DECLARE
+    receiver UUID := CASE WHEN TG_OP = 'DELETE' THEN OLD.to_student_id ELSE NEW.to_student_id END;
+BEGIN
+    WITH per_request AS (
+        SELECT AVG(rating) AS rating
+        FROM endorsements
+        WHERE to_student_id = receiver
+        GROUP BY COALESCE(help_request_id, id)
+    )
+    UPDATE students
+    SET
+        help_rating = (SELECT COALESCE(AVG(rating)::DECIMAL(3,2), 0) FROM per_request),
+        help_count = (SELECT COUNT(*) FROM per_request)
+    WHERE id = receiver;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS update_help_rating_trigger ON endorsements;
+CREATE TRIGGER update_help_rating_trigger
+    AFTER INSERT OR DELETE ON endorsements
+    FOR EACH ROW
+    EXECUTE FUNCTION update_help_rating();
+`
+
+const migration043Down = `
+CREATE OR REPLACE FUNCTION update_help_rating()
+RETURNS TRIGGER AS $$
+BEGIN
+    WITH per_request AS (
+        SELECT AVG(rating) AS rating
+        FROM endorsements
+        WHERE to_student_id = NEW.to_student_id
+        GROUP BY COALESCE(help_request_id, id)
+    )
+    UPDATE students
+    SET
+        help_rating = (SELECT COALESCE(AVG(rating)::DECIMAL(3,2), 0) FROM per_request),
+        help_count = (SELECT COUNT(*) FROM per_request)
+    WHERE id = NEW.to_student_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS update_help_rating_trigger ON endorsements;
+CREATE TRIGGER update_help_rating_trigger
+    AFTER INSERT ON endorsements
+    FOR EACH ROW
+    EXECUTE FUNCTION update_help_rating();
+
+DROP INDEX IF EXISTS idx_endorsements_task;
+ALTER TABLE endorsements DROP COLUMN IF EXISTS is_public;
+ALTER TABLE endorsements DROP COLUMN IF EXISTS task_id;
+ALTER TABLE endorsements DROP COLUMN IF EXISTS endorsement_type;
+
+DROP INDEX IF EXISTS idx_connections_pending;
+DELETE FROM connections WHERE status <> 'active';
+UPDATE connections SET connection_type = 'peer' WHERE connection_type IN ('helper', 'coworker');
+ALTER TABLE connections DROP CONSTRAINT IF EXISTS valid_connection_type;
+ALTER TABLE connections ADD CONSTRAINT valid_connection_type
+    CHECK (connection_type IN ('peer', 'mentor', 'study_buddy'));
+ALTER TABLE connections DROP CONSTRAINT IF EXISTS valid_connection_status;
+ALTER TABLE connections DROP COLUMN IF EXISTS end_reason;
+ALTER TABLE connections DROP COLUMN IF EXISTS ended_at;
+ALTER TABLE connections DROP COLUMN IF EXISTS accepted_at;
+ALTER TABLE connections DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE connections DROP COLUMN IF EXISTS last_interaction_at;
+ALTER TABLE connections DROP COLUMN IF EXISTS tasks_solved_together;
+ALTER TABLE connections DROP COLUMN IF EXISTS total_help_minutes;
+ALTER TABLE connections DROP COLUMN IF EXISTS interaction_count;
+ALTER TABLE connections DROP COLUMN IF EXISTS note;
+ALTER TABLE connections DROP COLUMN IF EXISTS help_request_id;
+ALTER TABLE connections DROP COLUMN IF EXISTS task_id;
+ALTER TABLE connections DROP COLUMN IF EXISTS status;
+`
+
+const migration044Up = `
+-- A student has at most one open or matched help request per task, so two
+-- /help taps arriving together cannot both create one. Duplicates stored so
+-- far are cancelled, keeping the oldest request the helpers were pinged about.
+UPDATE help_requests h
+SET status = 'cancelled', updated_at = NOW()
+WHERE h.status IN ('open', 'matched')
+  AND EXISTS (
+    SELECT 1 FROM help_requests o
+    WHERE o.requester_id = h.requester_id
+      AND o.task_id = h.task_id
+      AND o.status IN ('open', 'matched')
+      AND (o.created_at, o.id) < (h.created_at, h.id)
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_help_requests_open_requester_task
+    ON help_requests(requester_id, task_id) WHERE status IN ('open', 'matched');
+`
+
+const migration044Down = `
+DROP INDEX IF EXISTS idx_help_requests_open_requester_task;
+`
+
+const migration045Up = `
+-- The helpers matched when a request was created, shown again when the
+-- student asks about the same task while the request is open.
+ALTER TABLE help_requests ADD COLUMN IF NOT EXISTS matched_helpers JSONB NOT NULL DEFAULT '[]';
+`
+
+const migration045Down = `
+ALTER TABLE help_requests DROP COLUMN IF EXISTS matched_helpers;
+`
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMigrationDB records applied migrations in memory. failures[v] errors
+// are returned by successive apply calls for version v; commitThenFail
+// versions are recorded before their error is returned, like a connection
+// dropped while COMMIT was in flight.
+type fakeMigrationDB struct {
+	recorded       map[int]time.Time
+	calls          map[int]int
+	failures       map[int][]error
+	commitThenFail map[int]bool
+}
+
+func newFakeMigrationDB() *fakeMigrationDB {
+	return &fakeMigrationDB{
+		recorded:       make(map[int]time.Time),
+		calls:          make(map[int]int),
+		failures:       make(map[int][]error),
+		commitThenFail: make(map[int]bool),
+	}
+}
+
+func (f *fakeMigrationDB) ensureTable(ctx context.Context) error { return nil }
+
+func (f *fakeMigrationDB) applied(ctx context.Context) (map[int]time.Time, error) {
+	applied := make(map[int]time.Time, len(f.recorded))
+	for v, at := range f.recorded {
+		applied[v] = at
+	}
+	return applied, nil
+}
+
+func (f *fakeMigrationDB) apply(ctx context.Context, mig Migration) error {
+	f.calls[mig.Version]++
+	if errs := f.failures[mig.Version]; len(errs) > 0 {
+		f.failures[mig.Version] = errs[1:]
+		if f.commitThenFail[mig.Version] {
+			f.recorded[mig.Version] = time.Now()
+		}
+		return errs[0]
+	}
+	if _, ok := f.recorded[mig.Version]; ok {
+		return fmt.Errorf("migration %d applied twice", mig.Version)
+	}
+	f.recorded[mig.Version] = time.Now()
+	return nil
+}
+
+func newTestMigrator(db migrationDB) *Migrator {
+	m := NewMigratorWithMigrations(nil, []Migration{
+		{Version: 1, Name: "one", UpSQL: "SELECT 1"},
+		{Version: 2, Name: "two", UpSQL: "SELECT 2"},
+		{Version: 3, Name: "three", UpSQL: "SELECT 3"},
+	}).WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	m.db = db
+	m.retryBackoff = time.Millisecond
+	return m
+}
+
+func TestMigrator_RetriesTransientFailure(t *testing.T) {
+	db := newFakeMigrationDB()
+	db.failures[2] = []error{&pgconn.PgError{Code: "08006", Message: "connection failure"}}
+
+	require.NoError(t, newTestMigrator(db).Migrate(context.Background()))
+
+	assert.Len(t, db.recorded, 3)
+	assert.Equal(t, 1, db.calls[1])
+	assert.Equal(t, 2, db.calls[2], "second migration retried once")
+	assert.Equal(t, 1, db.calls[3])
+}
+
+func TestMigrator_DoesNotReapplyCommittedMigration(t *testing.T) {
+	db := newFakeMigrationDB()
+	db.failures[2] = []error{io.ErrUnexpectedEOF}
+	db.commitThenFail[2] = true
+
+	require.NoError(t, newTestMigrator(db).Migrate(context.Background()))
+
+	assert.Len(t, db.recorded, 3)
+	assert.Equal(t, 1, db.calls[2], "committed migration must not run again")
+}
+
+func TestMigrator_StopsOnPermanentOrRepeatedFailure(t *testing.T) {
+	syntax := &pgconn.PgError{Code: "42601", Message: "syntax error"}
+	lockTimeout := &pgconn.PgError{Code: "55P03", Message: "lock timeout"}
+
+	tests := []struct {
+		name      string
+		failures  []error
+		wantCalls int
+	}{
+		{"permanent error is not retried", []error{syntax}, 1},
+		{"transient error gives up after three attempts", []error{lockTimeout, lockTimeout, lockTimeout}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newFakeMigrationDB()
+			db.failures[2] = tt.failures
+
+			err := newTestMigrator(db).Migrate(context.Background())
+			assert.ErrorIs(t, err, ErrMigrationFailed)
+			assert.Equal(t, tt.wantCalls, db.calls[2])
+			assert.NotContains(t, db.recorded, 2)
+			assert.NotContains(t, db.recorded, 3, "later migrations are not applied")
+		})
+	}
+}
+
+func TestMigrator_CheckSchemaVersion(t *testing.T) {
+	db := newFakeMigrationDB()
+	m := newTestMigrator(db)
+
+	db.recorded[MinSchemaVersion-1] = time.Now()
+	assert.ErrorIs(t, m.CheckSchemaVersion(context.Background()), ErrSchemaOutdated)
+
+	db.recorded[MinSchemaVersion] = time.Now()
+	assert.NoError(t, m.CheckSchemaVersion(context.Background()))
+}
+
+func TestMinSchemaVersionCoversMigrations(t *testing.T) {
+	latest := 0
+	for _, m := range GetMigrations() {
+		assert.LessOrEqual(t, m.Version, MinSchemaVersion,
+			"migration %d (%s) is newer than MinSchemaVersion: bump it", m.Version, m.Name)
+		latest = max(latest, m.Version)
+	}
+	assert.Equal(t, latest, MinSchemaVersion, "no migration brings the schema to MinSchemaVersion")
+}
+
+func TestIsTransient(t *testing.T) {
+	assert.True(t, IsTransient(&pgconn.PgError{Code: "40001"}))
+	assert.True(t, IsTransient(fmt.Errorf("exec: %w", &pgconn.PgError{Code: "08003"})))
+	assert.True(t, IsTransient(io.EOF))
+	assert.False(t, IsTransient(&pgconn.PgError{Code: "23505"}))
+	assert.False(t, IsTransient(context.DeadlineExceeded))
+	assert.False(t, IsTransient(errors.New("boom")))
+	assert.False(t, IsTransient(nil))
+}
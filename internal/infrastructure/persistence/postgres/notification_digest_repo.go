@@ -0,0 +1,217 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Daily digest deliveries
+// ─────────────────────────────────────────────────────────────────────────────
+
+// A daily digest is one notification row per student and local date
+// (digest_date), updated by every attempt: retry_count counts the attempts,
+// error_category is the category of the last failure. The day's highlights
+// are kept in metadata for carrying over.
+
+// digestNotificationID returns the row ID of a student's digest of a date.
+func digestNotificationID(studentID string, date time.Time) string {
+	return "digest-" + studentID + "-" + digestDate(date).Format("2006-01-02")
+}
+
+const digestAttemptColumns = `
+	recipient_id, telegram_chat_id, digest_date, status, retry_count,
+	error_category, last_error, updated_at, carry_over,
+	COALESCE((metadata->>'digest_xp')::int, 0), COALESCE((metadata->>'digest_tasks')::int, 0)
+`
+
+// RecordDigestAttempt records one attempt to deliver the student's digest
+// of a date. A delivered digest supersedes earlier unread digests in the
+// notification center; a failed one stays out of it.
+func (r *NotificationRepository) RecordDigestAttempt(ctx context.Context, attempt notification.DigestAttempt, message string) error {
+	metadata, err := json.Marshal(map[string]string{
+		"digest_xp":    strconv.Itoa(attempt.Highlights.XP),
+		"digest_tasks": strconv.Itoa(attempt.Highlights.Tasks),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal digest metadata: %w", err)
+	}
+
+	status := notification.StatusFailed
+	var deliveredAt *time.Time
+	if attempt.Delivered {
+		status = notification.StatusDelivered
+		deliveredAt = &attempt.LastAttemptAt
+	}
+	id := digestNotificationID(attempt.StudentID, attempt.Date)
+
+	query := `
+		INSERT INTO notifications (
+			id, type, recipient_id, telegram_chat_id, priority, status, message, metadata,
+			sent_at, delivered_at, retry_count, max_retries, last_error, error_category,
+			digest_date, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 1, $11, $12, $13, $14, $9, $9)
+		ON CONFLICT (id) DO UPDATE SET
+			telegram_chat_id = EXCLUDED.telegram_chat_id,
+			status = EXCLUDED.status,
+			message = EXCLUDED.message,
+			metadata = EXCLUDED.metadata,
+			sent_at = EXCLUDED.sent_at,
+			delivered_at = EXCLUDED.delivered_at,
+			retry_count = notifications.retry_count + 1,
+			last_error = EXCLUDED.last_error,
+			error_category = EXCLUDED.error_category,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, query,
+			id,
+			string(notification.NotificationTypeDailyDigest),
+			attempt.StudentID,
+			int64(attempt.ChatID),
+			int(notification.PriorityLow),
+			string(status),
+			message,
+			metadata,
+			attempt.LastAttemptAt,
+			deliveredAt,
+			notification.DigestMaxAttempts,
+			attempt.LastError,
+			string(attempt.ErrorCategory),
+			digestDate(attempt.Date),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record digest attempt: %w", err)
+		}
+
+		if attempt.Delivered {
+			return r.supersedePrevious(ctx, tx, notification.NotificationID(id))
+		}
+		return nil
+	})
+}
+
+// GetFailedDigests returns undelivered digests of dates on or after since
+// that are not carried over yet.
+func (r *NotificationRepository) GetFailedDigests(ctx context.Context, since time.Time) ([]notification.DigestAttempt, error) {
+	query := `
+		SELECT ` + digestAttemptColumns + `
+		FROM notifications
+		WHERE digest_date IS NOT NULL AND digest_date >= $1
+		  AND status = 'failed' AND NOT carry_over
+		ORDER BY updated_at
+	`
+	return r.queryDigestAttempts(ctx, query, digestDate(since))
+}
+
+// MarkDigestCarryOver flags the student's undelivered digest of a date, so
+// the next digest shows its highlights.
+func (r *NotificationRepository) MarkDigestCarryOver(ctx context.Context, studentID string, date time.Time) error {
+	query := `
+		UPDATE notifications SET carry_over = TRUE, updated_at = NOW()
+		WHERE id = $1 AND status = 'failed'
+	`
+
+	if _, err := r.conn.Exec(ctx, query, digestNotificationID(studentID, date)); err != nil {
+		return fmt.Errorf("failed to mark digest carry-over: %w", err)
+	}
+	return nil
+}
+
+// GetDigestCarryOver returns the student's latest carried-over digest
+// before a date, or nil if there is none.
+func (r *NotificationRepository) GetDigestCarryOver(ctx context.Context, studentID string, before time.Time) (*notification.DigestAttempt, error) {
+	query := `
+		SELECT ` + digestAttemptColumns + `
+		FROM notifications
+		WHERE recipient_id = $1 AND carry_over AND digest_date < $2
+		ORDER BY digest_date DESC
+		LIMIT 1
+	`
+
+	attempts, err := r.queryDigestAttempts(ctx, query, studentID, digestDate(before))
+	if err != nil || len(attempts) == 0 {
+		return nil, err
+	}
+	return &attempts[0], nil
+}
+
+// ClearDigestCarryOver clears the student's carry-over flags before a date
+// once a digest that showed them was delivered.
+func (r *NotificationRepository) ClearDigestCarryOver(ctx context.Context, studentID string, before time.Time) error {
+	query := `
+		UPDATE notifications SET carry_over = FALSE, updated_at = NOW()
+		WHERE recipient_id = $1 AND carry_over AND digest_date < $2
+	`
+
+	if _, err := r.conn.Exec(ctx, query, studentID, digestDate(before)); err != nil {
+		return fmt.Errorf("failed to clear digest carry-over: %w", err)
+	}
+	return nil
+}
+
+// GetDigestDeliverySummary counts digests first attempted since a time.
+func (r *NotificationRepository) GetDigestDeliverySummary(ctx context.Context, since time.Time) (notification.DigestDeliverySummary, error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'delivered' AND retry_count = 1),
+			COUNT(*) FILTER (WHERE status = 'delivered')
+		FROM notifications
+		WHERE digest_date IS NOT NULL AND created_at >= $1
+	`
+
+	var summary notification.DigestDeliverySummary
+	err := r.conn.QueryRow(ctx, query, since).Scan(&summary.Total, &summary.FirstPassDelivered, &summary.Delivered)
+	if err != nil {
+		return summary, fmt.Errorf("failed to summarize digest deliveries: %w", err)
+	}
+	return summary, nil
+}
+
+// queryDigestAttempts runs a query returning digestAttemptColumns rows.
+func (r *NotificationRepository) queryDigestAttempts(ctx context.Context, query string, args ...interface{}) ([]notification.DigestAttempt, error) {
+	rows, err := r.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query digest attempts: %w", err)
+	}
+	defer rows.Close()
+
+	var result []notification.DigestAttempt
+	for rows.Next() {
+		var a notification.DigestAttempt
+		var chatID int64
+		var status, category string
+		err := rows.Scan(
+			&a.StudentID,
+			&chatID,
+			&a.Date,
+			&status,
+			&a.Attempts,
+			&category,
+			&a.LastError,
+			&a.LastAttemptAt,
+			&a.CarryOver,
+			&a.Highlights.XP,
+			&a.Highlights.Tasks,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan digest attempt: %w", err)
+		}
+		a.ChatID = notification.TelegramChatID(chatID)
+		a.Delivered = status == string(notification.StatusDelivered)
+		a.ErrorCategory = notification.DeliveryErrorCategory(category)
+		result = append(result, a)
+	}
+
+	return result, rows.Err()
+}
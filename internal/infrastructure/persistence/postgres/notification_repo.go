@@ -0,0 +1,408 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// NOTIFICATION REPOSITORY
+// ══════════════════════════════════════════════════════════════════════════════
+
+// NotificationRepository implements notification.NotificationRepository for PostgreSQL.
+type NotificationRepository struct {
+	conn *Connection
+}
+
+// NewNotificationRepository creates a new NotificationRepository.
+func NewNotificationRepository(conn *Connection) *NotificationRepository {
+	return &NotificationRepository{conn: conn}
+}
+
+const notificationColumns = `
+	id, type, recipient_id, telegram_chat_id, priority, status, title, message,
+	data, metadata, scheduled_at, sent_at, delivered_at, expires_at,
+	retry_count, max_retries, last_error, read_at, superseded_at, created_at, updated_at
+`
+
+// inboxStatuses are the statuses shown in the notification center.
+var inboxStatuses = []string{string(notification.StatusDelivered), string(notification.StatusSkipped)}
+
+// Save inserts or updates a notification. Saving a delivered notification
+// supersedes earlier unread notifications of the same type.
+func (r *NotificationRepository) Save(ctx context.Context, n *notification.Notification) error {
+	data, err := json.Marshal(n.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification data: %w", err)
+	}
+	metadata, err := json.Marshal(n.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification metadata: %w", err)
+	}
+
+	query := `
+		INSERT INTO notifications (` + notificationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			title = EXCLUDED.title,
+			message = EXCLUDED.message,
+			data = EXCLUDED.data,
+			metadata = EXCLUDED.metadata,
+			scheduled_at = EXCLUDED.scheduled_at,
+			sent_at = EXCLUDED.sent_at,
+			delivered_at = EXCLUDED.delivered_at,
+			expires_at = EXCLUDED.expires_at,
+			retry_count = EXCLUDED.retry_count,
+			last_error = EXCLUDED.last_error,
+			read_at = EXCLUDED.read_at,
+			superseded_at = EXCLUDED.superseded_at,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, query,
+			string(n.ID),
+			string(n.Type),
+			string(n.RecipientID),
+			int64(n.TelegramChatID),
+			int(n.Priority),
+			string(n.Status),
+			n.Title,
+			n.Message,
+			data,
+			metadata,
+			n.ScheduledAt,
+			n.SentAt,
+			n.DeliveredAt,
+			n.ExpiresAt,
+			n.RetryCount,
+			n.MaxRetries,
+			n.LastError,
+			n.ReadAt,
+			n.SupersededAt,
+			n.CreatedAt,
+			n.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save notification: %w", err)
+		}
+
+		if n.Status == notification.StatusDelivered {
+			return r.supersedePrevious(ctx, tx, n.ID)
+		}
+		return nil
+	})
+}
+
+// GetByID returns a notification by ID.
+func (r *NotificationRepository) GetByID(ctx context.Context, id notification.NotificationID) (*notification.Notification, error) {
+	query := `SELECT ` + notificationColumns + ` FROM notifications WHERE id = $1`
+
+	n, err := r.scanNotification(r.conn.QueryRow(ctx, query, string(id)))
+	if IsNoRows(err) {
+		return nil, notification.ErrNotificationNotFound
+	}
+	return n, err
+}
+
+// GetPending returns notifications waiting to be sent.
+func (r *NotificationRepository) GetPending(ctx context.Context, limit int) ([]*notification.Notification, error) {
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE status IN ('pending', 'queued')
+		  AND (scheduled_at IS NULL OR scheduled_at <= NOW())
+		  AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY priority DESC, created_at
+		LIMIT $1
+	`
+	return r.queryNotifications(ctx, query, limit)
+}
+
+// GetByRecipient returns the recipient's notifications, newest first.
+func (r *NotificationRepository) GetByRecipient(ctx context.Context, recipientID notification.RecipientID, limit int) ([]*notification.Notification, error) {
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE recipient_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+	return r.queryNotifications(ctx, query, string(recipientID), limit)
+}
+
+// GetByStatus returns notifications with the given status.
+func (r *NotificationRepository) GetByStatus(ctx context.Context, status notification.NotificationStatus, limit int) ([]*notification.Notification, error) {
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE status = $1
+		ORDER BY created_at
+		LIMIT $2
+	`
+	return r.queryNotifications(ctx, query, string(status), limit)
+}
+
+// GetFailedForRetry returns failed notifications that can be retried.
+func (r *NotificationRepository) GetFailedForRetry(ctx context.Context, maxRetries int, limit int) ([]*notification.Notification, error) {
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE status = 'failed'
+		  AND retry_count < LEAST(max_retries, $1)
+		  AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY updated_at
+		LIMIT $2
+	`
+	return r.queryNotifications(ctx, query, maxRetries, limit)
+}
+
+// GetExpired returns unsent notifications past their expiry.
+func (r *NotificationRepository) GetExpired(ctx context.Context, limit int) ([]*notification.Notification, error) {
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE status IN ('pending', 'queued', 'failed')
+		  AND expires_at IS NOT NULL AND expires_at <= NOW()
+		ORDER BY expires_at
+		LIMIT $1
+	`
+	return r.queryNotifications(ctx, query, limit)
+}
+
+// UpdateStatus updates a notification's status. Marking a notification
+// delivered supersedes earlier unread notifications of the same type.
+func (r *NotificationRepository) UpdateStatus(ctx context.Context, id notification.NotificationID, status notification.NotificationStatus) error {
+	query := `
+		UPDATE notifications SET
+			status = $2,
+			delivered_at = CASE WHEN $2 = 'delivered' THEN COALESCE(delivered_at, NOW()) ELSE delivered_at END,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, query, string(id), string(status))
+		if err != nil {
+			return fmt.Errorf("failed to update notification status: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return notification.ErrNotificationNotFound
+		}
+
+		if status == notification.StatusDelivered {
+			return r.supersedePrevious(ctx, tx, id)
+		}
+		return nil
+	})
+}
+
+// Delete deletes a notification.
+func (r *NotificationRepository) Delete(ctx context.Context, id notification.NotificationID) error {
+	result, err := r.conn.Exec(ctx, `DELETE FROM notifications WHERE id = $1`, string(id))
+	if err != nil {
+		return fmt.Errorf("failed to delete notification: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return notification.ErrNotificationNotFound
+	}
+	return nil
+}
+
+// DeleteOlderThan deletes notifications created before the given time.
+func (r *NotificationRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.conn.Exec(ctx, `DELETE FROM notifications WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old notifications: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// ExpireSending marks notifications that have been sending since before
+// sentBefore as expired. Whether they reached the recipient is unknown, so
+// they are not sent again.
+func (r *NotificationRepository) ExpireSending(ctx context.Context, sentBefore time.Time) (int64, error) {
+	result, err := r.conn.Exec(ctx, `
+		UPDATE notifications
+		SET status = 'expired', last_error = 'interrupted while sending', updated_at = NOW()
+		WHERE status = 'sending' AND sent_at < $1
+	`, sentBefore.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire stuck notifications: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// CountByRecipient returns how many notifications the recipient got since the given time.
+func (r *NotificationRepository) CountByRecipient(ctx context.Context, recipientID notification.RecipientID, since time.Time) (int, error) {
+	var count int
+	err := r.conn.QueryRow(ctx,
+		`SELECT COUNT(*) FROM notifications WHERE recipient_id = $1 AND created_at >= $2`,
+		string(recipientID), since,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+	return count, nil
+}
+
+// CountByType returns how many notifications of a type were created since the given time.
+func (r *NotificationRepository) CountByType(ctx context.Context, notificationType notification.NotificationType, since time.Time) (int, error) {
+	var count int
+	err := r.conn.QueryRow(ctx,
+		`SELECT COUNT(*) FROM notifications WHERE type = $1 AND created_at >= $2`,
+		string(notificationType), since,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count notifications by type: %w", err)
+	}
+	return count, nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Notification center
+// ─────────────────────────────────────────────────────────────────────────────
+
+// GetInbox returns the recipient's notification center page, newest first.
+func (r *NotificationRepository) GetInbox(ctx context.Context, recipientID notification.RecipientID, offset, limit int) ([]*notification.Notification, error) {
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE recipient_id = $1 AND status = ANY($2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+	return r.queryNotifications(ctx, query, string(recipientID), inboxStatuses, limit, offset)
+}
+
+// CountUnread returns the number of unread, non-superseded notifications.
+func (r *NotificationRepository) CountUnread(ctx context.Context, recipientID notification.RecipientID) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM notifications
+		WHERE recipient_id = $1 AND status = ANY($2)
+		  AND read_at IS NULL AND superseded_at IS NULL
+	`
+
+	var count int
+	if err := r.conn.QueryRow(ctx, query, string(recipientID), inboxStatuses).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkAllRead marks all of the recipient's notifications as read.
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, recipientID notification.RecipientID) (int64, error) {
+	query := `
+		UPDATE notifications SET read_at = NOW(), updated_at = NOW()
+		WHERE recipient_id = $1 AND read_at IS NULL
+	`
+
+	result, err := r.conn.Exec(ctx, query, string(recipientID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// supersedePrevious marks older unread notifications of the same type and
+// recipient as superseded by the given one.
+func (r *NotificationRepository) supersedePrevious(ctx context.Context, tx pgx.Tx, id notification.NotificationID) error {
+	query := `
+		UPDATE notifications AS old SET superseded_at = NOW(), updated_at = NOW()
+		FROM notifications AS cur
+		WHERE cur.id = $1
+		  AND old.recipient_id = cur.recipient_id
+		  AND old.type = cur.type
+		  AND old.id <> cur.id
+		  AND old.created_at <= cur.created_at
+		  AND old.read_at IS NULL
+		  AND old.superseded_at IS NULL
+	`
+
+	if _, err := tx.Exec(ctx, query, string(id)); err != nil {
+		return fmt.Errorf("failed to supersede notifications: %w", err)
+	}
+	return nil
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HELPER METHODS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// queryNotifications runs a query returning full notification rows.
+func (r *NotificationRepository) queryNotifications(ctx context.Context, query string, args ...interface{}) ([]*notification.Notification, error) {
+	rows, err := r.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*notification.Notification
+	for rows.Next() {
+		n, err := r.scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, n)
+	}
+
+	return result, rows.Err()
+}
+
+// scanNotification scans a single notification row.
+func (r *NotificationRepository) scanNotification(row pgx.Row) (*notification.Notification, error) {
+	var n notification.Notification
+	var id, notifType, recipientID, status string
+	var chatID int64
+	var priority int
+	var data, metadata []byte
+
+	err := row.Scan(
+		&id,
+		&notifType,
+		&recipientID,
+		&chatID,
+		&priority,
+		&status,
+		&n.Title,
+		&n.Message,
+		&data,
+		&metadata,
+		&n.ScheduledAt,
+		&n.SentAt,
+		&n.DeliveredAt,
+		&n.ExpiresAt,
+		&n.RetryCount,
+		&n.MaxRetries,
+		&n.LastError,
+		&n.ReadAt,
+		&n.SupersededAt,
+		&n.CreatedAt,
+		&n.UpdatedAt,
+	)
+	if err != nil {
+		if IsNoRows(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan notification: %w", err)
+	}
+
+	n.ID = notification.NotificationID(id)
+	n.Type = notification.NotificationType(notifType)
+	n.RecipientID = notification.RecipientID(recipientID)
+	n.TelegramChatID = notification.TelegramChatID(chatID)
+	n.Priority = notification.Priority(priority)
+	n.Status = notification.NotificationStatus(status)
+	_ = json.Unmarshal(data, &n.Data)
+	_ = json.Unmarshal(metadata, &n.Metadata)
+
+	return &n, nil
+}
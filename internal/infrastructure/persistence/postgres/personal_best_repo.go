@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// PersonalBestRepository implements student.PersonalBestRepository for PostgreSQL.
+type PersonalBestRepository struct {
+	conn *Connection
+}
+
+// NewPersonalBestRepository creates a new PersonalBestRepository.
+func NewPersonalBestRepository(conn *Connection) *PersonalBestRepository {
+	return &PersonalBestRepository{conn: conn}
+}
+
+// GetBests returns the personal bests of a student.
+func (r *PersonalBestRepository) GetBests(ctx context.Context, studentID string) ([]student.PersonalBest, error) {
+	query := `
+		SELECT student_id, metric, value, achieved_on
+		FROM personal_bests
+		WHERE student_id = $1
+	`
+
+	rows, err := r.conn.Query(ctx, query, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personal bests: %w", err)
+	}
+	defer rows.Close()
+
+	var bests []student.PersonalBest
+	for rows.Next() {
+		var (
+			best   student.PersonalBest
+			metric string
+		)
+		if err := rows.Scan(&best.StudentID, &metric, &best.Value, &best.AchievedOn); err != nil {
+			return nil, fmt.Errorf("failed to scan personal best: %w", err)
+		}
+		best.Metric = student.PersonalBestMetric(metric)
+		bests = append(bests, best)
+	}
+
+	return bests, rows.Err()
+}
+
+// UpsertBest stores best only if its value is strictly greater than the
+// stored one. The previous record is read in the same statement, so the
+// comparison and the write cannot race.
+func (r *PersonalBestRepository) UpsertBest(ctx context.Context, best student.PersonalBest) (student.PersonalBestUpdate, error) {
+	query := `
+		WITH prev AS (
+			SELECT value, achieved_on FROM personal_bests
+			WHERE student_id = $1 AND metric = $2
+		), upserted AS (
+			INSERT INTO personal_bests (student_id, metric, value, achieved_on, updated_at)
+			VALUES ($1, $2, $3, $4, NOW())
+			ON CONFLICT (student_id, metric) DO UPDATE SET
+				value = EXCLUDED.value,
+				achieved_on = EXCLUDED.achieved_on,
+				updated_at = EXCLUDED.updated_at
+			WHERE personal_bests.value < EXCLUDED.value
+			RETURNING value
+		)
+		SELECT
+			(SELECT value FROM prev),
+			(SELECT achieved_on FROM prev),
+			EXISTS (SELECT 1 FROM upserted)
+	`
+
+	var (
+		prevValue      *int
+		prevAchievedOn *time.Time
+		improved       bool
+	)
+	err := r.conn.QueryRow(ctx, query,
+		best.StudentID,
+		string(best.Metric),
+		best.Value,
+		best.AchievedOn.UTC(),
+	).Scan(&prevValue, &prevAchievedOn, &improved)
+	if err != nil {
+		return student.PersonalBestUpdate{}, fmt.Errorf("failed to upsert personal best: %w", err)
+	}
+
+	update := student.PersonalBestUpdate{Best: best, Improved: improved}
+	if prevValue != nil && prevAchievedOn != nil {
+		update.Previous = &student.PersonalBest{
+			StudentID:  best.StudentID,
+			Metric:     best.Metric,
+			Value:      *prevValue,
+			AchievedOn: prevAchievedOn.UTC(),
+		}
+		if !improved {
+			update.Best = *update.Previous
+		}
+	}
+
+	return update, nil
+}
+
+// Ensure interface is implemented
+var _ student.PersonalBestRepository = (*PersonalBestRepository)(nil)
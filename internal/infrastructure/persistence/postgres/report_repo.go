@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ReportRepository implements social.ReportRepository for PostgreSQL.
+type ReportRepository struct {
+	conn *Connection
+}
+
+// NewReportRepository creates a new ReportRepository.
+func NewReportRepository(conn *Connection) *ReportRepository {
+	return &ReportRepository{conn: conn}
+}
+
+const reportColumns = `id, reporter_id, reported_id, context, context_id, reason, status, created_at, resolved_at, COALESCE(resolved_by, 0)`
+
+// Create inserts the report and fills its ID.
+func (r *ReportRepository) Create(ctx context.Context, report *social.Report) error {
+	query := `
+		INSERT INTO student_reports (reporter_id, reported_id, context, context_id, reason, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	err := r.conn.QueryRow(ctx, query,
+		string(report.ReporterID),
+		string(report.ReportedID),
+		string(report.Context),
+		report.ContextID,
+		report.Reason,
+		string(report.Status),
+		report.CreatedAt.UTC(),
+	).Scan(&report.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save report: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID returns the report with the given ID.
+func (r *ReportRepository) GetByID(ctx context.Context, id int64) (*social.Report, error) {
+	report, err := scanReport(r.conn.QueryRow(ctx, `SELECT `+reportColumns+` FROM student_reports WHERE id = $1`, id))
+	if IsNoRows(err) {
+		return nil, social.ErrReportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+	return report, nil
+}
+
+// AttachReason stores the reason on the reporter's latest pending report
+// without one.
+func (r *ReportRepository) AttachReason(ctx context.Context, reporterID social.StudentID, reason string, since time.Time) (*social.Report, error) {
+	report, err := scanReport(r.conn.QueryRow(ctx, `
+		UPDATE student_reports SET reason = $2
+		WHERE id = (
+			SELECT id FROM student_reports
+			WHERE reporter_id = $1 AND reason = '' AND status = 'pending' AND created_at >= $3
+			ORDER BY created_at DESC
+			LIMIT 1
+		)
+		RETURNING `+reportColumns,
+		string(reporterID), reason, since.UTC(),
+	))
+	if IsNoRows(err) {
+		return nil, social.ErrReportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach report reason: %w", err)
+	}
+	return report, nil
+}
+
+// Resolve records the admin's decision on a pending report.
+func (r *ReportRepository) Resolve(ctx context.Context, id int64, status social.ReportStatus, adminID int64, at time.Time) (*social.Report, error) {
+	report, err := scanReport(r.conn.QueryRow(ctx, `
+		UPDATE student_reports SET status = $2, resolved_at = $3, resolved_by = $4
+		WHERE id = $1 AND status = 'pending'
+		RETURNING `+reportColumns,
+		id, string(status), at.UTC(), adminID,
+	))
+	if IsNoRows(err) {
+		if _, getErr := r.GetByID(ctx, id); getErr != nil {
+			return nil, getErr
+		}
+		return nil, social.ErrReportResolved
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve report: %w", err)
+	}
+	return report, nil
+}
+
+// ListByReported returns every report on the student, newest first.
+func (r *ReportRepository) ListByReported(ctx context.Context, reportedID social.StudentID) ([]*social.Report, error) {
+	rows, err := r.conn.Query(ctx, `
+		SELECT `+reportColumns+` FROM student_reports
+		WHERE reported_id = $1
+		ORDER BY created_at DESC
+	`, string(reportedID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reports: %w", err)
+	}
+	return scanReports(rows)
+}
+
+// ListPending returns up to limit pending reports, oldest first.
+func (r *ReportRepository) ListPending(ctx context.Context, limit int) ([]*social.Report, error) {
+	rows, err := r.conn.Query(ctx, `
+		SELECT `+reportColumns+` FROM student_reports
+		WHERE status = 'pending'
+		ORDER BY created_at
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending reports: %w", err)
+	}
+	return scanReports(rows)
+}
+
+// scanReports scans rows selected with reportColumns.
+func scanReports(rows pgx.Rows) ([]*social.Report, error) {
+	defer rows.Close()
+
+	var reports []*social.Report
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report: %w", err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
+
+// scanReport scans a row selected with reportColumns.
+func scanReport(row pgx.Row) (*social.Report, error) {
+	var (
+		report                 social.Report
+		reporterID, reportedID string
+		reportContext, status  string
+	)
+	err := row.Scan(
+		&report.ID,
+		&reporterID,
+		&reportedID,
+		&reportContext,
+		&report.ContextID,
+		&report.Reason,
+		&status,
+		&report.CreatedAt,
+		&report.ResolvedAt,
+		&report.ResolvedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	report.ReporterID = social.StudentID(reporterID)
+	report.ReportedID = social.StudentID(reportedID)
+	report.Context = social.ReportContext(reportContext)
+	report.Status = social.ReportStatus(status)
+	return &report, nil
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RetentionRepository rolls up and deletes old rows of historical tables.
+type RetentionRepository struct {
+	conn *Connection
+}
+
+// NewRetentionRepository creates a new RetentionRepository.
+func NewRetentionRepository(conn *Connection) *RetentionRepository {
+	return &RetentionRepository{conn: conn}
+}
+
+// pruneQueries deletes up to $2 rows older than $1, one statement per table.
+// Batches are picked by ctid so tables without a single-column key work too.
+var pruneQueries = map[string]string{
+	// Only rows whose (student, month) is already rolled up.
+	"xp_history": `
+		DELETE FROM xp_history WHERE ctid IN (
+			SELECT h.ctid FROM xp_history h
+			WHERE h.created_at < $1
+			  AND EXISTS (
+				SELECT 1 FROM xp_history_monthly m
+				WHERE m.student_id = h.student_id AND m.month = ` + xpMonthExpr + `
+			  )
+			LIMIT $2
+		)
+	`,
+	"notifications": `
+		DELETE FROM notifications WHERE ctid IN (
+			SELECT ctid FROM notifications WHERE created_at < $1 LIMIT $2
+		)
+	`,
+	"command_usage": `
+		DELETE FROM command_usage WHERE ctid IN (
+			SELECT ctid FROM command_usage WHERE day < ($1::timestamptz AT TIME ZONE 'UTC')::date LIMIT $2
+		)
+	`,
+	"audit_log": `
+		DELETE FROM audit_log WHERE ctid IN (
+			SELECT ctid FROM audit_log WHERE occurred_at < $1 LIMIT $2
+		)
+	`,
+}
+
+// RollupXPHistory stores monthly per-reason totals of xp_history rows older
+// than before (a month start) for every (student, month) not rolled up yet.
+// All rows of a (student, month) are rolled up by the same statement, so a
+// month is never half rolled up. Returns the number of totals created.
+func (r *RetentionRepository) RollupXPHistory(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.conn.Exec(ctx, `
+		INSERT INTO xp_history_monthly (student_id, month, reason, delta, entries)
+		SELECT h.student_id, `+xpMonthExpr+`, h.reason, SUM(h.delta), COUNT(*)
+		FROM xp_history h
+		WHERE h.created_at < $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM xp_history_monthly m
+			WHERE m.student_id = h.student_id AND m.month = `+xpMonthExpr+`
+		  )
+		GROUP BY 1, 2, 3
+		ON CONFLICT (student_id, month, reason) DO NOTHING
+	`, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to roll up xp history: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// PruneBatch deletes up to limit rows of table older than before.
+func (r *RetentionRepository) PruneBatch(ctx context.Context, table string, before time.Time, limit int) (int64, error) {
+	query, ok := pruneQueries[table]
+	if !ok {
+		return 0, fmt.Errorf("no retention policy for table %q", table)
+	}
+
+	tag, err := r.conn.Exec(ctx, query, before.UTC(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune %s: %w", table, err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/saga"
+)
+
+// SagaExecutionRepository implements saga.ExecutionRepository for PostgreSQL.
+type SagaExecutionRepository struct {
+	conn *Connection
+}
+
+// NewSagaExecutionRepository creates a new SagaExecutionRepository.
+func NewSagaExecutionRepository(conn *Connection) *SagaExecutionRepository {
+	return &SagaExecutionRepository{conn: conn}
+}
+
+// Create stores a new execution.
+func (r *SagaExecutionRepository) Create(ctx context.Context, exec *saga.Execution) error {
+	query := `
+		INSERT INTO saga_executions (
+			id, saga_type, input, state, current_step, status, error,
+			started_at, updated_at, completed_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.conn.Exec(ctx, query,
+		exec.ID,
+		string(exec.SagaType),
+		[]byte(exec.Input),
+		[]byte(exec.State),
+		exec.CurrentStep,
+		string(exec.Status),
+		exec.Error,
+		exec.StartedAt,
+		exec.UpdatedAt,
+		exec.CompletedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create saga execution: %w", err)
+	}
+
+	return nil
+}
+
+// Update stores the progress of an execution.
+func (r *SagaExecutionRepository) Update(ctx context.Context, exec *saga.Execution) error {
+	query := `
+		UPDATE saga_executions
+		SET state = $2, current_step = $3, status = $4, error = $5,
+			updated_at = $6, completed_at = $7
+		WHERE id = $1
+	`
+
+	tag, err := r.conn.Exec(ctx, query,
+		exec.ID,
+		[]byte(exec.State),
+		exec.CurrentStep,
+		string(exec.Status),
+		exec.Error,
+		exec.UpdatedAt,
+		exec.CompletedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update saga execution: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return saga.ErrExecutionNotFound
+	}
+
+	return nil
+}
+
+// GetStalled returns running executions not updated since updatedBefore.
+func (r *SagaExecutionRepository) GetStalled(ctx context.Context, updatedBefore time.Time, limit int) ([]*saga.Execution, error) {
+	query := `
+		SELECT id, saga_type, input, state, current_step, status, error,
+			started_at, updated_at, completed_at
+		FROM saga_executions
+		WHERE status = 'running' AND updated_at < $1
+		ORDER BY updated_at
+		LIMIT $2
+	`
+
+	rows, err := r.conn.Query(ctx, query, updatedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stalled saga executions: %w", err)
+	}
+	defer rows.Close()
+
+	var executions []*saga.Execution
+	for rows.Next() {
+		var (
+			exec             saga.Execution
+			sagaType, status string
+			input, stateJSON []byte
+		)
+		err := rows.Scan(
+			&exec.ID,
+			&sagaType,
+			&input,
+			&stateJSON,
+			&exec.CurrentStep,
+			&status,
+			&exec.Error,
+			&exec.StartedAt,
+			&exec.UpdatedAt,
+			&exec.CompletedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saga execution: %w", err)
+		}
+
+		exec.SagaType = saga.SagaType(sagaType)
+		exec.Status = saga.ExecutionStatus(status)
+		exec.Input = input
+		exec.State = stateJSON
+		executions = append(executions, &exec)
+	}
+
+	return executions, rows.Err()
+}
+
+// ClaimStalled bumps updated_at of a stalled execution so that only one
+// worker resumes it.
+func (r *SagaExecutionRepository) ClaimStalled(ctx context.Context, id string, updatedBefore, at time.Time) (bool, error) {
+	query := `
+		UPDATE saga_executions
+		SET updated_at = $3
+		WHERE id = $1 AND status = 'running' AND updated_at < $2
+	`
+
+	tag, err := r.conn.Exec(ctx, query, id, updatedBefore, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim saga execution: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
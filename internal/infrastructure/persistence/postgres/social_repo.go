@@ -2,10 +2,16 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+
+	"github.com/jackc/pgx/v5"
 )
 
 // SocialRepository implements social.Repository using PostgreSQL.
@@ -35,6 +41,16 @@ func (r *SocialRepository) Endorsements() social.EndorsementRepository {
 	return &EndorsementRepository{conn: r.conn}
 }
 
+// HelpFeedback returns the private help feedback repository.
+func (r *SocialRepository) HelpFeedback() social.HelpFeedbackRepository {
+	return &HelpFeedbackRepository{conn: r.conn}
+}
+
+// HelpSnoozes returns the help snooze repository.
+func (r *SocialRepository) HelpSnoozes() social.HelpSnoozeRepository {
+	return &HelpSnoozeRepository{conn: r.conn}
+}
+
 // Matching returns the matching repository.
 func (r *SocialRepository) Matching() social.MatchingRepository {
 	return &MatchingRepository{conn: r.conn}
@@ -53,88 +69,491 @@ type ConnectionRepository struct {
 	conn *Connection
 }
 
-func (r *ConnectionRepository) Create(ctx context.Context, conn *social.Connection) error {
-	return errors.New("not implemented")
+// connectionColumns are the connections columns read by scanConnection.
+const connectionColumns = `
+	id, from_student_id, to_student_id, connection_type, status,
+	COALESCE(task_id, ''), help_request_id, COALESCE(note, ''),
+	interaction_count, total_help_minutes, tasks_solved_together, last_interaction_at,
+	created_at, updated_at, accepted_at, ended_at, COALESCE(end_reason, '')`
+
+// Create stores a new connection. A connection between the two students in
+// either direction makes it fail with social.ErrConnectionAlreadyExists.
+func (r *ConnectionRepository) Create(ctx context.Context, c *social.Connection) error {
+	query := `
+		INSERT INTO connections (
+			id, from_student_id, to_student_id, connection_type, status,
+			task_id, help_request_id, note,
+			interaction_count, total_help_minutes, tasks_solved_together, last_interaction_at,
+			created_at, updated_at, accepted_at, ended_at, end_reason
+		)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+		WHERE NOT EXISTS (
+			SELECT 1 FROM connections
+			WHERE from_student_id = $3 AND to_student_id = $2
+		)
+	`
+
+	tag, err := r.conn.Exec(ctx, query,
+		c.ID, string(c.InitiatorID), string(c.ReceiverID), connectionTypeToDB(c.Type), connectionStatusToDB(c.Status),
+		nullIfEmpty(string(c.Context.TaskID)), nullIfEmpty(c.Context.HelpRequestID), nullIfEmpty(c.Context.Note),
+		c.Stats.InteractionCount, c.Stats.TotalHelpTime, c.Stats.TasksSolvedTogether, nullTime(c.Stats.LastInteractionAt),
+		c.CreatedAt, c.UpdatedAt, c.AcceptedAt, c.EndedAt, nullIfEmpty(c.EndReason),
+	)
+	if IsUniqueViolation(err) {
+		return social.ErrConnectionAlreadyExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create connection: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return social.ErrConnectionAlreadyExists
+	}
+	return nil
 }
 
 func (r *ConnectionRepository) GetByID(ctx context.Context, id string) (*social.Connection, error) {
-	return nil, errors.New("not implemented")
-}
+	query := `SELECT ` + connectionColumns + ` FROM connections WHERE id = $1`
 
-func (r *ConnectionRepository) Update(ctx context.Context, conn *social.Connection) error {
-	return errors.New("not implemented")
+	c, err := scanConnection(r.conn.QueryRow(ctx, query, id))
+	if IsNoRows(err) {
+		return nil, social.ErrConnectionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	return c, nil
+}
+
+// Update saves the type, status, stats and lifecycle timestamps. The pair
+// of students and the context a connection was made in do not change.
+func (r *ConnectionRepository) Update(ctx context.Context, c *social.Connection) error {
+	query := `
+		UPDATE connections SET
+			connection_type = $2,
+			status = $3,
+			interaction_count = $4,
+			total_help_minutes = $5,
+			tasks_solved_together = $6,
+			last_interaction_at = $7,
+			updated_at = $8,
+			accepted_at = $9,
+			ended_at = $10,
+			end_reason = $11
+		WHERE id = $1
+	`
+
+	tag, err := r.conn.Exec(ctx, query,
+		c.ID, connectionTypeToDB(c.Type), connectionStatusToDB(c.Status),
+		c.Stats.InteractionCount, c.Stats.TotalHelpTime, c.Stats.TasksSolvedTogether, nullTime(c.Stats.LastInteractionAt),
+		c.UpdatedAt, c.AcceptedAt, c.EndedAt, nullIfEmpty(c.EndReason),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update connection: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return social.ErrConnectionNotFound
+	}
+	return nil
 }
 
+// Delete ends the connection; the row is kept for history and merges.
 func (r *ConnectionRepository) Delete(ctx context.Context, id string) error {
-	return errors.New("not implemented")
+	query := `
+		UPDATE connections
+		SET status = 'ended', ended_at = COALESCE(ended_at, NOW()), updated_at = NOW()
+		WHERE id = $1
+	`
+
+	tag, err := r.conn.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete connection: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return social.ErrConnectionNotFound
+	}
+	return nil
 }
 
+// GetByStudents returns the connection between two students whoever
+// initiated it. The unique (from, to) pair and Create keep at most one row
+// per pair, so the newest row is only a tie-break for legacy duplicates.
 func (r *ConnectionRepository) GetByStudents(ctx context.Context, student1, student2 social.StudentID) (*social.Connection, error) {
-	return nil, errors.New("not implemented")
+	query := `SELECT ` + connectionColumns + `
+		FROM connections
+		WHERE (from_student_id = $1 AND to_student_id = $2)
+		   OR (from_student_id = $2 AND to_student_id = $1)
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	c, err := scanConnection(r.conn.QueryRow(ctx, query, string(student1), string(student2)))
+	if IsNoRows(err) {
+		return nil, social.ErrConnectionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection between students: %w", err)
+	}
+	return c, nil
 }
 
+// GetByStudentID returns a page of a student's connections, newest first.
+// Ended and declined connections are left out unless opts.IncludeEnded.
+// Names of the other side are resolved by the caller, not joined here.
 func (r *ConnectionRepository) GetByStudentID(ctx context.Context, studentID social.StudentID, opts social.ConnectionListOptions) ([]*social.Connection, error) {
-	return nil, errors.New("not implemented")
+	page := opts.Normalized()
+	query := `SELECT ` + connectionColumns + `
+		FROM connections
+		WHERE (from_student_id = $1 OR to_student_id = $1)
+		  AND ($2 OR status IN ('pending', 'active'))
+		  AND (cardinality($3::text[]) = 0 OR connection_type = ANY($3))
+		ORDER BY created_at DESC
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := r.conn.Query(ctx, query,
+		string(studentID), opts.IncludeEnded, connectionTypesToDB(opts.Types), page.Limit, page.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connections: %w", err)
+	}
+	defer rows.Close()
+
+	return scanConnections(rows)
 }
 
+// GetActiveByStudentID returns the active connections of a student in either direction.
 func (r *ConnectionRepository) GetActiveByStudentID(ctx context.Context, studentID social.StudentID) ([]*social.Connection, error) {
-	return nil, errors.New("not implemented")
+	return r.list(ctx, "failed to get active connections", `
+		WHERE (from_student_id = $1 OR to_student_id = $1) AND status = 'active'
+		ORDER BY created_at DESC
+	`, string(studentID))
 }
 
+// GetPendingByStudentID returns pending requests sent or received by a student.
 func (r *ConnectionRepository) GetPendingByStudentID(ctx context.Context, studentID social.StudentID) ([]*social.Connection, error) {
-	return nil, errors.New("not implemented")
+	return r.list(ctx, "failed to get pending connections", `
+		WHERE (from_student_id = $1 OR to_student_id = $1) AND status = 'pending'
+		ORDER BY created_at DESC
+	`, string(studentID))
 }
 
+// GetIncomingPending returns pending requests a student received.
 func (r *ConnectionRepository) GetIncomingPending(ctx context.Context, studentID social.StudentID) ([]*social.Connection, error) {
-	return nil, errors.New("not implemented")
+	return r.list(ctx, "failed to get incoming connection requests", `
+		WHERE to_student_id = $1 AND status = 'pending'
+		ORDER BY created_at DESC
+	`, string(studentID))
 }
 
+// GetOutgoingPending returns pending requests a student sent.
 func (r *ConnectionRepository) GetOutgoingPending(ctx context.Context, studentID social.StudentID) ([]*social.Connection, error) {
-	return nil, errors.New("not implemented")
+	return r.list(ctx, "failed to get outgoing connection requests", `
+		WHERE from_student_id = $1 AND status = 'pending'
+		ORDER BY created_at DESC
+	`, string(studentID))
 }
 
+// GetByType returns the active connections of a student of the given type.
 func (r *ConnectionRepository) GetByType(ctx context.Context, studentID social.StudentID, connType social.ConnectionType) ([]*social.Connection, error) {
-	return nil, errors.New("not implemented")
+	return r.list(ctx, "failed to get connections by type", `
+		WHERE (from_student_id = $1 OR to_student_id = $1)
+		  AND status = 'active'
+		  AND connection_type = ANY($2)
+		ORDER BY created_at DESC
+	`, string(studentID), connectionTypesToDB([]social.ConnectionType{connType}))
 }
 
+// GetByStatus returns a page of connections with the given status, newest first.
 func (r *ConnectionRepository) GetByStatus(ctx context.Context, status social.ConnectionStatus, opts social.ConnectionListOptions) ([]*social.Connection, error) {
-	return nil, errors.New("not implemented")
+	page := opts.Normalized()
+	return r.list(ctx, "failed to get connections by status", `
+		WHERE status = $1
+		  AND (cardinality($2::text[]) = 0 OR connection_type = ANY($2))
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`, string(status), connectionTypesToDB(opts.Types), page.Limit, page.Offset)
 }
 
 func (r *ConnectionRepository) Exists(ctx context.Context, id string) (bool, error) {
-	return false, errors.New("not implemented")
+	var exists bool
+	err := r.conn.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM connections WHERE id = $1)`, id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check connection: %w", err)
+	}
+	return exists, nil
 }
 
+// ExistsBetweenStudents reports whether the students have a pending or
+// active connection in either direction.
 func (r *ConnectionRepository) ExistsBetweenStudents(ctx context.Context, student1, student2 social.StudentID) (bool, error) {
-	return false, errors.New("not implemented")
+	return r.existsBetween(ctx, student1, student2, []string{"pending", "active"})
 }
 
+// ExistsActiveConnection reports whether the students have an active
+// connection in either direction.
 func (r *ConnectionRepository) ExistsActiveConnection(ctx context.Context, student1, student2 social.StudentID) (bool, error) {
-	return false, errors.New("not implemented")
+	return r.existsBetween(ctx, student1, student2, []string{"active"})
 }
 
+// CountByStudentID counts a student's pending and active connections.
 func (r *ConnectionRepository) CountByStudentID(ctx context.Context, studentID social.StudentID) (int, error) {
-	return 0, errors.New("not implemented")
+	return r.count(ctx, `(from_student_id = $1 OR to_student_id = $1) AND status IN ('pending', 'active')`, string(studentID))
 }
 
+// CountActiveByStudentID counts a student's active connections.
 func (r *ConnectionRepository) CountActiveByStudentID(ctx context.Context, studentID social.StudentID) (int, error) {
-	return 0, errors.New("not implemented")
+	return r.count(ctx, `(from_student_id = $1 OR to_student_id = $1) AND status = 'active'`, string(studentID))
 }
 
+// CountByType counts a student's active connections of the given type.
 func (r *ConnectionRepository) CountByType(ctx context.Context, studentID social.StudentID, connType social.ConnectionType) (int, error) {
-	return 0, errors.New("not implemented")
+	return r.count(ctx, `(from_student_id = $1 OR to_student_id = $1) AND status = 'active' AND connection_type = ANY($2)`,
+		string(studentID), connectionTypesToDB([]social.ConnectionType{connType}))
 }
 
+// GetConnectionStats aggregates a student's connections per type in one
+// query; the totals are summed from the per-type rows. Declined requests
+// are not connections and are left out.
 func (r *ConnectionRepository) GetConnectionStats(ctx context.Context, studentID social.StudentID) (*social.ConnectionStatsAggregate, error) {
-	return nil, errors.New("not implemented")
+	query := `
+		SELECT
+			connection_type,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'active'),
+			COUNT(*) FILTER (WHERE status = 'pending'),
+			COALESCE(SUM(interaction_count), 0),
+			COALESCE(SUM(total_help_minutes), 0),
+			COUNT(accepted_at),
+			COALESCE(SUM(EXTRACT(EPOCH FROM COALESCE(ended_at, NOW()) - accepted_at)), 0)::FLOAT8
+		FROM connections
+		WHERE (from_student_id = $1 OR to_student_id = $1) AND status <> 'declined'
+		GROUP BY connection_type
+	`
+
+	rows, err := r.conn.Query(ctx, query, string(studentID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &social.ConnectionStatsAggregate{ConnectionsByType: make(map[social.ConnectionType]int)}
+	var accepted int
+	var acceptedSeconds float64
+	for rows.Next() {
+		var (
+			connType                  string
+			total, active, pending    int
+			interactions, helpMinutes int
+			typeAccepted              int
+			typeSeconds               float64
+		)
+		if err := rows.Scan(&connType, &total, &active, &pending, &interactions, &helpMinutes, &typeAccepted, &typeSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan connection stats: %w", err)
+		}
+		stats.ConnectionsByType[connectionTypeFromDB(connType)] += total
+		stats.TotalConnections += total
+		stats.ActiveConnections += active
+		stats.PendingConnections += pending
+		stats.TotalInteractions += interactions
+		stats.TotalHelpTime += helpMinutes
+		accepted += typeAccepted
+		acceptedSeconds += typeSeconds
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if accepted > 0 {
+		stats.AverageDurationDays = int(acceptedSeconds / float64(accepted) / (24 * 60 * 60))
+	}
+	return stats, nil
 }
 
 func (r *ConnectionRepository) GetByIDs(ctx context.Context, ids []string) ([]*social.Connection, error) {
-	return nil, errors.New("not implemented")
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return r.list(ctx, "failed to get connections by ids", `WHERE id = ANY($1)`, ids)
 }
 
+// FindStale returns active connections without interactions for longer
+// than threshold; never-used connections count from their acceptance.
 func (r *ConnectionRepository) FindStale(ctx context.Context, threshold time.Duration) ([]*social.Connection, error) {
-	return nil, errors.New("not implemented")
+	return r.list(ctx, "failed to find stale connections", `
+		WHERE status = 'active'
+		  AND COALESCE(last_interaction_at, accepted_at, created_at) < $1
+		ORDER BY COALESCE(last_interaction_at, accepted_at, created_at)
+	`, time.Now().Add(-threshold))
+}
+
+// CreateBatch bulk-loads connections using COPY.
+func (r *ConnectionRepository) CreateBatch(ctx context.Context, conns []*social.Connection) error {
+	if len(conns) == 0 {
+		return nil
+	}
+
+	rows := make([][]interface{}, len(conns))
+	for i, c := range conns {
+		rows[i] = []interface{}{
+			c.ID, string(c.InitiatorID), string(c.ReceiverID), connectionTypeToDB(c.Type), connectionStatusToDB(c.Status),
+			nullIfEmpty(string(c.Context.TaskID)), nullIfEmpty(c.Context.Note),
+			c.CreatedAt, c.UpdatedAt, c.AcceptedAt,
+		}
+	}
+
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		_, err := tx.CopyFrom(ctx,
+			pgx.Identifier{"connections"},
+			[]string{
+				"id", "from_student_id", "to_student_id", "connection_type", "status",
+				"task_id", "note", "created_at", "updated_at", "accepted_at",
+			},
+			pgx.CopyFromRows(rows),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to copy connections: %w", err)
+		}
+		return nil
+	})
+}
+
+// list returns the connections matching the query tail (WHERE, ORDER BY, ...).
+func (r *ConnectionRepository) list(ctx context.Context, failure, tail string, args ...interface{}) ([]*social.Connection, error) {
+	rows, err := r.conn.Query(ctx, `SELECT `+connectionColumns+` FROM connections `+tail, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", failure, err)
+	}
+	defer rows.Close()
+
+	return scanConnections(rows)
+}
+
+// count counts the connections matching the condition.
+func (r *ConnectionRepository) count(ctx context.Context, condition string, args ...interface{}) (int, error) {
+	var n int
+	if err := r.conn.QueryRow(ctx, `SELECT COUNT(*) FROM connections WHERE `+condition, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count connections: %w", err)
+	}
+	return n, nil
+}
+
+// existsBetween reports whether the students have a connection with one of
+// the statuses in either direction.
+func (r *ConnectionRepository) existsBetween(ctx context.Context, student1, student2 social.StudentID, statuses []string) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM connections
+			WHERE ((from_student_id = $1 AND to_student_id = $2)
+			    OR (from_student_id = $2 AND to_student_id = $1))
+			  AND status = ANY($3)
+		)
+	`
+
+	var exists bool
+	if err := r.conn.QueryRow(ctx, query, string(student1), string(student2), statuses).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check connection between students: %w", err)
+	}
+	return exists, nil
+}
+
+// scanConnections scans rows of connectionColumns.
+func scanConnections(rows pgx.Rows) ([]*social.Connection, error) {
+	var conns []*social.Connection
+	for rows.Next() {
+		c, err := scanConnection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan connection: %w", err)
+		}
+		conns = append(conns, c)
+	}
+
+	return conns, rows.Err()
+}
+
+// scanConnection scans a row of connectionColumns.
+func scanConnection(row pgx.Row) (*social.Connection, error) {
+	var c social.Connection
+	var fromID, toID, connType, status, taskID string
+	var helpRequestID *string
+	var lastInteractionAt *time.Time
+
+	err := row.Scan(
+		&c.ID, &fromID, &toID, &connType, &status,
+		&taskID, &helpRequestID, &c.Context.Note,
+		&c.Stats.InteractionCount, &c.Stats.TotalHelpTime, &c.Stats.TasksSolvedTogether, &lastInteractionAt,
+		&c.CreatedAt, &c.UpdatedAt, &c.AcceptedAt, &c.EndedAt, &c.EndReason,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.InitiatorID = social.StudentID(fromID)
+	c.ReceiverID = social.StudentID(toID)
+	c.Type = connectionTypeFromDB(connType)
+	c.Status = social.ConnectionStatus(status)
+	c.Context.TaskID = social.TaskID(taskID)
+	if helpRequestID != nil {
+		c.Context.HelpRequestID = *helpRequestID
+	}
+	if lastInteractionAt != nil {
+		c.Stats.LastInteractionAt = *lastInteractionAt
+	}
+
+	return &c, nil
+}
+
+// connectionTypeToDB maps a domain connection type onto the
+// connections.connection_type check constraint.
+func connectionTypeToDB(t social.ConnectionType) string {
+	if t.IsValid() {
+		return string(t)
+	}
+	return string(social.ConnectionTypeHelper)
+}
+
+// connectionTypeFromDB maps connections.connection_type back onto a domain type.
+// Legacy "peer" rows covered helper and coworker; helper is the closest match.
+func connectionTypeFromDB(t string) social.ConnectionType {
+	if t == "peer" {
+		return social.ConnectionTypeHelper
+	}
+	return social.ConnectionType(t)
+}
+
+// connectionTypesToDB returns the stored types matching the domain types,
+// including legacy "peer" for helper.
+func connectionTypesToDB(types []social.ConnectionType) []string {
+	stored := make([]string, 0, len(types)+1)
+	for _, t := range types {
+		stored = append(stored, connectionTypeToDB(t))
+		if t == social.ConnectionTypeHelper {
+			stored = append(stored, "peer")
+		}
+	}
+	return stored
+}
+
+// connectionStatusToDB returns the stored status; an empty status is stored as pending.
+func connectionStatusToDB(status social.ConnectionStatus) string {
+	if status == "" {
+		return string(social.ConnectionStatusPending)
+	}
+	return string(status)
+}
+
+// nullIfEmpty converts an optional string to a nullable column value.
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// nullTime converts an optional time to a nullable column value.
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
 }
 
 // -----------------------------------------------------------------------------
@@ -145,100 +564,964 @@ type HelpRequestRepository struct {
 	conn *Connection
 }
 
+// helpRequestColumns are the help_requests columns read by scanHelpRequest.
+const helpRequestColumns = `
+	id, requester_id, task_id, COALESCE(task_name, ''), COALESCE(message, ''), status, priority,
+	helper_id, created_at, updated_at, expires_at, resolved_at,
+	first_matched_at, first_helper_response_at, escalated_at, endorsement_reminder_sent_at,
+	feedback_poll_sent_at, cluster_id, group_opt_in_at, matched_helpers`
+
 func (r *HelpRequestRepository) Create(ctx context.Context, req *social.HelpRequest) error {
-	return errors.New("not implemented")
+	query := `
+		INSERT INTO help_requests (
+			id, requester_id, task_id, task_name, message, status, priority, helper_id,
+			created_at, updated_at, expires_at, resolved_at,
+			first_matched_at, first_helper_response_at, escalated_at, endorsement_reminder_sent_at,
+			feedback_poll_sent_at, cluster_id, group_opt_in_at, matched_helpers
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+	`
+
+	helpers, err := matchedHelpersToDB(req.MatchedHelpers)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.conn.Exec(ctx, query,
+		req.ID, string(req.RequesterID), string(req.TaskID), req.TaskName, req.Description,
+		helpStatusToDB(req.Status), string(req.Priority), helperIDToDB(req.HelperID),
+		req.CreatedAt, req.UpdatedAt, req.ExpiresAt, req.ResolvedAt,
+		req.FirstMatchedAt, req.FirstHelperResponseAt, req.EscalatedAt, req.EndorsementReminderSentAt,
+		req.FeedbackPollSentAt, clusterIDToDB(req.ClusterID), req.GroupOptInAt, helpers,
+	)
+	// idx_help_requests_open_requester_task: one open request per task
+	if IsUniqueViolation(err) {
+		return social.ErrHelpRequestDuplicate
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create help request: %w", err)
+	}
+	return nil
 }
 
 func (r *HelpRequestRepository) GetByID(ctx context.Context, id string) (*social.HelpRequest, error) {
-	return nil, errors.New("not implemented")
+	query := `SELECT ` + helpRequestColumns + ` FROM help_requests WHERE id = $1`
+
+	req, err := scanHelpRequest(r.conn.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, social.ErrHelpRequestNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get help request: %w", err)
+	}
+	if err := r.attachMembers(ctx, req); err != nil {
+		return nil, err
+	}
+	return req, nil
 }
 
+// Update saves the request. First-reaction timestamps, the cluster and the
+// group opt-in are written with COALESCE, so once stored they are never
+// overwritten, even by concurrent updates.
 func (r *HelpRequestRepository) Update(ctx context.Context, req *social.HelpRequest) error {
-	return errors.New("not implemented")
+	query := `
+		UPDATE help_requests SET
+			status = $2,
+			priority = $3,
+			helper_id = $4,
+			updated_at = $5,
+			expires_at = $6,
+			resolved_at = $7,
+			first_matched_at = COALESCE(first_matched_at, $8),
+			first_helper_response_at = COALESCE(first_helper_response_at, $9),
+			escalated_at = COALESCE(escalated_at, $10),
+			endorsement_reminder_sent_at = COALESCE(endorsement_reminder_sent_at, $11),
+			feedback_poll_sent_at = COALESCE(feedback_poll_sent_at, $12),
+			cluster_id = COALESCE(cluster_id, $13),
+			group_opt_in_at = COALESCE(group_opt_in_at, $14),
+			matched_helpers = $15
+		WHERE id = $1
+	`
+
+	helpers, err := matchedHelpersToDB(req.MatchedHelpers)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.conn.Exec(ctx, query,
+		req.ID, helpStatusToDB(req.Status), string(req.Priority), helperIDToDB(req.HelperID),
+		req.UpdatedAt, req.ExpiresAt, req.ResolvedAt,
+		req.FirstMatchedAt, req.FirstHelperResponseAt, req.EscalatedAt, req.EndorsementReminderSentAt,
+		req.FeedbackPollSentAt, clusterIDToDB(req.ClusterID), req.GroupOptInAt, helpers,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update help request: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return social.ErrHelpRequestNotFound
+	}
+	return nil
 }
 
+// Delete cancels an unclosed request; the row is kept for stats and
+// endorsements. Closed requests are left as they are.
 func (r *HelpRequestRepository) Delete(ctx context.Context, id string) error {
-	return errors.New("not implemented")
+	query := `
+		UPDATE help_requests
+		SET status = CASE WHEN status IN ('open', 'matched', 'in_progress') THEN 'cancelled' ELSE status END,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	tag, err := r.conn.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete help request: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return social.ErrHelpRequestNotFound
+	}
+	return nil
 }
 
+// GetByRequesterID returns a page of a student's requests.
 func (r *HelpRequestRepository) GetByRequesterID(ctx context.Context, requesterID social.StudentID, opts social.HelpRequestListOptions) ([]*social.HelpRequest, error) {
-	return nil, errors.New("not implemented")
+	return r.page(ctx, "failed to get help requests by requester", `requester_id = $1`, opts, string(requesterID))
 }
 
+// GetOpenByRequesterID returns a student's open and matched requests, newest first.
 func (r *HelpRequestRepository) GetOpenByRequesterID(ctx context.Context, requesterID social.StudentID) ([]*social.HelpRequest, error) {
-	return nil, errors.New("not implemented")
+	return r.list(ctx, "failed to get open help requests by requester", `
+		WHERE requester_id = $1 AND status IN ('open', 'matched')
+		ORDER BY created_at DESC
+	`, string(requesterID))
 }
 
+// GetByTaskID returns a page of the requests for a task.
 func (r *HelpRequestRepository) GetByTaskID(ctx context.Context, taskID social.TaskID, opts social.HelpRequestListOptions) ([]*social.HelpRequest, error) {
-	return nil, errors.New("not implemented")
+	return r.page(ctx, "failed to get help requests by task", `task_id = $1`, opts, string(taskID))
 }
 
+// GetOpenByTaskID returns the open and matched requests for a task, oldest first.
 func (r *HelpRequestRepository) GetOpenByTaskID(ctx context.Context, taskID social.TaskID) ([]*social.HelpRequest, error) {
-	return nil, errors.New("not implemented")
+	return r.list(ctx, "failed to get open help requests by task", `
+		WHERE task_id = $1 AND status IN ('open', 'matched')
+		ORDER BY created_at
+	`, string(taskID))
 }
 
+// GetByHelperID returns a page of the requests a student helps with.
 func (r *HelpRequestRepository) GetByHelperID(ctx context.Context, helperID social.StudentID, opts social.HelpRequestListOptions) ([]*social.HelpRequest, error) {
-	return nil, errors.New("not implemented")
+	return r.page(ctx, "failed to get help requests by helper", `helper_id = $1`, opts, string(helperID))
 }
 
+// GetByStatus returns a page of the requests with the given status.
 func (r *HelpRequestRepository) GetByStatus(ctx context.Context, status social.HelpRequestStatus, opts social.HelpRequestListOptions) ([]*social.HelpRequest, error) {
-	return nil, errors.New("not implemented")
+	opts.IncludeClosed = true
+	return r.page(ctx, "failed to get help requests by status", `status = $1`, opts, string(status))
 }
 
+// GetByPriority returns a page of the requests with the given priority.
 func (r *HelpRequestRepository) GetByPriority(ctx context.Context, priority social.HelpRequestPriority, opts social.HelpRequestListOptions) ([]*social.HelpRequest, error) {
-	return nil, errors.New("not implemented")
+	return r.page(ctx, "failed to get help requests by priority", `priority = $1`, opts, string(priority))
 }
 
+// GetExpired returns unclosed requests past their expires_at that
+// MarkExpiredRequests has not expired yet.
 func (r *HelpRequestRepository) GetExpired(ctx context.Context) ([]*social.HelpRequest, error) {
-	return nil, errors.New("not implemented")
+	return r.list(ctx, "failed to get expired help requests", `
+		WHERE status IN ('open', 'matched')
+		  AND expires_at IS NOT NULL AND expires_at < NOW()
+		ORDER BY expires_at
+	`)
 }
 
+// GetRecentOpen returns the newest open requests.
 func (r *HelpRequestRepository) GetRecentOpen(ctx context.Context, limit int) ([]*social.HelpRequest, error) {
-	return nil, errors.New("not implemented")
+	return r.list(ctx, "failed to get recent open help requests", `
+		WHERE status = 'open'
+		ORDER BY created_at DESC
+		LIMIT $1
+	`, social.ListBounds.ClampLimit(limit))
 }
 
+// GetUrgent returns open requests that expire within the given hours, the
+// closest first.
 func (r *HelpRequestRepository) GetUrgent(ctx context.Context, withinHours int) ([]*social.HelpRequest, error) {
-	return nil, errors.New("not implemented")
+	return r.list(ctx, "failed to get urgent help requests", `
+		WHERE status IN ('open', 'matched')
+		  AND expires_at BETWEEN NOW() AND NOW() + make_interval(hours => $1)
+		ORDER BY expires_at
+	`, withinHours)
+}
+
+// GetUnansweredUrgent returns open urgent requests created before the given
+// time that nobody has reacted to and that were not escalated yet.
+func (r *HelpRequestRepository) GetUnansweredUrgent(ctx context.Context, createdBefore time.Time) ([]*social.HelpRequest, error) {
+	query := `SELECT ` + helpRequestColumns + `
+		FROM help_requests
+		WHERE priority = 'urgent'
+		  AND status IN ('open', 'matched')
+		  AND first_helper_response_at IS NULL
+		  AND escalated_at IS NULL
+		  AND created_at <= $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.conn.Query(ctx, query, createdBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unanswered urgent help requests: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*social.HelpRequest
+	for rows.Next() {
+		req, err := scanHelpRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan help request: %w", err)
+		}
+		result = append(result, req)
+	}
+	return result, rows.Err()
+}
+
+// GetAwaitingEndorsement returns requests resolved with a helper within
+// [resolvedAfter, resolvedBefore] that no reminder was sent for and that not
+// every participant (the requester and accepted teammates) endorsed yet.
+func (r *HelpRequestRepository) GetAwaitingEndorsement(ctx context.Context, resolvedAfter, resolvedBefore time.Time) ([]*social.HelpRequest, error) {
+	query := `SELECT ` + helpRequestColumns + `
+		FROM help_requests hr
+		WHERE status = 'resolved'
+		  AND helper_id IS NOT NULL
+		  AND endorsement_reminder_sent_at IS NULL
+		  AND resolved_at BETWEEN $1 AND $2
+		  AND (SELECT COUNT(DISTINCT e.from_student_id) FROM endorsements e WHERE e.help_request_id = hr.id)
+		      < 1 + (SELECT COUNT(*) FROM help_request_members m WHERE m.help_request_id = hr.id AND m.status = 'accepted')
+		ORDER BY resolved_at
+	`
+
+	rows, err := r.conn.Query(ctx, query, resolvedAfter, resolvedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get help requests awaiting endorsement: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*social.HelpRequest
+	for rows.Next() {
+		req, err := scanHelpRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan help request: %w", err)
+		}
+		result = append(result, req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, r.attachMembers(ctx, result...)
+}
+
+// CountOpenByHelpers returns how many matched or in-progress requests each
+// helper is assigned to. Helpers without such requests are absent.
+func (r *HelpRequestRepository) CountOpenByHelpers(ctx context.Context, helperIDs []social.StudentID) (map[social.StudentID]int, error) {
+	counts := make(map[social.StudentID]int)
+	if len(helperIDs) == 0 {
+		return counts, nil
+	}
+
+	ids := make([]string, len(helperIDs))
+	for i, id := range helperIDs {
+		ids[i] = string(id)
+	}
+
+	query := `
+		SELECT helper_id, COUNT(*)
+		FROM help_requests
+		WHERE helper_id = ANY($1::uuid[])
+		  AND status IN ('matched', 'in_progress')
+		GROUP BY helper_id
+	`
+
+	rows, err := r.conn.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count open help requests by helper: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var helperID string
+		var count int
+		if err := rows.Scan(&helperID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan open help request count: %w", err)
+		}
+		counts[social.StudentID(helperID)] = count
+	}
+	return counts, rows.Err()
+}
+
+// ClaimEndorsementReminder sets endorsement_reminder_sent_at unless it is
+// already set, so concurrent runs cannot both send a reminder.
+func (r *HelpRequestRepository) ClaimEndorsementReminder(ctx context.Context, id string, at time.Time) (bool, error) {
+	query := `
+		UPDATE help_requests
+		SET endorsement_reminder_sent_at = $2, updated_at = $2
+		WHERE id = $1 AND endorsement_reminder_sent_at IS NULL
+	`
+
+	tag, err := r.conn.Exec(ctx, query, id, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim endorsement reminder: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// GetAwaitingFeedbackPoll returns requests resolved with a helper within
+// [resolvedAfter, resolvedBefore] that have no feedback and no poll yet.
+func (r *HelpRequestRepository) GetAwaitingFeedbackPoll(ctx context.Context, resolvedAfter, resolvedBefore time.Time) ([]*social.HelpRequest, error) {
+	query := `SELECT ` + helpRequestColumns + `
+		FROM help_requests hr
+		WHERE status = 'resolved'
+		  AND helper_id IS NOT NULL
+		  AND feedback_poll_sent_at IS NULL
+		  AND resolved_at BETWEEN $1 AND $2
+		  AND NOT EXISTS (SELECT 1 FROM help_feedback f WHERE f.help_request_id = hr.id)
+		ORDER BY resolved_at
+	`
+
+	rows, err := r.conn.Query(ctx, query, resolvedAfter, resolvedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get help requests awaiting feedback poll: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*social.HelpRequest
+	for rows.Next() {
+		req, err := scanHelpRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan help request: %w", err)
+		}
+		result = append(result, req)
+	}
+	return result, rows.Err()
+}
+
+// ClaimFeedbackPoll sets feedback_poll_sent_at unless it is already set,
+// so concurrent runs cannot both send a poll.
+func (r *HelpRequestRepository) ClaimFeedbackPoll(ctx context.Context, id string, at time.Time) (bool, error) {
+	query := `
+		UPDATE help_requests
+		SET feedback_poll_sent_at = $2, updated_at = $2
+		WHERE id = $1 AND feedback_poll_sent_at IS NULL
+	`
+
+	tag, err := r.conn.Exec(ctx, query, id, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim feedback poll: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
 }
 
+// Search returns the requests matching every set criterion, newest first.
 func (r *HelpRequestRepository) Search(ctx context.Context, criteria social.HelpRequestSearchCriteria) ([]*social.HelpRequest, error) {
-	return nil, errors.New("not implemented")
+	where, args := helpRequestSearchWhere(criteria)
+	args = append(args, social.ListBounds.ClampLimit(criteria.Limit), max(criteria.Offset, 0))
+	tail := fmt.Sprintf(`%s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args))
+
+	return r.list(ctx, "failed to search help requests", tail, args...)
 }
 
 func (r *HelpRequestRepository) Exists(ctx context.Context, id string) (bool, error) {
-	return false, errors.New("not implemented")
+	var exists bool
+	err := r.conn.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM help_requests WHERE id = $1)`, id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check help request: %w", err)
+	}
+	return exists, nil
 }
 
+// HasOpenRequestForTask reports whether the student has an open or matched
+// request for the task.
 func (r *HelpRequestRepository) HasOpenRequestForTask(ctx context.Context, requesterID social.StudentID, taskID social.TaskID) (bool, error) {
-	return false, errors.New("not implemented")
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM help_requests
+			WHERE requester_id = $1 AND task_id = $2 AND status IN ('open', 'matched')
+		)
+	`
+
+	var exists bool
+	if err := r.conn.QueryRow(ctx, query, string(requesterID), string(taskID)).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check open help request for task: %w", err)
+	}
+	return exists, nil
 }
 
 func (r *HelpRequestRepository) CountByRequesterID(ctx context.Context, requesterID social.StudentID) (int, error) {
-	return 0, errors.New("not implemented")
+	return r.count(ctx, `requester_id = $1`, string(requesterID))
 }
 
+// CountOpenByRequesterID counts a student's open and matched requests.
 func (r *HelpRequestRepository) CountOpenByRequesterID(ctx context.Context, requesterID social.StudentID) (int, error) {
-	return 0, errors.New("not implemented")
+	return r.count(ctx, `requester_id = $1 AND status IN ('open', 'matched')`, string(requesterID))
 }
 
 func (r *HelpRequestRepository) CountByTaskID(ctx context.Context, taskID social.TaskID) (int, error) {
-	return 0, errors.New("not implemented")
+	return r.count(ctx, `task_id = $1`, string(taskID))
+}
+
+// CountResolved returns how many help requests were resolved, all-time.
+func (r *HelpRequestRepository) CountResolved(ctx context.Context) (int, error) {
+	var count int
+	err := r.conn.QueryRow(ctx, `SELECT COUNT(*) FROM help_requests WHERE status = 'resolved'`).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count resolved help requests: %w", err)
+	}
+	return count, nil
 }
 
+// GetHelpRequestStats aggregates a student's requests per status and
+// priority in one query. TopTasks are the tasks asked about most.
 func (r *HelpRequestRepository) GetHelpRequestStats(ctx context.Context, studentID social.StudentID) (*social.HelpRequestStatsAggregate, error) {
-	return nil, errors.New("not implemented")
+	query := `
+		SELECT
+			status,
+			priority,
+			COUNT(*),
+			COALESCE(SUM(EXTRACT(EPOCH FROM resolved_at - created_at))
+				FILTER (WHERE status = 'resolved' AND resolved_at IS NOT NULL), 0)::FLOAT8,
+			COUNT(*) FILTER (WHERE status = 'resolved' AND resolved_at IS NOT NULL)
+		FROM help_requests
+		WHERE requester_id = $1
+		GROUP BY status, priority
+	`
+
+	rows, err := r.conn.Query(ctx, query, string(studentID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get help request stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &social.HelpRequestStatsAggregate{
+		RequestsByPriority: make(map[social.HelpRequestPriority]int),
+		RequestsByStatus:   make(map[social.HelpRequestStatus]int),
+	}
+	var resolvedSeconds float64
+	var timedResolved int
+	for rows.Next() {
+		var status, priority string
+		var n, timed int
+		var seconds float64
+		if err := rows.Scan(&status, &priority, &n, &seconds, &timed); err != nil {
+			return nil, fmt.Errorf("failed to scan help request stats: %w", err)
+		}
+		st := social.HelpRequestStatus(status)
+		stats.TotalRequests += n
+		stats.RequestsByStatus[st] += n
+		stats.RequestsByPriority[social.HelpRequestPriority(priority)] += n
+		if st.IsOpen() {
+			stats.OpenRequests += n
+		}
+		if st == social.HelpRequestStatusResolved {
+			stats.ResolvedRequests += n
+		}
+		resolvedSeconds += seconds
+		timedResolved += timed
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if timedResolved > 0 {
+		stats.AverageResolutionTimeMinutes = int(resolvedSeconds / float64(timedResolved) / 60)
+	}
+
+	taskRows, err := r.conn.Query(ctx, `
+		SELECT task_id FROM help_requests
+		WHERE requester_id = $1
+		GROUP BY task_id
+		ORDER BY COUNT(*) DESC, MAX(created_at) DESC
+		LIMIT 5
+	`, string(studentID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get help request top tasks: %w", err)
+	}
+	defer taskRows.Close()
+
+	for taskRows.Next() {
+		var taskID string
+		if err := taskRows.Scan(&taskID); err != nil {
+			return nil, fmt.Errorf("failed to scan help request top task: %w", err)
+		}
+		stats.TopTasks = append(stats.TopTasks, social.TaskID(taskID))
+	}
+	return stats, taskRows.Err()
 }
 
+// GetPopularTasks returns the tasks with the most help requests created since
+// the given time, in one aggregate over idx_help_requests_created_task.
+// Tasks with fewer than social.MinPopularTaskRequests requests are left out.
 func (r *HelpRequestRepository) GetPopularTasks(ctx context.Context, limit int, since time.Time) ([]social.TaskHelpStats, error) {
-	return nil, errors.New("not implemented")
+	query := `
+		SELECT
+			task_id,
+			MAX(task_name),
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'resolved'),
+			COALESCE(ROUND(AVG(EXTRACT(EPOCH FROM resolved_at - created_at) / 60)
+				FILTER (WHERE status = 'resolved' AND resolved_at IS NOT NULL)), 0)::int
+		FROM help_requests
+		WHERE created_at >= $1
+		GROUP BY task_id
+		HAVING COUNT(*) >= $2
+		ORDER BY COUNT(*) DESC, task_id
+		LIMIT $3
+	`
+
+	rows, err := r.conn.Query(ctx, query, since, social.MinPopularTaskRequests, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get popular tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []social.TaskHelpStats
+	for rows.Next() {
+		var t social.TaskHelpStats
+		var taskName *string
+		if err := rows.Scan(&t.TaskID, &taskName, &t.RequestCount, &t.ResolvedCount, &t.AverageResolutionMinutes); err != nil {
+			return nil, fmt.Errorf("failed to scan popular task: %w", err)
+		}
+		if taskName != nil {
+			t.TaskName = *taskName
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, rows.Err()
+}
+
+// GetResponseTimeStats returns median first-response times of requests
+// created since the given time, overall and per requester cohort per week.
+func (r *HelpRequestRepository) GetResponseTimeStats(ctx context.Context, since time.Time) (*social.ResponseTimeStats, error) {
+	stats := &social.ResponseTimeStats{Since: since}
+
+	overallQuery := `
+		SELECT
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM first_helper_response_at - created_at))
+				FILTER (WHERE first_helper_response_at IS NOT NULL),
+			COUNT(*) FILTER (WHERE first_helper_response_at IS NOT NULL),
+			COUNT(*) FILTER (WHERE first_helper_response_at IS NULL)
+		FROM help_requests
+		WHERE created_at >= $1
+	`
+
+	var median *float64
+	if err := r.conn.QueryRow(ctx, overallQuery, since).Scan(&median, &stats.Answered, &stats.Unanswered); err != nil {
+		return nil, fmt.Errorf("failed to get response time stats: %w", err)
+	}
+	if median != nil {
+		stats.MedianFirstResponse = secondsToDuration(*median)
+	}
+
+	weeklyQuery := `
+		SELECT
+			s.cohort,
+			date_trunc('week', hr.created_at AT TIME ZONE 'UTC') AS week,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM hr.first_helper_response_at - hr.created_at)),
+			COUNT(*)
+		FROM help_requests hr
+		JOIN students s ON s.id = hr.requester_id
+		WHERE hr.created_at >= $1 AND hr.first_helper_response_at IS NOT NULL
+		GROUP BY s.cohort, week
+		ORDER BY week DESC, s.cohort
+	`
+
+	rows, err := r.conn.Query(ctx, weeklyQuery, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weekly response times: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var w social.WeeklyResponseTime
+		var seconds float64
+		if err := rows.Scan(&w.Cohort, &w.WeekStart, &seconds, &w.Answered); err != nil {
+			return nil, fmt.Errorf("failed to scan weekly response time: %w", err)
+		}
+		w.WeekStart = time.Date(w.WeekStart.Year(), w.WeekStart.Month(), w.WeekStart.Day(), 0, 0, 0, 0, time.UTC)
+		w.MedianFirstResponse = secondsToDuration(seconds)
+		stats.Weekly = append(stats.Weekly, w)
+	}
+
+	return stats, rows.Err()
 }
 
 func (r *HelpRequestRepository) GetByIDs(ctx context.Context, ids []string) ([]*social.HelpRequest, error) {
-	return nil, errors.New("not implemented")
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return r.list(ctx, "failed to get help requests by ids", `WHERE id = ANY($1)`, ids)
+}
+
+// MarkExpiredRequests expires requests nobody has taken yet whose expires_at
+// is before now, matching HelpRequest.IsExpired. Requests with an assigned
+// helper are left to finish.
+func (r *HelpRequestRepository) MarkExpiredRequests(ctx context.Context, now time.Time) (int, error) {
+	query := `
+		UPDATE help_requests
+		SET status = 'expired', updated_at = $1
+		WHERE status IN ('open', 'matched')
+		  AND expires_at IS NOT NULL AND expires_at < $1
+	`
+
+	tag, err := r.conn.Exec(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark expired help requests: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// FindDuplicates returns unclosed requests for the task by other students of
+// the cohort created at or after since, oldest first.
+func (r *HelpRequestRepository) FindDuplicates(ctx context.Context, taskID social.TaskID, cohort string, requesterID social.StudentID, since time.Time) ([]*social.HelpRequest, error) {
+	query := `SELECT ` + helpRequestColumns + `
+		FROM help_requests
+		WHERE task_id = $1
+		  AND status IN ('open', 'matched', 'in_progress')
+		  AND created_at >= $2
+		  AND requester_id <> $3
+		  AND requester_id IN (SELECT id FROM students WHERE cohort = $4)
+		ORDER BY created_at
+	`
+
+	rows, err := r.conn.Query(ctx, query, string(taskID), since, string(requesterID), cohort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate help requests: %w", err)
+	}
+	defer rows.Close()
+
+	return scanHelpRequests(rows)
 }
 
-func (r *HelpRequestRepository) MarkExpiredRequests(ctx context.Context) (int, error) {
-	return 0, errors.New("not implemented")
+// SetCluster sets the cluster of requests that have none yet.
+func (r *HelpRequestRepository) SetCluster(ctx context.Context, clusterID string, requestIDs []string) error {
+	if len(requestIDs) == 0 {
+		return nil
+	}
+
+	_, err := r.conn.Exec(ctx,
+		`UPDATE help_requests SET cluster_id = $1 WHERE id = ANY($2) AND cluster_id IS NULL`,
+		clusterID, requestIDs,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set help request cluster: %w", err)
+	}
+	return nil
+}
+
+// GetByCluster returns the requests of a cluster, oldest first.
+func (r *HelpRequestRepository) GetByCluster(ctx context.Context, clusterID string) ([]*social.HelpRequest, error) {
+	query := `SELECT ` + helpRequestColumns + `
+		FROM help_requests
+		WHERE cluster_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.conn.Query(ctx, query, clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get help request cluster: %w", err)
+	}
+	defer rows.Close()
+
+	return scanHelpRequests(rows)
+}
+
+// ClaimHelperNotification records that the helper was notified about the
+// cluster; the primary key makes concurrent claims for one helper exclusive.
+func (r *HelpRequestRepository) ClaimHelperNotification(ctx context.Context, clusterID string, helperID social.StudentID, at time.Time) (bool, error) {
+	tag, err := r.conn.Exec(ctx, `
+		INSERT INTO help_cluster_notifications (cluster_id, helper_id, notified_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`, clusterID, string(helperID), at)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim help cluster notification: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// scanHelpRequests scans rows of helpRequestColumns.
+func scanHelpRequests(rows pgx.Rows) ([]*social.HelpRequest, error) {
+	var result []*social.HelpRequest
+	for rows.Next() {
+		req, err := scanHelpRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan help request: %w", err)
+		}
+		result = append(result, req)
+	}
+	return result, rows.Err()
+}
+
+// scanHelpRequest scans a row of helpRequestColumns.
+func scanHelpRequest(row pgx.Row) (*social.HelpRequest, error) {
+	var req social.HelpRequest
+	var requesterID, taskID, status, priority string
+	var helperID, clusterID *string
+	var expiresAt *time.Time
+	var helpers []byte
+
+	err := row.Scan(
+		&req.ID, &requesterID, &taskID, &req.TaskName, &req.Description, &status, &priority,
+		&helperID, &req.CreatedAt, &req.UpdatedAt, &expiresAt, &req.ResolvedAt,
+		&req.FirstMatchedAt, &req.FirstHelperResponseAt, &req.EscalatedAt, &req.EndorsementReminderSentAt,
+		&req.FeedbackPollSentAt, &clusterID, &req.GroupOptInAt, &helpers,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	req.RequesterID = social.StudentID(requesterID)
+	req.TaskID = social.TaskID(taskID)
+	req.Status = social.HelpRequestStatus(status)
+	req.Priority = social.HelpRequestPriority(priority)
+	req.MatchedHelpers = matchedHelpersFromDB(helpers)
+	if helperID != nil {
+		id := social.StudentID(*helperID)
+		req.HelperID = &id
+	}
+	if expiresAt != nil {
+		req.ExpiresAt = *expiresAt
+	}
+	if clusterID != nil {
+		req.ClusterID = *clusterID
+	}
+
+	return &req, nil
+}
+
+// storedMatchedHelper is a matched helper as stored in help_requests.matched_helpers.
+type storedMatchedHelper struct {
+	StudentID    string     `json:"student_id"`
+	DisplayName  string     `json:"display_name,omitempty"`
+	HelpRating   float64    `json:"help_rating,omitempty"`
+	SolvedAt     *time.Time `json:"solved_at,omitempty"`
+	IsOnline     bool       `json:"is_online,omitempty"`
+	LastSeenAt   *time.Time `json:"last_seen_at,omitempty"`
+	MatchScore   int        `json:"match_score"`
+	MatchReasons []string   `json:"match_reasons,omitempty"`
+}
+
+// matchedHelpersToDB encodes the matched helpers for the matched_helpers column.
+func matchedHelpersToDB(helpers []social.MatchedHelper) ([]byte, error) {
+	stored := make([]storedMatchedHelper, len(helpers))
+	for i, h := range helpers {
+		stored[i] = storedMatchedHelper{
+			StudentID:    string(h.StudentID),
+			DisplayName:  h.DisplayName,
+			HelpRating:   float64(h.HelpRating),
+			SolvedAt:     nullTime(h.SolvedAt),
+			IsOnline:     h.IsOnline,
+			LastSeenAt:   nullTime(h.LastSeenAt),
+			MatchScore:   h.MatchScore,
+			MatchReasons: h.MatchReasons,
+		}
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal matched helpers: %w", err)
+	}
+	return data, nil
+}
+
+// matchedHelpersFromDB decodes the matched_helpers column. A value that
+// can't be decoded reads as no helpers: they are only shown again on a
+// repeated ask.
+func matchedHelpersFromDB(data []byte) []social.MatchedHelper {
+	var stored []storedMatchedHelper
+	_ = json.Unmarshal(data, &stored)
+
+	helpers := make([]social.MatchedHelper, 0, len(stored))
+	for _, h := range stored {
+		helper := social.MatchedHelper{
+			StudentID:    social.StudentID(h.StudentID),
+			DisplayName:  h.DisplayName,
+			HelpRating:   social.Rating(h.HelpRating),
+			IsOnline:     h.IsOnline,
+			MatchScore:   h.MatchScore,
+			MatchReasons: h.MatchReasons,
+		}
+		if h.SolvedAt != nil {
+			helper.SolvedAt = *h.SolvedAt
+		}
+		if h.LastSeenAt != nil {
+			helper.LastSeenAt = *h.LastSeenAt
+		}
+		helpers = append(helpers, helper)
+	}
+	return helpers
+}
+
+// helpStatusToDB returns the stored status; an empty status is stored as open.
+func helpStatusToDB(status social.HelpRequestStatus) string {
+	if status == "" {
+		return string(social.HelpRequestStatusOpen)
+	}
+	return string(status)
+}
+
+// helperIDToDB converts an optional helper ID to a nullable column value.
+func helperIDToDB(id *social.StudentID) *string {
+	if id == nil {
+		return nil
+	}
+	s := string(*id)
+	return &s
+}
+
+// clusterIDToDB converts an optional cluster ID to a nullable column value.
+func clusterIDToDB(id string) *string {
+	if id == "" {
+		return nil
+	}
+	return &id
+}
+
+// secondsToDuration converts fractional seconds to a duration.
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// list returns the requests matching the query tail (WHERE, ORDER BY, ...)
+// with their members.
+func (r *HelpRequestRepository) list(ctx context.Context, failure, tail string, args ...interface{}) ([]*social.HelpRequest, error) {
+	rows, err := r.conn.Query(ctx, `SELECT `+helpRequestColumns+` FROM help_requests `+tail, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", failure, err)
+	}
+	defer rows.Close()
+
+	requests, err := scanHelpRequests(rows)
+	if err != nil {
+		return nil, err
+	}
+	return requests, r.attachMembers(ctx, requests...)
+}
+
+// page returns a page of the requests matching condition and the list
+// options. The condition uses args from $1 on.
+func (r *HelpRequestRepository) page(ctx context.Context, failure, condition string, opts social.HelpRequestListOptions, args ...interface{}) ([]*social.HelpRequest, error) {
+	page := opts.Normalized()
+	n := len(args)
+	args = append(args, opts.IncludeClosed, helpStatusesToDB(opts.Statuses), helpPrioritiesToDB(opts.Priorities), page.Limit, page.Offset)
+
+	tail := fmt.Sprintf(`
+		WHERE %s
+		  AND ($%d OR status NOT IN ('resolved', 'cancelled', 'expired'))
+		  AND (cardinality($%d::text[]) = 0 OR status = ANY($%d))
+		  AND (cardinality($%d::text[]) = 0 OR priority = ANY($%d))
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, condition, n+1, n+2, n+2, n+3, n+3, helpRequestOrderBy(opts), n+4, n+5)
+
+	return r.list(ctx, failure, tail, args...)
+}
+
+// count counts the requests matching the condition.
+func (r *HelpRequestRepository) count(ctx context.Context, condition string, args ...interface{}) (int, error) {
+	var n int
+	if err := r.conn.QueryRow(ctx, `SELECT COUNT(*) FROM help_requests WHERE `+condition, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count help requests: %w", err)
+	}
+	return n, nil
+}
+
+// helpRequestOrderBy returns the ORDER BY of the list options. Only known
+// columns are accepted; anything else sorts by created_at.
+func helpRequestOrderBy(opts social.HelpRequestListOptions) string {
+	column := "created_at"
+	switch opts.SortBy {
+	case "updated_at", "expires_at", "resolved_at":
+		column = opts.SortBy
+	}
+	if opts.SortDesc {
+		return column + " DESC NULLS LAST, id"
+	}
+	return column + " ASC NULLS LAST, id"
+}
+
+// helpRequestSearchWhere builds the WHERE clause of Search; set criteria
+// are ANDed.
+func helpRequestSearchWhere(c social.HelpRequestSearchCriteria) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if len(c.TaskIDs) > 0 {
+		ids := make([]string, len(c.TaskIDs))
+		for i, id := range c.TaskIDs {
+			ids[i] = string(id)
+		}
+		add("task_id = ANY($%d)", ids)
+	}
+	if len(c.RequesterIDs) > 0 {
+		add("requester_id = ANY($%d)", studentIDsToDB(c.RequesterIDs))
+	}
+	if len(c.HelperIDs) > 0 {
+		add("helper_id = ANY($%d)", studentIDsToDB(c.HelperIDs))
+	}
+	if len(c.Statuses) > 0 {
+		add("status = ANY($%d)", helpStatusesToDB(c.Statuses))
+	}
+	if len(c.Priorities) > 0 {
+		add("priority = ANY($%d)", helpPrioritiesToDB(c.Priorities))
+	}
+	if c.CreatedAfter != nil {
+		add("created_at > $%d", *c.CreatedAfter)
+	}
+	if c.CreatedBefore != nil {
+		add("created_at < $%d", *c.CreatedBefore)
+	}
+	if c.HasDeadline != nil {
+		if *c.HasDeadline {
+			conditions = append(conditions, "expires_at IS NOT NULL")
+		} else {
+			conditions = append(conditions, "expires_at IS NULL")
+		}
+	}
+	if c.DeadlineBefore != nil {
+		add("expires_at < $%d", *c.DeadlineBefore)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// helpStatusesToDB converts domain statuses to column values.
+func helpStatusesToDB(statuses []social.HelpRequestStatus) []string {
+	stored := make([]string, len(statuses))
+	for i, s := range statuses {
+		stored[i] = string(s)
+	}
+	return stored
+}
+
+// helpPrioritiesToDB converts domain priorities to column values.
+func helpPrioritiesToDB(priorities []social.HelpRequestPriority) []string {
+	stored := make([]string, len(priorities))
+	for i, p := range priorities {
+		stored[i] = string(p)
+	}
+	return stored
+}
+
+// studentIDsToDB converts student IDs to column values.
+func studentIDsToDB(ids []social.StudentID) []string {
+	stored := make([]string, len(ids))
+	for i, id := range ids {
+		stored[i] = string(id)
+	}
+	return stored
 }
 
 // -----------------------------------------------------------------------------
@@ -249,76 +1532,391 @@ type EndorsementRepository struct {
 	conn *Connection
 }
 
+// endorsementColumns are the endorsements columns read by scanEndorsement.
+const endorsementColumns = `
+	id, from_student_id, to_student_id, help_request_id, COALESCE(task_id, ''),
+	COALESCE(endorsement_type, ''), rating, message, is_public, created_at`
+
 func (r *EndorsementRepository) Create(ctx context.Context, endorsement *social.Endorsement) error {
-	return errors.New("not implemented")
+	query := `
+		INSERT INTO endorsements (
+			id, from_student_id, to_student_id, help_request_id, task_id,
+			endorsement_type, rating, message, is_public, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.conn.Exec(ctx, query, endorsementValues(endorsement)...)
+	if IsUniqueViolation(err) {
+		return social.ErrEndorsementAlreadyExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create endorsement: %w", err)
+	}
+	return nil
 }
 
 func (r *EndorsementRepository) GetByID(ctx context.Context, id string) (*social.Endorsement, error) {
-	return nil, errors.New("not implemented")
+	query := `SELECT ` + endorsementColumns + ` FROM endorsements WHERE id = $1`
+
+	e, err := scanEndorsement(r.conn.QueryRow(ctx, query, id))
+	if IsNoRows(err) {
+		return nil, social.ErrEndorsementNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get endorsement: %w", err)
+	}
+	return e, nil
 }
 
+// Delete removes the endorsement; the help rating trigger recomputes the
+// receiver's rating.
 func (r *EndorsementRepository) Delete(ctx context.Context, id string) error {
-	return errors.New("not implemented")
+	tag, err := r.conn.Exec(ctx, `DELETE FROM endorsements WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete endorsement: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return social.ErrEndorsementNotFound
+	}
+	return nil
 }
 
+// GetByGiverID returns a page of endorsements given by a student, newest first.
 func (r *EndorsementRepository) GetByGiverID(ctx context.Context, giverID social.StudentID, opts social.EndorsementListOptions) ([]*social.Endorsement, error) {
-	return nil, errors.New("not implemented")
+	return r.page(ctx, "failed to get given endorsements", `from_student_id = $1`, opts, string(giverID))
 }
 
+// GetByReceiverID returns a page of endorsements received by a student, newest first.
+// Giver names are resolved by the caller, not joined here.
 func (r *EndorsementRepository) GetByReceiverID(ctx context.Context, receiverID social.StudentID, opts social.EndorsementListOptions) ([]*social.Endorsement, error) {
-	return nil, errors.New("not implemented")
+	return r.page(ctx, "failed to get endorsements", `to_student_id = $1`, opts, string(receiverID))
 }
 
+// GetByHelpRequestID returns the endorsement given for a help request.
 func (r *EndorsementRepository) GetByHelpRequestID(ctx context.Context, helpRequestID string) (*social.Endorsement, error) {
-	return nil, errors.New("not implemented")
+	query := `SELECT ` + endorsementColumns + `
+		FROM endorsements
+		WHERE help_request_id = $1
+		ORDER BY created_at
+		LIMIT 1
+	`
+
+	e, err := scanEndorsement(r.conn.QueryRow(ctx, query, helpRequestID))
+	if IsNoRows(err) {
+		return nil, social.ErrEndorsementNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get endorsement by help request: %w", err)
+	}
+	return e, nil
+}
+
+// ListByHelpRequestID returns every endorsement given for a help request,
+// oldest first.
+func (r *EndorsementRepository) ListByHelpRequestID(ctx context.Context, helpRequestID string) ([]*social.Endorsement, error) {
+	return r.list(ctx, "failed to list endorsements by help request", `
+		WHERE help_request_id = $1
+		ORDER BY created_at
+	`, helpRequestID)
 }
 
+// GetByTaskID returns a page of endorsements for help with a task, newest first.
 func (r *EndorsementRepository) GetByTaskID(ctx context.Context, taskID social.TaskID, opts social.EndorsementListOptions) ([]*social.Endorsement, error) {
-	return nil, errors.New("not implemented")
+	return r.page(ctx, "failed to get endorsements by task", `task_id = $1`, opts, string(taskID))
 }
 
+// GetByType returns the endorsements of a type a student received, newest first.
 func (r *EndorsementRepository) GetByType(ctx context.Context, receiverID social.StudentID, endorsementType social.EndorsementType) ([]*social.Endorsement, error) {
-	return nil, errors.New("not implemented")
+	return r.list(ctx, "failed to get endorsements by type", `
+		WHERE to_student_id = $1 AND endorsement_type = $2
+		ORDER BY created_at DESC
+	`, string(receiverID), string(endorsementType))
 }
 
+// GetPublic returns a page of the public endorsements a student received.
 func (r *EndorsementRepository) GetPublic(ctx context.Context, receiverID social.StudentID, opts social.EndorsementListOptions) ([]*social.Endorsement, error) {
-	return nil, errors.New("not implemented")
+	opts.PublicOnly = true
+	return r.GetByReceiverID(ctx, receiverID, opts)
 }
 
+// GetRecent returns the newest endorsements given since the given time.
 func (r *EndorsementRepository) GetRecent(ctx context.Context, limit int, since time.Time) ([]*social.Endorsement, error) {
-	return nil, errors.New("not implemented")
+	return r.list(ctx, "failed to get recent endorsements", `
+		WHERE created_at >= $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, since, social.ListBounds.ClampLimit(limit))
 }
 
 func (r *EndorsementRepository) Exists(ctx context.Context, id string) (bool, error) {
-	return false, errors.New("not implemented")
+	var exists bool
+	if err := r.conn.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM endorsements WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check endorsement: %w", err)
+	}
+	return exists, nil
 }
 
 func (r *EndorsementRepository) ExistsForHelpRequest(ctx context.Context, helpRequestID string) (bool, error) {
-	return false, errors.New("not implemented")
+	query := `SELECT EXISTS(SELECT 1 FROM endorsements WHERE help_request_id = $1)`
+
+	var exists bool
+	if err := r.conn.QueryRow(ctx, query, helpRequestID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check endorsement for help request: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *EndorsementRepository) ExistsFromGiverSince(ctx context.Context, giverID, receiverID social.StudentID, since time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM endorsements
+			WHERE from_student_id = $1 AND to_student_id = $2 AND created_at >= $3
+		)
+	`
+
+	var exists bool
+	if err := r.conn.QueryRow(ctx, query, string(giverID), string(receiverID), since).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check recent endorsements: %w", err)
+	}
+	return exists, nil
 }
 
 func (r *EndorsementRepository) CountByReceiverID(ctx context.Context, receiverID social.StudentID) (int, error) {
-	return 0, errors.New("not implemented")
+	var n int
+	if err := r.conn.QueryRow(ctx, `SELECT COUNT(*) FROM endorsements WHERE to_student_id = $1`, string(receiverID)).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count endorsements: %w", err)
+	}
+	return n, nil
 }
 
+// GetAverageRating returns the average rating a student received, 0 if none.
 func (r *EndorsementRepository) GetAverageRating(ctx context.Context, receiverID social.StudentID) (social.Rating, error) {
-	return 0, errors.New("not implemented")
+	var avg float64
+	err := r.conn.QueryRow(ctx,
+		`SELECT COALESCE(AVG(rating), 0)::FLOAT8 FROM endorsements WHERE to_student_id = $1`,
+		string(receiverID),
+	).Scan(&avg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get average rating: %w", err)
+	}
+	return social.Rating(avg), nil
 }
 
+// GetEndorsementStats returns a student's received and given totals in one
+// query, plus the per-type breakdown.
 func (r *EndorsementRepository) GetEndorsementStats(ctx context.Context, studentID social.StudentID) (*social.EndorsementStatsAggregate, error) {
-	return nil, errors.New("not implemented")
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE to_student_id = $1),
+			COUNT(*) FILTER (WHERE from_student_id = $1),
+			COALESCE(AVG(rating) FILTER (WHERE to_student_id = $1), 0)::FLOAT8,
+			COUNT(*) FILTER (WHERE to_student_id = $1 AND rating >= 4),
+			COUNT(*) FILTER (WHERE to_student_id = $1 AND created_at >= NOW() - INTERVAL '7 days')
+		FROM endorsements
+		WHERE to_student_id = $1 OR from_student_id = $1
+	`
+
+	stats := &social.EndorsementStatsAggregate{}
+	var avg float64
+	err := r.conn.QueryRow(ctx, query, string(studentID)).Scan(
+		&stats.TotalReceived, &stats.TotalGiven, &avg, &stats.PositiveCount, &stats.RecentCount,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get endorsement stats: %w", err)
+	}
+	stats.AverageRating = social.Rating(avg)
+
+	if stats.ByType, err = r.GetTypeStats(ctx, studentID); err != nil {
+		return nil, err
+	}
+	return stats, nil
 }
 
+// GetTypeStats counts the endorsements a student received per type, the
+// most frequent first. Endorsements without a type are left out.
 func (r *EndorsementRepository) GetTypeStats(ctx context.Context, receiverID social.StudentID) ([]social.EndorsementTypeStat, error) {
-	return nil, errors.New("not implemented")
+	query := `
+		SELECT endorsement_type, COUNT(*)
+		FROM endorsements
+		WHERE to_student_id = $1 AND endorsement_type IS NOT NULL
+		GROUP BY endorsement_type
+		ORDER BY COUNT(*) DESC, endorsement_type
+	`
+
+	rows, err := r.conn.Query(ctx, query, string(receiverID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get endorsement type stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []social.EndorsementTypeStat
+	for rows.Next() {
+		var stat social.EndorsementTypeStat
+		var endorsementType string
+		if err := rows.Scan(&endorsementType, &stat.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan endorsement type stat: %w", err)
+		}
+		stat.Type = social.EndorsementType(endorsementType)
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
 }
 
+// GetTopHelpers ranks helpers by endorsements received since the given time.
+// Entries carry no DisplayName: names are resolved by the caller.
 func (r *EndorsementRepository) GetTopHelpers(ctx context.Context, limit int, since time.Time) ([]social.HelperRankingEntry, error) {
-	return nil, errors.New("not implemented")
+	query := `
+		SELECT to_student_id, AVG(rating)::FLOAT8, COUNT(*), COUNT(DISTINCT help_request_id)
+		FROM endorsements
+		WHERE created_at >= $1
+		GROUP BY to_student_id
+		ORDER BY AVG(rating) * COUNT(*) DESC, COUNT(*) DESC, to_student_id
+		LIMIT $2
+	`
+
+	rows, err := r.conn.Query(ctx, query, since, social.ListBounds.ClampLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top helpers: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []social.HelperRankingEntry
+	for rows.Next() {
+		var (
+			entry     social.HelperRankingEntry
+			studentID string
+			avgRating float64
+		)
+		if err := rows.Scan(&studentID, &avgRating, &entry.EndorsementCount, &entry.HelpCount); err != nil {
+			return nil, fmt.Errorf("failed to scan top helper: %w", err)
+		}
+		entry.StudentID = social.StudentID(studentID)
+		entry.AverageRating = social.Rating(avgRating)
+		entry.Rank = len(entries) + 1
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
 }
 
 func (r *EndorsementRepository) GetByIDs(ctx context.Context, ids []string) ([]*social.Endorsement, error) {
-	return nil, errors.New("not implemented")
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return r.list(ctx, "failed to get endorsements by ids", `WHERE id = ANY($1)`, ids)
+}
+
+// CreateBatch bulk-loads endorsements using COPY.
+func (r *EndorsementRepository) CreateBatch(ctx context.Context, endorsements []*social.Endorsement) error {
+	if len(endorsements) == 0 {
+		return nil
+	}
+
+	rows := make([][]interface{}, len(endorsements))
+	for i, e := range endorsements {
+		rows[i] = endorsementValues(e)
+	}
+
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		_, err := tx.CopyFrom(ctx,
+			pgx.Identifier{"endorsements"},
+			[]string{
+				"id", "from_student_id", "to_student_id", "help_request_id", "task_id",
+				"endorsement_type", "rating", "message", "is_public", "created_at",
+			},
+			pgx.CopyFromRows(rows),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to copy endorsements: %w", err)
+		}
+		return nil
+	})
+}
+
+// list returns the endorsements matching the query tail (WHERE, ORDER BY, ...).
+func (r *EndorsementRepository) list(ctx context.Context, failure, tail string, args ...interface{}) ([]*social.Endorsement, error) {
+	rows, err := r.conn.Query(ctx, `SELECT `+endorsementColumns+` FROM endorsements `+tail, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", failure, err)
+	}
+	defer rows.Close()
+
+	var endorsements []*social.Endorsement
+	for rows.Next() {
+		e, err := scanEndorsement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan endorsement: %w", err)
+		}
+		endorsements = append(endorsements, e)
+	}
+	return endorsements, rows.Err()
+}
+
+// page returns a page of the endorsements matching condition and the list
+// options, newest first. The condition uses args from $1 on.
+func (r *EndorsementRepository) page(ctx context.Context, failure, condition string, opts social.EndorsementListOptions, args ...interface{}) ([]*social.Endorsement, error) {
+	page := opts.Normalized()
+	types := make([]string, len(opts.Types))
+	for i, t := range opts.Types {
+		types[i] = string(t)
+	}
+
+	n := len(args)
+	args = append(args, int(math.Ceil(float64(opts.MinRating))), opts.PublicOnly, types, page.Limit, page.Offset)
+	tail := fmt.Sprintf(`
+		WHERE %s
+		  AND rating >= $%d
+		  AND (NOT $%d OR is_public)
+		  AND (cardinality($%d::text[]) = 0 OR endorsement_type = ANY($%d))
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, condition, n+1, n+2, n+3, n+3, n+4, n+5)
+
+	return r.list(ctx, failure, tail, args...)
+}
+
+// endorsementValues returns the column values of an endorsement in the
+// order of the INSERT and COPY column lists.
+func endorsementValues(e *social.Endorsement) []interface{} {
+	return []interface{}{
+		e.ID,
+		string(e.GiverID),
+		string(e.ReceiverID),
+		nullIfEmpty(e.HelpRequestID),
+		nullIfEmpty(string(e.TaskID)),
+		nullIfEmpty(string(e.Type)),
+		int(math.Round(float64(e.Rating))),
+		nullIfEmpty(e.Comment),
+		e.IsPublic,
+		e.CreatedAt,
+	}
+}
+
+// scanEndorsement scans a row of endorsementColumns.
+func scanEndorsement(row pgx.Row) (*social.Endorsement, error) {
+	var (
+		e                                 social.Endorsement
+		giverID, receiverID, taskID, kind string
+		helpRequestID, message            *string
+		rating                            int
+	)
+	err := row.Scan(&e.ID, &giverID, &receiverID, &helpRequestID, &taskID, &kind, &rating, &message, &e.IsPublic, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	e.GiverID = social.StudentID(giverID)
+	e.ReceiverID = social.StudentID(receiverID)
+	e.TaskID = social.TaskID(taskID)
+	e.Type = social.EndorsementType(kind)
+	e.Rating = social.Rating(rating)
+	if helpRequestID != nil {
+		e.HelpRequestID = *helpRequestID
+	}
+	if message != nil {
+		e.Comment = *message
+	}
+	return &e, nil
 }
 
 // -----------------------------------------------------------------------------
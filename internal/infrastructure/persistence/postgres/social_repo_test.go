@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+func TestHelpRequestSearchWhere(t *testing.T) {
+	after := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	hasDeadline := true
+
+	where, args := helpRequestSearchWhere(social.HelpRequestSearchCriteria{
+		TaskIDs:      []social.TaskID{"go-reloaded"},
+		Statuses:     []social.HelpRequestStatus{social.HelpRequestStatusOpen, social.HelpRequestStatusMatched},
+		CreatedAfter: &after,
+		HasDeadline:  &hasDeadline,
+	})
+
+	assert.Equal(t, "WHERE task_id = ANY($1) AND status = ANY($2) AND created_at > $3 AND expires_at IS NOT NULL", where)
+	assert.Equal(t, []interface{}{[]string{"go-reloaded"}, []string{"open", "matched"}, after}, args)
+
+	where, args = helpRequestSearchWhere(social.HelpRequestSearchCriteria{})
+	assert.Empty(t, where)
+	assert.Empty(t, args)
+}
+
+func TestHelpRequestOrderBy(t *testing.T) {
+	assert.Equal(t, "expires_at ASC NULLS LAST, id", helpRequestOrderBy(social.HelpRequestListOptions{SortBy: "expires_at"}))
+	assert.Equal(t, "created_at DESC NULLS LAST, id",
+		helpRequestOrderBy(social.HelpRequestListOptions{SortBy: "1; DROP TABLE help_requests", SortDesc: true}))
+}
+
+func TestConnectionTypes(t *testing.T) {
+	// Legacy "peer" rows are found by a helper filter and read back as helper
+	assert.Equal(t, []string{"helper", "peer", "mentor"},
+		connectionTypesToDB([]social.ConnectionType{social.ConnectionTypeHelper, social.ConnectionTypeMentor}))
+	assert.Equal(t, social.ConnectionTypeHelper, connectionTypeFromDB("peer"))
+	assert.Equal(t, "coworker", connectionTypeToDB(social.ConnectionTypeCoworker))
+	assert.Equal(t, "helper", connectionTypeToDB("unknown"))
+}
+
+// fakeRow is a pgx.Row returning fixed column values.
+type fakeRow []interface{}
+
+func (r fakeRow) Scan(dest ...interface{}) error {
+	if len(dest) != len(r) {
+		return fmt.Errorf("scan: %d destinations for %d columns", len(dest), len(r))
+	}
+	for i, value := range r {
+		target := reflect.ValueOf(dest[i]).Elem()
+		if value == nil {
+			target.Set(reflect.Zero(target.Type()))
+			continue
+		}
+		target.Set(reflect.ValueOf(value))
+	}
+	return nil
+}
+
+func helpRequestRow(matchedHelpers []byte) fakeRow {
+	created := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	expires := created.Add(24 * time.Hour)
+	return fakeRow{
+		"req-1", "dana", "go-reloaded", "go-reloaded", "", "open", "normal",
+		nil, created, created, &expires, nil,
+		nil, nil, nil, nil,
+		nil, nil, nil, matchedHelpers,
+	}
+}
+
+func TestScanHelpRequest_MatchedHelpers(t *testing.T) {
+	seen := time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)
+	helpers := []social.MatchedHelper{{
+		StudentID:    "aigerim",
+		DisplayName:  "Айгерим",
+		HelpRating:   4.5,
+		IsOnline:     true,
+		LastSeenAt:   seen,
+		MatchScore:   87,
+		MatchReasons: []string{"solved the task", "online now"},
+	}}
+
+	stored, err := matchedHelpersToDB(helpers)
+	require.NoError(t, err)
+
+	req, err := scanHelpRequest(helpRequestRow(stored))
+	require.NoError(t, err)
+	assert.Equal(t, helpers, req.MatchedHelpers)
+
+	// The column default and unreadable values read as no helpers
+	for _, column := range [][]byte{[]byte(`[]`), []byte(`{`), nil} {
+		req, err := scanHelpRequest(helpRequestRow(column))
+		require.NoError(t, err)
+		assert.NotNil(t, req.MatchedHelpers)
+		assert.Empty(t, req.MatchedHelpers)
+	}
+}
@@ -0,0 +1,453 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// STUDENT MERGE UNIT OF WORK
+// One transaction spanning the student and social tables, used to merge a
+// duplicate student account into the one that is kept.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// StudentMergeUnitOfWorkFactory implements command.StudentMergeUnitOfWorkFactory.
+type StudentMergeUnitOfWorkFactory struct {
+	conn *Connection
+}
+
+// NewStudentMergeUnitOfWorkFactory creates a new StudentMergeUnitOfWorkFactory.
+func NewStudentMergeUnitOfWorkFactory(conn *Connection) *StudentMergeUnitOfWorkFactory {
+	return &StudentMergeUnitOfWorkFactory{conn: conn}
+}
+
+// Begin starts a new merge transaction.
+func (f *StudentMergeUnitOfWorkFactory) Begin(ctx context.Context) (command.StudentMergeUnitOfWork, error) {
+	tx, err := f.conn.BeginTx(ctx, DefaultTxOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	return &studentMergeUnitOfWork{
+		tx:       tx,
+		progress: NewProgressRepository(f.conn),
+	}, nil
+}
+
+// studentMergeUnitOfWork implements command.StudentMergeUnitOfWork over a pgx.Tx.
+type studentMergeUnitOfWork struct {
+	tx pgx.Tx
+
+	// progress is only used for its row scanners.
+	progress *ProgressRepository
+}
+
+// Students returns the student merge repository bound to the transaction.
+func (u *studentMergeUnitOfWork) Students() student.MergeRepository {
+	return &studentMergeRepository{tx: u.tx, progress: u.progress}
+}
+
+// Social returns the social merge repository bound to the transaction.
+func (u *studentMergeUnitOfWork) Social() social.MergeRepository {
+	return &socialMergeRepository{tx: u.tx}
+}
+
+// Audit returns the audit log bound to the transaction.
+func (u *studentMergeUnitOfWork) Audit() shared.AuditLog {
+	return &txAuditLog{tx: u.tx}
+}
+
+// Commit commits the transaction.
+func (u *studentMergeUnitOfWork) Commit(ctx context.Context) error {
+	return u.tx.Commit(ctx)
+}
+
+// Rollback rolls the transaction back. A no-op after Commit.
+func (u *studentMergeUnitOfWork) Rollback(ctx context.Context) error {
+	return u.tx.Rollback(ctx)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Student tables
+// ─────────────────────────────────────────────────────────────────────────────
+
+// studentMergeRepository implements student.MergeRepository.
+type studentMergeRepository struct {
+	tx       pgx.Tx
+	progress *ProgressRepository
+}
+
+// GetAllDailyGrinds returns all daily progress rows of a student.
+func (r *studentMergeRepository) GetAllDailyGrinds(ctx context.Context, studentID string) ([]*student.DailyGrind, error) {
+	query := `
+		SELECT student_id, date, xp_start, xp_current, xp_gained, tasks_completed,
+			   sessions_count, total_session_minutes, first_activity_at, last_activity_at,
+			   rank_at_start, rank_current, rank_change, streak_day
+		FROM daily_grinds
+		WHERE student_id = $1
+		ORDER BY date
+	`
+
+	rows, err := r.tx.Query(ctx, query, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily grinds: %w", err)
+	}
+	defer rows.Close()
+
+	var grinds []*student.DailyGrind
+	for rows.Next() {
+		grind, err := r.progress.scanDailyGrindFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		grinds = append(grinds, grind)
+	}
+
+	return grinds, rows.Err()
+}
+
+// ReplaceDailyGrinds deletes the duplicate's rows and the kept student's rows
+// for the same days, then writes the merged rows.
+func (r *studentMergeRepository) ReplaceDailyGrinds(ctx context.Context, fromID string, grinds []*student.DailyGrind) error {
+	if _, err := r.tx.Exec(ctx, `DELETE FROM daily_grinds WHERE student_id = $1`, fromID); err != nil {
+		return fmt.Errorf("failed to delete daily grinds: %w", err)
+	}
+	if len(grinds) == 0 {
+		return nil
+	}
+
+	dates := make([]time.Time, len(grinds))
+	for i, grind := range grinds {
+		dates[i] = grind.Date
+	}
+	if _, err := r.tx.Exec(ctx,
+		`DELETE FROM daily_grinds WHERE student_id = $1 AND date = ANY($2)`,
+		grinds[0].StudentID, dates,
+	); err != nil {
+		return fmt.Errorf("failed to delete daily grinds: %w", err)
+	}
+
+	batch := &pgx.Batch{}
+	for _, grind := range grinds {
+		batch.Queue(upsertDailyGrindQuery, dailyGrindArgs(grind)...)
+	}
+	if err := execBatch(ctx, r.tx, batch); err != nil {
+		return fmt.Errorf("failed to save daily grinds: %w", err)
+	}
+
+	return nil
+}
+
+// GetStreak returns a student's streak, or nil if there is none.
+func (r *studentMergeRepository) GetStreak(ctx context.Context, studentID string) (*student.Streak, error) {
+	query := `
+		SELECT student_id, current_streak, best_streak, last_active_date, streak_start_date, milestone_reached
+		FROM streaks
+		WHERE student_id = $1
+	`
+
+	var streak student.Streak
+	var lastActive, streakStart *time.Time
+
+	err := r.tx.QueryRow(ctx, query, studentID).Scan(
+		&streak.StudentID,
+		&streak.CurrentStreak,
+		&streak.BestStreak,
+		&lastActive,
+		&streakStart,
+		&streak.MilestoneReached,
+	)
+	if IsNoRows(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get streak: %w", err)
+	}
+
+	if lastActive != nil {
+		streak.LastActiveDate = *lastActive
+	}
+	if streakStart != nil {
+		streak.StreakStartDate = *streakStart
+	}
+
+	return &streak, nil
+}
+
+// ReplaceStreak deletes the duplicate's streak and saves the merged one.
+func (r *studentMergeRepository) ReplaceStreak(ctx context.Context, fromID string, streak *student.Streak) error {
+	if _, err := r.tx.Exec(ctx, `DELETE FROM streaks WHERE student_id = ANY($1)`, []string{fromID, streak.StudentID}); err != nil {
+		return fmt.Errorf("failed to delete streaks: %w", err)
+	}
+	if _, err := r.tx.Exec(ctx, upsertStreakQuery, streakArgs(streak)...); err != nil {
+		return fmt.Errorf("failed to save streak: %w", err)
+	}
+	return nil
+}
+
+// MoveXPHistory re-points the duplicate's XP history, raw rows and monthly
+// rollups alike. A month rolled up for only one of the two students is
+// first rolled up for the other as well: raw rows of a rolled-up month are
+// skipped, so they would be lost after the move otherwise.
+func (r *studentMergeRepository) MoveXPHistory(ctx context.Context, fromID, intoID string) (int64, error) {
+	for _, pair := range [][2]string{{intoID, fromID}, {fromID, intoID}} {
+		if _, err := r.tx.Exec(ctx, rollupMatchingMonthsQuery, pair[0], pair[1]); err != nil {
+			return 0, fmt.Errorf("failed to roll up xp history before merge: %w", err)
+		}
+	}
+
+	tag, err := r.tx.Exec(ctx, `UPDATE xp_history SET student_id = $2 WHERE student_id = $1`, fromID, intoID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move xp history: %w", err)
+	}
+
+	_, err = r.tx.Exec(ctx, `
+		INSERT INTO xp_history_monthly (student_id, month, reason, delta, entries)
+		SELECT $2, month, reason, delta, entries
+		FROM xp_history_monthly
+		WHERE student_id = $1
+		ON CONFLICT (student_id, month, reason) DO UPDATE SET
+			delta = xp_history_monthly.delta + EXCLUDED.delta,
+			entries = xp_history_monthly.entries + EXCLUDED.entries
+	`, fromID, intoID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move xp rollups: %w", err)
+	}
+
+	if _, err := r.tx.Exec(ctx, `DELETE FROM xp_history_monthly WHERE student_id = $1`, fromID); err != nil {
+		return 0, fmt.Errorf("failed to delete moved xp rollups: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// rollupMatchingMonthsQuery rolls up the raw XP history of student $1 for
+// the months rolled up for student $2 but not for $1.
+const rollupMatchingMonthsQuery = `
+	INSERT INTO xp_history_monthly (student_id, month, reason, delta, entries)
+	SELECT h.student_id, ` + xpMonthExpr + `, h.reason, SUM(h.delta), COUNT(*)
+	FROM xp_history h
+	WHERE h.student_id = $1
+	  AND ` + xpMonthExpr + ` IN (SELECT month FROM xp_history_monthly WHERE student_id = $2)
+	  AND ` + xpMonthExpr + ` NOT IN (SELECT month FROM xp_history_monthly WHERE student_id = $1)
+	GROUP BY 1, 2, 3
+`
+
+// MoveAchievements moves achievements the kept student doesn't have yet
+// and deletes the rest.
+func (r *studentMergeRepository) MoveAchievements(ctx context.Context, fromID, intoID string) (int64, error) {
+	query := `
+		UPDATE achievements SET student_id = $2
+		WHERE student_id = $1
+		  AND achievement_type NOT IN (SELECT achievement_type FROM achievements WHERE student_id = $2)
+	`
+
+	tag, err := r.tx.Exec(ctx, query, fromID, intoID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move achievements: %w", err)
+	}
+	if _, err := r.tx.Exec(ctx, `DELETE FROM achievements WHERE student_id = $1`, fromID); err != nil {
+		return 0, fmt.Errorf("failed to delete achievements: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// SetTelegramID changes a student's Telegram ID.
+func (r *studentMergeRepository) SetTelegramID(ctx context.Context, studentID string, telegramID student.TelegramID) error {
+	tag, err := r.tx.Exec(ctx,
+		`UPDATE students SET telegram_id = $2, updated_at = NOW() WHERE id = $1`,
+		studentID, int64(telegramID),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set telegram id: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return student.ErrStudentNotFound
+	}
+	return nil
+}
+
+// MarkMerged soft-deletes the duplicate. Its Telegram ID is negated to free
+// the unique value while keeping it recoverable. Its Alem login is cleared,
+// so the next sync can backfill it on the kept student.
+func (r *studentMergeRepository) MarkMerged(ctx context.Context, studentID string) error {
+	query := `
+		UPDATE students
+		SET status = $2,
+			telegram_id = CASE WHEN telegram_id > 0 THEN -telegram_id ELSE telegram_id END,
+			alem_login = NULL,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	tag, err := r.tx.Exec(ctx, query, studentID, string(student.StatusLeft))
+	if err != nil {
+		return fmt.Errorf("failed to mark student merged: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return student.ErrStudentNotFound
+	}
+	return nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Social tables
+// ─────────────────────────────────────────────────────────────────────────────
+
+// socialMergeRepository implements social.MergeRepository.
+type socialMergeRepository struct {
+	tx pgx.Tx
+}
+
+// GetConnections returns all connections of a student in either direction.
+func (r *socialMergeRepository) GetConnections(ctx context.Context, studentID social.StudentID) ([]*social.Connection, error) {
+	query := `SELECT ` + connectionColumns + `
+		FROM connections
+		WHERE from_student_id = $1 OR to_student_id = $1
+	`
+
+	rows, err := r.tx.Query(ctx, query, string(studentID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connections: %w", err)
+	}
+	defer rows.Close()
+
+	return scanConnections(rows)
+}
+
+// ApplyConnectionPlan deletes dropped connections and re-points the rest.
+func (r *socialMergeRepository) ApplyConnectionPlan(ctx context.Context, plan social.ConnectionMergePlan) error {
+	dropped := plan.Dropped()
+	if len(dropped) == 0 && len(plan.Repoint) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, c := range dropped {
+		batch.Queue(`DELETE FROM connections WHERE id = $1`, c.ID)
+	}
+	for _, c := range plan.Repoint {
+		batch.Queue(
+			`UPDATE connections SET from_student_id = $2, to_student_id = $3 WHERE id = $1`,
+			c.ID, string(c.InitiatorID), string(c.ReceiverID),
+		)
+	}
+
+	if err := execBatch(ctx, r.tx, batch); err != nil {
+		return fmt.Errorf("failed to apply connection plan: %w", err)
+	}
+	return nil
+}
+
+// MoveHelpRequests re-points help requests. Requests the kept student would
+// have helped with themselves lose their helper.
+func (r *socialMergeRepository) MoveHelpRequests(ctx context.Context, fromID, intoID social.StudentID) (int64, error) {
+	requested, err := r.tx.Exec(ctx,
+		`UPDATE help_requests SET requester_id = $2 WHERE requester_id = $1`,
+		string(fromID), string(intoID),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move help requests: %w", err)
+	}
+
+	helped, err := r.tx.Exec(ctx,
+		`UPDATE help_requests SET helper_id = $2 WHERE helper_id = $1`,
+		string(fromID), string(intoID),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move helped requests: %w", err)
+	}
+
+	if _, err := r.tx.Exec(ctx,
+		`UPDATE help_requests SET helper_id = NULL WHERE requester_id = $1 AND helper_id = $1`,
+		string(intoID),
+	); err != nil {
+		return 0, fmt.Errorf("failed to clear self help: %w", err)
+	}
+
+	// Clusters the merged student was already pinged about stay deduplicated
+	if _, err := r.tx.Exec(ctx, `
+		INSERT INTO help_cluster_notifications (cluster_id, helper_id, notified_at)
+		SELECT cluster_id, $2, notified_at FROM help_cluster_notifications WHERE helper_id = $1
+		ON CONFLICT DO NOTHING
+	`, string(fromID), string(intoID)); err != nil {
+		return 0, fmt.Errorf("failed to move help cluster notifications: %w", err)
+	}
+
+	return requested.RowsAffected() + helped.RowsAffected(), nil
+}
+
+// MoveEndorsements re-points endorsements and recomputes the kept student's
+// help rating, which the insert trigger doesn't cover.
+func (r *socialMergeRepository) MoveEndorsements(ctx context.Context, fromID, intoID social.StudentID) (int64, int64, error) {
+	dropped, err := r.tx.Exec(ctx, `
+		DELETE FROM endorsements
+		WHERE (from_student_id = $1 AND to_student_id = $2)
+		   OR (from_student_id = $2 AND to_student_id = $1)
+	`, string(fromID), string(intoID))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to delete endorsements: %w", err)
+	}
+
+	given, err := r.tx.Exec(ctx,
+		`UPDATE endorsements SET from_student_id = $2 WHERE from_student_id = $1`,
+		string(fromID), string(intoID),
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to move given endorsements: %w", err)
+	}
+
+	received, err := r.tx.Exec(ctx,
+		`UPDATE endorsements SET to_student_id = $2 WHERE to_student_id = $1`,
+		string(fromID), string(intoID),
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to move received endorsements: %w", err)
+	}
+
+	if _, err := r.tx.Exec(ctx, `
+		UPDATE students
+		SET help_rating = (
+				SELECT COALESCE(AVG(rating)::DECIMAL(3,2), 0) FROM endorsements WHERE to_student_id = $1
+			),
+			help_count = (
+				SELECT COUNT(*) FROM endorsements WHERE to_student_id = $1
+			)
+		WHERE id = $1
+	`, string(intoID)); err != nil {
+		return 0, 0, fmt.Errorf("failed to recompute help rating: %w", err)
+	}
+
+	return given.RowsAffected() + received.RowsAffected(), dropped.RowsAffected(), nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Audit
+// ─────────────────────────────────────────────────────────────────────────────
+
+// txAuditLog implements shared.AuditLog within a transaction.
+type txAuditLog struct {
+	tx pgx.Tx
+}
+
+// Record stores an audit entry.
+func (a *txAuditLog) Record(ctx context.Context, entry shared.AuditEntry) error {
+	args, err := auditEntryArgs(entry)
+	if err != nil {
+		return err
+	}
+
+	if _, err := a.tx.Exec(ctx, insertAuditEntryQuery, args...); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
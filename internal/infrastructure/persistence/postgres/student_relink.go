@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// RelinkTelegramID moves a student to another Telegram account and records
+// the audit entry in the same transaction. The update only matches while the
+// student still has the old Telegram ID, so of two concurrent relinks only
+// one commits. The old ID is no longer stored anywhere and can be registered
+// again. Implements student.TelegramRelinker.
+func (r *StudentRepository) RelinkTelegramID(
+	ctx context.Context,
+	studentID string,
+	from, to student.TelegramID,
+	audit shared.AuditEntry,
+) error {
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE students
+			SET telegram_id = $3, updated_at = NOW()
+			WHERE id = $1 AND telegram_id = $2
+		`, studentID, int64(from), int64(to))
+		if err != nil {
+			if IsUniqueViolation(err) {
+				return student.ErrTelegramIDInUse
+			}
+			return fmt.Errorf("failed to relink telegram id: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return student.ErrRelinkConflict
+		}
+
+		return (&txAuditLog{tx: tx}).Record(ctx, audit)
+	})
+}
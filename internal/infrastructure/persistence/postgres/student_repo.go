@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
@@ -20,6 +22,9 @@ import (
 // StudentRepository implements student.Repository for PostgreSQL.
 type StudentRepository struct {
 	conn *Connection
+
+	// names is the display name cache kept up to date on update and delete (optional).
+	names student.DisplayNameCache
 }
 
 // NewStudentRepository creates a new StudentRepository.
@@ -27,44 +32,34 @@ func NewStudentRepository(conn *Connection) *StudentRepository {
 	return &StudentRepository{conn: conn}
 }
 
+// WithDisplayNameCache makes Update write the display name through to cache
+// and Delete evict it.
+func (r *StudentRepository) WithDisplayNameCache(cache student.DisplayNameCache) *StudentRepository {
+	r.names = cache
+	return r
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // CRUD Operations
 // ─────────────────────────────────────────────────────────────────────────────
 
+// insertStudentQuery inserts a single student row.
+const insertStudentQuery = `
+	INSERT INTO students (
+		id, telegram_id, email, password_hash, display_name, current_xp, cohort,
+		status, online_state, last_seen_at, last_synced_at, joined_at,
+		preferences, help_rating, help_count, created_at, updated_at, alem_login
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+`
+
 // Create creates a new student.
 func (r *StudentRepository) Create(ctx context.Context, s *student.Student) error {
-	query := `
-		INSERT INTO students (
-			id, telegram_id, email, password_hash, display_name, current_xp, cohort,
-			status, online_state, last_seen_at, last_synced_at, joined_at,
-			preferences, help_rating, help_count, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
-	`
-
-	prefsJSON, err := json.Marshal(preferencesToMap(s.Preferences))
+	args, err := studentInsertArgs(s)
 	if err != nil {
-		return fmt.Errorf("failed to marshal preferences: %w", err)
+		return err
 	}
 
-	_, err = r.conn.Exec(ctx, query,
-		s.ID,
-		int64(s.TelegramID),
-		s.Email,
-		s.PasswordHash,
-		s.DisplayName,
-		int(s.CurrentXP),
-		string(s.Cohort),
-		string(s.Status),
-		string(s.OnlineState),
-		s.LastSeenAt,
-		s.LastSyncedAt,
-		s.JoinedAt,
-		prefsJSON,
-		s.HelpRating,
-		s.HelpCount,
-		s.CreatedAt,
-		s.UpdatedAt,
-	)
+	_, err = r.conn.Exec(ctx, insertStudentQuery, args...)
 	if err != nil {
 		if IsUniqueViolation(err) {
 			return student.ErrStudentAlreadyExists
@@ -80,7 +75,8 @@ func (r *StudentRepository) GetByID(ctx context.Context, id string) (*student.St
 	query := `
 		SELECT id, telegram_id, email, password_hash, display_name, current_xp, cohort,
 			   status, online_state, last_seen_at, last_synced_at, joined_at,
-			   preferences, help_rating, help_count, created_at, updated_at
+			   preferences, help_rating, help_count, created_at, updated_at, muted_until,
+			   help_matching_disabled, alem_login
 		FROM students
 		WHERE id = $1
 	`
@@ -94,7 +90,8 @@ func (r *StudentRepository) GetByTelegramID(ctx context.Context, telegramID stud
 	query := `
 		SELECT id, telegram_id, email, password_hash, display_name, current_xp, cohort,
 			   status, online_state, last_seen_at, last_synced_at, joined_at,
-			   preferences, help_rating, help_count, created_at, updated_at
+			   preferences, help_rating, help_count, created_at, updated_at, muted_until,
+			   help_matching_disabled, alem_login
 		FROM students
 		WHERE telegram_id = $1
 	`
@@ -108,7 +105,8 @@ func (r *StudentRepository) GetByEmail(ctx context.Context, email string) (*stud
 	query := `
 		SELECT id, telegram_id, email, password_hash, display_name, current_xp, cohort,
 			   status, online_state, last_seen_at, last_synced_at, joined_at,
-			   preferences, help_rating, help_count, created_at, updated_at
+			   preferences, help_rating, help_count, created_at, updated_at, muted_until,
+			   help_matching_disabled, alem_login
 		FROM students
 		WHERE email = $1
 	`
@@ -117,6 +115,21 @@ func (r *StudentRepository) GetByEmail(ctx context.Context, email string) (*stud
 	return r.scanStudent(row)
 }
 
+// GetByAlemLogin returns a student by Alem login.
+func (r *StudentRepository) GetByAlemLogin(ctx context.Context, login string) (*student.Student, error) {
+	query := `
+		SELECT id, telegram_id, email, password_hash, display_name, current_xp, cohort,
+			   status, online_state, last_seen_at, last_synced_at, joined_at,
+			   preferences, help_rating, help_count, created_at, updated_at, muted_until,
+			   help_matching_disabled, alem_login
+		FROM students
+		WHERE alem_login = $1
+	`
+
+	row := r.conn.QueryRow(ctx, query, student.NormalizeAlemLogin(login))
+	return r.scanStudent(row)
+}
+
 // Update updates a student.
 func (r *StudentRepository) Update(ctx context.Context, s *student.Student) error {
 	query := `
@@ -134,11 +147,12 @@ func (r *StudentRepository) Update(ctx context.Context, s *student.Student) erro
 			preferences = $11,
 			help_rating = $12,
 			help_count = $13,
-			updated_at = $14
+			updated_at = $14,
+			alem_login = $16
 		WHERE id = $15
 	`
 
-	prefsJSON, err := json.Marshal(preferencesToMap(s.Preferences))
+	prefsJSON, err := student.EncodePreferences(s.Preferences)
 	if err != nil {
 		return fmt.Errorf("failed to marshal preferences: %w", err)
 	}
@@ -159,8 +173,12 @@ func (r *StudentRepository) Update(ctx context.Context, s *student.Student) erro
 		s.HelpCount,
 		time.Now().UTC(),
 		s.ID,
+		alemLoginToDB(s.AlemLogin),
 	)
 	if err != nil {
+		if IsUniqueViolation(err) {
+			return student.ErrStudentAlreadyExists
+		}
 		return fmt.Errorf("failed to update student: %w", err)
 	}
 
@@ -168,9 +186,99 @@ func (r *StudentRepository) Update(ctx context.Context, s *student.Student) erro
 		return student.ErrStudentNotFound
 	}
 
+	r.writeDisplayName(ctx, s.ID, s.DisplayName)
+
 	return nil
 }
 
+// SaveMutes stores the student's notification mutes. Only muted_until and
+// the "mutes" key of preferences are written, so a concurrent update of
+// other settings is not overwritten.
+func (r *StudentRepository) SaveMutes(ctx context.Context, s *student.Student) error {
+	query := `
+		UPDATE students
+		SET muted_until = $2,
+			preferences = CASE WHEN $3::jsonb IS NULL THEN preferences - 'mutes'
+				ELSE jsonb_set(preferences, '{mutes}', $3::jsonb) END,
+			updated_at = $4
+		WHERE id = $1
+	`
+
+	var mutesJSON []byte
+	if mutes := categoryMutesToMap(s.Preferences.CategoryMutes); mutes != nil {
+		var err error
+		if mutesJSON, err = json.Marshal(mutes); err != nil {
+			return fmt.Errorf("failed to marshal mutes: %w", err)
+		}
+	}
+
+	result, err := r.conn.Exec(ctx, query, s.ID, s.MutedUntil, mutesJSON, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save mutes: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return student.ErrStudentNotFound
+	}
+
+	return nil
+}
+
+// SetHelpMatchingDisabled excludes the student from helper matching or
+// brings them back.
+func (r *StudentRepository) SetHelpMatchingDisabled(ctx context.Context, studentID string, disabled bool) error {
+	query := `
+		UPDATE students
+		SET help_matching_disabled = $2, updated_at = $3
+		WHERE id = $1
+	`
+
+	result, err := r.conn.Exec(ctx, query, studentID, disabled, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to set help matching: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return student.ErrStudentNotFound
+	}
+
+	return nil
+}
+
+// ClaimDigest records that the daily digest of date (a local date) goes to
+// the student. Only the first claim of a date returns true.
+func (r *StudentRepository) ClaimDigest(ctx context.Context, studentID string, date time.Time) (bool, error) {
+	query := `
+		UPDATE students
+		SET last_digest_date = $2
+		WHERE id = $1 AND (last_digest_date IS NULL OR last_digest_date < $2)
+	`
+
+	result, err := r.conn.Exec(ctx, query, studentID, digestDate(date))
+	if err != nil {
+		return false, fmt.Errorf("failed to claim digest: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// ReleaseDigest undoes a claim of date after the digest failed to send.
+func (r *StudentRepository) ReleaseDigest(ctx context.Context, studentID string, date time.Time) error {
+	query := `
+		UPDATE students
+		SET last_digest_date = NULL
+		WHERE id = $1 AND last_digest_date = $2
+	`
+
+	if _, err := r.conn.Exec(ctx, query, studentID, digestDate(date)); err != nil {
+		return fmt.Errorf("failed to release digest: %w", err)
+	}
+	return nil
+}
+
+// digestDate keeps the calendar date of t, whatever its location.
+func digestDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
 // Delete performs a soft delete on a student (sets status to 'left').
 func (r *StudentRepository) Delete(ctx context.Context, id string) error {
 	query := `
@@ -192,29 +300,75 @@ func (r *StudentRepository) Delete(ctx context.Context, id string) error {
 		return student.ErrStudentNotFound
 	}
 
+	if r.names != nil {
+		_ = r.names.DeleteNames(ctx, id)
+	}
+
 	return nil
 }
 
+// writeDisplayName writes a student's current name through to the cache.
+// If the write fails the entry is evicted instead, so the next lookup
+// reloads it rather than serving the old name.
+func (r *StudentRepository) writeDisplayName(ctx context.Context, id, name string) {
+	if r.names == nil {
+		return
+	}
+	if err := r.names.SetNames(ctx, map[string]string{id: name}); err != nil {
+		_ = r.names.DeleteNames(ctx, id)
+	}
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // Bulk Operations
 // ─────────────────────────────────────────────────────────────────────────────
 
 // GetAll returns all students with pagination.
 func (r *StudentRepository) GetAll(ctx context.Context, opts student.ListOptions) ([]*student.Student, error) {
+	page := opts.Normalized()
 	query := r.buildListQuery(opts, "")
-	return r.queryStudents(ctx, query, opts.Limit, opts.Offset)
+	return r.queryStudents(ctx, query, page.Limit, page.Offset)
 }
 
 // GetByCohort returns students by cohort.
 func (r *StudentRepository) GetByCohort(ctx context.Context, cohort student.Cohort, opts student.ListOptions) ([]*student.Student, error) {
+	page := opts.Normalized()
 	query := r.buildListQuery(opts, "cohort = $3")
-	return r.queryStudentsWithArgs(ctx, query, opts.Limit, opts.Offset, string(cohort))
+	return r.queryStudentsWithArgs(ctx, query, page.Limit, page.Offset, string(cohort))
 }
 
 // GetByStatus returns students by status.
 func (r *StudentRepository) GetByStatus(ctx context.Context, status student.Status, opts student.ListOptions) ([]*student.Student, error) {
+	page := opts.Normalized()
 	query := r.buildListQuery(opts, "status = $3")
-	return r.queryStudentsWithArgs(ctx, query, opts.Limit, opts.Offset, string(status))
+	return r.queryStudentsWithArgs(ctx, query, page.Limit, page.Offset, string(status))
+}
+
+// CreateBatch inserts several students in a single transaction.
+func (r *StudentRepository) CreateBatch(ctx context.Context, students []*student.Student) error {
+	if len(students) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, s := range students {
+		args, err := studentInsertArgs(s)
+		if err != nil {
+			return err
+		}
+		batch.Queue(insertStudentQuery, args...)
+	}
+
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		err := execBatch(ctx, tx, batch)
+		if IsUniqueViolation(err) {
+			return student.ErrStudentAlreadyExists
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create students: %w", err)
+		}
+		return nil
+	})
 }
 
 // GetByIDs returns students by a list of IDs.
@@ -233,7 +387,8 @@ func (r *StudentRepository) GetByIDs(ctx context.Context, ids []string) ([]*stud
 	query := fmt.Sprintf(`
 		SELECT id, telegram_id, email, password_hash, display_name, current_xp, cohort,
 			   status, online_state, last_seen_at, last_synced_at, joined_at,
-			   preferences, help_rating, help_count, created_at, updated_at
+			   preferences, help_rating, help_count, created_at, updated_at, muted_until,
+			   help_matching_disabled, alem_login
 		FROM students
 		WHERE id IN (%s)
 	`, strings.Join(placeholders, ", "))
@@ -281,7 +436,8 @@ func (r *StudentRepository) Search(ctx context.Context, query string, opts stude
 	sqlQuery := `
 		SELECT id, telegram_id, email, password_hash, display_name, current_xp, cohort,
 			   status, online_state, last_seen_at, last_synced_at, joined_at,
-			   preferences, help_rating, help_count, created_at, updated_at
+			   preferences, help_rating, help_count, created_at, updated_at, muted_until,
+			   help_matching_disabled, alem_login
 		FROM students
 		WHERE (LOWER(email) LIKE $1 OR LOWER(display_name) LIKE $1)
 	`
@@ -293,7 +449,8 @@ func (r *StudentRepository) Search(ctx context.Context, query string, opts stude
 	sqlQuery += r.buildOrderBy(opts)
 	sqlQuery += " LIMIT $2 OFFSET $3"
 
-	rows, err := r.conn.Query(ctx, sqlQuery, searchPattern, opts.Limit, opts.Offset)
+	page := opts.Normalized()
+	rows, err := r.conn.Query(ctx, sqlQuery, searchPattern, page.Limit, page.Offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search students: %w", err)
 	}
@@ -309,7 +466,8 @@ func (r *StudentRepository) FindInactive(ctx context.Context, threshold time.Dur
 	query := `
 		SELECT id, telegram_id, email, password_hash, display_name, current_xp, cohort,
 			   status, online_state, last_seen_at, last_synced_at, joined_at,
-			   preferences, help_rating, help_count, created_at, updated_at
+			   preferences, help_rating, help_count, created_at, updated_at, muted_until,
+			   help_matching_disabled, alem_login
 		FROM students
 		WHERE last_seen_at < $1 AND status = 'active'
 		ORDER BY last_seen_at ASC
@@ -329,7 +487,8 @@ func (r *StudentRepository) FindOnline(ctx context.Context) ([]*student.Student,
 	query := `
 		SELECT id, telegram_id, email, password_hash, display_name, current_xp, cohort,
 			   status, online_state, last_seen_at, last_synced_at, joined_at,
-			   preferences, help_rating, help_count, created_at, updated_at
+			   preferences, help_rating, help_count, created_at, updated_at, muted_until,
+			   help_matching_disabled, alem_login
 		FROM students
 		WHERE online_state = 'online' AND status = 'active'
 		ORDER BY current_xp DESC
@@ -349,7 +508,8 @@ func (r *StudentRepository) FindByXPRange(ctx context.Context, minXP, maxXP stud
 	query := `
 		SELECT id, telegram_id, email, password_hash, display_name, current_xp, cohort,
 			   status, online_state, last_seen_at, last_synced_at, joined_at,
-			   preferences, help_rating, help_count, created_at, updated_at
+			   preferences, help_rating, help_count, created_at, updated_at, muted_until,
+			   help_matching_disabled, alem_login
 		FROM students
 		WHERE current_xp >= $1 AND current_xp <= $2
 		ORDER BY current_xp DESC
@@ -364,6 +524,34 @@ func (r *StudentRepository) FindByXPRange(ctx context.Context, minXP, maxXP stud
 	return r.scanStudents(rows)
 }
 
+// FindHelpersInCohort finds up to limit students of the cohort with at least
+// minXP who can be matched as helpers, highest XP first. The filter mirrors
+// Student.IsDiscoverableHelper so hidden students do not use up the limit.
+func (r *StudentRepository) FindHelpersInCohort(ctx context.Context, cohort student.Cohort, minXP student.XP, limit int) ([]*student.Student, error) {
+	query := `
+		SELECT id, telegram_id, email, password_hash, display_name, current_xp, cohort,
+			   status, online_state, last_seen_at, last_synced_at, joined_at,
+			   preferences, help_rating, help_count, created_at, updated_at, muted_until,
+			   help_matching_disabled, alem_login
+		FROM students
+		WHERE cohort = $1 AND current_xp >= $2
+		  AND status IN ('active', 'inactive')
+		  AND NOT help_matching_disabled
+		  AND COALESCE(preferences->>'help_requests' = 'true', false)
+		  AND NOT COALESCE(preferences->>'hide_from_helper_search' = 'true', false)
+		ORDER BY current_xp DESC, id
+		LIMIT $3
+	`
+
+	rows, err := r.conn.Query(ctx, query, string(cohort), int(minXP), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find helpers in cohort: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanStudents(rows)
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // Existence Checks
 // ─────────────────────────────────────────────────────────────────────────────
@@ -481,6 +669,56 @@ func (r *ProgressRepository) SaveXPChangeForStudent(ctx context.Context, student
 	return nil
 }
 
+// HasXPChangeReason checks whether the student's XP history has an entry with the given reason.
+func (r *ProgressRepository) HasXPChangeReason(ctx context.Context, studentID string, reason string) (bool, error) {
+	var exists bool
+	err := r.conn.QueryRow(ctx,
+		hasXPChangeReasonQuery,
+		studentID,
+		reason,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check xp history: %w", err)
+	}
+	return exists, nil
+}
+
+// SaveXPChangesBatch bulk-loads XP changes using COPY.
+func (r *ProgressRepository) SaveXPChangesBatch(ctx context.Context, changes []student.StudentXPChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	rows := make([][]interface{}, len(changes))
+	for i, c := range changes {
+		var taskID *string
+		if c.Entry.TaskID != "" {
+			taskID = &changes[i].Entry.TaskID
+		}
+		rows[i] = []interface{}{
+			c.StudentID,
+			int(c.Entry.OldXP),
+			int(c.Entry.NewXP),
+			int(c.Entry.Delta),
+			c.Entry.Reason,
+			taskID,
+			c.Entry.Timestamp,
+		}
+	}
+
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		_, err := tx.CopyFrom(ctx,
+			pgx.Identifier{"xp_history"},
+			[]string{"student_id", "old_xp", "new_xp", "delta", "reason", "task_id", "created_at"},
+			pgx.CopyFromRows(rows),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to copy xp history: %w", err)
+		}
+		return nil
+	})
+}
+
 // GetXPHistory returns XP history for a student within a time range.
 func (r *ProgressRepository) GetXPHistory(ctx context.Context, studentID string, from, to time.Time) ([]student.XPHistoryEntry, error) {
 	query := `
@@ -518,55 +756,73 @@ func (r *ProgressRepository) GetRecentXPChanges(ctx context.Context, studentID s
 	return r.scanXPHistoryEntries(rows)
 }
 
+// GetXPChangeTotal returns the XP change within [from, to). Rolled-up months
+// count as a whole when they start inside the range; raw rows of those
+// months are skipped, so the result doesn't change once they are pruned.
+func (r *ProgressRepository) GetXPChangeTotal(ctx context.Context, studentID string, from, to time.Time) (student.XP, error) {
+	query := `
+		SELECT
+			COALESCE((
+				SELECT SUM(h.delta) FROM xp_history h
+				WHERE h.student_id = $1 AND h.created_at >= $2 AND h.created_at < $3
+				  AND NOT EXISTS (
+					SELECT 1 FROM xp_history_monthly m
+					WHERE m.student_id = h.student_id AND m.month = ` + xpMonthExpr + `
+				  )
+			), 0)
+			+ COALESCE((
+				SELECT SUM(delta) FROM xp_history_monthly
+				WHERE student_id = $1
+				  AND month >= ($2::timestamptz AT TIME ZONE 'UTC')
+				  AND month < ($3::timestamptz AT TIME ZONE 'UTC')
+			), 0)
+	`
+
+	var total int64
+	if err := r.conn.QueryRow(ctx, query, studentID, from, to).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to get xp change total: %w", err)
+	}
+	return student.XP(total), nil
+}
+
+// xpMonthExpr is the UTC month of the xp_history row aliased h, matching
+// xp_history_monthly.month.
+const xpMonthExpr = `date_trunc('month', h.created_at AT TIME ZONE 'UTC')::date`
+
+// hasXPChangeReasonQuery also looks at rollups, so one-off bonuses are not
+// granted again after their history row is pruned.
+const hasXPChangeReasonQuery = `
+	SELECT EXISTS(SELECT 1 FROM xp_history WHERE student_id = $1 AND reason = $2)
+	    OR EXISTS(SELECT 1 FROM xp_history_monthly WHERE student_id = $1 AND reason = $2)
+`
+
 // ─────────────────────────────────────────────────────────────────────────────
 // Daily Grind
 // ─────────────────────────────────────────────────────────────────────────────
 
+// upsertDailyGrindQuery inserts or updates a daily_grinds row.
+const upsertDailyGrindQuery = `
+	INSERT INTO daily_grinds (
+		student_id, date, xp_start, xp_current, xp_gained, tasks_completed,
+		sessions_count, total_session_minutes, first_activity_at, last_activity_at,
+		rank_at_start, rank_current, rank_change, streak_day
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	ON CONFLICT(student_id, date) DO UPDATE SET
+		xp_current = EXCLUDED.xp_current,
+		xp_gained = EXCLUDED.xp_gained,
+		tasks_completed = EXCLUDED.tasks_completed,
+		sessions_count = EXCLUDED.sessions_count,
+		total_session_minutes = EXCLUDED.total_session_minutes,
+		first_activity_at = COALESCE(daily_grinds.first_activity_at, EXCLUDED.first_activity_at),
+		last_activity_at = EXCLUDED.last_activity_at,
+		rank_current = EXCLUDED.rank_current,
+		rank_change = EXCLUDED.rank_change,
+		streak_day = EXCLUDED.streak_day
+`
+
 // SaveDailyGrind saves or updates daily progress.
 func (r *ProgressRepository) SaveDailyGrind(ctx context.Context, grind *student.DailyGrind) error {
-	query := `
-		INSERT INTO daily_grinds (
-			student_id, date, xp_start, xp_current, xp_gained, tasks_completed,
-			sessions_count, total_session_minutes, first_activity_at, last_activity_at,
-			rank_at_start, rank_current, rank_change, streak_day
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-		ON CONFLICT(student_id, date) DO UPDATE SET
-			xp_current = EXCLUDED.xp_current,
-			xp_gained = EXCLUDED.xp_gained,
-			tasks_completed = EXCLUDED.tasks_completed,
-			sessions_count = EXCLUDED.sessions_count,
-			total_session_minutes = EXCLUDED.total_session_minutes,
-			first_activity_at = COALESCE(daily_grinds.first_activity_at, EXCLUDED.first_activity_at),
-			last_activity_at = EXCLUDED.last_activity_at,
-			rank_current = EXCLUDED.rank_current,
-			rank_change = EXCLUDED.rank_change,
-			streak_day = EXCLUDED.streak_day
-	`
-
-	var firstActivity, lastActivity *time.Time
-	if !grind.FirstActivityAt.IsZero() {
-		firstActivity = &grind.FirstActivityAt
-	}
-	if !grind.LastActivityAt.IsZero() {
-		lastActivity = &grind.LastActivityAt
-	}
-
-	_, err := r.conn.Exec(ctx, query,
-		grind.StudentID,
-		grind.Date,
-		int(grind.XPStart),
-		int(grind.XPCurrent),
-		int(grind.XPGained),
-		grind.TasksCompleted,
-		grind.SessionsCount,
-		grind.TotalSessionMinutes,
-		firstActivity,
-		lastActivity,
-		grind.RankAtStart,
-		grind.RankCurrent,
-		grind.RankChange,
-		grind.StreakDay,
-	)
+	_, err := r.conn.Exec(ctx, upsertDailyGrindQuery, dailyGrindArgs(grind)...)
 	if err != nil {
 		return fmt.Errorf("failed to save daily grind: %w", err)
 	}
@@ -574,6 +830,25 @@ func (r *ProgressRepository) SaveDailyGrind(ctx context.Context, grind *student.
 	return nil
 }
 
+// SaveDailyGrindsBatch saves or updates several daily progress rows in one transaction.
+func (r *ProgressRepository) SaveDailyGrindsBatch(ctx context.Context, grinds []*student.DailyGrind) error {
+	if len(grinds) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, grind := range grinds {
+		batch.Queue(upsertDailyGrindQuery, dailyGrindArgs(grind)...)
+	}
+
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		if err := execBatch(ctx, tx, batch); err != nil {
+			return fmt.Errorf("failed to save daily grinds: %w", err)
+		}
+		return nil
+	})
+}
+
 // GetDailyGrind returns daily progress for a specific date.
 func (r *ProgressRepository) GetDailyGrind(ctx context.Context, studentID string, date time.Time) (*student.DailyGrind, error) {
 	dateOnly := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
@@ -620,42 +895,335 @@ func (r *ProgressRepository) GetDailyGrindHistory(ctx context.Context, studentID
 	return grinds, rows.Err()
 }
 
-// GetTodayDailyGrind returns today's progress.
+// GetTodayDailyGrind returns today's progress. "Today" is the date in the
+// student's own timezone.
 func (r *ProgressRepository) GetTodayDailyGrind(ctx context.Context, studentID string) (*student.DailyGrind, error) {
-	return r.GetDailyGrind(ctx, studentID, time.Now().UTC())
+	var data []byte
+	if err := r.conn.QueryRow(ctx, `SELECT preferences FROM students WHERE id = $1`, studentID).Scan(&data); err != nil {
+		if IsNoRows(err) {
+			return nil, student.ErrStudentNotFound
+		}
+		return nil, fmt.Errorf("failed to get student timezone: %w", err)
+	}
+	loc := decodeStoredPreferences(studentID, data).Location()
+
+	return r.GetDailyGrind(ctx, studentID, student.LocalDate(time.Now(), loc))
 }
 
-// ─────────────────────────────────────────────────────────────────────────────
-// Streaks
-// ─────────────────────────────────────────────────────────────────────────────
+// UpsertDailyGrindDelta atomically increments daily progress for a specific date.
+// A missing row is created with xp_start derived from the student's current XP.
+func (r *ProgressRepository) UpsertDailyGrindDelta(ctx context.Context, studentID string, date time.Time, xpDelta student.XP, tasksDelta int) error {
+	date = date.UTC()
+	dateOnly := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
 
-// SaveStreak saves or updates a streak.
-func (r *ProgressRepository) SaveStreak(ctx context.Context, streak *student.Streak) error {
 	query := `
-		INSERT INTO streaks (student_id, current_streak, best_streak, last_active_date, streak_start_date)
-		VALUES ($1, $2, $3, $4, $5)
-		ON CONFLICT(student_id) DO UPDATE SET
-			current_streak = EXCLUDED.current_streak,
-			best_streak = GREATEST(streaks.best_streak, EXCLUDED.best_streak),
-			last_active_date = EXCLUDED.last_active_date,
-			streak_start_date = EXCLUDED.streak_start_date
+		INSERT INTO daily_grinds (
+			student_id, date, xp_start, xp_current, xp_gained, tasks_completed,
+			first_activity_at, last_activity_at
+		)
+		SELECT s.id, $2, GREATEST(s.current_xp - $3, 0), s.current_xp, $3, $4, NOW(), NOW()
+		FROM students s
+		WHERE s.id = $1
+		ON CONFLICT(student_id, date) DO UPDATE SET
+			xp_current = daily_grinds.xp_current + EXCLUDED.xp_gained,
+			xp_gained = daily_grinds.xp_gained + EXCLUDED.xp_gained,
+			tasks_completed = daily_grinds.tasks_completed + EXCLUDED.tasks_completed,
+			first_activity_at = COALESCE(daily_grinds.first_activity_at, EXCLUDED.first_activity_at),
+			last_activity_at = EXCLUDED.last_activity_at
 	`
 
-	var lastActive, streakStart *time.Time
-	if !streak.LastActiveDate.IsZero() {
-		lastActive = &streak.LastActiveDate
+	_, err := r.conn.Exec(ctx, query, studentID, dateOnly, int(xpDelta), tasksDelta)
+	if err != nil {
+		return fmt.Errorf("failed to upsert daily grind delta: %w", err)
 	}
-	if !streak.StreakStartDate.IsZero() {
-		streakStart = &streak.StreakStartDate
+
+	return nil
+}
+
+// RecordDailySession increments the session counter of the daily grind for the
+// day of at, creating the row when needed.
+func (r *ProgressRepository) RecordDailySession(ctx context.Context, studentID string, at time.Time) error {
+	at = at.UTC()
+	dateOnly := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+
+	query := `
+		INSERT INTO daily_grinds (
+			student_id, date, xp_start, xp_current, sessions_count,
+			first_activity_at, last_activity_at
+		)
+		SELECT s.id, $2, s.current_xp, s.current_xp, 1, $3, $3
+		FROM students s
+		WHERE s.id = $1
+		ON CONFLICT(student_id, date) DO UPDATE SET
+			sessions_count = daily_grinds.sessions_count + 1,
+			first_activity_at = COALESCE(daily_grinds.first_activity_at, EXCLUDED.first_activity_at),
+			last_activity_at = GREATEST(daily_grinds.last_activity_at, EXCLUDED.last_activity_at)
+	`
+
+	_, err := r.conn.Exec(ctx, query, studentID, dateOnly, at)
+	if err != nil {
+		return fmt.Errorf("failed to record daily session: %w", err)
 	}
 
-	_, err := r.conn.Exec(ctx, query,
-		streak.StudentID,
-		streak.CurrentStreak,
-		streak.BestStreak,
-		lastActive,
-		streakStart,
+	return nil
+}
+
+// RecordDailySessionMinutes counts a session of the given length in the
+// daily progress of at's date, creating the row if needed.
+func (r *ProgressRepository) RecordDailySessionMinutes(ctx context.Context, studentID string, at time.Time, minutes int) error {
+	at = at.UTC()
+	dateOnly := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+
+	query := `
+		INSERT INTO daily_grinds (
+			student_id, date, xp_start, xp_current, sessions_count,
+			total_session_minutes, first_activity_at, last_activity_at
+		)
+		SELECT s.id, $2, s.current_xp, s.current_xp, 1, $4, $3, $3
+		FROM students s
+		WHERE s.id = $1
+		ON CONFLICT(student_id, date) DO UPDATE SET
+			sessions_count = daily_grinds.sessions_count + 1,
+			total_session_minutes = daily_grinds.total_session_minutes + EXCLUDED.total_session_minutes,
+			first_activity_at = COALESCE(daily_grinds.first_activity_at, EXCLUDED.first_activity_at),
+			last_activity_at = GREATEST(daily_grinds.last_activity_at, EXCLUDED.last_activity_at)
+	`
+
+	_, err := r.conn.Exec(ctx, query, studentID, dateOnly, at, minutes)
+	if err != nil {
+		return fmt.Errorf("failed to record daily session minutes: %w", err)
+	}
+
+	return nil
+}
+
+// GetPlateauCandidates returns active students who logged sessions within
+// [from, to) without gaining any XP in that range.
+func (r *ProgressRepository) GetPlateauCandidates(ctx context.Context, from, to time.Time) ([]string, error) {
+	query := `
+		SELECT dg.student_id
+		FROM daily_grinds dg
+		JOIN students s ON s.id = dg.student_id
+		WHERE dg.date >= $1 AND dg.date < $2
+		  AND s.status = 'active'
+		GROUP BY dg.student_id
+		HAVING SUM(dg.sessions_count) > 0 AND SUM(dg.xp_gained) = 0
+	`
+
+	rows, err := r.conn.Query(ctx, query, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plateau candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan plateau candidate: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// GetXPGainedBetween sums daily XP gains of a student for dates in [from, to).
+func (r *ProgressRepository) GetXPGainedBetween(ctx context.Context, studentID string, from, to time.Time) (student.XP, error) {
+	query := `
+		SELECT COALESCE(SUM(xp_gained), 0)
+		FROM daily_grinds
+		WHERE student_id = $1 AND date >= $2 AND date < $3
+	`
+
+	var total int
+	if err := r.conn.QueryRow(ctx, query, studentID, from.UTC(), to.UTC()).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to sum xp gained: %w", err)
+	}
+
+	return student.XP(total), nil
+}
+
+// SumXPGainedBetween sums daily XP gains of every student that gained XP on
+// dates in [from, to).
+func (r *ProgressRepository) SumXPGainedBetween(ctx context.Context, from, to time.Time) (map[string]student.XP, error) {
+	query := `
+		SELECT student_id, SUM(xp_gained)
+		FROM daily_grinds
+		WHERE date >= $1 AND date < $2
+		GROUP BY student_id
+		HAVING SUM(xp_gained) > 0
+	`
+
+	rows, err := r.conn.Query(ctx, query, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum xp gained: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make(map[string]student.XP)
+	for rows.Next() {
+		var (
+			id    string
+			total int
+		)
+		if err := rows.Scan(&id, &total); err != nil {
+			return nil, fmt.Errorf("failed to scan xp gained: %w", err)
+		}
+		totals[id] = student.XP(total)
+	}
+
+	return totals, rows.Err()
+}
+
+// GetCohortXPSeries sums daily XP gains of a cohort per day or ISO week for
+// dates in [from, to], counting distinct active students per bucket. Only
+// buckets with data are returned; see student.FillXPSeries.
+func (r *ProgressRepository) GetCohortXPSeries(ctx context.Context, cohort student.Cohort, from, to time.Time, bucket student.XPSeriesBucket) ([]student.XPSeriesPoint, error) {
+	if !bucket.IsValid() {
+		return nil, fmt.Errorf("unknown series bucket %q", bucket)
+	}
+
+	// daily_grinds.date is already the Almaty calendar date, so buckets are
+	// truncated without any time zone conversion.
+	query := `
+		SELECT
+			date_trunc($4::text, dg.date::timestamp)::date AS bucket,
+			COALESCE(SUM(dg.xp_gained), 0),
+			COUNT(DISTINCT dg.student_id) FILTER (
+				WHERE dg.xp_gained > 0 OR dg.tasks_completed > 0 OR dg.sessions_count > 0
+			)
+		FROM daily_grinds dg
+		JOIN students s ON s.id = dg.student_id
+		WHERE s.cohort = $1 AND dg.date >= $2::date AND dg.date <= $3::date
+		GROUP BY bucket
+		ORDER BY bucket
+	`
+
+	rows, err := r.conn.Query(ctx, query,
+		string(cohort),
+		from.Format("2006-01-02"),
+		to.Format("2006-01-02"),
+		string(bucket),
 	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cohort xp series: %w", err)
+	}
+	defer rows.Close()
+
+	var points []student.XPSeriesPoint
+	for rows.Next() {
+		var (
+			date   time.Time
+			total  int64
+			active int
+		)
+		if err := rows.Scan(&date, &total, &active); err != nil {
+			return nil, fmt.Errorf("failed to scan cohort xp series: %w", err)
+		}
+		points = append(points, student.XPSeriesPoint{
+			Date:           student.SeriesDate(date),
+			TotalXP:        student.XP(total),
+			ActiveStudents: active,
+		})
+	}
+
+	return points, rows.Err()
+}
+
+// StartDailyRanks records the rank at the start of the day for every student
+// in entries. Rows are created from the student's current XP when missing;
+// an already recorded rank_at_start is kept, so a repeated call is harmless.
+func (r *ProgressRepository) StartDailyRanks(ctx context.Context, date time.Time, entries []student.StudentRank) error {
+	query := `
+		INSERT INTO daily_grinds (student_id, date, xp_start, xp_current, rank_at_start, rank_current)
+		SELECT t.student_id, $1, s.current_xp, s.current_xp, t.rank, t.rank
+		FROM tmp_daily_ranks t
+		JOIN students s ON s.id = t.student_id
+		ON CONFLICT(student_id, date) DO UPDATE SET
+			rank_at_start = CASE WHEN daily_grinds.rank_at_start > 0
+				THEN daily_grinds.rank_at_start ELSE EXCLUDED.rank_at_start END,
+			rank_current = EXCLUDED.rank_current,
+			rank_change = CASE WHEN daily_grinds.rank_at_start > 0
+				THEN daily_grinds.rank_at_start ELSE EXCLUDED.rank_at_start END - EXCLUDED.rank_current
+	`
+
+	return r.withDailyRanks(ctx, "start daily ranks", query, date, entries)
+}
+
+// BulkUpdateTodayRanks updates rank_current and rank_change of existing rows
+// with a single UPDATE ... FROM a temp table. Rows without a start rank
+// (students who first appeared mid-day) start from their current rank.
+func (r *ProgressRepository) BulkUpdateTodayRanks(ctx context.Context, date time.Time, entries []student.StudentRank) error {
+	query := `
+		UPDATE daily_grinds dg SET
+			rank_at_start = CASE WHEN dg.rank_at_start > 0 THEN dg.rank_at_start ELSE t.rank END,
+			rank_current = t.rank,
+			rank_change = CASE WHEN dg.rank_at_start > 0 THEN dg.rank_at_start ELSE t.rank END - t.rank
+		FROM tmp_daily_ranks t
+		WHERE dg.student_id = t.student_id AND dg.date = $1
+	`
+
+	return r.withDailyRanks(ctx, "update daily ranks", query, date, entries)
+}
+
+// withDailyRanks loads entries into the tmp_daily_ranks temp table with COPY
+// and runs query (with the date as $1) in the same transaction.
+func (r *ProgressRepository) withDailyRanks(ctx context.Context, what, query string, date time.Time, entries []student.StudentRank) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	date = date.UTC()
+	dateOnly := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	rows := make([][]interface{}, len(entries))
+	for i, e := range entries {
+		rows[i] = []interface{}{e.StudentID, e.Rank}
+	}
+
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `
+			CREATE TEMP TABLE tmp_daily_ranks (student_id UUID PRIMARY KEY, rank INTEGER NOT NULL)
+			ON COMMIT DROP
+		`); err != nil {
+			return fmt.Errorf("failed to %s: create temp table: %w", what, err)
+		}
+
+		if _, err := tx.CopyFrom(ctx,
+			pgx.Identifier{"tmp_daily_ranks"},
+			[]string{"student_id", "rank"},
+			pgx.CopyFromRows(rows),
+		); err != nil {
+			return fmt.Errorf("failed to %s: copy ranks: %w", what, err)
+		}
+
+		if _, err := tx.Exec(ctx, query, dateOnly); err != nil {
+			return fmt.Errorf("failed to %s: %w", what, err)
+		}
+		return nil
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Streaks
+// ─────────────────────────────────────────────────────────────────────────────
+
+// upsertStreakQuery inserts or updates a streaks row.
+const upsertStreakQuery = `
+	INSERT INTO streaks (student_id, current_streak, best_streak, last_active_date, streak_start_date, milestone_reached)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT(student_id) DO UPDATE SET
+		current_streak = EXCLUDED.current_streak,
+		best_streak = GREATEST(streaks.best_streak, EXCLUDED.best_streak),
+		last_active_date = EXCLUDED.last_active_date,
+		streak_start_date = EXCLUDED.streak_start_date,
+		milestone_reached = EXCLUDED.milestone_reached
+`
+
+// SaveStreak saves or updates a streak.
+func (r *ProgressRepository) SaveStreak(ctx context.Context, streak *student.Streak) error {
+	_, err := r.conn.Exec(ctx, upsertStreakQuery, streakArgs(streak)...)
 	if err != nil {
 		return fmt.Errorf("failed to save streak: %w", err)
 	}
@@ -663,10 +1231,29 @@ func (r *ProgressRepository) SaveStreak(ctx context.Context, streak *student.Str
 	return nil
 }
 
+// SaveStreaksBatch saves or updates several streaks in one transaction.
+func (r *ProgressRepository) SaveStreaksBatch(ctx context.Context, streaks []*student.Streak) error {
+	if len(streaks) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, streak := range streaks {
+		batch.Queue(upsertStreakQuery, streakArgs(streak)...)
+	}
+
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		if err := execBatch(ctx, tx, batch); err != nil {
+			return fmt.Errorf("failed to save streaks: %w", err)
+		}
+		return nil
+	})
+}
+
 // GetStreak returns the current streak for a student.
 func (r *ProgressRepository) GetStreak(ctx context.Context, studentID string) (*student.Streak, error) {
 	query := `
-		SELECT student_id, current_streak, best_streak, last_active_date, streak_start_date
+		SELECT student_id, current_streak, best_streak, last_active_date, streak_start_date, milestone_reached
 		FROM streaks
 		WHERE student_id = $1
 	`
@@ -682,6 +1269,7 @@ func (r *ProgressRepository) GetStreak(ctx context.Context, studentID string) (*
 		&streak.BestStreak,
 		&lastActive,
 		&streakStart,
+		&streak.MilestoneReached,
 	)
 
 	if IsNoRows(err) {
@@ -704,7 +1292,7 @@ func (r *ProgressRepository) GetStreak(ctx context.Context, studentID string) (*
 // GetTopStreaks returns top students by current streak.
 func (r *ProgressRepository) GetTopStreaks(ctx context.Context, limit int) ([]*student.Streak, error) {
 	query := `
-		SELECT student_id, current_streak, best_streak, last_active_date, streak_start_date
+		SELECT student_id, current_streak, best_streak, last_active_date, streak_start_date, milestone_reached
 		FROM streaks
 		WHERE current_streak > 0
 		ORDER BY current_streak DESC
@@ -728,6 +1316,7 @@ func (r *ProgressRepository) GetTopStreaks(ctx context.Context, limit int) ([]*s
 			&streak.BestStreak,
 			&lastActive,
 			&streakStart,
+			&streak.MilestoneReached,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan streak: %w", err)
@@ -758,13 +1347,9 @@ func (r *ProgressRepository) SaveAchievement(ctx context.Context, studentID stri
 		ON CONFLICT(student_id, achievement_type) DO NOTHING
 	`
 
-	var metadataJSON []byte
-	var err error
-	if achievement.Metadata != nil {
-		metadataJSON, err = json.Marshal(achievement.Metadata)
-		if err != nil {
-			return fmt.Errorf("failed to marshal achievement metadata: %w", err)
-		}
+	metadataJSON, err := achievementMetadataJSON(achievement)
+	if err != nil {
+		return err
 	}
 
 	_, err = r.conn.Exec(ctx, query,
@@ -780,6 +1365,97 @@ func (r *ProgressRepository) SaveAchievement(ctx context.Context, studentID stri
 	return nil
 }
 
+// GrantAchievementWithBonus saves an achievement, its XP history entry and
+// the XP increment in one transaction. The student row is locked first, so
+// concurrent grants of the same bonus award it once.
+func (r *ProgressRepository) GrantAchievementWithBonus(
+	ctx context.Context,
+	studentID string,
+	achievement student.Achievement,
+	bonus student.XP,
+	reason string,
+) (bool, student.XP, error) {
+	metadataJSON, err := achievementMetadataJSON(achievement)
+	if err != nil {
+		return false, 0, err
+	}
+
+	var (
+		awarded   bool
+		currentXP int
+	)
+	err = r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `SELECT current_xp FROM students WHERE id = $1 FOR UPDATE`, studentID).Scan(&currentXP)
+		if err != nil {
+			if IsNoRows(err) {
+				return student.ErrStudentNotFound
+			}
+			return fmt.Errorf("failed to lock student: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO achievements (student_id, achievement_type, unlocked_at, metadata)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT(student_id, achievement_type) DO NOTHING
+		`, studentID, string(achievement.Type), achievement.UnlockedAt, metadataJSON)
+		if err != nil {
+			return fmt.Errorf("failed to save achievement: %w", err)
+		}
+
+		if bonus <= 0 {
+			return nil
+		}
+
+		var exists bool
+		err = tx.QueryRow(ctx,
+			hasXPChangeReasonQuery,
+			studentID, reason,
+		).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to check xp history: %w", err)
+		}
+		if exists {
+			return nil
+		}
+
+		err = tx.QueryRow(ctx, `
+			UPDATE students SET current_xp = current_xp + $2, updated_at = NOW()
+			WHERE id = $1
+			RETURNING current_xp
+		`, studentID, int(bonus)).Scan(&currentXP)
+		if err != nil {
+			return fmt.Errorf("failed to add xp bonus: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO xp_history (student_id, old_xp, new_xp, delta, reason, created_at)
+			VALUES ($1, $2, $3, $4, $5, NOW())
+		`, studentID, currentXP-int(bonus), currentXP, int(bonus), reason)
+		if err != nil {
+			return fmt.Errorf("failed to save xp change: %w", err)
+		}
+
+		awarded = true
+		return nil
+	})
+	if err != nil {
+		return false, 0, err
+	}
+
+	return awarded, student.XP(currentXP), nil
+}
+
+func achievementMetadataJSON(achievement student.Achievement) ([]byte, error) {
+	if achievement.Metadata == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(achievement.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal achievement metadata: %w", err)
+	}
+	return data, nil
+}
+
 // GetAchievements returns all achievements for a student.
 func (r *ProgressRepository) GetAchievements(ctx context.Context, studentID string) ([]student.Achievement, error) {
 	query := `
@@ -872,11 +1548,107 @@ func (r *ProgressRepository) GetRecentAchievements(ctx context.Context, since ti
 // HELPER METHODS
 // ══════════════════════════════════════════════════════════════════════════════
 
+// studentInsertArgs returns the arguments for insertStudentQuery.
+func studentInsertArgs(s *student.Student) ([]interface{}, error) {
+	prefsJSON, err := student.EncodePreferences(s.Preferences)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal preferences: %w", err)
+	}
+
+	return []interface{}{
+		s.ID,
+		int64(s.TelegramID),
+		s.Email,
+		s.PasswordHash,
+		s.DisplayName,
+		int(s.CurrentXP),
+		string(s.Cohort),
+		string(s.Status),
+		string(s.OnlineState),
+		s.LastSeenAt,
+		s.LastSyncedAt,
+		s.JoinedAt,
+		prefsJSON,
+		s.HelpRating,
+		s.HelpCount,
+		s.CreatedAt,
+		s.UpdatedAt,
+		alemLoginToDB(s.AlemLogin),
+	}, nil
+}
+
+// alemLoginToDB stores an empty Alem login as NULL, so legacy rows don't
+// collide on the unique index.
+func alemLoginToDB(login string) *string {
+	login = student.NormalizeAlemLogin(login)
+	if login == "" {
+		return nil
+	}
+	return &login
+}
+
+// alemLoginFromDB converts a nullable alem_login column.
+func alemLoginFromDB(login *string) string {
+	if login == nil {
+		return ""
+	}
+	return *login
+}
+
+// dailyGrindArgs returns the arguments for upsertDailyGrindQuery.
+func dailyGrindArgs(grind *student.DailyGrind) []interface{} {
+	var firstActivity, lastActivity *time.Time
+	if !grind.FirstActivityAt.IsZero() {
+		firstActivity = &grind.FirstActivityAt
+	}
+	if !grind.LastActivityAt.IsZero() {
+		lastActivity = &grind.LastActivityAt
+	}
+
+	return []interface{}{
+		grind.StudentID,
+		grind.Date,
+		int(grind.XPStart),
+		int(grind.XPCurrent),
+		int(grind.XPGained),
+		grind.TasksCompleted,
+		grind.SessionsCount,
+		grind.TotalSessionMinutes,
+		firstActivity,
+		lastActivity,
+		grind.RankAtStart,
+		grind.RankCurrent,
+		grind.RankChange,
+		grind.StreakDay,
+	}
+}
+
+// streakArgs returns the arguments for upsertStreakQuery.
+func streakArgs(streak *student.Streak) []interface{} {
+	var lastActive, streakStart *time.Time
+	if !streak.LastActiveDate.IsZero() {
+		lastActive = &streak.LastActiveDate
+	}
+	if !streak.StreakStartDate.IsZero() {
+		streakStart = &streak.StreakStartDate
+	}
+
+	return []interface{}{
+		streak.StudentID,
+		streak.CurrentStreak,
+		streak.BestStreak,
+		lastActive,
+		streakStart,
+		streak.MilestoneReached,
+	}
+}
+
 // scanStudent scans a single student from a row.
 func (r *StudentRepository) scanStudent(row pgx.Row) (*student.Student, error) {
 	var s student.Student
 	var telegramID int64
 	var email, passwordHash, cohort, status, onlineState string
+	var alemLogin *string
 	var currentXP int
 	var prefsJSON []byte
 
@@ -898,6 +1670,9 @@ func (r *StudentRepository) scanStudent(row pgx.Row) (*student.Student, error) {
 		&s.HelpCount,
 		&s.CreatedAt,
 		&s.UpdatedAt,
+		&s.MutedUntil,
+		&s.HelpMatchingDisabled,
+		&alemLogin,
 	)
 
 	if IsNoRows(err) {
@@ -910,11 +1685,12 @@ func (r *StudentRepository) scanStudent(row pgx.Row) (*student.Student, error) {
 	s.TelegramID = student.TelegramID(telegramID)
 	s.Email = email
 	s.PasswordHash = passwordHash
+	s.AlemLogin = alemLoginFromDB(alemLogin)
 	s.CurrentXP = student.XP(currentXP)
 	s.Cohort = student.Cohort(cohort)
 	s.Status = student.Status(status)
 	s.OnlineState = student.OnlineState(onlineState)
-	s.Preferences = mapToPreferences(prefsJSON)
+	s.Preferences = decodeStoredPreferences(s.ID, prefsJSON)
 
 	return &s, nil
 }
@@ -927,6 +1703,7 @@ func (r *StudentRepository) scanStudents(rows pgx.Rows) ([]*student.Student, err
 		var s student.Student
 		var telegramID int64
 		var email, passwordHash, cohort, status, onlineState string
+		var alemLogin *string
 		var currentXP int
 		var prefsJSON []byte
 
@@ -948,6 +1725,9 @@ func (r *StudentRepository) scanStudents(rows pgx.Rows) ([]*student.Student, err
 			&s.HelpCount,
 			&s.CreatedAt,
 			&s.UpdatedAt,
+			&s.MutedUntil,
+			&s.HelpMatchingDisabled,
+			&alemLogin,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan student: %w", err)
@@ -956,11 +1736,12 @@ func (r *StudentRepository) scanStudents(rows pgx.Rows) ([]*student.Student, err
 		s.TelegramID = student.TelegramID(telegramID)
 		s.Email = email
 		s.PasswordHash = passwordHash
+		s.AlemLogin = alemLoginFromDB(alemLogin)
 		s.CurrentXP = student.XP(currentXP)
 		s.Cohort = student.Cohort(cohort)
 		s.Status = student.Status(status)
 		s.OnlineState = student.OnlineState(onlineState)
-		s.Preferences = mapToPreferences(prefsJSON)
+		s.Preferences = decodeStoredPreferences(s.ID, prefsJSON)
 
 		students = append(students, &s)
 	}
@@ -977,7 +1758,8 @@ func (r *StudentRepository) buildListQuery(opts student.ListOptions, whereClause
 	query := `
 		SELECT id, telegram_id, email, password_hash, display_name, current_xp, cohort,
 			   status, online_state, last_seen_at, last_synced_at, joined_at,
-			   preferences, help_rating, help_count, created_at, updated_at
+			   preferences, help_rating, help_count, created_at, updated_at, muted_until,
+			   help_matching_disabled, alem_login
 		FROM students
 	`
 
@@ -1156,49 +1938,85 @@ func (r *ProgressRepository) scanDailyGrindFromRows(rows pgx.Rows) (*student.Dai
 // PREFERENCES CONVERSION
 // ══════════════════════════════════════════════════════════════════════════════
 
-// preferencesToMap converts NotificationPreferences to a map for JSON storage.
-func preferencesToMap(prefs student.NotificationPreferences) map[string]interface{} {
-	return map[string]interface{}{
-		"rank_changes":         prefs.RankChanges,
-		"daily_digest":         prefs.DailyDigest,
-		"help_requests":        prefs.HelpRequests,
-		"inactivity_reminders": prefs.InactivityReminders,
-		"quiet_hours_start":    prefs.QuietHoursStart,
-		"quiet_hours_end":      prefs.QuietHoursEnd,
-	}
-}
-
-// mapToPreferences converts JSON bytes to NotificationPreferences.
-func mapToPreferences(data []byte) student.NotificationPreferences {
-	prefs := student.DefaultNotificationPreferences()
+// ListStoredPreferences returns raw preference blobs ordered by student ID,
+// starting after afterID ("" for the first page).
+func (r *StudentRepository) ListStoredPreferences(ctx context.Context, afterID string, limit int) ([]student.StoredPreferences, error) {
+	query := `
+		SELECT id, preferences
+		FROM students
+		WHERE id::text > $1
+		ORDER BY id::text
+		LIMIT $2
+	`
 
-	if len(data) == 0 {
-		return prefs
+	rows, err := r.conn.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list preferences: %w", err)
 	}
+	defer rows.Close()
 
-	var m map[string]interface{}
-	if err := json.Unmarshal(data, &m); err != nil {
-		return prefs
+	var result []student.StoredPreferences
+	for rows.Next() {
+		var p student.StoredPreferences
+		if err := rows.Scan(&p.StudentID, &p.Data); err != nil {
+			return nil, fmt.Errorf("failed to scan preferences: %w", err)
+		}
+		result = append(result, p)
 	}
 
-	if v, ok := m["rank_changes"].(bool); ok {
-		prefs.RankChanges = v
-	}
-	if v, ok := m["daily_digest"].(bool); ok {
-		prefs.DailyDigest = v
-	}
-	if v, ok := m["help_requests"].(bool); ok {
-		prefs.HelpRequests = v
-	}
-	if v, ok := m["inactivity_reminders"].(bool); ok {
-		prefs.InactivityReminders = v
+	return result, rows.Err()
+}
+
+// ReplacePreferences rewrites a preference blob if it still equals old, so a
+// concurrent settings change is never overwritten by the migration.
+func (r *StudentRepository) ReplacePreferences(ctx context.Context, studentID string, old, updated []byte) (bool, error) {
+	query := `
+		UPDATE students
+		SET preferences = $3::jsonb
+		WHERE id = $1 AND preferences = $2::jsonb
+	`
+
+	result, err := r.conn.Exec(ctx, query, studentID, old, updated)
+	if err != nil {
+		return false, fmt.Errorf("failed to replace preferences: %w", err)
 	}
-	if v, ok := m["quiet_hours_start"].(float64); ok {
-		prefs.QuietHoursStart = int(v)
+	return result.RowsAffected() > 0, nil
+}
+
+// categoryMutesToMap converts per-category mutes to {"rank": RFC3339}.
+func categoryMutesToMap(mutes map[student.MuteCategory]time.Time) map[string]string {
+	if len(mutes) == 0 {
+		return nil
 	}
-	if v, ok := m["quiet_hours_end"].(float64); ok {
-		prefs.QuietHoursEnd = int(v)
+	m := make(map[string]string, len(mutes))
+	for category, until := range mutes {
+		m[string(category)] = until.UTC().Format(time.RFC3339)
 	}
+	return m
+}
 
+// preferencesDecodeFailures counts stored preferences that had to be
+// repaired or defaulted on read.
+var preferencesDecodeFailures atomic.Int64
+
+// PreferencesDecodeFailures returns how many corrupted preference blobs were
+// read since start.
+func PreferencesDecodeFailures() int64 {
+	return preferencesDecodeFailures.Load()
+}
+
+// decodeStoredPreferences decodes the preferences column. Corrupted blobs are
+// logged and counted; the repaired value is used until the blob is rewritten
+// (by the next Update or the migrate_preferences job).
+func decodeStoredPreferences(studentID string, data []byte) student.NotificationPreferences {
+	prefs, report := student.DecodePreferences(data)
+	if report.Failed() {
+		preferencesDecodeFailures.Add(1)
+		slog.Warn("corrupted notification preferences",
+			"student_id", studentID,
+			"status", string(report.Status),
+			"issues", strings.Join(report.Issues, "; "),
+		)
+	}
 	return prefs
 }
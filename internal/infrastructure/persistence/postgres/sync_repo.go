@@ -2,7 +2,6 @@ package postgres
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
@@ -10,43 +9,67 @@ import (
 )
 
 // SyncRepository implements student.SyncRepository for PostgreSQL.
+// Runs are kept in sync_runs, per-student status in the last_sync_* columns
+// of students.
 type SyncRepository struct {
-	conn *Connection
+	conn     *Connection
+	students *StudentRepository
 }
 
 // NewSyncRepository creates a new SyncRepository.
 func NewSyncRepository(conn *Connection) *SyncRepository {
-	return &SyncRepository{conn: conn}
+	return &SyncRepository{conn: conn, students: NewStudentRepository(conn)}
 }
 
-// GetLastSyncTime returns the time of the last successful sync.
-// It uses a system_settings table or defaults to a reasonable time if not found.
+// GetLastSyncTime returns when the last completed run finished, or the zero
+// time if no run has completed yet.
 func (r *SyncRepository) GetLastSyncTime(ctx context.Context) (time.Time, error) {
-	// Simple implementation: return current time - 24h if checks fail
-	// Ideally we'd query a settings table e.g. SELECT value FROM settings WHERE key = 'last_sync_time'
-	// For now we assume we sync frequently.
-	return time.Now().Add(-24 * time.Hour), nil
+	query := `
+		SELECT finished_at FROM sync_runs
+		WHERE status = 'completed'
+		ORDER BY finished_at DESC
+		LIMIT 1
+	`
+
+	var finishedAt time.Time
+	err := r.conn.QueryRow(ctx, query).Scan(&finishedAt)
+	if IsNoRows(err) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get last sync time: %w", err)
+	}
+	return finishedAt, nil
 }
 
-// SetLastSyncTime sets the time of the last sync.
+// SetLastSyncTime records a completed run without counts, for syncs that
+// do not go through StartSyncRun.
 func (r *SyncRepository) SetLastSyncTime(ctx context.Context, t time.Time) error {
-	// No-op for now unless we have a settings table
+	query := `
+		INSERT INTO sync_runs (started_at, finished_at, status)
+		VALUES ($1, $1, 'completed')
+	`
+
+	if _, err := r.conn.Exec(ctx, query, t); err != nil {
+		return fmt.Errorf("failed to set last sync time: %w", err)
+	}
 	return nil
 }
 
-// GetStudentsToSync returns students that need to be synced.
+// GetStudentsToSync returns students not synced within olderThan, the
+// stalest first, so a run that stopped midway is continued by the next one.
 func (r *SyncRepository) GetStudentsToSync(ctx context.Context, olderThan time.Duration) ([]*student.Student, error) {
 	threshold := time.Now().Add(-olderThan)
 
 	query := `
 		SELECT id, telegram_id, email, password_hash, display_name, current_xp, cohort,
 			   status, online_state, last_seen_at, last_synced_at, joined_at,
-			   preferences, help_rating, help_count, created_at, updated_at
+			   preferences, help_rating, help_count, created_at, updated_at, muted_until,
+			   help_matching_disabled, alem_login
 		FROM students
-		WHERE status != 'left' 
-		  AND (last_synced_at IS NULL OR last_synced_at < $1)
-		ORDER BY last_synced_at ASC NULLS FIRST
-		LIMIT 100
+		WHERE status != 'left'
+		  AND last_synced_at < $1
+		ORDER BY last_synced_at ASC
 	`
 
 	rows, err := r.conn.Query(ctx, query, threshold)
@@ -55,85 +78,112 @@ func (r *SyncRepository) GetStudentsToSync(ctx context.Context, olderThan time.D
 	}
 	defer rows.Close()
 
-	// We can reuse scanStudents from student_repo.go if we make it public or duplicate logic.
-	// Since we are in the same package 'postgres', we can access unexported methods of other types?
-	// No, scanStudents is a method on *StudentRepository. We can't call it easily on *SyncRepository.
-	// So we duplicate the scan logic or refactor. Duplication is safer for now to avoid breaking existing code.
+	return r.students.scanStudents(rows)
+}
 
-	var students []*student.Student
-	for rows.Next() {
-		var s student.Student
-		var telegramID int64
-		var email, passwordHash, cohort, status, onlineState string
-		var currentXP int
-		var prefsJSON []byte
-
-		err := rows.Scan(
-			&s.ID,
-			&telegramID,
-			&email,
-			&passwordHash,
-			&s.DisplayName,
-			&currentXP,
-			&cohort,
-			&status,
-			&onlineState,
-			&s.LastSeenAt,
-			&s.LastSyncedAt,
-			&s.JoinedAt,
-			&prefsJSON,
-			&s.HelpRating,
-			&s.HelpCount,
-			&s.CreatedAt,
-			&s.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan student: %w", err)
-		}
+// MarkSynced records a successful sync of a student and resets its failures.
+func (r *SyncRepository) MarkSynced(ctx context.Context, studentID string, syncTime time.Time) error {
+	query := `
+		UPDATE students SET
+			last_synced_at = $2,
+			last_sync_status = 'ok',
+			last_sync_error_type = NULL,
+			last_sync_error = NULL,
+			sync_failures = 0
+		WHERE id = $1
+	`
 
-		s.TelegramID = student.TelegramID(telegramID)
-		s.Email = email
-		s.PasswordHash = passwordHash
-		s.CurrentXP = student.XP(currentXP)
-		s.Cohort = student.Cohort(cohort)
-		s.Status = student.Status(status)
-		s.OnlineState = student.OnlineState(onlineState)
-
-		// Map preferences if needed, logic duplicated from student_repo
-		s.Preferences = student.DefaultNotificationPreferences()
-		if len(prefsJSON) > 0 {
-			var m map[string]interface{}
-			if err := json.Unmarshal(prefsJSON, &m); err == nil {
-				// Simplified mapping
-				if v, ok := m["rank_changes"].(bool); ok {
-					s.Preferences.RankChanges = v
-				}
-			}
-		}
+	if _, err := r.conn.Exec(ctx, query, studentID, syncTime); err != nil {
+		return fmt.Errorf("failed to mark student synced: %w", err)
+	}
+	return nil
+}
 
-		students = append(students, &s)
+// GetSyncErrors returns students whose last sync attempt failed since the
+// given time, the most recent first.
+func (r *SyncRepository) GetSyncErrors(ctx context.Context, since time.Time) ([]student.SyncError, error) {
+	query := `
+		SELECT id, COALESCE(last_sync_error_type, ''), COALESCE(last_sync_error, ''),
+			   last_sync_failed_at, sync_failures
+		FROM students
+		WHERE last_sync_status = 'failed' AND last_sync_failed_at >= $1
+		ORDER BY last_sync_failed_at DESC
+	`
+
+	rows, err := r.conn.Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sync errors: %w", err)
 	}
+	defer rows.Close()
 
-	return students, nil
+	var errs []student.SyncError
+	for rows.Next() {
+		var e student.SyncError
+		if err := rows.Scan(&e.StudentID, &e.ErrorType, &e.Message, &e.OccurredAt, &e.Retries); err != nil {
+			return nil, fmt.Errorf("failed to scan sync error: %w", err)
+		}
+		errs = append(errs, e)
+	}
+	return errs, rows.Err()
 }
 
-// MarkSynced marks a student as synced at the given time.
-func (r *SyncRepository) MarkSynced(ctx context.Context, studentID string, syncTime time.Time) error {
-	query := `UPDATE students SET last_synced_at = $1 WHERE id = $2`
-	_, err := r.conn.Exec(ctx, query, syncTime, studentID)
+// SaveSyncError records a failed sync attempt of a student. last_synced_at
+// is left as is, so the student stays due. Errors without a student belong
+// to the run and are stored by FinishSyncRun.
+func (r *SyncRepository) SaveSyncError(ctx context.Context, syncErr student.SyncError) error {
+	if syncErr.StudentID == "" {
+		return nil
+	}
+
+	query := `
+		UPDATE students SET
+			last_sync_status = 'failed',
+			last_sync_error_type = $2,
+			last_sync_error = $3,
+			last_sync_failed_at = $4,
+			sync_failures = sync_failures + 1
+		WHERE id = $1
+	`
+
+	_, err := r.conn.Exec(ctx, query,
+		syncErr.StudentID, syncErr.ErrorType, syncErr.Message, syncErr.OccurredAt)
 	if err != nil {
-		return fmt.Errorf("failed to mark student synced: %w", err)
+		return fmt.Errorf("failed to save sync error: %w", err)
 	}
 	return nil
 }
 
-// GetSyncErrors returns sync errors (not implemented, returns empty).
-func (r *SyncRepository) GetSyncErrors(ctx context.Context, since time.Time) ([]student.SyncError, error) {
-	return []student.SyncError{}, nil
+// StartSyncRun records the start of a run.
+func (r *SyncRepository) StartSyncRun(ctx context.Context, startedAt time.Time) (int64, error) {
+	query := `INSERT INTO sync_runs (started_at) VALUES ($1) RETURNING id`
+
+	var id int64
+	if err := r.conn.QueryRow(ctx, query, startedAt).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to start sync run: %w", err)
+	}
+	return id, nil
 }
 
-// SaveSyncError saves a sync error (not implemented, logs only).
-func (r *SyncRepository) SaveSyncError(ctx context.Context, err student.SyncError) error {
-	// Could log to a table if it exists
+// FinishSyncRun stores the outcome of a run.
+func (r *SyncRepository) FinishSyncRun(ctx context.Context, run student.SyncRun) error {
+	query := `
+		UPDATE sync_runs SET
+			finished_at = $2,
+			status = $3,
+			students_total = $4,
+			students_synced = $5,
+			students_failed = $6,
+			error = NULLIF($7, '')
+		WHERE id = $1
+	`
+
+	tag, err := r.conn.Exec(ctx, query,
+		run.ID, run.FinishedAt, string(run.Status), run.Total, run.Synced, run.Failed, run.Error)
+	if err != nil {
+		return fmt.Errorf("failed to finish sync run: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("sync run %d not found", run.ID)
+	}
 	return nil
 }
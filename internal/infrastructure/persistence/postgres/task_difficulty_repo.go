@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/activity"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+// TaskDifficultyRepository implements social.TaskDifficultyRepository for PostgreSQL.
+type TaskDifficultyRepository struct {
+	conn *Connection
+}
+
+// NewTaskDifficultyRepository creates a new TaskDifficultyRepository.
+func NewTaskDifficultyRepository(conn *Connection) *TaskDifficultyRepository {
+	return &TaskDifficultyRepository{conn: conn}
+}
+
+const taskDifficultyColumns = `task_id, help_requests, completions, score, computed_at`
+
+// GetHelpDensity counts help requests and completions per task since the
+// given time in one pass over both tables. Cancelled requests are not counted.
+func (r *TaskDifficultyRepository) GetHelpDensity(ctx context.Context, since time.Time) ([]social.TaskHelpDensity, error) {
+	query := `
+		WITH requests AS (
+			SELECT task_id, COUNT(*) AS cnt
+			FROM help_requests
+			WHERE created_at >= $1 AND status <> 'cancelled'
+			GROUP BY task_id
+		), completions AS (
+			SELECT task_id, COUNT(*) AS cnt
+			FROM task_completions
+			WHERE completed_at >= $1
+			GROUP BY task_id
+		)
+		SELECT
+			COALESCE(r.task_id, c.task_id),
+			COALESCE(r.cnt, 0),
+			COALESCE(c.cnt, 0)
+		FROM requests r
+		FULL OUTER JOIN completions c ON c.task_id = r.task_id
+	`
+
+	rows, err := r.conn.Query(ctx, query, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task help density: %w", err)
+	}
+	defer rows.Close()
+
+	var densities []social.TaskHelpDensity
+	for rows.Next() {
+		var d social.TaskHelpDensity
+		var taskID string
+		if err := rows.Scan(&taskID, &d.HelpRequests, &d.Completions); err != nil {
+			return nil, fmt.Errorf("failed to scan task help density: %w", err)
+		}
+		d.TaskID = social.TaskID(taskID)
+		densities = append(densities, d)
+	}
+
+	return densities, rows.Err()
+}
+
+// ReplaceAll replaces the stored difficulties with a new snapshot in a
+// single transaction, so readers never see a half-refreshed table.
+func (r *TaskDifficultyRepository) ReplaceAll(ctx context.Context, difficulties []social.TaskDifficulty) error {
+	batch := &pgx.Batch{}
+	batch.Queue(`DELETE FROM task_difficulty`)
+	for _, d := range difficulties {
+		var score *float64
+		if d.Scored {
+			score = &d.Score
+		}
+		batch.Queue(`
+			INSERT INTO task_difficulty (`+taskDifficultyColumns+`)
+			VALUES ($1, $2, $3, $4, $5)
+		`, string(d.TaskID), d.HelpRequests, d.Completions, score, d.ComputedAt)
+	}
+
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		if err := execBatch(ctx, tx, batch); err != nil {
+			return fmt.Errorf("failed to replace task difficulty: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetByTaskID returns the difficulty of a task.
+func (r *TaskDifficultyRepository) GetByTaskID(ctx context.Context, taskID social.TaskID) (*social.TaskDifficulty, error) {
+	query := `SELECT ` + taskDifficultyColumns + ` FROM task_difficulty WHERE task_id = $1`
+
+	d, err := scanTaskDifficulty(r.conn.QueryRow(ctx, query, string(taskID)))
+	if err != nil {
+		if IsNoRows(err) {
+			return nil, social.ErrTaskDifficultyNotFound
+		}
+		return nil, fmt.Errorf("failed to get task difficulty: %w", err)
+	}
+
+	return d, nil
+}
+
+// List returns rated tasks by descending score, then unrated ones.
+func (r *TaskDifficultyRepository) List(ctx context.Context, limit int) ([]social.TaskDifficulty, error) {
+	query := `
+		SELECT ` + taskDifficultyColumns + `
+		FROM task_difficulty
+		ORDER BY score DESC NULLS LAST, help_requests DESC, task_id
+		LIMIT $1
+	`
+
+	rows, err := r.conn.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task difficulty: %w", err)
+	}
+	defer rows.Close()
+
+	var difficulties []social.TaskDifficulty
+	for rows.Next() {
+		d, err := scanTaskDifficulty(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task difficulty: %w", err)
+		}
+		difficulties = append(difficulties, *d)
+	}
+
+	return difficulties, rows.Err()
+}
+
+func scanTaskDifficulty(row pgx.Row) (*social.TaskDifficulty, error) {
+	var d social.TaskDifficulty
+	var taskID string
+	var score *float64
+	if err := row.Scan(&taskID, &d.HelpRequests, &d.Completions, &score, &d.ComputedAt); err != nil {
+		return nil, err
+	}
+	d.TaskID = social.TaskID(taskID)
+	if score != nil {
+		d.Score = *score
+		d.Scored = true
+	}
+	return &d, nil
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// TASK CATALOG
+// ══════════════════════════════════════════════════════════════════════════════
+
+// TaskCatalog implements activity.TaskCatalog from the task names recorded
+// with completions.
+type TaskCatalog struct {
+	conn *Connection
+}
+
+// NewTaskCatalog creates a new TaskCatalog.
+func NewTaskCatalog(conn *Connection) *TaskCatalog {
+	return &TaskCatalog{conn: conn}
+}
+
+// TaskNames returns the latest non-empty name recorded for each task.
+func (c *TaskCatalog) TaskNames(ctx context.Context, taskIDs []activity.TaskID) (map[activity.TaskID]string, error) {
+	names := make(map[activity.TaskID]string, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return names, nil
+	}
+
+	ids := make([]string, len(taskIDs))
+	for i, id := range taskIDs {
+		ids[i] = string(id)
+	}
+
+	query := `
+		SELECT DISTINCT ON (task_id) task_id, task_name
+		FROM task_completions
+		WHERE task_id = ANY($1) AND COALESCE(task_name, '') <> ''
+		ORDER BY task_id, completed_at DESC
+	`
+
+	rows, err := c.conn.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task names: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan task name: %w", err)
+		}
+		names[activity.TaskID(id)] = name
+	}
+
+	return names, rows.Err()
+}
@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+)
+
+// TriggerRuleRepository implements notification.TriggerRuleRepository for PostgreSQL.
+// Rules are stored as notification.TriggerRuleDocument JSON; is_enabled and
+// notification_type are kept in sync with the document for filtering.
+type TriggerRuleRepository struct {
+	conn *Connection
+}
+
+// NewTriggerRuleRepository creates a new TriggerRuleRepository.
+func NewTriggerRuleRepository(conn *Connection) *TriggerRuleRepository {
+	return &TriggerRuleRepository{conn: conn}
+}
+
+// Save stores a rule, replacing an existing rule with the same ID.
+func (r *TriggerRuleRepository) Save(ctx context.Context, rule *notification.TriggerRule) error {
+	doc, err := notification.EncodeTriggerRule(rule)
+	if err != nil {
+		return fmt.Errorf("failed to encode trigger rule: %w", err)
+	}
+
+	query := `
+		INSERT INTO trigger_rules (id, notification_type, is_enabled, rule, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET
+			notification_type = EXCLUDED.notification_type,
+			is_enabled = EXCLUDED.is_enabled,
+			rule = EXCLUDED.rule,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err = r.conn.Exec(ctx, query,
+		string(rule.ID), string(rule.NotificationType), rule.IsEnabled, doc, rule.CreatedAt, rule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save trigger rule: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a rule.
+func (r *TriggerRuleRepository) GetByID(ctx context.Context, id notification.TriggerRuleID) (*notification.TriggerRule, error) {
+	var data []byte
+	err := r.conn.QueryRow(ctx, `SELECT rule FROM trigger_rules WHERE id = $1`, string(id)).Scan(&data)
+	if err != nil {
+		if IsNoRows(err) {
+			return nil, notification.ErrTriggerRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get trigger rule: %w", err)
+	}
+
+	rule, err := notification.DecodeTriggerRule(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode trigger rule %s: %w", id, err)
+	}
+	return rule, nil
+}
+
+// GetAll returns all rules ordered by ID.
+func (r *TriggerRuleRepository) GetAll(ctx context.Context) ([]*notification.TriggerRule, error) {
+	return r.query(ctx, `SELECT rule FROM trigger_rules ORDER BY id`)
+}
+
+// GetEnabled returns enabled rules ordered by ID.
+func (r *TriggerRuleRepository) GetEnabled(ctx context.Context) ([]*notification.TriggerRule, error) {
+	return r.query(ctx, `SELECT rule FROM trigger_rules WHERE is_enabled ORDER BY id`)
+}
+
+// GetByNotificationType returns rules that create the given notification type.
+func (r *TriggerRuleRepository) GetByNotificationType(ctx context.Context, notificationType notification.NotificationType) ([]*notification.TriggerRule, error) {
+	return r.query(ctx, `SELECT rule FROM trigger_rules WHERE notification_type = $1 ORDER BY id`, string(notificationType))
+}
+
+// GetByConditionType returns rules with at least one condition of the given type.
+func (r *TriggerRuleRepository) GetByConditionType(ctx context.Context, conditionType notification.ConditionType) ([]*notification.TriggerRule, error) {
+	query := `
+		SELECT rule FROM trigger_rules
+		WHERE rule->'conditions' @> jsonb_build_array(jsonb_build_object('type', $1::text))
+		ORDER BY id
+	`
+	return r.query(ctx, query, string(conditionType))
+}
+
+// GetByTag returns rules with the given tag.
+func (r *TriggerRuleRepository) GetByTag(ctx context.Context, tag string) ([]*notification.TriggerRule, error) {
+	return r.query(ctx, `SELECT rule FROM trigger_rules WHERE rule->'tags' ? $1 ORDER BY id`, tag)
+}
+
+// Update replaces an existing rule.
+func (r *TriggerRuleRepository) Update(ctx context.Context, rule *notification.TriggerRule) error {
+	doc, err := notification.EncodeTriggerRule(rule)
+	if err != nil {
+		return fmt.Errorf("failed to encode trigger rule: %w", err)
+	}
+
+	query := `
+		UPDATE trigger_rules
+		SET notification_type = $2, is_enabled = $3, rule = $4, updated_at = $5
+		WHERE id = $1
+	`
+
+	tag, err := r.conn.Exec(ctx, query,
+		string(rule.ID), string(rule.NotificationType), rule.IsEnabled, doc, rule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update trigger rule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return notification.ErrTriggerRuleNotFound
+	}
+	return nil
+}
+
+// Delete removes a rule.
+func (r *TriggerRuleRepository) Delete(ctx context.Context, id notification.TriggerRuleID) error {
+	tag, err := r.conn.Exec(ctx, `DELETE FROM trigger_rules WHERE id = $1`, string(id))
+	if err != nil {
+		return fmt.Errorf("failed to delete trigger rule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return notification.ErrTriggerRuleNotFound
+	}
+	return nil
+}
+
+// Enable turns a rule on.
+func (r *TriggerRuleRepository) Enable(ctx context.Context, id notification.TriggerRuleID) error {
+	return r.setEnabled(ctx, id, true)
+}
+
+// Disable turns a rule off.
+func (r *TriggerRuleRepository) Disable(ctx context.Context, id notification.TriggerRuleID) error {
+	return r.setEnabled(ctx, id, false)
+}
+
+// setEnabled updates the enabled flag in both the column and the document.
+func (r *TriggerRuleRepository) setEnabled(ctx context.Context, id notification.TriggerRuleID, enabled bool) error {
+	query := `
+		UPDATE trigger_rules
+		SET is_enabled = $2,
+			rule = jsonb_set(rule, '{enabled}', to_jsonb($2::boolean)),
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	tag, err := r.conn.Exec(ctx, query, string(id), enabled)
+	if err != nil {
+		return fmt.Errorf("failed to set trigger rule enabled: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return notification.ErrTriggerRuleNotFound
+	}
+	return nil
+}
+
+// query runs a rule query. A rule that no longer decodes is an error rather
+// than being skipped: evaluating a partial rule set silently is worse.
+func (r *TriggerRuleRepository) query(ctx context.Context, query string, args ...any) ([]*notification.TriggerRule, error) {
+	rows, err := r.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trigger rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]*notification.TriggerRule, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan trigger rule: %w", err)
+		}
+		rule, err := notification.DecodeTriggerRule(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode trigger rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
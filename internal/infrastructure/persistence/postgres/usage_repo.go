@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/analytics"
+	"github.com/jackc/pgx/v5"
+)
+
+// CommandUsageRepository implements analytics.UsageRepository for PostgreSQL.
+type CommandUsageRepository struct {
+	conn *Connection
+}
+
+// NewCommandUsageRepository creates a new CommandUsageRepository.
+func NewCommandUsageRepository(conn *Connection) *CommandUsageRepository {
+	return &CommandUsageRepository{conn: conn}
+}
+
+// upsertCommandUsageQuery overwrites a day's counter, so re-flushing a day
+// with the same data is a no-op.
+const upsertCommandUsageQuery = `
+	INSERT INTO command_usage (day, command, cohort, count)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (day, command, cohort) DO UPDATE SET count = EXCLUDED.count
+`
+
+// SaveDay stores the counters of a day in a single transaction.
+func (r *CommandUsageRepository) SaveDay(ctx context.Context, day time.Time, usage []analytics.CommandUsage) error {
+	if len(usage) == 0 {
+		return nil
+	}
+
+	day = analytics.DayOf(day)
+	batch := &pgx.Batch{}
+	for _, u := range usage {
+		batch.Queue(upsertCommandUsageQuery, day, u.Command, u.Cohort, u.Count)
+	}
+
+	return r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		if err := execBatch(ctx, tx, batch); err != nil {
+			return fmt.Errorf("failed to save command usage: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetRange returns the counters for days from..to inclusive.
+func (r *CommandUsageRepository) GetRange(ctx context.Context, from, to time.Time) ([]analytics.CommandUsage, error) {
+	query := `
+		SELECT day, command, cohort, count
+		FROM command_usage
+		WHERE day BETWEEN $1 AND $2
+		ORDER BY day, command, cohort
+	`
+
+	rows, err := r.conn.Query(ctx, query, analytics.DayOf(from), analytics.DayOf(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get command usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []analytics.CommandUsage
+	for rows.Next() {
+		var u analytics.CommandUsage
+		if err := rows.Scan(&u.Day, &u.Command, &u.Cohort, &u.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan command usage: %w", err)
+		}
+		u.Day = analytics.DayOf(u.Day)
+		usage = append(usage, u)
+	}
+
+	return usage, rows.Err()
+}
+
+// DeleteBefore removes counters older than day.
+func (r *CommandUsageRepository) DeleteBefore(ctx context.Context, day time.Time) (int64, error) {
+	tag, err := r.conn.Exec(ctx, `DELETE FROM command_usage WHERE day < $1`, analytics.DayOf(day))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old command usage: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/webhook"
+)
+
+// WebhookRepository implements webhook.Repository for PostgreSQL.
+type WebhookRepository struct {
+	conn *Connection
+}
+
+// NewWebhookRepository creates a new WebhookRepository.
+func NewWebhookRepository(conn *Connection) *WebhookRepository {
+	return &WebhookRepository{conn: conn}
+}
+
+const webhookColumns = `id, url, secret, event_types, cohort, active,
+	consecutive_failures, last_status, last_error, last_delivery_at, created_at, updated_at`
+
+// Create stores a new subscription.
+func (r *WebhookRepository) Create(ctx context.Context, s *webhook.Subscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (id, url, secret, event_types, cohort, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.conn.Exec(ctx, query,
+		s.ID, s.URL, s.Secret, eventTypeStrings(s.EventTypes), s.Cohort, s.Active, s.CreatedAt, s.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a subscription.
+func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*webhook.Subscription, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhook_subscriptions WHERE id = $1`
+
+	s, err := scanWebhook(r.conn.QueryRow(ctx, query, id))
+	if err != nil {
+		if IsNoRows(err) {
+			return nil, webhook.ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return s, nil
+}
+
+// List returns all subscriptions, newest first.
+func (r *WebhookRepository) List(ctx context.Context) ([]*webhook.Subscription, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhook_subscriptions ORDER BY created_at DESC`
+	return r.query(ctx, query)
+}
+
+// ListActive returns active subscriptions.
+func (r *WebhookRepository) ListActive(ctx context.Context) ([]*webhook.Subscription, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhook_subscriptions WHERE active ORDER BY created_at`
+	return r.query(ctx, query)
+}
+
+// Update saves the settings of a subscription. Re-activating resets the
+// failure counter.
+func (r *WebhookRepository) Update(ctx context.Context, s *webhook.Subscription) error {
+	query := `
+		UPDATE webhook_subscriptions
+		SET url = $2, secret = $3, event_types = $4, cohort = $5, active = $6,
+			consecutive_failures = $7, updated_at = $8
+		WHERE id = $1
+	`
+
+	tag, err := r.conn.Exec(ctx, query,
+		s.ID, s.URL, s.Secret, eventTypeStrings(s.EventTypes), s.Cohort, s.Active, s.ConsecutiveFailures, s.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return webhook.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// Delete removes a subscription.
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.conn.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return webhook.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// RecordDelivery stores the outcome of a delivery. The failure counter is
+// incremented in SQL so concurrent deliveries do not lose updates; the
+// subscription is deactivated by the delivery that reaches the limit.
+func (r *WebhookRepository) RecordDelivery(ctx context.Context, id string, d webhook.Delivery) (bool, error) {
+	query := `
+		UPDATE webhook_subscriptions
+		SET consecutive_failures = CASE WHEN $2 THEN 0 ELSE consecutive_failures + 1 END,
+			active = active AND ($2 OR consecutive_failures + 1 < $6),
+			last_status = $3,
+			last_error = $4,
+			last_delivery_at = $5
+		WHERE id = $1
+		RETURNING active, consecutive_failures
+	`
+
+	var (
+		active   bool
+		failures int
+	)
+	err := r.conn.QueryRow(ctx, query,
+		id, d.Succeeded(), d.Status, d.Error, d.At.UTC(), webhook.MaxConsecutiveFailures,
+	).Scan(&active, &failures)
+	if err != nil {
+		if IsNoRows(err) {
+			return false, webhook.ErrSubscriptionNotFound
+		}
+		return false, fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+
+	return !active && failures == webhook.MaxConsecutiveFailures, nil
+}
+
+func (r *WebhookRepository) query(ctx context.Context, query string, args ...interface{}) ([]*webhook.Subscription, error) {
+	rows, err := r.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []*webhook.Subscription
+	for rows.Next() {
+		s, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		subs = append(subs, s)
+	}
+
+	return subs, rows.Err()
+}
+
+func scanWebhook(row pgx.Row) (*webhook.Subscription, error) {
+	var (
+		s     webhook.Subscription
+		types []string
+	)
+	err := row.Scan(
+		&s.ID, &s.URL, &s.Secret, &types, &s.Cohort, &s.Active,
+		&s.ConsecutiveFailures, &s.LastStatus, &s.LastError, &s.LastDeliveryAt, &s.CreatedAt, &s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range types {
+		s.EventTypes = append(s.EventTypes, webhook.EventType(t))
+	}
+	return &s, nil
+}
+
+func eventTypeStrings(types []webhook.EventType) []string {
+	result := make([]string, len(types))
+	for i, t := range types {
+		result[i] = string(t)
+	}
+	return result
+}
+
+// Ensure interface is implemented
+var _ webhook.Repository = (*WebhookRepository)(nil)
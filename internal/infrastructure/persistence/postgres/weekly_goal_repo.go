@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// WeeklyGoalRepository implements student.WeeklyGoalRepository for PostgreSQL.
+type WeeklyGoalRepository struct {
+	conn *Connection
+}
+
+// NewWeeklyGoalRepository creates a new WeeklyGoalRepository.
+func NewWeeklyGoalRepository(conn *Connection) *WeeklyGoalRepository {
+	return &WeeklyGoalRepository{conn: conn}
+}
+
+const weeklyGoalColumns = `student_id, iso_week, target_xp, reached_at, created_at, updated_at`
+
+// Get returns the goal of a student for a week.
+func (r *WeeklyGoalRepository) Get(ctx context.Context, studentID string, week student.ISOWeek) (*student.WeeklyGoal, error) {
+	query := `SELECT ` + weeklyGoalColumns + ` FROM weekly_goals WHERE student_id = $1 AND iso_week = $2`
+
+	goal, err := scanWeeklyGoal(r.conn.QueryRow(ctx, query, studentID, string(week)))
+	if err != nil {
+		if IsNoRows(err) {
+			return nil, student.ErrGoalNotFound
+		}
+		return nil, fmt.Errorf("failed to get weekly goal: %w", err)
+	}
+
+	return goal, nil
+}
+
+// Save inserts or updates a goal. A goal already marked as reached is left
+// untouched and ErrGoalAlreadyReached is returned.
+func (r *WeeklyGoalRepository) Save(ctx context.Context, goal *student.WeeklyGoal) error {
+	query := `
+		INSERT INTO weekly_goals (student_id, iso_week, target_xp, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (student_id, iso_week) DO UPDATE SET
+			target_xp = EXCLUDED.target_xp,
+			updated_at = EXCLUDED.updated_at
+		WHERE weekly_goals.reached_at IS NULL
+	`
+
+	tag, err := r.conn.Exec(ctx, query,
+		goal.StudentID,
+		string(goal.Week),
+		int(goal.TargetXP),
+		goal.CreatedAt,
+		goal.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save weekly goal: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return student.ErrGoalAlreadyReached
+	}
+
+	return nil
+}
+
+// GetPending returns goals of a week that are not reached yet.
+func (r *WeeklyGoalRepository) GetPending(ctx context.Context, week student.ISOWeek) ([]*student.WeeklyGoal, error) {
+	query := `SELECT ` + weeklyGoalColumns + ` FROM weekly_goals WHERE iso_week = $1 AND reached_at IS NULL`
+
+	return r.query(ctx, "pending weekly goals", query, string(week))
+}
+
+// MarkReached sets reached_at once; only the first caller gets true.
+func (r *WeeklyGoalRepository) MarkReached(ctx context.Context, studentID string, week student.ISOWeek, at time.Time) (bool, error) {
+	query := `
+		UPDATE weekly_goals
+		SET reached_at = $3, updated_at = $3
+		WHERE student_id = $1 AND iso_week = $2 AND reached_at IS NULL
+	`
+
+	tag, err := r.conn.Exec(ctx, query, studentID, string(week), at.UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to mark weekly goal reached: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// GetRecent returns the latest goals of a student, newest first.
+func (r *WeeklyGoalRepository) GetRecent(ctx context.Context, studentID string, weeks int) ([]*student.WeeklyGoal, error) {
+	query := `
+		SELECT ` + weeklyGoalColumns + `
+		FROM weekly_goals
+		WHERE student_id = $1
+		ORDER BY iso_week DESC
+		LIMIT $2
+	`
+
+	return r.query(ctx, "recent weekly goals", query, studentID, weeks)
+}
+
+func (r *WeeklyGoalRepository) query(ctx context.Context, what, query string, args ...interface{}) ([]*student.WeeklyGoal, error) {
+	rows, err := r.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", what, err)
+	}
+	defer rows.Close()
+
+	var goals []*student.WeeklyGoal
+	for rows.Next() {
+		goal, err := scanWeeklyGoal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan weekly goal: %w", err)
+		}
+		goals = append(goals, goal)
+	}
+
+	return goals, rows.Err()
+}
+
+func scanWeeklyGoal(row pgx.Row) (*student.WeeklyGoal, error) {
+	var (
+		goal   student.WeeklyGoal
+		week   string
+		target int
+	)
+	if err := row.Scan(&goal.StudentID, &week, &target, &goal.ReachedAt, &goal.CreatedAt, &goal.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	goal.Week = student.ISOWeek(week)
+	goal.TargetXP = student.XP(target)
+	return &goal, nil
+}
+
+// Ensure interface is implemented
+var _ student.WeeklyGoalRepository = (*WeeklyGoalRepository)(nil)
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// XPFlagRepository implements student.XPFlagRepository for PostgreSQL.
+type XPFlagRepository struct {
+	conn *Connection
+}
+
+// NewXPFlagRepository creates a new XPFlagRepository.
+func NewXPFlagRepository(conn *Connection) *XPFlagRepository {
+	return &XPFlagRepository{conn: conn}
+}
+
+const xpFlagColumns = `id, student_id, date, amount, z_score, excess, status, created_at, resolved_at, COALESCE(resolved_by, 0)`
+
+// Flag inserts the flag or refreshes the pending flag of the same day.
+// xmax is zero only for freshly inserted rows, which tells a new flag
+// from an update.
+func (r *XPFlagRepository) Flag(ctx context.Context, flag *student.XPFlag) (bool, error) {
+	query := `
+		INSERT INTO xp_flags (student_id, date, amount, z_score, excess, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, 'pending', $6)
+		ON CONFLICT (student_id, date) DO UPDATE SET
+			amount = EXCLUDED.amount,
+			z_score = EXCLUDED.z_score,
+			excess = EXCLUDED.excess
+		WHERE xp_flags.status = 'pending'
+		RETURNING id, xmax = 0
+	`
+
+	var created bool
+	err := r.conn.QueryRow(ctx, query,
+		flag.StudentID,
+		flag.Date,
+		int(flag.Amount),
+		flag.ZScore,
+		int(flag.Excess),
+		flag.CreatedAt.UTC(),
+	).Scan(&flag.ID, &created)
+	if IsNoRows(err) {
+		// Already resolved: the curator's decision stands
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to save xp flag: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetByID returns the flag with the given ID.
+func (r *XPFlagRepository) GetByID(ctx context.Context, id int64) (*student.XPFlag, error) {
+	flag, err := scanXPFlag(r.conn.QueryRow(ctx, `SELECT `+xpFlagColumns+` FROM xp_flags WHERE id = $1`, id))
+	if IsNoRows(err) {
+		return nil, student.ErrXPFlagNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get xp flag: %w", err)
+	}
+	return flag, nil
+}
+
+// Resolve records the curator's decision. A dismissed flag writes the
+// negative adjustment to xp_history in the same transaction, so the
+// decision and the adjustment cannot get out of step.
+func (r *XPFlagRepository) Resolve(ctx context.Context, id int64, status student.XPFlagStatus, curatorID int64, at time.Time) (*student.XPFlag, error) {
+	var flag *student.XPFlag
+	err := r.conn.WithTx(ctx, DefaultTxOptions(), func(tx pgx.Tx) error {
+		var err error
+		flag, err = scanXPFlag(tx.QueryRow(ctx, `
+			UPDATE xp_flags SET status = $2, resolved_at = $3, resolved_by = $4
+			WHERE id = $1 AND status = 'pending'
+			RETURNING `+xpFlagColumns,
+			id, string(status), at.UTC(), curatorID,
+		))
+		if IsNoRows(err) {
+			var exists bool
+			if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM xp_flags WHERE id = $1)`, id).Scan(&exists); err != nil {
+				return fmt.Errorf("failed to check xp flag: %w", err)
+			}
+			if !exists {
+				return student.ErrXPFlagNotFound
+			}
+			return student.ErrXPFlagResolved
+		}
+		if err != nil {
+			return fmt.Errorf("failed to resolve xp flag: %w", err)
+		}
+
+		if status != student.XPFlagDismissed || flag.Excess <= 0 {
+			return nil
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO xp_history (student_id, old_xp, new_xp, delta, reason, created_at)
+			SELECT id, current_xp, GREATEST(current_xp - $2, 0), GREATEST(current_xp - $2, 0) - current_xp, $3, $4
+			FROM students WHERE id = $1
+		`, flag.StudentID, int(flag.Excess), student.XPAdjustmentReason, at.UTC())
+		if err != nil {
+			return fmt.Errorf("failed to save xp adjustment: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return flag, nil
+}
+
+// PendingStudentIDs returns the students with flags awaiting review.
+func (r *XPFlagRepository) PendingStudentIDs(ctx context.Context) (map[string]bool, error) {
+	rows, err := r.conn.Query(ctx, `SELECT DISTINCT student_id FROM xp_flags WHERE status = 'pending'`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending xp flags: %w", err)
+	}
+	defer rows.Close()
+
+	pending := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan pending xp flag: %w", err)
+		}
+		pending[id] = true
+	}
+
+	return pending, rows.Err()
+}
+
+// scanXPFlag scans a row selected with xpFlagColumns.
+func scanXPFlag(row pgx.Row) (*student.XPFlag, error) {
+	var (
+		flag           student.XPFlag
+		amount, excess int
+		status         string
+	)
+	err := row.Scan(
+		&flag.ID,
+		&flag.StudentID,
+		&flag.Date,
+		&amount,
+		&flag.ZScore,
+		&excess,
+		&status,
+		&flag.CreatedAt,
+		&flag.ResolvedAt,
+		&flag.ResolvedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	flag.Amount = student.XP(amount)
+	flag.Excess = student.XP(excess)
+	flag.Status = student.XPFlagStatus(status)
+	return &flag, nil
+}
@@ -0,0 +1,312 @@
+package projections
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// STUDENT CARD ASSEMBLER
+// Collects BuildCardParams from the repositories concurrently. Only the student
+// row is essential: any other section that fails or runs out of time is left
+// empty and the card is marked partial instead of failing the whole /me.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// CardSection names one piece of data a card is assembled from.
+type CardSection string
+
+const (
+	CardSectionStudent       CardSection = "student"
+	CardSectionDailyGrind    CardSection = "daily_grind"
+	CardSectionStreak        CardSection = "streak"
+	CardSectionAchievements  CardSection = "achievements"
+	CardSectionSocial        CardSection = "social_profile"
+	CardSectionLeaderboard   CardSection = "leaderboard_entry"
+	CardSectionTotalStudents CardSection = "total_students"
+	CardSectionCohortSize    CardSection = "cohort_size"
+)
+
+// DefaultCardAssemblyTimeout is the shared deadline of all section fetches.
+const DefaultCardAssemblyTimeout = 2 * time.Second
+
+// CardAssemblerDeps contains the repositories a card is assembled from.
+// Students is required; a nil optional repository leaves its sections empty.
+type CardAssemblerDeps struct {
+	Students    student.Repository
+	Progress    student.ProgressRepository
+	Social      social.SocialProfileRepository
+	Leaderboard leaderboard.LeaderboardRepository
+
+	// Timeout is the shared deadline; DefaultCardAssemblyTimeout if zero.
+	Timeout time.Duration
+
+	// Metrics receives per-section latencies; a new one is created if nil.
+	Metrics *CardAssemblyMetrics
+
+	Logger *slog.Logger
+}
+
+// StudentCardAssembler builds student cards from the repositories.
+type StudentCardAssembler struct {
+	deps    CardAssemblerDeps
+	view    *StudentCardView
+	metrics *CardAssemblyMetrics
+	logger  *slog.Logger
+}
+
+// NewStudentCardAssembler creates an assembler that builds cards through view.
+func NewStudentCardAssembler(view *StudentCardView, deps CardAssemblerDeps) *StudentCardAssembler {
+	if deps.Timeout <= 0 {
+		deps.Timeout = DefaultCardAssemblyTimeout
+	}
+	if deps.Metrics == nil {
+		deps.Metrics = NewCardAssemblyMetrics()
+	}
+	if deps.Logger == nil {
+		deps.Logger = slog.Default()
+	}
+
+	return &StudentCardAssembler{
+		deps:    deps,
+		view:    view,
+		metrics: deps.Metrics,
+		logger:  deps.Logger.With("component", "student_card_assembler"),
+	}
+}
+
+// Metrics returns the per-section latency metrics.
+func (a *StudentCardAssembler) Metrics() *CardAssemblyMetrics {
+	return a.metrics
+}
+
+// Assemble loads every section of studentID's card concurrently, builds the
+// card and stores it in the view. It fails only if the student row can't be
+// loaded; other failures leave their section empty and mark the card partial.
+func (a *StudentCardAssembler) Assemble(ctx context.Context, studentID string) (*StudentCard, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.deps.Timeout)
+	defer cancel()
+
+	var (
+		params  BuildCardParams
+		mu      sync.Mutex
+		missing []CardSection
+	)
+	soft := func(section CardSection, err error) {
+		a.logger.Warn("student card section unavailable",
+			"student_id", studentID,
+			"section", section,
+			"error", err,
+		)
+		mu.Lock()
+		missing = append(missing, section)
+		mu.Unlock()
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	// The student row is the only hard dependency: its error cancels the
+	// rest of the group. Cohort size needs the cohort, so it follows it.
+	g.Go(func() error {
+		stud, err := timed(gctx, a.metrics, CardSectionStudent, func(ctx context.Context) (*student.Student, error) {
+			return a.deps.Students.GetByID(ctx, studentID)
+		})
+		if err != nil {
+			return fmt.Errorf("projections: load student %s: %w", studentID, err)
+		}
+		params.Student = stud
+
+		size, err := timed(gctx, a.metrics, CardSectionCohortSize, func(ctx context.Context) (int, error) {
+			return a.deps.Students.CountByCohort(ctx, stud.Cohort)
+		})
+		if err != nil {
+			soft(CardSectionCohortSize, err)
+		}
+		params.CohortSize = size
+		return nil
+	})
+
+	if repo := a.deps.Progress; repo != nil {
+		goSoft(g, gctx, a.metrics, CardSectionDailyGrind, soft, func(ctx context.Context) (*student.DailyGrind, error) {
+			return repo.GetTodayDailyGrind(ctx, studentID)
+		}, &params.DailyGrind)
+		goSoft(g, gctx, a.metrics, CardSectionStreak, soft, func(ctx context.Context) (*student.Streak, error) {
+			return repo.GetStreak(ctx, studentID)
+		}, &params.Streak)
+		goSoft(g, gctx, a.metrics, CardSectionAchievements, soft, func(ctx context.Context) ([]student.Achievement, error) {
+			return repo.GetAchievements(ctx, studentID)
+		}, &params.Achievements)
+	}
+
+	if repo := a.deps.Social; repo != nil {
+		goSoft(g, gctx, a.metrics, CardSectionSocial, soft, func(ctx context.Context) (*social.SocialProfile, error) {
+			return repo.GetByStudentID(ctx, social.StudentID(studentID))
+		}, &params.SocialProfile)
+	}
+
+	if repo := a.deps.Leaderboard; repo != nil {
+		goSoft(g, gctx, a.metrics, CardSectionLeaderboard, soft, func(ctx context.Context) (*leaderboard.LeaderboardEntry, error) {
+			return repo.GetStudentRank(ctx, studentID, leaderboard.CohortAll)
+		}, &params.LeaderboardEntry)
+		goSoft(g, gctx, a.metrics, CardSectionTotalStudents, soft, func(ctx context.Context) (int, error) {
+			return repo.GetTotalCount(ctx, leaderboard.CohortAll)
+		}, &params.TotalStudents)
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	card, err := a.view.BuildCard(params)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+		card.Partial = true
+		card.MissingSections = missing
+	}
+
+	if err := a.view.UpsertCard(card); err != nil {
+		return nil, err
+	}
+	return card.clone(), nil
+}
+
+// goSoft fetches a non-essential section in the group: on success the value
+// is stored into dst, on failure the section is reported to soft and the
+// group goes on.
+func goSoft[T any](
+	g *errgroup.Group,
+	ctx context.Context,
+	metrics *CardAssemblyMetrics,
+	section CardSection,
+	soft func(CardSection, error),
+	fetch func(ctx context.Context) (T, error),
+	dst *T,
+) {
+	g.Go(func() error {
+		v, err := timed(ctx, metrics, section, fetch)
+		if err != nil {
+			soft(section, err)
+			return nil
+		}
+		*dst = v
+		return nil
+	})
+}
+
+// timed runs fetch and records its latency. A fetch that returns after the
+// deadline counts as failed even if it returned a value.
+func timed[T any](ctx context.Context, metrics *CardAssemblyMetrics, section CardSection, fetch func(ctx context.Context) (T, error)) (T, error) {
+	start := time.Now()
+	v, err := fetch(ctx)
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	metrics.Record(section, time.Since(start), err)
+
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return v, nil
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// METRICS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// CardAssemblyMetrics tracks latency per card section, so a slow dependency
+// shows up by name.
+type CardAssemblyMetrics struct {
+	mu       sync.RWMutex
+	sections map[CardSection]*cardSectionStats
+}
+
+type cardSectionStats struct {
+	calls    int64
+	failures int64
+	timeouts int64
+	total    time.Duration
+	max      time.Duration
+	last     time.Duration
+}
+
+// NewCardAssemblyMetrics creates new metrics tracker.
+func NewCardAssemblyMetrics() *CardAssemblyMetrics {
+	return &CardAssemblyMetrics{sections: make(map[CardSection]*cardSectionStats)}
+}
+
+// Record records one fetch of section.
+func (m *CardAssemblyMetrics) Record(section CardSection, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.sections[section]
+	if !ok {
+		stats = &cardSectionStats{}
+		m.sections[section] = stats
+	}
+
+	stats.calls++
+	stats.total += duration
+	stats.last = duration
+	if duration > stats.max {
+		stats.max = duration
+	}
+	if err != nil {
+		stats.failures++
+		if errors.Is(err, context.DeadlineExceeded) {
+			stats.timeouts++
+		}
+	}
+}
+
+// CardSectionLatency is a point-in-time snapshot of one section's latency.
+type CardSectionLatency struct {
+	Section  CardSection   `json:"section"`
+	Calls    int64         `json:"calls"`
+	Failures int64         `json:"failures"`
+	Timeouts int64         `json:"timeouts"`
+	Average  time.Duration `json:"average"`
+	Max      time.Duration `json:"max"`
+	Last     time.Duration `json:"last"`
+}
+
+// Snapshot returns the sections sorted from the slowest on average.
+func (m *CardAssemblyMetrics) Snapshot() []CardSectionLatency {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]CardSectionLatency, 0, len(m.sections))
+	for section, stats := range m.sections {
+		result = append(result, CardSectionLatency{
+			Section:  section,
+			Calls:    stats.calls,
+			Failures: stats.failures,
+			Timeouts: stats.timeouts,
+			Average:  stats.total / time.Duration(stats.calls),
+			Max:      stats.max,
+			Last:     stats.last,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Average != result[j].Average {
+			return result[i].Average > result[j].Average
+		}
+		return result[i].Section < result[j].Section
+	})
+	return result
+}
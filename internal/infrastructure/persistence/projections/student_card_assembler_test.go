@@ -0,0 +1,190 @@
+package projections
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+var errCardRepoDown = errors.New("repository down")
+
+// wait sleeps for delay unless ctx ends first.
+func wait(ctx context.Context, delay time.Duration) error {
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type fakeCardStudents struct {
+	student.Repository
+	delay time.Duration
+	err   error
+}
+
+func (f *fakeCardStudents) GetByID(ctx context.Context, id string) (*student.Student, error) {
+	if err := wait(ctx, f.delay); err != nil {
+		return nil, err
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	s := &student.Student{ID: id, TelegramID: 7, DisplayName: "Dana", Cohort: "2024", CurrentXP: 900}
+	s.Preferences = student.DefaultNotificationPreferences()
+	return s, nil
+}
+
+func (f *fakeCardStudents) CountByCohort(ctx context.Context, _ student.Cohort) (int, error) {
+	return 40, wait(ctx, f.delay)
+}
+
+type fakeCardProgress struct {
+	student.ProgressRepository
+	delay           time.Duration
+	achievementsErr error
+}
+
+func (f *fakeCardProgress) GetTodayDailyGrind(ctx context.Context, id string) (*student.DailyGrind, error) {
+	return &student.DailyGrind{StudentID: id, XPGained: 120, TasksCompleted: 2}, wait(ctx, f.delay)
+}
+
+func (f *fakeCardProgress) GetStreak(ctx context.Context, id string) (*student.Streak, error) {
+	return &student.Streak{StudentID: id, CurrentStreak: 5, BestStreak: 9}, wait(ctx, f.delay)
+}
+
+func (f *fakeCardProgress) GetAchievements(ctx context.Context, _ string) ([]student.Achievement, error) {
+	if err := wait(ctx, f.delay); err != nil {
+		return nil, err
+	}
+	if f.achievementsErr != nil {
+		return nil, f.achievementsErr
+	}
+	return []student.Achievement{{Type: student.AchievementFirstTask, UnlockedAt: time.Now()}}, nil
+}
+
+type fakeCardSocial struct {
+	social.SocialProfileRepository
+	delay time.Duration
+	err   error
+}
+
+func (f *fakeCardSocial) GetByStudentID(ctx context.Context, id social.StudentID) (*social.SocialProfile, error) {
+	if err := wait(ctx, f.delay); err != nil {
+		return nil, err
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &social.SocialProfile{StudentID: id, TotalConnections: 3}, nil
+}
+
+type fakeCardLeaderboard struct {
+	leaderboard.LeaderboardRepository
+	delay time.Duration
+}
+
+func (f *fakeCardLeaderboard) GetStudentRank(ctx context.Context, id string, _ leaderboard.Cohort) (*leaderboard.LeaderboardEntry, error) {
+	return &leaderboard.LeaderboardEntry{StudentID: id, Rank: 4}, wait(ctx, f.delay)
+}
+
+func (f *fakeCardLeaderboard) GetTotalCount(ctx context.Context, _ leaderboard.Cohort) (int, error) {
+	return 100, wait(ctx, f.delay)
+}
+
+func newCardDeps(delay time.Duration) CardAssemblerDeps {
+	return CardAssemblerDeps{
+		Students:    &fakeCardStudents{delay: delay},
+		Progress:    &fakeCardProgress{delay: delay},
+		Social:      &fakeCardSocial{delay: delay},
+		Leaderboard: &fakeCardLeaderboard{delay: delay},
+	}
+}
+
+func TestStudentCardAssembler_FullCard(t *testing.T) {
+	view := NewStudentCardView()
+	card, err := NewStudentCardAssembler(view, newCardDeps(0)).Assemble(context.Background(), "dana")
+	require.NoError(t, err)
+
+	assert.False(t, card.Partial)
+	assert.Empty(t, card.MissingSections)
+	assert.Equal(t, leaderboard.Rank(4), card.GlobalRank)
+	assert.Equal(t, 40, card.CohortSize)
+	assert.Equal(t, 5, card.CurrentStreak)
+	assert.Equal(t, 3, card.ConnectionsCount)
+	assert.Equal(t, 1, card.AchievementsCount)
+	assert.True(t, view.Exists("dana"))
+}
+
+func TestStudentCardAssembler_SoftFailureLeavesSectionEmpty(t *testing.T) {
+	deps := newCardDeps(0)
+	deps.Social = &fakeCardSocial{err: errCardRepoDown}
+	deps.Progress = &fakeCardProgress{achievementsErr: errCardRepoDown}
+
+	card, err := NewStudentCardAssembler(NewStudentCardView(), deps).Assemble(context.Background(), "dana")
+	require.NoError(t, err)
+
+	assert.True(t, card.Partial)
+	assert.Equal(t, []CardSection{CardSectionAchievements, CardSectionSocial}, card.MissingSections)
+	assert.Zero(t, card.ConnectionsCount)
+	assert.Zero(t, card.AchievementsCount)
+
+	// The rest of the card is still usable.
+	assert.Equal(t, "Dana", card.DisplayName)
+	assert.Equal(t, leaderboard.Rank(4), card.GlobalRank)
+	assert.Equal(t, 5, card.CurrentStreak)
+}
+
+func TestStudentCardAssembler_StudentFailureIsHard(t *testing.T) {
+	deps := newCardDeps(0)
+	deps.Students = &fakeCardStudents{err: student.ErrStudentNotFound}
+	view := NewStudentCardView()
+
+	card, err := NewStudentCardAssembler(view, deps).Assemble(context.Background(), "dana")
+	assert.ErrorIs(t, err, student.ErrStudentNotFound)
+	assert.Nil(t, card)
+	assert.False(t, view.Exists("dana"))
+}
+
+func TestStudentCardAssembler_SlowSectionHitsSharedDeadline(t *testing.T) {
+	deps := newCardDeps(0)
+	deps.Social = &fakeCardSocial{delay: time.Second}
+	deps.Timeout = 50 * time.Millisecond
+	assembler := NewStudentCardAssembler(NewStudentCardView(), deps)
+
+	card, err := assembler.Assemble(context.Background(), "dana")
+	require.NoError(t, err)
+	assert.Equal(t, []CardSection{CardSectionSocial}, card.MissingSections)
+
+	snapshot := assembler.Metrics().Snapshot()
+	require.NotEmpty(t, snapshot)
+	assert.Equal(t, CardSectionSocial, snapshot[0].Section, "slowest section comes first")
+	assert.Equal(t, int64(1), snapshot[0].Timeouts)
+}
+
+func TestStudentCardAssembler_ConcurrentIsFasterThanSerial(t *testing.T) {
+	const delay = 30 * time.Millisecond
+	assembler := NewStudentCardAssembler(NewStudentCardView(), newCardDeps(delay))
+
+	start := time.Now()
+	_, err := assembler.Assemble(context.Background(), "dana")
+	require.NoError(t, err)
+	elapsed := time.Since(start)
+
+	// Serial assembly would take the sum of all section latencies.
+	var serial time.Duration
+	for _, s := range assembler.Metrics().Snapshot() {
+		serial += s.Last
+	}
+	assert.GreaterOrEqual(t, serial, 8*delay)
+	assert.Less(t, elapsed, serial/2, "elapsed %s, serial %s", elapsed, serial)
+}
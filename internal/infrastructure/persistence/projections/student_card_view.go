@@ -2,13 +2,17 @@
 package projections
 
 import (
+	"container/list"
 	"context"
 	"fmt"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unsafe"
 
 	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
 	"github.com/alem-hub/alem-community-hub/internal/domain/social"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
 )
@@ -23,14 +27,34 @@ import (
 //
 // Philosophy: This view supports "от конкуренции к сотрудничеству" by highlighting
 // a student's contributions to the community, not just their XP ranking.
+//
+// The view holds at most MaxEntries cards; beyond that the least recently
+// used card is evicted from all indexes at once. Reads only take the read
+// lock and stamp the entry's access tick; the recency list catches up on
+// them when an eviction reaches the read entries.
 type StudentCardView struct {
 	mu sync.RWMutex
 
-	// cards holds all student cards indexed by student ID.
-	cards map[string]*StudentCard
+	// cards holds the card entries indexed by student ID.
+	cards map[string]*cardEntry
 
 	// byTelegramID indexes cards by Telegram ID for fast lookup.
-	byTelegramID map[int64]*StudentCard
+	byTelegramID map[int64]*cardEntry
+
+	// byAlemLogin indexes cards by Alem login.
+	byAlemLogin map[string]*cardEntry
+
+	// recency orders entries from most (front) to least recently used.
+	recency *list.List
+
+	// tick is the access clock stamped on entries.
+	tick atomic.Int64
+
+	config StudentCardViewConfig
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
 
 	// lastUpdated is the timestamp of the last update.
 	lastUpdated time.Time
@@ -39,6 +63,46 @@ type StudentCardView struct {
 	version int64
 }
 
+// cardEntry is a card with its place in the recency list.
+type cardEntry struct {
+	card *StudentCard
+	elem *list.Element
+
+	// accessed is the tick of the last access; listed is the tick the entry
+	// had when it was last moved to the front.
+	accessed atomic.Int64
+	listed   int64
+}
+
+// StudentCardViewConfig configures a StudentCardView.
+type StudentCardViewConfig struct {
+	// MaxEntries is the most cards kept (<= 0 means the default).
+	MaxEntries int
+
+	// OnEvict, if set, is called for every card evicted to make room, once
+	// it has left all indexes. It runs with the view locked and must not
+	// call back into the view.
+	OnEvict func(card *StudentCard)
+}
+
+// DefaultStudentCardViewMaxEntries is the default capacity of the view.
+const DefaultStudentCardViewMaxEntries = 5000
+
+// DefaultStudentCardViewConfig returns the default configuration.
+func DefaultStudentCardViewConfig() StudentCardViewConfig {
+	return StudentCardViewConfig{MaxEntries: DefaultStudentCardViewMaxEntries}
+}
+
+// StudentCardViewStats are the view's size and cache counters.
+type StudentCardViewStats struct {
+	Entries        int   `json:"entries"`
+	MaxEntries     int   `json:"max_entries"`
+	EstimatedBytes int64 `json:"estimated_bytes"`
+	Hits           int64 `json:"hits"`
+	Misses         int64 `json:"misses"`
+	Evictions      int64 `json:"evictions"`
+}
+
 // StudentCard is a comprehensive denormalized view of a student.
 // It contains everything needed to display a full student profile.
 type StudentCard struct {
@@ -48,6 +112,7 @@ type StudentCard struct {
 
 	StudentID   string             `json:"student_id"`
 	TelegramID  student.TelegramID `json:"telegram_id"`
+	AlemLogin   string             `json:"alem_login"`
 	DisplayName string             `json:"display_name"`
 	Cohort      student.Cohort     `json:"cohort"`
 	Status      student.Status     `json:"status"`
@@ -156,6 +221,11 @@ type StudentCard struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	Version   int64     `json:"version"`
+
+	// Partial is set when some sections could not be loaded and were left
+	// empty; MissingSections names them (see CardSection).
+	Partial         bool          `json:"partial,omitempty"`
+	MissingSections []CardSection `json:"missing_sections,omitempty"`
 }
 
 // StudentPreferences represents notification and display preferences.
@@ -164,6 +234,8 @@ type StudentPreferences struct {
 	DailyDigest              bool `json:"daily_digest"`
 	HelpRequestNotifications bool `json:"help_request_notifications"`
 	InactivityReminders      bool `json:"inactivity_reminders"`
+	BuddyOnline              bool `json:"buddy_online"`
+	StuckHelpOffers          bool `json:"stuck_help_offers"`
 	QuietHoursStart          int  `json:"quiet_hours_start"`
 	QuietHoursEnd            int  `json:"quiet_hours_end"`
 }
@@ -189,11 +261,24 @@ type AchievementSummary struct {
 // STUDENT CARD VIEW CONSTRUCTOR
 // ══════════════════════════════════════════════════════════════════════════════
 
-// NewStudentCardView creates a new empty student card view.
+// NewStudentCardView creates a new empty student card view with the
+// default configuration.
 func NewStudentCardView() *StudentCardView {
+	return NewStudentCardViewWithConfig(DefaultStudentCardViewConfig())
+}
+
+// NewStudentCardViewWithConfig creates a new empty student card view.
+func NewStudentCardViewWithConfig(config StudentCardViewConfig) *StudentCardView {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = DefaultStudentCardViewMaxEntries
+	}
+
 	return &StudentCardView{
-		cards:        make(map[string]*StudentCard),
-		byTelegramID: make(map[int64]*StudentCard),
+		cards:        make(map[string]*cardEntry),
+		byTelegramID: make(map[int64]*cardEntry),
+		byAlemLogin:  make(map[string]*cardEntry),
+		recency:      list.New(),
+		config:       config,
 		lastUpdated:  time.Now().UTC(),
 		version:      1,
 	}
@@ -229,6 +314,7 @@ func (sv *StudentCardView) BuildCard(params BuildCardParams) (*StudentCard, erro
 		// Core identity
 		StudentID:   s.ID,
 		TelegramID:  s.TelegramID,
+		AlemLogin:   s.AlemLogin,
 		DisplayName: s.DisplayName,
 		Cohort:      s.Cohort,
 		Status:      s.Status,
@@ -248,7 +334,7 @@ func (sv *StudentCardView) BuildCard(params BuildCardParams) (*StudentCard, erro
 		HelpRating:         s.HelpRating,
 		HelpCount:          s.HelpCount,
 		HelpScore:          calculateHelpScore(s.HelpRating, s.HelpCount),
-		IsAvailableForHelp: s.CanHelp(),
+		IsAvailableForHelp: s.IsDiscoverableHelper(),
 
 		// Preferences
 		Preferences: StudentPreferences{
@@ -256,6 +342,8 @@ func (sv *StudentCardView) BuildCard(params BuildCardParams) (*StudentCard, erro
 			DailyDigest:              s.Preferences.DailyDigest,
 			HelpRequestNotifications: s.Preferences.HelpRequests,
 			InactivityReminders:      s.Preferences.InactivityReminders,
+			BuddyOnline:              s.Preferences.BuddyOnline,
+			StuckHelpOffers:          s.Preferences.StuckHelpOffers,
 			QuietHoursStart:          s.Preferences.QuietHoursStart,
 			QuietHoursEnd:            s.Preferences.QuietHoursEnd,
 		},
@@ -278,7 +366,7 @@ func (sv *StudentCardView) BuildCard(params BuildCardParams) (*StudentCard, erro
 		card.BestStreak = params.Streak.BestStreak
 		card.StreakStartDate = params.Streak.StreakStartDate
 		card.LastActiveDate = params.Streak.LastActiveDate
-		card.IsStreakAtRisk = isStreakAtRisk(params.Streak)
+		card.IsStreakAtRisk = isStreakAtRisk(params.Streak, s.Preferences.Location())
 	}
 
 	// Calculate days inactive
@@ -333,11 +421,35 @@ func (sv *StudentCardView) BuildCard(params BuildCardParams) (*StudentCard, erro
 	return card, nil
 }
 
+// BuildCards constructs cards for a batch of students. ranks holds leaderboard
+// entries prefetched for the whole batch (e.g. by GetStudentRanks) and is used
+// for params without their own LeaderboardEntry, so assembling N cards does not
+// need N rank lookups. Students missing from ranks get a card without a rank.
+func (sv *StudentCardView) BuildCards(params []BuildCardParams, ranks map[string]*leaderboard.LeaderboardEntry) ([]*StudentCard, error) {
+	cards := make([]*StudentCard, 0, len(params))
+
+	for _, p := range params {
+		if p.LeaderboardEntry == nil && p.Student != nil {
+			p.LeaderboardEntry = ranks[p.Student.ID]
+		}
+
+		card, err := sv.BuildCard(p)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+
+	return cards, nil
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // UPDATE OPERATIONS
 // ══════════════════════════════════════════════════════════════════════════════
 
-// UpsertCard inserts or updates a student card.
+// UpsertCard inserts or updates a student card. If the student's Telegram
+// ID or Alem login changed, the index entries under the old values are
+// removed. Inserting beyond MaxEntries evicts the least recently used cards.
 func (sv *StudentCardView) UpsertCard(card *StudentCard) error {
 	if card == nil {
 		return fmt.Errorf("projections: cannot upsert nil card")
@@ -349,9 +461,33 @@ func (sv *StudentCardView) UpsertCard(card *StudentCard) error {
 	card.UpdatedAt = time.Now().UTC()
 	card.Version++
 
+	e, exists := sv.cards[card.StudentID]
+	if exists {
+		// Repair the indexes of the old identity
+		old := e.card
+		if old.TelegramID != card.TelegramID && sv.byTelegramID[int64(old.TelegramID)] == e {
+			delete(sv.byTelegramID, int64(old.TelegramID))
+		}
+		if old.AlemLogin != card.AlemLogin && sv.byAlemLogin[old.AlemLogin] == e {
+			delete(sv.byAlemLogin, old.AlemLogin)
+		}
+		e.card = card
+	} else {
+		e = &cardEntry{card: card}
+		e.elem = sv.recency.PushFront(e)
+		sv.cards[card.StudentID] = e
+	}
+	sv.touch(e)
+
 	// Update all indexes
-	sv.cards[card.StudentID] = card
-	sv.byTelegramID[int64(card.TelegramID)] = card
+	sv.byTelegramID[int64(card.TelegramID)] = e
+	if card.AlemLogin != "" {
+		sv.byAlemLogin[card.AlemLogin] = e
+	}
+
+	for len(sv.cards) > sv.config.MaxEntries {
+		sv.evictOldest()
+	}
 
 	sv.lastUpdated = time.Now().UTC()
 	sv.version++
@@ -359,12 +495,92 @@ func (sv *StudentCardView) UpsertCard(card *StudentCard) error {
 	return nil
 }
 
+// touch marks an entry used and moves it to the front. Caller holds the
+// write lock.
+func (sv *StudentCardView) touch(e *cardEntry) {
+	e.listed = sv.tick.Add(1)
+	e.accessed.Store(e.listed)
+	sv.recency.MoveToFront(e.elem)
+}
+
+// evictOldest evicts the least recently used card. An entry read since it
+// was last listed is first moved to its place by the read's tick, so the
+// list stays ordered by last use. Caller holds the write lock.
+func (sv *StudentCardView) evictOldest() {
+	for elem := sv.recency.Back(); elem != nil; elem = sv.recency.Back() {
+		e := elem.Value.(*cardEntry)
+		if accessed := e.accessed.Load(); accessed > e.listed {
+			e.listed = accessed
+			if mark := sv.placeOf(e); mark != elem {
+				sv.recency.MoveBefore(elem, mark)
+				continue
+			}
+			// Still the least recently used
+		}
+
+		sv.remove(e)
+		sv.evictions.Add(1)
+		if sv.config.OnEvict != nil {
+			sv.config.OnEvict(e.card)
+		}
+		return
+	}
+}
+
+// placeOf returns the first element, from the front, listed no later than
+// e. Reads are mostly recent, so the walk is short.
+func (sv *StudentCardView) placeOf(e *cardEntry) *list.Element {
+	mark := sv.recency.Front()
+	for mark.Value.(*cardEntry).listed > e.listed {
+		mark = mark.Next()
+	}
+	return mark
+}
+
+// remove drops an entry from the recency list and every index. Index
+// entries already taken over by another card are left alone. Caller holds
+// the write lock.
+func (sv *StudentCardView) remove(e *cardEntry) {
+	card := e.card
+	delete(sv.cards, card.StudentID)
+	if sv.byTelegramID[int64(card.TelegramID)] == e {
+		delete(sv.byTelegramID, int64(card.TelegramID))
+	}
+	if sv.byAlemLogin[card.AlemLogin] == e {
+		delete(sv.byAlemLogin, card.AlemLogin)
+	}
+	sv.recency.Remove(e.elem)
+}
+
+// entry returns the card of a student for an update and marks it used.
+// Caller holds the write lock.
+func (sv *StudentCardView) entry(studentID string) (*StudentCard, bool) {
+	e, exists := sv.cards[studentID]
+	if !exists {
+		return nil, false
+	}
+	sv.touch(e)
+	return e.card, true
+}
+
+// lookup records a read of e (nil = miss) and returns a copy of its card.
+// Caller holds at least the read lock.
+func (sv *StudentCardView) lookup(e *cardEntry) (*StudentCard, bool) {
+	if e == nil {
+		sv.misses.Add(1)
+		return nil, false
+	}
+	sv.hits.Add(1)
+	e.accessed.Store(sv.tick.Add(1))
+	return e.card.clone(), true
+}
+
 // UpdateOnlineStatus updates the online status for a student.
 func (sv *StudentCardView) UpdateOnlineStatus(studentID string, state student.OnlineState, lastSeen time.Time) {
 	sv.mu.Lock()
 	defer sv.mu.Unlock()
 
-	if card, exists := sv.cards[studentID]; exists {
+	if card, exists := sv.entry(studentID); exists {
 		card.OnlineState = state
 		card.IsOnline = state == student.OnlineStateOnline
 		card.LastSeenAt = lastSeen
@@ -378,7 +594,7 @@ func (sv *StudentCardView) UpdateXP(studentID string, currentXP student.XP, toda
 	sv.mu.Lock()
 	defer sv.mu.Unlock()
 
-	if card, exists := sv.cards[studentID]; exists {
+	if card, exists := sv.entry(studentID); exists {
 		card.CurrentXP = currentXP
 		card.CurrentLevel = student.CalculateLevel(currentXP)
 		card.TodayXP = todayXP
@@ -391,7 +607,7 @@ func (sv *StudentCardView) UpdateRank(studentID string, globalRank leaderboard.R
 	sv.mu.Lock()
 	defer sv.mu.Unlock()
 
-	if card, exists := sv.cards[studentID]; exists {
+	if card, exists := sv.entry(studentID); exists {
 		card.GlobalRank = globalRank
 		card.GlobalRankChange = rankChange
 		card.CohortRank = cohortRank
@@ -404,7 +620,7 @@ func (sv *StudentCardView) UpdateStreak(studentID string, currentStreak, bestStr
 	sv.mu.Lock()
 	defer sv.mu.Unlock()
 
-	if card, exists := sv.cards[studentID]; exists {
+	if card, exists := sv.entry(studentID); exists {
 		card.CurrentStreak = currentStreak
 		card.BestStreak = bestStreak
 		card.LastActiveDate = lastActiveDate
@@ -419,7 +635,7 @@ func (sv *StudentCardView) UpdateDailyGrind(studentID string, xpGained student.X
 	sv.mu.Lock()
 	defer sv.mu.Unlock()
 
-	if card, exists := sv.cards[studentID]; exists {
+	if card, exists := sv.entry(studentID); exists {
 		card.TodayXP = xpGained
 		card.TodayTasksCompleted = tasksCompleted
 		card.TodaySessionMinutes = sessionMinutes
@@ -434,7 +650,7 @@ func (sv *StudentCardView) UpdateHelpStats(studentID string, rating float64, hel
 	sv.mu.Lock()
 	defer sv.mu.Unlock()
 
-	if card, exists := sv.cards[studentID]; exists {
+	if card, exists := sv.entry(studentID); exists {
 		card.HelpRating = rating
 		card.HelpCount = helpCount
 		card.HelpReceivedCount = helpReceivedCount
@@ -448,7 +664,7 @@ func (sv *StudentCardView) AddAchievement(studentID string, achievement student.
 	sv.mu.Lock()
 	defer sv.mu.Unlock()
 
-	if card, exists := sv.cards[studentID]; exists {
+	if card, exists := sv.entry(studentID); exists {
 		summary := convertAchievement(achievement)
 		card.Achievements = append(card.Achievements, summary)
 		card.AchievementsCount = len(card.Achievements)
@@ -462,7 +678,7 @@ func (sv *StudentCardView) UpdateConnections(studentID string, total, studyBuddi
 	sv.mu.Lock()
 	defer sv.mu.Unlock()
 
-	if card, exists := sv.cards[studentID]; exists {
+	if card, exists := sv.entry(studentID); exists {
 		card.ConnectionsCount = total
 		card.StudyBuddiesCount = studyBuddies
 		card.MentoringCount = mentoring
@@ -471,18 +687,72 @@ func (sv *StudentCardView) UpdateConnections(studentID string, total, studyBuddi
 	}
 }
 
+// UpdateDisplayName updates the display name on a student's card.
+func (sv *StudentCardView) UpdateDisplayName(studentID, displayName string) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+
+	if card, exists := sv.entry(studentID); exists {
+		card.DisplayName = displayName
+		card.UpdatedAt = time.Now().UTC()
+	}
+}
+
 // DeleteCard removes a student card from the view.
 func (sv *StudentCardView) DeleteCard(studentID string) {
 	sv.mu.Lock()
 	defer sv.mu.Unlock()
 
-	if card, exists := sv.cards[studentID]; exists {
-		delete(sv.byTelegramID, int64(card.TelegramID))
-		delete(sv.cards, studentID)
+	if e, exists := sv.cards[studentID]; exists {
+		sv.remove(e)
 		sv.version++
 	}
 }
 
+// ══════════════════════════════════════════════════════════════════════════════
+// CACHE INVALIDATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Invalidate drops the cards a cache invalidation makes stale; they are
+// upserted again when the student is next loaded. Leaderboard rebuilds are
+// ignored: ranks are refreshed by UpdateRank.
+// Implements shared.InvalidationTarget.
+func (sv *StudentCardView) Invalidate(msg shared.CacheInvalidation) {
+	switch msg.Kind {
+	case shared.InvalidationStudentUpdated, shared.InvalidationStudentDeleted:
+		sv.DeleteCard(msg.StudentID)
+	case shared.InvalidationCohortArchived:
+		sv.deleteCohort(student.Cohort(msg.Cohort))
+	}
+}
+
+// InvalidateAll drops every card.
+// Implements shared.InvalidationTarget.
+func (sv *StudentCardView) InvalidateAll() {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+
+	sv.cards = make(map[string]*cardEntry)
+	sv.byTelegramID = make(map[int64]*cardEntry)
+	sv.byAlemLogin = make(map[string]*cardEntry)
+	sv.recency.Init()
+	sv.lastUpdated = time.Now().UTC()
+	sv.version++
+}
+
+// deleteCohort removes the cards of a cohort.
+func (sv *StudentCardView) deleteCohort(cohort student.Cohort) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+
+	for _, e := range sv.cards {
+		if e.card.Cohort == cohort {
+			sv.remove(e)
+		}
+	}
+	sv.version++
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // QUERY OPERATIONS
 // ══════════════════════════════════════════════════════════════════════════════
@@ -492,8 +762,8 @@ func (sv *StudentCardView) GetByStudentID(ctx context.Context, studentID string)
 	sv.mu.RLock()
 	defer sv.mu.RUnlock()
 
-	if card, exists := sv.cards[studentID]; exists {
-		return card.clone(), nil
+	if card, ok := sv.lookup(sv.cards[studentID]); ok {
+		return card, nil
 	}
 
 	return nil, fmt.Errorf("projections: student card not found for ID %s", studentID)
@@ -504,13 +774,25 @@ func (sv *StudentCardView) GetByTelegramID(ctx context.Context, telegramID int64
 	sv.mu.RLock()
 	defer sv.mu.RUnlock()
 
-	if card, exists := sv.byTelegramID[telegramID]; exists {
-		return card.clone(), nil
+	if card, ok := sv.lookup(sv.byTelegramID[telegramID]); ok {
+		return card, nil
 	}
 
 	return nil, fmt.Errorf("projections: student card not found for Telegram ID %d", telegramID)
 }
 
+// GetByAlemLogin returns a student card by Alem login.
+func (sv *StudentCardView) GetByAlemLogin(ctx context.Context, login string) (*StudentCard, error) {
+	sv.mu.RLock()
+	defer sv.mu.RUnlock()
+
+	if card, ok := sv.lookup(sv.byAlemLogin[login]); ok {
+		return card, nil
+	}
+
+	return nil, fmt.Errorf("projections: student card not found for Alem login %s", login)
+}
+
 // GetAll returns all student cards with pagination.
 func (sv *StudentCardView) GetAll(ctx context.Context, offset, limit int) ([]*StudentCard, error) {
 	sv.mu.RLock()
@@ -518,8 +800,8 @@ func (sv *StudentCardView) GetAll(ctx context.Context, offset, limit int) ([]*St
 
 	// Convert map to slice
 	all := make([]*StudentCard, 0, len(sv.cards))
-	for _, card := range sv.cards {
-		all = append(all, card)
+	for _, e := range sv.cards {
+		all = append(all, e.card)
 	}
 
 	// Sort by XP descending
@@ -551,8 +833,8 @@ func (sv *StudentCardView) GetByCohort(ctx context.Context, cohort student.Cohor
 	defer sv.mu.RUnlock()
 
 	result := make([]*StudentCard, 0)
-	for _, card := range sv.cards {
-		if card.Cohort == cohort {
+	for _, e := range sv.cards {
+		if card := e.card; card.Cohort == cohort {
 			result = append(result, card.clone())
 		}
 	}
@@ -571,8 +853,8 @@ func (sv *StudentCardView) GetTopHelpers(ctx context.Context, limit int) ([]*Stu
 	defer sv.mu.RUnlock()
 
 	helpers := make([]*StudentCard, 0)
-	for _, card := range sv.cards {
-		if card.HelpCount > 0 {
+	for _, e := range sv.cards {
+		if card := e.card; card.HelpCount > 0 {
 			helpers = append(helpers, card.clone())
 		}
 	}
@@ -595,8 +877,8 @@ func (sv *StudentCardView) GetInactiveStudents(ctx context.Context, daysInactive
 	defer sv.mu.RUnlock()
 
 	result := make([]*StudentCard, 0)
-	for _, card := range sv.cards {
-		if card.DaysInactive >= daysInactive {
+	for _, e := range sv.cards {
+		if card := e.card; card.DaysInactive >= daysInactive {
 			result = append(result, card.clone())
 		}
 	}
@@ -615,8 +897,8 @@ func (sv *StudentCardView) GetStreaksAtRisk(ctx context.Context) ([]*StudentCard
 	defer sv.mu.RUnlock()
 
 	result := make([]*StudentCard, 0)
-	for _, card := range sv.cards {
-		if card.IsStreakAtRisk && card.CurrentStreak > 0 {
+	for _, e := range sv.cards {
+		if card := e.card; card.IsStreakAtRisk && card.CurrentStreak > 0 {
 			result = append(result, card.clone())
 		}
 	}
@@ -631,11 +913,41 @@ func (sv *StudentCardView) GetStreaksAtRisk(ctx context.Context) ([]*StudentCard
 
 // Count returns the total number of student cards.
 func (sv *StudentCardView) Count() int {
+	return sv.Len()
+}
+
+// Len returns the number of cards held.
+func (sv *StudentCardView) Len() int {
 	sv.mu.RLock()
 	defer sv.mu.RUnlock()
 	return len(sv.cards)
 }
 
+// MemoryEstimate returns the approximate bytes held by the cards: the
+// structs plus their strings and slices, without map and list overhead.
+func (sv *StudentCardView) MemoryEstimate() int64 {
+	sv.mu.RLock()
+	defer sv.mu.RUnlock()
+
+	var total int64
+	for _, e := range sv.cards {
+		total += e.card.estimateSize()
+	}
+	return total
+}
+
+// Stats returns the size and cache counters for the metrics endpoint.
+func (sv *StudentCardView) Stats() StudentCardViewStats {
+	return StudentCardViewStats{
+		Entries:        sv.Len(),
+		MaxEntries:     sv.config.MaxEntries,
+		EstimatedBytes: sv.MemoryEstimate(),
+		Hits:           sv.hits.Load(),
+		Misses:         sv.misses.Load(),
+		Evictions:      sv.evictions.Load(),
+	}
+}
+
 // Exists checks if a student card exists.
 func (sv *StudentCardView) Exists(studentID string) bool {
 	sv.mu.RLock()
@@ -662,6 +974,33 @@ func (sv *StudentCardView) GetLastUpdated() time.Time {
 // HELPER FUNCTIONS
 // ══════════════════════════════════════════════════════════════════════════════
 
+// estimateSize returns the approximate bytes of the card and the data it
+// references.
+func (c *StudentCard) estimateSize() int64 {
+	size := int64(unsafe.Sizeof(*c))
+	size += int64(len(c.StudentID) + len(c.AlemLogin) + len(c.DisplayName) + len(c.Cohort) + len(c.Status))
+	size += int64(len(c.DailyGrindSummary) + len(c.LastSeenDisplay))
+
+	size += int64(cap(c.TopEndorsements)) * int64(unsafe.Sizeof(EndorsementSummary{}))
+	for _, e := range c.TopEndorsements {
+		size += int64(len(e.Emoji) + len(e.Label))
+	}
+	for _, achievements := range [][]AchievementSummary{c.Achievements, c.RecentAchievements} {
+		size += int64(cap(achievements)) * int64(unsafe.Sizeof(AchievementSummary{}))
+		for _, a := range achievements {
+			size += int64(len(a.Type) + len(a.Name) + len(a.Emoji))
+		}
+	}
+	for _, strs := range [][]string{c.RecentTasks, c.SpecializedTopics} {
+		size += int64(cap(strs)) * int64(unsafe.Sizeof(""))
+		for _, str := range strs {
+			size += int64(len(str))
+		}
+	}
+	size += int64(cap(c.MissingSections)) * int64(unsafe.Sizeof(CardSection("")))
+	return size
+}
+
 // clone creates a deep copy of a StudentCard.
 func (c *StudentCard) clone() *StudentCard {
 	if c == nil {
@@ -696,6 +1035,11 @@ func (c *StudentCard) clone() *StudentCard {
 		copy(cardCopy.SpecializedTopics, c.SpecializedTopics)
 	}
 
+	if c.MissingSections != nil {
+		cardCopy.MissingSections = make([]CardSection, len(c.MissingSections))
+		copy(cardCopy.MissingSections, c.MissingSections)
+	}
+
 	return &cardCopy
 }
 
@@ -726,13 +1070,14 @@ func formatLastSeen(t time.Time) string {
 	return fmt.Sprintf("%d дн назад", days)
 }
 
-// isStreakAtRisk checks if a streak is at risk (no activity today).
-func isStreakAtRisk(streak *student.Streak) bool {
+// isStreakAtRisk checks if a streak is at risk (no activity today in the
+// student's timezone).
+func isStreakAtRisk(streak *student.Streak, loc *time.Location) bool {
 	if streak == nil || streak.CurrentStreak == 0 {
 		return false
 	}
 
-	today := time.Now().UTC().Truncate(24 * time.Hour)
+	today := student.LocalDate(time.Now(), loc)
 	lastActive := streak.LastActiveDate.Truncate(24 * time.Hour)
 
 	return !lastActive.Equal(today)
@@ -842,6 +1187,8 @@ func convertEndorsements(stats []social.EndorsementTypeStat) []EndorsementSummar
 	return result
 }
 
+var _ shared.InvalidationTarget = (*StudentCardView)(nil)
+
 // ══════════════════════════════════════════════════════════════════════════════
 // STUDENT CARD VIEW REPOSITORY INTERFACE
 // ══════════════════════════════════════════════════════════════════════════════
@@ -0,0 +1,156 @@
+package projections
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+func testCard(i int) *StudentCard {
+	return &StudentCard{
+		StudentID:  fmt.Sprintf("s%d", i),
+		TelegramID: student.TelegramID(100 + i),
+		AlemLogin:  fmt.Sprintf("login%d", i),
+		Cohort:     "2025-spring",
+	}
+}
+
+// assertCardGone checks that no index still finds the card.
+func assertCardGone(t *testing.T, view *StudentCardView, card *StudentCard) {
+	t.Helper()
+	ctx := context.Background()
+	_, err := view.GetByStudentID(ctx, card.StudentID)
+	assert.Error(t, err, "by student ID")
+	_, err = view.GetByTelegramID(ctx, int64(card.TelegramID))
+	assert.Error(t, err, "by Telegram ID")
+	_, err = view.GetByAlemLogin(ctx, card.AlemLogin)
+	assert.Error(t, err, "by Alem login")
+}
+
+func TestStudentCardView_EvictsLeastRecentlyUsedFromAllIndexes(t *testing.T) {
+	ctx := context.Background()
+	var evicted []string
+	view := NewStudentCardViewWithConfig(StudentCardViewConfig{
+		MaxEntries: 3,
+		OnEvict:    func(card *StudentCard) { evicted = append(evicted, card.StudentID) },
+	})
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, view.UpsertCard(testCard(i)))
+	}
+
+	// s0 is read, so s1 is now the least recently used
+	_, err := view.GetByAlemLogin(ctx, "login0")
+	require.NoError(t, err)
+
+	require.NoError(t, view.UpsertCard(testCard(3)))
+	assert.Equal(t, []string{"s1"}, evicted)
+	assert.Equal(t, 3, view.Len())
+	assertCardGone(t, view, testCard(1))
+
+	// Updates count as use too
+	view.UpdateXP("s2", 500, 20)
+	require.NoError(t, view.UpsertCard(testCard(4)))
+	assert.Equal(t, []string{"s1", "s0"}, evicted)
+	assertCardGone(t, view, testCard(0))
+
+	for _, i := range []int{2, 3, 4} {
+		card, err := view.GetByTelegramID(ctx, int64(100+i))
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("s%d", i), card.StudentID)
+	}
+
+	stats := view.Stats()
+	assert.Equal(t, 3, stats.Entries)
+	assert.Equal(t, 3, stats.MaxEntries)
+	assert.Equal(t, int64(2), stats.Evictions)
+	assert.Positive(t, stats.EstimatedBytes)
+	assert.Positive(t, stats.Hits)
+	assert.Positive(t, stats.Misses)
+}
+
+func TestStudentCardView_UpsertRepairsChangedIdentity(t *testing.T) {
+	ctx := context.Background()
+	view := NewStudentCardView()
+	require.NoError(t, view.UpsertCard(testCard(1)))
+
+	// The student moved to a new Telegram account and was renamed on Alem
+	moved := testCard(1)
+	moved.TelegramID = 999
+	moved.AlemLogin = "new-login"
+	require.NoError(t, view.UpsertCard(moved))
+
+	_, err := view.GetByTelegramID(ctx, 101)
+	assert.Error(t, err)
+	_, err = view.GetByAlemLogin(ctx, "login1")
+	assert.Error(t, err)
+
+	card, err := view.GetByTelegramID(ctx, 999)
+	require.NoError(t, err)
+	assert.Equal(t, "s1", card.StudentID)
+	card, err = view.GetByAlemLogin(ctx, "new-login")
+	require.NoError(t, err)
+	assert.Equal(t, "s1", card.StudentID)
+
+	// The old Telegram ID now belongs to another student; dropping s1
+	// must not take that index entry with it
+	other := testCard(2)
+	other.TelegramID = 999
+	require.NoError(t, view.UpsertCard(other))
+	view.DeleteCard("s1")
+
+	card, err = view.GetByTelegramID(ctx, 999)
+	require.NoError(t, err)
+	assert.Equal(t, "s2", card.StudentID)
+	assert.Equal(t, 1, view.Len())
+}
+
+func TestStudentCardView_InvalidateAllClearsIndexes(t *testing.T) {
+	view := NewStudentCardViewWithConfig(StudentCardViewConfig{MaxEntries: 2})
+	require.NoError(t, view.UpsertCard(testCard(1)))
+	require.NoError(t, view.UpsertCard(testCard(2)))
+
+	view.InvalidateAll()
+	assert.Zero(t, view.Len())
+	assertCardGone(t, view, testCard(1))
+
+	// The recency list was reset along with the maps
+	for i := 0; i < 3; i++ {
+		require.NoError(t, view.UpsertCard(testCard(i)))
+	}
+	assert.Equal(t, 2, view.Len())
+	assert.Equal(t, int64(1), view.Stats().Evictions)
+}
+
+// BenchmarkStudentCardView_ConcurrentReads measures reads racing with
+// upserts that keep the view at capacity.
+func BenchmarkStudentCardView_ConcurrentReads(b *testing.B) {
+	const size = DefaultStudentCardViewMaxEntries
+	view := NewStudentCardView()
+	for i := 0; i < size; i++ {
+		_ = view.UpsertCard(testCard(i))
+	}
+
+	var next atomic.Int64
+	next.Store(size)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			i++
+			if i%64 == 0 {
+				_ = view.UpsertCard(testCard(int(next.Add(1))))
+				continue
+			}
+			_, _ = view.GetByTelegramID(ctx, int64(100+i%size))
+		}
+	})
+}
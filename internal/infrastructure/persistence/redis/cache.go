@@ -56,6 +56,17 @@ type Config struct {
 
 	// PoolTimeout is the timeout for getting a connection from the pool.
 	PoolTimeout time.Duration
+
+	// CircuitFailureThreshold is the number of consecutive connection errors
+	// that open the circuit.
+	CircuitFailureThreshold int
+
+	// CircuitCoolDown is how long the circuit stays open before the prober
+	// tries Redis again.
+	CircuitCoolDown time.Duration
+
+	// CircuitProbeInterval is how often the prober checks an open circuit.
+	CircuitProbeInterval time.Duration
 }
 
 // DefaultConfig returns a sensible default configuration.
@@ -72,6 +83,10 @@ func DefaultConfig() Config {
 		ReadTimeout:  3 * time.Second,
 		WriteTimeout: 3 * time.Second,
 		PoolTimeout:  4 * time.Second,
+
+		CircuitFailureThreshold: 5,
+		CircuitCoolDown:         15 * time.Second,
+		CircuitProbeInterval:    time.Second,
 	}
 }
 
@@ -102,6 +117,10 @@ var (
 
 	// ErrCacheNilValue is returned when attempting to cache a nil value.
 	ErrCacheNilValue = errors.New("cache: value cannot be nil")
+
+	// ErrCacheUnavailable is returned by reads while the circuit to Redis is
+	// open. Callers fall back to Postgres.
+	ErrCacheUnavailable = errors.New("cache: unavailable")
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -136,6 +155,21 @@ const (
 
 	// PrefixPubSub is the prefix for pub/sub channels.
 	PrefixPubSub = "pubsub:"
+
+	// PrefixUsage is the prefix for command usage counters.
+	PrefixUsage = "usage:"
+
+	// PrefixCooldown is the prefix for notification cooldown keys.
+	PrefixCooldown = "cooldown:"
+
+	// PrefixEvent is the prefix for community event scoreboards.
+	PrefixEvent = "event:"
+
+	// PrefixDigest is the prefix for daily digest shard markers.
+	PrefixDigest = "digest:"
+
+	// PrefixHelpers is the prefix for helper rankings.
+	PrefixHelpers = "helpers:"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -167,6 +201,22 @@ const (
 
 	// TTLDistributedLock is the default lock TTL.
 	TTLDistributedLock = 30 * time.Second
+
+	// TTLUsageCounters keeps daily usage counters long enough for the nightly
+	// flush to catch up after a few missed runs.
+	TTLUsageCounters = 8 * 24 * time.Hour
+
+	// TTLEventScores keeps an event scoreboard past its longest window, so
+	// the finalizer can freeze results after a few missed runs.
+	TTLEventScores = 45 * 24 * time.Hour
+
+	// TTLDigestShardMarkers keeps digest shard markers until the slot is
+	// long past; the per-student guard covers anything older.
+	TTLDigestShardMarkers = 48 * time.Hour
+
+	// TTLHelperBoard keeps a month's helper ranking through the next month,
+	// after which it is no longer refreshed.
+	TTLHelperBoard = 62 * 24 * time.Hour
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -175,9 +225,12 @@ const (
 
 // Cache provides general-purpose caching functionality with Redis.
 // It handles serialization, TTL management, and error handling.
+// All commands, including those sent through Client(), pass the circuit
+// breaker (see circuit.go).
 type Cache struct {
-	client *redis.Client
-	config Config
+	client  *redis.Client
+	config  Config
+	circuit *circuit
 }
 
 // NewCache creates a new Cache instance with the given configuration.
@@ -203,10 +256,19 @@ func NewCache(cfg Config) (*Cache, error) {
 		return nil, fmt.Errorf("%w: %v", ErrCacheConnection, err)
 	}
 
-	return &Cache{
+	return newCache(client, cfg), nil
+}
+
+// newCache wraps a connected client with the circuit breaker and starts
+// its prober.
+func newCache(client *redis.Client, cfg Config) *Cache {
+	c := &Cache{
 		client: client,
 		config: cfg,
-	}, nil
+	}
+	c.circuit = newCircuit(cfg, client)
+	client.AddHook(c.circuit)
+	return c
 }
 
 // Client returns the underlying Redis client for advanced operations.
@@ -215,8 +277,9 @@ func (c *Cache) Client() *redis.Client {
 	return c.client
 }
 
-// Close closes the Redis connection.
+// Close stops the circuit prober and closes the Redis connection.
 func (c *Cache) Close() error {
+	c.circuit.stop()
 	return c.client.Close()
 }
 
@@ -667,6 +730,11 @@ func StudentKey(studentID string) string {
 	return PrefixStudent + studentID
 }
 
+// DisplayNamesKey is the key of the hash with student display names.
+func DisplayNamesKey() string {
+	return PrefixStudent + "display_names"
+}
+
 // LeaderboardKey generates a cache key for leaderboard data.
 func LeaderboardKey(cohort string) string {
 	if cohort == "" {
@@ -690,6 +758,21 @@ func RateLimitKey(identifier, action string) string {
 	return PrefixRateLimit + identifier + ":" + action
 }
 
+// UsageKey generates the key of the command usage hash for a day.
+func UsageKey(day time.Time) string {
+	return PrefixUsage + "commands:" + day.UTC().Format("2006-01-02")
+}
+
+// EventScoresKey generates the key of an event's scoreboard sorted set.
+func EventScoresKey(eventID string) string {
+	return PrefixEvent + eventID + ":scores"
+}
+
+// HelperBoardKey generates the key of a month's helper ranking sorted set.
+func HelperBoardKey(month time.Time) string {
+	return PrefixHelpers + "month:" + month.Format("2006-01") + ":endorsements"
+}
+
 // LockKey generates a cache key for distributed locks.
 func LockKey(resource string) string {
 	return PrefixLock + resource
@@ -0,0 +1,261 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/alem-hub/alem-community-hub/pkg/circuitbreaker"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CIRCUIT BREAKER
+// Redis is a cache: when it dies mid-operation the bot keeps serving from
+// Postgres. After CircuitFailureThreshold consecutive connection errors the
+// circuit opens for CircuitCoolDown. While it is open nothing reaches the
+// network:
+//   - reads fail immediately with ErrCacheUnavailable;
+//   - writes are no-ops, counted by DroppedWrites.
+//
+// Only the background prober half-opens the circuit, with a PING. When it
+// succeeds the circuit closes and the OnRecover callbacks (leaderboard
+// warmup) run, since the writes dropped meanwhile left the cache stale.
+//
+// The breaker is a go-redis hook, so commands sent through Client() by the
+// leaderboard cache, online tracker and others are covered too. Pub/Sub
+// subscriptions are not commands and reconnect on their own.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// circuitProbeKey marks the prober's PING, which passes an open circuit.
+type circuitProbeKey struct{}
+
+// circuit is the go-redis hook guarding a Cache.
+type circuit struct {
+	breaker       *circuitbreaker.CircuitBreaker
+	client        *redis.Client
+	config        Config
+	droppedWrites atomic.Int64
+
+	mu        sync.Mutex
+	onRecover []func(ctx context.Context)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// newCircuit creates the breaker for client and starts its prober.
+func newCircuit(cfg Config, client *redis.Client) *circuit {
+	defaults := DefaultConfig()
+	if cfg.CircuitFailureThreshold <= 0 {
+		cfg.CircuitFailureThreshold = defaults.CircuitFailureThreshold
+	}
+	if cfg.CircuitCoolDown <= 0 {
+		cfg.CircuitCoolDown = defaults.CircuitCoolDown
+	}
+	if cfg.CircuitProbeInterval <= 0 {
+		cfg.CircuitProbeInterval = defaults.CircuitProbeInterval
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = defaults.DialTimeout
+	}
+
+	c := &circuit{client: client, config: cfg}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	c.breaker = circuitbreaker.New(
+		"redis",
+		circuitbreaker.WithFailureThreshold(cfg.CircuitFailureThreshold),
+		circuitbreaker.WithSuccessThreshold(1),
+		circuitbreaker.WithTimeout(cfg.CircuitCoolDown),
+		circuitbreaker.WithMaxHalfOpenRequests(1),
+		circuitbreaker.WithIsFailure(isConnectionError),
+		circuitbreaker.WithOnStateChange(c.stateChanged),
+	)
+
+	go c.probe()
+	return c
+}
+
+// DialHook implements redis.Hook.
+func (c *circuit) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook.
+func (c *circuit) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if ctx.Value(circuitProbeKey{}) != nil {
+			return next(ctx, cmd)
+		}
+		if c.breaker.State() != circuitbreaker.StateClosed {
+			return c.reject([]redis.Cmder{cmd})
+		}
+		err := c.breaker.Execute(ctx, func(ctx context.Context) error {
+			return next(ctx, cmd)
+		})
+		if isCircuitRejection(err) {
+			return c.reject([]redis.Cmder{cmd})
+		}
+		return err
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook. A pipeline is one request to
+// the breaker.
+func (c *circuit) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if c.breaker.State() != circuitbreaker.StateClosed {
+			return c.reject(cmds)
+		}
+		err := c.breaker.Execute(ctx, func(ctx context.Context) error {
+			return next(ctx, cmds)
+		})
+		if isCircuitRejection(err) {
+			return c.reject(cmds)
+		}
+		return err
+	}
+}
+
+// reject answers commands without touching the network: writes are dropped,
+// anything that reads fails with ErrCacheUnavailable. A pipeline that mixes
+// both fails as a whole.
+func (c *circuit) reject(cmds []redis.Cmder) error {
+	for _, cmd := range cmds {
+		if !isWriteCommand(cmd) {
+			for _, cmd := range cmds {
+				cmd.SetErr(ErrCacheUnavailable)
+			}
+			return ErrCacheUnavailable
+		}
+	}
+	for _, cmd := range cmds {
+		if name := cmd.Name(); name != "multi" && name != "exec" {
+			c.droppedWrites.Add(1)
+		}
+	}
+	return nil
+}
+
+// probe pings Redis while the circuit is not closed. The breaker itself
+// decides when the cool-down is over: until then Execute returns at once.
+func (c *circuit) probe() {
+	ticker := time.NewTicker(c.config.CircuitProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if c.breaker.State() == circuitbreaker.StateClosed {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.WithValue(c.ctx, circuitProbeKey{}, true), c.config.DialTimeout)
+		_ = c.breaker.Execute(ctx, func(ctx context.Context) error {
+			return c.client.Ping(ctx).Err()
+		})
+		cancel()
+	}
+}
+
+// stateChanged runs the recovery callbacks once a probe closes the circuit.
+// It is called under the breaker's lock, so the callbacks run in their own
+// goroutine.
+func (c *circuit) stateChanged(_ string, from, to circuitbreaker.State) {
+	if from != circuitbreaker.StateHalfOpen || to != circuitbreaker.StateClosed {
+		return
+	}
+
+	c.mu.Lock()
+	callbacks := append([]func(ctx context.Context){}, c.onRecover...)
+	c.mu.Unlock()
+
+	go func() {
+		for _, fn := range callbacks {
+			fn(c.ctx)
+		}
+	}()
+}
+
+// stop ends the prober and cancels running recovery callbacks.
+func (c *circuit) stop() {
+	c.cancel()
+}
+
+// isConnectionError reports whether err means Redis could not be reached.
+// Misses, Redis replies (WRONGTYPE, ...) and callers giving up don't count.
+func isConnectionError(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var reply redis.Error
+	return !errors.As(err, &reply)
+}
+
+// isCircuitRejection reports whether the breaker refused the request.
+func isCircuitRejection(err error) bool {
+	return errors.Is(err, circuitbreaker.ErrCircuitOpen) || errors.Is(err, circuitbreaker.ErrTooManyRequests)
+}
+
+// writeCommands are the commands whose replies callers can do without, so
+// they may be dropped while the circuit is open.
+var writeCommands = map[string]bool{
+	"set": true, "setex": true, "mset": true, "del": true, "unlink": true,
+	"expire": true, "pexpire": true, "expireat": true,
+	"incr": true, "incrby": true, "decr": true, "decrby": true,
+	"hset": true, "hmset": true, "hdel": true, "hincrby": true,
+	"sadd": true, "srem": true,
+	"zadd": true, "zrem": true, "zincrby": true, "zremrangebyscore": true, "zremrangebyrank": true,
+	"publish": true, "multi": true, "exec": true,
+}
+
+// isWriteCommand reports whether cmd may be dropped. SET with NX, XX or GET
+// is answered to the caller (locks, cooldowns), so it is a read.
+func isWriteCommand(cmd redis.Cmder) bool {
+	name := cmd.Name()
+	if !writeCommands[name] {
+		return false
+	}
+	if args := cmd.Args(); name == "set" && len(args) > 3 {
+		for _, arg := range args[3:] {
+			if s, ok := arg.(string); ok {
+				switch strings.ToLower(s) {
+				case "nx", "xx", "get":
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CIRCUIT STATE
+// ══════════════════════════════════════════════════════════════════════════════
+
+// CircuitState returns the state of the circuit to Redis: "closed", "open"
+// or "half-open".
+func (c *Cache) CircuitState() string {
+	return c.circuit.breaker.State().String()
+}
+
+// DroppedWrites returns how many writes were dropped while the circuit was
+// open.
+func (c *Cache) DroppedWrites() int64 {
+	return c.circuit.droppedWrites.Load()
+}
+
+// OnRecover registers fn to run after the circuit closes again, e.g. to
+// warm the leaderboard cache from Postgres.
+func (c *Cache) OnRecover(fn func(ctx context.Context)) {
+	c.circuit.mu.Lock()
+	defer c.circuit.mu.Unlock()
+	c.circuit.onRecover = append(c.circuit.onRecover, fn)
+}
@@ -0,0 +1,175 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis answers GET, SET and PING in memory. While down, every command
+// hangs for latency and fails like a refused connection.
+type fakeRedis struct {
+	down    atomic.Bool
+	latency time.Duration
+	calls   atomic.Int64
+
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func (f *fakeRedis) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (f *fakeRedis) ProcessHook(redis.ProcessHook) redis.ProcessHook {
+	return func(_ context.Context, cmd redis.Cmder) error {
+		return f.process(cmd)
+	}
+}
+
+func (f *fakeRedis) ProcessPipelineHook(redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(_ context.Context, cmds []redis.Cmder) error {
+		var first error
+		for _, cmd := range cmds {
+			if err := f.process(cmd); err != nil && first == nil {
+				first = err
+			}
+		}
+		return first
+	}
+}
+
+func (f *fakeRedis) process(cmd redis.Cmder) error {
+	f.calls.Add(1)
+	if f.down.Load() {
+		time.Sleep(f.latency)
+		err := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+		cmd.SetErr(err)
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	args := cmd.Args()
+	switch c := cmd.(type) {
+	case *redis.StatusCmd:
+		if cmd.Name() == "set" {
+			f.data[fmt.Sprint(args[1])] = fmt.Sprint(args[2])
+		}
+		c.SetVal("OK")
+	case *redis.StringCmd:
+		val, ok := f.data[fmt.Sprint(args[1])]
+		if !ok {
+			c.SetErr(redis.Nil)
+			return redis.Nil
+		}
+		c.SetVal(val)
+	case *redis.BoolCmd:
+		c.SetVal(true)
+	}
+	return nil
+}
+
+// newFakeCache returns a Cache over fakeRedis whose circuit opens after 3
+// connection errors and cools down for 50ms.
+func newFakeCache(t *testing.T) (*Cache, *fakeRedis) {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.CircuitFailureThreshold = 3
+	cfg.CircuitCoolDown = 50 * time.Millisecond
+	cfg.CircuitProbeInterval = 5 * time.Millisecond
+
+	fake := &fakeRedis{latency: 20 * time.Millisecond, data: make(map[string]string)}
+	cache := newCache(redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"}), cfg)
+	cache.Client().AddHook(fake)
+	t.Cleanup(func() { _ = cache.Close() })
+	return cache, fake
+}
+
+func TestCache_CircuitFailsFastWhileOpen(t *testing.T) {
+	ctx := context.Background()
+	cache, fake := newFakeCache(t)
+	require.NoError(t, cache.SetString(ctx, "k", "v", time.Minute))
+
+	fake.down.Store(true)
+	for i := 0; i < 3; i++ {
+		_, err := cache.GetString(ctx, "k")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCacheUnavailable)
+	}
+	assert.Equal(t, "open", cache.CircuitState())
+
+	// Reads fail at once without reaching Redis
+	calls := fake.calls.Load()
+	start := time.Now()
+	for i := 0; i < 50; i++ {
+		_, err := cache.GetString(ctx, "k")
+		assert.ErrorIs(t, err, ErrCacheUnavailable)
+	}
+	assert.Less(t, time.Since(start), fake.latency)
+	assert.Equal(t, calls, fake.calls.Load())
+
+	// Writes are dropped and counted; so is a pipeline of writes
+	require.NoError(t, cache.SetString(ctx, "k", "v2", time.Minute))
+	pipe := cache.Client().Pipeline()
+	pipe.Set(ctx, "a", "1", time.Minute)
+	pipe.Expire(ctx, "b", time.Minute)
+	_, err := pipe.Exec(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), cache.DroppedWrites())
+
+	// A SET NX answers the caller, so it is a read
+	_, err = cache.SetNX(ctx, "lock", "1", time.Minute)
+	assert.ErrorIs(t, err, ErrCacheUnavailable)
+
+	// The online tracker reports unknown states instead of errors
+	tracker := NewOnlineTracker(cache)
+	state, err := tracker.GetState(ctx, "dana")
+	require.NoError(t, err)
+	assert.Equal(t, StateUnknown, state)
+	states, err := tracker.GetStates(ctx, []string{"dana", "arman"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]OnlineState{"dana": StateUnknown, "arman": StateUnknown}, states)
+	require.NoError(t, tracker.Heartbeat(ctx, "dana"))
+}
+
+func TestCache_CircuitRecoversAndWarmsUp(t *testing.T) {
+	ctx := context.Background()
+	cache, fake := newFakeCache(t)
+
+	warmups := make(chan struct{}, 1)
+	cache.OnRecover(func(context.Context) { warmups <- struct{}{} })
+
+	fake.down.Store(true)
+	for i := 0; i < 3; i++ {
+		_, _ = cache.GetString(ctx, "k")
+	}
+	require.Equal(t, "open", cache.CircuitState())
+
+	// Probes keep the circuit open while Redis is still down
+	time.Sleep(120 * time.Millisecond)
+	assert.Equal(t, "open", cache.CircuitState())
+	assert.Empty(t, warmups)
+
+	fake.down.Store(false)
+	select {
+	case <-warmups:
+	case <-time.After(2 * time.Second):
+		t.Fatal("warmup did not run after recovery")
+	}
+	assert.Equal(t, "closed", cache.CircuitState())
+
+	require.NoError(t, cache.SetString(ctx, "k", "v", time.Minute))
+	val, err := cache.GetString(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, "v", val)
+}
@@ -0,0 +1,39 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// DigestShardMarkers records digest shards whose recipients were all served,
+// one key per slot and shard: digest:shard:<slot>:<shard>.
+type DigestShardMarkers struct {
+	cache *Cache
+}
+
+// NewDigestShardMarkers creates a new DigestShardMarkers.
+func NewDigestShardMarkers(cache *Cache) *DigestShardMarkers {
+	return &DigestShardMarkers{cache: cache}
+}
+
+// IsShardDone returns true if the shard of the slot is marked as done.
+func (m *DigestShardMarkers) IsShardDone(ctx context.Context, slot string, shard int) (bool, error) {
+	done, err := m.cache.Exists(ctx, digestShardKey(slot, shard))
+	if err != nil {
+		return false, fmt.Errorf("digest_shards: check: %w", err)
+	}
+	return done, nil
+}
+
+// MarkShardDone marks the shard of the slot as done.
+func (m *DigestShardMarkers) MarkShardDone(ctx context.Context, slot string, shard int) error {
+	if err := m.cache.SetString(ctx, digestShardKey(slot, shard), "1", TTLDigestShardMarkers); err != nil {
+		return fmt.Errorf("digest_shards: mark: %w", err)
+	}
+	return nil
+}
+
+func digestShardKey(slot string, shard int) string {
+	return PrefixDigest + "shard:" + slot + ":" + strconv.Itoa(shard)
+}
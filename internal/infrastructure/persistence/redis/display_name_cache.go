@@ -0,0 +1,70 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+)
+
+// DisplayNameCache implements student.DisplayNameCache with a single hash:
+// student:display_names <student_id> <display_name>.
+// The hash has no TTL; entries are replaced on rename and removed on deletion.
+type DisplayNameCache struct {
+	cache *Cache
+}
+
+// NewDisplayNameCache creates a new DisplayNameCache.
+func NewDisplayNameCache(cache *Cache) *DisplayNameCache {
+	return &DisplayNameCache{cache: cache}
+}
+
+// GetNames returns the cached names of the given students.
+func (c *DisplayNameCache) GetNames(ctx context.Context, ids []string) (map[string]string, error) {
+	names := make(map[string]string, len(ids))
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	values, err := c.cache.client.HMGet(ctx, DisplayNamesKey(), ids...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("display_name_cache: get: %w", err)
+	}
+
+	for i, v := range values {
+		if name, ok := v.(string); ok {
+			names[ids[i]] = name
+		}
+	}
+
+	return names, nil
+}
+
+// SetNames stores display names.
+func (c *DisplayNameCache) SetNames(ctx context.Context, names map[string]string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	values := make(map[string]interface{}, len(names))
+	for id, name := range names {
+		values[id] = name
+	}
+
+	if err := c.cache.client.HSet(ctx, DisplayNamesKey(), values).Err(); err != nil {
+		return fmt.Errorf("display_name_cache: set: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteNames removes display names.
+func (c *DisplayNameCache) DeleteNames(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := c.cache.HDel(ctx, DisplayNamesKey(), ids...); err != nil {
+		return fmt.Errorf("display_name_cache: delete: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/event"
+)
+
+// EventScoreboard implements event.Scoreboard with one sorted set per event:
+// ZINCRBY event:<id>:scores <score> <student_id>.
+// Equal scores share a rank, so ranks are counted from higher scores.
+type EventScoreboard struct {
+	cache *Cache
+}
+
+// NewEventScoreboard creates a new EventScoreboard.
+func NewEventScoreboard(cache *Cache) *EventScoreboard {
+	return &EventScoreboard{cache: cache}
+}
+
+// AddScore adds score points to a student.
+func (b *EventScoreboard) AddScore(ctx context.Context, eventID, studentID string, score int) error {
+	key := EventScoresKey(eventID)
+
+	pipe := b.cache.client.Pipeline()
+	pipe.ZIncrBy(ctx, key, float64(score), studentID)
+	pipe.Expire(ctx, key, TTLEventScores)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("event_scoreboard: add score: %w", err)
+	}
+	return nil
+}
+
+// Top returns the first limit participants.
+func (b *EventScoreboard) Top(ctx context.Context, eventID string, limit int) ([]event.Standing, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	members, err := b.cache.client.ZRevRangeWithScores(ctx, EventScoresKey(eventID), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("event_scoreboard: top: %w", err)
+	}
+	return event.RankStandings(toStandings(members)), nil
+}
+
+// Position returns the student's standing, or nil without points.
+func (b *EventScoreboard) Position(ctx context.Context, eventID, studentID string) (*event.Standing, error) {
+	key := EventScoresKey(eventID)
+
+	score, err := b.cache.client.ZScore(ctx, key, studentID).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("event_scoreboard: score: %w", err)
+	}
+	if score <= 0 {
+		return nil, nil
+	}
+
+	higher, err := b.cache.client.ZCount(ctx, key, "("+strconv.FormatFloat(score, 'f', -1, 64), "+inf").Result()
+	if err != nil {
+		return nil, fmt.Errorf("event_scoreboard: rank: %w", err)
+	}
+
+	return &event.Standing{StudentID: studentID, Score: int(score), Rank: int(higher) + 1}, nil
+}
+
+// All returns all participants of an event.
+func (b *EventScoreboard) All(ctx context.Context, eventID string) ([]event.Standing, error) {
+	members, err := b.cache.client.ZRevRangeWithScores(ctx, EventScoresKey(eventID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("event_scoreboard: all: %w", err)
+	}
+	return toStandings(members), nil
+}
+
+// Count returns the number of participants.
+func (b *EventScoreboard) Count(ctx context.Context, eventID string) (int, error) {
+	count, err := b.cache.client.ZCount(ctx, EventScoresKey(eventID), "(0", "+inf").Result()
+	if err != nil {
+		return 0, fmt.Errorf("event_scoreboard: count: %w", err)
+	}
+	return int(count), nil
+}
+
+func toStandings(members []redis.Z) []event.Standing {
+	standings := make([]event.Standing, 0, len(members))
+	for _, m := range members {
+		id, ok := m.Member.(string)
+		if !ok {
+			continue
+		}
+		standings = append(standings, event.Standing{StudentID: id, Score: int(m.Score)})
+	}
+	return standings
+}
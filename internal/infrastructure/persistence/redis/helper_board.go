@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
+)
+
+// HelperBoard implements student.HelperBoard with one sorted set per month
+// of endorsement counts: helpers:month:<2006-01>:endorsements. Months are
+// calendar months in Almaty time.
+type HelperBoard struct {
+	cache *Cache
+}
+
+// NewHelperBoard creates a new HelperBoard.
+func NewHelperBoard(cache *Cache) *HelperBoard {
+	return &HelperBoard{cache: cache}
+}
+
+// ReplaceMonth replaces the ranking of the month containing month.
+func (b *HelperBoard) ReplaceMonth(ctx context.Context, month time.Time, counts map[string]int) error {
+	key := HelperBoardKey(timeutil.StartOfMonth(month))
+
+	members := make([]redis.Z, 0, len(counts))
+	for id, count := range counts {
+		if count > 0 {
+			members = append(members, redis.Z{Score: float64(count), Member: id})
+		}
+	}
+
+	pipe := b.cache.client.TxPipeline()
+	pipe.Del(ctx, key)
+	if len(members) > 0 {
+		pipe.ZAdd(ctx, key, members...)
+		pipe.Expire(ctx, key, TTLHelperBoard)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("helper_board: replace month: %w", err)
+	}
+	return nil
+}
+
+// TopOfMonth returns the first limit helpers of the month containing month.
+func (b *HelperBoard) TopOfMonth(ctx context.Context, month time.Time, limit int) ([]student.HelperBoardEntry, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	key := HelperBoardKey(timeutil.StartOfMonth(month))
+
+	members, err := b.cache.client.ZRevRangeWithScores(ctx, key, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("helper_board: top: %w", err)
+	}
+
+	entries := make([]student.HelperBoardEntry, 0, len(members))
+	for _, m := range members {
+		id, ok := m.Member.(string)
+		if !ok {
+			continue
+		}
+		entries = append(entries, student.HelperBoardEntry{StudentID: id, Endorsements: int(m.Score)})
+	}
+	return entries, nil
+}
+
+// Ensure interface is implemented
+var _ student.HelperBoard = (*HelperBoard)(nil)
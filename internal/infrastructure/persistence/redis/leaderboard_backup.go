@@ -0,0 +1,576 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// LEADERBOARD CACHE BACKUP
+// Export/import of the leaderboard cache state (sorted sets, info hashes,
+// meta keys and snapshots) for moving between Redis instances.
+//
+// The backup is NDJSON, one record per line:
+//
+//	{"type":"header","version":1,"created_at":"...","cohorts":["all","2024-fall"]}
+//	{"type":"cohort","cohort":"all","meta":"{...}","ttl_ms":-1}
+//	{"type":"entry","cohort":"all","student_id":"...","score":1200,"info":"{...}"}
+//	{"type":"snapshot","cohort":"all","snapshot_id":"...","data":"{...}"}
+//	{"type":"cohort_end","cohort":"all","entries":1,"snapshots":1}
+//
+// Stored values are carried as strings, so a restore writes back exactly the
+// bytes that were exported. The export walks Redis with SCAN/ZSCAN cursors in
+// bounded batches and never blocks it; the import rebuilds each cohort into
+// temporary keys and swaps them in with RENAME in one transaction, so readers
+// see either the old or the restored cohort, never a half-written one.
+// ══════════════════════════════════════════════════════════════════════════════
+
+var _ leaderboard.CacheBackup = (*LeaderboardCache)(nil)
+
+// LeaderboardBackupVersion is the backup format version.
+const LeaderboardBackupVersion = 1
+
+// leaderboardBackupBatch bounds the size of every SCAN, ZSCAN, HMGET and
+// write pipeline of a backup.
+const leaderboardBackupBatch = 500
+
+// Backup record types.
+const (
+	backupRecordHeader    = "header"
+	backupRecordCohort    = "cohort"
+	backupRecordEntry     = "entry"
+	backupRecordSnapshot  = "snapshot"
+	backupRecordCohortEnd = "cohort_end"
+)
+
+// backupRecord is one line of a backup. Fields are set by record type.
+type backupRecord struct {
+	Type string `json:"type"`
+
+	// header
+	Version   int        `json:"version,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	Cohorts   []string   `json:"cohorts,omitempty"`
+
+	Cohort string `json:"cohort,omitempty"`
+
+	// cohort: raw meta value and remaining TTL of the sorted set
+	// (-1 = no expiry, 0 = unknown).
+	Meta  *string `json:"meta,omitempty"`
+	TTLMs int64   `json:"ttl_ms,omitempty"`
+
+	// entry
+	StudentID string   `json:"student_id,omitempty"`
+	Score     *float64 `json:"score,omitempty"`
+	Info      *string  `json:"info,omitempty"`
+
+	// snapshot
+	SnapshotID string  `json:"snapshot_id,omitempty"`
+	Data       *string `json:"data,omitempty"`
+
+	// cohort_end
+	Entries   *int `json:"entries,omitempty"`
+	Snapshots *int `json:"snapshots,omitempty"`
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// EXPORT
+// ─────────────────────────────────────────────────────────────────────────────
+
+// ExportBackup writes the cache state of the given cohorts to w.
+// Without cohorts every cached cohort is exported.
+func (l *LeaderboardCache) ExportBackup(ctx context.Context, w io.Writer, cohorts []string) (*leaderboard.BackupSummary, error) {
+	if len(cohorts) == 0 {
+		found, err := l.cachedCohorts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list cohorts: %w", err)
+		}
+		cohorts = found
+	}
+	cohorts = normalizeBackupCohorts(cohorts)
+
+	out := bufio.NewWriter(w)
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+
+	now := time.Now().UTC()
+	if err := enc.Encode(backupRecord{
+		Type:      backupRecordHeader,
+		Version:   LeaderboardBackupVersion,
+		CreatedAt: &now,
+		Cohorts:   cohorts,
+	}); err != nil {
+		return nil, err
+	}
+
+	summary := &leaderboard.BackupSummary{Version: LeaderboardBackupVersion}
+	for _, cohort := range cohorts {
+		counts, err := l.exportCohort(ctx, enc, cohort)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export cohort %s: %w", cohort, err)
+		}
+		summary.Cohorts = append(summary.Cohorts, counts)
+	}
+
+	if err := out.Flush(); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// cachedCohorts returns the cohorts with a cached sorted set.
+func (l *LeaderboardCache) cachedCohorts(ctx context.Context) ([]string, error) {
+	var cohorts []string
+	iter := l.cache.Client().Scan(ctx, 0, keyLeaderboardXP+"*", leaderboardBackupBatch).Iterator()
+	for iter.Next(ctx) {
+		cohort := strings.TrimPrefix(iter.Val(), keyLeaderboardXP)
+		if isBackupTempKey(cohort) {
+			continue
+		}
+		cohorts = append(cohorts, cohort)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(cohorts)
+	return cohorts, nil
+}
+
+// exportCohort writes one cohort: its meta, entries, snapshots and counts.
+func (l *LeaderboardCache) exportCohort(ctx context.Context, enc *json.Encoder, cohort string) (leaderboard.BackupCohort, error) {
+	client := l.cache.Client()
+	counts := leaderboard.BackupCohort{Cohort: cohort}
+	xpKey := keyLeaderboardXP + cohort
+	infoKey := keyLeaderboardInfo + cohort
+
+	header := backupRecord{Type: backupRecordCohort, Cohort: cohort}
+	meta, err := client.Get(ctx, keyLeaderboardMeta+cohort).Result()
+	switch {
+	case err == nil:
+		header.Meta = &meta
+	case !errors.Is(err, redis.Nil):
+		return counts, err
+	}
+	ttl, err := client.PTTL(ctx, xpKey).Result()
+	if err != nil {
+		return counts, err
+	}
+	header.TTLMs = ttlMillis(ttl)
+	if err := enc.Encode(header); err != nil {
+		return counts, err
+	}
+
+	// ZSCAN may return a member twice while the set is rehashed
+	seen := make(map[string]struct{})
+	var cursor uint64
+	for {
+		pairs, next, err := client.ZScan(ctx, xpKey, cursor, "", leaderboardBackupBatch).Result()
+		if err != nil {
+			return counts, err
+		}
+
+		ids := make([]string, 0, len(pairs)/2)
+		scores := make(map[string]string, len(pairs)/2)
+		for i := 0; i+1 < len(pairs); i += 2 {
+			if _, dup := seen[pairs[i]]; dup {
+				continue
+			}
+			seen[pairs[i]] = struct{}{}
+			ids = append(ids, pairs[i])
+			scores[pairs[i]] = pairs[i+1]
+		}
+
+		if len(ids) > 0 {
+			infos, err := client.HMGet(ctx, infoKey, ids...).Result()
+			if err != nil {
+				return counts, err
+			}
+			for i, id := range ids {
+				score, err := parseScore(scores[id])
+				if err != nil {
+					return counts, fmt.Errorf("bad score of %s: %w", id, err)
+				}
+				rec := backupRecord{Type: backupRecordEntry, Cohort: cohort, StudentID: id, Score: &score}
+				if info, ok := infos[i].(string); ok {
+					rec.Info = &info
+				}
+				if err := enc.Encode(rec); err != nil {
+					return counts, err
+				}
+				counts.Entries++
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	prefix := keyLeaderboardSnapshot + cohort + ":"
+	iter := client.Scan(ctx, 0, prefix+"*", leaderboardBackupBatch).Iterator()
+	var keys []string
+	flush := func() error {
+		if len(keys) == 0 {
+			return nil
+		}
+		values, err := client.MGet(ctx, keys...).Result()
+		if err != nil {
+			return err
+		}
+		for i, key := range keys {
+			data, ok := values[i].(string)
+			if !ok {
+				continue // expired between SCAN and MGET
+			}
+			if err := enc.Encode(backupRecord{
+				Type:       backupRecordSnapshot,
+				Cohort:     cohort,
+				SnapshotID: strings.TrimPrefix(key, prefix),
+				Data:       &data,
+			}); err != nil {
+				return err
+			}
+			counts.Snapshots++
+		}
+		keys = keys[:0]
+		return nil
+	}
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) >= leaderboardBackupBatch {
+			if err := flush(); err != nil {
+				return counts, err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return counts, err
+	}
+	if err := flush(); err != nil {
+		return counts, err
+	}
+
+	entries, snapshots := counts.Entries, counts.Snapshots
+	err = enc.Encode(backupRecord{Type: backupRecordCohortEnd, Cohort: cohort, Entries: &entries, Snapshots: &snapshots})
+	return counts, err
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// IMPORT
+// ─────────────────────────────────────────────────────────────────────────────
+
+// cohortRestore collects one cohort of a backup being imported.
+type cohortRestore struct {
+	cohort    string
+	tmpXP     string
+	tmpInfo   string
+	meta      *string
+	ttlMs     int64
+	entries   int
+	infos     int
+	snapshots map[string]string
+
+	zBatch []redis.Z
+	hBatch map[string]interface{}
+}
+
+// ImportBackup restores a backup written by ExportBackup. Each cohort is
+// replaced atomically once all its records are read and their counts check
+// out; a cohort that fails validation leaves the cached one untouched.
+// Cohorts restored before a failure stay restored.
+//
+// Restored keys get the cache's usual TTLs, since the exported remaining
+// TTLs have usually run out by the time a backup is restored; keys that
+// never expired stay persistent.
+func (l *LeaderboardCache) ImportBackup(ctx context.Context, r io.Reader) (*leaderboard.BackupSummary, error) {
+	in := bufio.NewReader(r)
+
+	header, err := readBackupRecord(in)
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: empty backup", leaderboard.ErrInvalidBackup)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if header.Type != backupRecordHeader {
+		return nil, fmt.Errorf("%w: missing header", leaderboard.ErrInvalidBackup)
+	}
+	if header.Version != LeaderboardBackupVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", leaderboard.ErrInvalidBackup, header.Version)
+	}
+
+	summary := &leaderboard.BackupSummary{Version: header.Version}
+	var current *cohortRestore
+	abort := func(err error) (*leaderboard.BackupSummary, error) {
+		if current != nil {
+			l.dropRestore(ctx, current)
+		}
+		return summary, err
+	}
+
+	for {
+		rec, err := readBackupRecord(in)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return abort(err)
+		}
+
+		switch rec.Type {
+		case backupRecordCohort:
+			if current != nil {
+				return abort(fmt.Errorf("%w: cohort %s is not closed", leaderboard.ErrInvalidBackup, current.cohort))
+			}
+			if rec.Cohort == "" {
+				return abort(fmt.Errorf("%w: cohort without a name", leaderboard.ErrInvalidBackup))
+			}
+			current = newCohortRestore(rec)
+
+		case backupRecordEntry:
+			if current == nil || rec.Cohort != current.cohort {
+				return abort(fmt.Errorf("%w: entry outside of its cohort", leaderboard.ErrInvalidBackup))
+			}
+			if rec.StudentID == "" || rec.Score == nil {
+				return abort(fmt.Errorf("%w: entry without student_id or score", leaderboard.ErrInvalidBackup))
+			}
+			current.addEntry(rec)
+			if len(current.zBatch) >= leaderboardBackupBatch {
+				if err := l.writeRestoreBatch(ctx, current); err != nil {
+					return abort(err)
+				}
+			}
+
+		case backupRecordSnapshot:
+			if current == nil || rec.Cohort != current.cohort {
+				return abort(fmt.Errorf("%w: snapshot outside of its cohort", leaderboard.ErrInvalidBackup))
+			}
+			if rec.SnapshotID == "" || rec.Data == nil {
+				return abort(fmt.Errorf("%w: snapshot without id or data", leaderboard.ErrInvalidBackup))
+			}
+			current.snapshots[rec.SnapshotID] = *rec.Data
+
+		case backupRecordCohortEnd:
+			if current == nil || rec.Cohort != current.cohort {
+				return abort(fmt.Errorf("%w: unexpected end of cohort %s", leaderboard.ErrInvalidBackup, rec.Cohort))
+			}
+			if err := l.writeRestoreBatch(ctx, current); err != nil {
+				return abort(err)
+			}
+			if err := l.commitRestore(ctx, current, rec); err != nil {
+				return abort(err)
+			}
+			summary.Cohorts = append(summary.Cohorts, leaderboard.BackupCohort{
+				Cohort:    current.cohort,
+				Entries:   current.entries,
+				Snapshots: len(current.snapshots),
+			})
+			current = nil
+
+		default:
+			return abort(fmt.Errorf("%w: unknown record type %q", leaderboard.ErrInvalidBackup, rec.Type))
+		}
+	}
+
+	if current != nil {
+		return abort(fmt.Errorf("%w: backup ends inside cohort %s", leaderboard.ErrInvalidBackup, current.cohort))
+	}
+	if len(summary.Cohorts) != len(header.Cohorts) {
+		return summary, fmt.Errorf("%w: header lists %d cohorts, backup has %d", leaderboard.ErrInvalidBackup, len(header.Cohorts), len(summary.Cohorts))
+	}
+	return summary, nil
+}
+
+// newCohortRestore starts restoring the cohort a cohort record opens.
+func newCohortRestore(rec backupRecord) *cohortRestore {
+	suffix := backupTempSuffix + uuid.NewString()
+	return &cohortRestore{
+		cohort:    rec.Cohort,
+		tmpXP:     keyLeaderboardXP + rec.Cohort + suffix,
+		tmpInfo:   keyLeaderboardInfo + rec.Cohort + suffix,
+		meta:      rec.Meta,
+		ttlMs:     rec.TTLMs,
+		snapshots: make(map[string]string),
+		hBatch:    make(map[string]interface{}),
+	}
+}
+
+// addEntry queues an entry for the temporary keys.
+func (c *cohortRestore) addEntry(rec backupRecord) {
+	c.zBatch = append(c.zBatch, redis.Z{Score: *rec.Score, Member: rec.StudentID})
+	if rec.Info != nil {
+		c.hBatch[rec.StudentID] = *rec.Info
+		c.infos++
+	}
+	c.entries++
+}
+
+// writeRestoreBatch writes the queued entries to the temporary keys.
+func (l *LeaderboardCache) writeRestoreBatch(ctx context.Context, c *cohortRestore) error {
+	if len(c.zBatch) == 0 {
+		return nil
+	}
+
+	pipe := l.cache.Client().Pipeline()
+	pipe.ZAdd(ctx, c.tmpXP, c.zBatch...)
+	if len(c.hBatch) > 0 {
+		pipe.HSet(ctx, c.tmpInfo, c.hBatch)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	c.zBatch = c.zBatch[:0]
+	c.hBatch = make(map[string]interface{})
+	return nil
+}
+
+// commitRestore checks the counts of a fully read cohort and swaps the
+// temporary keys in.
+func (l *LeaderboardCache) commitRestore(ctx context.Context, c *cohortRestore, end backupRecord) error {
+	if end.Entries == nil || *end.Entries != c.entries {
+		return fmt.Errorf("%w: cohort %s has %d entries, expected %s", leaderboard.ErrInvalidBackup, c.cohort, c.entries, countString(end.Entries))
+	}
+	if end.Snapshots == nil || *end.Snapshots != len(c.snapshots) {
+		return fmt.Errorf("%w: cohort %s has %d snapshots, expected %s", leaderboard.ErrInvalidBackup, c.cohort, len(c.snapshots), countString(end.Snapshots))
+	}
+
+	client := l.cache.Client()
+	stored, err := client.ZCard(ctx, c.tmpXP).Result()
+	if err != nil {
+		return err
+	}
+	if int(stored) != c.entries {
+		return fmt.Errorf("%w: cohort %s has duplicate entries", leaderboard.ErrInvalidBackup, c.cohort)
+	}
+
+	xpKey := keyLeaderboardXP + c.cohort
+	infoKey := keyLeaderboardInfo + c.cohort
+	metaKey := keyLeaderboardMeta + c.cohort
+	persistent := c.ttlMs < 0
+
+	tx := client.TxPipeline()
+	tx.Del(ctx, xpKey, infoKey, metaKey)
+	if c.entries > 0 {
+		tx.Rename(ctx, c.tmpXP, xpKey)
+		if !persistent {
+			tx.Expire(ctx, xpKey, TTLLeaderboardCache)
+		}
+	}
+	if c.infos > 0 {
+		tx.Rename(ctx, c.tmpInfo, infoKey)
+		if !persistent {
+			tx.Expire(ctx, infoKey, TTLLeaderboardCache)
+		}
+	}
+	if c.meta != nil {
+		ttl := TTLLeaderboardCache
+		if persistent {
+			ttl = 0
+		}
+		tx.Set(ctx, metaKey, *c.meta, ttl)
+	}
+	for id, data := range c.snapshots {
+		tx.Set(ctx, keyLeaderboardSnapshot+c.cohort+":"+id, data, TTLSnapshotCache)
+	}
+	if _, err := tx.Exec(ctx); err != nil {
+		l.dropRestore(ctx, c)
+		return err
+	}
+
+	l.publishRebuilt(ctx, c.cohort, c.entries)
+	return nil
+}
+
+// dropRestore deletes the temporary keys of an abandoned restore.
+func (l *LeaderboardCache) dropRestore(ctx context.Context, c *cohortRestore) {
+	_ = l.cache.Client().Del(context.WithoutCancel(ctx), c.tmpXP, c.tmpInfo).Err()
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// HELPERS
+// ─────────────────────────────────────────────────────────────────────────────
+
+// backupTempSuffix marks the temporary keys of an import.
+const backupTempSuffix = ":restore:"
+
+// isBackupTempKey reports whether a cohort name is a temporary import key.
+func isBackupTempKey(cohort string) bool {
+	return strings.Contains(cohort, backupTempSuffix)
+}
+
+// readBackupRecord reads the next non-empty line. Lines aren't size-limited:
+// a snapshot of a large cohort is one long line.
+func readBackupRecord(in *bufio.Reader) (backupRecord, error) {
+	for {
+		line, err := in.ReadBytes('\n')
+		if len(strings.TrimSpace(string(line))) > 0 {
+			var rec backupRecord
+			if jsonErr := json.Unmarshal(line, &rec); jsonErr != nil {
+				return rec, fmt.Errorf("%w: %v", leaderboard.ErrInvalidBackup, jsonErr)
+			}
+			return rec, nil
+		}
+		if err != nil {
+			return backupRecord{}, err
+		}
+	}
+}
+
+// normalizeBackupCohorts maps the empty cohort to the default one and drops
+// duplicates.
+func normalizeBackupCohorts(cohorts []string) []string {
+	seen := make(map[string]struct{}, len(cohorts))
+	normalized := make([]string, 0, len(cohorts))
+	for _, cohort := range cohorts {
+		if cohort == "" {
+			cohort = defaultCohort
+		}
+		if _, dup := seen[cohort]; dup {
+			continue
+		}
+		seen[cohort] = struct{}{}
+		normalized = append(normalized, cohort)
+	}
+	return normalized
+}
+
+// parseScore parses a ZSCAN score.
+func parseScore(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
+
+// ttlMillis converts a PTTL result: -1 (no expiry) is kept, a missing key
+// is reported as unknown.
+func ttlMillis(ttl time.Duration) int64 {
+	switch {
+	case ttl == -1:
+		return -1
+	case ttl < 0:
+		return 0
+	default:
+		return ttl.Milliseconds()
+	}
+}
+
+// countString renders an optional count for errors.
+func countString(n *int) string {
+	if n == nil {
+		return "none"
+	}
+	return fmt.Sprint(*n)
+}
@@ -0,0 +1,121 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+)
+
+func backupEntries(n int, cohort string) []LeaderboardEntry {
+	entries := make([]LeaderboardEntry, n)
+	for i := range entries {
+		entries[i] = LeaderboardEntry{
+			StudentID:   fmt.Sprintf("student-%05d", i),
+			DisplayName: fmt.Sprintf("Студент <%d> & co", i),
+			XP:          int64((i * 7919) % 100000),
+			Level:       i % 30,
+			Cohort:      cohort,
+		}
+	}
+	return entries
+}
+
+func TestLeaderboardCache_BackupRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := NewLeaderboardCache(newTestCache(t))
+	target := NewLeaderboardCache(newTestCacheDB(t, 14))
+
+	require.NoError(t, source.RebuildFromSnapshot(ctx, backupEntries(50000, ""), ""))
+	require.NoError(t, source.RebuildFromSnapshot(ctx, backupEntries(300, "2024-fall"), "2024-fall"))
+	require.NoError(t, source.SaveSnapshot(ctx, "daily", backupEntries(10, "2024-fall"), "2024-fall"))
+
+	var backup bytes.Buffer
+	exported, err := source.ExportBackup(ctx, &backup, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []leaderboard.BackupCohort{
+		{Cohort: "2024-fall", Entries: 300, Snapshots: 1},
+		{Cohort: "all", Entries: 50000},
+	}, exported.Cohorts)
+
+	imported, err := target.ImportBackup(ctx, bytes.NewReader(backup.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, exported.Cohorts, imported.Cohorts)
+
+	for _, cohort := range []string{"", "2024-fall"} {
+		want, err := source.GetTop(ctx, 1000, cohort)
+		require.NoError(t, err)
+		got, err := target.GetTop(ctx, 1000, cohort)
+		require.NoError(t, err)
+
+		wantJSON, err := json.Marshal(want)
+		require.NoError(t, err)
+		gotJSON, err := json.Marshal(got)
+		require.NoError(t, err)
+		assert.Equal(t, string(wantJSON), string(gotJSON), "cohort %q", cohort)
+	}
+
+	snapshot, err := target.cache.Client().Get(ctx, keyLeaderboardSnapshot+"2024-fall:daily").Result()
+	require.NoError(t, err)
+	original, err := source.cache.Client().Get(ctx, keyLeaderboardSnapshot+"2024-fall:daily").Result()
+	require.NoError(t, err)
+	assert.Equal(t, original, snapshot)
+
+	// No temporary keys are left behind.
+	keys, err := target.cache.Client().Keys(ctx, "*"+backupTempSuffix+"*").Result()
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestLeaderboardCache_ImportBackupKeepsCohortOnCountMismatch(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLeaderboardCache(newTestCache(t))
+	require.NoError(t, cache.RebuildFromSnapshot(ctx, backupEntries(3, ""), ""))
+
+	var backup bytes.Buffer
+	_, err := cache.ExportBackup(ctx, &backup, []string{""})
+	require.NoError(t, err)
+	before, err := cache.GetTop(ctx, 10, "")
+	require.NoError(t, err)
+
+	// Drop one entry line: the cohort_end count no longer matches.
+	lines := strings.SplitAfter(backup.String(), "\n")
+	truncated := strings.Join(append(lines[:2:2], lines[3:]...), "")
+	require.NoError(t, cache.cache.Client().ZRem(ctx, keyLeaderboardXP+"all", "student-00000").Err())
+
+	_, err = cache.ImportBackup(ctx, strings.NewReader(truncated))
+	require.ErrorIs(t, err, leaderboard.ErrInvalidBackup)
+
+	after, err := cache.GetTop(ctx, 10, "")
+	require.NoError(t, err)
+	assert.Len(t, after, len(before)-1, "the cached cohort is left as it was")
+
+	keys, err := cache.cache.Client().Keys(ctx, "*"+backupTempSuffix+"*").Result()
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestLeaderboardCache_ImportBackupRejectsHeader(t *testing.T) {
+	// The header is checked before Redis is touched.
+	cache := NewLeaderboardCache(nil)
+
+	tests := map[string]string{
+		"empty":         "",
+		"no header":     `{"type":"cohort","cohort":"all"}` + "\n",
+		"wrong version": `{"type":"header","version":99,"cohorts":["all"]}` + "\n",
+		"not json":      "leaderboard\n",
+	}
+	for name, backup := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := cache.ImportBackup(context.Background(), strings.NewReader(backup))
+			assert.ErrorIs(t, err, leaderboard.ErrInvalidBackup)
+		})
+	}
+}
@@ -69,6 +69,15 @@ type LeaderboardEntry struct {
 
 	// LastActiveAt is the last activity timestamp.
 	LastActiveAt time.Time `json:"last_active_at,omitempty"`
+
+	// HiddenFromLeaderboard indicates the student opted out of public listings.
+	HiddenFromLeaderboard bool `json:"hidden_from_leaderboard,omitempty"`
+
+	// HidesOnlineStatus indicates the student hides their online status.
+	HidesOnlineStatus bool `json:"hides_online_status,omitempty"`
+
+	// XPReviewPending indicates a curator has yet to review the student's XP spike.
+	XPReviewPending bool `json:"xp_review_pending,omitempty"`
 }
 
 // LeaderboardPage represents a page of leaderboard entries.
@@ -104,6 +113,8 @@ type NeighborsResult struct {
 //   - Sorted Set "leaderboard:xp:{cohort}" stores studentID -> XP mapping
 //   - Hash "leaderboard:info:{cohort}" stores studentID -> LeaderboardEntry JSON
 //   - String "leaderboard:meta:{cohort}" stores metadata (last update, total count)
+//   - String "leaderboard:weekly:{week}:{cohort}" stores the weekly top as JSON
+//   - Pub/Sub "leaderboard:updates:{cohort}" carries live updates (see leaderboard_updates.go)
 //
 // This design allows O(log N) rank lookups and O(log N + M) range queries.
 type LeaderboardCache struct {
@@ -124,6 +135,9 @@ const (
 	// keyLeaderboardSnapshot is for storing full snapshots.
 	keyLeaderboardSnapshot = "leaderboard:snapshot:"
 
+	// keyLeaderboardWeekly is the weekly top, "leaderboard:weekly:{week}:{cohort}".
+	keyLeaderboardWeekly = "leaderboard:weekly:"
+
 	// defaultCohort is used when no cohort is specified.
 	defaultCohort = "all"
 )
@@ -156,11 +170,13 @@ func (l *LeaderboardCache) UpdateEntry(ctx context.Context, entry LeaderboardEnt
 		cohort = defaultCohort
 	}
 
+	xpKey := keyLeaderboardXP + cohort
+	before := l.readEntryState(ctx, xpKey, entry.StudentID)
+
 	// Use pipeline for atomic update
 	pipe := l.cache.Client().Pipeline()
 
 	// 1. Update XP in sorted set (score = XP)
-	xpKey := keyLeaderboardXP + cohort
 	pipe.ZAdd(ctx, xpKey, redis.Z{
 		Score:  float64(entry.XP),
 		Member: entry.StudentID,
@@ -178,8 +194,12 @@ func (l *LeaderboardCache) UpdateEntry(ctx context.Context, entry LeaderboardEnt
 	pipe.Expire(ctx, xpKey, TTLLeaderboardCache)
 	pipe.Expire(ctx, infoKey, TTLLeaderboardCache)
 
-	_, err = pipe.Exec(ctx)
-	return err
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	l.publishEntryUpdate(ctx, cohort, entry.StudentID, before, entry.XP)
+	return nil
 }
 
 // UpdateEntries updates multiple entries in a batch.
@@ -240,8 +260,13 @@ func (l *LeaderboardCache) UpdateEntries(ctx context.Context, entries []Leaderbo
 	pipe.Expire(ctx, xpKey, TTLLeaderboardCache)
 	pipe.Expire(ctx, infoKey, TTLLeaderboardCache)
 
-	_, err := pipe.Exec(ctx)
-	return err
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	// One message for the whole batch, not one per entry
+	l.publishRebuilt(ctx, cohort, len(zMembers))
+	return nil
 }
 
 // RebuildFromSnapshot rebuilds the cache from a full snapshot.
@@ -261,8 +286,11 @@ func (l *LeaderboardCache) RebuildFromSnapshot(ctx context.Context, entries []Le
 	pipe.Del(ctx, xpKey, infoKey)
 
 	if len(entries) == 0 {
-		_, err := pipe.Exec(ctx)
-		return err
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+		l.publishRebuilt(ctx, cohort, 0)
+		return nil
 	}
 
 	// 2. Insert all entries
@@ -307,8 +335,12 @@ func (l *LeaderboardCache) RebuildFromSnapshot(ctx context.Context, entries []Le
 	pipe.Expire(ctx, xpKey, TTLLeaderboardCache)
 	pipe.Expire(ctx, infoKey, TTLLeaderboardCache)
 
-	_, err := pipe.Exec(ctx)
-	return err
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	l.publishRebuilt(ctx, cohort, len(zMembers))
+	return nil
 }
 
 // RemoveEntry removes a student from the leaderboard.
@@ -342,10 +374,52 @@ func (l *LeaderboardCache) UpdateXP(ctx context.Context, studentID string, newXP
 	}
 
 	xpKey := keyLeaderboardXP + cohort
-	return l.cache.Client().ZAdd(ctx, xpKey, redis.Z{
+	before := l.readEntryState(ctx, xpKey, studentID)
+
+	err := l.cache.Client().ZAdd(ctx, xpKey, redis.Z{
 		Score:  float64(newXP),
 		Member: studentID,
 	}).Err()
+	if err != nil {
+		return err
+	}
+
+	l.publishEntryUpdate(ctx, cohort, studentID, before, newXP)
+	return nil
+}
+
+// RenameEntry rewrites the display name in a student's cached entry so the
+// leaderboard doesn't show a stale name until the next rebuild.
+// A student missing from the cohort is not an error.
+func (l *LeaderboardCache) RenameEntry(ctx context.Context, studentID, displayName string, cohort string) error {
+	if studentID == "" {
+		return ErrStudentIDEmpty
+	}
+	if cohort == "" {
+		cohort = defaultCohort
+	}
+
+	infoKey := keyLeaderboardInfo + cohort
+
+	data, err := l.cache.Client().HGet(ctx, infoKey, studentID).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		return err
+	}
+
+	var entry LeaderboardEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return fmt.Errorf("failed to unmarshal entry: %w", err)
+	}
+	entry.DisplayName = displayName
+
+	data, err = json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal entry: %w", err)
+	}
+	return l.cache.Client().HSet(ctx, infoKey, studentID, data).Err()
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -374,7 +448,7 @@ func (l *LeaderboardCache) GetTop(ctx context.Context, count int, cohort string)
 		return []LeaderboardEntry{}, nil
 	}
 
-	return l.getEntriesWithRanks(ctx, studentIDs, cohort, true)
+	return l.getEntries(ctx, studentIDs, cohort, 1)
 }
 
 // GetPage returns a paginated view of the leaderboard.
@@ -409,7 +483,7 @@ func (l *LeaderboardCache) GetPage(ctx context.Context, page, pageSize int, coho
 		return nil, err
 	}
 
-	entries, err := l.getEntriesWithRanks(ctx, studentIDs, cohort, true)
+	entries, err := l.getEntries(ctx, studentIDs, cohort, start+1)
 	if err != nil {
 		return nil, err
 	}
@@ -449,6 +523,45 @@ func (l *LeaderboardCache) GetRank(ctx context.Context, studentID string, cohort
 	return rank + 1, nil // Convert to 1-based
 }
 
+// GetRanks returns the ranks (1-based) of several students in one round trip.
+// Students missing from the leaderboard are absent from the map.
+func (l *LeaderboardCache) GetRanks(ctx context.Context, studentIDs []string, cohort string) (map[string]int64, error) {
+	ranks := make(map[string]int64, len(studentIDs))
+	if len(studentIDs) == 0 {
+		return ranks, nil
+	}
+	if cohort == "" {
+		cohort = defaultCohort
+	}
+
+	xpKey := keyLeaderboardXP + cohort
+
+	pipe := l.cache.Client().Pipeline()
+	cmds := make([]*redis.IntCmd, len(studentIDs))
+	for i, id := range studentIDs {
+		cmds[i] = pipe.ZRevRank(ctx, xpKey, id)
+	}
+
+	// Exec reports redis.Nil when any member is missing; per-command
+	// results are checked below.
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	for i, cmd := range cmds {
+		rank, err := cmd.Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			return nil, err
+		}
+		ranks[studentIDs[i]] = rank + 1 // Convert to 1-based
+	}
+
+	return ranks, nil
+}
+
 // GetXP returns the XP of a student.
 func (l *LeaderboardCache) GetXP(ctx context.Context, studentID string, cohort string) (int64, error) {
 	if studentID == "" {
@@ -537,7 +650,7 @@ func (l *LeaderboardCache) GetNeighbors(ctx context.Context, studentID string, r
 		return nil, err
 	}
 
-	entries, err := l.getEntriesWithRanks(ctx, studentIDs, cohort, true)
+	entries, err := l.getEntries(ctx, studentIDs, cohort, start+1)
 	if err != nil {
 		return nil, err
 	}
@@ -577,7 +690,7 @@ func (l *LeaderboardCache) GetStudentsInXPRange(ctx context.Context, minXP, maxX
 		return nil, err
 	}
 
-	return l.getEntriesWithRanks(ctx, studentIDs, cohort, false)
+	return l.getEntries(ctx, studentIDs, cohort, 0)
 }
 
 // GetCount returns the total number of entries in the leaderboard.
@@ -764,6 +877,11 @@ func (l *LeaderboardCache) InvalidateAll(ctx context.Context) error {
 	}
 
 	pattern = keyLeaderboardMeta + "*"
+	if err := l.cache.DeleteByPattern(ctx, pattern); err != nil {
+		return err
+	}
+
+	pattern = keyLeaderboardWeekly + "*"
 	return l.cache.DeleteByPattern(ctx, pattern)
 }
 
@@ -790,8 +908,14 @@ func (l *LeaderboardCache) RefreshTTL(ctx context.Context, cohort string, ttl ti
 // HELPER METHODS
 // ══════════════════════════════════════════════════════════════════════════════
 
-// getEntriesWithRanks retrieves entries and populates their ranks.
-func (l *LeaderboardCache) getEntriesWithRanks(ctx context.Context, studentIDs []string, cohort string, calculateRanks bool) ([]LeaderboardEntry, error) {
+// getEntries retrieves the entries of studentIDs in one HMGET.
+//
+// When studentIDs is a contiguous ZRevRange slice, firstRank is the rank of
+// its first member and the others are ranked by their offset from it, with
+// no rank lookups. ZREVRANK orders equal scores the same way ZREVRANGE does
+// (by member, descending), so tied students keep the ranks GetRank reports.
+// A firstRank of 0 leaves ranks unset.
+func (l *LeaderboardCache) getEntries(ctx context.Context, studentIDs []string, cohort string, firstRank int64) ([]LeaderboardEntry, error) {
 	if len(studentIDs) == 0 {
 		return []LeaderboardEntry{}, nil
 	}
@@ -805,31 +929,21 @@ func (l *LeaderboardCache) getEntriesWithRanks(ctx context.Context, studentIDs [
 	}
 
 	entries := make([]LeaderboardEntry, 0, len(studentIDs))
-	validIDs := make([]string, 0, len(studentIDs))
-
 	for i, v := range data {
-		if v == nil {
+		str, ok := v.(string)
+		if !ok {
 			continue
 		}
 
 		var entry LeaderboardEntry
-		if str, ok := v.(string); ok {
-			if err := json.Unmarshal([]byte(str), &entry); err != nil {
-				continue
-			}
-			entries = append(entries, entry)
-			validIDs = append(validIDs, studentIDs[i])
+		if err := json.Unmarshal([]byte(str), &entry); err != nil {
+			continue
 		}
-	}
-
-	// Calculate and set ranks if requested
-	if calculateRanks {
-		for i := range entries {
-			rank, err := l.GetRank(ctx, entries[i].StudentID, cohort)
-			if err == nil {
-				entries[i].Rank = rank
-			}
+		// A member without details still takes its place in the ranking
+		if firstRank > 0 {
+			entry.Rank = firstRank + int64(i)
 		}
+		entries = append(entries, entry)
 	}
 
 	return entries, nil
@@ -872,6 +986,56 @@ func (l *LeaderboardCache) SetCachedTop(ctx context.Context, cohort leaderboard.
 	return nil
 }
 
+// weeklyTop is the cached weekly top of a cohort. Entries may be a prefix of
+// the ranking; Total is the size of the whole ranking.
+type weeklyTop struct {
+	Entries []LeaderboardEntry `json:"entries"`
+	Total   int                `json:"total"`
+}
+
+// weeklyKey returns the key of the weekly top of week for cohort.
+func weeklyKey(week string, cohort leaderboard.Cohort) string {
+	return keyLeaderboardWeekly + week + ":" + cohort.String()
+}
+
+// GetCachedWeeklyTop returns the cached weekly top-N and the ranking size.
+// Returns ErrCacheMiss if the week is not cached or the cached prefix is
+// shorter than limit.
+func (l *LeaderboardCache) GetCachedWeeklyTop(ctx context.Context, week string, cohort leaderboard.Cohort, limit int) ([]*leaderboard.LeaderboardEntry, int, error) {
+	var top weeklyTop
+	if err := l.cache.Get(ctx, weeklyKey(week, cohort), &top); err != nil {
+		return nil, 0, err
+	}
+	if len(top.Entries) < limit && len(top.Entries) < top.Total {
+		return nil, 0, ErrCacheMiss
+	}
+
+	entries := top.Entries
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	domainEntries := make([]*leaderboard.LeaderboardEntry, len(entries))
+	for i := range entries {
+		domainEntries[i] = l.toDomainEntry(&entries[i])
+	}
+	return domainEntries, top.Total, nil
+}
+
+// SetCachedWeeklyTop saves the weekly top of week for ttl
+// (TTLLeaderboardCache if not positive). Past weeks are never read again
+// and just expire.
+func (l *LeaderboardCache) SetCachedWeeklyTop(ctx context.Context, week string, cohort leaderboard.Cohort, entries []*leaderboard.LeaderboardEntry, total int, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = TTLLeaderboardCache
+	}
+
+	top := weeklyTop{Entries: make([]LeaderboardEntry, len(entries)), Total: total}
+	for i, e := range entries {
+		top.Entries[i] = l.fromDomainEntry(e)
+	}
+	return l.cache.Set(ctx, weeklyKey(week, cohort), top, ttl)
+}
+
 // GetCachedRank returns cached rank for a student using domain types.
 func (l *LeaderboardCache) GetCachedRank(ctx context.Context, studentID string, cohort leaderboard.Cohort) (*leaderboard.LeaderboardEntry, error) {
 	entry, err := l.GetEntry(ctx, studentID, string(cohort))
@@ -884,6 +1048,20 @@ func (l *LeaderboardCache) GetCachedRank(ctx context.Context, studentID string,
 	return l.toDomainEntry(entry), nil
 }
 
+// GetCachedRanks returns cached ranks of several students using domain types.
+func (l *LeaderboardCache) GetCachedRanks(ctx context.Context, studentIDs []string, cohort leaderboard.Cohort) (map[string]leaderboard.Rank, error) {
+	ranks, err := l.GetRanks(ctx, studentIDs, string(cohort))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]leaderboard.Rank, len(ranks))
+	for id, rank := range ranks {
+		result[id] = leaderboard.Rank(rank)
+	}
+	return result, nil
+}
+
 // SetCachedRank saves a single student rank to cache.
 func (l *LeaderboardCache) SetCachedRank(ctx context.Context, entry *leaderboard.LeaderboardEntry, ttl time.Duration) error {
 	if entry == nil {
@@ -929,6 +1107,10 @@ func (l *LeaderboardCache) toDomainEntry(e *LeaderboardEntry) *leaderboard.Leade
 		IsAvailableForHelp: e.IsAvailableForHelp,
 		HelpRating:         e.HelpRating,
 		UpdatedAt:          e.LastActiveAt,
+
+		HiddenFromLeaderboard: e.HiddenFromLeaderboard,
+		HidesOnlineStatus:     e.HidesOnlineStatus,
+		XPReviewPending:       e.XPReviewPending,
 	}
 }
 
@@ -949,6 +1131,10 @@ func (l *LeaderboardCache) fromDomainEntry(e *leaderboard.LeaderboardEntry) Lead
 		IsAvailableForHelp: e.IsAvailableForHelp,
 		HelpRating:         e.HelpRating,
 		LastActiveAt:       e.UpdatedAt,
+
+		HiddenFromLeaderboard: e.HiddenFromLeaderboard,
+		HidesOnlineStatus:     e.HidesOnlineStatus,
+		XPReviewPending:       e.XPReviewPending,
 	}
 }
 
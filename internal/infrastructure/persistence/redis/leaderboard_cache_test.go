@@ -0,0 +1,96 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tiedEntries returns n entries where every three students share the same XP.
+func tiedEntries(n int) []LeaderboardEntry {
+	entries := make([]LeaderboardEntry, n)
+	for i := range entries {
+		entries[i] = LeaderboardEntry{
+			StudentID:   fmt.Sprintf("student-%04d", i),
+			DisplayName: fmt.Sprintf("Student %d", i),
+			XP:          int64(10000 - (i/3)*10),
+		}
+	}
+	return entries
+}
+
+func TestLeaderboardCache_RangeRanksMatchRevRank(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLeaderboardCache(newTestCache(t))
+	require.NoError(t, cache.RebuildFromSnapshot(ctx, tiedEntries(40), ""))
+
+	assertRanks := func(t *testing.T, entries []LeaderboardEntry) {
+		t.Helper()
+		ids := make([]string, len(entries))
+		for i, e := range entries {
+			ids[i] = e.StudentID
+		}
+		want, err := cache.GetRanks(ctx, ids, "")
+		require.NoError(t, err)
+		for _, e := range entries {
+			assert.Equal(t, want[e.StudentID], e.Rank, e.StudentID)
+		}
+	}
+
+	top, err := cache.GetTop(ctx, 10, "")
+	require.NoError(t, err)
+	require.Len(t, top, 10)
+	assert.Equal(t, int64(1), top[0].Rank)
+	assertRanks(t, top)
+
+	page, err := cache.GetPage(ctx, 3, 7, "")
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 7)
+	assert.Equal(t, int64(15), page.Entries[0].Rank)
+	assertRanks(t, page.Entries)
+
+	neighbors, err := cache.GetNeighbors(ctx, "student-0020", 2, "")
+	require.NoError(t, err)
+	require.NotNil(t, neighbors.Current)
+	assertRanks(t, append(append(neighbors.Above, *neighbors.Current), neighbors.Below...))
+	assert.Len(t, neighbors.Above, 2)
+	assert.Len(t, neighbors.Below, 2)
+
+	// Ranks of tied students do not change between reads
+	again, err := cache.GetTop(ctx, 10, "")
+	require.NoError(t, err)
+	assert.Equal(t, top, again)
+}
+
+// BenchmarkLeaderboardCache_GetTop compares ranking a top-100 page with a
+// pipelined ZREVRANK per entry (the previous approach) against taking the
+// ranks from the ZREVRANGE offsets. Needs REDIS_TEST_ADDR.
+func BenchmarkLeaderboardCache_GetTop(b *testing.B) {
+	ctx := context.Background()
+	cache := NewLeaderboardCache(newTestCache(b))
+	require.NoError(b, cache.RebuildFromSnapshot(ctx, tiedEntries(5000), ""))
+
+	b.Run("pipelined_revrank", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ids, err := cache.cache.Client().ZRevRange(ctx, keyLeaderboardXP+defaultCohort, 0, 99).Result()
+			require.NoError(b, err)
+			entries, err := cache.getEntries(ctx, ids, defaultCohort, 0)
+			require.NoError(b, err)
+			ranks, err := cache.GetRanks(ctx, ids, defaultCohort)
+			require.NoError(b, err)
+			for j := range entries {
+				entries[j].Rank = ranks[entries[j].StudentID]
+			}
+		}
+	})
+
+	b.Run("range_offsets", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, err := cache.GetTop(ctx, 100, "")
+			require.NoError(b, err)
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/redis/go-redis/v9"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// LEADERBOARD LIVE UPDATES
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// channelLeaderboardUpdates is the Pub/Sub channel prefix for leaderboard
+	// updates; the full channel is "leaderboard:updates:{cohort}".
+	channelLeaderboardUpdates = "leaderboard:updates:"
+
+	// updatesBufferSize is the buffer of a subscriber's channel.
+	updatesBufferSize = 64
+)
+
+// LeaderboardUpdatesChannel returns the Pub/Sub channel of a cohort.
+func LeaderboardUpdatesChannel(cohort string) string {
+	if cohort == "" {
+		cohort = defaultCohort
+	}
+	return channelLeaderboardUpdates + cohort
+}
+
+// entryState is a student's position before a write.
+type entryState struct {
+	xp      int64
+	rank    int64 // 1-based, 0 if not in the leaderboard
+	present bool
+}
+
+// readEntryState reads a student's XP and rank in one round trip.
+func (l *LeaderboardCache) readEntryState(ctx context.Context, xpKey, studentID string) entryState {
+	pipe := l.cache.Client().Pipeline()
+	scoreCmd := pipe.ZScore(ctx, xpKey, studentID)
+	rankCmd := pipe.ZRevRank(ctx, xpKey, studentID)
+	_, _ = pipe.Exec(ctx) // redis.Nil for absent students is handled below
+
+	score, err := scoreCmd.Result()
+	if err != nil {
+		return entryState{}
+	}
+	rank, err := rankCmd.Result()
+	if err != nil {
+		return entryState{}
+	}
+	return entryState{xp: int64(score), rank: rank + 1, present: true}
+}
+
+// publishEntryUpdate publishes an entry update if the student's XP or rank
+// band changed. Publishing is best effort: a failure never fails the write.
+func (l *LeaderboardCache) publishEntryUpdate(ctx context.Context, cohort, studentID string, before entryState, newXP int64) {
+	var rank int64
+	if r, err := l.cache.Client().ZRevRank(ctx, keyLeaderboardXP+cohort, studentID).Result(); err == nil {
+		rank = r + 1
+	}
+
+	bandChanged := leaderboard.Rank(before.rank).Band() != leaderboard.Rank(rank).Band()
+	if before.present && before.xp == newXP && !bandChanged {
+		return
+	}
+
+	l.publishUpdate(ctx, leaderboard.Update{
+		Kind:      leaderboard.UpdateEntry,
+		Cohort:    cohort,
+		StudentID: studentID,
+		XP:        newXP,
+		Rank:      rank,
+		OldRank:   before.rank,
+		At:        time.Now().UTC(),
+	})
+}
+
+// publishRebuilt publishes a single message for a bulk write instead of one
+// per entry.
+func (l *LeaderboardCache) publishRebuilt(ctx context.Context, cohort string, count int) {
+	l.publishUpdate(ctx, leaderboard.Update{
+		Kind:   leaderboard.UpdateRebuilt,
+		Cohort: cohort,
+		Count:  count,
+		At:     time.Now().UTC(),
+	})
+}
+
+func (l *LeaderboardCache) publishUpdate(ctx context.Context, update leaderboard.Update) {
+	// Fire and forget - live updates must not fail leaderboard writes
+	_ = l.cache.Publish(ctx, LeaderboardUpdatesChannel(update.Cohort), update)
+}
+
+// SubscribeUpdates streams leaderboard updates of a cohort ("" for all
+// cohorts). The subscription is re-established after a connection loss, and
+// an UpdateResync is sent then, since updates published meanwhile are lost.
+// The channel is closed when ctx is done.
+func (l *LeaderboardCache) SubscribeUpdates(ctx context.Context, cohort string) <-chan leaderboard.Update {
+	out := make(chan leaderboard.Update, updatesBufferSize)
+	go l.runSubscription(ctx, cohort, out)
+	return out
+}
+
+// runSubscription keeps a subscription alive until ctx is done.
+func (l *LeaderboardCache) runSubscription(ctx context.Context, cohort string, out chan<- leaderboard.Update) {
+	defer close(out)
+
+	subscribe := func() *redis.PubSub {
+		if cohort == "" {
+			return l.cache.PSubscribe(ctx, channelLeaderboardUpdates+"*")
+		}
+		return l.cache.Subscribe(ctx, LeaderboardUpdatesChannel(cohort))
+	}
+	send := func(update leaderboard.Update) bool {
+		select {
+		case out <- update:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	keepSubscribed(ctx, subscribe,
+		func() bool {
+			return send(leaderboard.Update{Kind: leaderboard.UpdateResync, Cohort: cohort, At: time.Now().UTC()})
+		},
+		func(msg *redis.Message) bool {
+			var update leaderboard.Update
+			if err := json.Unmarshal([]byte(msg.Payload), &update); err != nil {
+				return true // Skip malformed messages
+			}
+			return send(update)
+		},
+	)
+}
+
+var _ leaderboard.UpdateSubscriber = (*LeaderboardCache)(nil)
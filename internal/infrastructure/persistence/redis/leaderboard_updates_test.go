@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+)
+
+// newTestCache connects to the Redis at REDIS_TEST_ADDR (host:port, DB 15)
+// and skips the test if it is not set.
+func newTestCache(t testing.TB) *Cache {
+	t.Helper()
+	return newTestCacheDB(t, 15)
+}
+
+// newTestCacheDB connects to a database of the test Redis and empties it.
+func newTestCacheDB(t testing.TB, db int) *Cache {
+	t.Helper()
+	addr := os.Getenv("REDIS_TEST_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_TEST_ADDR not set")
+	}
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+
+	cfg := DefaultConfig()
+	cfg.Host = host
+	cfg.Port, err = strconv.Atoi(port)
+	require.NoError(t, err)
+	cfg.DB = db
+
+	cache, err := NewCache(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = cache.FlushDB(context.Background())
+		_ = cache.Close()
+	})
+	require.NoError(t, cache.FlushDB(context.Background()))
+	return cache
+}
+
+func TestLeaderboardCache_PublishesUpdates(t *testing.T) {
+	lb := NewLeaderboardCache(newTestCache(t))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	updates := lb.SubscribeUpdates(ctx, "2025-spring")
+
+	next := func() leaderboard.Update {
+		t.Helper()
+		select {
+		case u := <-updates:
+			return u
+		case <-ctx.Done():
+			t.Fatal("no update received")
+			return leaderboard.Update{}
+		}
+	}
+
+	// Give the subscription time to be confirmed before publishing
+	time.Sleep(100 * time.Millisecond)
+
+	require.NoError(t, lb.UpdateXP(ctx, "dana", 1200, "2025-spring"))
+	u := next()
+	assert.Equal(t, leaderboard.UpdateEntry, u.Kind)
+	assert.Equal(t, "dana", u.StudentID)
+	assert.Equal(t, int64(1200), u.XP)
+	assert.Equal(t, int64(1), u.Rank)
+	assert.Zero(t, u.OldRank)
+
+	// Same XP and band: nothing is published. Another cohort is not received.
+	require.NoError(t, lb.UpdateXP(ctx, "dana", 1200, "2025-spring"))
+	require.NoError(t, lb.UpdateXP(ctx, "ali", 500, "2025-autumn"))
+
+	// A bulk rebuild is a single message
+	require.NoError(t, lb.RebuildFromSnapshot(ctx, []LeaderboardEntry{
+		{StudentID: "dana", XP: 1300},
+		{StudentID: "ali", XP: 900},
+		{StudentID: "arman", XP: 100},
+	}, "2025-spring"))
+	u = next()
+	assert.Equal(t, leaderboard.UpdateRebuilt, u.Kind)
+	assert.Equal(t, 3, u.Count)
+
+	select {
+	case extra := <-updates:
+		t.Fatalf("unexpected update: %+v", extra)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
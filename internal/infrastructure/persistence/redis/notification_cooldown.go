@@ -0,0 +1,28 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// NotificationCooldown implements notification.CooldownStore with SET NX:
+// the first caller takes the key for ttl, the rest are rejected until it expires.
+// Shared through Redis, so it also holds across worker restarts and replicas.
+type NotificationCooldown struct {
+	cache *Cache
+}
+
+// NewNotificationCooldown creates a new NotificationCooldown.
+func NewNotificationCooldown(cache *Cache) *NotificationCooldown {
+	return &NotificationCooldown{cache: cache}
+}
+
+// Acquire takes the cooldown key for ttl. Returns false if it is already taken.
+func (c *NotificationCooldown) Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	ok, err := c.cache.SetNX(ctx, PrefixCooldown+key, time.Now().UTC().Unix(), ttl)
+	if err != nil {
+		return false, fmt.Errorf("notification_cooldown: acquire: %w", err)
+	}
+	return ok, nil
+}
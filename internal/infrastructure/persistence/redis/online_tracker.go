@@ -40,6 +40,10 @@ const (
 
 	// StateOffline indicates the student is offline (last seen > 30 min ago or never).
 	StateOffline OnlineState = "offline"
+
+	// StateUnknown is reported while the cache is unavailable: the student
+	// may be online, but there is no way to tell.
+	StateUnknown OnlineState = "unknown"
 )
 
 // IsValid checks if the online state is valid.
@@ -64,6 +68,8 @@ func (s OnlineState) Emoji() string {
 		return "🟢"
 	case StateAway:
 		return "🟡"
+	case StateUnknown:
+		return "❔"
 	default:
 		return "⚪"
 	}
@@ -277,6 +283,9 @@ func (t *OnlineTracker) Heartbeat(ctx context.Context, studentID string) error {
 				State:      StateOnline,
 				LastSeenAt: now,
 			}
+		} else if errors.Is(err, ErrCacheUnavailable) {
+			// The write would be dropped anyway
+			return nil
 		} else {
 			return err
 		}
@@ -410,12 +419,16 @@ func (t *OnlineTracker) GetInfo(ctx context.Context, studentID string) (*OnlineI
 }
 
 // GetState returns just the online state for a student.
+// While the cache is unavailable it returns StateUnknown without an error.
 func (t *OnlineTracker) GetState(ctx context.Context, studentID string) (OnlineState, error) {
 	info, err := t.GetInfo(ctx, studentID)
 	if err != nil {
 		if errors.Is(err, ErrCacheMiss) {
 			return StateOffline, nil
 		}
+		if errors.Is(err, ErrCacheUnavailable) {
+			return StateUnknown, nil
+		}
 		return StateOffline, err
 	}
 	return info.CalculateState(), nil
@@ -449,6 +462,9 @@ func (t *OnlineTracker) GetAllOnline(ctx context.Context) ([]OnlineInfo, error)
 		Min: strconv.FormatInt(cutoff, 10),
 		Max: "+inf",
 	}).Result()
+	if errors.Is(err, ErrCacheUnavailable) {
+		return []OnlineInfo{}, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get online students: %w", err)
 	}
@@ -469,6 +485,9 @@ func (t *OnlineTracker) GetAllAvailable(ctx context.Context) ([]OnlineInfo, erro
 		Min: strconv.FormatInt(cutoff, 10),
 		Max: "+inf",
 	}).Result()
+	if errors.Is(err, ErrCacheUnavailable) {
+		return []OnlineInfo{}, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get available students: %w", err)
 	}
@@ -547,7 +566,8 @@ func (t *OnlineTracker) GetRecentlyOnline(ctx context.Context, within time.Durat
 // BATCH OPERATIONS
 // ══════════════════════════════════════════════════════════════════════════════
 
-// GetStates returns online states for multiple students.
+// GetStates returns online states for multiple students. While the cache is
+// unavailable every student is StateUnknown.
 func (t *OnlineTracker) GetStates(ctx context.Context, studentIDs []string) (map[string]OnlineState, error) {
 	if len(studentIDs) == 0 {
 		return make(map[string]OnlineState), nil
@@ -569,6 +589,10 @@ func (t *OnlineTracker) GetStates(ctx context.Context, studentIDs []string) (map
 	now := time.Now()
 	for id, cmd := range cmds {
 		data, err := cmd.Bytes()
+		if errors.Is(err, ErrCacheUnavailable) {
+			result[id] = StateUnknown
+			continue
+		}
 		if err != nil {
 			result[id] = StateOffline
 			continue
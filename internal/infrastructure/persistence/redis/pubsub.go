@@ -0,0 +1,164 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/messaging"
+	"github.com/redis/go-redis/v9"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PUB/SUB SUBSCRIPTIONS
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// subscribeMinBackoff and subscribeMaxBackoff bound the delay between
+	// resubscription attempts after a connection loss.
+	subscribeMinBackoff = 500 * time.Millisecond
+	subscribeMaxBackoff = 30 * time.Second
+
+	// subscriptionHealthCheck is how long a subscription may stay silent
+	// before the connection is pinged.
+	subscriptionHealthCheck = 30 * time.Second
+)
+
+// keepSubscribed keeps a subscription alive until ctx is done. subscribe
+// opens the subscription; resubscribed is called after every reconnect,
+// since messages published meanwhile are lost, and deliver for every
+// message. Either returns false to stop.
+func keepSubscribed(
+	ctx context.Context,
+	subscribe func() *redis.PubSub,
+	resubscribed func() bool,
+	deliver func(msg *redis.Message) bool,
+) {
+	backoff := subscribeMinBackoff
+	subscribed := false
+	for ctx.Err() == nil {
+		pubsub := subscribe()
+
+		// Wait for the subscription confirmation
+		if _, err := pubsub.Receive(ctx); err != nil {
+			_ = pubsub.Close()
+			if !sleepContext(ctx, backoff) {
+				return
+			}
+			backoff = min(backoff*2, subscribeMaxBackoff)
+			continue
+		}
+		backoff = subscribeMinBackoff
+
+		if subscribed && !resubscribed() {
+			_ = pubsub.Close()
+			return
+		}
+		subscribed = true
+
+		_ = relayMessages(ctx, pubsub, deliver)
+		_ = pubsub.Close()
+	}
+}
+
+// relayMessages forwards messages until the connection fails, deliver
+// returns false or ctx is done.
+func relayMessages(ctx context.Context, pubsub *redis.PubSub, deliver func(msg *redis.Message) bool) error {
+	for {
+		msg, err := pubsub.ReceiveTimeout(ctx, subscriptionHealthCheck)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() && ctx.Err() == nil {
+				// Silent channel: make sure the connection is still alive
+				if err := pubsub.Ping(ctx); err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+
+		m, ok := msg.(*redis.Message)
+		if !ok {
+			continue // subscription confirmations and pongs
+		}
+		if !deliver(m) {
+			return ctx.Err()
+		}
+	}
+}
+
+// sleepContext waits for d; returns false if ctx is done first.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MESSAGING ADAPTER
+// ══════════════════════════════════════════════════════════════════════════════
+
+// PubSubClient implements messaging.RedisClient on top of Cache. Subscriptions
+// survive connection losses; after a reconnect messaging.ErrMessagesLost is
+// delivered, since messages published meanwhile are gone.
+type PubSubClient struct {
+	cache *Cache
+}
+
+// NewPubSubClient creates a new PubSubClient.
+func NewPubSubClient(cache *Cache) *PubSubClient {
+	return &PubSubClient{cache: cache}
+}
+
+// Publish publishes a message. Strings and byte slices are sent as is,
+// other values as JSON.
+func (c *PubSubClient) Publish(ctx context.Context, channel string, message interface{}) error {
+	switch m := message.(type) {
+	case string, []byte:
+		return c.cache.Client().Publish(ctx, channel, m).Err()
+	default:
+		return c.cache.Publish(ctx, channel, message)
+	}
+}
+
+// Subscribe subscribes to channels until ctx is done; the returned channel
+// is closed then.
+func (c *PubSubClient) Subscribe(ctx context.Context, channels ...string) (<-chan messaging.RedisMessage, error) {
+	out := make(chan messaging.RedisMessage, updatesBufferSize)
+	send := func(msg messaging.RedisMessage) bool {
+		select {
+		case out <- msg:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer close(out)
+		keepSubscribed(ctx,
+			func() *redis.PubSub { return c.cache.Subscribe(ctx, channels...) },
+			func() bool { return send(messaging.RedisMessage{Err: messaging.ErrMessagesLost}) },
+			func(msg *redis.Message) bool {
+				return send(messaging.RedisMessage{Channel: msg.Channel, Payload: msg.Payload})
+			},
+		)
+	}()
+
+	return out, nil
+}
+
+// Close does nothing: the underlying Cache is closed by its owner.
+func (c *PubSubClient) Close() error {
+	return nil
+}
+
+var _ messaging.RedisClient = (*PubSubClient)(nil)
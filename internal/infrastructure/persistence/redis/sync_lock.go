@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultSyncLockTTL bounds how long a crashed sync can hold a student.
+const DefaultSyncLockTTL = 2 * time.Minute
+
+// SyncLock implements student.SyncLock with SET NX, so the worker's batch
+// sync and the bot's /sync_me never sync the same student at once.
+type SyncLock struct {
+	cache *Cache
+	ttl   time.Duration
+}
+
+// NewSyncLock creates a new SyncLock.
+func NewSyncLock(cache *Cache) *SyncLock {
+	return &SyncLock{cache: cache, ttl: DefaultSyncLockTTL}
+}
+
+// TryLock takes the lock of a student. Returns false if it is already taken.
+func (l *SyncLock) TryLock(ctx context.Context, studentID string) (bool, error) {
+	ok, err := l.cache.SetNX(ctx, syncLockKey(studentID), time.Now().UTC().Unix(), l.ttl)
+	if err != nil {
+		return false, fmt.Errorf("sync_lock: lock: %w", err)
+	}
+	return ok, nil
+}
+
+// Unlock releases the lock of a student.
+func (l *SyncLock) Unlock(ctx context.Context, studentID string) error {
+	if err := l.cache.Delete(ctx, syncLockKey(studentID)); err != nil {
+		return fmt.Errorf("sync_lock: unlock: %w", err)
+	}
+	return nil
+}
+
+// IsLocked reports whether a student is being synced.
+func (l *SyncLock) IsLocked(ctx context.Context, studentID string) (bool, error) {
+	ok, err := l.cache.Exists(ctx, syncLockKey(studentID))
+	if err != nil {
+		return false, fmt.Errorf("sync_lock: check: %w", err)
+	}
+	return ok, nil
+}
+
+// syncLockKey returns the key of a student's sync lock.
+func syncLockKey(studentID string) string {
+	return PrefixLock + "sync:" + studentID
+}
@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/analytics"
+)
+
+// usageFieldSeparator joins command and cohort into a hash field.
+// Neither command names nor cohorts contain it.
+const usageFieldSeparator = "|"
+
+// UsageCounter implements analytics.UsageCounter with one hash per day:
+// HINCRBY usage:commands:<date> <command>|<cohort> 1.
+type UsageCounter struct {
+	cache *Cache
+}
+
+// NewUsageCounter creates a new UsageCounter.
+func NewUsageCounter(cache *Cache) *UsageCounter {
+	return &UsageCounter{cache: cache}
+}
+
+// Increment increments the day's counter of a command for a cohort.
+func (u *UsageCounter) Increment(ctx context.Context, day time.Time, command, cohort string) error {
+	key := UsageKey(day)
+
+	pipe := u.cache.client.Pipeline()
+	pipe.HIncrBy(ctx, key, command+usageFieldSeparator+cohort, 1)
+	pipe.Expire(ctx, key, TTLUsageCounters)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("usage_counter: increment: %w", err)
+	}
+
+	return nil
+}
+
+// GetDay returns all counters of a day.
+func (u *UsageCounter) GetDay(ctx context.Context, day time.Time) ([]analytics.CommandUsage, error) {
+	fields, err := u.cache.HGetAll(ctx, UsageKey(day))
+	if err != nil {
+		return nil, fmt.Errorf("usage_counter: get day: %w", err)
+	}
+
+	usage := make([]analytics.CommandUsage, 0, len(fields))
+	for field, value := range fields {
+		command, cohort, ok := strings.Cut(field, usageFieldSeparator)
+		if !ok {
+			continue
+		}
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		usage = append(usage, analytics.CommandUsage{
+			Day:     analytics.DayOf(day),
+			Command: command,
+			Cohort:  cohort,
+			Count:   count,
+		})
+	}
+
+	return usage, nil
+}
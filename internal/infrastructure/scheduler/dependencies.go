@@ -0,0 +1,267 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// JOB DEPENDENCIES
+// ══════════════════════════════════════════════════════════════════════════════
+
+// DependencyPolicy defines what a job does when an upstream job has no
+// successful run within the freshness window.
+type DependencyPolicy string
+
+const (
+	// DependencyWait waits until the upstream succeeds, up to WaitTimeout,
+	// and skips the run if it is still stale.
+	DependencyWait DependencyPolicy = "wait"
+
+	// DependencyRunStale runs anyway with a "stale upstream" warning.
+	DependencyRunStale DependencyPolicy = "run_stale"
+
+	// DependencySkip skips the run until the next scheduled time.
+	DependencySkip DependencyPolicy = "skip"
+)
+
+// DefaultDependencyWaitTimeout bounds how long a DependencyWait job waits.
+const DefaultDependencyWaitTimeout = 10 * time.Minute
+
+// Dependency declares that a job needs a recent successful run of another job.
+type Dependency struct {
+	// Job is the name of the upstream job.
+	Job string
+
+	// MaxAge is the freshness window: the last successful upstream run must
+	// have completed no longer than MaxAge ago.
+	MaxAge time.Duration
+
+	// Policy applies when the upstream is stale (default: DependencyWait).
+	Policy DependencyPolicy
+
+	// WaitTimeout bounds DependencyWait (default: DefaultDependencyWaitTimeout).
+	WaitTimeout time.Duration
+}
+
+// withDefaults fills in the policy and the wait timeout.
+func (d Dependency) withDefaults() Dependency {
+	if d.Policy == "" {
+		d.Policy = DependencyWait
+	}
+	if d.Policy == DependencyWait && d.WaitTimeout <= 0 {
+		d.WaitTimeout = DefaultDependencyWaitTimeout
+	}
+	return d
+}
+
+// validate checks the dependency after defaults are applied.
+func (d Dependency) validate() error {
+	if d.Job == "" || d.MaxAge <= 0 {
+		return fmt.Errorf("%w: upstream job and max age are required", ErrInvalidDependency)
+	}
+	switch d.Policy {
+	case DependencyWait, DependencyRunStale, DependencySkip:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown policy %q", ErrInvalidDependency, d.Policy)
+	}
+}
+
+// DependsOn declares that jobName needs fresh runs of the upstream jobs.
+// Both jobs must be registered. A dependency that would close a cycle is
+// rejected with ErrDependencyCycle and nothing is added. Declaring the same
+// upstream again replaces its settings.
+//
+// Dependencies are checked before scheduled runs only; RunNow runs the job
+// immediately as requested.
+func (s *Scheduler) DependsOn(jobName string, deps ...Dependency) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[jobName]; !exists {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, jobName)
+	}
+
+	normalized := make([]Dependency, 0, len(deps))
+	for _, dep := range deps {
+		dep = dep.withDefaults()
+		if err := dep.validate(); err != nil {
+			return err
+		}
+		if _, exists := s.jobs[dep.Job]; !exists {
+			return fmt.Errorf("%w: %s", ErrJobNotFound, dep.Job)
+		}
+		if path := s.dependencyPath(dep.Job, jobName); path != nil {
+			cycle := append([]string{jobName}, path...)
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(cycle, " -> "))
+		}
+		normalized = append(normalized, dep)
+	}
+
+	for _, dep := range normalized {
+		s.setDependency(jobName, dep)
+		s.logger.Info("job dependency added",
+			"job", jobName,
+			"upstream", dep.Job,
+			"max_age", dep.MaxAge.String(),
+			"policy", string(dep.Policy),
+		)
+	}
+
+	return nil
+}
+
+// setDependency adds dep to jobName or replaces the one on the same upstream.
+// Caller must hold s.mu.
+func (s *Scheduler) setDependency(jobName string, dep Dependency) {
+	deps := s.dependencies[jobName]
+	for i := range deps {
+		if deps[i].Job == dep.Job {
+			deps[i] = dep
+			return
+		}
+	}
+	s.dependencies[jobName] = append(deps, dep)
+}
+
+// dependencyPath returns the chain of jobs from one job to another along
+// dependency edges, or nil if to is not reachable. Caller must hold s.mu.
+func (s *Scheduler) dependencyPath(from, to string) []string {
+	visited := make(map[string]bool)
+
+	var walk func(name string) []string
+	walk = func(name string) []string {
+		if name == to {
+			return []string{name}
+		}
+		if visited[name] {
+			return nil
+		}
+		visited[name] = true
+
+		for _, dep := range s.dependencies[name] {
+			if path := walk(dep.Job); path != nil {
+				return append([]string{name}, path...)
+			}
+		}
+		return nil
+	}
+
+	return walk(from)
+}
+
+// DependencyGraph returns the upstream job names of every job that has
+// dependencies.
+func (s *Scheduler) DependencyGraph() map[string][]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	graph := make(map[string][]string, len(s.dependencies))
+	for name, deps := range s.dependencies {
+		upstreams := make([]string, 0, len(deps))
+		for _, dep := range deps {
+			upstreams = append(upstreams, dep.Job)
+		}
+		graph[name] = upstreams
+	}
+	return graph
+}
+
+// checkDependencies decides whether a scheduled run may start. It returns the
+// upstream jobs the run proceeds without (DependencyRunStale) or a non-empty
+// skip reason.
+func (s *Scheduler) checkDependencies(ctx context.Context, jobName string) (stale []string, skipReason string) {
+	s.mu.RLock()
+	deps := append([]Dependency(nil), s.dependencies[jobName]...)
+	s.mu.RUnlock()
+
+	for _, dep := range deps {
+		reason := s.upstreamStaleness(dep)
+		if reason == "" {
+			continue
+		}
+
+		switch dep.Policy {
+		case DependencySkip:
+			return stale, reason
+		case DependencyRunStale:
+			s.logger.Warn("running job with stale upstream",
+				"job", jobName,
+				"upstream", dep.Job,
+				"reason", reason,
+			)
+			stale = append(stale, dep.Job)
+		default:
+			s.logger.Info("job waiting for upstream", "job", jobName, "upstream", dep.Job, "reason", reason)
+			if reason := s.waitForUpstream(ctx, dep); reason != "" {
+				return stale, reason
+			}
+		}
+	}
+
+	return stale, ""
+}
+
+// upstreamStaleness returns why the upstream is stale, or "" if it is fresh.
+func (s *Scheduler) upstreamStaleness(dep Dependency) string {
+	s.mu.RLock()
+	last, ok := s.lastSuccess[dep.Job]
+	s.mu.RUnlock()
+
+	if !ok {
+		return fmt.Sprintf("upstream %s has not succeeded yet", dep.Job)
+	}
+	if age := time.Since(last); age > dep.MaxAge {
+		return fmt.Sprintf("upstream %s last succeeded %s ago (max %s)",
+			dep.Job, age.Round(time.Second), dep.MaxAge)
+	}
+	return ""
+}
+
+// waitForUpstream polls until the upstream is fresh. It returns "" once it
+// is, or the skip reason after WaitTimeout or when the scheduler stops.
+func (s *Scheduler) waitForUpstream(ctx context.Context, dep Dependency) string {
+	timer := time.NewTimer(dep.WaitTimeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(s.dependencyPoll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Sprintf("scheduler stopped while waiting for upstream %s", dep.Job)
+		case <-timer.C:
+			return fmt.Sprintf("%s after waiting %s", s.upstreamStaleness(dep), dep.WaitTimeout)
+		case <-ticker.C:
+			if s.upstreamStaleness(dep) == "" {
+				return ""
+			}
+		}
+	}
+}
+
+// recordSkip records a scheduled run that did not start because of its
+// dependencies. Skips are not failures and are not counted in metrics.
+func (s *Scheduler) recordSkip(sj *scheduledJob, at time.Time, reason string) {
+	jobName := sj.job.Name()
+	result := JobResult{
+		JobName:     jobName,
+		StartedAt:   at,
+		CompletedAt: time.Now(),
+		Skipped:     true,
+		SkipReason:  reason,
+		Metadata:    make(map[string]interface{}),
+	}
+
+	s.mu.Lock()
+	sj.skipCount++
+	sj.lastSkipReason = reason
+	s.lastRuns[jobName] = &result
+	s.addToHistory(result)
+	s.mu.Unlock()
+
+	s.logger.Warn("job skipped", "job", jobName, "reason", reason)
+}
@@ -0,0 +1,167 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingJob counts its runs.
+type countingJob struct {
+	name string
+	runs atomic.Int32
+}
+
+func (j *countingJob) Name() string                  { return j.name }
+func (j *countingJob) Description() string           { return "test job " + j.name }
+func (j *countingJob) Run(ctx context.Context) error { j.runs.Add(1); return nil }
+
+// newTestScheduler registers the jobs on a scheduler that is ready to run
+// them via runScheduled without the tick loop.
+func newTestScheduler(t *testing.T, jobs ...*countingJob) *Scheduler {
+	t.Helper()
+
+	config := DefaultSchedulerConfig()
+	config.DependencyPollInterval = 5 * time.Millisecond
+	s := NewScheduler(config)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	t.Cleanup(s.cancel)
+
+	for _, job := range jobs {
+		require.NoError(t, s.Register(job, NewIntervalSchedule(time.Hour)))
+	}
+	return s
+}
+
+// runScheduled runs a job as the tick loop would.
+func runScheduled(s *Scheduler, name string) {
+	s.mu.RLock()
+	sj := s.jobs[name]
+	s.mu.RUnlock()
+
+	s.wg.Add(1)
+	s.runJob(sj)
+}
+
+func TestDependsOn_RejectsCycles(t *testing.T) {
+	sync := &countingJob{name: "sync"}
+	rebuild := &countingJob{name: "rebuild"}
+	digest := &countingJob{name: "digest"}
+	s := newTestScheduler(t, sync, rebuild, digest)
+
+	require.NoError(t, s.DependsOn("rebuild", Dependency{Job: "sync", MaxAge: time.Hour}))
+	require.NoError(t, s.DependsOn("digest", Dependency{Job: "rebuild", MaxAge: time.Hour, Policy: DependencyRunStale}))
+
+	err := s.DependsOn("sync", Dependency{Job: "digest", MaxAge: time.Hour})
+	require.ErrorIs(t, err, ErrDependencyCycle)
+	assert.Contains(t, err.Error(), "sync -> digest -> rebuild -> sync")
+
+	assert.ErrorIs(t, s.DependsOn("sync", Dependency{Job: "sync", MaxAge: time.Hour}), ErrDependencyCycle)
+	assert.ErrorIs(t, s.DependsOn("sync", Dependency{Job: "missing", MaxAge: time.Hour}), ErrJobNotFound)
+	assert.ErrorIs(t, s.DependsOn("digest", Dependency{Job: "sync"}), ErrInvalidDependency)
+
+	// Rejected dependencies leave the graph untouched
+	assert.Equal(t, map[string][]string{
+		"rebuild": {"sync"},
+		"digest":  {"rebuild"},
+	}, s.DependencyGraph())
+
+	info, err := s.GetJobInfo("rebuild")
+	require.NoError(t, err)
+	require.Len(t, info.DependsOn, 1)
+	assert.Equal(t, DependencyWait, info.DependsOn[0].Policy)
+	assert.Equal(t, DefaultDependencyWaitTimeout, info.DependsOn[0].WaitTimeout)
+}
+
+func TestRunJob_SkipsOnStaleUpstream(t *testing.T) {
+	sync := &countingJob{name: "sync"}
+	rebuild := &countingJob{name: "rebuild"}
+	s := newTestScheduler(t, sync, rebuild)
+	require.NoError(t, s.DependsOn("rebuild", Dependency{Job: "sync", MaxAge: time.Hour, Policy: DependencySkip}))
+
+	runScheduled(s, "rebuild")
+
+	assert.Zero(t, rebuild.runs.Load())
+	info, err := s.GetJobInfo("rebuild")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), info.SkipCount)
+	assert.Zero(t, info.RunCount)
+	assert.Zero(t, info.FailCount)
+	assert.Contains(t, info.LastSkipReason, "upstream sync has not succeeded yet")
+	require.NotNil(t, info.LastResult)
+	assert.True(t, info.LastResult.Skipped)
+
+	// An old success is stale too
+	s.mu.Lock()
+	s.lastSuccess["sync"] = time.Now().Add(-2 * time.Hour)
+	s.mu.Unlock()
+	runScheduled(s, "rebuild")
+	assert.Zero(t, rebuild.runs.Load())
+	info, _ = s.GetJobInfo("rebuild")
+	assert.Contains(t, info.LastSkipReason, "last succeeded 2h0m0s ago (max 1h0m0s)")
+
+	// A fresh upstream run unblocks the job
+	_, err = s.RunNow(context.Background(), "sync")
+	require.NoError(t, err)
+	runScheduled(s, "rebuild")
+	assert.Equal(t, int32(1), rebuild.runs.Load())
+	info, _ = s.GetJobInfo("rebuild")
+	assert.False(t, info.LastResult.Skipped)
+	assert.Equal(t, int64(1), info.RunCount)
+}
+
+func TestRunJob_WaitsForUpstream(t *testing.T) {
+	sync := &countingJob{name: "sync"}
+	rebuild := &countingJob{name: "rebuild"}
+	s := newTestScheduler(t, sync, rebuild)
+	require.NoError(t, s.DependsOn("rebuild", Dependency{Job: "sync", MaxAge: time.Hour, WaitTimeout: 5 * time.Second}))
+
+	done := make(chan struct{})
+	go func() {
+		runScheduled(s, "rebuild")
+		close(done)
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Zero(t, rebuild.runs.Load(), "must wait for the upstream")
+
+	_, err := s.RunNow(context.Background(), "sync")
+	require.NoError(t, err)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("job did not run after the upstream succeeded")
+	}
+	assert.Equal(t, int32(1), rebuild.runs.Load())
+}
+
+func TestRunJob_WaitTimesOut(t *testing.T) {
+	sync := &countingJob{name: "sync"}
+	rebuild := &countingJob{name: "rebuild"}
+	s := newTestScheduler(t, sync, rebuild)
+	require.NoError(t, s.DependsOn("rebuild", Dependency{Job: "sync", MaxAge: time.Hour, WaitTimeout: 20 * time.Millisecond}))
+
+	runScheduled(s, "rebuild")
+
+	assert.Zero(t, rebuild.runs.Load())
+	info, _ := s.GetJobInfo("rebuild")
+	assert.Contains(t, info.LastSkipReason, "after waiting 20ms")
+}
+
+func TestRunJob_RunsWithStaleUpstream(t *testing.T) {
+	rebuild := &countingJob{name: "rebuild"}
+	digest := &countingJob{name: "digest"}
+	s := newTestScheduler(t, rebuild, digest)
+	require.NoError(t, s.DependsOn("digest", Dependency{Job: "rebuild", MaxAge: time.Hour, Policy: DependencyRunStale}))
+
+	runScheduled(s, "digest")
+
+	assert.Equal(t, int32(1), digest.runs.Load())
+	info, _ := s.GetJobInfo("digest")
+	assert.Equal(t, []string{"rebuild"}, info.LastResult.Metadata["stale_upstream"])
+}
@@ -0,0 +1,347 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/activity"
+	"github.com/alem-hub/alem-community-hub/internal/domain/backfill"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/alem"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// BACKFILL ACTIVITY JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// CompletionHistory pages through the task completion history in Alem.
+type CompletionHistory interface {
+	GetTaskCompletions(ctx context.Context, req alem.TaskCompletionsRequestDTO) ([]alem.TaskCompletionDTO, *alem.Meta, error)
+}
+
+// errBackfillInterrupted stops a run that was paused or cancelled meanwhile.
+var errBackfillInterrupted = errors.New("backfill run interrupted")
+
+// BackfillActivityJob loads the full task completion history of every student
+// into activity.Repository. Runs are started and controlled through
+// backfill.Repository (admin endpoints or BACKFILL_ACTIVITY); each scheduler
+// tick advances the active run by up to StudentsPerRun students.
+//
+// The cursor of a student is saved after every page, so an interrupted run
+// resumes at the first page not stored yet. Requests are paced to the run's
+// share of the Alem rate budget, leaving the rest to the regular sync.
+type BackfillActivityJob struct {
+	// Dependencies
+	runs     backfill.Repository
+	activity activity.Repository
+	history  CompletionHistory
+	mapper   *alem.Mapper
+	logger   *slog.Logger
+
+	// Configuration
+	config BackfillActivityConfig
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+
+	// State
+	running      atomic.Bool
+	lastRequest  time.Time
+	lastRunStats atomic.Value // *BackfillActivityStats
+}
+
+// BackfillActivityConfig contains configuration for the backfill job.
+type BackfillActivityConfig struct {
+	// BudgetPerSecond is the whole Alem rate budget in requests per second;
+	// a run uses its RateShare of it.
+	BudgetPerSecond float64
+
+	// PerPage is the number of completions requested per page.
+	PerPage int
+
+	// StudentsPerRun is the number of students handled per tick.
+	StudentsPerRun int
+
+	// Timeout is the maximum duration of a tick.
+	Timeout time.Duration
+}
+
+// DefaultBackfillActivityConfig returns sensible defaults.
+func DefaultBackfillActivityConfig() BackfillActivityConfig {
+	return BackfillActivityConfig{
+		BudgetPerSecond: alem.DefaultRateLimiterConfig().RequestsPerSecond,
+		PerPage:         100,
+		StudentsPerRun:  50,
+		Timeout:         10 * time.Minute,
+	}
+}
+
+// BackfillActivityStats contains statistics from a tick.
+type BackfillActivityStats struct {
+	StartedAt   time.Time
+	CompletedAt time.Time
+	Duration    time.Duration
+	RunID       string
+	Students    int
+	Pages       int
+	Inserted    int
+	Completed   bool
+	Interrupted bool
+	Errors      []error
+}
+
+// NewBackfillActivityJob creates a new backfill job.
+func NewBackfillActivityJob(
+	runs backfill.Repository,
+	activityRepo activity.Repository,
+	history CompletionHistory,
+	logger *slog.Logger,
+	config BackfillActivityConfig,
+) *BackfillActivityJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &BackfillActivityJob{
+		runs:     runs,
+		activity: activityRepo,
+		history:  history,
+		mapper:   alem.NewMapper(),
+		logger:   logger,
+		config:   config,
+		now:      time.Now,
+		sleep:    sleepContext,
+	}
+}
+
+// Name returns the job name.
+func (j *BackfillActivityJob) Name() string {
+	return "backfill_activity"
+}
+
+// Description returns a human-readable description.
+func (j *BackfillActivityJob) Description() string {
+	return "Loads the full task completion history from Alem in resumable chunks"
+}
+
+// Run advances the active backfill run.
+func (j *BackfillActivityJob) Run(ctx context.Context) error {
+	// A tick may outlive the interval; the next one just skips
+	if !j.running.CompareAndSwap(false, true) {
+		return nil
+	}
+	defer j.running.Store(false)
+
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	run, err := j.runs.GetActiveRun(ctx)
+	if err != nil {
+		if shared.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get backfill run: %w", err)
+	}
+	if run.Status != backfill.StatusRunning {
+		return nil
+	}
+
+	startedAt := j.now()
+	stats := &BackfillActivityStats{StartedAt: startedAt, RunID: run.ID}
+	activeBefore := run.ActiveTime
+
+	err = j.advance(ctx, run, activeBefore, startedAt, stats)
+	switch {
+	case errors.Is(err, errBackfillInterrupted):
+		stats.Interrupted = true
+	case err != nil:
+		stats.Errors = append(stats.Errors, err)
+		j.logger.Warn("backfill tick stopped", "run_id", run.ID, "error", err)
+	}
+
+	// Finalize stats
+	stats.CompletedAt = j.now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("backfill_activity job completed",
+		"run_id", run.ID,
+		"dry_run", run.DryRun,
+		"duration", stats.Duration.String(),
+		"students", stats.Students,
+		"pages", stats.Pages,
+		"inserted", stats.Inserted,
+		"progress", fmt.Sprintf("%d/%d", run.StudentsDone, run.StudentsTotal),
+		"completed", stats.Completed,
+		"interrupted", stats.Interrupted,
+	)
+
+	return nil
+}
+
+// advance handles up to StudentsPerRun students of the run.
+func (j *BackfillActivityJob) advance(ctx context.Context, run *backfill.Run, activeBefore time.Duration, startedAt time.Time, stats *BackfillActivityStats) error {
+	for stats.Students < j.config.StudentsPerRun {
+		ids, err := j.runs.NextStudents(ctx, run.LastStudentID, j.config.StudentsPerRun-stats.Students)
+		if err != nil {
+			return fmt.Errorf("list students: %w", err)
+		}
+		if len(ids) == 0 {
+			if err := run.Complete(j.now()); err != nil {
+				return err
+			}
+			if err := j.runs.UpdateStatus(ctx, run, backfill.StatusRunning); err != nil {
+				if errors.Is(err, backfill.ErrInvalidTransition) {
+					return errBackfillInterrupted
+				}
+				return fmt.Errorf("complete run: %w", err)
+			}
+			stats.Completed = true
+			return nil
+		}
+
+		for _, id := range ids {
+			if err := j.backfillStudent(ctx, run, id, activeBefore, startedAt, stats); err != nil {
+				return err
+			}
+			run.StudentsDone++
+			run.LastStudentID = id
+			stats.Students++
+			if err := j.saveProgress(ctx, run, activeBefore, startedAt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// backfillStudent stores the history of a student from the first page its
+// cursor has not stored yet.
+func (j *BackfillActivityJob) backfillStudent(ctx context.Context, run *backfill.Run, studentID string, activeBefore time.Duration, startedAt time.Time, stats *BackfillActivityStats) error {
+	cursor, err := j.runs.GetCursor(ctx, run.ID, studentID)
+	if err != nil {
+		return fmt.Errorf("get cursor: %w", err)
+	}
+
+	for !cursor.Done {
+		if err := j.checkRunning(ctx, run.ID); err != nil {
+			return err
+		}
+		if err := j.pace(ctx, run.RateShare); err != nil {
+			return err
+		}
+
+		page := cursor.Page + 1
+		dtos, meta, err := j.history.GetTaskCompletions(ctx, alem.TaskCompletionsRequestDTO{
+			StudentID: studentID,
+			Page:      page,
+			PerPage:   j.config.PerPage,
+		})
+		if err != nil {
+			return fmt.Errorf("get completions of %s, page %d: %w", studentID, page, err)
+		}
+
+		completions := j.completions(studentID, dtos)
+		var inserted int
+		if run.DryRun {
+			inserted, err = j.activity.CountNewTaskCompletions(ctx, completions)
+		} else {
+			inserted, err = j.activity.ImportTaskCompletions(ctx, completions)
+		}
+		if err != nil {
+			return fmt.Errorf("store completions of %s, page %d: %w", studentID, page, err)
+		}
+
+		cursor.Page = page
+		cursor.Inserted += inserted
+		cursor.Done = len(dtos) < j.config.PerPage || (meta != nil && meta.TotalPages > 0 && page >= meta.TotalPages)
+		for _, c := range completions {
+			if cursor.LastCompletedAt == nil || c.CompletedAt.After(*cursor.LastCompletedAt) {
+				at := c.CompletedAt
+				cursor.LastCompletedAt = &at
+			}
+		}
+		if err := j.runs.SaveCursor(ctx, cursor); err != nil {
+			return fmt.Errorf("save cursor: %w", err)
+		}
+
+		run.Pages++
+		run.Inserted += inserted
+		stats.Pages++
+		stats.Inserted += inserted
+		if err := j.saveProgress(ctx, run, activeBefore, startedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// completions maps a page to domain completions. Failed attempts without XP
+// are skipped as in the regular sync.
+func (j *BackfillActivityJob) completions(studentID string, dtos []alem.TaskCompletionDTO) []*activity.TaskCompletion {
+	completions := make([]*activity.TaskCompletion, 0, len(dtos))
+	for i := range dtos {
+		if !dtos[i].IsSuccessful() && dtos[i].XPEarned == 0 {
+			continue
+		}
+		tc, err := j.mapper.TaskCompletionFromDTO(&dtos[i])
+		if err != nil {
+			continue
+		}
+		tc.StudentID = activity.StudentID(studentID)
+		completions = append(completions, tc)
+	}
+	return completions
+}
+
+// checkRunning stops the tick once the run is paused or cancelled.
+func (j *BackfillActivityJob) checkRunning(ctx context.Context, runID string) error {
+	current, err := j.runs.GetRun(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("get run: %w", err)
+	}
+	if current.Status != backfill.StatusRunning {
+		return errBackfillInterrupted
+	}
+	return nil
+}
+
+// pace spaces the requests so the run stays within its share of the budget.
+func (j *BackfillActivityJob) pace(ctx context.Context, share float64) error {
+	if j.config.BudgetPerSecond > 0 {
+		interval := time.Duration(float64(time.Second) / (j.config.BudgetPerSecond * share))
+		if wait := j.lastRequest.Add(interval).Sub(j.now()); wait > 0 {
+			if err := j.sleep(ctx, wait); err != nil {
+				return err
+			}
+		}
+	}
+	j.lastRequest = j.now()
+	return nil
+}
+
+// saveProgress stores the run counters with the time worked so far.
+func (j *BackfillActivityJob) saveProgress(ctx context.Context, run *backfill.Run, activeBefore time.Duration, startedAt time.Time) error {
+	now := j.now()
+	run.ActiveTime = activeBefore + now.Sub(startedAt)
+	run.UpdatedAt = now.UTC()
+	if err := j.runs.SaveProgress(ctx, run); err != nil {
+		return fmt.Errorf("save progress: %w", err)
+	}
+	return nil
+}
+
+// LastRunStats returns statistics from the last tick.
+func (j *BackfillActivityJob) LastRunStats() *BackfillActivityStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*BackfillActivityStats)
+}
@@ -0,0 +1,188 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/activity"
+	"github.com/alem-hub/alem-community-hub/internal/domain/backfill"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/alem"
+)
+
+type memoryBackfill struct {
+	backfill.Repository
+	run      *backfill.Run
+	students []string
+	cursors  map[string]backfill.Cursor
+}
+
+func (m *memoryBackfill) GetActiveRun(context.Context) (*backfill.Run, error) {
+	if m.run == nil || m.run.Status.IsFinal() {
+		return nil, backfill.ErrRunNotFound
+	}
+	run := *m.run
+	return &run, nil
+}
+
+func (m *memoryBackfill) GetRun(context.Context, string) (*backfill.Run, error) {
+	run := *m.run
+	return &run, nil
+}
+
+func (m *memoryBackfill) UpdateStatus(_ context.Context, run *backfill.Run, from backfill.Status) error {
+	if m.run.Status != from {
+		return backfill.ErrInvalidTransition
+	}
+	m.run.Status, m.run.FinishedAt = run.Status, run.FinishedAt
+	return nil
+}
+
+func (m *memoryBackfill) SaveProgress(_ context.Context, run *backfill.Run) error {
+	m.run.StudentsDone, m.run.LastStudentID = run.StudentsDone, run.LastStudentID
+	m.run.Pages, m.run.Inserted, m.run.ActiveTime = run.Pages, run.Inserted, run.ActiveTime
+	return nil
+}
+
+func (m *memoryBackfill) GetCursor(_ context.Context, runID, studentID string) (*backfill.Cursor, error) {
+	c, ok := m.cursors[studentID]
+	if !ok {
+		c = backfill.Cursor{RunID: runID, StudentID: studentID}
+	}
+	return &c, nil
+}
+
+func (m *memoryBackfill) SaveCursor(_ context.Context, c *backfill.Cursor) error {
+	m.cursors[c.StudentID] = *c
+	return nil
+}
+
+func (m *memoryBackfill) NextStudents(_ context.Context, afterID string, limit int) ([]string, error) {
+	var ids []string
+	for _, id := range m.students {
+		if id > afterID && len(ids) < limit {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+type pagedHistory struct {
+	pages   map[string]int // student -> number of pages
+	perPage int
+	fetched []string
+	onFetch func()
+}
+
+func (h *pagedHistory) GetTaskCompletions(_ context.Context, req alem.TaskCompletionsRequestDTO) ([]alem.TaskCompletionDTO, *alem.Meta, error) {
+	h.fetched = append(h.fetched, fmt.Sprintf("%s/%d", req.StudentID, req.Page))
+	if h.onFetch != nil {
+		h.onFetch()
+	}
+
+	var dtos []alem.TaskCompletionDTO
+	if req.Page <= h.pages[req.StudentID] {
+		for i := range h.perPage {
+			at := time.Date(2026, 1, req.Page, i, 0, 0, 0, time.UTC)
+			dtos = append(dtos, alem.TaskCompletionDTO{
+				ID: fmt.Sprintf("%s-%d-%d", req.StudentID, req.Page, i), StudentID: req.StudentID,
+				TaskID: fmt.Sprintf("task-%d-%d", req.Page, i),
+				Status: "passed", XPEarned: 10, CompletedAt: &at,
+			})
+		}
+	}
+	return dtos, &alem.Meta{Page: req.Page, TotalPages: h.pages[req.StudentID]}, nil
+}
+
+type importedCompletions struct {
+	activity.Repository
+	stored map[string]bool
+}
+
+func (r *importedCompletions) ImportTaskCompletions(_ context.Context, completions []*activity.TaskCompletion) (int, error) {
+	inserted := 0
+	for _, c := range completions {
+		key := string(c.StudentID) + "/" + string(c.TaskID)
+		if !r.stored[key] {
+			r.stored[key] = true
+			inserted++
+		}
+	}
+	return inserted, nil
+}
+
+func newBackfillFixture(t *testing.T) (*memoryBackfill, *pagedHistory, *importedCompletions, *BackfillActivityJob) {
+	t.Helper()
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	run, err := backfill.NewRun("run-1", false, 0.25, 2, now)
+	require.NoError(t, err)
+
+	runs := &memoryBackfill{run: run, students: []string{"a", "b"}, cursors: map[string]backfill.Cursor{}}
+	history := &pagedHistory{pages: map[string]int{"a": 3, "b": 1}, perPage: 2}
+	stored := &importedCompletions{stored: map[string]bool{}}
+
+	config := DefaultBackfillActivityConfig()
+	config.PerPage = 2
+	config.BudgetPerSecond = 4
+	job := NewBackfillActivityJob(runs, stored, history, nil, config)
+	job.now = func() time.Time { return now }
+	job.sleep = func(_ context.Context, d time.Duration) error {
+		now = now.Add(d)
+		return nil
+	}
+	return runs, history, stored, job
+}
+
+func TestBackfillActivityJob_ResumesFromCursor(t *testing.T) {
+	runs, history, stored, job := newBackfillFixture(t)
+
+	// Pause right after the second page of the first student is stored
+	history.onFetch = func() {
+		if len(history.fetched) == 2 {
+			history.onFetch = nil
+			runs.run.Status = backfill.StatusPaused
+		}
+	}
+	require.NoError(t, job.Run(context.Background()))
+	assert.True(t, job.LastRunStats().Interrupted)
+	assert.Equal(t, []string{"a/1", "a/2"}, history.fetched)
+	assert.Equal(t, 2, runs.cursors["a"].Page)
+	assert.Equal(t, 4, runs.run.Inserted)
+
+	// Paused: the tick does nothing
+	require.NoError(t, job.Run(context.Background()))
+	assert.Len(t, history.fetched, 2)
+
+	// Resumed: the stored pages are not fetched again
+	runs.run.Status = backfill.StatusRunning
+	require.NoError(t, job.Run(context.Background()))
+	assert.Equal(t, []string{"a/1", "a/2", "a/3", "b/1"}, history.fetched)
+	assert.Equal(t, backfill.StatusCompleted, runs.run.Status)
+	assert.Equal(t, 2, runs.run.StudentsDone)
+	assert.Equal(t, 8, runs.run.Inserted)
+	assert.Len(t, stored.stored, 8)
+	assert.True(t, runs.cursors["a"].Done)
+	assert.Equal(t, time.Date(2026, 1, 3, 1, 0, 0, 0, time.UTC), *runs.cursors["a"].LastCompletedAt)
+}
+
+func TestBackfillActivityJob_HonorsRateShare(t *testing.T) {
+	runs, history, _, job := newBackfillFixture(t)
+	history.pages = map[string]int{"a": 20, "b": 20}
+
+	var at []time.Time
+	history.onFetch = func() { at = append(at, job.now()) }
+	require.NoError(t, job.Run(context.Background()))
+	require.Equal(t, backfill.StatusCompleted, runs.run.Status)
+	require.Len(t, at, 40)
+
+	// A quarter of 4 requests/s: one request per second at most
+	for i := 1; i < len(at); i++ {
+		assert.GreaterOrEqual(t, at[i].Sub(at[i-1]), time.Second)
+	}
+	rate := float64(len(at)-1) / at[len(at)-1].Sub(at[0]).Seconds()
+	assert.LessOrEqual(t, rate, job.config.BudgetPerSecond*runs.run.RateShare)
+}
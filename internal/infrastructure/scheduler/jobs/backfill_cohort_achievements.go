@@ -0,0 +1,224 @@
+// Package jobs contains implementations of scheduled jobs for Alem Community Hub.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/saga"
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// BACKFILL COHORT ACHIEVEMENTS JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// AchievementGranter runs the achievement flow for a single student.
+// Implemented by saga.AchievementFlowSaga.
+type AchievementGranter interface {
+	Execute(ctx context.Context, input saga.AchievementCheckInput) (*saga.AchievementFlowResult, error)
+}
+
+// BackfillCohortAchievementsJob grants cohort rank achievements to students
+// who were already in their cohort's top before the achievements existed.
+//
+// It is a one-off job: achievements are granted silently, so long-time
+// cohort leaders don't get a burst of notifications for old positions.
+type BackfillCohortAchievementsJob struct {
+	// Dependencies
+	leaderboardRepo leaderboard.LeaderboardRepository
+	granter         AchievementGranter
+	logger          *slog.Logger
+
+	// Configuration
+	config BackfillCohortAchievementsConfig
+
+	// State
+	lastRunStats atomic.Value // *BackfillCohortAchievementsStats
+}
+
+// BackfillCohortAchievementsConfig contains configuration for the backfill job.
+type BackfillCohortAchievementsConfig struct {
+	// TopN is how many top students of each cohort are checked.
+	// Must cover the widest step of the cohort ladder.
+	TopN int
+
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultBackfillCohortAchievementsConfig returns sensible defaults.
+func DefaultBackfillCohortAchievementsConfig() BackfillCohortAchievementsConfig {
+	topN := 0
+	for _, step := range student.CohortRankLadder {
+		topN = max(topN, step.MaxRank)
+	}
+
+	return BackfillCohortAchievementsConfig{
+		TopN:    topN,
+		Timeout: 10 * time.Minute,
+	}
+}
+
+// BackfillCohortAchievementsStats contains statistics from a backfill run.
+type BackfillCohortAchievementsStats struct {
+	StartedAt          time.Time
+	CompletedAt        time.Time
+	Duration           time.Duration
+	CohortsProcessed   int
+	StudentsChecked    int
+	AchievementsGiven  int
+	AchievementsByType map[student.AchievementType]int
+	Errors             []error
+}
+
+// NewBackfillCohortAchievementsJob creates a new backfill job.
+func NewBackfillCohortAchievementsJob(
+	leaderboardRepo leaderboard.LeaderboardRepository,
+	granter AchievementGranter,
+	logger *slog.Logger,
+	config BackfillCohortAchievementsConfig,
+) *BackfillCohortAchievementsJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &BackfillCohortAchievementsJob{
+		leaderboardRepo: leaderboardRepo,
+		granter:         granter,
+		logger:          logger,
+		config:          config,
+	}
+}
+
+// Name returns the job name.
+func (j *BackfillCohortAchievementsJob) Name() string {
+	return "backfill_cohort_achievements"
+}
+
+// Description returns a human-readable description.
+func (j *BackfillCohortAchievementsJob) Description() string {
+	return "Silently grants cohort rank achievements to current cohort leaders"
+}
+
+// Run executes the backfill job.
+func (j *BackfillCohortAchievementsJob) Run(ctx context.Context) error {
+	startedAt := time.Now()
+	stats := &BackfillCohortAchievementsStats{
+		StartedAt:          startedAt,
+		AchievementsByType: make(map[student.AchievementType]int),
+		Errors:             make([]error, 0),
+	}
+
+	j.logger.Info("starting backfill_cohort_achievements job")
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	cohorts, err := j.leaderboardRepo.ListCohorts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list cohorts: %w", err)
+	}
+
+	onlyTypes := make([]student.AchievementType, 0, len(student.CohortRankLadder))
+	for _, step := range student.CohortRankLadder {
+		onlyTypes = append(onlyTypes, step.Type)
+	}
+
+	for _, cohort := range cohorts {
+		if cohort == leaderboard.CohortAll {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if err := j.backfillCohort(ctx, cohort, onlyTypes, stats); err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Error("failed to backfill cohort",
+				"cohort", string(cohort),
+				"error", err,
+			)
+			continue
+		}
+		stats.CohortsProcessed++
+	}
+
+	// Finalize stats
+	stats.CompletedAt = time.Now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("backfill_cohort_achievements job completed",
+		"duration", stats.Duration.String(),
+		"cohorts", stats.CohortsProcessed,
+		"students_checked", stats.StudentsChecked,
+		"achievements_given", stats.AchievementsGiven,
+		"errors", len(stats.Errors),
+	)
+
+	return nil
+}
+
+// backfillCohort runs the achievement flow for the top students of one cohort.
+func (j *BackfillCohortAchievementsJob) backfillCohort(
+	ctx context.Context,
+	cohort leaderboard.Cohort,
+	onlyTypes []student.AchievementType,
+	stats *BackfillCohortAchievementsStats,
+) error {
+	entries, err := j.leaderboardRepo.GetTop(ctx, cohort, j.config.TopN)
+	if err != nil {
+		return fmt.Errorf("get top of %s: %w", cohort, err)
+	}
+
+	for _, entry := range entries {
+		stats.StudentsChecked++
+
+		result, err := j.granter.Execute(ctx, saga.AchievementCheckInput{
+			StudentID:    entry.StudentID,
+			TriggerEvent: "backfill",
+			Context: saga.AchievementContext{
+				CohortRank: int(entry.Rank),
+			},
+			OnlyTypes: onlyTypes,
+			Silent:    true,
+		})
+		if err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to grant cohort achievements",
+				"student_id", entry.StudentID,
+				"cohort", string(cohort),
+				"error", err,
+			)
+			continue
+		}
+
+		for _, a := range result.NewAchievements {
+			stats.AchievementsGiven++
+			stats.AchievementsByType[a.Type]++
+		}
+	}
+
+	return nil
+}
+
+// LastRunStats returns statistics from the last backfill run.
+func (j *BackfillCohortAchievementsJob) LastRunStats() *BackfillCohortAchievementsStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*BackfillCohortAchievementsStats)
+}
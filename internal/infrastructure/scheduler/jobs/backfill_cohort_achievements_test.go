@@ -0,0 +1,72 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/saga"
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type fakeCohortLeaderboard struct {
+	leaderboard.LeaderboardRepository
+	top map[leaderboard.Cohort][]*leaderboard.LeaderboardEntry
+}
+
+func (f *fakeCohortLeaderboard) ListCohorts(context.Context) ([]leaderboard.Cohort, error) {
+	return []leaderboard.Cohort{leaderboard.CohortAll, "2024-09", "2025-01"}, nil
+}
+
+func (f *fakeCohortLeaderboard) GetTop(_ context.Context, cohort leaderboard.Cohort, limit int) ([]*leaderboard.LeaderboardEntry, error) {
+	entries := f.top[cohort]
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+type fakeGranter struct {
+	inputs []saga.AchievementCheckInput
+}
+
+func (f *fakeGranter) Execute(_ context.Context, input saga.AchievementCheckInput) (*saga.AchievementFlowResult, error) {
+	f.inputs = append(f.inputs, input)
+
+	var unlocked []student.Achievement
+	for _, step := range student.CohortRankLadder {
+		if input.Context.CohortRank <= step.MaxRank {
+			unlocked = append(unlocked, student.Achievement{Type: step.Type})
+		}
+	}
+	return &saga.AchievementFlowResult{StudentID: input.StudentID, NewAchievements: unlocked}, nil
+}
+
+func TestBackfillCohortAchievementsJob_GrantsSilently(t *testing.T) {
+	repo := &fakeCohortLeaderboard{top: map[leaderboard.Cohort][]*leaderboard.LeaderboardEntry{
+		leaderboard.CohortAll: {{Rank: 1, StudentID: "global-leader"}},
+		"2024-09":             {{Rank: 1, StudentID: "a"}, {Rank: 7, StudentID: "b"}},
+		"2025-01":             {{Rank: 3, StudentID: "c"}},
+	}}
+	granter := &fakeGranter{}
+
+	job := NewBackfillCohortAchievementsJob(repo, granter, nil, DefaultBackfillCohortAchievementsConfig())
+	require.NoError(t, job.Run(context.Background()))
+
+	require.Len(t, granter.inputs, 3, "global leaderboard is not a cohort")
+	for _, input := range granter.inputs {
+		assert.True(t, input.Silent)
+		assert.ElementsMatch(t, []student.AchievementType{student.AchievementCohortTop10, student.AchievementCohortTop3}, input.OnlyTypes)
+		assert.NotEqual(t, "global-leader", input.StudentID)
+	}
+
+	stats := job.LastRunStats()
+	require.NotNil(t, stats)
+	assert.Equal(t, 2, stats.CohortsProcessed)
+	assert.Equal(t, 5, stats.AchievementsGiven)
+	assert.Equal(t, 3, stats.AchievementsByType[student.AchievementCohortTop10])
+	assert.Equal(t, 2, stats.AchievementsByType[student.AchievementCohortTop3])
+}
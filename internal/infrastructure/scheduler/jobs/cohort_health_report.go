@@ -0,0 +1,215 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// COHORT HEALTH REPORT JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// CohortReportSource builds a cohort's weekly report.
+// Implemented by query.CohortReportBuilder.
+type CohortReportSource interface {
+	Build(ctx context.Context, cohort string) (*query.CohortReport, error)
+}
+
+// CohortLister lists cohorts with active students.
+// Implemented by leaderboard.LeaderboardRepository.
+type CohortLister interface {
+	ListCohorts(ctx context.Context) ([]leaderboard.Cohort, error)
+}
+
+// ReportSender delivers reports to a Telegram chat.
+// Implemented by telegram.Client.
+type ReportSender interface {
+	SendHTML(ctx context.Context, chatID int64, html string) (*telegram.Message, error)
+	SendDocument(ctx context.Context, chatID int64, filename string, content []byte, caption string) (*telegram.Message, error)
+}
+
+// CohortHealthReportJob sends each cohort's weekly health report to the
+// cohort's curator chat (settings.KeyCuratorChatID). Cohorts without a
+// curator chat are skipped; a failing cohort does not stop the others.
+type CohortHealthReportJob struct {
+	// Dependencies
+	cohorts  CohortLister
+	settings settings.Reader
+	reports  CohortReportSource
+	sender   ReportSender
+	logger   *slog.Logger
+
+	// Configuration
+	config CohortHealthReportConfig
+
+	// State
+	lastRunStats atomic.Value // *CohortHealthReportStats
+}
+
+// CohortHealthReportConfig contains configuration for the report job.
+type CohortHealthReportConfig struct {
+	// AttachCSV is the global default for settings.KeyCuratorReportCSV.
+	AttachCSV bool
+
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultCohortHealthReportConfig returns sensible defaults.
+func DefaultCohortHealthReportConfig() CohortHealthReportConfig {
+	return CohortHealthReportConfig{
+		AttachCSV: false,
+		Timeout:   5 * time.Minute,
+	}
+}
+
+// CohortHealthReportStats contains statistics from a report run.
+type CohortHealthReportStats struct {
+	StartedAt      time.Time
+	CompletedAt    time.Time
+	Duration       time.Duration
+	Cohorts        int
+	ReportsSent    int
+	SkippedNoChat  int
+	CSVAttachments int
+	Errors         []error
+}
+
+// NewCohortHealthReportJob creates a new report job.
+func NewCohortHealthReportJob(
+	cohorts CohortLister,
+	cohortSettings settings.Reader,
+	reports CohortReportSource,
+	sender ReportSender,
+	logger *slog.Logger,
+	config CohortHealthReportConfig,
+) *CohortHealthReportJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if cohortSettings == nil {
+		cohortSettings = settings.Defaults{}
+	}
+
+	return &CohortHealthReportJob{
+		cohorts:  cohorts,
+		settings: cohortSettings,
+		reports:  reports,
+		sender:   sender,
+		logger:   logger,
+		config:   config,
+	}
+}
+
+// Name returns the job name.
+func (j *CohortHealthReportJob) Name() string {
+	return "cohort_health_report"
+}
+
+// Description returns a human-readable description.
+func (j *CohortHealthReportJob) Description() string {
+	return "Sends the weekly cohort health report to curator chats"
+}
+
+// Run sends the report of every cohort that has a curator chat.
+func (j *CohortHealthReportJob) Run(ctx context.Context) error {
+	startedAt := time.Now()
+	stats := &CohortHealthReportStats{StartedAt: startedAt}
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	cohorts, err := j.cohorts.ListCohorts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list cohorts: %w", err)
+	}
+	stats.Cohorts = len(cohorts)
+
+	for _, cohort := range cohorts {
+		if ctx.Err() != nil {
+			break
+		}
+
+		chatID := int64(j.settings.GetInt(ctx, string(cohort), settings.KeyCuratorChatID, 0))
+		if chatID == 0 {
+			stats.SkippedNoChat++
+			continue
+		}
+
+		attached, err := j.sendReport(ctx, string(cohort), chatID)
+		if err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to send cohort health report", "cohort", cohort, "error", err)
+			continue
+		}
+		stats.ReportsSent++
+		if attached {
+			stats.CSVAttachments++
+		}
+	}
+
+	// Finalize stats
+	stats.CompletedAt = time.Now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("cohort_health_report job completed",
+		"duration", stats.Duration.String(),
+		"cohorts", stats.Cohorts,
+		"sent", stats.ReportsSent,
+		"skipped_no_chat", stats.SkippedNoChat,
+		"errors", len(stats.Errors),
+	)
+
+	return nil
+}
+
+// sendReport builds and sends one cohort's report. It reports whether the
+// CSV was attached; a failed attachment is logged, the message still counts.
+func (j *CohortHealthReportJob) sendReport(ctx context.Context, cohort string, chatID int64) (bool, error) {
+	report, err := j.reports.Build(ctx, cohort)
+	if err != nil {
+		return false, fmt.Errorf("build report for %s: %w", cohort, err)
+	}
+
+	if _, err := j.sender.SendHTML(ctx, chatID, query.RenderCohortReport(report)); err != nil {
+		return false, fmt.Errorf("send report for %s: %w", cohort, err)
+	}
+
+	if !j.settings.GetBool(ctx, cohort, settings.KeyCuratorReportCSV, j.config.AttachCSV) {
+		return false, nil
+	}
+
+	content, err := query.CohortReportCSV(report)
+	if err != nil {
+		j.logger.Warn("failed to render cohort report csv", "cohort", cohort, "error", err)
+		return false, nil
+	}
+	filename := fmt.Sprintf("cohort-%s-%s.csv", cohort, report.WeekStart.Format("2006-01-02"))
+	if _, err := j.sender.SendDocument(ctx, chatID, filename, content, ""); err != nil {
+		j.logger.Warn("failed to send cohort report csv", "cohort", cohort, "error", err)
+		return false, nil
+	}
+	return true, nil
+}
+
+// LastRunStats returns statistics from the last report run.
+func (j *CohortHealthReportJob) LastRunStats() *CohortHealthReportStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*CohortHealthReportStats)
+}
@@ -4,16 +4,20 @@ package jobs
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 	"log/slog"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
 	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
 	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
 	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/forecast"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -30,6 +34,12 @@ import (
 // - Provides gentle nudges based on goals
 //
 // Each digest is personalized to make students feel seen and valued.
+//
+// To spread the nightly load, recipients can be split into shards by a hash
+// of the student ID; each shard is a separate scheduled job (see ShardJobs)
+// running at its own offset within SpreadWindow. A shard whose digests were
+// all sent is marked done, so a retry only repeats failed shards; the
+// per-student guard still decides whether a digest may go out.
 type DailyDigestJob struct {
 	// Dependencies
 	studentRepo     student.Repository
@@ -38,13 +48,23 @@ type DailyDigestJob struct {
 	socialRepo      SocialRepository
 	notificationSvc NotificationService
 	eventPublisher  shared.EventPublisher
+	cohortSettings  settings.Reader
+	weeklyGoals     student.WeeklyGoalRepository // Optional
+	milestones      ConnectionMilestones         // Optional
+	guard           DigestGuard                  // Optional
+	shardMarkers    DigestShardMarkers           // Optional
+	attempts        DigestAttempts               // Optional
 	logger          *slog.Logger
 
 	// Configuration
 	config DailyDigestConfig
 
 	// State
-	lastRunStats atomic.Value // *DailyDigestStats
+	lastRunStats atomic.Value  // *DailyDigestStats
+	shardSlots   chan struct{} // bounds shards running at once
+
+	// now is the time source (replaced in tests).
+	now func() time.Time
 }
 
 // SocialRepository interface for getting social data.
@@ -53,6 +73,42 @@ type SocialRepository interface {
 	GetHelpProvidedCount(ctx context.Context, studentID string, since time.Time) (int, error)
 }
 
+// ConnectionMilestones finds the student's buddies and mentees close to a
+// milestone. Implemented by query.GetConnectionMilestonesHandler.
+type ConnectionMilestones interface {
+	Handle(ctx context.Context, q query.GetConnectionMilestonesQuery) (*query.GetConnectionMilestonesResult, error)
+}
+
+// DigestGuard is the authoritative per-student guard against a second digest
+// on the same day. Implemented by postgres.StudentRepository.
+type DigestGuard interface {
+	// ClaimDigest claims the digest of a local date; only the first claim
+	// returns true.
+	ClaimDigest(ctx context.Context, studentID string, date time.Time) (bool, error)
+
+	// ReleaseDigest undoes a claim after the digest failed to send.
+	ReleaseDigest(ctx context.Context, studentID string, date time.Time) error
+}
+
+// DigestShardMarkers records shards whose digests were all sent, keyed by
+// slot (local date and hour) and shard. Implemented by redis.DigestShardMarkers.
+type DigestShardMarkers interface {
+	IsShardDone(ctx context.Context, slot string, shard int) (bool, error)
+	MarkShardDone(ctx context.Context, slot string, shard int) error
+}
+
+// DigestAttempts records every digest delivery attempt per student and
+// local date, and keeps the highlights of digests that never arrived for the
+// next one. Implemented by postgres.NotificationRepository.
+type DigestAttempts interface {
+	RecordDigestAttempt(ctx context.Context, attempt notification.DigestAttempt, message string) error
+	GetFailedDigests(ctx context.Context, since time.Time) ([]notification.DigestAttempt, error)
+	MarkDigestCarryOver(ctx context.Context, studentID string, date time.Time) error
+	GetDigestCarryOver(ctx context.Context, studentID string, before time.Time) (*notification.DigestAttempt, error)
+	ClearDigestCarryOver(ctx context.Context, studentID string, before time.Time) error
+	GetDigestDeliverySummary(ctx context.Context, since time.Time) (notification.DigestDeliverySummary, error)
+}
+
 // NotificationService interface for sending notifications.
 type NotificationService interface {
 	Send(ctx context.Context, notification *notification.Notification) notification.DeliveryResult
@@ -60,10 +116,12 @@ type NotificationService interface {
 
 // DailyDigestConfig contains configuration for the daily digest job.
 type DailyDigestConfig struct {
-	// SendTime is the hour (0-23) in the timezone to send digests.
+	// SendTime is the default hour (0-23) to send digests, in each student's
+	// own timezone. Cohorts may override it (digest_hour setting); the job is
+	// expected to run hourly and only sends to students whose hour has come.
 	SendTime int
 
-	// Timezone for calculating send time.
+	// Timezone of the run slots and community-wide dates.
 	Timezone *time.Location
 
 	// EnableDigest enables sending daily digests.
@@ -89,19 +147,23 @@ type DailyDigestConfig struct {
 
 	// SkipInactiveAfterDays skips sending to students inactive for N days.
 	SkipInactiveAfterDays int
+
+	// Shards is the number of recipient shards (1 = no sharding).
+	Shards int
+
+	// SpreadWindow is the window over which shard runs are spread after the
+	// hour starts. Must be shorter than an hour.
+	SpreadWindow time.Duration
+
+	// MaxParallelShards is how many shards may send at the same time.
+	MaxParallelShards int
 }
 
 // DefaultDailyDigestConfig returns sensible defaults.
 func DefaultDailyDigestConfig() DailyDigestConfig {
-	// Default to Almaty timezone (UTC+5)
-	loc, err := time.LoadLocation("Asia/Almaty")
-	if err != nil {
-		loc = time.FixedZone("UTC+5", 5*60*60)
-	}
-
 	return DailyDigestConfig{
-		SendTime:                 21, // 9 PM Almaty time
-		Timezone:                 loc,
+		SendTime:                 21, // 9 PM in the student's timezone
+		Timezone:                 timeutil.AlmatyTZ,
 		EnableDigest:             true,
 		IncludeLeaderboard:       true,
 		IncludeSocialStats:       true,
@@ -110,11 +172,15 @@ func DefaultDailyDigestConfig() DailyDigestConfig {
 		Concurrency:              10,
 		Timeout:                  15 * time.Minute,
 		SkipInactiveAfterDays:    14,
+		Shards:                   1,
+		SpreadWindow:             30 * time.Minute,
+		MaxParallelShards:        2,
 	}
 }
 
 // DailyDigestStats contains statistics from a digest run.
 type DailyDigestStats struct {
+	Shard          int // -1 for a run over all shards
 	StartedAt      time.Time
 	CompletedAt    time.Time
 	Duration       time.Duration
@@ -126,44 +192,13 @@ type DailyDigestStats struct {
 	Errors         []error
 }
 
-// DigestContent contains the personalized content for a student's digest.
-type DigestContent struct {
-	// Basic info
-	StudentName string
-	Date        string
-
-	// Progress
-	TodayXP        int
-	TotalXP        int
-	TasksCompleted int
-	Level          int
-
-	// Streak
-	CurrentStreak int
-	BestStreak    int
-	StreakStatus  string // "maintained", "broken", "new_record"
-
-	// Leaderboard
-	CurrentRank   int
-	RankChange    int
-	RankDirection string // "up", "down", "same"
-	NeighborAbove string // Name of student above
-	NeighborBelow string // Name of student below
-	XPToNextRank  int
-
-	// Social
-	HelpProvided         int
-	EndorsementsReceived int
-	NewConnections       int
-
-	// Community
-	StudentsOnlineNow int
-	CommunityXPToday  int
-	TopMover          string // Who gained the most XP today
-
-	// Motivation
-	MotivationalQuote string
-	PersonalizedTip   string
+// SuccessRate returns the share of attempted digests that were delivered.
+func (s *DailyDigestStats) SuccessRate() float64 {
+	attempted := s.DigestsSent + s.DigestsFailed
+	if attempted == 0 {
+		return 0
+	}
+	return float64(s.DigestsSent) / float64(attempted)
 }
 
 // NewDailyDigestJob creates a new daily digest job.
@@ -174,15 +209,28 @@ func NewDailyDigestJob(
 	socialRepo SocialRepository,
 	notificationSvc NotificationService,
 	eventPublisher shared.EventPublisher,
+	cohortSettings settings.Reader,
 	logger *slog.Logger,
 	config DailyDigestConfig,
 ) *DailyDigestJob {
 	if logger == nil {
 		logger = slog.Default()
 	}
+	if cohortSettings == nil {
+		cohortSettings = settings.Defaults{}
+	}
 	if config.Concurrency <= 0 {
 		config.Concurrency = 10
 	}
+	if config.Shards <= 0 {
+		config.Shards = 1
+	}
+	if config.MaxParallelShards <= 0 {
+		config.MaxParallelShards = 1
+	}
+	if config.SpreadWindow < 0 || config.SpreadWindow >= time.Hour {
+		config.SpreadWindow = 30 * time.Minute
+	}
 
 	return &DailyDigestJob{
 		studentRepo:     studentRepo,
@@ -191,11 +239,47 @@ func NewDailyDigestJob(
 		socialRepo:      socialRepo,
 		notificationSvc: notificationSvc,
 		eventPublisher:  eventPublisher,
+		cohortSettings:  cohortSettings,
 		logger:          logger,
 		config:          config,
+		shardSlots:      make(chan struct{}, config.MaxParallelShards),
+		now:             time.Now,
 	}
 }
 
+// WithWeeklyGoals adds weekly goal progress to digests of students with a goal.
+func (j *DailyDigestJob) WithWeeklyGoals(goals student.WeeklyGoalRepository) *DailyDigestJob {
+	j.weeklyGoals = goals
+	return j
+}
+
+// WithConnectionMilestones lists connections close to a milestone in digests,
+// each with a "👏 Подбодрить" button when the notifier supports keyboards.
+func (j *DailyDigestJob) WithConnectionMilestones(milestones ConnectionMilestones) *DailyDigestJob {
+	j.milestones = milestones
+	return j
+}
+
+// WithDigestGuard makes every digest claim the student's day first, so a
+// retried run never sends a second digest.
+func (j *DailyDigestJob) WithDigestGuard(guard DigestGuard) *DailyDigestJob {
+	j.guard = guard
+	return j
+}
+
+// WithShardMarkers skips shards already completed in the current slot.
+func (j *DailyDigestJob) WithShardMarkers(markers DigestShardMarkers) *DailyDigestJob {
+	j.shardMarkers = markers
+	return j
+}
+
+// WithDigestAttempts records every attempt, so failed digests can be retried
+// by RetryJob and carried over to the next digest.
+func (j *DailyDigestJob) WithDigestAttempts(attempts DigestAttempts) *DailyDigestJob {
+	j.attempts = attempts
+	return j
+}
+
 // Name returns the job name.
 func (j *DailyDigestJob) Name() string {
 	return "daily_digest"
@@ -206,22 +290,57 @@ func (j *DailyDigestJob) Description() string {
 	return "Sends personalized daily progress summaries to students"
 }
 
-// Run executes the daily digest job.
+// Run sends digests to all shards at once.
 func (j *DailyDigestJob) Run(ctx context.Context) error {
+	return j.run(ctx, -1)
+}
+
+// RunShard sends digests to the students of one shard. A shard that was
+// already completed in the current slot is skipped; if any digest fails the
+// shard is not marked done and an error is returned, so it can be retried.
+func (j *DailyDigestJob) RunShard(ctx context.Context, shard int) error {
+	if shard < 0 || shard >= j.config.Shards {
+		return fmt.Errorf("digest shard %d out of range [0, %d)", shard, j.config.Shards)
+	}
+	return j.run(ctx, shard)
+}
+
+// run sends digests to one shard, or to all students if shard is -1.
+func (j *DailyDigestJob) run(ctx context.Context, shard int) error {
+	// Bound the number of shards sending at once
+	select {
+	case j.shardSlots <- struct{}{}:
+		defer func() { <-j.shardSlots }()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
 	startedAt := time.Now()
 	stats := &DailyDigestStats{
+		Shard:          shard,
 		StartedAt:      startedAt,
 		SkippedReasons: make(map[string]int),
 		Errors:         make([]error, 0),
 	}
 
-	j.logger.Info("starting daily_digest job")
+	j.logger.Info("starting daily_digest job", "shard", shard)
 
 	if !j.config.EnableDigest {
 		j.logger.Info("daily digest is disabled")
 		return nil
 	}
 
+	slot := j.now().In(j.config.Timezone).Format("2006-01-02T15")
+	if shard >= 0 && j.shardMarkers != nil {
+		done, err := j.shardMarkers.IsShardDone(ctx, slot, shard)
+		if err != nil {
+			j.logger.Warn("failed to check digest shard marker", "shard", shard, "error", err)
+		} else if done {
+			j.logger.Info("digest shard already completed", "shard", shard, "slot", slot)
+			return nil
+		}
+	}
+
 	// Apply timeout
 	if j.config.Timeout > 0 {
 		var cancel context.CancelFunc
@@ -230,7 +349,7 @@ func (j *DailyDigestJob) Run(ctx context.Context) error {
 	}
 
 	// Get students who should receive digest
-	students, err := j.getEligibleStudents(ctx, stats)
+	students, err := j.getEligibleStudents(ctx, shard, stats)
 	if err != nil {
 		return fmt.Errorf("failed to get eligible students: %w", err)
 	}
@@ -242,14 +361,18 @@ func (j *DailyDigestJob) Run(ctx context.Context) error {
 		stats.CompletedAt = time.Now()
 		stats.Duration = stats.CompletedAt.Sub(startedAt)
 		j.lastRunStats.Store(stats)
+		j.markShardDone(ctx, slot, shard)
 		return nil
 	}
 
 	// Get community-wide stats
 	communityStats := j.getCommunityStats(ctx)
 
+	// Prefetch ranks for the whole batch (one query instead of one per student)
+	ranks := j.prefetchRanks(ctx, students)
+
 	// Send digests concurrently
-	j.sendDigestsConcurrently(ctx, students, communityStats, stats)
+	j.sendDigestsConcurrently(ctx, students, communityStats, ranks, stats)
 
 	// Finalize stats
 	stats.CompletedAt = time.Now()
@@ -257,18 +380,91 @@ func (j *DailyDigestJob) Run(ctx context.Context) error {
 	j.lastRunStats.Store(stats)
 
 	j.logger.Info("daily_digest job completed",
+		"shard", shard,
 		"duration", stats.Duration.String(),
 		"total", stats.TotalStudents,
 		"sent", stats.DigestsSent,
 		"skipped", stats.DigestsSkipped,
 		"failed", stats.DigestsFailed,
+		"first_pass_success_rate", stats.SuccessRate(),
 	)
 
+	if shard >= 0 && (stats.DigestsFailed > 0 || ctx.Err() != nil) {
+		return fmt.Errorf("digest shard %d incomplete: %d failed", shard, stats.DigestsFailed)
+	}
+	j.markShardDone(ctx, slot, shard)
+
 	return nil
 }
 
-// getEligibleStudents returns students who should receive the digest.
-func (j *DailyDigestJob) getEligibleStudents(ctx context.Context, stats *DailyDigestStats) ([]*student.Student, error) {
+// markShardDone records a completed shard; a failure only costs a rerun
+// that the per-student guard turns into no-ops.
+func (j *DailyDigestJob) markShardDone(ctx context.Context, slot string, shard int) {
+	if shard < 0 || j.shardMarkers == nil {
+		return
+	}
+	if notification.SkipEffect(ctx, notification.DryRunEffectDeliveryMarkers) {
+		return
+	}
+	if err := j.shardMarkers.MarkShardDone(ctx, slot, shard); err != nil {
+		j.logger.Warn("failed to mark digest shard done", "shard", shard, "error", err)
+	}
+}
+
+// digestShardOf returns the shard of a student: FNV-1a of the ID modulo
+// shards. Stable across runs and processes.
+func digestShardOf(studentID string, shards int) int {
+	if shards <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(studentID))
+	return int(h.Sum32() % uint32(shards))
+}
+
+// ShardOffset returns how long after the hour starts a shard runs.
+func (j *DailyDigestJob) ShardOffset(shard int) time.Duration {
+	return j.config.SpreadWindow * time.Duration(shard) / time.Duration(j.config.Shards)
+}
+
+// ShardJobs returns one schedulable job per shard.
+func (j *DailyDigestJob) ShardJobs() []*DailyDigestShardJob {
+	shards := make([]*DailyDigestShardJob, j.config.Shards)
+	for i := range shards {
+		shards[i] = &DailyDigestShardJob{digest: j, shard: i}
+	}
+	return shards
+}
+
+// DailyDigestShardJob runs the daily digest for one shard.
+type DailyDigestShardJob struct {
+	digest *DailyDigestJob
+	shard  int
+}
+
+// Name returns the job name.
+func (s *DailyDigestShardJob) Name() string {
+	return fmt.Sprintf("daily_digest_shard_%02d", s.shard)
+}
+
+// Description returns a human-readable description.
+func (s *DailyDigestShardJob) Description() string {
+	return fmt.Sprintf("Sends daily digests to shard %d of %d", s.shard+1, s.digest.config.Shards)
+}
+
+// Run sends the digests of the shard.
+func (s *DailyDigestShardJob) Run(ctx context.Context) error {
+	return s.digest.RunShard(ctx, s.shard)
+}
+
+// Offset returns how long after the hour starts the shard runs.
+func (s *DailyDigestShardJob) Offset() time.Duration {
+	return s.digest.ShardOffset(s.shard)
+}
+
+// getEligibleStudents returns students of the shard (-1 = all) who should
+// receive the digest.
+func (j *DailyDigestJob) getEligibleStudents(ctx context.Context, shard int, stats *DailyDigestStats) ([]*student.Student, error) {
 	// Get all active students with digest enabled
 	opts := student.DefaultListOptions()
 	allStudents, err := j.studentRepo.GetByStatus(ctx, student.StatusActive, opts)
@@ -277,21 +473,41 @@ func (j *DailyDigestJob) getEligibleStudents(ctx context.Context, stats *DailyDi
 	}
 
 	eligible := make([]*student.Student, 0, len(allStudents))
-	now := time.Now()
+	now := j.now()
 
 	for _, s := range allStudents {
+		if shard >= 0 && digestShardOf(s.ID, j.config.Shards) != shard {
+			continue
+		}
+
 		// Check if student wants daily digest
 		if !s.Preferences.DailyDigest {
 			stats.SkippedReasons["digest_disabled"]++
 			continue
 		}
 
+		// Check if it is the student's digest hour: personal, else the cohort's
+		digestHour := j.cohortSettings.GetInt(ctx, string(s.Cohort), settings.KeyDigestHour, j.config.SendTime)
+		if s.Preferences.DigestHour != nil {
+			digestHour = *s.Preferences.DigestHour
+		}
+		if now.In(s.Preferences.Location()).Hour() != digestHour {
+			stats.SkippedReasons["not_digest_hour"]++
+			continue
+		}
+
 		// Check if in quiet hours
-		if s.Preferences.IsQuietHour(now.In(j.config.Timezone)) {
+		if s.Preferences.IsQuietHour(now) {
 			stats.SkippedReasons["quiet_hours"]++
 			continue
 		}
 
+		// Check if the student muted the digest (/mute or /mute digest)
+		if !s.AcceptsNotification(string(notification.NotificationTypeDailyDigest), now) {
+			stats.SkippedReasons["muted"]++
+			continue
+		}
+
 		// Skip long-inactive students
 		if j.config.SkipInactiveAfterDays > 0 {
 			daysSinceActive := int(now.Sub(s.LastSeenAt).Hours() / 24)
@@ -338,11 +554,37 @@ func (j *DailyDigestJob) getCommunityStats(ctx context.Context) *CommunityStats
 	return stats
 }
 
+// prefetchRanks loads leaderboard entries for all recipients in one query.
+// Returns nil if ranks are not needed or the batch lookup failed; digests then
+// fall back to per-student lookups.
+func (j *DailyDigestJob) prefetchRanks(ctx context.Context, students []*student.Student) map[string]*leaderboard.LeaderboardEntry {
+	if !j.config.IncludeLeaderboard {
+		return nil
+	}
+
+	ids := make([]string, len(students))
+	for i, s := range students {
+		ids[i] = s.ID
+	}
+
+	ranks, err := j.leaderboardRepo.GetStudentRanks(ctx, ids, leaderboard.CohortAll)
+	if err != nil {
+		j.logger.Warn("failed to prefetch ranks, falling back to per-student lookups",
+			"students", len(ids),
+			"error", err,
+		)
+		return nil
+	}
+
+	return ranks
+}
+
 // sendDigestsConcurrently sends digests using a worker pool.
 func (j *DailyDigestJob) sendDigestsConcurrently(
 	ctx context.Context,
 	students []*student.Student,
 	communityStats *CommunityStats,
+	ranks map[string]*leaderboard.LeaderboardEntry,
 	stats *DailyDigestStats,
 ) {
 	var (
@@ -366,12 +608,15 @@ func (j *DailyDigestJob) sendDigestsConcurrently(
 			defer func() { <-semaphore }() // Release
 
 			// Build and send digest
-			err := j.sendDigestToStudent(ctx, st, communityStats)
+			sent, err := j.sendDigestToStudent(ctx, st, communityStats, ranks)
 
 			mu.Lock()
 			defer mu.Unlock()
 
-			if err != nil {
+			if err == nil && !sent {
+				stats.DigestsSkipped++
+				stats.SkippedReasons["already_sent"]++
+			} else if err != nil {
 				stats.DigestsFailed++
 				stats.Errors = append(stats.Errors, err)
 				j.logger.Error("failed to send digest",
@@ -388,16 +633,37 @@ func (j *DailyDigestJob) sendDigestsConcurrently(
 }
 
 // sendDigestToStudent builds and sends a digest to a single student.
+// Returns false without an error if the student already got today's digest
+// or the sender skipped it (the student muted the digest meanwhile).
 func (j *DailyDigestJob) sendDigestToStudent(
 	ctx context.Context,
 	s *student.Student,
 	communityStats *CommunityStats,
-) error {
+	ranks map[string]*leaderboard.LeaderboardEntry,
+) (bool, error) {
+	// Claim the day first: the guard, not the shard marker, is authoritative.
+	// A dry run that suppresses delivery markers leaves the day unclaimed.
+	// The day is the student's own.
+	date := j.now().In(s.Preferences.Location())
+	guard := j.guard
+	if notification.SkipEffect(ctx, notification.DryRunEffectDeliveryMarkers) {
+		guard = nil
+	}
+	if guard != nil {
+		claimed, err := guard.ClaimDigest(ctx, s.ID, date)
+		if err != nil {
+			return false, err
+		}
+		if !claimed {
+			return false, nil
+		}
+	}
+
 	// Build digest content
-	content := j.buildDigestContent(ctx, s, communityStats)
+	content := j.buildDigestContent(ctx, s, communityStats, ranks)
 
 	// Format the message
-	message := j.formatDigestMessage(content)
+	message := notification.RenderDigest(content)
 
 	// Create notification
 	n := &notification.Notification{
@@ -411,35 +677,116 @@ func (j *DailyDigestJob) sendDigestToStudent(
 	}
 
 	// Send notification
-	result := j.notificationSvc.Send(ctx, n)
+	result := j.send(ctx, n, content)
+	if !result.Skipped() {
+		j.recordAttempt(ctx, s, content, message, result)
+	}
 	if !result.Success {
-		return result.Error
+		if guard != nil {
+			if err := guard.ReleaseDigest(ctx, s.ID, date); err != nil {
+				j.logger.Warn("failed to release digest claim", "student_id", s.ID, "error", err)
+			}
+		}
+		if result.Skipped() {
+			return false, nil
+		}
+		return false, result.Error
 	}
 
-	return nil
+	return true, nil
+}
+
+// recordAttempt records the outcome of a send for retries and carry-over.
+// A dry run that suppresses delivery markers records nothing.
+func (j *DailyDigestJob) recordAttempt(
+	ctx context.Context,
+	s *student.Student,
+	content *notification.DigestContent,
+	message string,
+	result notification.DeliveryResult,
+) {
+	if j.attempts == nil || notification.SkipEffect(ctx, notification.DryRunEffectDeliveryMarkers) {
+		return
+	}
+
+	now := j.now()
+	today := student.LocalDate(now, s.Preferences.Location())
+	attempt := notification.DigestAttempt{
+		StudentID:     s.ID,
+		ChatID:        notification.TelegramChatID(s.TelegramID),
+		Date:          today,
+		Delivered:     result.Success,
+		ErrorCategory: notification.CategorizeDelivery(result),
+		LastAttemptAt: now,
+		Highlights:    notification.DigestHighlights{XP: content.TodayXP, Tasks: content.TasksCompleted},
+	}
+	if result.Error != nil {
+		attempt.LastError = result.Error.Error()
+	}
+	if err := j.attempts.RecordDigestAttempt(ctx, attempt, message); err != nil {
+		j.logger.Warn("failed to record digest attempt", "student_id", s.ID, "error", err)
+	}
+
+	// The carried-over day has been shown now
+	if result.Success && content.CarryOver != nil {
+		if err := j.attempts.ClearDigestCarryOver(ctx, s.ID, today); err != nil {
+			j.logger.Warn("failed to clear digest carry-over", "student_id", s.ID, "error", err)
+		}
+	}
+}
+
+// send delivers the digest, with a nudge button per connection close to a
+// milestone when the notifier supports keyboards.
+func (j *DailyDigestJob) send(ctx context.Context, n *notification.Notification, content *notification.DigestContent) notification.DeliveryResult {
+	kn, ok := j.notificationSvc.(KeyboardNotificationService)
+	if !ok || len(content.CheerConnections) == 0 {
+		return j.notificationSvc.Send(ctx, n)
+	}
+
+	keyboard := make([][]notification.InlineButton, 0, len(content.CheerConnections))
+	for _, c := range content.CheerConnections {
+		keyboard = append(keyboard, []notification.InlineButton{
+			notification.NewCallbackButton("👏 Подбодрить "+c.Name, "nudge:"+c.StudentID),
+		})
+	}
+	return kn.SendWithKeyboard(ctx, n, keyboard)
 }
 
 // buildDigestContent builds personalized content for a student's digest.
+// ranks holds prefetched leaderboard entries; nil means they were not
+// prefetched and the rank is looked up individually.
 func (j *DailyDigestJob) buildDigestContent(
 	ctx context.Context,
 	s *student.Student,
 	communityStats *CommunityStats,
-) *DigestContent {
-	content := &DigestContent{
+	ranks map[string]*leaderboard.LeaderboardEntry,
+) *notification.DigestContent {
+	now := j.now()
+	content := &notification.DigestContent{
 		StudentName: s.DisplayName,
-		Date:        time.Now().In(j.config.Timezone).Format("02.01.2006"),
+		Date:        now.In(s.Preferences.Location()).Format("02.01.2006"),
 		TotalXP:     int(s.CurrentXP),
 		Level:       int(s.Level()),
 	}
+	today := student.LocalDate(now, s.Preferences.Location())
+
+	// Highlights of an earlier day whose digest never arrived
+	if j.attempts != nil {
+		j.addCarryOver(ctx, content, s, today)
+	}
 
 	// Get today's progress
-	today := time.Now().In(j.config.Timezone).Truncate(24 * time.Hour)
 	dailyGrind, err := j.progressRepo.GetDailyGrind(ctx, s.ID, today)
 	if err == nil && dailyGrind != nil {
 		content.TodayXP = int(dailyGrind.XPGained)
 		content.TasksCompleted = dailyGrind.TasksCompleted
 	}
 
+	// Get weekly goal progress
+	if j.weeklyGoals != nil {
+		j.addWeeklyGoal(ctx, content, s)
+	}
+
 	// Get streak info
 	if j.config.IncludeStreakInfo {
 		streak, err := j.progressRepo.GetStreak(ctx, s.ID)
@@ -448,25 +795,30 @@ func (j *DailyDigestJob) buildDigestContent(
 			content.BestStreak = streak.BestStreak
 
 			if streak.CurrentStreak > streak.BestStreak {
-				content.StreakStatus = "new_record"
+				content.StreakStatus = notification.DigestStreakNewRecord
 			} else if streak.CurrentStreak > 0 {
-				content.StreakStatus = "maintained"
+				content.StreakStatus = notification.DigestStreakMaintained
 			} else {
-				content.StreakStatus = "broken"
+				content.StreakStatus = notification.DigestStreakBroken
 			}
 		}
 	}
 
 	// Get leaderboard info
 	if j.config.IncludeLeaderboard {
-		entry, err := j.leaderboardRepo.GetStudentRank(ctx, s.ID, leaderboard.CohortAll)
+		entry, err := j.studentRank(ctx, s.ID, ranks)
 		if err == nil && entry != nil {
 			content.CurrentRank = int(entry.Rank)
 			content.RankChange = int(entry.RankChange)
 
-			if entry.RankChange > 0 {
+			// Движение за день, если позиция на начало дня зафиксирована
+			if dailyGrind != nil && dailyGrind.RankAtStart > 0 {
+				content.RankChange = dailyGrind.RankAtStart - int(entry.Rank)
+			}
+
+			if content.RankChange > 0 {
 				content.RankDirection = "up"
-			} else if entry.RankChange < 0 {
+			} else if content.RankChange < 0 {
 				content.RankDirection = "down"
 			} else {
 				content.RankDirection = "same"
@@ -491,6 +843,11 @@ func (j *DailyDigestJob) buildDigestContent(
 		content.EndorsementsReceived = endorsements
 	}
 
+	// Connections close to a milestone
+	if j.milestones != nil {
+		j.addCheerConnections(ctx, content, s)
+	}
+
 	// Add community stats
 	content.StudentsOnlineNow = communityStats.OnlineNow
 	content.CommunityXPToday = communityStats.TotalXPToday
@@ -498,190 +855,143 @@ func (j *DailyDigestJob) buildDigestContent(
 
 	// Add motivational content
 	if j.config.IncludeMotivationalQuote {
-		content.MotivationalQuote = j.getMotivationalQuote()
-		content.PersonalizedTip = j.getPersonalizedTip(content)
+		now := time.Now()
+		content.MotivationalQuote = notification.DigestQuote(now)
+		content.PersonalizedTip = notification.DigestTip(content, now)
 	}
 
 	return content
 }
 
-type neighborInfo struct {
-	above    string
-	below    string
-	xpToNext int
+// addCarryOver adds the latest carried-over day before today, if any.
+func (j *DailyDigestJob) addCarryOver(ctx context.Context, content *notification.DigestContent, s *student.Student, today time.Time) {
+	missed, err := j.attempts.GetDigestCarryOver(ctx, s.ID, today)
+	if err != nil {
+		j.logger.Debug("failed to get digest carry-over", "student_id", s.ID, "error", err)
+		return
+	}
+	if missed != nil {
+		content.CarryOver = notification.NewDigestCarryOver(*missed, today)
+	}
 }
 
-func (j *DailyDigestJob) getNeighbors(ctx context.Context, studentID string, rank leaderboard.Rank) neighborInfo {
-	info := neighborInfo{}
+// addWeeklyGoal fills goal progress for the current week, if the student has a goal.
+func (j *DailyDigestJob) addWeeklyGoal(ctx context.Context, content *notification.DigestContent, s *student.Student) {
+	now := j.now()
+	week := student.ISOWeekOf(now)
 
-	neighbors, err := j.leaderboardRepo.GetNeighbors(ctx, studentID, leaderboard.CohortAll, 1)
-	if err != nil || len(neighbors) == 0 {
-		return info
+	goal, err := j.weeklyGoals.Get(ctx, s.ID, week)
+	if err != nil {
+		return
 	}
-
-	for _, n := range neighbors {
-		if n.StudentID == studentID {
-			continue
-		}
-		if n.Rank < rank {
-			info.above = n.DisplayName
-			info.xpToNext = int(n.XP) - int(neighbors[0].XP) + 1
-		} else if n.Rank > rank {
-			info.below = n.DisplayName
-		}
+	start, err := week.Start()
+	if err != nil {
+		return
+	}
+	weekXP, err := j.progressRepo.GetXPGainedBetween(ctx, s.ID, start, start.AddDate(0, 0, 7))
+	if err != nil {
+		return
 	}
 
-	return info
-}
-
-// formatDigestMessage formats the digest content into a Telegram message.
-func (j *DailyDigestJob) formatDigestMessage(content *DigestContent) string {
-	var sb strings.Builder
+	progress := goal.Progress(weekXP, now)
+	content.GoalTargetXP = int(progress.TargetXP)
+	content.GoalWeekXP = int(progress.WeekXP)
+	content.GoalDaysLeft = progress.DaysRemaining
+	content.GoalReached = progress.Reached
 
-	// Header
-	sb.WriteString(fmt.Sprintf("📊 *Твой день, %s*\n", content.StudentName))
-	sb.WriteString(fmt.Sprintf("_%s_\n\n", content.Date))
+	j.addLevelForecast(ctx, content, s)
+}
 
-	// Progress section
-	sb.WriteString("*📈 Прогресс*\n")
-	if content.TodayXP > 0 {
-		sb.WriteString(fmt.Sprintf("• Сегодня: +%d XP\n", content.TodayXP))
-	} else {
-		sb.WriteString("• Сегодня: пока без XP\n")
+// addLevelForecast fills when the student reaches the next level at the
+// recent pace. Nothing is added when the pace can't tell.
+func (j *DailyDigestJob) addLevelForecast(ctx context.Context, content *notification.DigestContent, s *student.Student) {
+	grinds, err := j.progressRepo.GetDailyGrindHistory(ctx, s.ID, int(forecast.Lookback/(24*time.Hour)))
+	if err != nil {
+		return
 	}
-	sb.WriteString(fmt.Sprintf("• Всего: %d XP (уровень %d)\n", content.TotalXP, content.Level))
-
-	if content.TasksCompleted > 0 {
-		sb.WriteString(fmt.Sprintf("• Задач решено: %d\n", content.TasksCompleted))
+	days := make([]forecast.Day, 0, len(grinds))
+	for _, g := range grinds {
+		days = append(days, forecast.Day{Date: g.Date, XP: int64(g.XPGained)})
 	}
-	sb.WriteString("\n")
 
-	// Streak section
-	if content.CurrentStreak > 0 || content.BestStreak > 0 {
-		sb.WriteString("*🔥 Серия*\n")
-		switch content.StreakStatus {
-		case "new_record":
-			sb.WriteString(fmt.Sprintf("• Новый рекорд! %d дней подряд! 🎉\n", content.CurrentStreak))
-		case "maintained":
-			sb.WriteString(fmt.Sprintf("• %d дней подряд (рекорд: %d)\n", content.CurrentStreak, content.BestStreak))
-		case "broken":
-			sb.WriteString(fmt.Sprintf("• Серия прервалась :(\n"))
-			sb.WriteString(fmt.Sprintf("• Твой рекорд: %d дней — побьём его?\n", content.BestStreak))
-		}
-		sb.WriteString("\n")
+	now := j.now().In(j.config.Timezone)
+	next := student.NextLevel(s.CurrentXP)
+	f := forecast.Estimate(int64(s.CurrentXP), int64(next.MinXP()), forecast.PaceOf(days, now), now)
+	if f.Status != forecast.StatusForecast {
+		return
 	}
 
-	// Leaderboard section
-	if content.CurrentRank > 0 {
-		sb.WriteString("*🏆 Рейтинг*\n")
-
-		rankEmoji := "➖"
-		if content.RankDirection == "up" {
-			rankEmoji = fmt.Sprintf("🔼+%d", content.RankChange)
-		} else if content.RankDirection == "down" {
-			rankEmoji = fmt.Sprintf("🔽%d", content.RankChange)
-		}
+	content.ForecastLevel = int(next)
+	content.ForecastDate = f.Date.Format("02.01.2006")
+}
 
-		sb.WriteString(fmt.Sprintf("• Место: #%d %s\n", content.CurrentRank, rankEmoji))
+// maxDigestCheers caps the nudge buttons in one digest.
+const maxDigestCheers = 3
 
-		if content.NeighborAbove != "" && content.XPToNextRank > 0 {
-			sb.WriteString(fmt.Sprintf("• До %s: %d XP\n", content.NeighborAbove, content.XPToNextRank))
-		}
-		sb.WriteString("\n")
+// addCheerConnections lists buddies and mentees close to a milestone.
+func (j *DailyDigestJob) addCheerConnections(ctx context.Context, content *notification.DigestContent, s *student.Student) {
+	result, err := j.milestones.Handle(ctx, query.GetConnectionMilestonesQuery{StudentID: s.ID})
+	if err != nil {
+		j.logger.Debug("failed to get connection milestones", "student_id", s.ID, "error", err)
+		return
 	}
 
-	// Social section
-	if content.HelpProvided > 0 || content.EndorsementsReceived > 0 {
-		sb.WriteString("*🤝 Сообщество*\n")
-		if content.HelpProvided > 0 {
-			sb.WriteString(fmt.Sprintf("• Помог %d студентам 👏\n", content.HelpProvided))
-		}
-		if content.EndorsementsReceived > 0 {
-			sb.WriteString(fmt.Sprintf("• Получено благодарностей: %d ⭐\n", content.EndorsementsReceived))
+	for _, c := range result.NearMilestone() {
+		if len(content.CheerConnections) == maxDigestCheers {
+			break
 		}
-		sb.WriteString("\n")
-	}
-
-	// Community section
-	if content.StudentsOnlineNow > 0 {
-		sb.WriteString("*👥 Прямо сейчас*\n")
-		sb.WriteString(fmt.Sprintf("• Студентов онлайн: %d\n", content.StudentsOnlineNow))
-		if content.TopMover != "" {
-			sb.WriteString(fmt.Sprintf("• Топ-мувер дня: %s 🚀\n", content.TopMover))
+		cheer := notification.DigestCheer{StudentID: c.StudentID, Name: c.DisplayName}
+		if c.Milestone.Kind == student.MilestoneKindStreak {
+			cheer.StreakDays = c.Milestone.Target
+		} else {
+			cheer.Level = c.Milestone.Target
+			cheer.XPLeft = c.Milestone.Remaining
 		}
-		sb.WriteString("\n")
+		content.CheerConnections = append(content.CheerConnections, cheer)
 	}
-
-	// Motivational section
-	if content.MotivationalQuote != "" {
-		sb.WriteString("─────────────────\n")
-		sb.WriteString(fmt.Sprintf("_\"%s\"_\n", content.MotivationalQuote))
-		sb.WriteString("\n")
-	}
-
-	// Personalized tip
-	if content.PersonalizedTip != "" {
-		sb.WriteString(fmt.Sprintf("💡 %s\n", content.PersonalizedTip))
-	}
-
-	// Footer
-	sb.WriteString("\n_Удачного дня! 🍀_")
-
-	return sb.String()
 }
 
-// getMotivationalQuote returns a random motivational quote.
-func (j *DailyDigestJob) getMotivationalQuote() string {
-	quotes := []string{
-		"Код — это поэзия, которую понимают машины",
-		"Каждый эксперт когда-то был новичком",
-		"Лучший код — это тот, который не нужно писать",
-		"Ошибки — это ступени к мастерству",
-		"Вместе мы можем больше, чем поодиночке",
-		"Сегодняшний баг — завтрашний опыт",
-		"Маленькие шаги приводят к большим результатам",
-		"Помогая другим, мы растём сами",
-		"Учиться никогда не поздно",
-		"Успех — это сумма маленьких усилий",
+// studentRank returns the student's leaderboard entry from the prefetched
+// ranks, or looks it up when ranks were not prefetched. A student missing
+// from a prefetched batch is not on the leaderboard.
+func (j *DailyDigestJob) studentRank(
+	ctx context.Context,
+	studentID string,
+	ranks map[string]*leaderboard.LeaderboardEntry,
+) (*leaderboard.LeaderboardEntry, error) {
+	if ranks != nil {
+		return ranks[studentID], nil
 	}
-
-	// Use day of year as seed for consistent daily quote
-	dayOfYear := time.Now().YearDay()
-	return quotes[dayOfYear%len(quotes)]
+	return j.leaderboardRepo.GetStudentRank(ctx, studentID, leaderboard.CohortAll)
 }
 
-// getPersonalizedTip returns a personalized tip based on the student's data.
-func (j *DailyDigestJob) getPersonalizedTip(content *DigestContent) string {
-	// Prioritize tips based on student's situation
-	if content.StreakStatus == "broken" {
-		return "Самое время начать новую серию! Даже одна задача сегодня — уже победа."
-	}
-
-	if content.TodayXP == 0 {
-		return "Ещё не поздно добавить XP сегодня. Начни с чего-то простого!"
-	}
-
-	if content.CurrentStreak >= 7 {
-		return "Целая неделя подряд! Ты на правильном пути. Не останавливайся!"
-	}
+type neighborInfo struct {
+	above    string
+	below    string
+	xpToNext int
+}
 
-	if content.RankDirection == "down" && content.RankChange < -5 {
-		return "Не расстраивайся из-за рейтинга — завтра новый день для рывка!"
-	}
+func (j *DailyDigestJob) getNeighbors(ctx context.Context, studentID string, rank leaderboard.Rank) neighborInfo {
+	info := neighborInfo{}
 
-	if content.HelpProvided > 0 {
-		return "Спасибо, что помогаешь другим. Это делает сообщество сильнее!"
+	neighbors, err := j.leaderboardRepo.GetNeighbors(ctx, studentID, leaderboard.CohortAll, 1)
+	if err != nil || len(neighbors) == 0 {
+		return info
 	}
 
-	// Default tips
-	tips := []string{
-		"Попробуй помочь кому-нибудь сегодня — это лучший способ закрепить знания.",
-		"Не забывай делать перерывы. Отдохнувший мозг работает лучше!",
-		"Застрял? Загляни в /help — кто-то точно может подсказать.",
+	for _, n := range neighbors {
+		if n.StudentID == studentID {
+			continue
+		}
+		if n.Rank < rank {
+			info.above = n.DisplayName
+			info.xpToNext = int(n.XP) - int(neighbors[0].XP) + 1
+		} else if n.Rank > rank {
+			info.below = n.DisplayName
+		}
 	}
 
-	dayOfYear := time.Now().YearDay()
-	return tips[dayOfYear%len(tips)]
+	return info
 }
 
 // LastRunStats returns statistics from the last digest run.
@@ -0,0 +1,198 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// DAILY DIGEST RETRY JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// DailyDigestRetryJob gives failed digests a second chance. On every run it
+// looks at the undelivered digests recorded by the digest job and:
+//   - resends a digest RetryDelay after a failure of a retryable category
+//     (network, rate limit, server error), up to MaxAttempts attempts in all;
+//     blocked and deleted chats are never retried;
+//   - once it is 23:30 in the student's timezone, gives up on the day and
+//     carries its highlights over to the student's next digest.
+//
+// The run summary reports how many digests of the last day went out on the
+// first attempt and how many after retries.
+type DailyDigestRetryJob struct {
+	digest *DailyDigestJob
+	config DigestRetryConfig
+
+	lastRunStats atomic.Value // *DigestRetryStats
+}
+
+// DigestRetryConfig contains configuration for the digest retry job.
+type DigestRetryConfig struct {
+	// RetryDelay is the pause between a failed attempt and the retry.
+	RetryDelay time.Duration
+
+	// MaxAttempts is the number of attempts per digest, the first included.
+	MaxAttempts int
+
+	// Lookback is how far back failed digests are looked at.
+	Lookback time.Duration
+}
+
+// DefaultDigestRetryConfig returns sensible defaults.
+func DefaultDigestRetryConfig() DigestRetryConfig {
+	return DigestRetryConfig{
+		RetryDelay:  notification.DigestRetryDelay,
+		MaxAttempts: notification.DigestMaxAttempts,
+		Lookback:    48 * time.Hour,
+	}
+}
+
+// DigestRetryStats contains statistics from a retry run.
+type DigestRetryStats struct {
+	StartedAt   time.Time
+	CompletedAt time.Time
+	Failed      int // undelivered digests looked at
+	Retried     int
+	Recovered   int // delivered by the retry
+	CarriedOver int
+
+	// Summary covers digests first attempted in the last 24 hours.
+	Summary notification.DigestDeliverySummary
+}
+
+// RetryJob returns the job retrying this job's failed digests. It does
+// nothing unless attempts are recorded (WithDigestAttempts).
+func (j *DailyDigestJob) RetryJob(config DigestRetryConfig) *DailyDigestRetryJob {
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = notification.DigestRetryDelay
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = notification.DigestMaxAttempts
+	}
+	if config.Lookback <= 0 {
+		config.Lookback = 48 * time.Hour
+	}
+	return &DailyDigestRetryJob{digest: j, config: config}
+}
+
+// Name returns the job name.
+func (r *DailyDigestRetryJob) Name() string {
+	return "daily_digest_retry"
+}
+
+// Description returns a human-readable description.
+func (r *DailyDigestRetryJob) Description() string {
+	return "Retries failed daily digests and carries missed ones over to the next day"
+}
+
+// Run retries due digests and carries over the ones past the day's deadline.
+func (r *DailyDigestRetryJob) Run(ctx context.Context) error {
+	j := r.digest
+	if !j.config.EnableDigest || j.attempts == nil {
+		return nil
+	}
+
+	now := j.now()
+	stats := &DigestRetryStats{StartedAt: time.Now()}
+
+	failed, err := j.attempts.GetFailedDigests(ctx, now.Add(-r.config.Lookback))
+	if err != nil {
+		return fmt.Errorf("failed to get failed digests: %w", err)
+	}
+	stats.Failed = len(failed)
+
+	var due []*student.Student
+	for _, attempt := range failed {
+		s, err := j.studentRepo.GetByID(ctx, attempt.StudentID)
+		if err != nil {
+			if !errors.Is(err, student.ErrStudentNotFound) {
+				j.logger.Warn("failed to load student for digest retry", "student_id", attempt.StudentID, "error", err)
+			}
+			continue
+		}
+
+		if attempt.ReconcileDue(now, s.Preferences.Location()) {
+			if r.carryOver(ctx, attempt) {
+				stats.CarriedOver++
+			}
+			continue
+		}
+
+		if attempt.RetryDue(now, r.config.RetryDelay, r.config.MaxAttempts) && r.mayResend(s, now) {
+			due = append(due, s)
+		}
+	}
+
+	if len(due) > 0 {
+		communityStats := j.getCommunityStats(ctx)
+		for _, s := range due {
+			if ctx.Err() != nil {
+				break
+			}
+			stats.Retried++
+			sent, err := j.sendDigestToStudent(ctx, s, communityStats, nil)
+			if err != nil {
+				j.logger.Warn("digest retry failed", "student_id", s.ID, "error", err)
+				continue
+			}
+			if sent {
+				stats.Recovered++
+			}
+		}
+	}
+
+	summary, err := j.attempts.GetDigestDeliverySummary(ctx, now.Add(-24*time.Hour))
+	if err != nil {
+		j.logger.Warn("failed to summarize digest deliveries", "error", err)
+	}
+	stats.Summary = summary
+	stats.CompletedAt = time.Now()
+	r.lastRunStats.Store(stats)
+
+	j.logger.Info("daily_digest_retry job completed",
+		"failed", stats.Failed,
+		"retried", stats.Retried,
+		"recovered", stats.Recovered,
+		"carried_over", stats.CarriedOver,
+		"digests_24h", summary.Total,
+		"first_pass_success_rate", summary.FirstPassRate(),
+		"after_retry_success_rate", summary.AfterRetryRate(),
+	)
+
+	return nil
+}
+
+// mayResend reports whether the student still wants the digest right now.
+func (r *DailyDigestRetryJob) mayResend(s *student.Student, now time.Time) bool {
+	return s.Status == student.StatusActive &&
+		!s.Preferences.IsQuietHour(now) &&
+		s.AcceptsNotification(string(notification.NotificationTypeDailyDigest), now)
+}
+
+// carryOver flags a digest for the next one. A dry run that suppresses
+// delivery markers changes nothing.
+func (r *DailyDigestRetryJob) carryOver(ctx context.Context, attempt notification.DigestAttempt) bool {
+	if notification.SkipEffect(ctx, notification.DryRunEffectDeliveryMarkers) {
+		return false
+	}
+	if err := r.digest.attempts.MarkDigestCarryOver(ctx, attempt.StudentID, attempt.Date); err != nil {
+		r.digest.logger.Warn("failed to carry digest over", "student_id", attempt.StudentID, "error", err)
+		return false
+	}
+	return true
+}
+
+// LastRunStats returns statistics from the last retry run.
+func (r *DailyDigestRetryJob) LastRunStats() *DigestRetryStats {
+	stats := r.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*DigestRetryStats)
+}
@@ -0,0 +1,275 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// fakeDigestAttempts повторяет учёт сводок в notifications.
+type fakeDigestAttempts struct {
+	mu       sync.Mutex
+	rows     map[string]*notification.DigestAttempt
+	messages map[string]string
+}
+
+func newFakeDigestAttempts() *fakeDigestAttempts {
+	return &fakeDigestAttempts{rows: map[string]*notification.DigestAttempt{}, messages: map[string]string{}}
+}
+
+func digestKey(studentID string, date time.Time) string {
+	return studentID + "/" + date.Format("2006-01-02")
+}
+
+func (f *fakeDigestAttempts) RecordDigestAttempt(_ context.Context, a notification.DigestAttempt, message string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := digestKey(a.StudentID, a.Date)
+	a.Attempts = 1
+	if prev, ok := f.rows[key]; ok {
+		a.Attempts = prev.Attempts + 1
+		a.CarryOver = prev.CarryOver
+	}
+	f.rows[key] = &a
+	f.messages[a.StudentID] = message
+	return nil
+}
+
+func (f *fakeDigestAttempts) GetFailedDigests(_ context.Context, since time.Time) ([]notification.DigestAttempt, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var failed []notification.DigestAttempt
+	for _, a := range f.rows {
+		if !a.Delivered && !a.CarryOver && !a.Date.Before(student.LocalDate(since, nil)) {
+			failed = append(failed, *a)
+		}
+	}
+	return failed, nil
+}
+
+func (f *fakeDigestAttempts) MarkDigestCarryOver(_ context.Context, studentID string, date time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if a, ok := f.rows[digestKey(studentID, date)]; ok && !a.Delivered {
+		a.CarryOver = true
+	}
+	return nil
+}
+
+func (f *fakeDigestAttempts) GetDigestCarryOver(_ context.Context, studentID string, before time.Time) (*notification.DigestAttempt, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var latest *notification.DigestAttempt
+	for _, a := range f.rows {
+		if a.StudentID == studentID && a.CarryOver && a.Date.Before(before) && (latest == nil || a.Date.After(latest.Date)) {
+			copied := *a
+			latest = &copied
+		}
+	}
+	return latest, nil
+}
+
+func (f *fakeDigestAttempts) ClearDigestCarryOver(_ context.Context, studentID string, before time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, a := range f.rows {
+		if a.StudentID == studentID && a.Date.Before(before) {
+			a.CarryOver = false
+		}
+	}
+	return nil
+}
+
+func (f *fakeDigestAttempts) GetDigestDeliverySummary(context.Context, time.Time) (notification.DigestDeliverySummary, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var summary notification.DigestDeliverySummary
+	for _, a := range f.rows {
+		summary.Total++
+		if a.Delivered {
+			summary.Delivered++
+			if a.Attempts == 1 {
+				summary.FirstPassDelivered++
+			}
+		}
+	}
+	return summary, nil
+}
+
+func (f *fakeDigestAttempts) get(studentID string, date time.Time) notification.DigestAttempt {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return *f.rows[digestKey(studentID, date)]
+}
+
+// scriptedDigestNotifier отвечает заданными результатами по очереди,
+// затем доставляет.
+type scriptedDigestNotifier struct {
+	mu        sync.Mutex
+	failures  map[string][]notification.DeliveryResult
+	delivered map[string]int
+}
+
+func (n *scriptedDigestNotifier) Send(_ context.Context, notif *notification.Notification) notification.DeliveryResult {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	id := string(notif.RecipientID)
+	if queue := n.failures[id]; len(queue) > 0 {
+		n.failures[id] = queue[1:]
+		return queue[0]
+	}
+	n.delivered[id]++
+	return notification.NewSuccessResult(notification.ChannelTypeTelegram, "1")
+}
+
+// retryTestStudentRepo adds GetByID to shardTestStudentRepo.
+type retryTestStudentRepo struct {
+	shardTestStudentRepo
+}
+
+func (r *retryTestStudentRepo) GetByID(_ context.Context, id string) (*student.Student, error) {
+	for _, s := range r.students {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+// fakeGrindProgressRepo returns the same daily grind for every day.
+type fakeGrindProgressRepo struct {
+	student.ProgressRepository
+	grind *student.DailyGrind
+}
+
+func (r fakeGrindProgressRepo) GetDailyGrind(context.Context, string, time.Time) (*student.DailyGrind, error) {
+	return r.grind, nil
+}
+
+func newRetryTestJob(t *testing.T, names ...string) (*DailyDigestJob, *fakeDigestAttempts, *scriptedDigestNotifier, *time.Time) {
+	t.Helper()
+	repo := &retryTestStudentRepo{}
+	for _, id := range names {
+		s := &student.Student{ID: id, DisplayName: id, TelegramID: 1, Status: student.StatusActive, LastSeenAt: time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)}
+		s.Preferences = student.DefaultNotificationPreferences()
+		s.Preferences.Timezone = "UTC"
+		s.Preferences.QuietHoursStart = 1
+		s.Preferences.QuietHoursEnd = 6
+		repo.students = append(repo.students, s)
+	}
+
+	config := DefaultDailyDigestConfig()
+	config.Timezone = time.UTC
+	config.IncludeLeaderboard = false
+	config.IncludeSocialStats = false
+	config.IncludeStreakInfo = false
+	config.IncludeMotivationalQuote = false
+
+	attempts := newFakeDigestAttempts()
+	notifier := &scriptedDigestNotifier{failures: map[string][]notification.DeliveryResult{}, delivered: map[string]int{}}
+	progress := fakeGrindProgressRepo{grind: &student.DailyGrind{XPGained: 300, TasksCompleted: 2}}
+	job := NewDailyDigestJob(repo, progress, nil, nil, notifier, nil, nil, nil, config).
+		WithDigestGuard(&fakeDigestGuard{last: map[string]time.Time{}}).
+		WithDigestAttempts(attempts)
+
+	now := time.Date(2025, 3, 10, 21, 0, 0, 0, time.UTC)
+	job.now = func() time.Time { return now }
+	return job, attempts, notifier, &now
+}
+
+func TestDailyDigestJob_RecordsCategorizedAttempts(t *testing.T) {
+	job, attempts, notifier, _ := newRetryTestJob(t, "ok", "busy", "gone")
+	notifier.failures["busy"] = []notification.DeliveryResult{notification.NewRateLimitedResult(notification.ChannelTypeTelegram, time.Second)}
+	notifier.failures["gone"] = []notification.DeliveryResult{notification.NewFailureResult(notification.ChannelTypeTelegram, notification.ErrRecipientBlocked, false)}
+
+	require.NoError(t, job.Run(context.Background()))
+
+	today := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	ok := attempts.get("ok", today)
+	assert.True(t, ok.Delivered)
+	assert.Equal(t, notification.DeliveryErrorNone, ok.ErrorCategory)
+	assert.Equal(t, notification.DigestHighlights{XP: 300, Tasks: 2}, ok.Highlights)
+
+	busy := attempts.get("busy", today)
+	assert.False(t, busy.Delivered)
+	assert.Equal(t, notification.DeliveryErrorRateLimited, busy.ErrorCategory)
+	assert.Equal(t, notification.ErrRateLimited.Error(), busy.LastError)
+
+	assert.Equal(t, notification.DeliveryErrorBlocked, attempts.get("gone", today).ErrorCategory)
+	assert.InDelta(t, 1.0/3, job.LastRunStats().SuccessRate(), 0.001)
+}
+
+func TestDailyDigestRetryJob_RetriesOnlyRetryableFailures(t *testing.T) {
+	job, attempts, notifier, now := newRetryTestJob(t, "ok", "busy", "down", "gone")
+	notifier.failures["busy"] = []notification.DeliveryResult{notification.NewRateLimitedResult(notification.ChannelTypeTelegram, time.Second)}
+	notifier.failures["down"] = []notification.DeliveryResult{
+		{Error: errors.New("telegram api error 502"), ErrorCode: notification.ErrorCodeServer, Retryable: true},
+		{Error: errors.New("telegram api error 502"), ErrorCode: notification.ErrorCodeServer, Retryable: true},
+	}
+	notifier.failures["gone"] = []notification.DeliveryResult{notification.NewFailureResult(notification.ChannelTypeTelegram, notification.ErrRecipientBlocked, false)}
+
+	ctx := context.Background()
+	require.NoError(t, job.Run(ctx))
+	retry := job.RetryJob(DefaultDigestRetryConfig())
+
+	// Too early: nothing is retried before the delay
+	*now = now.Add(20 * time.Minute)
+	require.NoError(t, retry.Run(ctx))
+	assert.Equal(t, 0, retry.LastRunStats().Retried)
+
+	*now = now.Add(10 * time.Minute)
+	require.NoError(t, retry.Run(ctx))
+	stats := retry.LastRunStats()
+	assert.Equal(t, 3, stats.Failed)
+	assert.Equal(t, 2, stats.Retried) // busy and down, not the blocked chat
+	assert.Equal(t, 1, stats.Recovered)
+	assert.Equal(t, 1, notifier.delivered["busy"])
+	assert.Equal(t, 0, notifier.delivered["gone"])
+
+	today := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 2, attempts.get("down", today).Attempts)
+
+	// The attempts are used up
+	*now = now.Add(time.Hour)
+	require.NoError(t, retry.Run(ctx))
+	assert.Equal(t, 0, retry.LastRunStats().Retried)
+
+	assert.Equal(t, notification.DigestDeliverySummary{Total: 4, FirstPassDelivered: 1, Delivered: 2}, retry.LastRunStats().Summary)
+}
+
+func TestDailyDigestRetryJob_CarriesMissedDayOver(t *testing.T) {
+	job, attempts, notifier, now := newRetryTestJob(t, "s1")
+	down := notification.DeliveryResult{Error: errors.New("connection reset"), ErrorCode: notification.ErrorCodeNetwork, Retryable: true}
+	notifier.failures["s1"] = []notification.DeliveryResult{down, down}
+
+	ctx := context.Background()
+	require.NoError(t, job.Run(ctx))
+	retry := job.RetryJob(DefaultDigestRetryConfig())
+
+	*now = now.Add(40 * time.Minute)
+	require.NoError(t, retry.Run(ctx))
+
+	// 23:30: the day is given up and carried over
+	*now = time.Date(2025, 3, 10, 23, 30, 0, 0, time.UTC)
+	require.NoError(t, retry.Run(ctx))
+	assert.Equal(t, 1, retry.LastRunStats().CarriedOver)
+
+	// The next digest opens with yesterday's highlights
+	*now = time.Date(2025, 3, 11, 21, 0, 0, 0, time.UTC)
+	require.NoError(t, job.Run(ctx))
+	assert.Equal(t, 1, notifier.delivered["s1"])
+	assert.Contains(t, attempts.messages["s1"], "Вчера: +300 XP, задач: 2")
+
+	// Shown once: the day after has nothing to carry over
+	*now = time.Date(2025, 3, 12, 21, 0, 0, 0, time.UTC)
+	require.NoError(t, job.Run(ctx))
+	assert.NotContains(t, attempts.messages["s1"], "Вчера")
+}
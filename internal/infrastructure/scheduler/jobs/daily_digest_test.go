@@ -0,0 +1,436 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/service"
+)
+
+// fakeRankRepo serves ranks and counts round trips; roundTrip simulates
+// the latency of one database call.
+type fakeRankRepo struct {
+	leaderboard.LeaderboardRepository
+	entries    map[string]*leaderboard.LeaderboardEntry
+	batchErr   error
+	roundTrip  time.Duration
+	singleCall int
+	batchCall  int
+}
+
+func (f *fakeRankRepo) GetStudentRank(_ context.Context, studentID string, _ leaderboard.Cohort) (*leaderboard.LeaderboardEntry, error) {
+	f.singleCall++
+	simulateRoundTrip(f.roundTrip)
+	return f.entries[studentID], nil
+}
+
+func (f *fakeRankRepo) GetStudentRanks(_ context.Context, studentIDs []string, _ leaderboard.Cohort) (map[string]*leaderboard.LeaderboardEntry, error) {
+	f.batchCall++
+	simulateRoundTrip(f.roundTrip)
+	if f.batchErr != nil {
+		return nil, f.batchErr
+	}
+	result := make(map[string]*leaderboard.LeaderboardEntry, len(studentIDs))
+	for _, id := range studentIDs {
+		if e, ok := f.entries[id]; ok {
+			result[id] = e
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeRankRepo) GetNeighbors(context.Context, string, leaderboard.Cohort, int) ([]*leaderboard.LeaderboardEntry, error) {
+	return nil, nil
+}
+
+// simulateRoundTrip busy-waits for d (time.Sleep is too coarse for microseconds).
+func simulateRoundTrip(d time.Duration) {
+	for start := time.Now(); time.Since(start) < d; {
+	}
+}
+
+type fakeDigestProgressRepo struct {
+	student.ProgressRepository
+}
+
+func (fakeDigestProgressRepo) GetDailyGrind(context.Context, string, time.Time) (*student.DailyGrind, error) {
+	return nil, nil
+}
+
+func newRankTestJob(repo *fakeRankRepo) *DailyDigestJob {
+	config := DefaultDailyDigestConfig()
+	config.IncludeSocialStats = false
+	config.IncludeStreakInfo = false
+	config.IncludeMotivationalQuote = false
+	return NewDailyDigestJob(nil, fakeDigestProgressRepo{}, repo, nil, nil, nil, nil, nil, config)
+}
+
+func newRankTestStudents(n int) []*student.Student {
+	students := make([]*student.Student, n)
+	for i := range students {
+		students[i] = &student.Student{ID: fmt.Sprintf("s%d", i), DisplayName: fmt.Sprintf("s%d", i)}
+	}
+	return students
+}
+
+func TestDailyDigestJob_PrefetchedRanks(t *testing.T) {
+	ctx := context.Background()
+	students := newRankTestStudents(3)
+	repo := &fakeRankRepo{entries: map[string]*leaderboard.LeaderboardEntry{
+		"s0": {StudentID: "s0", Rank: 4, RankChange: 2},
+		"s2": {StudentID: "s2", Rank: 9, RankChange: -1},
+	}}
+	job := newRankTestJob(repo)
+
+	t.Run("batch lookup", func(t *testing.T) {
+		ranks := job.prefetchRanks(ctx, students)
+		require.NotNil(t, ranks)
+
+		var got []int
+		for _, s := range students {
+			got = append(got, job.buildDigestContent(ctx, s, &CommunityStats{}, ranks).CurrentRank)
+		}
+
+		assert.Equal(t, []int{4, 0, 9}, got, "student missing from the board has no rank")
+		assert.Equal(t, 1, repo.batchCall)
+		assert.Zero(t, repo.singleCall, "missing student must not trigger a per-student lookup")
+	})
+
+	t.Run("falls back to per-student lookups when batch fails", func(t *testing.T) {
+		repo.batchErr = errors.New("connection reset")
+		repo.singleCall = 0
+
+		ranks := job.prefetchRanks(ctx, students)
+		assert.Nil(t, ranks)
+
+		content := job.buildDigestContent(ctx, students[2], &CommunityStats{}, ranks)
+		assert.Equal(t, 9, content.CurrentRank)
+		assert.Equal(t, "down", content.RankDirection)
+		assert.Equal(t, 1, repo.singleCall)
+	})
+}
+
+// BenchmarkDigestRanks compares one rank lookup per recipient with a single
+// batched lookup for 1000 recipients, with a simulated 20µs round trip.
+func BenchmarkDigestRanks(b *testing.B) {
+	ctx := context.Background()
+	students := newRankTestStudents(1000)
+	entries := make(map[string]*leaderboard.LeaderboardEntry, len(students))
+	for i, s := range students {
+		if i%10 != 0 { // some recipients are not on the board
+			entries[s.ID] = &leaderboard.LeaderboardEntry{StudentID: s.ID, Rank: leaderboard.Rank(i + 1)}
+		}
+	}
+	repo := &fakeRankRepo{entries: entries, roundTrip: 20 * time.Microsecond}
+	job := newRankTestJob(repo)
+
+	b.Run("per-call", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, s := range students {
+				_, _ = job.studentRank(ctx, s.ID, nil)
+			}
+		}
+	})
+
+	b.Run("batched", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ranks := job.prefetchRanks(ctx, students)
+			for _, s := range students {
+				_, _ = job.studentRank(ctx, s.ID, ranks)
+			}
+		}
+	})
+}
+
+// fakeCohortSettings is a settings.Reader backed by per-cohort overrides.
+type fakeCohortSettings map[string]settings.Values
+
+func (f fakeCohortSettings) GetBool(_ context.Context, cohort string, key settings.Key, def bool) bool {
+	return f[cohort].Bool(key, def)
+}
+
+func (f fakeCohortSettings) GetInt(_ context.Context, cohort string, key settings.Key, def int) int {
+	return f[cohort].Int(key, def)
+}
+
+func (f fakeCohortSettings) GetString(_ context.Context, cohort string, key settings.Key, def string) string {
+	return f[cohort].String(key, def)
+}
+
+type fakeDigestStudentRepo struct {
+	student.Repository
+	students []*student.Student
+}
+
+func (f *fakeDigestStudentRepo) GetByStatus(context.Context, student.Status, student.ListOptions) ([]*student.Student, error) {
+	return f.students, nil
+}
+
+func TestDailyDigestJob_DigestHourPrecedence(t *testing.T) {
+	newStudent := func(id, cohort string, digest bool) *student.Student {
+		s := &student.Student{ID: id, Cohort: student.Cohort(cohort), LastSeenAt: time.Now()}
+		s.Preferences = student.DefaultNotificationPreferences()
+		s.Preferences.DailyDigest = digest
+		s.Preferences.Timezone = "UTC"
+		return s
+	}
+	students := &fakeDigestStudentRepo{students: []*student.Student{
+		newStudent("global", "2024-09", true),   // global default 21:00
+		newStudent("evening", "2025-01", true),  // cohort override 20:00
+		newStudent("optout", "2025-01", false),  // student opted out
+		newStudent("personal", "2025-01", true), // personal hour 22:00
+		newStudent("berlin", "2024-09", true),   // global 21:00 in Berlin (UTC+1 in March)
+	}}
+	personalHour := 22
+	students.students[3].Preferences.DigestHour = &personalHour
+	students.students[4].Preferences.Timezone = "Europe/Berlin"
+	cohorts := fakeCohortSettings{"2025-01": {settings.KeyDigestHour: []byte("20")}}
+
+	config := DefaultDailyDigestConfig()
+	config.Timezone = time.UTC
+	job := NewDailyDigestJob(students, nil, nil, nil, nil, nil, cohorts, nil, config)
+
+	eligibleAt := func(hour int) []string {
+		job.now = func() time.Time { return time.Date(2025, 3, 10, hour, 0, 0, 0, time.UTC) }
+		eligible, err := job.getEligibleStudents(context.Background(), -1, &DailyDigestStats{SkippedReasons: map[string]int{}})
+		require.NoError(t, err)
+		ids := make([]string, 0, len(eligible))
+		for _, s := range eligible {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	// Student preference > cohort setting > global default; the hour is
+	// the student's own
+	assert.Equal(t, []string{"evening", "berlin"}, eligibleAt(20))
+	assert.Equal(t, []string{"global"}, eligibleAt(21))
+	assert.Equal(t, []string{"personal"}, eligibleAt(22))
+	assert.Empty(t, eligibleAt(12))
+}
+
+func TestDigestShardOf_Deterministic(t *testing.T) {
+	counts := make([]int, 4)
+	for i := 0; i < 400; i++ {
+		id := fmt.Sprintf("student-%d", i)
+		shard := digestShardOf(id, 4)
+		require.Equal(t, shard, digestShardOf(id, 4), "one student - one shard")
+		counts[shard]++
+	}
+	for shard, n := range counts {
+		assert.Greater(t, n, 50, "shard %d is almost empty", shard)
+	}
+	assert.Zero(t, digestShardOf("student-1", 1))
+}
+
+// shardTestStudentRepo adds the community stats lookups to fakeDigestStudentRepo.
+type shardTestStudentRepo struct {
+	fakeDigestStudentRepo
+}
+
+func (r *shardTestStudentRepo) Count(context.Context) (int, error) { return len(r.students), nil }
+
+func (r *shardTestStudentRepo) FindOnline(context.Context) ([]*student.Student, error) {
+	return nil, nil
+}
+
+// flakyDigestNotifier fails the first send to the students in failOnce.
+type flakyDigestNotifier struct {
+	mu       sync.Mutex
+	failOnce map[string]bool
+	received map[string]int
+}
+
+func (f *flakyDigestNotifier) Send(_ context.Context, n *notification.Notification) notification.DeliveryResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := string(n.RecipientID)
+	if f.failOnce[id] {
+		delete(f.failOnce, id)
+		return notification.DeliveryResult{Error: errors.New("telegram: timeout")}
+	}
+	f.received[id]++
+	return notification.DeliveryResult{Success: true}
+}
+
+// fakeDigestGuard повторяет last_digest_date из PostgreSQL.
+type fakeDigestGuard struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+func (g *fakeDigestGuard) ClaimDigest(_ context.Context, studentID string, date time.Time) (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	day := date.Truncate(24 * time.Hour)
+	if last, ok := g.last[studentID]; ok && !last.Before(day) {
+		return false, nil
+	}
+	g.last[studentID] = day
+	return true, nil
+}
+
+func (g *fakeDigestGuard) ReleaseDigest(_ context.Context, studentID string, _ time.Time) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.last, studentID)
+	return nil
+}
+
+type fakeShardMarkers map[string]bool
+
+func (m fakeShardMarkers) IsShardDone(_ context.Context, slot string, shard int) (bool, error) {
+	return m[fmt.Sprintf("%s/%d", slot, shard)], nil
+}
+
+func (m fakeShardMarkers) MarkShardDone(_ context.Context, slot string, shard int) error {
+	m[fmt.Sprintf("%s/%d", slot, shard)] = true
+	return nil
+}
+
+func TestDailyDigestJob_ShardRetryDoesNotResend(t *testing.T) {
+	const shards = 2
+	repo := &shardTestStudentRepo{}
+	byShard := make([][]string, shards)
+	for i := 0; i < 10; i++ {
+		s := &student.Student{ID: fmt.Sprintf("s%d", i), DisplayName: fmt.Sprintf("s%d", i), LastSeenAt: time.Now()}
+		s.Preferences = student.DefaultNotificationPreferences()
+		s.Preferences.Timezone = "UTC"
+		repo.students = append(repo.students, s)
+		shard := digestShardOf(s.ID, shards)
+		byShard[shard] = append(byShard[shard], s.ID)
+	}
+	require.NotEmpty(t, byShard[0])
+	require.NotEmpty(t, byShard[1])
+
+	notifier := &flakyDigestNotifier{failOnce: map[string]bool{byShard[0][0]: true}, received: map[string]int{}}
+	markers := fakeShardMarkers{}
+
+	config := DefaultDailyDigestConfig()
+	config.Timezone = time.UTC
+	config.IncludeLeaderboard = false
+	config.IncludeSocialStats = false
+	config.IncludeStreakInfo = false
+	config.Shards = shards
+	job := NewDailyDigestJob(repo, fakeDigestProgressRepo{}, nil, nil, notifier, nil, nil, nil, config).
+		WithDigestGuard(&fakeDigestGuard{last: map[string]time.Time{}}).
+		WithShardMarkers(markers)
+	job.now = func() time.Time { return time.Date(2025, 3, 10, 21, 5, 0, 0, time.UTC) }
+
+	ctx := context.Background()
+
+	// Шард 0 падает на одном студенте, шард 1 проходит
+	require.Error(t, job.RunShard(ctx, 0))
+	require.NoError(t, job.RunShard(ctx, 1))
+	assert.True(t, markers["2025-03-10T21/1"])
+	assert.False(t, markers["2025-03-10T21/0"])
+
+	// Повтор шарда 0 досылает только упавшего
+	require.NoError(t, job.RunShard(ctx, 0))
+	assert.Equal(t, 1, job.LastRunStats().DigestsSent)
+	assert.Equal(t, len(byShard[0])-1, job.LastRunStats().SkippedReasons["already_sent"])
+
+	// Без маркера решает last_digest_date: повтор шарда 1 ничего не шлёт
+	delete(markers, "2025-03-10T21/1")
+	require.NoError(t, job.RunShard(ctx, 1))
+
+	for _, s := range repo.students {
+		assert.Equal(t, 1, notifier.received[s.ID], "student %s", s.ID)
+	}
+}
+
+// fakeDryRunOutbox collects recorded messages.
+type fakeDryRunOutbox struct {
+	notification.DryRunOutbox
+	mu      sync.Mutex
+	entries []notification.DryRunEntry
+}
+
+func (o *fakeDryRunOutbox) Record(_ context.Context, entry notification.DryRunEntry) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries = append(o.entries, entry)
+	return nil
+}
+
+func TestDailyDigestJob_DryRunSendsNothing(t *testing.T) {
+	// The real Telegram client talks to a fake Bot API that counts calls
+	var calls atomic.Int64
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1,"chat":{"id":1}}}`))
+	}))
+	defer api.Close()
+
+	tgConfig := telegram.DefaultClientConfig("test-token")
+	tgConfig.BaseURL = api.URL
+	outbox := &fakeDryRunOutbox{}
+	policy := notification.DryRunPolicy{
+		Jobs:     map[string]bool{"daily_digest": true},
+		Suppress: []notification.DryRunEffect{notification.DryRunEffectDeliveryMarkers},
+	}
+	guard := service.NewDryRunGuard(telegram.NewClient(tgConfig), outbox, policy, nil)
+
+	repo := &shardTestStudentRepo{}
+	for i := 0; i < 5; i++ {
+		s := &student.Student{ID: fmt.Sprintf("s%d", i), DisplayName: fmt.Sprintf("s%d", i), TelegramID: student.TelegramID(1000 + i), LastSeenAt: time.Now()}
+		s.Preferences = student.DefaultNotificationPreferences()
+		s.Preferences.Timezone = "UTC"
+		repo.students = append(repo.students, s)
+	}
+
+	config := DefaultDailyDigestConfig()
+	config.Timezone = time.UTC
+	config.IncludeLeaderboard = false
+	config.IncludeSocialStats = false
+	config.IncludeStreakInfo = false
+	digestGuard := &fakeDigestGuard{last: map[string]time.Time{}}
+	markers := fakeShardMarkers{}
+	newJob := func() *DailyDigestJob {
+		job := NewDailyDigestJob(repo, fakeDigestProgressRepo{}, nil, nil,
+			service.NewChannelSender(guard, notification.DefaultDeliveryOptions()), nil, nil, nil, config).
+			WithDigestGuard(digestGuard).
+			WithShardMarkers(markers)
+		job.now = func() time.Time { return time.Date(2025, 3, 10, 21, 5, 0, 0, time.UTC) }
+		return job
+	}
+
+	run := policy.ForJob("daily_digest_shard_00")
+	require.True(t, run.Enabled, "the job override covers its shards")
+	ctx := notification.WithDryRun(context.Background(), run)
+
+	require.NoError(t, newJob().RunShard(ctx, 0))
+
+	assert.Zero(t, calls.Load(), "no request may reach Telegram in dry-run mode")
+	require.Len(t, outbox.entries, len(repo.students))
+	assert.EqualValues(t, len(repo.students), run.Recorded())
+	for _, e := range outbox.entries {
+		assert.Equal(t, "daily_digest_shard_00", e.Job)
+		assert.Equal(t, string(notification.NotificationTypeDailyDigest), e.Type)
+		assert.NotEmpty(t, e.Message)
+	}
+	assert.Empty(t, digestGuard.last, "suppressed delivery markers leave the day unclaimed")
+	assert.Empty(t, markers)
+
+	// Control: the same wiring outside dry-run mode does reach the API
+	live := notification.WithDryRun(context.Background(), notification.DryRunPolicy{}.ForJob("daily_digest_shard_00"))
+	require.NoError(t, newJob().RunShard(live, 0))
+	assert.EqualValues(t, len(repo.students), calls.Load())
+	assert.Len(t, outbox.entries, len(repo.students))
+}
@@ -0,0 +1,319 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// DELIVER NOTIFICATIONS JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// NotificationOutbox is the part of notification.NotificationRepository the
+// delivery job needs. Implemented by postgres.NotificationRepository.
+type NotificationOutbox interface {
+	// GetPending returns due pending notifications, most urgent first.
+	GetPending(ctx context.Context, limit int) ([]*notification.Notification, error)
+
+	// Save updates a notification.
+	Save(ctx context.Context, n *notification.Notification) error
+
+	// ExpireSending marks notifications still sending since before the given
+	// time as expired and returns how many there were.
+	ExpireSending(ctx context.Context, sentBefore time.Time) (int64, error)
+}
+
+// DeliverNotificationsJob drains the notification outbox: notifications
+// scheduled through the notification service are stored as pending and sent
+// here in batches.
+//
+// A recipient in quiet hours gets the notification when the quiet hours end:
+// it is rescheduled, not dropped. Urgent notifications ignore quiet hours.
+// A notification the recipient muted (/mute) or turned off in the settings
+// is marked skipped. A transient Telegram error reschedules the notification
+// with exponential backoff until it has been tried MaxRetries times; then,
+// like permanent errors (blocked bot, deleted chat), it is marked failed.
+//
+// A notification is marked as sending before it goes out, so a crash
+// mid-send loses it rather than sending it twice: whether Telegram got it
+// is unknown. Each run expires notifications that have been sending for
+// longer than StuckAfter, so they don't stay in that state forever.
+type DeliverNotificationsJob struct {
+	// Dependencies
+	outbox      NotificationOutbox
+	studentRepo student.Repository
+	sender      NotificationService
+	logger      *slog.Logger
+
+	// Configuration
+	config DeliverNotificationsConfig
+	now    func() time.Time
+
+	// State
+	lastRunStats atomic.Value // *DeliverNotificationsStats
+}
+
+// DeliverNotificationsConfig contains configuration for the delivery job.
+type DeliverNotificationsConfig struct {
+	// BatchSize is how many pending notifications one run sends at most.
+	BatchSize int
+
+	// RetryBackoff is the delay before the first retry; it doubles with
+	// every further attempt.
+	RetryBackoff time.Duration
+
+	// MaxRetryBackoff caps the retry delay.
+	MaxRetryBackoff time.Duration
+
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+
+	// StuckAfter is how long a notification may stay sending before it is
+	// considered lost in a crash and expired. Longer than Timeout.
+	StuckAfter time.Duration
+}
+
+// DefaultDeliverNotificationsConfig returns sensible defaults.
+func DefaultDeliverNotificationsConfig() DeliverNotificationsConfig {
+	return DeliverNotificationsConfig{
+		BatchSize:       200,
+		RetryBackoff:    time.Minute,
+		MaxRetryBackoff: time.Hour,
+		Timeout:         2 * time.Minute,
+		StuckAfter:      15 * time.Minute,
+	}
+}
+
+// DeliverNotificationsStats contains statistics from a delivery run.
+type DeliverNotificationsStats struct {
+	StartedAt   time.Time
+	CompletedAt time.Time
+	Duration    time.Duration
+	Pending     int
+	Delivered   int
+	Deferred    int
+	Retried     int
+	Failed      int
+	Skipped     int
+	Expired     int64
+	Errors      []error
+}
+
+// NewDeliverNotificationsJob creates a new delivery job.
+func NewDeliverNotificationsJob(
+	outbox NotificationOutbox,
+	studentRepo student.Repository,
+	sender NotificationService,
+	logger *slog.Logger,
+	config DeliverNotificationsConfig,
+) *DeliverNotificationsJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	defaults := DefaultDeliverNotificationsConfig()
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = defaults.RetryBackoff
+	}
+	if config.MaxRetryBackoff < config.RetryBackoff {
+		config.MaxRetryBackoff = max(defaults.MaxRetryBackoff, config.RetryBackoff)
+	}
+	if config.StuckAfter <= config.Timeout {
+		config.StuckAfter = max(defaults.StuckAfter, 2*config.Timeout)
+	}
+
+	return &DeliverNotificationsJob{
+		outbox:      outbox,
+		studentRepo: studentRepo,
+		sender:      sender,
+		logger:      logger,
+		config:      config,
+		now:         time.Now,
+	}
+}
+
+// Name returns the job name.
+func (j *DeliverNotificationsJob) Name() string {
+	return "deliver_notifications"
+}
+
+// Description returns a human-readable description.
+func (j *DeliverNotificationsJob) Description() string {
+	return "Sends pending notifications from the outbox, retrying transient failures"
+}
+
+// Run sends one batch of pending notifications.
+func (j *DeliverNotificationsJob) Run(ctx context.Context) error {
+	startedAt := j.now()
+	stats := &DeliverNotificationsStats{StartedAt: startedAt}
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	// Sends cut off by a crash or a deploy
+	expired, err := j.outbox.ExpireSending(ctx, startedAt.Add(-j.config.StuckAfter))
+	if err != nil {
+		j.logger.Warn("failed to expire stuck notifications", "error", err)
+	}
+	stats.Expired = expired
+
+	pending, err := j.outbox.GetPending(ctx, j.config.BatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get pending notifications: %w", err)
+	}
+	stats.Pending = len(pending)
+
+	for _, n := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := j.deliver(ctx, n, stats); err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to deliver notification",
+				"notification_id", n.ID,
+				"recipient_id", n.RecipientID,
+				"error", err,
+			)
+		}
+	}
+
+	// Finalize stats
+	stats.CompletedAt = j.now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	if stats.Pending > 0 || stats.Expired > 0 {
+		j.logger.Info("deliver_notifications job completed",
+			"duration", stats.Duration.String(),
+			"pending", stats.Pending,
+			"delivered", stats.Delivered,
+			"deferred", stats.Deferred,
+			"retried", stats.Retried,
+			"failed", stats.Failed,
+			"skipped", stats.Skipped,
+			"expired", stats.Expired,
+			"errors", len(stats.Errors),
+		)
+	}
+
+	return nil
+}
+
+// deliver sends one notification and records the outcome.
+func (j *DeliverNotificationsJob) deliver(ctx context.Context, n *notification.Notification, stats *DeliverNotificationsStats) error {
+	now := j.now()
+
+	recipient, err := j.studentRepo.GetByID(ctx, string(n.RecipientID))
+	if errors.Is(err, student.ErrStudentNotFound) {
+		return j.skip(ctx, n, "recipient not found", stats)
+	}
+	if err != nil {
+		return fmt.Errorf("get recipient: %w", err)
+	}
+	if !recipient.Status.CanReceiveNotifications() {
+		return j.skip(ctx, n, "recipient does not receive notifications", stats)
+	}
+	if n.Priority != notification.PriorityUrgent {
+		notifType := string(n.Type)
+		if recipient.IsMuted(student.MuteCategoryFor(notifType), now) {
+			return j.skip(ctx, n, notification.ErrRecipientMuted.Error(), stats)
+		}
+		if !recipient.AcceptsNotification(notifType, now) {
+			return j.skip(ctx, n, notification.ErrRecipientOptedOut.Error(), stats)
+		}
+	}
+
+	// Quiet hours: keep it for the morning
+	if n.Priority != notification.PriorityUrgent && recipient.Preferences.IsQuietHour(now) {
+		prefs := recipient.Preferences
+		quiet := notification.QuietHours{Start: prefs.QuietHoursStart, End: prefs.QuietHoursEnd}
+		at := quiet.EndAfter(now.In(prefs.Location())).UTC()
+		n.ScheduledAt = &at
+		n.UpdatedAt = now.UTC()
+		if err := j.outbox.Save(ctx, n); err != nil {
+			return fmt.Errorf("reschedule after quiet hours: %w", err)
+		}
+		stats.Deferred++
+		return nil
+	}
+
+	// Claim before sending: a duplicate is worse than a lost notification
+	if err := n.MarkSending(); err != nil {
+		return err
+	}
+	if err := j.outbox.Save(ctx, n); err != nil {
+		return fmt.Errorf("claim notification: %w", err)
+	}
+
+	result := j.sender.Send(ctx, n)
+	if result.Success {
+		if err := n.MarkDelivered(); err != nil {
+			return err
+		}
+		stats.Delivered++
+		return j.outbox.Save(ctx, n)
+	}
+
+	reason := "delivery failed"
+	if result.Error != nil {
+		reason = result.Error.Error()
+	}
+	if result.Skipped() {
+		// Muted between the check above and the send
+		return j.skip(ctx, n, reason, stats)
+	}
+	if err := n.MarkFailed(reason); err != nil {
+		return err
+	}
+	if result.Retryable && n.CanRetry() {
+		if err := n.ResetForRetry(); err != nil {
+			return err
+		}
+		at := now.Add(j.retryDelay(n.RetryCount, result.RetryAfter)).UTC()
+		n.ScheduledAt = &at
+		stats.Retried++
+	} else {
+		stats.Failed++
+	}
+	return j.outbox.Save(ctx, n)
+}
+
+// skip marks a notification that can't be delivered as skipped.
+func (j *DeliverNotificationsJob) skip(ctx context.Context, n *notification.Notification, reason string, stats *DeliverNotificationsStats) error {
+	if err := n.MarkSkipped(reason); err != nil {
+		return err
+	}
+	stats.Skipped++
+	return j.outbox.Save(ctx, n)
+}
+
+// retryDelay is the backoff after the given number of failed attempts,
+// never shorter than the delay Telegram asked for.
+func (j *DeliverNotificationsJob) retryDelay(attempts int, retryAfter time.Duration) time.Duration {
+	delay := j.config.RetryBackoff
+	for i := 1; i < attempts && delay < j.config.MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return max(min(delay, j.config.MaxRetryBackoff), retryAfter)
+}
+
+// LastRunStats returns statistics from the last run.
+func (j *DeliverNotificationsJob) LastRunStats() *DeliverNotificationsStats {
+	if v := j.lastRunStats.Load(); v != nil {
+		return v.(*DeliverNotificationsStats)
+	}
+	return nil
+}
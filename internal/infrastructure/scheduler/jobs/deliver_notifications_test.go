@@ -0,0 +1,264 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// fakeOutbox keeps notifications by ID and, like the postgres query, returns
+// the pending ones that are due.
+type fakeOutbox struct {
+	now   *time.Time
+	items map[notification.NotificationID]*notification.Notification
+}
+
+func (f *fakeOutbox) GetPending(_ context.Context, limit int) ([]*notification.Notification, error) {
+	var pending []*notification.Notification
+	for _, n := range f.items {
+		if n.Status == notification.StatusPending && (n.ScheduledAt == nil || !n.ScheduledAt.After(*f.now)) {
+			pending = append(pending, n.Clone())
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].ID < pending[j].ID })
+	return pending[:min(limit, len(pending))], nil
+}
+
+func (f *fakeOutbox) Save(_ context.Context, n *notification.Notification) error {
+	f.items[n.ID] = n.Clone()
+	return nil
+}
+
+func (f *fakeOutbox) ExpireSending(_ context.Context, sentBefore time.Time) (int64, error) {
+	var expired int64
+	for _, n := range f.items {
+		if n.Status == notification.StatusSending && n.SentAt != nil && n.SentAt.Before(sentBefore) {
+			n.Status = notification.StatusExpired
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// scriptedSender answers each chat with the next result from its script
+// (success once the script is exhausted).
+type scriptedSender struct {
+	script map[notification.TelegramChatID][]notification.DeliveryResult
+	sent   []notification.NotificationID
+}
+
+func (s *scriptedSender) Send(_ context.Context, n *notification.Notification) notification.DeliveryResult {
+	s.sent = append(s.sent, n.ID)
+	if results := s.script[n.TelegramChatID]; len(results) > 0 {
+		s.script[n.TelegramChatID] = results[1:]
+		return results[0]
+	}
+	return notification.NewSuccessResult(notification.ChannelTypeTelegram, "1")
+}
+
+func transientFailure() notification.DeliveryResult {
+	return notification.NewFailureResult(notification.ChannelTypeTelegram, errors.New("telegram: 502 bad gateway"), true)
+}
+
+func newOutboxNotification(t *testing.T, id, recipient string, chatID int64, priority notification.Priority) *notification.Notification {
+	t.Helper()
+	n, err := notification.NewNotification(notification.NewNotificationParams{
+		ID:             notification.NotificationID(id),
+		Type:           notification.NotificationTypeAchievement,
+		RecipientID:    notification.RecipientID(recipient),
+		TelegramChatID: notification.TelegramChatID(chatID),
+		Message:        "🏅 Новое достижение!",
+		Priority:       &priority,
+	})
+	require.NoError(t, err)
+	return n
+}
+
+type deliverFixture struct {
+	now      time.Time
+	outbox   *fakeOutbox
+	sender   *scriptedSender
+	students map[string]*student.Student
+	job      *DeliverNotificationsJob
+}
+
+func newDeliverFixture(t *testing.T, now time.Time, notifs ...*notification.Notification) *deliverFixture {
+	f := &deliverFixture{now: now, sender: &scriptedSender{script: map[notification.TelegramChatID][]notification.DeliveryResult{}}}
+	f.outbox = &fakeOutbox{now: &f.now, items: map[notification.NotificationID]*notification.Notification{}}
+	for _, n := range notifs {
+		require.NoError(t, f.outbox.Save(context.Background(), n))
+	}
+
+	f.students = map[string]*student.Student{
+		"dana":  newMentorCandidate("dana", 0, 0),
+		"arman": newMentorCandidate("arman", 0, 0),
+	}
+	f.job = NewDeliverNotificationsJob(f.outbox, &fakeMentorStudentRepo{students: f.students}, f.sender, nil, DefaultDeliverNotificationsConfig())
+	f.job.now = func() time.Time { return f.now }
+	return f
+}
+
+func (f *deliverFixture) run(t *testing.T) *DeliverNotificationsStats {
+	t.Helper()
+	require.NoError(t, f.job.Run(context.Background()))
+	return f.job.LastRunStats()
+}
+
+func TestDeliverNotificationsJob_QuietHoursRescheduleInsteadOfDropping(t *testing.T) {
+	// 01:00 in Almaty: both are in their 23:00-08:00 quiet hours
+	now := time.Date(2025, 3, 10, 20, 0, 0, 0, time.UTC)
+	f := newDeliverFixture(t, now,
+		newOutboxNotification(t, "n1", "dana", 1, notification.PriorityHigh),
+		newOutboxNotification(t, "n2", "arman", 2, notification.PriorityUrgent),
+	)
+
+	stats := f.run(t)
+	assert.Equal(t, 1, stats.Deferred)
+	assert.Equal(t, 1, stats.Delivered)
+	assert.Equal(t, []notification.NotificationID{"n2"}, f.sender.sent, "urgent ignores quiet hours")
+
+	deferred := f.outbox.items["n1"]
+	assert.Equal(t, notification.StatusPending, deferred.Status)
+	require.NotNil(t, deferred.ScheduledAt)
+	assert.Equal(t, time.Date(2025, 3, 11, 3, 0, 0, 0, time.UTC), *deferred.ScheduledAt, "08:00 in Almaty")
+	assert.Equal(t, notification.StatusDelivered, f.outbox.items["n2"].Status)
+
+	// Sent in the morning
+	f.now = *deferred.ScheduledAt
+	stats = f.run(t)
+	assert.Equal(t, 1, stats.Delivered)
+	assert.Equal(t, notification.StatusDelivered, f.outbox.items["n1"].Status)
+}
+
+func TestDeliverNotificationsJob_RetriesTransientErrorsWithBackoff(t *testing.T) {
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	f := newDeliverFixture(t, now, newOutboxNotification(t, "n1", "dana", 1, notification.PriorityNormal))
+	f.sender.script[1] = []notification.DeliveryResult{transientFailure(), transientFailure(), transientFailure()}
+
+	// First failure: retried in a minute
+	stats := f.run(t)
+	assert.Equal(t, 1, stats.Retried)
+	n := f.outbox.items["n1"]
+	assert.Equal(t, notification.StatusPending, n.Status)
+	assert.Equal(t, 1, n.RetryCount)
+	assert.Equal(t, now.Add(time.Minute), *n.ScheduledAt)
+
+	// Too early: nothing is retried before the delay
+	f.now = now.Add(30 * time.Second)
+	assert.Zero(t, f.run(t).Pending)
+
+	// Second failure: retried in two minutes
+	f.now = *n.ScheduledAt
+	f.run(t)
+	n = f.outbox.items["n1"]
+	assert.Equal(t, 2, n.RetryCount)
+	assert.Equal(t, f.now.Add(2*time.Minute), *n.ScheduledAt)
+
+	// The attempts are used up
+	f.now = *n.ScheduledAt
+	stats = f.run(t)
+	assert.Equal(t, 1, stats.Failed)
+	n = f.outbox.items["n1"]
+	assert.Equal(t, notification.StatusFailed, n.Status)
+	assert.Equal(t, 3, n.RetryCount)
+	assert.Contains(t, n.LastError, "502")
+	assert.Len(t, f.sender.sent, 3)
+}
+
+func TestDeliverNotificationsJob_PermanentFailuresAndUnknownRecipients(t *testing.T) {
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	f := newDeliverFixture(t, now,
+		newOutboxNotification(t, "n1", "dana", 1, notification.PriorityNormal),
+		newOutboxNotification(t, "n2", "ghost", 3, notification.PriorityNormal),
+	)
+	blocked := notification.NewFailureResult(notification.ChannelTypeTelegram, notification.ErrRecipientBlocked, false)
+	f.sender.script[1] = []notification.DeliveryResult{blocked}
+
+	stats := f.run(t)
+	assert.Equal(t, 1, stats.Failed)
+	assert.Equal(t, 1, stats.Skipped)
+	assert.Equal(t, notification.StatusFailed, f.outbox.items["n1"].Status)
+	assert.Equal(t, notification.StatusSkipped, f.outbox.items["n2"].Status)
+	assert.Equal(t, []notification.NotificationID{"n1"}, f.sender.sent)
+}
+
+func TestDeliverNotificationsJob_SkipsMutedAndOptedOutRecipients(t *testing.T) {
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	rankUp := newOutboxNotification(t, "n2", "arman", 2, notification.PriorityNormal)
+	rankUp.Type = notification.NotificationTypeRankUp
+	f := newDeliverFixture(t, now,
+		newOutboxNotification(t, "n1", "dana", 1, notification.PriorityNormal),
+		rankUp,
+		newOutboxNotification(t, "n3", "dana", 1, notification.PriorityUrgent),
+	)
+	f.students["dana"].Mute(24*time.Hour, now)
+	f.students["arman"].Preferences.RankChanges = false
+
+	stats := f.run(t)
+	assert.Equal(t, 2, stats.Skipped)
+	assert.Equal(t, 1, stats.Delivered)
+	assert.Equal(t, []notification.NotificationID{"n3"}, f.sender.sent, "urgent ignores the mute")
+
+	muted := f.outbox.items["n1"]
+	assert.Equal(t, notification.StatusSkipped, muted.Status)
+	assert.Equal(t, notification.ErrRecipientMuted.Error(), muted.LastError)
+	optedOut := f.outbox.items["n2"]
+	assert.Equal(t, notification.StatusSkipped, optedOut.Status)
+	assert.Equal(t, notification.ErrRecipientOptedOut.Error(), optedOut.LastError)
+
+	// Skipped notifications are final: the next run sends nothing
+	stats = f.run(t)
+	assert.Equal(t, 0, stats.Pending)
+}
+
+func TestDeliverNotificationsJob_SkippedBySender(t *testing.T) {
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	f := newDeliverFixture(t, now, newOutboxNotification(t, "n1", "dana", 1, notification.PriorityNormal))
+	f.sender.script[1] = []notification.DeliveryResult{
+		notification.NewSkippedResult(notification.ChannelTypeTelegram, notification.ErrRecipientMuted),
+	}
+
+	stats := f.run(t)
+	assert.Equal(t, 1, stats.Skipped)
+	assert.Equal(t, 0, stats.Failed)
+	assert.Equal(t, notification.StatusSkipped, f.outbox.items["n1"].Status)
+}
+
+func TestDeliverNotificationsJob_ExpiresNotificationsStuckInSending(t *testing.T) {
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	stuck := newOutboxNotification(t, "n1", "dana", 1, notification.PriorityNormal)
+	require.NoError(t, stuck.MarkSending())
+	stuckAt := now.Add(-time.Hour)
+	stuck.SentAt = &stuckAt
+	inFlight := newOutboxNotification(t, "n2", "arman", 2, notification.PriorityNormal)
+	require.NoError(t, inFlight.MarkSending())
+	inFlightAt := now.Add(-time.Minute)
+	inFlight.SentAt = &inFlightAt
+	f := newDeliverFixture(t, now, stuck, inFlight)
+
+	stats := f.run(t)
+	assert.Equal(t, int64(1), stats.Expired)
+	assert.Equal(t, notification.StatusExpired, f.outbox.items["n1"].Status)
+	assert.Equal(t, notification.StatusSending, f.outbox.items["n2"].Status, "a send of the running process is left alone")
+	assert.Empty(t, f.sender.sent, "a lost notification is not sent again")
+}
+
+func TestDeliverNotificationsJob_RetryDelayHonoursRetryAfter(t *testing.T) {
+	job := NewDeliverNotificationsJob(nil, nil, nil, nil, DeliverNotificationsConfig{
+		RetryBackoff:    time.Minute,
+		MaxRetryBackoff: 10 * time.Minute,
+	})
+
+	assert.Equal(t, time.Minute, job.retryDelay(1, 0))
+	assert.Equal(t, 4*time.Minute, job.retryDelay(3, 0))
+	assert.Equal(t, 10*time.Minute, job.retryDelay(8, 0))
+	assert.Equal(t, 30*time.Minute, job.retryDelay(1, 30*time.Minute))
+}
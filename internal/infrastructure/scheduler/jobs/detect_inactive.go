@@ -9,9 +9,12 @@ import (
 	"time"
 
 	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
 	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
 	"github.com/alem-hub/alem-community-hub/internal/domain/social"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+
+	"github.com/google/uuid"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -35,6 +38,7 @@ type DetectInactiveJob struct {
 	notificationSvc  notification.NotificationService
 	notificationRepo notification.NotificationRepository
 	eventPublisher   shared.EventPublisher
+	cohortSettings   settings.Reader
 	logger           *slog.Logger
 
 	// Configuration
@@ -47,7 +51,8 @@ type DetectInactiveJob struct {
 // DetectInactiveConfig contains configuration for the detect inactive job.
 type DetectInactiveConfig struct {
 	// InactivityThresholds defines inactivity levels and their actions.
-	// Key: days of inactivity, Value: action type
+	// Key: days of inactivity, Value: action type.
+	// Cohorts may override the day count of each action (inactivity_days.* settings).
 	InactivityThresholds map[int]InactivityAction
 
 	// EnableNotifications enables sending notifications to inactive students.
@@ -72,6 +77,9 @@ type DetectInactiveConfig struct {
 	Timeout time.Duration
 }
 
+// inactivityMaxRetries is how many times a failed reminder is retried.
+const inactivityMaxRetries = 3
+
 // InactivityAction defines what action to take for inactive students.
 type InactivityAction string
 
@@ -92,6 +100,15 @@ const (
 	ActionMarkInactive InactivityAction = "mark_inactive"
 )
 
+// inactivityDaysKeys maps each action to the cohort setting overriding its day count.
+var inactivityDaysKeys = map[InactivityAction]settings.Key{
+	ActionGentleReminder:   settings.KeyInactivityGentleReminderDays,
+	ActionEncouragement:    settings.KeyInactivityEncouragementDays,
+	ActionNotifyStudyBuddy: settings.KeyInactivityStudyBuddyDays,
+	ActionUrgentOutreach:   settings.KeyInactivityUrgentOutreachDays,
+	ActionMarkInactive:     settings.KeyInactivityMarkInactiveDays,
+}
+
 // DefaultDetectInactiveConfig returns sensible defaults.
 func DefaultDetectInactiveConfig() DetectInactiveConfig {
 	return DetectInactiveConfig{
@@ -147,12 +164,16 @@ func NewDetectInactiveJob(
 	notificationSvc notification.NotificationService,
 	notificationRepo notification.NotificationRepository,
 	eventPublisher shared.EventPublisher,
+	cohortSettings settings.Reader,
 	logger *slog.Logger,
 	config DetectInactiveConfig,
 ) *DetectInactiveJob {
 	if logger == nil {
 		logger = slog.Default()
 	}
+	if cohortSettings == nil {
+		cohortSettings = settings.Defaults{}
+	}
 
 	return &DetectInactiveJob{
 		studentRepo:      studentRepo,
@@ -160,6 +181,7 @@ func NewDetectInactiveJob(
 		notificationSvc:  notificationSvc,
 		notificationRepo: notificationRepo,
 		eventPublisher:   eventPublisher,
+		cohortSettings:   cohortSettings,
 		logger:           logger,
 		config:           config,
 	}
@@ -259,13 +281,9 @@ func (j *DetectInactiveJob) findInactiveStudents(ctx context.Context, stats *Det
 		// Calculate days since last activity
 		daysSinceLastSeen := int(now.Sub(s.LastSeenAt).Hours() / 24)
 
-		// Skip recently active students
-		if daysSinceLastSeen < 3 { // Minimum threshold
-			continue
-		}
-
 		// Determine the appropriate action based on inactivity level
-		action := j.determineAction(daysSinceLastSeen)
+		// (recently active students match no threshold)
+		action := j.determineAction(ctx, string(s.Cohort), daysSinceLastSeen)
 		if action == "" {
 			continue
 		}
@@ -295,12 +313,18 @@ func (j *DetectInactiveJob) findInactiveStudents(ctx context.Context, stats *Det
 	return inactiveStudents, nil
 }
 
-// determineAction determines what action to take based on days inactive.
-func (j *DetectInactiveJob) determineAction(daysInactive int) InactivityAction {
+// determineAction determines what action to take based on days inactive,
+// using the cohort's day counts where they are overridden.
+func (j *DetectInactiveJob) determineAction(ctx context.Context, cohort string, daysInactive int) InactivityAction {
 	var selectedAction InactivityAction
 	var selectedThreshold int
 
-	for threshold, action := range j.config.InactivityThresholds {
+	for defaultThreshold, action := range j.config.InactivityThresholds {
+		threshold := defaultThreshold
+		if key, ok := inactivityDaysKeys[action]; ok {
+			threshold = j.cohortSettings.GetInt(ctx, cohort, key, defaultThreshold)
+		}
+
 		if daysInactive >= threshold && threshold > selectedThreshold {
 			selectedThreshold = threshold
 			selectedAction = action
@@ -455,24 +479,17 @@ func (j *DetectInactiveJob) notifyStudyBuddies(
 		}
 
 		// Create notification for study buddy
-		n := &notification.Notification{
-			RecipientID:    notification.RecipientID(buddy.ID),
-			TelegramChatID: notification.TelegramChatID(buddy.TelegramID),
-			Type:           notification.NotificationTypeInactivityReminder,
-			Priority:       notification.PriorityNormal,
-			Status:         notification.StatusPending,
-			Message: fmt.Sprintf(
-				"Привет, %s! 💙\n\n"+
-					"Твой друг %s не заходил уже %d дней. "+
-					"Может, напишешь ему/ей? Иногда одно сообщение поддержки "+
-					"может всё изменить.\n\n"+
-					"Вместе мы сильнее! 🤝",
-				buddy.DisplayName,
-				info.Student.DisplayName,
-				info.DaysInactive,
-			),
-			CreatedAt: time.Now(),
-		}
+		n := newInactivityNotification(buddy, notification.PriorityNormal)
+		n.Message = fmt.Sprintf(
+			"Привет, %s! 💙\n\n"+
+				"Твой друг %s не заходил уже %d дней. "+
+				"Может, напишешь ему/ей? Иногда одно сообщение поддержки "+
+				"может всё изменить.\n\n"+
+				"Вместе мы сильнее! 🤝",
+			buddy.DisplayName,
+			info.Student.DisplayName,
+			info.DaysInactive,
+		)
 
 		if err := j.sendNotification(ctx, n); err != nil {
 			j.logger.Warn("failed to notify study buddy",
@@ -521,25 +538,18 @@ func (j *DetectInactiveJob) urgentOutreach(
 			continue
 		}
 
-		n := &notification.Notification{
-			RecipientID:    notification.RecipientID(buddy.ID),
-			TelegramChatID: notification.TelegramChatID(buddy.TelegramID),
-			Type:           notification.NotificationTypeInactivityReminder,
-			Priority:       notification.PriorityHigh,
-			Status:         notification.StatusPending,
-			Message: fmt.Sprintf(
-				"⚠️ %s, обрати внимание!\n\n"+
-					"Твой друг %s не появлялся уже %d дней. "+
-					"Это довольно долго.\n\n"+
-					"Если у вас есть связь вне платформы — "+
-					"может, стоит написать и узнать, всё ли в порядке?\n\n"+
-					"Твоя поддержка может многое значить! 💪",
-				buddy.DisplayName,
-				info.Student.DisplayName,
-				info.DaysInactive,
-			),
-			CreatedAt: time.Now(),
-		}
+		n := newInactivityNotification(buddy, notification.PriorityHigh)
+		n.Message = fmt.Sprintf(
+			"⚠️ %s, обрати внимание!\n\n"+
+				"Твой друг %s не появлялся уже %d дней. "+
+				"Это довольно долго.\n\n"+
+				"Если у вас есть связь вне платформы — "+
+				"может, стоит написать и узнать, всё ли в порядке?\n\n"+
+				"Твоя поддержка может многое значить! 💪",
+			buddy.DisplayName,
+			info.Student.DisplayName,
+			info.DaysInactive,
+		)
 
 		if err := j.sendNotification(ctx, n); err == nil {
 			stats.StudyBuddiesNotified++
@@ -564,8 +574,10 @@ func (j *DetectInactiveJob) markStudentInactive(
 		return err
 	}
 
-	if err := j.studentRepo.Update(ctx, info.Student); err != nil {
-		return fmt.Errorf("failed to update student status: %w", err)
+	if !notification.SkipEffect(ctx, notification.DryRunEffectStatusUpdates) {
+		if err := j.studentRepo.Update(ctx, info.Student); err != nil {
+			return fmt.Errorf("failed to update student status: %w", err)
+		}
 	}
 
 	stats.StudentsMarkedInactive++
@@ -601,26 +613,39 @@ func (j *DetectInactiveJob) createInactivityNotification(
 	info *InactiveStudentInfo,
 	notifType notification.NotificationType,
 ) *notification.Notification {
+	n := newInactivityNotification(info.Student, notification.PriorityNormal)
+	n.Type = notifType
+	n.Data = notification.NotificationData{
+		DaysInactive: info.DaysInactive,
+	}
+	return n
+}
+
+// newInactivityNotification creates a pending inactivity reminder for the
+// recipient; the caller sets the message.
+func newInactivityNotification(recipient *student.Student, priority notification.Priority) *notification.Notification {
+	now := time.Now().UTC()
 	return &notification.Notification{
-		RecipientID:    notification.RecipientID(info.Student.ID),
-		TelegramChatID: notification.TelegramChatID(info.Student.TelegramID),
-		Type:           notifType,
-		Priority:       notification.PriorityNormal,
+		ID:             notification.NotificationID(uuid.New().String()),
+		RecipientID:    notification.RecipientID(recipient.ID),
+		TelegramChatID: notification.TelegramChatID(recipient.TelegramID),
+		Type:           notification.NotificationTypeInactivityReminder,
+		Priority:       priority,
 		Status:         notification.StatusPending,
-		CreatedAt:      time.Now(),
-		Data: notification.NotificationData{
-			DaysInactive: info.DaysInactive,
-		},
+		MaxRetries:     inactivityMaxRetries,
+		Metadata:       make(map[string]string),
+		CreatedAt:      now,
+		UpdatedAt:      now,
 	}
 }
 
+// sendNotification queues the notification in the outbox. The worker's
+// DeliverNotificationsJob sends it, applying mutes, opt-outs and quiet
+// hours, and the recipient finds it in /notifications.
 func (j *DetectInactiveJob) sendNotification(ctx context.Context, n *notification.Notification) error {
-	// TODO: Implement actual notification sending through notification service
-	// For now, we just log it
-	j.logger.Info("sending notification",
-		"recipient", n.RecipientID,
-		"type", n.Type,
-	)
+	if err := j.notificationSvc.ScheduleNotification(ctx, n); err != nil {
+		return fmt.Errorf("failed to queue notification: %w", err)
+	}
 	return nil
 }
 
@@ -0,0 +1,78 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// queuingNotificationService records notifications scheduled in the outbox.
+type queuingNotificationService struct {
+	notification.NotificationService
+	queued []*notification.Notification
+}
+
+func (s *queuingNotificationService) ScheduleNotification(_ context.Context, n *notification.Notification) error {
+	s.queued = append(s.queued, n)
+	return nil
+}
+
+func TestDetectInactiveJob_DetermineActionCohortOverride(t *testing.T) {
+	cohorts := fakeCohortSettings{"2025-01": {
+		settings.KeyInactivityGentleReminderDays: []byte("1"),
+		settings.KeyInactivityEncouragementDays:  []byte("2"),
+	}}
+	job := NewDetectInactiveJob(nil, nil, nil, nil, nil, cohorts, nil, DefaultDetectInactiveConfig())
+	ctx := context.Background()
+
+	// Global ladder
+	assert.Equal(t, InactivityAction(""), job.determineAction(ctx, "2024-09", 2))
+	assert.Equal(t, ActionGentleReminder, job.determineAction(ctx, "2024-09", 3))
+	assert.Equal(t, ActionNotifyStudyBuddy, job.determineAction(ctx, "2024-09", 7))
+
+	// Cohort override moves earlier steps; the rest keep global values
+	assert.Equal(t, ActionGentleReminder, job.determineAction(ctx, "2025-01", 1))
+	assert.Equal(t, ActionEncouragement, job.determineAction(ctx, "2025-01", 3))
+	assert.Equal(t, ActionNotifyStudyBuddy, job.determineAction(ctx, "2025-01", 7))
+}
+
+func TestDetectInactiveJob_QueuesRemindersInOutbox(t *testing.T) {
+	outbox := &queuingNotificationService{}
+	job := NewDetectInactiveJob(goalStudents{}, nil, outbox, nil, nil, nil, nil, DefaultDetectInactiveConfig())
+	info := &InactiveStudentInfo{
+		Student: &student.Student{
+			ID:          "dana",
+			TelegramID:  7,
+			DisplayName: "Дана",
+			Preferences: student.DefaultNotificationPreferences(),
+		},
+		DaysInactive:      7,
+		StudyBuddies:      []string{"arman"},
+		RecommendedAction: ActionNotifyStudyBuddy,
+	}
+	stats := &DetectInactiveStats{ActionsByType: make(map[InactivityAction]int)}
+
+	require.NoError(t, job.processInactiveStudent(context.Background(), info, stats))
+
+	// Both go to the outbox, where delivery applies mutes and quiet hours
+	// and /notifications finds them
+	require.Len(t, outbox.queued, 2)
+	assert.Equal(t, 1, stats.NotificationsSent)
+	assert.Equal(t, 1, stats.StudyBuddiesNotified)
+	for _, n := range outbox.queued {
+		assert.NotEmpty(t, n.ID)
+		assert.Equal(t, notification.StatusPending, n.Status)
+		assert.Equal(t, notification.NotificationTypeInactivityReminder, n.Type)
+		assert.Positive(t, n.MaxRetries)
+	}
+	assert.Equal(t, notification.RecipientID("dana"), outbox.queued[0].RecipientID)
+	assert.Equal(t, 7, outbox.queued[0].Data.DaysInactive)
+	assert.Equal(t, notification.RecipientID("arman"), outbox.queued[1].RecipientID)
+	assert.Contains(t, outbox.queued[1].Message, "Дана")
+}
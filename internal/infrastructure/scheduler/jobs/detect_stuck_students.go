@@ -0,0 +1,247 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/activity"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/alem"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// DETECT STUCK STUDENTS JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// DetectStuckStudentsJob finds students whose XP stalled while they keep
+// logging in, and publishes a StudentStuck event for each of them with the
+// task they most likely struggle with.
+//
+// Rate limiting and the opt-out live in the event handler, so the job may
+// report the same student on consecutive runs.
+type DetectStuckStudentsJob struct {
+	// Dependencies
+	progressRepo   student.ProgressRepository
+	activityRepo   activity.Repository
+	attempts       TaskAttemptSource
+	eventPublisher shared.EventPublisher
+	logger         *slog.Logger
+
+	// Configuration
+	config DetectStuckStudentsConfig
+	now    func() time.Time
+
+	// State
+	lastRunStats atomic.Value // *DetectStuckStudentsStats
+}
+
+// TaskAttemptSource provides a student's task attempts from the Alem Platform.
+type TaskAttemptSource interface {
+	GetStudentTaskCompletions(ctx context.Context, studentID string) ([]alem.TaskCompletionDTO, error)
+}
+
+// DetectStuckStudentsConfig contains configuration for the detection job.
+type DetectStuckStudentsConfig struct {
+	// Criteria defines what counts as a plateau.
+	Criteria student.PlateauCriteria
+
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultDetectStuckStudentsConfig returns sensible defaults.
+func DefaultDetectStuckStudentsConfig() DetectStuckStudentsConfig {
+	return DetectStuckStudentsConfig{
+		Criteria: student.DefaultPlateauCriteria(),
+		Timeout:  10 * time.Minute,
+	}
+}
+
+// DetectStuckStudentsStats contains statistics from a detection run.
+type DetectStuckStudentsStats struct {
+	StartedAt       time.Time
+	CompletedAt     time.Time
+	Duration        time.Duration
+	Candidates      int
+	StudentsStuck   int
+	StuckWithNoTask int
+	Errors          []error
+}
+
+// NewDetectStuckStudentsJob creates a new detection job.
+func NewDetectStuckStudentsJob(
+	progressRepo student.ProgressRepository,
+	activityRepo activity.Repository,
+	attempts TaskAttemptSource,
+	eventPublisher shared.EventPublisher,
+	logger *slog.Logger,
+	config DetectStuckStudentsConfig,
+) *DetectStuckStudentsJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &DetectStuckStudentsJob{
+		progressRepo:   progressRepo,
+		activityRepo:   activityRepo,
+		attempts:       attempts,
+		eventPublisher: eventPublisher,
+		logger:         logger,
+		config:         config,
+		now:            time.Now,
+	}
+}
+
+// Name returns the job name.
+func (j *DetectStuckStudentsJob) Name() string {
+	return "detect_stuck_students"
+}
+
+// Description returns a human-readable description.
+func (j *DetectStuckStudentsJob) Description() string {
+	return "Finds students stuck on an XP plateau and offers them help"
+}
+
+// Run detects stuck students and publishes StudentStuck events.
+func (j *DetectStuckStudentsJob) Run(ctx context.Context) error {
+	startedAt := j.now()
+	stats := &DetectStuckStudentsStats{StartedAt: startedAt}
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	criteria := j.config.Criteria
+	today := startedAt.UTC().Truncate(24 * time.Hour)
+	plateauStart := today.AddDate(0, 0, -criteria.PlateauDays)
+
+	candidates, err := j.progressRepo.GetPlateauCandidates(ctx, plateauStart, today)
+	if err != nil {
+		return fmt.Errorf("failed to get plateau candidates: %w", err)
+	}
+	stats.Candidates = len(candidates)
+
+	for _, studentID := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+
+		// One extra day covers today's row, which DetectPlateau skips
+		history, err := j.progressRepo.GetDailyGrindHistory(ctx, studentID, criteria.HistoryDays()+1)
+		if err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to get daily grind history", "student_id", studentID, "error", err)
+			continue
+		}
+
+		plateau, stuck := student.DetectPlateau(history, startedAt, criteria)
+		if !stuck {
+			continue
+		}
+		stats.StudentsStuck++
+
+		taskID, err := j.stuckTask(ctx, studentID)
+		if err != nil {
+			// Still worth offering help, just without task-specific buttons
+			j.logger.Warn("failed to find stuck task", "student_id", studentID, "error", err)
+		}
+		if taskID == "" {
+			stats.StuckWithNoTask++
+		}
+
+		event := shared.NewStudentStuckEvent(studentID, taskID, plateau.Days, plateau.OnlineDays)
+		if err := j.eventPublisher.Publish(event); err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to publish StudentStuck event", "student_id", studentID, "error", err)
+		}
+	}
+
+	// Finalize stats
+	stats.CompletedAt = j.now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("detect_stuck_students job completed",
+		"duration", stats.Duration.String(),
+		"candidates", stats.Candidates,
+		"stuck", stats.StudentsStuck,
+		"without_task", stats.StuckWithNoTask,
+		"errors", len(stats.Errors),
+	)
+
+	return nil
+}
+
+// stuckTask returns the most recently attempted task the student has not
+// passed yet, or "" if there is none.
+func (j *DetectStuckStudentsJob) stuckTask(ctx context.Context, studentID string) (string, error) {
+	attempts, err := j.attempts.GetStudentTaskCompletions(ctx, studentID)
+	if err != nil {
+		return "", fmt.Errorf("get task attempts: %w", err)
+	}
+
+	passed := make(map[string]bool)
+	for i := range attempts {
+		if attempts[i].IsSuccessful() {
+			passed[attempts[i].TaskID] = true
+		}
+	}
+
+	var (
+		latestTask string
+		latestAt   time.Time
+	)
+	for i := range attempts {
+		a := &attempts[i]
+		if a.TaskID == "" || passed[a.TaskID] {
+			continue
+		}
+
+		at := attemptTime(a)
+		if latestTask != "" && !at.After(latestAt) {
+			continue
+		}
+
+		// The platform may lag behind our own records
+		done, err := j.activityRepo.HasStudentCompletedTask(ctx, activity.StudentID(studentID), activity.TaskID(a.TaskID))
+		if err != nil {
+			return "", fmt.Errorf("check task completion: %w", err)
+		}
+		if done {
+			passed[a.TaskID] = true
+			continue
+		}
+
+		latestTask, latestAt = a.TaskID, at
+	}
+
+	return latestTask, nil
+}
+
+// attemptTime returns the latest known timestamp of an attempt.
+func attemptTime(a *alem.TaskCompletionDTO) time.Time {
+	var t time.Time
+	if a.StartedAt != nil {
+		t = *a.StartedAt
+	}
+	if a.CompletedAt != nil && a.CompletedAt.After(t) {
+		t = *a.CompletedAt
+	}
+	return t
+}
+
+// LastRunStats returns statistics from the last detection run.
+func (j *DetectStuckStudentsJob) LastRunStats() *DetectStuckStudentsStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*DetectStuckStudentsStats)
+}
@@ -0,0 +1,284 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// ESCALATE HELP REQUESTS JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// EscalateHelpRequestsJob notifies mentors of a task about urgent help
+// requests that nobody responded to within the configured window.
+//
+// A mentor is an experienced helper who solved the task: a high help rating
+// or many past helps. Each request is escalated at most once.
+type EscalateHelpRequestsJob struct {
+	// Dependencies
+	helpRequests social.HelpRequestRepository
+	solvers      TaskSolverFinder
+	studentRepo  student.Repository
+	notifier     NotificationService
+	logger       *slog.Logger
+
+	// Configuration
+	config EscalateHelpRequestsConfig
+	now    func() time.Time
+
+	// State
+	lastRunStats atomic.Value // *EscalateHelpRequestsStats
+}
+
+// TaskSolverFinder finds students who solved a task and accept help requests.
+type TaskSolverFinder interface {
+	FindHelpersForTask(ctx context.Context, taskID string, limit int) ([]*leaderboard.LeaderboardEntry, error)
+}
+
+// EscalateHelpRequestsConfig contains configuration for the escalation job.
+type EscalateHelpRequestsConfig struct {
+	// Window is how long an urgent request may stay without a response.
+	Window time.Duration
+
+	// MaxMentors is the maximum number of mentors notified per request.
+	MaxMentors int
+
+	// MinMentorRating is the help rating that makes a solver a mentor.
+	MinMentorRating float64
+
+	// MinMentorHelpCount is the number of past helps that makes a solver a mentor.
+	MinMentorHelpCount int
+
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultEscalateHelpRequestsConfig returns sensible defaults.
+func DefaultEscalateHelpRequestsConfig() EscalateHelpRequestsConfig {
+	return EscalateHelpRequestsConfig{
+		Window:             30 * time.Minute,
+		MaxMentors:         3,
+		MinMentorRating:    4.0,
+		MinMentorHelpCount: 10,
+		Timeout:            2 * time.Minute,
+	}
+}
+
+// EscalateHelpRequestsStats contains statistics from an escalation run.
+type EscalateHelpRequestsStats struct {
+	StartedAt         time.Time
+	CompletedAt       time.Time
+	Duration          time.Duration
+	RequestsChecked   int
+	RequestsEscalated int
+	MentorsNotified   int
+	RequestsNoMentor  int
+	Errors            []error
+}
+
+// NewEscalateHelpRequestsJob creates a new escalation job.
+func NewEscalateHelpRequestsJob(
+	helpRequests social.HelpRequestRepository,
+	solvers TaskSolverFinder,
+	studentRepo student.Repository,
+	notifier NotificationService,
+	logger *slog.Logger,
+	config EscalateHelpRequestsConfig,
+) *EscalateHelpRequestsJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &EscalateHelpRequestsJob{
+		helpRequests: helpRequests,
+		solvers:      solvers,
+		studentRepo:  studentRepo,
+		notifier:     notifier,
+		logger:       logger,
+		config:       config,
+		now:          time.Now,
+	}
+}
+
+// Name returns the job name.
+func (j *EscalateHelpRequestsJob) Name() string {
+	return "escalate_help_requests"
+}
+
+// Description returns a human-readable description.
+func (j *EscalateHelpRequestsJob) Description() string {
+	return "Notifies task mentors about urgent help requests without a response"
+}
+
+// Run escalates unanswered urgent help requests.
+func (j *EscalateHelpRequestsJob) Run(ctx context.Context) error {
+	startedAt := j.now()
+	stats := &EscalateHelpRequestsStats{StartedAt: startedAt}
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	requests, err := j.helpRequests.GetUnansweredUrgent(ctx, startedAt.Add(-j.config.Window))
+	if err != nil {
+		return fmt.Errorf("failed to get unanswered urgent requests: %w", err)
+	}
+
+	for _, req := range requests {
+		if ctx.Err() != nil {
+			break
+		}
+		// The query is a pre-filter; the entity has the final say
+		if !req.NeedsEscalation(startedAt, j.config.Window) {
+			continue
+		}
+		stats.RequestsChecked++
+
+		notified, err := j.escalate(ctx, req)
+		if err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to escalate help request", "request_id", req.ID, "error", err)
+			continue
+		}
+		if notified == 0 {
+			// Left unmarked so the request is retried when a mentor appears
+			stats.RequestsNoMentor++
+			continue
+		}
+
+		stats.RequestsEscalated++
+		stats.MentorsNotified += notified
+	}
+
+	// Finalize stats
+	stats.CompletedAt = j.now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("escalate_help_requests job completed",
+		"duration", stats.Duration.String(),
+		"checked", stats.RequestsChecked,
+		"escalated", stats.RequestsEscalated,
+		"mentors_notified", stats.MentorsNotified,
+		"without_mentor", stats.RequestsNoMentor,
+	)
+
+	return nil
+}
+
+// escalate notifies the mentors of the request's task and marks the request
+// escalated if anyone was notified. Returns the number of notified mentors.
+func (j *EscalateHelpRequestsJob) escalate(ctx context.Context, req *social.HelpRequest) (int, error) {
+	mentors, err := j.findMentors(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	requesterName := string(req.RequesterID)
+	if requester, err := j.studentRepo.GetByID(ctx, string(req.RequesterID)); err == nil {
+		requesterName = requester.DisplayName
+	}
+
+	waited := j.now().Sub(req.CreatedAt).Round(time.Minute)
+	message := fmt.Sprintf("🆘 <b>%s</b> срочно нужна помощь с задачей <b>%s</b> — уже %d мин без ответа. Ты решил эту задачу, можешь помочь?",
+		html.EscapeString(requesterName), html.EscapeString(req.TaskName), int(waited.Minutes()))
+
+	notified := 0
+	for _, mentor := range mentors {
+		n := &notification.Notification{
+			ID:             notification.NotificationID(uuid.New().String()),
+			RecipientID:    notification.RecipientID(mentor.ID),
+			TelegramChatID: notification.TelegramChatID(mentor.TelegramID),
+			Type:           notification.NotificationTypeHelpRequest,
+			Priority:       notification.PriorityHigh,
+			Status:         notification.StatusPending,
+			Message:        message,
+			CreatedAt:      j.now(),
+		}
+		n.SetMetadata("help_request_id", req.ID)
+
+		if result := j.send(ctx, n, req.ID); !result.Success {
+			if !result.Skipped() {
+				j.logger.Warn("failed to notify mentor", "request_id", req.ID, "mentor_id", mentor.ID, "error", result.Error)
+			}
+			continue
+		}
+		notified++
+	}
+
+	if notified == 0 {
+		return 0, nil
+	}
+
+	req.MarkEscalated(j.now())
+	if err := j.helpRequests.Update(ctx, req); err != nil {
+		return notified, fmt.Errorf("failed to mark request %s escalated: %w", req.ID, err)
+	}
+
+	return notified, nil
+}
+
+// send delivers the alert with the report button when the notifier supports
+// keyboards: the mentor can flag the requester the bot introduced them to.
+func (j *EscalateHelpRequestsJob) send(ctx context.Context, n *notification.Notification, requestID string) notification.DeliveryResult {
+	kn, ok := j.notifier.(KeyboardNotificationService)
+	if !ok {
+		return j.notifier.Send(ctx, n)
+	}
+	return kn.SendWithKeyboard(ctx, n, [][]notification.InlineButton{{
+		notification.NewCallbackButton("⚠️ Пожаловаться", "report:h:"+requestID),
+	}})
+}
+
+// findMentors returns up to MaxMentors experienced solvers of the request's task.
+func (j *EscalateHelpRequestsJob) findMentors(ctx context.Context, req *social.HelpRequest) ([]*student.Student, error) {
+	// Fetch extra solvers: most of them are not experienced enough
+	solvers, err := j.solvers.FindHelpersForTask(ctx, string(req.TaskID), j.config.MaxMentors*5)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find solvers of %s: %w", req.TaskID, err)
+	}
+
+	mentors := make([]*student.Student, 0, j.config.MaxMentors)
+	for _, solver := range solvers {
+		if len(mentors) >= j.config.MaxMentors {
+			break
+		}
+		if solver.StudentID == string(req.RequesterID) {
+			continue
+		}
+
+		s, err := j.studentRepo.GetByID(ctx, solver.StudentID)
+		if err != nil || !s.IsDiscoverableHelper() {
+			continue
+		}
+		if s.HelpRating < j.config.MinMentorRating && s.HelpCount < j.config.MinMentorHelpCount {
+			continue
+		}
+		mentors = append(mentors, s)
+	}
+
+	return mentors, nil
+}
+
+// LastRunStats returns statistics from the last escalation run.
+func (j *EscalateHelpRequestsJob) LastRunStats() *EscalateHelpRequestsStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*EscalateHelpRequestsStats)
+}
@@ -0,0 +1,131 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type fakeHelpRequestRepo struct {
+	social.HelpRequestRepository
+	requests []*social.HelpRequest
+	updated  []string
+}
+
+func (f *fakeHelpRequestRepo) GetUnansweredUrgent(_ context.Context, createdBefore time.Time) ([]*social.HelpRequest, error) {
+	var result []*social.HelpRequest
+	for _, r := range f.requests {
+		if r.Priority == social.HelpRequestPriorityUrgent && r.EscalatedAt == nil &&
+			r.FirstHelperResponseAt == nil && !r.CreatedAt.After(createdBefore) {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeHelpRequestRepo) Update(_ context.Context, r *social.HelpRequest) error {
+	f.updated = append(f.updated, r.ID)
+	return nil
+}
+
+type fakeTaskSolvers map[string][]string
+
+func (f fakeTaskSolvers) FindHelpersForTask(_ context.Context, taskID string, _ int) ([]*leaderboard.LeaderboardEntry, error) {
+	var result []*leaderboard.LeaderboardEntry
+	for _, id := range f[taskID] {
+		result = append(result, &leaderboard.LeaderboardEntry{StudentID: id})
+	}
+	return result, nil
+}
+
+type fakeMentorStudentRepo struct {
+	student.Repository
+	students map[string]*student.Student
+}
+
+func (f *fakeMentorStudentRepo) GetByID(_ context.Context, id string) (*student.Student, error) {
+	if s, ok := f.students[id]; ok {
+		return s, nil
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+type fakeJobNotifier struct {
+	sent []*notification.Notification
+}
+
+func (f *fakeJobNotifier) Send(_ context.Context, n *notification.Notification) notification.DeliveryResult {
+	f.sent = append(f.sent, n)
+	return notification.NewSuccessResult(notification.ChannelTypeTelegram, "1")
+}
+
+func newMentorCandidate(id string, rating float64, helpCount int) *student.Student {
+	return &student.Student{
+		ID:          id,
+		TelegramID:  student.TelegramID(len(id)),
+		DisplayName: id,
+		Status:      student.StatusActive,
+		HelpRating:  rating,
+		HelpCount:   helpCount,
+		Preferences: student.DefaultNotificationPreferences(),
+	}
+}
+
+func TestEscalateHelpRequestsJob_NotifiesMentorsAfterWindow(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+
+	newRequest := func(id string, priority social.HelpRequestPriority, age time.Duration) *social.HelpRequest {
+		req, err := social.NewHelpRequest(social.NewHelpRequestParams{
+			ID: id, RequesterID: "dana", TaskID: "graph-01", TaskName: "graph-01", Priority: priority,
+		})
+		require.NoError(t, err)
+		req.CreatedAt = now.Add(-age)
+		return req
+	}
+
+	stale := newRequest("stale", social.HelpRequestPriorityUrgent, 45*time.Minute)
+	fresh := newRequest("fresh", social.HelpRequestPriorityUrgent, 10*time.Minute)
+	normal := newRequest("normal", social.HelpRequestPriorityNormal, 2*time.Hour)
+	repo := &fakeHelpRequestRepo{requests: []*social.HelpRequest{stale, fresh, normal}}
+
+	students := &fakeMentorStudentRepo{students: map[string]*student.Student{
+		"dana":    newMentorCandidate("dana", 5, 50),
+		"expert":  newMentorCandidate("expert", 4.6, 2),
+		"veteran": newMentorCandidate("veteran", 3.0, 12),
+		"novice":  newMentorCandidate("novice", 3.5, 1),
+	}}
+	solvers := fakeTaskSolvers{"graph-01": {"dana", "novice", "expert", "veteran"}}
+	notifier := &fakeJobNotifier{}
+
+	job := NewEscalateHelpRequestsJob(repo, solvers, students, notifier, nil, DefaultEscalateHelpRequestsConfig())
+	job.now = func() time.Time { return now }
+
+	require.NoError(t, job.Run(context.Background()))
+
+	// Только просроченный срочный запрос, только опытным решившим задачу
+	require.Len(t, notifier.sent, 2)
+	assert.ElementsMatch(t,
+		[]notification.RecipientID{"expert", "veteran"},
+		[]notification.RecipientID{notifier.sent[0].RecipientID, notifier.sent[1].RecipientID},
+	)
+	assert.Equal(t, []string{"stale"}, repo.updated)
+	require.NotNil(t, stale.EscalatedAt)
+	assert.Nil(t, fresh.EscalatedAt)
+
+	stats := job.LastRunStats()
+	require.NotNil(t, stats)
+	assert.Equal(t, 1, stats.RequestsEscalated)
+	assert.Equal(t, 2, stats.MentorsNotified)
+
+	// Повторный запуск не эскалирует запрос снова
+	require.NoError(t, job.Run(context.Background()))
+	assert.Len(t, notifier.sent, 2)
+}
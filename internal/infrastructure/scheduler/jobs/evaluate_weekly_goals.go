@@ -0,0 +1,283 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/saga"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// EVALUATE WEEKLY GOALS JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// EvaluateWeeklyGoalsJob marks weekly XP goals as reached, congratulates the
+// student once, and grants "Goal Getter" after student.GoalGetterWeeks
+// reached weeks in a row.
+//
+// Right after a week ends the previous week is evaluated too, so XP earned
+// in the last hour of the week still counts.
+//
+// The goal is claimed (marked reached) before the congratulation is queued,
+// so it is never queued twice. The congratulation goes through the outbox:
+// DeliverNotificationsJob retries failed sends and holds it through quiet
+// hours. It is lost only if queueing itself fails after the claim.
+type EvaluateWeeklyGoalsJob struct {
+	// Dependencies
+	goals        student.WeeklyGoalRepository
+	progressRepo student.ProgressRepository
+	studentRepo  student.Repository
+	outbox       NotificationScheduler
+	granter      AchievementGranter
+	logger       *slog.Logger
+
+	// Configuration
+	config EvaluateWeeklyGoalsConfig
+	now    func() time.Time
+
+	// State
+	lastRunStats atomic.Value // *EvaluateWeeklyGoalsStats
+}
+
+// NotificationScheduler queues a notification in the outbox, from which
+// DeliverNotificationsJob sends it. Implemented by
+// service.NotificationServiceStub with an outbox.
+type NotificationScheduler interface {
+	ScheduleNotification(ctx context.Context, n *notification.Notification) error
+}
+
+// EvaluateWeeklyGoalsConfig contains configuration for the evaluation job.
+type EvaluateWeeklyGoalsConfig struct {
+	// LateWindow is how long after a week ends it is still evaluated.
+	// Should be longer than the job interval.
+	LateWindow time.Duration
+
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultEvaluateWeeklyGoalsConfig returns sensible defaults.
+func DefaultEvaluateWeeklyGoalsConfig() EvaluateWeeklyGoalsConfig {
+	return EvaluateWeeklyGoalsConfig{
+		LateWindow: 3 * time.Hour,
+		Timeout:    10 * time.Minute,
+	}
+}
+
+// EvaluateWeeklyGoalsStats contains statistics from an evaluation run.
+type EvaluateWeeklyGoalsStats struct {
+	StartedAt         time.Time
+	CompletedAt       time.Time
+	Duration          time.Duration
+	GoalsChecked      int
+	GoalsReached      int
+	CongratsQueued    int
+	AchievementsGiven int
+	Errors            []error
+}
+
+// NewEvaluateWeeklyGoalsJob creates a new evaluation job.
+// A nil granter disables the "Goal Getter" achievement.
+func NewEvaluateWeeklyGoalsJob(
+	goals student.WeeklyGoalRepository,
+	progressRepo student.ProgressRepository,
+	studentRepo student.Repository,
+	outbox NotificationScheduler,
+	granter AchievementGranter,
+	logger *slog.Logger,
+	config EvaluateWeeklyGoalsConfig,
+) *EvaluateWeeklyGoalsJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &EvaluateWeeklyGoalsJob{
+		goals:        goals,
+		progressRepo: progressRepo,
+		studentRepo:  studentRepo,
+		outbox:       outbox,
+		granter:      granter,
+		logger:       logger,
+		config:       config,
+		now:          time.Now,
+	}
+}
+
+// Name returns the job name.
+func (j *EvaluateWeeklyGoalsJob) Name() string {
+	return "evaluate_weekly_goals"
+}
+
+// Description returns a human-readable description.
+func (j *EvaluateWeeklyGoalsJob) Description() string {
+	return "Marks reached weekly XP goals and grants the Goal Getter achievement"
+}
+
+// Run evaluates pending goals of the current (and just finished) week.
+func (j *EvaluateWeeklyGoalsJob) Run(ctx context.Context) error {
+	startedAt := j.now()
+	stats := &EvaluateWeeklyGoalsStats{StartedAt: startedAt}
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	week := student.ISOWeekOf(startedAt)
+	weeks := []student.ISOWeek{week}
+	if start, err := week.Start(); err == nil && startedAt.Sub(start) < j.config.LateWindow {
+		weeks = append(weeks, week.Previous())
+	}
+
+	for _, w := range weeks {
+		if err := j.evaluateWeek(ctx, w, stats); err != nil {
+			return err
+		}
+	}
+
+	// Finalize stats
+	stats.CompletedAt = j.now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("evaluate_weekly_goals job completed",
+		"duration", stats.Duration.String(),
+		"checked", stats.GoalsChecked,
+		"reached", stats.GoalsReached,
+		"congrats_queued", stats.CongratsQueued,
+		"achievements", stats.AchievementsGiven,
+		"errors", len(stats.Errors),
+	)
+
+	return nil
+}
+
+// evaluateWeek checks all pending goals of one week.
+func (j *EvaluateWeeklyGoalsJob) evaluateWeek(ctx context.Context, week student.ISOWeek, stats *EvaluateWeeklyGoalsStats) error {
+	start, err := week.Start()
+	if err != nil {
+		return err
+	}
+	end := start.AddDate(0, 0, 7)
+
+	goals, err := j.goals.GetPending(ctx, week)
+	if err != nil {
+		return fmt.Errorf("failed to get pending weekly goals: %w", err)
+	}
+
+	for _, goal := range goals {
+		if ctx.Err() != nil {
+			break
+		}
+		stats.GoalsChecked++
+
+		weekXP, err := j.progressRepo.GetXPGainedBetween(ctx, goal.StudentID, start, end)
+		if err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to get week xp", "student_id", goal.StudentID, "week", week, "error", err)
+			continue
+		}
+		if weekXP < goal.TargetXP {
+			continue
+		}
+
+		if err := j.reach(ctx, goal, weekXP, stats); err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to process reached goal", "student_id", goal.StudentID, "week", week, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// reach marks the goal as reached and, if this run marked it, queues the
+// congratulation and checks the "Goal Getter" achievement.
+func (j *EvaluateWeeklyGoalsJob) reach(ctx context.Context, goal *student.WeeklyGoal, weekXP student.XP, stats *EvaluateWeeklyGoalsStats) error {
+	now := j.now()
+
+	// Claim first: the congratulation must not be sent twice
+	claimed, err := j.goals.MarkReached(ctx, goal.StudentID, goal.Week, now)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+	goal.MarkReached(now)
+	stats.GoalsReached++
+
+	stud, err := j.studentRepo.GetByID(ctx, goal.StudentID)
+	if err != nil {
+		return fmt.Errorf("get student: %w", err)
+	}
+
+	// Mutes, preferences and quiet hours are applied on delivery
+	priority := notification.NotificationTypeGoalReached.DefaultPriority()
+	n, err := notification.NewNotification(notification.NewNotificationParams{
+		ID:             goalNotificationID(goal),
+		Type:           notification.NotificationTypeGoalReached,
+		RecipientID:    notification.RecipientID(stud.ID),
+		TelegramChatID: notification.TelegramChatID(stud.TelegramID),
+		Message: fmt.Sprintf("🎯 <b>Цель недели выполнена!</b>\n\n%d / %d XP — так держать! 💪",
+			weekXP, goal.TargetXP),
+		Priority: &priority,
+	})
+	if err != nil {
+		j.logger.Warn("failed to build goal congratulation", "student_id", stud.ID, "error", err)
+	} else if err := j.outbox.ScheduleNotification(ctx, n); err != nil {
+		j.logger.Warn("failed to queue goal congratulation", "student_id", stud.ID, "error", err)
+	} else {
+		stats.CongratsQueued++
+	}
+
+	if j.granter == nil {
+		return nil
+	}
+
+	recent, err := j.goals.GetRecent(ctx, goal.StudentID, student.GoalGetterWeeks)
+	if err != nil {
+		return fmt.Errorf("get recent goals: %w", err)
+	}
+	inARow := student.ConsecutiveReachedWeeks(recent, goal.Week)
+	if inARow < student.GoalGetterWeeks {
+		return nil
+	}
+
+	result, err := j.granter.Execute(ctx, saga.AchievementCheckInput{
+		StudentID:    goal.StudentID,
+		TriggerEvent: "weekly_goal_reached",
+		Context: saga.AchievementContext{
+			GoalWeeksInARow: inARow,
+			Timestamp:       now,
+		},
+		OnlyTypes: []student.AchievementType{student.AchievementGoalGetter},
+	})
+	if err != nil {
+		return fmt.Errorf("grant goal getter: %w", err)
+	}
+	stats.AchievementsGiven += len(result.NewAchievements)
+
+	return nil
+}
+
+// goalNotificationID returns the congratulation ID of a goal; a goal is
+// reached once, so the ID is stable.
+func goalNotificationID(goal *student.WeeklyGoal) notification.NotificationID {
+	return notification.NotificationID(fmt.Sprintf("goal:%s:%s", goal.StudentID, goal.Week))
+}
+
+// LastRunStats returns statistics from the last evaluation run.
+func (j *EvaluateWeeklyGoalsJob) LastRunStats() *EvaluateWeeklyGoalsStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*EvaluateWeeklyGoalsStats)
+}
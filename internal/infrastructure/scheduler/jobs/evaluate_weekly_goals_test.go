@@ -0,0 +1,195 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/saga"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// memoryWeeklyGoals keeps goals by student and week like weekly_goals.
+type memoryWeeklyGoals struct {
+	student.WeeklyGoalRepository
+	goals map[string]*student.WeeklyGoal
+}
+
+func newMemoryWeeklyGoals(goals ...*student.WeeklyGoal) *memoryWeeklyGoals {
+	m := &memoryWeeklyGoals{goals: make(map[string]*student.WeeklyGoal)}
+	for _, g := range goals {
+		m.goals[g.StudentID+"/"+string(g.Week)] = g
+	}
+	return m
+}
+
+func (m *memoryWeeklyGoals) GetPending(_ context.Context, week student.ISOWeek) ([]*student.WeeklyGoal, error) {
+	var pending []*student.WeeklyGoal
+	for _, g := range m.goals {
+		if g.Week == week && !g.IsReached() {
+			stored := *g
+			pending = append(pending, &stored)
+		}
+	}
+	return pending, nil
+}
+
+func (m *memoryWeeklyGoals) MarkReached(_ context.Context, studentID string, week student.ISOWeek, at time.Time) (bool, error) {
+	g, ok := m.goals[studentID+"/"+string(week)]
+	if !ok || g.IsReached() {
+		return false, nil
+	}
+	g.MarkReached(at)
+	return true, nil
+}
+
+func (m *memoryWeeklyGoals) GetRecent(_ context.Context, studentID string, weeks int) ([]*student.WeeklyGoal, error) {
+	var recent []*student.WeeklyGoal
+	for _, g := range m.goals {
+		if g.StudentID == studentID {
+			recent = append(recent, g)
+		}
+	}
+	sort.Slice(recent, func(i, k int) bool { return recent[i].Week > recent[k].Week })
+	if len(recent) > weeks {
+		recent = recent[:weeks]
+	}
+	return recent, nil
+}
+
+// weekXPProgress returns the XP of each student's week.
+type weekXPProgress struct {
+	student.ProgressRepository
+	xp map[string]student.XP // student/week -> XP
+}
+
+func (p *weekXPProgress) GetXPGainedBetween(_ context.Context, studentID string, from, _ time.Time) (student.XP, error) {
+	return p.xp[studentID+"/"+string(student.ISOWeekOf(from))], nil
+}
+
+type goalStudents struct {
+	student.Repository
+}
+
+func (goalStudents) GetByID(_ context.Context, id string) (*student.Student, error) {
+	return &student.Student{ID: id, TelegramID: 42}, nil
+}
+
+type recordingScheduler struct {
+	queued []*notification.Notification
+	err    error
+}
+
+func (s *recordingScheduler) ScheduleNotification(_ context.Context, n *notification.Notification) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.queued = append(s.queued, n)
+	return nil
+}
+
+type goalGetterGranter struct {
+	inputs []saga.AchievementCheckInput
+}
+
+func (g *goalGetterGranter) Execute(_ context.Context, input saga.AchievementCheckInput) (*saga.AchievementFlowResult, error) {
+	g.inputs = append(g.inputs, input)
+	return &saga.AchievementFlowResult{
+		StudentID:       input.StudentID,
+		NewAchievements: []student.Achievement{{Type: student.AchievementGoalGetter}},
+	}, nil
+}
+
+func newGoalsJob(goals *memoryWeeklyGoals, progress *weekXPProgress, outbox *recordingScheduler, granter AchievementGranter, now time.Time) *EvaluateWeeklyGoalsJob {
+	job := NewEvaluateWeeklyGoalsJob(goals, progress, goalStudents{}, outbox, granter, nil, DefaultEvaluateWeeklyGoalsConfig())
+	job.now = func() time.Time { return now }
+	return job
+}
+
+func TestEvaluateWeeklyGoalsJob_QueuesCongratulationOnce(t *testing.T) {
+	wednesday := time.Date(2026, time.October, 14, 10, 0, 0, 0, time.UTC)
+	week := student.ISOWeekOf(wednesday)
+	goals := newMemoryWeeklyGoals(
+		&student.WeeklyGoal{StudentID: "dana", Week: week, TargetXP: 500},
+		&student.WeeklyGoal{StudentID: "arman", Week: week, TargetXP: 500},
+	)
+	progress := &weekXPProgress{xp: map[string]student.XP{"dana/" + string(week): 520, "arman/" + string(week): 499}}
+	outbox := &recordingScheduler{}
+	job := newGoalsJob(goals, progress, outbox, nil, wednesday)
+
+	require.NoError(t, job.Run(context.Background()))
+
+	stats := job.LastRunStats()
+	assert.Equal(t, 2, stats.GoalsChecked)
+	assert.Equal(t, 1, stats.GoalsReached)
+	assert.Equal(t, 1, stats.CongratsQueued)
+	assert.True(t, goals.goals["dana/"+string(week)].IsReached())
+	assert.False(t, goals.goals["arman/"+string(week)].IsReached())
+
+	// Queued as pending for DeliverNotificationsJob, which applies mutes and
+	// quiet hours and retries failed sends
+	require.Len(t, outbox.queued, 1)
+	n := outbox.queued[0]
+	assert.Equal(t, notification.NotificationID("goal:dana:"+string(week)), n.ID)
+	assert.Equal(t, notification.NotificationTypeGoalReached, n.Type)
+	assert.Equal(t, notification.StatusPending, n.Status)
+	assert.Equal(t, notification.TelegramChatID(42), n.TelegramChatID)
+	assert.Contains(t, n.Message, "520 / 500 XP")
+
+	// The next run finds the goal already reached
+	require.NoError(t, job.Run(context.Background()))
+	assert.Len(t, outbox.queued, 1)
+}
+
+func TestEvaluateWeeklyGoalsJob_QueueFailureKeepsClaim(t *testing.T) {
+	wednesday := time.Date(2026, time.October, 14, 10, 0, 0, 0, time.UTC)
+	week := student.ISOWeekOf(wednesday)
+	goals := newMemoryWeeklyGoals(&student.WeeklyGoal{StudentID: "dana", Week: week, TargetXP: 500})
+	progress := &weekXPProgress{xp: map[string]student.XP{"dana/" + string(week): 600}}
+	outbox := &recordingScheduler{err: errors.New("connection refused")}
+	job := newGoalsJob(goals, progress, outbox, nil, wednesday)
+
+	require.NoError(t, job.Run(context.Background()))
+
+	// The documented loss: the goal stays reached without a congratulation
+	assert.True(t, goals.goals["dana/"+string(week)].IsReached())
+	assert.Equal(t, 1, job.LastRunStats().GoalsReached)
+	assert.Equal(t, 0, job.LastRunStats().CongratsQueued)
+}
+
+func TestEvaluateWeeklyGoalsJob_LateWindowAndGoalGetter(t *testing.T) {
+	// An hour into Monday the week that just ended is still evaluated
+	monday := time.Date(2026, time.October, 19, 1, 0, 0, 0, time.UTC)
+	lastWeek := student.ISOWeekOf(monday).Previous()
+	reachedAt := monday.AddDate(0, 0, -7)
+
+	var history []*student.WeeklyGoal
+	week := lastWeek
+	for i := 0; i < student.GoalGetterWeeks-1; i++ {
+		week = week.Previous()
+		history = append(history, &student.WeeklyGoal{StudentID: "dana", Week: week, TargetXP: 300, ReachedAt: &reachedAt})
+	}
+	goals := newMemoryWeeklyGoals(append(history, &student.WeeklyGoal{StudentID: "dana", Week: lastWeek, TargetXP: 300})...)
+	progress := &weekXPProgress{xp: map[string]student.XP{"dana/" + string(lastWeek): 310}}
+	granter := &goalGetterGranter{}
+	job := newGoalsJob(goals, progress, &recordingScheduler{}, granter, monday)
+
+	require.NoError(t, job.Run(context.Background()))
+
+	assert.True(t, goals.goals["dana/"+string(lastWeek)].IsReached())
+	require.Len(t, granter.inputs, 1)
+	assert.Equal(t, student.GoalGetterWeeks, granter.inputs[0].Context.GoalWeeksInARow)
+	assert.Equal(t, 1, job.LastRunStats().AchievementsGiven)
+
+	// Outside the late window the previous week is left alone
+	goals.goals["dana/"+string(lastWeek)].ReachedAt = nil
+	job.now = func() time.Time { return monday.Add(DefaultEvaluateWeeklyGoalsConfig().LateWindow) }
+	require.NoError(t, job.Run(context.Background()))
+	assert.Equal(t, 0, job.LastRunStats().GoalsChecked)
+}
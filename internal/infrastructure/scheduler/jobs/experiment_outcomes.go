@@ -0,0 +1,121 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/experiment"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// EXPERIMENT OUTCOMES JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ExperimentOutcomesJob recomputes the per-variant next-day activity of
+// notification experiments into experiment_results. An experiment is
+// recomputed while it runs and for experiment.OutcomeWindow after it ends,
+// until the last exposure's window has closed.
+type ExperimentOutcomesJob struct {
+	// Dependencies
+	experiments experiment.Repository
+	logger      *slog.Logger
+
+	// Configuration
+	now func() time.Time
+
+	// State
+	lastRunStats atomic.Value // *ExperimentOutcomesStats
+}
+
+// ExperimentOutcomesStats contains statistics from a run.
+type ExperimentOutcomesStats struct {
+	StartedAt   time.Time
+	CompletedAt time.Time
+	Duration    time.Duration
+	Experiments int
+	Exposures   int
+	Errors      []error
+}
+
+// NewExperimentOutcomesJob creates a new outcomes job.
+func NewExperimentOutcomesJob(experiments experiment.Repository, logger *slog.Logger) *ExperimentOutcomesJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ExperimentOutcomesJob{
+		experiments: experiments,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Name returns the job name.
+func (j *ExperimentOutcomesJob) Name() string {
+	return "experiment_outcomes"
+}
+
+// Description returns a human-readable description.
+func (j *ExperimentOutcomesJob) Description() string {
+	return "Computes next-day activity per variant of notification experiments"
+}
+
+// Run recomputes the results of every experiment with open outcome windows.
+func (j *ExperimentOutcomesJob) Run(ctx context.Context) error {
+	startedAt := j.now()
+	stats := &ExperimentOutcomesStats{StartedAt: startedAt}
+
+	active, err := j.experiments.ListEndedSince(ctx, startedAt.Add(-experiment.OutcomeWindow))
+	if err != nil {
+		return fmt.Errorf("failed to list experiments: %w", err)
+	}
+
+	for _, e := range active {
+		if ctx.Err() != nil {
+			break
+		}
+		exposures, err := j.experiments.ListExposures(ctx, e.ID)
+		if err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to list experiment exposures", "experiment", e.Name, "error", err)
+			continue
+		}
+
+		results := e.Summarize(exposures, startedAt)
+		if err := j.experiments.SaveResults(ctx, e.ID, results, startedAt); err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to save experiment results", "experiment", e.Name, "error", err)
+			continue
+		}
+		stats.Experiments++
+		stats.Exposures += len(exposures)
+	}
+
+	// Finalize stats
+	stats.CompletedAt = j.now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	if stats.Experiments > 0 || len(stats.Errors) > 0 {
+		j.logger.Info("experiment_outcomes job completed",
+			"duration", stats.Duration.String(),
+			"experiments", stats.Experiments,
+			"exposures", stats.Exposures,
+			"errors", len(stats.Errors),
+		)
+	}
+
+	return nil
+}
+
+// LastRunStats returns statistics from the last run.
+func (j *ExperimentOutcomesJob) LastRunStats() *ExperimentOutcomesStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*ExperimentOutcomesStats)
+}
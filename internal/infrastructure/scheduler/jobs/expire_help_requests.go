@@ -0,0 +1,131 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// EXPIRE HELP REQUESTS JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// HelpRequestExpirer marks overdue help requests expired.
+// Implemented by social.HelpRequestRepository.
+type HelpRequestExpirer interface {
+	MarkExpiredRequests(ctx context.Context, now time.Time) (int, error)
+}
+
+// ExpireHelpRequestsJob closes help requests that nobody took before their
+// ExpiresAt, so they stop showing up in helper matching.
+type ExpireHelpRequestsJob struct {
+	// Dependencies
+	helpRequests HelpRequestExpirer
+	logger       *slog.Logger
+
+	// Configuration
+	config ExpireHelpRequestsConfig
+	clock  shared.Clock
+
+	// State
+	lastRunStats atomic.Value // *ExpireHelpRequestsStats
+}
+
+// ExpireHelpRequestsConfig contains configuration for the expiry job.
+type ExpireHelpRequestsConfig struct {
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultExpireHelpRequestsConfig returns sensible defaults.
+func DefaultExpireHelpRequestsConfig() ExpireHelpRequestsConfig {
+	return ExpireHelpRequestsConfig{
+		Timeout: time.Minute,
+	}
+}
+
+// ExpireHelpRequestsStats contains statistics from an expiry run.
+type ExpireHelpRequestsStats struct {
+	StartedAt   time.Time
+	CompletedAt time.Time
+	Duration    time.Duration
+	Expired     int
+}
+
+// NewExpireHelpRequestsJob creates a new expiry job.
+func NewExpireHelpRequestsJob(
+	helpRequests HelpRequestExpirer,
+	logger *slog.Logger,
+	config ExpireHelpRequestsConfig,
+) *ExpireHelpRequestsJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ExpireHelpRequestsJob{
+		helpRequests: helpRequests,
+		logger:       logger,
+		config:       config,
+		clock:        shared.SystemClock{},
+	}
+}
+
+// WithClock sets the clock used to decide which requests are overdue.
+func (j *ExpireHelpRequestsJob) WithClock(clock shared.Clock) *ExpireHelpRequestsJob {
+	j.clock = shared.ClockOrSystem(clock)
+	return j
+}
+
+// Name returns the job name.
+func (j *ExpireHelpRequestsJob) Name() string {
+	return "expire_help_requests"
+}
+
+// Description returns a human-readable description.
+func (j *ExpireHelpRequestsJob) Description() string {
+	return "Marks help requests expired once nobody took them before the deadline"
+}
+
+// Run expires overdue help requests.
+func (j *ExpireHelpRequestsJob) Run(ctx context.Context) error {
+	startedAt := j.clock.Now()
+	stats := &ExpireHelpRequestsStats{StartedAt: startedAt}
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	expired, err := j.helpRequests.MarkExpiredRequests(ctx, startedAt)
+	if err != nil {
+		return fmt.Errorf("failed to expire help requests: %w", err)
+	}
+	stats.Expired = expired
+
+	// Finalize stats
+	stats.CompletedAt = j.clock.Now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("expire_help_requests job completed",
+		"duration", stats.Duration.String(),
+		"expired", stats.Expired,
+	)
+
+	return nil
+}
+
+// LastRunStats returns statistics from the last expiry run.
+func (j *ExpireHelpRequestsJob) LastRunStats() *ExpireHelpRequestsStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*ExpireHelpRequestsStats)
+}
@@ -0,0 +1,273 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/saga"
+	"github.com/alem-hub/alem-community-hub/internal/domain/event"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// FINALIZE EVENTS JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// FinalizeEventsJob freezes the results of community events whose window has
+// ended: the live scoreboard is copied into event_results, everyone with a
+// nonzero score gets the participation achievement, and the winners are
+// announced in the announcement chat.
+//
+// Achievements are granted before the event is marked finalized: the grant
+// is idempotent, so a crash in between only repeats it, while the
+// announcement is sent once by the run that finalized the event.
+type FinalizeEventsJob struct {
+	// Dependencies
+	events      event.Repository
+	scoreboard  event.Scoreboard
+	studentRepo student.Repository
+	announcer   Announcer
+	granter     AchievementGranter
+	logger      *slog.Logger
+
+	// Configuration
+	config FinalizeEventsConfig
+	now    func() time.Time
+
+	// State
+	lastRunStats atomic.Value // *FinalizeEventsStats
+}
+
+// FinalizeEventsConfig contains configuration for the finalize job.
+type FinalizeEventsConfig struct {
+	// AnnouncementChatID is the chat the winners are announced in
+	// (0 disables announcements).
+	AnnouncementChatID int64
+
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultFinalizeEventsConfig returns sensible defaults.
+func DefaultFinalizeEventsConfig() FinalizeEventsConfig {
+	return FinalizeEventsConfig{
+		Timeout: 5 * time.Minute,
+	}
+}
+
+// FinalizeEventsStats contains statistics from a finalize run.
+type FinalizeEventsStats struct {
+	StartedAt         time.Time
+	CompletedAt       time.Time
+	Duration          time.Duration
+	EventsFinalized   int
+	Participants      int
+	AchievementsGiven int
+	Announced         int
+	Errors            []error
+}
+
+// NewFinalizeEventsJob creates a new finalize job.
+// A nil announcer disables announcements, a nil granter the achievement.
+func NewFinalizeEventsJob(
+	events event.Repository,
+	scoreboard event.Scoreboard,
+	studentRepo student.Repository,
+	announcer Announcer,
+	granter AchievementGranter,
+	logger *slog.Logger,
+	config FinalizeEventsConfig,
+) *FinalizeEventsJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &FinalizeEventsJob{
+		events:      events,
+		scoreboard:  scoreboard,
+		studentRepo: studentRepo,
+		announcer:   announcer,
+		granter:     granter,
+		logger:      logger,
+		config:      config,
+		now:         time.Now,
+	}
+}
+
+// Name returns the job name.
+func (j *FinalizeEventsJob) Name() string {
+	return "finalize_events"
+}
+
+// Description returns a human-readable description.
+func (j *FinalizeEventsJob) Description() string {
+	return "Freezes results of ended community events and announces the winners"
+}
+
+// Run finalizes all open events whose window has ended.
+func (j *FinalizeEventsJob) Run(ctx context.Context) error {
+	startedAt := j.now()
+	stats := &FinalizeEventsStats{StartedAt: startedAt}
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	open, err := j.events.ListOpen(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list open events: %w", err)
+	}
+
+	for _, e := range open {
+		if ctx.Err() != nil {
+			break
+		}
+		if !e.HasEnded(startedAt) {
+			continue
+		}
+		if err := j.finalize(ctx, e, stats); err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to finalize event", "event_id", e.ID, "error", err)
+		}
+	}
+
+	// Finalize stats
+	stats.CompletedAt = j.now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	if stats.EventsFinalized > 0 || len(stats.Errors) > 0 {
+		j.logger.Info("finalize_events job completed",
+			"duration", stats.Duration.String(),
+			"finalized", stats.EventsFinalized,
+			"participants", stats.Participants,
+			"achievements", stats.AchievementsGiven,
+			"announced", stats.Announced,
+			"errors", len(stats.Errors),
+		)
+	}
+
+	return nil
+}
+
+// finalize freezes one event.
+func (j *FinalizeEventsJob) finalize(ctx context.Context, e *event.Event, stats *FinalizeEventsStats) error {
+	standings, err := j.scoreboard.All(ctx, e.ID)
+	if err != nil {
+		return fmt.Errorf("get scoreboard: %w", err)
+	}
+	results := event.RankStandings(standings)
+
+	if j.granter != nil {
+		for _, res := range results {
+			result, err := j.granter.Execute(ctx, saga.AchievementCheckInput{
+				StudentID:    res.StudentID,
+				TriggerEvent: "event_finished",
+				Context: saga.AchievementContext{
+					EventID:    e.ID,
+					EventScore: res.Score,
+					Timestamp:  e.EndsAt,
+				},
+				OnlyTypes: []student.AchievementType{student.AchievementEventParticipant},
+			})
+			if err != nil {
+				// The results must be frozen anyway; the student keeps the score
+				j.logger.Warn("failed to grant event achievement", "event_id", e.ID, "student_id", res.StudentID, "error", err)
+				continue
+			}
+			stats.AchievementsGiven += len(result.NewAchievements)
+		}
+	}
+
+	finalized, err := j.events.Finalize(ctx, e.ID, results, j.now())
+	if err != nil {
+		return fmt.Errorf("save results: %w", err)
+	}
+	if !finalized {
+		return nil
+	}
+	stats.EventsFinalized++
+	stats.Participants += len(results)
+
+	j.logger.Info("event finalized", "event_id", e.ID, "name", e.Name, "participants", len(results))
+
+	if j.announcer == nil || j.config.AnnouncementChatID == 0 {
+		return nil
+	}
+
+	// Queued during the chat's quiet hours; a re-run is collapsed by the key
+	_, err = j.announcer.Announce(ctx, notification.Announcement{
+		ChatID:   j.config.AnnouncementChatID,
+		DedupKey: fmt.Sprintf("event:%s:winners", e.ID),
+		Message:  j.announcement(ctx, e, results),
+	})
+	if err != nil {
+		return fmt.Errorf("announce winners: %w", err)
+	}
+	stats.Announced++
+
+	return nil
+}
+
+// announcement renders the winners message.
+func (j *FinalizeEventsJob) announcement(ctx context.Context, e *event.Event, results []event.Standing) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🏁 <b>Челлендж «%s» завершён!</b>\n\n", html.EscapeString(e.Name))
+
+	winners := event.Winners(results)
+	if len(winners) == 0 {
+		b.WriteString("В этот раз никто не набрал очков. Ждём вас в следующем челлендже! 💪")
+		return b.String()
+	}
+
+	names := j.displayNames(ctx, winners)
+	medals := map[int]string{1: "🥇", 2: "🥈", 3: "🥉"}
+	for _, w := range winners {
+		fmt.Fprintf(&b, "%s %s — %d %s\n", medals[w.Rank], html.EscapeString(names[w.StudentID]), w.Score, e.Metric.Unit())
+	}
+
+	fmt.Fprintf(&b, "\nУчастников: %d. Спасибо всем — каждый получил достижение «Участник челленджа» 🎉", len(results))
+	return b.String()
+}
+
+// displayNames resolves winner names; unknown students are shown anonymously.
+func (j *FinalizeEventsJob) displayNames(ctx context.Context, winners []event.Standing) map[string]string {
+	names := make(map[string]string, len(winners))
+	for _, w := range winners {
+		names[w.StudentID] = "Студент"
+	}
+
+	ids := make([]string, len(winners))
+	for i, w := range winners {
+		ids[i] = w.StudentID
+	}
+	students, err := j.studentRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		j.logger.Warn("failed to resolve winner names", "error", err)
+		return names
+	}
+	for _, s := range students {
+		if s.DisplayName != "" {
+			names[s.ID] = s.DisplayName
+		}
+	}
+	return names
+}
+
+// LastRunStats returns statistics from the last finalize run.
+func (j *FinalizeEventsJob) LastRunStats() *FinalizeEventsStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*FinalizeEventsStats)
+}
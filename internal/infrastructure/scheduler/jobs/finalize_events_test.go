@@ -0,0 +1,232 @@
+package jobs
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/saga"
+	"github.com/alem-hub/alem-community-hub/internal/domain/event"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// memoryEvents keeps events and their frozen results like the events and
+// event_results tables. It counts ListOpen calls.
+type memoryEvents struct {
+	event.Repository
+	mu        sync.Mutex
+	events    map[string]*event.Event
+	results   map[string][]event.Standing
+	listCalls int
+}
+
+func newMemoryEvents(events ...*event.Event) *memoryEvents {
+	m := &memoryEvents{events: make(map[string]*event.Event), results: make(map[string][]event.Standing)}
+	for _, e := range events {
+		m.events[e.ID] = e
+	}
+	return m
+}
+
+func (m *memoryEvents) ListOpen(context.Context) ([]*event.Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listCalls++
+	var open []*event.Event
+	for _, e := range m.events {
+		if e.Status != event.StatusFinalized {
+			stored := *e
+			open = append(open, &stored)
+		}
+	}
+	sort.Slice(open, func(i, k int) bool { return open[i].StartsAt.Before(open[k].StartsAt) })
+	return open, nil
+}
+
+func (m *memoryEvents) Finalize(_ context.Context, eventID string, results []event.Standing, finalizedAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.events[eventID]
+	if e.Status == event.StatusFinalized {
+		return false, nil
+	}
+	e.Status = event.StatusFinalized
+	e.FinalizedAt = &finalizedAt
+	m.results[eventID] = results
+	return true, nil
+}
+
+// memoryScoreboard is the live scoreboard of each event.
+type memoryScoreboard struct {
+	event.Scoreboard
+	mu     sync.Mutex
+	scores map[string]map[string]int
+}
+
+func newMemoryScoreboard() *memoryScoreboard {
+	return &memoryScoreboard{scores: make(map[string]map[string]int)}
+}
+
+func (b *memoryScoreboard) AddScore(_ context.Context, eventID, studentID string, score int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.scores[eventID] == nil {
+		b.scores[eventID] = make(map[string]int)
+	}
+	b.scores[eventID][studentID] += score
+	return nil
+}
+
+func (b *memoryScoreboard) All(_ context.Context, eventID string) ([]event.Standing, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var standings []event.Standing
+	for id, score := range b.scores[eventID] {
+		standings = append(standings, event.Standing{StudentID: id, Score: score})
+	}
+	return standings, nil
+}
+
+type recordingAnnouncer struct {
+	Announcer
+	sent []notification.Announcement
+}
+
+func (a *recordingAnnouncer) Announce(_ context.Context, ann notification.Announcement) (bool, error) {
+	a.sent = append(a.sent, ann)
+	return false, nil
+}
+
+type eventGranter struct {
+	granted map[string][]string // event ID -> students
+}
+
+func (g *eventGranter) Execute(_ context.Context, input saga.AchievementCheckInput) (*saga.AchievementFlowResult, error) {
+	g.granted[input.Context.EventID] = append(g.granted[input.Context.EventID], input.StudentID)
+	return &saga.AchievementFlowResult{
+		StudentID:       input.StudentID,
+		NewAchievements: []student.Achievement{{Type: student.AchievementEventParticipant}},
+	}, nil
+}
+
+type namedStudents struct {
+	student.Repository
+	names map[string]string
+}
+
+func (n *namedStudents) GetByIDs(_ context.Context, ids []string) ([]*student.Student, error) {
+	var found []*student.Student
+	for _, id := range ids {
+		if name, ok := n.names[id]; ok {
+			found = append(found, &student.Student{ID: id, DisplayName: name})
+		}
+	}
+	return found, nil
+}
+
+func TestFinalizeEventsJob_OverlappingEvents(t *testing.T) {
+	now := time.Date(2025, 6, 20, 12, 0, 0, 0, time.UTC)
+	sprint := &event.Event{ID: "sprint", Name: "Спринт", StartsAt: now.Add(-72 * time.Hour), EndsAt: now.Add(-time.Hour), Metric: event.MetricXPGained, Status: event.StatusOpen}
+	marathon := &event.Event{ID: "marathon", Name: "Марафон", StartsAt: now.Add(-96 * time.Hour), EndsAt: now, Metric: event.MetricTasksCompleted, Status: event.StatusOpen}
+	live := &event.Event{ID: "live", Name: "Неделя", StartsAt: now.Add(-24 * time.Hour), EndsAt: now.Add(24 * time.Hour), Metric: event.MetricXPGained, Status: event.StatusOpen}
+
+	events := newMemoryEvents(sprint, marathon, live)
+	board := newMemoryScoreboard()
+	ctx := context.Background()
+	for _, s := range []struct {
+		event, student string
+		score          int
+	}{
+		{"sprint", "dana", 500}, {"sprint", "arman", 300}, {"sprint", "aida", 500},
+		{"marathon", "arman", 4}, {"marathon", "dana", 2},
+		{"live", "dana", 100},
+	} {
+		require.NoError(t, board.AddScore(ctx, s.event, s.student, s.score))
+	}
+
+	announcer := &recordingAnnouncer{}
+	granter := &eventGranter{granted: make(map[string][]string)}
+	students := &namedStudents{names: map[string]string{"dana": "Дана", "arman": "Арман"}}
+	config := DefaultFinalizeEventsConfig()
+	config.AnnouncementChatID = -100
+	job := NewFinalizeEventsJob(events, board, students, announcer, granter, nil, config)
+	job.now = func() time.Time { return now }
+
+	require.NoError(t, job.Run(ctx))
+
+	stats := job.LastRunStats()
+	assert.Equal(t, 2, stats.EventsFinalized)
+	assert.Equal(t, 5, stats.Participants)
+	assert.Equal(t, 5, stats.AchievementsGiven)
+	assert.Equal(t, 2, stats.Announced)
+
+	// Scoreboards are frozen per event; equal scores share a place
+	assert.Equal(t, []event.Standing{
+		{StudentID: "aida", Score: 500, Rank: 1},
+		{StudentID: "dana", Score: 500, Rank: 1},
+		{StudentID: "arman", Score: 300, Rank: 3},
+	}, events.results["sprint"])
+	assert.Equal(t, []event.Standing{
+		{StudentID: "arman", Score: 4, Rank: 1},
+		{StudentID: "dana", Score: 2, Rank: 2},
+	}, events.results["marathon"])
+	assert.NotContains(t, events.results, "live", "a live event is not finalized")
+	assert.Equal(t, event.StatusOpen, live.Status)
+
+	assert.ElementsMatch(t, []string{"aida", "dana", "arman"}, granter.granted["sprint"])
+	assert.ElementsMatch(t, []string{"arman", "dana"}, granter.granted["marathon"])
+
+	// The chat gets one message per event
+	require.Len(t, announcer.sent, 2)
+	keys := []string{announcer.sent[0].DedupKey, announcer.sent[1].DedupKey}
+	assert.ElementsMatch(t, []string{"event:sprint:winners", "event:marathon:winners"}, keys)
+	for _, ann := range announcer.sent {
+		assert.Equal(t, int64(-100), ann.ChatID)
+		if ann.DedupKey == "event:sprint:winners" {
+			assert.Contains(t, ann.Message, "🥇 Студент — 500")
+			assert.Contains(t, ann.Message, "🥇 Дана — 500")
+			assert.Contains(t, ann.Message, "🥉 Арман — 300")
+		}
+	}
+}
+
+func TestFinalizeEventsJob_RerunIsIdempotent(t *testing.T) {
+	now := time.Date(2025, 6, 20, 12, 0, 0, 0, time.UTC)
+	sprint := &event.Event{ID: "sprint", Name: "Спринт", StartsAt: now.Add(-72 * time.Hour), EndsAt: now.Add(-time.Hour), Metric: event.MetricXPGained, Status: event.StatusOpen}
+	events := newMemoryEvents(sprint)
+	board := newMemoryScoreboard()
+	ctx := context.Background()
+	require.NoError(t, board.AddScore(ctx, "sprint", "dana", 500))
+
+	announcer := &recordingAnnouncer{}
+	granter := &eventGranter{granted: make(map[string][]string)}
+	config := DefaultFinalizeEventsConfig()
+	config.AnnouncementChatID = -100
+	job := NewFinalizeEventsJob(events, board, &namedStudents{}, announcer, granter, nil, config)
+	job.now = func() time.Time { return now }
+
+	require.NoError(t, job.Run(ctx))
+	require.Len(t, announcer.sent, 1)
+	frozen := events.results["sprint"]
+
+	// A later score does not change the frozen results
+	require.NoError(t, board.AddScore(ctx, "sprint", "arman", 900))
+	require.NoError(t, job.Run(ctx))
+	assert.Equal(t, 0, job.LastRunStats().EventsFinalized)
+
+	// A run that loaded the event before it was finalized grants again
+	// (the grant is idempotent) but neither refreezes nor re-announces
+	stats := &FinalizeEventsStats{}
+	require.NoError(t, job.finalize(ctx, sprint, stats))
+	assert.Equal(t, 0, stats.EventsFinalized)
+	assert.Equal(t, 0, stats.Announced)
+
+	assert.Equal(t, frozen, events.results["sprint"])
+	assert.Len(t, announcer.sent, 1)
+}
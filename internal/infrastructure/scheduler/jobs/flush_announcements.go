@@ -0,0 +1,140 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// FLUSH ANNOUNCEMENTS JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Announcer sends group chat announcements, holding them back during the
+// chat's quiet hours. Implemented by service.Announcer.
+type Announcer interface {
+	// Announce sends the announcement or queues it; true when queued.
+	Announce(ctx context.Context, ann notification.Announcement) (bool, error)
+
+	// FlushDue delivers due queued announcements in order, collapsing
+	// duplicates, and returns how many were sent and collapsed.
+	FlushDue(ctx context.Context, limit int) (sent, collapsed int, err error)
+}
+
+// FlushAnnouncementsJob delivers announcements queued during quiet hours
+// once the window is over.
+type FlushAnnouncementsJob struct {
+	// Dependencies
+	announcer Announcer
+	logger    *slog.Logger
+
+	// Configuration
+	config FlushAnnouncementsConfig
+
+	// State
+	lastRunStats atomic.Value // *FlushAnnouncementsStats
+}
+
+// FlushAnnouncementsConfig contains configuration for the flush job.
+type FlushAnnouncementsConfig struct {
+	// BatchSize is how many queued announcements one run looks at.
+	BatchSize int
+
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultFlushAnnouncementsConfig returns sensible defaults.
+func DefaultFlushAnnouncementsConfig() FlushAnnouncementsConfig {
+	return FlushAnnouncementsConfig{
+		BatchSize: 100,
+		Timeout:   2 * time.Minute,
+	}
+}
+
+// FlushAnnouncementsStats contains statistics from a flush run.
+type FlushAnnouncementsStats struct {
+	StartedAt   time.Time
+	CompletedAt time.Time
+	Duration    time.Duration
+	Sent        int
+	Collapsed   int
+}
+
+// NewFlushAnnouncementsJob creates a new flush job.
+func NewFlushAnnouncementsJob(
+	announcer Announcer,
+	logger *slog.Logger,
+	config FlushAnnouncementsConfig,
+) *FlushAnnouncementsJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultFlushAnnouncementsConfig().BatchSize
+	}
+
+	return &FlushAnnouncementsJob{
+		announcer: announcer,
+		logger:    logger,
+		config:    config,
+	}
+}
+
+// Name returns the job name.
+func (j *FlushAnnouncementsJob) Name() string {
+	return "flush_announcements"
+}
+
+// Description returns a human-readable description.
+func (j *FlushAnnouncementsJob) Description() string {
+	return "Delivers group chat announcements held back during quiet hours"
+}
+
+// Run delivers every due announcement.
+func (j *FlushAnnouncementsJob) Run(ctx context.Context) error {
+	startedAt := time.Now()
+	stats := &FlushAnnouncementsStats{StartedAt: startedAt}
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	sent, collapsed, err := j.announcer.FlushDue(ctx, j.config.BatchSize)
+	stats.Sent = sent
+	stats.Collapsed = collapsed
+
+	// Finalize stats
+	stats.CompletedAt = time.Now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	if sent > 0 || collapsed > 0 {
+		j.logger.Info("flush_announcements job completed",
+			"duration", stats.Duration.String(),
+			"sent", sent,
+			"collapsed", collapsed,
+		)
+	}
+
+	if err != nil {
+		return fmt.Errorf("flush announcements: %w", err)
+	}
+	return nil
+}
+
+// LastRunStats returns statistics from the last flush run.
+func (j *FlushAnnouncementsJob) LastRunStats() *FlushAnnouncementsStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*FlushAnnouncementsStats)
+}
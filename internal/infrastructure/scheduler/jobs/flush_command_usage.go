@@ -0,0 +1,158 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/analytics"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// FLUSH COMMAND USAGE JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// FlushCommandUsageJob copies the previous day's command counters from Redis
+// into PostgreSQL and removes rows older than the retention period.
+//
+// Redis hashes are left to expire on their own, and rows are overwritten
+// rather than incremented, so re-running the job for the same day is safe.
+type FlushCommandUsageJob struct {
+	// Dependencies
+	counter analytics.UsageCounter
+	repo    analytics.UsageRepository
+	logger  *slog.Logger
+
+	// Configuration
+	config FlushCommandUsageConfig
+
+	// State
+	lastRunStats atomic.Value // *FlushCommandUsageStats
+}
+
+// FlushCommandUsageConfig contains configuration for the flush job.
+type FlushCommandUsageConfig struct {
+	// Retention is how long daily counters are kept in PostgreSQL.
+	Retention time.Duration
+
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultFlushCommandUsageConfig returns sensible defaults.
+func DefaultFlushCommandUsageConfig() FlushCommandUsageConfig {
+	return FlushCommandUsageConfig{
+		Retention: 183 * 24 * time.Hour, // ~6 months
+		Timeout:   2 * time.Minute,
+	}
+}
+
+// FlushCommandUsageStats contains statistics from a flush run.
+type FlushCommandUsageStats struct {
+	StartedAt   time.Time
+	CompletedAt time.Time
+	Duration    time.Duration
+	Day         time.Time
+	RowsFlushed int
+	RowsPurged  int64
+}
+
+// NewFlushCommandUsageJob creates a new flush job.
+func NewFlushCommandUsageJob(
+	counter analytics.UsageCounter,
+	repo analytics.UsageRepository,
+	logger *slog.Logger,
+	config FlushCommandUsageConfig,
+) *FlushCommandUsageJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &FlushCommandUsageJob{
+		counter: counter,
+		repo:    repo,
+		logger:  logger,
+		config:  config,
+	}
+}
+
+// Name returns the job name.
+func (j *FlushCommandUsageJob) Name() string {
+	return "flush_command_usage"
+}
+
+// Description returns a human-readable description.
+func (j *FlushCommandUsageJob) Description() string {
+	return "Flushes yesterday's command usage counters from Redis to PostgreSQL"
+}
+
+// Run flushes the previous UTC day and purges expired rows.
+func (j *FlushCommandUsageJob) Run(ctx context.Context) error {
+	return j.FlushDay(ctx, analytics.DayOf(time.Now()).AddDate(0, 0, -1))
+}
+
+// FlushDay flushes the counters of a single day and purges expired rows.
+func (j *FlushCommandUsageJob) FlushDay(ctx context.Context, day time.Time) error {
+	startedAt := time.Now()
+	day = analytics.DayOf(day)
+	stats := &FlushCommandUsageStats{
+		StartedAt: startedAt,
+		Day:       day,
+	}
+
+	j.logger.Info("starting flush_command_usage job", "day", day.Format("2006-01-02"))
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	usage, err := j.counter.GetDay(ctx, day)
+	if err != nil {
+		return fmt.Errorf("failed to read usage counters: %w", err)
+	}
+
+	// An empty hash means Redis lost or already expired the day;
+	// keep whatever was flushed before instead of zeroing it.
+	if len(usage) > 0 {
+		if err := j.repo.SaveDay(ctx, day, usage); err != nil {
+			return fmt.Errorf("failed to save usage: %w", err)
+		}
+		stats.RowsFlushed = len(usage)
+	}
+
+	if j.config.Retention > 0 {
+		purged, err := j.repo.DeleteBefore(ctx, day.Add(-j.config.Retention))
+		if err != nil {
+			j.logger.Warn("failed to purge old command usage", "error", err)
+		}
+		stats.RowsPurged = purged
+	}
+
+	// Finalize stats
+	stats.CompletedAt = time.Now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("flush_command_usage job completed",
+		"duration", stats.Duration.String(),
+		"day", day.Format("2006-01-02"),
+		"rows_flushed", stats.RowsFlushed,
+		"rows_purged", stats.RowsPurged,
+	)
+
+	return nil
+}
+
+// LastRunStats returns statistics from the last flush run.
+func (j *FlushCommandUsageJob) LastRunStats() *FlushCommandUsageStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*FlushCommandUsageStats)
+}
@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/analytics"
+)
+
+type fakeUsageCounter struct {
+	days map[time.Time][]analytics.CommandUsage
+}
+
+func (f *fakeUsageCounter) Increment(context.Context, time.Time, string, string) error {
+	return nil
+}
+
+func (f *fakeUsageCounter) GetDay(_ context.Context, day time.Time) ([]analytics.CommandUsage, error) {
+	return f.days[analytics.DayOf(day)], nil
+}
+
+type usageRowKey struct {
+	day             time.Time
+	command, cohort string
+}
+
+type fakeUsageRepo struct {
+	rows map[usageRowKey]int64
+}
+
+func (f *fakeUsageRepo) SaveDay(_ context.Context, day time.Time, usage []analytics.CommandUsage) error {
+	for _, u := range usage {
+		f.rows[usageRowKey{analytics.DayOf(day), u.Command, u.Cohort}] = u.Count
+	}
+	return nil
+}
+
+func (f *fakeUsageRepo) GetRange(context.Context, time.Time, time.Time) ([]analytics.CommandUsage, error) {
+	return nil, nil
+}
+
+func (f *fakeUsageRepo) DeleteBefore(_ context.Context, day time.Time) (int64, error) {
+	var deleted int64
+	for k := range f.rows {
+		if k.day.Before(day) {
+			delete(f.rows, k)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func TestFlushCommandUsageJob_RerunIsIdempotent(t *testing.T) {
+	day := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	counter := &fakeUsageCounter{days: map[time.Time][]analytics.CommandUsage{
+		day: {
+			{Day: day, Command: "top", Cohort: "2024-09", Count: 12},
+			{Day: day, Command: "top", Cohort: analytics.CohortUnknown, Count: 3},
+			{Day: day, Command: "cb:refresh", Cohort: "2024-09", Count: 5},
+		},
+	}}
+	stale := usageRowKey{day.AddDate(-1, 0, 0), "me", "2024-09"}
+	repo := &fakeUsageRepo{rows: map[usageRowKey]int64{stale: 7}}
+
+	job := NewFlushCommandUsageJob(counter, repo, nil, DefaultFlushCommandUsageConfig())
+	require.NoError(t, job.FlushDay(context.Background(), day))
+	first := make(map[usageRowKey]int64, len(repo.rows))
+	for k, v := range repo.rows {
+		first[k] = v
+	}
+
+	require.NoError(t, job.FlushDay(context.Background(), day.Add(15*time.Hour)))
+
+	assert.Equal(t, first, repo.rows, "re-running a day must not double the counts")
+	assert.Equal(t, int64(12), repo.rows[usageRowKey{day, "top", "2024-09"}])
+	assert.NotContains(t, repo.rows, stale, "rows past retention are purged")
+
+	// Redis expired the day: previously flushed rows stay intact
+	counter.days = nil
+	require.NoError(t, job.FlushDay(context.Background(), day))
+	assert.Equal(t, first, repo.rows)
+	assert.Equal(t, 0, job.LastRunStats().RowsFlushed)
+}
@@ -0,0 +1,214 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MIGRATE PREFERENCES JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// PreferencesStore gives raw access to stored notification preferences.
+// Implemented by postgres.StudentRepository.
+type PreferencesStore interface {
+	// ListStoredPreferences returns blobs ordered by student ID after afterID.
+	ListStoredPreferences(ctx context.Context, afterID string, limit int) ([]student.StoredPreferences, error)
+
+	// ReplacePreferences rewrites a blob if it still equals old.
+	ReplacePreferences(ctx context.Context, studentID string, old, updated []byte) (bool, error)
+}
+
+// MigratePreferencesJob rewrites every stored preferences blob that is not in
+// the current versioned format. Corrupted blobs are written back in their
+// repaired form, so the decoder stops reporting them on every read.
+//
+// It is a one-off job and safe to repeat: current blobs are left untouched,
+// and a blob changed concurrently is skipped rather than overwritten.
+type MigratePreferencesJob struct {
+	// Dependencies
+	store  PreferencesStore
+	logger *slog.Logger
+
+	// Configuration
+	config MigratePreferencesConfig
+
+	// State
+	lastRunStats atomic.Value // *MigratePreferencesStats
+}
+
+// MigratePreferencesConfig contains configuration for the migration job.
+type MigratePreferencesConfig struct {
+	// BatchSize is how many students are read per query.
+	BatchSize int
+
+	// DryRun reports what would be rewritten without writing.
+	DryRun bool
+
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultMigratePreferencesConfig returns sensible defaults.
+func DefaultMigratePreferencesConfig() MigratePreferencesConfig {
+	return MigratePreferencesConfig{
+		BatchSize: 500,
+		Timeout:   10 * time.Minute,
+	}
+}
+
+// MigratePreferencesStats contains statistics from a migration run.
+type MigratePreferencesStats struct {
+	StartedAt   time.Time
+	CompletedAt time.Time
+	Duration    time.Duration
+	Scanned     int
+	Current     int // already in the current format
+	Upgraded    int // legacy format, values kept as is
+	Repaired    int // some fields fixed or reset
+	Defaulted   int // unreadable, reset to defaults
+	Skipped     int // changed concurrently
+	Errors      []error
+}
+
+// NewMigratePreferencesJob creates a new migration job.
+func NewMigratePreferencesJob(
+	store PreferencesStore,
+	logger *slog.Logger,
+	config MigratePreferencesConfig,
+) *MigratePreferencesJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultMigratePreferencesConfig().BatchSize
+	}
+
+	return &MigratePreferencesJob{
+		store:  store,
+		logger: logger,
+		config: config,
+	}
+}
+
+// Name returns the job name.
+func (j *MigratePreferencesJob) Name() string {
+	return "migrate_preferences"
+}
+
+// Description returns a human-readable description.
+func (j *MigratePreferencesJob) Description() string {
+	return "Rewrites legacy and corrupted notification preferences in the current format"
+}
+
+// Run executes the migration.
+func (j *MigratePreferencesJob) Run(ctx context.Context) error {
+	startedAt := time.Now()
+	stats := &MigratePreferencesStats{StartedAt: startedAt}
+
+	j.logger.Info("starting migrate_preferences job", "dry_run", j.config.DryRun)
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	var runErr error
+	afterID := ""
+	for {
+		batch, err := j.store.ListStoredPreferences(ctx, afterID, j.config.BatchSize)
+		if err != nil {
+			runErr = fmt.Errorf("failed to list preferences: %w", err)
+			break
+		}
+
+		for _, stored := range batch {
+			j.migrate(ctx, stored, stats)
+		}
+
+		if len(batch) < j.config.BatchSize {
+			break
+		}
+		afterID = batch[len(batch)-1].StudentID
+	}
+
+	// Finalize stats
+	stats.CompletedAt = time.Now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("migrate_preferences job completed",
+		"duration", stats.Duration.String(),
+		"scanned", stats.Scanned,
+		"current", stats.Current,
+		"upgraded", stats.Upgraded,
+		"repaired", stats.Repaired,
+		"defaulted", stats.Defaulted,
+		"skipped", stats.Skipped,
+		"errors", len(stats.Errors),
+		"dry_run", j.config.DryRun,
+	)
+
+	return runErr
+}
+
+// migrate rewrites one blob if it is not current.
+func (j *MigratePreferencesJob) migrate(ctx context.Context, stored student.StoredPreferences, stats *MigratePreferencesStats) {
+	stats.Scanned++
+
+	prefs, report := student.DecodePreferences(stored.Data)
+	if !report.NeedsRewrite() {
+		stats.Current++
+		return
+	}
+	if report.Failed() {
+		j.logger.Warn("repairing notification preferences",
+			"student_id", stored.StudentID,
+			"status", string(report.Status),
+			"issues", strings.Join(report.Issues, "; "),
+		)
+	}
+
+	if !j.config.DryRun {
+		encoded, err := student.EncodePreferences(prefs)
+		if err != nil {
+			stats.Errors = append(stats.Errors, fmt.Errorf("student %s: %w", stored.StudentID, err))
+			return
+		}
+		replaced, err := j.store.ReplacePreferences(ctx, stored.StudentID, stored.Data, encoded)
+		if err != nil {
+			stats.Errors = append(stats.Errors, fmt.Errorf("student %s: %w", stored.StudentID, err))
+			return
+		}
+		if !replaced {
+			stats.Skipped++
+			return
+		}
+	}
+
+	switch report.Status {
+	case student.PreferencesRepaired:
+		stats.Repaired++
+	case student.PreferencesDefaulted:
+		stats.Defaulted++
+	default:
+		stats.Upgraded++
+	}
+}
+
+// LastRunStats returns statistics from the last migration run.
+func (j *MigratePreferencesJob) LastRunStats() *MigratePreferencesStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*MigratePreferencesStats)
+}
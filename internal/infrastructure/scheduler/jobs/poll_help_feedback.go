@@ -0,0 +1,246 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// POLL HELP FEEDBACK JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// PollHelpFeedbackJob sends requesters a private one-tap poll about how
+// useful the help was, social.HelpFeedbackPollDelay after a request was
+// resolved with a helper.
+//
+// Requesters who already endorsed the helper with a rating are not polled:
+// the endorsement's rating is stored as their feedback instead. Each request
+// gets at most one poll.
+type PollHelpFeedbackJob struct {
+	// Dependencies
+	helpRequests social.HelpRequestRepository
+	endorsements social.EndorsementRepository
+	feedback     social.HelpFeedbackRepository
+	studentRepo  student.Repository
+	notifier     KeyboardNotificationService
+	logger       *slog.Logger
+
+	// Configuration
+	config PollHelpFeedbackConfig
+	now    func() time.Time
+
+	// State
+	lastRunStats atomic.Value // *PollHelpFeedbackStats
+}
+
+// PollHelpFeedbackConfig contains configuration for the poll job.
+type PollHelpFeedbackConfig struct {
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultPollHelpFeedbackConfig returns sensible defaults.
+func DefaultPollHelpFeedbackConfig() PollHelpFeedbackConfig {
+	return PollHelpFeedbackConfig{
+		Timeout: 5 * time.Minute,
+	}
+}
+
+// PollHelpFeedbackStats contains statistics from a poll run.
+type PollHelpFeedbackStats struct {
+	StartedAt          time.Time
+	CompletedAt        time.Time
+	Duration           time.Duration
+	Checked            int
+	PollsSent          int
+	ReusedEndorsements int
+	Errors             []error
+}
+
+// NewPollHelpFeedbackJob creates a new poll job.
+func NewPollHelpFeedbackJob(
+	helpRequests social.HelpRequestRepository,
+	endorsements social.EndorsementRepository,
+	feedback social.HelpFeedbackRepository,
+	studentRepo student.Repository,
+	notifier KeyboardNotificationService,
+	logger *slog.Logger,
+	config PollHelpFeedbackConfig,
+) *PollHelpFeedbackJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &PollHelpFeedbackJob{
+		helpRequests: helpRequests,
+		endorsements: endorsements,
+		feedback:     feedback,
+		studentRepo:  studentRepo,
+		notifier:     notifier,
+		logger:       logger,
+		config:       config,
+		now:          time.Now,
+	}
+}
+
+// Name returns the job name.
+func (j *PollHelpFeedbackJob) Name() string {
+	return "poll_help_feedback"
+}
+
+// Description returns a human-readable description.
+func (j *PollHelpFeedbackJob) Description() string {
+	return "Asks requesters privately how useful the help they received was"
+}
+
+// Run polls requesters of resolved help requests that have no feedback yet.
+func (j *PollHelpFeedbackJob) Run(ctx context.Context) error {
+	startedAt := j.now()
+	stats := &PollHelpFeedbackStats{StartedAt: startedAt}
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	requests, err := j.helpRequests.GetAwaitingFeedbackPoll(ctx,
+		startedAt.Add(-social.HelpFeedbackPollDelay-social.HelpFeedbackAnswerTTL),
+		startedAt.Add(-social.HelpFeedbackPollDelay),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get help requests awaiting feedback poll: %w", err)
+	}
+
+	for _, req := range requests {
+		if ctx.Err() != nil {
+			break
+		}
+		// The query is a pre-filter; the entity has the final say
+		if !req.NeedsFeedbackPoll(startedAt) {
+			continue
+		}
+		stats.Checked++
+
+		if err := j.poll(ctx, req, stats); err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to send help feedback poll", "request_id", req.ID, "error", err)
+		}
+	}
+
+	// Finalize stats
+	stats.CompletedAt = j.now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("poll_help_feedback job completed",
+		"duration", stats.Duration.String(),
+		"checked", stats.Checked,
+		"sent", stats.PollsSent,
+		"reused_endorsements", stats.ReusedEndorsements,
+		"errors", len(stats.Errors),
+	)
+
+	return nil
+}
+
+// poll stores the endorsement rating as feedback or sends the poll for one
+// request. Requests that are skipped without claiming the poll are checked
+// again on the next run.
+func (j *PollHelpFeedbackJob) poll(ctx context.Context, req *social.HelpRequest, stats *PollHelpFeedbackStats) error {
+	now := j.now()
+
+	// A rating the requester already gave is their answer; don't ask twice
+	endorsement, err := j.endorsements.GetByHelpRequestID(ctx, req.ID)
+	if err != nil && !errors.Is(err, social.ErrEndorsementNotFound) {
+		return fmt.Errorf("get endorsement: %w", err)
+	}
+	if endorsement != nil && endorsement.GiverID == req.RequesterID {
+		if feedback, ok := social.HelpFeedbackFromEndorsement(endorsement, now); ok {
+			if err := j.feedback.Create(ctx, feedback); err != nil && !errors.Is(err, social.ErrHelpFeedbackExists) {
+				return fmt.Errorf("save feedback from endorsement: %w", err)
+			}
+			stats.ReusedEndorsements++
+			return nil
+		}
+	}
+
+	requester, err := j.studentRepo.GetByID(ctx, string(req.RequesterID))
+	if err != nil {
+		return fmt.Errorf("get requester: %w", err)
+	}
+	if !requester.CanReceiveNotification(string(notification.NotificationTypeHelpFeedbackPoll), now) {
+		return nil
+	}
+
+	// Claim before sending: a failed delivery is not retried, a duplicate is worse
+	claimed, err := j.helpRequests.ClaimFeedbackPoll(ctx, req.ID, now)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+	req.MarkFeedbackPollSent(now)
+
+	task := req.TaskName
+	if task == "" {
+		task = string(req.TaskID)
+	}
+	n := &notification.Notification{
+		ID:             notification.NotificationID(uuid.New().String()),
+		RecipientID:    notification.RecipientID(requester.ID),
+		TelegramChatID: notification.TelegramChatID(requester.TelegramID),
+		Type:           notification.NotificationTypeHelpFeedbackPoll,
+		Priority:       notification.PriorityLow,
+		Status:         notification.StatusPending,
+		Message: fmt.Sprintf("💬 Насколько полезной была помощь с задачей <b>%s</b>?\n\n"+
+			"<i>Ответ анонимный: помощник его не увидит.</i>", html.EscapeString(task)),
+		CreatedAt: now,
+	}
+	n.SetMetadata("help_request_id", req.ID)
+
+	result := j.notifier.SendWithKeyboard(ctx, n, helpFeedbackKeyboard(req.ID))
+	if result.Skipped() {
+		return nil
+	}
+	if !result.Success {
+		return fmt.Errorf("deliver poll: %w", result.Error)
+	}
+
+	stats.PollsSent++
+	return nil
+}
+
+// helpFeedbackKeyboard returns one-tap score buttons bound to the help request.
+func helpFeedbackKeyboard(helpRequestID string) [][]notification.InlineButton {
+	row := make([]notification.InlineButton, 0, 5)
+	for score := 1; score <= 5; score++ {
+		row = append(row, notification.NewCallbackButton(
+			fmt.Sprintf("%d", score),
+			fmt.Sprintf("feedback:%s:%d", helpRequestID, score),
+		))
+	}
+	return [][]notification.InlineButton{row}
+}
+
+// LastRunStats returns statistics from the last poll run.
+func (j *PollHelpFeedbackJob) LastRunStats() *PollHelpFeedbackStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*PollHelpFeedbackStats)
+}
@@ -0,0 +1,113 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// fakePollRequests отдаёт запросы без опроса и оценки, как запрос в PostgreSQL.
+type fakePollRequests struct {
+	social.HelpRequestRepository
+	requests []*social.HelpRequest
+	feedback *fakeHelpFeedback
+	claimed  map[string]bool
+}
+
+func (f *fakePollRequests) GetAwaitingFeedbackPoll(_ context.Context, resolvedAfter, resolvedBefore time.Time) ([]*social.HelpRequest, error) {
+	var result []*social.HelpRequest
+	for _, r := range f.requests {
+		if f.claimed[r.ID] || f.feedback.scores[r.ID] != nil ||
+			r.ResolvedAt.Before(resolvedAfter) || r.ResolvedAt.After(resolvedBefore) {
+			continue
+		}
+		result = append(result, r.Clone())
+	}
+	return result, nil
+}
+
+func (f *fakePollRequests) ClaimFeedbackPoll(_ context.Context, id string, _ time.Time) (bool, error) {
+	if f.claimed[id] {
+		return false, nil
+	}
+	f.claimed[id] = true
+	return true, nil
+}
+
+type fakeRequestEndorsements struct {
+	social.EndorsementRepository
+	byRequest map[string]*social.Endorsement
+}
+
+func (f *fakeRequestEndorsements) GetByHelpRequestID(_ context.Context, id string) (*social.Endorsement, error) {
+	if e, ok := f.byRequest[id]; ok {
+		return e, nil
+	}
+	return nil, social.ErrEndorsementNotFound
+}
+
+type fakeHelpFeedback struct {
+	social.HelpFeedbackRepository
+	scores map[string]*social.HelpFeedback
+}
+
+func (f *fakeHelpFeedback) Create(_ context.Context, feedback *social.HelpFeedback) error {
+	if f.scores[feedback.HelpRequestID] != nil {
+		return social.ErrHelpFeedbackExists
+	}
+	f.scores[feedback.HelpRequestID] = feedback
+	return nil
+}
+
+func TestPollHelpFeedbackJob_ReusesEndorsementRating(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	endorsed := newResolvedRequest(t, "req-endorsed", "arman", now.Add(-25*time.Hour))
+	silent := newResolvedRequest(t, "req-silent", "aigerim", now.Add(-25*time.Hour))
+	tooEarly := newResolvedRequest(t, "req-early", "arman", now.Add(-10*time.Hour))
+
+	feedback := &fakeHelpFeedback{scores: map[string]*social.HelpFeedback{}}
+	requests := &fakePollRequests{
+		requests: []*social.HelpRequest{endorsed, silent, tooEarly},
+		feedback: feedback,
+		claimed:  map[string]bool{},
+	}
+	endorsements := &fakeRequestEndorsements{byRequest: map[string]*social.Endorsement{
+		"req-endorsed": {ID: "e-1", GiverID: "dana", ReceiverID: "arman", HelpRequestID: "req-endorsed", Rating: 4},
+	}}
+	students := &fakeMentorStudentRepo{students: map[string]*student.Student{
+		"dana": newMentorCandidate("dana", 0, 0),
+	}}
+	notifier := &fakeKeyboardNotifier{}
+
+	job := NewPollHelpFeedbackJob(requests, endorsements, feedback, students, notifier, nil, DefaultPollHelpFeedbackConfig())
+	job.now = func() time.Time { return now }
+
+	require.NoError(t, job.Run(context.Background()))
+
+	// Звёзды благодарности стали оценкой, опрос не отправлен
+	require.NotNil(t, feedback.scores["req-endorsed"])
+	assert.Equal(t, 4, feedback.scores["req-endorsed"].Score)
+	assert.Equal(t, social.HelpFeedbackSourceEndorsement, feedback.scores["req-endorsed"].Source)
+	assert.False(t, requests.claimed["req-endorsed"])
+
+	// Без благодарности - один опрос с кнопками 1-5
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, "req-silent", notifier.sent[0].Metadata["help_request_id"])
+	require.Len(t, notifier.keyboards[0][0], 5)
+	assert.Equal(t, "feedback:req-silent:5", notifier.keyboards[0][0][4].CallbackData)
+	assert.False(t, requests.claimed["req-early"])
+
+	stats := job.LastRunStats()
+	assert.Equal(t, 1, stats.PollsSent)
+	assert.Equal(t, 1, stats.ReusedEndorsements)
+
+	// Повторный запуск ничего не отправляет
+	require.NoError(t, job.Run(context.Background()))
+	assert.Len(t, notifier.sent, 1)
+}
@@ -0,0 +1,98 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PRUNE FEED JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// PruneFeedJob deletes activity feed items older than social.FeedRetention.
+// It runs hourly, so each run only removes about an hour's worth of items.
+type PruneFeedJob struct {
+	// Dependencies
+	feed   social.FeedRepository
+	logger *slog.Logger
+
+	// Configuration
+	now func() time.Time
+
+	// State
+	lastRunStats atomic.Value // *PruneFeedStats
+}
+
+// PruneFeedStats contains statistics from a run.
+type PruneFeedStats struct {
+	StartedAt   time.Time
+	CompletedAt time.Time
+	Duration    time.Duration
+	Cutoff      time.Time
+	Pruned      int64
+}
+
+// NewPruneFeedJob creates a new feed prune job.
+func NewPruneFeedJob(feed social.FeedRepository, logger *slog.Logger) *PruneFeedJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &PruneFeedJob{
+		feed:   feed,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Name returns the job name.
+func (j *PruneFeedJob) Name() string {
+	return "prune_feed"
+}
+
+// Description returns a human-readable description.
+func (j *PruneFeedJob) Description() string {
+	return "Deletes activity feed items past their retention"
+}
+
+// Run deletes the expired feed items.
+func (j *PruneFeedJob) Run(ctx context.Context) error {
+	startedAt := j.now()
+	stats := &PruneFeedStats{
+		StartedAt: startedAt,
+		Cutoff:    startedAt.Add(-social.FeedRetention),
+	}
+
+	pruned, err := j.feed.DeleteOlderThan(ctx, stats.Cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to prune feed: %w", err)
+	}
+	stats.Pruned = pruned
+
+	// Finalize stats
+	stats.CompletedAt = j.now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("prune_feed job completed",
+		"duration", stats.Duration.String(),
+		"cutoff", stats.Cutoff,
+		"pruned", stats.Pruned,
+	)
+
+	return nil
+}
+
+// LastRunStats returns statistics from the last run.
+func (j *PruneFeedJob) LastRunStats() *PruneFeedStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*PruneFeedStats)
+}
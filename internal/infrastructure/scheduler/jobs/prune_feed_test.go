@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+type fakeFeed struct {
+	social.FeedRepository
+	items []*social.FeedItem
+}
+
+func (f *fakeFeed) DeleteOlderThan(_ context.Context, cutoff time.Time) (int64, error) {
+	var (
+		kept   []*social.FeedItem
+		pruned int64
+	)
+	for _, item := range f.items {
+		if item.CreatedAt.Before(cutoff) {
+			pruned++
+			continue
+		}
+		kept = append(kept, item)
+	}
+	f.items = kept
+	return pruned, nil
+}
+
+func TestPruneFeedJob_KeepsThirtyDays(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	feed := &fakeFeed{items: []*social.FeedItem{
+		{ID: 1, CreatedAt: now.Add(-social.FeedRetention - time.Minute)},
+		{ID: 2, CreatedAt: now.Add(-social.FeedRetention)},
+		{ID: 3, CreatedAt: now.Add(-29 * 24 * time.Hour)},
+		{ID: 4, CreatedAt: now.Add(-time.Hour)},
+	}}
+	job := NewPruneFeedJob(feed, nil)
+	job.now = func() time.Time { return now }
+
+	require.NoError(t, job.Run(context.Background()))
+
+	ids := make([]int64, 0, len(feed.items))
+	for _, item := range feed.items {
+		ids = append(ids, item.ID)
+	}
+	assert.Equal(t, []int64{2, 3, 4}, ids)
+	assert.Equal(t, int64(1), job.LastRunStats().Pruned)
+	assert.Equal(t, now.AddDate(0, 0, -30), job.LastRunStats().Cutoff)
+}
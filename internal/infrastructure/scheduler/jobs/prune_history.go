@@ -0,0 +1,261 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PRUNE HISTORY JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Tables the prune job knows about.
+const (
+	TableXPHistory     = "xp_history"
+	TableNotifications = "notifications"
+	TableCommandUsage  = "command_usage"
+	TableAuditLog      = "audit_log"
+)
+
+// HistoryPruner rolls up and deletes old rows.
+// Implemented by postgres.RetentionRepository.
+type HistoryPruner interface {
+	// RollupXPHistory stores monthly totals of xp_history rows before the
+	// given month start and returns how many totals were created.
+	RollupXPHistory(ctx context.Context, before time.Time) (int64, error)
+
+	// PruneBatch deletes up to limit rows of table older than before. Only
+	// rolled-up xp_history rows are deleted.
+	PruneBatch(ctx context.Context, table string, before time.Time, limit int) (int64, error)
+}
+
+// PruneHistoryJob keeps historical tables bounded. Raw xp_history rows are
+// rolled up into monthly totals first and deleted only once rolled up;
+// the other tables are deleted by age. Deletes run in small batches with a
+// pause in between, so no statement holds locks for long.
+//
+// daily_grinds is not pruned: weekly and daily XP queries read it.
+type PruneHistoryJob struct {
+	// Dependencies
+	pruner HistoryPruner
+	logger *slog.Logger
+
+	// Configuration
+	config PruneHistoryConfig
+	clock  shared.Clock
+	sleep  func(ctx context.Context, d time.Duration) error
+
+	// State
+	lastRunStats atomic.Value // *PruneHistoryStats
+}
+
+// PruneHistoryConfig contains configuration for the prune job.
+// A zero retention keeps the table forever.
+type PruneHistoryConfig struct {
+	// XPHistoryMonths is how many months of raw XP history are kept, not
+	// counting the current one. Older months remain as monthly totals.
+	XPHistoryMonths int
+
+	// Notifications is how long notifications are kept.
+	Notifications time.Duration
+
+	// CommandUsage is how long daily command counters are kept.
+	CommandUsage time.Duration
+
+	// AuditLog is how long audit entries are kept.
+	AuditLog time.Duration
+
+	// BatchSize is how many rows one delete statement removes at most.
+	BatchSize int
+
+	// BatchPause is the pause between two batches of the same table.
+	BatchPause time.Duration
+
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultPruneHistoryConfig returns sensible defaults.
+func DefaultPruneHistoryConfig() PruneHistoryConfig {
+	return PruneHistoryConfig{
+		XPHistoryMonths: 12,
+		Notifications:   90 * 24 * time.Hour,
+		CommandUsage:    183 * 24 * time.Hour, // ~6 months
+		AuditLog:        730 * 24 * time.Hour, // ~2 years
+		BatchSize:       5000,
+		BatchPause:      200 * time.Millisecond,
+		Timeout:         30 * time.Minute,
+	}
+}
+
+// PruneHistoryStats contains statistics from a prune run.
+type PruneHistoryStats struct {
+	StartedAt   time.Time
+	CompletedAt time.Time
+	Duration    time.Duration
+
+	// XPMonthlyTotals is how many monthly totals were created.
+	XPMonthlyTotals int64
+
+	// Pruned is the number of deleted rows per table.
+	Pruned map[string]int64
+}
+
+// NewPruneHistoryJob creates a new prune job.
+func NewPruneHistoryJob(
+	pruner HistoryPruner,
+	logger *slog.Logger,
+	config PruneHistoryConfig,
+) *PruneHistoryJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultPruneHistoryConfig().BatchSize
+	}
+
+	return &PruneHistoryJob{
+		pruner: pruner,
+		logger: logger,
+		config: config,
+		clock:  shared.SystemClock{},
+		sleep:  sleepContext,
+	}
+}
+
+// WithClock sets the clock the retention cutoffs are computed from.
+func (j *PruneHistoryJob) WithClock(clock shared.Clock) *PruneHistoryJob {
+	j.clock = shared.ClockOrSystem(clock)
+	return j
+}
+
+// Name returns the job name.
+func (j *PruneHistoryJob) Name() string {
+	return "prune_history"
+}
+
+// Description returns a human-readable description.
+func (j *PruneHistoryJob) Description() string {
+	return "Rolls up old XP history and prunes historical tables past their retention"
+}
+
+// Run rolls up XP history and prunes every table with a retention.
+// A failing table doesn't stop the others; the first error is returned.
+func (j *PruneHistoryJob) Run(ctx context.Context) error {
+	startedAt := time.Now()
+	now := j.clock.Now()
+	stats := &PruneHistoryStats{
+		StartedAt: startedAt,
+		Pruned:    make(map[string]int64),
+	}
+
+	j.logger.Info("starting prune_history job")
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	var errs []error
+	prune := func(table string, before time.Time) {
+		pruned, err := j.pruneTable(ctx, table, before)
+		stats.Pruned[table] = pruned
+		if err != nil {
+			j.logger.Warn("failed to prune table", "table", table, "pruned", pruned, "error", err)
+			errs = append(errs, err)
+		}
+	}
+
+	if j.config.XPHistoryMonths > 0 {
+		// Whole months only: the cutoff is a month start
+		before := student.XPMonth(now).AddDate(0, -j.config.XPHistoryMonths, 0)
+		totals, err := j.pruner.RollupXPHistory(ctx, before)
+		stats.XPMonthlyTotals = totals
+		if err != nil {
+			// Nothing is deleted without a rollup anyway
+			j.logger.Warn("failed to roll up xp history", "error", err)
+			errs = append(errs, err)
+		} else {
+			prune(TableXPHistory, before)
+		}
+	}
+	if j.config.Notifications > 0 {
+		prune(TableNotifications, now.Add(-j.config.Notifications))
+	}
+	if j.config.CommandUsage > 0 {
+		prune(TableCommandUsage, now.Add(-j.config.CommandUsage))
+	}
+	if j.config.AuditLog > 0 {
+		prune(TableAuditLog, now.Add(-j.config.AuditLog))
+	}
+
+	// Finalize stats
+	stats.CompletedAt = time.Now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("prune_history job completed",
+		"duration", stats.Duration.String(),
+		"xp_monthly_totals", stats.XPMonthlyTotals,
+		"xp_history_pruned", stats.Pruned[TableXPHistory],
+		"notifications_pruned", stats.Pruned[TableNotifications],
+		"command_usage_pruned", stats.Pruned[TableCommandUsage],
+		"audit_log_pruned", stats.Pruned[TableAuditLog],
+	)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("prune history: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// pruneTable deletes batches until one comes back short.
+func (j *PruneHistoryJob) pruneTable(ctx context.Context, table string, before time.Time) (int64, error) {
+	var total int64
+	for {
+		n, err := j.pruner.PruneBatch(ctx, table, before, j.config.BatchSize)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < int64(j.config.BatchSize) {
+			return total, nil
+		}
+		if err := j.sleep(ctx, j.config.BatchPause); err != nil {
+			return total, err
+		}
+	}
+}
+
+// LastRunStats returns statistics from the last prune run.
+func (j *PruneHistoryJob) LastRunStats() *PruneHistoryStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*PruneHistoryStats)
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
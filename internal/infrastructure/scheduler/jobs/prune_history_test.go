@@ -0,0 +1,102 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+// fakePruner holds a number of expired rows per table and records calls.
+type fakePruner struct {
+	rows      map[string]int64
+	rollupErr error
+	calls     []string
+	cutoffs   map[string]time.Time
+}
+
+func (f *fakePruner) RollupXPHistory(_ context.Context, before time.Time) (int64, error) {
+	f.calls = append(f.calls, "rollup")
+	f.cutoffs["rollup"] = before
+	if f.rollupErr != nil {
+		return 0, f.rollupErr
+	}
+	return 3, nil
+}
+
+func (f *fakePruner) PruneBatch(_ context.Context, table string, before time.Time, limit int) (int64, error) {
+	f.calls = append(f.calls, table)
+	f.cutoffs[table] = before
+	n := min(f.rows[table], int64(limit))
+	f.rows[table] -= n
+	return n, nil
+}
+
+func newTestPruneJob(pruner *fakePruner, now time.Time) (*PruneHistoryJob, *int) {
+	config := DefaultPruneHistoryConfig()
+	config.AuditLog = 0
+
+	job := NewPruneHistoryJob(pruner, nil, config).WithClock(shared.NewFakeClock(now))
+	pauses := 0
+	job.sleep = func(context.Context, time.Duration) error {
+		pauses++
+		return nil
+	}
+	return job, &pauses
+}
+
+func TestPruneHistoryJob_RollsUpThenPrunesInBatches(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 3, 30, 0, 0, time.UTC)
+	pruner := &fakePruner{
+		rows:    map[string]int64{TableXPHistory: 7000, TableNotifications: 12000, TableAuditLog: 50},
+		cutoffs: make(map[string]time.Time),
+	}
+	job, pauses := newTestPruneJob(pruner, now)
+
+	require.NoError(t, job.Run(context.Background()))
+
+	// The rollup runs before any xp_history delete; audit_log is kept
+	assert.Equal(t, []string{
+		"rollup", TableXPHistory, TableXPHistory,
+		TableNotifications, TableNotifications, TableNotifications,
+		TableCommandUsage,
+	}, pruner.calls)
+	assert.Equal(t, 3, *pauses)
+
+	// XP history is cut at a month start, the rest by age
+	xpCutoff := time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, xpCutoff, pruner.cutoffs["rollup"])
+	assert.Equal(t, xpCutoff, pruner.cutoffs[TableXPHistory])
+	assert.Equal(t, now.AddDate(0, 0, -90), pruner.cutoffs[TableNotifications])
+
+	stats := job.LastRunStats()
+	require.NotNil(t, stats)
+	assert.Equal(t, int64(3), stats.XPMonthlyTotals)
+	assert.Equal(t, map[string]int64{
+		TableXPHistory:     7000,
+		TableNotifications: 12000,
+		TableCommandUsage:  0,
+	}, stats.Pruned)
+	assert.Equal(t, int64(50), pruner.rows[TableAuditLog])
+}
+
+func TestPruneHistoryJob_FailedRollupKeepsXPHistory(t *testing.T) {
+	pruner := &fakePruner{
+		rows:      map[string]int64{TableXPHistory: 10, TableNotifications: 10},
+		rollupErr: errors.New("connection reset"),
+		cutoffs:   make(map[string]time.Time),
+	}
+	job, _ := newTestPruneJob(pruner, time.Now())
+
+	err := job.Run(context.Background())
+	require.Error(t, err)
+
+	assert.NotContains(t, pruner.calls, TableXPHistory)
+	assert.Equal(t, int64(10), pruner.rows[TableXPHistory])
+	assert.Zero(t, pruner.rows[TableNotifications])
+}
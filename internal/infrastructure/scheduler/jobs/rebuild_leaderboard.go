@@ -9,8 +9,11 @@ import (
 	"time"
 
 	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
 	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/pagination"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
 
 	"github.com/google/uuid"
 )
@@ -32,13 +35,38 @@ type RebuildLeaderboardJob struct {
 	onlineTracker    student.OnlineTracker
 	eventPublisher   shared.EventPublisher
 	notifier         leaderboard.RankChangeNotifier
+	dailyRanks       DailyRankRepository
+	xpReviews        XPReviewSource
+	invalidations    CacheInvalidations
 	logger           *slog.Logger
 
 	// Configuration
 	config RebuildLeaderboardConfig
+	now    func() time.Time
 
 	// State
 	lastRebuildStats atomic.Value // *RebuildStats
+	rankDay          time.Time    // local day whose start ranks are recorded
+}
+
+// DailyRankRepository records intraday rank movement in the daily grind.
+// Implemented by student.ProgressRepository.
+type DailyRankRepository interface {
+	StartDailyRanks(ctx context.Context, date time.Time, entries []student.StudentRank) error
+	BulkUpdateTodayRanks(ctx context.Context, date time.Time, entries []student.StudentRank) error
+}
+
+// CacheInvalidations announces changed data to the in-process caches of
+// other services. Implemented by messaging.InvalidationPublisher.
+type CacheInvalidations interface {
+	StudentUpdated(ctx context.Context, studentID string, telegramID int64)
+	LeaderboardRebuilt(ctx context.Context, cohort string)
+}
+
+// XPReviewSource lists students whose XP spike awaits curator review.
+// Implemented by student.XPFlagRepository.
+type XPReviewSource interface {
+	PendingStudentIDs(ctx context.Context) (map[string]bool, error)
 }
 
 // RebuildLeaderboardConfig contains configuration for the rebuild job.
@@ -66,6 +94,9 @@ type RebuildLeaderboardConfig struct {
 
 	// Timeout is the maximum duration for the rebuild operation.
 	Timeout time.Duration
+
+	// Location defines local midnight, when the start-of-day ranks are taken.
+	Location *time.Location
 }
 
 // DefaultRebuildLeaderboardConfig returns sensible defaults.
@@ -79,6 +110,7 @@ func DefaultRebuildLeaderboardConfig() RebuildLeaderboardConfig {
 		RebuildCohorts:               nil, // nil = all
 		CacheTTL:                     10 * time.Minute,
 		Timeout:                      5 * time.Minute,
+		Location:                     timeutil.AlmatyTZ,
 	}
 }
 
@@ -94,6 +126,8 @@ type RebuildStats struct {
 	NotificationsSent int
 	TopNEntries       int
 	TopNExits         int
+	DailyRanksStarted bool
+	DailyRanksUpdated int
 	Errors            []error
 }
 
@@ -111,6 +145,9 @@ func NewRebuildLeaderboardJob(
 	if logger == nil {
 		logger = slog.Default()
 	}
+	if config.Location == nil {
+		config.Location = timeutil.AlmatyTZ
+	}
 
 	return &RebuildLeaderboardJob{
 		studentRepo:      studentRepo,
@@ -121,9 +158,30 @@ func NewRebuildLeaderboardJob(
 		notifier:         notifier,
 		logger:           logger,
 		config:           config,
+		now:              time.Now,
 	}
 }
 
+// WithDailyRanks enables tracking of the rank at the start of the day and
+// the change since then in the daily grind of every student.
+func (j *RebuildLeaderboardJob) WithDailyRanks(repo DailyRankRepository) *RebuildLeaderboardJob {
+	j.dailyRanks = repo
+	return j
+}
+
+// WithXPReviews marks students with a pending XP review in the rebuilt
+// leaderboard and its cache.
+func (j *RebuildLeaderboardJob) WithXPReviews(source XPReviewSource) *RebuildLeaderboardJob {
+	j.xpReviews = source
+	return j
+}
+
+// WithInvalidations announces every rebuilt leaderboard to other services.
+func (j *RebuildLeaderboardJob) WithInvalidations(invalidations CacheInvalidations) *RebuildLeaderboardJob {
+	j.invalidations = invalidations
+	return j
+}
+
 // Name returns the job name.
 func (j *RebuildLeaderboardJob) Name() string {
 	return "rebuild_leaderboard"
@@ -136,7 +194,7 @@ func (j *RebuildLeaderboardJob) Description() string {
 
 // Run executes the rebuild job.
 func (j *RebuildLeaderboardJob) Run(ctx context.Context) error {
-	startedAt := time.Now()
+	startedAt := j.now()
 	stats := &RebuildStats{
 		StartedAt: startedAt,
 		Errors:    make([]error, 0),
@@ -172,12 +230,32 @@ func (j *RebuildLeaderboardJob) Run(ctx context.Context) error {
 	for i, s := range students {
 		studentIDs[i] = s.ID
 	}
-	onlineStates, _ := j.onlineTracker.GetOnlineStates(ctx, studentIDs)
+	var onlineStates map[string]student.OnlineState
+	if j.onlineTracker != nil {
+		onlineStates, _ = j.onlineTracker.GetOnlineStates(ctx, studentIDs)
+	}
+
+	// The review marker is cosmetic: without it the rebuild still goes on
+	var pendingReview map[string]bool
+	if j.xpReviews != nil {
+		if pendingReview, err = j.xpReviews.PendingStudentIDs(ctx); err != nil {
+			j.logger.Warn("failed to list pending xp reviews", "error", err)
+		}
+	}
 
 	// Rebuild general leaderboard (all cohorts)
-	if err := j.rebuildLeaderboard(ctx, leaderboard.CohortAll, students, onlineStates, stats); err != nil {
+	general, err := j.rebuildLeaderboard(ctx, leaderboard.CohortAll, students, onlineStates, pendingReview, stats)
+	if err != nil {
 		stats.Errors = append(stats.Errors, err)
 		j.logger.Error("failed to rebuild general leaderboard", "error", err)
+	} else {
+		j.announceRebuilt(ctx, string(leaderboard.CohortAll))
+		if j.dailyRanks != nil {
+			if err := j.trackDailyRanks(ctx, general, startedAt, stats); err != nil {
+				stats.Errors = append(stats.Errors, err)
+				j.logger.Error("failed to track daily ranks", "error", err)
+			}
+		}
 	}
 
 	// Rebuild per-cohort leaderboards
@@ -188,12 +266,14 @@ func (j *RebuildLeaderboardJob) Run(ctx context.Context) error {
 			continue
 		}
 
-		if err := j.rebuildLeaderboard(ctx, leaderboard.Cohort(cohort), cohortStudents, onlineStates, stats); err != nil {
+		if _, err := j.rebuildLeaderboard(ctx, leaderboard.Cohort(cohort), cohortStudents, onlineStates, pendingReview, stats); err != nil {
 			stats.Errors = append(stats.Errors, err)
 			j.logger.Error("failed to rebuild cohort leaderboard",
 				"cohort", cohort,
 				"error", err,
 			)
+		} else {
+			j.announceRebuilt(ctx, cohort)
 		}
 		stats.CohortsProcessed++
 	}
@@ -210,7 +290,7 @@ func (j *RebuildLeaderboardJob) Run(ctx context.Context) error {
 	}
 
 	// Finalize stats
-	stats.CompletedAt = time.Now()
+	stats.CompletedAt = j.now()
 	stats.Duration = stats.CompletedAt.Sub(startedAt)
 	j.lastRebuildStats.Store(stats)
 
@@ -220,6 +300,7 @@ func (j *RebuildLeaderboardJob) Run(ctx context.Context) error {
 		"snapshots_created", stats.SnapshotsCreated,
 		"rank_changes", stats.RankChangesFound,
 		"notifications", stats.NotificationsSent,
+		"daily_ranks", stats.DailyRanksUpdated,
 	)
 
 	if len(stats.Errors) > 0 {
@@ -229,14 +310,22 @@ func (j *RebuildLeaderboardJob) Run(ctx context.Context) error {
 	return nil
 }
 
+// announceRebuilt publishes that a cohort leaderboard was rebuilt.
+func (j *RebuildLeaderboardJob) announceRebuilt(ctx context.Context, cohort string) {
+	if j.invalidations != nil && !notification.SkipEffect(ctx, notification.DryRunEffectCacheRebuilds) {
+		j.invalidations.LeaderboardRebuilt(ctx, cohort)
+	}
+}
+
 // rebuildLeaderboard rebuilds the leaderboard for a specific cohort.
 func (j *RebuildLeaderboardJob) rebuildLeaderboard(
 	ctx context.Context,
 	cohort leaderboard.Cohort,
 	students []*student.Student,
 	onlineStates map[string]student.OnlineState,
+	pendingReview map[string]bool,
 	stats *RebuildStats,
-) error {
+) (*leaderboard.LeaderboardSnapshot, error) {
 	// Get previous snapshot for comparison
 	prevSnapshot, _ := j.leaderboardRepo.GetLatestSnapshot(ctx, cohort)
 
@@ -258,8 +347,11 @@ func (j *RebuildLeaderboardJob) rebuildLeaderboard(
 		// Set online state
 		if state, ok := onlineStates[s.ID]; ok {
 			entry.IsOnline = state == student.OnlineStateOnline
-			entry.IsAvailableForHelp = state.IsAvailable() && s.CanHelp()
+			entry.IsAvailableForHelp = state.IsAvailable() && s.IsDiscoverableHelper()
 		}
+		entry.HiddenFromLeaderboard = !s.IsOnPublicLeaderboard()
+		entry.HidesOnlineStatus = !s.ShowsOnlineStatus()
+		entry.XPReviewPending = pendingReview[s.ID]
 		entry.HelpRating = s.HelpRating
 		entry.UpdatedAt = s.UpdatedAt
 
@@ -314,12 +406,12 @@ func (j *RebuildLeaderboardJob) rebuildLeaderboard(
 
 	// Save snapshot
 	if err := j.leaderboardRepo.SaveSnapshot(ctx, newSnapshot); err != nil {
-		return fmt.Errorf("failed to save snapshot: %w", err)
+		return nil, fmt.Errorf("failed to save snapshot: %w", err)
 	}
 	stats.SnapshotsCreated++
 
 	// Update cache
-	if j.leaderboardCache != nil {
+	if j.leaderboardCache != nil && !notification.SkipEffect(ctx, notification.DryRunEffectCacheRebuilds) {
 		// Cache top entries
 		topEntries := newSnapshot.Top(100)
 		if err := j.leaderboardCache.SetCachedTop(ctx, cohort, topEntries, j.config.CacheTTL); err != nil {
@@ -343,6 +435,39 @@ func (j *RebuildLeaderboardJob) rebuildLeaderboard(
 		"average_xp", newSnapshot.AverageXP,
 	)
 
+	return newSnapshot, nil
+}
+
+// trackDailyRanks writes the general ranks into today's daily grind.
+// The first rebuild after local midnight records the start-of-day ranks;
+// later rebuilds only update the current rank and the change. The day is
+// kept in memory, so after a restart the start is recorded again, which
+// is harmless: already recorded start ranks are not overwritten.
+func (j *RebuildLeaderboardJob) trackDailyRanks(
+	ctx context.Context,
+	snapshot *leaderboard.LeaderboardSnapshot,
+	at time.Time,
+	stats *RebuildStats,
+) error {
+	local := at.In(j.config.Location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	entries := make([]student.StudentRank, len(snapshot.Entries))
+	for i, e := range snapshot.Entries {
+		entries[i] = student.StudentRank{StudentID: e.StudentID, Rank: int(e.Rank)}
+	}
+
+	if !day.Equal(j.rankDay) {
+		if err := j.dailyRanks.StartDailyRanks(ctx, day, entries); err != nil {
+			return err
+		}
+		j.rankDay = day
+		stats.DailyRanksStarted = true
+	} else if err := j.dailyRanks.BulkUpdateTodayRanks(ctx, day, entries); err != nil {
+		return err
+	}
+
+	stats.DailyRanksUpdated = len(entries)
 	return nil
 }
 
@@ -417,7 +542,10 @@ func (j *RebuildLeaderboardJob) notifyTopNEntry(
 
 // getAllActiveStudents retrieves all students for the leaderboard.
 func (j *RebuildLeaderboardJob) getAllActiveStudents(ctx context.Context) ([]*student.Student, error) {
-	opts := student.DefaultListOptions().WithLimit(10000) // High limit for all students
+	const allStudents = 10000
+	opts := student.DefaultListOptions().
+		WithLimit(allStudents).
+		WithBounds(pagination.Bounds{MaxLimit: allStudents})
 	return j.studentRepo.GetByStatus(ctx, student.StatusActive, opts)
 }
 
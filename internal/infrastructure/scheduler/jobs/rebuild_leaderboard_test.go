@@ -0,0 +1,113 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type fakeActiveStudents struct {
+	student.Repository
+	students []*student.Student
+}
+
+func (f *fakeActiveStudents) GetByStatus(_ context.Context, _ student.Status, _ student.ListOptions) ([]*student.Student, error) {
+	return f.students, nil
+}
+
+type fakeSnapshotRepo struct {
+	leaderboard.LeaderboardRepository
+	latest map[leaderboard.Cohort]*leaderboard.LeaderboardSnapshot
+}
+
+func (f *fakeSnapshotRepo) GetLatestSnapshot(_ context.Context, cohort leaderboard.Cohort) (*leaderboard.LeaderboardSnapshot, error) {
+	return f.latest[cohort], nil
+}
+
+func (f *fakeSnapshotRepo) SaveSnapshot(_ context.Context, snapshot *leaderboard.LeaderboardSnapshot) error {
+	f.latest[snapshot.Cohort] = snapshot
+	return nil
+}
+
+func (f *fakeSnapshotRepo) DeleteOldSnapshots(_ context.Context, _ time.Time) (int, error) {
+	return 0, nil
+}
+
+// rankCall - один вызов DailyRankRepository.
+type rankCall struct {
+	start bool
+	date  time.Time
+	ranks map[string]int
+}
+
+type fakeDailyRanks struct {
+	calls []rankCall
+}
+
+func (f *fakeDailyRanks) record(start bool, date time.Time, entries []student.StudentRank) {
+	ranks := make(map[string]int, len(entries))
+	for _, e := range entries {
+		ranks[e.StudentID] = e.Rank
+	}
+	f.calls = append(f.calls, rankCall{start: start, date: date, ranks: ranks})
+}
+
+func (f *fakeDailyRanks) StartDailyRanks(_ context.Context, date time.Time, entries []student.StudentRank) error {
+	f.record(true, date, entries)
+	return nil
+}
+
+func (f *fakeDailyRanks) BulkUpdateTodayRanks(_ context.Context, date time.Time, entries []student.StudentRank) error {
+	f.record(false, date, entries)
+	return nil
+}
+
+func TestRebuildLeaderboard_TracksDailyRanksAcrossAlmatyMidnight(t *testing.T) {
+	dana := &student.Student{ID: "dana", DisplayName: "dana", CurrentXP: 500, Cohort: "2025"}
+	arman := &student.Student{ID: "arman", DisplayName: "arman", CurrentXP: 300, Cohort: "2025"}
+	students := &fakeActiveStudents{students: []*student.Student{dana, arman}}
+	ranks := &fakeDailyRanks{}
+
+	config := DefaultRebuildLeaderboardConfig()
+	config.NotifyRankChanges = false
+	config.NotifyTopNEntry = false
+	job := NewRebuildLeaderboardJob(
+		students,
+		&fakeSnapshotRepo{latest: map[leaderboard.Cohort]*leaderboard.LeaderboardSnapshot{}},
+		nil, nil, nil, nil, nil,
+		config,
+	).WithDailyRanks(ranks)
+
+	run := func(at time.Time) {
+		t.Helper()
+		job.now = func() time.Time { return at }
+		require.NoError(t, job.Run(context.Background()))
+	}
+
+	// 23:50 по Алматы (18:50 UTC) - первый запуск фиксирует начало дня
+	run(time.Date(2026, 3, 9, 18, 50, 0, 0, time.UTC))
+	// Арман обгоняет Дану до полуночи
+	arman.CurrentXP = 700
+	run(time.Date(2026, 3, 9, 18, 55, 0, 0, time.UTC))
+	// 00:05 по Алматы - по UTC ещё 9 марта, но начался новый день
+	run(time.Date(2026, 3, 9, 19, 5, 0, 0, time.UTC))
+	run(time.Date(2026, 3, 9, 19, 15, 0, 0, time.UTC))
+
+	march9 := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	march10 := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	require.Len(t, ranks.calls, 4)
+	assert.Equal(t, rankCall{start: true, date: march9, ranks: map[string]int{"dana": 1, "arman": 2}}, ranks.calls[0])
+	assert.Equal(t, rankCall{start: false, date: march9, ranks: map[string]int{"dana": 2, "arman": 1}}, ranks.calls[1])
+	assert.Equal(t, rankCall{start: true, date: march10, ranks: map[string]int{"dana": 2, "arman": 1}}, ranks.calls[2])
+	assert.False(t, ranks.calls[3].start)
+	assert.Equal(t, march10, ranks.calls[3].date)
+
+	assert.Equal(t, 2, job.LastRebuildStats().DailyRanksUpdated)
+}
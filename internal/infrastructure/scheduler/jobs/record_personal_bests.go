@@ -0,0 +1,326 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RECORD PERSONAL BESTS JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// XPTotalsSource sums daily XP gains of all students for a date range.
+// Implemented by postgres.ProgressRepository.
+type XPTotalsSource interface {
+	SumXPGainedBetween(ctx context.Context, from, to time.Time) (map[string]student.XP, error)
+}
+
+// FocusSessionSource lists finished /focus sessions.
+// Implemented by postgres.FocusRepository.
+type FocusSessionSource interface {
+	ListEndedBetween(ctx context.Context, from, to time.Time) ([]*social.FocusSession, error)
+}
+
+// PersonalBestRecorder records a candidate personal best.
+// Implemented by command.RecordPersonalBestHandler.
+type PersonalBestRecorder interface {
+	Handle(ctx context.Context, cmd command.RecordPersonalBestCommand) (*command.RecordPersonalBestResult, error)
+}
+
+// RecordPersonalBestsJob updates the "best day" and "best week" personal
+// bests from closed days and weeks of daily progress (UTC), and the
+// "longest session" best from /focus sessions that ended on those days.
+// Focus sessions are the only sessions the hub times: the sync only sees
+// XP, not when a student goes offline.
+//
+// Recent days are re-evaluated on every run so XP synced late still counts;
+// records only move up, so reprocessing is harmless. Backfill walks the
+// history once with announcements suppressed.
+type RecordPersonalBestsJob struct {
+	// Dependencies
+	totals   XPTotalsSource
+	recorder PersonalBestRecorder
+	logger   *slog.Logger
+
+	// Focus sessions (optional, nil skips the longest session best)
+	focus FocusSessionSource
+
+	// Configuration
+	config RecordPersonalBestsConfig
+	now    func() time.Time
+
+	// State
+	lastRunStats atomic.Value // *RecordPersonalBestsStats
+}
+
+// RecordPersonalBestsConfig contains configuration for the personal bests job.
+type RecordPersonalBestsConfig struct {
+	// LookbackDays is how many closed days are re-evaluated on every run.
+	// The last closed ISO week is evaluated too.
+	LookbackDays int
+
+	// BackfillDays is how far back Backfill goes.
+	BackfillDays int
+
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultRecordPersonalBestsConfig returns sensible defaults.
+func DefaultRecordPersonalBestsConfig() RecordPersonalBestsConfig {
+	return RecordPersonalBestsConfig{
+		LookbackDays: 2,
+		BackfillDays: 365,
+		Timeout:      10 * time.Minute,
+	}
+}
+
+// RecordPersonalBestsStats contains statistics from a run.
+type RecordPersonalBestsStats struct {
+	StartedAt       time.Time
+	CompletedAt     time.Time
+	Duration        time.Duration
+	DaysProcessed   int
+	WeeksProcessed  int
+	Sessions        int
+	RecordsImproved int
+	Announced       int
+	Suppressed      bool
+	Errors          []error
+}
+
+// NewRecordPersonalBestsJob creates a new personal bests job.
+func NewRecordPersonalBestsJob(
+	totals XPTotalsSource,
+	recorder PersonalBestRecorder,
+	logger *slog.Logger,
+	config RecordPersonalBestsConfig,
+) *RecordPersonalBestsJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &RecordPersonalBestsJob{
+		totals:   totals,
+		recorder: recorder,
+		logger:   logger,
+		config:   config,
+		now:      time.Now,
+	}
+}
+
+// WithFocusSessions enables the "longest session" best from finished
+// /focus sessions.
+func (j *RecordPersonalBestsJob) WithFocusSessions(focus FocusSessionSource) *RecordPersonalBestsJob {
+	j.focus = focus
+	return j
+}
+
+// Name returns the job name.
+func (j *RecordPersonalBestsJob) Name() string {
+	return "record_personal_bests"
+}
+
+// Description returns a human-readable description.
+func (j *RecordPersonalBestsJob) Description() string {
+	return "Updates best day, best week and longest session personal records from closed days"
+}
+
+// Run evaluates the last closed days and the last closed week.
+func (j *RecordPersonalBestsJob) Run(ctx context.Context) error {
+	today := shared.StartOfDay(j.now(), time.UTC)
+	lastWeek, err := student.ISOWeekOf(today).Previous().Start()
+	if err != nil {
+		return err
+	}
+	return j.run(ctx, today.AddDate(0, 0, -j.config.LookbackDays), lastWeek, today, false)
+}
+
+// Backfill evaluates BackfillDays of history without announcing records,
+// so students don't get a burst of notifications for old days.
+func (j *RecordPersonalBestsJob) Backfill(ctx context.Context) error {
+	today := shared.StartOfDay(j.now(), time.UTC)
+	from := today.AddDate(0, 0, -j.config.BackfillDays)
+	return j.run(ctx, from, from, today, true)
+}
+
+// run evaluates closed days in [daysFrom, today) and closed ISO weeks
+// starting on or after weeksFrom.
+func (j *RecordPersonalBestsJob) run(ctx context.Context, daysFrom, weeksFrom, today time.Time, suppress bool) error {
+	startedAt := j.now()
+	stats := &RecordPersonalBestsStats{StartedAt: startedAt, Suppressed: suppress}
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	// Oldest first: during a backfill the record moves up day by day
+	for day := daysFrom; day.Before(today); day = day.AddDate(0, 0, 1) {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := j.evaluate(ctx, student.PersonalBestDayXP, day, day.AddDate(0, 0, 1), suppress, stats); err != nil {
+			return err
+		}
+		if err := j.evaluateSessions(ctx, day, suppress, stats); err != nil {
+			return err
+		}
+		stats.DaysProcessed++
+	}
+
+	weeks, err := closedWeeks(weeksFrom, today)
+	if err != nil {
+		return err
+	}
+	for _, week := range weeks {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := j.evaluate(ctx, student.PersonalBestWeekXP, week, week.AddDate(0, 0, 7), suppress, stats); err != nil {
+			return err
+		}
+		stats.WeeksProcessed++
+	}
+
+	// Finalize stats
+	stats.CompletedAt = j.now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("record_personal_bests job completed",
+		"duration", stats.Duration.String(),
+		"days", stats.DaysProcessed,
+		"weeks", stats.WeeksProcessed,
+		"sessions", stats.Sessions,
+		"improved", stats.RecordsImproved,
+		"announced", stats.Announced,
+		"suppressed", suppress,
+		"errors", len(stats.Errors),
+	)
+
+	return nil
+}
+
+// evaluate records every student's XP for [start, end) as a candidate.
+func (j *RecordPersonalBestsJob) evaluate(
+	ctx context.Context,
+	metric student.PersonalBestMetric,
+	start, end time.Time,
+	suppress bool,
+	stats *RecordPersonalBestsStats,
+) error {
+	totals, err := j.totals.SumXPGainedBetween(ctx, start, end)
+	if err != nil {
+		return fmt.Errorf("failed to sum xp for %s: %w", start.Format("2006-01-02"), err)
+	}
+
+	for studentID, xp := range totals {
+		if ctx.Err() != nil {
+			break
+		}
+		j.record(ctx, studentID, metric, int(xp), start, suppress, stats)
+	}
+
+	return nil
+}
+
+// evaluateSessions records every participant's longest focus session that
+// ended on day as a candidate.
+func (j *RecordPersonalBestsJob) evaluateSessions(ctx context.Context, day time.Time, suppress bool, stats *RecordPersonalBestsStats) error {
+	if j.focus == nil {
+		return nil
+	}
+
+	sessions, err := j.focus.ListEndedBetween(ctx, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return fmt.Errorf("failed to list focus sessions for %s: %w", day.Format("2006-01-02"), err)
+	}
+
+	longest := make(map[string]int)
+	for _, session := range sessions {
+		for _, id := range session.Participants() {
+			longest[string(id)] = max(longest[string(id)], session.Minutes(id))
+		}
+	}
+	stats.Sessions += len(sessions)
+
+	for studentID, minutes := range longest {
+		if ctx.Err() != nil {
+			break
+		}
+		if minutes > 0 {
+			j.record(ctx, studentID, student.PersonalBestSessionMinutes, minutes, day, suppress, stats)
+		}
+	}
+
+	return nil
+}
+
+// record submits one candidate; failures are counted, not returned.
+func (j *RecordPersonalBestsJob) record(
+	ctx context.Context,
+	studentID string,
+	metric student.PersonalBestMetric,
+	value int,
+	achievedOn time.Time,
+	suppress bool,
+	stats *RecordPersonalBestsStats,
+) {
+	result, err := j.recorder.Handle(ctx, command.RecordPersonalBestCommand{
+		StudentID:  studentID,
+		Metric:     metric,
+		Value:      value,
+		AchievedOn: achievedOn,
+		Suppress:   suppress,
+	})
+	if err != nil {
+		stats.Errors = append(stats.Errors, err)
+		j.logger.Warn("failed to record personal best",
+			"student_id", studentID, "metric", metric, "error", err)
+		return
+	}
+	if result.Update.Improved {
+		stats.RecordsImproved++
+	}
+	if result.Announced {
+		stats.Announced++
+	}
+}
+
+// closedWeeks returns Mondays of ISO weeks that start on or after from and
+// end on or before today.
+func closedWeeks(from, today time.Time) ([]time.Time, error) {
+	monday, err := student.ISOWeekOf(from).Start()
+	if err != nil {
+		return nil, err
+	}
+	if monday.Before(from) {
+		monday = monday.AddDate(0, 0, 7)
+	}
+
+	var weeks []time.Time
+	for ; !monday.AddDate(0, 0, 7).After(today); monday = monday.AddDate(0, 0, 7) {
+		weeks = append(weeks, monday)
+	}
+	return weeks, nil
+}
+
+// LastRunStats returns statistics from the last run.
+func (j *RecordPersonalBestsJob) LastRunStats() *RecordPersonalBestsStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*RecordPersonalBestsStats)
+}
@@ -0,0 +1,162 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// fakePersonalBests повторяет условный upsert из PostgreSQL.
+type fakePersonalBests struct {
+	bests map[student.PersonalBestMetric]student.PersonalBest
+}
+
+func (f *fakePersonalBests) GetBests(_ context.Context, _ string) ([]student.PersonalBest, error) {
+	var result []student.PersonalBest
+	for _, b := range f.bests {
+		result = append(result, b)
+	}
+	return result, nil
+}
+
+func (f *fakePersonalBests) UpsertBest(_ context.Context, best student.PersonalBest) (student.PersonalBestUpdate, error) {
+	update := student.PersonalBestUpdate{Best: best}
+	if prev, ok := f.bests[best.Metric]; ok {
+		update.Previous = &prev
+		if prev.Value >= best.Value {
+			update.Best = prev
+			return update, nil
+		}
+	}
+	f.bests[best.Metric] = best
+	update.Improved = true
+	return update, nil
+}
+
+// fakeDailyXP хранит XP студента "dana" по дням.
+type fakeDailyXP map[time.Time]student.XP
+
+func (f fakeDailyXP) SumXPGainedBetween(_ context.Context, from, to time.Time) (map[string]student.XP, error) {
+	var total student.XP
+	for day, xp := range f {
+		if !day.Before(from) && day.Before(to) {
+			total += xp
+		}
+	}
+	if total == 0 {
+		return nil, nil
+	}
+	return map[string]student.XP{"dana": total}, nil
+}
+
+type recordingPublisher struct {
+	events []shared.Event
+}
+
+func (p *recordingPublisher) Publish(event shared.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestRecordPersonalBests_TiesAndBackfill(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, time.March, d, 0, 0, 0, 0, time.UTC) }
+
+	bests := &fakePersonalBests{bests: map[student.PersonalBestMetric]student.PersonalBest{}}
+	publisher := &recordingPublisher{}
+	xp := fakeDailyXP{day(3): 500, day(4): 300, day(5): 500, day(6): 820}
+
+	job := NewRecordPersonalBestsJob(xp, command.NewRecordPersonalBestHandler(bests, publisher), nil, RecordPersonalBestsConfig{
+		LookbackDays: 1,
+		BackfillDays: 3,
+	})
+
+	// Бэкфилл 3-5 марта: рекорд сохраняется, но без уведомлений
+	job.now = func() time.Time { return day(6).Add(time.Hour) }
+	require.NoError(t, job.Backfill(context.Background()))
+	assert.Empty(t, publisher.events)
+	assert.Equal(t, 500, bests.bests[student.PersonalBestDayXP].Value)
+	assert.Equal(t, day(3), bests.bests[student.PersonalBestDayXP].AchievedOn, "5 марта - ничья, рекорд за 3 марта")
+
+	// 6 марта побит рекорд - одно уведомление
+	job.now = func() time.Time { return day(7).Add(time.Hour) }
+	require.NoError(t, job.Run(context.Background()))
+	require.Len(t, publisher.events, 1)
+	event := publisher.events[0].(shared.PersonalBestSetEvent)
+	assert.Equal(t, string(student.PersonalBestDayXP), event.Metric)
+	assert.Equal(t, 820, event.Value)
+	assert.Equal(t, 500, event.PreviousValue)
+
+	// Повторная обработка того же дня ничего не шлёт
+	require.NoError(t, job.Run(context.Background()))
+	assert.Len(t, publisher.events, 1)
+
+	// Пересчёт дня с большим XP обновляет рекорд, но не поздравляет второй раз
+	xp[day(6)] = 900
+	require.NoError(t, job.Run(context.Background()))
+	assert.Len(t, publisher.events, 1)
+	assert.Equal(t, 900, bests.bests[student.PersonalBestDayXP].Value)
+}
+
+// fakeFocusSessions отдаёт завершённые сессии по времени окончания.
+type fakeFocusSessions []*social.FocusSession
+
+func (f fakeFocusSessions) ListEndedBetween(_ context.Context, from, to time.Time) ([]*social.FocusSession, error) {
+	var ended []*social.FocusSession
+	for _, s := range f {
+		if s.EndedAt != nil && !s.EndedAt.Before(from) && s.EndedAt.Before(to) {
+			ended = append(ended, s)
+		}
+	}
+	return ended, nil
+}
+
+func focusSession(start time.Time, minutes int) *social.FocusSession {
+	session := social.NewFocusSession("dana", false, start)
+	ended := start.Add(time.Duration(minutes) * time.Minute)
+	session.EndedAt = &ended
+	return session
+}
+
+func TestRecordPersonalBests_LongestFocusSession(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, time.March, d, 0, 0, 0, 0, time.UTC) }
+
+	bests := &fakePersonalBests{bests: map[student.PersonalBestMetric]student.PersonalBest{}}
+	publisher := &recordingPublisher{}
+	focus := fakeFocusSessions{
+		focusSession(day(5).Add(9*time.Hour), 40),
+		focusSession(day(6).Add(9*time.Hour), 20),   // остановлена досрочно
+		focusSession(day(6).Add(14*time.Hour), 50),  // полная сессия
+		focusSession(day(6).Add(23*time.Hour), 200), // закончилась 7 марта, ещё не закрытый день
+	}
+
+	job := NewRecordPersonalBestsJob(fakeDailyXP{}, command.NewRecordPersonalBestHandler(bests, publisher), nil, RecordPersonalBestsConfig{
+		LookbackDays: 2,
+	}).WithFocusSessions(focus)
+	job.now = func() time.Time { return day(7).Add(time.Hour) }
+
+	require.NoError(t, job.Run(context.Background()))
+
+	best := bests.bests[student.PersonalBestSessionMinutes]
+	assert.Equal(t, 50, best.Value, "самая длинная сессия за закрытые дни")
+	assert.Equal(t, day(6), best.AchievedOn)
+	assert.Equal(t, 3, job.LastRunStats().Sessions)
+
+	// Первый рекорд (5 марта) сохраняется молча, поздравляем с побитым
+	require.Len(t, publisher.events, 1)
+	event := publisher.events[0].(shared.PersonalBestSetEvent)
+	assert.Equal(t, string(student.PersonalBestSessionMinutes), event.Metric)
+	assert.Equal(t, 50, event.Value)
+	assert.Equal(t, 40, event.PreviousValue)
+
+	// Повторный прогон тех же дней ничего не шлёт
+	require.NoError(t, job.Run(context.Background()))
+	assert.Len(t, publisher.events, 1)
+}
@@ -0,0 +1,149 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// REFRESH TASK DIFFICULTY JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// RefreshTaskDifficultyJob recomputes the difficulty of every task from
+// help requests per 100 completions over the last social.DifficultyWindow
+// and replaces the stored snapshot.
+//
+// Tasks with fewer than social.MinDifficultySample completions are stored
+// without a score, so the dashboard can still show how often they are asked.
+type RefreshTaskDifficultyJob struct {
+	// Dependencies
+	repo   social.TaskDifficultyRepository
+	logger *slog.Logger
+
+	// Configuration
+	config RefreshTaskDifficultyConfig
+	now    func() time.Time
+
+	// State
+	lastRunStats atomic.Value // *RefreshTaskDifficultyStats
+}
+
+// RefreshTaskDifficultyConfig contains configuration for the refresh job.
+type RefreshTaskDifficultyConfig struct {
+	// Window is the period help requests and completions are counted over.
+	Window time.Duration
+
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultRefreshTaskDifficultyConfig returns sensible defaults.
+func DefaultRefreshTaskDifficultyConfig() RefreshTaskDifficultyConfig {
+	return RefreshTaskDifficultyConfig{
+		Window:  social.DifficultyWindow,
+		Timeout: 2 * time.Minute,
+	}
+}
+
+// RefreshTaskDifficultyStats contains statistics from a refresh run.
+type RefreshTaskDifficultyStats struct {
+	StartedAt   time.Time
+	CompletedAt time.Time
+	Duration    time.Duration
+	Tasks       int
+	Scored      int
+	High        int
+}
+
+// NewRefreshTaskDifficultyJob creates a new refresh job.
+func NewRefreshTaskDifficultyJob(
+	repo social.TaskDifficultyRepository,
+	logger *slog.Logger,
+	config RefreshTaskDifficultyConfig,
+) *RefreshTaskDifficultyJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &RefreshTaskDifficultyJob{
+		repo:   repo,
+		logger: logger,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Name returns the job name.
+func (j *RefreshTaskDifficultyJob) Name() string {
+	return "refresh_task_difficulty"
+}
+
+// Description returns a human-readable description.
+func (j *RefreshTaskDifficultyJob) Description() string {
+	return "Recomputes task difficulty from help requests per 100 completions"
+}
+
+// Run recomputes and stores the difficulty of all recently active tasks.
+func (j *RefreshTaskDifficultyJob) Run(ctx context.Context) error {
+	startedAt := j.now()
+	stats := &RefreshTaskDifficultyStats{StartedAt: startedAt}
+
+	j.logger.Info("starting refresh_task_difficulty job")
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	densities, err := j.repo.GetHelpDensity(ctx, startedAt.Add(-j.config.Window))
+	if err != nil {
+		return fmt.Errorf("failed to count help density: %w", err)
+	}
+
+	difficulties := make([]social.TaskDifficulty, 0, len(densities))
+	for _, density := range densities {
+		d := social.NewTaskDifficulty(density, startedAt)
+		if d.Scored {
+			stats.Scored++
+		}
+		if d.IsHigh() {
+			stats.High++
+		}
+		difficulties = append(difficulties, d)
+	}
+	stats.Tasks = len(difficulties)
+
+	if err := j.repo.ReplaceAll(ctx, difficulties); err != nil {
+		return fmt.Errorf("failed to save task difficulty: %w", err)
+	}
+
+	// Finalize stats
+	stats.CompletedAt = j.now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("refresh_task_difficulty job completed",
+		"duration", stats.Duration.String(),
+		"tasks", stats.Tasks,
+		"scored", stats.Scored,
+		"high", stats.High,
+	)
+
+	return nil
+}
+
+// LastRunStats returns statistics from the last refresh run.
+func (j *RefreshTaskDifficultyJob) LastRunStats() *RefreshTaskDifficultyStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*RefreshTaskDifficultyStats)
+}
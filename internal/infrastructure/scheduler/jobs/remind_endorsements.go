@@ -0,0 +1,307 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// REMIND ENDORSEMENTS JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// RemindEndorsementsJob reminds requesters to thank their helper when a help
+// request was resolved but not endorsed within social.EndorsementReminderDelay.
+// Every participant of a team request who hasn't thanked the helper yet is
+// reminded.
+//
+// Each request gets at most one reminder. The reminder's buttons stay valid
+// until social.EndorsementPromptTTL after resolution.
+type RemindEndorsementsJob struct {
+	// Dependencies
+	helpRequests social.HelpRequestRepository
+	endorsements social.EndorsementRepository
+	studentRepo  student.Repository
+	notifier     KeyboardNotificationService
+	logger       *slog.Logger
+
+	// Configuration
+	config RemindEndorsementsConfig
+	now    func() time.Time
+
+	// State
+	lastRunStats atomic.Value // *RemindEndorsementsStats
+}
+
+// KeyboardNotificationService delivers a notification with inline buttons.
+type KeyboardNotificationService interface {
+	SendWithKeyboard(ctx context.Context, notif *notification.Notification, keyboard [][]notification.InlineButton) notification.DeliveryResult
+}
+
+// RemindEndorsementsConfig contains configuration for the reminder job.
+type RemindEndorsementsConfig struct {
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultRemindEndorsementsConfig returns sensible defaults.
+func DefaultRemindEndorsementsConfig() RemindEndorsementsConfig {
+	return RemindEndorsementsConfig{
+		Timeout: 5 * time.Minute,
+	}
+}
+
+// RemindEndorsementsStats contains statistics from a reminder run.
+type RemindEndorsementsStats struct {
+	StartedAt      time.Time
+	CompletedAt    time.Time
+	Duration       time.Duration
+	Checked        int
+	RemindersSent  int
+	SkippedFatigue int
+	Errors         []error
+}
+
+// NewRemindEndorsementsJob creates a new reminder job.
+func NewRemindEndorsementsJob(
+	helpRequests social.HelpRequestRepository,
+	endorsements social.EndorsementRepository,
+	studentRepo student.Repository,
+	notifier KeyboardNotificationService,
+	logger *slog.Logger,
+	config RemindEndorsementsConfig,
+) *RemindEndorsementsJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &RemindEndorsementsJob{
+		helpRequests: helpRequests,
+		endorsements: endorsements,
+		studentRepo:  studentRepo,
+		notifier:     notifier,
+		logger:       logger,
+		config:       config,
+		now:          time.Now,
+	}
+}
+
+// Name returns the job name.
+func (j *RemindEndorsementsJob) Name() string {
+	return "remind_endorsements"
+}
+
+// Description returns a human-readable description.
+func (j *RemindEndorsementsJob) Description() string {
+	return "Reminds students to thank helpers for resolved help requests"
+}
+
+// Run sends endorsement reminders for resolved, unendorsed help requests.
+func (j *RemindEndorsementsJob) Run(ctx context.Context) error {
+	startedAt := j.now()
+	stats := &RemindEndorsementsStats{StartedAt: startedAt}
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	requests, err := j.helpRequests.GetAwaitingEndorsement(ctx,
+		startedAt.Add(-social.EndorsementPromptTTL),
+		startedAt.Add(-social.EndorsementReminderDelay),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get help requests awaiting endorsement: %w", err)
+	}
+
+	for _, req := range requests {
+		if ctx.Err() != nil {
+			break
+		}
+		// The query is a pre-filter; the entity has the final say
+		if !req.NeedsEndorsementReminder(startedAt) {
+			continue
+		}
+		stats.Checked++
+
+		sent, err := j.remind(ctx, req, stats)
+		if err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to send endorsement reminder", "request_id", req.ID, "error", err)
+			continue
+		}
+		if sent {
+			stats.RemindersSent++
+		}
+	}
+
+	// Finalize stats
+	stats.CompletedAt = j.now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("remind_endorsements job completed",
+		"duration", stats.Duration.String(),
+		"checked", stats.Checked,
+		"sent", stats.RemindersSent,
+		"skipped_fatigue", stats.SkippedFatigue,
+		"errors", len(stats.Errors),
+	)
+
+	return nil
+}
+
+// remind sends the reminder for one request to every participant who hasn't
+// thanked the helper yet. Requests that are skipped without claiming the
+// reminder are checked again on the next run.
+func (j *RemindEndorsementsJob) remind(ctx context.Context, req *social.HelpRequest, stats *RemindEndorsementsStats) (bool, error) {
+	now := j.now()
+
+	recipients, err := j.reminderRecipients(ctx, req, now, stats)
+	if err != nil {
+		return false, err
+	}
+	if len(recipients) == 0 {
+		return false, nil
+	}
+
+	helperName := string(*req.HelperID)
+	if helper, err := j.studentRepo.GetByID(ctx, string(*req.HelperID)); err == nil {
+		helperName = helper.DisplayName
+	}
+
+	// Claim before sending: a failed delivery is not retried, a duplicate is worse
+	claimed, err := j.helpRequests.ClaimEndorsementReminder(ctx, req.ID, now)
+	if err != nil {
+		return false, err
+	}
+	if !claimed {
+		return false, nil
+	}
+	req.MarkEndorsementReminderSent(now)
+
+	task := req.TaskName
+	if task == "" {
+		task = string(req.TaskID)
+	}
+	message := fmt.Sprintf("🙏 <b>%s</b> помог тебе с задачей <b>%s</b>. Если помощь пригодилась, поблагодари — это займёт секунду.",
+		html.EscapeString(helperName), html.EscapeString(task))
+	if req.IsTeam() {
+		message = fmt.Sprintf("🙏 <b>%s</b> помог вашей команде с задачей <b>%s</b>. Если помощь пригодилась, поблагодари — это займёт секунду.",
+			html.EscapeString(helperName), html.EscapeString(task))
+	}
+
+	sent := false
+	var deliveryErr error
+	for _, recipient := range recipients {
+		n := &notification.Notification{
+			ID:             notification.NotificationID(uuid.New().String()),
+			RecipientID:    notification.RecipientID(recipient.ID),
+			TelegramChatID: notification.TelegramChatID(recipient.TelegramID),
+			Type:           notification.NotificationTypeEndorsementReminder,
+			Priority:       notification.PriorityLow,
+			Status:         notification.StatusPending,
+			Message:        message,
+			CreatedAt:      now,
+		}
+		n.SetMetadata("help_request_id", req.ID)
+
+		result := j.notifier.SendWithKeyboard(ctx, n, endorsementReminderKeyboard(req.ID))
+		if result.Skipped() {
+			continue
+		}
+		if !result.Success {
+			deliveryErr = fmt.Errorf("deliver reminder: %w", result.Error)
+			continue
+		}
+		sent = true
+	}
+
+	if !sent {
+		return false, deliveryErr
+	}
+	return true, nil
+}
+
+// reminderRecipients returns the participants of the request who haven't
+// thanked the helper for it and can be reminded now. A teammate who can't be
+// loaded is skipped rather than holding back the others' reminders.
+func (j *RemindEndorsementsJob) reminderRecipients(
+	ctx context.Context,
+	req *social.HelpRequest,
+	now time.Time,
+	stats *RemindEndorsementsStats,
+) ([]*student.Student, error) {
+	// Teammates endorse one by one; those who already did aren't reminded
+	var endorsed []*social.Endorsement
+	if req.IsTeam() {
+		var err error
+		endorsed, err = j.endorsements.ListByHelpRequestID(ctx, req.ID)
+		if err != nil {
+			return nil, fmt.Errorf("list endorsements: %w", err)
+		}
+	}
+
+	dayStart := now.UTC().Truncate(24 * time.Hour)
+	var recipients []*student.Student
+	for _, id := range req.Participants() {
+		if social.HasEndorsed(endorsed, id) {
+			continue
+		}
+
+		// Someone who already thanked this helper today is not asked again
+		endorsedToday, err := j.endorsements.ExistsFromGiverSince(ctx, id, *req.HelperID, dayStart)
+		if err != nil {
+			return nil, fmt.Errorf("check recent endorsements: %w", err)
+		}
+		if endorsedToday {
+			stats.SkippedFatigue++
+			continue
+		}
+
+		participant, err := j.studentRepo.GetByID(ctx, string(id))
+		if err != nil {
+			if id == req.RequesterID {
+				return nil, fmt.Errorf("get requester: %w", err)
+			}
+			continue
+		}
+		if !participant.CanReceiveNotification(string(notification.NotificationTypeEndorsementReminder), now) {
+			continue
+		}
+		recipients = append(recipients, participant)
+	}
+	return recipients, nil
+}
+
+// endorsementReminderKeyboard returns rating buttons bound to the help request.
+func endorsementReminderKeyboard(helpRequestID string) [][]notification.InlineButton {
+	button := func(text string, rating int) notification.InlineButton {
+		return notification.NewCallbackButton(text, fmt.Sprintf("endorse:req:%s:%d", helpRequestID, rating))
+	}
+
+	return [][]notification.InlineButton{
+		{button("⭐", 1), button("⭐⭐", 2), button("⭐⭐⭐", 3)},
+		{button("⭐⭐⭐⭐", 4), button("⭐⭐⭐⭐⭐", 5)},
+	}
+}
+
+// LastRunStats returns statistics from the last reminder run.
+func (j *RemindEndorsementsJob) LastRunStats() *RemindEndorsementsStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*RemindEndorsementsStats)
+}
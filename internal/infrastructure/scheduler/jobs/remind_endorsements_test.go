@@ -0,0 +1,170 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// fakeResolvedRequests returns copies of the stored requests, like a database.
+type fakeResolvedRequests struct {
+	social.HelpRequestRepository
+	requests []*social.HelpRequest
+	claimed  map[string]bool
+
+	// staleReads makes the query ignore claims, as a read racing a claim would.
+	staleReads bool
+}
+
+func (f *fakeResolvedRequests) GetAwaitingEndorsement(_ context.Context, resolvedAfter, resolvedBefore time.Time) ([]*social.HelpRequest, error) {
+	var result []*social.HelpRequest
+	for _, r := range f.requests {
+		if (f.claimed[r.ID] && !f.staleReads) || r.ResolvedAt == nil || r.ResolvedAt.Before(resolvedAfter) || r.ResolvedAt.After(resolvedBefore) {
+			continue
+		}
+		result = append(result, r.Clone())
+	}
+	return result, nil
+}
+
+func (f *fakeResolvedRequests) ClaimEndorsementReminder(_ context.Context, id string, _ time.Time) (bool, error) {
+	if f.claimed[id] {
+		return false, nil
+	}
+	f.claimed[id] = true
+	return true, nil
+}
+
+type fakeEndorsements struct {
+	social.EndorsementRepository
+	endorsedToday map[social.StudentID]bool
+	byRequest     map[string][]*social.Endorsement
+}
+
+func (f *fakeEndorsements) ListByHelpRequestID(_ context.Context, helpRequestID string) ([]*social.Endorsement, error) {
+	return f.byRequest[helpRequestID], nil
+}
+
+func (f *fakeEndorsements) ExistsFromGiverSince(_ context.Context, _, receiverID social.StudentID, _ time.Time) (bool, error) {
+	return f.endorsedToday[receiverID], nil
+}
+
+type fakeKeyboardNotifier struct {
+	sent      []*notification.Notification
+	keyboards [][][]notification.InlineButton
+}
+
+func (f *fakeKeyboardNotifier) SendWithKeyboard(_ context.Context, n *notification.Notification, keyboard [][]notification.InlineButton) notification.DeliveryResult {
+	f.sent = append(f.sent, n)
+	f.keyboards = append(f.keyboards, keyboard)
+	return notification.NewSuccessResult(notification.ChannelTypeTelegram, "1")
+}
+
+func newResolvedRequest(t *testing.T, id, helperID string, resolvedAt time.Time) *social.HelpRequest {
+	t.Helper()
+
+	req, err := social.NewHelpRequest(social.NewHelpRequestParams{
+		ID: id, RequesterID: "dana", TaskID: "graph-01", TaskName: "graph-01",
+	})
+	require.NoError(t, err)
+
+	helper := social.StudentID(helperID)
+	req.Status = social.HelpRequestStatusResolved
+	req.HelperID = &helper
+	req.ResolvedAt = &resolvedAt
+	return req
+}
+
+func newRemindEndorsementsFixture(now time.Time, requests ...*social.HelpRequest) (*RemindEndorsementsJob, *fakeResolvedRequests, *fakeEndorsements, *fakeKeyboardNotifier) {
+	repo := &fakeResolvedRequests{requests: requests, claimed: map[string]bool{}}
+	endorsements := &fakeEndorsements{endorsedToday: map[social.StudentID]bool{}, byRequest: map[string][]*social.Endorsement{}}
+	students := &fakeMentorStudentRepo{students: map[string]*student.Student{
+		"dana":    newMentorCandidate("dana", 0, 0),
+		"arman":   newMentorCandidate("arman", 4.5, 3),
+		"aigerim": newMentorCandidate("aigerim", 4.0, 1),
+	}}
+	notifier := &fakeKeyboardNotifier{}
+
+	job := NewRemindEndorsementsJob(repo, endorsements, students, notifier, nil, DefaultRemindEndorsementsConfig())
+	job.now = func() time.Time { return now }
+	return job, repo, endorsements, notifier
+}
+
+func TestRemindEndorsementsJob_SendsSingleReminder(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	req := newResolvedRequest(t, "req-1", "arman", now.Add(-21*time.Hour))
+
+	job, repo, _, notifier := newRemindEndorsementsFixture(now, req)
+
+	require.NoError(t, job.Run(context.Background()))
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, notification.RecipientID("dana"), notifier.sent[0].RecipientID)
+	assert.Equal(t, "endorse:req:req-1:5", notifier.keyboards[0][1][1].CallbackData)
+	assert.True(t, repo.claimed["req-1"])
+
+	// Следующий запуск не напоминает снова
+	require.NoError(t, job.Run(context.Background()))
+	assert.Len(t, notifier.sent, 1)
+
+	// Даже если выборка вернула запрос до того, как отметка стала видна,
+	// повторное напоминание не отправляется
+	repo.staleReads = true
+	require.NoError(t, job.Run(context.Background()))
+	assert.Len(t, notifier.sent, 1)
+}
+
+func TestRemindEndorsementsJob_SkipsTooEarlyAndExpired(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	tooEarly := newResolvedRequest(t, "early", "arman", now.Add(-10*time.Hour))
+	expired := newResolvedRequest(t, "expired", "arman", now.Add(-8*24*time.Hour))
+
+	job, repo, _, notifier := newRemindEndorsementsFixture(now, tooEarly, expired)
+
+	require.NoError(t, job.Run(context.Background()))
+	assert.Empty(t, notifier.sent)
+	assert.Empty(t, repo.claimed)
+
+	// Кнопки истёкшего запроса больше не принимают благодарность
+	assert.False(t, expired.IsEndorsableAt(now))
+	assert.True(t, tooEarly.IsEndorsableAt(now))
+}
+
+func TestRemindEndorsementsJob_SkipsHelperEndorsedToday(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	endorsedHelper := newResolvedRequest(t, "req-arman", "arman", now.Add(-22*time.Hour))
+	otherHelper := newResolvedRequest(t, "req-aigerim", "aigerim", now.Add(-22*time.Hour))
+
+	job, repo, endorsements, notifier := newRemindEndorsementsFixture(now, endorsedHelper, otherHelper)
+	endorsements.endorsedToday["arman"] = true
+
+	require.NoError(t, job.Run(context.Background()))
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, "req-aigerim", notifier.sent[0].Metadata["help_request_id"])
+
+	// Пропущенный запрос не заявлен и будет проверен снова
+	assert.False(t, repo.claimed["req-arman"])
+	assert.Equal(t, 1, job.LastRunStats().SkippedFatigue)
+}
+
+func TestRemindEndorsementsJob_RemindsTeammatesWhoHaveNotEndorsed(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	req := newResolvedRequest(t, "req-team", "arman", now.Add(-21*time.Hour))
+	req.Members = []social.HelpRequestMember{{StudentID: "aigerim", Status: social.HelpMemberAccepted}}
+
+	job, repo, endorsements, notifier := newRemindEndorsementsFixture(now, req)
+	endorsements.byRequest["req-team"] = []*social.Endorsement{{GiverID: "dana", ReceiverID: "arman", Rating: 5}}
+
+	require.NoError(t, job.Run(context.Background()))
+	require.Len(t, notifier.sent, 1, "the requester already thanked the helper")
+	assert.Equal(t, notification.RecipientID("aigerim"), notifier.sent[0].RecipientID)
+	assert.Contains(t, notifier.sent[0].Message, "вашей команде")
+	assert.True(t, repo.claimed["req-team"])
+	assert.Equal(t, 1, job.LastRunStats().RemindersSent)
+}
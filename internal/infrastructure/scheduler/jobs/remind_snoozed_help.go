@@ -0,0 +1,278 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// REMIND SNOOZED HELP JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// RemindSnoozedHelpJob sends snoozed help invitations ("⏰ Напомнить через
+// 2 часа") again once their time has come.
+//
+// The invitation is only repeated while the request still waits for a
+// helper. If it was resolved, cancelled, expired or taken by another helper
+// in the meantime, the helper gets a short "уже решили, спасибо!" instead;
+// if the helper took it themselves, nothing is sent. Each snooze is sent
+// once: it is claimed before delivery.
+type RemindSnoozedHelpJob struct {
+	// Dependencies
+	snoozes      social.HelpSnoozeRepository
+	helpRequests social.HelpRequestRepository
+	studentRepo  student.Repository
+	notifier     KeyboardNotificationService
+	logger       *slog.Logger
+
+	// Configuration
+	config RemindSnoozedHelpConfig
+	now    func() time.Time
+
+	// State
+	lastRunStats atomic.Value // *RemindSnoozedHelpStats
+}
+
+// RemindSnoozedHelpConfig contains configuration for the job.
+type RemindSnoozedHelpConfig struct {
+	// BatchSize is the maximum number of snoozes handled per run.
+	BatchSize int
+
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultRemindSnoozedHelpConfig returns sensible defaults.
+func DefaultRemindSnoozedHelpConfig() RemindSnoozedHelpConfig {
+	return RemindSnoozedHelpConfig{
+		BatchSize: 200,
+		Timeout:   2 * time.Minute,
+	}
+}
+
+// RemindSnoozedHelpStats contains statistics from a run.
+type RemindSnoozedHelpStats struct {
+	StartedAt     time.Time
+	CompletedAt   time.Time
+	Duration      time.Duration
+	Due           int
+	RemindersSent int
+	ClosedSent    int
+	Dropped       int
+	Deferred      int
+	Errors        []error
+}
+
+// NewRemindSnoozedHelpJob creates a new job.
+func NewRemindSnoozedHelpJob(
+	snoozes social.HelpSnoozeRepository,
+	helpRequests social.HelpRequestRepository,
+	studentRepo student.Repository,
+	notifier KeyboardNotificationService,
+	logger *slog.Logger,
+	config RemindSnoozedHelpConfig,
+) *RemindSnoozedHelpJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultRemindSnoozedHelpConfig().BatchSize
+	}
+
+	return &RemindSnoozedHelpJob{
+		snoozes:      snoozes,
+		helpRequests: helpRequests,
+		studentRepo:  studentRepo,
+		notifier:     notifier,
+		logger:       logger,
+		config:       config,
+		now:          time.Now,
+	}
+}
+
+// Name returns the job name.
+func (j *RemindSnoozedHelpJob) Name() string {
+	return "remind_snoozed_help"
+}
+
+// Description returns a human-readable description.
+func (j *RemindSnoozedHelpJob) Description() string {
+	return "Re-sends help invitations that helpers snoozed"
+}
+
+// Run handles the snoozes that are due.
+func (j *RemindSnoozedHelpJob) Run(ctx context.Context) error {
+	startedAt := j.now()
+	stats := &RemindSnoozedHelpStats{StartedAt: startedAt}
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	due, err := j.snoozes.GetDue(ctx, startedAt, j.config.BatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get due help snoozes: %w", err)
+	}
+	stats.Due = len(due)
+
+	for _, snooze := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := j.handle(ctx, snooze, stats); err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to handle help snooze",
+				"snooze_id", snooze.ID,
+				"request_id", snooze.HelpRequestID,
+				"error", err,
+			)
+		}
+	}
+
+	// Finalize stats
+	stats.CompletedAt = j.now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("remind_snoozed_help job completed",
+		"duration", stats.Duration.String(),
+		"due", stats.Due,
+		"reminders_sent", stats.RemindersSent,
+		"closed_sent", stats.ClosedSent,
+		"dropped", stats.Dropped,
+		"deferred", stats.Deferred,
+		"errors", len(stats.Errors),
+	)
+
+	return nil
+}
+
+// handle sends the reminder or the "already handled" notice for one snooze.
+// A helper in quiet hours is left for a later run; a helper who turned help
+// invitations off, muted them or left gets nothing.
+func (j *RemindSnoozedHelpJob) handle(ctx context.Context, snooze *social.HelpSnooze, stats *RemindSnoozedHelpStats) error {
+	now := j.now()
+
+	req, err := j.helpRequests.GetByID(ctx, snooze.HelpRequestID)
+	if err != nil {
+		return fmt.Errorf("get help request: %w", err)
+	}
+	helper, err := j.studentRepo.GetByID(ctx, string(snooze.HelperID))
+	if err != nil {
+		return fmt.Errorf("get helper: %w", err)
+	}
+
+	action := req.SnoozeAction(snooze.HelperID, now)
+	switch {
+	case action == social.HelpSnoozeNothing,
+		!helper.Status.CanReceiveNotifications(),
+		!helper.AcceptsNotification(string(notification.NotificationTypeHelpRequest), now):
+		if _, err := j.snoozes.MarkSent(ctx, snooze.ID, now); err != nil {
+			return err
+		}
+		stats.Dropped++
+		return nil
+	case helper.Preferences.IsQuietHour(now):
+		stats.Deferred++
+		return nil
+	}
+
+	// Claim before sending: a failed delivery is not retried, a duplicate is worse
+	claimed, err := j.snoozes.MarkSent(ctx, snooze.ID, now)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+
+	requesterName := string(req.RequesterID)
+	if requester, err := j.studentRepo.GetByID(ctx, string(req.RequesterID)); err == nil {
+		requesterName = requester.DisplayName
+	}
+	task := req.TaskName
+	if task == "" {
+		task = string(req.TaskID)
+	}
+
+	var (
+		message  string
+		keyboard [][]notification.InlineButton
+	)
+	if action == social.HelpSnoozeRemind {
+		message = fmt.Sprintf("⏰ Напоминаю: <b>%s</b> всё ещё ждёт помощи по задаче <b>%s</b>. Сможешь помочь?",
+			html.EscapeString(requesterName), html.EscapeString(task))
+		keyboard = snoozedHelpKeyboard(req.ID, snooze.Attempt < social.MaxHelpSnoozes)
+	} else {
+		message = fmt.Sprintf("✅ Запрос <b>%s</b> по задаче <b>%s</b> уже решили, спасибо!",
+			html.EscapeString(requesterName), html.EscapeString(task))
+	}
+
+	n := &notification.Notification{
+		ID:             notification.NotificationID(uuid.New().String()),
+		RecipientID:    notification.RecipientID(helper.ID),
+		TelegramChatID: notification.TelegramChatID(helper.TelegramID),
+		Type:           notification.NotificationTypeHelpRequest,
+		Priority:       notification.PriorityNormal,
+		Status:         notification.StatusPending,
+		Message:        message,
+		CreatedAt:      now,
+	}
+	n.SetMetadata("help_request_id", req.ID)
+
+	result := j.notifier.SendWithKeyboard(ctx, n, keyboard)
+	if result.Skipped() {
+		stats.Dropped++
+		return nil
+	}
+	if !result.Success {
+		return fmt.Errorf("deliver snoozed invitation: %w", result.Error)
+	}
+
+	if action == social.HelpSnoozeRemind {
+		stats.RemindersSent++
+	} else {
+		stats.ClosedSent++
+	}
+	return nil
+}
+
+// snoozedHelpKeyboard returns the buttons of the repeated invitation, the
+// same as the first one. The snooze button is left out once the helper has
+// used up their snoozes.
+func snoozedHelpKeyboard(helpRequestID string, canSnooze bool) [][]notification.InlineButton {
+	keyboard := [][]notification.InlineButton{{
+		notification.NewCallbackButton("✅ Помогу", "helpreq:accept:"+helpRequestID),
+		notification.NewCallbackButton("🙅 Не сейчас", "helpreq:decline:"+helpRequestID),
+	}}
+	if canSnooze {
+		keyboard = append(keyboard, []notification.InlineButton{
+			notification.NewCallbackButton("⏰ Напомнить через 2 часа", "helpreq:snooze:"+helpRequestID),
+		})
+	}
+	return append(keyboard, []notification.InlineButton{
+		notification.NewCallbackButton("⚠️ Пожаловаться", "report:h:"+helpRequestID),
+	})
+}
+
+// LastRunStats returns statistics from the last run.
+func (j *RemindSnoozedHelpJob) LastRunStats() *RemindSnoozedHelpStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*RemindSnoozedHelpStats)
+}
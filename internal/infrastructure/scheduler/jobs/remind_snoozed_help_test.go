@@ -0,0 +1,161 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type fakeHelpSnoozes struct {
+	social.HelpSnoozeRepository
+	snoozes []*social.HelpSnooze
+}
+
+func (f *fakeHelpSnoozes) GetDue(_ context.Context, now time.Time, limit int) ([]*social.HelpSnooze, error) {
+	var due []*social.HelpSnooze
+	for _, s := range f.snoozes {
+		if s.SentAt == nil && !s.RemindAt.After(now) && len(due) < limit {
+			copied := *s
+			due = append(due, &copied)
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeHelpSnoozes) MarkSent(_ context.Context, id int64, at time.Time) (bool, error) {
+	for _, s := range f.snoozes {
+		if s.ID == id && s.SentAt == nil {
+			s.SentAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeSnoozedRequests struct {
+	social.HelpRequestRepository
+	requests map[string]*social.HelpRequest
+}
+
+func (f *fakeSnoozedRequests) GetByID(_ context.Context, id string) (*social.HelpRequest, error) {
+	if req, ok := f.requests[id]; ok {
+		return req.Clone(), nil
+	}
+	return nil, social.ErrHelpRequestNotFound
+}
+
+func TestRemindSnoozedHelpJob(t *testing.T) {
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	other := social.StudentID("arman")
+	self := social.StudentID("aigerim")
+
+	tests := []struct {
+		name        string
+		change      func(req *social.HelpRequest)
+		wantText    string
+		wantNone    bool
+		wantButtons bool
+	}{
+		{name: "still open", change: func(*social.HelpRequest) {}, wantText: "всё ещё ждёт помощи", wantButtons: true},
+		{name: "resolved", change: func(r *social.HelpRequest) { r.Status = social.HelpRequestStatusResolved }, wantText: "уже решили, спасибо!"},
+		{name: "cancelled", change: func(r *social.HelpRequest) { r.Status = social.HelpRequestStatusCancelled }, wantText: "уже решили, спасибо!"},
+		{name: "expired", change: func(r *social.HelpRequest) { r.ExpiresAt = now.Add(-time.Minute) }, wantText: "уже решили, спасибо!"},
+		{name: "taken by another helper", change: func(r *social.HelpRequest) {
+			r.HelperID, r.Status = &other, social.HelpRequestStatusInProgress
+		}, wantText: "уже решили, спасибо!"},
+		{name: "taken by the helper", change: func(r *social.HelpRequest) {
+			r.HelperID, r.Status = &self, social.HelpRequestStatusInProgress
+		}, wantNone: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := social.NewHelpRequest(social.NewHelpRequestParams{
+				ID: "req-1", RequesterID: "dana", TaskID: "graph-01", TaskName: "graph-01",
+			})
+			require.NoError(t, err)
+			req.ExpiresAt = now.Add(time.Hour)
+			tt.change(req)
+
+			snoozes := &fakeHelpSnoozes{snoozes: []*social.HelpSnooze{
+				{ID: 1, HelpRequestID: "req-1", HelperID: self, Attempt: 1, RemindAt: now.Add(-time.Minute)},
+				{ID: 2, HelpRequestID: "req-1", HelperID: other, Attempt: 1, RemindAt: now.Add(time.Hour)},
+			}}
+			students := &fakeMentorStudentRepo{students: map[string]*student.Student{
+				"dana":    newMentorCandidate("dana", 0, 0),
+				"aigerim": newMentorCandidate("aigerim", 4.0, 1),
+			}}
+			notifier := &fakeKeyboardNotifier{}
+			job := NewRemindSnoozedHelpJob(snoozes, &fakeSnoozedRequests{requests: map[string]*social.HelpRequest{"req-1": req}},
+				students, notifier, nil, DefaultRemindSnoozedHelpConfig())
+			job.now = func() time.Time { return now }
+
+			require.NoError(t, job.Run(context.Background()))
+			require.NotNil(t, snoozes.snoozes[0].SentAt)
+			assert.Nil(t, snoozes.snoozes[1].SentAt, "not due yet")
+
+			if tt.wantNone {
+				assert.Empty(t, notifier.sent)
+				return
+			}
+			require.Len(t, notifier.sent, 1)
+			assert.Equal(t, notification.RecipientID("aigerim"), notifier.sent[0].RecipientID)
+			assert.Contains(t, notifier.sent[0].Message, tt.wantText)
+			if tt.wantButtons {
+				assert.Equal(t, "helpreq:accept:req-1", notifier.keyboards[0][0][0].CallbackData)
+				assert.Equal(t, "helpreq:snooze:req-1", notifier.keyboards[0][1][0].CallbackData)
+			} else {
+				assert.Empty(t, notifier.keyboards[0])
+			}
+
+			// A snooze is sent once
+			require.NoError(t, job.Run(context.Background()))
+			assert.Len(t, notifier.sent, 1)
+		})
+	}
+}
+
+func TestRemindSnoozedHelpJob_LastSnoozeAndQuietHours(t *testing.T) {
+	req, err := social.NewHelpRequest(social.NewHelpRequestParams{
+		ID: "req-1", RequesterID: "dana", TaskID: "graph-01", TaskName: "graph-01",
+	})
+	require.NoError(t, err)
+
+	// 23:30 in Almaty: quiet hours
+	now := time.Date(2025, 3, 10, 18, 30, 0, 0, time.UTC)
+	req.ExpiresAt = now.Add(12 * time.Hour)
+
+	snoozes := &fakeHelpSnoozes{snoozes: []*social.HelpSnooze{
+		{ID: 1, HelpRequestID: "req-1", HelperID: "aigerim", Attempt: social.MaxHelpSnoozes, RemindAt: now.Add(-time.Minute)},
+	}}
+	students := &fakeMentorStudentRepo{students: map[string]*student.Student{
+		"dana":    newMentorCandidate("dana", 0, 0),
+		"aigerim": newMentorCandidate("aigerim", 4.0, 1),
+	}}
+	notifier := &fakeKeyboardNotifier{}
+	job := NewRemindSnoozedHelpJob(snoozes, &fakeSnoozedRequests{requests: map[string]*social.HelpRequest{"req-1": req}},
+		students, notifier, nil, DefaultRemindSnoozedHelpConfig())
+	job.now = func() time.Time { return now }
+
+	require.NoError(t, job.Run(context.Background()))
+	assert.Empty(t, notifier.sent)
+	assert.Nil(t, snoozes.snoozes[0].SentAt)
+	assert.Equal(t, 1, job.LastRunStats().Deferred)
+
+	// In the morning the invitation comes, without another snooze
+	now = time.Date(2025, 3, 11, 4, 0, 0, 0, time.UTC)
+	require.NoError(t, job.Run(context.Background()))
+	require.Len(t, notifier.sent, 1)
+	for _, row := range notifier.keyboards[0] {
+		for _, button := range row {
+			assert.NotEqual(t, "helpreq:snooze:req-1", button.CallbackData)
+		}
+	}
+}
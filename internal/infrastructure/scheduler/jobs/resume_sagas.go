@@ -0,0 +1,222 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/saga"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RESUME SAGAS JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// AchievementFlowResumer continues an interrupted achievement flow.
+// Implemented by saga.AchievementFlowSaga.
+type AchievementFlowResumer interface {
+	Resume(ctx context.Context, exec *saga.Execution) (*saga.AchievementFlowResult, error)
+}
+
+// OnboardingResumer continues an interrupted onboarding.
+// Implemented by saga.OnboardingSaga.
+type OnboardingResumer interface {
+	Resume(ctx context.Context, exec *saga.Execution) (*saga.OnboardingResult, error)
+}
+
+// ResumeSagasJob finds saga executions that stopped in an intermediate step,
+// e.g. because the process was restarted, and resumes them from the step
+// after the last completed one.
+type ResumeSagasJob struct {
+	// Dependencies
+	executions      saga.ExecutionRepository
+	achievementFlow AchievementFlowResumer
+	onboarding      OnboardingResumer
+	logger          *slog.Logger
+
+	// Configuration
+	config ResumeSagasConfig
+	now    func() time.Time
+
+	// State
+	lastRunStats atomic.Value // *ResumeSagasStats
+}
+
+// ResumeSagasConfig contains configuration for the recovery job.
+type ResumeSagasConfig struct {
+	// StalledAfter is how long an execution may go without progress
+	// before it is considered interrupted.
+	StalledAfter time.Duration
+
+	// BatchSize is the maximum number of executions resumed per run.
+	BatchSize int
+
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultResumeSagasConfig returns sensible defaults.
+func DefaultResumeSagasConfig() ResumeSagasConfig {
+	return ResumeSagasConfig{
+		StalledAfter: 10 * time.Minute,
+		BatchSize:    50,
+		Timeout:      5 * time.Minute,
+	}
+}
+
+// ResumeSagasStats contains statistics from a recovery run.
+type ResumeSagasStats struct {
+	StartedAt   time.Time
+	CompletedAt time.Time
+	Duration    time.Duration
+	Stalled     int
+	Resumed     int
+	Abandoned   int
+	Errors      []error
+}
+
+// NewResumeSagasJob creates a new recovery job.
+// A nil resumer leaves executions of that saga type untouched.
+func NewResumeSagasJob(
+	executions saga.ExecutionRepository,
+	achievementFlow AchievementFlowResumer,
+	onboarding OnboardingResumer,
+	logger *slog.Logger,
+	config ResumeSagasConfig,
+) *ResumeSagasJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ResumeSagasJob{
+		executions:      executions,
+		achievementFlow: achievementFlow,
+		onboarding:      onboarding,
+		logger:          logger,
+		config:          config,
+		now:             time.Now,
+	}
+}
+
+// Name returns the job name.
+func (j *ResumeSagasJob) Name() string {
+	return "resume_sagas"
+}
+
+// Description returns a human-readable description.
+func (j *ResumeSagasJob) Description() string {
+	return "Resumes saga executions interrupted in an intermediate step"
+}
+
+// Run resumes stalled saga executions.
+func (j *ResumeSagasJob) Run(ctx context.Context) error {
+	startedAt := j.now()
+	stats := &ResumeSagasStats{StartedAt: startedAt}
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	stalledBefore := startedAt.Add(-j.config.StalledAfter)
+	executions, err := j.executions.GetStalled(ctx, stalledBefore, j.config.BatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get stalled saga executions: %w", err)
+	}
+	stats.Stalled = len(executions)
+
+	for _, exec := range executions {
+		if ctx.Err() != nil {
+			break
+		}
+		if !j.canResume(exec.SagaType) {
+			continue
+		}
+
+		// Another worker may be resuming the same execution
+		claimed, err := j.executions.ClaimStalled(ctx, exec.ID, stalledBefore, j.now())
+		if err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to claim saga execution", "execution_id", exec.ID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		err = j.resume(ctx, exec)
+		switch {
+		case err == nil:
+			stats.Resumed++
+		case errors.Is(err, saga.ErrExecutionNotResumable):
+			stats.Abandoned++
+			j.logger.Info("saga execution cannot be resumed",
+				"execution_id", exec.ID,
+				"saga_type", exec.SagaType,
+				"step", exec.CurrentStep,
+				"error", err,
+			)
+		default:
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to resume saga execution",
+				"execution_id", exec.ID,
+				"saga_type", exec.SagaType,
+				"step", exec.CurrentStep,
+				"error", err,
+			)
+		}
+	}
+
+	// Finalize stats
+	stats.CompletedAt = j.now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("resume_sagas job completed",
+		"duration", stats.Duration.String(),
+		"stalled", stats.Stalled,
+		"resumed", stats.Resumed,
+		"abandoned", stats.Abandoned,
+		"errors", len(stats.Errors),
+	)
+
+	return nil
+}
+
+// canResume returns true if the job has a resumer for the saga type.
+func (j *ResumeSagasJob) canResume(sagaType saga.SagaType) bool {
+	switch sagaType {
+	case saga.SagaTypeAchievementFlow:
+		return j.achievementFlow != nil
+	case saga.SagaTypeOnboarding:
+		return j.onboarding != nil
+	default:
+		return false
+	}
+}
+
+// resume dispatches an execution to the saga it belongs to.
+func (j *ResumeSagasJob) resume(ctx context.Context, exec *saga.Execution) error {
+	var err error
+	switch exec.SagaType {
+	case saga.SagaTypeAchievementFlow:
+		_, err = j.achievementFlow.Resume(ctx, exec)
+	case saga.SagaTypeOnboarding:
+		_, err = j.onboarding.Resume(ctx, exec)
+	}
+	return err
+}
+
+// LastRunStats returns statistics from the last recovery run.
+func (j *ResumeSagasJob) LastRunStats() *ResumeSagasStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*ResumeSagasStats)
+}
@@ -5,7 +5,9 @@ package jobs
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"html"
 	"log/slog"
 	"strings"
 	"sync"
@@ -13,9 +15,14 @@ import (
 	"time"
 
 	"github.com/alem-hub/alem-community-hub/internal/domain/activity"
+	"github.com/alem-hub/alem-community-hub/internal/domain/event"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
 	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
 	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/alem"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/scheduler"
+
+	"github.com/google/uuid"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -38,6 +45,24 @@ type SyncAllStudentsJob struct {
 	logger         *slog.Logger
 	mapper         *alem.Mapper
 
+	// Community events (optional, nil disables event scoring)
+	events     event.Repository
+	scoreboard event.Scoreboard
+
+	// XP spike review (optional, nil disables detection)
+	xpFlags    student.XPFlagRepository
+	xpReviewer KeyboardNotificationService
+
+	// Cache invalidations for other services (optional)
+	invalidations CacheInvalidations
+
+	// Identity reconciliation (optional, nil skips flagging and auditing)
+	identityConflicts student.IdentityConflictRepository
+	auditLog          shared.AuditLog
+
+	// Per-student lock shared with /sync_me (optional, nil disables)
+	syncLock student.SyncLock
+
 	// Configuration
 	config SyncAllStudentsConfig
 
@@ -68,6 +93,13 @@ type SyncAllStudentsConfig struct {
 	// Bootcamp Config
 	BootcampID string
 	CohortID   string
+
+	// XPSpike defines which daily gains are sent to curators for review.
+	XPSpike student.XPSpikeCriteria
+
+	// XPReviewChatID is the curator chat flagged days are posted to
+	// (0 records flags without notifying).
+	XPReviewChatID int64
 }
 
 // DefaultSyncAllStudentsConfig returns sensible defaults.
@@ -79,6 +111,7 @@ func DefaultSyncAllStudentsConfig() SyncAllStudentsConfig {
 		Timeout:            10 * time.Minute,
 		RetryAttempts:      2,
 		SkipRecentlySynced: true,
+		XPSpike:            student.DefaultXPSpikeCriteria(),
 	}
 }
 
@@ -94,6 +127,9 @@ type SyncStats struct {
 	FailedCount   int
 	TotalXPDelta  int
 	Errors        []SyncError
+
+	// Cache holds Alem response cache counters for this run
+	Cache alem.CacheStats
 }
 
 // SyncError represents an error during sync.
@@ -120,6 +156,11 @@ type AlemClient interface {
 	GetStudentTaskCompletions(ctx context.Context, studentID string) ([]alem.TaskCompletionDTO, error)
 }
 
+// CacheStatsProvider is implemented by Alem clients that cache responses.
+type CacheStatsProvider interface {
+	CacheStats() alem.CacheStats
+}
+
 // NewSyncAllStudentsJob creates a new sync job.
 func NewSyncAllStudentsJob(
 	studentRepo student.Repository,
@@ -151,6 +192,45 @@ func NewSyncAllStudentsJob(
 	}
 }
 
+// WithEvents enables scoring of live community events from the XP and
+// tasks gained during sync.
+func (j *SyncAllStudentsJob) WithEvents(events event.Repository, scoreboard event.Scoreboard) *SyncAllStudentsJob {
+	j.events = events
+	j.scoreboard = scoreboard
+	return j
+}
+
+// WithXPFlags enables detection of suspicious XP spikes. Flagged days are
+// posted to the curator chat through reviewer (nil only records them).
+func (j *SyncAllStudentsJob) WithXPFlags(flags student.XPFlagRepository, reviewer KeyboardNotificationService) *SyncAllStudentsJob {
+	j.xpFlags = flags
+	j.xpReviewer = reviewer
+	return j
+}
+
+// WithInvalidations announces students whose XP or name changed to other
+// services, so their in-process caches drop the stale copies.
+func (j *SyncAllStudentsJob) WithInvalidations(invalidations CacheInvalidations) *SyncAllStudentsJob {
+	j.invalidations = invalidations
+	return j
+}
+
+// WithIdentity enables flagging of login/email conflicts for /merge and
+// auditing of email changes found by Alem login.
+func (j *SyncAllStudentsJob) WithIdentity(conflicts student.IdentityConflictRepository, auditLog shared.AuditLog) *SyncAllStudentsJob {
+	j.identityConflicts = conflicts
+	j.auditLog = auditLog
+	return j
+}
+
+// WithSyncLock makes the job skip students that are being synced by
+// /sync_me and hold the lock while syncing, so the bot waits instead of
+// syncing the same student twice.
+func (j *SyncAllStudentsJob) WithSyncLock(lock student.SyncLock) *SyncAllStudentsJob {
+	j.syncLock = lock
+	return j
+}
+
 // Name returns the job name.
 func (j *SyncAllStudentsJob) Name() string {
 	return "sync_all_students"
@@ -171,6 +251,12 @@ func (j *SyncAllStudentsJob) Run(ctx context.Context) error {
 
 	j.logger.Info("starting sync_all_students job")
 
+	// Manual triggers must see the platform's current state
+	if scheduler.IsManualRun(ctx) {
+		ctx = alem.WithForceFresh(ctx)
+	}
+	cacheBefore := j.cacheStats()
+
 	// Apply timeout
 	if j.config.Timeout > 0 {
 		var cancel context.CancelFunc
@@ -178,10 +264,14 @@ func (j *SyncAllStudentsJob) Run(ctx context.Context) error {
 		defer cancel()
 	}
 
+	runID := j.startRun(ctx, startedAt)
+
 	// Get all students to sync from our database
 	students, err := j.getStudentsToSync(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get students to sync: %w", err)
+		err = fmt.Errorf("failed to get students to sync: %w", err)
+		j.finishRun(ctx, runID, stats, err)
+		return err
 	}
 
 	stats.TotalStudents = len(students)
@@ -191,20 +281,17 @@ func (j *SyncAllStudentsJob) Run(ctx context.Context) error {
 		stats.CompletedAt = time.Now()
 		stats.Duration = stats.CompletedAt.Sub(startedAt)
 		j.lastSyncStats.Store(stats)
+		j.finishRun(ctx, runID, stats, nil)
 		return nil
 	}
 
 	// Sync students using bootcamp data (no external GetAllStudents API call)
-	j.syncStudentsFromBootcamp(ctx, students, stats)
-
-	// Update last sync time
-	if err := j.syncRepo.SetLastSyncTime(ctx, time.Now()); err != nil {
-		j.logger.Error("failed to set last sync time", "error", err)
-	}
+	j.syncStudentsFromBootcamp(ctx, students, j.openEvents(ctx), stats)
 
 	// Finalize stats
 	stats.CompletedAt = time.Now()
 	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	stats.Cache = j.cacheStats().Sub(cacheBefore)
 	j.lastSyncStats.Store(stats)
 
 	// Emit sync completed event
@@ -217,22 +304,102 @@ func (j *SyncAllStudentsJob) Run(ctx context.Context) error {
 		"updated", stats.UpdatedCount,
 		"failed", stats.FailedCount,
 		"skipped", stats.SkippedCount,
+		"cache_hit_ratio", fmt.Sprintf("%.2f", stats.Cache.HitRatio()),
+		"cache_not_modified", stats.Cache.NotModified,
 	)
 
-	// Return error if too many failures
-	failureRate := float64(stats.FailedCount) / float64(stats.TotalStudents)
-	if failureRate > 0.5 {
-		return fmt.Errorf("sync failed for more than 50%% of students (%d/%d)",
+	// A run cut short leaves the rest of the students stale for the next one
+	var runErr error
+	if err := ctx.Err(); err != nil {
+		runErr = fmt.Errorf("sync interrupted after %d/%d students: %w",
+			stats.SyncedCount+stats.FailedCount, stats.TotalStudents, err)
+	} else if float64(stats.FailedCount)/float64(stats.TotalStudents) > 0.5 {
+		// Return error if too many failures
+		runErr = fmt.Errorf("sync failed for more than 50%% of students (%d/%d)",
 			stats.FailedCount, stats.TotalStudents)
 	}
+	j.finishRun(ctx, runID, stats, runErr)
 
-	return nil
+	return runErr
+}
+
+// startRun records the start of a run. Returns 0 if it could not be recorded.
+func (j *SyncAllStudentsJob) startRun(ctx context.Context, startedAt time.Time) int64 {
+	if j.syncRepo == nil {
+		return 0
+	}
+	runID, err := j.syncRepo.StartSyncRun(ctx, startedAt)
+	if err != nil {
+		j.logger.Error("failed to record sync run start", "error", err)
+		return 0
+	}
+	return runID
+}
+
+// finishRun stores the outcome of the run; runErr marks it failed.
+func (j *SyncAllStudentsJob) finishRun(ctx context.Context, runID int64, stats *SyncStats, runErr error) {
+	if j.syncRepo == nil || runID == 0 {
+		return
+	}
+
+	run := student.SyncRun{
+		ID:         runID,
+		StartedAt:  stats.StartedAt,
+		FinishedAt: time.Now(),
+		Status:     student.SyncRunCompleted,
+		Total:      stats.TotalStudents,
+		Synced:     stats.SyncedCount,
+		Failed:     stats.FailedCount,
+	}
+	if runErr != nil {
+		run.Status = student.SyncRunFailed
+		run.Error = runErr.Error()
+	}
+
+	// The run may have been stopped by the job timeout
+	if err := j.syncRepo.FinishSyncRun(context.WithoutCancel(ctx), run); err != nil {
+		j.logger.Error("failed to record sync run result", "run_id", runID, "error", err)
+	}
+}
+
+// recordSyncError stores a failed sync of a student, so it stays due and the
+// failure is visible until the next successful sync.
+func (j *SyncAllStudentsJob) recordSyncError(ctx context.Context, studentID string, syncErr error) {
+	if j.syncRepo == nil {
+		return
+	}
+	err := j.syncRepo.SaveSyncError(context.WithoutCancel(ctx), student.SyncError{
+		StudentID:  studentID,
+		ErrorType:  "sync",
+		Message:    syncErr.Error(),
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		j.logger.Warn("failed to save sync error",
+			"student_id", studentID,
+			"error", err,
+		)
+	}
+}
+
+// markSynced records a successful sync of a student.
+func (j *SyncAllStudentsJob) markSynced(ctx context.Context, studentID string) {
+	if j.syncRepo == nil {
+		return
+	}
+	if err := j.syncRepo.MarkSynced(ctx, studentID, time.Now()); err != nil {
+		j.logger.Warn("failed to mark student as synced",
+			"student_id", studentID,
+			"error", err,
+		)
+	}
 }
 
 // syncStudentsFromBootcamp syncs students using bootcamp data instead of external API.
 func (j *SyncAllStudentsJob) syncStudentsFromBootcamp(
 	ctx context.Context,
 	students []*student.Student,
+	liveEvents []*event.Event,
 	stats *SyncStats,
 ) {
 	var (
@@ -256,7 +423,10 @@ func (j *SyncAllStudentsJob) syncStudentsFromBootcamp(
 			defer func() { <-semaphore }() // Release
 
 			// Sync bootcamp progress for this student
-			updated, xpDelta, err := j.syncStudentBootcamp(ctx, st)
+			updated, xpDelta, err := j.syncStudentBootcamp(ctx, st, liveEvents)
+			if err != nil {
+				j.recordSyncError(ctx, st.ID, err)
+			}
 
 			mu.Lock()
 			defer mu.Unlock()
@@ -288,9 +458,11 @@ func (j *SyncAllStudentsJob) syncStudentsFromBootcamp(
 }
 
 // syncStudentBootcamp syncs a single student using bootcamp data.
+// liveEvents are the open events loaded once for the run.
 func (j *SyncAllStudentsJob) syncStudentBootcamp(
 	ctx context.Context,
 	s *student.Student,
+	liveEvents []*event.Event,
 ) (updated bool, xpDelta int, err error) {
 	// Fetch bootcamp data
 	j.logger.Info("fetching bootcamp data",
@@ -323,6 +495,7 @@ func (j *SyncAllStudentsJob) syncStudentBootcamp(
 	// Extract XP from bootcamp UserXP
 	newXP := student.XP(bootcamp.UserXP)
 	oldXP := int(s.CurrentXP)
+	syncedAt := time.Now()
 
 	if newXP != s.CurrentXP {
 		delta, err := s.UpdateXP(newXP)
@@ -356,26 +529,41 @@ func (j *SyncAllStudentsJob) syncStudentBootcamp(
 	}
 
 	// Update sync timestamp
-	s.SyncedWith(time.Now())
+	s.SyncedWith(syncedAt)
 
 	// Persist changes
 	if err := j.studentRepo.Update(ctx, s); err != nil {
 		return false, 0, fmt.Errorf("failed to save student: %w", err)
 	}
 
-	// Mark as synced
-	if err := j.syncRepo.MarkSynced(ctx, s.ID, time.Now()); err != nil {
-		j.logger.Warn("failed to mark student as synced",
-			"student_id", s.ID,
-			"error", err,
-		)
+	// Attribute the XP delta to the days the tasks were actually completed
+	if updated {
+		completions, err := j.collectNewCompletions(ctx, s)
+		if err != nil {
+			j.logger.Warn("failed to fetch task completions, attributing XP to sync time",
+				"student_id", s.ID,
+				"error", err,
+			)
+		}
+		days := j.applyDailyXP(ctx, s, student.XP(xpDelta), completions, syncedAt)
+		j.updateStreak(ctx, s, days, syncedAt)
+		j.flagXPSpikes(ctx, s, student.XP(oldXP), days)
+		j.scoreEvents(ctx, liveEvents, s.ID, student.XP(xpDelta), completions, syncedAt)
 	}
 
+	// Mark as synced
+	j.markSynced(ctx, s.ID)
+
 	return updated, xpDelta, nil
 }
 
-// getStudentsToSync returns the list of students that need syncing.
+// getStudentsToSync returns the list of students that need syncing: those
+// not synced within MinSyncInterval, the stalest first.
 func (j *SyncAllStudentsJob) getStudentsToSync(ctx context.Context) ([]*student.Student, error) {
+	if j.config.SkipRecentlySynced && j.syncRepo != nil {
+		return j.syncRepo.GetStudentsToSync(ctx, j.config.MinSyncInterval)
+	}
+
 	opts := student.DefaultListOptions().WithInactive()
 	students, err := j.studentRepo.GetAll(ctx, opts)
 	if err != nil {
@@ -403,6 +591,7 @@ func (j *SyncAllStudentsJob) syncStudentsConcurrently(
 	ctx context.Context,
 	students []*student.Student,
 	alemData map[string]alem.StudentDTO,
+	liveEvents []*event.Event,
 	stats *SyncStats,
 ) {
 	var (
@@ -426,26 +615,38 @@ func (j *SyncAllStudentsJob) syncStudentsConcurrently(
 			defer func() { <-semaphore }() // Release
 
 			// Find Alem data for this student
-			// Derive login from email (assuming email is login@alem.school or similar)
-			login := ""
-			if idx := strings.Index(st.Email, "@"); idx > 0 {
-				login = st.Email[:idx]
+			alemStudent, found := alemData[st.SyncLogin()]
+			if !found {
+				mu.Lock()
+				stats.SkippedCount++
+				mu.Unlock()
+				return
 			}
 
-			alemStudent, found := alemData[login]
-			if !found {
+			// Skip students synced by /sync_me right now
+			unlock, ok := j.lockStudent(ctx, st.ID)
+			if !ok {
 				mu.Lock()
 				stats.SkippedCount++
 				mu.Unlock()
 				return
 			}
+			defer unlock()
 
 			// Sync the student
-			updated, xpDelta, err := j.syncStudent(ctx, st, &alemStudent)
+			updated, xpDelta, err := j.syncStudent(ctx, st, &alemStudent, liveEvents)
+			if err != nil && !errors.Is(err, student.ErrIdentityConflict) {
+				j.recordSyncError(ctx, st.ID, err)
+			}
 
 			mu.Lock()
 			defer mu.Unlock()
 
+			if errors.Is(err, student.ErrIdentityConflict) {
+				// Flagged for /merge, nothing to retry
+				stats.SkippedCount++
+				return
+			}
 			if err != nil {
 				stats.FailedCount++
 				stats.Errors = append(stats.Errors, SyncError{
@@ -472,14 +673,44 @@ func (j *SyncAllStudentsJob) syncStudentsConcurrently(
 	wg.Wait()
 }
 
+// lockStudent takes the sync lock of a student. Returns false if /sync_me
+// holds it. Lock errors do not stop the batch sync.
+func (j *SyncAllStudentsJob) lockStudent(ctx context.Context, studentID string) (unlock func(), ok bool) {
+	noop := func() {}
+	if j.syncLock == nil {
+		return noop, true
+	}
+	locked, err := j.syncLock.TryLock(ctx, studentID)
+	if err != nil {
+		j.logger.Warn("failed to lock student for sync",
+			"student_id", studentID,
+			"error", err,
+		)
+		return noop, true
+	}
+	if !locked {
+		return noop, false
+	}
+	return func() { _ = j.syncLock.Unlock(context.WithoutCancel(ctx), studentID) }, true
+}
+
 // syncStudent synchronizes a single student with Alem data.
+// liveEvents are the open events the gains are scored against.
 func (j *SyncAllStudentsJob) syncStudent(
 	ctx context.Context,
 	s *student.Student,
 	alemData *alem.StudentDTO,
+	liveEvents []*event.Event,
 ) (updated bool, xpDelta int, err error) {
+	identityChanged, err := j.reconcileIdentity(ctx, s, alemData)
+	if err != nil {
+		return false, 0, err
+	}
+	updated = identityChanged
+
 	oldXP := int(s.CurrentXP)
 	newXP := student.XP(alemData.XP)
+	syncedAt := time.Now()
 
 	// Check if XP changed
 	if newXP != s.CurrentXP {
@@ -524,33 +755,47 @@ func (j *SyncAllStudentsJob) syncStudent(
 	}
 
 	// Update online state
-	if alemData.IsOnline {
-		s.MarkOnline()
-	} else {
-		s.MarkOffline()
-	}
+	cameOnline := s.ApplyOnlineStatus(alemData.IsOnline)
 
 	// Update sync timestamp
-	s.SyncedWith(time.Now())
+	s.SyncedWith(syncedAt)
 
 	// Persist changes
 	if err := j.studentRepo.Update(ctx, s); err != nil {
 		return false, 0, fmt.Errorf("failed to save student: %w", err)
 	}
+	if updated && j.invalidations != nil {
+		j.invalidations.StudentUpdated(ctx, s.ID, int64(s.TelegramID))
+	}
 
-	// Mark as synced
-	if err := j.syncRepo.MarkSynced(ctx, s.ID, time.Now()); err != nil {
-		j.logger.Warn("failed to mark student as synced",
-			"student_id", s.ID,
-			"error", err,
-		)
+	// Only the offline → online transition is an event; staying online is not
+	if cameOnline {
+		if err := j.eventPublisher.Publish(shared.NewStudentWentOnlineEvent(s.ID, "")); err != nil {
+			j.logger.Warn("failed to publish StudentWentOnline event",
+				"student_id", s.ID,
+				"error", err,
+			)
+		}
+
+		// Sessions without XP are what plateau detection looks for
+		if err := j.progressRepo.RecordDailySession(ctx, s.ID, syncedAt); err != nil {
+			j.logger.Warn("failed to record daily session",
+				"student_id", s.ID,
+				"error", err,
+			)
+		}
 	}
 
+	// Mark as synced
+	j.markSynced(ctx, s.ID)
+
 	// ─────────────────────────────────────────────────────────────────────────
 	// Sync Bootcamp Progression
 	// ─────────────────────────────────────────────────────────────────────────
+	var completions []student.CompletionStamp
 	if j.config.BootcampID != "" {
-		if err := j.syncBootcampProgress(ctx, s); err != nil {
+		completions, err = j.collectNewCompletions(ctx, s)
+		if err != nil {
 			j.logger.Error("failed to sync bootcamp progress",
 				"student_id", s.ID,
 				"error", err,
@@ -559,19 +804,26 @@ func (j *SyncAllStudentsJob) syncStudent(
 		}
 	}
 
+	days := j.applyDailyXP(ctx, s, student.XP(xpDelta), completions, syncedAt)
+	j.updateStreak(ctx, s, days, syncedAt)
+	j.flagXPSpikes(ctx, s, student.XP(oldXP), days)
+	j.scoreEvents(ctx, liveEvents, s.ID, student.XP(xpDelta), completions, syncedAt)
+
 	return updated, xpDelta, nil
 }
 
-// syncBootcampProgress fetches and processes bootcamp data.
-func (j *SyncAllStudentsJob) syncBootcampProgress(ctx context.Context, s *student.Student) error {
+// collectNewCompletions fetches the student's task completions, persists the
+// ones not seen before and returns them as completion stamps. Completions that
+// were already stored are skipped, so the same data seen twice yields nothing.
+func (j *SyncAllStudentsJob) collectNewCompletions(ctx context.Context, s *student.Student) ([]student.CompletionStamp, error) {
 	// Use GetStudentTaskCompletions to fetch the specific student's completions.
 	// This ensures we get their individual progress, not the progress of the service account.
 	completions, err := j.alemClient.GetStudentTaskCompletions(ctx, s.ID)
 	if err != nil {
-		return fmt.Errorf("get student task completions: %w", err)
+		return nil, fmt.Errorf("get student task completions: %w", err)
 	}
 
-	newCompletions := 0
+	stamps := make([]student.CompletionStamp, 0)
 	for _, dto := range completions {
 		// Only save successful completions OR if we want to track everything
 		if !dto.IsSuccessful() && dto.XPEarned == 0 {
@@ -590,18 +842,241 @@ func (j *SyncAllStudentsJob) syncBootcampProgress(ctx context.Context, s *studen
 			if err := j.activityRepo.SaveTaskCompletion(ctx, tc); err != nil {
 				j.logger.Warn("failed to save task completion", "task_id", tc.TaskID, "error", err)
 			} else {
-				newCompletions++
+				stamp := student.CompletionStamp{XP: student.XP(dto.XPEarned)}
+				if dto.CompletedAt != nil {
+					stamp.CompletedAt = *dto.CompletedAt
+				}
+				stamps = append(stamps, stamp)
 			}
 		}
 	}
 
-	if newCompletions > 0 {
-		j.logger.Info("synced bootcamp completions", "student_id", s.ID, "count", newCompletions)
+	if len(stamps) > 0 {
+		j.logger.Info("synced bootcamp completions", "student_id", s.ID, "count", len(stamps))
 	}
 
+	return stamps, nil
+}
+
+// applyDailyXP splits an XP delta across DailyGrind dates using completion
+// timestamps, falling back to the sync time when they are unavailable.
+// Dates are days in the student's timezone.
+// Returns the dates that gained XP, oldest first.
+func (j *SyncAllStudentsJob) applyDailyXP(
+	ctx context.Context,
+	s *student.Student,
+	delta student.XP,
+	completions []student.CompletionStamp,
+	syncedAt time.Time,
+) []time.Time {
+	var gained []time.Time
+	for _, d := range student.AttributeXPDelta(delta, completions, syncedAt, s.Preferences.Location()) {
+		if err := j.progressRepo.UpsertDailyGrindDelta(ctx, s.ID, d.Date, d.XPDelta, d.TasksDelta); err != nil {
+			j.logger.Warn("failed to update daily grind",
+				"student_id", s.ID,
+				"date", d.Date.Format("2006-01-02"),
+				"error", err,
+			)
+			continue
+		}
+		if d.XPDelta > 0 {
+			gained = append(gained, d.Date)
+		}
+	}
+	return gained
+}
+
+// updateStreak extends the student's streak with the days that gained XP
+// and publishes the milestone the streak crossed. A milestone reached on
+// an older day (the first sync backfilling history, XP synced days late) is
+// marked as celebrated without a message.
+func (j *SyncAllStudentsJob) updateStreak(ctx context.Context, s *student.Student, days []time.Time, syncedAt time.Time) {
+	if len(days) == 0 {
+		return
+	}
+
+	streak, err := j.progressRepo.GetStreak(ctx, s.ID)
+	if err != nil {
+		j.logger.Warn("failed to load streak", "student_id", s.ID, "error", err)
+		return
+	}
+
+	// Days are already dates in the student's timezone
+	previous := streak.CurrentStreak
+	for _, day := range days {
+		streak.RecordActivity(day)
+	}
+	milestone, reached := streak.ReachMilestone(previous)
+
+	if err := j.progressRepo.SaveStreak(ctx, streak); err != nil {
+		j.logger.Warn("failed to save streak", "student_id", s.ID, "error", err)
+		return
+	}
+	if !reached || j.eventPublisher == nil {
+		return
+	}
+
+	yesterday := student.LocalDate(syncedAt, s.Preferences.Location()).AddDate(0, 0, -1)
+	if streak.LastActiveDate.Before(yesterday) {
+		j.logger.Info("streak milestone reached on an old day, not announced",
+			"student_id", s.ID,
+			"milestone", milestone,
+		)
+		return
+	}
+
+	if err := j.eventPublisher.Publish(shared.NewStreakMilestoneReachedEvent(s.ID, milestone, streak.CurrentStreak)); err != nil {
+		j.logger.Warn("failed to publish StreakMilestoneReached event",
+			"student_id", s.ID,
+			"error", err,
+		)
+	}
+}
+
+// flagXPSpikes checks the days that just gained XP against the student's
+// usual pace and queues spikes for curator review. The first sync of a
+// student backfills their history and is exempt; days older than the
+// window only show up through late completions and are not checked.
+// XP is never removed here: a curator decides.
+func (j *SyncAllStudentsJob) flagXPSpikes(ctx context.Context, s *student.Student, oldXP student.XP, days []time.Time) {
+	if j.xpFlags == nil || len(days) == 0 {
+		return
+	}
+
+	criteria := j.config.XPSpike
+	now := time.Now()
+	cutoff := now.UTC().AddDate(0, 0, -criteria.WindowDays)
+
+	// The oldest checked day needs its own window of history before it
+	history, err := j.progressRepo.GetDailyGrindHistory(ctx, s.ID, criteria.HistoryDays()+criteria.WindowDays)
+	if err != nil {
+		j.logger.Warn("failed to load daily history for xp spike check", "student_id", s.ID, "error", err)
+		return
+	}
+
+	for _, day := range days {
+		if day.Before(cutoff) || student.IsXPImport(oldXP, history, day) {
+			continue
+		}
+
+		spike, ok := student.DetectXPSpike(history, day, criteria)
+		if !ok {
+			continue
+		}
+
+		flag := student.NewXPFlag(s.ID, *spike, now)
+		created, err := j.xpFlags.Flag(ctx, flag)
+		if err != nil {
+			j.logger.Warn("failed to flag xp spike", "student_id", s.ID, "date", day.Format("2006-01-02"), "error", err)
+			continue
+		}
+		if !created {
+			continue
+		}
+
+		j.logger.Info("xp spike flagged for review",
+			"student_id", s.ID,
+			"date", day.Format("2006-01-02"),
+			"amount", int(spike.Amount),
+			"z_score", spike.ZScore,
+		)
+		if err := j.notifyXPReview(ctx, s, flag, spike); err != nil {
+			j.logger.Warn("failed to notify curators about xp spike", "flag_id", flag.ID, "error", err)
+		}
+	}
+}
+
+// notifyXPReview posts a flagged day to the curator chat with
+// approve/dismiss buttons.
+func (j *SyncAllStudentsJob) notifyXPReview(ctx context.Context, s *student.Student, flag *student.XPFlag, spike *student.XPSpike) error {
+	if j.xpReviewer == nil || j.config.XPReviewChatID == 0 {
+		return nil
+	}
+
+	n := &notification.Notification{
+		ID:             notification.NotificationID(uuid.New().String()),
+		RecipientID:    notification.RecipientID(fmt.Sprintf("chat:%d", j.config.XPReviewChatID)),
+		TelegramChatID: notification.TelegramChatID(j.config.XPReviewChatID),
+		Type:           notification.NotificationTypeSystemAlert,
+		Priority:       notification.NotificationTypeSystemAlert.DefaultPriority(),
+		Status:         notification.StatusPending,
+		Message: fmt.Sprintf("🚩 <b>Подозрительный прирост XP</b>\n\n"+
+			"Студент: <b>%s</b> (%s)\n"+
+			"День: %s\n"+
+			"XP за день: <b>%d</b> (обычно %.0f ± %.0f, z = %.1f)\n\n"+
+			"XP не снят, в /top студент отмечен ⏳ до решения.",
+			html.EscapeString(s.DisplayName), html.EscapeString(string(s.Cohort)),
+			flag.Date.Format("02.01.2006"),
+			int(spike.Amount), spike.Mean, spike.StdDev, spike.ZScore),
+		CreatedAt: flag.CreatedAt,
+	}
+	n.SetMetadata("xp_flag_id", fmt.Sprintf("%d", flag.ID))
+
+	if result := j.xpReviewer.SendWithKeyboard(ctx, n, xpReviewKeyboard(flag.ID)); !result.Success {
+		return fmt.Errorf("deliver xp review: %w", result.Error)
+	}
 	return nil
 }
 
+// xpReviewKeyboard returns the curator decision buttons for a flag.
+func xpReviewKeyboard(flagID int64) [][]notification.InlineButton {
+	return [][]notification.InlineButton{{
+		notification.NewCallbackButton("✅ Честно", fmt.Sprintf("xpflag:approve:%d", flagID)),
+		notification.NewCallbackButton("🚫 Накрутка", fmt.Sprintf("xpflag:dismiss:%d", flagID)),
+	}}
+}
+
+// openEvents loads the open events once per run, so scoring a student's
+// gains costs no query. An event created during a run is scored from the
+// next one. Returns nil when events are not configured or can't be loaded.
+func (j *SyncAllStudentsJob) openEvents(ctx context.Context) []*event.Event {
+	if j.events == nil || j.scoreboard == nil {
+		return nil
+	}
+	open, err := j.events.ListOpen(ctx)
+	if err != nil {
+		j.logger.Warn("failed to list open events, gains are not scored this run", "error", err)
+		return nil
+	}
+	return open
+}
+
+// scoreEvents adds the gains that fall into the window of each live event
+// to its scoreboard. Gains are attributed to task completion times, so XP
+// earned before an event starts does not count even if synced later.
+func (j *SyncAllStudentsJob) scoreEvents(
+	ctx context.Context,
+	open []*event.Event,
+	studentID string,
+	delta student.XP,
+	completions []student.CompletionStamp,
+	syncedAt time.Time,
+) {
+	if len(open) == 0 || j.scoreboard == nil || (delta <= 0 && len(completions) == 0) {
+		return
+	}
+
+	xpGains := student.AttributeXPGains(delta, completions, syncedAt)
+	gains := make([]event.Gain, len(xpGains))
+	for i, g := range xpGains {
+		gains[i] = event.Gain{At: g.At, XP: int(g.XP), Tasks: g.Tasks}
+	}
+
+	for _, e := range open {
+		score := e.Score(gains)
+		if score <= 0 {
+			continue
+		}
+		if err := j.scoreboard.AddScore(ctx, e.ID, studentID, score); err != nil {
+			j.logger.Warn("failed to add event score",
+				"event_id", e.ID,
+				"student_id", studentID,
+				"error", err,
+			)
+		}
+	}
+}
+
 // emitSyncCompletedEvent publishes a sync completed event.
 func (j *SyncAllStudentsJob) emitSyncCompletedEvent(stats *SyncStats) {
 	// Create a generic event for sync completion
@@ -624,6 +1099,14 @@ func (j *SyncAllStudentsJob) emitSyncCompletedEvent(stats *SyncStats) {
 	_ = event // Use the event when proper infrastructure is in place
 }
 
+// cacheStats returns the Alem client's cache counters, if it caches.
+func (j *SyncAllStudentsJob) cacheStats() alem.CacheStats {
+	if p, ok := j.alemClient.(CacheStatsProvider); ok {
+		return p.CacheStats()
+	}
+	return alem.CacheStats{}
+}
+
 // LastSyncStats returns statistics from the last sync run.
 func (j *SyncAllStudentsJob) LastSyncStats() *SyncStats {
 	stats := j.lastSyncStats.Load()
@@ -633,6 +1116,133 @@ func (j *SyncAllStudentsJob) LastSyncStats() *SyncStats {
 	return stats.(*SyncStats)
 }
 
+// reconcileIdentity checks the Alem record against local students by login
+// first and email second, before s is synced with it. A login found under a
+// new email moves the email and records an audit entry; a legacy row gets
+// its login backfilled. When login and email point at different students
+// the pair is flagged for /merge and student.ErrIdentityConflict returned.
+// Reports whether s changed.
+func (j *SyncAllStudentsJob) reconcileIdentity(
+	ctx context.Context,
+	s *student.Student,
+	alemData *alem.StudentDTO,
+) (bool, error) {
+	login := student.NormalizeAlemLogin(alemData.Login)
+	if login == "" {
+		return false, nil
+	}
+	email := strings.TrimSpace(alemData.Email)
+	if email == "" {
+		// Alem does not always report the email: ours stands
+		email = s.Email
+	}
+
+	byLogin := s
+	if s.AlemLogin != login {
+		found, err := j.findStudent(ctx, j.studentRepo.GetByAlemLogin, login)
+		if err != nil {
+			return false, err
+		}
+		byLogin = found
+		if byLogin == nil && s.AlemLogin == "" {
+			// A legacy row is fetched by the login derived from its email
+			byLogin = s
+		}
+	}
+
+	byEmail := s
+	if !strings.EqualFold(email, s.Email) {
+		found, err := j.findStudent(ctx, j.studentRepo.GetByEmail, email)
+		if err != nil {
+			return false, err
+		}
+		byEmail = found
+	}
+
+	match := student.MatchIdentity(byLogin, byEmail)
+	switch {
+	case match.Kind == student.IdentityConflictFound:
+		j.flagIdentityConflict(ctx, student.NewIdentityConflict(match, login, email))
+		return false, student.ErrIdentityConflict
+	case match.Student == nil:
+		return false, fmt.Errorf("alem returned login %s for a student stored as %s", login, s.AlemLogin)
+	case match.Student.ID != s.ID:
+		// The record belongs to another student: s looks like its duplicate
+		pair := student.IdentityMatch{ByLogin: match.Student, ByEmail: s}
+		if match.Kind == student.IdentityByEmail {
+			pair = student.IdentityMatch{ByLogin: s, ByEmail: match.Student}
+		}
+		j.flagIdentityConflict(ctx, student.NewIdentityConflict(pair, login, email))
+		return false, student.ErrIdentityConflict
+	}
+
+	changed := false
+	if s.AlemLogin == "" {
+		s.AlemLogin = login
+		changed = true
+	}
+	if email != s.Email {
+		oldEmail := s.Email
+		s.Email = email
+		changed = true
+
+		j.logger.Info("student email changed on Alem",
+			"student_id", s.ID,
+			"alem_login", login,
+		)
+		if j.auditLog != nil {
+			entry := shared.NewAuditEntry("sync", shared.AuditActionIdentityChanged, s.ID, map[string]interface{}{
+				"alem_login": login,
+				"old_email":  oldEmail,
+				"new_email":  email,
+			})
+			if err := j.auditLog.Record(ctx, entry); err != nil {
+				j.logger.Warn("failed to record identity change",
+					"student_id", s.ID,
+					"error", err,
+				)
+			}
+		}
+	}
+
+	return changed, nil
+}
+
+// findStudent runs a repository lookup, turning ErrStudentNotFound into nil.
+func (j *SyncAllStudentsJob) findStudent(
+	ctx context.Context,
+	get func(context.Context, string) (*student.Student, error),
+	key string,
+) (*student.Student, error) {
+	s, err := get(ctx, key)
+	if errors.Is(err, student.ErrStudentNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to match student identity: %w", err)
+	}
+	return s, nil
+}
+
+// flagIdentityConflict hands a login/email pair over to /merge.
+func (j *SyncAllStudentsJob) flagIdentityConflict(ctx context.Context, conflict student.IdentityConflict) {
+	j.logger.Warn("alem login and email belong to different students",
+		"login_student_id", conflict.LoginStudentID,
+		"email_student_id", conflict.EmailStudentID,
+		"alem_login", conflict.AlemLogin,
+	)
+	if j.identityConflicts == nil {
+		return
+	}
+	if err := j.identityConflicts.Flag(ctx, conflict); err != nil {
+		j.logger.Warn("failed to flag identity conflict",
+			"login_student_id", conflict.LoginStudentID,
+			"email_student_id", conflict.EmailStudentID,
+			"error", err,
+		)
+	}
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // SYNC SINGLE STUDENT (for on-demand sync)
 // ══════════════════════════════════════════════════════════════════════════════
@@ -645,17 +1255,13 @@ func (j *SyncAllStudentsJob) SyncSingleStudent(ctx context.Context, studentID st
 		return fmt.Errorf("student not found: %w", err)
 	}
 
-	// Fetch fresh data from Alem
-	login := ""
-	if idx := strings.Index(s.Email, "@"); idx > 0 {
-		login = s.Email[:idx]
-	}
-
-	alemData, err := j.alemClient.GetStudentByLogin(ctx, login)
+	// Fetch fresh data from Alem, bypassing the response cache
+	ctx = alem.WithForceFresh(ctx)
+	alemData, err := j.alemClient.GetStudentByLogin(ctx, s.SyncLogin())
 	if err != nil {
 		return fmt.Errorf("failed to fetch from Alem API: %w", err)
 	}
 
-	_, _, err = j.syncStudent(ctx, s, alemData)
+	_, _, err = j.syncStudent(ctx, s, alemData, j.openEvents(ctx))
 	return err
 }
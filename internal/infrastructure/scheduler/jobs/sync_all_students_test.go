@@ -0,0 +1,443 @@
+package jobs
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/activity"
+	"github.com/alem-hub/alem-community-hub/internal/domain/event"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/alem"
+)
+
+type fakeGrindRepo struct {
+	student.ProgressRepository
+	xp    map[string]student.XP
+	tasks map[string]int
+}
+
+func (f *fakeGrindRepo) UpsertDailyGrindDelta(_ context.Context, _ string, date time.Time, xpDelta student.XP, tasksDelta int) error {
+	key := date.Format("2006-01-02")
+	f.xp[key] += xpDelta
+	f.tasks[key] += tasksDelta
+	return nil
+}
+
+type fakeCompletionRepo struct {
+	activity.Repository
+	saved map[activity.TaskID]bool
+}
+
+func (f *fakeCompletionRepo) HasStudentCompletedTask(_ context.Context, _ activity.StudentID, taskID activity.TaskID) (bool, error) {
+	return f.saved[taskID], nil
+}
+
+func (f *fakeCompletionRepo) SaveTaskCompletion(_ context.Context, tc *activity.TaskCompletion) error {
+	f.saved[tc.TaskID] = true
+	return nil
+}
+
+type fakeCompletionClient struct {
+	AlemClient
+	completions []alem.TaskCompletionDTO
+}
+
+func (f *fakeCompletionClient) GetStudentTaskCompletions(context.Context, string) ([]alem.TaskCompletionDTO, error) {
+	return f.completions, nil
+}
+
+func TestSyncAllStudentsJob_DailyXPAttribution(t *testing.T) {
+	beforeMidnight := time.Date(2025, 6, 16, 23, 58, 0, 0, time.UTC)
+	afterMidnight := time.Date(2025, 6, 17, 0, 1, 0, 0, time.UTC)
+	syncedAt := time.Date(2025, 6, 17, 0, 2, 0, 0, time.UTC)
+
+	const studentID = "5f0c2f4e-3a5b-4d8e-9a51-8d2f0c6b7e11"
+	client := &fakeCompletionClient{completions: []alem.TaskCompletionDTO{
+		{ID: "c1", StudentID: studentID, TaskID: "go-reloaded", Status: "passed", XPEarned: 300, CompletedAt: &beforeMidnight},
+		{ID: "c2", StudentID: studentID, TaskID: "ascii-art", Status: "passed", XPEarned: 200, CompletedAt: &afterMidnight},
+	}}
+	progress := &fakeGrindRepo{xp: map[string]student.XP{}, tasks: map[string]int{}}
+	activityRepo := &fakeCompletionRepo{saved: map[activity.TaskID]bool{}}
+
+	job := NewSyncAllStudentsJob(nil, progress, activityRepo, nil, client, nil, nil, DefaultSyncAllStudentsConfig())
+	s := &student.Student{ID: studentID, Preferences: student.NotificationPreferences{Timezone: "UTC"}}
+
+	sync := func(delta student.XP) {
+		completions, err := job.collectNewCompletions(context.Background(), s)
+		require.NoError(t, err)
+		job.applyDailyXP(context.Background(), s, delta, completions, syncedAt)
+	}
+
+	t.Run("delta spanning midnight", func(t *testing.T) {
+		sync(550)
+
+		assert.Equal(t, student.XP(300), progress.xp["2025-06-16"])
+		assert.Equal(t, 1, progress.tasks["2025-06-16"])
+		assert.Equal(t, student.XP(250), progress.xp["2025-06-17"], "unattributed remainder goes to sync date")
+		assert.Equal(t, 1, progress.tasks["2025-06-17"])
+	})
+
+	t.Run("same completions seen twice", func(t *testing.T) {
+		sync(0)
+
+		assert.Equal(t, student.XP(300), progress.xp["2025-06-16"])
+		assert.Equal(t, 1, progress.tasks["2025-06-16"])
+		assert.Equal(t, student.XP(250), progress.xp["2025-06-17"])
+		assert.Equal(t, 1, progress.tasks["2025-06-17"])
+	})
+}
+
+func TestAttributeXPDelta_CapsOlderCompletions(t *testing.T) {
+	older := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	newer := time.Date(2025, 6, 16, 9, 0, 0, 0, time.UTC)
+	syncedAt := time.Date(2025, 6, 16, 10, 0, 0, 0, time.UTC)
+
+	got := student.AttributeXPDelta(250, []student.CompletionStamp{
+		{CompletedAt: older, XP: 200},
+		{CompletedAt: newer, XP: 200},
+	}, syncedAt, time.UTC)
+
+	require.Len(t, got, 2)
+	assert.Equal(t, student.DailyXPDelta{Date: time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC), XPDelta: 50, TasksDelta: 1}, got[0])
+	assert.Equal(t, student.DailyXPDelta{Date: time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC), XPDelta: 200, TasksDelta: 1}, got[1])
+}
+
+type fakeIdentityRepo struct {
+	student.Repository
+	students []*student.Student
+}
+
+func (f *fakeIdentityRepo) GetByAlemLogin(_ context.Context, login string) (*student.Student, error) {
+	for _, s := range f.students {
+		if s.AlemLogin != "" && s.AlemLogin == login {
+			return s, nil
+		}
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+func (f *fakeIdentityRepo) GetByEmail(_ context.Context, email string) (*student.Student, error) {
+	for _, s := range f.students {
+		if s.Email == email {
+			return s, nil
+		}
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+type fakeConflictRepo struct {
+	flagged []student.IdentityConflict
+}
+
+func (f *fakeConflictRepo) Flag(_ context.Context, c student.IdentityConflict) error {
+	f.flagged = append(f.flagged, c)
+	return nil
+}
+
+func (f *fakeConflictRepo) ListOpen(context.Context, int) ([]student.IdentityConflict, error) {
+	return f.flagged, nil
+}
+
+type fakeAuditLog struct {
+	entries []shared.AuditEntry
+}
+
+func (f *fakeAuditLog) Record(_ context.Context, entry shared.AuditEntry) error {
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func TestSyncAllStudentsJob_ReconcileIdentity(t *testing.T) {
+	setup := func(students ...*student.Student) (*SyncAllStudentsJob, *fakeConflictRepo, *fakeAuditLog) {
+		conflicts := &fakeConflictRepo{}
+		audit := &fakeAuditLog{}
+		job := NewSyncAllStudentsJob(&fakeIdentityRepo{students: students}, nil, nil, nil, nil, nil, nil, DefaultSyncAllStudentsConfig())
+		job.WithIdentity(conflicts, audit)
+		return job, conflicts, audit
+	}
+
+	t.Run("login matches, email changed", func(t *testing.T) {
+		s := &student.Student{ID: "a", AlemLogin: "akim", Email: "akim@alem.school", Status: student.StatusActive}
+		job, conflicts, audit := setup(s)
+
+		changed, err := job.reconcileIdentity(context.Background(), s, &alem.StudentDTO{Login: "Akim", Email: "akim@astanahub.com"})
+		require.NoError(t, err)
+
+		assert.True(t, changed)
+		assert.Equal(t, "akim@astanahub.com", s.Email)
+		assert.Empty(t, conflicts.flagged)
+		require.Len(t, audit.entries, 1)
+		assert.Equal(t, shared.AuditActionIdentityChanged, audit.entries[0].Action)
+		assert.Equal(t, "a", audit.entries[0].TargetID)
+		assert.Equal(t, "akim@alem.school", audit.entries[0].Details["old_email"])
+	})
+
+	t.Run("legacy row matched by email gets its login", func(t *testing.T) {
+		s := &student.Student{ID: "a", Email: "akim@alem.school", Status: student.StatusActive}
+		job, conflicts, audit := setup(s)
+
+		changed, err := job.reconcileIdentity(context.Background(), s, &alem.StudentDTO{Login: "akim", Email: "akim@alem.school"})
+		require.NoError(t, err)
+
+		assert.True(t, changed)
+		assert.Equal(t, "akim", s.AlemLogin)
+		assert.Equal(t, "akim@alem.school", s.Email)
+		assert.Empty(t, conflicts.flagged)
+		assert.Empty(t, audit.entries)
+	})
+
+	t.Run("login and email on different rows", func(t *testing.T) {
+		byLogin := &student.Student{ID: "a", AlemLogin: "akim", Email: "akim@alem.school", Status: student.StatusActive}
+		byEmail := &student.Student{ID: "b", Email: "akim@astanahub.com", Status: student.StatusActive}
+		job, conflicts, audit := setup(byLogin, byEmail)
+
+		changed, err := job.reconcileIdentity(context.Background(), byLogin, &alem.StudentDTO{Login: "akim", Email: "akim@astanahub.com"})
+		assert.ErrorIs(t, err, student.ErrIdentityConflict)
+
+		assert.False(t, changed)
+		assert.Equal(t, "akim@alem.school", byLogin.Email, "nothing is guessed")
+		assert.Empty(t, byEmail.AlemLogin)
+		require.Len(t, conflicts.flagged, 1)
+		assert.Equal(t, "a", conflicts.flagged[0].LoginStudentID)
+		assert.Equal(t, "b", conflicts.flagged[0].EmailStudentID)
+		assert.Empty(t, audit.entries)
+	})
+}
+
+// fakeSyncStore keeps students and sync runs in memory the way the postgres
+// repositories do: Update fails once the context is done.
+type fakeSyncStore struct {
+	student.Repository
+	student.SyncRepository
+
+	students map[string]*student.Student
+	runs     []student.SyncRun
+	errors   []student.SyncError
+}
+
+func (f *fakeSyncStore) Update(ctx context.Context, s *student.Student) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	stored := *s
+	f.students[s.ID] = &stored
+	return nil
+}
+
+func (f *fakeSyncStore) GetStudentsToSync(_ context.Context, olderThan time.Duration) ([]*student.Student, error) {
+	threshold := time.Now().Add(-olderThan)
+	var due []*student.Student
+	for _, s := range f.students {
+		if s.LastSyncedAt.Before(threshold) {
+			stored := *s
+			due = append(due, &stored)
+		}
+	}
+	sort.Slice(due, func(i, k int) bool { return due[i].LastSyncedAt.Before(due[k].LastSyncedAt) })
+	return due, nil
+}
+
+func (f *fakeSyncStore) MarkSynced(_ context.Context, studentID string, syncTime time.Time) error {
+	f.students[studentID].LastSyncedAt = syncTime
+	return nil
+}
+
+func (f *fakeSyncStore) SaveSyncError(_ context.Context, err student.SyncError) error {
+	f.errors = append(f.errors, err)
+	return nil
+}
+
+func (f *fakeSyncStore) StartSyncRun(_ context.Context, startedAt time.Time) (int64, error) {
+	f.runs = append(f.runs, student.SyncRun{ID: int64(len(f.runs) + 1), StartedAt: startedAt, Status: student.SyncRunRunning})
+	return int64(len(f.runs)), nil
+}
+
+func (f *fakeSyncStore) FinishSyncRun(_ context.Context, run student.SyncRun) error {
+	f.runs[run.ID-1] = run
+	return nil
+}
+
+// fakeBootcampClient returns the bootcamp and calls onFetch before each response.
+type fakeBootcampClient struct {
+	AlemClient
+	calls   int
+	onFetch func(n int)
+}
+
+func (f *fakeBootcampClient) GetBootcamp(context.Context, string, string) (*alem.BootcampDTO, error) {
+	f.calls++
+	if f.onFetch != nil {
+		f.onFetch(f.calls)
+	}
+	return &alem.BootcampDTO{UserXP: 100}, nil
+}
+
+func TestSyncAllStudentsJob_ResumesAfterFailedRun(t *testing.T) {
+	stale := time.Now().Add(-3 * time.Hour)
+	store := &fakeSyncStore{students: map[string]*student.Student{}}
+	for i, id := range []string{"s1", "s2", "s3", "s4"} {
+		store.students[id] = &student.Student{ID: id, CurrentXP: 100, LastSyncedAt: stale.Add(time.Duration(i) * time.Minute)}
+	}
+
+	config := DefaultSyncAllStudentsConfig()
+	config.Concurrency = 1
+	config.MinSyncInterval = time.Hour
+	client := &fakeBootcampClient{}
+	job := NewSyncAllStudentsJob(store, nil, nil, store, client, nil, nil, config)
+
+	// Первый прогон обрывается на втором студенте
+	ctx, cancel := context.WithCancel(context.Background())
+	client.onFetch = func(n int) {
+		if n == 2 {
+			cancel()
+		}
+	}
+	require.Error(t, job.Run(ctx))
+
+	require.Len(t, store.runs, 1)
+	assert.Equal(t, student.SyncRunFailed, store.runs[0].Status)
+	assert.Equal(t, 4, store.runs[0].Total)
+	assert.Equal(t, 1, store.runs[0].Synced)
+	require.NotEmpty(t, store.errors)
+	assert.Equal(t, "s2", store.errors[0].StudentID)
+	assert.True(t, store.students["s1"].LastSyncedAt.After(stale.Add(time.Hour)))
+	assert.Equal(t, stale.Add(time.Minute), store.students["s2"].LastSyncedAt, "failed student stays stale")
+
+	// Следующий прогон берёт только оставшихся, самых старых первыми
+	client.onFetch = nil
+	require.NoError(t, job.Run(context.Background()))
+
+	require.Len(t, store.runs, 2)
+	assert.Equal(t, student.SyncRunCompleted, store.runs[1].Status)
+	assert.Equal(t, 3, store.runs[1].Total)
+	assert.Equal(t, 3, store.runs[1].Synced)
+	for _, id := range []string{"s1", "s2", "s3", "s4"} {
+		assert.True(t, store.students[id].LastSyncedAt.After(stale.Add(time.Hour)), id)
+	}
+}
+
+// fakeStreakProgress хранит одну серию; остальной прогресс не нужен.
+type fakeStreakProgress struct {
+	student.ProgressRepository
+	streak *student.Streak
+}
+
+func (f *fakeStreakProgress) SaveXPChange(context.Context, student.XPHistoryEntry) error { return nil }
+
+func (f *fakeStreakProgress) UpsertDailyGrindDelta(context.Context, string, time.Time, student.XP, int) error {
+	return nil
+}
+
+func (f *fakeStreakProgress) GetStreak(context.Context, string) (*student.Streak, error) {
+	stored := *f.streak
+	return &stored, nil
+}
+
+func (f *fakeStreakProgress) SaveStreak(_ context.Context, streak *student.Streak) error {
+	stored := *streak
+	f.streak = &stored
+	return nil
+}
+
+// fakeXPClient отдаёт XP студента из буткемпа и ни одной сданной задачи.
+type fakeXPClient struct {
+	AlemClient
+	xp int
+}
+
+func (f *fakeXPClient) GetBootcamp(context.Context, string, string) (*alem.BootcampDTO, error) {
+	return &alem.BootcampDTO{UserXP: f.xp}, nil
+}
+
+func (f *fakeXPClient) GetStudentTaskCompletions(context.Context, string) ([]alem.TaskCompletionDTO, error) {
+	return nil, nil
+}
+
+func TestSyncAllStudentsJob_StreakMilestone(t *testing.T) {
+	today := student.LocalDate(time.Now(), time.UTC)
+	progress := &fakeStreakProgress{streak: &student.Streak{
+		StudentID:       "dana",
+		CurrentStreak:   6,
+		BestStreak:      6,
+		LastActiveDate:  today.AddDate(0, 0, -1),
+		StreakStartDate: today.AddDate(0, 0, -6),
+	}}
+	store := &fakeSyncStore{students: map[string]*student.Student{}}
+	client := &fakeXPClient{xp: 1200}
+	publisher := &recordingPublisher{}
+	job := NewSyncAllStudentsJob(store, progress, nil, nil, client, publisher, nil, DefaultSyncAllStudentsConfig())
+
+	s := &student.Student{ID: "dana", CurrentXP: 1000, Preferences: student.NotificationPreferences{Timezone: "UTC"}}
+	_, _, err := job.syncStudentBootcamp(context.Background(), s, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 7, progress.streak.CurrentStreak)
+	assert.Equal(t, today, progress.streak.LastActiveDate)
+	require.Len(t, publisher.events, 1)
+	event, ok := publisher.events[0].(shared.StreakMilestoneReachedEvent)
+	require.True(t, ok)
+	assert.Equal(t, "dana", event.StudentID)
+	assert.Equal(t, 7, event.Milestone)
+
+	// Ещё XP в тот же день: веху повторно не празднуем
+	client.xp = 1500
+	_, _, err = job.syncStudentBootcamp(context.Background(), s, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 7, progress.streak.CurrentStreak)
+	assert.Len(t, publisher.events, 1)
+}
+
+func TestSyncAllStudentsJob_StreakMilestoneOnOldDayIsNotAnnounced(t *testing.T) {
+	today := student.LocalDate(time.Now(), time.UTC)
+	progress := &fakeStreakProgress{streak: student.NewStreak("dana")}
+	publisher := &recordingPublisher{}
+	job := NewSyncAllStudentsJob(nil, progress, nil, nil, nil, publisher, nil, DefaultSyncAllStudentsConfig())
+
+	// Первая синхронизация подтягивает неделю, закончившуюся три дня назад
+	var days []time.Time
+	for d := 9; d >= 3; d-- {
+		days = append(days, today.AddDate(0, 0, -d))
+	}
+	s := &student.Student{ID: "dana", Preferences: student.NotificationPreferences{Timezone: "UTC"}}
+	job.updateStreak(context.Background(), s, days, time.Now())
+
+	assert.Equal(t, 7, progress.streak.CurrentStreak)
+	assert.Equal(t, 7, progress.streak.MilestoneReached, "отмечена, чтобы не праздновать позже")
+	assert.Empty(t, publisher.events)
+}
+
+func TestSyncAllStudentsJob_ScoresEventsLoadedOncePerRun(t *testing.T) {
+	now := time.Now().UTC()
+	// Two overlapping live events and one that has not started yet
+	week := &event.Event{ID: "week", StartsAt: now.Add(-48 * time.Hour), EndsAt: now.Add(48 * time.Hour), Metric: event.MetricXPGained, Status: event.StatusOpen}
+	day := &event.Event{ID: "day", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), Metric: event.MetricXPGained, Status: event.StatusOpen}
+	next := &event.Event{ID: "next", StartsAt: now.Add(24 * time.Hour), EndsAt: now.Add(72 * time.Hour), Metric: event.MetricXPGained, Status: event.StatusOpen}
+	events := newMemoryEvents(week, day, next)
+	board := newMemoryScoreboard()
+
+	stale := now.Add(-3 * time.Hour)
+	store := &fakeSyncStore{students: map[string]*student.Student{}}
+	for _, id := range []string{"s1", "s2", "s3"} {
+		store.students[id] = &student.Student{ID: id, CurrentXP: 1000, LastSyncedAt: stale, Preferences: student.NotificationPreferences{Timezone: "UTC"}}
+	}
+
+	config := DefaultSyncAllStudentsConfig()
+	config.Concurrency = 1
+	config.MinSyncInterval = time.Hour
+	progress := &fakeStreakProgress{streak: student.NewStreak("s1")}
+	job := NewSyncAllStudentsJob(store, progress, nil, store, &fakeXPClient{xp: 1200}, &recordingPublisher{}, nil, config).
+		WithEvents(events, board)
+
+	require.NoError(t, job.Run(context.Background()))
+
+	assert.Equal(t, 1, events.listCalls, "open events are loaded once per run, not per student")
+	assert.Equal(t, map[string]int{"s1": 200, "s2": 200, "s3": 200}, board.scores["week"])
+	assert.Equal(t, map[string]int{"s1": 200, "s2": 200, "s3": 200}, board.scores["day"])
+	assert.Empty(t, board.scores["next"])
+}
@@ -0,0 +1,354 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/saga"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// TRACK HELPER STREAKS JOB
+// ══════════════════════════════════════════════════════════════════════════════
+
+// TrackHelperStreaksJob computes helper streaks: consecutive ISO weeks
+// (Almaty time) in which a student resolved a help request as the helper or
+// received an endorsement. Each finished week is evaluated once; weeks missed
+// while the worker was down are caught up in order. Students reaching
+// student.ConsistentHelperWeeks and student.ConsistentHelperLongWeeks get
+// "Consistent Helper" through the achievement saga.
+//
+// Every run also refreshes the month's endorsement ranking behind the
+// "Топ помощников месяца" view of /top.
+//
+// With an event publisher, the student with the most endorsements in the
+// last evaluated week is announced as the helper of the week (the activity
+// feed shows it). The backfill of the first run announces nobody.
+type TrackHelperStreaksJob struct {
+	// Dependencies
+	streaks   student.HelperStreakRepository
+	activity  student.HelperActivityRepository
+	board     student.HelperBoard
+	granter   AchievementGranter
+	publisher shared.EventPublisher
+	logger    *slog.Logger
+
+	// Configuration
+	config TrackHelperStreaksConfig
+	now    func() time.Time
+
+	// State
+	lastRunStats atomic.Value // *TrackHelperStreaksStats
+}
+
+// TrackHelperStreaksConfig contains configuration for the helper streak job.
+type TrackHelperStreaksConfig struct {
+	// BackfillWeeks is how many finished weeks are evaluated at most in
+	// one run: on the first run it builds streaks from history, later it
+	// bounds the catch-up after downtime.
+	BackfillWeeks int
+
+	// Timeout is the maximum duration for the job.
+	Timeout time.Duration
+}
+
+// DefaultTrackHelperStreaksConfig returns sensible defaults.
+func DefaultTrackHelperStreaksConfig() TrackHelperStreaksConfig {
+	return TrackHelperStreaksConfig{
+		BackfillWeeks: student.ConsistentHelperLongWeeks,
+		Timeout:       10 * time.Minute,
+	}
+}
+
+// TrackHelperStreaksStats contains statistics from a run.
+type TrackHelperStreaksStats struct {
+	StartedAt         time.Time
+	CompletedAt       time.Time
+	Duration          time.Duration
+	WeeksEvaluated    []student.ISOWeek
+	StreaksUpdated    int
+	ActiveStreaks     int
+	BoardSize         int
+	AchievementsGiven int
+	HelperOfTheWeek   string
+	Errors            []error
+}
+
+// NewTrackHelperStreaksJob creates a new helper streak job.
+// A nil board disables the monthly ranking, a nil granter disables
+// the "Consistent Helper" achievements.
+func NewTrackHelperStreaksJob(
+	streaks student.HelperStreakRepository,
+	activity student.HelperActivityRepository,
+	board student.HelperBoard,
+	granter AchievementGranter,
+	logger *slog.Logger,
+	config TrackHelperStreaksConfig,
+) *TrackHelperStreaksJob {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if config.BackfillWeeks <= 0 {
+		config.BackfillWeeks = 1
+	}
+
+	return &TrackHelperStreaksJob{
+		streaks:  streaks,
+		activity: activity,
+		board:    board,
+		granter:  granter,
+		logger:   logger,
+		config:   config,
+		now:      time.Now,
+	}
+}
+
+// WithEventPublisher sets the publisher of the helper of the week.
+func (j *TrackHelperStreaksJob) WithEventPublisher(publisher shared.EventPublisher) *TrackHelperStreaksJob {
+	j.publisher = publisher
+	return j
+}
+
+// Name returns the job name.
+func (j *TrackHelperStreaksJob) Name() string {
+	return "track_helper_streaks"
+}
+
+// Description returns a human-readable description.
+func (j *TrackHelperStreaksJob) Description() string {
+	return "Computes weekly helper streaks, grants Consistent Helper and ranks the month's helpers"
+}
+
+// Run evaluates finished weeks that are not evaluated yet and refreshes
+// the month's helper ranking.
+func (j *TrackHelperStreaksJob) Run(ctx context.Context) error {
+	startedAt := j.now()
+	stats := &TrackHelperStreaksStats{StartedAt: startedAt}
+
+	// Apply timeout
+	if j.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.config.Timeout)
+		defer cancel()
+	}
+
+	evaluated, err := j.streaks.LastEvaluatedWeek(ctx)
+	if err != nil {
+		return err
+	}
+	weeks := j.weeksToEvaluate(evaluated, student.HelperWeekOf(startedAt).Previous())
+
+	if len(weeks) > 0 {
+		if err := j.evaluateWeeks(ctx, weeks, evaluated == "", stats); err != nil {
+			return err
+		}
+		if evaluated != "" && j.publisher != nil {
+			if err := j.announceHelperOfTheWeek(ctx, weeks[len(weeks)-1], stats); err != nil {
+				stats.Errors = append(stats.Errors, err)
+				j.logger.Warn("failed to announce helper of the week", "error", err)
+			}
+		}
+	}
+
+	if j.board != nil {
+		if err := j.refreshBoard(ctx, startedAt, stats); err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to refresh helper board", "error", err)
+		}
+	}
+
+	// Finalize stats
+	stats.CompletedAt = j.now()
+	stats.Duration = stats.CompletedAt.Sub(startedAt)
+	j.lastRunStats.Store(stats)
+
+	j.logger.Info("track_helper_streaks job completed",
+		"duration", stats.Duration.String(),
+		"weeks", len(stats.WeeksEvaluated),
+		"updated", stats.StreaksUpdated,
+		"active", stats.ActiveStreaks,
+		"board_size", stats.BoardSize,
+		"achievements", stats.AchievementsGiven,
+		"helper_of_the_week", stats.HelperOfTheWeek,
+		"errors", len(stats.Errors),
+	)
+
+	return nil
+}
+
+// weeksToEvaluate returns the finished weeks after evaluated up to last,
+// oldest first, at most BackfillWeeks of them.
+func (j *TrackHelperStreaksJob) weeksToEvaluate(evaluated, last student.ISOWeek) []student.ISOWeek {
+	var weeks []student.ISOWeek
+	for week := last; week != "" && week > evaluated && len(weeks) < j.config.BackfillWeeks; week = week.Previous() {
+		weeks = append(weeks, week)
+	}
+	slices.Reverse(weeks)
+	return weeks
+}
+
+// evaluateWeeks records the weeks in order into every active streak and
+// every streak of a student who helped, then saves the changed ones.
+// On the first run the history is backfilled silently.
+func (j *TrackHelperStreaksJob) evaluateWeeks(ctx context.Context, weeks []student.ISOWeek, backfill bool, stats *TrackHelperStreaksStats) error {
+	active, err := j.streaks.GetActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get active helper streaks: %w", err)
+	}
+	streaks := make(map[string]*student.HelperStreak, len(active))
+	for _, s := range active {
+		streaks[s.StudentID] = s
+	}
+
+	now := j.now()
+	changed := make(map[string]bool)
+	for _, week := range weeks {
+		start, end, err := student.HelperWeekBounds(week)
+		if err != nil {
+			return err
+		}
+		helping, err := j.activity.GetHelpingStudents(ctx, start, end)
+		if err != nil {
+			return fmt.Errorf("failed to get helping students: %w", err)
+		}
+
+		helped := make(map[string]bool, len(helping))
+		for _, id := range helping {
+			helped[id] = true
+			if streaks[id] == nil {
+				if streaks[id], err = j.loadStreak(ctx, id); err != nil {
+					return err
+				}
+			}
+		}
+
+		for id, s := range streaks {
+			if s.RecordWeek(week, helped[id], now) {
+				changed[id] = true
+			}
+		}
+		stats.WeeksEvaluated = append(stats.WeeksEvaluated, week)
+	}
+
+	for id := range changed {
+		if ctx.Err() != nil {
+			break
+		}
+		s := streaks[id]
+		if err := j.streaks.Save(ctx, s); err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to save helper streak", "student_id", id, "error", err)
+			continue
+		}
+		stats.StreaksUpdated++
+		if s.CurrentWeeks > 0 {
+			stats.ActiveStreaks++
+		}
+
+		if err := j.grant(ctx, s, backfill, stats); err != nil {
+			stats.Errors = append(stats.Errors, err)
+			j.logger.Warn("failed to grant consistent helper", "student_id", id, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// loadStreak returns the stored streak of a student, or a new one.
+func (j *TrackHelperStreaksJob) loadStreak(ctx context.Context, studentID string) (*student.HelperStreak, error) {
+	s, err := j.streaks.Get(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get helper streak: %w", err)
+	}
+	if s == nil {
+		s = student.NewHelperStreak(studentID)
+	}
+	return s, nil
+}
+
+// grant checks the "Consistent Helper" achievements for a streak that has
+// reached the first step.
+func (j *TrackHelperStreaksJob) grant(ctx context.Context, s *student.HelperStreak, silent bool, stats *TrackHelperStreaksStats) error {
+	if j.granter == nil || s.CurrentWeeks < student.ConsistentHelperWeeks {
+		return nil
+	}
+
+	result, err := j.granter.Execute(ctx, saga.AchievementCheckInput{
+		StudentID:    s.StudentID,
+		TriggerEvent: "helper_streak_extended",
+		Context: saga.AchievementContext{
+			HelperWeeksInARow: s.CurrentWeeks,
+			Timestamp:         j.now(),
+		},
+		OnlyTypes: []student.AchievementType{
+			student.AchievementConsistentHelper,
+			student.AchievementConsistentHelperLong,
+		},
+		Silent: silent,
+	})
+	if err != nil {
+		return err
+	}
+	stats.AchievementsGiven += len(result.NewAchievements)
+	return nil
+}
+
+// announceHelperOfTheWeek publishes the student with the most endorsements
+// in the week; a tie goes to the smallest student ID, so a rerun picks the
+// same one. A week without endorsements has no helper of the week.
+func (j *TrackHelperStreaksJob) announceHelperOfTheWeek(ctx context.Context, week student.ISOWeek, stats *TrackHelperStreaksStats) error {
+	start, end, err := student.HelperWeekBounds(week)
+	if err != nil {
+		return err
+	}
+	counts, err := j.activity.CountEndorsementsBetween(ctx, start, end)
+	if err != nil {
+		return fmt.Errorf("failed to count endorsements: %w", err)
+	}
+
+	var best string
+	for id, n := range counts {
+		if n > counts[best] || (n == counts[best] && n > 0 && id < best) {
+			best = id
+		}
+	}
+	if best == "" {
+		return nil
+	}
+
+	if err := j.publisher.Publish(shared.NewHelperOfTheWeekEvent(best, string(week), counts[best])); err != nil {
+		return err
+	}
+	stats.HelperOfTheWeek = best
+	return nil
+}
+
+// refreshBoard replaces the month's helper ranking with endorsement counts
+// from the start of the month (Almaty time) until now.
+func (j *TrackHelperStreaksJob) refreshBoard(ctx context.Context, now time.Time, stats *TrackHelperStreaksStats) error {
+	counts, err := j.activity.CountEndorsementsBetween(ctx, timeutil.StartOfMonth(now), now)
+	if err != nil {
+		return err
+	}
+	if err := j.board.ReplaceMonth(ctx, now, counts); err != nil {
+		return err
+	}
+	stats.BoardSize = len(counts)
+	return nil
+}
+
+// LastRunStats returns statistics from the last run.
+func (j *TrackHelperStreaksJob) LastRunStats() *TrackHelperStreaksStats {
+	stats := j.lastRunStats.Load()
+	if stats == nil {
+		return nil
+	}
+	return stats.(*TrackHelperStreaksStats)
+}
@@ -0,0 +1,239 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/saga"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
+)
+
+// helpEvent is a resolved help request or a received endorsement.
+type helpEvent struct {
+	studentID   string
+	at          time.Time
+	endorsement bool
+}
+
+type fakeHelperActivity struct {
+	events []helpEvent
+}
+
+func (f *fakeHelperActivity) GetHelpingStudents(_ context.Context, from, to time.Time) ([]string, error) {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, e := range f.events {
+		if !e.at.Before(from) && e.at.Before(to) && !seen[e.studentID] {
+			seen[e.studentID] = true
+			ids = append(ids, e.studentID)
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakeHelperActivity) CountEndorsementsBetween(_ context.Context, from, to time.Time) (map[string]int, error) {
+	counts := make(map[string]int)
+	for _, e := range f.events {
+		if e.endorsement && !e.at.Before(from) && e.at.Before(to) {
+			counts[e.studentID]++
+		}
+	}
+	return counts, nil
+}
+
+type memoryHelperStreaks struct {
+	streaks map[string]*student.HelperStreak
+}
+
+func (m *memoryHelperStreaks) Get(_ context.Context, studentID string) (*student.HelperStreak, error) {
+	if s, ok := m.streaks[studentID]; ok {
+		copied := *s
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *memoryHelperStreaks) GetActive(context.Context) ([]*student.HelperStreak, error) {
+	var active []*student.HelperStreak
+	for _, s := range m.streaks {
+		if s.CurrentWeeks > 0 {
+			copied := *s
+			active = append(active, &copied)
+		}
+	}
+	return active, nil
+}
+
+func (m *memoryHelperStreaks) Save(_ context.Context, streak *student.HelperStreak) error {
+	copied := *streak
+	m.streaks[streak.StudentID] = &copied
+	return nil
+}
+
+func (m *memoryHelperStreaks) LastEvaluatedWeek(context.Context) (student.ISOWeek, error) {
+	var last student.ISOWeek
+	for _, s := range m.streaks {
+		if s.EvaluatedWeek > last {
+			last = s.EvaluatedWeek
+		}
+	}
+	return last, nil
+}
+
+type fakeHelperBoard struct {
+	months map[string]map[string]int
+}
+
+func (f *fakeHelperBoard) ReplaceMonth(_ context.Context, month time.Time, counts map[string]int) error {
+	f.months[month.In(timeutil.AlmatyTZ).Format("2006-01")] = counts
+	return nil
+}
+
+func (f *fakeHelperBoard) TopOfMonth(context.Context, time.Time, int) ([]student.HelperBoardEntry, error) {
+	return nil, nil
+}
+
+type helperGranter struct {
+	inputs []saga.AchievementCheckInput
+}
+
+func (g *helperGranter) Execute(_ context.Context, input saga.AchievementCheckInput) (*saga.AchievementFlowResult, error) {
+	g.inputs = append(g.inputs, input)
+	unlocked := student.NewAchievementChecker().CheckConsistentHelper(input.Context.HelperWeeksInARow, nil)
+	return &saga.AchievementFlowResult{StudentID: input.StudentID, NewAchievements: unlocked}, nil
+}
+
+// almaty returns a moment in Almaty time.
+func almaty(month time.Month, day, hour int) time.Time {
+	return time.Date(2026, month, day, hour, 0, 0, 0, timeutil.AlmatyTZ)
+}
+
+func TestTrackHelperStreaksJob_MonthOfFixtures(t *testing.T) {
+	// Weeks of the fixture month (Almaty time):
+	// W40 Sep 28, W41 Oct 5, W42 Oct 12, W43 Oct 19, W44 Oct 26 - Nov 1
+	activity := &fakeHelperActivity{events: []helpEvent{
+		// dana helps every week
+		{studentID: "dana", at: almaty(time.September, 29, 15)},
+		{studentID: "dana", at: almaty(time.October, 7, 11), endorsement: true},
+		{studentID: "dana", at: almaty(time.October, 14, 18)},
+		{studentID: "dana", at: almaty(time.October, 21, 9), endorsement: true},
+		{studentID: "dana", at: almaty(time.October, 28, 20), endorsement: true},
+
+		// arman skips W42: the streak starts over
+		{studentID: "arman", at: almaty(time.September, 30, 12)},
+		{studentID: "arman", at: almaty(time.October, 8, 12), endorsement: true},
+		{studentID: "arman", at: almaty(time.October, 22, 12)},
+		{studentID: "arman", at: almaty(time.October, 29, 12), endorsement: true},
+
+		// aigerim stops in W44
+		{studentID: "aigerim", at: almaty(time.October, 1, 10)},
+		{studentID: "aigerim", at: almaty(time.October, 9, 10)},
+		{studentID: "aigerim", at: almaty(time.October, 16, 10)},
+		{studentID: "aigerim", at: almaty(time.October, 23, 10)},
+
+		// Sunday 20:00 UTC is already Monday in Almaty: W44, not W43
+		{studentID: "bolat", at: time.Date(2026, time.October, 25, 20, 0, 0, 0, time.UTC), endorsement: true},
+	}}
+	streaks := &memoryHelperStreaks{streaks: make(map[string]*student.HelperStreak)}
+	board := &fakeHelperBoard{months: make(map[string]map[string]int)}
+	granter := &helperGranter{}
+
+	job := NewTrackHelperStreaksJob(streaks, activity, board, granter, nil, DefaultTrackHelperStreaksConfig())
+	job.now = func() time.Time { return almaty(time.November, 2, 10) }
+
+	require.NoError(t, job.Run(context.Background()))
+
+	stats := job.LastRunStats()
+	require.NotNil(t, stats)
+	assert.Len(t, stats.WeeksEvaluated, student.ConsistentHelperLongWeeks, "the first run backfills history")
+	assert.Equal(t, student.ISOWeek("2026-W44"), stats.WeeksEvaluated[len(stats.WeeksEvaluated)-1])
+
+	expect := map[string]struct{ current, best int }{
+		"dana":    {5, 5},
+		"arman":   {2, 2},
+		"aigerim": {0, 4},
+		"bolat":   {1, 1},
+	}
+	for id, want := range expect {
+		s := streaks.streaks[id]
+		require.NotNil(t, s, id)
+		assert.Equal(t, want.current, s.CurrentWeeks, "%s current", id)
+		assert.Equal(t, want.best, s.BestWeeks, "%s best", id)
+	}
+
+	// Only dana is on a 4+ week streak; the backfill doesn't notify
+	require.Len(t, granter.inputs, 1)
+	assert.Equal(t, "dana", granter.inputs[0].StudentID)
+	assert.Equal(t, 5, granter.inputs[0].Context.HelperWeeksInARow)
+	assert.True(t, granter.inputs[0].Silent)
+	assert.Equal(t, 1, stats.AchievementsGiven)
+
+	// November has just begun: nobody is endorsed yet
+	assert.Empty(t, board.months["2026-11"])
+
+	// Next week: dana helps again, arman doesn't. The week is evaluated
+	// once however often the job runs.
+	activity.events = append(activity.events,
+		helpEvent{studentID: "dana", at: almaty(time.November, 4, 16), endorsement: true},
+		helpEvent{studentID: "dana", at: almaty(time.November, 5, 16), endorsement: true},
+	)
+	job.now = func() time.Time { return almaty(time.November, 9, 10) }
+	require.NoError(t, job.Run(context.Background()))
+	require.NoError(t, job.Run(context.Background()))
+
+	assert.Empty(t, job.LastRunStats().WeeksEvaluated, "the second run has nothing left to evaluate")
+	assert.Equal(t, 6, streaks.streaks["dana"].CurrentWeeks)
+	assert.Equal(t, 0, streaks.streaks["arman"].CurrentWeeks)
+	assert.Equal(t, 0, streaks.streaks["bolat"].CurrentWeeks)
+
+	require.Len(t, granter.inputs, 2)
+	assert.False(t, granter.inputs[1].Silent, "regular weeks notify")
+
+	assert.Equal(t, map[string]int{"dana": 2}, board.months["2026-11"])
+}
+
+func TestTrackHelperStreaksJob_AnnouncesHelperOfTheWeek(t *testing.T) {
+	activity := &fakeHelperActivity{events: []helpEvent{
+		{studentID: "dana", at: almaty(time.October, 28, 20), endorsement: true},
+	}}
+	streaks := &memoryHelperStreaks{streaks: make(map[string]*student.HelperStreak)}
+	publisher := &recordingPublisher{}
+
+	job := NewTrackHelperStreaksJob(streaks, activity, nil, nil, nil, DefaultTrackHelperStreaksConfig()).
+		WithEventPublisher(publisher)
+	job.now = func() time.Time { return almaty(time.November, 2, 10) }
+
+	// The backfill announces nobody
+	require.NoError(t, job.Run(context.Background()))
+	assert.Empty(t, publisher.events)
+
+	// W45: arman and bolat tie, the smallest ID wins; W44 is not counted
+	activity.events = append(activity.events,
+		helpEvent{studentID: "bolat", at: almaty(time.November, 3, 12), endorsement: true},
+		helpEvent{studentID: "bolat", at: almaty(time.November, 6, 12), endorsement: true},
+		helpEvent{studentID: "arman", at: almaty(time.November, 4, 12), endorsement: true},
+		helpEvent{studentID: "arman", at: almaty(time.November, 5, 12), endorsement: true},
+		helpEvent{studentID: "dana", at: almaty(time.November, 5, 12)},
+	)
+	job.now = func() time.Time { return almaty(time.November, 9, 10) }
+	require.NoError(t, job.Run(context.Background()))
+	require.NoError(t, job.Run(context.Background()))
+
+	require.Len(t, publisher.events, 1, "a week is announced once")
+	event, ok := publisher.events[0].(shared.HelperOfTheWeekEvent)
+	require.True(t, ok)
+	assert.Equal(t, "arman", event.StudentID)
+	assert.Equal(t, "2026-W45", event.Week)
+	assert.Equal(t, 2, event.Endorsements)
+
+	// A week without endorsements has no helper of the week
+	job.now = func() time.Time { return almaty(time.November, 16, 10) }
+	require.NoError(t, job.Run(context.Background()))
+	assert.Len(t, publisher.events, 1)
+}
@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// OnceSchedule runs a job a single time. A time in the past runs the job on
+// the next tick, so timers restored after a restart still fire.
+type OnceSchedule struct {
+	At time.Time
+}
+
+// NewOnceSchedule creates a new OnceSchedule.
+func NewOnceSchedule(at time.Time) *OnceSchedule {
+	return &OnceSchedule{At: at}
+}
+
+// Next returns the scheduled time, or t if it has already passed.
+func (s *OnceSchedule) Next(t time.Time) time.Time {
+	if t.Before(s.At) {
+		return s.At
+	}
+	return t
+}
+
+// String returns the string representation of the schedule.
+func (s *OnceSchedule) String() string {
+	return "@once " + s.At.Format(time.RFC3339)
+}
+
+// FuncJob adapts a function to the Job interface.
+type FuncJob struct {
+	name        string
+	description string
+	fn          func(ctx context.Context) error
+}
+
+// NewFuncJob creates a job that calls fn.
+func NewFuncJob(name, description string, fn func(ctx context.Context) error) *FuncJob {
+	return &FuncJob{name: name, description: description, fn: fn}
+}
+
+// Name returns the unique name of the job.
+func (j *FuncJob) Name() string { return j.name }
+
+// Description returns a human-readable description of the job.
+func (j *FuncJob) Description() string { return j.description }
+
+// Run executes the job.
+func (j *FuncJob) Run(ctx context.Context) error { return j.fn(ctx) }
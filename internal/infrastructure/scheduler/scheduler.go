@@ -46,6 +46,11 @@ type JobResult struct {
 	Success     bool
 	Error       error
 	Metadata    map[string]interface{}
+
+	// Skipped is set when the run did not start because an upstream job
+	// was stale; SkipReason explains why.
+	Skipped    bool
+	SkipReason string
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -73,10 +78,16 @@ type Scheduler struct {
 	lastRuns   map[string]*JobResult
 	runHistory []JobResult
 
+	// Dependencies between jobs
+	dependencies   map[string][]Dependency
+	lastSuccess    map[string]time.Time
+	dependencyPoll time.Duration
+
 	// Hooks
 	onJobStart    func(jobName string)
 	onJobComplete func(result JobResult)
 	onJobError    func(jobName string, err error)
+	onJobRun      RunContextFunc
 }
 
 // scheduledJob wraps a Job with scheduling information.
@@ -88,6 +99,10 @@ type scheduledJob struct {
 	nextRun   time.Time
 	runCount  int64
 	failCount int64
+	once      bool // removed after its only run
+
+	skipCount      int64
+	lastSkipReason string
 }
 
 // SchedulerConfig contains configuration for the Scheduler.
@@ -103,15 +118,20 @@ type SchedulerConfig struct {
 
 	// EnableMetrics enables metrics collection.
 	EnableMetrics bool
+
+	// DependencyPollInterval is how often a job waiting for an upstream job
+	// checks it again (default: 5s).
+	DependencyPollInterval time.Duration
 }
 
 // DefaultSchedulerConfig returns sensible defaults.
 func DefaultSchedulerConfig() SchedulerConfig {
 	return SchedulerConfig{
-		Logger:         slog.Default(),
-		Timezone:       time.UTC,
-		MaxHistorySize: 1000,
-		EnableMetrics:  true,
+		Logger:                 slog.Default(),
+		Timezone:               time.UTC,
+		MaxHistorySize:         1000,
+		EnableMetrics:          true,
+		DependencyPollInterval: 5 * time.Second,
 	}
 }
 
@@ -126,6 +146,9 @@ func NewScheduler(config SchedulerConfig) *Scheduler {
 	if config.MaxHistorySize <= 0 {
 		config.MaxHistorySize = 1000
 	}
+	if config.DependencyPollInterval <= 0 {
+		config.DependencyPollInterval = 5 * time.Second
+	}
 
 	s := &Scheduler{
 		logger:     config.Logger,
@@ -133,6 +156,10 @@ func NewScheduler(config SchedulerConfig) *Scheduler {
 		jobs:       make(map[string]*scheduledJob),
 		lastRuns:   make(map[string]*JobResult),
 		runHistory: make([]JobResult, 0, config.MaxHistorySize),
+
+		dependencies:   make(map[string][]Dependency),
+		lastSuccess:    make(map[string]time.Time),
+		dependencyPoll: config.DependencyPollInterval,
 	}
 
 	if config.EnableMetrics {
@@ -182,6 +209,36 @@ func (s *Scheduler) Register(job Job, schedule Schedule) error {
 	return nil
 }
 
+// ScheduleOnce adds a job that runs a single time at the given moment and is
+// then removed. Returns ErrJobAlreadyExists if a job with the same name is
+// still pending.
+func (s *Scheduler) ScheduleOnce(job Job, at time.Time) error {
+	if job == nil {
+		return ErrNilJob
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := job.Name()
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("%w: %s", ErrJobAlreadyExists, name)
+	}
+
+	schedule := NewOnceSchedule(at)
+	s.jobs[name] = &scheduledJob{
+		job:      job,
+		schedule: schedule,
+		enabled:  true,
+		nextRun:  schedule.Next(time.Now().In(s.timezone)),
+		once:     true,
+	}
+
+	s.logger.Debug("one-shot job scheduled", "job", name, "run_at", at.Format(time.RFC3339))
+
+	return nil
+}
+
 // Unregister removes a job from the scheduler.
 func (s *Scheduler) Unregister(jobName string) error {
 	s.mu.Lock()
@@ -192,6 +249,7 @@ func (s *Scheduler) Unregister(jobName string) error {
 	}
 
 	delete(s.jobs, jobName)
+	delete(s.dependencies, jobName)
 	s.logger.Info("job unregistered", "job", jobName)
 
 	return nil
@@ -330,24 +388,40 @@ func (s *Scheduler) runJob(sj *scheduledJob) {
 	defer s.wg.Done()
 
 	jobName := sj.job.Name()
-	startedAt := time.Now()
+	dueAt := time.Now()
 
-	// Call onJobStart hook
-	if s.onJobStart != nil {
-		s.onJobStart(jobName)
+	// Update next run time before waiting for upstream jobs and executing
+	s.mu.Lock()
+	if sj.once {
+		sj.enabled = false
+		delete(s.jobs, jobName)
+	} else {
+		sj.nextRun = sj.schedule.Next(dueAt.In(s.timezone))
 	}
+	s.mu.Unlock()
 
-	s.logger.Info("job started", "job", jobName)
+	staleUpstreams, skipReason := s.checkDependencies(s.ctx, jobName)
+	if skipReason != "" {
+		s.recordSkip(sj, dueAt, skipReason)
+		return
+	}
 
-	// Update next run time before executing
+	startedAt := time.Now()
 	s.mu.Lock()
 	sj.lastRun = startedAt
-	sj.nextRun = sj.schedule.Next(startedAt.In(s.timezone))
 	sj.runCount++
 	s.mu.Unlock()
 
+	// Call onJobStart hook
+	if s.onJobStart != nil {
+		s.onJobStart(jobName)
+	}
+
+	s.logger.Info("job started", "job", jobName)
+
 	// Execute the job
-	err := sj.job.Run(s.ctx)
+	runCtx, finish := s.prepareRun(s.ctx, jobName)
+	err := sj.job.Run(runCtx)
 	completedAt := time.Now()
 	duration := completedAt.Sub(startedAt)
 
@@ -361,6 +435,10 @@ func (s *Scheduler) runJob(sj *scheduledJob) {
 		Error:       err,
 		Metadata:    make(map[string]interface{}),
 	}
+	if len(staleUpstreams) > 0 {
+		result.Metadata["stale_upstream"] = staleUpstreams
+	}
+	finish(result.Metadata)
 
 	// Update metrics
 	if s.metrics != nil {
@@ -371,6 +449,8 @@ func (s *Scheduler) runJob(sj *scheduledJob) {
 	s.mu.Lock()
 	if err != nil {
 		sj.failCount++
+	} else {
+		s.lastSuccess[jobName] = completedAt
 	}
 	s.lastRuns[jobName] = &result
 	s.addToHistory(result)
@@ -392,6 +472,7 @@ func (s *Scheduler) runJob(sj *scheduledJob) {
 		s.logger.Info("job completed",
 			"job", jobName,
 			"duration", duration.String(),
+			"summary", result.Metadata,
 		)
 	}
 
@@ -401,6 +482,22 @@ func (s *Scheduler) runJob(sj *scheduledJob) {
 	}
 }
 
+// prepareRun applies the onJobRun hook to the context of a single run.
+func (s *Scheduler) prepareRun(ctx context.Context, jobName string) (context.Context, func(map[string]interface{})) {
+	s.mu.RLock()
+	hook := s.onJobRun
+	s.mu.RUnlock()
+
+	if hook == nil {
+		return ctx, func(map[string]interface{}) {}
+	}
+	runCtx, finish := hook(ctx, jobName)
+	if finish == nil {
+		finish = func(map[string]interface{}) {}
+	}
+	return runCtx, finish
+}
+
 // addToHistory adds a result to the run history with size limit.
 func (s *Scheduler) addToHistory(result JobResult) {
 	s.runHistory = append(s.runHistory, result)
@@ -416,6 +513,16 @@ func (s *Scheduler) addToHistory(result JobResult) {
 // MANUAL EXECUTION
 // ══════════════════════════════════════════════════════════════════════════════
 
+// manualRunKey marks the context of a job started via RunNow.
+type manualRunKey struct{}
+
+// IsManualRun reports whether the job was triggered manually via RunNow.
+// Jobs can use it to skip caches and fetch fresh data.
+func IsManualRun(ctx context.Context) bool {
+	v, _ := ctx.Value(manualRunKey{}).(bool)
+	return v
+}
+
 // RunNow immediately executes a job by name, ignoring its schedule.
 func (s *Scheduler) RunNow(ctx context.Context, jobName string) (*JobResult, error) {
 	s.mu.RLock()
@@ -429,7 +536,8 @@ func (s *Scheduler) RunNow(ctx context.Context, jobName string) (*JobResult, err
 	startedAt := time.Now()
 	s.logger.Info("manual job execution started", "job", jobName)
 
-	err := sj.job.Run(ctx)
+	runCtx, finish := s.prepareRun(context.WithValue(ctx, manualRunKey{}, true), jobName)
+	err := sj.job.Run(runCtx)
 	completedAt := time.Now()
 	duration := completedAt.Sub(startedAt)
 
@@ -442,6 +550,7 @@ func (s *Scheduler) RunNow(ctx context.Context, jobName string) (*JobResult, err
 		Error:       err,
 		Metadata:    map[string]interface{}{"manual": true},
 	}
+	finish(result.Metadata)
 
 	// Update metrics
 	if s.metrics != nil {
@@ -450,6 +559,9 @@ func (s *Scheduler) RunNow(ctx context.Context, jobName string) (*JobResult, err
 
 	// Update state
 	s.mu.Lock()
+	if err == nil {
+		s.lastSuccess[jobName] = completedAt
+	}
 	s.lastRuns[jobName] = result
 	s.addToHistory(*result)
 	s.mu.Unlock()
@@ -464,6 +576,7 @@ func (s *Scheduler) RunNow(ctx context.Context, jobName string) (*JobResult, err
 		s.logger.Info("manual job execution completed",
 			"job", jobName,
 			"duration", duration.String(),
+			"summary", result.Metadata,
 		)
 	}
 
@@ -485,6 +598,12 @@ type JobInfo struct {
 	RunCount    int64
 	FailCount   int64
 	LastResult  *JobResult
+
+	// DependsOn lists the upstream jobs; together they form the dependency graph.
+	DependsOn      []Dependency
+	LastSuccess    time.Time
+	SkipCount      int64
+	LastSkipReason string
 }
 
 // ListJobs returns information about all registered jobs.
@@ -494,18 +613,7 @@ func (s *Scheduler) ListJobs() []JobInfo {
 
 	infos := make([]JobInfo, 0, len(s.jobs))
 	for name, sj := range s.jobs {
-		info := JobInfo{
-			Name:        name,
-			Description: sj.job.Description(),
-			Enabled:     sj.enabled,
-			Schedule:    sj.schedule.String(),
-			LastRun:     sj.lastRun,
-			NextRun:     sj.nextRun,
-			RunCount:    sj.runCount,
-			FailCount:   sj.failCount,
-			LastResult:  s.lastRuns[name],
-		}
-		infos = append(infos, info)
+		infos = append(infos, s.jobInfo(name, sj))
 	}
 
 	return infos
@@ -521,19 +629,27 @@ func (s *Scheduler) GetJobInfo(jobName string) (*JobInfo, error) {
 		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, jobName)
 	}
 
-	info := &JobInfo{
-		Name:        jobName,
-		Description: sj.job.Description(),
-		Enabled:     sj.enabled,
-		Schedule:    sj.schedule.String(),
-		LastRun:     sj.lastRun,
-		NextRun:     sj.nextRun,
-		RunCount:    sj.runCount,
-		FailCount:   sj.failCount,
-		LastResult:  s.lastRuns[jobName],
-	}
+	info := s.jobInfo(jobName, sj)
+	return &info, nil
+}
 
-	return info, nil
+// jobInfo builds the JobInfo of a job. Caller must hold s.mu.
+func (s *Scheduler) jobInfo(name string, sj *scheduledJob) JobInfo {
+	return JobInfo{
+		Name:           name,
+		Description:    sj.job.Description(),
+		Enabled:        sj.enabled,
+		Schedule:       sj.schedule.String(),
+		LastRun:        sj.lastRun,
+		NextRun:        sj.nextRun,
+		RunCount:       sj.runCount,
+		FailCount:      sj.failCount,
+		LastResult:     s.lastRuns[name],
+		DependsOn:      append([]Dependency(nil), s.dependencies[name]...),
+		LastSuccess:    s.lastSuccess[name],
+		SkipCount:      sj.skipCount,
+		LastSkipReason: sj.lastSkipReason,
+	}
 }
 
 // GetHistory returns the recent job execution history.
@@ -583,6 +699,18 @@ func (s *Scheduler) OnJobError(fn func(jobName string, err error)) {
 	s.onJobError = fn
 }
 
+// RunContextFunc prepares the context of a single job run, scheduled or
+// manual. The returned finish func receives the run metadata after the job
+// returns and may add to the run summary.
+type RunContextFunc func(ctx context.Context, jobName string) (context.Context, func(metadata map[string]interface{}))
+
+// OnJobRun sets a hook that prepares the context of every job run.
+func (s *Scheduler) OnJobRun(fn RunContextFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onJobRun = fn
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // METRICS
 // ══════════════════════════════════════════════════════════════════════════════
@@ -686,4 +814,10 @@ var (
 
 	// ErrSchedulerNotRunning is returned when Stop is called on a stopped scheduler.
 	ErrSchedulerNotRunning = fmt.Errorf("scheduler is not running")
+
+	// ErrInvalidDependency is returned when a dependency is malformed.
+	ErrInvalidDependency = fmt.Errorf("invalid job dependency")
+
+	// ErrDependencyCycle is returned when a dependency would create a cycle.
+	ErrDependencyCycle = fmt.Errorf("job dependency cycle")
 )
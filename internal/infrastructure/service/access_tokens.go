@@ -0,0 +1,153 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// DefaultAccessTokenCacheTTL is how long resolved access tokens are cached in
+// memory. A revoked token keeps working for at most this long.
+const DefaultAccessTokenCacheTTL = time.Minute
+
+// DefaultAccessTokenCacheSize is the most lookups (hits and misses) kept in
+// memory. Beyond it the least recently used lookup is dropped.
+const DefaultAccessTokenCacheSize = 10000
+
+// accessTokenTouchInterval is how often last_used_at is written per token.
+const accessTokenTouchInterval = time.Minute
+
+// AccessTokens resolves personal access tokens for the HTTP API. Lookups are
+// cached by token hash (including misses, so a guessed token cannot hammer
+// the database); expiry is checked on every call, so an expired token is
+// rejected even while cached. The cache holds at most maxEntries lookups,
+// so a stream of guessed tokens evicts old misses instead of growing it.
+type AccessTokens struct {
+	repo       student.AccessTokenRepository
+	ttl        time.Duration
+	maxEntries int
+	logger     *slog.Logger
+
+	mu      sync.Mutex
+	cache   map[string]*list.Element
+	recency *list.List
+	touched map[string]time.Time
+
+	// now is the time source (replaced in tests).
+	now func() time.Time
+}
+
+// cachedAccessToken is a cached lookup; token is nil for unknown tokens.
+type cachedAccessToken struct {
+	hash      string
+	token     *student.AccessToken
+	expiresAt time.Time
+}
+
+// NewAccessTokens creates a new AccessTokens.
+func NewAccessTokens(repo student.AccessTokenRepository, logger *slog.Logger) *AccessTokens {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &AccessTokens{
+		repo:       repo,
+		ttl:        DefaultAccessTokenCacheTTL,
+		maxEntries: DefaultAccessTokenCacheSize,
+		logger:     logger.With("component", "access_tokens"),
+		cache:      make(map[string]*list.Element),
+		recency:    list.New(),
+		touched:    make(map[string]time.Time),
+		now:        time.Now,
+	}
+}
+
+// Resolve returns the active token for a secret. It returns
+// student.ErrAccessTokenNotFound for unknown, revoked and expired tokens.
+func (s *AccessTokens) Resolve(ctx context.Context, secret string) (*student.AccessToken, error) {
+	if !student.LooksLikeAccessToken(secret) {
+		return nil, student.ErrAccessTokenNotFound
+	}
+
+	hash := student.HashAccessToken(secret)
+	now := s.now()
+
+	cached, ok := s.cached(hash)
+	if !ok || !now.Before(cached.expiresAt) {
+		token, err := s.repo.GetByHash(ctx, hash)
+		if err != nil && !errors.Is(err, student.ErrAccessTokenNotFound) {
+			return nil, err
+		}
+		cached = cachedAccessToken{hash: hash, token: token, expiresAt: now.Add(s.ttl)}
+		s.store(cached)
+	}
+
+	if cached.token == nil || !cached.token.IsActive(now) {
+		return nil, student.ErrAccessTokenNotFound
+	}
+
+	s.touch(ctx, cached.token.ID, now)
+	return cached.token, nil
+}
+
+// cached returns the cached lookup for hash and marks it used.
+func (s *AccessTokens) cached(hash string) (cachedAccessToken, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.cache[hash]
+	if !ok {
+		return cachedAccessToken{}, false
+	}
+	s.recency.MoveToFront(elem)
+	return elem.Value.(cachedAccessToken), true
+}
+
+// store caches a lookup, evicting the least recently used ones beyond
+// maxEntries.
+func (s *AccessTokens) store(cached cachedAccessToken) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.cache[cached.hash]; ok {
+		elem.Value = cached
+		s.recency.MoveToFront(elem)
+		return
+	}
+	s.cache[cached.hash] = s.recency.PushFront(cached)
+
+	for len(s.cache) > s.maxEntries {
+		oldest := s.recency.Back()
+		s.recency.Remove(oldest)
+		delete(s.cache, oldest.Value.(cachedAccessToken).hash)
+	}
+}
+
+// touch records the token use, at most once per accessTokenTouchInterval.
+func (s *AccessTokens) touch(ctx context.Context, tokenID string, now time.Time) {
+	s.mu.Lock()
+	last, ok := s.touched[tokenID]
+	if ok && now.Sub(last) < accessTokenTouchInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.touched[tokenID] = now
+	if len(s.touched) > s.maxEntries {
+		// Entries past the interval no longer hold back a write
+		for id, at := range s.touched {
+			if now.Sub(at) >= accessTokenTouchInterval {
+				delete(s.touched, id)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if err := s.repo.TouchLastUsed(ctx, tokenID, now); err != nil {
+		s.logger.Warn("failed to record access token use", "token_id", tokenID, "error", err)
+	}
+}
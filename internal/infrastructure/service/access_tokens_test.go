@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type fakeAccessTokenRepo struct {
+	student.AccessTokenRepository
+	byHash  map[string]*student.AccessToken
+	lookups int
+	touches int
+}
+
+func (f *fakeAccessTokenRepo) GetByHash(_ context.Context, hash string) (*student.AccessToken, error) {
+	f.lookups++
+	token, ok := f.byHash[hash]
+	if !ok {
+		return nil, student.ErrAccessTokenNotFound
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (f *fakeAccessTokenRepo) TouchLastUsed(context.Context, string, time.Time) error {
+	f.touches++
+	return nil
+}
+
+func newTestAccessTokens(t *testing.T, now *time.Time) (*AccessTokens, *fakeAccessTokenRepo, *student.AccessToken, string) {
+	t.Helper()
+
+	token, secret, err := student.NewAccessToken("s1", *now)
+	require.NoError(t, err)
+	token.ID = "t1"
+
+	repo := &fakeAccessTokenRepo{byHash: map[string]*student.AccessToken{token.Hash: token}}
+	s := NewAccessTokens(repo, nil)
+	s.now = func() time.Time { return *now }
+	return s, repo, token, secret
+}
+
+func TestAccessTokens_ResolveAndCache(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	s, repo, _, secret := newTestAccessTokens(t, &now)
+	ctx := context.Background()
+
+	token, err := s.Resolve(ctx, secret)
+	require.NoError(t, err)
+	assert.Equal(t, "s1", token.StudentID)
+
+	_, err = s.Resolve(ctx, secret)
+	require.NoError(t, err)
+	assert.Equal(t, 1, repo.lookups)
+	assert.Equal(t, 1, repo.touches, "last_used_at is written at most once a minute")
+
+	// Unknown tokens are rejected and cached too
+	_, err = s.Resolve(ctx, "ach_unknown")
+	assert.ErrorIs(t, err, student.ErrAccessTokenNotFound)
+	_, err = s.Resolve(ctx, "ach_unknown")
+	assert.ErrorIs(t, err, student.ErrAccessTokenNotFound)
+	assert.Equal(t, 2, repo.lookups)
+
+	_, err = s.Resolve(ctx, "not-a-token")
+	assert.ErrorIs(t, err, student.ErrAccessTokenNotFound)
+	assert.Equal(t, 2, repo.lookups)
+}
+
+func TestAccessTokens_RevocationWithinCacheTTL(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	s, repo, stored, secret := newTestAccessTokens(t, &now)
+	ctx := context.Background()
+
+	_, err := s.Resolve(ctx, secret)
+	require.NoError(t, err)
+
+	revokedAt := now
+	stored.RevokedAt = &revokedAt
+
+	// Still cached
+	now = now.Add(30 * time.Second)
+	_, err = s.Resolve(ctx, secret)
+	require.NoError(t, err)
+
+	// Rejected once the cache entry expires
+	now = now.Add(DefaultAccessTokenCacheTTL)
+	_, err = s.Resolve(ctx, secret)
+	assert.ErrorIs(t, err, student.ErrAccessTokenNotFound)
+	assert.Equal(t, 2, repo.lookups)
+}
+
+func TestAccessTokens_Expiry(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	s, _, stored, secret := newTestAccessTokens(t, &now)
+	ctx := context.Background()
+
+	now = stored.ExpiresAt.Add(-time.Second)
+	_, err := s.Resolve(ctx, secret)
+	require.NoError(t, err)
+
+	// Expired while cached
+	now = stored.ExpiresAt
+	_, err = s.Resolve(ctx, secret)
+	assert.ErrorIs(t, err, student.ErrAccessTokenNotFound)
+}
+
+func TestAccessTokens_CacheIsBounded(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	s, repo, _, secret := newTestAccessTokens(t, &now)
+	s.maxEntries = 3
+	ctx := context.Background()
+
+	_, err := s.Resolve(ctx, secret)
+	require.NoError(t, err)
+
+	// A stream of guessed tokens never grows the cache past maxEntries
+	for i := 0; i < 50; i++ {
+		_, err := s.Resolve(ctx, fmt.Sprintf("ach_guess%d", i))
+		assert.ErrorIs(t, err, student.ErrAccessTokenNotFound)
+		_, err = s.Resolve(ctx, secret)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(s.cache), 3)
+		assert.Equal(t, len(s.cache), s.recency.Len())
+	}
+
+	// The token in use stays cached: one lookup for it, one per guess
+	assert.Equal(t, 51, repo.lookups)
+
+	// The oldest misses are evicted and looked up again
+	_, err = s.Resolve(ctx, "ach_guess0")
+	assert.ErrorIs(t, err, student.ErrAccessTokenNotFound)
+	assert.Equal(t, 52, repo.lookups)
+}
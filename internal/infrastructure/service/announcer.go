@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
+)
+
+// NotificationSender delivers a single notification.
+// Implemented by ChannelSender.
+type NotificationSender interface {
+	Send(ctx context.Context, notif *notification.Notification) notification.DeliveryResult
+}
+
+// Announcer sends announcements to group chats. During a chat's quiet hours
+// announcements are queued until the window ends instead of being sent;
+// FlushDue delivers them once they are due.
+type Announcer struct {
+	sender   NotificationSender
+	queue    notification.AnnouncementQueue
+	settings settings.Reader
+	defaults notification.QuietHours
+	location *time.Location
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// NewAnnouncer creates a new Announcer. defaults are the quiet hours of chats
+// that don't override them (settings of settings.ChatScope), in location.
+func NewAnnouncer(
+	sender NotificationSender,
+	queue notification.AnnouncementQueue,
+	reader settings.Reader,
+	defaults notification.QuietHours,
+	location *time.Location,
+	logger *slog.Logger,
+) *Announcer {
+	if reader == nil {
+		reader = settings.Defaults{}
+	}
+	if location == nil {
+		location = time.UTC
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Announcer{
+		sender:   sender,
+		queue:    queue,
+		settings: reader,
+		defaults: defaults,
+		location: location,
+		logger:   logger.With("component", "announcer"),
+		now:      time.Now,
+	}
+}
+
+// QuietHours returns the quiet hours of a chat.
+func (a *Announcer) QuietHours(ctx context.Context, chatID int64) notification.QuietHours {
+	scope := settings.ChatScope(chatID)
+	return notification.QuietHours{
+		Start: a.settings.GetInt(ctx, scope, settings.KeyQuietHoursStart, a.defaults.Start),
+		End:   a.settings.GetInt(ctx, scope, settings.KeyQuietHoursEnd, a.defaults.End),
+	}
+}
+
+// Announce sends the announcement, or queues it if the chat is in its quiet
+// hours and the announcement isn't forced. Returns true when it was queued.
+func (a *Announcer) Announce(ctx context.Context, ann notification.Announcement) (bool, error) {
+	now := a.now().In(a.location)
+
+	quiet := a.QuietHours(ctx, ann.ChatID)
+	if ann.Force || !quiet.Contains(now) {
+		return false, a.deliver(ctx, ann.ChatID, ann.Message)
+	}
+
+	pending := &notification.PendingAnnouncement{
+		ID:           uuid.New().String(),
+		ChatID:       ann.ChatID,
+		DedupKey:     ann.DedupKey,
+		Message:      ann.Message,
+		CreatedAt:    now,
+		DeliverAfter: quiet.EndAfter(now),
+	}
+	if err := a.queue.Enqueue(ctx, pending); err != nil {
+		return false, fmt.Errorf("queue announcement: %w", err)
+	}
+
+	a.logger.Info("announcement queued for quiet hours",
+		"chat_id", ann.ChatID,
+		"dedup_key", ann.DedupKey,
+		"deliver_after", pending.DeliverAfter,
+	)
+	return true, nil
+}
+
+// FlushDue delivers up to limit due announcements in the order they were
+// queued, collapsing duplicates. Delivery stops at the first failure so the
+// order is kept; the rest is retried by the next flush.
+// Returns how many were sent and how many duplicates were dropped.
+func (a *Announcer) FlushDue(ctx context.Context, limit int) (sent, collapsed int, err error) {
+	due, err := a.queue.ListDue(ctx, a.now(), limit)
+	if err != nil {
+		return 0, 0, fmt.Errorf("list due announcements: %w", err)
+	}
+	if len(due) == 0 {
+		return 0, 0, nil
+	}
+
+	send, duplicates := notification.CollapseAnnouncements(due)
+
+	// A duplicate is dropped once the announcement it merged into is sent
+	mergedInto := make(map[string][]string)
+	for _, d := range duplicates {
+		for _, s := range send {
+			if s.ChatID == d.ChatID && s.DedupKey == d.DedupKey {
+				mergedInto[s.ID] = append(mergedInto[s.ID], d.ID)
+				break
+			}
+		}
+	}
+
+	var done []string
+	for _, ann := range send {
+		if err = a.deliver(ctx, ann.ChatID, ann.Message); err != nil {
+			break
+		}
+		sent++
+		collapsed += len(mergedInto[ann.ID])
+		done = append(done, ann.ID)
+		done = append(done, mergedInto[ann.ID]...)
+	}
+
+	if len(done) > 0 {
+		if delErr := a.queue.Delete(ctx, done); delErr != nil {
+			return sent, collapsed, fmt.Errorf("delete sent announcements: %w", delErr)
+		}
+	}
+	return sent, collapsed, err
+}
+
+// deliver sends the announcement right away.
+func (a *Announcer) deliver(ctx context.Context, chatID int64, message string) error {
+	n := &notification.Notification{
+		ID:             notification.NotificationID(uuid.New().String()),
+		RecipientID:    notification.RecipientID(settings.ChatScope(chatID)),
+		TelegramChatID: notification.TelegramChatID(chatID),
+		Type:           notification.NotificationTypeSystemAlert,
+		Priority:       notification.NotificationTypeSystemAlert.DefaultPriority(),
+		Status:         notification.StatusPending,
+		Message:        message,
+		CreatedAt:      a.now(),
+	}
+	if result := a.sender.Send(ctx, n); !result.Success {
+		return fmt.Errorf("send announcement to chat %d: %v", chatID, result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
+)
+
+// memoryAnnouncementQueue keeps queued announcements in insertion order.
+type memoryAnnouncementQueue struct {
+	pending []*notification.PendingAnnouncement
+}
+
+func (q *memoryAnnouncementQueue) Enqueue(_ context.Context, a *notification.PendingAnnouncement) error {
+	q.pending = append(q.pending, a)
+	return nil
+}
+
+func (q *memoryAnnouncementQueue) ListDue(_ context.Context, now time.Time, limit int) ([]*notification.PendingAnnouncement, error) {
+	var due []*notification.PendingAnnouncement
+	for _, a := range q.pending {
+		if !a.DeliverAfter.After(now) && len(due) < limit {
+			due = append(due, a)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].CreatedAt.Before(due[j].CreatedAt) })
+	return due, nil
+}
+
+func (q *memoryAnnouncementQueue) Delete(_ context.Context, ids []string) error {
+	deleted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+	kept := q.pending[:0]
+	for _, a := range q.pending {
+		if !deleted[a.ID] {
+			kept = append(kept, a)
+		}
+	}
+	q.pending = kept
+	return nil
+}
+
+// recordingSender records delivered messages.
+type recordingSender struct {
+	sent []*notification.Notification
+}
+
+func (s *recordingSender) Send(_ context.Context, n *notification.Notification) notification.DeliveryResult {
+	s.sent = append(s.sent, n)
+	return notification.DeliveryResult{Success: true}
+}
+
+func (s *recordingSender) messages() []string {
+	result := make([]string, len(s.sent))
+	for i, n := range s.sent {
+		result[i] = n.Message
+	}
+	return result
+}
+
+const announcementChat int64 = -100500
+
+func newTestAnnouncer(t *testing.T, now *time.Time, chatSettings settings.Values) (*Announcer, *memoryAnnouncementQueue, *recordingSender) {
+	t.Helper()
+	almaty, err := time.LoadLocation("Asia/Almaty")
+	require.NoError(t, err)
+
+	repo := &fakeSettingsRepo{values: map[string]settings.Values{
+		settings.ChatScope(announcementChat): chatSettings,
+	}}
+	queue := &memoryAnnouncementQueue{}
+	sender := &recordingSender{}
+	a := NewAnnouncer(sender, queue, NewCohortSettings(repo, nil), notification.QuietHours{Start: 23, End: 8}, almaty, nil)
+	a.now = func() time.Time { return *now }
+	return a, queue, sender
+}
+
+func TestAnnouncer_QueuesDuringQuietHours(t *testing.T) {
+	almaty, err := time.LoadLocation("Asia/Almaty")
+	require.NoError(t, err)
+	now := time.Date(2026, time.October, 17, 23, 40, 0, 0, almaty)
+	a, queue, sender := newTestAnnouncer(t, &now, nil)
+	ctx := context.Background()
+
+	queued, err := a.Announce(ctx, notification.Announcement{ChatID: announcementChat, DedupKey: "event:1:winners", Message: "winners"})
+	require.NoError(t, err)
+	assert.True(t, queued)
+	assert.Empty(t, sender.sent)
+	require.Len(t, queue.pending, 1)
+	assert.True(t, queue.pending[0].DeliverAfter.Equal(time.Date(2026, time.October, 18, 8, 0, 0, 0, almaty)))
+
+	// An admin can send right away
+	queued, err = a.Announce(ctx, notification.Announcement{ChatID: announcementChat, Message: "manual", Force: true})
+	require.NoError(t, err)
+	assert.False(t, queued)
+	assert.Equal(t, []string{"manual"}, sender.messages())
+
+	// Outside the window announcements go out directly
+	now = time.Date(2026, time.October, 18, 12, 0, 0, 0, almaty)
+	queued, err = a.Announce(ctx, notification.Announcement{ChatID: announcementChat, Message: "noon"})
+	require.NoError(t, err)
+	assert.False(t, queued)
+	assert.Len(t, queue.pending, 1)
+}
+
+func TestAnnouncer_ChatOverridesQuietHours(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 18, 0, 0, 0, time.UTC) // 23:00 in Almaty
+	a, queue, sender := newTestAnnouncer(t, &now, settings.Values{
+		settings.KeyQuietHoursStart: json.RawMessage("0"),
+		settings.KeyQuietHoursEnd:   json.RawMessage("0"),
+	})
+
+	queued, err := a.Announce(context.Background(), notification.Announcement{ChatID: announcementChat, Message: "late"})
+	require.NoError(t, err)
+	assert.False(t, queued)
+	assert.Empty(t, queue.pending)
+	assert.Equal(t, []string{"late"}, sender.messages())
+}
+
+func TestAnnouncer_FlushesInOrderAndCollapsesReruns(t *testing.T) {
+	almaty, err := time.LoadLocation("Asia/Almaty")
+	require.NoError(t, err)
+	now := time.Date(2026, time.October, 17, 23, 5, 0, 0, almaty)
+	a, queue, sender := newTestAnnouncer(t, &now, nil)
+	ctx := context.Background()
+
+	announce := func(key, msg string) {
+		queued, err := a.Announce(ctx, notification.Announcement{ChatID: announcementChat, DedupKey: key, Message: msg})
+		require.NoError(t, err)
+		require.True(t, queued)
+		now = now.Add(10 * time.Minute)
+	}
+	announce("event:1:winners", "event 1")
+	announce("event:2:winners", "event 2")
+	announce("", "plain")
+	announce("event:1:winners", "event 1 (rerun)") // the job ran twice
+
+	// Nothing is due before the morning
+	sent, collapsed, err := a.FlushDue(ctx, 100)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+	assert.Zero(t, collapsed)
+	assert.Empty(t, sender.sent)
+
+	now = time.Date(2026, time.October, 18, 8, 0, 0, 0, almaty)
+	sent, collapsed, err = a.FlushDue(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 3, sent)
+	assert.Equal(t, 1, collapsed)
+	assert.Equal(t, []string{"event 1 (rerun)", "event 2", "plain"}, sender.messages())
+	assert.Equal(t, notification.TelegramChatID(announcementChat), sender.sent[0].TelegramChatID)
+	assert.Empty(t, queue.pending)
+}
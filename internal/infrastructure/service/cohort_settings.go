@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
+)
+
+// DefaultCohortSettingsTTL is how long cohort settings are cached in memory.
+const DefaultCohortSettingsTTL = time.Minute
+
+// CohortSettings provides typed, cached access to per-cohort settings.
+// It implements settings.Reader: feature code passes its global (env) default
+// and gets the cohort override when one exists. Lookup errors fall back to
+// the default, so a database outage never disables a feature.
+type CohortSettings struct {
+	repo   settings.Repository
+	ttl    time.Duration
+	logger *slog.Logger
+
+	mu    sync.Mutex
+	cache map[string]cachedCohortSettings
+
+	// now is the time source (replaced in tests).
+	now func() time.Time
+}
+
+// cachedCohortSettings is a cached snapshot of one cohort's overrides.
+type cachedCohortSettings struct {
+	values    settings.Values
+	expiresAt time.Time
+}
+
+// NewCohortSettings creates a new CohortSettings.
+func NewCohortSettings(repo settings.Repository, logger *slog.Logger) *CohortSettings {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &CohortSettings{
+		repo:   repo,
+		ttl:    DefaultCohortSettingsTTL,
+		logger: logger.With("component", "cohort_settings"),
+		cache:  make(map[string]cachedCohortSettings),
+		now:    time.Now,
+	}
+}
+
+// Values returns the overridden settings of a cohort.
+func (s *CohortSettings) Values(ctx context.Context, cohort string) (settings.Values, error) {
+	s.mu.Lock()
+	cached, ok := s.cache[cohort]
+	s.mu.Unlock()
+
+	if ok && s.now().Before(cached.expiresAt) {
+		return cached.values, nil
+	}
+
+	values, err := s.repo.GetByCohort(ctx, cohort)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[cohort] = cachedCohortSettings{values: values, expiresAt: s.now().Add(s.ttl)}
+	s.mu.Unlock()
+
+	return values, nil
+}
+
+// GetBool returns a boolean setting of the cohort, or def.
+func (s *CohortSettings) GetBool(ctx context.Context, cohort string, key settings.Key, def bool) bool {
+	return s.lookup(ctx, cohort, key).Bool(key, def)
+}
+
+// GetInt returns an integer setting of the cohort, or def.
+func (s *CohortSettings) GetInt(ctx context.Context, cohort string, key settings.Key, def int) int {
+	return s.lookup(ctx, cohort, key).Int(key, def)
+}
+
+// GetString returns a string setting of the cohort, or def.
+func (s *CohortSettings) GetString(ctx context.Context, cohort string, key settings.Key, def string) string {
+	return s.lookup(ctx, cohort, key).String(key, def)
+}
+
+// Set validates and stores a cohort setting.
+func (s *CohortSettings) Set(ctx context.Context, cohort string, key settings.Key, value json.RawMessage) error {
+	if err := settings.Validate(key, value); err != nil {
+		return err
+	}
+	if err := s.repo.Set(ctx, cohort, key, value); err != nil {
+		return err
+	}
+	s.invalidate(cohort)
+	return nil
+}
+
+// Delete removes a cohort override, restoring the global default.
+func (s *CohortSettings) Delete(ctx context.Context, cohort string, key settings.Key) error {
+	if err := s.repo.Delete(ctx, cohort, key); err != nil {
+		return err
+	}
+	s.invalidate(cohort)
+	return nil
+}
+
+// lookup returns the cohort values, or nil (all defaults) on error.
+func (s *CohortSettings) lookup(ctx context.Context, cohort string, key settings.Key) settings.Values {
+	if cohort == "" {
+		return nil
+	}
+
+	values, err := s.Values(ctx, cohort)
+	if err != nil {
+		s.logger.Warn("failed to load cohort settings, using default",
+			"cohort", cohort,
+			"key", key,
+			"error", err,
+		)
+		return nil
+	}
+	return values
+}
+
+// invalidate drops the cached settings of a cohort.
+func (s *CohortSettings) invalidate(cohort string) {
+	s.mu.Lock()
+	delete(s.cache, cohort)
+	s.mu.Unlock()
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+)
+
+type fakeSettingsRepo struct {
+	values map[string]settings.Values
+	err    error
+	loads  int
+}
+
+func (f *fakeSettingsRepo) GetByCohort(_ context.Context, cohort string) (settings.Values, error) {
+	f.loads++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.values[cohort], nil
+}
+
+func (f *fakeSettingsRepo) Set(_ context.Context, cohort string, key settings.Key, value json.RawMessage) error {
+	if f.values[cohort] == nil {
+		f.values[cohort] = settings.Values{}
+	}
+	f.values[cohort][key] = value
+	return nil
+}
+
+func (f *fakeSettingsRepo) Delete(_ context.Context, cohort string, key settings.Key) error {
+	delete(f.values[cohort], key)
+	return nil
+}
+
+func TestCohortSettings_OverrideAndCache(t *testing.T) {
+	repo := &fakeSettingsRepo{values: map[string]settings.Values{
+		"2025-01": {settings.KeyDigestHour: json.RawMessage("20")},
+	}}
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	s := NewCohortSettings(repo, nil)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Cohort override wins over the global default; other cohorts keep it
+	assert.Equal(t, 20, s.GetInt(ctx, "2025-01", settings.KeyDigestHour, 21))
+	assert.Equal(t, 21, s.GetInt(ctx, "2024-09", settings.KeyDigestHour, 21))
+	assert.True(t, s.GetBool(ctx, "2025-01", settings.KeyCompetitorCloseEnabled, true))
+
+	// Cached for a minute
+	repo.values["2025-01"] = settings.Values{settings.KeyDigestHour: json.RawMessage("19")}
+	assert.Equal(t, 20, s.GetInt(ctx, "2025-01", settings.KeyDigestHour, 21))
+	assert.Equal(t, 2, repo.loads)
+
+	now = now.Add(DefaultCohortSettingsTTL)
+	assert.Equal(t, 19, s.GetInt(ctx, "2025-01", settings.KeyDigestHour, 21))
+
+	// Writes invalidate the cache immediately
+	require.NoError(t, s.Set(ctx, "2025-01", settings.KeyCompetitorCloseEnabled, json.RawMessage("false")))
+	assert.False(t, s.GetBool(ctx, "2025-01", settings.KeyCompetitorCloseEnabled, true))
+
+	require.NoError(t, s.Delete(ctx, "2025-01", settings.KeyDigestHour))
+	assert.Equal(t, 21, s.GetInt(ctx, "2025-01", settings.KeyDigestHour, 21))
+}
+
+func TestCohortSettings_SetValidates(t *testing.T) {
+	s := NewCohortSettings(&fakeSettingsRepo{values: map[string]settings.Values{}}, nil)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		key   settings.Key
+		value string
+	}{
+		{settings.KeyDigestHour, "24"},
+		{settings.KeyDigestHour, `"20"`},
+		{settings.KeyCompetitorCloseEnabled, "1"},
+		{settings.KeyInactivityMarkInactiveDays, "0"},
+		{settings.KeyWeeklyRecapEnabled, "null"},
+		{"unknown_key", "true"},
+	} {
+		err := s.Set(ctx, "2025-01", tc.key, json.RawMessage(tc.value))
+		assert.True(t, shared.IsValidation(err), "%s=%s", tc.key, tc.value)
+	}
+}
+
+func TestCohortSettings_RepoErrorFallsBackToDefault(t *testing.T) {
+	s := NewCohortSettings(&fakeSettingsRepo{err: errors.New("db down")}, nil)
+
+	assert.Equal(t, 21, s.GetInt(context.Background(), "2025-01", settings.KeyDigestHour, 21))
+	assert.True(t, s.GetBool(context.Background(), "2025-01", settings.KeyStreakFreezeEnabled, true))
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+// DefaultContentBlocklistTTL is how long the blocklist snapshot is cached.
+const DefaultContentBlocklistTTL = time.Minute
+
+// ContentBlocklist provides cached access to the blocklist of phrases
+// masked in user-generated content. Rendering reads an in-memory snapshot
+// that is reloaded from the repository once it is older than the TTL;
+// changes made through Replace take effect immediately in this process and
+// within a minute elsewhere.
+type ContentBlocklist struct {
+	repo   social.BlockedPhraseRepository
+	ttl    time.Duration
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	phrases   []string
+	loaded    bool
+	expiresAt time.Time
+
+	// now is the time source (replaced in tests).
+	now func() time.Time
+}
+
+// NewContentBlocklist creates a new ContentBlocklist.
+func NewContentBlocklist(repo social.BlockedPhraseRepository, logger *slog.Logger) *ContentBlocklist {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ContentBlocklist{
+		repo:   repo,
+		ttl:    DefaultContentBlocklistTTL,
+		logger: logger.With("component", "content_blocklist"),
+		now:    time.Now,
+	}
+}
+
+// Phrases returns the snapshot of blocked phrases. The returned slice is
+// shared and must not be modified. If a reload fails, the previous snapshot
+// is kept until the next attempt.
+func (b *ContentBlocklist) Phrases(ctx context.Context) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.loaded && b.now().Before(b.expiresAt) {
+		return b.phrases
+	}
+
+	phrases, err := b.repo.List(ctx)
+	if err != nil {
+		b.logger.Warn("failed to reload content blocklist, using previous snapshot", "error", err)
+		b.expiresAt = b.now().Add(b.ttl)
+		return b.phrases
+	}
+
+	b.phrases = phrases
+	b.loaded = true
+	b.expiresAt = b.now().Add(b.ttl)
+	return phrases
+}
+
+// List returns the stored phrases.
+func (b *ContentBlocklist) List(ctx context.Context) ([]string, error) {
+	return b.repo.List(ctx)
+}
+
+// Replace validates, normalizes and stores the whole blocklist.
+func (b *ContentBlocklist) Replace(ctx context.Context, phrases []string) ([]string, error) {
+	normalized, err := social.NormalizeBlocklist(phrases)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.repo.Replace(ctx, normalized); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	b.phrases = normalized
+	b.loaded = true
+	b.expiresAt = b.now().Add(b.ttl)
+	b.mu.Unlock()
+
+	b.logger.Info("content blocklist updated", "phrases", len(normalized))
+	return normalized, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+)
+
+// memoryBlocklist is a social.BlockedPhraseRepository shared by "processes".
+type memoryBlocklist struct {
+	phrases []string
+	err     error
+	lists   int
+}
+
+func (r *memoryBlocklist) List(context.Context) ([]string, error) {
+	r.lists++
+	return r.phrases, r.err
+}
+
+func (r *memoryBlocklist) Replace(_ context.Context, phrases []string) error {
+	r.phrases = phrases
+	return nil
+}
+
+func TestContentBlocklist_HotReload(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	repo := &memoryBlocklist{}
+
+	admin := NewContentBlocklist(repo, nil)
+	other := NewContentBlocklist(repo, nil)
+	other.now = func() time.Time { return now }
+
+	assert.Empty(t, other.Phrases(ctx))
+
+	// The admin's process applies the change at once...
+	phrases, err := admin.Replace(ctx, []string{"Казино", "spam link", "казино"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"spam link", "казино"}, phrases)
+	assert.Equal(t, phrases, admin.Phrases(ctx))
+
+	// ...others within the TTL, without reloading on every render
+	assert.Empty(t, other.Phrases(ctx))
+	now = now.Add(DefaultContentBlocklistTTL)
+	assert.Equal(t, phrases, other.Phrases(ctx))
+	lists := repo.lists
+	other.Phrases(ctx)
+	assert.Equal(t, lists, repo.lists)
+
+	// A failed reload keeps the previous snapshot
+	repo.err = errors.New("db down")
+	now = now.Add(DefaultContentBlocklistTTL)
+	assert.Equal(t, phrases, other.Phrases(ctx))
+
+	_, err = admin.Replace(ctx, []string{"x"})
+	assert.ErrorIs(t, err, social.ErrInvalidBlocklist)
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// displayNameBatchSize caps the number of IDs loaded from the database per query.
+const displayNameBatchSize = 500
+
+// StudentsByIDsLoader loads students in bulk. Implemented by student.Repository.
+type StudentsByIDsLoader interface {
+	GetByIDs(ctx context.Context, ids []string) ([]*student.Student, error)
+}
+
+// DisplayNameResolver implements student.DisplayNameResolver.
+// Names are read from the cache; misses are loaded from the database in
+// batches and written back. The cache is kept fresh by the student repository,
+// which writes the new name through on update, so it never needs a full flush.
+//
+// Cache errors degrade to database reads, so a Redis outage only makes
+// lookups slower.
+type DisplayNameResolver struct {
+	cache    student.DisplayNameCache
+	students StudentsByIDsLoader
+	logger   *slog.Logger
+}
+
+// NewDisplayNameResolver creates a new DisplayNameResolver.
+// A nil cache makes every lookup go to the database.
+func NewDisplayNameResolver(cache student.DisplayNameCache, students StudentsByIDsLoader, logger *slog.Logger) *DisplayNameResolver {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &DisplayNameResolver{
+		cache:    cache,
+		students: students,
+		logger:   logger.With("component", "display_names"),
+	}
+}
+
+// Resolve returns display names of the given students.
+// Unknown students are left out of the result.
+func (r *DisplayNameResolver) Resolve(ctx context.Context, ids []string) map[string]string {
+	ids = uniqueIDs(ids)
+	names := make(map[string]string, len(ids))
+	if len(ids) == 0 {
+		return names
+	}
+
+	if r.cache != nil {
+		cached, err := r.cache.GetNames(ctx, ids)
+		if err != nil {
+			r.logger.Warn("failed to read cached display names", "error", err)
+		}
+		for id, name := range cached {
+			names[id] = name
+		}
+	}
+
+	var missing []string
+	for _, id := range ids {
+		if _, ok := names[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+
+	loaded := r.load(ctx, missing)
+	for id, name := range loaded {
+		names[id] = name
+	}
+
+	if r.cache != nil && len(loaded) > 0 {
+		if err := r.cache.SetNames(ctx, loaded); err != nil {
+			r.logger.Warn("failed to cache display names", "error", err)
+		}
+	}
+
+	return names
+}
+
+// Invalidate removes names from the cache, e.g. after a student is deleted.
+func (r *DisplayNameResolver) Invalidate(ctx context.Context, ids ...string) error {
+	if r.cache == nil || len(ids) == 0 {
+		return nil
+	}
+	return r.cache.DeleteNames(ctx, ids...)
+}
+
+// load reads names of the given students from the database in batches.
+func (r *DisplayNameResolver) load(ctx context.Context, ids []string) map[string]string {
+	names := make(map[string]string, len(ids))
+
+	for start := 0; start < len(ids); start += displayNameBatchSize {
+		end := min(start+displayNameBatchSize, len(ids))
+
+		students, err := r.students.GetByIDs(ctx, ids[start:end])
+		if err != nil {
+			r.logger.Warn("failed to load display names", "count", end-start, "error", err)
+			continue
+		}
+		for _, s := range students {
+			names[s.ID] = s.DisplayName
+		}
+	}
+
+	return names
+}
+
+// uniqueIDs drops empty and duplicate IDs, keeping the order.
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		result = append(result, id)
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type fakeNameCache struct {
+	names map[string]string
+}
+
+func (f *fakeNameCache) GetNames(_ context.Context, ids []string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, id := range ids {
+		if name, ok := f.names[id]; ok {
+			result[id] = name
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeNameCache) SetNames(_ context.Context, names map[string]string) error {
+	for id, name := range names {
+		f.names[id] = name
+	}
+	return nil
+}
+
+func (f *fakeNameCache) DeleteNames(_ context.Context, ids ...string) error {
+	for _, id := range ids {
+		delete(f.names, id)
+	}
+	return nil
+}
+
+// fakeNameStudents - база студентов; Rename ведёт себя как
+// StudentRepository.Update с подключённым кешем имён.
+type fakeNameStudents struct {
+	names  map[string]string
+	cache  student.DisplayNameCache
+	loaded []string
+}
+
+func (f *fakeNameStudents) GetByIDs(_ context.Context, ids []string) ([]*student.Student, error) {
+	f.loaded = append(f.loaded, ids...)
+	var result []*student.Student
+	for _, id := range ids {
+		if name, ok := f.names[id]; ok {
+			result = append(result, &student.Student{ID: id, DisplayName: name})
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeNameStudents) Rename(ctx context.Context, id, name string) {
+	f.names[id] = name
+	_ = f.cache.SetNames(ctx, map[string]string{id: name})
+}
+
+func TestDisplayNameResolver_RenamePropagatesWithoutFlush(t *testing.T) {
+	ctx := context.Background()
+	cache := &fakeNameCache{names: map[string]string{}}
+	students := &fakeNameStudents{
+		names: map[string]string{"dana": "Dana", "arman": "Arman"},
+		cache: cache,
+	}
+	resolver := NewDisplayNameResolver(cache, students, nil)
+
+	// Первый запрос читает базу одной пачкой и заполняет кеш
+	names := resolver.Resolve(ctx, []string{"dana", "arman", "dana", "ghost", ""})
+	assert.Equal(t, map[string]string{"dana": "Dana", "arman": "Arman"}, names)
+	assert.ElementsMatch(t, []string{"dana", "arman", "ghost"}, students.loaded)
+
+	// Переименование пишет новое имя в кеш, остальные записи не трогаются
+	students.Rename(ctx, "dana", "Dana K.")
+	students.loaded = nil
+
+	names = resolver.Resolve(ctx, []string{"dana", "arman"})
+	assert.Equal(t, map[string]string{"dana": "Dana K.", "arman": "Arman"}, names)
+	assert.Empty(t, students.loaded)
+
+	// После удаления имя перечитывается из базы
+	assert.NoError(t, resolver.Invalidate(ctx, "arman"))
+	resolver.Resolve(ctx, []string{"dana", "arman"})
+	assert.Equal(t, []string{"arman"}, students.loaded)
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
+)
+
+// ErrDryRunNotRecorded is returned when a send was suppressed in dry-run
+// mode but could not be written to the outbox. The message is never sent.
+var ErrDryRunNotRecorded = errors.New("dry run: message not recorded")
+
+// TelegramSender is every way the worker reaches Telegram.
+// Implemented by telegram.Client and DryRunGuard.
+type TelegramSender interface {
+	DeliveryChannel
+	SendHTML(ctx context.Context, chatID int64, html string) (*telegram.Message, error)
+	SendDocument(ctx context.Context, chatID int64, filename string, content []byte, caption string) (*telegram.Message, error)
+	SendPhoto(ctx context.Context, chatID int64, filename string, photo []byte, caption string) (*telegram.Message, error)
+}
+
+// DryRunGuard sits between the worker and the Telegram client. When the
+// policy puts the current run in dry-run mode, every send is recorded into
+// the outbox and counted on the run instead of reaching the client; the
+// client is only called in live mode, so a dry run can't leak a message
+// even if recording fails.
+type DryRunGuard struct {
+	next   TelegramSender
+	outbox notification.DryRunOutbox
+	policy notification.DryRunPolicy
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewDryRunGuard creates a new DryRunGuard around next.
+func NewDryRunGuard(next TelegramSender, outbox notification.DryRunOutbox, policy notification.DryRunPolicy, logger *slog.Logger) *DryRunGuard {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &DryRunGuard{
+		next:   next,
+		outbox: outbox,
+		policy: policy,
+		logger: logger.With("component", "dry_run_guard"),
+		now:    time.Now,
+	}
+}
+
+// Send implements DeliveryChannel.
+func (g *DryRunGuard) Send(ctx context.Context, notif *notification.Notification, opts notification.DeliveryOptions) notification.DeliveryResult {
+	if !g.policy.Enabled(ctx) {
+		return g.next.Send(ctx, notif, opts)
+	}
+
+	message := notif.Message
+	if opts.Photo != nil {
+		message = fmt.Sprintf("[%s, %d bytes] %s", opts.Photo.Filename, len(opts.Photo.Data), message)
+	}
+	err := g.record(ctx, notification.DryRunEntry{
+		RecipientID: string(notif.RecipientID),
+		ChatID:      int64(notif.TelegramChatID),
+		Type:        string(notif.Type),
+		Message:     message,
+	})
+	if err != nil {
+		return notification.NewFailureResult(notification.ChannelTypeTelegram, err, false)
+	}
+
+	return notification.NewSuccessResult(notification.ChannelTypeTelegram, "")
+}
+
+// SendHTML sends an HTML message, or records it in dry-run mode.
+func (g *DryRunGuard) SendHTML(ctx context.Context, chatID int64, html string) (*telegram.Message, error) {
+	if !g.policy.Enabled(ctx) {
+		return g.next.SendHTML(ctx, chatID, html)
+	}
+	return g.recordMessage(ctx, chatID, "html", html)
+}
+
+// SendDocument sends a file, or records its caption in dry-run mode.
+func (g *DryRunGuard) SendDocument(ctx context.Context, chatID int64, filename string, content []byte, caption string) (*telegram.Message, error) {
+	if !g.policy.Enabled(ctx) {
+		return g.next.SendDocument(ctx, chatID, filename, content, caption)
+	}
+	return g.recordMessage(ctx, chatID, "document", fmt.Sprintf("[%s, %d bytes] %s", filename, len(content), caption))
+}
+
+// SendPhoto sends a photo, or records its caption in dry-run mode.
+func (g *DryRunGuard) SendPhoto(ctx context.Context, chatID int64, filename string, photo []byte, caption string) (*telegram.Message, error) {
+	if !g.policy.Enabled(ctx) {
+		return g.next.SendPhoto(ctx, chatID, filename, photo, caption)
+	}
+	return g.recordMessage(ctx, chatID, "photo", fmt.Sprintf("[%s, %d bytes] %s", filename, len(photo), caption))
+}
+
+// recordMessage records a raw send and returns a stand-in message.
+func (g *DryRunGuard) recordMessage(ctx context.Context, chatID int64, kind, text string) (*telegram.Message, error) {
+	if err := g.record(ctx, notification.DryRunEntry{ChatID: chatID, Type: kind, Message: text}); err != nil {
+		return nil, err
+	}
+	return &telegram.Message{Chat: &telegram.Chat{ID: chatID}, Date: g.now().Unix()}, nil
+}
+
+// record writes the entry to the outbox and counts it on the current run.
+func (g *DryRunGuard) record(ctx context.Context, entry notification.DryRunEntry) error {
+	run := notification.DryRunFrom(ctx)
+	if run != nil {
+		entry.Job = run.Job
+	}
+	entry.CreatedAt = g.now()
+
+	if g.outbox == nil {
+		return ErrDryRunNotRecorded
+	}
+	if err := g.outbox.Record(ctx, entry); err != nil {
+		g.logger.Error("failed to record dry run message",
+			"job", entry.Job,
+			"type", entry.Type,
+			"error", err,
+		)
+		return fmt.Errorf("%w: %v", ErrDryRunNotRecorded, err)
+	}
+
+	run.CountRecorded()
+	return nil
+}
@@ -2,10 +2,19 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
 )
 
+// Cache warmup: the top of the global leaderboard, the same slice the
+// rebuild job caches.
+const (
+	warmupTopN = 100
+	warmupTTL  = 10 * time.Minute
+)
+
 // LeaderboardService provides high-level leaderboard operations.
 type LeaderboardService struct {
 	repo  leaderboard.LeaderboardRepository
@@ -20,31 +29,70 @@ func NewLeaderboardService(repo leaderboard.LeaderboardRepository, cache leaderb
 	}
 }
 
-// GetStudentRank returns the global rank of a student.
+// GetStudentRank returns the global rank of a student, across all cohorts.
+// A student missing from the leaderboard (e.g. new since the last rebuild)
+// yields leaderboard.ErrStudentNotRanked; other errors are lookup failures.
 func (s *LeaderboardService) GetStudentRank(ctx context.Context, studentID string) (int, error) {
-	// We use the global cohort for sync purposes
-	cohort := leaderboard.CohortAll
-
 	// 1. Try cache if available
 	if s.cache != nil {
-		entry, err := s.cache.GetCachedRank(ctx, studentID, cohort)
+		entry, err := s.cache.GetCachedRank(ctx, studentID, leaderboard.CohortAll)
 		if err == nil && entry != nil {
 			return int(entry.Rank), nil
 		}
 	}
 
 	// 2. Try repository
-	entry, err := s.repo.GetStudentRank(ctx, studentID, cohort)
+	entry, err := s.repo.GetGlobalRank(ctx, studentID)
 	if err != nil {
-		return 0, err
+		return 0, fmt.Errorf("failed to get global rank: %w", err)
 	}
 	if entry == nil {
-		return 0, nil // Rank unknown/unranked
+		return 0, leaderboard.ErrStudentNotRanked
 	}
 
 	return int(entry.Rank), nil
 }
 
+// GetStudentRanks returns the global ranks of several students.
+// Ranks come from the cache in one round trip; students missing from it are
+// looked up in the repository with a single batched query. Unranked students
+// are absent from the result.
+func (s *LeaderboardService) GetStudentRanks(ctx context.Context, studentIDs []string) (map[string]int, error) {
+	cohort := leaderboard.CohortAll
+	ranks := make(map[string]int, len(studentIDs))
+
+	// 1. Try cache if available
+	missing := studentIDs
+	if s.cache != nil {
+		cached, err := s.cache.GetCachedRanks(ctx, studentIDs, cohort)
+		if err == nil {
+			missing = make([]string, 0, len(studentIDs)-len(cached))
+			for _, id := range studentIDs {
+				if rank, ok := cached[id]; ok {
+					ranks[id] = int(rank)
+				} else {
+					missing = append(missing, id)
+				}
+			}
+		}
+	}
+
+	if len(missing) == 0 {
+		return ranks, nil
+	}
+
+	// 2. Fall back to repository for cache misses
+	entries, err := s.repo.GetStudentRanks(ctx, missing, cohort)
+	if err != nil {
+		return nil, err
+	}
+	for id, entry := range entries {
+		ranks[id] = int(entry.Rank)
+	}
+
+	return ranks, nil
+}
+
 // InvalidateCache invalidates the leaderboard cache.
 func (s *LeaderboardService) InvalidateCache(ctx context.Context) error {
 	if s.cache == nil {
@@ -52,3 +100,31 @@ func (s *LeaderboardService) InvalidateCache(ctx context.Context) error {
 	}
 	return s.cache.InvalidateAll(ctx)
 }
+
+// WarmCache replaces the cached global top with the latest snapshot from the
+// repository. It runs after the cache comes back from an outage, when
+// updates dropped meanwhile have left it stale.
+func (s *LeaderboardService) WarmCache(ctx context.Context) error {
+	if s.cache == nil {
+		return nil
+	}
+
+	cohort := leaderboard.CohortAll
+	entries, err := s.repo.GetTop(ctx, cohort, warmupTopN)
+	if err != nil {
+		return fmt.Errorf("failed to load leaderboard for warmup: %w", err)
+	}
+
+	if err := s.cache.InvalidateAll(ctx); err != nil {
+		return fmt.Errorf("failed to invalidate leaderboard cache: %w", err)
+	}
+	if err := s.cache.SetCachedTop(ctx, cohort, entries, warmupTTL); err != nil {
+		return fmt.Errorf("failed to cache leaderboard top: %w", err)
+	}
+	for _, entry := range entries {
+		if err := s.cache.SetCachedRank(ctx, entry, warmupTTL); err != nil {
+			return fmt.Errorf("failed to cache rank of %s: %w", entry.StudentID, err)
+		}
+	}
+	return nil
+}
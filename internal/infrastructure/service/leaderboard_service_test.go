@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+)
+
+// fakeRankRepo answers GetGlobalRank from fixed ranks. Which snapshot the
+// general rank comes from is tested with the postgres repository.
+type fakeRankRepo struct {
+	leaderboard.LeaderboardRepository
+	ranks map[string]leaderboard.Rank
+	err   error
+}
+
+func (f *fakeRankRepo) GetGlobalRank(_ context.Context, studentID string) (*leaderboard.LeaderboardEntry, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	rank, ok := f.ranks[studentID]
+	if !ok {
+		return nil, nil
+	}
+	return &leaderboard.LeaderboardEntry{StudentID: studentID, Rank: rank}, nil
+}
+
+func TestLeaderboardService_GetStudentRank(t *testing.T) {
+	repo := &fakeRankRepo{ranks: map[string]leaderboard.Rank{"aida": 1, "dana": 2, "arman": 3}}
+	s := NewLeaderboardService(repo, nil)
+	ctx := context.Background()
+
+	rank, err := s.GetStudentRank(ctx, "arman")
+	require.NoError(t, err)
+	assert.Equal(t, 3, rank)
+
+	rank, err = s.GetStudentRank(ctx, "aida")
+	require.NoError(t, err)
+	assert.Equal(t, 1, rank)
+
+	// A new student is not an error for the caller to log, just unranked
+	_, err = s.GetStudentRank(ctx, "newcomer")
+	assert.ErrorIs(t, err, leaderboard.ErrStudentNotRanked)
+
+	repo.err = errors.New("connection refused")
+	_, err = s.GetStudentRank(ctx, "arman")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, leaderboard.ErrStudentNotRanked)
+}
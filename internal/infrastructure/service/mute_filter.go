@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// MuteStudents is the part of student.Repository the MuteFilter needs.
+type MuteStudents interface {
+	GetByID(ctx context.Context, id string) (*student.Student, error)
+	SaveMutes(ctx context.Context, s *student.Student) error
+}
+
+// MuteFilter is the one place that applies the recipient's mutes (/mute)
+// and per-type preferences to outgoing notifications. A notification the
+// recipient does not accept is not sent and comes back as a skipped result
+// (DeliveryResult.Skipped). The first notification after a mute ends
+// carries student.MuteEndedNotice; the cleared mutes are saved once it is
+// delivered.
+//
+// Urgent notifications and recipients that are not students (curator
+// chats) pass through unchanged. If the recipient can't be loaded the
+// notification is sent: a mute is a courtesy, losing a message is worse.
+type MuteFilter struct {
+	channel  DeliveryChannel
+	students MuteStudents
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// NewMuteFilter creates a new MuteFilter around channel.
+func NewMuteFilter(channel DeliveryChannel, students MuteStudents, logger *slog.Logger) *MuteFilter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &MuteFilter{
+		channel:  channel,
+		students: students,
+		logger:   logger.With("component", "mute_filter"),
+		now:      time.Now,
+	}
+}
+
+// Send implements DeliveryChannel.
+func (f *MuteFilter) Send(ctx context.Context, notif *notification.Notification, opts notification.DeliveryOptions) notification.DeliveryResult {
+	if notif.Priority == notification.PriorityUrgent || !isStudentRecipient(notif.RecipientID) {
+		return f.channel.Send(ctx, notif, opts)
+	}
+
+	recipient, err := f.students.GetByID(ctx, string(notif.RecipientID))
+	if err != nil {
+		if !errors.Is(err, student.ErrStudentNotFound) {
+			f.logger.Warn("failed to load recipient, sending unfiltered",
+				"recipient_id", notif.RecipientID,
+				"error", err,
+			)
+		}
+		return f.channel.Send(ctx, notif, opts)
+	}
+
+	now := f.now()
+	notifType := string(notif.Type)
+	if recipient.IsMuted(student.MuteCategoryFor(notifType), now) {
+		return notification.NewSkippedResult(notification.ChannelTypeTelegram, notification.ErrRecipientMuted)
+	}
+	if !recipient.AcceptsNotification(notifType, now) {
+		return notification.NewSkippedResult(notification.ChannelTypeTelegram, notification.ErrRecipientOptedOut)
+	}
+
+	message, muteEnded := recipient.WithMuteEndedNotice(notif.Message, now)
+	if !muteEnded {
+		return f.channel.Send(ctx, notif, opts)
+	}
+
+	// The caller keeps its notification as built
+	withNotice := notif.Clone()
+	withNotice.Message = message
+	result := f.channel.Send(ctx, withNotice, opts)
+	if result.Success && !notification.SkipEffect(ctx, notification.DryRunEffectStatusUpdates) {
+		// The message is already delivered; a repeated notice is harmless
+		if err := f.students.SaveMutes(ctx, recipient); err != nil {
+			f.logger.Warn("failed to save ended mutes", "student_id", recipient.ID, "error", err)
+		}
+	}
+	return result
+}
+
+// isStudentRecipient reports whether the recipient is a student rather than
+// a group chat (settings.ChatScope, "chat:<id>").
+func isStudentRecipient(id notification.RecipientID) bool {
+	return id != "" && !strings.HasPrefix(string(id), "chat:")
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type fakeMuteStudents struct {
+	students map[string]*student.Student
+	saved    []string
+}
+
+func (f *fakeMuteStudents) GetByID(_ context.Context, id string) (*student.Student, error) {
+	s, ok := f.students[id]
+	if !ok {
+		return nil, student.ErrStudentNotFound
+	}
+	return s, nil
+}
+
+func (f *fakeMuteStudents) SaveMutes(_ context.Context, s *student.Student) error {
+	f.saved = append(f.saved, s.ID)
+	return nil
+}
+
+type recordingChannel struct {
+	sent []*notification.Notification
+}
+
+func (c *recordingChannel) Send(_ context.Context, notif *notification.Notification, _ notification.DeliveryOptions) notification.DeliveryResult {
+	c.sent = append(c.sent, notif)
+	return notification.NewSuccessResult(notification.ChannelTypeTelegram, "1")
+}
+
+func newMuteFilterFixture(now time.Time) (*MuteFilter, *recordingChannel, *fakeMuteStudents, *student.Student) {
+	dana := &student.Student{ID: "dana", Preferences: student.DefaultNotificationPreferences()}
+	students := &fakeMuteStudents{students: map[string]*student.Student{"dana": dana}}
+	channel := &recordingChannel{}
+
+	f := NewMuteFilter(channel, students, nil)
+	f.now = func() time.Time { return now }
+	return f, channel, students, dana
+}
+
+func TestMuteFilter_SkipsMutedAndOptedOutTypes(t *testing.T) {
+	now := time.Date(2026, time.May, 10, 12, 0, 0, 0, time.UTC)
+	f, channel, _, dana := newMuteFilterFixture(now)
+	dana.MuteCategory(student.MuteCategoryRank, time.Hour, now)
+	dana.Preferences.DailyDigest = false
+
+	rank := &notification.Notification{RecipientID: "dana", Type: notification.NotificationTypeEnteredTop, Priority: notification.PriorityNormal}
+	result := f.Send(context.Background(), rank, notification.DeliveryOptions{})
+	assert.True(t, result.Skipped())
+	assert.ErrorIs(t, result.Error, notification.ErrRecipientMuted)
+
+	digest := &notification.Notification{RecipientID: "dana", Type: notification.NotificationTypeDailyDigest, Priority: notification.PriorityLow}
+	result = f.Send(context.Background(), digest, notification.DeliveryOptions{})
+	assert.True(t, result.Skipped())
+	assert.ErrorIs(t, result.Error, notification.ErrRecipientOptedOut)
+
+	assert.Empty(t, channel.sent)
+
+	// Other categories and urgent notifications go through
+	goal := &notification.Notification{RecipientID: "dana", Type: notification.NotificationTypeGoalReached, Priority: notification.PriorityNormal}
+	assert.True(t, f.Send(context.Background(), goal, notification.DeliveryOptions{}).Success)
+	urgent := &notification.Notification{RecipientID: "dana", Type: notification.NotificationTypeRankDown, Priority: notification.PriorityUrgent}
+	assert.True(t, f.Send(context.Background(), urgent, notification.DeliveryOptions{}).Success)
+	assert.Len(t, channel.sent, 2)
+}
+
+func TestMuteFilter_FirstMessageAfterMuteCarriesNotice(t *testing.T) {
+	now := time.Date(2026, time.May, 10, 12, 0, 0, 0, time.UTC)
+	f, channel, students, dana := newMuteFilterFixture(now)
+	dana.Mute(time.Hour, now.Add(-2*time.Hour))
+
+	notif := &notification.Notification{RecipientID: "dana", Type: notification.NotificationTypeGoalReached, Message: "🎯 Цель недели взята!"}
+	require.True(t, f.Send(context.Background(), notif, notification.DeliveryOptions{}).Success)
+
+	require.Len(t, channel.sent, 1)
+	assert.Equal(t, student.MuteEndedNotice+"\n\n🎯 Цель недели взята!", channel.sent[0].Message)
+	assert.Equal(t, "🎯 Цель недели взята!", notif.Message, "the caller's notification is left as built")
+	assert.Equal(t, []string{"dana"}, students.saved)
+	assert.Nil(t, dana.MutedUntil)
+
+	// The next message no longer carries the notice
+	require.True(t, f.Send(context.Background(), notif, notification.DeliveryOptions{}).Success)
+	assert.Equal(t, "🎯 Цель недели взята!", channel.sent[1].Message)
+	assert.Len(t, students.saved, 1)
+}
+
+func TestMuteFilter_PassesNonStudentRecipients(t *testing.T) {
+	now := time.Date(2026, time.May, 10, 12, 0, 0, 0, time.UTC)
+	f, channel, _, _ := newMuteFilterFixture(now)
+
+	chat := &notification.Notification{RecipientID: "chat:-100", Type: notification.NotificationTypeSystemAlert}
+	assert.True(t, f.Send(context.Background(), chat, notification.DeliveryOptions{}).Success)
+	unknown := &notification.Notification{RecipientID: "arman", Type: notification.NotificationTypeRankUp}
+	assert.True(t, f.Send(context.Background(), unknown, notification.DeliveryOptions{}).Success)
+	assert.Len(t, channel.sent, 2)
+}
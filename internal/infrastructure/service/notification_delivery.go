@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+)
+
+// DeliveryChannel sends one notification with delivery options
+// (the Send part of notification.NotificationChannel).
+type DeliveryChannel interface {
+	Send(ctx context.Context, notif *notification.Notification, opts notification.DeliveryOptions) notification.DeliveryResult
+}
+
+// ChannelSender delivers notifications through a single channel with fixed options.
+// Used by processes that only talk to Telegram (the worker).
+type ChannelSender struct {
+	channel DeliveryChannel
+	opts    notification.DeliveryOptions
+}
+
+// NewChannelSender creates a new ChannelSender.
+func NewChannelSender(channel DeliveryChannel, opts notification.DeliveryOptions) *ChannelSender {
+	return &ChannelSender{channel: channel, opts: opts}
+}
+
+// Send delivers the notification through the channel.
+func (s *ChannelSender) Send(ctx context.Context, notif *notification.Notification) notification.DeliveryResult {
+	return s.channel.Send(ctx, notif, s.opts)
+}
+
+// SendWithKeyboard delivers the notification with an inline keyboard attached.
+func (s *ChannelSender) SendWithKeyboard(ctx context.Context, notif *notification.Notification, keyboard [][]notification.InlineButton) notification.DeliveryResult {
+	return s.channel.Send(ctx, notif, s.opts.WithInlineKeyboard(keyboard))
+}
+
+// SendWithPhoto delivers the notification as a photo with the message as its caption.
+func (s *ChannelSender) SendWithPhoto(ctx context.Context, notif *notification.Notification, filename string, photo []byte) notification.DeliveryResult {
+	return s.channel.Send(ctx, notif, s.opts.WithPhoto(filename, photo))
+}
+
+// InMemoryCooldownStore implements notification.CooldownStore in process memory.
+// Fallback when Redis is disabled: cooldowns reset on restart.
+type InMemoryCooldownStore struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+	now     func() time.Time
+}
+
+// NewInMemoryCooldownStore creates a new InMemoryCooldownStore.
+func NewInMemoryCooldownStore() *InMemoryCooldownStore {
+	return &InMemoryCooldownStore{
+		expires: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// Acquire takes the key for ttl. Returns false if it is already taken.
+func (s *InMemoryCooldownStore) Acquire(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if until, ok := s.expires[key]; ok && now.Before(until) {
+		return false, nil
+	}
+
+	// Drop expired keys so the map does not grow without bound
+	for k, until := range s.expires {
+		if !now.Before(until) {
+			delete(s.expires, k)
+		}
+	}
+
+	s.expires[key] = now.Add(ttl)
+	return true, nil
+}
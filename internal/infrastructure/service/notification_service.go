@@ -22,6 +22,21 @@ func (g *IDGeneratorImpl) GenerateID() string {
 // NotificationServiceStub implements NotificationService.
 type NotificationServiceStub struct {
 	logger *slog.Logger
+	rules  TriggerRuleSource
+	outbox NotificationOutbox
+}
+
+// NotificationOutbox stores scheduled notifications until the worker's
+// DeliverNotificationsJob sends them. Implemented by
+// postgres.NotificationRepository.
+type NotificationOutbox interface {
+	Save(ctx context.Context, n *notification.Notification) error
+	GetByID(ctx context.Context, id notification.NotificationID) (*notification.Notification, error)
+}
+
+// TriggerRuleSource returns the enabled trigger rules to evaluate.
+type TriggerRuleSource interface {
+	Enabled(ctx context.Context) ([]*notification.TriggerRule, error)
 }
 
 func NewNotificationServiceStub(logger *slog.Logger) *NotificationServiceStub {
@@ -30,19 +45,50 @@ func NewNotificationServiceStub(logger *slog.Logger) *NotificationServiceStub {
 	}
 }
 
+// WithTriggerRules sets the rules EvaluateTriggers reads (nil = no rules).
+func (s *NotificationServiceStub) WithTriggerRules(rules TriggerRuleSource) *NotificationServiceStub {
+	s.rules = rules
+	return s
+}
+
 func (s *NotificationServiceStub) CreateNotification(ctx context.Context, rule *notification.TriggerRule, triggerCtx *notification.TriggerContext) (*notification.Notification, error) {
 	s.logger.Info("stub: creating notification", "rule", rule.Name)
 	return &notification.Notification{}, nil
 }
 
+// WithOutbox makes ScheduleNotification store notifications for delivery
+// (nil = only log them).
+func (s *NotificationServiceStub) WithOutbox(outbox NotificationOutbox) *NotificationServiceStub {
+	s.outbox = outbox
+	return s
+}
+
+// ScheduleNotification stores the notification in the outbox as pending.
 func (s *NotificationServiceStub) ScheduleNotification(ctx context.Context, notif *notification.Notification) error {
-	s.logger.Info("stub: scheduling notification", "id", notif.ID, "recipient", notif.RecipientID)
-	return nil
+	if s.outbox == nil {
+		s.logger.Info("stub: scheduling notification", "id", notif.ID, "recipient", notif.RecipientID)
+		return nil
+	}
+	if notif.Status != notification.StatusPending {
+		return notification.ErrInvalidStatusTransition
+	}
+	return s.outbox.Save(ctx, notif)
 }
 
+// CancelNotification cancels a notification that has not been sent yet.
 func (s *NotificationServiceStub) CancelNotification(ctx context.Context, id notification.NotificationID) error {
-	s.logger.Info("stub: cancelling notification", "id", id)
-	return nil
+	if s.outbox == nil {
+		s.logger.Info("stub: cancelling notification", "id", id)
+		return nil
+	}
+	notif, err := s.outbox.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := notif.MarkCancelled(); err != nil {
+		return err
+	}
+	return s.outbox.Save(ctx, notif)
 }
 
 func (s *NotificationServiceStub) ProcessPendingNotifications(ctx context.Context, batchSize int) (processed int, err error) {
@@ -57,6 +103,21 @@ func (s *NotificationServiceStub) RetryFailedNotifications(ctx context.Context,
 	return 0, nil
 }
 
+// EvaluateTriggers returns the enabled rules that fire for the context.
 func (s *NotificationServiceStub) EvaluateTriggers(ctx context.Context, triggerCtx *notification.TriggerContext) ([]*notification.TriggerRule, error) {
-	return []*notification.TriggerRule{}, nil
+	matched := []*notification.TriggerRule{}
+	if s.rules == nil {
+		return matched, nil
+	}
+
+	rules, err := s.rules.Enabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.Evaluate(triggerCtx).ShouldTrigger {
+			matched = append(matched, rule)
+		}
+	}
+	return matched, nil
 }
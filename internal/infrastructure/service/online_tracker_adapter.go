@@ -98,6 +98,10 @@ func (a *StudentOnlineTrackerAdapter) GetOnlineStates(ctx context.Context, stude
 	}
 	result := make(map[string]student.OnlineState)
 	for id, state := range states {
+		if state == redis.StateUnknown {
+			// Cache unavailable: leave the student out rather than guess
+			continue
+		}
 		result[id] = redisStateToStudentState(state)
 	}
 	return result, nil
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+)
+
+// ChannelRateLimit is the sending budget of a delivery channel.
+type ChannelRateLimit struct {
+	// PerSecond is how many messages go out per second in total.
+	PerSecond float64
+
+	// PerChat is the minimum interval between two messages to the same chat.
+	PerChat time.Duration
+}
+
+// DefaultTelegramRateLimit returns the limits Telegram documents for bots:
+// 30 messages per second overall and one message per second per chat.
+func DefaultTelegramRateLimit() ChannelRateLimit {
+	return ChannelRateLimit{
+		PerSecond: 30,
+		PerChat:   time.Second,
+	}
+}
+
+// RateLimitedChannel delays sends so that they stay within a rate limit.
+// A message waits for the later of its global and per-chat slots; slots are
+// reserved in call order, so concurrent senders share the budget fairly.
+// A long message split into several parts still takes one slot.
+type RateLimitedChannel struct {
+	channel  DeliveryChannel
+	interval time.Duration
+	perChat  time.Duration
+
+	mu       sync.Mutex
+	next     time.Time
+	chatNext map[int64]time.Time
+	now      func() time.Time
+}
+
+// NewRateLimitedChannel creates a new RateLimitedChannel around channel.
+func NewRateLimitedChannel(channel DeliveryChannel, limit ChannelRateLimit) *RateLimitedChannel {
+	var interval time.Duration
+	if limit.PerSecond > 0 {
+		interval = time.Duration(float64(time.Second) / limit.PerSecond)
+	}
+
+	return &RateLimitedChannel{
+		channel:  channel,
+		interval: interval,
+		perChat:  limit.PerChat,
+		chatNext: make(map[int64]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Send implements DeliveryChannel. It waits for a free slot first; if the
+// context ends while waiting, the send fails as retryable.
+func (c *RateLimitedChannel) Send(ctx context.Context, notif *notification.Notification, opts notification.DeliveryOptions) notification.DeliveryResult {
+	if wait := c.reserve(int64(notif.TelegramChatID)); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return notification.NewFailureResult(notification.ChannelTypeTelegram, ctx.Err(), true)
+		case <-timer.C:
+		}
+	}
+	return c.channel.Send(ctx, notif, opts)
+}
+
+// reserve takes the next slot for the chat and returns how long to wait for it.
+func (c *RateLimitedChannel) reserve(chatID int64) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	at := now
+	if c.next.After(at) {
+		at = c.next
+	}
+	if next := c.chatNext[chatID]; next.After(at) {
+		at = next
+	}
+	c.next = at.Add(c.interval)
+
+	// Drop chats whose slot has passed so the map does not grow without bound
+	for id, next := range c.chatNext {
+		if !next.After(now) {
+			delete(c.chatNext, id)
+		}
+	}
+	if c.perChat > 0 {
+		c.chatNext[chatID] = at.Add(c.perChat)
+	}
+
+	return at.Sub(now)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+)
+
+type countingChannel struct {
+	sent int
+}
+
+func (c *countingChannel) Send(context.Context, *notification.Notification, notification.DeliveryOptions) notification.DeliveryResult {
+	c.sent++
+	return notification.NewSuccessResult(notification.ChannelTypeTelegram, "1")
+}
+
+func TestRateLimitedChannel_ReservesGlobalAndPerChatSlots(t *testing.T) {
+	c := NewRateLimitedChannel(&countingChannel{}, ChannelRateLimit{PerSecond: 10, PerChat: time.Second})
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	// Different chats are spaced by the global interval
+	assert.Equal(t, time.Duration(0), c.reserve(1))
+	assert.Equal(t, 100*time.Millisecond, c.reserve(2))
+	assert.Equal(t, 200*time.Millisecond, c.reserve(3))
+
+	// The same chat waits a second after its previous message
+	assert.Equal(t, time.Second, c.reserve(1))
+
+	// Once the slots have passed, nothing waits
+	now = now.Add(5 * time.Second)
+	assert.Equal(t, time.Duration(0), c.reserve(1))
+	assert.Len(t, c.chatNext, 1, "passed chats are forgotten")
+}
+
+func TestRateLimitedChannel_CancelledWaitIsRetryable(t *testing.T) {
+	channel := &countingChannel{}
+	c := NewRateLimitedChannel(channel, ChannelRateLimit{PerChat: time.Hour})
+	notif := &notification.Notification{TelegramChatID: 1}
+
+	assert.True(t, c.Send(context.Background(), notif, notification.DeliveryOptions{}).Success)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := c.Send(ctx, notif, notification.DeliveryOptions{})
+	assert.False(t, result.Success)
+	assert.True(t, result.Retryable)
+	assert.Equal(t, 1, channel.sent)
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+)
+
+// DefaultTriggerRulesTTL is how long the enabled rule snapshot is cached.
+const DefaultTriggerRulesTTL = time.Minute
+
+// TriggerRules provides cached access to notification trigger rules.
+// Evaluation reads an in-memory snapshot of the enabled rules that is
+// reloaded from the repository once it is older than the TTL; admin changes
+// made through this type take effect immediately, changes made elsewhere
+// within a minute.
+type TriggerRules struct {
+	repo   notification.TriggerRuleRepository
+	ttl    time.Duration
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	enabled   []*notification.TriggerRule
+	expiresAt time.Time
+
+	// now is the time source (replaced in tests).
+	now func() time.Time
+}
+
+// NewTriggerRules creates a new TriggerRules.
+func NewTriggerRules(repo notification.TriggerRuleRepository, logger *slog.Logger) *TriggerRules {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &TriggerRules{
+		repo:   repo,
+		ttl:    DefaultTriggerRulesTTL,
+		logger: logger.With("component", "trigger_rules"),
+		now:    time.Now,
+	}
+}
+
+// Seed stores the built-in rules when no rules exist yet. Existing rules are
+// never overwritten, so tuned thresholds survive restarts.
+func (s *TriggerRules) Seed(ctx context.Context) error {
+	rules, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list trigger rules: %w", err)
+	}
+	if len(rules) > 0 {
+		return nil
+	}
+
+	for _, rule := range notification.BuiltinTriggerRules() {
+		if err := s.repo.Save(ctx, rule); err != nil {
+			return fmt.Errorf("failed to seed trigger rule %s: %w", rule.ID, err)
+		}
+	}
+	s.invalidate()
+
+	s.logger.Info("seeded built-in trigger rules", "count", len(notification.BuiltinTriggerRules()))
+	return nil
+}
+
+// Enabled returns the snapshot of enabled rules. The returned rules are
+// shared and must not be modified. If a reload fails, the previous snapshot
+// is kept until the next attempt.
+func (s *TriggerRules) Enabled(ctx context.Context) ([]*notification.TriggerRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.enabled != nil && s.now().Before(s.expiresAt) {
+		return s.enabled, nil
+	}
+
+	rules, err := s.repo.GetEnabled(ctx)
+	if err != nil {
+		if s.enabled != nil {
+			s.logger.Warn("failed to reload trigger rules, using previous snapshot", "error", err)
+			s.expiresAt = s.now().Add(s.ttl)
+			return s.enabled, nil
+		}
+		return nil, err
+	}
+
+	s.enabled = rules
+	s.expiresAt = s.now().Add(s.ttl)
+	return rules, nil
+}
+
+// List returns all rules, enabled or not.
+func (s *TriggerRules) List(ctx context.Context) ([]*notification.TriggerRule, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// Get returns a rule.
+func (s *TriggerRules) Get(ctx context.Context, id notification.TriggerRuleID) (*notification.TriggerRule, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// Update validates and replaces an existing rule, keeping its creation time.
+func (s *TriggerRules) Update(ctx context.Context, rule *notification.TriggerRule) (*notification.TriggerRule, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetByID(ctx, rule.ID)
+	if err != nil {
+		return nil, err
+	}
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = s.now().UTC()
+
+	if err := s.repo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return rule, nil
+}
+
+// SetEnabled turns a rule on or off.
+func (s *TriggerRules) SetEnabled(ctx context.Context, id notification.TriggerRuleID, enabled bool) error {
+	var err error
+	if enabled {
+		err = s.repo.Enable(ctx, id)
+	} else {
+		err = s.repo.Disable(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// invalidate drops the cached snapshot.
+func (s *TriggerRules) invalidate() {
+	s.mu.Lock()
+	s.enabled = nil
+	s.mu.Unlock()
+}
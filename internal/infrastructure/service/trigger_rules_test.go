@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+)
+
+// fakeTriggerRuleRepo keeps rules like the trigger_rules table and counts
+// GetEnabled loads.
+type fakeTriggerRuleRepo struct {
+	notification.TriggerRuleRepository
+	rules map[notification.TriggerRuleID]*notification.TriggerRule
+	err   error
+	loads int
+}
+
+func (f *fakeTriggerRuleRepo) Save(_ context.Context, rule *notification.TriggerRule) error {
+	f.rules[rule.ID] = rule.Clone()
+	return nil
+}
+
+func (f *fakeTriggerRuleRepo) GetAll(context.Context) ([]*notification.TriggerRule, error) {
+	all := make([]*notification.TriggerRule, 0, len(f.rules))
+	for _, rule := range f.rules {
+		all = append(all, rule.Clone())
+	}
+	return all, nil
+}
+
+func (f *fakeTriggerRuleRepo) GetEnabled(context.Context) ([]*notification.TriggerRule, error) {
+	f.loads++
+	if f.err != nil {
+		return nil, f.err
+	}
+	var enabled []*notification.TriggerRule
+	for _, rule := range f.rules {
+		if rule.IsEnabled {
+			enabled = append(enabled, rule.Clone())
+		}
+	}
+	return enabled, nil
+}
+
+func (f *fakeTriggerRuleRepo) GetByID(_ context.Context, id notification.TriggerRuleID) (*notification.TriggerRule, error) {
+	rule, ok := f.rules[id]
+	if !ok {
+		return nil, notification.ErrTriggerRuleNotFound
+	}
+	return rule.Clone(), nil
+}
+
+func (f *fakeTriggerRuleRepo) Update(ctx context.Context, rule *notification.TriggerRule) error {
+	return f.Save(ctx, rule)
+}
+
+func (f *fakeTriggerRuleRepo) Enable(_ context.Context, id notification.TriggerRuleID) error {
+	f.rules[id].Enable()
+	return nil
+}
+
+func (f *fakeTriggerRuleRepo) Disable(_ context.Context, id notification.TriggerRuleID) error {
+	f.rules[id].Disable()
+	return nil
+}
+
+func newSeededTriggerRules(t *testing.T, now *time.Time) (*TriggerRules, *fakeTriggerRuleRepo) {
+	repo := &fakeTriggerRuleRepo{rules: make(map[notification.TriggerRuleID]*notification.TriggerRule)}
+	rules := NewTriggerRules(repo, nil)
+	rules.now = func() time.Time { return *now }
+	require.NoError(t, rules.Seed(context.Background()))
+	return rules, repo
+}
+
+func TestTriggerRules_CacheExpiryAndRefresh(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 12, 0, 0, 0, time.UTC)
+	rules, repo := newSeededTriggerRules(t, &now)
+	ctx := context.Background()
+	builtin := len(notification.BuiltinTriggerRules())
+
+	enabled, err := rules.Enabled(ctx)
+	require.NoError(t, err)
+	assert.Len(t, enabled, builtin)
+
+	// A change made by another process is picked up once the snapshot expires
+	require.NoError(t, repo.Disable(ctx, notification.BuiltinDailyDigestRuleID))
+	now = now.Add(DefaultTriggerRulesTTL - time.Second)
+	enabled, err = rules.Enabled(ctx)
+	require.NoError(t, err)
+	assert.Len(t, enabled, builtin)
+	assert.Equal(t, 1, repo.loads)
+
+	now = now.Add(time.Second)
+	enabled, err = rules.Enabled(ctx)
+	require.NoError(t, err)
+	assert.Len(t, enabled, builtin-1)
+	assert.Equal(t, 2, repo.loads)
+
+	// A failed reload keeps the previous snapshot until the next attempt
+	repo.err = errors.New("connection refused")
+	require.NoError(t, repo.Enable(ctx, notification.BuiltinDailyDigestRuleID))
+	now = now.Add(DefaultTriggerRulesTTL)
+	enabled, err = rules.Enabled(ctx)
+	require.NoError(t, err)
+	assert.Len(t, enabled, builtin-1)
+	assert.Equal(t, 3, repo.loads)
+
+	_, err = rules.Enabled(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, repo.loads, "the retry waits for the TTL")
+
+	repo.err = nil
+	now = now.Add(DefaultTriggerRulesTTL)
+	enabled, err = rules.Enabled(ctx)
+	require.NoError(t, err)
+	assert.Len(t, enabled, builtin)
+}
+
+func TestTriggerRules_ChangesApplyImmediately(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 12, 0, 0, 0, time.UTC)
+	rules, _ := newSeededTriggerRules(t, &now)
+	svc := NewNotificationServiceStub(slog.Default()).WithTriggerRules(rules)
+	ctx := context.Background()
+
+	rankUp := func(change int) []*notification.TriggerRule {
+		triggerCtx := notification.NewTriggerContext("dana", 42)
+		triggerCtx.SetValue(notification.ConditionTypeRankChange, change)
+		triggerCtx.SetUserPreference("rank_changes", true)
+		matched, err := svc.EvaluateTriggers(ctx, triggerCtx)
+		require.NoError(t, err)
+		return matched
+	}
+
+	require.Len(t, rankUp(5), 1)
+
+	// A raised threshold applies without waiting for the TTL
+	rule, err := rules.Get(ctx, notification.BuiltinRankUpRuleID)
+	require.NoError(t, err)
+	rule.Conditions[0].Value = 10
+	_, err = rules.Update(ctx, rule)
+	require.NoError(t, err)
+	assert.Empty(t, rankUp(5))
+	assert.Len(t, rankUp(10), 1)
+
+	require.NoError(t, rules.SetEnabled(ctx, notification.BuiltinRankUpRuleID, false))
+	assert.Empty(t, rankUp(10))
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/domain/webhook"
+	"github.com/alem-hub/alem-community-hub/pkg/httpclient"
+)
+
+// Headers sent with every webhook delivery.
+const (
+	WebhookSignatureHeader = "X-Hub-Signature-256"
+	WebhookEventHeader     = "X-Hub-Event"
+	WebhookDeliveryHeader  = "X-Hub-Delivery"
+)
+
+// webhookEvents maps domain events to the public webhook event names.
+var webhookEvents = map[shared.EventType]webhook.EventType{
+	shared.EventHelpRequested:       webhook.EventHelpRequestCreated,
+	shared.EventHelperAssigned:      webhook.EventHelperAssigned,
+	shared.EventHelpRequestResolved: webhook.EventHelpRequestResolved,
+}
+
+// HelpRequestLoader loads a help request. Implemented by social.HelpRequestRepository.
+type HelpRequestLoader interface {
+	GetByID(ctx context.Context, id string) (*social.HelpRequest, error)
+}
+
+// WebhookDispatcherConfig contains configuration for WebhookDispatcher.
+type WebhookDispatcherConfig struct {
+	// Attempts is how many times a delivery is tried.
+	Attempts int
+
+	// Backoff is the delay before the second attempt; it doubles after each failure.
+	Backoff time.Duration
+
+	// Timeout bounds the whole delivery of one event, including retries.
+	Timeout time.Duration
+}
+
+// DefaultWebhookDispatcherConfig returns sensible defaults.
+func DefaultWebhookDispatcherConfig() WebhookDispatcherConfig {
+	return WebhookDispatcherConfig{
+		Attempts: 3,
+		Backoff:  2 * time.Second,
+		Timeout:  time.Minute,
+	}
+}
+
+// WebhookDispatcher mirrors the help request lifecycle to external
+// integrations. It is subscribed to the event bus; every matching active
+// subscription receives a JSON payload signed with HMAC-SHA256 of the body
+// (see SignWebhookPayload). Failed deliveries are retried with exponential
+// backoff, and a subscription is deactivated after
+// webhook.MaxConsecutiveFailures failed deliveries in a row.
+type WebhookDispatcher struct {
+	subs     webhook.Repository
+	requests HelpRequestLoader
+	students StudentsByIDsLoader
+	client   *httpclient.Client
+	logger   *slog.Logger
+	config   WebhookDispatcherConfig
+
+	// sleep waits between attempts (replaced in tests).
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewWebhookDispatcher creates a new WebhookDispatcher.
+func NewWebhookDispatcher(
+	subs webhook.Repository,
+	requests HelpRequestLoader,
+	students StudentsByIDsLoader,
+	client *httpclient.Client,
+	logger *slog.Logger,
+	config WebhookDispatcherConfig,
+) *WebhookDispatcher {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if client == nil {
+		client = httpclient.New(httpclient.DefaultConfig("webhooks"))
+	}
+	if config.Attempts <= 0 {
+		config.Attempts = 1
+	}
+
+	return &WebhookDispatcher{
+		subs:     subs,
+		requests: requests,
+		students: students,
+		client:   client,
+		logger:   logger.With("component", "webhook_dispatcher"),
+		config:   config,
+		sleep:    sleepContext,
+	}
+}
+
+// Subscribe registers the dispatcher for all help request lifecycle events.
+func (d *WebhookDispatcher) Subscribe(bus shared.EventSubscriber) error {
+	for eventType := range webhookEvents {
+		if err := bus.Subscribe(eventType, d.Handle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WebhookPayload is the JSON body of a delivery.
+type WebhookPayload struct {
+	ID         string            `json:"id"`
+	Event      webhook.EventType `json:"event"`
+	OccurredAt time.Time         `json:"occurred_at"`
+	Data       WebhookHelpData   `json:"data"`
+}
+
+// WebhookHelpData describes the help request in a payload.
+type WebhookHelpData struct {
+	RequestID string             `json:"request_id"`
+	TaskID    string             `json:"task_id"`
+	TaskName  string             `json:"task_name,omitempty"`
+	Message   string             `json:"message,omitempty"`
+	Status    string             `json:"status,omitempty"`
+	Cohort    string             `json:"cohort,omitempty"`
+	Requester *WebhookStudentRef `json:"requester,omitempty"`
+	Helper    *WebhookStudentRef `json:"helper,omitempty"`
+}
+
+// WebhookStudentRef identifies a student in a payload.
+type WebhookStudentRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// Handle delivers one domain event to the matching subscriptions.
+// Delivery problems are logged and recorded per subscription, never returned,
+// so one broken integration does not show up as an event bus failure.
+func (d *WebhookDispatcher) Handle(event shared.Event) error {
+	eventType, ok := webhookEvents[event.EventType()]
+	if !ok {
+		return nil
+	}
+
+	ctx := context.Background()
+	if d.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.config.Timeout)
+		defer cancel()
+	}
+
+	subs, err := d.subs.ListActive(ctx)
+	if err != nil {
+		d.logger.Error("failed to list webhook subscriptions", "error", err)
+		return nil
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	payload := d.buildPayload(ctx, eventType, event)
+
+	var wg sync.WaitGroup
+	for _, sub := range subs {
+		if !sub.Matches(eventType, payload.Data.Cohort) {
+			continue
+		}
+		wg.Add(1)
+		go func(sub *webhook.Subscription) {
+			defer wg.Done()
+			d.deliver(ctx, sub, payload)
+		}(sub)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// buildPayload fills the payload from the event, enriched with the request
+// and the students' names and cohort when they can be loaded.
+func (d *WebhookDispatcher) buildPayload(ctx context.Context, eventType webhook.EventType, event shared.Event) WebhookPayload {
+	payload := WebhookPayload{
+		ID:         uuid.New().String(),
+		Event:      eventType,
+		OccurredAt: event.OccurredAt().UTC(),
+	}
+	data := &payload.Data
+
+	var requesterID, helperID string
+	switch e := event.(type) {
+	case shared.HelpRequestedEvent:
+		data.RequestID, data.TaskID, data.Message = e.RequestID, e.TaskID, e.Message
+		requesterID = e.StudentID
+	case shared.HelperAssignedEvent:
+		data.RequestID, data.TaskID = e.RequestID, e.TaskID
+		requesterID, helperID = e.RequesterID, e.HelperID
+	case shared.HelpRequestResolvedEvent:
+		data.RequestID, data.TaskID = e.RequestID, e.TaskID
+		requesterID, helperID = e.RequesterID, e.HelperID
+	}
+
+	if data.RequestID != "" && d.requests != nil {
+		if req, err := d.requests.GetByID(ctx, data.RequestID); err == nil {
+			data.TaskName = req.TaskName
+			data.Status = string(req.Status)
+		} else {
+			d.logger.Warn("failed to load help request for webhook", "request_id", data.RequestID, "error", err)
+		}
+	}
+
+	ids := []string{requesterID}
+	if helperID != "" {
+		ids = append(ids, helperID)
+	}
+	byID := make(map[string]*student.Student, len(ids))
+	if d.students != nil {
+		if students, err := d.students.GetByIDs(ctx, ids); err == nil {
+			for _, s := range students {
+				byID[s.ID] = s
+			}
+		} else {
+			d.logger.Warn("failed to load students for webhook", "error", err)
+		}
+	}
+
+	ref := func(id string) *WebhookStudentRef {
+		r := &WebhookStudentRef{ID: id}
+		if s, ok := byID[id]; ok {
+			r.Name = s.DisplayName
+		}
+		return r
+	}
+	data.Requester = ref(requesterID)
+	if helperID != "" {
+		data.Helper = ref(helperID)
+	}
+	if s, ok := byID[requesterID]; ok {
+		data.Cohort = string(s.Cohort)
+	}
+
+	return payload
+}
+
+// deliver posts the payload to one subscription with retries and records
+// the outcome.
+func (d *WebhookDispatcher) deliver(ctx context.Context, sub *webhook.Subscription, payload WebhookPayload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		d.logger.Error("failed to encode webhook payload", "error", err)
+		return
+	}
+
+	var delivery webhook.Delivery
+	backoff := d.config.Backoff
+	for attempt := 1; attempt <= d.config.Attempts; attempt++ {
+		var retry bool
+		delivery, retry = d.post(ctx, sub, payload, body)
+		if delivery.Succeeded() || !retry || attempt == d.config.Attempts {
+			break
+		}
+		if err := d.sleep(ctx, backoff); err != nil {
+			delivery.Error = err.Error()
+			break
+		}
+		backoff *= 2
+	}
+	delivery.At = time.Now()
+
+	// The event context may be spent by now; the outcome must still be saved
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	deactivated, err := d.subs.RecordDelivery(recordCtx, sub.ID, delivery)
+	if err != nil {
+		d.logger.Error("failed to record webhook delivery", "subscription_id", sub.ID, "error", err)
+		return
+	}
+
+	if !delivery.Succeeded() {
+		d.logger.Warn("webhook delivery failed",
+			"subscription_id", sub.ID,
+			"event", payload.Event,
+			"status", delivery.Status,
+			"error", delivery.Error,
+		)
+	}
+	if deactivated {
+		d.logger.Warn("webhook subscription deactivated after repeated failures",
+			"subscription_id", sub.ID,
+			"failures", webhook.MaxConsecutiveFailures,
+		)
+	}
+}
+
+// post makes one delivery attempt. retry reports whether a failure is
+// worth another attempt: network errors, 5xx, 408 and 429 are.
+func (d *WebhookDispatcher) post(ctx context.Context, sub *webhook.Subscription, payload WebhookPayload, body []byte) (delivery webhook.Delivery, retry bool) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return webhook.Delivery{Error: err.Error()}, false
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "alem-community-hub-webhooks")
+	req.Header.Set(WebhookEventHeader, string(payload.Event))
+	req.Header.Set(WebhookDeliveryHeader, payload.ID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(sub.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return webhook.Delivery{Error: err.Error()}, true
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	delivery.Status = resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return delivery, false
+	}
+
+	delivery.Error = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	retry = resp.StatusCode >= 500 ||
+		resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests
+	return delivery, retry
+}
+
+// SignWebhookPayload returns the signature header value for a body:
+// "sha256=" followed by the hex HMAC-SHA256 of the body keyed with secret.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks a signature header in constant time.
+// Receivers can use it (or its equivalent) to authenticate deliveries.
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhookPayload(secret, body)), []byte(signature))
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
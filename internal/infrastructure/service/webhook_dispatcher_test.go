@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/domain/webhook"
+)
+
+const testWebhookSecret = "0123456789abcdef0123"
+
+// fakeWebhookRepo - подписки в памяти; RecordDelivery повторяет SQL-логику.
+type fakeWebhookRepo struct {
+	webhook.Repository
+
+	mu   sync.Mutex
+	subs []*webhook.Subscription
+}
+
+func (f *fakeWebhookRepo) ListActive(_ context.Context) ([]*webhook.Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var result []*webhook.Subscription
+	for _, s := range f.subs {
+		if s.Active {
+			copied := *s
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeWebhookRepo) RecordDelivery(_ context.Context, id string, d webhook.Delivery) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, s := range f.subs {
+		if s.ID != id {
+			continue
+		}
+		s.LastStatus, s.LastError = d.Status, d.Error
+		if d.Succeeded() {
+			s.ConsecutiveFailures = 0
+			return false, nil
+		}
+		s.ConsecutiveFailures++
+		if s.Active && s.ConsecutiveFailures >= webhook.MaxConsecutiveFailures {
+			s.Active = false
+			return true, nil
+		}
+		return false, nil
+	}
+	return false, webhook.ErrSubscriptionNotFound
+}
+
+func newTestDispatcher(t *testing.T, subs ...*webhook.Subscription) (*WebhookDispatcher, *fakeWebhookRepo) {
+	t.Helper()
+
+	repo := &fakeWebhookRepo{subs: subs}
+	students := &fakeNameStudents{names: map[string]string{"s1": "Aigerim", "s2": "Daniyar"}}
+	d := NewWebhookDispatcher(repo, nil, cohortStudents{students}, nil, nil, DefaultWebhookDispatcherConfig())
+	d.sleep = func(context.Context, time.Duration) error { return nil }
+	return d, repo
+}
+
+// cohortStudents добавляет студентам поток.
+type cohortStudents struct {
+	*fakeNameStudents
+}
+
+func (c cohortStudents) GetByIDs(ctx context.Context, ids []string) ([]*student.Student, error) {
+	students, err := c.fakeNameStudents.GetByIDs(ctx, ids)
+	for _, s := range students {
+		s.Cohort = "2025-spring"
+	}
+	return students, err
+}
+
+func testSubscription(t *testing.T, id, url, cohort string) *webhook.Subscription {
+	t.Helper()
+
+	s, err := webhook.NewSubscription(id, url, testWebhookSecret, webhook.EventTypes(), cohort, time.Now())
+	require.NoError(t, err)
+	return s
+}
+
+func TestWebhookDispatcher_SignsPayload(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []WebhookPayload
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !VerifyWebhookSignature(testWebhookSecret, body, r.Header.Get(WebhookSignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var payload WebhookPayload
+		require.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, string(payload.Event), r.Header.Get(WebhookEventHeader))
+
+		mu.Lock()
+		received = append(received, payload)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	d, repo := newTestDispatcher(t,
+		testSubscription(t, "all", server.URL, ""),
+		testSubscription(t, "same-cohort", server.URL, "2025-spring"),
+		testSubscription(t, "other-cohort", server.URL, "2024-fall"),
+	)
+
+	event := shared.NewHelperAssignedEvent("r1", "s1", "s2", "go-reloaded")
+	require.NoError(t, d.Handle(event))
+
+	require.Len(t, received, 2, "other cohort must be skipped")
+	data := received[0].Data
+	assert.Equal(t, webhook.EventHelperAssigned, received[0].Event)
+	assert.Equal(t, "r1", data.RequestID)
+	assert.Equal(t, "2025-spring", data.Cohort)
+	assert.Equal(t, &WebhookStudentRef{ID: "s1", Name: "Aigerim"}, data.Requester)
+	assert.Equal(t, &WebhookStudentRef{ID: "s2", Name: "Daniyar"}, data.Helper)
+
+	for _, s := range repo.subs[:2] {
+		assert.Equal(t, http.StatusOK, s.LastStatus)
+		assert.Empty(t, s.LastError)
+	}
+
+	assert.False(t, VerifyWebhookSignature("another-secret-value", []byte("{}"), SignWebhookPayload(testWebhookSecret, []byte("{}"))))
+}
+
+func TestWebhookDispatcher_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	sub := testSubscription(t, "w1", server.URL, "")
+	sub.ConsecutiveFailures = 5
+	d, _ := newTestDispatcher(t, sub)
+
+	require.NoError(t, d.Handle(shared.NewHelpRequestedEvent("s1", "go-reloaded", "stuck")))
+
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, http.StatusOK, sub.LastStatus)
+	assert.Zero(t, sub.ConsecutiveFailures)
+}
+
+func TestWebhookDispatcher_ClientErrorsAreNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	sub := testSubscription(t, "w1", server.URL, "")
+	d, _ := newTestDispatcher(t, sub)
+
+	require.NoError(t, d.Handle(shared.NewHelpRequestResolvedEvent("r1", "s1", "", "go-reloaded")))
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, http.StatusGone, sub.LastStatus)
+	assert.Equal(t, 1, sub.ConsecutiveFailures)
+}
+
+func TestWebhookDispatcher_DeactivatesAfterRepeatedFailures(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sub := testSubscription(t, "w1", server.URL, "")
+	sub.ConsecutiveFailures = webhook.MaxConsecutiveFailures - 1
+	d, _ := newTestDispatcher(t, sub)
+
+	require.NoError(t, d.Handle(shared.NewHelpRequestedEvent("s1", "go-reloaded", "")))
+	assert.False(t, sub.Active)
+	assert.Equal(t, webhook.MaxConsecutiveFailures, sub.ConsecutiveFailures)
+	assert.Contains(t, sub.LastError, "500")
+
+	// Отключённая подписка больше не получает событий
+	calls.Store(0)
+	require.NoError(t, d.Handle(shared.NewHelpRequestedEvent("s1", "go-reloaded", "")))
+	assert.Zero(t, calls.Load())
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/logger"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PERSONAL ACCESS TOKENS
+// Students create read-only tokens with /token create in the bot. A token is
+// sent as "Authorization: Bearer ach_..." and only grants the "self" routes
+// under /api/v1/me, answered for the token owner. On the public API a token
+// never widens access: a request for another student's data is rejected
+// with 403.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// AccessTokenResolver resolves a token secret to an active token.
+// It returns student.ErrAccessTokenNotFound for unknown, revoked and expired tokens.
+type AccessTokenResolver interface {
+	Resolve(ctx context.Context, secret string) (*student.AccessToken, error)
+}
+
+// contextKeyAccessToken holds the resolved *student.AccessToken.
+const contextKeyAccessToken contextKey = "access_token"
+
+// bearerToken returns the bearer credentials of the request, if any.
+func bearerToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+}
+
+// accessTokenFrom returns the token resolved by selfAuthMiddleware.
+func accessTokenFrom(ctx context.Context) *student.AccessToken {
+	token, _ := ctx.Value(contextKeyAccessToken).(*student.AccessToken)
+	return token
+}
+
+// resolveAccessToken resolves the token and writes the error response when
+// it cannot be used. It returns nil in that case.
+func (s *Server) resolveAccessToken(w http.ResponseWriter, r *http.Request, secret string) *student.AccessToken {
+	token, err := s.deps.Self.Tokens.Resolve(r.Context(), secret)
+	if err != nil {
+		if errors.Is(err, student.ErrAccessTokenNotFound) {
+			writeJSONError(w, http.StatusUnauthorized, "invalid_token", "Access token is invalid, revoked or expired")
+			return nil
+		}
+		s.logger.Error("failed to resolve access token", logger.Err(err))
+		writeJSONError(w, http.StatusServiceUnavailable, "unavailable", "Failed to check access token")
+		return nil
+	}
+	if token.Scope != student.AccessTokenScopeSelfRead {
+		writeJSONError(w, http.StatusForbidden, "forbidden", "Access token does not allow this request")
+		return nil
+	}
+	return token
+}
+
+// selfAuthMiddleware requires a personal access token, applies the per-token
+// rate limit and stores the token in the request context.
+func (s *Server) selfAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.deps.Self.Tokens == nil {
+			writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Access tokens are not configured")
+			return
+		}
+
+		secret := bearerToken(r)
+		if secret == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSONError(w, http.StatusUnauthorized, "missing_token", "Access token is required")
+			return
+		}
+
+		token := s.resolveAccessToken(w, r, secret)
+		if token == nil {
+			return
+		}
+
+		if s.tokenLimiter != nil && !s.tokenLimiter.Allow(token.ID) {
+			w.Header().Set("Retry-After", "60")
+			writeJSONError(w, http.StatusTooManyRequests, "rate_limit_exceeded", "Too many requests with this token, please try again later")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), contextKeyAccessToken, token)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// studentScopeMiddleware keeps a personal access token sent to the public
+// API from reading another student: a route with a {id} of another student
+// is rejected with 403. Requests without a personal token (including API
+// keys) are not affected.
+func (s *Server) studentScopeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret := bearerToken(r)
+		if s.deps.Self.Tokens == nil || !student.LooksLikeAccessToken(secret) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token := s.resolveAccessToken(w, r, secret)
+		if token == nil {
+			return
+		}
+
+		if id := r.PathValue("id"); id != "" && id != token.StudentID {
+			writeJSONError(w, http.StatusForbidden, "forbidden", "Access token only allows reading your own data")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// asSelf serves a student route for the token owner.
+func asSelf(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.SetPathValue("id", accessTokenFrom(r.Context()).StudentID)
+		handler(w, r)
+	}
+}
+
+// handleGetMyCalendar handles GET /api/v1/me/calendar
+// Query params: weeks (default: 12).
+func (s *Server) handleGetMyCalendar(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Self.GetActivityCalendarHandler == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Calendar handler not configured")
+		return
+	}
+
+	studentID := accessTokenFrom(r.Context()).StudentID
+	result, err := s.deps.Self.GetActivityCalendarHandler.Handle(r.Context(), query.GetActivityCalendarQuery{
+		StudentID: studentID,
+		Weeks:     getQueryParamInt(r, "weeks", 0),
+	})
+	if err != nil {
+		switch {
+		case shared.IsValidation(err):
+			writeJSONError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		case errors.Is(err, student.ErrStudentNotFound):
+			writeJSONError(w, http.StatusNotFound, "not_found", "Student not found")
+		default:
+			s.logger.Error("failed to get activity calendar", logger.Err(err), logger.String("student_id", studentID))
+			writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to get activity calendar")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
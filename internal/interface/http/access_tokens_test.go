@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type fakeTokenResolver map[string]*student.AccessToken
+
+func (f fakeTokenResolver) Resolve(_ context.Context, secret string) (*student.AccessToken, error) {
+	token, ok := f[secret]
+	if !ok {
+		return nil, student.ErrAccessTokenNotFound
+	}
+	return token, nil
+}
+
+func TestAccessTokens_Scope(t *testing.T) {
+	config := DefaultConfig()
+	config.TokenRateLimitPerMinute = 3
+	server := NewServer(config, Dependencies{Self: SelfDependencies{
+		Tokens: fakeTokenResolver{
+			"ach_s1": {ID: "t1", StudentID: "s1", Scope: student.AccessTokenScopeSelfRead},
+		},
+	}})
+
+	get := func(path, token string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Self routes need a valid token
+	assert.Equal(t, http.StatusUnauthorized, get("/api/v1/me/calendar", ""))
+	assert.Equal(t, http.StatusUnauthorized, get("/api/v1/me/calendar", "ach_revoked"))
+
+	// A token cannot read another student on the public API
+	assert.Equal(t, http.StatusForbidden, get("/api/v1/students/s2/progress", "ach_s1"))
+	assert.Equal(t, http.StatusForbidden, get("/api/v1/students/s2", "ach_s1"))
+	assert.Equal(t, http.StatusUnauthorized, get("/api/v1/students/s1/progress", "ach_revoked"))
+
+	// Its own data passes the check (no handler is configured here)
+	assert.Equal(t, http.StatusNotImplemented, get("/api/v1/students/s1/progress", "ach_s1"))
+
+	// Requests without a personal token are unaffected
+	assert.Equal(t, http.StatusNotImplemented, get("/api/v1/students/s2/progress", ""))
+
+	// Self routes are rate limited per token
+	assert.Equal(t, http.StatusNotImplemented, get("/api/v1/me/calendar", "ach_s1"))
+	assert.Equal(t, http.StatusNotImplemented, get("/api/v1/me/progress", "ach_s1"))
+	assert.Equal(t, http.StatusNotImplemented, get("/api/v1/me/forecast", "ach_s1"))
+	assert.Equal(t, http.StatusTooManyRequests, get("/api/v1/me", "ach_s1"))
+}
+
+func TestAccessTokens_Documented(t *testing.T) {
+	server := NewServer(DefaultConfig(), Dependencies{})
+	doc := server.api.document()
+	paths := doc["paths"].(map[string]any)
+
+	require.Contains(t, paths, "/api/v1/me/calendar")
+	op := paths["/api/v1/me/calendar"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, []any{map[string]any{"accessToken": []string{}}}, op["security"])
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/pkg/logger"
+)
+
+// ContentBlocklistStore lists and replaces the phrases masked in
+// user-generated content.
+type ContentBlocklistStore interface {
+	List(ctx context.Context) ([]string, error)
+	Replace(ctx context.Context, phrases []string) ([]string, error)
+}
+
+// contentBlocklistBody is the body and response of the blocklist endpoints.
+type contentBlocklistBody struct {
+	Phrases []string `json:"phrases"`
+}
+
+// handleAdminGetContentBlocklist handles GET /api/v1/admin/content-blocklist
+func (s *Server) handleAdminGetContentBlocklist(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.ContentBlocklist == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Content blocklist not configured")
+		return
+	}
+
+	phrases, err := s.deps.Admin.ContentBlocklist.List(r.Context())
+	if err != nil {
+		s.logger.Error("failed to list content blocklist", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to list content blocklist")
+		return
+	}
+	writeJSON(w, http.StatusOK, contentBlocklistBody{Phrases: phrases})
+}
+
+// handleAdminPutContentBlocklist handles PUT /api/v1/admin/content-blocklist
+// The body replaces the whole list; phrases are lowercased and deduplicated.
+func (s *Server) handleAdminPutContentBlocklist(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.ContentBlocklist == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Content blocklist not configured")
+		return
+	}
+
+	var req contentBlocklistBody
+	decoder := json.NewDecoder(io.LimitReader(r.Body, 1<<17))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid JSON payload", err.Error())
+		return
+	}
+
+	phrases, err := s.deps.Admin.ContentBlocklist.Replace(r.Context(), req.Phrases)
+	if errors.Is(err, social.ErrInvalidBlocklist) {
+		writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid content blocklist", err.Error())
+		return
+	}
+	if err != nil {
+		s.logger.Error("failed to update content blocklist", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to update content blocklist")
+		return
+	}
+	writeJSON(w, http.StatusOK, contentBlocklistBody{Phrases: phrases})
+}
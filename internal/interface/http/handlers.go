@@ -2,12 +2,39 @@
 package http
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
 	"io"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/cqrs"
 	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/backfill"
+	"github.com/alem-hub/alem-community-hub/internal/domain/event"
+	"github.com/alem-hub/alem-community-hub/internal/domain/experiment"
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/domain/webhook"
+	"github.com/alem-hub/alem-community-hub/internal/interface/http/handlers"
+	"github.com/alem-hub/alem-community-hub/pkg/featureflag"
 	"github.com/alem-hub/alem-community-hub/pkg/logger"
+	"github.com/alem-hub/alem-community-hub/pkg/pagination"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -21,11 +48,16 @@ func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
 		"version":     "v1",
 		"description": "REST API for Alem Community Hub - From Competition to Collaboration",
 		"endpoints": map[string]string{
-			"health":      "/health",
-			"leaderboard": "/api/v1/leaderboard",
-			"online":      "/api/v1/students/online",
-			"helpers":     "/api/v1/helpers",
-			"stats":       "/api/v1/stats",
+			"health":           "/health",
+			"liveness":         "/healthz",
+			"readiness":        "/readyz",
+			"openapi":          "/api/v1/openapi.json",
+			"leaderboard":      "/api/v1/leaderboard",
+			"leaderboard_page": "/leaderboard",
+			"online":           "/api/v1/students/online",
+			"helpers":          "/api/v1/helpers",
+			"stats":            "/api/v1/stats",
+			"me":               "/api/v1/me",
 		},
 		"documentation": "https://github.com/alem-hub/alem-community-hub",
 	}
@@ -35,8 +67,20 @@ func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
 
 // handleHealth handles the health check endpoint.
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	if s.deps.HealthChecker != nil {
-		status := s.deps.HealthChecker.Check(r.Context())
+	if s.deps.Health.HealthChecker != nil {
+		status := s.deps.Health.HealthChecker.Check(r.Context())
+		if s.deps.Health.CacheCircuit != nil {
+			// An open circuit is not unhealthy: reads are served from Postgres
+			cache := s.deps.Health.CacheCircuit()
+			if status.Checks == nil {
+				status.Checks = make(map[string]handlers.CheckResult)
+			}
+			status.Checks["cache"] = handlers.CheckResult{
+				Healthy:     true,
+				Message:     "circuit " + cache.State,
+				LastChecked: time.Now().UTC(),
+			}
+		}
 		if !status.Healthy {
 			writeJSON(w, http.StatusServiceUnavailable, status)
 			return
@@ -46,96 +90,193 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Default health response
-	writeJSON(w, http.StatusOK, map[string]interface{}{
+	health := map[string]interface{}{
 		"status":  "healthy",
 		"uptime":  s.Uptime().String(),
 		"version": "v1",
-	})
+	}
+	if s.deps.Health.CacheCircuit != nil {
+		health["cache"] = s.deps.Health.CacheCircuit()
+	}
+	writeJSON(w, http.StatusOK, health)
+}
+
+// readinessFailure is a failed check in the /readyz response.
+type readinessFailure struct {
+	Name      string `json:"name"`
+	Latency   string `json:"latency"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error"`
 }
 
-// handleReady handles the readiness probe endpoint (for Kubernetes).
+// handleReady handles the readiness probe (/readyz): it runs every
+// dependency check and answers 503 with the failed ones.
 func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
-	if s.deps.HealthChecker != nil {
-		status := s.deps.HealthChecker.Check(r.Context())
-		if !status.Ready {
-			w.WriteHeader(http.StatusServiceUnavailable)
-			writeJSON(w, http.StatusServiceUnavailable, map[string]string{
-				"status": "not_ready",
-				"reason": status.Message,
-			})
-			return
+	if s.deps.Health.HealthChecker == nil {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+		return
+	}
+
+	status := s.deps.Health.HealthChecker.Check(r.Context())
+	if status.Ready {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"status": "ready",
+			"checks": status.Checks,
+		})
+		return
+	}
+
+	failing := make([]readinessFailure, 0, len(status.Checks))
+	for name, check := range status.Checks {
+		if check.Healthy {
+			continue
 		}
+		failing = append(failing, readinessFailure{
+			Name:      name,
+			Latency:   check.Duration,
+			LatencyMS: check.LatencyMS,
+			Error:     check.Message,
+		})
 	}
+	slices.SortFunc(failing, func(a, b readinessFailure) int {
+		return strings.Compare(a.Name, b.Name)
+	})
 
-	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+	writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{
+		"status":  "not_ready",
+		"reason":  status.Message,
+		"failing": failing,
+	})
 }
 
-// handleLive handles the liveness probe endpoint (for Kubernetes).
+// handleLive handles the liveness probe (/healthz): it only shows that the
+// process answers, so a slow dependency never gets it restarted.
 func (s *Server) handleLive(w http.ResponseWriter, r *http.Request) {
-	writeJSON(w, http.StatusOK, map[string]string{"status": "alive"})
+	writeJSON(w, http.StatusOK, map[string]string{
+		"status":  "alive",
+		"uptime":  s.Uptime().Round(time.Second).String(),
+		"version": s.config.Version,
+	})
 }
 
-// handleMetrics handles the Prometheus metrics endpoint.
+// handleMetrics serves the Prometheus metrics, or a JSON summary of the
+// server when metrics are not enabled.
 func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement Prometheus metrics exposition
-	// For now, return basic server metrics as JSON
+	if s.deps.Metrics != nil {
+		s.deps.Metrics.Handler().ServeHTTP(w, r)
+		return
+	}
+
 	metrics := map[string]interface{}{
 		"uptime_seconds": s.Uptime().Seconds(),
 		"running":        s.IsRunning(),
 	}
 
+	if dead, ok := s.deps.Webhook.Telegram.(deadUpdateReporter); ok {
+		metrics["telegram_dead_updates"] = dead.DeadUpdates()
+	}
+	if s.deps.Health.PreferencesDecodeFailures != nil {
+		metrics["preferences_decode_failures"] = s.deps.Health.PreferencesDecodeFailures()
+	}
+	if s.deps.Health.CacheCircuit != nil {
+		cache := s.deps.Health.CacheCircuit()
+		metrics["cache_circuit_state"] = cache.State
+		metrics["cache_dropped_writes"] = cache.DroppedWrites
+	}
+
 	writeJSON(w, http.StatusOK, metrics)
 }
 
+// deadUpdateReporter is implemented by webhook handlers that count ignored updates.
+type deadUpdateReporter interface {
+	DeadUpdates() map[handlers.UpdateType]int64
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // LEADERBOARD HANDLERS
 // ══════════════════════════════════════════════════════════════════════════════
 
 // handleGetLeaderboard handles GET /api/v1/leaderboard
 func (s *Server) handleGetLeaderboard(w http.ResponseWriter, r *http.Request) {
-	s.handleLeaderboardInternal(w, r, "")
+	s.handleLeaderboardInternal(w, r, "", false)
 }
 
 // handleGetLeaderboardByCohort handles GET /api/v1/leaderboard/{cohort}
 func (s *Server) handleGetLeaderboardByCohort(w http.ResponseWriter, r *http.Request) {
 	cohort := r.PathValue("cohort")
-	s.handleLeaderboardInternal(w, r, cohort)
+	s.handleLeaderboardInternal(w, r, cohort, false)
+}
+
+// handleAdminLeaderboard handles GET /api/v1/admin/leaderboard.
+// Unlike the public listing it includes students hidden via /privacy.
+func (s *Server) handleAdminLeaderboard(w http.ResponseWriter, r *http.Request) {
+	s.handleLeaderboardInternal(w, r, getQueryParam(r, "cohort", ""), true)
 }
 
 // handleLeaderboardInternal is the internal implementation for leaderboard handlers.
-func (s *Server) handleLeaderboardInternal(w http.ResponseWriter, r *http.Request, cohort string) {
-	if s.deps.GetLeaderboardHandler == nil {
+// includeHidden lists students who hid themselves from the public leaderboard.
+func (s *Server) handleLeaderboardInternal(w http.ResponseWriter, r *http.Request, cohort string, includeHidden bool) {
+	if !cqrs.Registered[query.GetLeaderboardQuery](s.deps.Bus) {
 		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Leaderboard handler not configured")
 		return
 	}
 
+	period, err := leaderboard.ParsePeriod(getQueryParam(r, "period", ""))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
 	// Parse query parameters
 	q := query.GetLeaderboardQuery{
 		Cohort:               cohort,
-		Limit:                getQueryParamInt(r, "limit", 20),
+		Period:               string(period),
+		Limit:                leaderboard.PageBounds.ClampLimit(getQueryParamInt(r, "limit", 0)),
 		Offset:               getQueryParamInt(r, "offset", 0),
 		OnlyOnline:           getQueryParamBool(r, "online"),
 		OnlyAvailableForHelp: getQueryParamBool(r, "available_for_help"),
 		IncludeRankChange:    getQueryParamBool(r, "include_rank_change"),
+		IncludeHidden:        includeHidden,
+	}
+
+	// A cursor continues after the last rank of the previous page
+	if raw := getQueryParam(r, "cursor", ""); raw != "" {
+		var cursor leaderboardCursor
+		if err := s.cursors.Decode(raw, &cursor); err != nil || cursor.Cohort != cohort || cursor.period() != period {
+			writeInvalidCursor(w)
+			return
+		}
+		q.Offset = 0
+		q.AfterRank = cursor.AfterRank
 	}
 
 	// Execute query
-	result, err := s.deps.GetLeaderboardHandler.Handle(r.Context(), q)
+	result, err := cqrs.Execute[*query.GetLeaderboardResult](r.Context(), s.deps.Bus, q)
 	if err != nil {
+		if shared.IsValidation(err) {
+			writeJSONError(w, http.StatusBadRequest, "invalid_request", err.Error())
+			return
+		}
 		s.logger.Error("failed to get leaderboard", logger.Err(err))
 		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to get leaderboard")
 		return
 	}
 
-	// Build response with pagination metadata
-	meta := &ResponseMeta{
-		TotalCount: result.TotalCount,
-		Page:       result.Page,
-		PageSize:   result.PageSize,
-		HasMore:    result.HasMore,
+	nextCursor := ""
+	if result.NextAfterRank > 0 {
+		nextCursor = s.encodeCursor(leaderboardCursor{Cohort: cohort, Period: period, AfterRank: result.NextAfterRank})
 	}
 
-	writeJSONWithMeta(w, r, http.StatusOK, result, meta)
+	writeJSON(w, http.StatusOK, leaderboardResponse{
+		Envelope:    pagination.NewEnvelope(result.Entries, nextCursor).WithTotal(result.TotalCount),
+		Cohort:      result.Cohort,
+		Period:      result.Period,
+		Week:        result.Week,
+		OnlineCount: result.OnlineCount,
+		AverageXP:   result.AverageXP,
+		MedianXP:    result.MedianXP,
+		GeneratedAt: result.GeneratedAt,
+	})
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -151,7 +292,7 @@ func (s *Server) handleGetStudent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get student rank (which includes student info)
-	if s.deps.GetStudentRankHandler == nil {
+	if !cqrs.Registered[query.GetStudentRankQuery](s.deps.Bus) {
 		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Student handler not configured")
 		return
 	}
@@ -160,9 +301,10 @@ func (s *Server) handleGetStudent(w http.ResponseWriter, r *http.Request) {
 		StudentID:      studentID,
 		IncludeHistory: getQueryParamBool(r, "include_history"),
 		HistoryDays:    getQueryParamInt(r, "history_days", 7),
+		Public:         true,
 	}
 
-	result, err := s.deps.GetStudentRankHandler.Handle(r.Context(), q)
+	result, err := cqrs.Execute[*query.GetStudentRankResult](r.Context(), s.deps.Bus, q)
 	if err != nil {
 		s.logger.Error("failed to get student", logger.Err(err), logger.String("student_id", studentID))
 		writeJSONError(w, http.StatusNotFound, "not_found", "Student not found")
@@ -180,7 +322,7 @@ func (s *Server) handleGetStudentRank(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if s.deps.GetStudentRankHandler == nil {
+	if !cqrs.Registered[query.GetStudentRankQuery](s.deps.Bus) {
 		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Rank handler not configured")
 		return
 	}
@@ -190,9 +332,10 @@ func (s *Server) handleGetStudentRank(w http.ResponseWriter, r *http.Request) {
 		Cohort:         getQueryParam(r, "cohort", ""),
 		IncludeHistory: getQueryParamBool(r, "include_history"),
 		HistoryDays:    getQueryParamInt(r, "history_days", 7),
+		Public:         true,
 	}
 
-	result, err := s.deps.GetStudentRankHandler.Handle(r.Context(), q)
+	result, err := cqrs.Execute[*query.GetStudentRankResult](r.Context(), s.deps.Bus, q)
 	if err != nil {
 		s.logger.Error("failed to get student rank", logger.Err(err), logger.String("student_id", studentID))
 		writeJSONError(w, http.StatusNotFound, "not_found", "Student rank not found")
@@ -210,7 +353,7 @@ func (s *Server) handleGetStudentNeighbors(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if s.deps.GetNeighborsHandler == nil {
+	if !cqrs.Registered[query.GetNeighborsQuery](s.deps.Bus) {
 		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Neighbors handler not configured")
 		return
 	}
@@ -222,7 +365,7 @@ func (s *Server) handleGetStudentNeighbors(w http.ResponseWriter, r *http.Reques
 		IncludeOnlineStatus: getQueryParamBool(r, "include_online"),
 	}
 
-	result, err := s.deps.GetNeighborsHandler.Handle(r.Context(), q)
+	result, err := cqrs.Execute[*query.GetNeighborsResult](r.Context(), s.deps.Bus, q)
 	if err != nil {
 		s.logger.Error("failed to get neighbors", logger.Err(err), logger.String("student_id", studentID))
 		writeJSONError(w, http.StatusNotFound, "not_found", "Neighbors not found")
@@ -240,7 +383,7 @@ func (s *Server) handleGetStudentProgress(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	if s.deps.GetDailyProgressHandler == nil {
+	if s.deps.Public.GetDailyProgressHandler == nil {
 		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Progress handler not configured")
 		return
 	}
@@ -252,7 +395,7 @@ func (s *Server) handleGetStudentProgress(w http.ResponseWriter, r *http.Request
 		IncludeComparison: getQueryParamBool(r, "include_comparison"),
 	}
 
-	result, err := s.deps.GetDailyProgressHandler.Handle(r.Context(), q)
+	result, err := s.deps.Public.GetDailyProgressHandler.Handle(r.Context(), q)
 	if err != nil {
 		s.logger.Error("failed to get progress", logger.Err(err), logger.String("student_id", studentID))
 		writeJSONError(w, http.StatusNotFound, "not_found", "Progress not found")
@@ -262,13 +405,172 @@ func (s *Server) handleGetStudentProgress(w http.ResponseWriter, r *http.Request
 	writeJSON(w, http.StatusOK, result)
 }
 
+// handleGetStudentForecast handles GET /api/v1/students/{id}/forecast
+func (s *Server) handleGetStudentForecast(w http.ResponseWriter, r *http.Request) {
+	studentID := r.PathValue("id")
+	if studentID == "" {
+		writeJSONError(w, http.StatusBadRequest, "invalid_request", "Student ID is required")
+		return
+	}
+
+	if s.deps.Public.GetForecastHandler == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Forecast handler not configured")
+		return
+	}
+
+	q := query.GetForecastQuery{
+		StudentID: studentID,
+		Target:    query.ForecastTarget(r.URL.Query().Get("target")),
+		Value:     getQueryParamInt(r, "value", 0),
+	}
+
+	result, err := s.deps.Public.GetForecastHandler.Handle(r.Context(), q)
+	if err != nil {
+		switch {
+		case shared.IsValidation(err):
+			writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid forecast target", err.Error())
+		case shared.IsNotFound(err):
+			writeJSONErrorWithDetails(w, http.StatusNotFound, "not_found", "Student or rank not found", err.Error())
+		default:
+			s.logger.Error("failed to get forecast", logger.Err(err), logger.String("student_id", studentID))
+			writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to build forecast")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleGetStudentNotifications handles GET /api/v1/students/{id}/notifications
+func (s *Server) handleGetStudentNotifications(w http.ResponseWriter, r *http.Request) {
+	studentID := r.PathValue("id")
+	if studentID == "" {
+		writeJSONError(w, http.StatusBadRequest, "invalid_request", "Student ID is required")
+		return
+	}
+
+	if s.deps.Public.GetNotificationsHandler == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Notifications handler not configured")
+		return
+	}
+
+	q := query.GetNotificationsQuery{
+		StudentID: studentID,
+		Page:      getQueryParamInt(r, "page", 1),
+		PageSize:  getQueryParamInt(r, "page_size", query.DefaultNotificationsPageSize),
+	}
+
+	result, err := s.deps.Public.GetNotificationsHandler.Handle(r.Context(), q)
+	if err != nil {
+		s.logger.Error("failed to get notifications", logger.Err(err), logger.String("student_id", studentID))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to get notifications")
+		return
+	}
+
+	meta := &ResponseMeta{
+		Page:     result.Page,
+		PageSize: result.PageSize,
+		HasMore:  result.HasMore,
+	}
+
+	writeJSONWithMeta(w, r, http.StatusOK, result, meta)
+}
+
+// handleGetStudentEndorsements handles GET /api/v1/students/{id}/endorsements
+func (s *Server) handleGetStudentEndorsements(w http.ResponseWriter, r *http.Request) {
+	studentID := r.PathValue("id")
+	if studentID == "" {
+		writeJSONError(w, http.StatusBadRequest, "invalid_request", "Student ID is required")
+		return
+	}
+
+	if s.deps.Public.ListEndorsementsHandler == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Endorsements handler not configured")
+		return
+	}
+
+	q := query.ListEndorsementsQuery{
+		StudentID: studentID,
+		Limit:     social.ListBounds.ClampLimit(getQueryParamInt(r, "limit", 0)),
+		Offset:    getQueryParamInt(r, "offset", 0),
+	}
+
+	scope := cursorScope(r, "endorsements:"+studentID)
+	if raw := getQueryParam(r, "cursor", ""); raw != "" {
+		var cursor offsetCursor
+		if err := s.cursors.Decode(raw, &cursor); err != nil || cursor.Scope != scope {
+			writeInvalidCursor(w)
+			return
+		}
+		q.Offset = cursor.Offset
+	}
+
+	result, err := s.deps.Public.ListEndorsementsHandler.Handle(r.Context(), q)
+	if err != nil {
+		s.logger.Error("failed to list endorsements", logger.Err(err), logger.String("student_id", studentID))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to list endorsements")
+		return
+	}
+
+	nextCursor := ""
+	if result.HasMore {
+		nextCursor = s.encodeCursor(offsetCursor{Scope: scope, Offset: q.Offset + q.Limit})
+	}
+
+	writeJSON(w, http.StatusOK, pagination.NewEnvelope(result.Endorsements, nextCursor))
+}
+
+// handleGetStudentConnections handles GET /api/v1/students/{id}/connections
+func (s *Server) handleGetStudentConnections(w http.ResponseWriter, r *http.Request) {
+	studentID := r.PathValue("id")
+	if studentID == "" {
+		writeJSONError(w, http.StatusBadRequest, "invalid_request", "Student ID is required")
+		return
+	}
+
+	if s.deps.Public.ListConnectionsHandler == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Connections handler not configured")
+		return
+	}
+
+	q := query.ListConnectionsQuery{
+		StudentID: studentID,
+		Limit:     social.ListBounds.ClampLimit(getQueryParamInt(r, "limit", 0)),
+		Offset:    getQueryParamInt(r, "offset", 0),
+	}
+
+	scope := cursorScope(r, "connections:"+studentID)
+	if raw := getQueryParam(r, "cursor", ""); raw != "" {
+		var cursor offsetCursor
+		if err := s.cursors.Decode(raw, &cursor); err != nil || cursor.Scope != scope {
+			writeInvalidCursor(w)
+			return
+		}
+		q.Offset = cursor.Offset
+	}
+
+	result, err := s.deps.Public.ListConnectionsHandler.Handle(r.Context(), q)
+	if err != nil {
+		s.logger.Error("failed to list connections", logger.Err(err), logger.String("student_id", studentID))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to list connections")
+		return
+	}
+
+	nextCursor := ""
+	if result.HasMore {
+		nextCursor = s.encodeCursor(offsetCursor{Scope: scope, Offset: q.Offset + q.Limit})
+	}
+
+	writeJSON(w, http.StatusOK, pagination.NewEnvelope(result.Connections, nextCursor))
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // ONLINE STUDENTS HANDLER
 // ══════════════════════════════════════════════════════════════════════════════
 
 // handleGetOnline handles GET /api/v1/students/online
 func (s *Server) handleGetOnline(w http.ResponseWriter, r *http.Request) {
-	if s.deps.GetOnlineNowHandler == nil {
+	if s.deps.Public.GetOnlineNowHandler == nil {
 		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Online handler not configured")
 		return
 	}
@@ -278,7 +580,7 @@ func (s *Server) handleGetOnline(w http.ResponseWriter, r *http.Request) {
 		IncludeAway:          getQueryParamBool(r, "include_away"),
 		IncludeRecent:        getQueryParamBool(r, "include_recent"),
 		OnlyAvailableForHelp: getQueryParamBool(r, "available_for_help"),
-		Limit:                getQueryParamInt(r, "limit", 50),
+		Limit:                query.OnlineNowBounds.ClampLimit(getQueryParamInt(r, "limit", 0)),
 		Offset:               getQueryParamInt(r, "offset", 0),
 		SortBy:               getQueryParam(r, "sort_by", "last_seen"),
 		SortDesc:             getQueryParamBool(r, "sort_desc"),
@@ -286,19 +588,43 @@ func (s *Server) handleGetOnline(w http.ResponseWriter, r *http.Request) {
 		IncludeRank:          getQueryParamBool(r, "include_rank"),
 	}
 
-	result, err := s.deps.GetOnlineNowHandler.Handle(r.Context(), q)
+	// The list is built in memory, so the cursor is an offset bound to the
+	// filters it was issued for
+	scope := cursorScope(r, "online")
+	if raw := getQueryParam(r, "cursor", ""); raw != "" {
+		var cursor offsetCursor
+		if err := s.cursors.Decode(raw, &cursor); err != nil || cursor.Scope != scope {
+			writeInvalidCursor(w)
+			return
+		}
+		q.Offset = cursor.Offset
+	}
+
+	result, err := s.deps.Public.GetOnlineNowHandler.Handle(r.Context(), q)
 	if err != nil {
+		if shared.IsValidation(err) {
+			writeJSONError(w, http.StatusBadRequest, "invalid_request", err.Error())
+			return
+		}
 		s.logger.Error("failed to get online students", logger.Err(err))
 		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to get online students")
 		return
 	}
 
-	meta := &ResponseMeta{
-		TotalCount: result.TotalCount,
-		HasMore:    result.HasMore,
+	nextCursor := ""
+	if result.HasMore {
+		nextCursor = s.encodeCursor(offsetCursor{Scope: scope, Offset: q.Offset + q.Limit})
 	}
 
-	writeJSONWithMeta(w, r, http.StatusOK, result, meta)
+	writeJSON(w, http.StatusOK, onlineResponse{
+		Envelope:          pagination.NewEnvelope(result.Students, nextCursor).WithTotal(result.TotalCount),
+		TotalOnline:       result.TotalOnline,
+		TotalAway:         result.TotalAway,
+		TotalRecent:       result.TotalRecent,
+		CommunityActivity: result.CommunityActivity,
+		Message:           result.Message,
+		GeneratedAt:       result.GeneratedAt,
+	})
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -307,7 +633,7 @@ func (s *Server) handleGetOnline(w http.ResponseWriter, r *http.Request) {
 
 // handleFindHelpers handles GET /api/v1/helpers
 func (s *Server) handleFindHelpers(w http.ResponseWriter, r *http.Request) {
-	if s.deps.FindHelpersHandler == nil {
+	if s.deps.Public.FindHelpersHandler == nil {
 		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Helpers handler not configured")
 		return
 	}
@@ -323,17 +649,183 @@ func (s *Server) handleFindHelpers(w http.ResponseWriter, r *http.Request) {
 		Cohort:        getQueryParam(r, "cohort", ""),
 		PreferOnline:  getQueryParamBool(r, "only_online"),
 		MinHelpRating: 0, // Could parse from query param if needed
-		Limit:         getQueryParamInt(r, "limit", 10),
+		Limit:         query.FindHelpersBounds.ClampLimit(getQueryParamInt(r, "limit", 0)),
 		RequesterID:   getQueryParam(r, "exclude", ""),
 	}
 
-	result, err := s.deps.FindHelpersHandler.Handle(r.Context(), q)
+	result, err := s.deps.Public.FindHelpersHandler.Handle(r.Context(), q)
 	if err != nil {
 		s.logger.Error("failed to find helpers", logger.Err(err), logger.String("task_id", taskID))
 		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to find helpers")
 		return
 	}
 
+	// Helpers are ranked by relevance and capped, so there is no next page
+	writeJSON(w, http.StatusOK, helpersResponse{
+		Envelope:        pagination.NewEnvelope(result.Helpers, "").WithTotal(result.TotalFound),
+		TaskID:          result.TaskID,
+		TotalSolvers:    result.TotalSolvers,
+		OnlineSolvers:   result.OnlineSolvers,
+		Difficulty:      result.Difficulty,
+		FrequentlyAsked: result.FrequentlyAsked,
+		GeneratedAt:     result.GeneratedAt,
+	})
+}
+
+// handleGetTopHelpers handles GET /api/v1/helpers/top
+// Query params: days (default: 30), limit.
+func (s *Server) handleGetTopHelpers(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Public.GetTopHelpersHandler == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Top helpers handler not configured")
+		return
+	}
+
+	result, err := s.deps.Public.GetTopHelpersHandler.Handle(r.Context(), query.GetTopHelpersQuery{
+		Days:  getQueryParamInt(r, "days", 0),
+		Limit: getQueryParamInt(r, "limit", 0),
+	})
+	if err != nil {
+		if shared.IsValidation(err) {
+			writeJSONError(w, http.StatusBadRequest, "invalid_request", err.Error())
+			return
+		}
+		s.logger.Error("failed to get top helpers", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to get top helpers")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleGetResponseTimes handles GET /api/v1/community/response-times
+// Query params: weeks (default: 8).
+func (s *Server) handleGetResponseTimes(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Public.GetResponseTimesHandler == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Response times handler not configured")
+		return
+	}
+
+	result, err := s.deps.Public.GetResponseTimesHandler.Handle(r.Context(), query.GetResponseTimesQuery{
+		Weeks: getQueryParamInt(r, "weeks", query.DefaultResponseTimeWeeks),
+	})
+	if err != nil {
+		if shared.IsValidation(err) {
+			writeJSONError(w, http.StatusBadRequest, "invalid_request", err.Error())
+			return
+		}
+		s.logger.Error("failed to get response times", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to get response times")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleGetFeed handles GET /api/v1/feed
+// Query params: cohort, limit, cursor.
+func (s *Server) handleGetFeed(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Public.GetFeedHandler == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Feed handler not configured")
+		return
+	}
+
+	q := query.GetFeedQuery{
+		Cohort: getQueryParam(r, "cohort", ""),
+		Limit:  getQueryParamInt(r, "limit", 0),
+	}
+	if raw := getQueryParam(r, "cursor", ""); raw != "" {
+		var cursor feedCursor
+		if err := s.cursors.Decode(raw, &cursor); err != nil || cursor.Cohort != q.Cohort {
+			writeInvalidCursor(w)
+			return
+		}
+		q.BeforeID = cursor.BeforeID
+	}
+
+	result, err := s.deps.Public.GetFeedHandler.Handle(r.Context(), q)
+	if err != nil {
+		if shared.IsValidation(err) {
+			writeJSONError(w, http.StatusBadRequest, "invalid_request", err.Error())
+			return
+		}
+		s.logger.Error("failed to get feed", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to get feed")
+		return
+	}
+
+	nextCursor := ""
+	if result.NextBeforeID > 0 {
+		nextCursor = s.encodeCursor(feedCursor{Cohort: q.Cohort, BeforeID: result.NextBeforeID})
+	}
+	writeJSON(w, http.StatusOK, pagination.NewEnvelope(result.Items, nextCursor))
+}
+
+// handleGetCohortXPSeries handles GET /api/v1/cohorts/{cohort}/xp-series
+// Query params: cohort (repeatable, cohorts to compare with), from, to
+// (YYYY-MM-DD, inclusive), bucket (day|week, default: week).
+func (s *Server) handleGetCohortXPSeries(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Public.GetCohortXPSeriesHandler == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Cohort XP series handler not configured")
+		return
+	}
+
+	q := query.GetCohortXPSeriesQuery{
+		Cohorts: []string{r.PathValue("cohort")},
+		Bucket:  student.XPSeriesBucket(getQueryParam(r, "bucket", "")),
+	}
+	for _, cohort := range r.URL.Query()["cohort"] {
+		if cohort != "" && !slices.Contains(q.Cohorts, cohort) {
+			q.Cohorts = append(q.Cohorts, cohort)
+		}
+	}
+	if v := getQueryParam(r, "from", ""); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid_request", "from must be YYYY-MM-DD")
+			return
+		}
+		q.From = parsed
+	}
+	if v := getQueryParam(r, "to", ""); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid_request", "to must be YYYY-MM-DD")
+			return
+		}
+		q.To = parsed
+	}
+
+	result, err := s.deps.Public.GetCohortXPSeriesHandler.Handle(r.Context(), q)
+	if err != nil {
+		if shared.IsValidation(err) {
+			writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid series parameters", err.Error())
+			return
+		}
+		s.logger.Error("failed to get cohort xp series", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to get cohort XP series")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleGetTaskDifficulty handles GET /api/v1/tasks/difficulty
+// Query params: limit (default: 50).
+func (s *Server) handleGetTaskDifficulty(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Public.GetTaskDifficultyHandler == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Task difficulty handler not configured")
+		return
+	}
+
+	result, err := s.deps.Public.GetTaskDifficultyHandler.Handle(r.Context(), query.GetTaskDifficultyQuery{
+		Limit: getQueryParamInt(r, "limit", 0),
+	})
+	if err != nil {
+		s.logger.Error("failed to get task difficulty", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to get task difficulty")
+		return
+	}
+
 	writeJSON(w, http.StatusOK, result)
 }
 
@@ -352,13 +844,13 @@ func (s *Server) handleGetStats(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Add online stats if handler is available
-	if s.deps.GetOnlineNowHandler != nil {
+	if s.deps.Public.GetOnlineNowHandler != nil {
 		q := query.GetOnlineNowQuery{
 			Limit:         1,
 			IncludeAway:   true,
 			IncludeRecent: true,
 		}
-		result, err := s.deps.GetOnlineNowHandler.Handle(r.Context(), q)
+		result, err := s.deps.Public.GetOnlineNowHandler.Handle(r.Context(), q)
 		if err == nil {
 			stats["community"] = map[string]interface{}{
 				"online_now":   result.TotalOnline,
@@ -370,12 +862,42 @@ func (s *Server) handleGetStats(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Add help response times if handler is available
+	if s.deps.Public.GetResponseTimesHandler != nil {
+		result, err := s.deps.Public.GetResponseTimesHandler.Handle(r.Context(), query.GetResponseTimesQuery{Weeks: query.DefaultResponseTimeWeeks})
+		if err == nil {
+			community, ok := stats["community"].(map[string]interface{})
+			if !ok {
+				community = make(map[string]interface{})
+				stats["community"] = community
+			}
+			community["help_response_median_minutes"] = result.MedianMinutes
+			community["help_requests_answered"] = result.Answered
+			community["help_requests_unanswered"] = result.Unanswered
+		}
+	}
+
+	// Add help satisfaction if handler is available
+	if s.deps.Public.GetHelpSatisfactionHandler != nil {
+		result, err := s.deps.Public.GetHelpSatisfactionHandler.Handle(r.Context(), query.GetHelpSatisfactionQuery{Weeks: query.DefaultHelpSatisfactionWeeks})
+		if err == nil {
+			community, ok := stats["community"].(map[string]interface{})
+			if !ok {
+				community = make(map[string]interface{})
+				stats["community"] = community
+			}
+			community["help_satisfaction_average"] = result.AverageScore
+			community["help_satisfaction_net_score"] = result.NetScore
+			community["help_satisfaction_weekly"] = result.Weekly
+		}
+	}
+
 	// Add leaderboard stats if handler is available
-	if s.deps.GetLeaderboardHandler != nil {
+	if cqrs.Registered[query.GetLeaderboardQuery](s.deps.Bus) {
 		q := query.GetLeaderboardQuery{
 			Limit: 1,
 		}
-		result, err := s.deps.GetLeaderboardHandler.Handle(r.Context(), q)
+		result, err := cqrs.Execute[*query.GetLeaderboardResult](r.Context(), s.deps.Bus, q)
 		if err == nil {
 			stats["leaderboard"] = map[string]interface{}{
 				"total_students": result.TotalCount,
@@ -385,94 +907,1299 @@ func (s *Server) handleGetStats(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Add outbound HTTP client stats if available
+	if s.deps.Health.OutboundStats != nil {
+		outbound := make(map[string]interface{})
+		for name, st := range s.deps.Health.OutboundStats() {
+			outbound[name] = map[string]interface{}{
+				"in_flight":    st.InFlight,
+				"requests":     st.Requests,
+				"failures":     st.Failures,
+				"reused_conns": st.ReusedConns,
+				"new_conns":    st.NewConns,
+				"reuse_ratio":  st.ReuseRatio(),
+			}
+		}
+		stats["outbound"] = outbound
+	}
+
 	writeJSON(w, http.StatusOK, stats)
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
-// WEBHOOK HANDLERS
+// ADMIN HANDLERS
 // ══════════════════════════════════════════════════════════════════════════════
 
-// TelegramWebhookPayload represents a Telegram webhook update.
-type TelegramWebhookPayload struct {
-	UpdateID int64 `json:"update_id"`
-	Message  *struct {
-		MessageID int64 `json:"message_id"`
-		From      *struct {
-			ID        int64  `json:"id"`
-			IsBot     bool   `json:"is_bot"`
-			FirstName string `json:"first_name"`
-			LastName  string `json:"last_name,omitempty"`
-			Username  string `json:"username,omitempty"`
-		} `json:"from,omitempty"`
-		Chat *struct {
-			ID   int64  `json:"id"`
-			Type string `json:"type"`
-		} `json:"chat,omitempty"`
-		Date int64  `json:"date"`
-		Text string `json:"text,omitempty"`
-	} `json:"message,omitempty"`
-	CallbackQuery *struct {
-		ID   string `json:"id"`
-		From *struct {
-			ID       int64  `json:"id"`
-			Username string `json:"username,omitempty"`
-		} `json:"from,omitempty"`
-		Data string `json:"data,omitempty"`
-	} `json:"callback_query,omitempty"`
-}
-
-// handleTelegramWebhook handles POST /webhook/telegram
-func (s *Server) handleTelegramWebhook(w http.ResponseWriter, r *http.Request) {
-	s.processTelegramWebhook(w, r, "")
+// previewBanner is prepended to previews delivered to an admin's chat so they
+// are never mistaken for a real notification.
+const previewBanner = "🧪 <b>ПРЕВЬЮ</b> · <code>%s</code>\n<i>Это сообщение видишь только ты.</i>\n\n"
+
+// PreviewRequest is the body of POST /api/v1/admin/preview.
+type PreviewRequest struct {
+	Type      string                         `json:"type"`
+	Template  string                         `json:"template,omitempty"`
+	StudentID string                         `json:"student_id,omitempty"`
+	Data      *notification.NotificationData `json:"data,omitempty"`
+	Digest    *notification.DigestContent    `json:"digest,omitempty"`
+
+	// DeliverTo is the requesting admin's Telegram ID. When set, the preview
+	// is also sent to that admin's chat.
+	DeliverTo int64 `json:"deliver_to,omitempty"`
 }
 
-// handleTelegramWebhookWithToken handles POST /webhook/telegram/{token}
-func (s *Server) handleTelegramWebhookWithToken(w http.ResponseWriter, r *http.Request) {
-	token := r.PathValue("token")
-	s.processTelegramWebhook(w, r, token)
+// PreviewResponse is the rendered preview plus delivery status.
+type PreviewResponse struct {
+	*query.PreviewNotificationResult
+	Delivered bool `json:"delivered"`
 }
 
-// processTelegramWebhook is the internal implementation for webhook processing.
-func (s *Server) processTelegramWebhook(w http.ResponseWriter, r *http.Request, token string) {
-	// Validate token if configured
-	if s.config.WebhookSecret != "" && token != s.config.WebhookSecret {
-		s.logger.Warn("invalid webhook token", logger.String("ip", getClientIP(r)))
-		writeJSONError(w, http.StatusUnauthorized, "unauthorized", "Invalid webhook token")
+// handleAdminPreview handles POST /api/v1/admin/preview
+func (s *Server) handleAdminPreview(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.PreviewNotificationHandler == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Preview handler not configured")
 		return
 	}
 
-	// Read body
-	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20)) // 1MB limit
-	if err != nil {
-		s.logger.Error("failed to read webhook body", logger.Err(err))
-		writeJSONError(w, http.StatusBadRequest, "invalid_request", "Failed to read request body")
+	var req PreviewRequest
+	decoder := json.NewDecoder(io.LimitReader(r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid JSON payload", err.Error())
 		return
 	}
-	defer r.Body.Close()
 
-	// Parse payload
-	var payload TelegramWebhookPayload
-	if err := json.Unmarshal(body, &payload); err != nil {
-		s.logger.Error("failed to parse webhook payload", logger.Err(err))
-		writeJSONError(w, http.StatusBadRequest, "invalid_request", "Invalid JSON payload")
+	if req.DeliverTo != 0 {
+		if !slices.Contains(s.config.AdminIDs, req.DeliverTo) {
+			writeJSONError(w, http.StatusForbidden, "forbidden", "Previews can only be delivered to admins")
+			return
+		}
+		if s.deps.Admin.PreviewSender == nil {
+			writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Preview delivery not configured")
+			return
+		}
+	}
+
+	result, err := s.deps.Admin.PreviewNotificationHandler.Handle(r.Context(), query.PreviewNotificationQuery{
+		Type:      req.Type,
+		Template:  req.Template,
+		StudentID: req.StudentID,
+		Data:      req.Data,
+		Digest:    req.Digest,
+	})
+	if err != nil {
+		switch {
+		case shared.IsValidation(err):
+			writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid preview request", err.Error())
+		case shared.IsNotFound(err):
+			writeJSONError(w, http.StatusNotFound, "not_found", "Student not found")
+		default:
+			s.logger.Error("failed to render preview", logger.Err(err), logger.String("type", req.Type))
+			writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to render preview")
+		}
 		return
 	}
 
-	// Log webhook receipt
-	s.logger.Info("received telegram webhook",
-		logger.Int64("update_id", payload.UpdateID),
-		logger.Bool("has_message", payload.Message != nil),
-		logger.Bool("has_callback", payload.CallbackQuery != nil),
-	)
+	resp := PreviewResponse{PreviewNotificationResult: result}
 
-	// Delegate to webhook handler if configured
-	if s.deps.WebhookHandler != nil {
-		if err := s.deps.WebhookHandler.HandleTelegramUpdate(r.Context(), body); err != nil {
+	if req.DeliverTo != 0 && result.Text != "" {
+		text := fmt.Sprintf(previewBanner, html.EscapeString(req.Type)) + result.Text
+		if err := s.deps.Admin.PreviewSender.SendPreview(r.Context(), req.DeliverTo, text); err != nil {
+			s.logger.Error("failed to deliver preview", logger.Err(err), logger.Int64("admin_id", req.DeliverTo))
+			writeJSONErrorWithDetails(w, http.StatusBadGateway, "delivery_failed", "Preview rendered but could not be delivered", err.Error())
+			return
+		}
+		resp.Delivered = true
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleAdminCommandUsage handles GET /api/v1/admin/analytics/commands
+// Query params: from, to (YYYY-MM-DD, inclusive), limit.
+// Defaults to the last 30 flushed days (today is not flushed yet).
+func (s *Server) handleAdminCommandUsage(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.GetCommandUsageHandler == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Command usage handler not configured")
+		return
+	}
+
+	to := time.Now().UTC().AddDate(0, 0, -1)
+	if v := getQueryParam(r, "to", ""); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid_request", "to must be YYYY-MM-DD")
+			return
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -29)
+	if v := getQueryParam(r, "from", ""); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid_request", "from must be YYYY-MM-DD")
+			return
+		}
+		from = parsed
+	}
+
+	result, err := s.deps.Admin.GetCommandUsageHandler.Handle(r.Context(), query.GetCommandUsageQuery{
+		From:  from,
+		To:    to,
+		Limit: getQueryParamInt(r, "limit", 0),
+	})
+	if err != nil {
+		if shared.IsValidation(err) {
+			writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid range", err.Error())
+			return
+		}
+		s.logger.Error("failed to get command usage", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to get command usage")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleAdminHelpSatisfaction handles GET /api/v1/admin/analytics/help-satisfaction
+// Query params: weeks (default: 8).
+func (s *Server) handleAdminHelpSatisfaction(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.GetHelpSatisfactionHandler == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Help satisfaction handler not configured")
+		return
+	}
+
+	result, err := s.deps.Admin.GetHelpSatisfactionHandler.Handle(r.Context(), query.GetHelpSatisfactionQuery{
+		Weeks: getQueryParamInt(r, "weeks", query.DefaultHelpSatisfactionWeeks),
+	})
+	if err != nil {
+		if shared.IsValidation(err) {
+			writeJSONError(w, http.StatusBadRequest, "invalid_request", err.Error())
+			return
+		}
+		s.logger.Error("failed to get help satisfaction", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to get help satisfaction")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// cohortSettingsResponse is the overridden settings of a cohort with the
+// definitions of all known settings.
+type cohortSettingsResponse struct {
+	Cohort      string                `json:"cohort"`
+	Settings    settings.Values       `json:"settings"`
+	Definitions []settings.Definition `json:"definitions"`
+}
+
+// cohortSettingRequest is the body of PUT /api/v1/admin/cohorts/{cohort}/settings/{key}.
+type cohortSettingRequest struct {
+	Value json.RawMessage `json:"value"`
+}
+
+// handleAdminGetCohortSettings handles GET /api/v1/admin/cohorts/{cohort}/settings
+func (s *Server) handleAdminGetCohortSettings(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.CohortSettings == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Cohort settings not configured")
+		return
+	}
+
+	cohort := r.PathValue("cohort")
+	values, err := s.deps.Admin.CohortSettings.Values(r.Context(), cohort)
+	if err != nil {
+		s.logger.Error("failed to get cohort settings", logger.Err(err), logger.String("cohort", cohort))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to get cohort settings")
+		return
+	}
+	if values == nil {
+		values = settings.Values{}
+	}
+
+	writeJSON(w, http.StatusOK, cohortSettingsResponse{
+		Cohort:      cohort,
+		Settings:    values,
+		Definitions: settings.Definitions(),
+	})
+}
+
+// handleAdminSetCohortSetting handles PUT /api/v1/admin/cohorts/{cohort}/settings/{key}
+func (s *Server) handleAdminSetCohortSetting(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.CohortSettings == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Cohort settings not configured")
+		return
+	}
+
+	var req cohortSettingRequest
+	decoder := json.NewDecoder(io.LimitReader(r.Body, 1<<16))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid JSON payload", err.Error())
+		return
+	}
+
+	cohort, key := r.PathValue("cohort"), settings.Key(r.PathValue("key"))
+	if err := s.deps.Admin.CohortSettings.Set(r.Context(), cohort, key, req.Value); err != nil {
+		if shared.IsValidation(err) {
+			writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid setting", err.Error())
+			return
+		}
+		s.logger.Error("failed to set cohort setting", logger.Err(err), logger.String("cohort", cohort), logger.String("key", string(key)))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to set cohort setting")
+		return
+	}
+
+	s.logger.Info("cohort setting updated", logger.String("cohort", cohort), logger.String("key", string(key)))
+	writeJSON(w, http.StatusOK, map[string]any{"cohort": cohort, "key": key, "value": req.Value})
+}
+
+// handleAdminDeleteCohortSetting handles DELETE /api/v1/admin/cohorts/{cohort}/settings/{key}
+// The cohort falls back to the global default.
+func (s *Server) handleAdminDeleteCohortSetting(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.CohortSettings == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Cohort settings not configured")
+		return
+	}
+
+	cohort, key := r.PathValue("cohort"), settings.Key(r.PathValue("key"))
+	if err := s.deps.Admin.CohortSettings.Delete(r.Context(), cohort, key); err != nil {
+		s.logger.Error("failed to delete cohort setting", logger.Err(err), logger.String("cohort", cohort), logger.String("key", string(key)))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to delete cohort setting")
+		return
+	}
+
+	s.logger.Info("cohort setting reset", logger.String("cohort", cohort), logger.String("key", string(key)))
+	writeJSON(w, http.StatusOK, map[string]any{"cohort": cohort, "key": key, "status": "deleted"})
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// TRIGGER RULE HANDLERS (admin)
+// ══════════════════════════════════════════════════════════════════════════════
+
+// triggerRulesResponse lists trigger rules with the fields templates may use.
+type triggerRulesResponse struct {
+	Rules          []notification.TriggerRuleDocument `json:"rules"`
+	TemplateFields []string                           `json:"template_fields"`
+}
+
+// handleAdminListTriggerRules handles GET /api/v1/admin/trigger-rules
+func (s *Server) handleAdminListTriggerRules(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.TriggerRules == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Trigger rules not configured")
+		return
+	}
+
+	rules, err := s.deps.Admin.TriggerRules.List(r.Context())
+	if err != nil {
+		s.logger.Error("failed to list trigger rules", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to list trigger rules")
+		return
+	}
+
+	resp := triggerRulesResponse{
+		Rules:          make([]notification.TriggerRuleDocument, 0, len(rules)),
+		TemplateFields: notification.TemplateFields(),
+	}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, rule.Document())
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleAdminGetTriggerRule handles GET /api/v1/admin/trigger-rules/{id}
+func (s *Server) handleAdminGetTriggerRule(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.TriggerRules == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Trigger rules not configured")
+		return
+	}
+
+	id := notification.TriggerRuleID(r.PathValue("id"))
+	rule, err := s.deps.Admin.TriggerRules.Get(r.Context(), id)
+	if err != nil {
+		s.writeTriggerRuleError(w, err, id, "get")
+		return
+	}
+	writeJSON(w, http.StatusOK, rule.Document())
+}
+
+// handleAdminUpdateTriggerRule handles PUT /api/v1/admin/trigger-rules/{id}
+// The body is the full rule; the ID is taken from the path and "v" may be
+// omitted. Invalid rules (unknown condition types or template fields, bad
+// ranges) are rejected with 400 and the stored rule is left unchanged.
+func (s *Server) handleAdminUpdateTriggerRule(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.TriggerRules == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Trigger rules not configured")
+		return
+	}
+
+	var doc notification.TriggerRuleDocument
+	decoder := json.NewDecoder(io.LimitReader(r.Body, 1<<16))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid JSON payload", err.Error())
+		return
+	}
+
+	id := notification.TriggerRuleID(r.PathValue("id"))
+	doc.ID = string(id)
+	if doc.Version == 0 {
+		doc.Version = notification.TriggerRuleSchemaVersion
+	}
+
+	rule, err := doc.Rule()
+	if err != nil {
+		writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid trigger rule", err.Error())
+		return
+	}
+
+	rule, err = s.deps.Admin.TriggerRules.Update(r.Context(), rule)
+	if err != nil {
+		s.writeTriggerRuleError(w, err, id, "update")
+		return
+	}
+
+	s.logger.Info("trigger rule updated", logger.String("rule_id", string(id)))
+	writeJSON(w, http.StatusOK, rule.Document())
+}
+
+// handleAdminEnableTriggerRule handles POST /api/v1/admin/trigger-rules/{id}/enable
+func (s *Server) handleAdminEnableTriggerRule(w http.ResponseWriter, r *http.Request) {
+	s.setTriggerRuleEnabled(w, r, true)
+}
+
+// handleAdminDisableTriggerRule handles POST /api/v1/admin/trigger-rules/{id}/disable
+func (s *Server) handleAdminDisableTriggerRule(w http.ResponseWriter, r *http.Request) {
+	s.setTriggerRuleEnabled(w, r, false)
+}
+
+// setTriggerRuleEnabled turns a rule on or off.
+func (s *Server) setTriggerRuleEnabled(w http.ResponseWriter, r *http.Request, enabled bool) {
+	if s.deps.Admin.TriggerRules == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Trigger rules not configured")
+		return
+	}
+
+	id := notification.TriggerRuleID(r.PathValue("id"))
+	if err := s.deps.Admin.TriggerRules.SetEnabled(r.Context(), id, enabled); err != nil {
+		s.writeTriggerRuleError(w, err, id, "toggle")
+		return
+	}
+
+	s.logger.Info("trigger rule toggled", logger.String("rule_id", string(id)), logger.Bool("enabled", enabled))
+	writeJSON(w, http.StatusOK, map[string]any{"id": id, "enabled": enabled})
+}
+
+// writeTriggerRuleError maps trigger rule store errors to responses.
+func (s *Server) writeTriggerRuleError(w http.ResponseWriter, err error, id notification.TriggerRuleID, op string) {
+	switch {
+	case errors.Is(err, notification.ErrTriggerRuleNotFound):
+		writeJSONError(w, http.StatusNotFound, "not_found", "Trigger rule not found")
+	case shared.IsValidation(err):
+		writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid trigger rule", err.Error())
+	default:
+		s.logger.Error("failed to "+op+" trigger rule", logger.Err(err), logger.String("rule_id", string(id)))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to "+op+" trigger rule")
+	}
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// FEATURE FLAG HANDLERS (admin)
+// ══════════════════════════════════════════════════════════════════════════════
+
+// featureFlagsResponse lists feature flags.
+type featureFlagsResponse struct {
+	Flags []featureflag.Flag `json:"flags"`
+}
+
+// featureFlagRequest is the body of PUT /api/v1/admin/feature-flags/{name}.
+type featureFlagRequest struct {
+	Description    string   `json:"description,omitempty"`
+	Enabled        bool     `json:"enabled"`
+	RolloutPercent int      `json:"rollout_percent"`
+	Cohorts        []string `json:"cohorts,omitempty"`
+	FailOpen       bool     `json:"fail_open"`
+}
+
+// handleAdminListFeatureFlags handles GET /api/v1/admin/feature-flags
+func (s *Server) handleAdminListFeatureFlags(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.FeatureFlags == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Feature flags not configured")
+		return
+	}
+
+	flags, err := s.deps.Admin.FeatureFlags.List(r.Context())
+	if err != nil {
+		s.logger.Error("failed to list feature flags", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to list feature flags")
+		return
+	}
+	writeJSON(w, http.StatusOK, featureFlagsResponse{Flags: flags})
+}
+
+// handleAdminGetFeatureFlag handles GET /api/v1/admin/feature-flags/{name}
+func (s *Server) handleAdminGetFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.FeatureFlags == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Feature flags not configured")
+		return
+	}
+
+	name := r.PathValue("name")
+	flag, err := s.deps.Admin.FeatureFlags.Get(r.Context(), name)
+	if err != nil {
+		s.writeFeatureFlagError(w, err, name, "get")
+		return
+	}
+	writeJSON(w, http.StatusOK, flag)
+}
+
+// handleAdminPutFeatureFlag handles PUT /api/v1/admin/feature-flags/{name}
+// The body is the full flag; the name is taken from the path.
+func (s *Server) handleAdminPutFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.FeatureFlags == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Feature flags not configured")
+		return
+	}
+
+	var req featureFlagRequest
+	decoder := json.NewDecoder(io.LimitReader(r.Body, 1<<16))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid JSON payload", err.Error())
+		return
+	}
+
+	name := r.PathValue("name")
+	flag, err := s.deps.Admin.FeatureFlags.Set(r.Context(), featureflag.Flag{
+		Name:           name,
+		Description:    req.Description,
+		Enabled:        req.Enabled,
+		RolloutPercent: req.RolloutPercent,
+		Cohorts:        req.Cohorts,
+		FailOpen:       req.FailOpen,
+	})
+	if err != nil {
+		s.writeFeatureFlagError(w, err, name, "update")
+		return
+	}
+	writeJSON(w, http.StatusOK, flag)
+}
+
+// writeFeatureFlagError maps feature flag store errors to responses.
+func (s *Server) writeFeatureFlagError(w http.ResponseWriter, err error, name, op string) {
+	switch {
+	case errors.Is(err, featureflag.ErrNotFound):
+		writeJSONError(w, http.StatusNotFound, "not_found", "Feature flag not found")
+	case errors.Is(err, featureflag.ErrInvalidFlag):
+		writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid feature flag", err.Error())
+	default:
+		s.logger.Error("failed to "+op+" feature flag", logger.Err(err), logger.String("flag", name))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to "+op+" feature flag")
+	}
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// WEBHOOK SUBSCRIPTION HANDLERS (outbound, admin)
+// ══════════════════════════════════════════════════════════════════════════════
+
+// webhookSubscriptionsResponse is the list of subscriptions with their
+// delivery log and the events that can be subscribed to.
+type webhookSubscriptionsResponse struct {
+	Subscriptions []*webhook.Subscription `json:"subscriptions"`
+	EventTypes    []webhook.EventType     `json:"event_types"`
+}
+
+// webhookSubscriptionRequest is the body of POST /api/v1/admin/webhooks.
+// An empty secret is generated and returned once in the response.
+type webhookSubscriptionRequest struct {
+	URL        string              `json:"url"`
+	Secret     string              `json:"secret,omitempty"`
+	EventTypes []webhook.EventType `json:"event_types"`
+	Cohort     string              `json:"cohort,omitempty"`
+}
+
+// webhookSubscriptionPatch is the body of PATCH /api/v1/admin/webhooks/{id};
+// omitted fields are left unchanged.
+type webhookSubscriptionPatch struct {
+	URL        *string             `json:"url,omitempty"`
+	Secret     *string             `json:"secret,omitempty"`
+	EventTypes []webhook.EventType `json:"event_types,omitempty"`
+	Cohort     *string             `json:"cohort,omitempty"`
+	Active     *bool               `json:"active,omitempty"`
+}
+
+// webhookCreatedResponse is the created subscription with its secret,
+// which is not returned anywhere else.
+type webhookCreatedResponse struct {
+	*webhook.Subscription
+	Secret string `json:"secret"`
+}
+
+// handleAdminListWebhooks handles GET /api/v1/admin/webhooks
+func (s *Server) handleAdminListWebhooks(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.Webhooks == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Webhooks not configured")
+		return
+	}
+
+	subs, err := s.deps.Admin.Webhooks.List(r.Context())
+	if err != nil {
+		s.logger.Error("failed to list webhook subscriptions", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to list webhook subscriptions")
+		return
+	}
+	if subs == nil {
+		subs = []*webhook.Subscription{}
+	}
+
+	writeJSON(w, http.StatusOK, webhookSubscriptionsResponse{
+		Subscriptions: subs,
+		EventTypes:    webhook.EventTypes(),
+	})
+}
+
+// handleAdminCreateWebhook handles POST /api/v1/admin/webhooks
+func (s *Server) handleAdminCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.Webhooks == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Webhooks not configured")
+		return
+	}
+
+	var req webhookSubscriptionRequest
+	if !decodeAdminJSON(w, r, &req) {
+		return
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		secret = generateWebhookSecret()
+	}
+
+	sub, err := webhook.NewSubscription(uuid.New().String(), req.URL, secret, req.EventTypes, req.Cohort, time.Now())
+	if err != nil {
+		writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid webhook subscription", err.Error())
+		return
+	}
+	if err := s.deps.Admin.Webhooks.Create(r.Context(), sub); err != nil {
+		s.logger.Error("failed to create webhook subscription", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to create webhook subscription")
+		return
+	}
+
+	s.logger.Info("webhook subscription created", logger.String("id", sub.ID), logger.String("url", sub.URL))
+	writeJSON(w, http.StatusCreated, webhookCreatedResponse{Subscription: sub, Secret: secret})
+}
+
+// handleAdminUpdateWebhook handles PATCH /api/v1/admin/webhooks/{id}
+// Re-activating a subscription resets its failure counter.
+func (s *Server) handleAdminUpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.Webhooks == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Webhooks not configured")
+		return
+	}
+
+	var patch webhookSubscriptionPatch
+	if !decodeAdminJSON(w, r, &patch) {
+		return
+	}
+
+	id := r.PathValue("id")
+	sub, err := s.deps.Admin.Webhooks.GetByID(r.Context(), id)
+	if err != nil {
+		s.writeWebhookError(w, err, id, "Failed to get webhook subscription")
+		return
+	}
+
+	now := time.Now()
+	if patch.URL != nil {
+		sub.URL = *patch.URL
+	}
+	if patch.Secret != nil {
+		sub.Secret = *patch.Secret
+	}
+	if patch.EventTypes != nil {
+		sub.EventTypes = patch.EventTypes
+	}
+	if patch.Cohort != nil {
+		sub.Cohort = *patch.Cohort
+	}
+	if patch.Active != nil {
+		if *patch.Active {
+			sub.Activate(now)
+		} else {
+			sub.Deactivate(now)
+		}
+	}
+	sub.UpdatedAt = now.UTC()
+
+	if err := sub.Validate(); err != nil {
+		writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid webhook subscription", err.Error())
+		return
+	}
+	if err := s.deps.Admin.Webhooks.Update(r.Context(), sub); err != nil {
+		s.writeWebhookError(w, err, id, "Failed to update webhook subscription")
+		return
+	}
+
+	s.logger.Info("webhook subscription updated", logger.String("id", id))
+	writeJSON(w, http.StatusOK, sub)
+}
+
+// handleAdminDeleteWebhook handles DELETE /api/v1/admin/webhooks/{id}
+func (s *Server) handleAdminDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.Webhooks == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Webhooks not configured")
+		return
+	}
+
+	id := r.PathValue("id")
+	if err := s.deps.Admin.Webhooks.Delete(r.Context(), id); err != nil {
+		s.writeWebhookError(w, err, id, "Failed to delete webhook subscription")
+		return
+	}
+
+	s.logger.Info("webhook subscription deleted", logger.String("id", id))
+	writeJSON(w, http.StatusOK, map[string]any{"id": id, "status": "deleted"})
+}
+
+// writeWebhookError maps repository errors to responses.
+func (s *Server) writeWebhookError(w http.ResponseWriter, err error, id, message string) {
+	if shared.IsNotFound(err) {
+		writeJSONError(w, http.StatusNotFound, "not_found", "Webhook subscription not found")
+		return
+	}
+	s.logger.Error(message, logger.Err(err), logger.String("id", id))
+	writeJSONError(w, http.StatusInternalServerError, "internal_error", message)
+}
+
+// decodeAdminJSON decodes a small JSON body, rejecting unknown fields.
+func decodeAdminJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	decoder := json.NewDecoder(io.LimitReader(r.Body, 1<<16))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid JSON payload", err.Error())
+		return false
+	}
+	return true
+}
+
+// generateWebhookSecret returns a random 32-byte hex secret.
+func generateWebhookSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// COMMUNITY EVENT HANDLERS (admin)
+// ══════════════════════════════════════════════════════════════════════════════
+
+// eventRequest is the body of POST /api/v1/admin/events. Times are RFC3339
+// or local Almaty time ("2026-05-04 09:00").
+type eventRequest struct {
+	Name     string       `json:"name"`
+	StartsAt string       `json:"starts_at"`
+	EndsAt   string       `json:"ends_at"`
+	Metric   event.Metric `json:"metric"`
+}
+
+// eventsResponse is the list of latest events with the available metrics.
+type eventsResponse struct {
+	Events  []*event.Event `json:"events"`
+	Metrics []event.Metric `json:"metrics"`
+}
+
+// handleAdminListEvents handles GET /api/v1/admin/events
+func (s *Server) handleAdminListEvents(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.Events == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Events not configured")
+		return
+	}
+
+	events, err := s.deps.Admin.Events.List(r.Context(), 50)
+	if err != nil {
+		s.logger.Error("failed to list events", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to list events")
+		return
+	}
+	if events == nil {
+		events = []*event.Event{}
+	}
+
+	writeJSON(w, http.StatusOK, eventsResponse{Events: events, Metrics: event.Metrics()})
+}
+
+// handleAdminCreateEvent handles POST /api/v1/admin/events
+func (s *Server) handleAdminCreateEvent(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.Events == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Events not configured")
+		return
+	}
+
+	var req eventRequest
+	if !decodeAdminJSON(w, r, &req) {
+		return
+	}
+
+	startsAt, err := parseEventTime(req.StartsAt)
+	if err != nil {
+		writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid starts_at", err.Error())
+		return
+	}
+	endsAt, err := parseEventTime(req.EndsAt)
+	if err != nil {
+		writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid ends_at", err.Error())
+		return
+	}
+
+	e, err := event.NewEvent(uuid.New().String(), req.Name, startsAt, endsAt, req.Metric, time.Now())
+	if err != nil {
+		writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid event", err.Error())
+		return
+	}
+	if err := s.deps.Admin.Events.Create(r.Context(), e); err != nil {
+		s.logger.Error("failed to create event", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to create event")
+		return
+	}
+
+	s.logger.Info("event created", logger.String("id", e.ID), logger.String("name", e.Name))
+	writeJSON(w, http.StatusCreated, e)
+}
+
+// handleAdminCloseEvent handles POST /api/v1/admin/events/{id}/close
+// The window is cut at the current time; the worker then freezes the results
+// and announces the winners as for an event that ended on schedule.
+func (s *Server) handleAdminCloseEvent(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.Events == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Events not configured")
+		return
+	}
+
+	id := r.PathValue("id")
+	e, err := s.deps.Admin.Events.GetByID(r.Context(), id)
+	if err == nil {
+		if err = e.Close(time.Now()); err == nil {
+			err = s.deps.Admin.Events.Update(r.Context(), e)
+		}
+	}
+	if err != nil {
+		switch {
+		case shared.IsNotFound(err):
+			writeJSONError(w, http.StatusNotFound, "not_found", "Event not found")
+		case errors.Is(err, event.ErrEventFinalized):
+			writeJSONError(w, http.StatusConflict, "conflict", "Event is already finalized")
+		default:
+			s.logger.Error("failed to close event", logger.Err(err), logger.String("id", id))
+			writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to close event")
+		}
+		return
+	}
+
+	s.logger.Info("event closed", logger.String("id", id))
+	writeJSON(w, http.StatusOK, e)
+}
+
+// parseEventTime parses RFC3339 or local Almaty time.
+func parseEventTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02 15:04"} {
+		if t, err := timeutil.ParseAlmaty(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("expected RFC3339 or YYYY-MM-DD HH:MM, got %q", value)
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// EXPERIMENT HANDLERS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// experimentRequest is the body of POST /api/v1/admin/experiments. Times are
+// RFC3339 or local Almaty time; starts_at defaults to now, ends_at to "until
+// stopped".
+type experimentRequest struct {
+	Name             string               `json:"name"`
+	NotificationType string               `json:"notification_type"`
+	Variants         []experiment.Variant `json:"variants"`
+	StartsAt         string               `json:"starts_at,omitempty"`
+	EndsAt           string               `json:"ends_at,omitempty"`
+}
+
+// experimentsResponse is the list of latest experiments.
+type experimentsResponse struct {
+	Experiments []*experiment.Experiment `json:"experiments"`
+}
+
+// experimentResultsResponse is the outcome of an experiment as last computed
+// by the worker.
+type experimentResultsResponse struct {
+	Experiment *experiment.Experiment `json:"experiment"`
+	Results    []experiment.Result    `json:"results"`
+	ComputedAt *time.Time             `json:"computed_at,omitempty"`
+	// WindowHours is the attribution window: an exposure converts when the
+	// student gains XP within it.
+	WindowHours int `json:"window_hours"`
+}
+
+// handleAdminListExperiments handles GET /api/v1/admin/experiments
+func (s *Server) handleAdminListExperiments(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.Experiments == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Experiments not configured")
+		return
+	}
+
+	experiments, err := s.deps.Admin.Experiments.List(r.Context(), 50)
+	if err != nil {
+		s.logger.Error("failed to list experiments", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to list experiments")
+		return
+	}
+	if experiments == nil {
+		experiments = []*experiment.Experiment{}
+	}
+
+	writeJSON(w, http.StatusOK, experimentsResponse{Experiments: experiments})
+}
+
+// handleAdminCreateExperiment handles POST /api/v1/admin/experiments
+// Every variant must have a template for the notification type, otherwise
+// its students would silently get the default wording.
+func (s *Server) handleAdminCreateExperiment(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.Experiments == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Experiments not configured")
+		return
+	}
+
+	var req experimentRequest
+	if !decodeAdminJSON(w, r, &req) {
+		return
+	}
+
+	now := time.Now()
+	startsAt := now
+	if req.StartsAt != "" {
+		t, err := parseEventTime(req.StartsAt)
+		if err != nil {
+			writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid starts_at", err.Error())
+			return
+		}
+		startsAt = t
+	}
+	var endsAt *time.Time
+	if req.EndsAt != "" {
+		t, err := parseEventTime(req.EndsAt)
+		if err != nil {
+			writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid ends_at", err.Error())
+			return
+		}
+		endsAt = &t
+	}
+
+	for _, v := range req.Variants {
+		if _, ok := notification.VariantTemplate(notification.NotificationType(req.NotificationType), v.Name); !ok {
+			writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Unknown variant",
+				fmt.Sprintf("no %q template for notification type %q", v.Name, req.NotificationType))
+			return
+		}
+	}
+
+	e, err := experiment.NewExperiment(uuid.New().String(), req.Name, req.NotificationType, req.Variants, startsAt, endsAt, now)
+	if err != nil {
+		writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid experiment", err.Error())
+		return
+	}
+	if err := s.deps.Admin.Experiments.Create(r.Context(), e); err != nil {
+		if shared.IsAlreadyExists(err) {
+			writeJSONError(w, http.StatusConflict, "conflict", "Experiment with this name already exists")
+			return
+		}
+		s.logger.Error("failed to create experiment", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to create experiment")
+		return
+	}
+
+	s.logger.Info("experiment created", logger.String("id", e.ID), logger.String("name", e.Name))
+	writeJSON(w, http.StatusCreated, e)
+}
+
+// handleAdminStopExperiment handles POST /api/v1/admin/experiments/{id}/stop
+// Exposures already recorded keep counting until their window closes.
+func (s *Server) handleAdminStopExperiment(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.Experiments == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Experiments not configured")
+		return
+	}
+
+	id := r.PathValue("id")
+	e, err := s.deps.Admin.Experiments.GetByID(r.Context(), id)
+	if err == nil {
+		if err = e.Stop(time.Now()); err == nil {
+			err = s.deps.Admin.Experiments.Update(r.Context(), e)
+		}
+	}
+	if err != nil {
+		switch {
+		case shared.IsNotFound(err):
+			writeJSONError(w, http.StatusNotFound, "not_found", "Experiment not found")
+		case errors.Is(err, experiment.ErrExperimentStopped):
+			writeJSONError(w, http.StatusConflict, "conflict", "Experiment is already stopped")
+		default:
+			s.logger.Error("failed to stop experiment", logger.Err(err), logger.String("id", id))
+			writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to stop experiment")
+		}
+		return
+	}
+
+	s.logger.Info("experiment stopped", logger.String("id", id))
+	writeJSON(w, http.StatusOK, e)
+}
+
+// handleAdminGetExperimentResults handles GET /api/v1/admin/experiments/{id}/results
+func (s *Server) handleAdminGetExperimentResults(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.Experiments == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Experiments not configured")
+		return
+	}
+
+	id := r.PathValue("id")
+	e, err := s.deps.Admin.Experiments.GetByID(r.Context(), id)
+	if err != nil {
+		if shared.IsNotFound(err) {
+			writeJSONError(w, http.StatusNotFound, "not_found", "Experiment not found")
+			return
+		}
+		s.logger.Error("failed to get experiment", logger.Err(err), logger.String("id", id))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to get experiment")
+		return
+	}
+
+	results, computedAt, err := s.deps.Admin.Experiments.GetResults(r.Context(), id)
+	if err != nil {
+		s.logger.Error("failed to get experiment results", logger.Err(err), logger.String("id", id))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to get experiment results")
+		return
+	}
+
+	// Variants in the experiment's order, with zeros for those not computed yet
+	resp := experimentResultsResponse{
+		Experiment:  e,
+		Results:     e.Summarize(nil, time.Now()),
+		WindowHours: int(experiment.OutcomeWindow / time.Hour),
+	}
+	for i, v := range resp.Results {
+		for _, res := range results {
+			if res.Variant == v.Variant {
+				resp.Results[i] = res
+			}
+		}
+	}
+	if !computedAt.IsZero() {
+		resp.ComputedAt = &computedAt
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// BACKFILL HANDLERS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// backfillRequest is the body of POST /api/v1/admin/backfill. rate_share is
+// the part of the Alem rate budget the run may use (default 0.25).
+type backfillRequest struct {
+	DryRun    bool     `json:"dry_run"`
+	RateShare *float64 `json:"rate_share,omitempty"`
+}
+
+// backfillRunResponse is a backfill run with its progress and ETA.
+type backfillRunResponse struct {
+	*backfill.Run
+	Progress backfill.Progress `json:"progress"`
+}
+
+// backfillRunsResponse is the list of latest backfill runs.
+type backfillRunsResponse struct {
+	Runs []backfillRunResponse `json:"runs"`
+}
+
+func newBackfillRunResponse(run *backfill.Run) backfillRunResponse {
+	return backfillRunResponse{Run: run, Progress: run.Progress()}
+}
+
+// handleAdminListBackfillRuns handles GET /api/v1/admin/backfill
+func (s *Server) handleAdminListBackfillRuns(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.Backfill == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Backfill not configured")
+		return
+	}
+
+	runs, err := s.deps.Admin.Backfill.ListRuns(r.Context(), 20)
+	if err != nil {
+		s.logger.Error("failed to list backfill runs", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to list backfill runs")
+		return
+	}
+
+	resp := backfillRunsResponse{Runs: make([]backfillRunResponse, 0, len(runs))}
+	for _, run := range runs {
+		resp.Runs = append(resp.Runs, newBackfillRunResponse(run))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleAdminStartBackfill handles POST /api/v1/admin/backfill
+// The worker picks the run up on its next tick.
+func (s *Server) handleAdminStartBackfill(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.Backfill == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Backfill not configured")
+		return
+	}
+
+	var req backfillRequest
+	if !decodeAdminJSON(w, r, &req) {
+		return
+	}
+	share := backfill.DefaultRateShare
+	if req.RateShare != nil {
+		share = *req.RateShare
+	}
+
+	run, err := backfill.Start(r.Context(), s.deps.Admin.Backfill, uuid.New().String(), req.DryRun, share, time.Now())
+	if err != nil {
+		switch {
+		case shared.IsAlreadyExists(err):
+			writeJSONError(w, http.StatusConflict, "conflict", "Another backfill run is in progress")
+		case shared.IsValidation(err):
+			writeJSONErrorWithDetails(w, http.StatusBadRequest, "invalid_request", "Invalid backfill run", err.Error())
+		default:
+			s.logger.Error("failed to start backfill", logger.Err(err))
+			writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to start backfill")
+		}
+		return
+	}
+
+	s.logger.Info("backfill started", logger.String("id", run.ID), logger.Bool("dry_run", run.DryRun))
+	writeJSON(w, http.StatusCreated, newBackfillRunResponse(run))
+}
+
+// handleAdminPauseBackfill handles POST /api/v1/admin/backfill/{id}/pause
+func (s *Server) handleAdminPauseBackfill(w http.ResponseWriter, r *http.Request) {
+	s.changeBackfillStatus(w, r, "pause", (*backfill.Run).Pause)
+}
+
+// handleAdminResumeBackfill handles POST /api/v1/admin/backfill/{id}/resume
+func (s *Server) handleAdminResumeBackfill(w http.ResponseWriter, r *http.Request) {
+	s.changeBackfillStatus(w, r, "resume", (*backfill.Run).Resume)
+}
+
+// handleAdminCancelBackfill handles POST /api/v1/admin/backfill/{id}/cancel
+// Stored completions stay; a new run starts from the first student.
+func (s *Server) handleAdminCancelBackfill(w http.ResponseWriter, r *http.Request) {
+	s.changeBackfillStatus(w, r, "cancel", (*backfill.Run).Cancel)
+}
+
+// changeBackfillStatus applies a transition to a run. The worker may change
+// the status concurrently (completing the run), so the update is conditional.
+func (s *Server) changeBackfillStatus(w http.ResponseWriter, r *http.Request, action string, transition func(*backfill.Run, time.Time) error) {
+	if s.deps.Admin.Backfill == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Backfill not configured")
+		return
+	}
+
+	id := r.PathValue("id")
+	run, err := s.deps.Admin.Backfill.GetRun(r.Context(), id)
+	if err == nil {
+		from := run.Status
+		if err = transition(run, time.Now()); err == nil {
+			err = s.deps.Admin.Backfill.UpdateStatus(r.Context(), run, from)
+		}
+	}
+	if err != nil {
+		switch {
+		case shared.IsNotFound(err):
+			writeJSONError(w, http.StatusNotFound, "not_found", "Backfill run not found")
+		case errors.Is(err, backfill.ErrInvalidTransition):
+			writeJSONErrorWithDetails(w, http.StatusConflict, "conflict", "Can't "+action+" backfill run",
+				fmt.Sprintf("run is %s", run.Status))
+		default:
+			s.logger.Error("failed to "+action+" backfill run", logger.Err(err), logger.String("id", id))
+			writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to "+action+" backfill run")
+		}
+		return
+	}
+
+	s.logger.Info("backfill run "+string(run.Status), logger.String("id", id))
+	writeJSON(w, http.StatusOK, newBackfillRunResponse(run))
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// WEBHOOK HANDLERS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// handleTelegramWebhook handles POST /webhook/telegram
+func (s *Server) handleTelegramWebhook(w http.ResponseWriter, r *http.Request) {
+	s.processTelegramWebhook(w, r, "")
+}
+
+// handleTelegramWebhookWithToken handles POST /webhook/telegram/{token}
+func (s *Server) handleTelegramWebhookWithToken(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("token")
+	s.processTelegramWebhook(w, r, token)
+}
+
+// telegramSecretTokenHeader carries the secret_token given to setWebhook.
+const telegramSecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// processTelegramWebhook is the internal implementation for webhook processing.
+func (s *Server) processTelegramWebhook(w http.ResponseWriter, r *http.Request, token string) {
+	// Reject forged updates before the body is read
+	if !s.validTelegramSecretToken(r) {
+		s.logger.Warn("invalid telegram secret token", logger.String("ip", getClientIP(r)))
+		writeJSONError(w, http.StatusForbidden, "forbidden", "Invalid secret token")
+		return
+	}
+
+	// Validate token if configured
+	if s.config.WebhookSecret != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.config.WebhookSecret)) != 1 {
+		s.logger.Warn("invalid webhook token", logger.String("ip", getClientIP(r)))
+		writeJSONError(w, http.StatusUnauthorized, "unauthorized", "Invalid webhook token")
+		return
+	}
+
+	// Read body (the size is limited by the webhook module)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.logger.Error("failed to read webhook body", logger.Err(err))
+		writeJSONError(w, http.StatusBadRequest, "invalid_request", "Failed to read request body")
+		return
+	}
+	defer r.Body.Close()
+
+	// Delegate to webhook handler if configured. The handler parses the body
+	// itself and ignores malformed or unsupported updates; any answer other
+	// than 200 makes Telegram re-deliver the same update.
+	if s.deps.Webhook.Telegram != nil {
+		if err := s.deps.Webhook.Telegram.HandleTelegramUpdate(r.Context(), body); err != nil {
 			s.logger.Error("failed to handle telegram update", logger.Err(err))
-			// Still return 200 to Telegram to avoid retries
 		}
 	}
 
 	// Always return 200 to acknowledge receipt
 	writeJSON(w, http.StatusOK, map[string]string{"status": "received"})
 }
+
+// validTelegramSecretToken reports whether the request carries the configured
+// secret token. The comparison takes constant time.
+func (s *Server) validTelegramSecretToken(r *http.Request) bool {
+	want := s.config.TelegramSecretToken
+	if want == "" {
+		return true
+	}
+	got := r.Header.Get(telegramSecretTokenHeader)
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+// maxDryRunOutboxLimit caps a dry-run outbox page.
+const maxDryRunOutboxLimit = 500
+
+// dryRunOutboxResponse is a page of recorded dry-run messages.
+type dryRunOutboxResponse struct {
+	Messages []notification.DryRunEntry `json:"messages"`
+	Count    int                        `json:"count"`
+}
+
+// dryRunPurgeResponse reports how many recorded messages were deleted.
+type dryRunPurgeResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
+// handleAdminListDryRunOutbox handles GET /api/v1/admin/dry-run/outbox
+// Query params: job, type, chat_id, since, until, limit (default: 100).
+func (s *Server) handleAdminListDryRunOutbox(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.DryRunOutbox == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Dry-run outbox not configured")
+		return
+	}
+
+	filter, ok := parseDryRunFilter(w, r)
+	if !ok {
+		return
+	}
+	filter.Limit = getQueryParamInt(r, "limit", 100)
+	if filter.Limit < 1 || filter.Limit > maxDryRunOutboxLimit {
+		writeJSONError(w, http.StatusBadRequest, "invalid_request", fmt.Sprintf("limit must be between 1 and %d", maxDryRunOutboxLimit))
+		return
+	}
+
+	entries, err := s.deps.Admin.DryRunOutbox.List(r.Context(), filter)
+	if err != nil {
+		s.logger.Error("failed to list dry-run outbox", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to list dry-run outbox")
+		return
+	}
+	if entries == nil {
+		entries = []notification.DryRunEntry{}
+	}
+
+	writeJSON(w, http.StatusOK, dryRunOutboxResponse{Messages: entries, Count: len(entries)})
+}
+
+// handleAdminPurgeDryRunOutbox handles DELETE /api/v1/admin/dry-run/outbox
+// Query params: job, type, chat_id, since, until; none purges everything.
+func (s *Server) handleAdminPurgeDryRunOutbox(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.DryRunOutbox == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Dry-run outbox not configured")
+		return
+	}
+
+	filter, ok := parseDryRunFilter(w, r)
+	if !ok {
+		return
+	}
+
+	deleted, err := s.deps.Admin.DryRunOutbox.Purge(r.Context(), filter)
+	if err != nil {
+		s.logger.Error("failed to purge dry-run outbox", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to purge dry-run outbox")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dryRunPurgeResponse{Deleted: deleted})
+}
+
+// parseDryRunFilter reads the dry-run outbox filters. On an invalid value it
+// writes a 400 response and returns false.
+func parseDryRunFilter(w http.ResponseWriter, r *http.Request) (notification.DryRunFilter, bool) {
+	filter := notification.DryRunFilter{
+		Job:  getQueryParam(r, "job", ""),
+		Type: getQueryParam(r, "type", ""),
+	}
+
+	if v := getQueryParam(r, "chat_id", ""); v != "" {
+		chatID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid_request", "chat_id must be an integer")
+			return filter, false
+		}
+		filter.ChatID = chatID
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &filter.Since}, {"until", &filter.Until}} {
+		v := getQueryParam(r, p.name, "")
+		if v == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid_request", p.name+" must be an RFC 3339 timestamp")
+			return filter, false
+		}
+		*p.dst = parsed
+	}
+
+	return filter, true
+}
@@ -3,6 +3,9 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 )
@@ -62,6 +65,9 @@ type CheckResult struct {
 	// Duration is how long the check took.
 	Duration string `json:"duration,omitempty"`
 
+	// LatencyMS is Duration in milliseconds.
+	LatencyMS int64 `json:"latency_ms"`
+
 	// LastChecked is when this check was last performed.
 	LastChecked time.Time `json:"last_checked,omitempty"`
 }
@@ -90,7 +96,10 @@ func NewCompositeHealthChecker(version string) *CompositeHealthChecker {
 }
 
 // SetTimeout sets the timeout for individual health checks.
+// A check still running after it is reported as failed.
 func (c *CompositeHealthChecker) SetTimeout(timeout time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.timeout = timeout
 }
 
@@ -115,6 +124,7 @@ func (c *CompositeHealthChecker) Check(ctx context.Context) HealthStatus {
 	for name, check := range c.checks {
 		checks[name] = check
 	}
+	timeout := c.timeout
 	c.mu.RUnlock()
 
 	status := HealthStatus{
@@ -144,25 +154,7 @@ func (c *CompositeHealthChecker) Check(ctx context.Context) HealthStatus {
 		go func(name string, check HealthCheckFunc) {
 			defer wg.Done()
 
-			// Create context with timeout
-			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
-			defer cancel()
-
-			start := time.Now()
-			err := check(checkCtx)
-			duration := time.Since(start)
-
-			result := CheckResult{
-				Healthy:     err == nil,
-				Duration:    duration.Round(time.Millisecond).String(),
-				LastChecked: time.Now().UTC(),
-			}
-
-			if err != nil {
-				result.Message = err.Error()
-			} else {
-				result.Message = "OK"
-			}
+			result := runCheck(ctx, check, timeout)
 
 			results <- struct {
 				name   string
@@ -189,6 +181,7 @@ func (c *CompositeHealthChecker) Check(ctx context.Context) HealthStatus {
 	}
 
 	// Set message based on results
+	sort.Strings(unhealthyChecks)
 	if status.Healthy {
 		status.Message = "All checks passed"
 	} else {
@@ -198,6 +191,42 @@ func (c *CompositeHealthChecker) Check(ctx context.Context) HealthStatus {
 	return status
 }
 
+// runCheck runs check with timeout. A check that ignores its context is
+// reported as failed once the timeout passes and left to finish on its own.
+func runCheck(ctx context.Context, check HealthCheckFunc, timeout time.Duration) CheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check(checkCtx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-checkCtx.Done():
+		err = checkCtx.Err()
+	}
+	if err != nil && errors.Is(checkCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+	duration := time.Since(start)
+
+	result := CheckResult{
+		Healthy:     err == nil,
+		Message:     "OK",
+		Duration:    duration.Round(time.Millisecond).String(),
+		LatencyMS:   duration.Milliseconds(),
+		LastChecked: time.Now().UTC(),
+	}
+	if err != nil {
+		result.Message = err.Error()
+	}
+	return result
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // PREDEFINED HEALTH CHECKS
 // ══════════════════════════════════════════════════════════════════════════════
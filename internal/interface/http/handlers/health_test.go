@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompositeHealthChecker_SlowCheckTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	checker := NewCompositeHealthChecker("test")
+	checker.SetTimeout(50 * time.Millisecond)
+	// Ignores its context, like a driver stuck on a dead connection
+	checker.AddCheck("alem_api", func(context.Context) error {
+		<-release
+		return nil
+	})
+	checker.AddCheck("database", func(context.Context) error { return nil })
+	checker.AddCheck("redis", func(context.Context) error { return errors.New("connection refused") })
+
+	start := time.Now()
+	status := checker.Check(context.Background())
+	assert.Less(t, time.Since(start), time.Second)
+
+	assert.False(t, status.Ready)
+	require.Len(t, status.Checks, 3)
+	assert.True(t, status.Checks["database"].Healthy)
+	assert.Equal(t, "connection refused", status.Checks["redis"].Message)
+
+	slow := status.Checks["alem_api"]
+	assert.False(t, slow.Healthy)
+	assert.Contains(t, slow.Message, "timed out after 50ms")
+	assert.GreaterOrEqual(t, slow.LatencyMS, int64(50))
+	assert.Equal(t, "Some checks failed: alem_api, redis", status.Message)
+}
@@ -49,6 +49,20 @@ func (a *APIKeyAuth) RemoveKey(key string) {
 	delete(a.validKeys, key)
 }
 
+// SetKeys replaces all valid API keys, e.g. on configuration reload.
+func (a *APIKeyAuth) SetKeys(keys []string) {
+	validKeys := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key != "" {
+			validKeys[key] = true
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.validKeys = validKeys
+}
+
 // IsValid checks if an API key is valid.
 func (a *APIKeyAuth) IsValid(key string) bool {
 	a.mu.RLock()
@@ -4,8 +4,12 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/logger"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -13,8 +17,10 @@ import (
 // ══════════════════════════════════════════════════════════════════════════════
 
 // TelegramUpdate represents a Telegram webhook update.
+// Only the supported update types are decoded; Type tells which one is set.
 type TelegramUpdate struct {
 	UpdateID      int64                  `json:"update_id"`
+	Type          UpdateType             `json:"-"`
 	Message       *TelegramMessage       `json:"message,omitempty"`
 	CallbackQuery *TelegramCallbackQuery `json:"callback_query,omitempty"`
 	InlineQuery   *TelegramInlineQuery   `json:"inline_query,omitempty"`
@@ -73,6 +79,157 @@ type MessageEntity struct {
 	Length int    `json:"length"`
 }
 
+// ══════════════════════════════════════════════════════════════════════════════
+// UPDATE CLASSIFICATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// UpdateType is the kind of a Telegram update (the name of its payload field).
+type UpdateType string
+
+const (
+	// Supported update types.
+	UpdateTypeMessage       UpdateType = "message"
+	UpdateTypeCallbackQuery UpdateType = "callback_query"
+	UpdateTypeInlineQuery   UpdateType = "inline_query"
+
+	// Known update types the bot does not handle.
+	UpdateTypeEditedMessage      UpdateType = "edited_message"
+	UpdateTypeChannelPost        UpdateType = "channel_post"
+	UpdateTypeEditedChannelPost  UpdateType = "edited_channel_post"
+	UpdateTypeChosenInlineResult UpdateType = "chosen_inline_result"
+	UpdateTypeShippingQuery      UpdateType = "shipping_query"
+	UpdateTypePreCheckoutQuery   UpdateType = "pre_checkout_query"
+	UpdateTypePoll               UpdateType = "poll"
+	UpdateTypePollAnswer         UpdateType = "poll_answer"
+	UpdateTypeMyChatMember       UpdateType = "my_chat_member"
+	UpdateTypeChatMember         UpdateType = "chat_member"
+	UpdateTypeChatJoinRequest    UpdateType = "chat_join_request"
+
+	// UpdateTypeUnknown is an update without any recognized payload field.
+	UpdateTypeUnknown UpdateType = "unknown"
+
+	// UpdateTypeMalformed is a body that could not be parsed as an update.
+	UpdateTypeMalformed UpdateType = "malformed"
+)
+
+// knownUpdateTypes lists update types in classification order.
+var knownUpdateTypes = []UpdateType{
+	UpdateTypeMessage,
+	UpdateTypeCallbackQuery,
+	UpdateTypeInlineQuery,
+	UpdateTypeEditedMessage,
+	UpdateTypeChannelPost,
+	UpdateTypeEditedChannelPost,
+	UpdateTypeChosenInlineResult,
+	UpdateTypeShippingQuery,
+	UpdateTypePreCheckoutQuery,
+	UpdateTypePoll,
+	UpdateTypePollAnswer,
+	UpdateTypeMyChatMember,
+	UpdateTypeChatMember,
+	UpdateTypeChatJoinRequest,
+}
+
+// IsSupported reports whether the bot routes updates of this type.
+func (t UpdateType) IsSupported() bool {
+	switch t {
+	case UpdateTypeMessage, UpdateTypeCallbackQuery, UpdateTypeInlineQuery:
+		return true
+	default:
+		return false
+	}
+}
+
+// ErrMalformedUpdate is returned when a payload is not a valid Telegram update.
+var ErrMalformedUpdate = errors.New("malformed telegram update")
+
+// ParseTelegramUpdate classifies a webhook payload and decodes its supported part.
+// Unsupported and unknown types are returned with only UpdateID and Type set,
+// so new or unusual Telegram payloads never fail parsing.
+func ParseTelegramUpdate(payload []byte) (*TelegramUpdate, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil || fields == nil {
+		return nil, fmt.Errorf("%w: not a JSON object", ErrMalformedUpdate)
+	}
+
+	update := &TelegramUpdate{Type: UpdateTypeUnknown}
+	if raw, ok := fields["update_id"]; ok {
+		// A bad update_id only affects logging, not routing.
+		_ = json.Unmarshal(raw, &update.UpdateID)
+	}
+
+	for _, t := range knownUpdateTypes {
+		if raw, ok := fields[string(t)]; ok && !isJSONNull(raw) {
+			update.Type = t
+			break
+		}
+	}
+
+	var target any
+	switch update.Type {
+	case UpdateTypeMessage:
+		target = &update.Message
+	case UpdateTypeCallbackQuery:
+		target = &update.CallbackQuery
+	case UpdateTypeInlineQuery:
+		target = &update.InlineQuery
+	default:
+		return update, nil
+	}
+
+	if err := json.Unmarshal(fields[string(update.Type)], target); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrMalformedUpdate, update.Type, err)
+	}
+
+	return update, nil
+}
+
+// isJSONNull reports whether raw is the JSON null literal.
+func isJSONNull(raw json.RawMessage) bool {
+	return string(raw) == "null"
+}
+
+// maxPayloadDump is the number of payload bytes included in logs.
+const maxPayloadDump = 512
+
+// TruncatePayload returns payload as a string cut to maxPayloadDump bytes, for logging.
+func TruncatePayload(payload []byte) string {
+	if len(payload) <= maxPayloadDump {
+		return string(payload)
+	}
+	return fmt.Sprintf("%s...(%d bytes total)", payload[:maxPayloadDump], len(payload))
+}
+
+// DeadUpdateCounter counts updates that were acknowledged but not handled, by type.
+type DeadUpdateCounter struct {
+	mu     sync.Mutex
+	counts map[UpdateType]int64
+}
+
+// NewDeadUpdateCounter creates an empty counter.
+func NewDeadUpdateCounter() *DeadUpdateCounter {
+	return &DeadUpdateCounter{counts: make(map[UpdateType]int64)}
+}
+
+// Record increments the counter for the update type.
+func (c *DeadUpdateCounter) Record(t UpdateType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[t]++
+}
+
+// Snapshot returns a copy of the counters.
+func (c *DeadUpdateCounter) Snapshot() map[UpdateType]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make(map[UpdateType]int64, len(c.counts))
+	for t, n := range c.counts {
+		result[t] = n
+	}
+	return result
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // WEBHOOK HANDLER IMPLEMENTATION
 // ══════════════════════════════════════════════════════════════════════════════
@@ -86,19 +243,29 @@ type CommandHandler func(ctx context.Context, message *TelegramMessage, args str
 // CallbackHandler is a function that handles a callback query.
 type CallbackHandler func(ctx context.Context, callback *TelegramCallbackQuery) error
 
+// InlineQueryHandler is a function that handles an inline query.
+type InlineQueryHandler func(ctx context.Context, query *TelegramInlineQuery) error
+
 // TelegramWebhookHandlerImpl implements WebhookHandler for Telegram.
+// Malformed and unsupported updates are logged, counted and acknowledged
+// without an error, so Telegram does not re-deliver them.
 type TelegramWebhookHandlerImpl struct {
-	mu              sync.RWMutex
-	commandHandlers map[string]CommandHandler
-	callbackHandler CallbackHandler
-	defaultHandler  UpdateHandler
-	errorHandler    func(error)
+	mu                 sync.RWMutex
+	commandHandlers    map[string]CommandHandler
+	callbackHandler    CallbackHandler
+	inlineQueryHandler InlineQueryHandler
+	defaultHandler     UpdateHandler
+	errorHandler       func(error)
+	logger             *logger.Logger
+	deadUpdates        *DeadUpdateCounter
 }
 
 // NewTelegramWebhookHandler creates a new Telegram webhook handler.
 func NewTelegramWebhookHandler() *TelegramWebhookHandlerImpl {
 	return &TelegramWebhookHandlerImpl{
 		commandHandlers: make(map[string]CommandHandler),
+		logger:          logger.Default(),
+		deadUpdates:     NewDeadUpdateCounter(),
 	}
 }
 
@@ -116,6 +283,13 @@ func (h *TelegramWebhookHandlerImpl) RegisterCallback(handler CallbackHandler) {
 	h.callbackHandler = handler
 }
 
+// RegisterInlineQuery registers a handler for inline queries.
+func (h *TelegramWebhookHandlerImpl) RegisterInlineQuery(handler InlineQueryHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.inlineQueryHandler = handler
+}
+
 // RegisterDefault registers a default handler for unhandled updates.
 func (h *TelegramWebhookHandlerImpl) RegisterDefault(handler UpdateHandler) {
 	h.mu.Lock()
@@ -130,14 +304,83 @@ func (h *TelegramWebhookHandlerImpl) SetErrorHandler(handler func(error)) {
 	h.errorHandler = handler
 }
 
+// SetLogger sets the logger.
+func (h *TelegramWebhookHandlerImpl) SetLogger(l *logger.Logger) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.logger = l
+}
+
+// DeadUpdates returns the number of ignored updates by type.
+func (h *TelegramWebhookHandlerImpl) DeadUpdates() map[UpdateType]int64 {
+	return h.deadUpdates.Snapshot()
+}
+
 // HandleTelegramUpdate processes a Telegram webhook update.
+// Only handler failures are returned; bad or unsupported payloads are ignored.
 func (h *TelegramWebhookHandlerImpl) HandleTelegramUpdate(ctx context.Context, payload []byte) error {
-	var update TelegramUpdate
-	if err := json.Unmarshal(payload, &update); err != nil {
-		return fmt.Errorf("failed to parse update: %w", err)
+	update, err := ParseTelegramUpdate(payload)
+	if err != nil {
+		h.deadUpdates.Record(UpdateTypeMalformed)
+		h.log().Warn("ignoring malformed telegram update",
+			logger.Err(err),
+			logger.String("payload", TruncatePayload(payload)),
+		)
+		return nil
+	}
+
+	if reason := ignoreReason(update); reason != "" {
+		h.deadUpdates.Record(update.Type)
+		h.log().Debug("ignoring telegram update",
+			logger.Int64("update_id", update.UpdateID),
+			logger.String("update_type", string(update.Type)),
+			logger.String("reason", reason),
+		)
+		return nil
 	}
 
-	return h.processUpdate(ctx, &update)
+	h.log().Debug("received telegram update",
+		logger.Int64("update_id", update.UpdateID),
+		logger.String("update_type", string(update.Type)),
+	)
+
+	return h.processUpdate(ctx, update)
+}
+
+// ignoreReason returns why an update is not routed (empty - route it).
+func ignoreReason(update *TelegramUpdate) string {
+	switch {
+	case !update.Type.IsSupported():
+		return "unsupported_type"
+	case update.Type == UpdateTypeCallbackQuery && update.CallbackQuery.Data == "":
+		return "empty_callback_data"
+	case !senderValid(update):
+		return "invalid_sender"
+	default:
+		return ""
+	}
+}
+
+// senderValid reports whether the update's sender, if any, has a valid
+// Telegram ID, so a bad ID never reaches the handlers and repositories.
+func senderValid(update *TelegramUpdate) bool {
+	var from *TelegramUser
+	switch {
+	case update.Message != nil:
+		from = update.Message.From
+	case update.CallbackQuery != nil:
+		from = update.CallbackQuery.From
+	case update.InlineQuery != nil:
+		from = update.InlineQuery.From
+	}
+	return from == nil || student.TelegramID(from.ID).IsValid()
+}
+
+// log returns the current logger.
+func (h *TelegramWebhookHandlerImpl) log() *logger.Logger {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.logger
 }
 
 // processUpdate routes the update to the appropriate handler.
@@ -156,6 +399,15 @@ func (h *TelegramWebhookHandlerImpl) processUpdate(ctx context.Context, update *
 		return err
 	}
 
+	// Handle inline queries
+	if update.InlineQuery != nil && h.inlineQueryHandler != nil {
+		err = h.inlineQueryHandler(ctx, update.InlineQuery)
+		if err != nil {
+			h.handleError(err)
+		}
+		return err
+	}
+
 	// Handle messages
 	if update.Message != nil {
 		// Check if it's a command
@@ -189,7 +441,9 @@ func (h *TelegramWebhookHandlerImpl) extractCommand(msg *TelegramMessage) (strin
 	}
 
 	for _, entity := range msg.Entities {
-		if entity.Type == "bot_command" && entity.Offset == 0 {
+		// Entity offsets come from the client; skip ones outside the text.
+		if entity.Type == "bot_command" && entity.Offset == 0 &&
+			entity.Length > 0 && entity.Length <= len(msg.Text) {
 			command := msg.Text[entity.Offset : entity.Offset+entity.Length]
 
 			// Remove bot username if present (e.g., /start@mybot)
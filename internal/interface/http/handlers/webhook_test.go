@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// oddUpdates are real-world payloads the bot does not handle.
+var oddUpdates = map[string]struct {
+	payload  string
+	deadType UpdateType
+}{
+	"edited_message": {
+		payload:  `{"update_id":1,"edited_message":{"message_id":5,"from":{"id":42,"is_bot":false,"first_name":"A"},"chat":{"id":42,"type":"private"},"date":1700000000,"edit_date":1700000100,"text":"/top"}}`,
+		deadType: UpdateTypeEditedMessage,
+	},
+	"channel_post": {
+		payload:  `{"update_id":2,"channel_post":{"message_id":7,"sender_chat":{"id":-100123,"type":"channel","title":"News"},"chat":{"id":-100123,"type":"channel"},"date":1700000000,"text":"hello"}}`,
+		deadType: UpdateTypeChannelPost,
+	},
+	"my_chat_member": {
+		payload:  `{"update_id":3,"my_chat_member":{"chat":{"id":42,"type":"private"},"from":{"id":42,"is_bot":false,"first_name":"A"},"date":1700000000,"old_chat_member":{"user":{"id":1,"is_bot":true,"first_name":"Bot"},"status":"member"},"new_chat_member":{"user":{"id":1,"is_bot":true,"first_name":"Bot"},"status":"kicked","until_date":0}}}`,
+		deadType: UpdateTypeMyChatMember,
+	},
+	"poll": {
+		payload:  `{"update_id":4,"poll":{"id":"5","question":"?","options":[{"text":"a","voter_count":1}],"total_voter_count":1,"is_closed":false,"is_anonymous":true,"type":"regular","allows_multiple_answers":false}}`,
+		deadType: UpdateTypePoll,
+	},
+	"poll_answer": {
+		payload:  `{"update_id":5,"poll_answer":{"poll_id":"5","user":{"id":42,"is_bot":false,"first_name":"A"},"option_ids":[0]}}`,
+		deadType: UpdateTypePollAnswer,
+	},
+	"empty callback data": {
+		payload:  `{"update_id":6,"callback_query":{"id":"99","from":{"id":42,"is_bot":false,"first_name":"A"},"chat_instance":"1","game_short_name":"g"}}`,
+		deadType: UpdateTypeCallbackQuery,
+	},
+	"zero sender id": {
+		payload:  `{"update_id":11,"message":{"message_id":5,"from":{"id":0,"is_bot":false,"first_name":"A"},"chat":{"id":42,"type":"private"},"date":1700000000,"text":"/me"}}`,
+		deadType: UpdateTypeMessage,
+	},
+	"negative sender id": {
+		payload:  `{"update_id":12,"callback_query":{"id":"99","from":{"id":-42,"is_bot":false,"first_name":"A"},"chat_instance":"1","data":"refresh:me"}}`,
+		deadType: UpdateTypeCallbackQuery,
+	},
+	"future update type": {
+		payload:  `{"update_id":7,"message_reaction":{"chat":{"id":42,"type":"private"},"message_id":5}}`,
+		deadType: UpdateTypeUnknown,
+	},
+	"null message": {
+		payload:  `{"update_id":8,"message":null}`,
+		deadType: UpdateTypeUnknown,
+	},
+	"message with wrong field types": {
+		payload:  `{"update_id":9,"message":{"message_id":"five","chat":"private"}}`,
+		deadType: UpdateTypeMalformed,
+	},
+	"truncated body": {
+		payload:  `{"update_id":10,"message":{"message_id":5,"te`,
+		deadType: UpdateTypeMalformed,
+	},
+	"not an object": {
+		payload:  `[1,2,3]`,
+		deadType: UpdateTypeMalformed,
+	},
+	"empty body": {
+		payload:  ``,
+		deadType: UpdateTypeMalformed,
+	},
+}
+
+func TestTelegramWebhookHandler_IgnoresOddUpdates(t *testing.T) {
+	for name, tc := range oddUpdates {
+		t.Run(name, func(t *testing.T) {
+			h := NewTelegramWebhookHandler()
+			called := false
+			h.RegisterCallback(func(context.Context, *TelegramCallbackQuery) error {
+				called = true
+				return nil
+			})
+			h.RegisterDefault(func(context.Context, *TelegramUpdate) error {
+				called = true
+				return nil
+			})
+
+			err := h.HandleTelegramUpdate(context.Background(), []byte(tc.payload))
+
+			require.NoError(t, err)
+			assert.False(t, called, "odd update must not be routed")
+			assert.Equal(t, map[UpdateType]int64{tc.deadType: 1}, h.DeadUpdates())
+		})
+	}
+}
+
+func TestTelegramWebhookHandler_RoutesSupportedUpdates(t *testing.T) {
+	h := NewTelegramWebhookHandler()
+
+	var routed []string
+	h.RegisterCommand("/top", func(_ context.Context, _ *TelegramMessage, args string) error {
+		routed = append(routed, "command:"+args)
+		return nil
+	})
+	h.RegisterCallback(func(_ context.Context, cb *TelegramCallbackQuery) error {
+		routed = append(routed, "callback:"+cb.Data)
+		return nil
+	})
+	h.RegisterInlineQuery(func(_ context.Context, q *TelegramInlineQuery) error {
+		routed = append(routed, "inline:"+q.Query)
+		return nil
+	})
+
+	payloads := []string{
+		`{"update_id":1,"message":{"message_id":1,"chat":{"id":42,"type":"private"},"date":1,"text":"/top 10","entities":[{"type":"bot_command","offset":0,"length":4}]}}`,
+		`{"update_id":2,"callback_query":{"id":"1","from":{"id":42,"is_bot":false,"first_name":"A"},"data":"settings:toggle:buddy_online"}}`,
+		`{"update_id":3,"inline_query":{"id":"1","from":{"id":42,"is_bot":false,"first_name":"A"},"query":"ali","offset":""}}`,
+		// Entity longer than the text must not panic.
+		`{"update_id":4,"message":{"message_id":2,"chat":{"id":42,"type":"private"},"date":1,"text":"/top","entities":[{"type":"bot_command","offset":0,"length":40}]}}`,
+	}
+	for _, p := range payloads {
+		require.NoError(t, h.HandleTelegramUpdate(context.Background(), []byte(p)))
+	}
+
+	assert.Equal(t, []string{
+		"command:10",
+		"callback:settings:toggle:buddy_online",
+		"inline:ali",
+		"command:",
+	}, routed)
+	assert.Empty(t, h.DeadUpdates())
+}
+
+func TestTruncatePayload(t *testing.T) {
+	short := []byte(`{"update_id":1}`)
+	assert.Equal(t, string(short), TruncatePayload(short))
+
+	long := make([]byte, maxPayloadDump+100)
+	for i := range long {
+		long[i] = 'x'
+	}
+	got := TruncatePayload(long)
+	assert.Len(t, got, maxPayloadDump+len("...(612 bytes total)"))
+	assert.Contains(t, got, "(612 bytes total)")
+}
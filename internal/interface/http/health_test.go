@@ -0,0 +1,106 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/interface/http/handlers"
+)
+
+func TestProbes_LivenessIgnoresDependencies(t *testing.T) {
+	checker := handlers.NewCompositeHealthChecker("1.2.3")
+	checker.SetTimeout(20 * time.Millisecond)
+	checker.AddCheck("database", func(context.Context) error { return nil })
+	checker.AddCheck("alem_api", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	config := DefaultConfig()
+	config.Version = "1.2.3"
+	server := NewServer(config, Dependencies{Health: HealthDependencies{HealthChecker: checker}})
+	ts := httptest.NewServer(server.httpServer.Handler)
+	defer ts.Close()
+
+	get := func(path string, data interface{}) int {
+		resp, err := ts.Client().Get(ts.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&JSONResponse{Data: data}))
+		return resp.StatusCode
+	}
+
+	var live map[string]string
+	assert.Equal(t, http.StatusOK, get("/healthz", &live))
+	assert.Equal(t, "alive", live["status"])
+	assert.Equal(t, "1.2.3", live["version"])
+	assert.Contains(t, live, "uptime")
+
+	var ready struct {
+		Status  string             `json:"status"`
+		Failing []readinessFailure `json:"failing"`
+	}
+	assert.Equal(t, http.StatusServiceUnavailable, get("/readyz", &ready))
+	assert.Equal(t, "not_ready", ready.Status)
+	require.Len(t, ready.Failing, 1)
+	assert.Equal(t, "alem_api", ready.Failing[0].Name)
+	assert.Equal(t, "timed out after 20ms", ready.Failing[0].Error)
+	assert.NotEmpty(t, ready.Failing[0].Latency)
+
+	checker.RemoveCheck("alem_api")
+	assert.Equal(t, http.StatusOK, get("/readyz", &map[string]interface{}{}))
+}
+
+// recordingMetrics remembers observed requests.
+type recordingMetrics struct {
+	requests []string
+}
+
+func (m *recordingMetrics) ObserveHTTPRequest(method, route string, status int, _ time.Duration) {
+	m.requests = append(m.requests, fmt.Sprintf("%s %s %d", method, route, status))
+}
+
+func (m *recordingMetrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("alem_hub_up 1\n"))
+	})
+}
+
+func TestMetrics_RecordsRoutePatterns(t *testing.T) {
+	metrics := &recordingMetrics{}
+	server := NewServer(DefaultConfig(), Dependencies{Metrics: metrics})
+	ts := httptest.NewServer(server.httpServer.Handler)
+	defer ts.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := ts.Client().Get(ts.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	status, _ := get("/healthz")
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = get("/api/v1/students/s-42/rank")
+	assert.Equal(t, http.StatusNotImplemented, status)
+	status, body := get("/metrics")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "alem_hub_up 1\n", body)
+
+	assert.Equal(t, []string{
+		"GET /healthz 200",
+		"GET /api/v1/students/{id}/rank 501",
+		"GET /metrics 200",
+	}, metrics.requests)
+}
@@ -0,0 +1,243 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/cqrs"
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/pkg/logger"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// LANDING PAGE STATS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// GET /stats.json gives the project's landing page live community numbers.
+// It needs no auth, so it returns aggregates only: counts and durations,
+// never an ID or a name, even where the underlying query carries them. The
+// numbers come from the query handlers of the public API and are rebuilt at
+// most every landingStatsTTL; clients revalidate with the ETag.
+
+// landingStatsTTL is how long the numbers are served before they are rebuilt.
+const landingStatsTTL = 5 * time.Minute
+
+// LandingStats is the body of GET /stats.json.
+type LandingStats struct {
+	// ActiveStudents - students on the leaderboard.
+	ActiveStudents int `json:"active_students"`
+
+	// WeeklyXP - XP earned by all cohorts since Monday (Almaty time).
+	WeeklyXP int `json:"weekly_xp"`
+
+	// HelpRequestsResolved - help requests resolved, all-time.
+	HelpRequestsResolved int `json:"help_requests_resolved"`
+
+	// ResponseMinutes - median time to the first helper response over the
+	// last query.DefaultResponseTimeWeeks weeks; null without responses.
+	ResponseMinutes *float64 `json:"response_minutes"`
+
+	// OnlineNow - students online now.
+	OnlineNow int `json:"online_now"`
+
+	// LongestActiveStreak - the longest running streak in days.
+	LongestActiveStreak int `json:"longest_active_streak"`
+
+	// UpdatedAt - when the numbers were collected.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// landingStatsCache is the last encoded /stats.json body.
+type landingStatsCache struct {
+	mu        sync.Mutex
+	body      []byte
+	etag      string
+	expiresAt time.Time
+}
+
+// get returns the cached body, rebuilding it with build once it has expired.
+// Requests wait for a rebuild in progress instead of starting their own. A
+// failed rebuild keeps serving the previous body, if there is one.
+func (c *landingStatsCache) get(now time.Time, build func() (*LandingStats, error)) (body []byte, etag string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.body != nil && now.Before(c.expiresAt) {
+		return c.body, c.etag, nil
+	}
+
+	stats, err := build()
+	if err == nil {
+		body, err = json.Marshal(stats)
+	}
+	if err != nil {
+		if c.body != nil {
+			return c.body, c.etag, err
+		}
+		return nil, "", err
+	}
+
+	sum := sha256.Sum256(body)
+	c.body = body
+	c.etag = `"` + hex.EncodeToString(sum[:8]) + `"`
+	c.expiresAt = now.Add(landingStatsTTL)
+	return c.body, c.etag, nil
+}
+
+// handleLandingStats handles GET /stats.json
+func (s *Server) handleLandingStats(w http.ResponseWriter, r *http.Request) {
+	body, etag, err := s.landingStats.get(time.Now(), func() (*LandingStats, error) {
+		// Detached from the request: the result is shared by every client
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), s.config.WriteTimeout)
+		defer cancel()
+		return s.collectLandingStats(ctx)
+	})
+	if err != nil {
+		s.logger.Error("failed to collect landing stats", logger.Err(err))
+		if body == nil {
+			writeJSONError(w, http.StatusServiceUnavailable, "stats_unavailable", "Stats are temporarily unavailable")
+			return
+		}
+	}
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(landingStatsTTL/time.Second)))
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// collectLandingStats reads the numbers from the public query handlers and
+// the landing sources. Only counts are copied out of their results.
+func (s *Server) collectLandingStats(ctx context.Context) (*LandingStats, error) {
+	stats := &LandingStats{UpdatedAt: time.Now().UTC()}
+
+	if cqrs.Registered[query.GetLeaderboardQuery](s.deps.Bus) {
+		result, err := cqrs.Execute[*query.GetLeaderboardResult](ctx, s.deps.Bus, query.GetLeaderboardQuery{Limit: 1})
+		if err != nil {
+			return nil, fmt.Errorf("leaderboard: %w", err)
+		}
+		stats.ActiveStudents = result.TotalCount
+	}
+
+	if h := s.deps.Public.GetOnlineNowHandler; h != nil {
+		result, err := h.Handle(ctx, query.GetOnlineNowQuery{Limit: 1})
+		if err != nil {
+			return nil, fmt.Errorf("online now: %w", err)
+		}
+		stats.OnlineNow = result.TotalOnline
+	}
+
+	if h := s.deps.Public.GetResponseTimesHandler; h != nil {
+		result, err := h.Handle(ctx, query.GetResponseTimesQuery{Weeks: query.DefaultResponseTimeWeeks})
+		if err != nil {
+			return nil, fmt.Errorf("response times: %w", err)
+		}
+		stats.ResponseMinutes = result.MedianMinutes
+	}
+
+	weeklyXP, err := s.landingWeeklyXP(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("weekly xp: %w", err)
+	}
+	stats.WeeklyXP = weeklyXP
+
+	if counter := s.deps.Landing.HelpRequests; counter != nil {
+		resolved, err := counter.CountResolved(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("resolved help requests: %w", err)
+		}
+		stats.HelpRequestsResolved = resolved
+	}
+
+	if leaders := s.deps.Landing.Streaks; leaders != nil {
+		streaks, err := leaders.GetTopStreaks(ctx, 1)
+		if err != nil {
+			return nil, fmt.Errorf("streaks: %w", err)
+		}
+		if len(streaks) > 0 {
+			stats.LongestActiveStreak = streaks[0].CurrentStreak
+		}
+	}
+
+	return stats, nil
+}
+
+// landingWeeklyXP sums the current week's bucket of every cohort's XP series
+// (cached per cohort by the series handler).
+func (s *Server) landingWeeklyXP(ctx context.Context) (int, error) {
+	series := s.deps.Public.GetCohortXPSeriesHandler
+	if series == nil || s.deps.Landing.Cohorts == nil {
+		return 0, nil
+	}
+
+	cohorts, err := s.deps.Landing.Cohorts.ListCohorts(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	today := timeutil.ToAlmaty(time.Now())
+	total := 0
+	for batch := range slices.Chunk(cohorts, query.MaxXPSeriesCohorts) {
+		names := make([]string, len(batch))
+		for i, c := range batch {
+			names[i] = string(c)
+		}
+		result, err := series.Handle(ctx, query.GetCohortXPSeriesQuery{Cohorts: names, From: today, To: today})
+		if err != nil {
+			return 0, err
+		}
+		for _, cohort := range result.Series {
+			for _, bucket := range cohort.Buckets {
+				total += bucket.TotalXP
+			}
+		}
+	}
+	return total, nil
+}
+
+// landingCORSMiddleware lets only the landing page origins read /stats.json
+// from a browser. Without configured origins the global CORS settings apply.
+func (s *Server) landingCORSMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(s.config.LandingOrigins) > 0 {
+			h := w.Header()
+			for _, name := range []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Methods", "Access-Control-Allow-Headers", "Access-Control-Max-Age"} {
+				h.Del(name)
+			}
+			h.Add("Vary", "Origin")
+
+			if origin := r.Header.Get("Origin"); slices.Contains(s.config.LandingOrigins, origin) {
+				h.Set("Access-Control-Allow-Origin", origin)
+				h.Set("Access-Control-Allow-Methods", "GET")
+				h.Set("Access-Control-Expose-Headers", "ETag")
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statsRateLimitMiddleware applies the per-IP limit of /stats.json.
+func (s *Server) statsRateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.statsLimiter.Allow(getClientIP(r)) {
+			w.Header().Set("Retry-After", "60")
+			writeJSONError(w, http.StatusTooManyRequests, "rate_limit_exceeded", "Too many requests, please try again later")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
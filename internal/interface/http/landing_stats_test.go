@@ -0,0 +1,187 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/cqrs"
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// The fakes below carry student IDs and names, as the real sources do.
+const (
+	secretStudentID = "student-7f3a"
+	secretName      = "Айдана Секретова"
+)
+
+type landingLeaderboard struct {
+	leaderboard.LeaderboardRepository
+}
+
+func (landingLeaderboard) GetTop(_ context.Context, cohort leaderboard.Cohort, _ int) ([]*leaderboard.LeaderboardEntry, error) {
+	return []*leaderboard.LeaderboardEntry{{Rank: 1, StudentID: secretStudentID, DisplayName: secretName, XP: 9000, Cohort: cohort}}, nil
+}
+
+func (landingLeaderboard) GetTotalCount(context.Context, leaderboard.Cohort) (int, error) {
+	return 120, nil
+}
+
+type landingHelpRequests struct {
+	social.HelpRequestRepository
+	calls int
+}
+
+func (h *landingHelpRequests) CountResolved(context.Context) (int, error) {
+	h.calls++
+	return 345, nil
+}
+
+func (h *landingHelpRequests) GetResponseTimeStats(_ context.Context, since time.Time) (*social.ResponseTimeStats, error) {
+	return &social.ResponseTimeStats{
+		Since:               since,
+		MedianFirstResponse: 12 * time.Minute,
+		Answered:            40,
+		Weekly:              []social.WeeklyResponseTime{{Cohort: "2025-spring", WeekStart: since, MedianFirstResponse: time.Minute, Answered: 40}},
+	}, nil
+}
+
+type landingStreaks struct{}
+
+func (landingStreaks) GetTopStreaks(context.Context, int) ([]*student.Streak, error) {
+	return []*student.Streak{{StudentID: secretStudentID, CurrentStreak: 48, BestStreak: 60}}, nil
+}
+
+type landingCohorts struct{}
+
+func (landingCohorts) ListCohorts(context.Context) ([]leaderboard.Cohort, error) {
+	return []leaderboard.Cohort{"2024-fall", "2025-spring", "2025-fall", "2026-spring"}, nil
+}
+
+type landingXPSeries struct{}
+
+func (landingXPSeries) GetCohortXPSeries(_ context.Context, _ student.Cohort, from, _ time.Time, _ student.XPSeriesBucket) ([]student.XPSeriesPoint, error) {
+	return []student.XPSeriesPoint{{Date: from, TotalXP: 250, ActiveStudents: 3}}, nil
+}
+
+func newLandingServer(t *testing.T, config Config) (*Server, *landingHelpRequests) {
+	t.Helper()
+	bus := cqrs.New()
+	cqrs.Register(bus, query.NewGetLeaderboardHandler(landingLeaderboard{}, nil, nil))
+	helpRequests := &landingHelpRequests{}
+
+	server := NewServer(config, Dependencies{
+		Bus: bus,
+		Public: PublicDependencies{
+			GetResponseTimesHandler:  query.NewGetResponseTimesHandler(helpRequests),
+			GetCohortXPSeriesHandler: query.NewGetCohortXPSeriesHandler(landingXPSeries{}),
+		},
+		Landing: LandingDependencies{
+			Cohorts:      landingCohorts{},
+			HelpRequests: helpRequests,
+			Streaks:      landingStreaks{},
+		},
+	})
+	return server, helpRequests
+}
+
+func getLandingStats(server *Server, ip string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/stats.json", nil)
+	req.RemoteAddr = ip + ":1234"
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLandingStats_OnlyAggregates(t *testing.T) {
+	server, _ := newLandingServer(t, DefaultConfig())
+
+	rec := getLandingStats(server, "10.0.0.1", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body := rec.Body.String()
+	assert.NotContains(t, body, secretStudentID)
+	assert.NotContains(t, body, secretName)
+
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &fields))
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	assert.ElementsMatch(t, []string{
+		"active_students", "weekly_xp", "help_requests_resolved", "response_minutes",
+		"online_now", "longest_active_streak", "updated_at",
+	}, keys)
+
+	var stats LandingStats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Equal(t, 120, stats.ActiveStudents)
+	assert.Equal(t, 4*250, stats.WeeklyXP)
+	assert.Equal(t, 345, stats.HelpRequestsResolved)
+	require.NotNil(t, stats.ResponseMinutes)
+	assert.Equal(t, 12.0, *stats.ResponseMinutes)
+	assert.Equal(t, 48, stats.LongestActiveStreak)
+}
+
+func TestLandingStats_CachedWithETag(t *testing.T) {
+	server, helpRequests := newLandingServer(t, DefaultConfig())
+
+	first := getLandingStats(server, "10.0.0.1", nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "public, max-age=300", first.Header().Get("Cache-Control"))
+
+	second := getLandingStats(server, "10.0.0.2", nil)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, etag, second.Header().Get("ETag"))
+
+	notModified := getLandingStats(server, "10.0.0.3", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, notModified.Code)
+	assert.Empty(t, notModified.Body.String())
+
+	// The sources are read once per TTL, not per request
+	assert.Equal(t, 1, helpRequests.calls)
+}
+
+func TestLandingStats_RateLimitedPerIP(t *testing.T) {
+	config := DefaultConfig()
+	config.StatsRateLimitPerMinute = 2
+	server, _ := newLandingServer(t, config)
+
+	assert.Equal(t, http.StatusOK, getLandingStats(server, "10.0.0.1", nil).Code)
+	assert.Equal(t, http.StatusOK, getLandingStats(server, "10.0.0.1", nil).Code)
+	limited := getLandingStats(server, "10.0.0.1", nil)
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, "60", limited.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, getLandingStats(server, "10.0.0.2", nil).Code)
+}
+
+func TestLandingStats_CORSOnlyForLandingOrigins(t *testing.T) {
+	config := DefaultConfig()
+	config.LandingOrigins = []string{"https://landing.example"}
+	server, _ := newLandingServer(t, config)
+
+	rec := getLandingStats(server, "10.0.0.1", http.Header{"Origin": {"https://landing.example"}})
+	assert.Equal(t, "https://landing.example", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "ETag", rec.Header().Get("Access-Control-Expose-Headers"))
+
+	// The global "*" does not extend to /stats.json
+	rec = getLandingStats(server, "10.0.0.2", http.Header{"Origin": {"https://other.example"}})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
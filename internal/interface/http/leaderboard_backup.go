@@ -0,0 +1,74 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/pkg/logger"
+)
+
+// maxLeaderboardBackupBytes caps the size of an uploaded leaderboard backup.
+const maxLeaderboardBackupBytes = 256 << 20 // 256 MB
+
+// handleAdminExportLeaderboardCache handles GET /api/v1/admin/leaderboard-cache/export
+// Query params: cohort (repeatable; default: every cached cohort).
+// Streams the backup as NDJSON.
+func (s *Server) handleAdminExportLeaderboardCache(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.LeaderboardBackup == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Leaderboard cache backup not configured")
+		return
+	}
+
+	// A large cache takes longer to stream than the server write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Streaming not supported")
+		return
+	}
+
+	filename := fmt.Sprintf("leaderboard-cache-%s.ndjson", time.Now().UTC().Format("20060102-150405"))
+	header := w.Header()
+	header.Set("Content-Type", "application/x-ndjson")
+	header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	// Headers are sent with the first write, so a failure mid-stream can only
+	// cut the backup short; the import rejects a truncated file.
+	if _, err := s.deps.Admin.LeaderboardBackup.ExportBackup(r.Context(), w, r.URL.Query()["cohort"]); err != nil {
+		s.logger.Error("failed to export leaderboard cache", logger.Err(err))
+	}
+}
+
+// handleAdminImportLeaderboardCache handles POST /api/v1/admin/leaderboard-cache/import
+// Body: an NDJSON backup made by the export endpoint.
+func (s *Server) handleAdminImportLeaderboardCache(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Admin.LeaderboardBackup == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Leaderboard cache backup not configured")
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetReadDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to read backup")
+		return
+	}
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	body := http.MaxBytesReader(w, r.Body, maxLeaderboardBackupBytes)
+	summary, err := s.deps.Admin.LeaderboardBackup.ImportBackup(r.Context(), body)
+
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		writeJSONError(w, http.StatusRequestEntityTooLarge, "payload_too_large", fmt.Sprintf("Backup exceeds %d bytes", tooLarge.Limit))
+	case errors.Is(err, leaderboard.ErrInvalidBackup):
+		writeJSONError(w, http.StatusBadRequest, "invalid_backup", err.Error())
+	case err != nil:
+		s.logger.Error("failed to import leaderboard cache", logger.Err(err))
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to import leaderboard cache")
+	default:
+		writeJSON(w, http.StatusOK, summary)
+	}
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+)
+
+// fakeCacheBackup exports a fixed backup and records what it imports.
+type fakeCacheBackup struct {
+	exported []string
+	imported string
+}
+
+func (f *fakeCacheBackup) ExportBackup(_ context.Context, w io.Writer, cohorts []string) (*leaderboard.BackupSummary, error) {
+	f.exported = cohorts
+	_, err := io.WriteString(w, `{"type":"header","version":1}`+"\n")
+	return &leaderboard.BackupSummary{Version: 1}, err
+}
+
+func (f *fakeCacheBackup) ImportBackup(_ context.Context, r io.Reader) (*leaderboard.BackupSummary, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	f.imported = string(data)
+	if !strings.HasPrefix(f.imported, `{"type":"header"`) {
+		return nil, fmt.Errorf("%w: missing header", leaderboard.ErrInvalidBackup)
+	}
+	return &leaderboard.BackupSummary{Version: 1, Cohorts: []leaderboard.BackupCohort{{Cohort: "all", Entries: 2}}}, nil
+}
+
+func TestAdminLeaderboardCacheBackup(t *testing.T) {
+	backup := &fakeCacheBackup{}
+	config := DefaultConfig()
+	config.APIKeys = []string{"admin-key"}
+	server := NewServer(config, Dependencies{Admin: AdminDependencies{LeaderboardBackup: backup}})
+	ts := httptest.NewServer(server.httpServer.Handler)
+	defer ts.Close()
+
+	do := func(method, path, body string) *http.Response {
+		req, err := http.NewRequest(method, ts.URL+"/api/v1/admin/leaderboard-cache"+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("X-API-Key", "admin-key")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("export streams NDJSON", func(t *testing.T) {
+		resp := do(http.MethodGet, "/export?cohort=all&cohort=2024-fall", "")
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+		assert.Contains(t, resp.Header.Get("Content-Disposition"), "attachment")
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"type":"header","version":1}`+"\n", string(body))
+		assert.Equal(t, []string{"all", "2024-fall"}, backup.exported)
+	})
+
+	t.Run("import returns the summary", func(t *testing.T) {
+		resp := do(http.MethodPost, "/import", `{"type":"header","version":1}`+"\n")
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var body struct {
+			Data leaderboard.BackupSummary `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, []leaderboard.BackupCohort{{Cohort: "all", Entries: 2}}, body.Data.Cohorts)
+	})
+
+	t.Run("invalid backup is a bad request", func(t *testing.T) {
+		resp := do(http.MethodPost, "/import", "not a backup\n")
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestAdminLeaderboardCacheBackup_NotConfigured(t *testing.T) {
+	config := DefaultConfig()
+	config.APIKeys = []string{"admin-key"}
+	server := NewServer(config, Dependencies{})
+	ts := httptest.NewServer(server.httpServer.Handler)
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/admin/leaderboard-cache/export", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", "admin-key")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/cqrs"
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/pkg/logger"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PUBLIC LEADERBOARD PAGE
+// ══════════════════════════════════════════════════════════════════════════════
+
+// The page is meant to be projected on a screen during events: it needs no
+// auth, reloads itself and shows only the top of one cohort.
+
+const (
+	// leaderboardPageSize - entries shown on the page.
+	leaderboardPageSize = 20
+
+	// leaderboardPageRefresh - how often the page reloads itself.
+	leaderboardPageRefresh = 30 * time.Second
+)
+
+//go:embed templates/leaderboard.html
+var pageTemplates embed.FS
+
+var leaderboardPageTemplate = template.Must(template.ParseFS(pageTemplates, "templates/leaderboard.html"))
+
+// leaderboardPage is the data rendered by templates/leaderboard.html.
+type leaderboardPage struct {
+	Cohort         string
+	Dark           bool
+	RefreshSeconds int
+	OnlineCount    int
+	TotalCount     int
+	UpdatedAt      string
+	Rows           []leaderboardPageRow
+}
+
+// leaderboardPageRow is one line of the page.
+type leaderboardPageRow struct {
+	Rank   int
+	Name   string
+	XP     int
+	Delta  string
+	Trend  string
+	Online bool
+}
+
+// newLeaderboardPage converts a leaderboard query result to page data.
+func newLeaderboardPage(result *query.GetLeaderboardResult, dark bool) leaderboardPage {
+	page := leaderboardPage{
+		Cohort:         result.Cohort,
+		Dark:           dark,
+		RefreshSeconds: int(leaderboardPageRefresh / time.Second),
+		OnlineCount:    result.OnlineCount,
+		TotalCount:     result.TotalCount,
+		UpdatedAt:      result.GeneratedAt.In(timeutil.AlmatyTZ).Format("15:04"),
+		Rows:           make([]leaderboardPageRow, 0, len(result.Entries)),
+	}
+
+	for _, e := range result.Entries {
+		row := leaderboardPageRow{
+			Rank:   e.Rank,
+			Name:   e.DisplayName,
+			XP:     e.XP,
+			Delta:  "–",
+			Trend:  "stable",
+			Online: e.IsOnline,
+		}
+		switch {
+		case e.RankDirection == "new":
+			row.Delta, row.Trend = "new", "new"
+		case e.RankChange > 0:
+			row.Delta, row.Trend = "▲ "+strconv.Itoa(e.RankChange), "up"
+		case e.RankChange < 0:
+			row.Delta, row.Trend = "▼ "+strconv.Itoa(-e.RankChange), "down"
+		}
+		page.Rows = append(page.Rows, row)
+	}
+
+	return page
+}
+
+// renderLeaderboardPage writes the HTML page to w.
+func renderLeaderboardPage(w io.Writer, page leaderboardPage) error {
+	return leaderboardPageTemplate.Execute(w, page)
+}
+
+// pageRateLimitMiddleware applies the per-IP limit of public pages.
+// Pages are read by people, so the limit is answered in plain text.
+func (s *Server) pageRateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.pageLimiter.Allow(getClientIP(r)) {
+			w.Header().Set("Retry-After", "60")
+			http.Error(w, "Too many requests, please try again later", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleLeaderboardPage handles GET /leaderboard
+func (s *Server) handleLeaderboardPage(w http.ResponseWriter, r *http.Request) {
+	if !cqrs.Registered[query.GetLeaderboardQuery](s.deps.Bus) {
+		http.Error(w, "Leaderboard is not configured", http.StatusNotImplemented)
+		return
+	}
+
+	result, err := cqrs.Execute[*query.GetLeaderboardResult](r.Context(), s.deps.Bus, query.GetLeaderboardQuery{
+		Cohort:            getQueryParam(r, "cohort", ""),
+		Limit:             leaderboardPageSize,
+		IncludeRankChange: true,
+	})
+	if err != nil {
+		s.logger.Error("failed to get leaderboard for page", logger.Err(err))
+		http.Error(w, "Failed to load leaderboard", http.StatusInternalServerError)
+		return
+	}
+
+	// Render into a buffer so a template error does not leave half a page
+	var buf bytes.Buffer
+	if err := renderLeaderboardPage(&buf, newLeaderboardPage(result, getQueryParam(r, "theme", "") == "dark")); err != nil {
+		s.logger.Error("failed to render leaderboard page", logger.Err(err))
+		http.Error(w, "Failed to render leaderboard", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/cqrs"
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+func TestLeaderboardPage_Golden(t *testing.T) {
+	result := &query.GetLeaderboardResult{
+		Cohort:      "2025-spring",
+		TotalCount:  42,
+		OnlineCount: 2,
+		GeneratedAt: time.Date(2026, 3, 9, 14, 30, 0, 0, time.UTC),
+		Entries: []query.LeaderboardEntryDTO{
+			{Rank: 1, DisplayName: "aigerim", XP: 12500, RankChange: 2, RankDirection: "up", IsOnline: true},
+			{Rank: 2, DisplayName: "<script>alert(1)</script>", XP: 11800, RankChange: -1, RankDirection: "down"},
+			{Rank: 3, DisplayName: "daniyar", XP: 9100, RankDirection: "stable", IsOnline: true},
+			{Rank: 4, DisplayName: "arman", XP: 400, RankDirection: "new"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, renderLeaderboardPage(&buf, newLeaderboardPage(result, true)))
+
+	golden := "testdata/leaderboard_page.golden"
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(golden, buf.Bytes(), 0o644))
+	}
+
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), buf.String())
+}
+
+type fakePageLeaderboard struct {
+	leaderboard.LeaderboardRepository
+}
+
+func (fakePageLeaderboard) GetTop(_ context.Context, cohort leaderboard.Cohort, _ int) ([]*leaderboard.LeaderboardEntry, error) {
+	return []*leaderboard.LeaderboardEntry{{Rank: 1, StudentID: "s1", DisplayName: "aigerim", XP: 100, Cohort: cohort}}, nil
+}
+
+func (fakePageLeaderboard) GetTotalCount(_ context.Context, _ leaderboard.Cohort) (int, error) {
+	return 1, nil
+}
+
+// pageLeaderboardBus serves the leaderboard query from fakePageLeaderboard.
+func pageLeaderboardBus() *cqrs.Bus {
+	bus := cqrs.New()
+	cqrs.Register(bus, query.NewGetLeaderboardHandler(fakePageLeaderboard{}, nil, nil))
+	return bus
+}
+
+func TestLeaderboardPage_RateLimitedPerIP(t *testing.T) {
+	config := DefaultConfig()
+	config.PageRateLimitPerMinute = 2
+	server := NewServer(config, Dependencies{Bus: pageLeaderboardBus()})
+
+	get := func(ip, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("10.0.0.1", "/leaderboard?cohort=2025-spring&theme=dark")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `<body class="dark">`)
+	assert.Contains(t, rec.Body.String(), "aigerim")
+
+	assert.Equal(t, http.StatusOK, get("10.0.0.1", "/leaderboard").Code)
+	assert.Equal(t, http.StatusTooManyRequests, get("10.0.0.1", "/leaderboard").Code)
+	assert.Equal(t, http.StatusOK, get("10.0.0.2", "/leaderboard").Code)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, get("10.0.0.3", "/leaderboard?theme=neon").Code)
+}
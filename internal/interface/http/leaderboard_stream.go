@@ -0,0 +1,97 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/pkg/logger"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// LEADERBOARD STREAM (Server-Sent Events)
+// ══════════════════════════════════════════════════════════════════════════════
+
+// The stream relays leaderboard updates published to Redis so a dashboard
+// can update live without polling. Each update is one SSE event named after
+// its type ("entry", "rebuilt", "resync"); "rebuilt" and "resync" mean the
+// client should reload the table.
+
+const (
+	// defaultStreamHeartbeat - how often an idle stream sends a comment, so
+	// proxies do not close the connection.
+	defaultStreamHeartbeat = 15 * time.Second
+
+	// streamRetry - reconnect delay suggested to EventSource clients.
+	streamRetry = 5 * time.Second
+)
+
+// handleLeaderboardStream handles GET /api/v1/leaderboard/stream
+func (s *Server) handleLeaderboardStream(w http.ResponseWriter, r *http.Request) {
+	if s.deps.Public.LeaderboardUpdates == nil {
+		writeJSONError(w, http.StatusNotImplemented, "not_implemented", "Leaderboard updates not configured")
+		return
+	}
+
+	rc := http.NewResponseController(w)
+
+	// The stream outlives the server write timeout
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		writeJSONError(w, http.StatusInternalServerError, "internal_error", "Streaming not supported")
+		return
+	}
+
+	ctx := r.Context()
+	cohort := r.URL.Query().Get("cohort")
+	updates := s.deps.Public.LeaderboardUpdates.SubscribeUpdates(ctx, cohort)
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := s.config.StreamHeartbeat
+	if heartbeat <= 0 {
+		heartbeat = defaultStreamHeartbeat
+	}
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			// Resync updates of an all-cohorts subscription carry no cohort
+			if cohort != "" && update.Cohort != "" && update.Cohort != cohort {
+				continue
+			}
+			data, err := json.Marshal(update)
+			if err != nil {
+				s.logger.Warn("failed to encode leaderboard update", logger.Err(err))
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", update.Kind, data)
+
+		case <-ticker.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+)
+
+// fakeUpdates replays updates to every subscriber and keeps the stream open.
+type fakeUpdates struct {
+	updates []leaderboard.Update
+	cohorts chan string
+}
+
+func (f *fakeUpdates) SubscribeUpdates(ctx context.Context, cohort string) <-chan leaderboard.Update {
+	f.cohorts <- cohort
+	ch := make(chan leaderboard.Update, len(f.updates))
+	for _, u := range f.updates {
+		ch <- u
+	}
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch
+}
+
+func TestLeaderboardStream_FiltersCohortAndSendsHeartbeats(t *testing.T) {
+	updates := &fakeUpdates{
+		cohorts: make(chan string, 1),
+		updates: []leaderboard.Update{
+			{Kind: leaderboard.UpdateEntry, Cohort: "2025-autumn", StudentID: "other", XP: 10},
+			{Kind: leaderboard.UpdateEntry, Cohort: "2025-spring", StudentID: "dana", XP: 1200, Rank: 3, OldRank: 5},
+			{Kind: leaderboard.UpdateRebuilt, Cohort: "2025-spring", Count: 40},
+		},
+	}
+	config := DefaultConfig()
+	config.StreamHeartbeat = 50 * time.Millisecond
+	server := NewServer(config, Dependencies{Public: PublicDependencies{LeaderboardUpdates: updates}})
+
+	ts := httptest.NewServer(server.httpServer.Handler)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/api/v1/leaderboard/stream?cohort=2025-spring", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "2025-spring", <-updates.cohorts)
+
+	// Читаем события до первого heartbeat
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == ": heartbeat" {
+			break
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	require.NoError(t, scanner.Err())
+
+	stream := strings.Join(lines, "\n")
+	assert.NotContains(t, stream, "other")
+	assert.Equal(t, strings.Join([]string{
+		"retry: 5000",
+		"event: entry",
+		`data: {"type":"entry","cohort":"2025-spring","student_id":"dana","xp":1200,"rank":3,"old_rank":5,"at":"0001-01-01T00:00:00Z"}`,
+		"event: rebuilt",
+		`data: {"type":"rebuilt","cohort":"2025-spring","count":40,"at":"0001-01-01T00:00:00Z"}`,
+	}, "\n"), stream)
+}
+
+func TestLeaderboardStream_NotConfigured(t *testing.T) {
+	server := NewServer(DefaultConfig(), Dependencies{})
+
+	rec := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/leaderboard/stream", nil))
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
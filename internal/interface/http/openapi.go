@@ -0,0 +1,400 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// OPENAPI
+// Every route is registered together with its Operation, which describes its
+// parameters and response. The same description drives request validation
+// and the OpenAPI 3 document served at GET /api/v1/openapi.json, so the
+// document cannot drift from the routes.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Parameter locations.
+const (
+	InPath  = "path"
+	InQuery = "query"
+)
+
+// Parameter types.
+const (
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+)
+
+// Param describes a path or query parameter.
+type Param struct {
+	Name        string
+	In          string
+	Type        string
+	Description string
+	Required    bool
+
+	// Format is an OpenAPI string format; "date" (YYYY-MM-DD) is validated.
+	Format string
+
+	// Enum lists the allowed values of a string parameter.
+	Enum []string
+
+	// Minimum and Maximum bound an integer parameter (nil = unbounded).
+	Minimum *int
+	Maximum *int
+}
+
+// Operation describes a route.
+type Operation struct {
+	Method  string
+	Path    string
+	Summary string
+	Tag     string
+	Params  []Param
+
+	// Body is a zero value of the JSON request body type (nil if none).
+	Body any
+
+	// Response is a zero value of the response data type (nil = free-form object).
+	Response any
+
+	// Admin routes require an API key.
+	Admin bool
+
+	// Self routes require a personal access token and answer for its owner.
+	Self bool
+
+	// Internal routes (probes, webhooks, metrics) are left out of the document.
+	Internal bool
+}
+
+// Pattern returns the ServeMux pattern of the operation.
+func (op Operation) Pattern() string {
+	return op.Method + " " + op.Path
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// PARAMETER BUILDERS
+// ─────────────────────────────────────────────────────────────────────────────
+
+// pathParam declares a required path parameter.
+func pathParam(name, description string) Param {
+	return Param{Name: name, In: InPath, Type: TypeString, Required: true, Description: description}
+}
+
+// queryString declares an optional string query parameter.
+func queryString(name, description string) Param {
+	return Param{Name: name, In: InQuery, Type: TypeString, Description: description}
+}
+
+// queryBool declares an optional boolean query parameter.
+func queryBool(name, description string) Param {
+	return Param{Name: name, In: InQuery, Type: TypeBoolean, Description: description}
+}
+
+// queryInt declares an optional integer query parameter within [minimum, maximum].
+// A negative maximum leaves the parameter unbounded above.
+func queryInt(name string, minimum, maximum int, description string) Param {
+	p := Param{Name: name, In: InQuery, Type: TypeInteger, Description: description, Minimum: &minimum}
+	if maximum >= 0 {
+		p.Maximum = &maximum
+	}
+	return p
+}
+
+// queryEnum declares an optional string query parameter with fixed values.
+func queryEnum(name, description string, values ...string) Param {
+	return Param{Name: name, In: InQuery, Type: TypeString, Description: description, Enum: values}
+}
+
+// asRequired marks the parameter as required.
+func (p Param) asRequired() Param {
+	p.Required = true
+	return p
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// SPEC
+// ─────────────────────────────────────────────────────────────────────────────
+
+// apiSpec collects the operations of all registered routes.
+type apiSpec struct {
+	mu         sync.RWMutex
+	operations map[string]Operation
+
+	apiKeyHeader string
+}
+
+// newAPISpec creates an empty spec.
+func newAPISpec(apiKeyHeader string) *apiSpec {
+	return &apiSpec{operations: make(map[string]Operation), apiKeyHeader: apiKeyHeader}
+}
+
+// add registers an operation.
+func (s *apiSpec) add(op Operation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.operations[op.Pattern()] = op
+}
+
+// lookup returns the operation of a ServeMux pattern.
+func (s *apiSpec) lookup(pattern string) (Operation, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	op, ok := s.operations[pattern]
+	return op, ok
+}
+
+// document builds the OpenAPI 3 document of all public and admin operations.
+func (s *apiSpec) document() map[string]any {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	paths := make(map[string]any)
+	for _, op := range s.operations {
+		if op.Internal {
+			continue
+		}
+		item, ok := paths[op.Path].(map[string]any)
+		if !ok {
+			item = make(map[string]any)
+			paths[op.Path] = item
+		}
+		item[strings.ToLower(op.Method)] = operationObject(op)
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "Alem Community Hub API",
+			"version":     "v1",
+			"description": "REST API for Alem Community Hub - From Competition to Collaboration",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"securitySchemes": map[string]any{
+				"apiKey":      map[string]any{"type": "apiKey", "in": "header", "name": s.apiKeyHeader},
+				"accessToken": map[string]any{"type": "http", "scheme": "bearer", "description": "Personal access token from /token create in the bot"},
+			},
+		},
+	}
+}
+
+// operationObject builds the OpenAPI operation object.
+func operationObject(op Operation) map[string]any {
+	responses := map[string]any{
+		"200": map[string]any{
+			"description": "Successful response",
+			"content":     jsonContent(envelopeSchema(schemaOf(op.Response))),
+		},
+	}
+	if len(op.Params) > 0 {
+		responses["422"] = map[string]any{
+			"description": "Invalid path or query parameters",
+			"content":     jsonContent(envelopeSchema(nil)),
+		}
+	}
+
+	obj := map[string]any{
+		"summary":     op.Summary,
+		"operationId": operationID(op),
+		"responses":   responses,
+	}
+	if op.Tag != "" {
+		obj["tags"] = []string{op.Tag}
+	}
+	if len(op.Params) > 0 {
+		params := make([]any, 0, len(op.Params))
+		for _, p := range op.Params {
+			params = append(params, parameterObject(p))
+		}
+		obj["parameters"] = params
+	}
+	if op.Body != nil {
+		obj["requestBody"] = map[string]any{
+			"required": true,
+			"content":  jsonContent(schemaOf(op.Body)),
+		}
+	}
+	if op.Admin {
+		obj["security"] = []any{map[string]any{"apiKey": []string{}}}
+		responses["401"] = map[string]any{"description": "Missing or invalid API key"}
+	}
+	if op.Self {
+		obj["security"] = []any{map[string]any{"accessToken": []string{}}}
+		responses["401"] = map[string]any{"description": "Missing, revoked or expired access token"}
+		responses["429"] = map[string]any{"description": "Too many requests with this token"}
+	}
+
+	return obj
+}
+
+// operationID derives a stable identifier, e.g. "get_api_v1_students_id_rank".
+func operationID(op Operation) string {
+	replacer := strings.NewReplacer("/", "_", "{", "", "}", "", ".", "_")
+	return strings.ToLower(op.Method) + strings.TrimRight(replacer.Replace(op.Path), "_")
+}
+
+// parameterObject builds the OpenAPI parameter object.
+func parameterObject(p Param) map[string]any {
+	schema := map[string]any{"type": p.Type}
+	if p.Format != "" {
+		schema["format"] = p.Format
+	}
+	if len(p.Enum) > 0 {
+		schema["enum"] = p.Enum
+	}
+	if p.Minimum != nil {
+		schema["minimum"] = *p.Minimum
+	}
+	if p.Maximum != nil {
+		schema["maximum"] = *p.Maximum
+	}
+
+	obj := map[string]any{
+		"name":     p.Name,
+		"in":       p.In,
+		"required": p.Required,
+		"schema":   schema,
+	}
+	if p.Description != "" {
+		obj["description"] = p.Description
+	}
+	return obj
+}
+
+// jsonContent wraps a schema in an application/json content map.
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// envelopeSchema describes JSONResponse with the given data schema.
+func envelopeSchema(data map[string]any) map[string]any {
+	properties := map[string]any{
+		"success":    map[string]any{"type": "boolean"},
+		"error":      schemaOf(APIError{}),
+		"meta":       schemaOf(ResponseMeta{}),
+		"request_id": map[string]any{"type": "string"},
+	}
+	if data != nil {
+		properties["data"] = data
+	}
+	return map[string]any{"type": "object", "properties": properties}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// SCHEMAS FROM GO TYPES
+// ─────────────────────────────────────────────────────────────────────────────
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	durationType    = reflect.TypeOf(time.Duration(0))
+	rawMessageType  = reflect.TypeOf(json.RawMessage(nil))
+	jsonMarshalType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaOf describes the JSON encoding of v's type.
+func schemaOf(v any) map[string]any {
+	if v == nil {
+		return map[string]any{"type": "object"}
+	}
+	return typeSchema(reflect.TypeOf(v), make(map[reflect.Type]bool))
+}
+
+// typeSchema describes the JSON encoding of t. seen breaks recursive types.
+func typeSchema(t reflect.Type, seen map[reflect.Type]bool) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case durationType:
+		return map[string]any{"type": "integer", "description": "nanoseconds"}
+	case rawMessageType:
+		return map[string]any{}
+	}
+	if t.Implements(jsonMarshalType) || reflect.PointerTo(t).Implements(jsonMarshalType) {
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem(), seen)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem(), seen)}
+	case reflect.Struct:
+		if seen[t] {
+			return map[string]any{"type": "object"}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		properties := make(map[string]any)
+		addStructFields(t, properties, seen)
+		return map[string]any{"type": "object", "properties": properties}
+	default:
+		return map[string]any{}
+	}
+}
+
+// addStructFields adds the JSON fields of struct t, flattening untagged
+// embedded structs the way encoding/json does.
+func addStructFields(t reflect.Type, properties map[string]any, seen map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addStructFields(embedded, properties, seen)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = typeSchema(field.Type, seen)
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// HANDLER
+// ─────────────────────────────────────────────────────────────────────────────
+
+// handleOpenAPI handles GET /api/v1/openapi.json
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	// The document is served as is, without the JSONResponse envelope
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(s.api.document())
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pathWildcard = regexp.MustCompile(`\{([^}.]+)(?:\.\.\.)?\}`)
+
+// TestRoutes_AllDocumented fails when a route is registered without an
+// operation, or when an operation does not declare its path parameters.
+func TestRoutes_AllDocumented(t *testing.T) {
+	server := NewServer(DefaultConfig(), Dependencies{})
+	doc := server.api.document()
+	paths := doc["paths"].(map[string]any)
+
+	require.NotEmpty(t, server.router.patterns)
+	for _, pattern := range server.router.patterns {
+		op, ok := server.api.lookup(pattern)
+		if !assert.True(t, ok, "route %q has no operation", pattern) {
+			continue
+		}
+
+		for _, m := range pathWildcard.FindAllStringSubmatch(op.Path, -1) {
+			assert.True(t, hasPathParam(op, m[1]), "route %q does not declare path parameter %q", pattern, m[1])
+		}
+
+		if !op.Internal {
+			assert.Contains(t, paths, op.Path, "route %q is missing from the document", pattern)
+		}
+	}
+}
+
+func hasPathParam(op Operation, name string) bool {
+	for _, p := range op.Params {
+		if p.In == InPath && p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestOpenAPIDocument_Served(t *testing.T) {
+	server := NewServer(DefaultConfig(), Dependencies{})
+
+	rec := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var doc struct {
+		OpenAPI string                               `json:"openapi"`
+		Paths   map[string]map[string]map[string]any `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	for _, path := range []string{
+		"/api/v1/leaderboard",
+		"/api/v1/students/{id}/rank",
+		"/api/v1/students/{id}/neighbors",
+		"/api/v1/students/{id}/progress",
+		"/api/v1/students/online",
+		"/api/v1/helpers",
+	} {
+		assert.Contains(t, doc.Paths, path)
+	}
+	assert.NotContains(t, doc.Paths, "/webhook/telegram/{token}")
+	assert.Contains(t, doc.Paths["/api/v1/admin/preview"]["post"], "security")
+}
+
+func TestValidateParams(t *testing.T) {
+	server := NewServer(DefaultConfig(), Dependencies{})
+
+	tests := []struct {
+		name   string
+		url    string
+		status int
+		fields []string
+	}{
+		{"valid request reaches handler", "/api/v1/leaderboard?limit=100&online=true", http.StatusNotImplemented, nil},
+		{"limit above maximum", "/api/v1/leaderboard/2025-01?limit=101", http.StatusUnprocessableEntity, []string{"limit"}},
+		{"several invalid fields", "/api/v1/students/online?offset=-1&include_rank=maybe&sort_by=name", http.StatusUnprocessableEntity, []string{"offset", "sort_by", "include_rank"}},
+		{"not an integer", "/api/v1/students/s1/neighbors?radius=five", http.StatusUnprocessableEntity, []string{"radius"}},
+		{"missing required", "/api/v1/helpers", http.StatusUnprocessableEntity, []string{"task_id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			server.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			require.Equal(t, tt.status, rec.Code, rec.Body.String())
+			if tt.fields == nil {
+				return
+			}
+
+			var resp JSONResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			require.NotNil(t, resp.Error)
+			assert.Equal(t, "validation_failed", resp.Error.Code)
+			fields := make([]string, 0, len(resp.Error.Fields))
+			for _, f := range resp.Error.Fields {
+				fields = append(fields, f.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
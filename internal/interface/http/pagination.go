@@ -0,0 +1,123 @@
+package http
+
+import (
+	"crypto/rand"
+	"net/http"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/pkg/pagination"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PAGINATION
+// List endpoints return pagination.Envelope ({items, total, next_cursor}).
+// Cursors are opaque and signed; a client only passes next_cursor back as
+// ?cursor= to fetch the following page.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// newCursorCodec creates the cursor codec, falling back to a random key.
+func newCursorCodec(secret string) *pagination.CursorCodec {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		_, _ = rand.Read(key)
+	}
+	return pagination.NewCursorCodec(key)
+}
+
+// leaderboardCursor continues a leaderboard after a rank (keyset).
+type leaderboardCursor struct {
+	Cohort    string             `json:"c"`
+	Period    leaderboard.Period `json:"p,omitempty"`
+	AfterRank int                `json:"r"`
+}
+
+// period returns the cursor's period; cursors issued before weekly
+// rankings have none and continue the all-time ranking.
+func (c leaderboardCursor) period() leaderboard.Period {
+	if c.Period == "" {
+		return leaderboard.PeriodAll
+	}
+	return c.Period
+}
+
+// feedCursor continues the activity feed before an item (keyset), so new
+// items do not shift the following pages.
+type feedCursor struct {
+	Cohort   string `json:"c"`
+	BeforeID int64  `json:"b"`
+}
+
+// offsetCursor continues an in-memory list at an offset.
+// Scope binds the cursor to the endpoint and filters it was issued for.
+type offsetCursor struct {
+	Scope  string `json:"s"`
+	Offset int    `json:"o"`
+}
+
+// cursorScope identifies an endpoint and its filters (all query parameters
+// except the page position and size), so a cursor cannot be replayed with other filters.
+func cursorScope(r *http.Request, endpoint string) string {
+	params := r.URL.Query()
+	params.Del("cursor")
+	params.Del("offset")
+	params.Del("limit")
+	return endpoint + "?" + params.Encode()
+}
+
+// encodeCursor encodes v, returning "" on failure (the client then sees the last page).
+func (s *Server) encodeCursor(v any) string {
+	cursor, err := s.cursors.Encode(v)
+	if err != nil {
+		return ""
+	}
+	return cursor
+}
+
+// writeInvalidCursor responds to a malformed or tampered cursor.
+func writeInvalidCursor(w http.ResponseWriter) {
+	writeJSONError(w, http.StatusBadRequest, "invalid_cursor", "Invalid or expired cursor")
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// RESPONSES
+// ─────────────────────────────────────────────────────────────────────────────
+
+// leaderboardResponse is the leaderboard page with cohort stats.
+type leaderboardResponse struct {
+	pagination.Envelope[query.LeaderboardEntryDTO]
+
+	Cohort      string    `json:"cohort"`
+	Period      string    `json:"period"`
+	Week        string    `json:"week,omitempty"`
+	OnlineCount int       `json:"online_count"`
+	AverageXP   int       `json:"average_xp"`
+	MedianXP    int       `json:"median_xp"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// onlineResponse is the online students page with status counters.
+type onlineResponse struct {
+	pagination.Envelope[query.OnlineStudentDTO]
+
+	TotalOnline       int       `json:"total_online"`
+	TotalAway         int       `json:"total_away"`
+	TotalRecent       int       `json:"total_recent"`
+	CommunityActivity string    `json:"community_activity"`
+	Message           string    `json:"message,omitempty"`
+	GeneratedAt       time.Time `json:"generated_at"`
+}
+
+// helpersResponse is the list of suggested helpers for a task.
+type helpersResponse struct {
+	pagination.Envelope[query.HelperDTO]
+
+	TaskID          string    `json:"task_id"`
+	TotalSolvers    int       `json:"total_solvers"`
+	OnlineSolvers   int       `json:"online_solvers"`
+	Difficulty      *float64  `json:"difficulty,omitempty"`
+	FrequentlyAsked bool      `json:"frequently_asked"`
+	GeneratedAt     time.Time `json:"generated_at"`
+}
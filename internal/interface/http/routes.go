@@ -0,0 +1,724 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/event"
+	"github.com/alem-hub/alem-community-hub/internal/domain/experiment"
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/webhook"
+	"github.com/alem-hub/alem-community-hub/internal/interface/http/handlers"
+	"github.com/alem-hub/alem-community-hub/pkg/featureflag"
+	"github.com/alem-hub/alem-community-hub/pkg/pagination"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// ROUTE MODULES
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Routes are grouped into modules. A module is mounted under its prefix and
+// wraps all of its routes in its own middleware chain; the global chain
+// (request ID, logging, recovery, CORS, rate limit) still applies on top.
+// Every route is registered with its Operation; see openapi.go.
+
+// Module names a group of routes that is mounted as a whole.
+type Module string
+
+const (
+	// ModuleHealth - health probes, API information and metrics.
+	ModuleHealth Module = "health"
+
+	// ModulePages - public HTML pages.
+	ModulePages Module = "pages"
+
+	// ModuleLanding - aggregate community numbers for the landing page.
+	ModuleLanding Module = "landing"
+
+	// ModulePublicAPI - public read-only API under /api/v1.
+	ModulePublicAPI Module = "public_api"
+
+	// ModuleAdminAPI - admin API under /api/v1/admin (API key required).
+	ModuleAdminAPI Module = "admin_api"
+
+	// ModuleSelfAPI - a student's own data under /api/v1/me (personal
+	// access token required).
+	ModuleSelfAPI Module = "self_api"
+
+	// ModuleWebhook - Telegram webhook under /webhook.
+	ModuleWebhook Module = "webhook"
+)
+
+// AllModules returns all modules in mount order.
+func AllModules() []Module {
+	return []Module{ModuleHealth, ModulePages, ModuleLanding, ModulePublicAPI, ModuleAdminAPI, ModuleSelfAPI, ModuleWebhook}
+}
+
+// maxWebhookBodyBytes limits the size of a Telegram update.
+const maxWebhookBodyBytes = 1 << 20 // 1 MB
+
+// routeModule is a group of routes under one prefix.
+type routeModule struct {
+	// prefix is prepended to the path of every route.
+	prefix string
+
+	// middleware wraps every route, the first one outermost.
+	middleware []handlers.MiddlewareFunc
+
+	// routes registers the routes of the module.
+	routes func(r *moduleRouter)
+}
+
+// moduleRouter registers the routes of one module.
+type moduleRouter struct {
+	server *Server
+	module routeModule
+}
+
+// route registers a handler together with its operation. Parameters are
+// validated before the handler runs, inside the module middleware. With
+// metrics enabled the route is timed outside the module middleware, so
+// requests rejected by it are counted too.
+func (r *moduleRouter) route(op Operation, handler http.HandlerFunc) {
+	op.Path = r.module.prefix + op.Path
+	middleware := r.module.middleware
+	if r.server.deps.Metrics != nil {
+		middleware = append([]handlers.MiddlewareFunc{r.server.metricsMiddleware(op.Path)}, middleware...)
+	}
+	h := handlers.ChainHandler(validateParams(op, handler), middleware...)
+
+	r.server.api.add(op)
+	r.server.router.Handle(op.Pattern(), h)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Health & Status
+// ─────────────────────────────────────────────────────────────────────────────
+
+// healthModule serves probes and metrics. Probes must never be answered
+// from a cache, and they need no API key.
+func (s *Server) healthModule() routeModule {
+	return routeModule{
+		middleware: []handlers.MiddlewareFunc{handlers.NoCacheMiddleware},
+		routes: func(r *moduleRouter) {
+			r.route(Operation{Method: "GET", Path: "/health", Summary: "Health check", Internal: true}, s.handleHealth)
+			r.route(Operation{Method: "GET", Path: "/healthz", Summary: "Liveness probe", Internal: true}, s.handleLive)
+			r.route(Operation{Method: "GET", Path: "/readyz", Summary: "Readiness probe", Internal: true}, s.handleReady)
+			r.route(Operation{Method: "GET", Path: "/live", Summary: "Liveness probe (alias of /healthz)", Internal: true}, s.handleLive)
+			r.route(Operation{Method: "GET", Path: "/ready", Summary: "Readiness probe (alias of /readyz)", Internal: true}, s.handleReady)
+			r.route(Operation{Method: "GET", Path: "/", Summary: "API information", Internal: true}, s.handleRoot)
+
+			if s.config.EnableMetrics {
+				r.route(Operation{Method: "GET", Path: "/metrics", Summary: "Server metrics", Internal: true}, s.handleMetrics)
+			}
+		},
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Public Pages
+// ─────────────────────────────────────────────────────────────────────────────
+
+// pagesModule serves HTML pages. They set their own cache headers and have
+// a stricter per-IP limit than the API.
+func (s *Server) pagesModule() routeModule {
+	var middleware []handlers.MiddlewareFunc
+	if s.pageLimiter != nil {
+		middleware = append(middleware, s.pageRateLimitMiddleware)
+	}
+
+	return routeModule{
+		middleware: middleware,
+		routes: func(r *moduleRouter) {
+			r.route(Operation{
+				Method: "GET", Path: "/leaderboard", Internal: true,
+				Summary: "Leaderboard page for screens",
+				Params: []Param{
+					queryString("cohort", "Cohort"),
+					queryEnum("theme", "Color theme", "light", "dark"),
+				},
+			}, s.handleLeaderboardPage)
+		},
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Landing Page Stats
+// ─────────────────────────────────────────────────────────────────────────────
+
+// landingModule serves /stats.json. It is read cross-origin by the landing
+// page, may be cached by browsers and has the strictest per-IP limit.
+func (s *Server) landingModule() routeModule {
+	middleware := []handlers.MiddlewareFunc{handlers.SecurityHeadersMiddleware, s.landingCORSMiddleware}
+	if s.statsLimiter != nil {
+		middleware = append(middleware, s.statsRateLimitMiddleware)
+	}
+
+	return routeModule{
+		middleware: middleware,
+		routes: func(r *moduleRouter) {
+			r.route(Operation{
+				Method: "GET", Path: "/stats.json", Internal: true,
+				Summary:  "Anonymous community numbers for the landing page",
+				Response: LandingStats{},
+			}, s.handleLandingStats)
+		},
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// API v1 - Public Endpoints
+// ─────────────────────────────────────────────────────────────────────────────
+
+// publicAPIModule serves the public read-only API. A personal access token
+// sent here cannot read another student (see studentScopeMiddleware).
+func (s *Server) publicAPIModule() routeModule {
+	return routeModule{
+		prefix: "/api/v1",
+		middleware: []handlers.MiddlewareFunc{
+			handlers.SecurityHeadersMiddleware,
+			handlers.NoCacheMiddleware,
+			s.studentScopeMiddleware,
+		},
+		routes: func(r *moduleRouter) {
+			r.route(Operation{
+				Method: "GET", Path: "/openapi.json", Tag: "meta",
+				Summary: "OpenAPI document of this API",
+			}, s.handleOpenAPI)
+
+			r.route(Operation{
+				Method: "GET", Path: "/leaderboard", Tag: "leaderboard",
+				Summary:  "Leaderboard of all cohorts",
+				Params:   leaderboardParams(),
+				Response: leaderboardResponse{},
+			}, s.handleGetLeaderboard)
+			r.route(Operation{
+				Method: "GET", Path: "/leaderboard/stream", Tag: "leaderboard",
+				Summary: "Live leaderboard updates as Server-Sent Events",
+				Params:  []Param{queryString("cohort", "Only updates of this cohort")},
+			}, s.handleLeaderboardStream)
+			r.route(Operation{
+				Method: "GET", Path: "/leaderboard/{cohort}", Tag: "leaderboard",
+				Summary:  "Leaderboard of a cohort",
+				Params:   append([]Param{pathParam("cohort", "Cohort")}, leaderboardParams()...),
+				Response: leaderboardResponse{},
+			}, s.handleGetLeaderboardByCohort)
+
+			r.route(Operation{
+				Method: "GET", Path: "/students/online", Tag: "students",
+				Summary: "Students online now",
+				Params: []Param{
+					queryString("cohort", "Cohort"),
+					queryBool("include_away", "Include away students"),
+					queryBool("include_recent", "Include recently active students"),
+					queryBool("available_for_help", "Only students available for help"),
+					queryInt("limit", 1, query.OnlineNowBounds.MaxLimit, "Page size"),
+					queryInt("offset", 0, -1, "Entries to skip (ignored with cursor)"),
+					queryString("cursor", "next_cursor of the previous page"),
+					queryEnum("sort_by", "Sort field", "last_seen", "xp", "help_rating", "rank"),
+					queryBool("sort_desc", "Sort descending"),
+					queryBool("include_activity", "Include current activity"),
+					queryBool("include_rank", "Include rank"),
+				},
+				Response: onlineResponse{},
+			}, s.handleGetOnline)
+
+			r.route(Operation{
+				Method: "GET", Path: "/students/{id}", Tag: "students",
+				Summary:  "Student with rank",
+				Params:   append([]Param{pathParam("id", "Student ID")}, rankHistoryParams()...),
+				Response: query.GetStudentRankResult{},
+			}, s.handleGetStudent)
+			r.route(Operation{
+				Method: "GET", Path: "/students/{id}/rank", Tag: "students",
+				Summary:  "Rank of a student",
+				Params:   append([]Param{pathParam("id", "Student ID"), queryString("cohort", "Cohort")}, rankHistoryParams()...),
+				Response: query.GetStudentRankResult{},
+			}, s.handleGetStudentRank)
+			r.route(Operation{
+				Method: "GET", Path: "/students/{id}/neighbors", Tag: "students",
+				Summary: "Students ranked next to a student",
+				Params: []Param{
+					pathParam("id", "Student ID"),
+					queryString("cohort", "Cohort"),
+					queryInt("radius", 1, 25, "Neighbors on each side"),
+					queryBool("include_online", "Include online status"),
+				},
+				Response: query.GetNeighborsResult{},
+			}, s.handleGetStudentNeighbors)
+			r.route(Operation{
+				Method: "GET", Path: "/students/{id}/progress", Tag: "students",
+				Summary:  "Daily progress of a student",
+				Params:   append([]Param{pathParam("id", "Student ID")}, progressParams()...),
+				Response: query.GetDailyProgressResult{},
+			}, s.handleGetStudentProgress)
+			r.route(Operation{
+				Method: "GET", Path: "/students/{id}/forecast", Tag: "students",
+				Summary:  "When a student reaches an XP, level or rank target at their recent pace",
+				Params:   append([]Param{pathParam("id", "Student ID")}, forecastParams()...),
+				Response: query.GetForecastResult{},
+			}, s.handleGetStudentForecast)
+			r.route(Operation{
+				Method: "GET", Path: "/students/{id}/notifications", Tag: "students",
+				Summary: "Notifications of a student",
+				Params: []Param{
+					pathParam("id", "Student ID"),
+					queryInt("page", 1, -1, "Page number"),
+					queryInt("page_size", 1, 50, "Page size"),
+				},
+				Response: query.GetNotificationsResult{},
+			}, s.handleGetStudentNotifications)
+			r.route(Operation{
+				Method: "GET", Path: "/students/{id}/endorsements", Tag: "students",
+				Summary: "Endorsements received by a student",
+				Params: []Param{
+					pathParam("id", "Student ID"),
+					queryInt("limit", 1, social.ListBounds.MaxLimit, "Page size"),
+					queryInt("offset", 0, -1, "Entries to skip (ignored with cursor)"),
+					queryString("cursor", "next_cursor of the previous page"),
+				},
+				Response: pagination.Envelope[query.EndorsementDTO]{},
+			}, s.handleGetStudentEndorsements)
+			r.route(Operation{
+				Method: "GET", Path: "/students/{id}/connections", Tag: "students",
+				Summary: "Connections of a student",
+				Params: []Param{
+					pathParam("id", "Student ID"),
+					queryInt("limit", 1, social.ListBounds.MaxLimit, "Page size"),
+					queryInt("offset", 0, -1, "Entries to skip (ignored with cursor)"),
+					queryString("cursor", "next_cursor of the previous page"),
+				},
+				Response: pagination.Envelope[query.ConnectionDTO]{},
+			}, s.handleGetStudentConnections)
+
+			r.route(Operation{
+				Method: "GET", Path: "/helpers", Tag: "helpers",
+				Summary: "Students who can help with a task",
+				Params: []Param{
+					queryString("task_id", "Task ID").asRequired(),
+					queryString("cohort", "Cohort"),
+					queryBool("only_online", "Prefer students online now"),
+					queryInt("limit", 1, query.FindHelpersBounds.MaxLimit, "Maximum helpers"),
+					queryString("exclude", "Student ID to exclude (the requester)"),
+				},
+				Response: helpersResponse{},
+			}, s.handleFindHelpers)
+			r.route(Operation{
+				Method: "GET", Path: "/helpers/top", Tag: "helpers",
+				Summary: "Helpers ranked by endorsements",
+				Params: []Param{
+					queryInt("days", 1, query.MaxTopHelpersDays, "Number of recent days"),
+					queryInt("limit", 1, query.TopHelpersBounds.MaxLimit, "Maximum helpers"),
+				},
+				Response: query.GetTopHelpersResult{},
+			}, s.handleGetTopHelpers)
+			r.route(Operation{
+				Method: "GET", Path: "/community/response-times", Tag: "community",
+				Summary: "Median time to the first helper response",
+				Params: []Param{
+					queryInt("weeks", 1, query.MaxResponseTimeWeeks, "Number of recent weeks"),
+				},
+				Response: query.GetResponseTimesResult{},
+			}, s.handleGetResponseTimes)
+			r.route(Operation{
+				Method: "GET", Path: "/cohorts/{cohort}/xp-series", Tag: "community",
+				Summary: "XP and active students of a cohort per day or ISO week (Almaty time), with empty buckets as zeros",
+				Params: []Param{
+					pathParam("cohort", "Cohort"),
+					queryString("cohort", "Cohort to compare with; repeat for more (at most 3 cohorts in total)"),
+					{Name: "from", In: InQuery, Type: TypeString, Format: "date", Description: "First day (default: 12 weeks before to)"},
+					{Name: "to", In: InQuery, Type: TypeString, Format: "date", Description: "Last day (default: today)"},
+					queryEnum("bucket", "Bucket size (default: week)", "day", "week"),
+				},
+				Response: query.GetCohortXPSeriesResult{},
+			}, s.handleGetCohortXPSeries)
+			r.route(Operation{
+				Method: "GET", Path: "/feed", Tag: "community",
+				Summary: "Recent community events, newest first",
+				Params: []Param{
+					queryString("cohort", "Cohort (default: all cohorts)"),
+					queryInt("limit", 1, social.FeedBounds.MaxLimit, "Page size"),
+					queryString("cursor", "next_cursor of the previous page"),
+				},
+				Response: pagination.Envelope[query.FeedItemDTO]{},
+			}, s.handleGetFeed)
+			r.route(Operation{
+				Method: "GET", Path: "/tasks/difficulty", Tag: "tasks",
+				Summary: "Tasks by help requests per 100 completions over the last 30 days",
+				Params: []Param{
+					queryInt("limit", 1, query.TaskDifficultyBounds.MaxLimit, "Maximum tasks"),
+				},
+				Response: query.GetTaskDifficultyResult{},
+			}, s.handleGetTaskDifficulty)
+			r.route(Operation{Method: "GET", Path: "/stats", Tag: "meta", Summary: "Community and server statistics"}, s.handleGetStats)
+		},
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// API v1 - Admin Endpoints (API key required)
+// ─────────────────────────────────────────────────────────────────────────────
+
+// adminAPIModule serves the admin API. The API key is checked before
+// parameters are validated.
+func (s *Server) adminAPIModule() routeModule {
+	return routeModule{
+		prefix: "/api/v1/admin",
+		middleware: []handlers.MiddlewareFunc{
+			handlers.SecurityHeadersMiddleware,
+			handlers.NoCacheMiddleware,
+			s.adminAuth.Middleware,
+		},
+		routes: func(r *moduleRouter) {
+			r.route(Operation{
+				Method: "POST", Path: "/preview", Tag: "admin", Admin: true,
+				Summary:  "Render a notification preview",
+				Body:     PreviewRequest{},
+				Response: PreviewResponse{},
+			}, s.handleAdminPreview)
+			r.route(Operation{
+				Method: "GET", Path: "/analytics/commands", Tag: "admin", Admin: true,
+				Summary: "Bot command usage",
+				Params: []Param{
+					{Name: "from", In: InQuery, Type: TypeString, Format: "date", Description: "First day (default: 29 days before to)"},
+					{Name: "to", In: InQuery, Type: TypeString, Format: "date", Description: "Last day (default: yesterday)"},
+					queryInt("limit", 0, -1, "Maximum commands (0 = all)"),
+				},
+				Response: query.GetCommandUsageResult{},
+			}, s.handleAdminCommandUsage)
+			r.route(Operation{
+				Method: "GET", Path: "/leaderboard", Tag: "admin", Admin: true,
+				Summary:  "Leaderboard including students hidden from public listings",
+				Params:   append([]Param{queryString("cohort", "Cohort (default: all)")}, leaderboardParams()...),
+				Response: leaderboardResponse{},
+			}, s.handleAdminLeaderboard)
+			r.route(Operation{
+				Method: "GET", Path: "/analytics/help-satisfaction", Tag: "admin", Admin: true,
+				Summary: "Weekly help satisfaction per cohort from private feedback polls",
+				Params: []Param{
+					queryInt("weeks", 1, query.MaxHelpSatisfactionWeeks, "Number of recent weeks"),
+				},
+				Response: query.GetHelpSatisfactionResult{},
+			}, s.handleAdminHelpSatisfaction)
+
+			var settingKeys []string
+			for _, def := range settings.Definitions() {
+				settingKeys = append(settingKeys, string(def.Key))
+			}
+			settingKeyParam := pathParam("key", "Setting key")
+			settingKeyParam.Enum = settingKeys
+
+			r.route(Operation{
+				Method: "GET", Path: "/cohorts/{cohort}/settings", Tag: "admin", Admin: true,
+				Summary:  "Settings of a cohort",
+				Params:   []Param{pathParam("cohort", "Cohort")},
+				Response: cohortSettingsResponse{},
+			}, s.handleAdminGetCohortSettings)
+			r.route(Operation{
+				Method: "PUT", Path: "/cohorts/{cohort}/settings/{key}", Tag: "admin", Admin: true,
+				Summary: "Override a cohort setting",
+				Params:  []Param{pathParam("cohort", "Cohort"), settingKeyParam},
+				Body:    cohortSettingRequest{},
+			}, s.handleAdminSetCohortSetting)
+			r.route(Operation{
+				Method: "DELETE", Path: "/cohorts/{cohort}/settings/{key}", Tag: "admin", Admin: true,
+				Summary: "Reset a cohort setting to the global default",
+				Params:  []Param{pathParam("cohort", "Cohort"), settingKeyParam},
+			}, s.handleAdminDeleteCohortSetting)
+
+			r.route(Operation{
+				Method: "GET", Path: "/trigger-rules", Tag: "admin", Admin: true,
+				Summary:  "Notification trigger rules",
+				Response: triggerRulesResponse{},
+			}, s.handleAdminListTriggerRules)
+			r.route(Operation{
+				Method: "GET", Path: "/trigger-rules/{id}", Tag: "admin", Admin: true,
+				Summary:  "A notification trigger rule",
+				Params:   []Param{pathParam("id", "Rule ID")},
+				Response: notification.TriggerRuleDocument{},
+			}, s.handleAdminGetTriggerRule)
+			r.route(Operation{
+				Method: "PUT", Path: "/trigger-rules/{id}", Tag: "admin", Admin: true,
+				Summary:  "Replace a trigger rule; applied by the bot within a minute",
+				Params:   []Param{pathParam("id", "Rule ID")},
+				Body:     notification.TriggerRuleDocument{},
+				Response: notification.TriggerRuleDocument{},
+			}, s.handleAdminUpdateTriggerRule)
+			r.route(Operation{
+				Method: "POST", Path: "/trigger-rules/{id}/enable", Tag: "admin", Admin: true,
+				Summary: "Enable a trigger rule",
+				Params:  []Param{pathParam("id", "Rule ID")},
+			}, s.handleAdminEnableTriggerRule)
+			r.route(Operation{
+				Method: "POST", Path: "/trigger-rules/{id}/disable", Tag: "admin", Admin: true,
+				Summary: "Disable a trigger rule",
+				Params:  []Param{pathParam("id", "Rule ID")},
+			}, s.handleAdminDisableTriggerRule)
+
+			r.route(Operation{
+				Method: "GET", Path: "/feature-flags", Tag: "admin", Admin: true,
+				Summary:  "Feature flags",
+				Response: featureFlagsResponse{},
+			}, s.handleAdminListFeatureFlags)
+			r.route(Operation{
+				Method: "GET", Path: "/feature-flags/{name}", Tag: "admin", Admin: true,
+				Summary:  "A feature flag",
+				Params:   []Param{pathParam("name", "Flag name")},
+				Response: featureflag.Flag{},
+			}, s.handleAdminGetFeatureFlag)
+			r.route(Operation{
+				Method: "PUT", Path: "/feature-flags/{name}", Tag: "admin", Admin: true,
+				Summary:  "Create or replace a feature flag; applied by all processes within 30 seconds",
+				Params:   []Param{pathParam("name", "Flag name")},
+				Body:     featureFlagRequest{},
+				Response: featureflag.Flag{},
+			}, s.handleAdminPutFeatureFlag)
+
+			r.route(Operation{
+				Method: "GET", Path: "/content-blocklist", Tag: "admin", Admin: true,
+				Summary:  "Phrases masked in help request descriptions and endorsement comments",
+				Response: contentBlocklistBody{},
+			}, s.handleAdminGetContentBlocklist)
+			r.route(Operation{
+				Method: "PUT", Path: "/content-blocklist", Tag: "admin", Admin: true,
+				Summary:  "Replace the content blocklist; applied by all processes within a minute",
+				Body:     contentBlocklistBody{},
+				Response: contentBlocklistBody{},
+			}, s.handleAdminPutContentBlocklist)
+
+			r.route(Operation{
+				Method: "GET", Path: "/webhooks", Tag: "admin", Admin: true,
+				Summary:  "Outbound webhook subscriptions with their last delivery",
+				Response: webhookSubscriptionsResponse{},
+			}, s.handleAdminListWebhooks)
+			r.route(Operation{
+				Method: "POST", Path: "/webhooks", Tag: "admin", Admin: true,
+				Summary:  "Subscribe a URL to help request events",
+				Body:     webhookSubscriptionRequest{},
+				Response: webhookCreatedResponse{},
+			}, s.handleAdminCreateWebhook)
+			r.route(Operation{
+				Method: "PATCH", Path: "/webhooks/{id}", Tag: "admin", Admin: true,
+				Summary:  "Update or re-activate a webhook subscription",
+				Params:   []Param{pathParam("id", "Subscription ID")},
+				Body:     webhookSubscriptionPatch{},
+				Response: webhook.Subscription{},
+			}, s.handleAdminUpdateWebhook)
+			r.route(Operation{
+				Method: "DELETE", Path: "/webhooks/{id}", Tag: "admin", Admin: true,
+				Summary: "Delete a webhook subscription",
+				Params:  []Param{pathParam("id", "Subscription ID")},
+			}, s.handleAdminDeleteWebhook)
+			r.route(Operation{
+				Method: "GET", Path: "/events", Tag: "admin", Admin: true,
+				Summary:  "Latest community events",
+				Response: eventsResponse{},
+			}, s.handleAdminListEvents)
+			r.route(Operation{
+				Method: "POST", Path: "/events", Tag: "admin", Admin: true,
+				Summary:  "Create a time-boxed community event with its own leaderboard",
+				Body:     eventRequest{},
+				Response: event.Event{},
+			}, s.handleAdminCreateEvent)
+			r.route(Operation{
+				Method: "POST", Path: "/events/{id}/close", Tag: "admin", Admin: true,
+				Summary:  "End a community event now; results are frozen by the worker",
+				Params:   []Param{pathParam("id", "Event ID")},
+				Response: event.Event{},
+			}, s.handleAdminCloseEvent)
+			r.route(Operation{
+				Method: "GET", Path: "/experiments", Tag: "admin", Admin: true,
+				Summary:  "Latest notification wording experiments",
+				Response: experimentsResponse{},
+			}, s.handleAdminListExperiments)
+			r.route(Operation{
+				Method: "POST", Path: "/experiments", Tag: "admin", Admin: true,
+				Summary:  "Start an A/B experiment with the wording of a notification type",
+				Body:     experimentRequest{},
+				Response: experiment.Experiment{},
+			}, s.handleAdminCreateExperiment)
+			r.route(Operation{
+				Method: "POST", Path: "/experiments/{id}/stop", Tag: "admin", Admin: true,
+				Summary:  "Stop an experiment; students get the default wording again",
+				Params:   []Param{pathParam("id", "Experiment ID")},
+				Response: experiment.Experiment{},
+			}, s.handleAdminStopExperiment)
+			r.route(Operation{
+				Method: "GET", Path: "/experiments/{id}/results", Tag: "admin", Admin: true,
+				Summary:  "Next-day activity rate per variant with 95% confidence intervals",
+				Params:   []Param{pathParam("id", "Experiment ID")},
+				Response: experimentResultsResponse{},
+			}, s.handleAdminGetExperimentResults)
+			r.route(Operation{
+				Method: "GET", Path: "/backfill", Tag: "admin", Admin: true,
+				Summary:  "Latest activity history backfill runs with progress and ETA",
+				Response: backfillRunsResponse{},
+			}, s.handleAdminListBackfillRuns)
+			r.route(Operation{
+				Method: "POST", Path: "/backfill", Tag: "admin", Admin: true,
+				Summary:  "Start loading the full task completion history from Alem",
+				Body:     backfillRequest{},
+				Response: backfillRunResponse{},
+			}, s.handleAdminStartBackfill)
+			r.route(Operation{
+				Method: "POST", Path: "/backfill/{id}/pause", Tag: "admin", Admin: true,
+				Summary:  "Pause a backfill run after the current page",
+				Params:   []Param{pathParam("id", "Backfill run ID")},
+				Response: backfillRunResponse{},
+			}, s.handleAdminPauseBackfill)
+			r.route(Operation{
+				Method: "POST", Path: "/backfill/{id}/resume", Tag: "admin", Admin: true,
+				Summary:  "Resume a paused backfill run from its saved cursors",
+				Params:   []Param{pathParam("id", "Backfill run ID")},
+				Response: backfillRunResponse{},
+			}, s.handleAdminResumeBackfill)
+			r.route(Operation{
+				Method: "POST", Path: "/backfill/{id}/cancel", Tag: "admin", Admin: true,
+				Summary:  "Cancel a backfill run",
+				Params:   []Param{pathParam("id", "Backfill run ID")},
+				Response: backfillRunResponse{},
+			}, s.handleAdminCancelBackfill)
+
+			r.route(Operation{
+				Method: "GET", Path: "/dry-run/outbox", Tag: "admin", Admin: true,
+				Summary:  "Messages the worker recorded in dry-run mode instead of sending, newest first",
+				Params:   append(dryRunOutboxParams(), queryInt("limit", 1, maxDryRunOutboxLimit, "Maximum messages (default 100)")),
+				Response: dryRunOutboxResponse{},
+			}, s.handleAdminListDryRunOutbox)
+			r.route(Operation{
+				Method: "DELETE", Path: "/dry-run/outbox", Tag: "admin", Admin: true,
+				Summary:  "Purge recorded dry-run messages matching the filters (all without filters)",
+				Params:   dryRunOutboxParams(),
+				Response: dryRunPurgeResponse{},
+			}, s.handleAdminPurgeDryRunOutbox)
+
+			r.route(Operation{
+				Method: "GET", Path: "/leaderboard-cache/export", Tag: "admin", Admin: true,
+				Summary: "Stream a versioned NDJSON backup of the leaderboard cache",
+				Params:  []Param{queryString("cohort", "Cohort to export; repeat for several (default: every cached cohort)")},
+			}, s.handleAdminExportLeaderboardCache)
+			r.route(Operation{
+				Method: "POST", Path: "/leaderboard-cache/import", Tag: "admin", Admin: true,
+				Summary:  "Restore an NDJSON leaderboard cache backup; each cohort is replaced atomically",
+				Response: leaderboard.BackupSummary{},
+			}, s.handleAdminImportLeaderboardCache)
+		},
+	}
+}
+
+// dryRunOutboxParams are the filters of the dry-run outbox.
+func dryRunOutboxParams() []Param {
+	return []Param{
+		queryString("job", "Job name"),
+		queryString("type", "Notification type, or html/document/photo for raw sends"),
+		{Name: "chat_id", In: InQuery, Type: TypeInteger, Description: "Telegram chat ID"},
+		{Name: "since", In: InQuery, Type: TypeString, Format: "date-time", Description: "Recorded at or after (RFC 3339)"},
+		{Name: "until", In: InQuery, Type: TypeString, Format: "date-time", Description: "Recorded before (RFC 3339)"},
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// API v1 - Self Endpoints (personal access token required)
+// ─────────────────────────────────────────────────────────────────────────────
+
+// selfAPIModule serves the token owner's own data. The token is checked
+// (and rate limited per token) before parameters are validated.
+func (s *Server) selfAPIModule() routeModule {
+	return routeModule{
+		prefix: "/api/v1/me",
+		middleware: []handlers.MiddlewareFunc{
+			handlers.SecurityHeadersMiddleware,
+			handlers.NoCacheMiddleware,
+			s.selfAuthMiddleware,
+		},
+		routes: func(r *moduleRouter) {
+			r.route(Operation{
+				Method: "GET", Path: "", Tag: "me", Self: true,
+				Summary:  "The token owner with rank",
+				Params:   rankHistoryParams(),
+				Response: query.GetStudentRankResult{},
+			}, asSelf(s.handleGetStudent))
+			r.route(Operation{
+				Method: "GET", Path: "/progress", Tag: "me", Self: true,
+				Summary:  "Daily progress of the token owner",
+				Params:   progressParams(),
+				Response: query.GetDailyProgressResult{},
+			}, asSelf(s.handleGetStudentProgress))
+			r.route(Operation{
+				Method: "GET", Path: "/forecast", Tag: "me", Self: true,
+				Summary:  "When the token owner reaches an XP, level or rank target at their recent pace",
+				Params:   forecastParams(),
+				Response: query.GetForecastResult{},
+			}, asSelf(s.handleGetStudentForecast))
+			r.route(Operation{
+				Method: "GET", Path: "/calendar", Tag: "me", Self: true,
+				Summary: "XP and tasks of the token owner per day, including inactive days",
+				Params: []Param{
+					queryInt("weeks", 1, query.MaxActivityCalendarWeeks, "Number of recent weeks (default 12)"),
+				},
+				Response: query.GetActivityCalendarResult{},
+			}, s.handleGetMyCalendar)
+		},
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Webhook Endpoints (Telegram)
+// ─────────────────────────────────────────────────────────────────────────────
+
+// webhookModule receives Telegram updates.
+func (s *Server) webhookModule() routeModule {
+	return routeModule{
+		prefix:     "/webhook",
+		middleware: []handlers.MiddlewareFunc{handlers.RequestSizeLimitMiddleware(maxWebhookBodyBytes)},
+		routes: func(r *moduleRouter) {
+			r.route(Operation{Method: "POST", Path: "/telegram", Summary: "Telegram webhook", Internal: true}, s.handleTelegramWebhook)
+			r.route(Operation{
+				Method: "POST", Path: "/telegram/{token}", Internal: true,
+				Summary: "Telegram webhook with secret token",
+				Params:  []Param{pathParam("token", "Webhook secret")},
+			}, s.handleTelegramWebhookWithToken)
+		},
+	}
+}
+
+// rankHistoryParams are the query parameters of a student with rank.
+func rankHistoryParams() []Param {
+	return []Param{
+		queryBool("include_history", "Include rank history"),
+		queryInt("history_days", 1, 30, "Days of rank history"),
+	}
+}
+
+// progressParams are the query parameters of daily progress.
+func progressParams() []Param {
+	return []Param{
+		queryInt("days", 1, 30, "Days of history"),
+		queryBool("include_comparison", "Compare with the cohort"),
+	}
+}
+
+// forecastParams are the query parameters of a forecast.
+func forecastParams() []Param {
+	return []Param{
+		queryEnum("target", "Target kind (default: next level)", "xp", "level", "rank"),
+		queryInt("value", 1, -1, "Target XP, level or rank"),
+	}
+}
+
+// leaderboardParams are the query parameters of leaderboard listings.
+func leaderboardParams() []Param {
+	return []Param{
+		queryInt("limit", 1, leaderboard.PageBounds.MaxLimit, "Page size"),
+		queryInt("offset", 0, -1, "Entries to skip (ignored with cursor)"),
+		queryString("cursor", "next_cursor of the previous page"),
+		queryEnum("period", "Ranking period: all-time XP or XP gained since Monday, Almaty time (default: all)",
+			string(leaderboard.PeriodAll), string(leaderboard.PeriodWeek)),
+		queryBool("online", "Only students online now"),
+		queryBool("available_for_help", "Only students available for help"),
+		queryBool("include_rank_change", "Include rank change since the previous snapshot"),
+	}
+}
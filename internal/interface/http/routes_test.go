@@ -0,0 +1,89 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModules_Middleware(t *testing.T) {
+	config := DefaultConfig()
+	config.APIKeys = []string{"admin-key"}
+	server := NewServer(config, Dependencies{Bus: pageLeaderboardBus()})
+	ts := httptest.NewServer(server.httpServer.Handler)
+	defer ts.Close()
+
+	do := func(method, path, apiKey string, body string) *http.Response {
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	t.Run("public API is not cacheable", func(t *testing.T) {
+		resp := do(http.MethodGet, "/api/v1/openapi.json", "", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "no-cache", resp.Header.Get("Pragma"))
+		assert.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+	})
+
+	t.Run("public page keeps its own headers", func(t *testing.T) {
+		resp := do(http.MethodGet, "/leaderboard", "", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+		assert.Empty(t, resp.Header.Get("Pragma"))
+		assert.Empty(t, resp.Header.Get("Content-Security-Policy"))
+	})
+
+	t.Run("admin API requires an API key", func(t *testing.T) {
+		resp := do(http.MethodGet, "/api/v1/admin/feature-flags", "", "")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, "no-cache", resp.Header.Get("Pragma"))
+
+		resp = do(http.MethodGet, "/api/v1/admin/feature-flags", "admin-key", "")
+		assert.NotEqual(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("health needs no API key", func(t *testing.T) {
+		resp := do(http.MethodGet, "/health", "", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "no-cache", resp.Header.Get("Pragma"))
+	})
+
+	t.Run("webhook limits the body size", func(t *testing.T) {
+		resp := do(http.MethodPost, "/webhook/telegram", "", strings.Repeat("x", maxWebhookBodyBytes+1))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+		resp = do(http.MethodPost, "/webhook/telegram", "", `{"update_id":1}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestModules_Subset(t *testing.T) {
+	config := DefaultConfig()
+	config.Modules = []Module{ModuleAdminAPI, ModuleHealth}
+	server := NewServer(config, Dependencies{})
+
+	assert.Contains(t, server.router.patterns, "GET /health")
+	assert.Contains(t, server.router.patterns, "GET /api/v1/admin/feature-flags")
+	for _, pattern := range server.router.patterns {
+		if strings.HasPrefix(pattern, "GET /api/v1/") {
+			assert.True(t, strings.HasPrefix(pattern, "GET /api/v1/admin/"), "route %q is not an admin route", pattern)
+		}
+		assert.NotContains(t, pattern, "/webhook/")
+		assert.NotEqual(t, "GET /leaderboard", pattern)
+	}
+
+	rec := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook/telegram", strings.NewReader("{}")))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
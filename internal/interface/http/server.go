@@ -13,9 +13,21 @@ import (
 	"sync"
 	"time"
 
+	"github.com/alem-hub/alem-community-hub/internal/application/cqrs"
 	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/backfill"
+	"github.com/alem-hub/alem-community-hub/internal/domain/event"
+	"github.com/alem-hub/alem-community-hub/internal/domain/experiment"
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/settings"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/domain/webhook"
 	"github.com/alem-hub/alem-community-hub/internal/interface/http/handlers"
+	"github.com/alem-hub/alem-community-hub/pkg/featureflag"
+	"github.com/alem-hub/alem-community-hub/pkg/httpclient"
 	"github.com/alem-hub/alem-community-hub/pkg/logger"
+	"github.com/alem-hub/alem-community-hub/pkg/pagination"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -57,6 +69,22 @@ type Config struct {
 	// RateLimitPerMinute - requests per minute per IP (0 = disabled).
 	RateLimitPerMinute int
 
+	// PageRateLimitPerMinute - requests per minute per IP to the public
+	// leaderboard page (0 = disabled).
+	PageRateLimitPerMinute int
+
+	// TokenRateLimitPerMinute - requests per minute per personal access
+	// token to /api/v1/me (0 = disabled).
+	TokenRateLimitPerMinute int
+
+	// StatsRateLimitPerMinute - requests per minute per IP to /stats.json
+	// (0 = disabled).
+	StatsRateLimitPerMinute int
+
+	// LandingOrigins - origins allowed to read /stats.json from a browser.
+	// Empty = the global CORS settings apply.
+	LandingOrigins []string
+
 	// TrustedProxies - list of trusted proxy IPs for X-Forwarded-For.
 	TrustedProxies []string
 
@@ -68,24 +96,50 @@ type Config struct {
 
 	// WebhookSecret - secret for validating webhook requests.
 	WebhookSecret string
+
+	// TelegramSecretToken - expected X-Telegram-Bot-Api-Secret-Token of
+	// Telegram updates, as set via setWebhook. Empty = the header is not
+	// checked (polling mode, where Telegram does not call the webhook).
+	TelegramSecretToken string
+
+	// AdminIDs - Telegram IDs of admins allowed to receive previews.
+	AdminIDs []int64
+
+	// CursorSecret - key for signing pagination cursors.
+	// If empty, a random key is used and cursors do not survive restarts.
+	CursorSecret string
+
+	// StreamHeartbeat - how often idle event streams send a heartbeat
+	// (default: 15s).
+	StreamHeartbeat time.Duration
+
+	// Modules - route modules to mount (default: all, see AllModules).
+	Modules []Module
+
+	// Version - build version reported by /healthz (default: "v1").
+	Version string
 }
 
 // DefaultConfig returns default server configuration.
 func DefaultConfig() Config {
 	return Config{
-		Host:               "0.0.0.0",
-		Port:               8080,
-		ReadTimeout:        15 * time.Second,
-		WriteTimeout:       15 * time.Second,
-		IdleTimeout:        60 * time.Second,
-		MaxHeaderBytes:     1 << 20, // 1 MB
-		EnableCORS:         true,
-		AllowedOrigins:     []string{"*"},
-		EnableMetrics:      true,
-		EnablePprof:        false,
-		RateLimitPerMinute: 100,
-		APIKeyHeader:       "X-API-Key",
-		APIKeys:            []string{},
+		Host:                    "0.0.0.0",
+		Port:                    8080,
+		ReadTimeout:             15 * time.Second,
+		WriteTimeout:            15 * time.Second,
+		IdleTimeout:             60 * time.Second,
+		MaxHeaderBytes:          1 << 20, // 1 MB
+		EnableCORS:              true,
+		AllowedOrigins:          []string{"*"},
+		EnableMetrics:           true,
+		EnablePprof:             false,
+		RateLimitPerMinute:      100,
+		PageRateLimitPerMinute:  20,
+		TokenRateLimitPerMinute: 60,
+		StatsRateLimitPerMinute: 10,
+		APIKeyHeader:            "X-API-Key",
+		APIKeys:                 []string{},
+		Version:                 "v1",
 	}
 }
 
@@ -98,24 +152,194 @@ func (c Config) Address() string {
 // DEPENDENCIES
 // ══════════════════════════════════════════════════════════════════════════════
 
-// Dependencies contains all dependencies required by HTTP handlers.
+// Dependencies contains the dependencies of HTTP handlers, grouped by the
+// route module that uses them. Only the groups of mounted modules are needed.
 type Dependencies struct {
-	// Query Handlers (CQRS Read Side)
-	GetLeaderboardHandler   *query.GetLeaderboardHandler
-	GetStudentRankHandler   *query.GetStudentRankHandler
-	GetOnlineNowHandler     *query.GetOnlineNowHandler
-	GetNeighborsHandler     *query.GetNeighborsHandler
-	GetDailyProgressHandler *query.GetDailyProgressHandler
-	FindHelpersHandler      *query.FindHelpersHandler
+	// Bus dispatches the queries registered on it (leaderboard, rank,
+	// neighbors). Endpoints whose query isn't registered answer 501.
+	Bus *cqrs.Bus
+
+	// Public is used by the public API and the public pages.
+	Public PublicDependencies
+
+	// Admin is used by the admin API.
+	Admin AdminDependencies
+
+	// Self is used by the self API and to scope personal access tokens on
+	// the public API.
+	Self SelfDependencies
+
+	// Landing is used by /stats.json together with the public query handlers.
+	Landing LandingDependencies
+
+	// Health is used by health checks and metrics.
+	Health HealthDependencies
+
+	// Webhook is used by the Telegram webhook.
+	Webhook WebhookDependencies
+
+	// Metrics records request metrics and serves /metrics in the Prometheus
+	// format (nil = /metrics returns the JSON summary, nothing is recorded).
+	Metrics Metrics
 
 	// Logger
 	Logger *logger.Logger
+}
+
+// PublicDependencies contains the query handlers (CQRS read side) behind
+// the public API and pages.
+type PublicDependencies struct {
+	GetOnlineNowHandler      *query.GetOnlineNowHandler
+	GetDailyProgressHandler  *query.GetDailyProgressHandler
+	FindHelpersHandler       *query.FindHelpersHandler
+	GetNotificationsHandler  *query.GetNotificationsHandler
+	GetResponseTimesHandler  *query.GetResponseTimesHandler
+	GetTaskDifficultyHandler *query.GetTaskDifficultyHandler
+	GetTopHelpersHandler     *query.GetTopHelpersHandler
+	ListEndorsementsHandler  *query.ListEndorsementsHandler
+	ListConnectionsHandler   *query.ListConnectionsHandler
+	GetForecastHandler       *query.GetForecastHandler
+	GetCohortXPSeriesHandler *query.GetCohortXPSeriesHandler
+	GetFeedHandler           *query.GetFeedHandler
+
+	// GetHelpSatisfactionHandler adds help satisfaction to /api/v1/stats.
+	GetHelpSatisfactionHandler *query.GetHelpSatisfactionHandler
+
+	// LeaderboardUpdates streams live leaderboard updates (nil = stream disabled).
+	LeaderboardUpdates leaderboard.UpdateSubscriber
+}
+
+// AdminDependencies contains the handlers and stores behind the admin API.
+type AdminDependencies struct {
+	PreviewNotificationHandler *query.PreviewNotificationHandler
+	PreviewSender              PreviewSender
+	GetCommandUsageHandler     *query.GetCommandUsageHandler
+	GetHelpSatisfactionHandler *query.GetHelpSatisfactionHandler
+	CohortSettings             CohortSettingsStore
+	TriggerRules               TriggerRuleStore
+	FeatureFlags               FeatureFlagStore
+	ContentBlocklist           ContentBlocklistStore
+	Webhooks                   webhook.Repository
+	Events                     event.Repository
+	Experiments                experiment.Repository
+	Backfill                   backfill.Repository
+	DryRunOutbox               notification.DryRunOutbox
+
+	// LeaderboardBackup exports and restores the leaderboard cache (nil = disabled).
+	LeaderboardBackup leaderboard.CacheBackup
+}
+
+// SelfDependencies contains the token resolver and the handlers behind the
+// self API. The self routes also use the progress and forecast handlers of
+// PublicDependencies.
+type SelfDependencies struct {
+	// Tokens resolves personal access tokens (nil = self API disabled).
+	Tokens AccessTokenResolver
 
-	// Health Check Dependencies
+	GetActivityCalendarHandler *query.GetActivityCalendarHandler
+}
+
+// LandingDependencies contains the sources of /stats.json that the public
+// API does not have. A nil source leaves its number at zero.
+type LandingDependencies struct {
+	// Cohorts lists the cohorts whose XP of the week is summed.
+	Cohorts CohortLister
+
+	// HelpRequests counts resolved help requests.
+	HelpRequests ResolvedHelpCounter
+
+	// Streaks returns the longest active streak.
+	Streaks StreakLeaders
+}
+
+// CohortLister lists cohorts with active students.
+type CohortLister interface {
+	ListCohorts(ctx context.Context) ([]leaderboard.Cohort, error)
+}
+
+// ResolvedHelpCounter counts help requests resolved all-time.
+type ResolvedHelpCounter interface {
+	CountResolved(ctx context.Context) (int, error)
+}
+
+// StreakLeaders returns students by current streak, longest first.
+type StreakLeaders interface {
+	GetTopStreaks(ctx context.Context, limit int) ([]*student.Streak, error)
+}
+
+// HealthDependencies contains the sources of health checks and metrics.
+type HealthDependencies struct {
+	// HealthChecker runs the dependency checks of /health and /readyz
+	// (nil = always ready).
 	HealthChecker handlers.HealthChecker
 
-	// Webhook Handler (for Telegram)
-	WebhookHandler handlers.WebhookHandler
+	// OutboundStats returns transport counters of external API clients, keyed by name.
+	OutboundStats func() map[string]httpclient.Stats
+
+	// PreferencesDecodeFailures returns how many corrupted preference blobs were read.
+	PreferencesDecodeFailures func() int64
+
+	// CacheCircuit returns the state of the circuit breaker in front of Redis (nil = no Redis).
+	CacheCircuit func() CacheCircuitStatus
+}
+
+// CacheCircuitStatus is the state of the circuit breaker in front of Redis.
+type CacheCircuitStatus struct {
+	// State is "closed", "open" or "half-open".
+	State string `json:"state"`
+
+	// DroppedWrites is how many cache writes were dropped while the circuit was open.
+	DroppedWrites int64 `json:"dropped_writes"`
+}
+
+// Metrics records HTTP request metrics and exposes all metrics of the process.
+type Metrics interface {
+	// ObserveHTTPRequest records one request; route is the registered path.
+	ObserveHTTPRequest(method, route string, status int, duration time.Duration)
+
+	// Handler serves the metrics in the Prometheus text format.
+	Handler() http.Handler
+}
+
+// WebhookDependencies contains the receiver of Telegram updates.
+type WebhookDependencies struct {
+	// Telegram handles webhook updates (nil = updates are only acknowledged).
+	Telegram handlers.WebhookHandler
+}
+
+// CohortSettingsStore reads and updates per-cohort settings.
+type CohortSettingsStore interface {
+	Values(ctx context.Context, cohort string) (settings.Values, error)
+	Set(ctx context.Context, cohort string, key settings.Key, value json.RawMessage) error
+	Delete(ctx context.Context, cohort string, key settings.Key) error
+}
+
+// TriggerRuleStore lists and updates notification trigger rules.
+type TriggerRuleStore interface {
+	List(ctx context.Context) ([]*notification.TriggerRule, error)
+	Get(ctx context.Context, id notification.TriggerRuleID) (*notification.TriggerRule, error)
+	Update(ctx context.Context, rule *notification.TriggerRule) (*notification.TriggerRule, error)
+	SetEnabled(ctx context.Context, id notification.TriggerRuleID, enabled bool) error
+}
+
+// FeatureFlagStore lists and changes feature flags.
+type FeatureFlagStore interface {
+	List(ctx context.Context) ([]featureflag.Flag, error)
+	Get(ctx context.Context, name string) (featureflag.Flag, error)
+	Set(ctx context.Context, flag featureflag.Flag) (featureflag.Flag, error)
+}
+
+// PreviewSender delivers rendered previews to a Telegram chat.
+type PreviewSender interface {
+	SendPreview(ctx context.Context, chatID int64, html string) error
+}
+
+// PreviewSenderFunc adapts a function to PreviewSender.
+type PreviewSenderFunc func(ctx context.Context, chatID int64, html string) error
+
+// SendPreview calls f(ctx, chatID, html).
+func (f PreviewSenderFunc) SendPreview(ctx context.Context, chatID int64, html string) error {
+	return f(ctx, chatID, html)
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -127,11 +351,24 @@ type Server struct {
 	config     Config
 	deps       Dependencies
 	httpServer *http.Server
-	router     *http.ServeMux
+	router     *routeMux
 	logger     *logger.Logger
 
+	// api describes all routes (OpenAPI document and request validation)
+	api       *apiSpec
+	adminAuth *handlers.APIKeyAuth
+
 	// Middleware state
-	rateLimiter *rateLimiter
+	rateLimiter  *rateLimiter
+	pageLimiter  *rateLimiter
+	tokenLimiter *rateLimiter
+	statsLimiter *rateLimiter
+
+	// landingStats caches the body of /stats.json
+	landingStats landingStatsCache
+
+	// cursors signs and verifies pagination cursors
+	cursors *pagination.CursorCodec
 
 	// Server state
 	mu        sync.RWMutex
@@ -144,21 +381,26 @@ func NewServer(config Config, deps Dependencies) *Server {
 	s := &Server{
 		config: config,
 		deps:   deps,
-		router: http.NewServeMux(),
+		router: &routeMux{ServeMux: http.NewServeMux()},
 		logger: deps.Logger,
+		api:    newAPISpec(config.APIKeyHeader),
 	}
 
 	if s.logger == nil {
 		s.logger = logger.Default()
 	}
 
-	// Initialize rate limiter
-	if config.RateLimitPerMinute > 0 {
-		s.rateLimiter = newRateLimiter(config.RateLimitPerMinute, time.Minute)
-	}
+	s.cursors = newCursorCodec(config.CursorSecret)
+
+	// Initialize rate limiters. Disabled ones are created too, so that
+	// SetRateLimits can turn them on without rebuilding the routes.
+	s.rateLimiter = newRateLimiter(config.RateLimitPerMinute, time.Minute)
+	s.pageLimiter = newRateLimiter(config.PageRateLimitPerMinute, time.Minute)
+	s.tokenLimiter = newRateLimiter(config.TokenRateLimitPerMinute, time.Minute)
+	s.statsLimiter = newRateLimiter(config.StatsRateLimitPerMinute, time.Minute)
 
-	// Setup routes
-	s.setupRoutes()
+	// Mount route modules
+	s.mountModules()
 
 	// Create HTTP server
 	s.httpServer = &http.Server{
@@ -177,42 +419,59 @@ func NewServer(config Config, deps Dependencies) *Server {
 // ROUTING
 // ══════════════════════════════════════════════════════════════════════════════
 
-// setupRoutes configures all HTTP routes.
-func (s *Server) setupRoutes() {
-	// ─────────────────────────────────────────────────────────────────────────
-	// Health & Status Endpoints
-	// ─────────────────────────────────────────────────────────────────────────
-	s.router.HandleFunc("GET /health", s.handleHealth)
-	s.router.HandleFunc("GET /healthz", s.handleHealth) // Kubernetes alias
-	s.router.HandleFunc("GET /ready", s.handleReady)
-	s.router.HandleFunc("GET /live", s.handleLive)
-	s.router.HandleFunc("GET /", s.handleRoot)
-
-	// ─────────────────────────────────────────────────────────────────────────
-	// API v1 - Public Endpoints
-	// ─────────────────────────────────────────────────────────────────────────
-	s.router.HandleFunc("GET /api/v1/leaderboard", s.handleGetLeaderboard)
-	s.router.HandleFunc("GET /api/v1/leaderboard/{cohort}", s.handleGetLeaderboardByCohort)
-	s.router.HandleFunc("GET /api/v1/students/online", s.handleGetOnline)
-	s.router.HandleFunc("GET /api/v1/students/{id}", s.handleGetStudent)
-	s.router.HandleFunc("GET /api/v1/students/{id}/rank", s.handleGetStudentRank)
-	s.router.HandleFunc("GET /api/v1/students/{id}/neighbors", s.handleGetStudentNeighbors)
-	s.router.HandleFunc("GET /api/v1/students/{id}/progress", s.handleGetStudentProgress)
-	s.router.HandleFunc("GET /api/v1/helpers", s.handleFindHelpers)
-	s.router.HandleFunc("GET /api/v1/stats", s.handleGetStats)
-
-	// ─────────────────────────────────────────────────────────────────────────
-	// Webhook Endpoints (Telegram)
-	// ─────────────────────────────────────────────────────────────────────────
-	s.router.HandleFunc("POST /webhook/telegram", s.handleTelegramWebhook)
-	s.router.HandleFunc("POST /webhook/telegram/{token}", s.handleTelegramWebhookWithToken)
-
-	// ─────────────────────────────────────────────────────────────────────────
-	// Metrics (if enabled)
-	// ─────────────────────────────────────────────────────────────────────────
-	if s.config.EnableMetrics {
-		s.router.HandleFunc("GET /metrics", s.handleMetrics)
+// mountModules registers the routes of the configured modules
+// (all modules by default).
+func (s *Server) mountModules() {
+	s.adminAuth = handlers.NewAPIKeyAuth(s.config.APIKeyHeader, s.config.APIKeys)
+
+	builders := map[Module]func() routeModule{
+		ModuleHealth:    s.healthModule,
+		ModulePages:     s.pagesModule,
+		ModuleLanding:   s.landingModule,
+		ModulePublicAPI: s.publicAPIModule,
+		ModuleAdminAPI:  s.adminAPIModule,
+		ModuleSelfAPI:   s.selfAPIModule,
+		ModuleWebhook:   s.webhookModule,
 	}
+
+	modules := s.config.Modules
+	if len(modules) == 0 {
+		modules = AllModules()
+	}
+
+	mounted := make(map[Module]bool, len(modules))
+	for _, name := range modules {
+		build, ok := builders[name]
+		if !ok {
+			s.logger.Warn("unknown HTTP module", logger.String("module", string(name)))
+			continue
+		}
+		if mounted[name] {
+			continue
+		}
+		mounted[name] = true
+
+		module := build()
+		module.routes(&moduleRouter{server: s, module: module})
+	}
+}
+
+// routeMux is an http.ServeMux that remembers registered patterns, so tests
+// can check that no route is registered without an operation.
+type routeMux struct {
+	*http.ServeMux
+	patterns []string
+}
+
+// Handle registers the handler for the given pattern.
+func (m *routeMux) Handle(pattern string, handler http.Handler) {
+	m.patterns = append(m.patterns, pattern)
+	m.ServeMux.Handle(pattern, handler)
+}
+
+// HandleFunc registers the handler function for the given pattern.
+func (m *routeMux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.Handle(pattern, http.HandlerFunc(handler))
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -283,6 +542,21 @@ func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// metricsMiddleware records the duration and status of requests to one
+// route. The route pattern is used as the label instead of the raw path.
+func (s *Server) metricsMiddleware(route string) handlers.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+			next.ServeHTTP(rw, r)
+
+			s.deps.Metrics.ObserveHTTPRequest(r.Method, route, rw.statusCode, time.Since(start))
+		})
+	}
+}
+
 // recoveryMiddleware recovers from panics and returns 500.
 func (s *Server) recoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -318,7 +592,7 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 
 		if allowed {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Request-ID")
 			w.Header().Set("Access-Control-Max-Age", "86400")
 		}
@@ -399,6 +673,11 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return s.httpServer.Shutdown(ctx)
 }
 
+// Handler returns the HTTP handler with all middleware.
+func (s *Server) Handler() http.Handler {
+	return s.httpServer.Handler
+}
+
 // IsRunning returns true if the server is running.
 func (s *Server) IsRunning() bool {
 	s.mu.RLock()
@@ -421,6 +700,33 @@ func (s *Server) Address() string {
 	return s.config.Address()
 }
 
+// ══════════════════════════════════════════════════════════════════════════════
+// RUNTIME RECONFIGURATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// RateLimits are the per-minute request limits of the server (0 = disabled).
+type RateLimits struct {
+	PerIP  int
+	Pages  int
+	Tokens int
+	Stats  int
+}
+
+// SetAPIKeys replaces the keys accepted by admin endpoints. Requests in
+// flight finish with the keys they were checked against.
+func (s *Server) SetAPIKeys(keys []string) {
+	s.adminAuth.SetKeys(keys)
+}
+
+// SetRateLimits applies new limits. Requests already counted in the
+// current window keep counting against the new limit.
+func (s *Server) SetRateLimits(limits RateLimits) {
+	s.rateLimiter.SetLimit(limits.PerIP)
+	s.pageLimiter.SetLimit(limits.Pages)
+	s.tokenLimiter.SetLimit(limits.Tokens)
+	s.statsLimiter.SetLimit(limits.Stats)
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // RESPONSE HELPERS
 // ══════════════════════════════════════════════════════════════════════════════
@@ -439,6 +745,9 @@ type APIError struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Details string `json:"details,omitempty"`
+
+	// Fields lists invalid request parameters (validation errors only).
+	Fields []FieldError `json:"fields,omitempty"`
 }
 
 // ResponseMeta contains response metadata.
@@ -547,6 +856,12 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer (flushing
+// and deadlines for event streams).
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // getClientIP extracts the client IP from the request.
 func getClientIP(r *http.Request) string {
 	// Check X-Forwarded-For header
@@ -638,10 +953,21 @@ func newRateLimiter(limit int, window time.Duration) *rateLimiter {
 	return rl
 }
 
+// SetLimit changes the number of requests allowed per window (0 = no limit).
+func (rl *rateLimiter) SetLimit(limit int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.limit = limit
+}
+
 func (rl *rateLimiter) Allow(key string) bool {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
+	if rl.limit <= 0 {
+		return true
+	}
+
 	now := time.Now()
 	windowStart := now.Add(-rl.window)
 
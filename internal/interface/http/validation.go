@@ -0,0 +1,122 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// REQUEST VALIDATION
+// Path and query parameters are checked against the route's Operation before
+// the handler runs. Invalid requests get 422 with one error per field.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// FieldError describes an invalid request parameter.
+type FieldError struct {
+	Field   string `json:"field"`
+	In      string `json:"in"`
+	Message string `json:"message"`
+}
+
+// validateParams returns middleware that rejects requests whose parameters
+// do not match the operation's declared parameters.
+func validateParams(op Operation, next http.Handler) http.Handler {
+	if len(op.Params) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if errs := checkParams(op.Params, r); len(errs) > 0 {
+			writeValidationError(w, errs)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkParams validates the request parameters, returning all violations.
+func checkParams(params []Param, r *http.Request) []FieldError {
+	var errs []FieldError
+	query := r.URL.Query()
+
+	for _, p := range params {
+		var value string
+		if p.In == InPath {
+			value = r.PathValue(p.Name)
+		} else {
+			value = query.Get(p.Name)
+		}
+
+		if value == "" {
+			if p.Required {
+				errs = append(errs, FieldError{Field: p.Name, In: p.In, Message: "is required"})
+			}
+			continue
+		}
+
+		if msg := checkValue(p, value); msg != "" {
+			errs = append(errs, FieldError{Field: p.Name, In: p.In, Message: msg})
+		}
+	}
+
+	return errs
+}
+
+// checkValue validates a single non-empty value, returning a message or "".
+func checkValue(p Param, value string) string {
+	switch p.Type {
+	case TypeInteger:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "must be an integer"
+		}
+		if p.Minimum != nil && n < *p.Minimum {
+			return fmt.Sprintf("must be at least %d", *p.Minimum)
+		}
+		if p.Maximum != nil && n > *p.Maximum {
+			return fmt.Sprintf("must be at most %d", *p.Maximum)
+		}
+
+	case TypeBoolean:
+		switch strings.ToLower(value) {
+		case "true", "false", "1", "0", "yes", "no":
+		default:
+			return "must be a boolean"
+		}
+
+	case TypeString:
+		if len(p.Enum) > 0 && !slices.Contains(p.Enum, value) {
+			return "must be one of: " + strings.Join(p.Enum, ", ")
+		}
+		if p.Format == "date" {
+			if _, err := time.Parse("2006-01-02", value); err != nil {
+				return "must be a date (YYYY-MM-DD)"
+			}
+		}
+	}
+
+	return ""
+}
+
+// writeValidationError writes a 422 response listing the invalid fields.
+func writeValidationError(w http.ResponseWriter, errs []FieldError) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+
+	_ = json.NewEncoder(w).Encode(JSONResponse{
+		Success: false,
+		Error: &APIError{
+			Code:    "validation_failed",
+			Message: "Request parameters are invalid",
+			Fields:  errs,
+		},
+		Meta: &ResponseMeta{
+			Timestamp: time.Now().UTC(),
+		},
+	})
+}
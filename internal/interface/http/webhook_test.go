@@ -0,0 +1,79 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alem-hub/alem-community-hub/internal/interface/http/handlers"
+)
+
+func TestTelegramWebhook_AlwaysAcknowledgesPayloads(t *testing.T) {
+	config := DefaultConfig()
+	config.WebhookSecret = "secret"
+	server := NewServer(config, Dependencies{Webhook: WebhookDependencies{Telegram: handlers.NewTelegramWebhookHandler()}})
+
+	payloads := []string{
+		`{"update_id":1,"edited_message":{"message_id":5,"chat":{"id":42,"type":"private"},"date":1,"text":"hi"}}`,
+		`{"update_id":2,"my_chat_member":{"chat":{"id":42,"type":"private"},"new_chat_member":{"status":"kicked"}}}`,
+		`{"update_id":3,"poll_answer":{"poll_id":"5","option_ids":[0]}}`,
+		`{"update_id":4,"callback_query":{"id":"1","from":{"id":42}}}`,
+		`{"update_id":5,"message":{"message_id":"not-a-number"}}`,
+		`not json at all`,
+	}
+
+	for _, p := range payloads {
+		req := httptest.NewRequest(http.MethodPost, "/webhook/telegram/secret", strings.NewReader(p))
+		rec := httptest.NewRecorder()
+
+		server.httpServer.Handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, "payload %s", p)
+	}
+}
+
+func TestTelegramWebhook_SecretToken(t *testing.T) {
+	const update = `{"update_id":1,"message":{"message_id":"not-a-number"}}`
+
+	post := func(server *Server, header string, set bool) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhook/telegram", strings.NewReader(update))
+		if set {
+			req.Header.Set(telegramSecretTokenHeader, header)
+		}
+		rec := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	config := DefaultConfig()
+	config.TelegramSecretToken = "s3cret-token"
+	updates := &countingWebhook{WebhookHandler: handlers.NewTelegramWebhookHandler()}
+	webhook := NewServer(config, Dependencies{Webhook: WebhookDependencies{Telegram: updates}})
+
+	assert.Equal(t, http.StatusForbidden, post(webhook, "", false), "missing header")
+	assert.Equal(t, http.StatusForbidden, post(webhook, "s3cret-tokeN", true), "wrong header")
+	assert.Zero(t, updates.calls, "rejected updates must not reach the handler")
+
+	// A valid token with an unparseable update is still acknowledged
+	assert.Equal(t, http.StatusOK, post(webhook, "s3cret-token", true), "correct header")
+	assert.Equal(t, 1, updates.calls)
+
+	// Polling mode: no token is set, the header is not checked
+	polling := NewServer(DefaultConfig(), Dependencies{Webhook: WebhookDependencies{Telegram: handlers.NewTelegramWebhookHandler()}})
+	assert.Equal(t, http.StatusOK, post(polling, "", false))
+}
+
+// countingWebhook counts the updates that reach the handler.
+type countingWebhook struct {
+	handlers.WebhookHandler
+	calls int
+}
+
+func (c *countingWebhook) HandleTelegramUpdate(ctx context.Context, payload []byte) error {
+	c.calls++
+	return c.WebhookHandler.HandleTelegramUpdate(ctx, payload)
+}
@@ -0,0 +1,162 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// ACCESS TOKENS
+// "/token create" issues a personal read-only API token (shown once) for
+// GET /api/v1/me/...; "/token" and "/token revoke" list the active tokens
+// with a revoke button each, "token:revoke:<id>". Tokens are only handed out
+// in a private chat.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// tokenCallbackPrefix is the callback prefix of the revoke buttons.
+const tokenCallbackPrefix = "token:revoke:"
+
+// AccessTokenHandler handles /token and the revoke buttons.
+type AccessTokenHandler struct {
+	tokensCmd   *command.AccessTokensHandler
+	studentRepo student.Repository
+	logger      *slog.Logger
+}
+
+// NewAccessTokenHandler creates a new AccessTokenHandler.
+func NewAccessTokenHandler(tokensCmd *command.AccessTokensHandler, studentRepo student.Repository, logger *slog.Logger) *AccessTokenHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &AccessTokenHandler{
+		tokensCmd:   tokensCmd,
+		studentRepo: studentRepo,
+		logger:      logger,
+	}
+}
+
+// Handle creates a token or lists the active ones.
+func (h *AccessTokenHandler) Handle(ctx context.Context, cmdCtx CommandContext) error {
+	if !telegram.IsPrivateChat(cmdCtx.Message) {
+		_, err := cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, "🔒 Токены выдаются только в личном чате с ботом.")
+		return err
+	}
+
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(cmdCtx.TelegramID))
+	if err != nil {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, "❌ Ты не зарегистрирован. Используй /start")
+		return err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(cmdCtx.Args)) {
+	case "create":
+		return h.create(ctx, cmdCtx, stud.ID)
+	case "", "revoke", "list":
+		return h.list(ctx, cmdCtx, stud.ID)
+	default:
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID,
+			"Используй <code>/token create</code>, чтобы создать токен, или <code>/token revoke</code>, чтобы отозвать.")
+		return err
+	}
+}
+
+// create issues a token and shows it once.
+func (h *AccessTokenHandler) create(ctx context.Context, cmdCtx CommandContext, studentID string) error {
+	result, err := h.tokensCmd.Create(ctx, studentID)
+	if errors.Is(err, student.ErrAccessTokenLimit) {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, fmt.Sprintf(
+			"У тебя уже %d активных токена. Отзови ненужный: /token revoke", student.MaxActiveAccessTokens))
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf("🔑 <b>Твой токен API</b>\n\n<code>%s</code>\n\n"+
+		"Сохрани его сейчас — больше он не будет показан.\n"+
+		"Токен только читает твои данные: <code>GET /api/v1/me</code>, <code>/me/progress</code>, "+
+		"<code>/me/forecast</code>, <code>/me/calendar</code>.\n"+
+		"Передавай его в заголовке <code>Authorization: Bearer …</code>. Действует до %s.\n\n"+
+		"Отозвать: /token revoke",
+		result.Token, result.AccessToken.ExpiresAt.Format("02.01.2006"))
+	_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, text)
+	return err
+}
+
+// list shows the active tokens with revoke buttons.
+func (h *AccessTokenHandler) list(ctx context.Context, cmdCtx CommandContext, studentID string) error {
+	tokens, err := h.tokensCmd.List(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID,
+			"🔑 <b>Токены API</b>\n\nАктивных токенов нет. Создать: <code>/token create</code>")
+		return err
+	}
+
+	text, keyboard := accessTokensView(tokens)
+	_, err = cmdCtx.Client.SendWithKeyboard(ctx, cmdCtx.ChatID, text, keyboard.InlineKeyboard)
+	return err
+}
+
+// HandleCallback revokes a token.
+func (h *AccessTokenHandler) HandleCallback(ctx context.Context, cbCtx CallbackContext) error {
+	tokenID := strings.TrimPrefix(cbCtx.Data, tokenCallbackPrefix)
+	if tokenID == "" {
+		return nil
+	}
+
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(cbCtx.TelegramID))
+	if err != nil {
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Ты не зарегистрирован. Используй /start", true)
+	}
+
+	err = h.tokensCmd.Revoke(ctx, stud.ID, tokenID)
+	switch {
+	case errors.Is(err, student.ErrAccessTokenNotFound):
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "Этот токен уже отозван или истёк.", true)
+	case err != nil:
+		h.logger.Warn("access token revoke failed", "student_id", stud.ID, "token_id", tokenID, "error", err)
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Не удалось отозвать, попробуй позже.", true)
+	}
+
+	if tokens, err := h.tokensCmd.List(ctx, stud.ID); err == nil {
+		text, keyboard := accessTokensView(tokens)
+		if len(tokens) == 0 {
+			text = "🔑 <b>Токены API</b>\n\nАктивных токенов нет. Создать: <code>/token create</code>"
+		}
+		_, _ = cbCtx.Client.EditMessageText(ctx, cbCtx.ChatID, int64(cbCtx.MessageID), text, "HTML", keyboard)
+	}
+	return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "🗑 Токен отозван. Он перестанет работать в течение минуты.", false)
+}
+
+// accessTokensView renders the active tokens with one revoke button each.
+func accessTokensView(tokens []*student.AccessToken) (string, *telegram.InlineKeyboardMarkup) {
+	var sb strings.Builder
+	sb.WriteString("🔑 <b>Токены API</b>\n\n")
+
+	rows := make([][]telegram.InlineKeyboardButton, 0, len(tokens))
+	for _, t := range tokens {
+		used := "не использовался"
+		if t.LastUsedAt != nil {
+			used = "использован " + t.LastUsedAt.Format("02.01.2006")
+		}
+		sb.WriteString(fmt.Sprintf("• <code>%s</code> — создан %s, %s, до %s\n",
+			t.Masked(), t.CreatedAt.Format("02.01.2006"), used, t.ExpiresAt.Format("02.01.2006")))
+		rows = append(rows, []telegram.InlineKeyboardButton{{
+			Text:         "🗑 Отозвать " + t.Masked(),
+			CallbackData: tokenCallbackPrefix + t.ID,
+		}})
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n"), &telegram.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
@@ -11,14 +11,21 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/application/cqrs"
 	"github.com/alem-hub/alem-community-hub/internal/application/query"
 	"github.com/alem-hub/alem-community-hub/internal/application/saga"
+	"github.com/alem-hub/alem-community-hub/internal/domain/analytics"
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
 	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/scheduler"
 	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/handler"
 	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/handler/callback"
 	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/middleware"
@@ -40,6 +47,10 @@ type BotConfig struct {
 	// WebhookURL is the URL for webhook mode (required if Mode is "webhook").
 	WebhookURL string
 
+	// WebhookSecretToken is passed to setWebhook; Telegram echoes it in the
+	// X-Telegram-Bot-Api-Secret-Token header so forged updates can be rejected.
+	WebhookSecretToken string
+
 	// WebhookPort is the port to listen on for webhook updates.
 	WebhookPort int
 
@@ -58,8 +69,18 @@ type BotConfig struct {
 	// MaxConcurrentUpdates limits concurrent update processing.
 	MaxConcurrentUpdates int
 
+	// OffsetStore persists the polling offset across restarts (optional).
+	OffsetStore telegram.OffsetStore
+
 	// GracefulShutdownTimeout is the timeout for graceful shutdown.
 	GracefulShutdownTimeout time.Duration
+
+	// AdminIDs are Telegram user IDs allowed to use admin commands.
+	AdminIDs []int64
+
+	// CommandMiddleware runs around every command, outermost first.
+	// nil uses DefaultCommandMiddleware (recovery, logging, metrics, rate limit).
+	CommandMiddleware []CommandMiddleware
 }
 
 // DefaultBotConfig returns sensible defaults.
@@ -70,7 +91,7 @@ func DefaultBotConfig(token string) BotConfig {
 		PollingTimeout:          30,
 		Debug:                   false,
 		Logger:                  slog.Default(),
-		AllowedUpdates:          []string{"message", "callback_query"},
+		AllowedUpdates:          telegram.DefaultAllowedUpdates,
 		MaxConcurrentUpdates:    100,
 		GracefulShutdownTimeout: 30 * time.Second,
 	}
@@ -84,26 +105,72 @@ func DefaultBotConfig(token string) BotConfig {
 // BotDependencies contains all dependencies for the bot handlers.
 type BotDependencies struct {
 	// Repositories
-	StudentRepo student.Repository
+	StudentRepo     student.Repository
+	ProgressRepo    student.ProgressRepository // nil disables streaks
+	HelpRequestRepo social.HelpRequestRepository
+	HelpFeedback    social.HelpFeedbackRepository // nil disables feedback poll answers
+	AuditLog        shared.AuditLog
+	ReportRepo      social.ReportRepository        // nil disables reports
+	HelperStreaks   student.HelperStreakRepository // nil hides helper streaks in /me
+
+	IdentityConflicts student.IdentityConflictRepository // nil hides flagged pairs in /merge
+	DryRunOutbox      notification.DryRunOutbox          // nil disables /dryrunpurge
+
+	// Bus dispatches the commands and queries registered on it
+	// (leaderboard, rank, neighbors, monthly helpers, sync, help request,
+	// connect)
+	Bus *cqrs.Bus
 
 	// Commands
-	SyncStudentCmd     *command.SyncStudentHandler
-	RequestHelpCmd     *command.RequestHelpHandler
-	ConnectStudentsCmd *command.ConnectStudentsHandler
+	HelpClusterCmd     *command.HelpClusterHandler // nil hides the group button
 	UpdatePrefsCmd     *command.UpdatePreferencesHandler
 	ResetPrefsCmd      *command.ResetPreferencesHandler
 	GiveEndorsementCmd *command.GiveEndorsementHandler
+	MarkNotifsReadCmd  *command.MarkNotificationsReadHandler
+	MergeStudentsCmd   *command.MergeStudentsHandler
+	SetWeeklyGoalCmd   *command.SetWeeklyGoalHandler
+	MuteNotifsCmd      *command.MuteNotificationsHandler
+	ReviewXPFlagCmd    *command.ReviewXPFlagHandler // nil disables XP flag review buttons
+	ReportStudentCmd   *command.ReportStudentHandler
+	ReviewReportCmd    *command.ReviewReportHandler
+	FocusCmd           *command.FocusSessionHandler         // nil disables /focus
+	NudgeCmd           *command.NudgeHandler                // nil disables /connections and nudges
+	RenameStudentCmd   *command.RenameStudentHandler        // nil disables display-name refresh
+	RespondToHelpCmd   *command.RespondToHelpRequestHandler // nil disables help invitation buttons
+	SnoozeHelpCmd      *command.SnoozeHelpRequestHandler    // required with RespondToHelpCmd
+	AccessTokensCmd    *command.AccessTokensHandler         // nil disables /token
+	SyncMeCmd          *command.SyncMeHandler               // nil disables /sync_me
+	RelinkCmd          *command.RelinkTelegramHandler       // nil disables /relink
+	HelpTeamCmd        *command.HelpTeamHandler             // nil disables /team
+
+	// Analytics (optional, nil disables usage counting)
+	UsageCounter      analytics.UsageCounter
+	CommandUsageQuery *query.GetCommandUsageHandler
+
+	// Metrics counts processed updates (optional, nil disables)
+	Metrics UpdateMetrics
 
 	// Queries
-	LeaderboardQuery   *query.GetLeaderboardHandler
-	StudentRankQuery   *query.GetStudentRankHandler
-	NeighborsQuery     *query.GetNeighborsHandler
 	FindHelpersQuery   *query.FindHelpersHandler
 	OnlineNowQuery     *query.GetOnlineNowHandler
 	DailyProgressQuery *query.GetDailyProgressHandler
+	NotificationsQuery *query.GetNotificationsHandler
+	AchievementsQuery  *query.GetAchievementsHandler
+	WeeklyGoalQuery    *query.GetWeeklyGoalHandler
+	ForecastQuery      *query.GetForecastHandler         // nil disables /forecast
+	EventQuery         *query.GetEventLeaderboardHandler // nil disables events
+	PopularTasksQuery  *query.GetPopularTasksHandler     // nil disables /populartasks
+	FeedQuery          *query.GetFeedHandler             // nil disables /feed
+	CompareQuery       *query.CompareStudentsHandler     // nil disables /compare
+
+	// ConnectionMilestonesQuery lists connections for /connections (required with NudgeCmd)
+	ConnectionMilestonesQuery *query.GetConnectionMilestonesHandler
 
 	// Sagas
 	OnboardingSaga *saga.OnboardingSaga
+
+	// FocusScheduler runs the one-shot /focus timers (required with FocusCmd)
+	FocusScheduler *scheduler.Scheduler
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -120,9 +187,29 @@ type Bot struct {
 
 	// Middleware chain
 	authMiddleware     *middleware.AuthMiddleware
+	students           *handler.StudentLoader
 	rateLimiter        *middleware.RateLimiter
 	recoveryMiddleware *middleware.RecoveryMiddleware
 	metricsMiddleware  *middleware.MetricsMiddleware
+	usageRecorder      *middleware.UsageRecorder
+
+	// commands is the global chain run around every command
+	commands CommandMiddleware
+
+	// reports takes follow-up report reasons (nil when reports are disabled)
+	reports *ReportHandler
+
+	// focus restores /focus timers on start (nil when /focus is disabled)
+	focus *FocusHandler
+
+	// renames keeps display names in step with Telegram profiles (may be nil)
+	renames *command.RenameStudentHandler
+
+	// relink takes /relink email and password replies (nil when disabled)
+	relink *RelinkHandler
+
+	// metrics counts processed updates (may be nil)
+	metrics UpdateMetrics
 
 	// Lifecycle management
 	running   bool
@@ -135,6 +222,16 @@ type Bot struct {
 	stats *BotStats
 }
 
+// UpdateMetrics counts processed updates by command.
+type UpdateMetrics interface {
+	// RecordTelegramUpdate counts one update; command is a registered
+	// command, "cb:<prefix>" for buttons, "unknown" or "message".
+	RecordTelegramUpdate(command string, err error)
+}
+
+// updateMessage labels updates with a message that is not a command.
+const updateMessage = "message"
+
 // BotStats holds runtime statistics.
 type BotStats struct {
 	mu              sync.RWMutex
@@ -145,6 +242,15 @@ type BotStats struct {
 	CommandsCount   map[string]int64
 }
 
+// bindHandler returns the bus handler of messages of type C, or nil when
+// the bus has none so that handlers keep treating it as disabled.
+func bindHandler[C, R any](bus *cqrs.Bus) cqrs.Handler[C, R] {
+	if !cqrs.Registered[C](bus) {
+		return nil
+	}
+	return cqrs.Bind[C, R](bus)
+}
+
 // NewBot creates a new Telegram bot with all dependencies.
 func NewBot(config BotConfig, deps BotDependencies) (*Bot, error) {
 	if config.Token == "" {
@@ -163,6 +269,9 @@ func NewBot(config BotConfig, deps BotDependencies) (*Bot, error) {
 
 	// Create presenters
 	keyboards := presenter.NewKeyboardBuilder()
+	// The bot token never leaves the process, so it doubles as the key
+	// for signed callback data.
+	callbackCodec := presenter.NewCallbackCodec([]byte(config.Token))
 	cardPresenter := presenter.NewStudentCardPresenter()
 	leaderboardPresenter := presenter.NewLeaderboardPresenter()
 
@@ -174,21 +283,26 @@ func NewBot(config BotConfig, deps BotDependencies) (*Bot, error) {
 	)
 
 	meHandler := handler.NewMeHandler(
-		deps.StudentRankQuery,
+		bindHandler[query.GetStudentRankQuery, *query.GetStudentRankResult](deps.Bus),
 		deps.DailyProgressQuery,
+		deps.NotificationsQuery,
 		deps.StudentRepo,
 		keyboards,
 		cardPresenter,
 	)
+	if deps.HelperStreaks != nil {
+		meHandler.WithHelperStreaks(deps.HelperStreaks)
+	}
 
 	topHandler := handler.NewTopHandler(
-		deps.LeaderboardQuery,
+		bindHandler[query.GetLeaderboardQuery, *query.GetLeaderboardResult](deps.Bus),
+		deps.StudentRepo,
 		keyboards,
-	)
+	).WithMonthlyHelpers(bindHandler[query.GetMonthlyHelpersQuery, *query.GetMonthlyHelpersResult](deps.Bus))
 	_ = leaderboardPresenter // may be used for detailed view later
 
 	neighborsHandler := handler.NewNeighborsHandler(
-		deps.NeighborsQuery,
+		bindHandler[query.GetNeighborsQuery, *query.GetNeighborsResult](deps.Bus),
 		deps.StudentRepo,
 		keyboards,
 	)
@@ -201,36 +315,94 @@ func NewBot(config BotConfig, deps BotDependencies) (*Bot, error) {
 
 	helpHandler := handler.NewHelpHandler(
 		deps.FindHelpersQuery,
+		bindHandler[command.RequestHelpCommand, *command.RequestHelpResult](deps.Bus),
 		deps.StudentRepo,
 		keyboards,
 	)
+	if deps.HelpClusterCmd != nil {
+		helpHandler.WithClusters(deps.HelpClusterCmd)
+	}
 
 	settingsHandler := handler.NewSettingsHandler(
 		deps.UpdatePrefsCmd,
 		deps.ResetPrefsCmd,
 		deps.StudentRepo,
+		callbackCodec,
+	)
+
+	notificationsHandler := handler.NewNotificationsHandler(
+		deps.NotificationsQuery,
+		deps.MarkNotifsReadCmd,
+		keyboards,
+	)
+
+	achievementsHandler := handler.NewAchievementsHandler(
+		deps.AchievementsQuery,
 		keyboards,
 	)
 
+	goalHandler := handler.NewGoalHandler(
+		deps.SetWeeklyGoalCmd,
+		deps.WeeklyGoalQuery,
+		deps.StudentRepo,
+	)
+
+	forecastHandler := handler.NewForecastHandler(
+		deps.ForecastQuery,
+		deps.StudentRepo,
+	)
+
+	muteHandler := handler.NewMuteHandler(
+		deps.MuteNotifsCmd,
+		deps.StudentRepo,
+	)
+
+	privacyHandler := handler.NewPrivacyHandler(
+		deps.UpdatePrefsCmd,
+		deps.StudentRepo,
+	)
+
+	timezoneHandler := handler.NewTimezoneHandler(
+		deps.UpdatePrefsCmd,
+		deps.StudentRepo,
+	)
+
+	eventHandler := handler.NewEventHandler(
+		deps.EventQuery,
+		deps.StudentRepo,
+	)
+
 	// Create callback handlers
 	connectCallback := callback.NewConnectHandler(
-		deps.ConnectStudentsCmd,
+		bindHandler[command.ConnectStudentsCommand, *command.ConnectStudentsResult](deps.Bus),
 		deps.StudentRepo,
 		keyboards,
 	)
 
 	endorseCallback := callback.NewEndorseHandler(
 		deps.GiveEndorsementCmd,
+		deps.HelpRequestRepo,
 		deps.StudentRepo,
 		keyboards,
 	)
 
 	// Create middleware
+	authConfig := middleware.DefaultAuthConfig()
+	authConfig.PublicCommands["/as"] = true // admin check is done by the handler itself
+	authConfig.PublicCommands["/merge"] = true
+	authConfig.PublicCommands["/usage"] = true
+	authConfig.PublicCommands["/reports"] = true
+	authConfig.PublicCommands["/relink"] = true // for new Telegram accounts without a profile
 	authMiddleware := middleware.NewAuthMiddleware(
 		deps.StudentRepo,
-		middleware.DefaultAuthConfig(),
+		authConfig,
 	)
 
+	// Per-update student data, resolved through the auth cache
+	students := handler.NewStudentLoader(authMiddleware).
+		WithRankQuery(bindHandler[query.GetStudentRankQuery, *query.GetStudentRankResult](deps.Bus)).
+		WithProgress(deps.ProgressRepo)
+
 	rateLimiter := middleware.NewRateLimiter(
 		middleware.DefaultRateLimitConfig(),
 	)
@@ -243,6 +415,11 @@ func NewBot(config BotConfig, deps BotDependencies) (*Bot, error) {
 		middleware.DefaultMetricsConfig(),
 	)
 
+	usageRecorder := middleware.NewUsageRecorder(
+		deps.UsageCounter,
+		middleware.DefaultUsageRecorderConfig(),
+	)
+
 	// Create router with all handlers
 	routerConfig := RouterConfig{
 		Logger: config.Logger,
@@ -259,16 +436,114 @@ func NewBot(config BotConfig, deps BotDependencies) (*Bot, error) {
 	router.RegisterCommand("online", onlineHandler)
 	router.RegisterCommand("help", helpHandler)
 	router.RegisterCommand("settings", settingsHandler)
+	router.RegisterCommand("notifications", notificationsHandler)
+	router.RegisterCommand("achievements", achievementsHandler)
+	router.RegisterCommand("goal", goalHandler)
+	router.RegisterCommand("forecast", forecastHandler)
+	router.RegisterCommand("mute", muteHandler)
+	router.RegisterCommand("unmute", muteHandler)
+	router.RegisterCommand("privacy", privacyHandler)
+	router.RegisterCommand("timezone", timezoneHandler)
+	router.RegisterCommand("event", eventHandler)
+	if deps.ProgressRepo != nil {
+		router.RegisterCommand("streak", handler.NewStreakHandler(deps.StudentRepo, query.NewGetStreakCalendarHandler(deps.StudentRepo, deps.ProgressRepo)))
+	}
+	if deps.PopularTasksQuery != nil {
+		router.RegisterCommand("populartasks", handler.NewPopularTasksHandler(deps.PopularTasksQuery, keyboards))
+	}
+	adminOnly := router.AdminOnly(config.AdminIDs)
+	router.RegisterCommand("as", NewImpersonationHandler(
+		router,
+		deps.StudentRepo,
+		deps.AuditLog,
+		config.Logger,
+	), adminOnly)
+	if deps.MergeStudentsCmd != nil {
+		router.RegisterCommand("merge", NewMergeStudentsHandler(
+			deps.StudentRepo,
+			deps.MergeStudentsCmd,
+			config.Logger,
+		).WithIdentityConflicts(deps.IdentityConflicts), adminOnly)
+	}
+	if deps.CommandUsageQuery != nil {
+		router.RegisterCommand("usage", NewCommandUsageHandler(deps.CommandUsageQuery), adminOnly)
+	}
+	if deps.DryRunOutbox != nil {
+		router.RegisterCommand("dryrunpurge", NewDryRunPurgeHandler(deps.DryRunOutbox), adminOnly)
+	}
 
 	// Register callback handlers
-	router.RegisterCallbackPrefix("connect:", connectCallback)
-	router.RegisterCallbackPrefix("endorse:", endorseCallback)
+	router.RegisterCallbackPrefix("connect:", router.createConnectCallbackHandler(connectCallback))
+	router.RegisterCallbackPrefix("endorse:", router.createEndorseCallbackHandler(endorseCallback))
 	router.RegisterCallbackPrefix("cmd:", router.createCommandCallbackHandler())
 	router.RegisterCallbackPrefix("refresh:", router.createRefreshCallbackHandler())
 	router.RegisterCallbackPrefix("top:", router.createTopCallbackHandler(topHandler))
 	router.RegisterCallbackPrefix("online:", router.createOnlineCallbackHandler(onlineHandler))
 	router.RegisterCallbackPrefix("settings:", router.createSettingsCallbackHandler(settingsHandler))
+	router.RegisterCallbackPrefix(handler.SettingsHubPrefix, router.createSettingsHubCallbackHandler(settingsHandler))
 	router.RegisterCallbackPrefix("help:", router.createHelpCallbackHandler(helpHandler))
+	router.RegisterCallbackPrefix("notif:", router.createNotificationsCallbackHandler(notificationsHandler))
+	router.RegisterCallbackPrefix("mute:", router.createMuteCallbackHandler(muteHandler))
+	router.RegisterCallbackPrefix("privacy:", router.createPrivacyCallbackHandler(privacyHandler))
+	router.RegisterCallbackPrefix("timezone:", router.createTimezoneCallbackHandler(timezoneHandler))
+	if deps.HelpFeedback != nil {
+		feedbackCallback := callback.NewFeedbackHandler(deps.HelpRequestRepo, deps.HelpFeedback, deps.StudentRepo)
+		router.RegisterCallbackPrefix("feedback:", router.createFeedbackCallbackHandler(feedbackCallback))
+	}
+	if deps.ReviewXPFlagCmd != nil {
+		router.RegisterCallbackPrefix(xpFlagCallbackPrefix, NewXPFlagReviewHandler(deps.ReviewXPFlagCmd, config.AdminIDs, config.Logger))
+	}
+	var reports *ReportHandler
+	if deps.ReportRepo != nil && deps.ReportStudentCmd != nil && deps.ReviewReportCmd != nil {
+		reports = NewReportHandler(router, deps.ReportStudentCmd, deps.ReviewReportCmd, deps.StudentRepo, client, config.AdminIDs, config.Logger)
+		router.RegisterCallbackPrefix(reportCallbackPrefix, reports)
+		router.RegisterCommand("reports", NewPendingReportsHandler(reports, deps.ReportRepo), adminOnly)
+	}
+	var focus *FocusHandler
+	if deps.FocusCmd != nil && deps.FocusScheduler != nil {
+		focus = NewFocusHandler(router, deps.FocusCmd, deps.StudentRepo, client, deps.FocusScheduler, config.Logger)
+		router.RegisterCommand("focus", focus)
+		router.RegisterCallbackPrefix(focusCallbackPrefix, focus.HandleCallback)
+	}
+	if deps.NudgeCmd != nil && deps.ConnectionMilestonesQuery != nil {
+		nudge := NewNudgeHandler(deps.NudgeCmd, deps.ConnectionMilestonesQuery, deps.StudentRepo, config.Logger)
+		router.RegisterCommand("connections", nudge)
+		router.RegisterCallbackPrefix(nudgeCallbackPrefix, nudge.HandleCallback)
+	}
+	if deps.FeedQuery != nil {
+		router.RegisterCommand("feed", NewFeedHandler(deps.FeedQuery, deps.StudentRepo))
+	}
+	if deps.CompareQuery != nil {
+		router.RegisterCommand("compare", NewCompareHandler(deps.CompareQuery, deps.StudentRepo))
+	}
+	if deps.AccessTokensCmd != nil {
+		tokens := NewAccessTokenHandler(deps.AccessTokensCmd, deps.StudentRepo, config.Logger)
+		router.RegisterCommand("token", tokens)
+		router.RegisterCallbackPrefix(tokenCallbackPrefix, tokens.HandleCallback)
+	}
+	if deps.SyncMeCmd != nil {
+		router.RegisterCommand("sync_me", NewSyncMeHandler(deps.SyncMeCmd, deps.StudentRepo, config.Logger))
+	}
+	if deps.RespondToHelpCmd != nil && deps.SnoozeHelpCmd != nil {
+		invitations := NewHelpInvitationHandler(deps.RespondToHelpCmd, deps.SnoozeHelpCmd, deps.StudentRepo, config.Logger)
+		router.RegisterCallbackPrefix(helpInvitationCallbackPrefix, invitations.HandleCallback)
+	}
+	if requestHelp := bindHandler[command.RequestHelpCommand, *command.RequestHelpResult](deps.Bus); deps.HelpTeamCmd != nil && requestHelp != nil {
+		team := NewTeamHelpHandler(requestHelp, deps.HelpTeamCmd, deps.StudentRepo, config.Logger)
+		router.RegisterCommand("team", team)
+		router.RegisterCallbackPrefix(helpTeamCallbackPrefix, team.HandleCallback)
+	}
+
+	var relink *RelinkHandler
+	if deps.RelinkCmd != nil {
+		relink = NewRelinkHandler(deps.RelinkCmd, deps.StudentRepo, authMiddleware, rateLimiter, client, config.Logger)
+		router.RegisterCommand("relink", relink)
+	}
+
+	commandMiddleware := config.CommandMiddleware
+	if commandMiddleware == nil {
+		commandMiddleware = DefaultCommandMiddleware(recoveryMiddleware, metricsMiddleware, rateLimiter, config.Logger)
+	}
 
 	// Create bot
 	bot := &Bot{
@@ -277,9 +552,17 @@ func NewBot(config BotConfig, deps BotDependencies) (*Bot, error) {
 		router:             router,
 		logger:             config.Logger,
 		authMiddleware:     authMiddleware,
+		students:           students,
 		rateLimiter:        rateLimiter,
 		recoveryMiddleware: recoveryMiddleware,
 		metricsMiddleware:  metricsMiddleware,
+		usageRecorder:      usageRecorder,
+		commands:           ChainCommands(commandMiddleware...),
+		reports:            reports,
+		focus:              focus,
+		renames:            deps.RenameStudentCmd,
+		relink:             relink,
+		metrics:            deps.Metrics,
 		stopCh:             make(chan struct{}),
 		updateSem:          make(chan struct{}, config.MaxConcurrentUpdates),
 		stats: &BotStats{
@@ -315,6 +598,11 @@ func (b *Bot) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to verify bot token: %w", err)
 	}
 
+	// Sessions that were running before a restart get their timers back
+	if b.focus != nil {
+		b.focus.Recover(ctx)
+	}
+
 	// Start based on mode
 	switch b.config.Mode {
 	case "polling":
@@ -358,6 +646,9 @@ func (b *Bot) Stop(ctx context.Context) error {
 		return ctx.Err()
 	}
 
+	// Flush queued usage events
+	b.usageRecorder.Close(ctx)
+
 	return nil
 }
 
@@ -389,10 +680,31 @@ func (b *Bot) verifyToken(ctx context.Context) error {
 // ══════════════════════════════════════════════════════════════════════════════
 
 // startPolling starts long polling for updates.
+// Stop cancels the pending long poll instead of waiting out its timeout,
+// and waits until the last handled offset is saved.
 func (b *Bot) startPolling(ctx context.Context) error {
 	b.logger.Info("starting long polling")
 
-	return b.client.StartPolling(ctx, func(ctx context.Context, update *telegram.Update) error {
+	b.wg.Add(1)
+	defer b.wg.Done()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-b.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	pollingConfig := telegram.DefaultPollingConfig()
+	pollingConfig.Timeout = b.config.PollingTimeout
+	pollingConfig.AllowedUpdates = b.config.AllowedUpdates
+	pollingConfig.Workers = b.config.MaxConcurrentUpdates
+	pollingConfig.OffsetStore = b.config.OffsetStore
+
+	return b.client.StartPolling(ctx, pollingConfig, func(ctx context.Context, update *telegram.Update) error {
 		return b.handleUpdate(ctx, update)
 	})
 }
@@ -413,7 +725,7 @@ func (b *Bot) startWebhook(ctx context.Context) error {
 	)
 
 	// Set webhook
-	err := b.client.SetWebhook(ctx, b.config.WebhookURL, 0, b.config.AllowedUpdates)
+	err := b.client.SetWebhook(ctx, b.config.WebhookURL, 0, b.config.AllowedUpdates, b.config.WebhookSecretToken)
 	if err != nil {
 		return fmt.Errorf("failed to set webhook: %w", err)
 	}
@@ -466,9 +778,10 @@ func (b *Bot) handleUpdate(ctx context.Context, update *telegram.Update) error {
 		return nil
 	}
 
-	// Record metrics (using metrics context if available)
 	duration := time.Since(startTime)
-	_ = duration // metrics tracking done via Start/Finish pattern
+	if b.metrics != nil {
+		b.metrics.RecordTelegramUpdate(b.updateCommand(update), err)
+	}
 
 	if err != nil {
 		b.stats.mu.Lock()
@@ -499,8 +812,8 @@ func (b *Bot) handleMessage(ctx context.Context, msg *telegram.Message) error {
 
 	// DEBUG: Log all incoming messages
 	b.logger.Info("📨 INCOMING MESSAGE",
-		"telegram_id", telegramID,
-		"chat_id", chatID,
+		"telegram_id", student.TelegramID(telegramID),
+		"chat_id", student.TelegramID(chatID),
 		"text", msg.Text,
 		"from", msg.From.Username,
 	)
@@ -537,60 +850,68 @@ func (b *Bot) handleCommand(
 	b.stats.CommandsCount[command]++
 	b.stats.mu.Unlock()
 
-	// Rate limiting
-	rateLimitResult := b.rateLimiter.Check(ctx, telegramID)
-	if !rateLimitResult.Allowed {
-		return b.sendRateLimitMessage(ctx, chatID, rateLimitResult.RetryAfter)
+	var dispatch CommandHandler = CommandHandlerFunc(b.dispatchCommand)
+	if b.commands != nil {
+		dispatch = b.commands(dispatch)
 	}
 
-	// Authentication
-	authResult, err := b.authMiddleware.Authenticate(ctx, telegramID, "/"+command)
+	return dispatch.Handle(ctx, CommandContext{
+		Command:    command,
+		TelegramID: telegramID,
+		ChatID:     chatID,
+		MessageID:  messageID,
+		Args:       args,
+		Message:    msg,
+		Client:     b.client,
+	})
+}
+
+// dispatchCommand authenticates the sender and routes the command.
+// It runs innermost in the global command chain.
+func (b *Bot) dispatchCommand(ctx context.Context, cmdCtx CommandContext) error {
+	authResult, err := b.authMiddleware.Authenticate(ctx, cmdCtx.TelegramID, "/"+cmdCtx.Command)
 	if err != nil {
 		b.logger.Error("auth error", "error", err)
-		return b.sendErrorMessage(ctx, chatID)
+		return b.sendErrorMessage(ctx, cmdCtx.ChatID)
+	}
+
+	usageCommand := analytics.CommandUnknown
+	if b.router.HasCommand(cmdCtx.Command) {
+		usageCommand = cmdCtx.Command
 	}
+	b.usageRecorder.Record(usageCommand, usageCohort(authResult))
 
 	if !authResult.ShouldContinue {
-		_, err := b.client.SendHTML(ctx, chatID, authResult.ResponseMessage)
+		_, err := b.client.SendHTML(ctx, cmdCtx.ChatID, authResult.ResponseMessage)
 		return err
 	}
 
 	// Add authenticated student to context
-	if authResult.Student != nil {
-		ctx = middleware.ContextWithStudent(ctx, authResult.Student)
+	var from *telegram.User
+	if cmdCtx.Message != nil {
+		from = cmdCtx.Message.From
 	}
-
-	// Recovery wrapper
-	recoveryResult := b.recoveryMiddleware.RecoverWithHandler(ctx, telegramID, command, func() error {
-		return b.router.HandleCommand(ctx, command, CommandContext{
-			TelegramID: telegramID,
-			ChatID:     chatID,
-			MessageID:  messageID,
-			Args:       args,
-			Message:    msg,
-			Client:     b.client,
-		})
-	})
-
-	if recoveryResult.Recovered {
-		b.logger.Error("panic recovered in command handler",
-			"command", command,
-			"telegram_id", telegramID,
-		)
-		_, err := b.client.SendHTML(ctx, chatID, recoveryResult.UserMessage)
-		return err
+	stud := b.refreshDisplayName(ctx, from, authResult.Student)
+	if stud != nil {
+		ctx = middleware.ContextWithStudent(ctx, stud)
 	}
+	ctx = handler.ContextWithStudentContext(ctx, b.students.ForUpdate(cmdCtx.TelegramID, stud))
 
-	return nil
+	return b.router.HandleCommand(ctx, cmdCtx.Command, cmdCtx)
 }
 
 // handleTextMessage processes a non-command text message.
 func (b *Bot) handleTextMessage(ctx context.Context, telegramID, chatID int64, msg *telegram.Message) error {
 	b.logger.Info("🔍 handleTextMessage CALLED",
-		"telegram_id", telegramID,
+		"telegram_id", student.TelegramID(telegramID),
 		"text", msg.Text,
 	)
 
+	// Quick action buttons run their command like the typed one
+	if command, ok := b.router.QuickActionCommand(msg.Text); ok {
+		return b.handleCommand(ctx, telegramID, chatID, int(msg.MessageID), command, "", msg)
+	}
+
 	// Check if user is in onboarding state (not registered)
 	authResult, err := b.authMiddleware.Authenticate(ctx, telegramID, "")
 	if err != nil {
@@ -605,6 +926,11 @@ func (b *Bot) handleTextMessage(ctx context.Context, telegramID, chatID int64, m
 
 	// If user is not registered, treat as Alem login input
 	if !authResult.IsAuthenticated {
+		// A message after /relink is the email or password of the profile
+		if b.relink != nil && msg.Chat != nil && msg.Chat.Type == privateChatType &&
+			b.relink.TakeInput(ctx, telegramID, chatID, int(msg.MessageID), msg.Text) {
+			return nil
+		}
 		b.logger.Info("✅ User NOT authenticated - forwarding to HandleTextInput")
 		err := b.router.HandleTextInput(ctx, TextInputContext{
 			TelegramID: telegramID,
@@ -620,9 +946,23 @@ func (b *Bot) handleTextMessage(ctx context.Context, telegramID, chatID int64, m
 		return err
 	}
 
-	// User is registered but sent a text message - might be a query or just chat
-	b.logger.Info("⏭️ User IS authenticated - ignoring text message")
-	return nil
+	// User is registered but sent a text message: in private chat point to
+	// the commands, group chatter is ignored
+	if msg.Chat == nil || msg.Chat.Type != privateChatType {
+		b.logger.Info("⏭️ User IS authenticated - ignoring group text message")
+		return nil
+	}
+	// A message right after "⚠️ Пожаловаться" is the reason of the report
+	if b.reports != nil && b.reports.TakeReason(ctx, telegramID, msg.Text) {
+		return nil
+	}
+	return b.router.defaultCommandHandler(ctx, CommandContext{
+		TelegramID: telegramID,
+		ChatID:     chatID,
+		MessageID:  int(msg.MessageID),
+		Message:    msg,
+		Client:     b.client,
+	})
 }
 
 // handleCallbackQuery processes a callback query from inline keyboard.
@@ -659,10 +999,18 @@ func (b *Bot) handleCallbackQuery(ctx context.Context, cq *telegram.CallbackQuer
 		return nil
 	}
 
+	usageCommand := analytics.CallbackPrefix + analytics.CommandUnknown
+	if prefix := b.router.MatchCallbackPrefix(cq.Data); prefix != "" {
+		usageCommand = analytics.CallbackPrefix + strings.TrimSuffix(prefix, ":")
+	}
+	b.usageRecorder.Record(usageCommand, usageCohort(authResult))
+
 	// Add authenticated student to context
-	if authResult.Student != nil {
-		ctx = middleware.ContextWithStudent(ctx, authResult.Student)
+	stud := b.refreshDisplayName(ctx, cq.From, authResult.Student)
+	if stud != nil {
+		ctx = middleware.ContextWithStudent(ctx, stud)
 	}
+	ctx = handler.ContextWithStudentContext(ctx, b.students.ForUpdate(telegramID, stud))
 
 	// Recovery wrapper
 	recoveryResult := b.recoveryMiddleware.RecoverWithHandler(ctx, telegramID, "callback:"+cq.Data, func() error {
@@ -680,7 +1028,7 @@ func (b *Bot) handleCallbackQuery(ctx context.Context, cq *telegram.CallbackQuer
 	if recoveryResult.Recovered {
 		b.logger.Error("panic recovered in callback handler",
 			"data", cq.Data,
-			"telegram_id", telegramID,
+			"telegram_id", student.TelegramID(telegramID),
 		)
 		if chatID > 0 {
 			_, _ = b.client.SendHTML(ctx, chatID, recoveryResult.UserMessage)
@@ -694,6 +1042,27 @@ func (b *Bot) handleCallbackQuery(ctx context.Context, cq *telegram.CallbackQuer
 // HELPER METHODS
 // ══════════════════════════════════════════════════════════════════════════════
 
+// updateCommand labels an update for metrics with the same names as
+// command usage, so the number of label values stays bounded.
+func (b *Bot) updateCommand(update *telegram.Update) string {
+	if cq := update.CallbackQuery; cq != nil {
+		if prefix := b.router.MatchCallbackPrefix(cq.Data); prefix != "" {
+			return analytics.CallbackPrefix + strings.TrimSuffix(prefix, ":")
+		}
+		return analytics.CallbackPrefix + analytics.CommandUnknown
+	}
+
+	command := telegram.ExtractCommand(update.Message)
+	switch {
+	case command == "":
+		return updateMessage
+	case b.router.HasCommand(command):
+		return command
+	default:
+		return analytics.CommandUnknown
+	}
+}
+
 // extractTelegramID extracts the Telegram user ID from an update.
 func (b *Bot) extractTelegramID(update *telegram.Update) int64 {
 	if update.Message != nil && update.Message.From != nil {
@@ -705,11 +1074,40 @@ func (b *Bot) extractTelegramID(update *telegram.Update) int64 {
 	return 0
 }
 
-// sendRateLimitMessage sends a rate limit warning message.
-func (b *Bot) sendRateLimitMessage(ctx context.Context, chatID int64, waitTime time.Duration) error {
-	text := fmt.Sprintf("⏳ Слишком много запросов!\nПопробуй через %d секунд.", int(waitTime.Seconds()))
-	_, err := b.client.SendHTML(ctx, chatID, text)
-	return err
+// refreshDisplayName renames the student when the sender's Telegram name no
+// longer matches the stored one, and returns the student to handle the
+// update with. Failures are logged: a stale name must not block the update.
+func (b *Bot) refreshDisplayName(ctx context.Context, from *telegram.User, stud *student.Student) *student.Student {
+	if b.renames == nil || from == nil || stud == nil {
+		return stud
+	}
+
+	result, err := b.renames.Handle(ctx, command.RenameStudentCommand{
+		StudentID: stud.ID,
+		KnownName: stud.DisplayName,
+		FirstName: from.FirstName,
+		LastName:  from.LastName,
+		Username:  from.Username,
+	})
+	if err != nil {
+		b.logger.Warn("failed to refresh display name", "student_id", stud.ID, "error", err)
+		return stud
+	}
+	if !result.Renamed {
+		return stud
+	}
+
+	b.logger.Info("student renamed", "student_id", stud.ID)
+	b.authMiddleware.InvalidateCache(int64(stud.TelegramID))
+	return result.Student
+}
+
+// usageCohort returns the cohort to count usage under.
+func usageCohort(authResult *middleware.AuthResult) string {
+	if authResult.Student == nil || authResult.Student.Cohort == "" {
+		return analytics.CohortUnknown
+	}
+	return string(authResult.Student.Cohort)
 }
 
 // sendErrorMessage sends a generic error message.
@@ -742,6 +1140,7 @@ func (b *Bot) GetStats() map[string]interface{} {
 		"updates_handled":  b.stats.UpdatesHandled,
 		"errors_count":     b.stats.ErrorsCount,
 		"commands_count":   commandsCopy,
+		"usage_recorder":   b.usageRecorder.Stats(),
 		"running":          b.IsRunning(),
 	}
 }
@@ -765,3 +1164,9 @@ func (b *Bot) Router() *Router {
 func (b *Bot) InvalidateAuthCache(telegramID int64) {
 	b.authMiddleware.InvalidateCache(telegramID)
 }
+
+// InvalidationTarget returns the bot's in-process student cache, to be fed
+// by a cache invalidation subscriber.
+func (b *Bot) InvalidationTarget() shared.InvalidationTarget {
+	return b.authMiddleware
+}
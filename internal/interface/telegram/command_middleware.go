@@ -0,0 +1,168 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/middleware"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// COMMAND MIDDLEWARE
+// Wraps command handlers the way HTTP middleware wraps http.Handler.
+// The bot runs a global chain around every command (BotConfig.CommandMiddleware);
+// RegisterCommand adds per-command middleware (e.g. AdminOnly) inside it.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// CommandHandlerFunc adapts a function to CommandHandler.
+type CommandHandlerFunc func(ctx context.Context, cmdCtx CommandContext) error
+
+// Handle calls f(ctx, cmdCtx).
+func (f CommandHandlerFunc) Handle(ctx context.Context, cmdCtx CommandContext) error {
+	return f(ctx, cmdCtx)
+}
+
+// CommandMiddleware is a function that wraps a CommandHandler.
+type CommandMiddleware func(CommandHandler) CommandHandler
+
+// ChainCommands chains multiple middleware functions.
+// The first middleware is the outermost: it runs first and returns last.
+func ChainCommands(middlewares ...CommandMiddleware) CommandMiddleware {
+	return func(final CommandHandler) CommandHandler {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			final = middlewares[i](final)
+		}
+		return final
+	}
+}
+
+// DefaultCommandMiddleware returns the global chain used when
+// BotConfig.CommandMiddleware is nil: recovery, logging, metrics, rate limit.
+func DefaultCommandMiddleware(
+	recovery *middleware.RecoveryMiddleware,
+	metrics *middleware.MetricsMiddleware,
+	limiter *middleware.RateLimiter,
+	logger *slog.Logger,
+) []CommandMiddleware {
+	return []CommandMiddleware{
+		RecoverCommands(recovery, logger),
+		LogCommands(logger),
+		MeasureCommands(metrics),
+		RateLimitCommands(limiter),
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// BUILT-IN MIDDLEWARE
+// ─────────────────────────────────────────────────────────────────────────────
+
+// RecoverCommands recovers from panics in the wrapped handler: the stack is
+// logged, the user gets a friendly error and the dispatcher keeps running.
+func RecoverCommands(recovery *middleware.RecoveryMiddleware, logger *slog.Logger) CommandMiddleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(next CommandHandler) CommandHandler {
+		return CommandHandlerFunc(func(ctx context.Context, cmdCtx CommandContext) error {
+			result, err := recovery.Run(ctx, cmdCtx.TelegramID, cmdCtx.Command, func() error {
+				return next.Handle(ctx, cmdCtx)
+			})
+			if !result.Recovered {
+				return err
+			}
+
+			attrs := []any{
+				"command", cmdCtx.Command,
+				"telegram_id", student.TelegramID(cmdCtx.TelegramID),
+			}
+			if result.PanicInfo != nil {
+				attrs = append(attrs, "panic", result.PanicInfo.PanicValue, "stack", result.PanicInfo.StackTrace)
+			}
+			logger.Error("panic recovered in command handler", attrs...)
+
+			if cmdCtx.Client == nil {
+				return nil
+			}
+			_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, result.UserMessage)
+			return err
+		})
+	}
+}
+
+// LogCommands logs every command with its chat, duration and outcome.
+func LogCommands(logger *slog.Logger) CommandMiddleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(next CommandHandler) CommandHandler {
+		return CommandHandlerFunc(func(ctx context.Context, cmdCtx CommandContext) error {
+			start := time.Now()
+			err := next.Handle(ctx, cmdCtx)
+
+			attrs := []any{
+				"command", cmdCtx.Command,
+				"chat_id", cmdCtx.ChatID,
+				"telegram_id", student.TelegramID(cmdCtx.TelegramID),
+				"duration", time.Since(start),
+			}
+			if err != nil {
+				logger.Warn("command handled", append(attrs, "outcome", "error", "error", err)...)
+			} else {
+				logger.Info("command handled", append(attrs, "outcome", "ok")...)
+			}
+			return err
+		})
+	}
+}
+
+// MeasureCommands records per-command latency and errors.
+func MeasureCommands(metrics *middleware.MetricsMiddleware) CommandMiddleware {
+	return func(next CommandHandler) CommandHandler {
+		return CommandHandlerFunc(func(ctx context.Context, cmdCtx CommandContext) error {
+			request := metrics.Start(cmdCtx.Command, cmdCtx.TelegramID)
+			err := next.Handle(ctx, cmdCtx)
+			request.End(err)
+			return err
+		})
+	}
+}
+
+// RateLimitCommands applies the per-user rate limiter; limited users get a
+// "too many requests" reply instead of the command.
+func RateLimitCommands(limiter *middleware.RateLimiter) CommandMiddleware {
+	return func(next CommandHandler) CommandHandler {
+		return CommandHandlerFunc(func(ctx context.Context, cmdCtx CommandContext) error {
+			result := limiter.Check(ctx, cmdCtx.TelegramID)
+			if result.Allowed {
+				return next.Handle(ctx, cmdCtx)
+			}
+
+			text := fmt.Sprintf("⏳ Слишком много запросов!\nПопробуй через %d секунд.", int(result.RetryAfter.Seconds()))
+			_, err := cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, text)
+			return err
+		})
+	}
+}
+
+// AdminOnly lets only Telegram users listed in adminIDs through; everyone
+// else sees the command as unknown.
+func (r *Router) AdminOnly(adminIDs []int64) CommandMiddleware {
+	admins := make(map[int64]bool, len(adminIDs))
+	for _, id := range adminIDs {
+		admins[id] = true
+	}
+
+	return func(next CommandHandler) CommandHandler {
+		return CommandHandlerFunc(func(ctx context.Context, cmdCtx CommandContext) error {
+			if !admins[cmdCtx.TelegramID] {
+				return r.defaultCommandHandler(ctx, cmdCtx)
+			}
+			return next.Handle(ctx, cmdCtx)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/handler"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/middleware"
+)
+
+// tracing records when it enters and leaves the wrapped handler.
+func tracing(name string, trace *[]string) CommandMiddleware {
+	return func(next CommandHandler) CommandHandler {
+		return CommandHandlerFunc(func(ctx context.Context, cmdCtx CommandContext) error {
+			*trace = append(*trace, name+">")
+			err := next.Handle(ctx, cmdCtx)
+			*trace = append(*trace, "<"+name)
+			return err
+		})
+	}
+}
+
+func TestChainCommands_OutermostFirst(t *testing.T) {
+	var trace []string
+	errBoom := errors.New("boom")
+
+	h := ChainCommands(tracing("a", &trace), tracing("b", &trace))(
+		CommandHandlerFunc(func(context.Context, CommandContext) error {
+			trace = append(trace, "handler")
+			return errBoom
+		}))
+
+	err := h.Handle(context.Background(), CommandContext{})
+	assert.ErrorIs(t, err, errBoom)
+	assert.Equal(t, []string{"a>", "b>", "handler", "<b", "<a"}, trace)
+}
+
+func TestRouter_CommandMiddlewareRunsInsideGlobalChain(t *testing.T) {
+	var trace []string
+	router := NewRouter(RouterConfig{})
+	router.RegisterCommand("ping", CommandHandlerFunc(func(_ context.Context, cmdCtx CommandContext) error {
+		trace = append(trace, "handler:"+cmdCtx.Command)
+		return nil
+	}), tracing("per-command", &trace))
+
+	global := ChainCommands(tracing("global", &trace))(CommandHandlerFunc(func(ctx context.Context, cmdCtx CommandContext) error {
+		return router.HandleCommand(ctx, "ping", cmdCtx)
+	}))
+
+	require.NoError(t, global.Handle(context.Background(), CommandContext{}))
+	assert.Equal(t, []string{"global>", "per-command>", "handler:ping", "<per-command", "<global"}, trace)
+}
+
+func TestBot_PanickingCommandRepliesAndKeepsDispatching(t *testing.T) {
+	repo := &fakeStudentRepo{byTelegramID: map[student.TelegramID]*student.Student{
+		42: {ID: "student-42", TelegramID: 42, DisplayName: "Айдана", LastSeenAt: time.Now()},
+	}}
+
+	router := NewRouter(RouterConfig{})
+	router.RegisterCommand("boom", CommandHandlerFunc(func(context.Context, CommandContext) error {
+		panic("nil map")
+	}))
+	router.RegisterCommand("ping", CommandHandlerFunc(func(ctx context.Context, cmdCtx CommandContext) error {
+		_, err := cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, "pong")
+		return err
+	}))
+
+	recoveryConfig := middleware.DefaultRecoveryConfig()
+	recoveryConfig.LogPanics = false
+	auth := middleware.NewAuthMiddleware(repo, middleware.DefaultAuthConfig())
+	client, sent := newFakeTelegram(t)
+	bot := &Bot{
+		client:         client,
+		router:         router,
+		logger:         slog.Default(),
+		authMiddleware: auth,
+		students:       handler.NewStudentLoader(auth),
+		usageRecorder:  middleware.NewUsageRecorder(nil, middleware.DefaultUsageRecorderConfig()),
+		commands: ChainCommands(DefaultCommandMiddleware(
+			middleware.NewRecoveryMiddleware(recoveryConfig),
+			middleware.NewMetricsMiddleware(middleware.DefaultMetricsConfig()),
+			middleware.NewRateLimiter(middleware.DefaultRateLimitConfig()),
+			nil,
+		)...),
+		stats: &BotStats{CommandsCount: make(map[string]int64)},
+	}
+	ctx := context.Background()
+
+	require.NotPanics(t, func() {
+		require.NoError(t, bot.handleCommand(ctx, 42, 42, 1, "boom", "", nil))
+	})
+	require.NoError(t, bot.handleCommand(ctx, 42, 42, 2, "ping", "", nil))
+
+	require.Len(t, sent.messages, 2)
+	assert.Equal(t, recoveryConfig.UserErrorMessage, sent.messages[0]["text"])
+	assert.Equal(t, "pong", sent.messages[1]["text"])
+}
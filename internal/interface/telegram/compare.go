@@ -0,0 +1,129 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// COMPARE
+// "/compare @login" shows the student and another one side by side: XP,
+// level, global and cohort rank, streak, tasks today and help rating, with
+// the gap between them (query.CompareStudentsHandler).
+// ══════════════════════════════════════════════════════════════════════════════
+
+// compareUsage explains the /compare arguments.
+const compareUsage = "⚔️ <b>Сравнение</b>\n\n" +
+	"<code>/compare @логин</code> — ты и другой студент бок о бок."
+
+// CompareHandler handles /compare.
+type CompareHandler struct {
+	compareQuery *query.CompareStudentsHandler
+	studentRepo  student.Repository
+}
+
+// NewCompareHandler creates a new CompareHandler.
+func NewCompareHandler(compareQuery *query.CompareStudentsHandler, studentRepo student.Repository) *CompareHandler {
+	return &CompareHandler{
+		compareQuery: compareQuery,
+		studentRepo:  studentRepo,
+	}
+}
+
+// Handle compares the student with the one given by login.
+func (h *CompareHandler) Handle(ctx context.Context, cmdCtx CommandContext) error {
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(cmdCtx.TelegramID))
+	if err != nil {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, "❌ Ты не зарегистрирован. Используй /start")
+		return err
+	}
+
+	login := strings.TrimSpace(cmdCtx.Args)
+	if login == "" {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, compareUsage)
+		return err
+	}
+
+	result, err := h.compareQuery.Handle(ctx, query.CompareStudentsQuery{ViewerID: stud.ID, OtherLogin: login})
+	switch {
+	case errors.Is(err, query.ErrCompareWithSelf):
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID,
+			"🙃 С собой сравнивать неинтересно — ты всегда наравне. Укажи логин другого студента.")
+		return err
+	case shared.IsNotFound(err):
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID,
+			fmt.Sprintf("🤔 Не нашёл студента <code>%s</code>.", html.EscapeString(login)))
+		return err
+	case err != nil:
+		return err
+	}
+
+	_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, compareView(result))
+	return err
+}
+
+// compareView renders the comparison in the style of the /me card:
+// the student's value first, the other's second.
+func compareView(r *query.CompareStudentsResult) string {
+	me, other := r.Viewer, r.Other
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("⚔️ <b>Ты</b> vs <b>%s</b>\n\n", html.EscapeString(other.DisplayName)))
+
+	sb.WriteString("📊 <b>Прогресс</b>\n")
+	sb.WriteString(fmt.Sprintf("├ XP: <code>%d</code> · <code>%d</code> (%s)\n", me.XP, other.XP, formatSigned(r.XPGap)))
+	sb.WriteString(fmt.Sprintf("├ Уровень: <b>%d</b> · <b>%d</b>\n", me.Level, other.Level))
+	sb.WriteString(fmt.Sprintf("├ Позиция: %s · %s\n", compareRank(me.GlobalRank, me.RankHidden), compareRank(other.GlobalRank, other.RankHidden)))
+	sb.WriteString(fmt.Sprintf("└ В потоке: %s · %s\n\n", compareRank(me.CohortRank, me.RankHidden), compareRank(other.CohortRank, other.RankHidden)))
+
+	sb.WriteString("🔥 <b>Активность</b>\n")
+	sb.WriteString(fmt.Sprintf("├ Серия: %d · %d дней\n", me.Streak, other.Streak))
+	sb.WriteString(fmt.Sprintf("├ Задач сегодня: %d · %d\n", me.TasksToday, other.TasksToday))
+	sb.WriteString(fmt.Sprintf("└ Рейтинг помощи: ⭐ %.1f · ⭐ %.1f\n\n", me.HelpRating, other.HelpRating))
+
+	switch r.Leader {
+	case query.CompareLeaderViewer:
+		sb.WriteString(fmt.Sprintf("🏁 Ты впереди на <b>%d XP</b>", r.XPGap))
+	case query.CompareLeaderOther:
+		sb.WriteString(fmt.Sprintf("🎯 До %s осталось <b>%d XP</b>", html.EscapeString(other.DisplayName), -r.XPGap))
+	default:
+		sb.WriteString("🤝 Идёте ноздря в ноздрю")
+	}
+	if r.RankGap != 0 {
+		places := r.RankGap
+		if places < 0 {
+			places = -places
+		}
+		sb.WriteString(fmt.Sprintf(" (%d мест в рейтинге)", places))
+	}
+	sb.WriteString("!")
+
+	return sb.String()
+}
+
+// compareRank formats a rank for /compare.
+func compareRank(rank int, hidden bool) string {
+	switch {
+	case hidden:
+		return "🙈"
+	case rank == 0:
+		return "—"
+	default:
+		return fmt.Sprintf("<b>#%d</b>", rank)
+	}
+}
+
+// formatSigned formats a number with an explicit sign.
+func formatSigned(n int) string {
+	if n > 0 {
+		return fmt.Sprintf("+%d", n)
+	}
+	return fmt.Sprintf("%d", n)
+}
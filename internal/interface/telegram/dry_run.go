@@ -0,0 +1,45 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/notification"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// DRY RUN OUTBOX
+// Admin-only "/dryrunpurge [job]": clears messages the worker recorded in
+// dry-run mode instead of sending. Without a job the whole outbox is cleared.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// DryRunPurgeHandler handles the admin-only /dryrunpurge command.
+type DryRunPurgeHandler struct {
+	outbox notification.DryRunOutbox
+}
+
+// NewDryRunPurgeHandler creates a new DryRunPurgeHandler.
+// Register it behind Router.AdminOnly.
+func NewDryRunPurgeHandler(outbox notification.DryRunOutbox) *DryRunPurgeHandler {
+	return &DryRunPurgeHandler{outbox: outbox}
+}
+
+// Handle processes "/dryrunpurge [job]".
+func (h *DryRunPurgeHandler) Handle(ctx context.Context, cmdCtx CommandContext) error {
+	job := strings.TrimSpace(cmdCtx.Args)
+
+	deleted, err := h.outbox.Purge(ctx, notification.DryRunFilter{Job: job})
+	if err != nil {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, "❌ Не удалось очистить outbox: "+html.EscapeString(err.Error()))
+		return err
+	}
+
+	scope := "все задачи"
+	if job != "" {
+		scope = "<code>" + html.EscapeString(job) + "</code>"
+	}
+	_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, fmt.Sprintf("🧹 Dry-run outbox очищен (%s): удалено %d", scope, deleted))
+	return err
+}
@@ -0,0 +1,71 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// FEED
+// "/feed" shows what recently happened in the student's cohort: unlocked
+// achievements, resolved help requests, personal bests, new students and the
+// helper of the week (query.GetFeedHandler).
+// ══════════════════════════════════════════════════════════════════════════════
+
+// feedItemsShown is how many items /feed lists.
+const feedItemsShown = 15
+
+// FeedHandler handles /feed.
+type FeedHandler struct {
+	feedQuery   *query.GetFeedHandler
+	studentRepo student.Repository
+}
+
+// NewFeedHandler creates a new FeedHandler.
+func NewFeedHandler(feedQuery *query.GetFeedHandler, studentRepo student.Repository) *FeedHandler {
+	return &FeedHandler{
+		feedQuery:   feedQuery,
+		studentRepo: studentRepo,
+	}
+}
+
+// Handle shows the latest items of the student's cohort.
+func (h *FeedHandler) Handle(ctx context.Context, cmdCtx CommandContext) error {
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(cmdCtx.TelegramID))
+	if err != nil {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, "❌ Ты не зарегистрирован. Используй /start")
+		return err
+	}
+
+	result, err := h.feedQuery.Handle(ctx, query.GetFeedQuery{
+		Cohort: string(stud.Cohort),
+		Limit:  feedItemsShown,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, feedView(result.Items))
+	return err
+}
+
+// feedView renders the feed, newest first.
+func feedView(items []query.FeedItemDTO) string {
+	var sb strings.Builder
+	sb.WriteString("📰 <b>Что нового в потоке</b>\n\n")
+
+	if len(items) == 0 {
+		sb.WriteString("Пока тихо. Реши задачу или помоги кому-нибудь — и попадёшь в ленту!")
+		return sb.String()
+	}
+
+	for _, item := range items {
+		sb.WriteString(fmt.Sprintf("%s <i>· %s</i>\n", html.EscapeString(item.Text), item.Ago))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,440 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/scheduler"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// FOCUS MODE
+// "/focus" starts a 50-minute session ("/focus тихо" skips the halfway
+// nudge, "/focus стоп" ends it early). The start message lists connections
+// to invite: "focus:i:<session>:<student>". The buddy answers with
+// "focus:a:<session>" or "focus:d:<session>". "focus:s:<session>" stops the
+// session and "focus:o:<session>:<outcome>" records what a participant got
+// done at the end; "stuck" opens the help flow.
+//
+// Timers are one-shot scheduler jobs named after the session; on startup
+// Recover schedules them again from focus_sessions.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// focusCallbackPrefix is the callback prefix of the focus buttons.
+const focusCallbackPrefix = "focus:"
+
+// FocusHandler handles /focus, its buttons and its timers.
+type FocusHandler struct {
+	router      *Router
+	focusCmd    *command.FocusSessionHandler
+	studentRepo student.Repository
+	client      *telegram.Client
+	scheduler   *scheduler.Scheduler
+	logger      *slog.Logger
+}
+
+// NewFocusHandler creates a new FocusHandler and registers it as the timer
+// scheduler of focusCmd.
+func NewFocusHandler(
+	router *Router,
+	focusCmd *command.FocusSessionHandler,
+	studentRepo student.Repository,
+	client *telegram.Client,
+	sch *scheduler.Scheduler,
+	logger *slog.Logger,
+) *FocusHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	h := &FocusHandler{
+		router:      router,
+		focusCmd:    focusCmd,
+		studentRepo: studentRepo,
+		client:      client,
+		scheduler:   sch,
+		logger:      logger,
+	}
+	focusCmd.WithTimers(h)
+	return h
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// TIMERS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ScheduleFocus schedules the halfway nudge and the end of the session.
+func (h *FocusHandler) ScheduleFocus(session *social.FocusSession) error {
+	id := session.ID
+
+	if session.HalfwayNudge && !session.HalfwaySent {
+		job := scheduler.NewFuncJob(fmt.Sprintf("focus_halfway:%d", id), "Focus session halfway nudge",
+			func(ctx context.Context) error { return h.halfway(ctx, id) })
+		if err := h.scheduler.ScheduleOnce(job, session.HalfwayAt()); err != nil && !errors.Is(err, scheduler.ErrJobAlreadyExists) {
+			return err
+		}
+	}
+
+	job := scheduler.NewFuncJob(fmt.Sprintf("focus_end:%d", id), "Focus session end",
+		func(ctx context.Context) error { return h.end(ctx, id) })
+	if err := h.scheduler.ScheduleOnce(job, session.EndsAt); err != nil && !errors.Is(err, scheduler.ErrJobAlreadyExists) {
+		return err
+	}
+	return nil
+}
+
+// Recover reschedules the timers of running sessions after a restart.
+func (h *FocusHandler) Recover(ctx context.Context) {
+	restored, err := h.focusCmd.Recover(ctx)
+	if err != nil {
+		h.logger.Error("failed to recover focus sessions", "restored", restored, "error", err)
+		return
+	}
+	if restored > 0 {
+		h.logger.Info("focus sessions recovered", "count", restored)
+	}
+}
+
+// halfway sends the halfway nudge to the participants.
+func (h *FocusHandler) halfway(ctx context.Context, sessionID int64) error {
+	result, err := h.focusCmd.Halfway(ctx, sessionID)
+	if err != nil || result == nil {
+		return err
+	}
+
+	left := int(time.Until(result.Session.EndsAt).Round(time.Minute).Minutes())
+	text := fmt.Sprintf("⏳ <b>Половина пройдена!</b>\n\nОсталось около %d мин. Держи темп 💪", left)
+	for _, s := range result.Participants {
+		h.send(ctx, int64(s.TelegramID), text, nil)
+	}
+	return nil
+}
+
+// end ends the session by its timer and asks for the outcome.
+func (h *FocusHandler) end(ctx context.Context, sessionID int64) error {
+	result, err := h.focusCmd.End(ctx, sessionID)
+	if err != nil || result == nil {
+		return err
+	}
+	h.sendSummary(ctx, result)
+	return nil
+}
+
+// sendSummary sends the end-of-session message with outcome buttons.
+func (h *FocusHandler) sendSummary(ctx context.Context, result *command.FocusEndResult) {
+	session := result.Session
+	for _, s := range result.Participants {
+		minutes := session.Minutes(social.StudentID(s.ID))
+
+		var sb strings.Builder
+		sb.WriteString("🏁 <b>Фокус-сессия закончилась</b>\n\n")
+		sb.WriteString(fmt.Sprintf("⏱ %d мин засчитано в дневной прогресс.\n", minutes))
+		if partner := focusPartner(session, s.ID, result.Participants); partner != nil {
+			sb.WriteString(fmt.Sprintf("👥 Вместе с %s\n", html.EscapeString(partner.DisplayName)))
+		}
+		sb.WriteString("\nЧто получилось?")
+
+		h.send(ctx, int64(s.TelegramID), sb.String(), focusOutcomeKeyboard(session.ID))
+	}
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// COMMAND
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Handle processes "/focus [тихо|стоп]".
+func (h *FocusHandler) Handle(ctx context.Context, cmdCtx CommandContext) error {
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(cmdCtx.TelegramID))
+	if err != nil {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, "❌ Ты не зарегистрирован. Используй /start")
+		return err
+	}
+
+	arg := strings.ToLower(strings.TrimSpace(cmdCtx.Args))
+	switch arg {
+	case "stop", "стоп":
+		return h.stop(ctx, stud, cmdCtx.ChatID)
+	case "", "quiet", "тихо":
+	default:
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID,
+			"🎯 <b>/focus</b> - 50 минут сосредоточенной работы\n\n"+
+				"/focus тихо - без напоминания на середине\n"+
+				"/focus стоп - закончить раньше")
+		return err
+	}
+
+	if active, err := h.focusCmd.Active(ctx, stud.ID); err == nil {
+		text := fmt.Sprintf("🎯 Сессия уже идёт, до %s.", active.EndsAt.Local().Format("15:04"))
+		_, err = cmdCtx.Client.SendWithKeyboard(ctx, cmdCtx.ChatID, text, focusStopKeyboard(active.ID).InlineKeyboard)
+		return err
+	}
+
+	result, err := h.focusCmd.Start(ctx, stud.ID, arg == "")
+	if errors.Is(err, social.ErrFocusActive) {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, "🎯 Сессия уже идёт.")
+		return err
+	}
+	if err != nil {
+		h.logger.Error("failed to start focus session", "student_id", stud.ID, "error", err)
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, "❌ Не удалось начать сессию. Попробуй позже.")
+		return err
+	}
+
+	h.logger.Info("focus session started", "session_id", result.Session.ID, "student_id", stud.ID)
+
+	var sb strings.Builder
+	sb.WriteString("🎯 <b>Фокус-сессия началась</b>\n\n")
+	sb.WriteString(fmt.Sprintf("50 минут без отвлечений, до %s.\n", result.Session.EndsAt.Local().Format("15:04")))
+	if result.Session.HalfwayNudge {
+		sb.WriteString("Напомню на середине.\n")
+	}
+	if len(result.Buddies) > 0 {
+		sb.WriteString("\nПозвать напарника?")
+	}
+
+	_, err = cmdCtx.Client.SendWithKeyboard(ctx, cmdCtx.ChatID, sb.String(), focusStartKeyboard(result.Session.ID, result.Buddies).InlineKeyboard)
+	return err
+}
+
+// stop ends the student's session early.
+func (h *FocusHandler) stop(ctx context.Context, stud *student.Student, chatID int64) error {
+	active, err := h.focusCmd.Active(ctx, stud.ID)
+	if err != nil {
+		_, err = h.client.SendHTML(ctx, chatID, "Сейчас нет активной сессии. Начать: /focus")
+		return err
+	}
+	return h.stopSession(ctx, active.ID, stud, chatID)
+}
+
+// stopSession ends the session and sends the summary to the participants.
+func (h *FocusHandler) stopSession(ctx context.Context, sessionID int64, stud *student.Student, chatID int64) error {
+	result, err := h.focusCmd.Stop(ctx, sessionID, stud.ID)
+	switch {
+	case errors.Is(err, social.ErrFocusEnded), errors.Is(err, social.ErrFocusNotParticipant):
+		_, err = h.client.SendHTML(ctx, chatID, "Сессия уже закончилась.")
+		return err
+	case err != nil:
+		return err
+	}
+
+	h.logger.Info("focus session stopped", "session_id", sessionID, "student_id", stud.ID)
+	h.sendSummary(ctx, result)
+	return nil
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CALLBACKS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// HandleCallback processes "focus:<action>:<session>[:<arg>]".
+func (h *FocusHandler) HandleCallback(ctx context.Context, cbCtx CallbackContext) error {
+	parts := strings.SplitN(strings.TrimPrefix(cbCtx.Data, focusCallbackPrefix), ":", 3)
+	if len(parts) < 2 {
+		return nil
+	}
+	sessionID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || sessionID <= 0 {
+		return nil
+	}
+	arg := ""
+	if len(parts) == 3 {
+		arg = parts[2]
+	}
+
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(cbCtx.TelegramID))
+	if err != nil {
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Ты не зарегистрирован. Используй /start", true)
+	}
+
+	switch parts[0] {
+	case "i":
+		return h.invite(ctx, cbCtx, sessionID, stud, arg)
+	case "a":
+		return h.respond(ctx, cbCtx, sessionID, stud, true)
+	case "d":
+		return h.respond(ctx, cbCtx, sessionID, stud, false)
+	case "s":
+		_ = cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "", false)
+		_ = removeKeyboard(ctx, cbCtx)
+		return h.stopSession(ctx, sessionID, stud, cbCtx.ChatID)
+	case "o":
+		return h.outcome(ctx, cbCtx, sessionID, stud, social.FocusOutcome(arg))
+	default:
+		return nil
+	}
+}
+
+// invite sends the invite to the buddy.
+func (h *FocusHandler) invite(ctx context.Context, cbCtx CallbackContext, sessionID int64, owner *student.Student, buddyID string) error {
+	result, err := h.focusCmd.Invite(ctx, sessionID, owner.ID, buddyID)
+	switch {
+	case errors.Is(err, command.ErrFocusBuddyBusy):
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "Напарник сейчас в другой сессии.", true)
+	case errors.Is(err, social.ErrFocusBuddyTaken):
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "Приглашение уже отправлено.", false)
+	case errors.Is(err, social.ErrFocusEnded):
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "Сессия уже закончилась.", false)
+	case err != nil:
+		h.logger.Warn("focus invite failed", "session_id", sessionID, "error", err)
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Не удалось отправить приглашение.", true)
+	}
+
+	text := fmt.Sprintf(
+		"🎯 <b>%s зовёт тебя в фокус-сессию</b>\n\n"+
+			"Работаете каждый над своим, до %s. Присоединишься?",
+		html.EscapeString(result.Owner.DisplayName), result.Session.EndsAt.Local().Format("15:04"),
+	)
+	if _, err := h.client.SendWithKeyboard(ctx, int64(result.Buddy.TelegramID), text, focusInviteKeyboard(sessionID).InlineKeyboard); err != nil {
+		h.logger.Warn("failed to send focus invite", "session_id", sessionID, "error", err)
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Не удалось отправить приглашение.", true)
+	}
+
+	_ = cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "Приглашение отправлено", false)
+	_, err = cbCtx.Client.EditMessageKeyboard(ctx, cbCtx.ChatID, int64(cbCtx.MessageID), focusStopKeyboard(sessionID))
+	return err
+}
+
+// respond handles the buddy's answer to the invite.
+func (h *FocusHandler) respond(ctx context.Context, cbCtx CallbackContext, sessionID int64, buddy *student.Student, accept bool) error {
+	result, err := h.focusCmd.Respond(ctx, sessionID, buddy.ID, accept)
+	switch {
+	case errors.Is(err, social.ErrFocusActive):
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "У тебя уже идёт своя сессия.", true)
+	case errors.Is(err, social.ErrFocusEnded), errors.Is(err, social.ErrFocusNotInvited), errors.Is(err, social.ErrFocusNotFound):
+		_ = cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "Приглашение больше не действует.", false)
+		return removeKeyboard(ctx, cbCtx)
+	case err != nil:
+		return err
+	}
+
+	_ = cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "", false)
+	owner := int64(result.Owner.TelegramID)
+	ownerName := html.EscapeString(result.Owner.DisplayName)
+	buddyName := html.EscapeString(result.Buddy.DisplayName)
+
+	if !accept {
+		h.send(ctx, owner, fmt.Sprintf("🙅 %s сейчас не может. Сессия продолжается, можно позвать другого.", buddyName),
+			focusStartKeyboard(sessionID, h.focusCmd.Buddies(ctx, result.Owner.ID)))
+		_, err = cbCtx.Client.EditMessageText(ctx, cbCtx.ChatID, int64(cbCtx.MessageID), "Ок, в другой раз 👋", "HTML", &telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{}})
+		return err
+	}
+
+	h.logger.Info("focus buddy joined", "session_id", sessionID, "buddy_id", buddy.ID)
+	ends := result.Session.EndsAt.Local().Format("15:04")
+	h.send(ctx, owner, fmt.Sprintf("👥 %s присоединился к сессии!", buddyName), nil)
+	_, err = cbCtx.Client.EditMessageText(ctx, cbCtx.ChatID, int64(cbCtx.MessageID),
+		fmt.Sprintf("🎯 <b>Фокус-сессия с %s началась</b>\n\nРаботаем до %s.", ownerName, ends),
+		"HTML", focusStopKeyboard(sessionID))
+	return err
+}
+
+// outcome records the participant's outcome.
+func (h *FocusHandler) outcome(ctx context.Context, cbCtx CallbackContext, sessionID int64, stud *student.Student, outcome social.FocusOutcome) error {
+	if !outcome.IsValid() {
+		return nil
+	}
+	if _, err := h.focusCmd.RecordOutcome(ctx, sessionID, stud.ID, outcome); err != nil {
+		h.logger.Warn("failed to record focus outcome", "session_id", sessionID, "error", err)
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Не удалось сохранить.", false)
+	}
+
+	_ = cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "Записал!", false)
+	if err := removeKeyboard(ctx, cbCtx); err != nil {
+		return err
+	}
+
+	switch outcome {
+	case social.FocusOutcomeSolved:
+		_, err := cbCtx.Client.SendHTML(ctx, cbCtx.ChatID, "🎉 Отлично! Задача решена.")
+		return err
+	case social.FocusOutcomeProgress:
+		_, err := cbCtx.Client.SendHTML(ctx, cbCtx.ChatID, "👍 Прогресс есть. Следующая сессия: /focus")
+		return err
+	default:
+		// Застрял - сразу к поиску помощи
+		return h.router.HandleCommand(ctx, "help", CommandContext{
+			TelegramID: cbCtx.TelegramID,
+			ChatID:     cbCtx.ChatID,
+			Client:     cbCtx.Client,
+		})
+	}
+}
+
+// send sends a message and logs failures.
+func (h *FocusHandler) send(ctx context.Context, chatID int64, text string, keyboard *telegram.InlineKeyboardMarkup) {
+	var err error
+	if keyboard != nil && len(keyboard.InlineKeyboard) > 0 {
+		_, err = h.client.SendWithKeyboard(ctx, chatID, text, keyboard.InlineKeyboard)
+	} else {
+		_, err = h.client.SendHTML(ctx, chatID, text)
+	}
+	if err != nil {
+		h.logger.Warn("failed to send focus message", "chat_id", student.TelegramID(chatID), "error", err)
+	}
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// KEYBOARDS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// focusStartKeyboard offers the connections as buddies and a stop button.
+func focusStartKeyboard(sessionID int64, buddies []*student.Student) *telegram.InlineKeyboardMarkup {
+	rows := make([][]telegram.InlineKeyboardButton, 0, len(buddies)+1)
+	for _, b := range buddies {
+		rows = append(rows, []telegram.InlineKeyboardButton{{
+			Text:         "👥 Позвать " + b.DisplayName,
+			CallbackData: fmt.Sprintf("%si:%d:%s", focusCallbackPrefix, sessionID, b.ID),
+		}})
+	}
+	rows = append(rows, focusStopKeyboard(sessionID).InlineKeyboard...)
+	return &telegram.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// focusStopKeyboard returns the button that ends the session early.
+func focusStopKeyboard(sessionID int64) *telegram.InlineKeyboardMarkup {
+	return &telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{{
+		{Text: "⏹ Закончить", CallbackData: fmt.Sprintf("%ss:%d", focusCallbackPrefix, sessionID)},
+	}}}
+}
+
+// focusInviteKeyboard returns the accept/decline buttons of an invite.
+func focusInviteKeyboard(sessionID int64) *telegram.InlineKeyboardMarkup {
+	return &telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{{
+		{Text: "✅ Присоединиться", CallbackData: fmt.Sprintf("%sa:%d", focusCallbackPrefix, sessionID)},
+		{Text: "🙅 Не сейчас", CallbackData: fmt.Sprintf("%sd:%d", focusCallbackPrefix, sessionID)},
+	}}}
+}
+
+// focusOutcomeKeyboard returns the end-of-session outcome buttons.
+func focusOutcomeKeyboard(sessionID int64) *telegram.InlineKeyboardMarkup {
+	button := func(text string, outcome social.FocusOutcome) telegram.InlineKeyboardButton {
+		return telegram.InlineKeyboardButton{Text: text, CallbackData: fmt.Sprintf("%so:%d:%s", focusCallbackPrefix, sessionID, outcome)}
+	}
+	return &telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{
+		{button("✅ решил задачу", social.FocusOutcomeSolved), button("📈 продвинулся", social.FocusOutcomeProgress)},
+		{button("🆘 застрял", social.FocusOutcomeStuck)},
+	}}
+}
+
+// focusPartner returns the other participant of a shared session.
+func focusPartner(session *social.FocusSession, studentID string, participants []*student.Student) *student.Student {
+	if session.BuddyStatus != social.FocusInviteAccepted {
+		return nil
+	}
+	for _, p := range participants {
+		if p.ID != studentID {
+			return p
+		}
+	}
+	return nil
+}
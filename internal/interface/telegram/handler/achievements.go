@@ -0,0 +1,137 @@
+// Package handler contains Telegram command handlers.
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// ACHIEVEMENTS HANDLER
+// Handles /achievements command - unlocked achievements and rank ladders.
+// The cohort ladder sits next to the global one so students from newer
+// cohorts always have a rank goal within reach.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// AchievementsHandler handles the /achievements command.
+type AchievementsHandler struct {
+	achievementsQuery *query.GetAchievementsHandler
+	keyboards         *presenter.KeyboardBuilder
+}
+
+// NewAchievementsHandler creates a new AchievementsHandler with dependencies.
+func NewAchievementsHandler(
+	achievementsQuery *query.GetAchievementsHandler,
+	keyboards *presenter.KeyboardBuilder,
+) *AchievementsHandler {
+	return &AchievementsHandler{
+		achievementsQuery: achievementsQuery,
+		keyboards:         keyboards,
+	}
+}
+
+// AchievementsRequest contains the parsed /achievements command data.
+type AchievementsRequest struct {
+	// TelegramID is the user's Telegram ID.
+	TelegramID int64
+
+	// ChatID is the chat ID for sending responses.
+	ChatID int64
+
+	// MessageID is the original message ID (for editing).
+	MessageID int
+
+	// IsRefresh indicates if this is a refresh request (from callback).
+	IsRefresh bool
+}
+
+// AchievementsResponse contains the response to send back.
+type AchievementsResponse struct {
+	// Text is the message text (HTML formatted).
+	Text string
+
+	// Keyboard is the inline keyboard to attach.
+	Keyboard *presenter.InlineKeyboard
+
+	// ParseMode is the parse mode (HTML).
+	ParseMode string
+
+	// IsError indicates if this is an error response.
+	IsError bool
+}
+
+// Handle processes the /achievements command.
+func (h *AchievementsHandler) Handle(ctx context.Context, req AchievementsRequest) (*AchievementsResponse, error) {
+	result, err := h.achievementsQuery.Handle(ctx, query.GetAchievementsQuery{
+		TelegramID: req.TelegramID,
+	})
+	if err != nil {
+		return &AchievementsResponse{
+			Text:      "❌ Не удалось загрузить достижения. Попробуйте позже.",
+			ParseMode: "HTML",
+			IsError:   true,
+		}, nil
+	}
+
+	return &AchievementsResponse{
+		Text:      formatAchievements(result),
+		Keyboard:  h.keyboards.AchievementsKeyboard(),
+		ParseMode: "HTML",
+	}, nil
+}
+
+// formatAchievements formats both rank ladders and the unlocked list.
+func formatAchievements(result *query.GetAchievementsResult) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("🏅 <b>Достижения</b> • %d из %d\n", len(result.Unlocked), result.TotalCount))
+
+	sb.WriteString("\n🌍 <b>Общий рейтинг</b>")
+	writeRankLadder(&sb, result.GlobalLadder)
+
+	sb.WriteString("\n🎓 <b>Рейтинг потока</b>")
+	if result.CohortLadder.Cohort != "" {
+		sb.WriteString(fmt.Sprintf(" <i>%s</i>", escapeHTML(result.CohortLadder.Cohort)))
+	}
+	writeRankLadder(&sb, result.CohortLadder)
+
+	sb.WriteString("\n<b>Полученные</b>\n")
+	if len(result.Unlocked) == 0 {
+		sb.WriteString("<i>Пока ничего — первая задача уже даст достижение!</i>")
+		return sb.String()
+	}
+	for _, a := range result.Unlocked {
+		line := fmt.Sprintf("%s %s", a.Emoji, escapeHTML(a.Name))
+		if a.UnlockedAt != nil {
+			line += fmt.Sprintf(" <i>(%s)</i>", a.UnlockedAt.Local().Format("02.01.2006"))
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// writeRankLadder writes the current position and ladder steps.
+func writeRankLadder(sb *strings.Builder, ladder query.RankLadderDTO) {
+	if ladder.Rank > 0 {
+		sb.WriteString(fmt.Sprintf(" • #%d из %d\n", ladder.Rank, ladder.Total))
+	} else {
+		sb.WriteString(" • <i>нет данных</i>\n")
+	}
+
+	for _, step := range ladder.Steps {
+		mark := "⬜"
+		if step.UnlockedAt != nil {
+			mark = "✅"
+		}
+		line := fmt.Sprintf("%s %s %s — топ-%d", mark, step.Emoji, escapeHTML(step.Name), step.MaxRank)
+		if step.PositionsToGo > 0 {
+			line += fmt.Sprintf(" <i>(ещё %d мест)</i>", step.PositionsToGo)
+		}
+		sb.WriteString(line + "\n")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/application/cqrs"
 	"github.com/alem-hub/alem-community-hub/internal/domain/social"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
 	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
@@ -23,14 +24,14 @@ import (
 
 // ConnectHandler handles the connect button callback.
 type ConnectHandler struct {
-	connectCmd  *command.ConnectStudentsHandler
+	connectCmd  cqrs.Handler[command.ConnectStudentsCommand, *command.ConnectStudentsResult]
 	studentRepo student.Repository
 	keyboards   *presenter.KeyboardBuilder
 }
 
 // NewConnectHandler creates a new ConnectHandler with dependencies.
 func NewConnectHandler(
-	connectCmd *command.ConnectStudentsHandler,
+	connectCmd cqrs.Handler[command.ConnectStudentsCommand, *command.ConnectStudentsResult],
 	studentRepo student.Repository,
 	keyboards *presenter.KeyboardBuilder,
 ) *ConnectHandler {
@@ -163,7 +164,7 @@ func (h *ConnectHandler) buildResponse(target *student.Student, connResult *comm
 	sb.WriteString(fmt.Sprintf("📨 <b>Связь с %s</b>\n\n", escapeHTML(target.DisplayName)))
 
 	// Profile info
-	sb.WriteString(fmt.Sprintf("👤 <b>%s</b>\n", escapeHTML(target.DisplayName)))
+	sb.WriteString(fmt.Sprintf("👤 <b>%s</b>\n", presenter.Mention(int64(target.TelegramID), target.DisplayName)))
 	sb.WriteString(fmt.Sprintf("🎯 Уровень: %d\n", target.Level()))
 
 	// Helper rating
@@ -197,13 +198,18 @@ func (h *ConnectHandler) buildResponse(target *student.Student, connResult *comm
 	sb.WriteString("<i>💡 Совет: Опиши проблему кратко и конкретно.\n")
 	sb.WriteString("Это поможет получить помощь быстрее!</i>")
 
+	// Report button: the student can flag an unwanted introduction
+	keyboard := presenter.NewInlineKeyboard()
+	keyboard.AddRow(presenter.CallbackButton("⚠️ Пожаловаться", "report:c:"+target.ID))
+
 	return &ConnectResponse{
-		AnswerText:     toastMsg,
-		ShowAlert:      false,
-		UpdatedText:    sb.String(),
-		ParseMode:      "HTML",
-		TargetUsername: "", // Removed usage of AlemLogin as username
-		IsError:        false,
+		AnswerText:      toastMsg,
+		ShowAlert:       false,
+		UpdatedText:     sb.String(),
+		UpdatedKeyboard: keyboard,
+		ParseMode:       "HTML",
+		TargetUsername:  "", // Removed usage of AlemLogin as username
+		IsError:         false,
 	}
 }
 
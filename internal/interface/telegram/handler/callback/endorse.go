@@ -3,8 +3,10 @@ package callback
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/alem-hub/alem-community-hub/internal/application/command"
 	"github.com/alem-hub/alem-community-hub/internal/domain/social"
@@ -22,24 +24,32 @@ import (
 
 // EndorseHandler handles the endorse/thanks button callback.
 type EndorseHandler struct {
-	endorseCmd  *command.GiveEndorsementHandler
-	studentRepo student.Repository
-	keyboards   *presenter.KeyboardBuilder
+	endorseCmd   *command.GiveEndorsementHandler
+	helpRequests social.HelpRequestRepository
+	studentRepo  student.Repository
+	keyboards    *presenter.KeyboardBuilder
+	now          func() time.Time
 }
 
 // NewEndorseHandler creates a new EndorseHandler with dependencies.
 func NewEndorseHandler(
 	endorseCmd *command.GiveEndorsementHandler,
+	helpRequests social.HelpRequestRepository,
 	studentRepo student.Repository,
 	keyboards *presenter.KeyboardBuilder,
 ) *EndorseHandler {
 	return &EndorseHandler{
-		endorseCmd:  endorseCmd,
-		studentRepo: studentRepo,
-		keyboards:   keyboards,
+		endorseCmd:   endorseCmd,
+		helpRequests: helpRequests,
+		studentRepo:  studentRepo,
+		keyboards:    keyboards,
+		now:          time.Now,
 	}
 }
 
+// expiredPromptText is shown when an endorsement prompt is no longer valid.
+const expiredPromptText = "⌛ Срок вышел, но можешь поблагодарить лично"
+
 // EndorseRequest contains the parsed callback data.
 type EndorseRequest struct {
 	// TelegramID is the user's Telegram ID who clicked the button.
@@ -51,6 +61,10 @@ type EndorseRequest struct {
 	// TaskID is the task for which help was given (optional).
 	TaskID string
 
+	// HelpRequestID is the resolved help request being endorsed (optional).
+	// When set, the helper and task are taken from the request.
+	HelpRequestID string
+
 	// Rating is the rating to give (1-5).
 	Rating float64
 
@@ -86,6 +100,9 @@ type EndorseResponse struct {
 
 	// NeedsRating indicates that we need the user to select a rating.
 	NeedsRating bool
+
+	// RemoveKeyboard indicates that the message buttons should be removed.
+	RemoveKeyboard bool
 }
 
 // Handle processes the endorse callback.
@@ -100,6 +117,16 @@ func (h *EndorseHandler) Handle(ctx context.Context, req EndorseRequest) (*Endor
 		}, nil
 	}
 
+	// A request-bound prompt is only valid while the request is endorsable
+	if req.HelpRequestID != "" {
+		helpReq, resp := h.endorsableRequest(ctx, req.HelpRequestID, giver.ID)
+		if resp != nil {
+			return resp, nil
+		}
+		req.HelperStudentID = string(*helpReq.HelperID)
+		req.TaskID = string(helpReq.TaskID)
+	}
+
 	// Get helper student
 	helper, err := h.studentRepo.GetByID(ctx, req.HelperStudentID)
 	if err != nil {
@@ -135,17 +162,21 @@ func (h *EndorseHandler) Handle(ctx context.Context, req EndorseRequest) (*Endor
 
 	// Create endorsement command
 	cmd := command.GiveEndorsementCommand{
-		GiverID:    giver.ID,
-		ReceiverID: helper.ID,
-		TaskID:     req.TaskID,
-		Type:       social.EndorsementTypeClear,
-		Rating:     req.Rating,
-		IsPublic:   true,
+		GiverID:       giver.ID,
+		ReceiverID:    helper.ID,
+		HelpRequestID: req.HelpRequestID,
+		TaskID:        req.TaskID,
+		Type:          social.EndorsementTypeClear,
+		Rating:        req.Rating,
+		IsPublic:      true,
 	}
 
 	// Execute endorsement
 	result, err := h.endorseCmd.Handle(ctx, cmd)
 	if err != nil {
+		if resp := endorsementErrorResponse(err); resp != nil {
+			return resp, nil
+		}
 		return &EndorseResponse{
 			AnswerText: "❌ Не удалось сохранить благодарность",
 			ShowAlert:  true,
@@ -157,6 +188,61 @@ func (h *EndorseHandler) Handle(ctx context.Context, req EndorseRequest) (*Endor
 	return h.buildSuccessResponse(helper, result, req.Rating, req.TaskID)
 }
 
+// endorsableRequest loads a help request and checks that the giver may still
+// endorse it. Returns a response to send instead when it may not.
+func (h *EndorseHandler) endorsableRequest(ctx context.Context, helpRequestID, giverID string) (*social.HelpRequest, *EndorseResponse) {
+	helpReq, err := h.helpRequests.GetByID(ctx, helpRequestID)
+	if err != nil {
+		return nil, &EndorseResponse{
+			AnswerText:     expiredPromptText,
+			ShowAlert:      true,
+			IsError:        true,
+			RemoveKeyboard: true,
+		}
+	}
+
+	if !helpReq.IsParticipant(social.StudentID(giverID)) {
+		return nil, &EndorseResponse{
+			AnswerText: "🤔 Этот запрос помощи не твой",
+			ShowAlert:  false,
+			IsError:    true,
+		}
+	}
+
+	if !helpReq.IsEndorsableAt(h.now()) {
+		return nil, &EndorseResponse{
+			AnswerText:     expiredPromptText,
+			ShowAlert:      true,
+			IsError:        true,
+			RemoveKeyboard: true,
+		}
+	}
+
+	return helpReq, nil
+}
+
+// endorsementErrorResponse maps command errors that the user can act on.
+func endorsementErrorResponse(err error) *EndorseResponse {
+	switch {
+	case errors.Is(err, social.ErrEndorsementPromptExpired):
+		return &EndorseResponse{
+			AnswerText:     expiredPromptText,
+			ShowAlert:      true,
+			IsError:        true,
+			RemoveKeyboard: true,
+		}
+	case errors.Is(err, social.ErrEndorsementAlreadyExists):
+		return &EndorseResponse{
+			AnswerText:     "✅ Ты уже поблагодарил за эту помощь",
+			ShowAlert:      false,
+			IsError:        true,
+			RemoveKeyboard: true,
+		}
+	default:
+		return nil
+	}
+}
+
 // showRatingSelection shows the rating selection keyboard.
 func (h *EndorseHandler) showRatingSelection(helper *student.Student, taskID string) (*EndorseResponse, error) {
 	var sb strings.Builder
@@ -253,6 +339,21 @@ func ParseEndorseCallbackData(data string) (studentID string, rating float64, ta
 	return
 }
 
+// ParseRequestEndorseCallbackData parses request-bound callback data.
+// Expected format: "endorse:req:helpRequestID:rating"
+func ParseRequestEndorseCallbackData(data string) (helpRequestID string, rating float64, ok bool) {
+	parts := strings.Split(data, ":")
+	if len(parts) < 3 || parts[0] != "endorse" || parts[1] != "req" || parts[2] == "" {
+		return "", 0, false
+	}
+
+	helpRequestID = parts[2]
+	if len(parts) >= 4 {
+		fmt.Sscanf(parts[3], "%f", &rating)
+	}
+	return helpRequestID, rating, true
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // HELPER FUNCTIONS
 // ══════════════════════════════════════════════════════════════════════════════
@@ -0,0 +1,77 @@
+package callback
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+)
+
+type fakeEndorseStudents struct {
+	student.Repository
+	byTelegram map[student.TelegramID]*student.Student
+}
+
+func (f *fakeEndorseStudents) GetByTelegramID(_ context.Context, id student.TelegramID) (*student.Student, error) {
+	if s, ok := f.byTelegram[id]; ok {
+		return s, nil
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+type fakeEndorseRequests struct {
+	social.HelpRequestRepository
+	requests map[string]*social.HelpRequest
+}
+
+func (f *fakeEndorseRequests) GetByID(_ context.Context, id string) (*social.HelpRequest, error) {
+	if r, ok := f.requests[id]; ok {
+		return r, nil
+	}
+	return nil, social.ErrHelpRequestNotFound
+}
+
+func TestEndorseHandler_ExpiredPromptDisablesButtons(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	resolvedAt := now.Add(-social.EndorsementPromptTTL - time.Hour)
+	helperID := social.StudentID("arman")
+
+	req, err := social.NewHelpRequest(social.NewHelpRequestParams{
+		ID: "req-1", RequesterID: "dana", TaskID: "graph-01", TaskName: "graph-01",
+	})
+	require.NoError(t, err)
+	req.Status = social.HelpRequestStatusResolved
+	req.HelperID = &helperID
+	req.ResolvedAt = &resolvedAt
+
+	students := &fakeEndorseStudents{byTelegram: map[student.TelegramID]*student.Student{
+		42: {ID: "dana", TelegramID: 42},
+	}}
+	requests := &fakeEndorseRequests{requests: map[string]*social.HelpRequest{"req-1": req}}
+
+	// Команда не нужна: истёкший запрос отклоняется до создания благодарности
+	h := NewEndorseHandler(nil, requests, students, presenter.NewKeyboardBuilder())
+	h.now = func() time.Time { return now }
+
+	resp, err := h.Handle(context.Background(), EndorseRequest{TelegramID: 42, HelpRequestID: "req-1", Rating: 5})
+	require.NoError(t, err)
+	assert.Equal(t, expiredPromptText, resp.AnswerText)
+	assert.True(t, resp.RemoveKeyboard)
+	assert.True(t, resp.IsError)
+}
+
+func TestParseRequestEndorseCallbackData(t *testing.T) {
+	id, rating, ok := ParseRequestEndorseCallbackData("endorse:req:req-1:4")
+	require.True(t, ok)
+	assert.Equal(t, "req-1", id)
+	assert.Equal(t, 4.0, rating)
+
+	_, _, ok = ParseRequestEndorseCallbackData("endorse:arman:4:graph-01")
+	assert.False(t, ok)
+}
@@ -0,0 +1,160 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// FEEDBACK CALLBACK HANDLER
+// Handles answers to the private "how useful was the help" poll.
+// Unlike endorsements, the score is never shown to the helper: it only feeds
+// the weekly per-cohort satisfaction metric.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// FeedbackHandler handles help feedback poll answers.
+type FeedbackHandler struct {
+	helpRequests social.HelpRequestRepository
+	feedback     social.HelpFeedbackRepository
+	studentRepo  student.Repository
+	now          func() time.Time
+}
+
+// NewFeedbackHandler creates a new FeedbackHandler with dependencies.
+func NewFeedbackHandler(
+	helpRequests social.HelpRequestRepository,
+	feedback social.HelpFeedbackRepository,
+	studentRepo student.Repository,
+) *FeedbackHandler {
+	return &FeedbackHandler{
+		helpRequests: helpRequests,
+		feedback:     feedback,
+		studentRepo:  studentRepo,
+		now:          time.Now,
+	}
+}
+
+// expiredPollText is shown when a poll no longer accepts answers.
+const expiredPollText = "⌛ Опрос закрыт — ответы принимаются 7 дней"
+
+// FeedbackRequest contains the parsed callback data.
+type FeedbackRequest struct {
+	// TelegramID is the user's Telegram ID who clicked the button.
+	TelegramID int64
+
+	// HelpRequestID is the help request the poll is about.
+	HelpRequestID string
+
+	// Score is the chosen score (1-5).
+	Score int
+}
+
+// FeedbackResponse contains the response data.
+type FeedbackResponse struct {
+	// AnswerText is the text to show in the callback answer toast.
+	AnswerText string
+
+	// ShowAlert determines if the answer should be shown as an alert.
+	ShowAlert bool
+
+	// UpdatedText replaces the poll message (optional).
+	UpdatedText string
+
+	// ParseMode is the parse mode for updated text.
+	ParseMode string
+
+	// IsError indicates if this is an error response.
+	IsError bool
+
+	// RemoveKeyboard indicates that the poll buttons should be removed.
+	RemoveKeyboard bool
+}
+
+// Handle records the poll answer. Answers after social.HelpFeedbackAnswerTTL
+// and second answers for the same request are rejected.
+func (h *FeedbackHandler) Handle(ctx context.Context, req FeedbackRequest) (*FeedbackResponse, error) {
+	requester, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(req.TelegramID))
+	if err != nil {
+		return &FeedbackResponse{
+			AnswerText: "❌ Ты не зарегистрирован. Используй /start",
+			ShowAlert:  true,
+			IsError:    true,
+		}, nil
+	}
+
+	helpReq, err := h.helpRequests.GetByID(ctx, req.HelpRequestID)
+	if err != nil {
+		return expiredPollResponse(), nil
+	}
+	if string(helpReq.RequesterID) != requester.ID {
+		return &FeedbackResponse{
+			AnswerText: "🤔 Этот опрос не тебе",
+			IsError:    true,
+		}, nil
+	}
+
+	now := h.now()
+	if !helpReq.AcceptsFeedbackAt(now) {
+		return expiredPollResponse(), nil
+	}
+
+	feedback, err := social.NewHelpFeedback(helpReq.ID, req.Score, social.HelpFeedbackSourcePoll, now)
+	if err != nil {
+		return &FeedbackResponse{
+			AnswerText: "❌ Оценка должна быть от 1 до 5",
+			ShowAlert:  true,
+			IsError:    true,
+		}, nil
+	}
+
+	if err := h.feedback.Create(ctx, feedback); err != nil {
+		if errors.Is(err, social.ErrHelpFeedbackExists) {
+			return &FeedbackResponse{
+				AnswerText:     "✅ Ты уже ответил на этот опрос",
+				IsError:        true,
+				RemoveKeyboard: true,
+			}, nil
+		}
+		return nil, fmt.Errorf("save help feedback: %w", err)
+	}
+
+	return &FeedbackResponse{
+		AnswerText: "🙏 Спасибо за ответ!",
+		UpdatedText: fmt.Sprintf("💬 Твоя оценка: <b>%d из 5</b>\n\n"+
+			"<i>Помощник её не увидит — мы смотрим только на общую картину, чтобы взаимопомощь становилась лучше.</i>",
+			feedback.Score),
+		ParseMode: "HTML",
+	}, nil
+}
+
+// expiredPollResponse tells the user the poll is closed and drops its buttons.
+func expiredPollResponse() *FeedbackResponse {
+	return &FeedbackResponse{
+		AnswerText:     expiredPollText,
+		ShowAlert:      true,
+		IsError:        true,
+		RemoveKeyboard: true,
+	}
+}
+
+// ParseFeedbackCallbackData parses poll callback data.
+// Expected format: "feedback:helpRequestID:score"
+func ParseFeedbackCallbackData(data string) (helpRequestID string, score int, ok bool) {
+	parts := strings.Split(data, ":")
+	if len(parts) != 3 || parts[0] != "feedback" || parts[1] == "" {
+		return "", 0, false
+	}
+
+	score, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return "", 0, false
+	}
+	return parts[1], score, true
+}
@@ -0,0 +1,88 @@
+package callback
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+type fakeHelpFeedback struct {
+	social.HelpFeedbackRepository
+	scores map[string]*social.HelpFeedback
+}
+
+func (f *fakeHelpFeedback) Create(_ context.Context, feedback *social.HelpFeedback) error {
+	if f.scores[feedback.HelpRequestID] != nil {
+		return social.ErrHelpFeedbackExists
+	}
+	f.scores[feedback.HelpRequestID] = feedback
+	return nil
+}
+
+func TestFeedbackHandler_RejectsDuplicateAndLateAnswers(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	resolvedAt := now.Add(-2 * 24 * time.Hour)
+	helperID := social.StudentID("arman")
+
+	req, err := social.NewHelpRequest(social.NewHelpRequestParams{
+		ID: "req-1", RequesterID: "dana", TaskID: "graph-01", TaskName: "graph-01",
+	})
+	require.NoError(t, err)
+	req.Status = social.HelpRequestStatusResolved
+	req.HelperID = &helperID
+	req.ResolvedAt = &resolvedAt
+	req.MarkFeedbackPollSent(resolvedAt.Add(social.HelpFeedbackPollDelay))
+
+	students := &fakeEndorseStudents{byTelegram: map[student.TelegramID]*student.Student{
+		42: {ID: "dana", TelegramID: 42},
+		43: {ID: "arman", TelegramID: 43},
+	}}
+	requests := &fakeEndorseRequests{requests: map[string]*social.HelpRequest{"req-1": req}}
+	feedback := &fakeHelpFeedback{scores: map[string]*social.HelpFeedback{}}
+
+	h := NewFeedbackHandler(requests, feedback, students)
+	h.now = func() time.Time { return now }
+
+	// Помощник не может ответить за просившего
+	resp, err := h.Handle(context.Background(), FeedbackRequest{TelegramID: 43, HelpRequestID: "req-1", Score: 5})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+	assert.Empty(t, feedback.scores)
+
+	resp, err = h.Handle(context.Background(), FeedbackRequest{TelegramID: 42, HelpRequestID: "req-1", Score: 4})
+	require.NoError(t, err)
+	assert.False(t, resp.IsError)
+	assert.Equal(t, 4, feedback.scores["req-1"].Score)
+
+	// Второй ответ не перезаписывает первый
+	resp, err = h.Handle(context.Background(), FeedbackRequest{TelegramID: 42, HelpRequestID: "req-1", Score: 1})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+	assert.True(t, resp.RemoveKeyboard)
+	assert.Equal(t, 4, feedback.scores["req-1"].Score)
+
+	// Через 7 дней после опроса ответы не принимаются
+	delete(feedback.scores, "req-1")
+	h.now = func() time.Time { return req.FeedbackPollSentAt.Add(social.HelpFeedbackAnswerTTL) }
+	resp, err = h.Handle(context.Background(), FeedbackRequest{TelegramID: 42, HelpRequestID: "req-1", Score: 5})
+	require.NoError(t, err)
+	assert.Equal(t, expiredPollText, resp.AnswerText)
+	assert.True(t, resp.RemoveKeyboard)
+	assert.Empty(t, feedback.scores)
+}
+
+func TestParseFeedbackCallbackData(t *testing.T) {
+	id, score, ok := ParseFeedbackCallbackData("feedback:req-1:3")
+	require.True(t, ok)
+	assert.Equal(t, "req-1", id)
+	assert.Equal(t, 3, score)
+
+	_, _, ok = ParseFeedbackCallbackData("feedback:req-1")
+	assert.False(t, ok)
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// EVENT HANDLER
+// Handles /event command - leaderboards of the running community events.
+// Events span all cohorts, so the top is global rather than per-cohort.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// EventHandler handles the /event command.
+type EventHandler struct {
+	eventQuery  *query.GetEventLeaderboardHandler
+	studentRepo student.Repository
+}
+
+// NewEventHandler creates a new EventHandler with dependencies.
+// A nil query means events are disabled (no Redis).
+func NewEventHandler(eventQuery *query.GetEventLeaderboardHandler, studentRepo student.Repository) *EventHandler {
+	return &EventHandler{
+		eventQuery:  eventQuery,
+		studentRepo: studentRepo,
+	}
+}
+
+// EventRequest contains the parsed /event command data.
+type EventRequest struct {
+	// TelegramID is the user's Telegram ID.
+	TelegramID int64
+
+	// ChatID is the chat ID for sending responses.
+	ChatID int64
+}
+
+// EventResponse contains the response to send back.
+type EventResponse struct {
+	// Text is the message text (HTML formatted).
+	Text string
+
+	// ParseMode is the parse mode (HTML).
+	ParseMode string
+}
+
+// Handle processes the /event command.
+func (h *EventHandler) Handle(ctx context.Context, req EventRequest) (*EventResponse, error) {
+	if h.eventQuery == nil {
+		return eventText("🏁 Челленджи сейчас недоступны."), nil
+	}
+
+	// Unregistered users can watch the top, they just have no position
+	var studentID string
+	if stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(req.TelegramID)); err == nil {
+		studentID = stud.ID
+	}
+
+	result, err := h.eventQuery.Handle(ctx, query.GetEventLeaderboardQuery{StudentID: studentID})
+	if err != nil {
+		return eventText("❌ Не удалось загрузить челлендж. Попробуйте позже."), nil
+	}
+	if len(result.Events) == 0 {
+		return eventText("🏁 <b>Сейчас челленджей нет</b>\n\nМы объявим следующий в чате сообщества."), nil
+	}
+
+	var sb strings.Builder
+	for i, board := range result.Events {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		writeEventBoard(&sb, board, studentID != "")
+	}
+
+	return eventText(sb.String()), nil
+}
+
+// writeEventBoard renders one event leaderboard.
+func writeEventBoard(sb *strings.Builder, board query.EventLeaderboard, registered bool) {
+	e := board.Event
+	unit := e.Metric.Unit()
+
+	sb.WriteString(fmt.Sprintf("🏁 <b>%s</b>\n", html.EscapeString(e.Name)))
+	sb.WriteString(fmt.Sprintf("⏳ До %s · участников: %d\n\n",
+		e.EndsAt.In(timeutil.AlmatyTZ).Format("02.01 15:04"), board.Participants))
+
+	if len(board.Top) == 0 {
+		sb.WriteString("Пока никто не набрал очков — стань первым! 💪\n")
+	}
+	for _, entry := range board.Top {
+		name := html.EscapeString(entry.DisplayName)
+		if entry.IsMe {
+			name = "<b>" + name + " (ты)</b>"
+		}
+		sb.WriteString(fmt.Sprintf("%s %s — %d %s\n", eventRankLabel(entry.Rank), name, entry.Score, unit))
+	}
+
+	switch {
+	case !registered:
+		sb.WriteString("\nИспользуй /start, чтобы участвовать.")
+	case board.Me == nil:
+		sb.WriteString("\nТы ещё не набрал очков в этом челлендже.")
+	default:
+		sb.WriteString(fmt.Sprintf("\n📍 Твоё место: <b>#%d</b> — %d %s", board.Me.Rank, board.Me.Score, unit))
+	}
+}
+
+// eventRankLabel returns a medal for the podium and the number otherwise.
+func eventRankLabel(rank int) string {
+	switch rank {
+	case 1:
+		return "🥇"
+	case 2:
+		return "🥈"
+	case 3:
+		return "🥉"
+	default:
+		return fmt.Sprintf("%d.", rank)
+	}
+}
+
+// eventText builds a plain response.
+func eventText(text string) *EventResponse {
+	return &EventResponse{Text: text, ParseMode: "HTML"}
+}
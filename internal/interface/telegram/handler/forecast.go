@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/pkg/forecast"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// FORECAST HANDLER
+// Handles /forecast command - "at this pace you'll reach the target by <date>".
+// "/forecast" forecasts the next level, "/forecast level 12", "/forecast rank 10"
+// and "/forecast xp 20000" (or just "/forecast 20000") pick another target.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ForecastHandler handles the /forecast command.
+type ForecastHandler struct {
+	forecastQuery *query.GetForecastHandler
+	studentRepo   student.Repository
+}
+
+// NewForecastHandler creates a new ForecastHandler with dependencies.
+func NewForecastHandler(
+	forecastQuery *query.GetForecastHandler,
+	studentRepo student.Repository,
+) *ForecastHandler {
+	return &ForecastHandler{
+		forecastQuery: forecastQuery,
+		studentRepo:   studentRepo,
+	}
+}
+
+// ForecastRequest contains the parsed /forecast command data.
+type ForecastRequest struct {
+	// TelegramID is the user's Telegram ID.
+	TelegramID int64
+
+	// ChatID is the chat ID for sending responses.
+	ChatID int64
+
+	// Args is the raw command argument ("rank 10" or empty).
+	Args string
+}
+
+// ForecastResponse contains the response to send back.
+type ForecastResponse struct {
+	// Text is the message text (HTML formatted).
+	Text string
+
+	// ParseMode is the parse mode (HTML).
+	ParseMode string
+
+	// IsError indicates if this is an error response.
+	IsError bool
+}
+
+const forecastUsage = "❓ Примеры:\n" +
+	"• <code>/forecast</code> — следующий уровень\n" +
+	"• <code>/forecast level 12</code> — уровень\n" +
+	"• <code>/forecast rank 10</code> — позиция в рейтинге\n" +
+	"• <code>/forecast xp 20000</code> — количество XP"
+
+// Handle processes the /forecast command.
+func (h *ForecastHandler) Handle(ctx context.Context, req ForecastRequest) (*ForecastResponse, error) {
+	if h.forecastQuery == nil {
+		return forecastError("🔮 Прогноз сейчас недоступен."), nil
+	}
+
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(req.TelegramID))
+	if err != nil {
+		return forecastError("❌ <b>Ты ещё не зарегистрирован</b>\n\nИспользуй /start чтобы присоединиться к сообществу."), nil
+	}
+
+	q, ok := parseForecastArgs(req.Args)
+	if !ok {
+		return forecastError(forecastUsage), nil
+	}
+	q.StudentID = stud.ID
+
+	result, err := h.forecastQuery.Handle(ctx, q)
+	switch {
+	case shared.IsValidation(err):
+		return forecastError(forecastUsage), nil
+	case shared.IsNotFound(err) && q.Target == query.ForecastTargetRank:
+		return forecastError(fmt.Sprintf("⚠️ В рейтинге нет позиции #%d.", q.Value)), nil
+	case err != nil:
+		return forecastError("❌ Не удалось построить прогноз. Попробуйте позже."), nil
+	}
+
+	return &ForecastResponse{
+		Text:      formatForecast(result),
+		ParseMode: "HTML",
+	}, nil
+}
+
+// parseForecastArgs parses "[rank|level|xp] N"; a bare number is an XP target.
+func parseForecastArgs(args string) (query.GetForecastQuery, bool) {
+	fields := strings.Fields(strings.ToLower(args))
+	if len(fields) == 0 {
+		return query.GetForecastQuery{}, true
+	}
+
+	target := query.ForecastTargetXP
+	if len(fields) == 2 {
+		switch fields[0] {
+		case "rank", "ранг", "место":
+			target = query.ForecastTargetRank
+		case "level", "уровень", "lvl":
+			target = query.ForecastTargetLevel
+		case "xp":
+			target = query.ForecastTargetXP
+		default:
+			return query.GetForecastQuery{}, false
+		}
+		fields = fields[1:]
+	}
+	if len(fields) != 1 {
+		return query.GetForecastQuery{}, false
+	}
+
+	value, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSuffix(fields[0], "xp"), "#"))
+	if err != nil || value <= 0 {
+		return query.GetForecastQuery{}, false
+	}
+	return query.GetForecastQuery{Target: target, Value: value}, true
+}
+
+// formatForecast formats the forecast message.
+func formatForecast(r *query.GetForecastResult) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("🔮 <b>Прогноз: %s</b>\n\n", forecastTargetTitle(r)))
+	sb.WriteString(fmt.Sprintf("📈 %d / %d XP\n", r.CurrentXP, r.TargetXP))
+	if r.RankHolder != nil {
+		sb.WriteString(fmt.Sprintf("<i>Сейчас на #%d — %s с %d XP. Это ориентир на сегодня: он тоже растёт.</i>\n",
+			r.Value, escapeHTML(r.RankHolder.DisplayName), r.RankHolder.XP))
+	}
+	sb.WriteString("\n")
+
+	f := r.Forecast
+	switch f.Status {
+	case forecast.StatusReached:
+		sb.WriteString("✅ Цель уже достигнута! Попробуй цель повыше.")
+	case forecast.StatusInsufficientData:
+		sb.WriteString(fmt.Sprintf("🤷 Недостаточно данных: нужно хотя бы %d активных дня за последние %d дней.\n"+
+			"Осталось %d XP.", forecast.MinActiveDays, int(forecast.Lookback.Hours()/24), f.RemainingXP))
+	case forecast.StatusOutOfReach:
+		sb.WriteString(fmt.Sprintf("🐢 В текущем темпе (~%d XP за активный день) это дольше двух лет.\n"+
+			"Осталось %d XP.", int(r.Pace.MeanXP), f.RemainingXP))
+	default:
+		sb.WriteString(fmt.Sprintf("🗓 В таком темпе — к <b>%s</b> (через %d дн.)\n", formatForecastDate(f.Date), f.Days))
+		if f.EarliestDays != f.LatestDays {
+			sb.WriteString(fmt.Sprintf("↔️ Скорее всего между %s и %s\n", formatForecastDate(f.Earliest), formatForecastDate(f.Latest)))
+		}
+		sb.WriteString(fmt.Sprintf("\n<i>Темп: ~%d XP за активный день по последним %d активным дням.</i>",
+			int(r.Pace.MeanXP), r.Pace.ActiveDays))
+	}
+
+	return sb.String()
+}
+
+// forecastTargetTitle describes the forecast target.
+func forecastTargetTitle(r *query.GetForecastResult) string {
+	switch r.Target {
+	case query.ForecastTargetLevel:
+		return fmt.Sprintf("уровень %d", r.Value)
+	case query.ForecastTargetRank:
+		return fmt.Sprintf("место #%d", r.Value)
+	default:
+		return fmt.Sprintf("%d XP", r.Value)
+	}
+}
+
+// formatForecastDate formats a date as "5 марта 2026".
+func formatForecastDate(t time.Time) string {
+	months := []string{
+		"января", "февраля", "марта", "апреля", "мая", "июня",
+		"июля", "августа", "сентября", "октября", "ноября", "декабря",
+	}
+	return fmt.Sprintf("%d %s %d", t.Day(), months[t.Month()-1], t.Year())
+}
+
+// forecastError builds an error response.
+func forecastError(text string) *ForecastResponse {
+	return &ForecastResponse{
+		Text:      text,
+		ParseMode: "HTML",
+		IsError:   true,
+	}
+}
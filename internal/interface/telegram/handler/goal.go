@@ -0,0 +1,157 @@
+// Package handler contains Telegram command handlers.
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GOAL HANDLER
+// Handles /goal command - personal XP target for the current week.
+// "/goal 500" sets or updates the target, "/goal" shows the progress.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// GoalHandler handles the /goal command.
+type GoalHandler struct {
+	setGoalCmd  *command.SetWeeklyGoalHandler
+	goalQuery   *query.GetWeeklyGoalHandler
+	studentRepo student.Repository
+}
+
+// NewGoalHandler creates a new GoalHandler with dependencies.
+func NewGoalHandler(
+	setGoalCmd *command.SetWeeklyGoalHandler,
+	goalQuery *query.GetWeeklyGoalHandler,
+	studentRepo student.Repository,
+) *GoalHandler {
+	return &GoalHandler{
+		setGoalCmd:  setGoalCmd,
+		goalQuery:   goalQuery,
+		studentRepo: studentRepo,
+	}
+}
+
+// GoalRequest contains the parsed /goal command data.
+type GoalRequest struct {
+	// TelegramID is the user's Telegram ID.
+	TelegramID int64
+
+	// ChatID is the chat ID for sending responses.
+	ChatID int64
+
+	// Args is the raw command argument ("500" or empty).
+	Args string
+}
+
+// GoalResponse contains the response to send back.
+type GoalResponse struct {
+	// Text is the message text (HTML formatted).
+	Text string
+
+	// ParseMode is the parse mode (HTML).
+	ParseMode string
+
+	// IsError indicates if this is an error response.
+	IsError bool
+}
+
+// Handle processes the /goal command.
+func (h *GoalHandler) Handle(ctx context.Context, req GoalRequest) (*GoalResponse, error) {
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(req.TelegramID))
+	if err != nil {
+		return goalError("❌ <b>Ты ещё не зарегистрирован</b>\n\nИспользуй /start чтобы присоединиться к сообществу."), nil
+	}
+
+	args := strings.TrimSpace(req.Args)
+	if args == "" {
+		return h.showGoal(ctx, stud.ID)
+	}
+
+	target, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(args), "xp"))
+	if err != nil {
+		return goalError(fmt.Sprintf("❓ Укажи цель числом, например: <code>/goal 500</code>\n\nДопустимо от %d до %d XP.",
+			student.MinWeeklyGoalXP, student.MaxWeeklyGoalXP)), nil
+	}
+
+	result, err := h.setGoalCmd.Handle(ctx, command.SetWeeklyGoalCommand{
+		StudentID: stud.ID,
+		TargetXP:  target,
+	})
+	switch {
+	case errors.Is(err, student.ErrGoalOutOfRange):
+		return goalError(fmt.Sprintf("⚠️ Цель должна быть от %d до %d XP.",
+			student.MinWeeklyGoalXP, student.MaxWeeklyGoalXP)), nil
+	case errors.Is(err, student.ErrGoalAlreadyReached):
+		return goalError("🎯 Цель этой недели уже достигнута — её нельзя изменить до понедельника."), nil
+	case errors.Is(err, student.ErrGoalTargetAlreadyEarned):
+		return goalError("⚠️ Новая цель должна быть больше XP, уже набранного на этой неделе."), nil
+	case err != nil:
+		return goalError("❌ Не удалось сохранить цель. Попробуйте позже."), nil
+	}
+
+	header := "🎯 <b>Цель на неделю поставлена!</b>"
+	if result.Updated {
+		header = "🎯 <b>Цель на неделю обновлена!</b>"
+	}
+
+	return &GoalResponse{
+		Text:      header + "\n\n" + formatGoalProgress(result.Progress),
+		ParseMode: "HTML",
+	}, nil
+}
+
+// showGoal shows the goal of the current week.
+func (h *GoalHandler) showGoal(ctx context.Context, studentID string) (*GoalResponse, error) {
+	result, err := h.goalQuery.Handle(ctx, query.GetWeeklyGoalQuery{StudentID: studentID})
+	if err != nil {
+		return goalError("❌ Не удалось загрузить цель. Попробуйте позже."), nil
+	}
+
+	if !result.HasGoal {
+		return &GoalResponse{
+			Text: fmt.Sprintf("🎯 <b>Цель на неделю</b>\n\n"+
+				"Цель пока не поставлена. За эту неделю уже +%d XP.\n\n"+
+				"Поставь цель: <code>/goal 500</code> (от %d до %d XP).",
+				result.WeekXP, student.MinWeeklyGoalXP, student.MaxWeeklyGoalXP),
+			ParseMode: "HTML",
+		}, nil
+	}
+
+	return &GoalResponse{
+		Text:      "🎯 <b>Цель на неделю</b>\n\n" + formatGoalProgress(result.Progress),
+		ParseMode: "HTML",
+	}, nil
+}
+
+// formatGoalProgress formats the progress bar and what is left to do.
+func formatGoalProgress(p student.WeeklyGoalProgress) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s %d%%\n", formatProgressBar(float64(p.Percent)/100), p.Percent))
+	sb.WriteString(fmt.Sprintf("📈 %d / %d XP\n", p.WeekXP, p.TargetXP))
+
+	if p.Reached {
+		sb.WriteString("\n✅ Цель достигнута! Так держать.")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("⏳ Осталось %d XP, дней до конца недели: %d", p.RemainingXP, p.DaysRemaining))
+	return sb.String()
+}
+
+// goalError builds an error response.
+func goalError(text string) *GoalResponse {
+	return &GoalResponse{
+		Text:      text,
+		ParseMode: "HTML",
+		IsError:   true,
+	}
+}
@@ -3,10 +3,14 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/application/cqrs"
 	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
 	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
 )
@@ -22,6 +26,8 @@ import (
 // HelpHandler handles the /help command.
 type HelpHandler struct {
 	findHelpersQuery *query.FindHelpersHandler
+	requestHelpCmd   cqrs.Handler[command.RequestHelpCommand, *command.RequestHelpResult]
+	clusterCmd       *command.HelpClusterHandler
 	studentRepo      student.Repository
 	keyboards        *presenter.KeyboardBuilder
 }
@@ -29,16 +35,24 @@ type HelpHandler struct {
 // NewHelpHandler creates a new HelpHandler with dependencies.
 func NewHelpHandler(
 	findHelpersQuery *query.FindHelpersHandler,
+	requestHelpCmd cqrs.Handler[command.RequestHelpCommand, *command.RequestHelpResult],
 	studentRepo student.Repository,
 	keyboards *presenter.KeyboardBuilder,
 ) *HelpHandler {
 	return &HelpHandler{
 		findHelpersQuery: findHelpersQuery,
+		requestHelpCmd:   requestHelpCmd,
 		studentRepo:      studentRepo,
 		keyboards:        keyboards,
 	}
 }
 
+// WithClusters enables the group button for students stuck on the same task.
+func (h *HelpHandler) WithClusters(clusterCmd *command.HelpClusterHandler) *HelpHandler {
+	h.clusterCmd = clusterCmd
+	return h
+}
+
 // HelpRequest contains the parsed /help command data.
 type HelpRequest struct {
 	// TelegramID is the user's Telegram ID.
@@ -53,6 +67,9 @@ type HelpRequest struct {
 	// TaskID is the task for which help is needed.
 	TaskID string
 
+	// HelpRequestID is the help request the group button belongs to.
+	HelpRequestID string
+
 	// PreferOnline prefers online helpers.
 	PreferOnline bool
 
@@ -76,6 +93,18 @@ type HelpResponse struct {
 
 	// NeedsTaskInput indicates that we need task input from user.
 	NeedsTaskInput bool
+
+	// Notices are messages for other students (the group members).
+	Notices []HelpNotice
+}
+
+// HelpNotice is a message for another student.
+type HelpNotice struct {
+	// TelegramID is the recipient's Telegram ID.
+	TelegramID int64
+
+	// Text is the message text (HTML formatted).
+	Text string
 }
 
 // Handle processes the /help command.
@@ -122,6 +151,177 @@ func (h *HelpHandler) Handle(ctx context.Context, req HelpRequest) (*HelpRespons
 	}, nil
 }
 
+// RequestHelp creates a help request for the task and notifies matched helpers.
+// Used by the one-tap button in the "stuck on a task" message.
+func (h *HelpHandler) RequestHelp(ctx context.Context, req HelpRequest) (*HelpResponse, error) {
+	currentStudent, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(req.TelegramID))
+	if err != nil {
+		return h.handleNotRegistered()
+	}
+
+	if req.TaskID == "" || h.requestHelpCmd == nil {
+		return h.handleAskForTask()
+	}
+
+	taskID := normalizeTaskID(req.TaskID)
+
+	result, err := h.requestHelpCmd.Handle(ctx, command.RequestHelpCommand{
+		RequesterID:   currentStudent.ID,
+		TaskID:        taskID,
+		NotifyHelpers: true,
+	})
+	if err != nil {
+		return &HelpResponse{Text: requestHelpErrorText(err, taskID), ParseMode: "HTML", IsError: true}, nil
+	}
+	if result.Deduplicated {
+		return h.existingRequestResponse(result, taskID), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🆘 <b>Запрос помощи создан</b>\n")
+	sb.WriteString(fmt.Sprintf("📋 <code>%s</code>\n\n", escapeHTML(taskID)))
+	switch {
+	case result.NotifiedCount > 0:
+		sb.WriteString(fmt.Sprintf("Я написал %d студентам, которые решили эту задачу. Скоро кто-нибудь откликнется 🤝", result.NotifiedCount))
+	case result.ClusterPeers > 0:
+		sb.WriteString("Помощники уже получили запрос по этой задаче от твоей когорты — они увидят и твой.")
+	default:
+		sb.WriteString("Сейчас свободных помощников нет, но запрос открыт — как только кто-то освободится, он увидит его.")
+	}
+
+	keyboard := presenter.NewInlineKeyboard()
+	if result.ClusterPeers > 0 && h.clusterCmd != nil {
+		stuck := "застряли"
+		if result.ClusterPeers%10 == 1 && result.ClusterPeers%100 != 11 {
+			stuck = "застрял"
+		}
+		sb.WriteString(fmt.Sprintf(
+			"\n\n👥 Ещё %d %s из твоей когорты %s на этой задаче. Можно объединиться и разбираться вместе.",
+			result.ClusterPeers, pluralizeStudents(result.ClusterPeers), stuck,
+		))
+		keyboard.AddRow(presenter.CallbackButton("👥 Объединиться", fmt.Sprintf("help:group:%s", result.RequestID)))
+	}
+	keyboard.AddRow(
+		presenter.CallbackButton("👀 Кто решил", fmt.Sprintf("help:refresh:%s", taskID)),
+	)
+
+	return &HelpResponse{
+		Text:      sb.String(),
+		Keyboard:  keyboard,
+		ParseMode: "HTML",
+	}, nil
+}
+
+// requestHelpErrorText explains why the help request was not created.
+func requestHelpErrorText(err error, taskID string) string {
+	var limit *social.HelpRequestLimitError
+	if errors.As(err, &limit) {
+		return fmt.Sprintf(
+			"✋ <b>Слишком много открытых запросов</b>\n\n"+
+				"Одновременно можно держать открытыми %d %s помощи. "+
+				"Закрой один из них или дождись ответа, а потом спроси про <code>%s</code>.",
+			limit.Limit, pluralizeRequests(limit.Limit), escapeHTML(taskID),
+		)
+	}
+	return fmt.Sprintf(
+		"❌ <b>Не удалось создать запрос помощи</b>\n\n"+
+			"Задача: <code>%s</code>\n\n"+
+			"<i>Попробуй ещё раз чуть позже: /help %s</i>",
+		escapeHTML(taskID), escapeHTML(taskID),
+	)
+}
+
+// existingRequestResponse answers a repeated /help for a task that already
+// has an open request: nothing new is sent to the helpers.
+func (h *HelpHandler) existingRequestResponse(result *command.RequestHelpResult, taskID string) *HelpResponse {
+	var sb strings.Builder
+	sb.WriteString("🆘 <b>У тебя уже есть открытый запрос по этой задаче</b>\n")
+	sb.WriteString(fmt.Sprintf("📋 <code>%s</code>\n\n", escapeHTML(taskID)))
+	if len(result.MatchedHelpers) > 0 {
+		names := make([]string, len(result.MatchedHelpers))
+		for i, helper := range result.MatchedHelpers {
+			names[i] = escapeHTML(helper.DisplayName)
+		}
+		sb.WriteString("Помощники уже получили его: " + strings.Join(names, ", ") + ". Скоро кто-нибудь откликнется 🤝")
+	} else {
+		sb.WriteString("Помощники увидят его, как только освободятся — повторно писать не нужно.")
+	}
+
+	keyboard := presenter.NewInlineKeyboard().AddRow(
+		presenter.CallbackButton("👀 Кто решил", fmt.Sprintf("help:refresh:%s", taskID)),
+	)
+	return &HelpResponse{Text: sb.String(), Keyboard: keyboard, ParseMode: "HTML"}
+}
+
+// JoinGroup opts the student into the group of students stuck on the same
+// task and shares contacts between the members who joined.
+func (h *HelpHandler) JoinGroup(ctx context.Context, req HelpRequest) (*HelpResponse, error) {
+	currentStudent, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(req.TelegramID))
+	if err != nil {
+		return h.handleNotRegistered()
+	}
+	if h.clusterCmd == nil || req.HelpRequestID == "" {
+		return h.handleAskForTask()
+	}
+
+	result, err := h.clusterCmd.JoinGroup(ctx, req.HelpRequestID, currentStudent.ID)
+	if err != nil {
+		text := "❌ <b>Группа недоступна</b>\n\n" +
+			"<i>Запрос уже закрыт или остальные уже получили помощь.</i>"
+		return &HelpResponse{Text: text, ParseMode: "HTML", IsError: true}, nil
+	}
+
+	taskID := string(result.Request.TaskID)
+	var sb strings.Builder
+	sb.WriteString("👥 <b>Группа по задаче</b>\n")
+	sb.WriteString(fmt.Sprintf("📋 <code>%s</code>\n\n", escapeHTML(taskID)))
+	if len(result.Members) > 0 {
+		sb.WriteString("Вместе с тобой:\n")
+		for _, m := range result.Members {
+			sb.WriteString(fmt.Sprintf("• %s\n", presenter.Mention(int64(m.TelegramID), m.DisplayName)))
+		}
+		sb.WriteString("\nНапиши им — вместе разбираться проще 🤝")
+	} else {
+		sb.WriteString("Ты первый в группе. Как только кто-то ещё присоединится, я пришлю его контакт.")
+	}
+	if result.Waiting > 0 {
+		sb.WriteString(fmt.Sprintf("\n\n<i>Ещё не присоединились: %d</i>", result.Waiting))
+	}
+
+	resp := &HelpResponse{
+		Text: sb.String(),
+		Keyboard: presenter.NewInlineKeyboard().AddRow(
+			presenter.CallbackButton("👀 Кто решил", fmt.Sprintf("help:refresh:%s", taskID)),
+		),
+		ParseMode: "HTML",
+	}
+
+	// Members learn about a newcomer only once
+	if result.Joined {
+		notice := fmt.Sprintf(
+			"👥 %s тоже застрял(а) на <code>%s</code> и присоединился(ась) к группе. Напиши — вместе проще 🤝",
+			presenter.Mention(int64(result.Requester.TelegramID), result.Requester.DisplayName),
+			escapeHTML(taskID),
+		)
+		for _, m := range result.Members {
+			resp.Notices = append(resp.Notices, HelpNotice{TelegramID: int64(m.TelegramID), Text: notice})
+		}
+	}
+	return resp, nil
+}
+
+// pluralizeStudents returns the Russian form of "студент" for n.
+func pluralizeStudents(n int) string {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return "студент"
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return "студента"
+	default:
+		return "студентов"
+	}
+}
+
 // handleNotRegistered handles the case when user is not registered.
 func (h *HelpHandler) handleNotRegistered() (*HelpResponse, error) {
 	text := "❌ <b>Ты ещё не зарегистрирован</b>\n\n" +
@@ -180,6 +380,10 @@ func (h *HelpHandler) buildHelpersView(result *query.FindHelpersResult, taskID s
 	sb.WriteString(fmt.Sprintf("🆘 <b>Помощь по задаче</b>\n"))
 	sb.WriteString(fmt.Sprintf("📋 <code>%s</code>\n\n", escapeHTML(taskID)))
 
+	if result.FrequentlyAsked {
+		sb.WriteString("⚠️ эту задачу часто спрашивают — не стесняйся\n\n")
+	}
+
 	// Stats
 	sb.WriteString(fmt.Sprintf("👥 Решило задачу: %d\n", result.TotalSolvers))
 	if result.OnlineSolvers > 0 {
@@ -196,6 +400,10 @@ func (h *HelpHandler) buildHelpersView(result *query.FindHelpersResult, taskID s
 		return sb.String()
 	}
 
+	if result.MatchedByXP {
+		sb.WriteString("🧭 <i>Задачу пока никто не решил — вот кто опытнее тебя в потоке.</i>\n\n")
+	}
+
 	// List helpers
 	sb.WriteString("<b>Кто может помочь:</b>\n\n")
 
@@ -243,6 +451,11 @@ func (h *HelpHandler) formatHelper(num int, helper query.HelperDTO) string {
 		sb.WriteString("\n")
 	}
 
+	// Helper streak
+	if helper.HelperStreakWeeks > 0 {
+		sb.WriteString(fmt.Sprintf("   🤝 %s\n", student.FormatHelperStreak(helper.HelperStreakWeeks)))
+	}
+
 	// Time since completion
 	if helper.TimeSinceCompletion != "" {
 		sb.WriteString(fmt.Sprintf("   ✅ Решил: %s\n", helper.TimeSinceCompletion))
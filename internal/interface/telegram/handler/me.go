@@ -3,9 +3,12 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/alem-hub/alem-community-hub/internal/application/cqrs"
 	"github.com/alem-hub/alem-community-hub/internal/application/query"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
 	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
@@ -20,30 +23,38 @@ import (
 
 // MeHandler handles the /me command for showing student card.
 type MeHandler struct {
-	studentRankQuery *query.GetStudentRankHandler
-	dailyProgress    *query.GetDailyProgressHandler
-	studentRepo      student.Repository
-	keyboards        *presenter.KeyboardBuilder
-	cardPresenter    *presenter.StudentCardPresenter
+	dailyProgress      *query.GetDailyProgressHandler
+	notificationsQuery *query.GetNotificationsHandler
+	helperStreaks      student.HelperStreakRepository
+	students           *StudentLoader
+	keyboards          *presenter.KeyboardBuilder
+	cardPresenter      *presenter.StudentCardPresenter
 }
 
 // NewMeHandler creates a new MeHandler with dependencies.
 func NewMeHandler(
-	studentRankQuery *query.GetStudentRankHandler,
+	studentRankQuery cqrs.Handler[query.GetStudentRankQuery, *query.GetStudentRankResult],
 	dailyProgress *query.GetDailyProgressHandler,
+	notificationsQuery *query.GetNotificationsHandler,
 	studentRepo student.Repository,
 	keyboards *presenter.KeyboardBuilder,
 	cardPresenter *presenter.StudentCardPresenter,
 ) *MeHandler {
 	return &MeHandler{
-		studentRankQuery: studentRankQuery,
-		dailyProgress:    dailyProgress,
-		studentRepo:      studentRepo,
-		keyboards:        keyboards,
-		cardPresenter:    cardPresenter,
+		dailyProgress:      dailyProgress,
+		notificationsQuery: notificationsQuery,
+		students:           newRepositoryLoader(studentRepo).WithRankQuery(studentRankQuery),
+		keyboards:          keyboards,
+		cardPresenter:      cardPresenter,
 	}
 }
 
+// WithHelperStreaks shows the helper streak on the card.
+func (h *MeHandler) WithHelperStreaks(repo student.HelperStreakRepository) *MeHandler {
+	h.helperStreaks = repo
+	return h
+}
+
 // MeRequest contains the parsed /me command data.
 type MeRequest struct {
 	// TelegramID is the user's Telegram ID.
@@ -76,19 +87,18 @@ type MeResponse struct {
 
 // Handle processes the /me command.
 func (h *MeHandler) Handle(ctx context.Context, req MeRequest) (*MeResponse, error) {
-	// Get student by Telegram ID
-	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(req.TelegramID))
-	if err != nil {
+	// The student and rank come from the update's context when the
+	// dispatcher already loaded them
+	sc := h.students.Current(ctx, req.TelegramID)
+	stud, err := sc.Student(ctx)
+	if errors.Is(err, ErrNotRegistered) {
 		return h.handleNotRegistered()
 	}
-
-	// Get rank information
-	rankQuery := query.GetStudentRankQuery{
-		TelegramID:     req.TelegramID,
-		IncludeHistory: false,
+	if err != nil {
+		return nil, err
 	}
 
-	rankResult, err := h.studentRankQuery.Handle(ctx, rankQuery)
+	rankResult, err := sc.Rank(ctx)
 	if err != nil {
 		// Continue without rank info
 		rankResult = nil
@@ -98,13 +108,28 @@ func (h *MeHandler) Handle(ctx context.Context, req MeRequest) (*MeResponse, err
 	var dailyResult *query.GetDailyProgressResult
 	if h.dailyProgress != nil {
 		progressQuery := query.GetDailyProgressQuery{
-			TelegramID: req.TelegramID,
+			Student: stud,
 		}
 		dailyResult, _ = h.dailyProgress.Handle(ctx, progressQuery)
 	}
 
+	// Helper streak (optional)
+	helperWeeks := 0
+	if h.helperStreaks != nil {
+		if streak, err := h.helperStreaks.Get(ctx, stud.ID); err == nil {
+			helperWeeks = streak.ActiveWeeks(time.Now())
+		}
+	}
+
 	// Build the student card
-	text := h.buildStudentCard(stud, rankResult, dailyResult)
+	text := h.buildStudentCard(stud, rankResult, dailyResult, helperWeeks)
+
+	// Unread notifications badge (optional)
+	if h.notificationsQuery != nil {
+		if unread, err := h.notificationsQuery.CountUnread(ctx, stud.ID); err == nil && unread > 0 {
+			text += fmt.Sprintf("\n🔔 %d непрочитанных — /notifications", unread)
+		}
+	}
 	keyboard := h.keyboards.StudentCardKeyboard(stud.ID)
 
 	return &MeResponse{
@@ -132,6 +157,7 @@ func (h *MeHandler) buildStudentCard(
 	stud *student.Student,
 	rankResult *query.GetStudentRankResult,
 	dailyResult *query.GetDailyProgressResult,
+	helperWeeks int,
 ) string {
 	var sb strings.Builder
 
@@ -171,6 +197,10 @@ func (h *MeHandler) buildStudentCard(
 			sb.WriteString(fmt.Sprintf(" (%s)", percentile))
 		}
 		sb.WriteString("\n")
+
+		if !stud.IsOnPublicLeaderboard() {
+			sb.WriteString("   🙈 <i>Ты скрыт из рейтинга — место видно только тебе (/privacy)</i>\n")
+		}
 	}
 	sb.WriteString("\n")
 
@@ -179,6 +209,13 @@ func (h *MeHandler) buildStudentCard(
 		sb.WriteString("🔥 <b>Сегодня</b>\n")
 		sb.WriteString(fmt.Sprintf("├ XP: +%d\n", dailyResult.Today.XPGained))
 		sb.WriteString(fmt.Sprintf("├ Задач: %d\n", dailyResult.Today.TasksCompleted))
+		if change := dailyResult.Today.RankChange; change != 0 {
+			changeEmoji, changeSign := "📈", "+"
+			if change < 0 {
+				changeEmoji, changeSign = "📉", ""
+			}
+			sb.WriteString(fmt.Sprintf("├ Позиция за день: %s %s%d\n", changeEmoji, changeSign, change))
+		}
 
 		if dailyResult.Streak != nil && dailyResult.Streak.CurrentStreak > 0 {
 			streakEmoji := "🔥"
@@ -193,6 +230,20 @@ func (h *MeHandler) buildStudentCard(
 		sb.WriteString("\n")
 	}
 
+	// Personal bests
+	if dailyResult != nil && len(dailyResult.PersonalBests) > 0 {
+		sb.WriteString("🏆 <b>Личные рекорды</b>\n")
+		for i, best := range dailyResult.PersonalBests {
+			prefix := "├"
+			if i == len(dailyResult.PersonalBests)-1 {
+				prefix = "└"
+			}
+			sb.WriteString(fmt.Sprintf("%s %s: %s <i>(%s)</i>\n",
+				prefix, best.Label, best.ValueFormatted, best.AchievedOnFormatted))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Neighbor info (who to catch up with)
 	if rankResult != nil && rankResult.Student.XPToNextRank > 0 && rankResult.Student.NextRankStudent != "" {
 		sb.WriteString("🎯 <b>Цель</b>\n")
@@ -202,10 +253,20 @@ func (h *MeHandler) buildStudentCard(
 	}
 
 	// Helper rating (if they've helped others)
-	if stud.HelpCount > 0 {
+	if stud.HelpCount > 0 || helperWeeks > 0 {
 		sb.WriteString("🤝 <b>Помощник</b>\n")
-		sb.WriteString(fmt.Sprintf("├ Рейтинг: %s (%.1f)\n", formatStarRating(stud.HelpRating), stud.HelpRating))
-		sb.WriteString(fmt.Sprintf("└ Помощей: %d\n\n", stud.HelpCount))
+		if stud.HelpCount > 0 {
+			sb.WriteString(fmt.Sprintf("├ Рейтинг: %s (%.1f)\n", formatStarRating(stud.HelpRating), stud.HelpRating))
+			prefix := "└"
+			if helperWeeks > 0 {
+				prefix = "├"
+			}
+			sb.WriteString(fmt.Sprintf("%s Помощей: %d\n", prefix, stud.HelpCount))
+		}
+		if helperWeeks > 0 {
+			sb.WriteString(fmt.Sprintf("└ 🤝 %s\n", student.FormatHelperStreak(helperWeeks)))
+		}
+		sb.WriteString("\n")
 	}
 
 	// Motivational message
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MUTE HANDLER
+// Handles /mute and /unmute - temporary silence without touching settings.
+// "/mute 24h" mutes everything non-urgent, "/mute rank [7d]" one category,
+// "/mute" shows the current mutes with preset buttons.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// MuteHandler handles the /mute and /unmute commands.
+type MuteHandler struct {
+	muteCmd     *command.MuteNotificationsHandler
+	studentRepo student.Repository
+	now         func() time.Time
+}
+
+// NewMuteHandler creates a new MuteHandler with dependencies.
+func NewMuteHandler(muteCmd *command.MuteNotificationsHandler, studentRepo student.Repository) *MuteHandler {
+	return &MuteHandler{
+		muteCmd:     muteCmd,
+		studentRepo: studentRepo,
+		now:         time.Now,
+	}
+}
+
+// MuteRequest contains the parsed /mute command data.
+type MuteRequest struct {
+	// TelegramID is the user's Telegram ID.
+	TelegramID int64
+
+	// ChatID is the chat ID to respond to.
+	ChatID int64
+
+	// Args is the raw command argument ("24h", "rank 7d" or empty).
+	Args string
+}
+
+// MuteResponse contains the response to send back.
+type MuteResponse struct {
+	// Text is the message text (HTML formatted).
+	Text string
+
+	// Keyboard is the inline keyboard to attach.
+	Keyboard *presenter.InlineKeyboard
+
+	// ParseMode is the parse mode (HTML).
+	ParseMode string
+}
+
+// muteCategoryNames are the category names shown to students.
+var muteCategoryNames = map[student.MuteCategory]string{
+	student.MuteCategoryRank:   "рейтинг",
+	student.MuteCategoryDigest: "ежедневная сводка",
+	student.MuteCategoryHelp:   "запросы помощи",
+}
+
+// Handle processes the /mute command.
+func (h *MuteHandler) Handle(ctx context.Context, req MuteRequest) (*MuteResponse, error) {
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(req.TelegramID))
+	if err != nil {
+		return muteText("❌ <b>Ты ещё не зарегистрирован</b>\n\nИспользуй /start чтобы присоединиться к сообществу."), nil
+	}
+
+	args := strings.Fields(req.Args)
+	if len(args) == 0 {
+		return h.showMutes(stud), nil
+	}
+
+	cmd := command.MuteNotificationsCommand{StudentID: stud.ID}
+	if category, ok := student.ParseMuteCategory(args[0]); ok {
+		cmd.Category = category
+		cmd.Duration = student.DefaultCategoryMute
+		args = args[1:]
+	}
+	if len(args) > 0 {
+		if cmd.Duration, err = student.ParseMuteDuration(args[0]); err != nil {
+			return muteText(muteUsage()), nil
+		}
+	}
+	if cmd.Duration == 0 {
+		return muteText(muteUsage()), nil
+	}
+
+	result, err := h.muteCmd.Handle(ctx, cmd)
+	if errors.Is(err, student.ErrInvalidMuteDuration) {
+		return muteText(muteUsage()), nil
+	}
+	if err != nil {
+		return muteText("❌ Не удалось включить тишину. Попробуйте позже."), nil
+	}
+
+	what := "Все уведомления, кроме срочных, заглушены"
+	if cmd.Category != student.MuteCategoryOther {
+		what = fmt.Sprintf("Уведомления «%s» заглушены", muteCategoryNames[cmd.Category])
+	}
+	return &MuteResponse{
+		Text: fmt.Sprintf("🔕 <b>%s</b> до %s.\n\nВернуть раньше: /unmute",
+			what, formatMuteUntil(result.Until)),
+		ParseMode: "HTML",
+	}, nil
+}
+
+// Unmute processes the /unmute command.
+func (h *MuteHandler) Unmute(ctx context.Context, telegramID int64) (*MuteResponse, error) {
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(telegramID))
+	if err != nil {
+		return muteText("❌ <b>Ты ещё не зарегистрирован</b>\n\nИспользуй /start чтобы присоединиться к сообществу."), nil
+	}
+
+	if err := h.muteCmd.Unmute(ctx, stud.ID); err != nil {
+		return muteText("❌ Не удалось выключить тишину. Попробуйте позже."), nil
+	}
+
+	return muteText("🔔 <b>Режим тишины выключен</b>\n\nУведомления снова приходят по твоим /settings."), nil
+}
+
+// showMutes shows the active mutes and the preset buttons.
+func (h *MuteHandler) showMutes(stud *student.Student) *MuteResponse {
+	now := h.now()
+
+	var sb strings.Builder
+	sb.WriteString("🔕 <b>Режим тишины</b>\n\n")
+
+	active := false
+	if stud.IsMuted(student.MuteCategoryOther, now) {
+		sb.WriteString(fmt.Sprintf("Всё, кроме срочного, — до %s\n", formatMuteUntil(*stud.MutedUntil)))
+		active = true
+	}
+	for _, category := range student.MuteCategories() {
+		until, ok := stud.Preferences.CategoryMutes[category]
+		if ok && now.Before(until) {
+			sb.WriteString(fmt.Sprintf("«%s» — до %s\n", muteCategoryNames[category], formatMuteUntil(until)))
+			active = true
+		}
+	}
+	if !active {
+		sb.WriteString("Сейчас уведомления не заглушены.\n")
+	}
+
+	sb.WriteString("\n" + muteUsage())
+
+	keyboard := presenter.NewInlineKeyboard()
+	var presets []presenter.InlineButton
+	for _, d := range student.MutePresets {
+		label := student.FormatMuteDuration(d)
+		presets = append(presets, presenter.CallbackButton("🔕 "+label, "mute:"+label))
+	}
+	keyboard.AddRow(presets...)
+	if active {
+		keyboard.AddRow(presenter.CallbackButton("🔔 Выключить тишину", "mute:off"))
+	}
+
+	return &MuteResponse{Text: sb.String(), Keyboard: keyboard, ParseMode: "HTML"}
+}
+
+// muteUsage describes the command arguments.
+func muteUsage() string {
+	return "Как заглушить:\n" +
+		"• <code>/mute 24h</code> — всё, кроме срочного (от 1m до 30d)\n" +
+		"• <code>/mute rank</code>, <code>/mute digest</code>, <code>/mute help</code> — одну категорию на сутки\n" +
+		"• <code>/mute rank 7d</code> — категорию на свой срок\n" +
+		"• /unmute — выключить всё"
+}
+
+// formatMuteUntil formats the end of a mute.
+func formatMuteUntil(t time.Time) string {
+	return t.Local().Format("02.01 15:04")
+}
+
+// muteText builds a plain response.
+func muteText(text string) *MuteResponse {
+	return &MuteResponse{Text: text, ParseMode: "HTML"}
+}
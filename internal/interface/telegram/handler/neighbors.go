@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/alem-hub/alem-community-hub/internal/application/cqrs"
 	"github.com/alem-hub/alem-community-hub/internal/application/query"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
 	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
@@ -21,14 +22,14 @@ import (
 
 // NeighborsHandler handles the /neighbors command.
 type NeighborsHandler struct {
-	neighborsQuery *query.GetNeighborsHandler
+	neighborsQuery cqrs.Handler[query.GetNeighborsQuery, *query.GetNeighborsResult]
 	studentRepo    student.Repository
 	keyboards      *presenter.KeyboardBuilder
 }
 
 // NewNeighborsHandler creates a new NeighborsHandler with dependencies.
 func NewNeighborsHandler(
-	neighborsQuery *query.GetNeighborsHandler,
+	neighborsQuery cqrs.Handler[query.GetNeighborsQuery, *query.GetNeighborsResult],
 	studentRepo student.Repository,
 	keyboards *presenter.KeyboardBuilder,
 ) *NeighborsHandler {
@@ -160,7 +161,7 @@ func (h *NeighborsHandler) buildNeighborsView(result *query.GetNeighborsResult)
 	// Motivational section
 	if result.ClosestAbove != nil && result.XPToOvertakeNext > 0 {
 		sb.WriteString("🎯 <b>Цель</b>\n")
-		sb.WriteString(fmt.Sprintf("До @%s осталось <b>%d XP</b>",
+		sb.WriteString(fmt.Sprintf("До %s осталось <b>%d XP</b>",
 			escapeHTML(result.ClosestAbove.DisplayName),
 			result.XPToOvertakeNext))
 
@@ -173,7 +174,7 @@ func (h *NeighborsHandler) buildNeighborsView(result *query.GetNeighborsResult)
 	// Warning about chaser
 	if result.ClosestBelow != nil && result.XPAheadOfChaser > 0 && result.XPAheadOfChaser <= 30 {
 		sb.WriteString("⚠️ <b>Внимание</b>\n")
-		sb.WriteString(fmt.Sprintf("@%s отстаёт всего на <b>%d XP</b>!\n\n",
+		sb.WriteString(fmt.Sprintf("%s отстаёт всего на <b>%d XP</b>!\n\n",
 			escapeHTML(result.ClosestBelow.DisplayName),
 			result.XPAheadOfChaser))
 	}
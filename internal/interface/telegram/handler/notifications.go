@@ -0,0 +1,197 @@
+// Package handler contains Telegram command handlers.
+package handler
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// NOTIFICATIONS HANDLER
+// Handles /notifications command - the notification center.
+// Students who mute the bot can review rank changes and achievements they missed.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// NotificationsHandler handles the /notifications command.
+type NotificationsHandler struct {
+	notificationsQuery *query.GetNotificationsHandler
+	markReadCmd        *command.MarkNotificationsReadHandler
+	keyboards          *presenter.KeyboardBuilder
+}
+
+// NewNotificationsHandler creates a new NotificationsHandler with dependencies.
+func NewNotificationsHandler(
+	notificationsQuery *query.GetNotificationsHandler,
+	markReadCmd *command.MarkNotificationsReadHandler,
+	keyboards *presenter.KeyboardBuilder,
+) *NotificationsHandler {
+	return &NotificationsHandler{
+		notificationsQuery: notificationsQuery,
+		markReadCmd:        markReadCmd,
+		keyboards:          keyboards,
+	}
+}
+
+// NotificationsRequest contains the parsed /notifications command data.
+type NotificationsRequest struct {
+	// TelegramID is the user's Telegram ID.
+	TelegramID int64
+
+	// ChatID is the chat ID for sending responses.
+	ChatID int64
+
+	// MessageID is the original message ID (for editing).
+	MessageID int
+
+	// Page is the page to show (1-based).
+	Page int
+
+	// IsRefresh indicates if this is a refresh request (from callback).
+	IsRefresh bool
+}
+
+// NotificationsResponse contains the response to send back.
+type NotificationsResponse struct {
+	// Text is the message text (HTML formatted).
+	Text string
+
+	// Keyboard is the inline keyboard to attach.
+	Keyboard *presenter.InlineKeyboard
+
+	// ParseMode is the parse mode (HTML).
+	ParseMode string
+
+	// IsError indicates if this is an error response.
+	IsError bool
+}
+
+// Handle processes the /notifications command.
+func (h *NotificationsHandler) Handle(ctx context.Context, req NotificationsRequest) (*NotificationsResponse, error) {
+	result, err := h.notificationsQuery.Handle(ctx, query.GetNotificationsQuery{
+		TelegramID: req.TelegramID,
+		Page:       req.Page,
+	})
+	if err != nil {
+		return &NotificationsResponse{
+			Text:      "❌ Не удалось загрузить уведомления. Попробуйте позже.",
+			ParseMode: "HTML",
+			IsError:   true,
+		}, nil
+	}
+
+	return &NotificationsResponse{
+		Text:      h.formatNotifications(result, time.Now()),
+		Keyboard:  h.keyboards.NotificationsKeyboard(result.Page, result.HasMore, result.UnreadCount > 0),
+		ParseMode: "HTML",
+	}, nil
+}
+
+// MarkAllRead marks every notification as read and re-renders the page.
+func (h *NotificationsHandler) MarkAllRead(ctx context.Context, req NotificationsRequest) (*NotificationsResponse, error) {
+	if _, err := h.markReadCmd.Handle(ctx, command.MarkNotificationsReadCommand{
+		TelegramID: req.TelegramID,
+	}); err != nil {
+		return &NotificationsResponse{
+			Text:      "❌ Не удалось отметить уведомления. Попробуйте позже.",
+			ParseMode: "HTML",
+			IsError:   true,
+		}, nil
+	}
+
+	return h.Handle(ctx, req)
+}
+
+// formatNotifications formats the notification list grouped by day.
+func (h *NotificationsHandler) formatNotifications(result *query.GetNotificationsResult, now time.Time) string {
+	var sb strings.Builder
+
+	sb.WriteString("🔔 <b>Уведомления</b>")
+	if result.UnreadCount > 0 {
+		sb.WriteString(fmt.Sprintf(" • %d непрочитанных", result.UnreadCount))
+	}
+	sb.WriteString("\n")
+
+	if len(result.Items) == 0 {
+		if result.Page > 1 {
+			sb.WriteString("\n<i>На этой странице пусто.</i>")
+		} else {
+			sb.WriteString("\n<i>Пока уведомлений нет.</i>")
+		}
+		return sb.String()
+	}
+
+	lastDay := ""
+	for _, item := range result.Items {
+		created := item.CreatedAt.Local()
+		day := formatNotificationDay(created, now)
+		if day != lastDay {
+			sb.WriteString(fmt.Sprintf("\n📅 <b>%s</b>\n", day))
+			lastDay = day
+		}
+
+		line := fmt.Sprintf("%s %s %s", created.Format("15:04"), item.Emoji, escapeHTML(notificationSummary(item)))
+		if item.Unread {
+			line = "<b>" + line + "</b>"
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	if result.Page > 1 || result.HasMore {
+		sb.WriteString(fmt.Sprintf("\n<i>Страница %d</i>", result.Page))
+	}
+
+	return sb.String()
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HELPER FUNCTIONS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// htmlTagPattern matches HTML tags in notification messages.
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// notificationSummary returns a one-line plain-text summary of a notification.
+func notificationSummary(item query.NotificationDTO) string {
+	text := item.Title
+	if text == "" {
+		text = item.Message
+	}
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	if idx := strings.IndexByte(text, '\n'); idx >= 0 {
+		text = text[:idx]
+	}
+	text = strings.TrimSpace(text)
+
+	const maxLen = 80
+	if runes := []rune(text); len(runes) > maxLen {
+		text = string(runes[:maxLen-1]) + "…"
+	}
+	return text
+}
+
+// formatNotificationDay formats the day header for a group of notifications.
+func formatNotificationDay(t, now time.Time) string {
+	months := []string{
+		"января", "февраля", "марта", "апреля", "мая", "июня",
+		"июля", "августа", "сентября", "октября", "ноября", "декабря",
+	}
+
+	y1, m1, d1 := t.Date()
+	y2, m2, d2 := now.Date()
+	if y1 == y2 && m1 == m2 && d1 == d2 {
+		return "Сегодня"
+	}
+	y3, m3, d3 := now.AddDate(0, 0, -1).Date()
+	if y1 == y3 && m1 == m3 && d1 == d3 {
+		return "Вчера"
+	}
+
+	return fmt.Sprintf("%d %s", t.Day(), months[t.Month()-1])
+}
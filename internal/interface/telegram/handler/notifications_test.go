@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+)
+
+func TestNotificationsHandler_FormatGroupsByDayAndBoldsUnread(t *testing.T) {
+	now := time.Date(2025, 6, 16, 18, 0, 0, 0, time.Local)
+	h := &NotificationsHandler{}
+
+	result := &query.GetNotificationsResult{
+		UnreadCount: 1,
+		Page:        1,
+		PageSize:    query.DefaultNotificationsPageSize,
+		Items: []query.NotificationDTO{
+			{Emoji: "📈", Title: "Вы поднялись на 12 место", CreatedAt: now.Add(-2 * time.Hour), Unread: true},
+			{Emoji: "🏆", Message: "<b>Новое достижение</b>\nПервая задача", CreatedAt: now.Add(-26 * time.Hour)},
+			{Emoji: "📉", Title: "Вы опустились на 14 место", CreatedAt: now.AddDate(0, 0, -5), Superseded: true},
+		},
+	}
+
+	text := h.formatNotifications(result, now)
+
+	assert.Contains(t, text, "1 непрочитанных")
+	assert.Contains(t, text, "<b>16:00 📈 Вы поднялись на 12 место</b>")
+	assert.Contains(t, text, "\n16:00 🏆 Новое достижение\n", "read items are not bolded and tags are stripped")
+	assert.NotContains(t, text, "Первая задача")
+	assert.NotContains(t, text, "Страница", "single page has no page footer")
+
+	today := strings.Index(text, "📅 <b>Сегодня</b>")
+	yesterday := strings.Index(text, "📅 <b>Вчера</b>")
+	older := strings.Index(text, "📅 <b>11 июня</b>")
+	assert.True(t, today >= 0 && today < yesterday && yesterday < older, text)
+}
+
+func TestNotificationsHandler_FormatEmpty(t *testing.T) {
+	h := &NotificationsHandler{}
+
+	text := h.formatNotifications(&query.GetNotificationsResult{Page: 1}, time.Now())
+
+	assert.Contains(t, text, "Пока уведомлений нет")
+	assert.NotContains(t, text, "непрочитанных")
+}
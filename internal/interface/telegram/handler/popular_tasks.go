@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// POPULAR TASKS HANDLER
+// Handles /populartasks command - tasks people asked help with most this week.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// popularTasksShown is how many tasks /populartasks lists.
+const popularTasksShown = 10
+
+// PopularTasksHandler handles the /populartasks command.
+type PopularTasksHandler struct {
+	popularQuery *query.GetPopularTasksHandler
+	keyboards    *presenter.KeyboardBuilder
+}
+
+// NewPopularTasksHandler creates a new PopularTasksHandler with dependencies.
+func NewPopularTasksHandler(
+	popularQuery *query.GetPopularTasksHandler,
+	keyboards *presenter.KeyboardBuilder,
+) *PopularTasksHandler {
+	return &PopularTasksHandler{
+		popularQuery: popularQuery,
+		keyboards:    keyboards,
+	}
+}
+
+// PopularTasksResponse contains the response to send back.
+type PopularTasksResponse struct {
+	// Text is the message text (HTML formatted).
+	Text string
+
+	// Keyboard has a "see helpers" button per task.
+	Keyboard *presenter.InlineKeyboard
+
+	// ParseMode is the parse mode (HTML).
+	ParseMode string
+}
+
+// Handle processes the /populartasks command.
+func (h *PopularTasksHandler) Handle(ctx context.Context) (*PopularTasksResponse, error) {
+	result, err := h.popularQuery.Handle(ctx, query.GetPopularTasksQuery{
+		Window: query.PopularTasksWeek,
+		Limit:  popularTasksShown,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get popular tasks: %w", err)
+	}
+
+	if len(result.Tasks) == 0 {
+		return &PopularTasksResponse{
+			Text:      "🔥 <b>Горячие задачи недели</b>\n\nНа этой неделе ни по одной задаче не просили помощи дважды.",
+			ParseMode: "HTML",
+		}, nil
+	}
+
+	return &PopularTasksResponse{
+		Text:      formatPopularTasks(result.Tasks),
+		Keyboard:  h.keyboards.PopularTasksKeyboard(result.Tasks),
+		ParseMode: "HTML",
+	}, nil
+}
+
+// formatPopularTasks renders the weekly list of hot tasks.
+func formatPopularTasks(tasks []query.PopularTaskDTO) string {
+	var sb strings.Builder
+	sb.WriteString("🔥 <b>Горячие задачи недели</b>\n")
+	sb.WriteString("<i>По ним чаще всего просили помощи</i>\n\n")
+
+	for i, t := range tasks {
+		sb.WriteString(fmt.Sprintf("%d. <b>%s</b> — %d %s",
+			i+1, html.EscapeString(t.TaskName), t.Requests, pluralizeRequests(t.Requests)))
+		if t.Resolved > 0 {
+			sb.WriteString(fmt.Sprintf(", решено %d", t.Resolved))
+			if t.AvgResolutionMinutes > 0 {
+				sb.WriteString(fmt.Sprintf(" (~%d мин)", t.AvgResolutionMinutes))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n💡 Застрял на одной из них? Нажми кнопку — покажу, кто поможет.")
+	return sb.String()
+}
+
+// pluralizeRequests returns the Russian form of "запрос" for n.
+func pluralizeRequests(n int) string {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return "запрос"
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return "запроса"
+	default:
+		return "запросов"
+	}
+}
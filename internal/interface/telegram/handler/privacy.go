@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PRIVACY HANDLER
+// Handles /privacy - what other students can see about you.
+// Each setting is a toggle button; the student always sees their own data,
+// and curators still see everyone.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// PrivacyHandler handles the /privacy command and its toggle buttons.
+type PrivacyHandler struct {
+	updatePrefsCmd *command.UpdatePreferencesHandler
+	studentRepo    student.Repository
+}
+
+// NewPrivacyHandler creates a new PrivacyHandler with dependencies.
+func NewPrivacyHandler(updatePrefsCmd *command.UpdatePreferencesHandler, studentRepo student.Repository) *PrivacyHandler {
+	return &PrivacyHandler{
+		updatePrefsCmd: updatePrefsCmd,
+		studentRepo:    studentRepo,
+	}
+}
+
+// PrivacyResponse contains the response to send back.
+type PrivacyResponse struct {
+	// Text is the message text (HTML formatted).
+	Text string
+
+	// Keyboard is the inline keyboard to attach.
+	Keyboard *presenter.InlineKeyboard
+
+	// ParseMode is the parse mode (HTML).
+	ParseMode string
+}
+
+// privacySettingNames are the setting names shown to students.
+var privacySettingNames = map[student.PrivacySetting]string{
+	student.PrivacyHideFromLeaderboard:  "Скрыть из рейтинга",
+	student.PrivacyHideFromHelperSearch: "Скрыть из поиска помощников",
+	student.PrivacyHideOnlineStatus:     "Скрыть онлайн-статус",
+}
+
+// privacySettingHints explain what each setting does when it is on.
+var privacySettingHints = map[student.PrivacySetting]string{
+	student.PrivacyHideFromLeaderboard:  "в /top и у соседей тебя не видно, своё место ты видишь в /me",
+	student.PrivacyHideFromHelperSearch: "тебя не предлагают как помощника",
+	student.PrivacyHideOnlineStatus:     "другие не видят, что ты онлайн",
+}
+
+// Handle shows the current privacy settings.
+func (h *PrivacyHandler) Handle(ctx context.Context, telegramID int64) (*PrivacyResponse, error) {
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(telegramID))
+	if err != nil {
+		return privacyText("❌ <b>Ты ещё не зарегистрирован</b>\n\nИспользуй /start чтобы присоединиться к сообществу."), nil
+	}
+
+	return h.showPrivacy(stud), nil
+}
+
+// Toggle flips one privacy setting and shows the updated view.
+func (h *PrivacyHandler) Toggle(ctx context.Context, telegramID int64, raw string) (*PrivacyResponse, error) {
+	setting, ok := student.ParsePrivacySetting(raw)
+	if !ok {
+		return privacyText("❌ Неизвестная настройка"), nil
+	}
+
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(telegramID))
+	if err != nil {
+		return privacyText("❌ <b>Ты ещё не зарегистрирован</b>\n\nИспользуй /start чтобы присоединиться к сообществу."), nil
+	}
+
+	hide := !stud.Preferences.Hidden(setting)
+	var updates command.PreferenceUpdates
+	switch setting {
+	case student.PrivacyHideFromLeaderboard:
+		updates.HideFromLeaderboard = &hide
+	case student.PrivacyHideFromHelperSearch:
+		updates.HideFromHelperSearch = &hide
+	case student.PrivacyHideOnlineStatus:
+		updates.HideOnlineStatus = &hide
+	}
+
+	result, err := h.updatePrefsCmd.Handle(ctx, command.UpdatePreferencesCommand{
+		StudentID:   stud.ID,
+		Preferences: updates,
+	})
+	if err != nil {
+		return privacyText("❌ Не удалось обновить настройки"), nil
+	}
+
+	invalidateStudentContext(ctx, telegramID)
+	stud.Preferences = result.UpdatedPreferences
+	return h.showPrivacy(stud), nil
+}
+
+// showPrivacy renders the settings with one toggle button per setting.
+func (h *PrivacyHandler) showPrivacy(stud *student.Student) *PrivacyResponse {
+	var sb strings.Builder
+	sb.WriteString("🔒 <b>Приватность</b>\n\n")
+
+	keyboard := presenter.NewInlineKeyboard()
+	for _, setting := range student.PrivacySettings() {
+		icon := "❌"
+		if stud.Preferences.Hidden(setting) {
+			icon = "✅"
+		}
+		sb.WriteString(fmt.Sprintf("%s %s\n   <i>%s</i>\n", icon, privacySettingNames[setting], privacySettingHints[setting]))
+		keyboard.AddRow(presenter.CallbackButton(
+			fmt.Sprintf("%s %s", icon, privacySettingNames[setting]),
+			"privacy:toggle:"+string(setting),
+		))
+	}
+	sb.WriteString("\n<i>Кураторы видят всех. Рейтинг обновляется в течение нескольких минут.</i>")
+
+	return &PrivacyResponse{
+		Text:      sb.String(),
+		Keyboard:  keyboard,
+		ParseMode: "HTML",
+	}
+}
+
+func privacyText(text string) *PrivacyResponse {
+	return &PrivacyResponse{Text: text, ParseMode: "HTML"}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+)
+
+type fakePrivacyStudents struct {
+	student.Repository
+	byTelegram map[student.TelegramID]*student.Student
+}
+
+func (f *fakePrivacyStudents) GetByTelegramID(_ context.Context, id student.TelegramID) (*student.Student, error) {
+	if s, ok := f.byTelegram[id]; ok {
+		return s, nil
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+type fakePrivacyLeaderboard struct {
+	leaderboard.LeaderboardRepository
+	entries []*leaderboard.LeaderboardEntry
+}
+
+// snapshot отдаёт копии: обработчики правят записи на месте.
+func (f *fakePrivacyLeaderboard) snapshot() []*leaderboard.LeaderboardEntry {
+	entries := make([]*leaderboard.LeaderboardEntry, len(f.entries))
+	for i, e := range f.entries {
+		entries[i] = e.Clone()
+	}
+	return entries
+}
+
+func (f *fakePrivacyLeaderboard) GetTop(_ context.Context, _ leaderboard.Cohort, limit int) ([]*leaderboard.LeaderboardEntry, error) {
+	entries := f.snapshot()
+	return entries[:min(limit, len(entries))], nil
+}
+
+func (f *fakePrivacyLeaderboard) GetTotalCount(context.Context, leaderboard.Cohort) (int, error) {
+	return len(f.entries), nil
+}
+
+func (f *fakePrivacyLeaderboard) GetStudentRank(_ context.Context, studentID string, _ leaderboard.Cohort) (*leaderboard.LeaderboardEntry, error) {
+	for _, e := range f.snapshot() {
+		if e.StudentID == studentID {
+			return e, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakePrivacyLeaderboard) GetNeighbors(context.Context, string, leaderboard.Cohort, int) ([]*leaderboard.LeaderboardEntry, error) {
+	return f.snapshot(), nil
+}
+
+func (f *fakePrivacyLeaderboard) GetBestRank(context.Context, string) (*leaderboard.RankHistoryEntry, error) {
+	return nil, nil
+}
+
+func TestPrivacy_HiddenStudentSeesOwnRankButNotListedForOthers(t *testing.T) {
+	dana := &student.Student{ID: "dana", TelegramID: 1, DisplayName: "Dana", CurrentXP: 900, Cohort: "2024"}
+	dana.Preferences = student.DefaultNotificationPreferences()
+	dana.Preferences.HideFromLeaderboard = true
+	arman := &student.Student{ID: "arman", TelegramID: 2, DisplayName: "Arman", CurrentXP: 500, Cohort: "2024"}
+	arman.Preferences = student.DefaultNotificationPreferences()
+
+	students := &fakePrivacyStudents{byTelegram: map[student.TelegramID]*student.Student{1: dana, 2: arman}}
+	board := &fakePrivacyLeaderboard{entries: []*leaderboard.LeaderboardEntry{
+		{Rank: 1, StudentID: "dana", DisplayName: "Dana", XP: 900, HiddenFromLeaderboard: true},
+		{Rank: 2, StudentID: "arman", DisplayName: "Arman", XP: 500},
+	}}
+	keyboards := presenter.NewKeyboardBuilder()
+
+	top := NewTopHandler(query.NewGetLeaderboardHandler(board, nil, nil), students, keyboards)
+	me := NewMeHandler(query.NewGetStudentRankHandler(students, board, nil, nil), nil, nil, students, keyboards, nil)
+
+	// Для других Дана в /top не показывается, Арман остаётся на своём месте
+	forArman, err := top.Handle(context.Background(), TopRequest{TelegramID: 2})
+	require.NoError(t, err)
+	assert.NotContains(t, forArman.Text, "Dana")
+	assert.Contains(t, forArman.Text, "Arman")
+
+	// Сама Дана видит себя и в /top, и своё место в /me
+	forDana, err := top.Handle(context.Background(), TopRequest{TelegramID: 1})
+	require.NoError(t, err)
+	assert.Contains(t, forDana.Text, "Dana")
+
+	card, err := me.Handle(context.Background(), MeRequest{TelegramID: 1})
+	require.NoError(t, err)
+	assert.Contains(t, card.Text, "Позиция: <b>#1</b>")
+	assert.Contains(t, card.Text, "/privacy")
+
+	// Арман в /me не видит имени скрытой соседки сверху
+	armanCard, err := me.Handle(context.Background(), MeRequest{TelegramID: 2})
+	require.NoError(t, err)
+	assert.Contains(t, armanCard.Text, "Позиция: <b>#2</b>")
+	assert.NotContains(t, armanCard.Text, "Dana")
+}
@@ -3,8 +3,7 @@ package handler
 
 import (
 	"context"
-	"fmt"
-	"strings"
+	"errors"
 
 	"github.com/alem-hub/alem-community-hub/internal/application/command"
 	"github.com/alem-hub/alem-community-hub/internal/domain/student"
@@ -22,22 +21,23 @@ import (
 type SettingsHandler struct {
 	updatePrefsCmd *command.UpdatePreferencesHandler
 	resetPrefsCmd  *command.ResetPreferencesHandler
-	studentRepo    student.Repository
-	keyboards      *presenter.KeyboardBuilder
+	students       *StudentLoader
+	codec          *presenter.CallbackCodec
 }
 
 // NewSettingsHandler creates a new SettingsHandler with dependencies.
+// codec signs the hub buttons (see SettingsHubPrefix).
 func NewSettingsHandler(
 	updatePrefsCmd *command.UpdatePreferencesHandler,
 	resetPrefsCmd *command.ResetPreferencesHandler,
 	studentRepo student.Repository,
-	keyboards *presenter.KeyboardBuilder,
+	codec *presenter.CallbackCodec,
 ) *SettingsHandler {
 	return &SettingsHandler{
 		updatePrefsCmd: updatePrefsCmd,
 		resetPrefsCmd:  resetPrefsCmd,
-		studentRepo:    studentRepo,
-		keyboards:      keyboards,
+		students:       newRepositoryLoader(studentRepo),
+		codec:          codec,
 	}
 }
 
@@ -69,26 +69,30 @@ type SettingsResponse struct {
 
 	// IsError indicates if this is an error response.
 	IsError bool
+
+	// QuickActions is set when the quick action keyboard was switched;
+	// the router then shows or removes it.
+	QuickActions *bool
 }
 
-// Handle processes the /settings command.
+// Handle processes the /settings command: shows the root of the hub.
 func (h *SettingsHandler) Handle(ctx context.Context, req SettingsRequest) (*SettingsResponse, error) {
 	// Get current student
-	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(req.TelegramID))
+	stud, err := h.students.Current(ctx, req.TelegramID).Student(ctx)
 	if err != nil {
-		return h.handleNotRegistered()
+		return h.studentError(err)
 	}
 
-	// Build settings view
-	text := h.buildSettingsView(stud)
-	keyboard := h.keyboards.SettingsKeyboard(stud)
+	return h.rootScreen(req.TelegramID, stud)
+}
 
-	return &SettingsResponse{
-		Text:      text,
-		Keyboard:  keyboard,
-		ParseMode: "HTML",
-		IsError:   false,
-	}, nil
+// studentError turns a failed student lookup into the response: a hint for
+// unregistered users, the error otherwise.
+func (h *SettingsHandler) studentError(err error) (*SettingsResponse, error) {
+	if errors.Is(err, ErrNotRegistered) {
+		return h.handleNotRegistered()
+	}
+	return nil, err
 }
 
 // handleNotRegistered handles the case when user is not registered.
@@ -103,70 +107,12 @@ func (h *SettingsHandler) handleNotRegistered() (*SettingsResponse, error) {
 	}, nil
 }
 
-// buildSettingsView builds the settings view text.
-func (h *SettingsHandler) buildSettingsView(stud *student.Student) string {
-	var sb strings.Builder
-
-	// Header
-	sb.WriteString("⚙️ <b>Настройки</b>\n\n")
-
-	// Profile info
-	sb.WriteString(fmt.Sprintf("👤 <b>%s</b>\n\n",
-		escapeHTML(stud.DisplayName)))
-
-	// Notifications section
-	sb.WriteString("🔔 <b>Уведомления</b>\n")
-
-	sb.WriteString(h.formatSettingLine("Изменения рейтинга", stud.Preferences.RankChanges))
-	sb.WriteString(h.formatSettingLine("Ежедневная сводка", stud.Preferences.DailyDigest))
-	sb.WriteString(h.formatSettingLine("Запросы помощи", stud.Preferences.HelpRequests))
-	sb.WriteString(h.formatSettingLine("Напоминания", stud.Preferences.InactivityReminders))
-
-	sb.WriteString("\n")
-
-	// Quiet hours
-	sb.WriteString("🌙 <b>Тихие часы</b>\n")
-	sb.WriteString(fmt.Sprintf("   %02d:00 — %02d:00\n\n",
-		stud.Preferences.QuietHoursStart,
-		stud.Preferences.QuietHoursEnd))
-
-	// Helper status
-	sb.WriteString("🤝 <b>Помощь другим</b>\n")
-	helperStatus := "Готов помогать"
-	helperEmoji := "✅"
-	if !stud.Preferences.HelpRequests {
-		helperStatus = "Не беспокоить"
-		helperEmoji = "⛔"
-	}
-	sb.WriteString(fmt.Sprintf("   %s %s\n\n", helperEmoji, helperStatus))
-
-	// Stats
-	if stud.HelpCount > 0 {
-		sb.WriteString("📊 <b>Статистика помощи</b>\n")
-		sb.WriteString(fmt.Sprintf("   Помог %d раз • Рейтинг: %.1f ⭐\n\n", stud.HelpCount, stud.HelpRating))
-	}
-
-	// Footer
-	sb.WriteString("<i>Используй кнопки ниже для изменения настроек.</i>")
-
-	return sb.String()
-}
-
-// formatSettingLine formats a single setting line.
-func (h *SettingsHandler) formatSettingLine(name string, enabled bool) string {
-	status := "✅"
-	if !enabled {
-		status = "❌"
-	}
-	return fmt.Sprintf("   %s %s\n", status, name)
-}
-
 // ToggleSetting handles toggling a specific setting.
 func (h *SettingsHandler) ToggleSetting(ctx context.Context, telegramID int64, setting string) (*SettingsResponse, error) {
 	// Get current student
-	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(telegramID))
+	stud, err := h.students.Current(ctx, telegramID).Student(ctx)
 	if err != nil {
-		return h.handleNotRegistered()
+		return h.studentError(err)
 	}
 
 	// Build update command based on setting
@@ -185,6 +131,15 @@ func (h *SettingsHandler) ToggleSetting(ctx context.Context, telegramID int64, s
 	case "inactivity_reminders":
 		newValue := !stud.Preferences.InactivityReminders
 		updates.InactivityReminders = &newValue
+	case "buddy_online":
+		newValue := !stud.Preferences.BuddyOnline
+		updates.BuddyOnline = &newValue
+	case "stuck_help_offers":
+		newValue := !stud.Preferences.StuckHelpOffers
+		updates.StuckHelpOffers = &newValue
+	case "quick_actions":
+		newValue := !stud.Preferences.QuickActions
+		updates.QuickActions = &newValue
 	default:
 		return &SettingsResponse{
 			Text:      "❌ Неизвестная настройка",
@@ -208,8 +163,15 @@ func (h *SettingsHandler) ToggleSetting(ctx context.Context, telegramID int64, s
 		}, nil
 	}
 
+	// The view below must show the new values
+	h.students.Current(ctx, telegramID).Invalidate()
+
 	// Refresh settings view
-	return h.Handle(ctx, SettingsRequest{TelegramID: telegramID})
+	resp, err := h.Handle(ctx, SettingsRequest{TelegramID: telegramID})
+	if err == nil && updates.QuickActions != nil {
+		resp.QuickActions = updates.QuickActions
+	}
+	return resp, err
 }
 
 // SetQuietHours handles setting quiet hours.
@@ -224,9 +186,9 @@ func (h *SettingsHandler) SetQuietHours(ctx context.Context, telegramID int64, s
 	}
 
 	// Get current student
-	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(telegramID))
+	stud, err := h.students.Current(ctx, telegramID).Student(ctx)
 	if err != nil {
-		return h.handleNotRegistered()
+		return h.studentError(err)
 	}
 
 	// Execute update
@@ -247,6 +209,9 @@ func (h *SettingsHandler) SetQuietHours(ctx context.Context, telegramID int64, s
 		}, nil
 	}
 
+	// The view below must show the new values
+	h.students.Current(ctx, telegramID).Invalidate()
+
 	// Refresh settings view
 	return h.Handle(ctx, SettingsRequest{TelegramID: telegramID})
 }
@@ -254,9 +219,9 @@ func (h *SettingsHandler) SetQuietHours(ctx context.Context, telegramID int64, s
 // ResetSettings handles resetting all settings to defaults.
 func (h *SettingsHandler) ResetSettings(ctx context.Context, telegramID int64) (*SettingsResponse, error) {
 	// Get current student
-	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(telegramID))
+	stud, err := h.students.Current(ctx, telegramID).Student(ctx)
 	if err != nil {
-		return h.handleNotRegistered()
+		return h.studentError(err)
 	}
 
 	// Execute reset
@@ -273,8 +238,14 @@ func (h *SettingsHandler) ResetSettings(ctx context.Context, telegramID int64) (
 		}, nil
 	}
 
+	// The view below must show the new values
+	h.students.Current(ctx, telegramID).Invalidate()
+
 	// Success message + refresh
-	result, _ := h.Handle(ctx, SettingsRequest{TelegramID: telegramID})
+	result, err := h.Handle(ctx, SettingsRequest{TelegramID: telegramID})
+	if err != nil {
+		return nil, err
+	}
 	result.Text = "✅ Настройки сброшены!\n\n" + result.Text
 
 	return result, nil
@@ -282,9 +253,9 @@ func (h *SettingsHandler) ResetSettings(ctx context.Context, telegramID int64) (
 
 // EnableAllNotifications enables all notifications.
 func (h *SettingsHandler) EnableAllNotifications(ctx context.Context, telegramID int64) (*SettingsResponse, error) {
-	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(telegramID))
+	stud, err := h.students.Current(ctx, telegramID).Student(ctx)
 	if err != nil {
-		return h.handleNotRegistered()
+		return h.studentError(err)
 	}
 
 	t := true
@@ -295,6 +266,8 @@ func (h *SettingsHandler) EnableAllNotifications(ctx context.Context, telegramID
 			DailyDigest:         &t,
 			HelpRequests:        &t,
 			InactivityReminders: &t,
+			BuddyOnline:         &t,
+			StuckHelpOffers:     &t,
 		},
 	}
 
@@ -307,7 +280,13 @@ func (h *SettingsHandler) EnableAllNotifications(ctx context.Context, telegramID
 		}, nil
 	}
 
-	result, _ := h.Handle(ctx, SettingsRequest{TelegramID: telegramID})
+	// The view below must show the new values
+	h.students.Current(ctx, telegramID).Invalidate()
+
+	result, err := h.Handle(ctx, SettingsRequest{TelegramID: telegramID})
+	if err != nil {
+		return nil, err
+	}
 	result.Text = "✅ Все уведомления включены!\n\n" + result.Text
 
 	return result, nil
@@ -315,9 +294,9 @@ func (h *SettingsHandler) EnableAllNotifications(ctx context.Context, telegramID
 
 // DisableAllNotifications disables all notifications.
 func (h *SettingsHandler) DisableAllNotifications(ctx context.Context, telegramID int64) (*SettingsResponse, error) {
-	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(telegramID))
+	stud, err := h.students.Current(ctx, telegramID).Student(ctx)
 	if err != nil {
-		return h.handleNotRegistered()
+		return h.studentError(err)
 	}
 
 	f := false
@@ -328,6 +307,8 @@ func (h *SettingsHandler) DisableAllNotifications(ctx context.Context, telegramI
 			DailyDigest:         &f,
 			HelpRequests:        &f,
 			InactivityReminders: &f,
+			BuddyOnline:         &f,
+			StuckHelpOffers:     &f,
 		},
 	}
 
@@ -340,7 +321,13 @@ func (h *SettingsHandler) DisableAllNotifications(ctx context.Context, telegramI
 		}, nil
 	}
 
-	result, _ := h.Handle(ctx, SettingsRequest{TelegramID: telegramID})
+	// The view below must show the new values
+	h.students.Current(ctx, telegramID).Invalidate()
+
+	result, err := h.Handle(ctx, SettingsRequest{TelegramID: telegramID})
+	if err != nil {
+		return nil, err
+	}
 	result.Text = "🔕 Все уведомления отключены!\n\n" + result.Text
 
 	return result, nil
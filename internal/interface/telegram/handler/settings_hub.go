@@ -0,0 +1,448 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// SETTINGS HUB
+// /settings opens a root menu of sections; each section edits its
+// preferences in place. Every screen replaces the same message, and the
+// current value of a preference is shown on its button.
+//
+// The menu is declared in settingsHub below: a new preference is one
+// settingsItem in its section.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// SettingsHubPrefix is the callback prefix of the hub buttons.
+// Payloads are signed for the user the menu was opened by.
+const SettingsHubPrefix = "st:"
+
+// Hub callback payloads (after the prefix, before the signature):
+//
+//	""                          root menu
+//	"s:<section>"               section
+//	"t:<section>:<item>"        flip a toggle
+//	"c:<section>:<item>"        list the choices of an item
+//	"v:<section>:<item>:<key>"  pick a choice
+const (
+	hubActionSection = "s"
+	hubActionToggle  = "t"
+	hubActionChoices = "c"
+	hubActionChoose  = "v"
+)
+
+// settingsSection is a root menu entry with its own submenu.
+type settingsSection struct {
+	key   string
+	title string
+	hint  string
+	items []settingsItem
+}
+
+// settingsItem is one editable preference. A toggle sets toggle; an item
+// with several values sets choices and current instead.
+type settingsItem struct {
+	key   string
+	label string
+
+	// value renders the current value for the button.
+	value func(p student.NotificationPreferences) string
+
+	// toggle returns the partial update that flips the preference.
+	toggle func(p student.NotificationPreferences) command.PreferenceUpdates
+
+	// choices are the values to pick from; current returns the key of the
+	// selected one.
+	choices []settingsChoice
+	current func(p student.NotificationPreferences) string
+}
+
+// settingsChoice is one value of a multiple-choice item.
+type settingsChoice struct {
+	key    string
+	label  string
+	update command.PreferenceUpdates
+}
+
+// settingsHub is the whole /settings menu.
+var settingsHub = []settingsSection{
+	{
+		key:   "n",
+		title: "🔔 Уведомления",
+		hint:  "Какие сообщения присылать. Отдельную категорию можно и временно заглушить через /mute.",
+		items: []settingsItem{
+			toggleItem("rk", "Изменения рейтинга",
+				func(p student.NotificationPreferences) bool { return p.RankChanges },
+				func(u *command.PreferenceUpdates, v bool) { u.RankChanges = &v }),
+			digestItem(),
+			toggleItem("hr", "Запросы помощи",
+				func(p student.NotificationPreferences) bool { return p.HelpRequests },
+				func(u *command.PreferenceUpdates, v bool) { u.HelpRequests = &v }),
+			toggleItem("ir", "Напоминания",
+				func(p student.NotificationPreferences) bool { return p.InactivityReminders },
+				func(u *command.PreferenceUpdates, v bool) { u.InactivityReminders = &v }),
+			toggleItem("bo", "Напарник онлайн",
+				func(p student.NotificationPreferences) bool { return p.BuddyOnline },
+				func(u *command.PreferenceUpdates, v bool) { u.BuddyOnline = &v }),
+			toggleItem("sh", "Помощь, если застрял",
+				func(p student.NotificationPreferences) bool { return p.StuckHelpOffers },
+				func(u *command.PreferenceUpdates, v bool) { u.StuckHelpOffers = &v }),
+		},
+	},
+	{
+		key:   "q",
+		title: "🌙 Тихие часы",
+		hint:  "В это время бот не присылает уведомления, они подождут до утра.",
+		items: []settingsItem{quietHoursItem()},
+	},
+	{
+		key:   "p",
+		title: "🔒 Приватность",
+		hint:  "Что о тебе видят другие студенты. Себя в рейтинге ты видишь всегда.",
+		items: []settingsItem{
+			toggleItem("lb", "Видно в рейтинге",
+				func(p student.NotificationPreferences) bool { return !p.HideFromLeaderboard },
+				func(u *command.PreferenceUpdates, v bool) { hide := !v; u.HideFromLeaderboard = &hide }),
+			toggleItem("hs", "Предлагать как помощника",
+				func(p student.NotificationPreferences) bool { return !p.HideFromHelperSearch },
+				func(u *command.PreferenceUpdates, v bool) { hide := !v; u.HideFromHelperSearch = &hide }),
+			toggleItem("os", "Показывать онлайн",
+				func(p student.NotificationPreferences) bool { return !p.HideOnlineStatus },
+				func(u *command.PreferenceUpdates, v bool) { hide := !v; u.HideOnlineStatus = &hide }),
+		},
+	},
+	{
+		key:   "o",
+		title: "🧩 Прочее",
+		hint:  "Интерфейс бота.",
+		items: []settingsItem{
+			toggleItem("qa", "Быстрые кнопки",
+				func(p student.NotificationPreferences) bool { return p.QuickActions },
+				func(u *command.PreferenceUpdates, v bool) { u.QuickActions = &v }),
+		},
+	},
+}
+
+// toggleItem declares an on/off preference.
+func toggleItem(
+	key, label string,
+	get func(p student.NotificationPreferences) bool,
+	set func(u *command.PreferenceUpdates, v bool),
+) settingsItem {
+	return settingsItem{
+		key:   key,
+		label: label,
+		value: func(p student.NotificationPreferences) string { return onOff(get(p)) },
+		toggle: func(p student.NotificationPreferences) command.PreferenceUpdates {
+			var u command.PreferenceUpdates
+			set(&u, !get(p))
+			return u
+		},
+	}
+}
+
+// digestHourChoices are the personal digest hours offered in the menu.
+var digestHourChoices = []int{18, 19, 20, 21, 22, 23}
+
+// digestItem declares the daily digest: off, the cohort's hour or a
+// personal hour.
+func digestItem() settingsItem {
+	off, on, cohortHour := false, true, command.DigestHourCohort
+	choices := []settingsChoice{
+		{key: "off", label: "❌ Не присылать", update: command.PreferenceUpdates{DailyDigest: &off}},
+		{key: "c", label: "Во время потока", update: command.PreferenceUpdates{DailyDigest: &on, DigestHour: &cohortHour}},
+	}
+	for _, hour := range digestHourChoices {
+		choices = append(choices, settingsChoice{
+			key:    fmt.Sprintf("h%d", hour),
+			label:  fmt.Sprintf("%02d:00", hour),
+			update: command.PreferenceUpdates{DailyDigest: &on, DigestHour: &hour},
+		})
+	}
+
+	return settingsItem{
+		key:   "dg",
+		label: "Дайджест",
+		value: func(p student.NotificationPreferences) string {
+			switch {
+			case !p.DailyDigest:
+				return onOff(false)
+			case p.DigestHour == nil:
+				return onOff(true) + " время потока"
+			default:
+				return fmt.Sprintf("%s %02d:00", onOff(true), *p.DigestHour)
+			}
+		},
+		choices: choices,
+		current: func(p student.NotificationPreferences) string {
+			switch {
+			case !p.DailyDigest:
+				return "off"
+			case p.DigestHour == nil:
+				return "c"
+			default:
+				return fmt.Sprintf("h%d", *p.DigestHour)
+			}
+		},
+	}
+}
+
+// quietHourPresets are the quiet hour windows offered in the menu;
+// equal start and end turn quiet hours off.
+var quietHourPresets = [][2]int{{22, 8}, {23, 8}, {23, 9}, {0, 8}, {1, 10}, {0, 0}}
+
+// quietHoursItem declares the quiet hours window.
+func quietHoursItem() settingsItem {
+	choices := make([]settingsChoice, 0, len(quietHourPresets))
+	for _, preset := range quietHourPresets {
+		start, end := preset[0], preset[1]
+		choices = append(choices, settingsChoice{
+			key:    fmt.Sprintf("%d-%d", start, end),
+			label:  formatQuietHours(start, end),
+			update: command.PreferenceUpdates{QuietHoursStart: &start, QuietHoursEnd: &end},
+		})
+	}
+
+	return settingsItem{
+		key:   "qh",
+		label: "Тихие часы",
+		value: func(p student.NotificationPreferences) string {
+			return formatQuietHours(p.QuietHoursStart, p.QuietHoursEnd)
+		},
+		choices: choices,
+		current: func(p student.NotificationPreferences) string {
+			return fmt.Sprintf("%d-%d", p.QuietHoursStart, p.QuietHoursEnd)
+		},
+	}
+}
+
+// formatQuietHours renders a quiet hours window.
+func formatQuietHours(start, end int) string {
+	if start == end {
+		return "🔔 выключены"
+	}
+	return fmt.Sprintf("%02d:00–%02d:00", start, end)
+}
+
+// onOff renders a boolean preference.
+func onOff(enabled bool) string {
+	if enabled {
+		return "✅"
+	}
+	return "❌"
+}
+
+// findSection returns the section with the key.
+func findSection(key string) (*settingsSection, bool) {
+	for i := range settingsHub {
+		if settingsHub[i].key == key {
+			return &settingsHub[i], true
+		}
+	}
+	return nil, false
+}
+
+// findItem returns the item with the key.
+func (s *settingsSection) findItem(key string) (*settingsItem, bool) {
+	for i := range s.items {
+		if s.items[i].key == key {
+			return &s.items[i], true
+		}
+	}
+	return nil, false
+}
+
+// findChoice returns the choice with the key.
+func (it *settingsItem) findChoice(key string) (*settingsChoice, bool) {
+	for i := range it.choices {
+		if it.choices[i].key == key {
+			return &it.choices[i], true
+		}
+	}
+	return nil, false
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Navigation
+// ─────────────────────────────────────────────────────────────────────────────
+
+// HandleHubCallback processes a hub button press: navigates or applies an
+// edit, and returns the screen to show in place of the menu message.
+func (h *SettingsHandler) HandleHubCallback(ctx context.Context, telegramID int64, data string) (*SettingsResponse, error) {
+	payload, err := h.codec.Decode(telegramID, SettingsHubPrefix, data)
+	if err != nil {
+		return &SettingsResponse{
+			Text:      "⚠️ Это меню устарело или открыто не тобой. Набери /settings, чтобы открыть своё.",
+			ParseMode: "HTML",
+			IsError:   true,
+		}, nil
+	}
+
+	stud, err := h.students.Current(ctx, telegramID).Student(ctx)
+	if err != nil {
+		return h.studentError(err)
+	}
+
+	parts := strings.Split(payload, ":")
+	if payload == "" || len(parts) < 2 {
+		return h.rootScreen(telegramID, stud)
+	}
+
+	section, ok := findSection(parts[1])
+	if !ok {
+		return h.rootScreen(telegramID, stud)
+	}
+	var item *settingsItem
+	if len(parts) >= 3 {
+		if item, ok = section.findItem(parts[2]); !ok {
+			return h.sectionScreen(telegramID, stud, section)
+		}
+	}
+
+	switch {
+	case parts[0] == hubActionToggle && item != nil && item.toggle != nil:
+		return h.applyHubUpdate(ctx, telegramID, stud, section, item.toggle(stud.Preferences))
+	case parts[0] == hubActionChoices && item != nil && len(item.choices) > 0:
+		return h.choicesScreen(telegramID, stud, section, item)
+	case parts[0] == hubActionChoose && item != nil && len(parts) == 4:
+		choice, ok := item.findChoice(parts[3])
+		if !ok {
+			return h.choicesScreen(telegramID, stud, section, item)
+		}
+		return h.applyHubUpdate(ctx, telegramID, stud, section, choice.update)
+	default:
+		return h.sectionScreen(telegramID, stud, section)
+	}
+}
+
+// applyHubUpdate saves a partial update and shows the section again.
+func (h *SettingsHandler) applyHubUpdate(
+	ctx context.Context,
+	telegramID int64,
+	stud *student.Student,
+	section *settingsSection,
+	updates command.PreferenceUpdates,
+) (*SettingsResponse, error) {
+	_, err := h.updatePrefsCmd.Handle(ctx, command.UpdatePreferencesCommand{
+		StudentID:   stud.ID,
+		Preferences: updates,
+	})
+	if err != nil {
+		return &SettingsResponse{
+			Text:      "❌ Не удалось обновить настройки",
+			ParseMode: "HTML",
+			IsError:   true,
+		}, nil
+	}
+
+	// The screen below must show the new values
+	h.students.Current(ctx, telegramID).Invalidate()
+	stud, err = h.students.Current(ctx, telegramID).Student(ctx)
+	if err != nil {
+		return h.studentError(err)
+	}
+
+	resp, err := h.sectionScreen(telegramID, stud, section)
+	if err == nil && updates.QuickActions != nil {
+		resp.QuickActions = updates.QuickActions
+	}
+	return resp, err
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Screens
+// ─────────────────────────────────────────────────────────────────────────────
+
+// rootScreen lists the sections.
+func (h *SettingsHandler) rootScreen(telegramID int64, stud *student.Student) (*SettingsResponse, error) {
+	var sb strings.Builder
+	sb.WriteString("⚙️ <b>Настройки</b>\n\n")
+	sb.WriteString(fmt.Sprintf("👤 <b>%s</b>\n\n", escapeHTML(stud.DisplayName)))
+	if stud.HelpCount > 0 {
+		sb.WriteString(fmt.Sprintf("🤝 Помог %d раз • Рейтинг: %.1f ⭐\n\n", stud.HelpCount, stud.HelpRating))
+	}
+	sb.WriteString("<i>Выбери раздел. Текущие значения видны прямо на кнопках.</i>")
+
+	kb := presenter.NewInlineKeyboard()
+	for i := range settingsHub {
+		button, err := h.hubButton(telegramID, settingsHub[i].title, hubActionSection, settingsHub[i].key)
+		if err != nil {
+			return nil, err
+		}
+		kb.AddRow(button)
+	}
+
+	return &SettingsResponse{Text: sb.String(), Keyboard: kb, ParseMode: "HTML"}, nil
+}
+
+// sectionScreen shows the items of a section with their current values.
+func (h *SettingsHandler) sectionScreen(telegramID int64, stud *student.Student, section *settingsSection) (*SettingsResponse, error) {
+	text := fmt.Sprintf("⚙️ Настройки › <b>%s</b>\n\n<i>%s</i>", section.title, section.hint)
+
+	kb := presenter.NewInlineKeyboard()
+	for i := range section.items {
+		item := &section.items[i]
+		action := hubActionToggle
+		if item.toggle == nil {
+			action = hubActionChoices
+		}
+		button, err := h.hubButton(telegramID, item.label+": "+item.value(stud.Preferences), action, section.key, item.key)
+		if err != nil {
+			return nil, err
+		}
+		kb.AddRow(button)
+	}
+
+	back, err := h.hubButton(telegramID, "← Назад")
+	if err != nil {
+		return nil, err
+	}
+	kb.AddRow(back)
+
+	return &SettingsResponse{Text: text, Keyboard: kb, ParseMode: "HTML"}, nil
+}
+
+// choicesScreen lists the values of a multiple-choice item.
+func (h *SettingsHandler) choicesScreen(telegramID int64, stud *student.Student, section *settingsSection, item *settingsItem) (*SettingsResponse, error) {
+	text := fmt.Sprintf("⚙️ Настройки › %s › <b>%s</b>\n\nСейчас: %s",
+		section.title, item.label, item.value(stud.Preferences))
+
+	current := item.current(stud.Preferences)
+	kb := presenter.NewInlineKeyboard()
+	for _, choice := range item.choices {
+		label := choice.label
+		if choice.key == current {
+			label = "• " + label + " •"
+		}
+		button, err := h.hubButton(telegramID, label, hubActionChoose, section.key, item.key, choice.key)
+		if err != nil {
+			return nil, err
+		}
+		kb.AddRow(button)
+	}
+
+	back, err := h.hubButton(telegramID, "← Назад", hubActionSection, section.key)
+	if err != nil {
+		return nil, err
+	}
+	kb.AddRow(back)
+
+	return &SettingsResponse{Text: text, Keyboard: kb, ParseMode: "HTML"}, nil
+}
+
+// hubButton builds a button with a signed hub payload; no parts lead to
+// the root menu.
+func (h *SettingsHandler) hubButton(telegramID int64, text string, parts ...string) (presenter.InlineButton, error) {
+	data, err := h.codec.Encode(telegramID, SettingsHubPrefix, strings.Join(parts, ":"))
+	if err != nil {
+		return presenter.InlineButton{}, fmt.Errorf("settings hub: %w", err)
+	}
+	return presenter.CallbackButton(text, data), nil
+}
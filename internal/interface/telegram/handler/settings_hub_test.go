@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+)
+
+type fakeSettingsStudents struct {
+	student.Repository
+	stud *student.Student
+}
+
+func (f *fakeSettingsStudents) GetByTelegramID(_ context.Context, id student.TelegramID) (*student.Student, error) {
+	if f.stud.TelegramID == id {
+		return f.stud, nil
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+func (f *fakeSettingsStudents) GetByID(_ context.Context, id string) (*student.Student, error) {
+	if f.stud.ID == id {
+		return f.stud, nil
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+func (f *fakeSettingsStudents) Update(_ context.Context, s *student.Student) error {
+	f.stud = s
+	return nil
+}
+
+func newSettingsHubFixture(t *testing.T) (*SettingsHandler, *fakeSettingsStudents) {
+	t.Helper()
+
+	stud := &student.Student{ID: "dana", TelegramID: 7, DisplayName: "Dana"}
+	stud.Preferences = student.DefaultNotificationPreferences()
+	repo := &fakeSettingsStudents{stud: stud}
+
+	h := NewSettingsHandler(
+		command.NewUpdatePreferencesHandler(repo, nil),
+		nil,
+		repo,
+		presenter.NewCallbackCodec([]byte("secret")),
+	)
+	return h, repo
+}
+
+// pressButton finds the button starting with label and presses it.
+func pressButton(t *testing.T, h *SettingsHandler, resp *SettingsResponse, label string) *SettingsResponse {
+	t.Helper()
+
+	require.NotNil(t, resp.Keyboard)
+	for _, row := range resp.Keyboard.Rows {
+		for _, button := range row {
+			if strings.HasPrefix(button.Text, label) {
+				next, err := h.HandleHubCallback(context.Background(), 7, button.CallbackData)
+				require.NoError(t, err)
+				require.False(t, next.IsError, next.Text)
+				return next
+			}
+		}
+	}
+	t.Fatalf("no button %q in %+v", label, resp.Keyboard.Rows)
+	return nil
+}
+
+func buttonTexts(resp *SettingsResponse) []string {
+	var texts []string
+	for _, row := range resp.Keyboard.Rows {
+		for _, button := range row {
+			texts = append(texts, button.Text)
+		}
+	}
+	return texts
+}
+
+func TestSettingsHub_ButtonsShowCurrentValues(t *testing.T) {
+	h, repo := newSettingsHubFixture(t)
+	hour := 21
+	repo.stud.Preferences.DigestHour = &hour
+	repo.stud.Preferences.HideFromLeaderboard = true
+
+	root, err := h.Handle(context.Background(), SettingsRequest{TelegramID: 7})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"🔔 Уведомления", "🌙 Тихие часы", "🔒 Приватность", "🧩 Прочее"}, buttonTexts(root))
+
+	notifications := pressButton(t, h, root, "🔔")
+	assert.Contains(t, buttonTexts(notifications), "Дайджест: ✅ 21:00")
+	assert.Contains(t, buttonTexts(notifications), "Изменения рейтинга: ✅")
+
+	privacy := pressButton(t, h, pressButton(t, h, notifications, "← Назад"), "🔒")
+	assert.Contains(t, buttonTexts(privacy), "Видно в рейтинге: ❌")
+	assert.Contains(t, buttonTexts(privacy), "Показывать онлайн: ✅")
+
+	for _, row := range privacy.Keyboard.Rows {
+		for _, button := range row {
+			assert.LessOrEqual(t, len(button.CallbackData), 64, button.Text)
+		}
+	}
+}
+
+func TestSettingsHub_NavigateAndEditInPlace(t *testing.T) {
+	h, repo := newSettingsHubFixture(t)
+
+	root, err := h.Handle(context.Background(), SettingsRequest{TelegramID: 7})
+	require.NoError(t, err)
+
+	section := pressButton(t, h, root, "🔔")
+	section = pressButton(t, h, section, "Изменения рейтинга")
+	assert.False(t, repo.stud.Preferences.RankChanges)
+	assert.Contains(t, buttonTexts(section), "Изменения рейтинга: ❌")
+
+	choices := pressButton(t, h, section, "Дайджест")
+	assert.Contains(t, buttonTexts(choices), "• Во время потока •")
+
+	section = pressButton(t, h, choices, "20:00")
+	require.NotNil(t, repo.stud.Preferences.DigestHour)
+	assert.Equal(t, 20, *repo.stud.Preferences.DigestHour)
+	assert.True(t, repo.stud.Preferences.DailyDigest)
+	assert.Contains(t, buttonTexts(section), "Дайджест: ✅ 20:00")
+
+	section = pressButton(t, h, pressButton(t, h, section, "Дайджест"), "Во время потока")
+	assert.Nil(t, repo.stud.Preferences.DigestHour)
+	assert.Contains(t, buttonTexts(section), "Дайджест: ✅ время потока")
+
+	root = pressButton(t, h, section, "← Назад")
+	assert.Contains(t, root.Text, "Настройки")
+	assert.Len(t, buttonTexts(root), 4)
+}
+
+func TestSettingsHub_QuickActionsToggleReportsSwitch(t *testing.T) {
+	h, repo := newSettingsHubFixture(t)
+
+	root, err := h.Handle(context.Background(), SettingsRequest{TelegramID: 7})
+	require.NoError(t, err)
+
+	resp := pressButton(t, h, pressButton(t, h, root, "🧩"), "Быстрые кнопки")
+	require.NotNil(t, resp.QuickActions)
+	assert.Equal(t, repo.stud.Preferences.QuickActions, *resp.QuickActions)
+}
+
+func TestSettingsHub_RejectsForeignButtons(t *testing.T) {
+	h, repo := newSettingsHubFixture(t)
+
+	root, err := h.Handle(context.Background(), SettingsRequest{TelegramID: 7})
+	require.NoError(t, err)
+	section := pressButton(t, h, root, "🔔")
+
+	data := section.Keyboard.Rows[0][0].CallbackData
+	resp, err := h.HandleHubCallback(context.Background(), 8, data)
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+
+	resp, err = h.HandleHubCallback(context.Background(), 7, "st:t:n:rk")
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+	assert.True(t, repo.stud.Preferences.RankChanges)
+}
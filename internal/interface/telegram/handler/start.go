@@ -46,6 +46,14 @@ var pendingOnboardings = struct {
 	data map[int64]*PendingOnboarding
 }{data: make(map[int64]*PendingOnboarding)}
 
+// ClearPendingOnboarding drops the onboarding session of a Telegram user,
+// e.g. after the account took over an existing profile with /relink.
+func ClearPendingOnboarding(telegramID int64) {
+	pendingOnboardings.Lock()
+	delete(pendingOnboardings.data, telegramID)
+	pendingOnboardings.Unlock()
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // START HANDLER
 // Handles /start command - the onboarding flow for new students.
@@ -110,6 +118,9 @@ type StartResponse struct {
 
 	// IsError indicates if this is an error response.
 	IsError bool
+
+	// QuickActions asks the router to attach the quick action keyboard.
+	QuickActions bool
 }
 
 // Handle processes the /start command.
@@ -140,10 +151,23 @@ func (h *StartHandler) handleExistingUser(ctx context.Context, stud *student.Stu
 			"🎯 <b>Уровень:</b> %d\n\n"+
 			"<b>Доступные команды:</b>\n"+
 			"• /me — твоя карточка\n"+
+			"• /sync_me — обновить свои данные сейчас\n"+
 			"• /top — лидерборд\n"+
 			"• /neighbors — соседи по рангу\n"+
+			"• /compare @логин — сравнить себя с другим студентом\n"+
 			"• /online — кто сейчас работает\n"+
 			"• /help — найти помощь по задаче\n"+
+			"• /populartasks — горячие задачи недели\n"+
+			"• /notifications — уведомления\n"+
+			"• /achievements — достижения\n"+
+			"• /goal — цель на неделю\n"+
+			"• /forecast — когда дойдёшь до цели\n"+
+			"• /focus — фокус-сессия на 50 минут\n"+
+			"• /event — челлендж сообщества\n"+
+			"• /feed — что нового в сообществе\n"+
+			"• /mute — режим тишины\n"+
+			"• /privacy — кто что видит\n"+
+			"• /token — токен API для своих дашбордов\n"+
 			"• /settings — настройки\n\n"+
 			"Удачи в обучении! 🚀",
 		stud.DisplayName,
@@ -154,10 +178,11 @@ func (h *StartHandler) handleExistingUser(ctx context.Context, stud *student.Stu
 	keyboard := h.keyboards.WelcomeBackKeyboard()
 
 	return &StartResponse{
-		Text:      text,
-		Keyboard:  keyboard,
-		ParseMode: "HTML",
-		IsError:   false,
+		Text:         text,
+		Keyboard:     keyboard,
+		ParseMode:    "HTML",
+		IsError:      false,
+		QuickActions: stud.Preferences.QuickActions,
 	}, nil
 }
 
@@ -184,7 +209,8 @@ func (h *StartHandler) handleAskForLogin(ctx context.Context, req StartRequest)
 			"Здесь ты можешь найти тех, кто решил задачу, на которой ты застрял, "+
 			"и помочь другим в ответ.\n\n"+
 			"📝 <b>Для регистрации введи email от alem.school:</b>\n"+
-			"Просто напиши его в чат (например: <code>student@alem.school</code>)",
+			"Просто напиши его в чат (например: <code>student@alem.school</code>)\n\n"+
+			"📱 Сменил Telegram аккаунт, а профиль остался на старом? Перенеси его: /relink",
 		greeting,
 	)
 
@@ -343,6 +369,14 @@ func (h *StartHandler) handleOnboardingSuccess(result *saga.OnboardingResult) (*
 			"• /neighbors — твои соседи по рангу\n"+
 			"• /online — кто сейчас работает\n"+
 			"• /help [задача] — найти того, кто решил задачу\n"+
+			"• /notifications — пропущенные уведомления\n"+
+			"• /achievements — достижения и цели в рейтинге\n"+
+			"• /goal 500 — личная цель по XP на неделю\n"+
+			"• /forecast rank 10 — когда дойдёшь до уровня, места или XP\n"+
+			"• /focus — 50 минут работы, можно позвать напарника из контактов\n"+
+			"• /event — лидерборд текущего челленджа\n"+
+			"• /mute 24h — временно заглушить уведомления\n"+
+			"• /privacy — скрыться из рейтинга или поиска помощников\n"+
 			"• /settings — настройки уведомлений\n\n"+
 			"<i>💡 Философия Hub: «От конкуренции к сотрудничеству».\n"+
 			"Здесь лидерборд — не про соревнование, а про поиск помощи.</i>\n\n"+
@@ -356,10 +390,11 @@ func (h *StartHandler) handleOnboardingSuccess(result *saga.OnboardingResult) (*
 	keyboard := h.keyboards.OnboardingSuccessKeyboard()
 
 	return &StartResponse{
-		Text:      text,
-		Keyboard:  keyboard,
-		ParseMode: "HTML",
-		IsError:   false,
+		Text:         text,
+		Keyboard:     keyboard,
+		ParseMode:    "HTML",
+		IsError:      false,
+		QuickActions: stud.Preferences.QuickActions,
 	}, nil
 }
 
@@ -488,7 +523,8 @@ func (h *StartHandler) handleAuthentication(ctx context.Context, req StartReques
 		return &StartResponse{
 			Text: fmt.Sprintf(
 				"⚠️ <b>Email уже используется</b>\n\n"+
-					"Email <code>%s</code> уже связан с другим аккаунтом.",
+					"Email <code>%s</code> уже связан с другим аккаунтом.\n\n"+
+					"Если это твой профиль и ты сменил Telegram аккаунт, перенеси его: /relink",
 				escapeHTML(email),
 			),
 			ParseMode: "HTML",
@@ -497,14 +533,9 @@ func (h *StartHandler) handleAuthentication(ctx context.Context, req StartReques
 	}
 
 	// Generate display name
-	displayName := login
-	if req.FirstName != "" {
-		displayName = req.FirstName
-		if req.LastName != "" {
-			displayName += " " + req.LastName
-		}
-	} else if req.TelegramUsername != "" {
-		displayName = req.TelegramUsername
+	displayName := student.TelegramDisplayName(req.FirstName, req.LastName, req.TelegramUsername)
+	if displayName == "" {
+		displayName = login
 	}
 
 	// Hash password (using simple SHA256 for now to avoid external deps issues if bcrypt not present,
@@ -554,8 +585,9 @@ func (h *StartHandler) handleAuthentication(ctx context.Context, req StartReques
 			escapeHTML(email),
 			escapeHTML(displayName),
 		),
-		ParseMode: "HTML",
-		IsError:   false,
+		ParseMode:    "HTML",
+		IsError:      false,
+		QuickActions: newStudent.Preferences.QuickActions,
 	}, nil
 }
 
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// STREAK HANDLER
+// Handles /streak command - the current series of active days and a calendar
+// of the last 30 days.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Calendar cells.
+const (
+	streakCellActive   = "🟩"
+	streakCellInactive = "⬜"
+	streakCellToday    = "🔥"
+	streakCellPadding  = "▫️"
+)
+
+// streakWeekdays is the calendar header; weeks start on Monday.
+const streakWeekdays = "Пн Вт Ср Чт Пт Сб Вс"
+
+// StreakHandler handles the /streak command.
+type StreakHandler struct {
+	students *StudentLoader
+	calendar *query.GetStreakCalendarHandler
+}
+
+// NewStreakHandler creates a new StreakHandler with dependencies.
+func NewStreakHandler(
+	studentRepo student.Repository,
+	calendar *query.GetStreakCalendarHandler,
+) *StreakHandler {
+	return &StreakHandler{
+		students: newRepositoryLoader(studentRepo),
+		calendar: calendar,
+	}
+}
+
+// StreakRequest contains the parsed /streak command data.
+type StreakRequest struct {
+	// TelegramID is the user's Telegram ID.
+	TelegramID int64
+
+	// ChatID is the chat ID for sending responses.
+	ChatID int64
+}
+
+// StreakResponse contains the response to send back.
+type StreakResponse struct {
+	// Text is the message text (HTML formatted).
+	Text string
+
+	// ParseMode is the parse mode (HTML).
+	ParseMode string
+}
+
+// Handle processes the /streak command.
+func (h *StreakHandler) Handle(ctx context.Context, req StreakRequest) (*StreakResponse, error) {
+	stud, err := h.students.Current(ctx, req.TelegramID).Student(ctx)
+	if errors.Is(err, ErrNotRegistered) {
+		return &StreakResponse{
+			Text:      "❌ <b>Ты ещё не зарегистрирован</b>\n\nИспользуй /start чтобы присоединиться к сообществу.",
+			ParseMode: "HTML",
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get student: %w", err)
+	}
+
+	calendar, err := h.calendar.Handle(ctx, query.GetStreakCalendarQuery{StudentID: stud.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get streak calendar: %w", err)
+	}
+
+	return &StreakResponse{
+		Text:      formatStreak(calendar),
+		ParseMode: "HTML",
+	}, nil
+}
+
+// formatStreak renders the streak card with the calendar.
+func formatStreak(c *query.GetStreakCalendarResult) string {
+	var sb strings.Builder
+	if c.CurrentStreak > 0 {
+		sb.WriteString(fmt.Sprintf("🔥 <b>Серия: %d дн.</b>\n", c.CurrentStreak))
+	} else {
+		sb.WriteString("🔥 <b>Серии пока нет</b>\n")
+	}
+	if c.BestStreak > 0 {
+		sb.WriteString(fmt.Sprintf("🏆 Лучшая серия: %d дн.\n", c.BestStreak))
+	}
+
+	sb.WriteString(fmt.Sprintf("\n<b>Последние %d дн.</b> · активных: %d\n", len(c.Days), c.ActiveDays))
+	sb.WriteString(formatStreakCalendar(c.Days))
+
+	switch {
+	case c.ActiveToday:
+		sb.WriteString("\n✅ Сегодня серия уже продлена.")
+	case c.AtRisk:
+		sb.WriteString(fmt.Sprintf("\n⚠️ Сегодня ещё не было активности — до конца дня %s. Реши задачу, чтобы не потерять серию.",
+			formatTimeLeft(c.TimeLeftToday)))
+	default:
+		sb.WriteString("\nРеши задачу сегодня, чтобы начать новую серию.")
+	}
+	return sb.String()
+}
+
+// formatStreakCalendar renders days as rows of weeks from Monday to Sunday.
+// Cells before the first day are padded so that columns match the header.
+func formatStreakCalendar(days []query.StreakCalendarDay) string {
+	if len(days) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(streakWeekdays + "\n")
+	// time.Weekday starts on Sunday
+	for i := 0; i < (int(days[0].Date.Weekday())+6)%7; i++ {
+		sb.WriteString(streakCellPadding)
+	}
+	for i, day := range days {
+		switch {
+		case day.Today && day.Active:
+			sb.WriteString(streakCellToday)
+		case day.Active:
+			sb.WriteString(streakCellActive)
+		default:
+			sb.WriteString(streakCellInactive)
+		}
+		if day.Date.Weekday() == time.Sunday || i == len(days)-1 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// formatTimeLeft renders the time until the end of the day in hours.
+func formatTimeLeft(d time.Duration) string {
+	if d < time.Hour {
+		return "меньше часа"
+	}
+	return fmt.Sprintf("%d ч", int(d.Hours()))
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+)
+
+// streakDays строит календарь до today включительно с активными днями active.
+func streakDays(today time.Time, active ...string) []query.StreakCalendarDay {
+	isActive := make(map[string]bool, len(active))
+	for _, d := range active {
+		isActive[d] = true
+	}
+	days := make([]query.StreakCalendarDay, 0, query.StreakCalendarDays)
+	for d := today.AddDate(0, 0, -(query.StreakCalendarDays - 1)); !d.After(today); d = d.AddDate(0, 0, 1) {
+		days = append(days, query.StreakCalendarDay{
+			Date:   d,
+			Active: isActive[d.Format(time.DateOnly)],
+			Today:  d.Equal(today),
+		})
+	}
+	return days
+}
+
+func TestFormatStreak_CalendarGroupedByWeeks(t *testing.T) {
+	// 18.09.2026 - пятница, 17.10.2026 - суббота
+	today := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	text := formatStreak(&query.GetStreakCalendarResult{
+		Days:          streakDays(today, "2026-09-19", "2026-10-15", "2026-10-16", "2026-10-17"),
+		ActiveDays:    4,
+		CurrentStreak: 3,
+		BestStreak:    8,
+		ActiveToday:   true,
+	})
+
+	assert.Contains(t, text, "Серия: 3 дн.")
+	assert.Contains(t, text, "Лучшая серия: 8 дн.")
+	assert.Contains(t, text, "активных: 4")
+	assert.Contains(t, text, "Сегодня серия уже продлена")
+
+	lines := strings.Split(text, "\n")
+	header := -1
+	for i, line := range lines {
+		if line == streakWeekdays {
+			header = i
+		}
+	}
+	require.NotEqual(t, -1, header)
+	// Первая неделя начинается с пятницы, последняя заканчивается субботой
+	assert.Equal(t, strings.Repeat(streakCellPadding, 4)+streakCellInactive+streakCellActive+streakCellInactive, lines[header+1])
+	assert.Equal(t, strings.Repeat(streakCellInactive, 3)+streakCellActive+streakCellActive+streakCellToday, lines[header+5])
+	assert.Empty(t, lines[header+6])
+}
+
+func TestFormatStreak_WarnsWhenTodayIsMissing(t *testing.T) {
+	today := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	text := formatStreak(&query.GetStreakCalendarResult{
+		Days:          streakDays(today, "2026-10-16", "2026-10-17"),
+		ActiveDays:    2,
+		CurrentStreak: 2,
+		BestStreak:    2,
+		AtRisk:        true,
+		TimeLeftToday: 3*time.Hour + 40*time.Minute,
+	})
+
+	assert.Contains(t, text, "до конца дня 3 ч. Реши задачу")
+	assert.Contains(t, text, strings.Repeat(streakCellInactive, 4)+streakCellActive+streakCellActive+streakCellInactive+"\n")
+
+	text = formatStreak(&query.GetStreakCalendarResult{
+		Days:          streakDays(today),
+		BestStreak:    5,
+		TimeLeftToday: 20 * time.Minute,
+	})
+	assert.Contains(t, text, "Серии пока нет")
+	assert.Contains(t, text, "Лучшая серия: 5 дн.")
+	assert.Contains(t, text, "чтобы начать новую серию")
+}
@@ -0,0 +1,246 @@
+// Package handler contains Telegram command handlers.
+package handler
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/cqrs"
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// STUDENT CONTEXT
+// Per-update view of the student who sent it. The dispatcher resolves the
+// student once and attaches a StudentContext to the handler context; rank,
+// streak and social profile are loaded on first use and then reused, so
+// middleware and handlers no longer fetch the same rows again.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ErrNotRegistered is returned for Telegram users without a student.
+var ErrNotRegistered = errors.New("telegram user is not registered")
+
+// IsNotRegistered reports whether err means the student doesn't exist.
+func IsNotRegistered(err error) bool {
+	return errors.Is(err, ErrNotRegistered) ||
+		errors.Is(err, student.ErrStudentNotFound) ||
+		shared.IsNotFound(err)
+}
+
+// StudentResolver finds the student behind a Telegram ID, usually through a
+// short-lived cache. Implemented by middleware.AuthMiddleware.
+type StudentResolver interface {
+	ResolveStudent(ctx context.Context, telegramID int64) (*student.Student, error)
+
+	// InvalidateCache drops the cached student after it was changed.
+	InvalidateCache(telegramID int64)
+}
+
+// repositoryResolver reads the student straight from the repository.
+type repositoryResolver struct {
+	repo student.Repository
+}
+
+func (r repositoryResolver) ResolveStudent(ctx context.Context, telegramID int64) (*student.Student, error) {
+	return r.repo.GetByTelegramID(ctx, student.TelegramID(telegramID))
+}
+
+func (repositoryResolver) InvalidateCache(int64) {}
+
+// StudentLoader builds StudentContexts. Sources left unset make the
+// matching accessor return nil.
+type StudentLoader struct {
+	resolver       StudentResolver
+	rankQuery      cqrs.Handler[query.GetStudentRankQuery, *query.GetStudentRankResult]
+	progressRepo   student.ProgressRepository
+	socialProfiles social.SocialProfileRepository
+}
+
+// NewStudentLoader creates a loader resolving students through resolver.
+func NewStudentLoader(resolver StudentResolver) *StudentLoader {
+	return &StudentLoader{resolver: resolver}
+}
+
+// newRepositoryLoader creates the loader a handler falls back to when the
+// dispatcher didn't attach a StudentContext.
+func newRepositoryLoader(repo student.Repository) *StudentLoader {
+	return NewStudentLoader(repositoryResolver{repo: repo})
+}
+
+// WithRankQuery enables StudentContext.Rank.
+func (l *StudentLoader) WithRankQuery(q cqrs.Handler[query.GetStudentRankQuery, *query.GetStudentRankResult]) *StudentLoader {
+	l.rankQuery = q
+	return l
+}
+
+// WithProgress enables StudentContext.Streak.
+func (l *StudentLoader) WithProgress(repo student.ProgressRepository) *StudentLoader {
+	l.progressRepo = repo
+	return l
+}
+
+// WithSocialProfiles enables StudentContext.SocialProfile.
+func (l *StudentLoader) WithSocialProfiles(repo social.SocialProfileRepository) *StudentLoader {
+	l.socialProfiles = repo
+	return l
+}
+
+// ForUpdate creates the context of one update. resolved is the student the
+// dispatcher already has (e.g. from auth), nil to resolve it on first use.
+func (l *StudentLoader) ForUpdate(telegramID int64, resolved *student.Student) *StudentContext {
+	sc := &StudentContext{telegramID: telegramID, loader: l}
+	if resolved != nil {
+		sc.student.set(resolved)
+	}
+	return sc
+}
+
+// Current returns the StudentContext attached for telegramID, or a fresh one
+// from this loader when there is none (or it belongs to someone else).
+func (l *StudentLoader) Current(ctx context.Context, telegramID int64) *StudentContext {
+	if sc := StudentContextFrom(ctx); sc != nil && sc.telegramID == telegramID {
+		return sc
+	}
+	return l.ForUpdate(telegramID, nil)
+}
+
+// studentContextKey is the context key of the StudentContext.
+type studentContextKey struct{}
+
+// ContextWithStudentContext attaches sc to the context.
+func ContextWithStudentContext(ctx context.Context, sc *StudentContext) context.Context {
+	return context.WithValue(ctx, studentContextKey{}, sc)
+}
+
+// StudentContextFrom returns the attached StudentContext, or nil.
+func StudentContextFrom(ctx context.Context) *StudentContext {
+	sc, _ := ctx.Value(studentContextKey{}).(*StudentContext)
+	return sc
+}
+
+// invalidateStudentContext drops the attached StudentContext of telegramID
+// (and the resolver's cached student) after a handler changed the student.
+func invalidateStudentContext(ctx context.Context, telegramID int64) {
+	if sc := StudentContextFrom(ctx); sc != nil && sc.telegramID == telegramID {
+		sc.Invalidate()
+	}
+}
+
+// StudentContext is the lazily loaded, memoized data of one update's student.
+// Safe for concurrent use.
+type StudentContext struct {
+	telegramID int64
+	loader     *StudentLoader
+
+	student lazy[*student.Student]
+	rank    lazy[*query.GetStudentRankResult]
+	streak  lazy[*student.Streak]
+	profile lazy[*social.SocialProfile]
+}
+
+// TelegramID returns the Telegram ID the context was built for.
+func (sc *StudentContext) TelegramID() int64 {
+	return sc.telegramID
+}
+
+// ForStudent returns a context for another student with the same sources,
+// e.g. for an admin viewing the bot as that student.
+func (sc *StudentContext) ForStudent(stud *student.Student) *StudentContext {
+	return sc.loader.ForUpdate(int64(stud.TelegramID), stud)
+}
+
+// Student returns the student, or ErrNotRegistered.
+func (sc *StudentContext) Student(ctx context.Context) (*student.Student, error) {
+	return sc.student.get(func() (*student.Student, error) {
+		stud, err := sc.loader.resolver.ResolveStudent(ctx, sc.telegramID)
+		if IsNotRegistered(err) || (err == nil && stud == nil) {
+			return nil, ErrNotRegistered
+		}
+		return stud, err
+	})
+}
+
+// Rank returns the student's position in the overall leaderboard.
+func (sc *StudentContext) Rank(ctx context.Context) (*query.GetStudentRankResult, error) {
+	return sc.rank.get(func() (*query.GetStudentRankResult, error) {
+		stud, err := sc.Student(ctx)
+		if err != nil || sc.loader.rankQuery == nil {
+			return nil, err
+		}
+		return sc.loader.rankQuery.Handle(ctx, query.GetStudentRankQuery{Student: stud})
+	})
+}
+
+// Streak returns the student's streak, nil if there is none yet.
+func (sc *StudentContext) Streak(ctx context.Context) (*student.Streak, error) {
+	return sc.streak.get(func() (*student.Streak, error) {
+		stud, err := sc.Student(ctx)
+		if err != nil || sc.loader.progressRepo == nil {
+			return nil, err
+		}
+		streak, err := sc.loader.progressRepo.GetStreak(ctx, stud.ID)
+		if streak != nil {
+			streak.WithLocation(stud.Preferences.Location())
+		}
+		return streak, err
+	})
+}
+
+// SocialProfile returns the student's social profile.
+func (sc *StudentContext) SocialProfile(ctx context.Context) (*social.SocialProfile, error) {
+	return sc.profile.get(func() (*social.SocialProfile, error) {
+		stud, err := sc.Student(ctx)
+		if err != nil || sc.loader.socialProfiles == nil {
+			return nil, err
+		}
+		return sc.loader.socialProfiles.GetByStudentID(ctx, social.StudentID(stud.ID))
+	})
+}
+
+// Invalidate forgets everything loaded so far, including the resolver's
+// cached student. Call it after changing the student.
+func (sc *StudentContext) Invalidate() {
+	sc.loader.resolver.InvalidateCache(sc.telegramID)
+	sc.student.reset()
+	sc.rank.reset()
+	sc.streak.reset()
+	sc.profile.reset()
+}
+
+// lazy memoizes the result of the first load, errors included.
+type lazy[T any] struct {
+	mu   sync.Mutex
+	done bool
+	val  T
+	err  error
+}
+
+func (l *lazy[T]) get(load func() (T, error)) (T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.done {
+		l.val, l.err = load()
+		l.done = true
+	}
+	return l.val, l.err
+}
+
+func (l *lazy[T]) set(val T) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.val, l.err, l.done = val, nil, true
+}
+
+func (l *lazy[T]) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var zero T
+	l.val, l.err, l.done = zero, nil, false
+}
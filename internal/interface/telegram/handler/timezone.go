@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+	"github.com/alem-hub/alem-community-hub/pkg/timeutil"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// TIMEZONE HANDLER
+// Handles /timezone - the student's own time zone. Quiet hours, the digest
+// hour and the day boundaries of the streak and daily progress follow it;
+// leaderboard periods stay in Almaty time for everyone.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// TimezoneHandler handles the /timezone command and its preset buttons.
+type TimezoneHandler struct {
+	updatePrefsCmd *command.UpdatePreferencesHandler
+	studentRepo    student.Repository
+	now            func() time.Time
+}
+
+// NewTimezoneHandler creates a new TimezoneHandler with dependencies.
+func NewTimezoneHandler(updatePrefsCmd *command.UpdatePreferencesHandler, studentRepo student.Repository) *TimezoneHandler {
+	return &TimezoneHandler{
+		updatePrefsCmd: updatePrefsCmd,
+		studentRepo:    studentRepo,
+		now:            time.Now,
+	}
+}
+
+// TimezoneResponse contains the response to send back.
+type TimezoneResponse struct {
+	// Text is the message text (HTML formatted).
+	Text string
+
+	// Keyboard is the inline keyboard to attach.
+	Keyboard *presenter.InlineKeyboard
+
+	// ParseMode is the parse mode (HTML).
+	ParseMode string
+}
+
+// Handle shows the current time zone, or sets the one given as an argument
+// (/timezone Europe/Berlin).
+func (h *TimezoneHandler) Handle(ctx context.Context, telegramID int64, args string) (*TimezoneResponse, error) {
+	if name := strings.TrimSpace(args); name != "" {
+		return h.Set(ctx, telegramID, name)
+	}
+
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(telegramID))
+	if err != nil {
+		return timezoneText("❌ <b>Ты ещё не зарегистрирован</b>\n\nИспользуй /start чтобы присоединиться к сообществу."), nil
+	}
+
+	return h.showTimezone(stud, ""), nil
+}
+
+// Set changes the student's time zone and shows the updated view.
+func (h *TimezoneHandler) Set(ctx context.Context, telegramID int64, name string) (*TimezoneResponse, error) {
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(telegramID))
+	if err != nil {
+		return timezoneText("❌ <b>Ты ещё не зарегистрирован</b>\n\nИспользуй /start чтобы присоединиться к сообществу."), nil
+	}
+
+	result, err := h.updatePrefsCmd.Handle(ctx, command.UpdatePreferencesCommand{
+		StudentID:   stud.ID,
+		Preferences: command.PreferenceUpdates{Timezone: &name},
+	})
+	if errors.Is(err, student.ErrInvalidTimezone) {
+		return timezoneText(fmt.Sprintf(
+			"❌ Не знаю часовой пояс «%s».\n\nВыбери из списка: /timezone — или укажи название IANA, например <code>/timezone Europe/Berlin</code>.",
+			escapeHTML(name),
+		)), nil
+	}
+	if err != nil {
+		return timezoneText("❌ Не удалось обновить часовой пояс"), nil
+	}
+
+	invalidateStudentContext(ctx, telegramID)
+	stud.Preferences = result.UpdatedPreferences
+	return h.showTimezone(stud, "✅ Часовой пояс сохранён.\n\n"), nil
+}
+
+// showTimezone renders the current zone with one button per preset.
+func (h *TimezoneHandler) showTimezone(stud *student.Student, notice string) *TimezoneResponse {
+	current := stud.Preferences.Timezone
+	if current == "" {
+		current = student.DefaultTimezone
+	}
+	localNow := h.now().In(stud.Preferences.Location())
+
+	var sb strings.Builder
+	sb.WriteString("🕰 <b>Часовой пояс</b>\n\n")
+	sb.WriteString(notice)
+	sb.WriteString(fmt.Sprintf("Сейчас: <b>%s</b>, у тебя %s\n\n", escapeHTML(current), localNow.Format("15:04, 02.01")))
+	sb.WriteString("<i>По нему считаются тихие часы, время сводки и границы дня для серии и дневного прогресса. Недельный и месячный рейтинг — по Алматы для всех.</i>\n\n")
+	sb.WriteString("Другой пояс: <code>/timezone Europe/Berlin</code>")
+
+	keyboard := presenter.NewInlineKeyboard()
+	for _, preset := range timeutil.TimezonePresets {
+		label := preset.Label
+		if preset.Name == current {
+			label = "✅ " + label
+		}
+		keyboard.AddRow(presenter.CallbackButton(label, "timezone:set:"+preset.Name))
+	}
+
+	return &TimezoneResponse{
+		Text:      sb.String(),
+		Keyboard:  keyboard,
+		ParseMode: "HTML",
+	}
+}
+
+// timezoneText builds a plain HTML response.
+func timezoneText(text string) *TimezoneResponse {
+	return &TimezoneResponse{Text: text, ParseMode: "HTML"}
+}
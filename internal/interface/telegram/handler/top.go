@@ -4,8 +4,13 @@ package handler
 import (
 	"context"
 	"fmt"
+	"html"
+	"strings"
 
+	"github.com/alem-hub/alem-community-hub/internal/application/cqrs"
 	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
 	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
 )
 
@@ -18,21 +23,31 @@ import (
 
 // TopHandler handles the /top command for showing leaderboard.
 type TopHandler struct {
-	leaderboardQuery *query.GetLeaderboardHandler
+	leaderboardQuery cqrs.Handler[query.GetLeaderboardQuery, *query.GetLeaderboardResult]
+	monthlyHelpers   cqrs.Handler[query.GetMonthlyHelpersQuery, *query.GetMonthlyHelpersResult]
+	students         *StudentLoader
 	keyboards        *presenter.KeyboardBuilder
 }
 
 // NewTopHandler creates a new TopHandler with dependencies.
 func NewTopHandler(
-	leaderboardQuery *query.GetLeaderboardHandler,
+	leaderboardQuery cqrs.Handler[query.GetLeaderboardQuery, *query.GetLeaderboardResult],
+	studentRepo student.Repository,
 	keyboards *presenter.KeyboardBuilder,
 ) *TopHandler {
 	return &TopHandler{
 		leaderboardQuery: leaderboardQuery,
+		students:         newRepositoryLoader(studentRepo),
 		keyboards:        keyboards,
 	}
 }
 
+// WithMonthlyHelpers enables the "Топ помощников месяца" view.
+func (h *TopHandler) WithMonthlyHelpers(q cqrs.Handler[query.GetMonthlyHelpersQuery, *query.GetMonthlyHelpersResult]) *TopHandler {
+	h.monthlyHelpers = q
+	return h
+}
+
 // TopRequest contains the parsed /top command data.
 type TopRequest struct {
 	// TelegramID is the user's Telegram ID.
@@ -50,6 +65,15 @@ type TopRequest struct {
 	// Limit is the number of entries to show.
 	Limit int
 
+	// Page is the 1-based page to show. Pages past the end show the last one.
+	Page int
+
+	// OnlyOnline shows only online students.
+	OnlyOnline bool
+
+	// Weekly ranks by XP gained this week (Monday 00:00 Almaty time).
+	Weekly bool
+
 	// IsRefresh indicates if this is a refresh request (from callback).
 	IsRefresh bool
 }
@@ -77,14 +101,21 @@ func (h *TopHandler) Handle(ctx context.Context, req TopRequest) (*TopResponse,
 		limit = 10
 	}
 
-	// Get leaderboard
-	leaderboardQuery := query.GetLeaderboardQuery{
-		Cohort: req.Cohort,
-		Limit:  limit,
-		Offset: 0,
+	page := req.Page
+	if page < 1 {
+		page = 1
 	}
+	viewerID := h.viewerID(ctx, req.TelegramID)
+
+	result, err := h.leaderboardPage(ctx, req, viewerID, page, limit)
 
-	result, err := h.leaderboardQuery.Handle(ctx, leaderboardQuery)
+	// The board may have shrunk since the buttons were sent: show the last page
+	if err == nil && len(result.Entries) == 0 && page > 1 {
+		if last := presenter.ClampPage(page, result.TotalCount, limit); last < page {
+			page = last
+			result, err = h.leaderboardPage(ctx, req, viewerID, page, limit)
+		}
+	}
 	if err != nil {
 		return &TopResponse{
 			Text:      "❌ Не удалось загрузить рейтинг. Попробуйте позже.",
@@ -94,59 +125,133 @@ func (h *TopHandler) Handle(ctx context.Context, req TopRequest) (*TopResponse,
 	}
 
 	// Build response text
-	text := h.formatLeaderboard(result, req.Cohort)
+	text := h.formatLeaderboard(result, req, page, limit)
 
 	return &TopResponse{
-		Text:      text,
-		Keyboard:  h.keyboards.LeaderboardKeyboard(0, result.HasMore, req.Cohort, false),
+		Text: text,
+		Keyboard: h.keyboards.LeaderboardKeyboard(presenter.TopPage{
+			Page:       page,
+			Cohort:     req.Cohort,
+			OnlyOnline: req.OnlyOnline,
+			Weekly:     req.Weekly,
+		}, result.HasMore),
 		ParseMode: "HTML",
 	}, nil
 }
 
+// leaderboardPage loads the given page of the leaderboard.
+func (h *TopHandler) leaderboardPage(ctx context.Context, req TopRequest, viewerID string, page, limit int) (*query.GetLeaderboardResult, error) {
+	period := leaderboard.PeriodAll
+	if req.Weekly {
+		period = leaderboard.PeriodWeek
+	}
+	return h.leaderboardQuery.Handle(ctx, query.GetLeaderboardQuery{
+		Cohort:     req.Cohort,
+		Period:     string(period),
+		Limit:      limit,
+		Offset:     (page - 1) * limit,
+		OnlyOnline: req.OnlyOnline,
+		ViewerID:   viewerID,
+	})
+}
+
+// viewerID returns the student ID of the user, so that a student hidden from
+// the leaderboard still sees themselves. Unregistered users see the public view.
+func (h *TopHandler) viewerID(ctx context.Context, telegramID int64) string {
+	stud, err := h.students.Current(ctx, telegramID).Student(ctx)
+	if err != nil {
+		return ""
+	}
+	return stud.ID
+}
+
 // formatLeaderboard formats the leaderboard for display.
-func (h *TopHandler) formatLeaderboard(result *query.GetLeaderboardResult, cohort string) string {
-	var text string
+func (h *TopHandler) formatLeaderboard(result *query.GetLeaderboardResult, req TopRequest, page, limit int) string {
+	opts := presenter.DefaultLeaderboardTableOptions()
 
 	// Header
-	if cohort != "" {
-		text = fmt.Sprintf("🏆 <b>Рейтинг - %s</b>\n\n", cohort)
-	} else {
-		text = "🏆 <b>Общий рейтинг</b>\n\n"
+	switch {
+	case req.Weekly && req.Cohort != "":
+		opts.Title = fmt.Sprintf("📅 <b>Рейтинг недели - %s</b>", html.EscapeString(req.Cohort))
+	case req.Weekly:
+		opts.Title = "📅 <b>Рейтинг недели</b>"
+	case req.Cohort != "":
+		opts.Title = fmt.Sprintf("🏆 <b>Рейтинг - %s</b>", html.EscapeString(req.Cohort))
+	default:
+		opts.Title = "🏆 <b>Общий рейтинг</b>"
 	}
 
-	// Entries
-	for _, entry := range result.Entries {
-		// Position emoji
-		posEmoji := h.getPositionEmoji(entry.Rank)
-
-		// Online indicator
-		onlineIndicator := ""
-		if entry.IsOnline {
-			onlineIndicator = " 🟢"
+	// Footer with the page and total count
+	if result.TotalCount > len(result.Entries) {
+		opts.Footer = fmt.Sprintf("\n<i>Страница %d из %d · всего %d студентов</i>",
+			page, presenter.LastPage(result.TotalCount, limit), result.TotalCount)
+	}
+	if req.Weekly {
+		opts.Footer += "\n<i>XP, набранный с понедельника (по времени Алматы)</i>"
+	}
+	for _, e := range result.Entries {
+		if e.XPReviewPending {
+			opts.Footer += "\n<i>" + presenter.XPReviewMarker + " - прирост XP проверяется куратором</i>"
+			break
 		}
+	}
 
-		text += fmt.Sprintf("%s <b>%s</b>%s\n", posEmoji, entry.DisplayName, onlineIndicator)
-		text += fmt.Sprintf("   ⚡ %d XP • 🎮 Ур. %d\n", entry.XP, entry.Level)
+	return presenter.RenderLeaderboardTable(result.Entries, opts)
+}
+
+// HandleMonthlyHelpers shows the month's most endorsed helpers with their
+// helper streaks.
+func (h *TopHandler) HandleMonthlyHelpers(ctx context.Context, req TopRequest) (*TopResponse, error) {
+	keyboard := h.keyboards.MonthlyHelpersKeyboard()
+	if h.monthlyHelpers == nil {
+		return &TopResponse{
+			Text:      "🤝 Топ помощников месяца пока недоступен.",
+			Keyboard:  keyboard,
+			ParseMode: "HTML",
+			IsError:   true,
+		}, nil
 	}
 
-	// Footer with total count
-	if result.TotalCount > len(result.Entries) {
-		text += fmt.Sprintf("\n<i>Показано %d из %d студентов</i>", len(result.Entries), result.TotalCount)
+	result, err := h.monthlyHelpers.Handle(ctx, query.GetMonthlyHelpersQuery{
+		Limit:    req.Limit,
+		ViewerID: h.viewerID(ctx, req.TelegramID),
+	})
+	if err != nil {
+		return &TopResponse{
+			Text:      "❌ Не удалось загрузить топ помощников. Попробуйте позже.",
+			Keyboard:  keyboard,
+			ParseMode: "HTML",
+			IsError:   true,
+		}, nil
 	}
 
-	return text
+	return &TopResponse{
+		Text:      formatMonthlyHelpers(result),
+		Keyboard:  keyboard,
+		ParseMode: "HTML",
+	}, nil
 }
 
-// getPositionEmoji returns an emoji for the position.
-func (h *TopHandler) getPositionEmoji(position int) string {
-	switch position {
-	case 1:
-		return "🥇"
-	case 2:
-		return "🥈"
-	case 3:
-		return "🥉"
-	default:
-		return fmt.Sprintf("%d.", position)
+// formatMonthlyHelpers formats the month's helper ranking.
+func formatMonthlyHelpers(result *query.GetMonthlyHelpersResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🤝 <b>Топ помощников месяца</b> — %s\n\n", html.EscapeString(result.Month)))
+
+	if len(result.Helpers) == 0 {
+		sb.WriteString("В этом месяце благодарностей ещё не было.\n")
+		sb.WriteString("<i>Помоги однокурснику через /help — и попади в топ!</i>")
+		return sb.String()
 	}
+
+	for _, helper := range result.Helpers {
+		sb.WriteString(fmt.Sprintf("%s <b>%s</b> — %d 🙏",
+			eventRankLabel(helper.Rank), html.EscapeString(helper.DisplayName), helper.Endorsements))
+		if helper.StreakWeeks > 0 {
+			sb.WriteString(" · 🤝 " + student.FormatHelperStreak(helper.StreakWeeks))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n<i>🙏 — благодарности с начала месяца</i>")
+
+	return sb.String()
 }
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+)
+
+func TestTop_PagesAndClampsPastTheEnd(t *testing.T) {
+	board := &fakePrivacyLeaderboard{}
+	for i := 1; i <= 25; i++ {
+		board.entries = append(board.entries, &leaderboard.LeaderboardEntry{
+			Rank: leaderboard.Rank(i), StudentID: fmt.Sprintf("s%d", i), DisplayName: fmt.Sprintf("Student%02d", i),
+			XP: leaderboard.XP(3000 - i*10),
+		})
+	}
+	top := NewTopHandler(query.NewGetLeaderboardHandler(board, nil, nil), &fakePrivacyStudents{}, presenter.NewKeyboardBuilder())
+	ctx := context.Background()
+
+	// Вторая страница: обе кнопки навигации
+	resp, err := top.Handle(ctx, TopRequest{Page: 2, Cohort: "2024-spring"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Student11")
+	assert.NotContains(t, resp.Text, "Student10")
+	assert.Contains(t, resp.Text, "Страница 2 из 3")
+	nav := resp.Keyboard.Rows[0]
+	require.Len(t, nav, 3)
+	assert.Equal(t, "top:page=1:cohort=2024-spring", nav[0].CallbackData)
+	assert.Equal(t, "top:page=3:cohort=2024-spring", nav[2].CallbackData)
+
+	// Страницы за концом рейтинга нет - показываем последнюю, без "Вперёд"
+	resp, err = top.Handle(ctx, TopRequest{Page: 7})
+	require.NoError(t, err)
+	assert.False(t, resp.IsError)
+	assert.Contains(t, resp.Text, "Student25")
+	assert.Contains(t, resp.Text, "Страница 3 из 3")
+	nav = resp.Keyboard.Rows[0]
+	require.Len(t, nav, 2)
+	assert.Equal(t, "◀️ Назад", nav[0].Text)
+	assert.Equal(t, "top:page=2", nav[0].CallbackData)
+}
@@ -0,0 +1,132 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// HELP INVITATIONS
+// The buttons under the invitation a helper gets for a help request:
+// "helpreq:accept:<id>" takes the request, "helpreq:decline:<id>" dismisses
+// the invitation and "helpreq:snooze:<id>" asks for it again in two hours
+// (command.SnoozeHelpRequestHandler; the worker sends the reminder).
+// ══════════════════════════════════════════════════════════════════════════════
+
+// helpInvitationCallbackPrefix is the callback prefix of the invitation buttons.
+const helpInvitationCallbackPrefix = "helpreq:"
+
+// HelpInvitationHandler handles the buttons of help invitations.
+type HelpInvitationHandler struct {
+	respondCmd  *command.RespondToHelpRequestHandler
+	snoozeCmd   *command.SnoozeHelpRequestHandler
+	studentRepo student.Repository
+	logger      *slog.Logger
+}
+
+// NewHelpInvitationHandler creates a new HelpInvitationHandler.
+func NewHelpInvitationHandler(
+	respondCmd *command.RespondToHelpRequestHandler,
+	snoozeCmd *command.SnoozeHelpRequestHandler,
+	studentRepo student.Repository,
+	logger *slog.Logger,
+) *HelpInvitationHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &HelpInvitationHandler{
+		respondCmd:  respondCmd,
+		snoozeCmd:   snoozeCmd,
+		studentRepo: studentRepo,
+		logger:      logger,
+	}
+}
+
+// HandleCallback handles "helpreq:<action>:<request id>".
+func (h *HelpInvitationHandler) HandleCallback(ctx context.Context, cbCtx CallbackContext) error {
+	action, requestID, ok := strings.Cut(strings.TrimPrefix(cbCtx.Data, helpInvitationCallbackPrefix), ":")
+	if !ok || requestID == "" {
+		return nil
+	}
+
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(cbCtx.TelegramID))
+	if err != nil {
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Ты не зарегистрирован. Используй /start", true)
+	}
+
+	switch action {
+	case "accept":
+		return h.accept(ctx, cbCtx, stud.ID, requestID)
+	case "decline":
+		_ = removeKeyboard(ctx, cbCtx)
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "Ок, в другой раз 👌", false)
+	case "snooze":
+		return h.snooze(ctx, cbCtx, stud.ID, requestID)
+	}
+	return nil
+}
+
+// accept takes the request for the helper.
+func (h *HelpInvitationHandler) accept(ctx context.Context, cbCtx CallbackContext, helperID, requestID string) error {
+	_, err := h.respondCmd.Handle(ctx, command.RespondToHelpRequestCommand{
+		RequestID: requestID,
+		HelperID:  helperID,
+		Kind:      command.HelpResponseAccept,
+	})
+	if err != nil {
+		if notice, ok := helpInvitationNotice(err); ok {
+			_ = removeKeyboard(ctx, cbCtx)
+			return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, notice, true)
+		}
+		h.logger.Warn("failed to accept help request", "request_id", requestID, "helper_id", helperID, "error", err)
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Не удалось, попробуй позже.", true)
+	}
+
+	_ = removeKeyboard(ctx, cbCtx)
+	return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "✅ Спасибо! Запрос твой — напиши студенту.", false)
+}
+
+// snooze postpones the invitation.
+func (h *HelpInvitationHandler) snooze(ctx context.Context, cbCtx CallbackContext, helperID, requestID string) error {
+	result, err := h.snoozeCmd.Handle(ctx, command.SnoozeHelpRequestCommand{
+		RequestID: requestID,
+		HelperID:  helperID,
+	})
+	if err != nil {
+		if notice, ok := helpInvitationNotice(err); ok {
+			_ = removeKeyboard(ctx, cbCtx)
+			return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, notice, true)
+		}
+		h.logger.Warn("failed to snooze help request", "request_id", requestID, "helper_id", helperID, "error", err)
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Не удалось, попробуй позже.", true)
+	}
+
+	notice := "⏰ Напомню через 2 часа."
+	if result.Remaining == 0 {
+		notice += " Больше этот запрос отложить не получится."
+	}
+	_ = removeKeyboard(ctx, cbCtx)
+	return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, notice, false)
+}
+
+// helpInvitationNotice explains why the invitation can no longer be answered.
+func helpInvitationNotice(err error) (string, bool) {
+	switch {
+	case errors.Is(err, social.ErrHelpRequestAlreadyClosed):
+		return "Этот запрос уже решили, спасибо!", true
+	case errors.Is(err, social.ErrHelpRequestAlreadyMatched):
+		return "Этим запросом уже занимаются.", true
+	case errors.Is(err, social.ErrHelpRequestSelfHelp):
+		return "Это твой собственный запрос.", true
+	case errors.Is(err, social.ErrHelpSnoozeLimit):
+		return "Этот запрос уже откладывался дважды.", true
+	}
+	return "", false
+}
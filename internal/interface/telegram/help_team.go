@@ -0,0 +1,203 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/application/cqrs"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// TEAM HELP REQUESTS
+// "/team <task> <login>..." opens one help request for the whole team and
+// invites the teammates by their Alem logins. Each teammate gets the
+// invitation with "helpteam:accept:<id>" and "helpteam:decline:<id>" buttons
+// (command.HelpTeamHandler); the rest of the team is told about the answer.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// helpTeamCallbackPrefix is the callback prefix of the team invitation buttons.
+const helpTeamCallbackPrefix = "helpteam:"
+
+// helpTeamUsage explains the /team arguments.
+const helpTeamUsage = "👥 <b>Командный запрос помощи</b>\n\n" +
+	"<code>/team &lt;задача&gt; &lt;логин&gt; [логин...]</code>\n\n" +
+	"Один запрос на всю команду: помощники получат одно приглашение, а не по одному от каждого."
+
+// TeamHelpHandler handles /team and the team invitation buttons.
+type TeamHelpHandler struct {
+	requestHelpCmd cqrs.Handler[command.RequestHelpCommand, *command.RequestHelpResult]
+	teamCmd        *command.HelpTeamHandler
+	studentRepo    student.Repository
+	logger         *slog.Logger
+}
+
+// NewTeamHelpHandler creates a new TeamHelpHandler.
+func NewTeamHelpHandler(
+	requestHelpCmd cqrs.Handler[command.RequestHelpCommand, *command.RequestHelpResult],
+	teamCmd *command.HelpTeamHandler,
+	studentRepo student.Repository,
+	logger *slog.Logger,
+) *TeamHelpHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &TeamHelpHandler{
+		requestHelpCmd: requestHelpCmd,
+		teamCmd:        teamCmd,
+		studentRepo:    studentRepo,
+		logger:         logger,
+	}
+}
+
+// Handle creates the team request and sends the invitations.
+func (h *TeamHelpHandler) Handle(ctx context.Context, cmdCtx CommandContext) error {
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(cmdCtx.TelegramID))
+	if err != nil {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, "❌ Ты не зарегистрирован. Используй /start")
+		return err
+	}
+
+	args := strings.Fields(cmdCtx.Args)
+	if len(args) < 2 {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, helpTeamUsage)
+		return err
+	}
+	taskID, logins := args[0], args[1:]
+
+	teammates := make([]*student.Student, 0, len(logins))
+	ids := make([]string, 0, len(logins))
+	for _, login := range logins {
+		mate, err := h.studentRepo.GetByAlemLogin(ctx, strings.TrimPrefix(login, "@"))
+		if err != nil {
+			_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID,
+				fmt.Sprintf("🤔 Не нашёл студента <code>%s</code>.", html.EscapeString(login)))
+			return err
+		}
+		teammates = append(teammates, mate)
+		ids = append(ids, mate.ID)
+	}
+
+	result, err := h.requestHelpCmd.Handle(ctx, command.RequestHelpCommand{
+		RequesterID:    stud.ID,
+		TaskID:         taskID,
+		NotifyHelpers:  true,
+		CoRequesterIDs: ids,
+	})
+	if err != nil {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, helpTeamErrorText(err))
+		return err
+	}
+	if result.Deduplicated {
+		// Teammates are invited only into a new request
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, fmt.Sprintf(
+			"🆘 У тебя уже есть открытый запрос по задаче <b>%s</b> — помощников уже позвали, новые приглашения не отправлены.",
+			html.EscapeString(taskID)))
+		return err
+	}
+
+	for _, mate := range teammates {
+		h.invite(ctx, cmdCtx.Client, stud, mate, taskID, result.RequestID)
+	}
+
+	_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, fmt.Sprintf(
+		"👥 Запрос по задаче <b>%s</b> создан, приглашения отправлены (%d). Помощников позвали: %d.",
+		html.EscapeString(taskID), len(teammates), result.NotifiedCount))
+	return err
+}
+
+// invite sends a teammate the invitation with the answer buttons.
+func (h *TeamHelpHandler) invite(ctx context.Context, client *telegram.Client, requester, mate *student.Student, taskID, requestID string) {
+	text := fmt.Sprintf("👥 <b>%s</b> зовёт тебя в общий запрос помощи по задаче <b>%s</b>.",
+		html.EscapeString(requester.DisplayName), html.EscapeString(taskID))
+	keyboard := [][]telegram.InlineKeyboardButton{{
+		{Text: "✅ Я в команде", CallbackData: helpTeamCallbackPrefix + "accept:" + requestID},
+		{Text: "❌ Не сейчас", CallbackData: helpTeamCallbackPrefix + "decline:" + requestID},
+	}}
+
+	if _, err := client.SendWithKeyboard(ctx, int64(mate.TelegramID), text, keyboard); err != nil {
+		h.logger.Warn("failed to send team invitation", "request_id", requestID, "student_id", mate.ID, "error", err)
+	}
+}
+
+// HandleCallback handles "helpteam:<action>:<request id>".
+func (h *TeamHelpHandler) HandleCallback(ctx context.Context, cbCtx CallbackContext) error {
+	action, requestID, ok := strings.Cut(strings.TrimPrefix(cbCtx.Data, helpTeamCallbackPrefix), ":")
+	if !ok || requestID == "" {
+		return nil
+	}
+
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(cbCtx.TelegramID))
+	if err != nil {
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Ты не зарегистрирован. Используй /start", true)
+	}
+
+	var result *command.HelpTeamResult
+	var notice, update string
+	switch action {
+	case "accept":
+		result, err = h.teamCmd.Accept(ctx, requestID, stud.ID)
+		notice, update = "✅ Ты в команде запроса.", "присоединился к запросу помощи"
+	case "decline":
+		result, err = h.teamCmd.Decline(ctx, requestID, stud.ID)
+		notice, update = "Ок, в другой раз 👌", "не присоединился к запросу помощи"
+	default:
+		return nil
+	}
+	if err != nil {
+		if text, ok := helpTeamNotice(err); ok {
+			_ = removeKeyboard(ctx, cbCtx)
+			return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, text, true)
+		}
+		h.logger.Warn("failed to answer team invitation", "request_id", requestID, "student_id", stud.ID, "error", err)
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Не удалось, попробуй позже.", true)
+	}
+
+	text := fmt.Sprintf("👥 <b>%s</b> %s по задаче <b>%s</b>.",
+		html.EscapeString(result.Student.DisplayName), update, html.EscapeString(result.Request.TaskName))
+	for _, p := range result.Participants {
+		if _, err := cbCtx.Client.SendHTML(ctx, int64(p.TelegramID), text); err != nil {
+			h.logger.Warn("failed to tell the team", "request_id", requestID, "student_id", p.ID, "error", err)
+		}
+	}
+
+	_ = removeKeyboard(ctx, cbCtx)
+	return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, notice, false)
+}
+
+// helpTeamErrorText explains why the team request couldn't be created.
+func helpTeamErrorText(err error) string {
+	var limit *social.HelpRequestLimitError
+	switch {
+	case errors.As(err, &limit):
+		return fmt.Sprintf("✋ Одновременно можно держать открытыми не больше %d запросов помощи — закрой один из них.", limit.Limit)
+	case errors.Is(err, social.ErrHelpRequestAlreadyMember):
+		return "👥 Ты уже в командном запросе по этой задаче — помощников позвали, жди ответа."
+	case errors.Is(err, social.ErrHelpRequestSelfMember):
+		return "🤔 Себя приглашать не нужно — ты уже в запросе."
+	case errors.Is(err, social.ErrHelpTeamFull):
+		return fmt.Sprintf("👥 В запрос можно позвать не больше %d однокомандников.", social.MaxHelpTeamSize)
+	}
+	return "❌ Не удалось создать запрос, попробуй позже."
+}
+
+// helpTeamNotice explains why the invitation can no longer be answered.
+func helpTeamNotice(err error) (string, bool) {
+	switch {
+	case errors.Is(err, social.ErrHelpRequestAlreadyClosed):
+		return "Этот запрос уже закрыт.", true
+	case errors.Is(err, social.ErrHelpRequestAlreadyMember):
+		return "Ты уже в команде этого запроса.", true
+	case errors.Is(err, social.ErrHelpRequestNotInvited):
+		return "Приглашение больше не действует.", true
+	}
+	return "", false
+}
@@ -0,0 +1,176 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/handler"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/middleware"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// IMPERSONATE VIEW
+// Admin-only "/as <student> <command>" passthrough for debugging what a student
+// sees. Works at dispatch level: the target's identity is substituted into the
+// command context and the regular handler renders the output to the admin.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// impersonationContextKey marks a context as an impersonated view.
+type impersonationContextKey struct{}
+
+// impersonationView carries the watermark for impersonated responses.
+type impersonationView struct {
+	watermark string
+}
+
+// contextWithImpersonation marks the context as an impersonated view.
+func contextWithImpersonation(ctx context.Context, view impersonationView) context.Context {
+	return context.WithValue(ctx, impersonationContextKey{}, view)
+}
+
+// impersonationFromContext returns the impersonated view, if any.
+func impersonationFromContext(ctx context.Context) (impersonationView, bool) {
+	view, ok := ctx.Value(impersonationContextKey{}).(impersonationView)
+	return view, ok
+}
+
+// DefaultImpersonationCommands maps the read-only commands allowed via /as
+// to the registered command that renders them.
+func DefaultImpersonationCommands() map[string]string {
+	return map[string]string{
+		"me":      "me",
+		"top":     "top",
+		"streak":  "streak",
+		"helpers": "help",
+	}
+}
+
+// ImpersonationHandler handles the admin-only /as command.
+type ImpersonationHandler struct {
+	router      *Router
+	studentRepo student.Repository
+	auditLog    shared.AuditLog
+	allowed     map[string]string
+	logger      *slog.Logger
+}
+
+// NewImpersonationHandler creates a new ImpersonationHandler.
+// Register it behind Router.AdminOnly.
+func NewImpersonationHandler(
+	router *Router,
+	studentRepo student.Repository,
+	auditLog shared.AuditLog,
+	logger *slog.Logger,
+) *ImpersonationHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &ImpersonationHandler{
+		router:      router,
+		studentRepo: studentRepo,
+		auditLog:    auditLog,
+		allowed:     DefaultImpersonationCommands(),
+		logger:      logger,
+	}
+}
+
+// Handle processes "/as <student> <command> [args]".
+func (h *ImpersonationHandler) Handle(ctx context.Context, cmdCtx CommandContext) error {
+	fields := strings.Fields(cmdCtx.Args)
+	if len(fields) < 2 {
+		return h.reply(ctx, cmdCtx, "ℹ️ Использование: <code>/as &lt;студент&gt; &lt;команда&gt;</code>\n\n"+
+			"Студент: Telegram ID, email или ID.\n"+
+			"Команды: /me, /top, /streak, /helpers")
+	}
+
+	targetRef := fields[0]
+	requested := strings.TrimPrefix(strings.ToLower(fields[1]), "/")
+	args := strings.Join(fields[2:], " ")
+
+	command, ok := h.allowed[requested]
+	if !ok {
+		h.audit(ctx, cmdCtx.TelegramID, targetRef, requested, false, "command not allowed")
+		return h.reply(ctx, cmdCtx, fmt.Sprintf(
+			"⛔ Команда /%s недоступна в режиме просмотра.\nРазрешены только команды без побочных эффектов.",
+			html.EscapeString(requested)))
+	}
+
+	target, err := resolveStudentRef(ctx, h.studentRepo, targetRef)
+	if err != nil || target == nil {
+		h.audit(ctx, cmdCtx.TelegramID, targetRef, requested, false, "student not found")
+		return h.reply(ctx, cmdCtx, "❌ Студент не найден: <code>"+html.EscapeString(targetRef)+"</code>")
+	}
+
+	h.audit(ctx, cmdCtx.TelegramID, target.ID, requested, true, "")
+
+	// Substitute the resolved student; responses still go to the admin's chat
+	ctx = middleware.ContextWithStudent(ctx, target)
+	if sc := handler.StudentContextFrom(ctx); sc != nil {
+		ctx = handler.ContextWithStudentContext(ctx, sc.ForStudent(target))
+	}
+	ctx = contextWithImpersonation(ctx, impersonationView{
+		watermark: fmt.Sprintf("👁 <i>просмотр от имени %s</i>", html.EscapeString(target.DisplayName)),
+	})
+
+	return h.router.HandleCommand(ctx, command, CommandContext{
+		TelegramID: int64(target.TelegramID),
+		ChatID:     cmdCtx.ChatID,
+		MessageID:  cmdCtx.MessageID,
+		Args:       args,
+		Client:     cmdCtx.Client,
+	})
+}
+
+// resolveStudentRef finds a student by Telegram ID, email or internal ID.
+// Shared by admin commands that take a student reference.
+func resolveStudentRef(ctx context.Context, repo student.Repository, ref string) (*student.Student, error) {
+	if _, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		id, err := student.ParseTelegramID(ref)
+		if err != nil {
+			return nil, err
+		}
+		return repo.GetByTelegramID(ctx, id)
+	}
+	if strings.Contains(ref, "@") {
+		return repo.GetByEmail(ctx, ref)
+	}
+	return repo.GetByID(ctx, ref)
+}
+
+// audit writes an impersonation attempt to the audit log.
+func (h *ImpersonationHandler) audit(ctx context.Context, adminID int64, target, command string, allowed bool, reason string) {
+	if h.auditLog == nil {
+		return
+	}
+
+	details := map[string]interface{}{
+		"command": command,
+		"allowed": allowed,
+	}
+	if reason != "" {
+		details["reason"] = reason
+	}
+
+	entry := shared.NewAuditEntry(strconv.FormatInt(adminID, 10), shared.AuditActionImpersonateView, target, details)
+	if err := h.auditLog.Record(ctx, entry); err != nil {
+		h.logger.Error("failed to write audit entry",
+			"admin_id", student.TelegramID(adminID),
+			"target", target,
+			"command", command,
+			"error", err,
+		)
+	}
+}
+
+// reply sends a plain HTML message to the admin.
+func (h *ImpersonationHandler) reply(ctx context.Context, cmdCtx CommandContext, text string) error {
+	_, err := cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, text)
+	return err
+}
@@ -0,0 +1,167 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/middleware"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+)
+
+const testAdminID int64 = 1000
+
+type fakeStudentRepo struct {
+	student.Repository
+	byTelegramID map[student.TelegramID]*student.Student
+}
+
+func (f *fakeStudentRepo) GetByTelegramID(_ context.Context, id student.TelegramID) (*student.Student, error) {
+	if s, ok := f.byTelegramID[id]; ok {
+		return s, nil
+	}
+	return nil, shared.ErrNotFound
+}
+
+type fakeAuditLog struct {
+	entries []shared.AuditEntry
+}
+
+func (f *fakeAuditLog) Record(_ context.Context, entry shared.AuditEntry) error {
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+// recordingCommand renders a card for whoever the command context says it is.
+type recordingCommand struct {
+	router   *Router
+	executed []CommandContext
+	students []*student.Student
+}
+
+func (c *recordingCommand) Handle(ctx context.Context, cmdCtx CommandContext) error {
+	c.executed = append(c.executed, cmdCtx)
+	c.students = append(c.students, middleware.StudentFromContext(ctx))
+	kb := &presenter.InlineKeyboard{Rows: [][]presenter.InlineButton{{{Text: "🔄", CallbackData: "refresh:me"}}}}
+	return c.router.sendResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, "card", "HTML", kb)
+}
+
+// sentMessages captures sendMessage calls made against a fake Telegram API.
+type sentMessages struct {
+	mu       sync.Mutex
+	messages []map[string]interface{}
+}
+
+func newFakeTelegram(t *testing.T) (*telegram.Client, *sentMessages) {
+	sent := &sentMessages{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		sent.mu.Lock()
+		sent.messages = append(sent.messages, body)
+		sent.mu.Unlock()
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1,"chat":{"id":1}}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := telegram.DefaultClientConfig("test")
+	cfg.BaseURL = srv.URL
+	cfg.RetryAttempts = 0
+	return telegram.NewClient(cfg), sent
+}
+
+func setupImpersonation(t *testing.T) (*Router, *ImpersonationHandler, *recordingCommand, *fakeAuditLog, *telegram.Client, *sentMessages) {
+	router := NewRouter(RouterConfig{})
+	me := &recordingCommand{router: router}
+	router.RegisterCommand("me", me)
+	router.RegisterCommand("settings", me)
+
+	repo := &fakeStudentRepo{byTelegramID: map[student.TelegramID]*student.Student{
+		42: {ID: "student-42", TelegramID: 42, DisplayName: "Айдана"},
+	}}
+	audit := &fakeAuditLog{}
+	h := NewImpersonationHandler(router, repo, audit, nil)
+	router.RegisterCommand("as", h, router.AdminOnly([]int64{testAdminID}))
+	client, sent := newFakeTelegram(t)
+	return router, h, me, audit, client, sent
+}
+
+func TestImpersonationHandler_RunsWhitelistedCommandAsStudent(t *testing.T) {
+	_, h, me, audit, client, sent := setupImpersonation(t)
+
+	err := h.Handle(context.Background(), CommandContext{
+		TelegramID: testAdminID,
+		ChatID:     testAdminID,
+		Args:       "42 /me",
+		Client:     client,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, me.executed, 1)
+	assert.Equal(t, int64(42), me.executed[0].TelegramID)
+	assert.Equal(t, testAdminID, me.executed[0].ChatID, "output goes to the admin")
+	require.NotNil(t, me.students[0])
+	assert.Equal(t, "student-42", me.students[0].ID)
+
+	require.Len(t, sent.messages, 1)
+	assert.Contains(t, sent.messages[0]["text"], "👁 <i>просмотр от имени Айдана</i>")
+	assert.NotContains(t, sent.messages[0], "reply_markup")
+
+	require.Len(t, audit.entries, 1)
+	entry := audit.entries[0]
+	assert.Equal(t, "1000", entry.ActorID)
+	assert.Equal(t, shared.AuditActionImpersonateView, entry.Action)
+	assert.Equal(t, "student-42", entry.TargetID)
+	assert.Equal(t, "me", entry.Details["command"])
+	assert.Equal(t, true, entry.Details["allowed"])
+}
+
+func TestImpersonationHandler_RejectsCommandsWithSideEffects(t *testing.T) {
+	for _, cmd := range []string{"settings", "start", "as", "connect"} {
+		t.Run(cmd, func(t *testing.T) {
+			_, h, me, audit, client, sent := setupImpersonation(t)
+
+			err := h.Handle(context.Background(), CommandContext{
+				TelegramID: testAdminID,
+				ChatID:     testAdminID,
+				Args:       "42 /" + cmd,
+				Client:     client,
+			})
+			require.NoError(t, err)
+
+			assert.Empty(t, me.executed)
+			require.Len(t, sent.messages, 1)
+			assert.Contains(t, sent.messages[0]["text"], "недоступна")
+
+			require.Len(t, audit.entries, 1)
+			assert.Equal(t, cmd, audit.entries[0].Details["command"])
+			assert.Equal(t, false, audit.entries[0].Details["allowed"])
+		})
+	}
+}
+
+func TestImpersonationHandler_IgnoresNonAdmins(t *testing.T) {
+	router, _, me, audit, client, sent := setupImpersonation(t)
+
+	err := router.HandleCommand(context.Background(), "as", CommandContext{
+		TelegramID: 42,
+		ChatID:     42,
+		Args:       "42 /me",
+		Client:     client,
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, me.executed)
+	assert.Empty(t, audit.entries)
+	require.Len(t, sent.messages, 1)
+	assert.Contains(t, sent.messages[0]["text"], "Неизвестная команда")
+}
@@ -0,0 +1,183 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MERGE STUDENTS
+// Admin-only "/merge <keep> <duplicate> [confirm]" for duplicate accounts.
+// Without "confirm" the merge runs in a rolled back transaction and shows
+// what would be moved, so the admin can double-check the direction.
+// Without arguments it also lists pairs flagged by sync, where the Alem
+// login and the email belong to different students.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// mergeConfirmArg confirms a merge after the preview.
+const mergeConfirmArg = "confirm"
+
+// mergeConflictsLimit caps the flagged pairs shown by a bare /merge.
+const mergeConflictsLimit = 10
+
+// MergeStudentsHandler handles the admin-only /merge command.
+type MergeStudentsHandler struct {
+	studentRepo student.Repository
+	mergeCmd    *command.MergeStudentsHandler
+	conflicts   student.IdentityConflictRepository
+	logger      *slog.Logger
+}
+
+// NewMergeStudentsHandler creates a new MergeStudentsHandler.
+// Register it behind Router.AdminOnly.
+func NewMergeStudentsHandler(
+	studentRepo student.Repository,
+	mergeCmd *command.MergeStudentsHandler,
+	logger *slog.Logger,
+) *MergeStudentsHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &MergeStudentsHandler{
+		studentRepo: studentRepo,
+		mergeCmd:    mergeCmd,
+		logger:      logger,
+	}
+}
+
+// WithIdentityConflicts lists pairs flagged by sync under the usage hint.
+func (h *MergeStudentsHandler) WithIdentityConflicts(conflicts student.IdentityConflictRepository) *MergeStudentsHandler {
+	h.conflicts = conflicts
+	return h
+}
+
+// Handle processes "/merge <keep> <duplicate> [confirm]".
+func (h *MergeStudentsHandler) Handle(ctx context.Context, cmdCtx CommandContext) error {
+	fields := strings.Fields(cmdCtx.Args)
+	if len(fields) < 2 || len(fields) > 3 || (len(fields) == 3 && fields[2] != mergeConfirmArg) {
+		return h.reply(ctx, cmdCtx, "ℹ️ Использование: <code>/merge &lt;оставить&gt; &lt;дубликат&gt; [confirm]</code>\n\n"+
+			"Студент: Telegram ID, email или ID.\n"+
+			"Без <code>confirm</code> покажет, что будет перенесено."+
+			h.formatConflicts(ctx))
+	}
+	confirmed := len(fields) == 3
+
+	into, err := resolveStudentRef(ctx, h.studentRepo, fields[0])
+	if err != nil || into == nil {
+		return h.reply(ctx, cmdCtx, "❌ Студент не найден: <code>"+html.EscapeString(fields[0])+"</code>")
+	}
+	from, err := resolveStudentRef(ctx, h.studentRepo, fields[1])
+	if err != nil || from == nil {
+		return h.reply(ctx, cmdCtx, "❌ Студент не найден: <code>"+html.EscapeString(fields[1])+"</code>")
+	}
+
+	result, err := h.mergeCmd.Handle(ctx, command.MergeStudentsCommand{
+		IntoID:  into.ID,
+		FromID:  from.ID,
+		ActorID: strconv.FormatInt(cmdCtx.TelegramID, 10),
+		DryRun:  !confirmed,
+	})
+	if err != nil {
+		h.logger.Warn("student merge failed",
+			"admin_id", student.TelegramID(cmdCtx.TelegramID),
+			"into_id", into.ID,
+			"from_id", from.ID,
+			"dry_run", !confirmed,
+			"error", err,
+		)
+		if errors.Is(err, command.ErrMergeActiveSessions) {
+			return h.reply(ctx, cmdCtx, "⛔ Оба аккаунта сейчас активны с разных Telegram-аккаунтов.\n"+
+				"Уточните у студента, какой аккаунт его, и повторите позже.")
+		}
+		return h.reply(ctx, cmdCtx, "❌ Не удалось объединить: "+html.EscapeString(err.Error()))
+	}
+
+	if confirmed {
+		h.logger.Info("students merged",
+			"admin_id", student.TelegramID(cmdCtx.TelegramID),
+			"into_id", into.ID,
+			"from_id", from.ID,
+		)
+	}
+
+	return h.reply(ctx, cmdCtx, formatMergeResult(result, fields[0], fields[1]))
+}
+
+// formatConflicts renders the open pairs flagged by sync, if any.
+func (h *MergeStudentsHandler) formatConflicts(ctx context.Context) string {
+	if h.conflicts == nil {
+		return ""
+	}
+	conflicts, err := h.conflicts.ListOpen(ctx, mergeConflictsLimit)
+	if err != nil {
+		h.logger.Warn("failed to list identity conflicts", "error", err)
+		return ""
+	}
+	if len(conflicts) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n\n⚠️ <b>Возможные дубликаты</b> (логин Alem и email у разных записей):\n")
+	for _, c := range conflicts {
+		sb.WriteString(fmt.Sprintf("\n• логин <code>%s</code>: <code>%s</code>\n  email <code>%s</code>: <code>%s</code>\n",
+			html.EscapeString(c.AlemLogin), html.EscapeString(c.LoginStudentID),
+			html.EscapeString(c.Email), html.EscapeString(c.EmailStudentID)))
+	}
+	return sb.String()
+}
+
+// formatMergeResult renders the merge preview or outcome.
+func formatMergeResult(result *command.MergeStudentsResult, intoRef, fromRef string) string {
+	var sb strings.Builder
+
+	if result.DryRun {
+		sb.WriteString("🔍 <b>Предпросмотр слияния</b>\n\n")
+	} else {
+		sb.WriteString("✅ <b>Аккаунты объединены</b>\n\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Оставить: <b>%s</b> <code>%s</code>\n",
+		html.EscapeString(result.Into.DisplayName), html.EscapeString(result.Into.ID)))
+	sb.WriteString(fmt.Sprintf("Дубликат: <b>%s</b> <code>%s</code>\n\n",
+		html.EscapeString(result.From.DisplayName), html.EscapeString(result.From.ID)))
+
+	s := result.Summary
+	sb.WriteString(fmt.Sprintf("📈 История XP: %d\n", s.XPHistory))
+	sb.WriteString(fmt.Sprintf("📅 Дни прогресса: %d (сложено: %d)\n", s.DailyGrinds, s.DailyGrindsSummed))
+	if s.StreakFromDuplicate {
+		sb.WriteString("🔥 Серия: берётся из дубликата\n")
+	} else {
+		sb.WriteString("🔥 Серия: остаётся текущая\n")
+	}
+	sb.WriteString(fmt.Sprintf("🏅 Достижения: %d\n", s.Achievements))
+	sb.WriteString(fmt.Sprintf("🤝 Связи: %d (удалено: %d между аккаунтами, %d дублей)\n",
+		s.ConnectionsRepointed, s.ConnectionsDroppedSelf, s.ConnectionsDroppedDuplicate))
+	sb.WriteString(fmt.Sprintf("🆘 Запросы помощи: %d\n", s.HelpRequests))
+	sb.WriteString(fmt.Sprintf("🙏 Благодарности: %d (удалено: %d)\n", s.Endorsements, s.EndorsementsDropped))
+	if s.TelegramIDMoved {
+		sb.WriteString("📱 Telegram ID переходит от дубликата\n")
+	}
+
+	if result.DryRun {
+		sb.WriteString(fmt.Sprintf("\nДля слияния: <code>/merge %s %s %s</code>",
+			html.EscapeString(intoRef), html.EscapeString(fromRef), mergeConfirmArg))
+	}
+
+	return sb.String()
+}
+
+// reply sends a plain HTML message to the admin.
+func (h *MergeStudentsHandler) reply(ctx context.Context, cmdCtx CommandContext, text string) error {
+	_, err := cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, text)
+	return err
+}
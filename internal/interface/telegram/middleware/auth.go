@@ -5,6 +5,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -133,7 +134,7 @@ func (m *AuthMiddleware) Authenticate(
 	stud, err := m.studentRepo.GetByTelegramID(ctx, student.TelegramID(telegramID))
 	if err != nil {
 		// Check if it's a "not found" error (user not registered)
-		if shared.IsNotFound(err) {
+		if shared.IsNotFound(err) || errors.Is(err, student.ErrStudentNotFound) {
 			return &AuthResult{
 				IsAuthenticated: false,
 				ShouldContinue:  false,
@@ -158,6 +159,24 @@ func (m *AuthMiddleware) Authenticate(
 	}, nil
 }
 
+// ResolveStudent returns the student with the given Telegram ID through the
+// same cache as Authenticate, so handlers resolving the sender of an update
+// don't query the repository again. Unregistered users get the repository's
+// not-found error.
+func (m *AuthMiddleware) ResolveStudent(ctx context.Context, telegramID int64) (*student.Student, error) {
+	if cachedStudent := m.cache.get(telegramID); cachedStudent != nil {
+		return cachedStudent, nil
+	}
+
+	stud, err := m.studentRepo.GetByTelegramID(ctx, student.TelegramID(telegramID))
+	if err != nil {
+		return nil, err
+	}
+
+	m.cache.set(telegramID, stud)
+	return stud, nil
+}
+
 // isPublicCommand checks if the command doesn't require authentication.
 func (m *AuthMiddleware) isPublicCommand(command string) bool {
 	return m.config.PublicCommands[command]
@@ -187,6 +206,27 @@ func (m *AuthMiddleware) InvalidateAllCache() {
 	m.cache.clear()
 }
 
+// Invalidate drops the students a cache invalidation makes stale.
+// Implements shared.InvalidationTarget.
+func (m *AuthMiddleware) Invalidate(msg shared.CacheInvalidation) {
+	switch msg.Kind {
+	case shared.InvalidationStudentUpdated, shared.InvalidationStudentDeleted:
+		if msg.TelegramID != 0 {
+			m.cache.delete(msg.TelegramID)
+			return
+		}
+		m.cache.deleteWhere(func(stud *student.Student) bool { return stud.ID == msg.StudentID })
+	case shared.InvalidationCohortArchived:
+		m.cache.deleteWhere(func(stud *student.Student) bool { return string(stud.Cohort) == msg.Cohort })
+	}
+}
+
+// InvalidateAll clears the entire auth cache.
+// Implements shared.InvalidationTarget.
+func (m *AuthMiddleware) InvalidateAll() {
+	m.cache.clear()
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // CONTEXT HELPERS
 // Functions to work with authenticated data in context.
@@ -291,6 +331,17 @@ func (c *studentCache) delete(telegramID int64) {
 	delete(c.entries, telegramID)
 }
 
+func (c *studentCache) deleteWhere(match func(*student.Student) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, entry := range c.entries {
+		if match(entry.student) {
+			delete(c.entries, id)
+		}
+	}
+}
+
 func (c *studentCache) clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
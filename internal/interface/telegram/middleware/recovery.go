@@ -156,44 +156,35 @@ func (m *RecoveryMiddleware) WrapWithContext(ctx context.Context, fn func() erro
 }
 
 // RecoverWithHandler executes a handler and recovers from any panics.
-// This is the main entry point for the middleware.
+// The handler's own error is dropped; use Run to keep it.
 func (m *RecoveryMiddleware) RecoverWithHandler(
 	ctx context.Context,
 	telegramID int64,
 	command string,
 	handler func() error,
 ) *RecoveryResult {
+	result, _ := m.Run(ctx, telegramID, command, handler)
+	return result
+}
+
+// Run executes a handler and recovers from any panics. Returns the handler's
+// error when it didn't panic.
+func (m *RecoveryMiddleware) Run(
+	ctx context.Context,
+	telegramID int64,
+	command string,
+	handler func() error,
+) (result *RecoveryResult, err error) {
 	// Add metadata to context for better panic info
 	ctx = context.WithValue(ctx, TelegramIDContextKey, telegramID)
 
-	var result *RecoveryResult
-	var handlerErr error
-
-	func() {
-		defer func() {
-			if r := recover(); r != nil {
-				result = m.handlePanicWithMeta(ctx, r, telegramID, command)
-			}
-		}()
-		handlerErr = handler()
-	}()
-
-	// If panic occurred, return the result
-	if result != nil {
-		return result
-	}
-
-	// No panic - check if handler returned an error
-	if handlerErr != nil {
-		return &RecoveryResult{
-			Recovered:   false,
-			UserMessage: "", // Let the caller handle the error
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = m.handlePanicWithMeta(ctx, r, telegramID, command), nil
 		}
-	}
+	}()
 
-	return &RecoveryResult{
-		Recovered: false,
-	}
+	return &RecoveryResult{Recovered: false}, handler()
 }
 
 // handlePanic processes a recovered panic.
@@ -0,0 +1,153 @@
+// Package middleware contains Telegram bot middlewares for request processing.
+package middleware
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/analytics"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// USAGE RECORDER
+// Counts command usage per (day, command, cohort) without ever blocking the
+// update loop. Events go through a small buffer to a single writer goroutine;
+// when the buffer is full or Redis is down, events are simply dropped.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// UsageRecorderConfig holds configuration for the usage recorder.
+type UsageRecorderConfig struct {
+	// BufferSize is how many events may wait for the writer.
+	BufferSize int
+
+	// WriteTimeout bounds a single counter increment.
+	WriteTimeout time.Duration
+}
+
+// DefaultUsageRecorderConfig returns sensible defaults for the usage recorder.
+func DefaultUsageRecorderConfig() UsageRecorderConfig {
+	return UsageRecorderConfig{
+		BufferSize:   1024,
+		WriteTimeout: 500 * time.Millisecond,
+	}
+}
+
+// usageEvent is a single recorded command call.
+type usageEvent struct {
+	at      time.Time
+	command string
+	cohort  string
+}
+
+// UsageRecorder records command usage asynchronously.
+// A recorder with a nil counter does nothing.
+type UsageRecorder struct {
+	counter analytics.UsageCounter
+	config  UsageRecorderConfig
+
+	events chan usageEvent
+	done   chan struct{}
+
+	// closed guards events against sends after Close.
+	mu     sync.RWMutex
+	closed bool
+
+	recorded atomic.Int64
+	dropped  atomic.Int64
+	failed   atomic.Int64
+}
+
+// NewUsageRecorder creates a usage recorder and starts its writer.
+func NewUsageRecorder(counter analytics.UsageCounter, config UsageRecorderConfig) *UsageRecorder {
+	if config.BufferSize <= 0 {
+		config.BufferSize = DefaultUsageRecorderConfig().BufferSize
+	}
+
+	r := &UsageRecorder{
+		counter: counter,
+		config:  config,
+		events:  make(chan usageEvent, config.BufferSize),
+		done:    make(chan struct{}),
+	}
+
+	if counter == nil {
+		close(r.done)
+		return r
+	}
+
+	go r.run()
+	return r
+}
+
+// Record queues a command call. It never blocks.
+func (r *UsageRecorder) Record(command, cohort string) {
+	if r.counter == nil {
+		return
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		r.dropped.Add(1)
+		return
+	}
+
+	select {
+	case r.events <- usageEvent{at: time.Now(), command: command, cohort: cohort}:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Close stops accepting events and waits for queued ones to be written
+// or for ctx to expire.
+func (r *UsageRecorder) Close(ctx context.Context) {
+	if r.counter == nil {
+		return
+	}
+
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.events)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+	}
+}
+
+// run writes queued events to the counter.
+func (r *UsageRecorder) run() {
+	defer close(r.done)
+
+	for event := range r.events {
+		ctx := context.Background()
+		var cancel context.CancelFunc = func() {}
+		if r.config.WriteTimeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, r.config.WriteTimeout)
+		}
+
+		err := r.counter.Increment(ctx, analytics.DayOf(event.at), event.command, event.cohort)
+		cancel()
+
+		if err != nil {
+			r.failed.Add(1)
+			continue
+		}
+		r.recorded.Add(1)
+	}
+}
+
+// Stats returns recorder counters.
+func (r *UsageRecorder) Stats() map[string]int64 {
+	return map[string]int64{
+		"recorded": r.recorded.Load(),
+		"dropped":  r.dropped.Load(),
+		"failed":   r.failed.Load(),
+	}
+}
@@ -0,0 +1,161 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CONNECTIONS AND NUDGES
+// "/connections" lists the student's buddies and mentees with their streaks.
+// Those close to a milestone get a "👏 Подбодрить" button,
+// "nudge:<student>", which is also attached to the daily digest. The nudge
+// itself (cap, quiet hours, mutes) is command.NudgeHandler; the bot binds
+// its notifier behind the mute filter.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// nudgeCallbackPrefix is the callback prefix of the nudge buttons.
+const nudgeCallbackPrefix = "nudge:"
+
+// NudgeHandler handles /connections and the nudge buttons.
+type NudgeHandler struct {
+	nudgeCmd    *command.NudgeHandler
+	milestones  *query.GetConnectionMilestonesHandler
+	studentRepo student.Repository
+	logger      *slog.Logger
+}
+
+// NewNudgeHandler creates a new NudgeHandler. The nudges themselves are
+// delivered by the notifier the process binds to nudgeCmd.
+func NewNudgeHandler(
+	nudgeCmd *command.NudgeHandler,
+	milestones *query.GetConnectionMilestonesHandler,
+	studentRepo student.Repository,
+	logger *slog.Logger,
+) *NudgeHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	h := &NudgeHandler{
+		nudgeCmd:    nudgeCmd,
+		milestones:  milestones,
+		studentRepo: studentRepo,
+		logger:      logger,
+	}
+	return h
+}
+
+// Handle shows the student's connections.
+func (h *NudgeHandler) Handle(ctx context.Context, cmdCtx CommandContext) error {
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(cmdCtx.TelegramID))
+	if err != nil {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, "❌ Ты не зарегистрирован. Используй /start")
+		return err
+	}
+
+	result, err := h.milestones.Handle(ctx, query.GetConnectionMilestonesQuery{StudentID: stud.ID})
+	if err != nil {
+		return err
+	}
+	if len(result.Connections) == 0 {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID,
+			"🤝 <b>Твои связи</b>\n\nПока пусто. Напарники появляются, когда вы помогаете друг другу, — попробуй /help или /focus.")
+		return err
+	}
+
+	text, keyboard := connectionsView(result)
+	if len(keyboard.InlineKeyboard) == 0 {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, text)
+		return err
+	}
+	_, err = cmdCtx.Client.SendWithKeyboard(ctx, cmdCtx.ChatID, text, keyboard.InlineKeyboard)
+	return err
+}
+
+// HandleCallback sends the nudge and answers with a short notice.
+func (h *NudgeHandler) HandleCallback(ctx context.Context, cbCtx CallbackContext) error {
+	recipientID := strings.TrimPrefix(cbCtx.Data, nudgeCallbackPrefix)
+	if recipientID == "" {
+		return nil
+	}
+
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(cbCtx.TelegramID))
+	if err != nil {
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Ты не зарегистрирован. Используй /start", true)
+	}
+
+	result, err := h.nudgeCmd.Handle(ctx, command.NudgeCommand{SenderID: stud.ID, RecipientID: recipientID})
+	switch {
+	case errors.Is(err, command.ErrNudgeNotConnected):
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "Вы больше не напарники.", true)
+	case err != nil:
+		h.logger.Warn("nudge failed", "sender_id", stud.ID, "recipient_id", recipientID, "error", err)
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Не удалось отправить, попробуй позже.", true)
+	}
+
+	return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, nudgeNotice(result), !result.Delivered)
+}
+
+// nudgeNotice explains the outcome of a nudge to the sender.
+func nudgeNotice(result *command.NudgeResult) string {
+	name := result.RecipientName
+	switch result.SkipReason {
+	case "":
+		return "👏 Отправлено! " + name + " получит твою поддержку."
+	case command.NudgeSkipAlreadySent:
+		return "Сегодня поддержка для " + name + " уже отправлена. Загляни завтра!"
+	case command.NudgeSkipNoMilestone:
+		return name + " пока не у вехи — подбодрить можно, когда останется чуть-чуть."
+	default:
+		return name + " сейчас не получает уведомления. Попробуй позже."
+	}
+}
+
+// connectionsView renders the connections list with one nudge button per
+// connection close to a milestone.
+func connectionsView(result *query.GetConnectionMilestonesResult) (string, *telegram.InlineKeyboardMarkup) {
+	var sb strings.Builder
+	sb.WriteString("🤝 <b>Твои связи</b>\n\n")
+
+	rows := make([][]telegram.InlineKeyboardButton, 0)
+	for _, c := range result.Connections {
+		role := "напарник"
+		if c.Type == social.ConnectionTypeMentor {
+			role = "менторство"
+		}
+		sb.WriteString(fmt.Sprintf("• <b>%s</b> — %s", html.EscapeString(c.DisplayName), role))
+		if c.CurrentStreak > 0 {
+			sb.WriteString(fmt.Sprintf(", 🔥 %d", c.CurrentStreak))
+		}
+		sb.WriteString("\n")
+
+		if c.Milestone == nil {
+			continue
+		}
+		if c.Milestone.Kind == student.MilestoneKindStreak {
+			sb.WriteString(fmt.Sprintf("   день до серии в %d дней\n", c.Milestone.Target))
+		} else {
+			sb.WriteString(fmt.Sprintf("   %d XP до %d уровня\n", c.Milestone.Remaining, c.Milestone.Target))
+		}
+		rows = append(rows, []telegram.InlineKeyboardButton{{
+			Text:         "👏 Подбодрить " + c.DisplayName,
+			CallbackData: nudgeCallbackPrefix + c.StudentID,
+		}})
+	}
+
+	if len(rows) > 0 {
+		sb.WriteString("\n<i>Кто-то почти у цели — подбодри, это займёт секунду.</i>")
+	}
+	return sb.String(), &telegram.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
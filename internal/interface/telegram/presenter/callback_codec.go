@@ -0,0 +1,76 @@
+package presenter
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// SIGNED CALLBACK CODEC
+// Callback data travels through the client, so anyone can send a crafted
+// callback query. Signed payloads are bound to the user the keyboard was
+// built for: a forged or forwarded button is rejected before it is parsed.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ErrInvalidCallback is returned for callback data that was tampered with,
+// belongs to another user, or was not produced by the codec.
+var ErrInvalidCallback = errors.New("invalid callback data")
+
+// callbackMACSize is the length of the truncated HMAC-SHA256 tag, in bytes.
+// 6 bytes are 8 base64 characters: enough against guessing, small enough
+// for Telegram's 64-byte limit.
+const callbackMACSize = 6
+
+// callbackMACSeparator separates the payload from its tag.
+const callbackMACSeparator = "~"
+
+// CallbackCodec signs callback payloads for one recipient.
+// Data is prefix + payload + "~" + base64url(HMAC(user, prefix+payload)[:6]).
+type CallbackCodec struct {
+	secret []byte
+}
+
+// NewCallbackCodec creates a codec signing payloads with secret.
+func NewCallbackCodec(secret []byte) *CallbackCodec {
+	return &CallbackCodec{secret: append([]byte(nil), secret...)}
+}
+
+// Encode signs payload for telegramID. Fails if the result does not fit
+// into Telegram's callback data limit.
+func (c *CallbackCodec) Encode(telegramID int64, prefix, payload string) (string, error) {
+	if strings.Contains(payload, callbackMACSeparator) {
+		return "", fmt.Errorf("callback payload %q contains %q", payload, callbackMACSeparator)
+	}
+
+	data := prefix + payload + callbackMACSeparator + c.sign(telegramID, prefix+payload)
+	if len(data) > maxCallbackDataLen {
+		return "", fmt.Errorf("callback data %q exceeds %d bytes", data, maxCallbackDataLen)
+	}
+	return data, nil
+}
+
+// Decode verifies data for telegramID and returns the payload without prefix.
+func (c *CallbackCodec) Decode(telegramID int64, prefix, data string) (string, error) {
+	body, mac, ok := strings.Cut(strings.TrimPrefix(data, prefix), callbackMACSeparator)
+	if !ok || !strings.HasPrefix(data, prefix) {
+		return "", ErrInvalidCallback
+	}
+	if !hmac.Equal([]byte(mac), []byte(c.sign(telegramID, prefix+body))) {
+		return "", ErrInvalidCallback
+	}
+	return body, nil
+}
+
+// sign returns the truncated tag of data for telegramID.
+func (c *CallbackCodec) sign(telegramID int64, data string) string {
+	h := hmac.New(sha256.New, c.secret)
+	h.Write([]byte(strconv.FormatInt(telegramID, 10)))
+	h.Write([]byte{0})
+	h.Write([]byte(data))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:callbackMACSize])
+}
@@ -0,0 +1,64 @@
+package presenter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallbackCodec_RoundTrip(t *testing.T) {
+	codec := NewCallbackCodec([]byte("secret"))
+
+	data, err := codec.Encode(42, "st:", "t:n:rk")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(data, "st:t:n:rk~"))
+
+	payload, err := codec.Decode(42, "st:", data)
+	require.NoError(t, err)
+	assert.Equal(t, "t:n:rk", payload)
+
+	root, err := codec.Encode(42, "st:", "")
+	require.NoError(t, err)
+	payload, err = codec.Decode(42, "st:", root)
+	require.NoError(t, err)
+	assert.Empty(t, payload)
+}
+
+func TestCallbackCodec_RejectsForeignAndTamperedData(t *testing.T) {
+	codec := NewCallbackCodec([]byte("secret"))
+	data, err := codec.Encode(42, "st:", "t:n:rk")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		telegramID int64
+		data       string
+	}{
+		"other user":     {telegramID: 43, data: data},
+		"edited payload": {telegramID: 42, data: strings.Replace(data, "rk", "lb", 1)},
+		"no signature":   {telegramID: 42, data: "st:t:n:rk"},
+		"other prefix":   {telegramID: 42, data: strings.Replace(data, "st:", "xx:", 1)},
+		"other secret": {telegramID: 42, data: func() string {
+			d, _ := NewCallbackCodec([]byte("other")).Encode(42, "st:", "t:n:rk")
+			return d
+		}()},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := codec.Decode(tt.telegramID, "st:", tt.data)
+			assert.ErrorIs(t, err, ErrInvalidCallback)
+		})
+	}
+}
+
+func TestCallbackCodec_EnforcesLimits(t *testing.T) {
+	codec := NewCallbackCodec([]byte("secret"))
+
+	_, err := codec.Encode(42, "st:", strings.Repeat("x", maxCallbackDataLen))
+	assert.Error(t, err)
+
+	_, err = codec.Encode(42, "st:", "a~b")
+	assert.Error(t, err)
+}
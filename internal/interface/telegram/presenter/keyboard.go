@@ -16,6 +16,9 @@ import (
 // The actual Telegram bot implementation will convert these to the library's format.
 // ══════════════════════════════════════════════════════════════════════════════
 
+// maxCallbackDataLen is Telegram's limit on callback data, in bytes.
+const maxCallbackDataLen = 64
+
 // InlineKeyboard represents an inline keyboard.
 type InlineKeyboard struct {
 	Rows [][]InlineButton
@@ -131,33 +134,114 @@ func (b *KeyboardBuilder) StudentCardKeyboard(studentID string) *InlineKeyboard
 // LEADERBOARD KEYBOARDS
 // ─────────────────────────────────────────────────────────────────────────────
 
+// topCallback is the callback prefix of /top pages.
+const topCallback = "top"
+
+// TopPage is the state of a /top message kept in its buttons.
+type TopPage struct {
+	// Page is the 1-based page number.
+	Page int
+
+	// Cohort is the cohort filter ("" - all cohorts).
+	Cohort string
+
+	// OnlyOnline shows only online students.
+	OnlyOnline bool
+
+	// Weekly ranks by XP gained this week instead of all-time XP.
+	Weekly bool
+}
+
+// Callback returns the callback data of the page.
+func (p TopPage) Callback() (string, error) {
+	cb := NewPageCallback(topCallback, p.Page).With("cohort", p.Cohort)
+	if p.OnlyOnline {
+		cb = cb.With("online", "1")
+	}
+	if p.Weekly {
+		cb = cb.With("period", "week")
+	}
+	return cb.Encode()
+}
+
+// ParseTopPage decodes the callback data of a /top button.
+func ParseTopPage(data string) (TopPage, error) {
+	cb, err := ParsePageCallback(topCallback, data)
+	if err != nil {
+		return TopPage{}, err
+	}
+	return TopPage{
+		Page:       cb.Page,
+		Cohort:     cb.Param("cohort"),
+		OnlyOnline: cb.Param("online") == "1",
+		Weekly:     cb.Param("period") == "week",
+	}, nil
+}
+
+// firstTopPage returns the callback data of the unfiltered first page.
+func firstTopPage() string {
+	data, _ := TopPage{Page: 1}.Callback() // always fits
+	return data
+}
+
 // LeaderboardKeyboard creates keyboard for leaderboard (/top).
-func (b *KeyboardBuilder) LeaderboardKeyboard(page int, hasMore bool, cohort string, onlyOnline bool) *InlineKeyboard {
+// "Назад" is hidden on the first page and "Вперёд" on the last one.
+func (b *KeyboardBuilder) LeaderboardKeyboard(current TopPage, hasMore bool) *InlineKeyboard {
 	kb := NewInlineKeyboard()
+	if current.Page < 1 {
+		current.Page = 1
+	}
+
+	// topButton skips buttons whose state does not fit into callback data
+	topButton := func(row []InlineButton, text string, p TopPage) []InlineButton {
+		data, err := p.Callback()
+		if err != nil {
+			return row
+		}
+		return append(row, CallbackButton(text, data))
+	}
+	withPage := func(page int) TopPage {
+		p := current
+		p.Page = page
+		return p
+	}
 
 	// Navigation row
 	navRow := make([]InlineButton, 0, 3)
 
-	if page > 1 {
-		navRow = append(navRow, CallbackButton("◀️ Назад", fmt.Sprintf("top:page:%d:%s:%t", page-1, cohort, onlyOnline)))
+	if current.Page > 1 {
+		navRow = topButton(navRow, "◀️ Назад", withPage(current.Page-1))
 	}
 
-	navRow = append(navRow, CallbackButton("🔄", fmt.Sprintf("top:refresh:%d:%s:%t", page, cohort, onlyOnline)))
+	navRow = topButton(navRow, "🔄", current)
 
 	if hasMore {
-		navRow = append(navRow, CallbackButton("Вперёд ▶️", fmt.Sprintf("top:page:%d:%s:%t", page+1, cohort, onlyOnline)))
+		navRow = topButton(navRow, "Вперёд ▶️", withPage(current.Page+1))
 	}
 
 	if len(navRow) > 0 {
 		kb.AddRow(navRow...)
 	}
 
-	// Filter row
+	// Filter rows: a filter changes the list, so it starts from the first page
 	onlineText := "🟢 Только онлайн"
-	if onlyOnline {
+	if current.OnlyOnline {
 		onlineText = "👥 Показать всех"
 	}
-	kb.AddRow(CallbackButton(onlineText, fmt.Sprintf("top:filter:%d:%s:%t", page, cohort, !onlyOnline)))
+	online := withPage(1)
+	online.OnlyOnline = !current.OnlyOnline
+	filterRow := topButton(nil, onlineText, online)
+	kb.AddRow(append(filterRow, CallbackButton("🤝 Топ помощников месяца", "top:helpers"))...)
+
+	periodText := "📅 За неделю"
+	if current.Weekly {
+		periodText = "🏆 За всё время"
+	}
+	period := withPage(1)
+	period.Weekly = !current.Weekly
+	if periodRow := topButton(nil, periodText, period); len(periodRow) > 0 {
+		kb.AddRow(periodRow...)
+	}
 
 	// Actions row
 	kb.AddRow(
@@ -168,6 +252,18 @@ func (b *KeyboardBuilder) LeaderboardKeyboard(page int, hasMore bool, cohort str
 	return kb
 }
 
+// MonthlyHelpersKeyboard creates keyboard for the month's helper ranking.
+func (b *KeyboardBuilder) MonthlyHelpersKeyboard() *InlineKeyboard {
+	return NewInlineKeyboard().
+		AddRow(
+			CallbackButton("🔄", "top:helpers"),
+			CallbackButton("🏆 Общий рейтинг", "cmd:top"),
+		).
+		AddRow(
+			CallbackButton("📊 Моя позиция", "cmd:me"),
+		)
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // NEIGHBORS KEYBOARDS
 // ─────────────────────────────────────────────────────────────────────────────
@@ -217,6 +313,47 @@ func (b *KeyboardBuilder) OnlineKeyboard(includeAway, onlyHelpers bool) *InlineK
 	return kb
 }
 
+// ─────────────────────────────────────────────────────────────────────────────
+// NOTIFICATIONS KEYBOARDS
+// ─────────────────────────────────────────────────────────────────────────────
+
+// NotificationsKeyboard creates keyboard for the notification center (/notifications).
+func (b *KeyboardBuilder) NotificationsKeyboard(page int, hasMore, hasUnread bool) *InlineKeyboard {
+	kb := NewInlineKeyboard()
+
+	navRow := make([]InlineButton, 0, 3)
+	if page > 1 {
+		navRow = append(navRow, CallbackButton("◀️ Назад", fmt.Sprintf("notif:page:%d", page-1)))
+	}
+	navRow = append(navRow, CallbackButton("🔄", fmt.Sprintf("notif:page:%d", page)))
+	if hasMore {
+		navRow = append(navRow, CallbackButton("Вперёд ▶️", fmt.Sprintf("notif:page:%d", page+1)))
+	}
+	kb.AddRow(navRow...)
+
+	if hasUnread {
+		kb.AddRow(CallbackButton("✅ Отметить всё прочитанным", fmt.Sprintf("notif:read_all:%d", page)))
+	}
+
+	return kb
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// ACHIEVEMENTS KEYBOARDS
+// ─────────────────────────────────────────────────────────────────────────────
+
+// AchievementsKeyboard creates keyboard for achievements view (/achievements).
+func (b *KeyboardBuilder) AchievementsKeyboard() *InlineKeyboard {
+	return NewInlineKeyboard().
+		AddRow(
+			CallbackButton("🔄 Обновить", "refresh:achievements"),
+		).
+		AddRow(
+			CallbackButton("📊 Моя карточка", "cmd:me"),
+			CallbackButton("🏆 Лидерборд", "cmd:top"),
+		)
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // HELP / HELPERS KEYBOARDS
 // ─────────────────────────────────────────────────────────────────────────────
@@ -254,6 +391,22 @@ func (b *KeyboardBuilder) HelpersKeyboard(helpers []query.HelperDTO, taskID stri
 	return kb
 }
 
+// PopularTasksKeyboard creates keyboard for hot tasks (/populartasks): one
+// button per task opening its helpers.
+func (b *KeyboardBuilder) PopularTasksKeyboard(tasks []query.PopularTaskDTO) *InlineKeyboard {
+	kb := NewInlineKeyboard()
+
+	for _, task := range tasks {
+		data := fmt.Sprintf("help:refresh:%s", task.TaskID)
+		if len(data) > maxCallbackDataLen {
+			continue
+		}
+		kb.AddRow(CallbackButton(fmt.Sprintf("🙋 Кто поможет с %s", task.TaskName), data))
+	}
+
+	return kb
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // SETTINGS KEYBOARDS
 // ─────────────────────────────────────────────────────────────────────────────
@@ -287,6 +440,24 @@ func (b *KeyboardBuilder) SettingsKeyboard(stud *student.Student) *InlineKeyboar
 	}
 	kb.AddRow(CallbackButton(fmt.Sprintf("%s Напоминания", inactivityIcon), "settings:toggle:inactivity_reminders"))
 
+	buddyIcon := "✅"
+	if !stud.Preferences.BuddyOnline {
+		buddyIcon = "❌"
+	}
+	kb.AddRow(CallbackButton(fmt.Sprintf("%s Напарник онлайн", buddyIcon), "settings:toggle:buddy_online"))
+
+	stuckIcon := "✅"
+	if !stud.Preferences.StuckHelpOffers {
+		stuckIcon = "❌"
+	}
+	kb.AddRow(CallbackButton(fmt.Sprintf("%s Помощь, если застрял", stuckIcon), "settings:toggle:stuck_help_offers"))
+
+	quickIcon := "✅"
+	if !stud.Preferences.QuickActions {
+		quickIcon = "❌"
+	}
+	kb.AddRow(CallbackButton(fmt.Sprintf("%s Быстрые кнопки", quickIcon), "settings:toggle:quick_actions"))
+
 	// Quiet hours
 	kb.AddRow(CallbackButton(fmt.Sprintf("🌙 Тихие часы: %02d:00-%02d:00",
 		stud.Preferences.QuietHoursStart,
@@ -75,12 +75,12 @@ func (p *LeaderboardPresenter) FormatLeaderboard(
 	sb.WriteString(p.formatStats(result))
 
 	// Клавиатура
-	keyboard := p.keyboardBuilder.LeaderboardKeyboard(
-		page,
-		result.HasMore,
-		result.Cohort,
-		onlyOnline,
-	)
+	keyboard := p.keyboardBuilder.LeaderboardKeyboard(TopPage{
+		Page:       page,
+		Cohort:     result.Cohort,
+		OnlyOnline: onlyOnline,
+		Weekly:     result.Period == "week",
+	}, result.HasMore)
 
 	return &LeaderboardView{
 		Text:      sb.String(),
@@ -145,6 +145,9 @@ func (p *LeaderboardPresenter) formatEntry(entry *query.LeaderboardEntryDTO, isC
 	} else {
 		sb.WriteString(p.escapeHTML(entry.DisplayName))
 	}
+	if entry.XPReviewPending {
+		sb.WriteString(" " + XPReviewMarker)
+	}
 
 	// XP
 	sb.WriteString(fmt.Sprintf(" • <code>%s XP</code>", p.formatNumber(entry.XP)))
@@ -629,22 +632,7 @@ func (p *LeaderboardPresenter) FormatMilestoneNotification(
 
 // formatNumber форматирует число с разделителями тысяч.
 func (p *LeaderboardPresenter) formatNumber(n int) string {
-	if n < 1000 {
-		return fmt.Sprintf("%d", n)
-	}
-
-	str := fmt.Sprintf("%d", n)
-	result := ""
-	length := len(str)
-
-	for i, c := range str {
-		if i > 0 && (length-i)%3 == 0 {
-			result += " "
-		}
-		result += string(c)
-	}
-
-	return result
+	return formatThousands(n)
 }
 
 // formatDuration форматирует длительность в человекочитаемый формат.
@@ -667,12 +655,7 @@ func (p *LeaderboardPresenter) formatDuration(d time.Duration) string {
 
 // escapeHTML экранирует HTML-символы для безопасного отображения.
 func (p *LeaderboardPresenter) escapeHTML(s string) string {
-	replacer := strings.NewReplacer(
-		"&", "&amp;",
-		"<", "&lt;",
-		">", "&gt;",
-	)
-	return replacer.Replace(s)
+	return htmlEscaper.Replace(s)
 }
 
 // ─────────────────────────────────────────────────────────────────────────────
@@ -694,7 +677,7 @@ func (p *LeaderboardPresenter) FormatEmptyLeaderboard() *LeaderboardView {
 
 	keyboard := NewInlineKeyboard().
 		AddRow(
-			CallbackButton("🔄 Обновить", "top:refresh:1::false"),
+			CallbackButton("🔄 Обновить", firstTopPage()),
 		)
 
 	return &LeaderboardView{
@@ -715,7 +698,7 @@ func (p *LeaderboardPresenter) FormatErrorState(err error) *LeaderboardView {
 
 	keyboard := NewInlineKeyboard().
 		AddRow(
-			CallbackButton("🔄 Попробовать снова", "top:refresh:1::false"),
+			CallbackButton("🔄 Попробовать снова", firstTopPage()),
 		)
 
 	return &LeaderboardView{
@@ -0,0 +1,312 @@
+package presenter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// LEADERBOARD TABLE
+// Моноширинная таблица рейтинга в блоке <pre>: колонки не разъезжаются
+// из-за длинных имён, эмодзи и RTL-текста, а сообщение всегда укладывается
+// в лимит Telegram. Чистая функция над записями - удобно тестировать.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// TelegramMessageLimit - максимальная длина сообщения Telegram (UTF-16).
+	TelegramMessageLimit = 4096
+
+	// ellipsis заменяет обрезанный хвост имени.
+	ellipsis = "…"
+
+	// Изоляция направления текста: RTL-имя не переставляет колонки строки.
+	bidiIsolateStart = "\u2068" // FIRST STRONG ISOLATE
+	bidiIsolateEnd   = "\u2069" // POP DIRECTIONAL ISOLATE
+
+	// zeroWidthJoiner склеивает эмодзи в одну графему (👨‍💻).
+	zeroWidthJoiner = '\u200d'
+
+	// XPReviewMarker отмечает студента, чей прирост XP проверяет куратор.
+	XPReviewMarker = "⏳"
+)
+
+// LeaderboardTableOptions - параметры таблицы рейтинга.
+type LeaderboardTableOptions struct {
+	// Title - заголовок над таблицей (HTML, выводится как есть).
+	Title string
+
+	// Footer - подпись под таблицей (HTML, выводится как есть).
+	Footer string
+
+	// NameWidth - ширина колонки имени в моноширинных ячейках.
+	NameWidth int
+
+	// MaxLength - максимальная длина сообщения (UTF-16).
+	MaxLength int
+}
+
+// DefaultLeaderboardTableOptions возвращает параметры по умолчанию.
+func DefaultLeaderboardTableOptions() LeaderboardTableOptions {
+	return LeaderboardTableOptions{
+		NameWidth: 16,
+		MaxLength: TelegramMessageLimit,
+	}
+}
+
+// RenderLeaderboardTable форматирует записи рейтинга таблицей
+// "ранг. имя XP". Если сообщение не помещается в MaxLength, строки
+// отбрасываются с конца и добавляется пометка "показаны первые N".
+func RenderLeaderboardTable(entries []query.LeaderboardEntryDTO, opts LeaderboardTableOptions) string {
+	if opts.NameWidth <= 0 {
+		opts.NameWidth = DefaultLeaderboardTableOptions().NameWidth
+	}
+	if opts.MaxLength <= 0 {
+		opts.MaxLength = TelegramMessageLimit
+	}
+
+	rows := make([]string, len(entries))
+	layout := newTableLayout(entries, opts.NameWidth)
+	for i := range entries {
+		rows[i] = layout.row(&entries[i])
+	}
+
+	n := len(rows)
+	text := assembleTable(rows, n, len(rows), opts)
+	for n > 0 && utf16Len(text) > opts.MaxLength {
+		n--
+		text = assembleTable(rows, n, len(rows), opts)
+	}
+
+	return text
+}
+
+// tableLayout - ширины колонок таблицы.
+type tableLayout struct {
+	rankWidth int
+	nameWidth int
+	xpWidth   int
+}
+
+// newTableLayout вычисляет ширины колонок по самым широким значениям.
+func newTableLayout(entries []query.LeaderboardEntryDTO, maxNameWidth int) tableLayout {
+	layout := tableLayout{rankWidth: 1, xpWidth: 1}
+	for i := range entries {
+		e := &entries[i]
+		layout.rankWidth = max(layout.rankWidth, len(fmt.Sprintf("%d", e.Rank)))
+		layout.xpWidth = max(layout.xpWidth, utf8.RuneCountInString(formatThousands(e.XP)))
+		layout.nameWidth = max(layout.nameWidth, displayWidth(tableName(e.DisplayName)))
+	}
+	layout.nameWidth = min(layout.nameWidth, maxNameWidth)
+	return layout
+}
+
+// row форматирует одну строку таблицы (HTML-экранированную).
+func (l tableLayout) row(e *query.LeaderboardEntryDTO) string {
+	name := truncateDisplay(tableName(e.DisplayName), l.nameWidth)
+	padding := strings.Repeat(" ", l.nameWidth-displayWidth(name))
+	xp := formatThousands(e.XP)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%*d. ", l.rankWidth, e.Rank))
+	sb.WriteString(bidiIsolateStart)
+	sb.WriteString(htmlEscaper.Replace(name))
+	sb.WriteString(bidiIsolateEnd)
+	sb.WriteString(padding)
+	sb.WriteString(strings.Repeat(" ", l.xpWidth-utf8.RuneCountInString(xp)+2))
+	sb.WriteString(xp)
+	if e.IsOnline {
+		sb.WriteString(" 🟢")
+	}
+	if e.XPReviewPending {
+		sb.WriteString(" " + XPReviewMarker)
+	}
+	return sb.String()
+}
+
+// tableName убирает из имени переводы строк и управляющие символы,
+// которые сломали бы строку таблицы.
+func tableName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, name)
+	return strings.TrimSpace(name)
+}
+
+// assembleTable собирает сообщение из первых n строк.
+func assembleTable(rows []string, n, total int, opts LeaderboardTableOptions) string {
+	var sb strings.Builder
+
+	if opts.Title != "" {
+		sb.WriteString(opts.Title)
+		sb.WriteString("\n\n")
+	}
+
+	if n > 0 {
+		sb.WriteString("<pre>")
+		sb.WriteString(strings.Join(rows[:n], "\n"))
+		sb.WriteString("</pre>")
+	} else {
+		sb.WriteString("📭 <i>Пока никого нет в списке</i>")
+	}
+
+	if n < total {
+		sb.WriteString(fmt.Sprintf("\n<i>показаны первые %d</i>", n))
+	}
+
+	if opts.Footer != "" {
+		sb.WriteString("\n")
+		sb.WriteString(opts.Footer)
+	}
+
+	return sb.String()
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// TEXT MEASUREMENT
+// ─────────────────────────────────────────────────────────────────────────────
+
+// truncateDisplay обрезает строку до width моноширинных ячеек по границам
+// графем (эмодзи, ZWJ-последовательности и буквы с диакритикой не режутся).
+func truncateDisplay(s string, width int) string {
+	if displayWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+
+	limit := width - displayWidth(ellipsis)
+	var sb strings.Builder
+	used := 0
+	for _, g := range graphemes(s) {
+		w := graphemeWidth(g)
+		if used+w > limit {
+			break
+		}
+		sb.WriteString(g)
+		used += w
+	}
+
+	return strings.TrimRightFunc(sb.String(), unicode.IsSpace) + ellipsis
+}
+
+// displayWidth возвращает ширину строки в моноширинных ячейках.
+func displayWidth(s string) int {
+	w := 0
+	for _, g := range graphemes(s) {
+		w += graphemeWidth(g)
+	}
+	return w
+}
+
+// graphemes делит строку на приближённые графемные кластеры:
+// базовый символ вместе с комбинируемыми знаками, селекторами вариантов,
+// модификаторами тона и ZWJ-продолжениями; пары региональных индикаторов
+// (флаги) образуют один кластер.
+func graphemes(s string) []string {
+	var clusters []string
+
+	for i := 0; i < len(s); {
+		start := i
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+
+		if isRegionalIndicator(r) && i < len(s) {
+			if next, nsize := utf8.DecodeRuneInString(s[i:]); isRegionalIndicator(next) {
+				i += nsize
+			}
+		}
+
+		for i < len(s) {
+			next, nsize := utf8.DecodeRuneInString(s[i:])
+			if isGraphemeExtend(next) {
+				i += nsize
+				continue
+			}
+			if next == zeroWidthJoiner {
+				i += nsize
+				if i < len(s) {
+					_, jsize := utf8.DecodeRuneInString(s[i:])
+					i += jsize
+				}
+				continue
+			}
+			break
+		}
+
+		clusters = append(clusters, s[start:i])
+	}
+
+	return clusters
+}
+
+// isGraphemeExtend сообщает, продолжает ли руна предыдущую графему.
+func isGraphemeExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		(r >= 0xFE00 && r <= 0xFE0F) || // variation selectors
+		(r >= 0x1F3FB && r <= 0x1F3FF) || // skin tone modifiers
+		(r >= 0xE0020 && r <= 0xE007F) // tag sequences (subdivision flags)
+}
+
+// isRegionalIndicator сообщает, является ли руна половиной флага.
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// graphemeWidth возвращает ширину графемы: 2 для эмодзи и CJK,
+// 0 для невидимых символов, иначе 1.
+func graphemeWidth(g string) int {
+	r, _ := utf8.DecodeRuneInString(g)
+
+	switch {
+	case unicode.Is(unicode.Cf, r) || unicode.IsControl(r):
+		return 0
+	case strings.ContainsRune(g, '\ufe0f'), strings.ContainsRune(g, zeroWidthJoiner):
+		return 2
+	case r >= 0x1F000 && r <= 0x1FAFF:
+		return 2
+	case unicode.In(r, unicode.Han, unicode.Hangul, unicode.Hiragana, unicode.Katakana):
+		return 2
+	case r >= 0xFF00 && r <= 0xFF60: // fullwidth forms
+		return 2
+	default:
+		return 1
+	}
+}
+
+// utf16Len возвращает длину строки в UTF-16 единицах, как её считает Telegram.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// formatThousands форматирует число с пробелами между тысячами.
+func formatThousands(n int) string {
+	if n < 0 {
+		return "-" + formatThousands(-n)
+	}
+
+	str := fmt.Sprintf("%d", n)
+	var sb strings.Builder
+	for i, c := range str {
+		if i > 0 && (len(str)-i)%3 == 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+// htmlEscaper экранирует HTML-символы для Telegram.
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
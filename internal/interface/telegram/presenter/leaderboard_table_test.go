@@ -0,0 +1,76 @@
+package presenter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+)
+
+func TestRenderLeaderboardTable_TrickyNames(t *testing.T) {
+	entries := []query.LeaderboardEntryDTO{
+		{Rank: 1, DisplayName: "Айгерим Нурсултановна Абдрахманова", XP: 152340, IsOnline: true},
+		{Rank: 2, DisplayName: "👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦", XP: 98000},
+		{Rank: 3, DisplayName: "🇰🇿 Daniyar", XP: 7500},
+		{Rank: 4, DisplayName: "שלום עליכם", XP: 999},
+		{Rank: 10, DisplayName: "Tom & <Jerry>\nline", XP: 5},
+		{Rank: 11, DisplayName: "Zoé Café", XP: 1000},
+	}
+
+	opts := DefaultLeaderboardTableOptions()
+	opts.Title = "🏆 <b>Общий рейтинг</b>"
+	got := RenderLeaderboardTable(entries, opts)
+
+	want := "🏆 <b>Общий рейтинг</b>\n\n<pre>" +
+		" 1. ⁨Айгерим Нурсулт…⁩  152 340 🟢\n" +
+		" 2. ⁨👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦👨‍👩‍👧‍👦…⁩    98 000\n" +
+		" 3. ⁨🇰🇿 Daniyar⁩          7 500\n" +
+		" 4. ⁨שלום עליכם⁩            999\n" +
+		"10. ⁨Tom &amp; &lt;Jerry&gt; l…⁩        5\n" +
+		"11. ⁨Zoé Café⁩            1 000" +
+		"</pre>"
+	assert.Equal(t, want, got)
+}
+
+func TestRenderLeaderboardTable_FitsMessageLimit(t *testing.T) {
+	for _, n := range []int{10, 50, 100} {
+		t.Run(fmt.Sprintf("%d rows", n), func(t *testing.T) {
+			entries := make([]query.LeaderboardEntryDTO, n)
+			for i := range entries {
+				entries[i] = query.LeaderboardEntryDTO{
+					Rank:        i + 1,
+					DisplayName: strings.Repeat("👩🏽‍💻Ж", 20),
+					XP:          1_000_000 - i*997,
+				}
+			}
+
+			text := RenderLeaderboardTable(entries, DefaultLeaderboardTableOptions())
+			require.LessOrEqual(t, utf16Len(text), TelegramMessageLimit)
+
+			table := text[strings.Index(text, "<pre>")+len("<pre>") : strings.Index(text, "</pre>")]
+			rows := strings.Split(table, "\n")
+			for _, row := range rows {
+				assert.Equal(t, displayWidth(rows[0]), displayWidth(row), "columns must line up")
+				assert.True(t, utf8.ValidString(row))
+			}
+
+			if len(rows) < n {
+				assert.Contains(t, text, fmt.Sprintf("показаны первые %d", len(rows)))
+			} else {
+				assert.NotContains(t, text, "показаны первые")
+			}
+		})
+	}
+}
+
+func TestTruncateDisplay_KeepsGraphemes(t *testing.T) {
+	assert.Equal(t, "Ab…", truncateDisplay("Abcdef", 3))
+	assert.Equal(t, "👍🏽…", truncateDisplay("👍🏽👍🏽👍🏽", 4))
+	assert.Equal(t, "é…", truncateDisplay("ééé", 2))
+	assert.Equal(t, "short", truncateDisplay("short", 10))
+}
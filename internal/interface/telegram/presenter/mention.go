@@ -0,0 +1,22 @@
+package presenter
+
+import (
+	"fmt"
+	"html"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MENTIONS
+// Students are mentioned by a tg://user?id= deep link rather than @username:
+// usernames change or disappear, the Telegram ID doesn't. The link text is
+// the current display name.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Mention renders an HTML mention of a Telegram user with name as the link
+// text. Without a Telegram ID only the escaped name is rendered.
+func Mention(telegramID int64, name string) string {
+	if telegramID <= 0 {
+		return html.EscapeString(name)
+	}
+	return fmt.Sprintf("<a href=\"tg://user?id=%d\">%s</a>", telegramID, html.EscapeString(name))
+}
@@ -0,0 +1,12 @@
+package presenter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMention(t *testing.T) {
+	assert.Equal(t, `<a href="tg://user?id=42">Дана &lt;3</a>`, Mention(42, "Дана <3"))
+	assert.Equal(t, "Дана", Mention(0, "Дана"))
+}
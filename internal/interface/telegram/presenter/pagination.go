@@ -0,0 +1,151 @@
+package presenter
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PAGE CALLBACKS
+// Paginated messages (/top and friends) keep their state in the buttons:
+// "<command>:page=<n>[:<key>=<value>]...", e.g. "top:page=3:cohort=2024-spring".
+// Pages are 1-based. Parameters are written in key order, so the same state
+// always encodes to the same data, and values are query-escaped, so they may
+// contain ":" and "=".
+// ══════════════════════════════════════════════════════════════════════════════
+
+// pageKey is the parameter holding the page number.
+const pageKey = "page"
+
+// PageCallback is the state of a paginated message.
+type PageCallback struct {
+	// Command is the callback prefix without ":" (e.g. "top").
+	Command string
+
+	// Page is the 1-based page number.
+	Page int
+
+	// Params are the command's filters; empty values are not encoded.
+	Params map[string]string
+}
+
+// NewPageCallback creates the callback of page of command.
+func NewPageCallback(command string, page int) PageCallback {
+	return PageCallback{Command: command, Page: page}
+}
+
+// With returns a copy of the callback with key set to value.
+// An empty value removes the key.
+func (c PageCallback) With(key, value string) PageCallback {
+	params := make(map[string]string, len(c.Params)+1)
+	for k, v := range c.Params {
+		params[k] = v
+	}
+	if value == "" {
+		delete(params, key)
+	} else {
+		params[key] = value
+	}
+	c.Params = params
+	return c
+}
+
+// WithPage returns a copy of the callback pointing at page.
+func (c PageCallback) WithPage(page int) PageCallback {
+	c.Page = page
+	return c
+}
+
+// Param returns the value of key, or "" if it is not set.
+func (c PageCallback) Param(key string) string {
+	return c.Params[key]
+}
+
+// Encode returns the callback data. Pages below 1 are encoded as 1.
+// Fails if the data does not fit into Telegram's callback data limit.
+func (c PageCallback) Encode() (string, error) {
+	page := c.Page
+	if page < 1 {
+		page = 1
+	}
+
+	keys := make([]string, 0, len(c.Params))
+	for k, v := range c.Params {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString(c.Command)
+	sb.WriteString(":" + pageKey + "=" + strconv.Itoa(page))
+	for _, k := range keys {
+		sb.WriteString(":" + k + "=" + url.QueryEscape(c.Params[k]))
+	}
+
+	data := sb.String()
+	if len(data) > maxCallbackDataLen {
+		return "", fmt.Errorf("callback data %q exceeds %d bytes", data, maxCallbackDataLen)
+	}
+	return data, nil
+}
+
+// ParsePageCallback decodes data produced by Encode for command.
+func ParsePageCallback(command, data string) (PageCallback, error) {
+	rest, ok := strings.CutPrefix(data, command+":")
+	if !ok {
+		return PageCallback{}, ErrInvalidCallback
+	}
+
+	c := PageCallback{Command: command}
+	for _, part := range strings.Split(rest, ":") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || key == "" {
+			return PageCallback{}, ErrInvalidCallback
+		}
+
+		if key == pageKey {
+			page, err := strconv.Atoi(value)
+			if err != nil || page < 1 {
+				return PageCallback{}, ErrInvalidCallback
+			}
+			c.Page = page
+			continue
+		}
+
+		value, err := url.QueryUnescape(value)
+		if err != nil {
+			return PageCallback{}, ErrInvalidCallback
+		}
+		c = c.With(key, value)
+	}
+
+	if c.Page == 0 {
+		return PageCallback{}, ErrInvalidCallback
+	}
+	return c, nil
+}
+
+// LastPage returns the number of pages needed for total items, pageSize
+// per page. An empty list still has page 1.
+func LastPage(total, pageSize int) int {
+	if pageSize <= 0 || total <= 0 {
+		return 1
+	}
+	return (total + pageSize - 1) / pageSize
+}
+
+// ClampPage returns page limited to [1, LastPage(total, pageSize)].
+func ClampPage(page, total, pageSize int) int {
+	if last := LastPage(total, pageSize); page > last {
+		return last
+	}
+	if page < 1 {
+		return 1
+	}
+	return page
+}
@@ -0,0 +1,92 @@
+package presenter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageCallback_RoundTrip(t *testing.T) {
+	cb := NewPageCallback("top", 3).With("cohort", "2024-spring").With("online", "")
+
+	data, err := cb.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, "top:page=3:cohort=2024-spring", data)
+
+	parsed, err := ParsePageCallback("top", data)
+	require.NoError(t, err)
+	assert.Equal(t, 3, parsed.Page)
+	assert.Equal(t, "2024-spring", parsed.Param("cohort"))
+	assert.Empty(t, parsed.Param("online"))
+
+	// Keys are sorted and separators in values are escaped
+	data, err = NewPageCallback("top", 0).With("z", "1").With("cohort", "a:b=c").Encode()
+	require.NoError(t, err)
+	assert.Equal(t, "top:page=1:cohort=a%3Ab%3Dc:z=1", data)
+
+	parsed, err = ParsePageCallback("top", data)
+	require.NoError(t, err)
+	assert.Equal(t, "a:b=c", parsed.Param("cohort"))
+}
+
+func TestPageCallback_RejectsMalformedData(t *testing.T) {
+	for _, data := range []string{
+		"top:page:2::false", // the old positional format
+		"top:page=0",
+		"top:page=x",
+		"top:cohort=2024",
+		"notif:page=2",
+		"top:page=2:cohort",
+		"top",
+	} {
+		_, err := ParsePageCallback("top", data)
+		assert.ErrorIs(t, err, ErrInvalidCallback, data)
+	}
+
+	_, err := NewPageCallback("top", 1).With("cohort", strings.Repeat("x", maxCallbackDataLen)).Encode()
+	assert.Error(t, err)
+}
+
+func TestClampPage(t *testing.T) {
+	assert.Equal(t, 1, ClampPage(0, 25, 10))
+	assert.Equal(t, 2, ClampPage(2, 25, 10))
+	assert.Equal(t, 3, ClampPage(7, 25, 10))
+	assert.Equal(t, 2, ClampPage(3, 20, 10))
+	assert.Equal(t, 1, ClampPage(4, 0, 10))
+	assert.Equal(t, 3, LastPage(25, 10))
+}
+
+func TestLeaderboardKeyboard_HidesNavigationAtEdges(t *testing.T) {
+	kb := NewKeyboardBuilder()
+
+	first := kb.LeaderboardKeyboard(TopPage{Page: 1, Cohort: "2024-spring"}, true)
+	assert.Equal(t, []string{"🔄", "Вперёд ▶️"}, buttonTexts(first.Rows[0]))
+	assert.Equal(t, "top:page=2:cohort=2024-spring", first.Rows[0][1].CallbackData)
+
+	last := kb.LeaderboardKeyboard(TopPage{Page: 3, OnlyOnline: true}, false)
+	assert.Equal(t, []string{"◀️ Назад", "🔄"}, buttonTexts(last.Rows[0]))
+	assert.Equal(t, "top:page=2:online=1", last.Rows[0][0].CallbackData)
+
+	// Toggling the filter starts over from the first page
+	assert.Equal(t, "top:page=1", last.Rows[1][0].CallbackData)
+	assert.Equal(t, "📅 За неделю", last.Rows[2][0].Text)
+	assert.Equal(t, "top:page=1:online=1:period=week", last.Rows[2][0].CallbackData)
+
+	weekly, err := ParseTopPage(last.Rows[2][0].CallbackData)
+	require.NoError(t, err)
+	assert.Equal(t, TopPage{Page: 1, OnlyOnline: true, Weekly: true}, weekly)
+
+	page, err := ParseTopPage(first.Rows[0][1].CallbackData)
+	require.NoError(t, err)
+	assert.Equal(t, TopPage{Page: 2, Cohort: "2024-spring"}, page)
+}
+
+func buttonTexts(row []InlineButton) []string {
+	texts := make([]string, len(row))
+	for i, b := range row {
+		texts[i] = b.Text
+	}
+	return texts
+}
@@ -0,0 +1,106 @@
+package presenter
+
+import "strings"
+
+// ══════════════════════════════════════════════════════════════════════════════
+// QUICK ACTIONS
+// Persistent reply keyboard with the most used commands. Pressing a button
+// sends its label as a plain message, so the labels double as routes.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// QuickAction identifies a quick action button.
+type QuickAction string
+
+const (
+	// QuickActionProgress shows the student card (/me).
+	QuickActionProgress QuickAction = "progress"
+
+	// QuickActionTop shows the leaderboard (/top).
+	QuickActionTop QuickAction = "top"
+
+	// QuickActionHelp finds help (/help).
+	QuickActionHelp QuickAction = "help"
+
+	// QuickActionStreak shows the daily streak.
+	QuickActionStreak QuickAction = "streak"
+)
+
+// DefaultQuickActionLanguage is used for languages without their own labels.
+const DefaultQuickActionLanguage = "ru"
+
+// quickActionOrder is the button order, two per row.
+var quickActionOrder = []QuickAction{
+	QuickActionProgress, QuickActionTop,
+	QuickActionHelp, QuickActionStreak,
+}
+
+// quickActionLabels holds button labels per Telegram language code.
+var quickActionLabels = map[string]map[QuickAction]string{
+	"ru": {
+		QuickActionProgress: "📊 Мой прогресс",
+		QuickActionTop:      "🏆 Топ",
+		QuickActionHelp:     "🆘 Помощь",
+		QuickActionStreak:   "🔥 Стрик",
+	},
+	"kk": {
+		QuickActionProgress: "📊 Менің прогресім",
+		QuickActionTop:      "🏆 Көшбасшылар",
+		QuickActionHelp:     "🆘 Көмек",
+		QuickActionStreak:   "🔥 Серия",
+	},
+}
+
+// ReplyKeyboard represents a reply keyboard (buttons send their text).
+type ReplyKeyboard struct {
+	Rows [][]string
+}
+
+// QuickActionLabel returns the label of the action in the given language.
+func QuickActionLabel(language string, action QuickAction) string {
+	return quickActionLabelsFor(language)[action]
+}
+
+// QuickActionsKeyboard builds the quick action keyboard in the given language.
+func QuickActionsKeyboard(language string) *ReplyKeyboard {
+	labels := quickActionLabelsFor(language)
+
+	kb := &ReplyKeyboard{}
+	for i := 0; i < len(quickActionOrder); i += 2 {
+		kb.Rows = append(kb.Rows, []string{
+			labels[quickActionOrder[i]],
+			labels[quickActionOrder[i+1]],
+		})
+	}
+	return kb
+}
+
+// QuickActionForText returns the action whose label matches text in any
+// language: the student's Telegram language may have changed since the
+// keyboard was sent.
+func QuickActionForText(text string) (QuickAction, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", false
+	}
+
+	for _, labels := range quickActionLabels {
+		for action, label := range labels {
+			if label == text {
+				return action, true
+			}
+		}
+	}
+	return "", false
+}
+
+// quickActionLabelsFor picks labels by language code ("kk", "ru-RU").
+func quickActionLabelsFor(language string) map[QuickAction]string {
+	language = strings.ToLower(language)
+	if i := strings.IndexAny(language, "-_"); i > 0 {
+		language = language[:i]
+	}
+	if labels, ok := quickActionLabels[language]; ok {
+		return labels
+	}
+	return quickActionLabels[DefaultQuickActionLanguage]
+}
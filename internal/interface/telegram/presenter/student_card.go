@@ -560,6 +560,10 @@ func (p *StudentCardPresenter) FormatSettings(stud *student.Student) *SettingsVi
 	sb.WriteString(p.formatSettingStatus("Запросы помощи", stud.Preferences.HelpRequests))
 	sb.WriteString("\n")
 	sb.WriteString(p.formatSettingStatus("Напоминания", stud.Preferences.InactivityReminders))
+	sb.WriteString("\n")
+	sb.WriteString(p.formatSettingStatus("Напарник онлайн", stud.Preferences.BuddyOnline))
+	sb.WriteString("\n")
+	sb.WriteString(p.formatSettingStatus("Помощь, если застрял", stud.Preferences.StuckHelpOffers))
 	sb.WriteString("\n\n")
 
 	// Тихие часы
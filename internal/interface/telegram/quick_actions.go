@@ -0,0 +1,96 @@
+package telegram
+
+import (
+	"context"
+
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// QUICK ACTIONS
+// Reply keyboard buttons send their label as plain text; the label is mapped
+// back to a command and handled like the typed command.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// privateChatType is the Telegram chat type of a one-to-one chat.
+const privateChatType = "private"
+
+// quickActionCommands maps quick actions to the commands they run.
+var quickActionCommands = map[presenter.QuickAction]string{
+	presenter.QuickActionProgress: "me",
+	presenter.QuickActionTop:      "top",
+	presenter.QuickActionHelp:     "help",
+	presenter.QuickActionStreak:   "streak",
+}
+
+// QuickActionCommand returns the command for a quick action button label.
+// Without a registered /streak the streak button opens /me, which shows the
+// streak in the card.
+func (r *Router) QuickActionCommand(text string) (string, bool) {
+	action, ok := presenter.QuickActionForText(text)
+	if !ok {
+		return "", false
+	}
+
+	command := quickActionCommands[action]
+	if !r.HasCommand(command) {
+		command = "me"
+	}
+	return command, true
+}
+
+// attachQuickActions sends the keyboard after a response; a failure is
+// logged, the response itself was already delivered.
+func (r *Router) attachQuickActions(ctx context.Context, client *telegram.Client, chat *telegram.Chat, from *telegram.User, enabled bool) {
+	if err := r.sendQuickActions(ctx, client, chat, from, enabled); err != nil {
+		r.logger.Warn("failed to send quick action keyboard", "enabled", enabled, "error", err)
+	}
+}
+
+// sendQuickActions shows or removes the quick action keyboard. Reply
+// keyboards are per chat and would pop up for every member of a group,
+// so outside private chats this does nothing.
+func (r *Router) sendQuickActions(ctx context.Context, client *telegram.Client, chat *telegram.Chat, from *telegram.User, enabled bool) error {
+	if chat == nil || chat.Type != privateChatType {
+		return nil
+	}
+
+	params := telegram.SendMessageParams{
+		ChatID:              chat.ID,
+		ParseMode:           "HTML",
+		DisableNotification: true,
+	}
+	if enabled {
+		language := presenter.DefaultQuickActionLanguage
+		if from != nil && from.LanguageCode != "" {
+			language = from.LanguageCode
+		}
+		params.Text = "⌨️ Быстрые кнопки внизу. Скрыть — в /settings."
+		params.ReplyKeyboard = convertReplyKeyboard(presenter.QuickActionsKeyboard(language))
+	} else {
+		params.Text = "⌨️ Быстрые кнопки скрыты."
+		params.RemoveKeyboard = true
+	}
+
+	_, err := client.SendMessage(ctx, params)
+	return err
+}
+
+// convertReplyKeyboard converts presenter reply keyboard to Telegram format.
+func convertReplyKeyboard(kb *presenter.ReplyKeyboard) *telegram.ReplyKeyboardMarkup {
+	markup := &telegram.ReplyKeyboardMarkup{
+		Keyboard:       make([][]telegram.KeyboardButton, len(kb.Rows)),
+		IsPersistent:   true,
+		ResizeKeyboard: true,
+	}
+
+	for i, row := range kb.Rows {
+		markup.Keyboard[i] = make([]telegram.KeyboardButton, len(row))
+		for j, label := range row {
+			markup.Keyboard[i][j] = telegram.KeyboardButton{Text: label}
+		}
+	}
+
+	return markup
+}
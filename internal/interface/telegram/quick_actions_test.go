@@ -0,0 +1,114 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+)
+
+func TestRouter_QuickActionCommand(t *testing.T) {
+	router := NewRouter(RouterConfig{})
+	for _, command := range []string{"me", "top", "help"} {
+		router.RegisterCommand(command, &recordingCommand{router: router})
+	}
+
+	tests := []struct {
+		language string
+		action   presenter.QuickAction
+		want     string
+	}{
+		{"ru", presenter.QuickActionProgress, "me"},
+		{"ru", presenter.QuickActionTop, "top"},
+		{"ru", presenter.QuickActionHelp, "help"},
+		{"ru", presenter.QuickActionStreak, "me"},
+		{"kk", presenter.QuickActionProgress, "me"},
+		{"kk", presenter.QuickActionTop, "top"},
+		{"kk", presenter.QuickActionHelp, "help"},
+		{"kk", presenter.QuickActionStreak, "me"},
+	}
+
+	for _, tt := range tests {
+		label := presenter.QuickActionLabel(tt.language, tt.action)
+		t.Run(label, func(t *testing.T) {
+			command, ok := router.QuickActionCommand(label)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, command)
+
+			// Stray whitespace from some clients still matches
+			command, ok = router.QuickActionCommand(" " + label + "\n")
+			require.True(t, ok)
+			assert.Equal(t, tt.want, command)
+		})
+	}
+
+	// Kazakh labels differ from Russian ones
+	assert.NotEqual(t,
+		presenter.QuickActionLabel("ru", presenter.QuickActionProgress),
+		presenter.QuickActionLabel("kk", presenter.QuickActionProgress))
+
+	// A registered /streak takes over the streak button
+	router.RegisterCommand("streak", &recordingCommand{router: router})
+	command, _ := router.QuickActionCommand(presenter.QuickActionLabel("kk", presenter.QuickActionStreak))
+	assert.Equal(t, "streak", command)
+
+	for _, text := range []string{"", "привет", "Топ", "🏆", "/top"} {
+		_, ok := router.QuickActionCommand(text)
+		assert.False(t, ok, "%q", text)
+	}
+}
+
+func TestQuickActionsKeyboard_Language(t *testing.T) {
+	kk := presenter.QuickActionsKeyboard("kk")
+	require.Len(t, kk.Rows, 2)
+	assert.Equal(t, []string{"📊 Менің прогресім", "🏆 Көшбасшылар"}, kk.Rows[0])
+
+	// Regional and unknown languages fall back sensibly
+	assert.Equal(t, presenter.QuickActionsKeyboard("ru").Rows, presenter.QuickActionsKeyboard("ru-RU").Rows)
+	assert.Equal(t, presenter.QuickActionsKeyboard("ru").Rows, presenter.QuickActionsKeyboard("en").Rows)
+}
+
+func TestRouter_SendQuickActions_PrivateChatOnly(t *testing.T) {
+	router := NewRouter(RouterConfig{})
+	from := &telegram.User{ID: 42, LanguageCode: "kk"}
+
+	for _, chatType := range []string{"group", "supergroup", "channel"} {
+		t.Run(chatType, func(t *testing.T) {
+			client, sent := newFakeTelegram(t)
+			chat := &telegram.Chat{ID: -100, Type: chatType}
+
+			require.NoError(t, router.sendQuickActions(context.Background(), client, chat, from, true))
+			require.NoError(t, router.sendQuickActions(context.Background(), client, chat, from, false))
+			assert.Empty(t, sent.messages)
+		})
+	}
+
+	t.Run("private", func(t *testing.T) {
+		client, sent := newFakeTelegram(t)
+		chat := &telegram.Chat{ID: 42, Type: "private"}
+
+		require.NoError(t, router.sendQuickActions(context.Background(), client, chat, from, true))
+		require.NoError(t, router.sendQuickActions(context.Background(), client, chat, from, false))
+		require.Len(t, sent.messages, 2)
+
+		shown := sent.messages[0]["reply_markup"].(map[string]interface{})
+		rows := shown["keyboard"].([]interface{})
+		require.Len(t, rows, 2)
+		first := rows[0].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "📊 Менің прогресім", first["text"])
+		assert.Equal(t, true, shown["is_persistent"])
+
+		removed := sent.messages[1]["reply_markup"].(map[string]interface{})
+		assert.Equal(t, true, removed["remove_keyboard"])
+	})
+
+	t.Run("no chat", func(t *testing.T) {
+		client, sent := newFakeTelegram(t)
+		require.NoError(t, router.sendQuickActions(context.Background(), client, nil, from, true))
+		assert.Empty(t, sent.messages)
+	})
+}
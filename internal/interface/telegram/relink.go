@@ -0,0 +1,230 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/handler"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/middleware"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RELINK
+// "/relink" on a new Telegram account (e.g. after a phone number change)
+// asks for the Alem email and password of an existing profile and moves the
+// profile here. The next two private messages are the email and the
+// password; the password message is deleted right away. Afterwards every
+// in-process state keyed by the old Telegram ID is dropped and the old chat
+// is told the profile has moved, if it is still reachable.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// relinkSessionTTL is how long /relink waits for the email and password.
+	relinkSessionTTL = 10 * time.Minute
+
+	// relinkMaxAttempts is how many wrong passwords end a /relink session.
+	relinkMaxAttempts = 3
+)
+
+// relinkSession is an in-progress /relink of one Telegram account.
+type relinkSession struct {
+	email     string // empty while waiting for the email
+	attempts  int
+	expiresAt time.Time
+}
+
+// RelinkHandler handles /relink and the email and password replies.
+type RelinkHandler struct {
+	relinkCmd   *command.RelinkTelegramHandler
+	studentRepo student.Repository
+	auth        *middleware.AuthMiddleware
+	rateLimiter *middleware.RateLimiter
+	client      *telegram.Client
+	logger      *slog.Logger
+
+	mu       sync.Mutex
+	sessions map[int64]*relinkSession
+
+	// now is the time source (replaced in tests).
+	now func() time.Time
+}
+
+// NewRelinkHandler creates a new RelinkHandler.
+func NewRelinkHandler(
+	relinkCmd *command.RelinkTelegramHandler,
+	studentRepo student.Repository,
+	auth *middleware.AuthMiddleware,
+	rateLimiter *middleware.RateLimiter,
+	client *telegram.Client,
+	logger *slog.Logger,
+) *RelinkHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &RelinkHandler{
+		relinkCmd:   relinkCmd,
+		studentRepo: studentRepo,
+		auth:        auth,
+		rateLimiter: rateLimiter,
+		client:      client,
+		logger:      logger,
+		sessions:    make(map[int64]*relinkSession),
+		now:         time.Now,
+	}
+}
+
+// Handle starts a relink session.
+func (h *RelinkHandler) Handle(ctx context.Context, cmdCtx CommandContext) error {
+	if _, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(cmdCtx.TelegramID)); err == nil {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID,
+			"ℹ️ Этот Telegram аккаунт уже привязан к профилю. Смотри /me")
+		return err
+	}
+
+	h.mu.Lock()
+	h.sessions[cmdCtx.TelegramID] = &relinkSession{expiresAt: h.now().Add(relinkSessionTTL)}
+	h.mu.Unlock()
+
+	_, err := cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID,
+		"📱 <b>Перенос профиля на этот аккаунт</b>\n\n"+
+			"Введи email от alem.school, с которым ты регистрировался в боте.")
+	return err
+}
+
+// TakeInput handles a private message of an unregistered user. Returns
+// false when no relink session is waiting, so the message is handled as
+// usual (onboarding).
+func (h *RelinkHandler) TakeInput(ctx context.Context, telegramID, chatID int64, messageID int, text string) bool {
+	h.mu.Lock()
+	session, ok := h.sessions[telegramID]
+	if ok && h.now().After(session.expiresAt) {
+		delete(h.sessions, telegramID)
+		ok = false
+	}
+	var email string
+	if ok {
+		email = session.email
+		if email == "" {
+			session.email = strings.TrimSpace(text)
+		}
+	}
+	h.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	if email == "" {
+		h.reply(ctx, chatID, "🔐 Теперь введи пароль от alem.school. Сообщение с паролем я сразу удалю.")
+		return true
+	}
+
+	// The password must not stay in the chat history
+	if err := h.client.DeleteMessage(ctx, chatID, int64(messageID)); err != nil {
+		h.logger.Warn("failed to delete relink password message", "error", err)
+	}
+
+	result, err := h.relinkCmd.Handle(ctx, command.RelinkTelegramCommand{
+		NewTelegramID: telegramID,
+		Email:         email,
+		Password:      text,
+	})
+	if errors.Is(err, command.ErrRelinkInvalidCredentials) && h.retry(telegramID) {
+		h.reply(ctx, chatID, "❌ Email или пароль не подходят. Попробуй ввести пароль ещё раз.")
+		return true
+	}
+
+	h.mu.Lock()
+	delete(h.sessions, telegramID)
+	h.mu.Unlock()
+
+	if err != nil {
+		if !errors.Is(err, command.ErrRelinkInvalidCredentials) && !errors.Is(err, command.ErrRelinkTooSoon) &&
+			!errors.Is(err, command.ErrRelinkAlreadyRegistered) {
+			h.logger.Error("relink failed", "telegram_id", student.TelegramID(telegramID), "error", err)
+		}
+		h.reply(ctx, chatID, relinkErrorText(err))
+		return true
+	}
+
+	h.forget(ctx, result.OldTelegramID, student.TelegramID(telegramID))
+	h.logger.Info("student relinked",
+		"student_id", result.Student.ID,
+		"old_telegram_id", result.OldTelegramID,
+		"new_telegram_id", result.Student.TelegramID,
+	)
+	h.reply(ctx, chatID, fmt.Sprintf(
+		"✅ <b>Профиль перенесён</b>\n\nС возвращением, %s! Прогресс, серии и связи на месте. Смотри /me",
+		html.EscapeString(result.Student.DisplayName),
+	))
+	return true
+}
+
+// retry counts a wrong password and reports whether the session continues.
+func (h *RelinkHandler) retry(telegramID int64) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	session, ok := h.sessions[telegramID]
+	if !ok {
+		return false
+	}
+	session.attempts++
+	return session.attempts < relinkMaxAttempts
+}
+
+// forget drops the in-process state of both Telegram accounts and tells the
+// old chat that the profile has moved. Other bot instances drop the old ID
+// through the cache invalidation published by the command.
+func (h *RelinkHandler) forget(ctx context.Context, oldID, newID student.TelegramID) {
+	h.auth.InvalidateCache(int64(oldID))
+	h.auth.InvalidateCache(int64(newID))
+	h.rateLimiter.Reset(int64(oldID))
+	handler.ClearPendingOnboarding(int64(oldID))
+	handler.ClearPendingOnboarding(int64(newID))
+
+	h.mu.Lock()
+	delete(h.sessions, int64(oldID))
+	h.mu.Unlock()
+
+	if _, err := h.client.SendHTML(ctx, int64(oldID),
+		"📱 <b>Аккаунт перенесён</b>\n\n"+
+			"Твой профиль теперь привязан к другому Telegram аккаунту. "+
+			"Если это был не ты, напиши администраторам."); err != nil {
+		h.logger.Info("old chat is not reachable after relink", "telegram_id", oldID, "error", err)
+	}
+}
+
+// reply sends a message and logs failures.
+func (h *RelinkHandler) reply(ctx context.Context, chatID int64, text string) {
+	if _, err := h.client.SendHTML(ctx, chatID, text); err != nil {
+		h.logger.Warn("failed to send relink reply", "error", err)
+	}
+}
+
+// relinkErrorText formats a failed relink.
+func relinkErrorText(err error) string {
+	switch {
+	case errors.Is(err, command.ErrRelinkInvalidCredentials):
+		return "❌ Email или пароль не подходят. Начни заново: /relink"
+	case errors.Is(err, command.ErrRelinkTooSoon):
+		return fmt.Sprintf("⏳ Этот профиль недавно переносили. Попробуй через %d минут.",
+			int(student.RelinkCooldown.Minutes()))
+	case errors.Is(err, command.ErrRelinkAlreadyRegistered):
+		return "⚠️ У этого Telegram аккаунта уже есть свой профиль. Чтобы объединить профили, напиши администраторам."
+	case errors.Is(err, student.ErrRelinkConflict), errors.Is(err, student.ErrTelegramIDInUse):
+		return "⚠️ Профиль только что перенесли с другого аккаунта. Если это был не ты, напиши администраторам."
+	default:
+		return "❌ Не получилось перенести профиль. Попробуй позже: /relink"
+	}
+}
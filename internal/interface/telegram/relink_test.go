@@ -0,0 +1,116 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/shared"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/middleware"
+)
+
+// relinkRepo adds the lookups and the swap /relink needs to fakeStudentRepo.
+type relinkRepo struct {
+	fakeStudentRepo
+}
+
+func (r *relinkRepo) ExistsByTelegramID(_ context.Context, id student.TelegramID) (bool, error) {
+	_, ok := r.byTelegramID[id]
+	return ok, nil
+}
+
+func (r *relinkRepo) GetByEmail(_ context.Context, email string) (*student.Student, error) {
+	for _, s := range r.byTelegramID {
+		if s.Email == email {
+			copied := *s
+			return &copied, nil
+		}
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+func (r *relinkRepo) RelinkTelegramID(_ context.Context, studentID string, from, to student.TelegramID, _ shared.AuditEntry) error {
+	s, ok := r.byTelegramID[from]
+	if !ok || s.ID != studentID {
+		return student.ErrRelinkConflict
+	}
+	delete(r.byTelegramID, from)
+	s.TelegramID = to
+	r.byTelegramID[to] = s
+	return nil
+}
+
+type openCooldowns struct{}
+
+func (openCooldowns) Acquire(context.Context, string, time.Duration) (bool, error) { return true, nil }
+
+func TestRelinkHandler_MovesProfileAndForgetsOldAccount(t *testing.T) {
+	ctx := context.Background()
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+	repo := &relinkRepo{fakeStudentRepo{byTelegramID: map[student.TelegramID]*student.Student{
+		100: {ID: "s1", TelegramID: 100, Email: "dana@alem.school", PasswordHash: string(hash), DisplayName: "Dana"},
+	}}}
+
+	auth := middleware.NewAuthMiddleware(repo, middleware.DefaultAuthConfig())
+	// The old account is cached before the move
+	_, err = auth.ResolveStudent(ctx, 100)
+	require.NoError(t, err)
+
+	client, sent := newFakeTelegram(t)
+	relinkCmd := command.NewRelinkTelegramHandler(repo, repo, openCooldowns{}, nil)
+	h := NewRelinkHandler(relinkCmd, repo, auth, middleware.NewRateLimiter(middleware.DefaultRateLimitConfig()), client, nil)
+
+	// Without /relink the message goes to onboarding
+	assert.False(t, h.TakeInput(ctx, 200, 200, 1, "hello"))
+
+	require.NoError(t, h.Handle(ctx, CommandContext{TelegramID: 200, ChatID: 200, Client: client}))
+	assert.True(t, h.TakeInput(ctx, 200, 200, 2, "dana@alem.school"))
+	assert.True(t, h.TakeInput(ctx, 200, 200, 3, "wrong"))
+	assert.Equal(t, student.TelegramID(100), repo.byTelegramID[100].TelegramID)
+	assert.True(t, h.TakeInput(ctx, 200, 200, 4, "secret"))
+
+	_, moved := repo.byTelegramID[200]
+	assert.True(t, moved)
+	_, stillOld := repo.byTelegramID[100]
+	assert.False(t, stillOld)
+
+	// The auth cache no longer resolves the old account
+	_, err = auth.ResolveStudent(ctx, 100)
+	assert.Error(t, err)
+	resolved, err := auth.ResolveStudent(ctx, 200)
+	require.NoError(t, err)
+	assert.Equal(t, "s1", resolved.ID)
+
+	// The session is over
+	assert.False(t, h.TakeInput(ctx, 200, 200, 5, "secret"))
+
+	sent.mu.Lock()
+	defer sent.mu.Unlock()
+	var deleted []float64
+	var notifiedOld, welcomed bool
+	for _, msg := range sent.messages {
+		text, _ := msg["text"].(string)
+		switch {
+		case text == "":
+			if id, ok := msg["message_id"].(float64); ok {
+				deleted = append(deleted, id)
+			}
+		case msg["chat_id"] == float64(100) && strings.Contains(text, "Аккаунт перенесён"):
+			notifiedOld = true
+		case msg["chat_id"] == float64(200) && strings.Contains(text, "Профиль перенесён"):
+			welcomed = true
+		}
+	}
+	// Both password messages are removed from the chat
+	assert.Equal(t, []float64{3, 4}, deleted)
+	assert.True(t, notifiedOld)
+	assert.True(t, welcomed)
+}
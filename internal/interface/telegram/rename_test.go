@@ -0,0 +1,166 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/persistence/projections"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/handler"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/middleware"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+)
+
+// renamingStudentRepo stores updates by Telegram ID; lookups by ID return a
+// copy like a real database read.
+type renamingStudentRepo struct {
+	fakeStudentRepo
+	updates int
+}
+
+func (r *renamingStudentRepo) GetByID(_ context.Context, id string) (*student.Student, error) {
+	for _, s := range r.byTelegramID {
+		if s.ID == id {
+			return s.Clone(), nil
+		}
+	}
+	return nil, student.ErrStudentNotFound
+}
+
+func (r *renamingStudentRepo) Update(_ context.Context, s *student.Student) error {
+	r.updates++
+	r.byTelegramID[s.TelegramID] = s.Clone()
+	return nil
+}
+
+// fakeNameCache is an in-memory display-name cache.
+type fakeNameCache struct {
+	names map[string]string
+}
+
+func (c *fakeNameCache) GetNames(_ context.Context, ids []string) (map[string]string, error) {
+	found := make(map[string]string)
+	for _, id := range ids {
+		if name, ok := c.names[id]; ok {
+			found[id] = name
+		}
+	}
+	return found, nil
+}
+
+func (c *fakeNameCache) SetNames(_ context.Context, names map[string]string) error {
+	for id, name := range names {
+		c.names[id] = name
+	}
+	return nil
+}
+
+func (c *fakeNameCache) DeleteNames(_ context.Context, ids ...string) error {
+	for _, id := range ids {
+		delete(c.names, id)
+	}
+	return nil
+}
+
+// cachedTop serves /top from its entries and renames them in place,
+// like the Redis info hash.
+type cachedTop struct {
+	leaderboard.LeaderboardCache
+	leaderboard.LeaderboardRepository
+	entries []*leaderboard.LeaderboardEntry
+}
+
+func (c *cachedTop) GetCachedTop(context.Context, leaderboard.Cohort, int) ([]*leaderboard.LeaderboardEntry, error) {
+	top := make([]*leaderboard.LeaderboardEntry, len(c.entries))
+	for i, e := range c.entries {
+		entry := *e
+		top[i] = &entry
+	}
+	return top, nil
+}
+
+func (c *cachedTop) GetTotalCount(context.Context, leaderboard.Cohort) (int, error) {
+	return len(c.entries), nil
+}
+
+func (c *cachedTop) RenameEntry(_ context.Context, studentID, displayName string, _ string) error {
+	for _, e := range c.entries {
+		if e.StudentID == studentID {
+			e.DisplayName = displayName
+		}
+	}
+	return nil
+}
+
+func topUpdate(firstName, lastName string) *telegram.Message {
+	return &telegram.Message{
+		Text: "/top",
+		From: &telegram.User{ID: 42, FirstName: firstName, LastName: lastName, Username: "aidana_dev"},
+		Chat: &telegram.Chat{ID: 42, Type: privateChatType},
+	}
+}
+
+func TestBot_RenameArrivingWithTopRefreshesAllCaches(t *testing.T) {
+	repo := &renamingStudentRepo{fakeStudentRepo: fakeStudentRepo{
+		byTelegramID: map[student.TelegramID]*student.Student{
+			42: {ID: "student-42", TelegramID: 42, DisplayName: "Айдана", Cohort: "2024-fall", LastSeenAt: time.Now()},
+		},
+	}}
+	top := &cachedTop{entries: []*leaderboard.LeaderboardEntry{
+		{StudentID: "student-42", DisplayName: "Айдана", Rank: 1, XP: 900},
+	}}
+	names := &fakeNameCache{names: map[string]string{"student-42": "Айдана"}}
+	cards := projections.NewStudentCardView()
+	require.NoError(t, cards.UpsertCard(&projections.StudentCard{StudentID: "student-42", TelegramID: 42, DisplayName: "Айдана"}))
+
+	renames := command.NewRenameStudentHandler(repo, nil, top, names).WithViews(cards)
+
+	router := NewRouter(RouterConfig{})
+	router.RegisterCommand("top", handler.NewTopHandler(
+		query.NewGetLeaderboardHandler(top, top, nil), repo, presenter.NewKeyboardBuilder(),
+	))
+	auth := middleware.NewAuthMiddleware(repo, middleware.DefaultAuthConfig())
+	client, sent := newFakeTelegram(t)
+	bot := &Bot{
+		client:         client,
+		router:         router,
+		logger:         router.logger,
+		authMiddleware: auth,
+		students:       handler.NewStudentLoader(auth),
+		usageRecorder:  middleware.NewUsageRecorder(nil, middleware.DefaultUsageRecorderConfig()),
+		renames:        renames,
+		stats:          &BotStats{CommandsCount: make(map[string]int64)},
+	}
+
+	// The student renamed themselves on Telegram, then asked for /top.
+	require.NoError(t, bot.handleCommand(context.Background(), 42, 42, 1, "top", "", topUpdate("Дана", "Сейт")))
+
+	assert.Equal(t, "Дана Сейт", repo.byTelegramID[42].DisplayName)
+	assert.Equal(t, "Дана Сейт", top.entries[0].DisplayName)
+	assert.Equal(t, "Дана Сейт", names.names["student-42"])
+	card, err := cards.GetByStudentID(context.Background(), "student-42")
+	require.NoError(t, err)
+	assert.Equal(t, "Дана Сейт", card.DisplayName)
+
+	// The same update already renders the fresh name.
+	require.Len(t, sent.messages, 1)
+	assert.Contains(t, sent.messages[0]["text"], "Дана Сейт")
+	assert.NotContains(t, sent.messages[0]["text"], "Айдана")
+
+	// The auth cache was refreshed, so an unchanged name costs no write...
+	require.NoError(t, bot.handleCommand(context.Background(), 42, 42, 2, "top", "", topUpdate("Дана", "Сейт")))
+	assert.Equal(t, 1, repo.updates)
+
+	// ...and another rename the same day waits for the next interval.
+	require.NoError(t, bot.handleCommand(context.Background(), 42, 42, 3, "top", "", topUpdate("Даночка", "")))
+	assert.Equal(t, 1, repo.updates)
+	assert.Equal(t, "Дана Сейт", top.entries[0].DisplayName)
+}
@@ -0,0 +1,360 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// REPORTS
+// "⚠️ Пожаловаться" under every introduction the bot sends:
+// "report:c:<student id>" reports the student of a connection (the contact
+// card from "📨 Написать"), "report:h:<help request id>" reports the
+// requester of a help request (the alert sent to helpers). The next private
+// message becomes the optional reason. Reports never reach the reported
+// student, so they work even if the reporter blocked them on Telegram.
+//
+// Admins get every report in a private message with "report:uphold:<id>"
+// and "report:dismiss:<id>"; "report:restore:<id>" brings a student that
+// was excluded from helper matching back. /reports lists the queue.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// reportCallbackPrefix is the callback prefix of the report buttons.
+const reportCallbackPrefix = "report:"
+
+// pendingReportsLimit is how many pending reports /reports shows.
+const pendingReportsLimit = 10
+
+// ReportHandler handles report buttons, report reasons and admin decisions.
+type ReportHandler struct {
+	router      *Router
+	reportCmd   *command.ReportStudentHandler
+	reviewCmd   *command.ReviewReportHandler
+	studentRepo student.Repository
+	client      *telegram.Client
+	adminIDs    []int64
+	admins      map[int64]bool
+	logger      *slog.Logger
+}
+
+// NewReportHandler creates a new ReportHandler.
+// Only Telegram users listed in adminIDs get reports and may decide.
+func NewReportHandler(
+	router *Router,
+	reportCmd *command.ReportStudentHandler,
+	reviewCmd *command.ReviewReportHandler,
+	studentRepo student.Repository,
+	client *telegram.Client,
+	adminIDs []int64,
+	logger *slog.Logger,
+) *ReportHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	admins := make(map[int64]bool, len(adminIDs))
+	for _, id := range adminIDs {
+		admins[id] = true
+	}
+
+	return &ReportHandler{
+		router:      router,
+		reportCmd:   reportCmd,
+		reviewCmd:   reviewCmd,
+		studentRepo: studentRepo,
+		client:      client,
+		adminIDs:    adminIDs,
+		admins:      admins,
+		logger:      logger,
+	}
+}
+
+// Handle processes "report:<action>:<id>".
+func (h *ReportHandler) Handle(ctx context.Context, cbCtx CallbackContext) error {
+	action, id, ok := strings.Cut(strings.TrimPrefix(cbCtx.Data, reportCallbackPrefix), ":")
+	if !ok || id == "" {
+		return nil
+	}
+
+	switch action {
+	case "c":
+		return h.report(ctx, cbCtx, command.ReportStudentCommand{Context: social.ReportContextConnection, ReportedID: id})
+	case "h":
+		return h.report(ctx, cbCtx, command.ReportStudentCommand{Context: social.ReportContextHelpRequest, HelpRequestID: id})
+	case "uphold", "dismiss", "restore":
+		reportID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil || reportID <= 0 {
+			return nil
+		}
+		return h.review(ctx, cbCtx, action, reportID)
+	default:
+		return nil
+	}
+}
+
+// report creates a report and asks for the reason.
+func (h *ReportHandler) report(ctx context.Context, cbCtx CallbackContext, cmd command.ReportStudentCommand) error {
+	reporter, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(cbCtx.TelegramID))
+	if err != nil {
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Ты не зарегистрирован. Используй /start", true)
+	}
+	cmd.ReporterID = reporter.ID
+
+	result, err := h.reportCmd.Handle(ctx, cmd)
+	switch {
+	case errors.Is(err, social.ErrReportSelf):
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "🤔 Нельзя пожаловаться на себя.", true)
+	case err != nil:
+		h.logger.Warn("report failed", "reporter_id", reporter.ID, "context", cmd.Context, "error", err)
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Не удалось отправить жалобу. Попробуй позже.", true)
+	}
+
+	h.logger.Info("student reported",
+		"report_id", result.Report.ID,
+		"reporter_id", result.Reporter.ID,
+		"reported_id", result.Reported.ID,
+		"context", result.Report.Context,
+	)
+
+	_ = cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "Жалоба отправлена администраторам.", false)
+	if _, err := cbCtx.Client.SendHTML(ctx, cbCtx.TelegramID, fmt.Sprintf(
+		"⚠️ <b>Жалоба #%d отправлена</b>\n\n"+
+			"Администраторы посмотрят её, %s об этом не узнает.\n"+
+			"Если хочешь, опиши, что произошло, следующим сообщением (в течение %d мин).",
+		result.Report.ID, html.EscapeString(result.Reported.DisplayName), int(social.ReportReasonWindow.Minutes()),
+	)); err != nil {
+		h.logger.Warn("failed to confirm report", "report_id", result.Report.ID, "error", err)
+	}
+
+	h.notifyAdmins(ctx, formatReportAlert(result.Report, result.Reporter, result.Reported, result.TaskID), reportDecisionKeyboard(result.Report.ID))
+	return nil
+}
+
+// TakeReason stores a private message as the reason of the sender's
+// latest report. Returns false when no report is waiting for a reason,
+// so the message is handled as usual.
+func (h *ReportHandler) TakeReason(ctx context.Context, telegramID int64, text string) bool {
+	reporter, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(telegramID))
+	if err != nil {
+		return false
+	}
+
+	report, err := h.reportCmd.AttachReason(ctx, reporter.ID, text)
+	if err != nil {
+		if !errors.Is(err, social.ErrReportNotFound) {
+			h.logger.Warn("failed to attach report reason", "reporter_id", reporter.ID, "error", err)
+		}
+		return false
+	}
+
+	if _, err := h.client.SendHTML(ctx, telegramID, "📝 Спасибо, добавили к жалобе."); err != nil {
+		h.logger.Warn("failed to confirm report reason", "report_id", report.ID, "error", err)
+	}
+	h.notifyAdmins(ctx, fmt.Sprintf("📝 <b>Причина к жалобе #%d</b>\n\n%s", report.ID, html.EscapeString(report.Reason)), nil)
+	return true
+}
+
+// review applies an admin decision.
+func (h *ReportHandler) review(ctx context.Context, cbCtx CallbackContext, action string, reportID int64) error {
+	if !h.admins[cbCtx.TelegramID] {
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "⛔ Решение принимают администраторы.", true)
+	}
+
+	var (
+		result *command.ReviewReportResult
+		err    error
+	)
+	switch action {
+	case "restore":
+		result, err = h.reviewCmd.RestoreHelpMatching(ctx, reportID, cbCtx.TelegramID)
+	case "uphold":
+		result, err = h.reviewCmd.Handle(ctx, command.ReviewReportCommand{ReportID: reportID, Decision: command.ReportUphold, AdminID: cbCtx.TelegramID})
+	default:
+		result, err = h.reviewCmd.Handle(ctx, command.ReviewReportCommand{ReportID: reportID, Decision: command.ReportDismiss, AdminID: cbCtx.TelegramID})
+	}
+
+	switch {
+	case errors.Is(err, social.ErrReportResolved):
+		_ = cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "Решение уже принято.", false)
+		return h.edit(ctx, cbCtx, result)
+	case errors.Is(err, social.ErrReportNotFound):
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "Жалоба не найдена.", true)
+	case err != nil:
+		h.logger.Warn("report review failed", "report_id", reportID, "admin_id", student.TelegramID(cbCtx.TelegramID), "action", action, "error", err)
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Не удалось сохранить решение.", true)
+	}
+
+	h.logger.Info("report reviewed",
+		"report_id", reportID,
+		"action", action,
+		"reported_id", result.Report.ReportedID,
+		"upheld_count", result.UpheldCount,
+		"matching_disabled", result.MatchingDisabled,
+		"admin_id", student.TelegramID(cbCtx.TelegramID),
+	)
+	return h.edit(ctx, cbCtx, result)
+}
+
+// edit replaces the admin alert with the current state of the report.
+func (h *ReportHandler) edit(ctx context.Context, cbCtx CallbackContext, result *command.ReviewReportResult) error {
+	text := formatReportAlert(result.Report, result.Reporter, result.Reported, "") + "\n\n" + formatReportOutcome(result, h.reviewCmd.Threshold())
+
+	keyboard := &telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{}}
+	if result.Report.IsPending() {
+		keyboard = reportDecisionKeyboard(result.Report.ID)
+	} else if result.Reported != nil && result.Reported.HelpMatchingDisabled {
+		keyboard = reportRestoreKeyboard(result.Report.ID)
+	}
+
+	_, err := cbCtx.Client.EditMessageText(ctx, cbCtx.ChatID, int64(cbCtx.MessageID), text, "HTML", keyboard)
+	return err
+}
+
+// notifyAdmins sends a message to every admin in private.
+func (h *ReportHandler) notifyAdmins(ctx context.Context, text string, keyboard *telegram.InlineKeyboardMarkup) {
+	if len(h.adminIDs) == 0 {
+		h.logger.Warn("no admins to review reports")
+		return
+	}
+
+	for _, adminID := range h.adminIDs {
+		var err error
+		if keyboard != nil {
+			_, err = h.client.SendWithKeyboard(ctx, adminID, text, keyboard.InlineKeyboard)
+		} else {
+			_, err = h.client.SendHTML(ctx, adminID, text)
+		}
+		if err != nil {
+			h.logger.Warn("failed to notify admin about report", "admin_id", student.TelegramID(adminID), "error", err)
+		}
+	}
+}
+
+// PendingReportsHandler handles the admin-only /reports command.
+type PendingReportsHandler struct {
+	reports *ReportHandler
+	repo    social.ReportRepository
+}
+
+// NewPendingReportsHandler creates a new PendingReportsHandler.
+// Register it behind Router.AdminOnly.
+func NewPendingReportsHandler(reports *ReportHandler, repo social.ReportRepository) *PendingReportsHandler {
+	return &PendingReportsHandler{reports: reports, repo: repo}
+}
+
+// Handle lists pending reports, each with its decision buttons.
+func (h *PendingReportsHandler) Handle(ctx context.Context, cmdCtx CommandContext) error {
+	pending, err := h.repo.ListPending(ctx, pendingReportsLimit)
+	if err != nil {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, "❌ Не удалось загрузить жалобы.")
+		return err
+	}
+	if len(pending) == 0 {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, "✅ Жалоб на проверке нет.")
+		return err
+	}
+
+	if _, err := cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, fmt.Sprintf("⚠️ <b>Жалобы на проверке:</b> %d (старые первыми)", len(pending))); err != nil {
+		return err
+	}
+	for _, report := range pending {
+		var reporter, reported *student.Student
+		if s, err := h.reports.studentRepo.GetByID(ctx, string(report.ReporterID)); err == nil {
+			reporter = s
+		}
+		if s, err := h.reports.studentRepo.GetByID(ctx, string(report.ReportedID)); err == nil {
+			reported = s
+		}
+		text := formatReportAlert(report, reporter, reported, "")
+		if _, err := cmdCtx.Client.SendWithKeyboard(ctx, cmdCtx.ChatID, text, reportDecisionKeyboard(report.ID).InlineKeyboard); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportDecisionKeyboard returns the uphold/dismiss buttons.
+func reportDecisionKeyboard(reportID int64) *telegram.InlineKeyboardMarkup {
+	return &telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{{
+		{Text: "✅ Подтвердить", CallbackData: fmt.Sprintf("%suphold:%d", reportCallbackPrefix, reportID)},
+		{Text: "🚫 Отклонить", CallbackData: fmt.Sprintf("%sdismiss:%d", reportCallbackPrefix, reportID)},
+	}}}
+}
+
+// reportRestoreKeyboard returns the button that restores helper matching.
+func reportRestoreKeyboard(reportID int64) *telegram.InlineKeyboardMarkup {
+	return &telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{{
+		{Text: "↩️ Вернуть в подбор помощников", CallbackData: fmt.Sprintf("%srestore:%d", reportCallbackPrefix, reportID)},
+	}}}
+}
+
+// formatReportAlert renders a report with the context of the introduction.
+func formatReportAlert(report *social.Report, reporter, reported *student.Student, taskID string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("⚠️ <b>Жалоба #%d</b>\n\n", report.ID))
+	sb.WriteString(fmt.Sprintf("От: %s\n", formatReportStudent(string(report.ReporterID), reporter)))
+	sb.WriteString(fmt.Sprintf("На: %s\n", formatReportStudent(string(report.ReportedID), reported)))
+
+	switch report.Context {
+	case social.ReportContextHelpRequest:
+		sb.WriteString(fmt.Sprintf("Где: запрос помощи <code>%s</code>\n", html.EscapeString(report.ContextID)))
+	default:
+		sb.WriteString(fmt.Sprintf("Где: контакт через «Написать» <code>%s</code>\n", html.EscapeString(report.ContextID)))
+	}
+	if taskID != "" {
+		sb.WriteString(fmt.Sprintf("Задача: <code>%s</code>\n", html.EscapeString(taskID)))
+	}
+	sb.WriteString(fmt.Sprintf("Когда: %s", report.CreatedAt.Format("02.01.2006 15:04")))
+
+	if report.Reason != "" {
+		sb.WriteString(fmt.Sprintf("\n\n📝 %s", html.EscapeString(report.Reason)))
+	}
+	return sb.String()
+}
+
+// formatReportStudent renders a student with a link to their chat.
+func formatReportStudent(id string, s *student.Student) string {
+	if s == nil {
+		return fmt.Sprintf("<code>%s</code>", html.EscapeString(id))
+	}
+	return fmt.Sprintf("%s <code>%s</code>", presenter.Mention(int64(s.TelegramID), s.DisplayName), html.EscapeString(s.ID))
+}
+
+// formatReportOutcome renders the decision and its effect on helper matching.
+func formatReportOutcome(result *command.ReviewReportResult, threshold int) string {
+	report := result.Report
+
+	var sb strings.Builder
+	switch report.Status {
+	case social.ReportUpheld:
+		sb.WriteString(fmt.Sprintf("✅ Подтверждена администратором <code>%d</code>.", report.ResolvedBy))
+	case social.ReportDismissed:
+		sb.WriteString(fmt.Sprintf("🚫 Отклонена администратором <code>%d</code>, не учитывается.", report.ResolvedBy))
+	default:
+		sb.WriteString("⏳ Ждёт решения.")
+	}
+	if report.Status == social.ReportUpheld && result.UpheldCount > 0 {
+		sb.WriteString(fmt.Sprintf("\nПодтверждённых жалоб: %d из %d.", result.UpheldCount, threshold))
+	}
+
+	switch {
+	case result.MatchingDisabled:
+		sb.WriteString("\n⛔ Студент исключён из подбора помощников до проверки куратором.")
+	case result.Reported != nil && result.Reported.HelpMatchingDisabled:
+		sb.WriteString("\n⛔ Студент исключён из подбора помощников.")
+	case result.Reported != nil && report.Status != social.ReportPending:
+		sb.WriteString("\nСтудент участвует в подборе помощников.")
+	}
+	return sb.String()
+}
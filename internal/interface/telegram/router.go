@@ -11,6 +11,7 @@ import (
 
 	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
 	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/handler"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/handler/callback"
 	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
 )
 
@@ -34,6 +35,9 @@ type RouterConfig struct {
 
 // CommandContext contains context for command handling.
 type CommandContext struct {
+	// Command is the command name (without /); set by the router.
+	Command string
+
 	// TelegramID is the user's Telegram ID.
 	TelegramID int64
 
@@ -132,8 +136,10 @@ type Router struct {
 	config RouterConfig
 	logger *slog.Logger
 
-	// Command handlers by command name (without /)
+	// Command handlers by command name (without /), and the middleware
+	// registered with them
 	commandHandlers   map[string]interface{}
+	commandMiddleware map[string]CommandMiddleware
 	commandHandlersMu sync.RWMutex
 
 	// Callback handlers by prefix
@@ -159,6 +165,7 @@ func NewRouter(config RouterConfig) *Router {
 		config:                 config,
 		logger:                 config.Logger,
 		commandHandlers:        make(map[string]interface{}),
+		commandMiddleware:      make(map[string]CommandMiddleware),
 		callbackPrefixHandlers: make(map[string]interface{}),
 	}
 
@@ -174,12 +181,18 @@ func NewRouter(config RouterConfig) *Router {
 // ══════════════════════════════════════════════════════════════════════════════
 
 // RegisterCommand registers a handler for a specific command.
-// The command should be without the leading "/".
-func (r *Router) RegisterCommand(command string, handler interface{}) {
+// The command should be without the leading "/". The middleware wraps the
+// handler, outermost first, inside the bot's global chain.
+func (r *Router) RegisterCommand(command string, handler interface{}, middleware ...CommandMiddleware) {
 	r.commandHandlersMu.Lock()
 	defer r.commandHandlersMu.Unlock()
 
 	r.commandHandlers[command] = handler
+	if len(middleware) > 0 {
+		r.commandMiddleware[command] = ChainCommands(middleware...)
+	} else {
+		delete(r.commandMiddleware, command)
+	}
 
 	if r.config.Debug {
 		r.logger.Debug("registered command handler", "command", command)
@@ -223,8 +236,11 @@ func (r *Router) SetDefaultCallbackHandler(handler func(ctx context.Context, cbC
 
 // HandleCommand routes a command to its handler.
 func (r *Router) HandleCommand(ctx context.Context, command string, cmdCtx CommandContext) error {
+	cmdCtx.Command = command
+
 	r.commandHandlersMu.RLock()
 	h, ok := r.commandHandlers[command]
+	mw := r.commandMiddleware[command]
 	r.commandHandlersMu.RUnlock()
 
 	if !ok {
@@ -234,7 +250,17 @@ func (r *Router) HandleCommand(ctx context.Context, command string, cmdCtx Comma
 		return r.defaultCommandHandler(ctx, cmdCtx)
 	}
 
-	return r.executeCommandHandler(ctx, h, command, cmdCtx)
+	return withCommandMiddleware(mw, func(ctx context.Context, cmdCtx CommandContext) error {
+		return r.executeCommandHandler(ctx, h, command, cmdCtx)
+	}).Handle(ctx, cmdCtx)
+}
+
+// withCommandMiddleware wraps fn in the command's middleware, if any.
+func withCommandMiddleware(mw CommandMiddleware, fn CommandHandlerFunc) CommandHandler {
+	if mw == nil {
+		return fn
+	}
+	return mw(fn)
 }
 
 // executeCommandHandler executes a command handler based on its type.
@@ -254,6 +280,26 @@ func (r *Router) executeCommandHandler(ctx context.Context, h interface{}, comma
 		return r.handleHelpCommand(ctx, handler, cmdCtx)
 	case *handler.SettingsHandler:
 		return r.handleSettingsCommand(ctx, handler, cmdCtx)
+	case *handler.NotificationsHandler:
+		return r.handleNotificationsCommand(ctx, handler, cmdCtx)
+	case *handler.AchievementsHandler:
+		return r.handleAchievementsCommand(ctx, handler, cmdCtx)
+	case *handler.GoalHandler:
+		return r.handleGoalCommand(ctx, handler, cmdCtx)
+	case *handler.ForecastHandler:
+		return r.handleForecastCommand(ctx, handler, cmdCtx)
+	case *handler.StreakHandler:
+		return r.handleStreakCommand(ctx, handler, cmdCtx)
+	case *handler.PopularTasksHandler:
+		return r.handlePopularTasksCommand(ctx, handler, cmdCtx)
+	case *handler.MuteHandler:
+		return r.handleMuteCommand(ctx, handler, command, cmdCtx)
+	case *handler.PrivacyHandler:
+		return r.handlePrivacyCommand(ctx, handler, cmdCtx)
+	case *handler.TimezoneHandler:
+		return r.handleTimezoneCommand(ctx, handler, cmdCtx)
+	case *handler.EventHandler:
+		return r.handleEventCommand(ctx, handler, cmdCtx)
 	case CommandHandler:
 		return handler.Handle(ctx, cmdCtx)
 	default:
@@ -264,20 +310,7 @@ func (r *Router) executeCommandHandler(ctx context.Context, h interface{}, comma
 
 // HandleCallback routes a callback to its handler.
 func (r *Router) HandleCallback(ctx context.Context, data string, cbCtx CallbackContext) error {
-	r.callbackPrefixHandlersMu.RLock()
-	var matchedPrefix string
-	var matchedHandler interface{}
-	for prefix, h := range r.callbackPrefixHandlers {
-		if strings.HasPrefix(data, prefix) {
-			// Find the longest matching prefix
-			if len(prefix) > len(matchedPrefix) {
-				matchedPrefix = prefix
-				matchedHandler = h
-			}
-		}
-	}
-	r.callbackPrefixHandlersMu.RUnlock()
-
+	matchedPrefix, matchedHandler := r.matchCallbackPrefix(data)
 	if matchedHandler == nil {
 		if r.config.Debug {
 			r.logger.Debug("no handler for callback", "data", data)
@@ -288,6 +321,22 @@ func (r *Router) HandleCallback(ctx context.Context, data string, cbCtx Callback
 	return r.executeCallbackHandler(ctx, matchedHandler, matchedPrefix, cbCtx)
 }
 
+// matchCallbackPrefix finds the handler with the longest prefix matching data.
+func (r *Router) matchCallbackPrefix(data string) (string, interface{}) {
+	r.callbackPrefixHandlersMu.RLock()
+	defer r.callbackPrefixHandlersMu.RUnlock()
+
+	var matchedPrefix string
+	var matchedHandler interface{}
+	for prefix, h := range r.callbackPrefixHandlers {
+		if strings.HasPrefix(data, prefix) && len(prefix) > len(matchedPrefix) {
+			matchedPrefix = prefix
+			matchedHandler = h
+		}
+	}
+	return matchedPrefix, matchedHandler
+}
+
 // executeCallbackHandler executes a callback handler based on its type.
 func (r *Router) executeCallbackHandler(ctx context.Context, h interface{}, prefix string, cbCtx CallbackContext) error {
 	switch handler := h.(type) {
@@ -349,7 +398,13 @@ func (r *Router) handleStartCommand(ctx context.Context, h *handler.StartHandler
 		return err
 	}
 
-	return r.sendResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, resp.Text, resp.ParseMode, resp.Keyboard)
+	if err := r.sendResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, resp.Text, resp.ParseMode, resp.Keyboard); err != nil {
+		return err
+	}
+	if resp.QuickActions && cmdCtx.Message != nil {
+		r.attachQuickActions(ctx, cmdCtx.Client, cmdCtx.Message.Chat, cmdCtx.Message.From, true)
+	}
+	return nil
 }
 
 func (r *Router) handleMeCommand(ctx context.Context, h *handler.MeHandler, cmdCtx CommandContext) error {
@@ -369,12 +424,15 @@ func (r *Router) handleMeCommand(ctx context.Context, h *handler.MeHandler, cmdC
 }
 
 func (r *Router) handleTopCommand(ctx context.Context, h *handler.TopHandler, cmdCtx CommandContext) error {
+	// "/top week" (or "/top неделя") opens the weekly ranking
+	arg := strings.ToLower(strings.TrimSpace(cmdCtx.Args))
 	req := handler.TopRequest{
 		TelegramID: cmdCtx.TelegramID,
 		ChatID:     cmdCtx.ChatID,
 		MessageID:  cmdCtx.MessageID,
 		Cohort:     "",
 		Limit:      10,
+		Weekly:     arg == "week" || arg == "неделя",
 		IsRefresh:  false,
 	}
 
@@ -449,6 +507,139 @@ func (r *Router) handleSettingsCommand(ctx context.Context, h *handler.SettingsH
 	return r.sendResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, resp.Text, resp.ParseMode, resp.Keyboard)
 }
 
+func (r *Router) handleGoalCommand(ctx context.Context, h *handler.GoalHandler, cmdCtx CommandContext) error {
+	req := handler.GoalRequest{
+		TelegramID: cmdCtx.TelegramID,
+		ChatID:     cmdCtx.ChatID,
+		Args:       cmdCtx.Args,
+	}
+
+	resp, err := h.Handle(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return r.sendResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, resp.Text, resp.ParseMode, nil)
+}
+
+func (r *Router) handleForecastCommand(ctx context.Context, h *handler.ForecastHandler, cmdCtx CommandContext) error {
+	req := handler.ForecastRequest{
+		TelegramID: cmdCtx.TelegramID,
+		ChatID:     cmdCtx.ChatID,
+		Args:       cmdCtx.Args,
+	}
+
+	resp, err := h.Handle(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return r.sendResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, resp.Text, resp.ParseMode, nil)
+}
+
+func (r *Router) handleStreakCommand(ctx context.Context, h *handler.StreakHandler, cmdCtx CommandContext) error {
+	resp, err := h.Handle(ctx, handler.StreakRequest{
+		TelegramID: cmdCtx.TelegramID,
+		ChatID:     cmdCtx.ChatID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.sendResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, resp.Text, resp.ParseMode, nil)
+}
+
+func (r *Router) handlePopularTasksCommand(ctx context.Context, h *handler.PopularTasksHandler, cmdCtx CommandContext) error {
+	resp, err := h.Handle(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.sendResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, resp.Text, resp.ParseMode, resp.Keyboard)
+}
+
+func (r *Router) handleMuteCommand(ctx context.Context, h *handler.MuteHandler, command string, cmdCtx CommandContext) error {
+	var resp *handler.MuteResponse
+	var err error
+	if command == "unmute" {
+		resp, err = h.Unmute(ctx, cmdCtx.TelegramID)
+	} else {
+		resp, err = h.Handle(ctx, handler.MuteRequest{
+			TelegramID: cmdCtx.TelegramID,
+			ChatID:     cmdCtx.ChatID,
+			Args:       cmdCtx.Args,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	return r.sendResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, resp.Text, resp.ParseMode, resp.Keyboard)
+}
+
+func (r *Router) handlePrivacyCommand(ctx context.Context, h *handler.PrivacyHandler, cmdCtx CommandContext) error {
+	resp, err := h.Handle(ctx, cmdCtx.TelegramID)
+	if err != nil {
+		return err
+	}
+
+	return r.sendResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, resp.Text, resp.ParseMode, resp.Keyboard)
+}
+
+func (r *Router) handleTimezoneCommand(ctx context.Context, h *handler.TimezoneHandler, cmdCtx CommandContext) error {
+	resp, err := h.Handle(ctx, cmdCtx.TelegramID, cmdCtx.Args)
+	if err != nil {
+		return err
+	}
+
+	return r.sendResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, resp.Text, resp.ParseMode, resp.Keyboard)
+}
+
+func (r *Router) handleEventCommand(ctx context.Context, h *handler.EventHandler, cmdCtx CommandContext) error {
+	resp, err := h.Handle(ctx, handler.EventRequest{
+		TelegramID: cmdCtx.TelegramID,
+		ChatID:     cmdCtx.ChatID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.sendResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, resp.Text, resp.ParseMode, nil)
+}
+
+func (r *Router) handleNotificationsCommand(ctx context.Context, h *handler.NotificationsHandler, cmdCtx CommandContext) error {
+	page, _ := strconv.Atoi(strings.TrimSpace(cmdCtx.Args))
+
+	req := handler.NotificationsRequest{
+		TelegramID: cmdCtx.TelegramID,
+		ChatID:     cmdCtx.ChatID,
+		MessageID:  cmdCtx.MessageID,
+		Page:       page,
+	}
+
+	resp, err := h.Handle(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return r.sendResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, resp.Text, resp.ParseMode, resp.Keyboard)
+}
+
+func (r *Router) handleAchievementsCommand(ctx context.Context, h *handler.AchievementsHandler, cmdCtx CommandContext) error {
+	req := handler.AchievementsRequest{
+		TelegramID: cmdCtx.TelegramID,
+		ChatID:     cmdCtx.ChatID,
+		MessageID:  cmdCtx.MessageID,
+	}
+
+	resp, err := h.Handle(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return r.sendResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, resp.Text, resp.ParseMode, resp.Keyboard)
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // CALLBACK HANDLER FACTORY METHODS
 // Create callback handlers for inline keyboard interactions.
@@ -482,14 +673,24 @@ func (r *Router) createCommandCallbackHandler() func(ctx context.Context, cbCtx
 
 // HandleCommandWithEdit handles a command but edits existing message instead of sending new.
 func (r *Router) HandleCommandWithEdit(ctx context.Context, command string, cmdCtx CommandContext) error {
+	cmdCtx.Command = command
+
 	r.commandHandlersMu.RLock()
 	h, ok := r.commandHandlers[command]
+	mw := r.commandMiddleware[command]
 	r.commandHandlersMu.RUnlock()
 
 	if !ok {
 		return nil
 	}
 
+	return withCommandMiddleware(mw, func(ctx context.Context, cmdCtx CommandContext) error {
+		return r.editCommand(ctx, h, command, cmdCtx)
+	}).Handle(ctx, cmdCtx)
+}
+
+// editCommand runs the handler, editing the message when it supports that.
+func (r *Router) editCommand(ctx context.Context, h interface{}, command string, cmdCtx CommandContext) error {
 	// Special handling for editing vs sending
 	// Note: We use 'hnd' as the variable name to avoid shadowing the 'handler' package import
 	switch hnd := h.(type) {
@@ -556,6 +757,32 @@ func (r *Router) HandleCommandWithEdit(ctx context.Context, command string, cmdC
 		}
 		return r.editResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, cmdCtx.MessageID, resp.Text, resp.ParseMode, resp.Keyboard)
 
+	case *handler.NotificationsHandler:
+		req := handler.NotificationsRequest{
+			TelegramID: cmdCtx.TelegramID,
+			ChatID:     cmdCtx.ChatID,
+			MessageID:  cmdCtx.MessageID,
+			IsRefresh:  true,
+		}
+		resp, err := hnd.Handle(ctx, req)
+		if err != nil {
+			return err
+		}
+		return r.editResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, cmdCtx.MessageID, resp.Text, resp.ParseMode, resp.Keyboard)
+
+	case *handler.AchievementsHandler:
+		req := handler.AchievementsRequest{
+			TelegramID: cmdCtx.TelegramID,
+			ChatID:     cmdCtx.ChatID,
+			MessageID:  cmdCtx.MessageID,
+			IsRefresh:  true,
+		}
+		resp, err := hnd.Handle(ctx, req)
+		if err != nil {
+			return err
+		}
+		return r.editResponse(ctx, cmdCtx.Client, cmdCtx.ChatID, cmdCtx.MessageID, resp.Text, resp.ParseMode, resp.Keyboard)
+
 	default:
 		return r.executeCommandHandler(ctx, h, command, cmdCtx)
 	}
@@ -582,43 +809,40 @@ func (r *Router) createRefreshCallbackHandler() func(ctx context.Context, cbCtx
 	}
 }
 
-// createTopCallbackHandler creates a handler for "top:" callbacks (pagination, filtering).
+// createTopCallbackHandler creates a handler for "top:" callbacks: pages
+// ("top:page=3:cohort=2024-spring", see presenter.TopPage) and "top:helpers".
+// The bot answers the callback query itself, so the spinner stops either way.
 func (r *Router) createTopCallbackHandler(topHandler *handler.TopHandler) func(ctx context.Context, cbCtx CallbackContext) error {
 	return func(ctx context.Context, cbCtx CallbackContext) error {
-		// Parse callback data: "top:page:2:cohort" or "top:filter:10:cohort"
-		parts := strings.Split(cbCtx.Data, ":")
-		if len(parts) < 2 {
-			return nil
-		}
-
-		action := parts[1]
-		limit := 10
-		cohort := ""
-
-		switch action {
-		case "page", "refresh", "filter":
-			if len(parts) >= 3 {
-				limit, _ = strconv.Atoi(parts[2])
-			}
-			if len(parts) >= 4 {
-				cohort = parts[3]
+		if cbCtx.Data == "top:helpers" {
+			resp, err := topHandler.HandleMonthlyHelpers(ctx, handler.TopRequest{
+				TelegramID: cbCtx.TelegramID,
+				ChatID:     cbCtx.ChatID,
+				MessageID:  cbCtx.MessageID,
+				IsRefresh:  true,
+			})
+			if err != nil {
+				return err
 			}
+			return r.editResponse(ctx, cbCtx.Client, cbCtx.ChatID, cbCtx.MessageID, resp.Text, resp.ParseMode, resp.Keyboard)
 		}
 
-		if limit < 1 {
-			limit = 10
+		// Buttons of messages sent before the current format open the first page
+		page, err := presenter.ParseTopPage(cbCtx.Data)
+		if err != nil {
+			page = presenter.TopPage{Page: 1}
 		}
 
-		req := handler.TopRequest{
+		resp, err := topHandler.Handle(ctx, handler.TopRequest{
 			TelegramID: cbCtx.TelegramID,
 			ChatID:     cbCtx.ChatID,
 			MessageID:  cbCtx.MessageID,
-			Limit:      limit,
-			Cohort:     cohort,
+			Page:       page.Page,
+			Cohort:     page.Cohort,
+			OnlyOnline: page.OnlyOnline,
+			Weekly:     page.Weekly,
 			IsRefresh:  true,
-		}
-
-		resp, err := topHandler.Handle(ctx, req)
+		})
 		if err != nil {
 			return err
 		}
@@ -720,29 +944,286 @@ func (r *Router) createSettingsCallbackHandler(settingsHandler *handler.Settings
 			return nil
 		}
 
-		return r.editResponse(ctx, cbCtx.Client, cbCtx.ChatID, cbCtx.MessageID, resp.Text, resp.ParseMode, resp.Keyboard)
+		if err := r.editResponse(ctx, cbCtx.Client, cbCtx.ChatID, cbCtx.MessageID, resp.Text, resp.ParseMode, resp.Keyboard); err != nil {
+			return err
+		}
+		if resp.QuickActions != nil && cbCtx.Query != nil && cbCtx.Query.Message != nil {
+			r.attachQuickActions(ctx, cbCtx.Client, cbCtx.Query.Message.Chat, cbCtx.Query.From, *resp.QuickActions)
+		}
+		return nil
+	}
+}
+
+// createSettingsHubCallbackHandler creates a handler for the signed
+// /settings hub callbacks (handler.SettingsHubPrefix).
+func (r *Router) createSettingsHubCallbackHandler(settingsHandler *handler.SettingsHandler) func(ctx context.Context, cbCtx CallbackContext) error {
+	return func(ctx context.Context, cbCtx CallbackContext) error {
+		resp, err := settingsHandler.HandleHubCallback(ctx, cbCtx.TelegramID, cbCtx.Data)
+		if err != nil {
+			return err
+		}
+
+		if err := r.editResponse(ctx, cbCtx.Client, cbCtx.ChatID, cbCtx.MessageID, resp.Text, resp.ParseMode, resp.Keyboard); err != nil {
+			return err
+		}
+		if resp.QuickActions != nil && cbCtx.Query != nil && cbCtx.Query.Message != nil {
+			r.attachQuickActions(ctx, cbCtx.Client, cbCtx.Query.Message.Chat, cbCtx.Query.From, *resp.QuickActions)
+		}
+		return nil
 	}
 }
 
 // createHelpCallbackHandler creates a handler for "help:" callbacks.
 func (r *Router) createHelpCallbackHandler(helpHandler *handler.HelpHandler) func(ctx context.Context, cbCtx CallbackContext) error {
 	return func(ctx context.Context, cbCtx CallbackContext) error {
-		// Parse: "help:refresh:task_id"
-		parts := strings.Split(cbCtx.Data, ":")
+		// Parse: "help:refresh:task_id", "help:request:task_id",
+		// "help:group:request_id"
+		parts := strings.SplitN(cbCtx.Data, ":", 3)
 		if len(parts) < 3 {
 			return nil
 		}
 
-		taskID := parts[2]
-
 		req := handler.HelpRequest{
 			TelegramID: cbCtx.TelegramID,
 			ChatID:     cbCtx.ChatID,
 			MessageID:  cbCtx.MessageID,
-			TaskID:     taskID,
 		}
 
-		resp, err := helpHandler.Handle(ctx, req)
+		var resp *handler.HelpResponse
+		var err error
+		switch parts[1] {
+		case "request":
+			req.TaskID = parts[2]
+			resp, err = helpHandler.RequestHelp(ctx, req)
+		case "group":
+			req.HelpRequestID = parts[2]
+			resp, err = helpHandler.JoinGroup(ctx, req)
+		default:
+			req.TaskID = parts[2]
+			resp, err = helpHandler.Handle(ctx, req)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, notice := range resp.Notices {
+			_, _ = cbCtx.Client.SendHTML(ctx, notice.TelegramID, notice.Text)
+		}
+
+		return r.editResponse(ctx, cbCtx.Client, cbCtx.ChatID, cbCtx.MessageID, resp.Text, resp.ParseMode, resp.Keyboard)
+	}
+}
+
+// createEndorseCallbackHandler creates a handler for "endorse:" callbacks.
+func (r *Router) createEndorseCallbackHandler(endorseHandler *callback.EndorseHandler) func(ctx context.Context, cbCtx CallbackContext) error {
+	return func(ctx context.Context, cbCtx CallbackContext) error {
+		// Parse: "endorse:cancel", "endorse:req:request_id:rating",
+		// "endorse:student_id:rating:task_id"
+		if cbCtx.Data == "endorse:cancel" {
+			return removeKeyboard(ctx, cbCtx)
+		}
+
+		req := callback.EndorseRequest{
+			TelegramID:      cbCtx.TelegramID,
+			CallbackQueryID: cbCtx.QueryID,
+			ChatID:          cbCtx.ChatID,
+			MessageID:       cbCtx.MessageID,
+		}
+		if helpRequestID, rating, ok := callback.ParseRequestEndorseCallbackData(cbCtx.Data); ok {
+			req.HelpRequestID = helpRequestID
+			req.Rating = rating
+		} else {
+			req.HelperStudentID, req.Rating, req.TaskID = callback.ParseEndorseCallbackData(cbCtx.Data)
+		}
+
+		resp, err := endorseHandler.Handle(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		if resp.AnswerText != "" {
+			_ = cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, resp.AnswerText, resp.ShowAlert)
+		}
+
+		switch {
+		case resp.UpdatedText != "":
+			return r.editResponse(ctx, cbCtx.Client, cbCtx.ChatID, cbCtx.MessageID, resp.UpdatedText, resp.ParseMode, resp.UpdatedKeyboard)
+		case resp.RemoveKeyboard:
+			return removeKeyboard(ctx, cbCtx)
+		default:
+			return nil
+		}
+	}
+}
+
+// createConnectCallbackHandler creates a handler for "connect:" callbacks.
+func (r *Router) createConnectCallbackHandler(connectHandler *callback.ConnectHandler) func(ctx context.Context, cbCtx CallbackContext) error {
+	return func(ctx context.Context, cbCtx CallbackContext) error {
+		// Parse: "connect:student_id:context:task_id"
+		studentID, connContext, taskID := callback.ParseConnectCallbackData(cbCtx.Data)
+		if studentID == "" {
+			return nil
+		}
+
+		resp, err := connectHandler.Handle(ctx, callback.ConnectRequest{
+			TelegramID:      cbCtx.TelegramID,
+			TargetStudentID: studentID,
+			Context:         connContext,
+			TaskID:          taskID,
+			CallbackQueryID: cbCtx.QueryID,
+			ChatID:          cbCtx.ChatID,
+			MessageID:       cbCtx.MessageID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if resp.AnswerText != "" {
+			_ = cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, resp.AnswerText, resp.ShowAlert)
+		}
+
+		// The contact card is a new message: the original one may be a
+		// list that the student wants to keep
+		if resp.UpdatedText == "" {
+			return nil
+		}
+		return r.sendResponse(ctx, cbCtx.Client, cbCtx.ChatID, resp.UpdatedText, resp.ParseMode, resp.UpdatedKeyboard)
+	}
+}
+
+// createFeedbackCallbackHandler creates a handler for "feedback:" callbacks.
+func (r *Router) createFeedbackCallbackHandler(feedbackHandler *callback.FeedbackHandler) func(ctx context.Context, cbCtx CallbackContext) error {
+	return func(ctx context.Context, cbCtx CallbackContext) error {
+		// Parse: "feedback:request_id:score"
+		helpRequestID, score, ok := callback.ParseFeedbackCallbackData(cbCtx.Data)
+		if !ok {
+			return nil
+		}
+
+		resp, err := feedbackHandler.Handle(ctx, callback.FeedbackRequest{
+			TelegramID:    cbCtx.TelegramID,
+			HelpRequestID: helpRequestID,
+			Score:         score,
+		})
+		if err != nil {
+			return err
+		}
+
+		if resp.AnswerText != "" {
+			_ = cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, resp.AnswerText, resp.ShowAlert)
+		}
+
+		switch {
+		case resp.UpdatedText != "":
+			return r.editResponse(ctx, cbCtx.Client, cbCtx.ChatID, cbCtx.MessageID, resp.UpdatedText, resp.ParseMode, nil)
+		case resp.RemoveKeyboard:
+			return removeKeyboard(ctx, cbCtx)
+		default:
+			return nil
+		}
+	}
+}
+
+// removeKeyboard removes the inline buttons from the callback's message.
+func removeKeyboard(ctx context.Context, cbCtx CallbackContext) error {
+	empty := &telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{}}
+	_, err := cbCtx.Client.EditMessageKeyboard(ctx, cbCtx.ChatID, int64(cbCtx.MessageID), empty)
+	return err
+}
+
+// createNotificationsCallbackHandler creates a handler for "notif:" callbacks.
+func (r *Router) createNotificationsCallbackHandler(notificationsHandler *handler.NotificationsHandler) func(ctx context.Context, cbCtx CallbackContext) error {
+	return func(ctx context.Context, cbCtx CallbackContext) error {
+		// Parse: "notif:page:2", "notif:read_all:2"
+		parts := strings.Split(cbCtx.Data, ":")
+		if len(parts) < 2 {
+			return nil
+		}
+
+		page := 1
+		if len(parts) >= 3 {
+			page, _ = strconv.Atoi(parts[2])
+		}
+
+		req := handler.NotificationsRequest{
+			TelegramID: cbCtx.TelegramID,
+			ChatID:     cbCtx.ChatID,
+			MessageID:  cbCtx.MessageID,
+			Page:       page,
+			IsRefresh:  true,
+		}
+
+		var resp *handler.NotificationsResponse
+		var err error
+		switch parts[1] {
+		case "read_all":
+			resp, err = notificationsHandler.MarkAllRead(ctx, req)
+		default:
+			resp, err = notificationsHandler.Handle(ctx, req)
+		}
+		if err != nil {
+			return err
+		}
+
+		return r.editResponse(ctx, cbCtx.Client, cbCtx.ChatID, cbCtx.MessageID, resp.Text, resp.ParseMode, resp.Keyboard)
+	}
+}
+
+// createMuteCallbackHandler creates a handler for "mute:" callbacks.
+func (r *Router) createMuteCallbackHandler(muteHandler *handler.MuteHandler) func(ctx context.Context, cbCtx CallbackContext) error {
+	return func(ctx context.Context, cbCtx CallbackContext) error {
+		// Parse: "mute:24h", "mute:off"
+		arg := strings.TrimPrefix(cbCtx.Data, "mute:")
+
+		var resp *handler.MuteResponse
+		var err error
+		if arg == "off" {
+			resp, err = muteHandler.Unmute(ctx, cbCtx.TelegramID)
+		} else {
+			resp, err = muteHandler.Handle(ctx, handler.MuteRequest{
+				TelegramID: cbCtx.TelegramID,
+				ChatID:     cbCtx.ChatID,
+				Args:       arg,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		return r.editResponse(ctx, cbCtx.Client, cbCtx.ChatID, cbCtx.MessageID, resp.Text, resp.ParseMode, resp.Keyboard)
+	}
+}
+
+// createPrivacyCallbackHandler creates a handler for "privacy:" callbacks.
+func (r *Router) createPrivacyCallbackHandler(privacyHandler *handler.PrivacyHandler) func(ctx context.Context, cbCtx CallbackContext) error {
+	return func(ctx context.Context, cbCtx CallbackContext) error {
+		// Parse: "privacy:toggle:hide_from_leaderboard"
+		var resp *handler.PrivacyResponse
+		var err error
+		if setting, ok := strings.CutPrefix(cbCtx.Data, "privacy:toggle:"); ok {
+			resp, err = privacyHandler.Toggle(ctx, cbCtx.TelegramID, setting)
+		} else {
+			resp, err = privacyHandler.Handle(ctx, cbCtx.TelegramID)
+		}
+		if err != nil {
+			return err
+		}
+
+		return r.editResponse(ctx, cbCtx.Client, cbCtx.ChatID, cbCtx.MessageID, resp.Text, resp.ParseMode, resp.Keyboard)
+	}
+}
+
+// createTimezoneCallbackHandler creates a handler for "timezone:" callbacks.
+func (r *Router) createTimezoneCallbackHandler(timezoneHandler *handler.TimezoneHandler) func(ctx context.Context, cbCtx CallbackContext) error {
+	return func(ctx context.Context, cbCtx CallbackContext) error {
+		// Parse: "timezone:set:Europe/Berlin"
+		var resp *handler.TimezoneResponse
+		var err error
+		if name, ok := strings.CutPrefix(cbCtx.Data, "timezone:set:"); ok {
+			resp, err = timezoneHandler.Set(ctx, cbCtx.TelegramID, name)
+		} else {
+			resp, err = timezoneHandler.Handle(ctx, cbCtx.TelegramID, "")
+		}
 		if err != nil {
 			return err
 		}
@@ -790,7 +1271,13 @@ func (r *Router) handleOnboardingInput(ctx context.Context, inputCtx TextInputCo
 		return err
 	}
 
-	return r.sendResponse(ctx, inputCtx.Client, inputCtx.ChatID, resp.Text, resp.ParseMode, resp.Keyboard)
+	if err := r.sendResponse(ctx, inputCtx.Client, inputCtx.ChatID, resp.Text, resp.ParseMode, resp.Keyboard); err != nil {
+		return err
+	}
+	if resp.QuickActions && inputCtx.Message != nil {
+		r.attachQuickActions(ctx, inputCtx.Client, inputCtx.Message.Chat, inputCtx.Message.From, true)
+	}
+	return nil
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -802,10 +1289,23 @@ func (r *Router) handleUnknownCommand(ctx context.Context, cmdCtx CommandContext
 	text := "❓ <b>Неизвестная команда</b>\n\n" +
 		"Доступные команды:\n" +
 		"• /me — твоя карточка\n" +
-		"• /top — лидерборд\n" +
+		"• /top — лидерборд (/top week — за неделю)\n" +
 		"• /neighbors — соседи по рангу\n" +
+		"• /compare @логин — ты и другой студент бок о бок\n" +
 		"• /online — кто сейчас онлайн\n" +
 		"• /help [задача] — найти помощь\n" +
+		"• /populartasks — горячие задачи недели\n" +
+		"• /notifications — уведомления\n" +
+		"• /achievements — достижения\n" +
+		"• /goal [XP] — цель на неделю\n" +
+		"• /streak — серия и календарь за 30 дней\n" +
+		"• /forecast [rank|level|xp N] — когда дойдёшь до цели\n" +
+		"• /focus — 50 минут фокуса, можно с напарником\n" +
+		"• /connections — напарники и подопечные\n" +
+		"• /event — челлендж сообщества\n" +
+		"• /mute [24h] — режим тишины\n" +
+		"• /privacy — кто что видит\n" +
+		"• /timezone — часовой пояс\n" +
 		"• /settings — настройки"
 
 	_, err := cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, text)
@@ -831,6 +1331,8 @@ func (r *Router) sendResponse(
 	text, parseMode string,
 	keyboard *presenter.InlineKeyboard,
 ) error {
+	text, keyboard = applyImpersonation(ctx, text, keyboard)
+
 	params := telegram.SendMessageParams{
 		ChatID:    chatID,
 		Text:      text,
@@ -841,11 +1343,13 @@ func (r *Router) sendResponse(
 		params.ReplyMarkup = convertKeyboard(keyboard)
 	}
 
-	_, err := client.SendMessage(ctx, params)
+	_, err := client.SendLong(ctx, params)
 	return err
 }
 
 // editResponse edits an existing message with optional inline keyboard.
+// Text over the Telegram limit replaces the message with its first chunk and
+// continues in new messages; the keyboard goes with the last one.
 func (r *Router) editResponse(
 	ctx context.Context,
 	client *telegram.Client,
@@ -854,13 +1358,45 @@ func (r *Router) editResponse(
 	text, parseMode string,
 	keyboard *presenter.InlineKeyboard,
 ) error {
+	text, keyboard = applyImpersonation(ctx, text, keyboard)
+
 	var kb *telegram.InlineKeyboardMarkup
 	if keyboard != nil {
 		kb = convertKeyboard(keyboard)
 	}
 
-	_, err := client.EditMessageText(ctx, chatID, int64(messageID), text, parseMode, kb)
-	return err
+	chunks := []string{text}
+	if parseMode == "HTML" {
+		chunks = telegram.SplitMessage(text, telegram.MessageLimit)
+	}
+	if len(chunks) == 1 {
+		_, err := client.EditMessageText(ctx, chatID, int64(messageID), text, parseMode, kb)
+		return err
+	}
+
+	if _, err := client.EditMessageText(ctx, chatID, int64(messageID), chunks[0], parseMode, nil); err != nil {
+		return err
+	}
+	for i, chunk := range chunks[1:] {
+		params := telegram.SendMessageParams{ChatID: chatID, Text: chunk, ParseMode: parseMode}
+		if i == len(chunks)-2 {
+			params.ReplyMarkup = kb
+		}
+		if _, err := client.SendMessage(ctx, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyImpersonation watermarks responses rendered for an admin via /as.
+// The keyboard is dropped: its callbacks would act as the admin, not the student.
+func applyImpersonation(ctx context.Context, text string, keyboard *presenter.InlineKeyboard) (string, *presenter.InlineKeyboard) {
+	view, ok := impersonationFromContext(ctx)
+	if !ok {
+		return text, keyboard
+	}
+	return view.watermark + "\n\n" + text, nil
 }
 
 // convertKeyboard converts presenter.InlineKeyboard to telegram.InlineKeyboardMarkup.
@@ -903,6 +1439,22 @@ func (r *Router) GetRegisteredCommands() []string {
 	return commands
 }
 
+// HasCommand reports whether a handler is registered for the command.
+func (r *Router) HasCommand(command string) bool {
+	r.commandHandlersMu.RLock()
+	defer r.commandHandlersMu.RUnlock()
+
+	_, ok := r.commandHandlers[command]
+	return ok
+}
+
+// MatchCallbackPrefix returns the registered prefix that would handle
+// the callback data, or "" if none does.
+func (r *Router) MatchCallbackPrefix(data string) string {
+	prefix, _ := r.matchCallbackPrefix(data)
+	return prefix
+}
+
 // GetRegisteredCallbackPrefixes returns a list of registered callback prefixes.
 func (r *Router) GetRegisteredCallbackPrefixes() []string {
 	r.callbackPrefixHandlersMu.RLock()
@@ -0,0 +1,84 @@
+package telegram
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/leaderboard"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/handler"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/middleware"
+	"github.com/alem-hub/alem-community-hub/internal/interface/telegram/presenter"
+)
+
+// countingStudentRepo counts every student lookup by Telegram ID.
+type countingStudentRepo struct {
+	fakeStudentRepo
+	lookups atomic.Int32
+}
+
+func (r *countingStudentRepo) GetByTelegramID(ctx context.Context, id student.TelegramID) (*student.Student, error) {
+	r.lookups.Add(1)
+	return r.fakeStudentRepo.GetByTelegramID(ctx, id)
+}
+
+// unrankedLeaderboard has no snapshot entry for anyone.
+type unrankedLeaderboard struct {
+	leaderboard.LeaderboardRepository
+}
+
+func (unrankedLeaderboard) GetStudentRank(context.Context, string, leaderboard.Cohort) (*leaderboard.LeaderboardEntry, error) {
+	return nil, nil
+}
+
+// emptyProgress has no progress recorded for anyone.
+type emptyProgress struct {
+	student.ProgressRepository
+}
+
+func (emptyProgress) GetTodayDailyGrind(context.Context, string) (*student.DailyGrind, error) {
+	return nil, student.ErrStudentNotFound
+}
+
+func TestBot_MeLoadsStudentOncePerUpdate(t *testing.T) {
+	repo := &countingStudentRepo{fakeStudentRepo: fakeStudentRepo{
+		byTelegramID: map[student.TelegramID]*student.Student{
+			42: {ID: "student-42", TelegramID: 42, DisplayName: "Айдана", LastSeenAt: time.Now()},
+		},
+	}}
+	rankQuery := query.NewGetStudentRankHandler(repo, unrankedLeaderboard{}, nil, nil)
+	dailyProgress := query.NewGetDailyProgressHandler(repo, emptyProgress{}, nil)
+
+	router := NewRouter(RouterConfig{})
+	router.RegisterCommand("me", handler.NewMeHandler(
+		rankQuery, dailyProgress, nil, repo,
+		presenter.NewKeyboardBuilder(), presenter.NewStudentCardPresenter(),
+	))
+
+	auth := middleware.NewAuthMiddleware(repo, middleware.DefaultAuthConfig())
+	client, sent := newFakeTelegram(t)
+	bot := &Bot{
+		client:             client,
+		router:             router,
+		logger:             router.logger,
+		authMiddleware:     auth,
+		students:           handler.NewStudentLoader(auth).WithRankQuery(rankQuery),
+		rateLimiter:        middleware.NewRateLimiter(middleware.DefaultRateLimitConfig()),
+		recoveryMiddleware: middleware.NewRecoveryMiddleware(middleware.DefaultRecoveryConfig()),
+		usageRecorder:      middleware.NewUsageRecorder(nil, middleware.DefaultUsageRecorderConfig()),
+		stats:              &BotStats{CommandsCount: make(map[string]int64)},
+	}
+
+	require.NoError(t, bot.handleCommand(context.Background(), 42, 42, 1, "me", "", nil))
+
+	// Auth, the card, its rank and daily progress share one lookup
+	assert.Equal(t, int32(1), repo.lookups.Load())
+	require.Len(t, sent.messages, 1)
+	assert.Contains(t, sent.messages[0]["text"], "Айдана")
+}
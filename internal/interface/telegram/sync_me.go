@@ -0,0 +1,86 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// SYNC ME
+// "/sync_me" pulls the student's data from Alem right away and replies with
+// what changed: "+120 XP, место 84 → 81". Once per 3 minutes per student.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// SyncMeHandler handles /sync_me.
+type SyncMeHandler struct {
+	syncMeCmd   *command.SyncMeHandler
+	studentRepo student.Repository
+	logger      *slog.Logger
+}
+
+// NewSyncMeHandler creates a new SyncMeHandler.
+func NewSyncMeHandler(syncMeCmd *command.SyncMeHandler, studentRepo student.Repository, logger *slog.Logger) *SyncMeHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SyncMeHandler{
+		syncMeCmd:   syncMeCmd,
+		studentRepo: studentRepo,
+		logger:      logger,
+	}
+}
+
+// Handle syncs the student and reports the change.
+func (h *SyncMeHandler) Handle(ctx context.Context, cmdCtx CommandContext) error {
+	stud, err := h.studentRepo.GetByTelegramID(ctx, student.TelegramID(cmdCtx.TelegramID))
+	if err != nil {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, "❌ Ты не зарегистрирован. Используй /start")
+		return err
+	}
+
+	progress, err := cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, "🔄 Синхронизирую с Alem…")
+	if err != nil {
+		return err
+	}
+
+	result, err := h.syncMeCmd.Handle(ctx, command.SyncMeCommand{StudentID: stud.ID})
+	text := syncMeText(result, err)
+	if err != nil && !errors.Is(err, command.ErrSyncMeTooSoon) && !errors.Is(err, command.ErrSyncMeBusy) {
+		h.logger.Error("sync_me failed", "student_id", stud.ID, "error", err)
+	}
+
+	_, err = cmdCtx.Client.EditMessageText(ctx, cmdCtx.ChatID, progress.MessageID, text, "HTML", nil)
+	return err
+}
+
+// syncMeText formats the reply to /sync_me.
+func syncMeText(result *command.SyncMeResult, err error) string {
+	switch {
+	case errors.Is(err, command.ErrSyncMeTooSoon):
+		return fmt.Sprintf("⏳ Синхронизироваться можно раз в %d минуты. Попробуй чуть позже.",
+			int(student.OnDemandSyncCooldown.Minutes()))
+	case errors.Is(err, command.ErrSyncMeBusy):
+		return "⏳ Твои данные сейчас обновляет общая синхронизация. Загляни в /me через минуту."
+	case err != nil:
+		return "❌ Не получилось синхронизироваться с Alem. Попробуй позже."
+	}
+
+	var changes []string
+	if result.XPDelta != 0 {
+		changes = append(changes, fmt.Sprintf("%+d XP", result.XPDelta))
+	}
+	if result.RankChanged() {
+		changes = append(changes, fmt.Sprintf("место %d → %d", result.OldRank, result.NewRank))
+	}
+	if len(changes) == 0 {
+		return fmt.Sprintf("✅ Данные актуальны: %d XP, ничего нового.", result.NewXP)
+	}
+	return "✅ Синхронизировано: " + strings.Join(changes, ", ")
+}
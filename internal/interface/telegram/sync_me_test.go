@@ -0,0 +1,23 @@
+package telegram
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+)
+
+func TestSyncMeText(t *testing.T) {
+	assert.Equal(t, "✅ Синхронизировано: +120 XP, место 84 → 81",
+		syncMeText(&command.SyncMeResult{OldXP: 1000, NewXP: 1120, XPDelta: 120, OldRank: 84, NewRank: 81}, nil))
+	assert.Equal(t, "✅ Данные актуальны: 1000 XP, ничего нового.",
+		syncMeText(&command.SyncMeResult{OldXP: 1000, NewXP: 1000, OldRank: 84, NewRank: 84}, nil))
+	assert.Equal(t, "✅ Синхронизировано: +50 XP",
+		syncMeText(&command.SyncMeResult{XPDelta: 50, NewRank: 12}, nil))
+
+	assert.Contains(t, syncMeText(nil, command.ErrSyncMeTooSoon), "раз в 3 минуты")
+	assert.Contains(t, syncMeText(nil, command.ErrSyncMeBusy), "общая синхронизация")
+	assert.Contains(t, syncMeText(nil, errors.New("alem down")), "Не получилось")
+}
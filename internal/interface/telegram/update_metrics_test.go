@@ -0,0 +1,34 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
+)
+
+func TestBot_UpdateCommandKeepsLabelsBounded(t *testing.T) {
+	router := NewRouter(RouterConfig{})
+	router.RegisterCommand("top", CommandHandlerFunc(func(context.Context, CommandContext) error { return nil }))
+	router.RegisterCallbackPrefix("top:", nil)
+	bot := &Bot{router: router}
+
+	command := func(text string) *telegram.Update {
+		return &telegram.Update{Message: &telegram.Message{
+			Text:     text,
+			Entities: []telegram.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(text)}},
+		}}
+	}
+	callback := func(data string) *telegram.Update {
+		return &telegram.Update{CallbackQuery: &telegram.CallbackQuery{Data: data}}
+	}
+
+	assert.Equal(t, "top", bot.updateCommand(command("/top")))
+	assert.Equal(t, "top", bot.updateCommand(command("/top@alem_hub_bot")))
+	assert.Equal(t, "unknown", bot.updateCommand(command("/a1b2c3")))
+	assert.Equal(t, "message", bot.updateCommand(&telegram.Update{Message: &telegram.Message{Text: "привет"}}))
+	assert.Equal(t, "cb:top", bot.updateCommand(callback("top:2:week")))
+	assert.Equal(t, "cb:unknown", bot.updateCommand(callback("stale:1")))
+}
@@ -0,0 +1,76 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/query"
+	"github.com/alem-hub/alem-community-hub/internal/domain/analytics"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// COMMAND USAGE
+// Admin-only "/usage": top commands over the last 7 flushed days.
+// Counters reach PostgreSQL once a day, so today is never included.
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// usageWindowDays is how many complete days /usage covers.
+	usageWindowDays = 7
+
+	// usageTopN is how many commands /usage lists.
+	usageTopN = 10
+)
+
+// CommandUsageHandler handles the admin-only /usage command.
+type CommandUsageHandler struct {
+	usageQuery *query.GetCommandUsageHandler
+}
+
+// NewCommandUsageHandler creates a new CommandUsageHandler.
+// Register it behind Router.AdminOnly.
+func NewCommandUsageHandler(usageQuery *query.GetCommandUsageHandler) *CommandUsageHandler {
+	return &CommandUsageHandler{usageQuery: usageQuery}
+}
+
+// Handle processes "/usage".
+func (h *CommandUsageHandler) Handle(ctx context.Context, cmdCtx CommandContext) error {
+	to := time.Now().UTC().AddDate(0, 0, -1)
+	result, err := h.usageQuery.Handle(ctx, query.GetCommandUsageQuery{
+		From:  to.AddDate(0, 0, -(usageWindowDays - 1)),
+		To:    to,
+		Limit: usageTopN,
+	})
+	if err != nil {
+		_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, "❌ Не удалось получить статистику: "+html.EscapeString(err.Error()))
+		return err
+	}
+
+	_, err = cmdCtx.Client.SendHTML(ctx, cmdCtx.ChatID, formatCommandUsage(result))
+	return err
+}
+
+// formatCommandUsage renders the top commands.
+func formatCommandUsage(result *query.GetCommandUsageResult) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("📊 <b>Команды за %d дней</b>\n<i>%s — %s</i>\n\n", usageWindowDays, result.From, result.To))
+
+	if len(result.Commands) == 0 {
+		sb.WriteString("Пока нет данных.")
+		return sb.String()
+	}
+
+	for i, c := range result.Commands {
+		name := "/" + c.Command
+		if strings.HasPrefix(c.Command, analytics.CallbackPrefix) {
+			name = "🔘 " + strings.TrimPrefix(c.Command, analytics.CallbackPrefix)
+		}
+		sb.WriteString(fmt.Sprintf("%d. <code>%s</code> — %d\n", i+1, html.EscapeString(name), c.Total))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,171 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/alem-hub/alem-community-hub/internal/application/command"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+	"github.com/alem-hub/alem-community-hub/internal/infrastructure/external/telegram"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// XP FLAG REVIEW
+// Curator buttons under the "suspicious XP" alert posted by the sync job:
+// "xpflag:approve:<id>" clears the flag, "xpflag:dismiss:<id>" asks for
+// confirmation and "xpflag:confirm:<id>" records the XP adjustment.
+// "xpflag:back:<id>" returns to the first two buttons.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// xpFlagCallbackPrefix is the callback prefix of the review buttons.
+const xpFlagCallbackPrefix = "xpflag:"
+
+// XPFlagReviewHandler handles curator decisions on XP flags.
+type XPFlagReviewHandler struct {
+	reviewCmd *command.ReviewXPFlagHandler
+	admins    map[int64]bool
+	logger    *slog.Logger
+}
+
+// NewXPFlagReviewHandler creates a new XPFlagReviewHandler.
+// Only Telegram users listed in adminIDs may decide.
+func NewXPFlagReviewHandler(reviewCmd *command.ReviewXPFlagHandler, adminIDs []int64, logger *slog.Logger) *XPFlagReviewHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	admins := make(map[int64]bool, len(adminIDs))
+	for _, id := range adminIDs {
+		admins[id] = true
+	}
+
+	return &XPFlagReviewHandler{
+		reviewCmd: reviewCmd,
+		admins:    admins,
+		logger:    logger,
+	}
+}
+
+// Handle processes "xpflag:<action>:<id>".
+func (h *XPFlagReviewHandler) Handle(ctx context.Context, cbCtx CallbackContext) error {
+	action, flagID, ok := parseXPFlagCallback(cbCtx.Data)
+	if !ok {
+		return nil
+	}
+
+	// The alert is posted to a group chat: other members may press too
+	if !h.admins[cbCtx.TelegramID] {
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "⛔ Решение принимают кураторы.", true)
+	}
+
+	cmd := command.ReviewXPFlagCommand{FlagID: flagID, CuratorID: cbCtx.TelegramID}
+	switch action {
+	case "approve":
+		cmd.Decision = command.XPFlagApprove
+	case "dismiss":
+		cmd.Decision = command.XPFlagDismiss
+	case "confirm":
+		cmd.Decision = command.XPFlagDismiss
+		cmd.Confirmed = true
+	case "back":
+		_, err := cbCtx.Client.EditMessageKeyboard(ctx, cbCtx.ChatID, int64(cbCtx.MessageID), xpFlagDecisionKeyboard(flagID))
+		return err
+	default:
+		return nil
+	}
+
+	result, err := h.reviewCmd.Handle(ctx, cmd)
+	switch {
+	case errors.Is(err, student.ErrXPFlagResolved):
+		_ = cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "Решение уже принято.", false)
+		return h.edit(ctx, cbCtx, formatXPFlagReview(result), nil)
+	case errors.Is(err, student.ErrXPFlagNotFound):
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "Флаг не найден.", true)
+	case err != nil:
+		h.logger.Warn("xp flag review failed", "flag_id", flagID, "curator_id", student.TelegramID(cbCtx.TelegramID), "error", err)
+		return cbCtx.Client.AnswerCallbackQuery(ctx, cbCtx.QueryID, "❌ Не удалось сохранить решение.", true)
+	}
+
+	if result.NeedsConfirmation {
+		return h.edit(ctx, cbCtx, formatXPFlagReview(result), xpFlagConfirmKeyboard(flagID, result.Flag.Excess))
+	}
+
+	h.logger.Info("xp flag reviewed",
+		"flag_id", flagID,
+		"student_id", result.Flag.StudentID,
+		"status", result.Flag.Status,
+		"curator_id", student.TelegramID(cbCtx.TelegramID),
+	)
+	return h.edit(ctx, cbCtx, formatXPFlagReview(result), nil)
+}
+
+// edit replaces the alert text and buttons (nil removes the buttons).
+func (h *XPFlagReviewHandler) edit(ctx context.Context, cbCtx CallbackContext, text string, keyboard *telegram.InlineKeyboardMarkup) error {
+	if keyboard == nil {
+		keyboard = &telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{}}
+	}
+	_, err := cbCtx.Client.EditMessageText(ctx, cbCtx.ChatID, int64(cbCtx.MessageID), text, "HTML", keyboard)
+	return err
+}
+
+// parseXPFlagCallback parses "xpflag:<action>:<id>".
+func parseXPFlagCallback(data string) (string, int64, bool) {
+	parts := strings.Split(strings.TrimPrefix(data, xpFlagCallbackPrefix), ":")
+	if len(parts) != 2 {
+		return "", 0, false
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || id <= 0 {
+		return "", 0, false
+	}
+	return parts[0], id, true
+}
+
+// xpFlagDecisionKeyboard returns the approve/dismiss buttons.
+func xpFlagDecisionKeyboard(flagID int64) *telegram.InlineKeyboardMarkup {
+	return &telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{{
+		{Text: "✅ Честно", CallbackData: fmt.Sprintf("%sapprove:%d", xpFlagCallbackPrefix, flagID)},
+		{Text: "🚫 Накрутка", CallbackData: fmt.Sprintf("%sdismiss:%d", xpFlagCallbackPrefix, flagID)},
+	}}}
+}
+
+// xpFlagConfirmKeyboard asks to confirm the XP adjustment.
+func xpFlagConfirmKeyboard(flagID int64, excess student.XP) *telegram.InlineKeyboardMarkup {
+	return &telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{{
+		{Text: fmt.Sprintf("⚠️ Списать %d XP", int(excess)), CallbackData: fmt.Sprintf("%sconfirm:%d", xpFlagCallbackPrefix, flagID)},
+		{Text: "↩️ Назад", CallbackData: fmt.Sprintf("%sback:%d", xpFlagCallbackPrefix, flagID)},
+	}}}
+}
+
+// formatXPFlagReview renders the alert with the current state of the flag.
+func formatXPFlagReview(result *command.ReviewXPFlagResult) string {
+	flag := result.Flag
+	name := flag.StudentID
+	if result.Student != nil {
+		name = result.Student.DisplayName
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🚩 <b>Подозрительный прирост XP</b>\n\n")
+	sb.WriteString(fmt.Sprintf("Студент: <b>%s</b>\n", html.EscapeString(name)))
+	sb.WriteString(fmt.Sprintf("День: %s\n", flag.Date.Format("02.01.2006")))
+	sb.WriteString(fmt.Sprintf("XP за день: <b>%d</b> (z = %.1f)\n\n", int(flag.Amount), flag.ZScore))
+
+	switch {
+	case result.NeedsConfirmation:
+		sb.WriteString(fmt.Sprintf("Признать накруткой и списать <b>%d XP</b> сверх обычного дня?", int(flag.Excess)))
+	case flag.Status == student.XPFlagApproved:
+		sb.WriteString(fmt.Sprintf("✅ Прирост подтверждён куратором <code>%d</code>.", flag.ResolvedBy))
+	case flag.Status == student.XPFlagDismissed:
+		sb.WriteString(fmt.Sprintf("🚫 Накрутка: списано %d XP, решение куратора <code>%d</code>.", int(flag.Excess), flag.ResolvedBy))
+	default:
+		sb.WriteString("XP не снят, в /top студент отмечен ⏳ до решения.")
+	}
+
+	return sb.String()
+}
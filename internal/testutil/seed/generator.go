@@ -0,0 +1,418 @@
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// GENERATOR
+// Pure, deterministic data generation. Every student gets its own random stream
+// derived from (seed, index), so the output doesn't depend on chunk size or on
+// the order in which students are generated.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// idNamespace is the UUID namespace used for all seeded identifiers.
+var idNamespace = uuid.MustParse("6f1b7a52-3c1e-4d0a-9a53-5e8d1c2b7f40")
+
+// Random streams, one per kind of data generated for a student.
+const (
+	streamStudent uint64 = iota
+	streamSocial
+)
+
+var firstNames = []string{
+	"Алихан", "Айгерим", "Данияр", "Жанель", "Ерлан", "Камила", "Нурсултан", "Асель",
+	"Тимур", "Дана", "Арман", "Мадина", "Санжар", "Аружан", "Бекзат", "Томирис",
+}
+
+var initials = []rune("АБВГДЕЖЗИКЛМНОПРСТУХШЭЮЯ")
+
+var connectionTypes = []social.ConnectionType{
+	social.ConnectionTypeStudyBuddy, social.ConnectionTypeStudyBuddy,
+	social.ConnectionTypeStudyBuddy, social.ConnectionTypeStudyBuddy,
+	social.ConnectionTypeStudyBuddy, social.ConnectionTypeHelper,
+	social.ConnectionTypeHelper, social.ConnectionTypeHelper,
+	social.ConnectionTypeMentor, social.ConnectionTypeCoworker,
+}
+
+var endorsementTypes = []social.EndorsementType{
+	social.EndorsementTypeClear, social.EndorsementTypePatient, social.EndorsementTypeDeep,
+	social.EndorsementTypeFast, social.EndorsementTypeFriendly, social.EndorsementTypeInspiring,
+}
+
+// StudentData is everything generated for a single student.
+type StudentData struct {
+	Student *student.Student
+	History []student.StudentXPChange
+	Grinds  []*student.DailyGrind
+	Streak  *student.Streak
+}
+
+// SocialData is the outgoing part of a student's social graph.
+type SocialData struct {
+	Connections  []*social.Connection
+	Endorsements []*social.Endorsement
+}
+
+// Generator produces synthetic students deterministically from a Config.
+type Generator struct {
+	cfg Config
+
+	// windowStart is midnight of the first day of history.
+	windowStart time.Time
+}
+
+// NewGenerator creates a generator. cfg is expected to be validated.
+func NewGenerator(cfg Config) *Generator {
+	now := cfg.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	cfg.Now = today
+
+	return &Generator{
+		cfg:         cfg,
+		windowStart: today.AddDate(0, 0, -(cfg.Days - 1)),
+	}
+}
+
+// StudentID returns the ID of the i-th seeded student.
+func (g *Generator) StudentID(i int) string {
+	return g.id("student", i, 0)
+}
+
+// Cohort returns the cohort of the i-th seeded student.
+func (g *Generator) Cohort(i int) student.Cohort {
+	// The cohort must be the first draw of the student stream: the social
+	// generator relies on being able to look it up cheaply.
+	r := g.rng(i, streamStudent)
+	return cohortName(r.IntN(g.cfg.Cohorts))
+}
+
+// Student generates the i-th student together with its progress history.
+func (g *Generator) Student(i int) *StudentData {
+	r := g.rng(i, streamStudent)
+	cohort := cohortName(r.IntN(g.cfg.Cohorts))
+
+	finalXP := paretoXP(r)
+	startXP := student.XP(float64(finalXP) * (0.3 + 0.5*r.Float64()))
+
+	// Activity level, skewed so that most students are moderately active and
+	// a few grind every day. Stickiness produces realistic streaks.
+	activeProb := 0.1 + 0.75*math.Pow(r.Float64(), 0.7)
+	active := make([]bool, g.cfg.Days)
+	for d := range active {
+		p := activeProb
+		if d > 0 && active[d-1] {
+			p = math.Min(p+0.2, 0.97)
+		}
+		active[d] = r.Float64() < p
+	}
+
+	id := g.StudentID(i)
+	gains := distribute(r, int(finalXP-startXP), active)
+
+	data := &StudentData{}
+	xp := startXP
+	streakDay := 0
+	var lastActivity time.Time
+
+	for d, gain := range gains {
+		if !active[d] {
+			streakDay = 0
+			continue
+		}
+		streakDay++
+
+		day := g.windowStart.AddDate(0, 0, d)
+
+		// A day without XP is still a session, just without completed tasks.
+		tasks := min(1+r.IntN(4), gain)
+		stamps := make([]time.Duration, max(tasks, 1))
+		for t := range stamps {
+			stamps[t] = 9*time.Hour + time.Duration(r.IntN(13*60))*time.Minute
+		}
+		sort.Slice(stamps, func(a, b int) bool { return stamps[a] < stamps[b] })
+
+		grind := &student.DailyGrind{
+			StudentID:           id,
+			Date:                day,
+			XPStart:             xp,
+			TasksCompleted:      tasks,
+			SessionsCount:       1 + r.IntN(3),
+			TotalSessionMinutes: 30 + r.IntN(270),
+			FirstActivityAt:     day.Add(stamps[0]),
+			LastActivityAt:      day.Add(stamps[len(stamps)-1]),
+			StreakDay:           streakDay,
+		}
+
+		remaining := gain
+		for t := 0; t < tasks; t++ {
+			// Every task is worth at least 1 XP; the last one takes the rest.
+			left := tasks - t - 1
+			delta := remaining
+			if left > 0 {
+				even := remaining / (left + 1)
+				delta = min(max(1, even/2+r.IntN(even+1)), remaining-left)
+			}
+			remaining -= delta
+
+			data.History = append(data.History, student.StudentXPChange{
+				StudentID: id,
+				Entry: student.XPHistoryEntry{
+					Timestamp: day.Add(stamps[t]),
+					OldXP:     xp,
+					NewXP:     xp + student.XP(delta),
+					Delta:     student.XP(delta),
+					Reason:    "task_completed",
+					TaskID:    fmt.Sprintf("task-%03d", r.IntN(200)),
+				},
+			})
+			xp += student.XP(delta)
+		}
+
+		grind.XPCurrent = xp
+		grind.XPGained = xp - grind.XPStart
+		data.Grinds = append(data.Grinds, grind)
+		lastActivity = grind.LastActivityAt
+	}
+
+	data.Streak = g.streak(id, active)
+
+	joinedAt := g.windowStart.AddDate(0, 0, -r.IntN(180)).Add(time.Duration(r.IntN(24*60)) * time.Minute)
+	lastSeen := lastActivity
+	if lastSeen.IsZero() {
+		lastSeen = joinedAt
+	}
+
+	status := student.StatusActive
+	if g.cfg.Now.Sub(lastSeen) > 14*24*time.Hour {
+		status = student.StatusInactive
+	}
+
+	prefs := student.DefaultNotificationPreferences()
+	prefs.DailyDigest = r.Float64() < 0.8
+	prefs.RankChanges = r.Float64() < 0.9
+
+	data.Student = &student.Student{
+		ID:           id,
+		TelegramID:   student.TelegramID(g.cfg.TelegramIDBase + int64(i)),
+		Email:        fmt.Sprintf("seed%07d@seed.alem.local", i),
+		PasswordHash: "seed",
+		DisplayName:  fmt.Sprintf("%s %c.", firstNames[r.IntN(len(firstNames))], initials[r.IntN(len(initials))]),
+		CurrentXP:    xp,
+		Cohort:       cohort,
+		Status:       status,
+		OnlineState:  student.OnlineStateOffline,
+		LastSeenAt:   lastSeen,
+		LastSyncedAt: g.cfg.Now,
+		JoinedAt:     joinedAt,
+		Preferences:  prefs,
+		CreatedAt:    joinedAt,
+		UpdatedAt:    g.cfg.Now,
+	}
+
+	return data
+}
+
+// Social generates the outgoing connections and endorsements of the i-th student.
+func (g *Generator) Social(i int) *SocialData {
+	data := &SocialData{}
+	if g.cfg.Students < 2 {
+		return data
+	}
+
+	r := g.rng(i, streamSocial)
+	from := social.StudentID(g.StudentID(i))
+	cohort := g.Cohort(i)
+
+	seen := map[int]bool{i: true}
+	for k, n := 0, poisson(r, g.cfg.ConnectionsPerStudent); k < n; k++ {
+		j := g.pickPeer(r, cohort, seen)
+		if j < 0 {
+			break
+		}
+		seen[j] = true
+
+		createdAt := g.randomTime(r)
+		acceptedAt := createdAt.Add(time.Duration(r.IntN(48*60)) * time.Minute)
+		data.Connections = append(data.Connections, &social.Connection{
+			ID:          g.id("connection", i, k),
+			InitiatorID: from,
+			ReceiverID:  social.StudentID(g.StudentID(j)),
+			Type:        connectionTypes[r.IntN(len(connectionTypes))],
+			Status:      social.ConnectionStatusActive,
+			CreatedAt:   createdAt,
+			UpdatedAt:   acceptedAt,
+			AcceptedAt:  &acceptedAt,
+		})
+	}
+
+	for k, n := 0, poisson(r, g.cfg.EndorsementsPerStudent); k < n; k++ {
+		j := r.IntN(g.cfg.Students - 1)
+		if j >= i {
+			j++
+		}
+
+		rating := social.Rating(5)
+		switch p := r.Float64(); {
+		case p < 0.1:
+			rating = 3
+		case p < 0.4:
+			rating = 4
+		}
+
+		data.Endorsements = append(data.Endorsements, &social.Endorsement{
+			ID:         g.id("endorsement", i, k),
+			GiverID:    from,
+			ReceiverID: social.StudentID(g.StudentID(j)),
+			TaskID:     social.TaskID(fmt.Sprintf("task-%03d", r.IntN(200))),
+			Type:       endorsementTypes[r.IntN(len(endorsementTypes))],
+			Rating:     rating,
+			IsPublic:   true,
+			CreatedAt:  g.randomTime(r),
+		})
+	}
+
+	return data
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// rng returns the random stream of the i-th student.
+func (g *Generator) rng(i int, stream uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(uint64(g.cfg.Seed), uint64(i)<<8|stream))
+}
+
+// id derives a stable UUID for a seeded entity.
+func (g *Generator) id(kind string, i, k int) string {
+	return uuid.NewSHA1(idNamespace, []byte(fmt.Sprintf("%s:%d:%d:%d", kind, g.cfg.Seed, i, k))).String()
+}
+
+// pickPeer picks a connection target, preferring the student's own cohort.
+// Returns -1 when no unused peer could be found.
+func (g *Generator) pickPeer(r *rand.Rand, cohort student.Cohort, seen map[int]bool) int {
+	sameCohort := r.Float64() < 0.7
+	for attempt := 0; attempt < 8; attempt++ {
+		j := r.IntN(g.cfg.Students)
+		if seen[j] {
+			continue
+		}
+		if sameCohort && attempt < 5 && g.Cohort(j) != cohort {
+			continue
+		}
+		return j
+	}
+	return -1
+}
+
+// randomTime returns a random moment within the history window.
+func (g *Generator) randomTime(r *rand.Rand) time.Time {
+	return g.windowStart.Add(time.Duration(r.Int64N(int64(g.cfg.Days) * int64(24*time.Hour))))
+}
+
+// streak derives the streak row from the activity calendar.
+func (g *Generator) streak(studentID string, active []bool) *student.Streak {
+	s := student.NewStreak(studentID)
+
+	run := 0
+	for d, ok := range active {
+		if !ok {
+			run = 0
+			continue
+		}
+		run++
+		s.BestStreak = max(s.BestStreak, run)
+		s.LastActiveDate = g.windowStart.AddDate(0, 0, d)
+	}
+
+	// A streak is still current if the student was active today or yesterday.
+	end := len(active) - 1
+	if end >= 0 && !active[end] {
+		end--
+	}
+	for d := end; d >= 0 && active[d]; d-- {
+		s.CurrentStreak++
+	}
+	if s.CurrentStreak > 0 {
+		s.StreakStartDate = g.windowStart.AddDate(0, 0, end-s.CurrentStreak+1)
+	}
+
+	return s
+}
+
+// cohortName returns the name of the c-th seeded cohort.
+func cohortName(c int) student.Cohort {
+	return student.Cohort(fmt.Sprintf("seed-cohort-%02d", c+1))
+}
+
+// paretoXP draws a final XP value from a power-law distribution:
+// most students have a few thousand XP and a long tail has a lot more.
+func paretoXP(r *rand.Rand) student.XP {
+	const (
+		minXP = 500
+		maxXP = 250_000
+		alpha = 1.2
+	)
+	xp := minXP / math.Pow(1-r.Float64(), 1/alpha)
+	return student.XP(math.Min(xp, maxXP))
+}
+
+// distribute splits total XP across active days with exponential weights.
+func distribute(r *rand.Rand, total int, active []bool) []int {
+	gains := make([]int, len(active))
+	weights := make([]float64, len(active))
+
+	var sum float64
+	lastActive := -1
+	for d, ok := range active {
+		if ok {
+			weights[d] = r.ExpFloat64()
+			sum += weights[d]
+			lastActive = d
+		}
+	}
+	if lastActive < 0 || total <= 0 {
+		return gains
+	}
+
+	assigned := 0
+	for d := range active {
+		if weights[d] > 0 {
+			gains[d] = int(float64(total) * weights[d] / sum)
+			assigned += gains[d]
+		}
+	}
+	gains[lastActive] += total - assigned
+
+	return gains
+}
+
+// poisson draws a Poisson-distributed count with mean lambda (Knuth).
+func poisson(r *rand.Rand, lambda float64) int {
+	if lambda <= 0 {
+		return 0
+	}
+	limit := math.Exp(-lambda)
+	k, p := 0, 1.0
+	for {
+		p *= r.Float64()
+		if p <= limit {
+			return k
+		}
+		k++
+	}
+}
@@ -0,0 +1,289 @@
+// Package seed generates realistic synthetic data for load tests and
+// integration tests: students spread over cohorts with a power-law XP
+// distribution, their XP history, daily grinds and streaks, and a social
+// graph of connections and endorsements.
+//
+// Generation is deterministic for a given Config.Seed, so benchmarks run
+// against the same data every time, and writes are chunked to keep memory
+// bounded regardless of the number of students.
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CONFIGURATION
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Config configures the generated data set.
+type Config struct {
+	// Seed makes the data set reproducible.
+	Seed int64
+
+	// Students is the number of students to generate.
+	Students int
+
+	// Cohorts is the number of cohorts students are spread across.
+	Cohorts int
+
+	// Days is the length of the generated history, ending today.
+	Days int
+
+	// ConnectionsPerStudent is the average number of outgoing connections.
+	ConnectionsPerStudent float64
+
+	// EndorsementsPerStudent is the average number of endorsements given.
+	EndorsementsPerStudent float64
+
+	// ChunkSize is the number of students generated and written at once.
+	ChunkSize int
+
+	// TelegramIDBase is added to the student index to build Telegram IDs.
+	// Keep it far away from real IDs.
+	TelegramIDBase int64
+
+	// Now anchors the history window. Defaults to the current day (UTC).
+	Now time.Time
+}
+
+// DefaultConfig returns a config for a medium-sized data set.
+func DefaultConfig() Config {
+	return Config{
+		Seed:                   1,
+		Students:               1000,
+		Cohorts:                4,
+		Days:                   90,
+		ConnectionsPerStudent:  3,
+		EndorsementsPerStudent: 1.5,
+		ChunkSize:              500,
+		TelegramIDBase:         9_000_000_000,
+	}
+}
+
+// Validate checks the configuration.
+func (c Config) Validate() error {
+	switch {
+	case c.Students <= 0:
+		return errors.New("students must be positive")
+	case c.Cohorts <= 0:
+		return errors.New("cohorts must be positive")
+	case c.Days <= 0 || c.Days > 366:
+		return errors.New("days must be between 1 and 366")
+	case c.ConnectionsPerStudent < 0 || c.EndorsementsPerStudent < 0:
+		return errors.New("densities must be non-negative")
+	case c.ChunkSize <= 0:
+		return errors.New("chunk size must be positive")
+	case c.TelegramIDBase <= 0:
+		return errors.New("telegram id base must be positive")
+	}
+	return nil
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// STORES
+// The seeder only needs the bulk write methods, so tests can pass small fakes
+// while the seed tool passes the postgres repositories.
+// ══════════════════════════════════════════════════════════════════════════════
+
+// StudentWriter bulk-inserts students.
+type StudentWriter interface {
+	CreateBatch(ctx context.Context, students []*student.Student) error
+}
+
+// ProgressWriter bulk-inserts progress history.
+type ProgressWriter interface {
+	SaveXPChangesBatch(ctx context.Context, changes []student.StudentXPChange) error
+	SaveDailyGrindsBatch(ctx context.Context, grinds []*student.DailyGrind) error
+	SaveStreaksBatch(ctx context.Context, streaks []*student.Streak) error
+}
+
+// ConnectionWriter bulk-inserts connections.
+type ConnectionWriter interface {
+	CreateBatch(ctx context.Context, conns []*social.Connection) error
+}
+
+// EndorsementWriter bulk-inserts endorsements.
+type EndorsementWriter interface {
+	CreateBatch(ctx context.Context, endorsements []*social.Endorsement) error
+}
+
+// Stores groups the writers the seeder loads data into.
+type Stores struct {
+	Students     StudentWriter
+	Progress     ProgressWriter
+	Connections  ConnectionWriter
+	Endorsements EndorsementWriter
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RESULT
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Result summarizes a seeding run.
+type Result struct {
+	Students     int
+	XPHistory    int
+	DailyGrinds  int
+	Streaks      int
+	Connections  int
+	Endorsements int
+	Elapsed      time.Duration
+}
+
+// Rows returns the total number of rows written.
+func (r Result) Rows() int {
+	return r.Students + r.XPHistory + r.DailyGrinds + r.Streaks + r.Connections + r.Endorsements
+}
+
+// String renders a human-readable summary.
+func (r Result) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-14s %10d\n", "students", r.Students)
+	fmt.Fprintf(&sb, "%-14s %10d\n", "xp_history", r.XPHistory)
+	fmt.Fprintf(&sb, "%-14s %10d\n", "daily_grinds", r.DailyGrinds)
+	fmt.Fprintf(&sb, "%-14s %10d\n", "streaks", r.Streaks)
+	fmt.Fprintf(&sb, "%-14s %10d\n", "connections", r.Connections)
+	fmt.Fprintf(&sb, "%-14s %10d\n", "endorsements", r.Endorsements)
+	fmt.Fprintf(&sb, "%-14s %10d\n", "total rows", r.Rows())
+	fmt.Fprintf(&sb, "%-14s %10s", "elapsed", r.Elapsed.Round(time.Millisecond))
+	return sb.String()
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// SEEDER
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Seeder generates data and writes it chunk by chunk.
+type Seeder struct {
+	cfg    Config
+	gen    *Generator
+	stores Stores
+
+	// OnChunk, if set, is called after every written chunk.
+	OnChunk func(phase string, done, total int)
+}
+
+// New creates a Seeder.
+func New(cfg Config, stores Stores) (*Seeder, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid seed config: %w", err)
+	}
+	if stores.Students == nil || stores.Progress == nil {
+		return nil, errors.New("student and progress stores are required")
+	}
+
+	return &Seeder{
+		cfg:    cfg,
+		gen:    NewGenerator(cfg),
+		stores: stores,
+	}, nil
+}
+
+// Generator returns the underlying generator, e.g. to look up seeded IDs.
+func (s *Seeder) Generator() *Generator {
+	return s.gen
+}
+
+// Run generates and writes the whole data set. Students and their progress
+// are written first so that the social graph can reference any student.
+func (s *Seeder) Run(ctx context.Context) (*Result, error) {
+	start := time.Now()
+	result := &Result{}
+
+	for lo := 0; lo < s.cfg.Students; lo += s.cfg.ChunkSize {
+		hi := min(lo+s.cfg.ChunkSize, s.cfg.Students)
+		if err := s.writeStudents(ctx, lo, hi, result); err != nil {
+			return nil, err
+		}
+		s.report("students", hi)
+	}
+
+	if s.stores.Connections != nil || s.stores.Endorsements != nil {
+		for lo := 0; lo < s.cfg.Students; lo += s.cfg.ChunkSize {
+			hi := min(lo+s.cfg.ChunkSize, s.cfg.Students)
+			if err := s.writeSocial(ctx, lo, hi, result); err != nil {
+				return nil, err
+			}
+			s.report("social", hi)
+		}
+	}
+
+	result.Elapsed = time.Since(start)
+	return result, nil
+}
+
+// writeStudents writes students [lo, hi) with their progress.
+func (s *Seeder) writeStudents(ctx context.Context, lo, hi int, result *Result) error {
+	students := make([]*student.Student, 0, hi-lo)
+	streaks := make([]*student.Streak, 0, hi-lo)
+	var history []student.StudentXPChange
+	var grinds []*student.DailyGrind
+
+	for i := lo; i < hi; i++ {
+		data := s.gen.Student(i)
+		students = append(students, data.Student)
+		history = append(history, data.History...)
+		grinds = append(grinds, data.Grinds...)
+		streaks = append(streaks, data.Streak)
+	}
+
+	if err := s.stores.Students.CreateBatch(ctx, students); err != nil {
+		return fmt.Errorf("write students %d-%d: %w", lo, hi, err)
+	}
+	if err := s.stores.Progress.SaveXPChangesBatch(ctx, history); err != nil {
+		return fmt.Errorf("write xp history %d-%d: %w", lo, hi, err)
+	}
+	if err := s.stores.Progress.SaveDailyGrindsBatch(ctx, grinds); err != nil {
+		return fmt.Errorf("write daily grinds %d-%d: %w", lo, hi, err)
+	}
+	if err := s.stores.Progress.SaveStreaksBatch(ctx, streaks); err != nil {
+		return fmt.Errorf("write streaks %d-%d: %w", lo, hi, err)
+	}
+
+	result.Students += len(students)
+	result.XPHistory += len(history)
+	result.DailyGrinds += len(grinds)
+	result.Streaks += len(streaks)
+	return nil
+}
+
+// writeSocial writes the outgoing social graph of students [lo, hi).
+func (s *Seeder) writeSocial(ctx context.Context, lo, hi int, result *Result) error {
+	var conns []*social.Connection
+	var endorsements []*social.Endorsement
+
+	for i := lo; i < hi; i++ {
+		data := s.gen.Social(i)
+		conns = append(conns, data.Connections...)
+		endorsements = append(endorsements, data.Endorsements...)
+	}
+
+	if s.stores.Connections != nil {
+		if err := s.stores.Connections.CreateBatch(ctx, conns); err != nil {
+			return fmt.Errorf("write connections %d-%d: %w", lo, hi, err)
+		}
+		result.Connections += len(conns)
+	}
+	if s.stores.Endorsements != nil {
+		if err := s.stores.Endorsements.CreateBatch(ctx, endorsements); err != nil {
+			return fmt.Errorf("write endorsements %d-%d: %w", lo, hi, err)
+		}
+		result.Endorsements += len(endorsements)
+	}
+	return nil
+}
+
+// report calls OnChunk if set.
+func (s *Seeder) report(phase string, done int) {
+	if s.OnChunk != nil {
+		s.OnChunk(phase, done, s.cfg.Students)
+	}
+}
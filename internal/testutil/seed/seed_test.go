@@ -0,0 +1,201 @@
+package seed
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alem-hub/alem-community-hub/internal/domain/social"
+	"github.com/alem-hub/alem-community-hub/internal/domain/student"
+)
+
+// memoryStore collects everything the seeder writes.
+type memoryStore struct {
+	students     []*student.Student
+	history      []student.StudentXPChange
+	grinds       []*student.DailyGrind
+	streaks      []*student.Streak
+	connections  []*social.Connection
+	endorsements []*social.Endorsement
+	writes       int
+}
+
+func (m *memoryStore) stores() Stores {
+	return Stores{
+		Students:     studentWriterFunc(m.createStudents),
+		Progress:     m,
+		Connections:  connectionWriterFunc(m.createConnections),
+		Endorsements: endorsementWriterFunc(m.createEndorsements),
+	}
+}
+
+func (m *memoryStore) createStudents(_ context.Context, s []*student.Student) error {
+	m.writes++
+	m.students = append(m.students, s...)
+	return nil
+}
+
+func (m *memoryStore) SaveXPChangesBatch(_ context.Context, c []student.StudentXPChange) error {
+	m.history = append(m.history, c...)
+	return nil
+}
+
+func (m *memoryStore) SaveDailyGrindsBatch(_ context.Context, g []*student.DailyGrind) error {
+	m.grinds = append(m.grinds, g...)
+	return nil
+}
+
+func (m *memoryStore) SaveStreaksBatch(_ context.Context, s []*student.Streak) error {
+	m.streaks = append(m.streaks, s...)
+	return nil
+}
+
+func (m *memoryStore) createConnections(_ context.Context, c []*social.Connection) error {
+	m.connections = append(m.connections, c...)
+	return nil
+}
+
+func (m *memoryStore) createEndorsements(_ context.Context, e []*social.Endorsement) error {
+	m.endorsements = append(m.endorsements, e...)
+	return nil
+}
+
+type studentWriterFunc func(context.Context, []*student.Student) error
+
+func (f studentWriterFunc) CreateBatch(ctx context.Context, s []*student.Student) error {
+	return f(ctx, s)
+}
+
+type connectionWriterFunc func(context.Context, []*social.Connection) error
+
+func (f connectionWriterFunc) CreateBatch(ctx context.Context, c []*social.Connection) error {
+	return f(ctx, c)
+}
+
+type endorsementWriterFunc func(context.Context, []*social.Endorsement) error
+
+func (f endorsementWriterFunc) CreateBatch(ctx context.Context, e []*social.Endorsement) error {
+	return f(ctx, e)
+}
+
+func testConfig() Config {
+	cfg := DefaultConfig()
+	cfg.Seed = 42
+	cfg.Students = 300
+	cfg.ChunkSize = 64
+	cfg.Now = time.Date(2025, 6, 16, 12, 0, 0, 0, time.UTC)
+	return cfg
+}
+
+func seedInto(t *testing.T, cfg Config) (*memoryStore, *Result) {
+	t.Helper()
+	store := &memoryStore{}
+	seeder, err := New(cfg, store.stores())
+	require.NoError(t, err)
+	result, err := seeder.Run(context.Background())
+	require.NoError(t, err)
+	return store, result
+}
+
+func TestSeeder_DeterministicAcrossChunkSizes(t *testing.T) {
+	cfg := testConfig()
+	a, resA := seedInto(t, cfg)
+
+	cfg.ChunkSize = 1000
+	b, resB := seedInto(t, cfg)
+
+	assert.Equal(t, 5, a.writes, "300 students in chunks of 64")
+	assert.Equal(t, 1, b.writes)
+
+	resA.Elapsed, resB.Elapsed = 0, 0
+	assert.Equal(t, resA, resB)
+	assert.Equal(t, a.students, b.students)
+	assert.Equal(t, a.history, b.history)
+	assert.Equal(t, a.connections, b.connections)
+	assert.Equal(t, a.endorsements, b.endorsements)
+
+	cfg.Seed = 43
+	c, _ := seedInto(t, cfg)
+	assert.NotEqual(t, a.students[0].ID, c.students[0].ID)
+	assert.NotEqual(t, a.students[0].CurrentXP, c.students[0].CurrentXP)
+}
+
+func TestSeeder_ProgressIsConsistent(t *testing.T) {
+	store, result := seedInto(t, testConfig())
+
+	assert.Equal(t, 300, result.Students)
+	assert.Equal(t, len(store.history), result.XPHistory)
+	assert.Equal(t, len(store.grinds), result.DailyGrinds)
+	assert.NotZero(t, result.Connections)
+	assert.NotZero(t, result.Endorsements)
+
+	finalXP := make(map[string]student.XP, len(store.students))
+	for _, s := range store.students {
+		finalXP[s.ID] = s.CurrentXP
+	}
+
+	lastXP := make(map[string]student.XP)
+	for _, c := range store.history {
+		assert.Equal(t, c.Entry.NewXP-c.Entry.OldXP, c.Entry.Delta)
+		assert.Positive(t, int(c.Entry.Delta))
+		lastXP[c.StudentID] = c.Entry.NewXP
+	}
+	for id, xp := range lastXP {
+		assert.Equal(t, finalXP[id], xp, "history must end at the student's current XP")
+	}
+
+	windowStart := testConfig().Now.AddDate(0, 0, -89).Truncate(24 * time.Hour)
+	for _, g := range store.grinds {
+		assert.Equal(t, g.XPCurrent-g.XPStart, g.XPGained)
+		assert.False(t, g.Date.Before(windowStart))
+		assert.Positive(t, g.StreakDay)
+	}
+
+	for _, s := range store.streaks {
+		assert.LessOrEqual(t, s.CurrentStreak, s.BestStreak)
+	}
+
+	ids := make(map[social.StudentID]bool, len(store.students))
+	for _, s := range store.students {
+		ids[social.StudentID(s.ID)] = true
+	}
+	pairs := make(map[[2]social.StudentID]bool)
+	for _, c := range store.connections {
+		assert.NotEqual(t, c.InitiatorID, c.ReceiverID)
+		assert.True(t, ids[c.ReceiverID])
+		pair := [2]social.StudentID{c.InitiatorID, c.ReceiverID}
+		assert.False(t, pairs[pair], "duplicate connection")
+		pairs[pair] = true
+	}
+}
+
+func TestSeeder_XPFollowsPowerLaw(t *testing.T) {
+	cfg := testConfig()
+	cfg.Students = 2000
+	cfg.ConnectionsPerStudent = 0
+	cfg.EndorsementsPerStudent = 0
+	store, result := seedInto(t, cfg)
+
+	assert.Zero(t, result.Connections)
+
+	xp := make([]int, len(store.students))
+	total := 0
+	for i, s := range store.students {
+		xp[i] = int(s.CurrentXP)
+		total += xp[i]
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(xp)))
+
+	top := 0
+	for _, v := range xp[:len(xp)/10] {
+		top += v
+	}
+	median := xp[len(xp)/2]
+
+	assert.Greater(t, float64(top)/float64(total), 0.3, "top tenth should hold a large share of XP")
+	assert.Greater(t, float64(total)/float64(len(xp)), 1.3*float64(median), "mean is pulled above the median by the tail")
+}
@@ -0,0 +1,291 @@
+// Package config loads application configuration from environment variables.
+//
+// Values are read either with typed loaders (String, Int, Bool, Duration,
+// Enum, ...) or by binding a struct with `env` tags. Problems are collected
+// instead of returned one by one, so a misconfigured deployment reports every
+// missing or malformed variable at once.
+//
+// Supported struct tags:
+//
+//	env:"NAME"            variable name (fields without it are skipped)
+//	default:"value"       value used when the variable is unset or empty
+//	required:"true"       the variable must be set
+//	enum:"a,b"            allowed values (strings only)
+//	secret:"true"         the value is redacted by Dump
+//
+// No external dependencies - uses only standard library.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Source looks up a variable. os.LookupEnv is used by default.
+type Source func(key string) (string, bool)
+
+// ValidationError lists every configuration problem found.
+type ValidationError struct {
+	Problems []string
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return "invalid configuration: " + e.Problems[0]
+	}
+	return fmt.Sprintf("invalid configuration (%d problems):\n  - %s",
+		len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// Loader reads typed values from a Source and collects problems.
+type Loader struct {
+	source   Source
+	problems []string
+}
+
+// New creates a Loader. A nil source reads the process environment.
+func New(source Source) *Loader {
+	if source == nil {
+		source = os.LookupEnv
+	}
+	return &Loader{source: source}
+}
+
+// Errorf records a problem, e.g. a rule spanning several variables.
+func (l *Loader) Errorf(format string, args ...interface{}) {
+	l.problems = append(l.problems, fmt.Sprintf(format, args...))
+}
+
+// Err returns a *ValidationError with all recorded problems, or nil.
+func (l *Loader) Err() error {
+	if len(l.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: slices.Clone(l.problems)}
+}
+
+// lookup returns the trimmed value; empty values count as unset.
+func (l *Loader) lookup(key string) (string, bool) {
+	value, ok := l.source(key)
+	value = strings.TrimSpace(value)
+	return value, ok && value != ""
+}
+
+// String returns the value or def when unset.
+func (l *Loader) String(key, def string) string {
+	if value, ok := l.lookup(key); ok {
+		return value
+	}
+	return def
+}
+
+// Required returns the value and records a problem when it is unset.
+func (l *Loader) Required(key string) string {
+	value, ok := l.lookup(key)
+	if !ok {
+		l.Errorf("%s is required", key)
+	}
+	return value
+}
+
+// Int returns the value parsed as int, or def when unset.
+func (l *Loader) Int(key string, def int) int {
+	value, ok := l.lookup(key)
+	if !ok {
+		return def
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		l.Errorf("%s: %q is not an integer", key, value)
+		return def
+	}
+	return i
+}
+
+// Bool returns the value parsed as bool, or def when unset.
+// Accepts true/false, 1/0 and yes/no.
+func (l *Loader) Bool(key string, def bool) bool {
+	value, ok := l.lookup(key)
+	if !ok {
+		return def
+	}
+	switch strings.ToLower(value) {
+	case "true", "1", "yes", "on":
+		return true
+	case "false", "0", "no", "off":
+		return false
+	}
+	l.Errorf("%s: %q is not a boolean (use true or false)", key, value)
+	return def
+}
+
+// Duration returns the value parsed with time.ParseDuration, or def when unset.
+func (l *Loader) Duration(key string, def time.Duration) time.Duration {
+	value, ok := l.lookup(key)
+	if !ok {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		l.Errorf("%s: %q is not a duration (e.g. 30s, 5m, 1h)", key, value)
+		return def
+	}
+	return d
+}
+
+// Enum returns the value if it is one of allowed, or def when unset.
+func (l *Loader) Enum(key, def string, allowed ...string) string {
+	value, ok := l.lookup(key)
+	if !ok {
+		return def
+	}
+	if !slices.Contains(allowed, value) {
+		l.Errorf("%s: %q is not one of %s", key, value, strings.Join(allowed, ", "))
+		return def
+	}
+	return value
+}
+
+// Strings returns a comma-separated list, skipping empty items.
+func (l *Loader) Strings(key string) []string {
+	value, ok := l.lookup(key)
+	if !ok {
+		return nil
+	}
+
+	var result []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+// Int64s returns a comma-separated list of integers.
+func (l *Loader) Int64s(key string) []int64 {
+	var result []int64
+	for _, part := range l.Strings(key) {
+		i, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			l.Errorf("%s: %q is not an integer", key, part)
+			continue
+		}
+		result = append(result, i)
+	}
+	return result
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// STRUCT BINDING
+// ══════════════════════════════════════════════════════════════════════════════
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Bind fills the `env`-tagged fields of the struct pointed to by dst.
+// Problems with values are recorded in the Loader (see Err); the returned
+// error is only for programming mistakes such as an unsupported field type.
+func (l *Loader) Bind(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return errors.New("config: Bind expects a pointer to a struct")
+	}
+	v = v.Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("env")
+		if key == "" {
+			continue
+		}
+		if err := l.bindField(v.Field(i), field, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bindField loads one tagged field.
+func (l *Loader) bindField(v reflect.Value, field reflect.StructField, key string) error {
+	def := field.Tag.Get("default")
+	if field.Tag.Get("required") == "true" {
+		if _, ok := l.lookup(key); !ok {
+			l.Errorf("%s is required", key)
+			return nil
+		}
+	}
+
+	if field.Type == durationType {
+		d, err := parseDefault(field, def, time.ParseDuration)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(l.Duration(key, d)))
+		return nil
+	}
+
+	switch field.Type.Kind() {
+	case reflect.String:
+		if allowed := field.Tag.Get("enum"); allowed != "" {
+			v.SetString(l.Enum(key, def, strings.Split(allowed, ",")...))
+		} else {
+			v.SetString(l.String(key, def))
+		}
+	case reflect.Bool:
+		b, err := parseDefault(field, def, strconv.ParseBool)
+		if err != nil {
+			return err
+		}
+		v.SetBool(l.Bool(key, b))
+	case reflect.Int:
+		n, err := parseDefault(field, def, strconv.Atoi)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(l.Int(key, n)))
+	case reflect.Slice:
+		switch field.Type.Elem().Kind() {
+		case reflect.String:
+			v.Set(reflect.ValueOf(l.Strings(key)))
+		case reflect.Int64:
+			v.Set(reflect.ValueOf(l.Int64s(key)))
+		default:
+			return fmt.Errorf("config: field %s has unsupported type %s", field.Name, field.Type)
+		}
+	default:
+		return fmt.Errorf("config: field %s has unsupported type %s", field.Name, field.Type)
+	}
+
+	return nil
+}
+
+// parseDefault parses the default tag; an empty tag gives the zero value.
+func parseDefault[T any](field reflect.StructField, def string, parse func(string) (T, error)) (T, error) {
+	var zero T
+	if def == "" {
+		return zero, nil
+	}
+	value, err := parse(def)
+	if err != nil {
+		return zero, fmt.Errorf("config: field %s has invalid default %q: %w", field.Name, def, err)
+	}
+	return value, nil
+}
+
+// Load binds dst from the process environment and returns all problems at once.
+func Load(dst interface{}) error {
+	l := New(nil)
+	if err := l.Bind(dst); err != nil {
+		return err
+	}
+	return l.Err()
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mapSource(env map[string]string) Source {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestLoader_TypedValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		load    func(l *Loader) interface{}
+		want    interface{}
+		problem bool
+	}{
+		{"string set", "x", func(l *Loader) interface{} { return l.String("V", "def") }, "x", false},
+		{"string unset", "", func(l *Loader) interface{} { return l.String("V", "def") }, "def", false},
+		{"int", " 42 ", func(l *Loader) interface{} { return l.Int("V", 1) }, 42, false},
+		{"int malformed", "4x", func(l *Loader) interface{} { return l.Int("V", 1) }, 1, true},
+		{"bool yes", "yes", func(l *Loader) interface{} { return l.Bool("V", false) }, true, false},
+		{"bool false", "FALSE", func(l *Loader) interface{} { return l.Bool("V", true) }, false, false},
+		{"bool malformed", "maybe", func(l *Loader) interface{} { return l.Bool("V", true) }, true, true},
+		{"duration", "90s", func(l *Loader) interface{} { return l.Duration("V", time.Second) }, 90 * time.Second, false},
+		{"duration malformed", "5", func(l *Loader) interface{} { return l.Duration("V", time.Second) }, time.Second, true},
+		{"enum allowed", "webhook", func(l *Loader) interface{} { return l.Enum("V", "polling", "polling", "webhook") }, "webhook", false},
+		{"enum unknown", "push", func(l *Loader) interface{} { return l.Enum("V", "polling", "polling", "webhook") }, "polling", true},
+		{"strings", "a, ,b", func(l *Loader) interface{} { return l.Strings("V") }, []string{"a", "b"}, false},
+		{"int64s", "1,2", func(l *Loader) interface{} { return l.Int64s("V") }, []int64{1, 2}, false},
+		{"int64s malformed", "1,x", func(l *Loader) interface{} { return l.Int64s("V") }, []int64{1}, true},
+		{"required unset", "", func(l *Loader) interface{} { return l.Required("V") }, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(mapSource(map[string]string{"V": tt.value}))
+
+			assert.Equal(t, tt.want, tt.load(l))
+			if tt.problem {
+				assert.Error(t, l.Err())
+			} else {
+				assert.NoError(t, l.Err())
+			}
+		})
+	}
+}
+
+type testConfig struct {
+	Token    string        `env:"TOKEN" required:"true" secret:"true"`
+	Mode     string        `env:"MODE" default:"polling" enum:"polling,webhook"`
+	Port     int           `env:"PORT" default:"8080"`
+	Debug    bool          `env:"DEBUG"`
+	Timeout  time.Duration `env:"TIMEOUT" default:"30s"`
+	Admins   []int64       `env:"ADMINS"`
+	Database string        `env:"DATABASE_URL"`
+	Keys     []string      `env:"KEYS" secret:"true"`
+	Internal string
+}
+
+func TestBind_ReportsAllProblems(t *testing.T) {
+	var cfg testConfig
+	l := New(mapSource(map[string]string{
+		"MODE":    "push",
+		"TIMEOUT": "soon",
+		"PORT":    "9090",
+	}))
+
+	require.NoError(t, l.Bind(&cfg))
+	err := l.Err()
+
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Len(t, verr.Problems, 3)
+	assert.Contains(t, err.Error(), "TOKEN is required")
+	assert.Contains(t, err.Error(), `MODE: "push" is not one of polling, webhook`)
+	assert.Contains(t, err.Error(), "TIMEOUT")
+
+	// Корректные значения всё равно подставлены
+	assert.Equal(t, 9090, cfg.Port)
+	assert.Equal(t, 30*time.Second, cfg.Timeout)
+}
+
+func TestBind_RejectsUnsupportedFields(t *testing.T) {
+	var cfg struct {
+		Ratio float64 `env:"RATIO"`
+	}
+	assert.Error(t, New(mapSource(nil)).Bind(&cfg))
+	assert.Error(t, New(mapSource(nil)).Bind(cfg))
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := testConfig{
+		Token:    "123:abc",
+		Mode:     "polling",
+		Admins:   []int64{1, 2},
+		Database: "postgres://hub:s3cret@db:5432/hub?sslmode=require",
+		Internal: "not dumped",
+	}
+
+	values := Redacted(&cfg)
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"TOKEN", "***"},
+		{"KEYS", ""}, // пустой секрет не маскируется, чтобы было видно, что он не задан
+		{"MODE", "polling"},
+		{"ADMINS", "1,2"},
+		{"DATABASE_URL", "postgres://hub:***@db:5432/hub?sslmode=require"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, values[tt.key], tt.key)
+	}
+	assert.NotContains(t, values, "Internal")
+	assert.Equal(t, "redis://localhost:6379", redactURL("redis://localhost:6379"))
+	assert.Equal(t, "redis://:***@localhost:6379", redactURL("redis://:pw@localhost:6379"))
+}
+
+func TestDiff(t *testing.T) {
+	before := testConfig{Token: "123:abc", Mode: "polling", Admins: []int64{1}, Database: "postgres://hub:old@db/hub"}
+	after := before
+	after.Token = "456:def"
+	after.Admins = []int64{1, 2}
+	after.Database = "postgres://hub:new@db/hub"
+	after.Internal = "not compared"
+
+	// Секреты сравниваются по значению, но в отчёте замаскированы
+	assert.Equal(t, []Change{
+		{Key: "ADMINS", Old: "1", New: "1,2"},
+		{Key: "DATABASE_URL", Old: "postgres://hub:***@db/hub", New: "postgres://hub:***@db/hub"},
+		{Key: "TOKEN", Old: "***", New: "***"},
+	}, Diff(&before, &after))
+	assert.Empty(t, Diff(before, before))
+}
@@ -0,0 +1,295 @@
+package featureflag
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultRefreshInterval is how often a running Client reloads flags.
+const DefaultRefreshInterval = 30 * time.Second
+
+// Store persists flags.
+type Store interface {
+	// List returns all flags.
+	List(ctx context.Context) ([]Flag, error)
+
+	// Get returns a flag or ErrNotFound.
+	Get(ctx context.Context, name string) (Flag, error)
+
+	// Save creates or replaces a flag.
+	Save(ctx context.Context, flag Flag) error
+}
+
+// FailMode overrides the per-flag FailOpen setting while flags cannot be
+// loaded.
+type FailMode string
+
+const (
+	// FailModeFlag uses the FailOpen setting of each flag (default).
+	FailModeFlag FailMode = "flag"
+	// FailModeOpen turns every known flag on.
+	FailModeOpen FailMode = "open"
+	// FailModeClosed turns every flag off.
+	FailModeClosed FailMode = "closed"
+)
+
+// Client evaluates flags from an in-memory snapshot that is reloaded from
+// the Store by Refresh (every RefreshInterval when started with Run).
+//
+// While the last refresh failed, or before the first one succeeded, every
+// flag answers with its FailOpen setting (or the process-wide FailMode)
+// instead of its rollout rules.
+// Flags missing from the Store fall back to the defaults given to NewClient;
+// unknown flags are off.
+type Client struct {
+	store    Store
+	interval time.Duration
+	logger   *slog.Logger
+	defaults map[string]Flag
+
+	mu       sync.RWMutex
+	flags    map[string]Flag
+	healthy  bool
+	failMode FailMode
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithRefreshInterval sets how often Run reloads flags.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(c *Client) {
+		if interval > 0 {
+			c.interval = interval
+		}
+	}
+}
+
+// WithLogger sets the logger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// WithDefaults sets the flags used when the Store has no row for them.
+// Their FailOpen setting also applies before the first successful refresh.
+func WithDefaults(flags ...Flag) Option {
+	return func(c *Client) {
+		for _, flag := range flags {
+			c.defaults[flag.Name] = flag
+		}
+	}
+}
+
+// NewClient creates a new Client. Flags are not loaded until Refresh or Run.
+func NewClient(store Store, opts ...Option) *Client {
+	c := &Client{
+		store:    store,
+		interval: DefaultRefreshInterval,
+		logger:   slog.Default(),
+		defaults: make(map[string]Flag),
+		flags:    make(map[string]Flag),
+		failMode: FailModeFlag,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.logger = c.logger.With("component", "featureflag")
+	return c
+}
+
+// Refresh reloads all flags from the Store. On error the previous snapshot
+// is kept but flags answer with their fail mode until a refresh succeeds.
+func (c *Client) Refresh(ctx context.Context) error {
+	flags, err := c.store.List(ctx)
+	if err != nil {
+		c.mu.Lock()
+		wasHealthy := c.healthy
+		c.healthy = false
+		c.mu.Unlock()
+
+		if wasHealthy {
+			c.logger.Warn("failed to refresh feature flags, using fail modes", "error", err)
+		}
+		return err
+	}
+
+	snapshot := make(map[string]Flag, len(flags))
+	for _, flag := range flags {
+		snapshot[flag.Name] = flag
+	}
+
+	c.mu.Lock()
+	c.flags = snapshot
+	c.healthy = true
+	c.mu.Unlock()
+	return nil
+}
+
+// Run refreshes flags immediately and then every refresh interval until
+// the context is cancelled.
+func (c *Client) Run(ctx context.Context) {
+	if err := c.Refresh(ctx); err != nil {
+		c.logger.Warn("initial feature flag load failed", "error", err)
+	}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = c.Refresh(ctx)
+		}
+	}
+}
+
+// SetFailMode sets how flags answer while they cannot be loaded. Unknown
+// modes fall back to FailModeFlag.
+func (c *Client) SetFailMode(mode FailMode) {
+	if mode != FailModeOpen && mode != FailModeClosed {
+		mode = FailModeFlag
+	}
+
+	c.mu.Lock()
+	c.failMode = mode
+	c.mu.Unlock()
+}
+
+// Enabled reports whether the feature is on for the student.
+func (c *Client) Enabled(name, studentID, cohort string) bool {
+	c.mu.RLock()
+	flag, ok := c.flags[name]
+	healthy := c.healthy
+	failMode := c.failMode
+	c.mu.RUnlock()
+
+	if !ok {
+		flag, ok = c.defaults[name]
+		if !ok {
+			return false
+		}
+	}
+	if !healthy {
+		switch failMode {
+		case FailModeOpen:
+			return true
+		case FailModeClosed:
+			return false
+		}
+		return flag.FailOpen
+	}
+	return flag.EnabledFor(studentID, cohort)
+}
+
+// List returns the stored flags.
+func (c *Client) List(ctx context.Context) ([]Flag, error) {
+	return c.store.List(ctx)
+}
+
+// Get returns a stored flag.
+func (c *Client) Get(ctx context.Context, name string) (Flag, error) {
+	return c.store.Get(ctx, name)
+}
+
+// Set validates and stores a flag. The change takes effect in this process
+// immediately and in other processes on their next refresh.
+func (c *Client) Set(ctx context.Context, flag Flag) (Flag, error) {
+	if err := flag.Validate(); err != nil {
+		return Flag{}, err
+	}
+	flag.UpdatedAt = time.Now().UTC()
+
+	if err := c.store.Save(ctx, flag); err != nil {
+		return Flag{}, err
+	}
+
+	c.mu.Lock()
+	c.flags[flag.Name] = flag
+	c.mu.Unlock()
+
+	c.logger.Info("feature flag updated",
+		"flag", flag.Name,
+		"enabled", flag.Enabled,
+		"rollout_percent", flag.RolloutPercent,
+		"cohorts", flag.Cohorts,
+	)
+	return flag, nil
+}
+
+// Seed stores the defaults that are missing from the Store, so they can be
+// managed through the admin API. Existing flags are never overwritten.
+func (c *Client) Seed(ctx context.Context) error {
+	existing, err := c.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	stored := make(map[string]bool, len(existing))
+	for _, flag := range existing {
+		stored[flag.Name] = true
+	}
+
+	for name, flag := range c.defaults {
+		if stored[name] {
+			continue
+		}
+		if _, err := c.Set(ctx, flag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CONTEXT HELPERS
+// ══════════════════════════════════════════════════════════════════════════════
+
+type contextKey struct{}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultClient *Client
+)
+
+// SetDefault sets the process-wide client used when the context carries none.
+func SetDefault(c *Client) {
+	defaultMu.Lock()
+	defaultClient = c
+	defaultMu.Unlock()
+}
+
+// WithClient returns a context carrying the client.
+func WithClient(ctx context.Context, c *Client) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the client from the context, or the default client.
+// It returns nil when neither is set.
+func FromContext(ctx context.Context) *Client {
+	if c, ok := ctx.Value(contextKey{}).(*Client); ok && c != nil {
+		return c
+	}
+
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultClient
+}
+
+// Enabled reports whether the feature is on for the student using the
+// client from the context. Without a client every feature is on, so code
+// that is not wired to flags (tests, tools) keeps its behaviour.
+func Enabled(ctx context.Context, name, studentID, cohort string) bool {
+	c := FromContext(ctx)
+	if c == nil {
+		return true
+	}
+	return c.Enabled(name, studentID, cohort)
+}
@@ -0,0 +1,283 @@
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is an in-memory Store whose reads can be made to fail.
+type memoryStore struct {
+	mu    sync.Mutex
+	flags map[string]Flag
+	err   error
+}
+
+func newMemoryStore(flags ...Flag) *memoryStore {
+	s := &memoryStore{flags: make(map[string]Flag)}
+	for _, flag := range flags {
+		s.flags[flag.Name] = flag
+	}
+	return s
+}
+
+func (s *memoryStore) List(ctx context.Context) ([]Flag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	flags := make([]Flag, 0, len(s.flags))
+	for _, flag := range s.flags {
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, name string) (Flag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	flag, ok := s.flags[name]
+	if !ok {
+		return Flag{}, ErrNotFound
+	}
+	return flag, nil
+}
+
+func (s *memoryStore) Save(ctx context.Context, flag Flag) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flags[flag.Name] = flag
+	return nil
+}
+
+func (s *memoryStore) put(flag Flag) {
+	s.mu.Lock()
+	s.flags[flag.Name] = flag
+	s.mu.Unlock()
+}
+
+func (s *memoryStore) fail(err error) {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+func students(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("student-%04d", i)
+	}
+	return ids
+}
+
+func TestBucket_IsDeterministicAndMonotonic(t *testing.T) {
+	ids := students(2000)
+
+	// Same input, same bucket; the value is pinned so a hash change that
+	// reshuffles every rollout fails loudly.
+	assert.Equal(t, Bucket("stuck_detection", "student-0001"), Bucket("stuck_detection", "student-0001"))
+	assert.Equal(t, 12, Bucket("stuck_detection", "student-0001"))
+
+	counts := make([]int, 100)
+	differ := 0
+	for _, id := range ids {
+		b := Bucket("stuck_detection", id)
+		require.GreaterOrEqual(t, b, 0)
+		require.Less(t, b, 100)
+		counts[b]++
+		if b != Bucket("competitor_close", id) {
+			differ++
+		}
+	}
+
+	// Roughly uniform: no bucket holds more than 3x its share
+	for b, n := range counts {
+		assert.Less(t, n, 60, "bucket %d", b)
+	}
+	// Flags get independent populations
+	assert.Greater(t, differ, len(ids)*9/10)
+
+	// Raising the percentage only adds students
+	prev := map[string]bool{}
+	for _, percent := range []int{0, 5, 25, 50, 100} {
+		flag := Flag{Name: "stuck_detection", Enabled: true, RolloutPercent: percent}
+		on := 0
+		for _, id := range ids {
+			enabled := flag.EnabledFor(id, "")
+			if prev[id] {
+				require.True(t, enabled, "%s dropped out at %d%%", id, percent)
+			}
+			prev[id] = enabled
+			if enabled {
+				on++
+			}
+		}
+		assert.InDelta(t, percent*len(ids)/100, on, float64(len(ids))*0.04, "%d%%", percent)
+	}
+}
+
+func TestPick_IsStableAndFollowsWeights(t *testing.T) {
+	ids := students(10000)
+	weights := []int{70, 0, 30}
+
+	counts := make([]int, len(weights))
+	for _, id := range ids {
+		i := Pick("rank_wording", id, weights)
+		require.Equal(t, i, Pick("rank_wording", id, weights), "the variant is deterministic")
+		counts[i]++
+	}
+
+	assert.Zero(t, counts[1], "a zero-weight variant gets nobody")
+	assert.InDelta(t, 7000, counts[0], 300)
+	assert.InDelta(t, 3000, counts[2], 300)
+	assert.Equal(t, -1, Pick("rank_wording", "student-0001", []int{0, 0}))
+}
+
+func TestFlag_EnabledFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    Flag
+		student string
+		cohort  string
+		want    bool
+	}{
+		{"disabled beats allowlist", Flag{Cohorts: []string{"2024-spring"}, RolloutPercent: 100}, "s1", "2024-spring", false},
+		{"allowlist beats zero percent", Flag{Enabled: true, Cohorts: []string{"2024-spring"}}, "s1", "2024-spring", true},
+		{"other cohort uses percent", Flag{Enabled: true, Cohorts: []string{"2024-spring"}}, "s1", "2025-fall", false},
+		{"empty cohort is not allowlisted", Flag{Enabled: true, Cohorts: []string{"2024-spring"}}, "s1", "", false},
+		{"full rollout", Flag{Enabled: true, RolloutPercent: 100}, "s1", "", true},
+		{"full rollout without student", Flag{Enabled: true, RolloutPercent: 100}, "", "", true},
+		{"partial rollout without student", Flag{Enabled: true, RolloutPercent: 99}, "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.flag.Name = "stuck_detection"
+			assert.Equal(t, tt.want, tt.flag.EnabledFor(tt.student, tt.cohort))
+		})
+	}
+}
+
+func TestFlag_Validate(t *testing.T) {
+	assert.NoError(t, Flag{Name: "stuck_detection", RolloutPercent: 100}.Validate())
+
+	for _, flag := range []Flag{
+		{Name: ""},
+		{Name: "Stuck Detection"},
+		{Name: "stuck_detection", RolloutPercent: 101},
+		{Name: "stuck_detection", RolloutPercent: -1},
+		{Name: "stuck_detection", Cohorts: []string{""}},
+	} {
+		assert.ErrorIs(t, flag.Validate(), ErrInvalidFlag, "%+v", flag)
+	}
+}
+
+func TestClient_RefreshAndFailModes(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore(
+		Flag{Name: "risky", Enabled: true, RolloutPercent: 100, FailOpen: false},
+		Flag{Name: "safe", Enabled: false, FailOpen: true},
+	)
+	client := NewClient(store, WithDefaults(
+		Flag{Name: "risky", FailOpen: true},
+		Flag{Name: "fallback", Enabled: true, RolloutPercent: 100},
+	))
+
+	// Before the first load the defaults' fail modes apply
+	assert.True(t, client.Enabled("risky", "s1", ""))
+	assert.False(t, client.Enabled("safe", "s1", ""))
+	assert.False(t, client.Enabled("fallback", "s1", ""))
+
+	require.NoError(t, client.Refresh(ctx))
+	assert.True(t, client.Enabled("risky", "s1", ""))
+	assert.False(t, client.Enabled("safe", "s1", ""))
+	assert.True(t, client.Enabled("fallback", "s1", ""), "missing rows fall back to defaults")
+	assert.False(t, client.Enabled("unknown", "s1", ""))
+
+	// Changes in the store are picked up only on refresh
+	store.put(Flag{Name: "risky", Enabled: false, FailOpen: false})
+	assert.True(t, client.Enabled("risky", "s1", ""))
+	require.NoError(t, client.Refresh(ctx))
+	assert.False(t, client.Enabled("risky", "s1", ""))
+
+	// Database unreachable: each flag answers with its stored fail mode
+	store.fail(errors.New("connection refused"))
+	require.Error(t, client.Refresh(ctx))
+	assert.False(t, client.Enabled("risky", "s1", ""))
+	assert.True(t, client.Enabled("safe", "s1", ""))
+
+	// Recovered: rollout rules apply again
+	store.fail(nil)
+	require.NoError(t, client.Refresh(ctx))
+	assert.False(t, client.Enabled("safe", "s1", ""))
+}
+
+func TestClient_FailModeOverridesFlags(t *testing.T) {
+	store := newMemoryStore(
+		Flag{Name: "risky", Enabled: true, RolloutPercent: 100, FailOpen: false},
+		Flag{Name: "safe", Enabled: false, FailOpen: true},
+	)
+	client := NewClient(store)
+	require.NoError(t, client.Refresh(context.Background()))
+	store.fail(errors.New("connection refused"))
+	require.Error(t, client.Refresh(context.Background()))
+
+	client.SetFailMode(FailModeClosed)
+	assert.False(t, client.Enabled("safe", "s1", ""))
+
+	client.SetFailMode(FailModeOpen)
+	assert.True(t, client.Enabled("risky", "s1", ""))
+	assert.False(t, client.Enabled("unknown", "s1", ""), "unknown flags stay off")
+
+	client.SetFailMode(FailModeFlag)
+	assert.False(t, client.Enabled("risky", "s1", ""))
+	assert.True(t, client.Enabled("safe", "s1", ""))
+}
+
+func TestClient_SetAppliesImmediately(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	client := NewClient(store, WithDefaults(Flag{Name: "stuck_detection", Enabled: true, RolloutPercent: 100}))
+
+	require.NoError(t, client.Seed(ctx))
+	require.NoError(t, client.Refresh(ctx))
+	stored, err := store.Get(ctx, "stuck_detection")
+	require.NoError(t, err)
+	assert.Equal(t, 100, stored.RolloutPercent)
+
+	_, err = client.Set(ctx, Flag{Name: "stuck_detection", Enabled: true, Cohorts: []string{"pilot"}})
+	require.NoError(t, err)
+	assert.True(t, client.Enabled("stuck_detection", "s1", "pilot"))
+	assert.False(t, client.Enabled("stuck_detection", "s1", "other"))
+
+	// Seed never overwrites a tuned flag
+	require.NoError(t, client.Seed(ctx))
+	stored, err = store.Get(ctx, "stuck_detection")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pilot"}, stored.Cohorts)
+
+	_, err = client.Set(ctx, Flag{Name: "stuck_detection", RolloutPercent: 150})
+	assert.ErrorIs(t, err, ErrInvalidFlag)
+}
+
+func TestEnabled_UsesContextThenDefaultClient(t *testing.T) {
+	ctx := context.Background()
+	assert.True(t, Enabled(ctx, "anything", "s1", ""), "no client keeps features on")
+
+	off := NewClient(newMemoryStore(Flag{Name: "f", Enabled: false}))
+	on := NewClient(newMemoryStore(Flag{Name: "f", Enabled: true, RolloutPercent: 100}))
+	require.NoError(t, off.Refresh(ctx))
+	require.NoError(t, on.Refresh(ctx))
+
+	SetDefault(off)
+	defer SetDefault(nil)
+
+	assert.False(t, Enabled(ctx, "f", "s1", ""))
+	assert.True(t, Enabled(WithClient(ctx, on), "f", "s1", ""))
+}
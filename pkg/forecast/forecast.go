@@ -0,0 +1,195 @@
+// Package forecast projects when a student reaches an XP target at their
+// recent pace. The pace is the mean XP of recent active days (days without
+// XP are left out of the mean but still slow the calendar down), and the
+// confidence band comes from the day-to-day variance of that XP.
+// No external dependencies - uses only standard library.
+package forecast
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// MaxActiveDays is how many recent active days make up the pace.
+	MaxActiveDays = 21
+
+	// MinActiveDays is the fewest active days a forecast is made from.
+	MinActiveDays = 3
+
+	// Lookback is how far back active days are looked for.
+	Lookback = 60 * 24 * time.Hour
+
+	// MaxHorizonDays is the furthest forecast; targets further away are
+	// reported as out of reach rather than as a date years ahead.
+	MaxHorizonDays = 730
+
+	// confidenceZ is the z-score of the 80% confidence band.
+	confidenceZ = 1.2816
+)
+
+// Day is the XP gained on one day.
+type Day struct {
+	Date time.Time
+	XP   int64
+}
+
+// Pace is the recent XP pace of a student.
+type Pace struct {
+	// ActiveDays is the number of active days the pace is based on.
+	ActiveDays int `json:"active_days"`
+
+	// MeanXP is the mean XP per active day.
+	MeanXP float64 `json:"mean_xp"`
+
+	// StdDevXP is the standard deviation of XP per active day.
+	StdDevXP float64 `json:"std_dev_xp"`
+
+	// ActiveShare is the share of calendar days with activity, in (0, 1].
+	ActiveShare float64 `json:"active_share"`
+}
+
+// Sufficient reports whether the pace is based on enough active days.
+func (p Pace) Sufficient() bool {
+	return p.ActiveDays >= MinActiveDays && p.MeanXP > 0
+}
+
+// PaceOf computes the pace from daily XP: the last MaxActiveDays days with
+// positive XP within Lookback of now. Days may come in any order.
+func PaceOf(days []Day, now time.Time) Pace {
+	today := dayStart(now)
+	since := today.Add(-Lookback)
+
+	active := make([]Day, 0, len(days))
+	for _, d := range days {
+		date := dayStart(d.Date.In(now.Location()))
+		if d.XP <= 0 || date.Before(since) || date.After(today) {
+			continue
+		}
+		active = append(active, Day{Date: date, XP: d.XP})
+	}
+	if len(active) == 0 {
+		return Pace{}
+	}
+
+	sort.Slice(active, func(i, j int) bool { return active[i].Date.After(active[j].Date) })
+	if len(active) > MaxActiveDays {
+		active = active[:MaxActiveDays]
+	}
+
+	n := float64(len(active))
+	var sum float64
+	for _, d := range active {
+		sum += float64(d.XP)
+	}
+	mean := sum / n
+
+	var variance float64
+	if len(active) > 1 {
+		for _, d := range active {
+			variance += (float64(d.XP) - mean) * (float64(d.XP) - mean)
+		}
+		variance /= n - 1
+	}
+
+	oldest := active[len(active)-1].Date
+	span := daysBetween(oldest, today) + 1
+
+	return Pace{
+		ActiveDays:  len(active),
+		MeanXP:      mean,
+		StdDevXP:    math.Sqrt(variance),
+		ActiveShare: math.Min(1, n/float64(span)),
+	}
+}
+
+// Status is the outcome of a forecast.
+type Status string
+
+const (
+	// StatusForecast - the target date is estimated.
+	StatusForecast Status = "forecast"
+
+	// StatusReached - the target is already reached.
+	StatusReached Status = "reached"
+
+	// StatusInsufficientData - too little recent activity to estimate.
+	StatusInsufficientData Status = "insufficient_data"
+
+	// StatusOutOfReach - the target is more than MaxHorizonDays away.
+	StatusOutOfReach Status = "out_of_reach"
+)
+
+// Forecast is the projected date of reaching a target.
+type Forecast struct {
+	Status Status `json:"status"`
+
+	// RemainingXP is the XP still needed (0 once reached).
+	RemainingXP int64 `json:"remaining_xp"`
+
+	// Days is the expected number of calendar days until the target;
+	// EarliestDays and LatestDays bound the 80% confidence band.
+	Days         int `json:"days,omitempty"`
+	EarliestDays int `json:"earliest_days,omitempty"`
+	LatestDays   int `json:"latest_days,omitempty"`
+
+	// Date, Earliest and Latest are the same as dates (StatusForecast only).
+	Date     time.Time `json:"date,omitempty"`
+	Earliest time.Time `json:"earliest,omitempty"`
+	Latest   time.Time `json:"latest,omitempty"`
+}
+
+// Estimate projects when currentXP grows to targetXP at the given pace.
+//
+// The XP of k future active days is roughly normal with mean k·μ and
+// standard deviation σ·√k, so the band ends are the k at which the
+// pessimistic (k·μ - zσ√k) and optimistic (k·μ + zσ√k) sums reach the
+// remaining XP. Active days are converted to calendar days by ActiveShare.
+func Estimate(currentXP, targetXP int64, pace Pace, now time.Time) Forecast {
+	remaining := targetXP - currentXP
+	if remaining <= 0 {
+		return Forecast{Status: StatusReached}
+	}
+	if !pace.Sufficient() {
+		return Forecast{Status: StatusInsufficientData, RemainingXP: remaining}
+	}
+
+	mu, zs, r := pace.MeanXP, confidenceZ*pace.StdDevXP, float64(remaining)
+	disc := math.Sqrt(zs*zs + 4*mu*r)
+	expected := r / mu
+	earliest := math.Pow((disc-zs)/(2*mu), 2)
+	latest := math.Pow((disc+zs)/(2*mu), 2)
+
+	toCalendar := func(activeDays float64) int {
+		return int(math.Ceil(activeDays/pace.ActiveShare - 1e-9))
+	}
+
+	f := Forecast{
+		Status:       StatusForecast,
+		RemainingXP:  remaining,
+		Days:         toCalendar(expected),
+		EarliestDays: toCalendar(earliest),
+		LatestDays:   toCalendar(latest),
+	}
+	if f.Days > MaxHorizonDays {
+		return Forecast{Status: StatusOutOfReach, RemainingXP: remaining}
+	}
+
+	today := dayStart(now)
+	f.Date = today.AddDate(0, 0, f.Days)
+	f.Earliest = today.AddDate(0, 0, f.EarliestDays)
+	f.Latest = today.AddDate(0, 0, min(f.LatestDays, MaxHorizonDays))
+	return f
+}
+
+// dayStart returns midnight of t in its location.
+func dayStart(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// daysBetween returns the number of calendar days from a to b.
+func daysBetween(a, b time.Time) int {
+	return int(math.Round(b.Sub(a).Hours() / 24))
+}
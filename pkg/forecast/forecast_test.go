@@ -0,0 +1,156 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2025, time.March, 20, 15, 0, 0, 0, time.UTC)
+
+// daily returns XP for consecutive days ending yesterday.
+func daily(xp ...int64) []Day {
+	days := make([]Day, len(xp))
+	for i, v := range xp {
+		days[i] = Day{Date: now.AddDate(0, 0, i-len(xp)), XP: v}
+	}
+	return days
+}
+
+func TestPaceOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		days  []Day
+		want  Pace
+		delta float64
+	}{
+		{
+			name: "no days",
+			want: Pace{},
+		},
+		{
+			name: "steady pace every day",
+			days: daily(100, 100, 100, 100),
+			// Four active days out of the five since the first one (today has none yet)
+			want: Pace{ActiveDays: 4, MeanXP: 100, StdDevXP: 0, ActiveShare: 0.8},
+		},
+		{
+			name: "zero days are left out of the mean but slow the calendar",
+			days: daily(200, 0, 200, 0, 200, 0),
+			want: Pace{ActiveDays: 3, MeanXP: 200, StdDevXP: 0, ActiveShare: 3.0 / 7},
+		},
+		{
+			name: "variance of daily XP",
+			days: daily(50, 150),
+			want: Pace{ActiveDays: 2, MeanXP: 100, StdDevXP: 70.7107, ActiveShare: 2.0 / 3},
+		},
+		{
+			name: "only the last 21 active days count",
+			days: append(daily(make30(1000)...)[:9], daily(make30(10)...)[9:]...),
+			want: Pace{ActiveDays: 21, MeanXP: 10, StdDevXP: 0, ActiveShare: 21.0 / 22},
+		},
+		{
+			name: "activity older than the lookback is ignored",
+			days: []Day{{Date: now.Add(-Lookback - 48*time.Hour), XP: 500}},
+			want: Pace{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PaceOf(tt.days, now)
+			assert.Equal(t, tt.want.ActiveDays, got.ActiveDays)
+			assert.InDelta(t, tt.want.MeanXP, got.MeanXP, 1e-3)
+			assert.InDelta(t, tt.want.StdDevXP, got.StdDevXP, 1e-3)
+			assert.InDelta(t, tt.want.ActiveShare, got.ActiveShare, 1e-3)
+		})
+	}
+}
+
+func make30(xp int64) []int64 {
+	out := make([]int64, 30)
+	for i := range out {
+		out[i] = xp
+	}
+	return out
+}
+
+func TestEstimate(t *testing.T) {
+	steady := Pace{ActiveDays: 21, MeanXP: 100, ActiveShare: 1}
+	today := time.Date(2025, time.March, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		current, target int64
+		pace            Pace
+		want            Forecast
+	}{
+		{
+			name:    "already past the target",
+			current: 1200, target: 1000,
+			pace: steady,
+			want: Forecast{Status: StatusReached},
+		},
+		{
+			name:    "exactly at the target",
+			current: 1000, target: 1000,
+			pace: Pace{},
+			want: Forecast{Status: StatusReached},
+		},
+		{
+			name:    "no recent activity",
+			current: 100, target: 1000,
+			pace: Pace{},
+			want: Forecast{Status: StatusInsufficientData, RemainingXP: 900},
+		},
+		{
+			name:    "too few active days",
+			current: 100, target: 1000,
+			pace: Pace{ActiveDays: 2, MeanXP: 500, ActiveShare: 1},
+			want: Forecast{Status: StatusInsufficientData, RemainingXP: 900},
+		},
+		{
+			name:    "steady pace has no band",
+			current: 0, target: 1000,
+			pace: steady,
+			want: Forecast{
+				Status: StatusForecast, RemainingXP: 1000,
+				Days: 10, EarliestDays: 10, LatestDays: 10,
+				Date: today.AddDate(0, 0, 10), Earliest: today.AddDate(0, 0, 10), Latest: today.AddDate(0, 0, 10),
+			},
+		},
+		{
+			name:    "every other day doubles the calendar time",
+			current: 0, target: 1000,
+			pace: Pace{ActiveDays: 10, MeanXP: 100, ActiveShare: 0.5},
+			want: Forecast{
+				Status: StatusForecast, RemainingXP: 1000,
+				Days: 20, EarliestDays: 20, LatestDays: 20,
+				Date: today.AddDate(0, 0, 20), Earliest: today.AddDate(0, 0, 20), Latest: today.AddDate(0, 0, 20),
+			},
+		},
+		{
+			name:    "variance widens the band",
+			current: 0, target: 1000,
+			pace: Pace{ActiveDays: 21, MeanXP: 100, StdDevXP: 100, ActiveShare: 1},
+			want: Forecast{
+				Status: StatusForecast, RemainingXP: 1000,
+				Days: 10, EarliestDays: 7, LatestDays: 15,
+				Date: today.AddDate(0, 0, 10), Earliest: today.AddDate(0, 0, 7), Latest: today.AddDate(0, 0, 15),
+			},
+		},
+		{
+			name:    "target years away",
+			current: 0, target: 1_000_000,
+			pace: steady,
+			want: Forecast{Status: StatusOutOfReach, RemainingXP: 1_000_000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Estimate(tt.current, tt.target, tt.pace, now))
+		})
+	}
+}
@@ -0,0 +1,393 @@
+// Package httpclient provides a tuned HTTP client for external APIs (Alem API, Telegram API).
+// All clients share one transport so connections are pooled per host, and every
+// request is traced so slow calls can be broken down into DNS, connect and TTFB.
+// No external dependencies - uses only standard library.
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// TRANSPORT
+// ══════════════════════════════════════════════════════════════════════════════
+
+// TransportConfig holds connection pool and network timeout settings.
+type TransportConfig struct {
+	// MaxIdleConns is the maximum number of idle connections across all hosts.
+	// Default: 100
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost is the maximum number of idle connections per host.
+	// The net/http default of 2 causes connection churn under digest load.
+	// Default: 32
+	MaxIdleConnsPerHost int
+
+	// IdleConnTimeout is how long an idle connection stays in the pool.
+	// Default: 90s
+	IdleConnTimeout time.Duration
+
+	// DialTimeout is the maximum time to establish a TCP connection.
+	// Default: 5s
+	DialTimeout time.Duration
+
+	// KeepAlive is the TCP keep-alive probe interval.
+	// Default: 30s
+	KeepAlive time.Duration
+
+	// TLSHandshakeTimeout is the maximum time for the TLS handshake.
+	// Default: 5s
+	TLSHandshakeTimeout time.Duration
+
+	// ResponseHeaderTimeout is the maximum time to wait for response headers
+	// after the request is written. Zero means no limit, which long polling needs.
+	// Default: 0
+	ResponseHeaderTimeout time.Duration
+
+	// ProxyFromEnvironment routes requests through HTTP_PROXY/HTTPS_PROXY/NO_PROXY.
+	// Default: true
+	ProxyFromEnvironment bool
+}
+
+// DefaultTransportConfig returns sensible defaults.
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxIdleConns:         100,
+		MaxIdleConnsPerHost:  32,
+		IdleConnTimeout:      90 * time.Second,
+		DialTimeout:          5 * time.Second,
+		KeepAlive:            30 * time.Second,
+		TLSHandshakeTimeout:  5 * time.Second,
+		ProxyFromEnvironment: true,
+	}
+}
+
+// NewTransport creates a transport with the given settings.
+func NewTransport(config TransportConfig) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   config.DialTimeout,
+		KeepAlive: config.KeepAlive,
+	}
+
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          config.MaxIdleConns,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		TLSHandshakeTimeout:   config.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: config.ResponseHeaderTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+	if config.ProxyFromEnvironment {
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+
+	return transport
+}
+
+var (
+	sharedTransport     *http.Transport
+	sharedTransportOnce sync.Once
+)
+
+// SharedTransport returns the process-wide transport used by clients
+// that don't set their own.
+func SharedTransport() *http.Transport {
+	sharedTransportOnce.Do(func() {
+		sharedTransport = NewTransport(DefaultTransportConfig())
+	})
+	return sharedTransport
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CLIENT
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Config holds client configuration.
+type Config struct {
+	// Name identifies the client in logs and metrics (e.g. "alem", "telegram").
+	Name string
+
+	// Timeout is the default per-request timeout, including reading the body.
+	// Can be overridden per call with WithTimeout.
+	// Default: 30s
+	Timeout time.Duration
+
+	// Transport overrides the shared transport.
+	Transport http.RoundTripper
+
+	// Logger receives per-request trace timings at debug level.
+	Logger *slog.Logger
+}
+
+// DefaultConfig returns sensible defaults for the given client name.
+func DefaultConfig(name string) Config {
+	return Config{
+		Name:    name,
+		Timeout: 30 * time.Second,
+	}
+}
+
+// RequestOption customizes a single request.
+type RequestOption func(*requestOptions)
+
+// requestOptions holds per-request settings.
+type requestOptions struct {
+	timeout time.Duration
+}
+
+// WithTimeout overrides the client timeout for one request.
+// Useful for long polling, where the server holds the request open on purpose.
+func WithTimeout(d time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		if d > 0 {
+			o.timeout = d
+		}
+	}
+}
+
+// Client is an HTTP client with per-request timeouts, tracing and metrics.
+type Client struct {
+	config Config
+	http   *http.Client
+	logger *slog.Logger
+
+	inFlight    atomic.Int64
+	requests    atomic.Int64
+	failures    atomic.Int64
+	reusedConns atomic.Int64
+	newConns    atomic.Int64
+}
+
+// New creates a new Client.
+func New(config Config) *Client {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultConfig(config.Name).Timeout
+	}
+	if config.Transport == nil {
+		config.Transport = SharedTransport()
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+
+	return &Client{
+		config: config,
+		// No http.Client.Timeout: it can't be raised per request, so the
+		// deadline is set on the request context in Do instead.
+		http:   &http.Client{Transport: config.Transport},
+		logger: config.Logger,
+	}
+}
+
+// Do sends the request with the client timeout (or the one from opts).
+// The timeout covers reading the body, which must be closed by the caller.
+func (c *Client) Do(req *http.Request, opts ...RequestOption) (*http.Response, error) {
+	options := requestOptions{timeout: c.config.Timeout}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), options.timeout)
+	t := &requestTrace{start: time.Now()}
+	ctx = httptrace.WithClientTrace(ctx, t.clientTrace())
+
+	c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+	c.requests.Add(1)
+
+	resp, err := c.http.Do(req.WithContext(ctx))
+
+	timings := t.snapshot()
+	if timings.gotConn {
+		if timings.reused {
+			c.reusedConns.Add(1)
+		} else {
+			c.newConns.Add(1)
+		}
+	}
+
+	if err != nil {
+		cancel()
+		c.failures.Add(1)
+		c.logTrace(req, timings, 0, options.timeout, err)
+		return nil, err
+	}
+
+	c.logTrace(req, timings, resp.StatusCode, options.timeout, nil)
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// logTrace logs request timings at debug level.
+func (c *Client) logTrace(req *http.Request, t traceTimings, status int, timeout time.Duration, err error) {
+	if !c.logger.Enabled(req.Context(), slog.LevelDebug) {
+		return
+	}
+
+	attrs := []any{
+		"client", c.config.Name,
+		"method", req.Method,
+		"host", req.URL.Host,
+		"status", status,
+		"timeout", timeout.String(),
+		"reused", t.reused,
+		"dns", t.dns.String(),
+		"connect", t.connect.String(),
+		"tls", t.tls.String(),
+		"ttfb", t.ttfb.String(),
+		"total", t.total.String(),
+	}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+
+	c.logger.Debug("http request trace", attrs...)
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// METRICS
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Stats contains transport-level counters.
+type Stats struct {
+	// InFlight is the number of requests currently in progress
+	InFlight int64
+	// Requests is the total number of requests sent
+	Requests int64
+	// Failures are requests that failed before a response was received
+	Failures int64
+	// ReusedConns are requests served over a pooled connection
+	ReusedConns int64
+	// NewConns are requests that had to open a new connection
+	NewConns int64
+}
+
+// ReuseRatio returns the share of requests served over a pooled connection.
+func (s Stats) ReuseRatio() float64 {
+	total := s.ReusedConns + s.NewConns
+	if total == 0 {
+		return 0
+	}
+	return float64(s.ReusedConns) / float64(total)
+}
+
+// Stats returns the counters since the client was created.
+func (c *Client) Stats() Stats {
+	return Stats{
+		InFlight:    c.inFlight.Load(),
+		Requests:    c.requests.Load(),
+		Failures:    c.failures.Load(),
+		ReusedConns: c.reusedConns.Load(),
+		NewConns:    c.newConns.Load(),
+	}
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// TRACING
+// ══════════════════════════════════════════════════════════════════════════════
+
+// traceTimings contains the timings of a single request.
+type traceTimings struct {
+	dns     time.Duration
+	connect time.Duration
+	tls     time.Duration
+	ttfb    time.Duration
+	total   time.Duration
+	gotConn bool
+	reused  bool
+}
+
+// requestTrace collects timings of a single request.
+// Callbacks may run on transport goroutines, so fields are guarded by mu.
+type requestTrace struct {
+	mu sync.Mutex
+
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+
+	traceTimings
+}
+
+// snapshot returns the timings collected so far.
+func (t *requestTrace) snapshot() traceTimings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	timings := t.traceTimings
+	timings.total = time.Since(t.start)
+	return timings
+}
+
+// clientTrace returns httptrace hooks that fill the trace.
+func (t *requestTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			t.dnsStart = time.Now()
+			t.mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			t.dns = time.Since(t.dnsStart)
+			t.mu.Unlock()
+		},
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			t.connectStart = time.Now()
+			t.mu.Unlock()
+		},
+		ConnectDone: func(string, string, error) {
+			t.mu.Lock()
+			t.connect = time.Since(t.connectStart)
+			t.mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			t.tlsStart = time.Now()
+			t.mu.Unlock()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			t.tls = time.Since(t.tlsStart)
+			t.mu.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.gotConn = true
+			t.reused = info.Reused
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			t.ttfb = time.Since(t.start)
+			t.mu.Unlock()
+		},
+	}
+}
+
+// cancelOnClose releases the request context once the body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the request context.
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
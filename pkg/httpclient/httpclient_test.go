@@ -0,0 +1,72 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, c *Client, url string, opts ...RequestOption) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	require.NoError(t, err)
+	return c.Do(req, opts...)
+}
+
+func TestClient_PerCallTimeoutOverride(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+			_, _ = io.WriteString(w, "ok")
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	c := New(Config{Name: "test", Timeout: 20 * time.Millisecond, Transport: NewTransport(DefaultTransportConfig())})
+
+	_, err := get(t, c, srv.URL)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+
+	resp, err := get(t, c, srv.URL, WithTimeout(time.Second))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, "ok", string(body))
+
+	stats := c.Stats()
+	assert.Equal(t, int64(2), stats.Requests)
+	assert.Equal(t, int64(1), stats.Failures)
+	assert.Equal(t, int64(0), stats.InFlight)
+}
+
+func TestClient_ReusesConnections(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "pong")
+	}))
+	defer srv.Close()
+
+	c := New(Config{Name: "test", Transport: NewTransport(DefaultTransportConfig())})
+
+	for i := 0; i < 3; i++ {
+		resp, err := get(t, c, srv.URL)
+		require.NoError(t, err)
+		_, err = io.Copy(io.Discard, resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	stats := c.Stats()
+	assert.Equal(t, int64(1), stats.NewConns)
+	assert.Equal(t, int64(2), stats.ReusedConns)
+	assert.InDelta(t, 2.0/3.0, stats.ReuseRatio(), 0.001)
+}
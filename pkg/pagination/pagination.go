@@ -0,0 +1,175 @@
+// Package pagination provides shared offset/limit handling, keyset cursors and
+// a response envelope for list endpoints. Limits are always clamped to a
+// per-call-site maximum so a caller cannot request an unbounded page.
+// No external dependencies - uses only standard library.
+package pagination
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ══════════════════════════════════════════════════════════════════════════════
+// PAGE
+// ══════════════════════════════════════════════════════════════════════════════
+
+const (
+	// DefaultLimit is used when a call site does not set its own default.
+	DefaultLimit = 50
+
+	// DefaultMaxLimit is used when a call site does not set its own maximum.
+	DefaultMaxLimit = 500
+)
+
+// Bounds are the limit default and maximum of a call site.
+// Zero fields fall back to DefaultLimit and DefaultMaxLimit.
+type Bounds struct {
+	// DefaultLimit is used when the requested limit is not positive.
+	DefaultLimit int
+
+	// MaxLimit is the largest limit a caller may request.
+	MaxLimit int
+}
+
+// withDefaults fills zero fields with the package defaults.
+func (b Bounds) withDefaults() Bounds {
+	if b.MaxLimit <= 0 {
+		b.MaxLimit = DefaultMaxLimit
+	}
+	if b.DefaultLimit <= 0 {
+		b.DefaultLimit = DefaultLimit
+	}
+	b.DefaultLimit = min(b.DefaultLimit, b.MaxLimit)
+	return b
+}
+
+// ClampLimit returns limit bounded to (0, MaxLimit], using DefaultLimit
+// when limit is not positive.
+func (b Bounds) ClampLimit(limit int) int {
+	b = b.withDefaults()
+	if limit <= 0 {
+		return b.DefaultLimit
+	}
+	return min(limit, b.MaxLimit)
+}
+
+// Page is a requested slice of a list: either Offset-based or, when Cursor
+// is set, continuing after the position encoded in the cursor.
+type Page struct {
+	// Limit is the maximum number of items to return.
+	Limit int
+
+	// Offset is the number of items to skip.
+	Offset int
+
+	// Cursor is an opaque keyset cursor from a previous page.
+	Cursor string
+}
+
+// Normalize returns the page with its limit clamped to b and a non-negative offset.
+func (p Page) Normalize(b Bounds) Page {
+	p.Limit = b.ClampLimit(p.Limit)
+	if p.Offset < 0 {
+		p.Offset = 0
+	}
+	return p
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// ENVELOPE
+// ══════════════════════════════════════════════════════════════════════════════
+
+// Envelope is the common shape of paginated list responses.
+type Envelope[T any] struct {
+	// Items are the items of the current page (never null in JSON).
+	Items []T `json:"items"`
+
+	// Total is the total number of items, when it is known.
+	Total *int `json:"total,omitempty"`
+
+	// NextCursor fetches the next page; empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewEnvelope creates an envelope for items.
+func NewEnvelope[T any](items []T, nextCursor string) Envelope[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Envelope[T]{Items: items, NextCursor: nextCursor}
+}
+
+// WithTotal sets the total item count.
+func (e Envelope[T]) WithTotal(total int) Envelope[T] {
+	e.Total = &total
+	return e
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// CURSOR CODEC
+// ══════════════════════════════════════════════════════════════════════════════
+
+// ErrInvalidCursor is returned for malformed or tampered cursors.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursorMACSize is the length of the truncated HMAC-SHA256 tag.
+const cursorMACSize = 16
+
+// CursorCodec encodes keyset positions into opaque, tamper-proof cursors.
+// A cursor is base64url(json(fields) || HMAC-SHA256(json(fields))[:16]).
+type CursorCodec struct {
+	secret []byte
+}
+
+// NewCursorCodec creates a codec signing cursors with secret.
+func NewCursorCodec(secret []byte) *CursorCodec {
+	return &CursorCodec{secret: append([]byte(nil), secret...)}
+}
+
+// Encode serializes v (a struct of typed cursor fields) into a cursor.
+func (c *CursorCodec) Encode(v any) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("pagination: encode cursor: %w", err)
+	}
+
+	buf := make([]byte, 0, len(payload)+cursorMACSize)
+	buf = append(buf, payload...)
+	buf = append(buf, c.sign(payload)...)
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Decode verifies cursor and unmarshals its fields into v.
+// Returns ErrInvalidCursor if the cursor was not produced by this codec.
+func (c *CursorCodec) Decode(cursor string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(raw) <= cursorMACSize {
+		return ErrInvalidCursor
+	}
+
+	payload, mac := raw[:len(raw)-cursorMACSize], raw[len(raw)-cursorMACSize:]
+	if !hmac.Equal(mac, c.sign(payload)) {
+		return ErrInvalidCursor
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return ErrInvalidCursor
+	}
+
+	return nil
+}
+
+// sign returns the truncated HMAC of payload.
+func (c *CursorCodec) sign(payload []byte) []byte {
+	h := hmac.New(sha256.New, c.secret)
+	h.Write(payload)
+	return h.Sum(nil)[:cursorMACSize]
+}
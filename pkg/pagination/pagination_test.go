@@ -0,0 +1,70 @@
+package pagination
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageNormalize_ClampsLimit(t *testing.T) {
+	bounds := Bounds{DefaultLimit: 20, MaxLimit: 100}
+
+	assert.Equal(t, Page{Limit: 100}, Page{Limit: 100000}.Normalize(bounds))
+	assert.Equal(t, Page{Limit: 20}, Page{Limit: 0, Offset: -5}.Normalize(bounds))
+	assert.Equal(t, Page{Limit: 20}, Page{Limit: -1}.Normalize(bounds))
+	assert.Equal(t, Page{Limit: 7, Offset: 40}, Page{Limit: 7, Offset: 40}.Normalize(bounds))
+
+	// Zero bounds fall back to package defaults
+	assert.Equal(t, DefaultMaxLimit, Page{Limit: 1 << 30}.Normalize(Bounds{}).Limit)
+	assert.Equal(t, DefaultLimit, Page{}.Normalize(Bounds{}).Limit)
+	assert.Equal(t, 10, Page{}.Normalize(Bounds{MaxLimit: 10}).Limit, "default never exceeds max")
+}
+
+type testCursor struct {
+	Cohort    string `json:"c"`
+	AfterRank int    `json:"r"`
+}
+
+func TestCursorCodec_RoundTrip(t *testing.T) {
+	codec := NewCursorCodec([]byte("secret"))
+
+	cursor, err := codec.Encode(testCursor{Cohort: "2024-09", AfterRank: 40})
+	require.NoError(t, err)
+	assert.NotContains(t, cursor, "2024-09", "cursor is opaque")
+
+	var got testCursor
+	require.NoError(t, codec.Decode(cursor, &got))
+	assert.Equal(t, testCursor{Cohort: "2024-09", AfterRank: 40}, got)
+}
+
+func TestCursorCodec_RejectsTampering(t *testing.T) {
+	codec := NewCursorCodec([]byte("secret"))
+	cursor, err := codec.Encode(testCursor{AfterRank: 40})
+	require.NoError(t, err)
+
+	// Flip one character of the payload
+	tampered := []byte(cursor)
+	if tampered[2] == 'A' {
+		tampered[2] = 'B'
+	} else {
+		tampered[2] = 'A'
+	}
+
+	// Re-signed with another key
+	forged, err := NewCursorCodec([]byte("other")).Encode(testCursor{AfterRank: 1000})
+	require.NoError(t, err)
+
+	for name, c := range map[string]string{
+		"tampered":  string(tampered),
+		"forged":    forged,
+		"truncated": cursor[:len(cursor)-3],
+		"garbage":   "not a cursor!",
+		"empty":     "",
+		"long":      strings.Repeat("A", 64),
+	} {
+		var got testCursor
+		assert.ErrorIs(t, codec.Decode(c, &got), ErrInvalidCursor, name)
+	}
+}
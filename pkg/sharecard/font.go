@@ -0,0 +1,103 @@
+package sharecard
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Glyph size of the embedded font, in font pixels.
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+// glyph is one character: a row per line, bit 4 is the leftmost pixel.
+type glyph [glyphHeight]uint8
+
+//go:embed font5x7.txt
+var fontSource string
+
+// font maps characters to glyphs; parsed once from font5x7.txt.
+var font = mustParseFont(fontSource)
+
+// mustParseFont parses the font file. Blank lines and lines starting with
+// "# " are skipped; a glyph is its character ("space" for ' ') followed by
+// glyphHeight rows of '#' and '.'.
+func mustParseFont(src string) map[rune]glyph {
+	var lines []string
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" || strings.HasPrefix(line, "# ") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	glyphs := make(map[rune]glyph)
+	for i := 0; i < len(lines); i += glyphHeight + 1 {
+		name := lines[i]
+		r, size := utf8.DecodeRuneInString(name)
+		if name == "space" {
+			r = ' '
+		} else if size != len(name) {
+			panic(fmt.Sprintf("sharecard: bad glyph name %q", name))
+		}
+		if i+glyphHeight >= len(lines) {
+			panic(fmt.Sprintf("sharecard: glyph %q is incomplete", name))
+		}
+
+		var g glyph
+		for row := 0; row < glyphHeight; row++ {
+			bits := lines[i+1+row]
+			if len(bits) != glyphWidth {
+				panic(fmt.Sprintf("sharecard: glyph %q row %d has %d pixels", name, row, len(bits)))
+			}
+			for col := 0; col < glyphWidth; col++ {
+				if bits[col] == '#' {
+					g[row] |= 1 << (glyphWidth - 1 - col)
+				}
+			}
+		}
+		glyphs[r] = g
+	}
+
+	return glyphs
+}
+
+// transliteration spells Cyrillic letters (Russian and Kazakh) with the
+// Latin letters of the font.
+var transliteration = map[rune]string{
+	'А': "A", 'Б': "B", 'В': "V", 'Г': "G", 'Д': "D", 'Е': "E", 'Ё': "E",
+	'Ж': "ZH", 'З': "Z", 'И': "I", 'Й': "Y", 'К': "K", 'Л': "L", 'М': "M",
+	'Н': "N", 'О': "O", 'П': "P", 'Р': "R", 'С': "S", 'Т': "T", 'У': "U",
+	'Ф': "F", 'Х': "KH", 'Ц': "TS", 'Ч': "CH", 'Ш': "SH", 'Щ': "SHCH",
+	'Ъ': "", 'Ы': "Y", 'Ь': "", 'Э': "E", 'Ю': "YU", 'Я': "YA",
+	'Ә': "A", 'Ғ': "G", 'Қ': "Q", 'Ң': "N", 'Ө': "O", 'Ұ': "U", 'Ү': "U",
+	'Һ': "H", 'І': "I",
+}
+
+// printable converts text to the characters the font can draw: letters
+// are uppercased, Cyrillic is transliterated and anything else becomes '?'.
+func printable(text string) []rune {
+	out := make([]rune, 0, len(text))
+	for _, r := range text {
+		r = unicode.ToUpper(r)
+		if _, ok := font[r]; ok {
+			out = append(out, r)
+			continue
+		}
+		if latin, ok := transliteration[r]; ok {
+			out = append(out, []rune(latin)...)
+			continue
+		}
+		if unicode.IsSpace(r) {
+			out = append(out, ' ')
+			continue
+		}
+		out = append(out, '?')
+	}
+	return out
+}
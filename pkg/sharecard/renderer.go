@@ -0,0 +1,130 @@
+package sharecard
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RendererConfig configures a Renderer.
+type RendererConfig struct {
+	// MaxConcurrent - renders running at the same time; further callers wait.
+	MaxConcurrent int
+
+	// CacheTTL - how long a rendered card is reused for the same content.
+	CacheTTL time.Duration
+
+	// CacheSize - maximum number of cached cards.
+	CacheSize int
+}
+
+// DefaultRendererConfig returns the default configuration.
+func DefaultRendererConfig() RendererConfig {
+	return RendererConfig{
+		MaxConcurrent: 2,
+		CacheTTL:      10 * time.Minute,
+		CacheSize:     256,
+	}
+}
+
+// Renderer renders cards with bounded CPU use: at most MaxConcurrent
+// renders run at once, and identical cards are served from a short-lived
+// cache. On a day when many streaks reach a milestone, senders queue up
+// instead of rendering all at once.
+type Renderer struct {
+	config RendererConfig
+	slots  chan struct{}
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[Card]cachedCard
+}
+
+type cachedCard struct {
+	png     []byte
+	expires time.Time
+}
+
+// NewRenderer creates a Renderer. Zero config fields take the defaults.
+func NewRenderer(config RendererConfig) *Renderer {
+	defaults := DefaultRendererConfig()
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = defaults.MaxConcurrent
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = defaults.CacheTTL
+	}
+	if config.CacheSize <= 0 {
+		config.CacheSize = defaults.CacheSize
+	}
+
+	return &Renderer{
+		config: config,
+		slots:  make(chan struct{}, config.MaxConcurrent),
+		now:    time.Now,
+		cache:  make(map[Card]cachedCard),
+	}
+}
+
+// Render returns the PNG of the card, from the cache when possible.
+// It waits for a free render slot until ctx is done.
+func (r *Renderer) Render(ctx context.Context, card Card) ([]byte, error) {
+	if png, ok := r.cached(card); ok {
+		return png, nil
+	}
+
+	select {
+	case r.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-r.slots }()
+
+	// Another caller may have rendered the same card while we waited
+	if png, ok := r.cached(card); ok {
+		return png, nil
+	}
+
+	png, err := Render(card)
+	if err != nil {
+		return nil, err
+	}
+	r.store(card, png)
+	return png, nil
+}
+
+// cached returns an unexpired cached card.
+func (r *Renderer) cached(card Card) ([]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.cache[card]
+	if !ok || !r.now().Before(entry.expires) {
+		return nil, false
+	}
+	return entry.png, true
+}
+
+// store caches a card, dropping expired entries (or an arbitrary one)
+// when the cache is full.
+func (r *Renderer) store(card Card, png []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if len(r.cache) >= r.config.CacheSize {
+		for key, entry := range r.cache {
+			if !now.Before(entry.expires) {
+				delete(r.cache, key)
+			}
+		}
+	}
+	if len(r.cache) >= r.config.CacheSize {
+		for key := range r.cache {
+			delete(r.cache, key)
+			break
+		}
+	}
+
+	r.cache[card] = cachedCard{png: png, expires: now.Add(r.config.CacheTTL)}
+}
@@ -0,0 +1,160 @@
+// Package sharecard renders streak milestone cards as PNG images that
+// students can share. Text is drawn with a small embedded bitmap font, so
+// rendering needs no font files.
+// No external dependencies - uses only standard library.
+package sharecard
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+)
+
+// Card size in pixels (close to the 1.91:1 ratio of link previews).
+const (
+	Width  = 800
+	Height = 420
+)
+
+// margin is the horizontal padding of text.
+const margin = 48
+
+// Card is the content of a share card.
+type Card struct {
+	// Name is the student's display name.
+	Name string
+
+	// Streak is the streak length in days.
+	Streak int
+
+	// Cohort is the student's cohort (optional).
+	Cohort string
+}
+
+var (
+	backgroundTop    = color.RGBA{R: 0xff, G: 0x6b, B: 0x35, A: 0xff}
+	backgroundBottom = color.RGBA{R: 0xc2, G: 0x2e, B: 0x5b, A: 0xff}
+	textColor        = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	mutedTextColor   = color.RGBA{R: 0xff, G: 0xe3, B: 0xd6, A: 0xff}
+)
+
+// Render draws the card and encodes it as PNG.
+func Render(card Card) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	fillGradient(img, backgroundTop, backgroundBottom)
+
+	drawText(img, []rune("ALEM COMMUNITY HUB"), margin, 40, 3, mutedTextColor)
+
+	// The streak is the largest element, centered
+	number := []rune(strconv.Itoa(card.Streak))
+	numberScale := fitScale(len(number), 20, 8, Width-2*margin)
+	drawText(img, number, (Width-textWidth(len(number), numberScale))/2, 92, numberScale, textColor)
+
+	label := []rune("DAY STREAK")
+	drawText(img, label, (Width-textWidth(len(label), 5))/2, 252, 5, textColor)
+
+	name := printable(card.Name)
+	nameScale := fitScale(len(name), 5, 3, Width-2*margin)
+	name = truncate(name, nameScale, Width-2*margin)
+	drawText(img, name, margin, 316, nameScale, textColor)
+
+	if card.Cohort != "" {
+		cohort := printable(card.Cohort)
+		drawText(img, truncate(cohort, 3, Width-2*margin), margin, 362, 3, mutedTextColor)
+	}
+
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestSpeed}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("sharecard: encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fillGradient paints a vertical gradient from top to bottom.
+func fillGradient(img *image.RGBA, top, bottom color.RGBA) {
+	bounds := img.Bounds()
+	height := bounds.Dy()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		t := float64(y-bounds.Min.Y) / float64(max(height-1, 1))
+		c := color.RGBA{
+			R: lerp(top.R, bottom.R, t),
+			G: lerp(top.G, bottom.G, t),
+			B: lerp(top.B, bottom.B, t),
+			A: 0xff,
+		}
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+func lerp(a, b uint8, t float64) uint8 {
+	return uint8(float64(a) + (float64(b)-float64(a))*t)
+}
+
+// textWidth returns the width of n characters at the given scale: glyphs
+// are separated by one font pixel.
+func textWidth(n, scale int) int {
+	if n == 0 {
+		return 0
+	}
+	return n*(glyphWidth+1)*scale - scale
+}
+
+// fitScale returns the largest scale from largest down to smallest at which
+// n characters fit into width (smallest if none does).
+func fitScale(n, largest, smallest, width int) int {
+	for scale := largest; scale > smallest; scale-- {
+		if textWidth(n, scale) <= width {
+			return scale
+		}
+	}
+	return smallest
+}
+
+// truncate shortens text that does not fit into width, ending it with "...".
+func truncate(text []rune, scale, width int) []rune {
+	if textWidth(len(text), scale) <= width {
+		return text
+	}
+
+	n := width / ((glyphWidth + 1) * scale)
+	if n <= 3 {
+		return []rune("...")[:max(n, 0)]
+	}
+	return append(append([]rune{}, text[:n-3]...), '.', '.', '.')
+}
+
+// drawText draws text with its top-left corner at (x, y). Each font pixel
+// is a scale×scale square.
+func drawText(img *image.RGBA, text []rune, x, y, scale int, c color.RGBA) {
+	for _, r := range text {
+		g, ok := font[r]
+		if !ok {
+			g = font['?']
+		}
+		for row := 0; row < glyphHeight; row++ {
+			for col := 0; col < glyphWidth; col++ {
+				if g[row]&(1<<(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				fillRect(img, x+col*scale, y+row*scale, scale, c)
+			}
+		}
+		x += (glyphWidth + 1) * scale
+	}
+}
+
+// fillRect fills a size×size square, clipped to the image.
+func fillRect(img *image.RGBA, x, y, size int, c color.RGBA) {
+	rect := image.Rect(x, y, x+size, y+size).Intersect(img.Bounds())
+	for py := rect.Min.Y; py < rect.Max.Y; py++ {
+		for px := rect.Min.X; px < rect.Max.X; px++ {
+			img.SetRGBA(px, py, c)
+		}
+	}
+}
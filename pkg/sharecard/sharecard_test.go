@@ -0,0 +1,67 @@
+package sharecard
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_EncodesPNG(t *testing.T) {
+	cards := []Card{
+		{Name: "aigerim", Streak: 7, Cohort: "2025-spring"},
+		{Name: strings.Repeat("Very Long Display Name ", 20), Streak: 100, Cohort: strings.Repeat("cohort-", 30)},
+		{Name: "Айгерім Қасымова 🔥", Streak: 30},
+		{Name: "", Streak: 1234567},
+	}
+
+	for _, card := range cards {
+		data, err := Render(card)
+		require.NoError(t, err, "card %+v", card)
+
+		img, err := png.Decode(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, Width, img.Bounds().Dx())
+		assert.Equal(t, Height, img.Bounds().Dy())
+	}
+}
+
+func TestPrintable(t *testing.T) {
+	assert.Equal(t, "AYGERIM QASYMOVA ?", string(printable("Айгерім Қасымова 🔥")))
+	assert.Equal(t, "JOHN_DOE-2", string(printable("john_doe-2")))
+}
+
+func TestTruncate(t *testing.T) {
+	name := []rune(strings.Repeat("A", 100))
+	short := truncate(name, 3, Width-2*margin)
+	assert.LessOrEqual(t, textWidth(len(short), 3), Width-2*margin)
+	assert.True(t, strings.HasSuffix(string(short), "..."))
+
+	assert.Equal(t, "AIGERIM", string(truncate([]rune("AIGERIM"), 5, Width-2*margin)))
+}
+
+func TestRenderer_CachesCards(t *testing.T) {
+	r := NewRenderer(RendererConfig{MaxConcurrent: 1, CacheSize: 1})
+	card := Card{Name: "aigerim", Streak: 14}
+
+	first, err := r.Render(context.Background(), card)
+	require.NoError(t, err)
+	second, err := r.Render(context.Background(), card)
+	require.NoError(t, err)
+	assert.Same(t, &first[0], &second[0], "identical card is served from the cache")
+
+	_, err = r.Render(context.Background(), Card{Name: "other", Streak: 14})
+	require.NoError(t, err)
+	assert.Len(t, r.cache, 1)
+
+	// A full slot makes callers wait until their context is done
+	r.slots <- struct{}{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = r.Render(ctx, Card{Name: "queued", Streak: 60})
+	assert.ErrorIs(t, err, context.Canceled)
+}
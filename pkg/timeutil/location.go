@@ -0,0 +1,56 @@
+package timeutil
+
+import (
+	"sync"
+	"time"
+	_ "time/tzdata" // IANA-зоны не зависят от базы часовых поясов хоста
+)
+
+// locations caches loaded time zones by IANA name. Loading a zone reads and
+// parses tzdata, so it is done once per zone for the process lifetime.
+// Only successful lookups are cached: the set of valid names is bounded.
+var locations sync.Map // map[string]*time.Location
+
+func init() {
+	locations.Store(AlmatyTZ.String(), AlmatyTZ)
+}
+
+// LoadLocation returns the time zone with the given IANA name, like
+// time.LoadLocation, but loads each zone only once.
+// "Asia/Almaty" is always AlmatyTZ.
+func LoadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := locations.LoadOrStore(name, loc)
+	return actual.(*time.Location), nil
+}
+
+// TimezonePreset is a time zone offered to students by /timezone.
+type TimezonePreset struct {
+	// Name is the IANA name.
+	Name string
+
+	// Label is the Russian label with the UTC offset.
+	Label string
+}
+
+// TimezonePresets are the common time zones of students: Kazakhstan and
+// the cities students move to for internships, from east to west.
+var TimezonePresets = []TimezonePreset{
+	{Name: "Asia/Tokyo", Label: "Токио (UTC+9)"},
+	{Name: "Asia/Shanghai", Label: "Пекин (UTC+8)"},
+	{Name: "Asia/Almaty", Label: "Алматы, Астана (UTC+5)"},
+	{Name: "Asia/Tashkent", Label: "Ташкент (UTC+5)"},
+	{Name: "Asia/Dubai", Label: "Дубай (UTC+4)"},
+	{Name: "Europe/Istanbul", Label: "Стамбул (UTC+3)"},
+	{Name: "Europe/Moscow", Label: "Москва (UTC+3)"},
+	{Name: "Europe/Berlin", Label: "Берлин (UTC+1/+2)"},
+	{Name: "Europe/London", Label: "Лондон (UTC+0/+1)"},
+	{Name: "America/New_York", Label: "Нью-Йорк (UTC−5/−4)"},
+}
@@ -208,9 +208,12 @@ func FormatRussian(t time.Time) string {
 
 // FormatRelative returns a human-readable relative time string.
 func FormatRelative(t time.Time) string {
-	now := Now()
-	almaty := ToAlmaty(t)
-	duration := now.Sub(almaty)
+	return FormatRelativeAt(t, Now())
+}
+
+// FormatRelativeAt is FormatRelative as seen at now ("2 ч назад").
+func FormatRelativeAt(t, now time.Time) string {
+	duration := now.Sub(t)
 
 	if duration < 0 {
 		duration = -duration